@@ -65,6 +65,17 @@ type ClusterSpec struct {
 	// +optional
 	InfrastructureRef *corev1.ObjectReference `json:"infrastructureRef,omitempty"`
 
+	// ImageRegistry defines the image registry and the image digests used for the components of the cluster;
+	// it is applied by the control plane and bootstrap providers to the generated configurations.
+	// If the Cluster uses a managed topology and ImageRegistry is not set, it defaults to the ImageRegistry of the ClusterClass.
+	// +optional
+	ImageRegistry *ImageRegistry `json:"imageRegistry,omitempty"`
+
+	// Tunnel defines a reverse tunnel used by the management cluster to reach the API server of the
+	// cluster, e.g. when the cluster has no inbound reachability because it runs behind NAT.
+	// +optional
+	Tunnel *ClusterTunnel `json:"tunnel,omitempty"`
+
 	// This encapsulates the topology for the cluster.
 	// NOTE: It is required to enable the ClusterTopology
 	// feature gate flag to activate managed topologies support;
@@ -368,6 +379,94 @@ type ClusterNetwork struct {
 
 // ANCHOR_END: ClusterNetwork
 
+// ANCHOR: ImageRegistry
+
+// ImageRegistry defines the image registry and the image digests used for the components of the cluster.
+type ImageRegistry struct {
+	// Repository is the container registry to pull the images of the cluster components from,
+	// e.g. a mirror of registry.k8s.io. It is used only if the provider configuration does not
+	// explicitly define an image repository, e.g. in the kubeadm ClusterConfiguration.
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// Digests pins the images of the cluster components to the given digests.
+	// Changes to the digests of kube-apiserver, kube-controller-manager, kube-scheduler and etcd trigger a rollout of
+	// the KubeadmControlPlane machines, while the digests of kube-proxy and CoreDNS are updated in place;
+	// worker machines are not rolled out because they do not run these components.
+	// +optional
+	Digests *ImageDigests `json:"digests,omitempty"`
+}
+
+// ImageDigests defines the digests the images of the cluster components are pinned to.
+// Each digest must be in the form sha256:<hex>.
+type ImageDigests struct {
+	// KubeAPIServer is the digest of the kube-apiserver image.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	KubeAPIServer string `json:"kubeAPIServer,omitempty"`
+
+	// KubeControllerManager is the digest of the kube-controller-manager image.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	KubeControllerManager string `json:"kubeControllerManager,omitempty"`
+
+	// KubeScheduler is the digest of the kube-scheduler image.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	KubeScheduler string `json:"kubeScheduler,omitempty"`
+
+	// KubeProxy is the digest of the kube-proxy image.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	KubeProxy string `json:"kubeProxy,omitempty"`
+
+	// Etcd is the digest of the etcd image.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Etcd string `json:"etcd,omitempty"`
+
+	// CoreDNS is the digest of the CoreDNS image.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	CoreDNS string `json:"coreDNS,omitempty"`
+}
+
+// ANCHOR_END: ImageRegistry
+
+// ANCHOR: ClusterTunnel
+
+// ClusterTunnel defines a reverse tunnel to the API server of a cluster, e.g. provided by a konnectivity server;
+// the tunnel agent running on the control plane machines connects to the tunnel server, and the controllers
+// on the management cluster reach the API server of the cluster through the tunnel server.
+type ClusterTunnel struct {
+	// ProxyURL is the URL of the tunnel server as reachable from the management cluster,
+	// e.g. http://konnectivity-server.tunnel-system.svc:8090. The tunnel server is used as HTTP CONNECT
+	// proxy for all the connections to the API server of the cluster.
+	// +kubebuilder:validation:MinLength=1
+	ProxyURL string `json:"proxyURL"`
+
+	// Agent defines the tunnel agent deployed on the control plane machines by the bootstrap provider.
+	// If not set, the tunnel agent must be deployed by other means.
+	// +optional
+	Agent *ClusterTunnelAgent `json:"agent,omitempty"`
+}
+
+// ClusterTunnelAgent defines the tunnel agent deployed on the control plane machines.
+// The agent authenticates to the tunnel server with the certificate and key stored in the
+// <cluster-name>-tunnel Secret, which must also contain the CA of the tunnel server.
+type ClusterTunnelAgent struct {
+	// ServerAddress is the address of the tunnel server as reachable from the cluster, in the host:port form.
+	// +kubebuilder:validation:MinLength=1
+	ServerAddress string `json:"serverAddress"`
+
+	// Image is the image of the tunnel agent.
+	// Defaults to registry.k8s.io/kas-network-proxy/proxy-agent:v0.28.0.
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// ANCHOR_END: ClusterTunnel
+
 // ANCHOR: NetworkRanges
 
 // NetworkRanges represents ranges of network addresses.
@@ -420,6 +519,21 @@ type ClusterStatus struct {
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
 
+	// Rollout summarizes the rollouts in progress for the control plane, the MachineDeployments and
+	// the MachinePools of the cluster; it is not set if there are no rollouts in progress.
+	// +optional
+	Rollout *ClusterRolloutStatus `json:"rollout,omitempty"`
+
+	// Machines summarizes the number of Machines of the control plane and of the worker pools of the cluster,
+	// i.e. the MachineDeployments and the MachinePools.
+	// +optional
+	Machines *ClusterMachinesStatus `json:"machines,omitempty"`
+
+	// Shard is the replica of the manager reconciling the cluster, when the controllers are sharded across
+	// multiple replicas of the manager.
+	// +optional
+	Shard *ClusterShardStatus `json:"shard,omitempty"`
+
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
@@ -427,6 +541,114 @@ type ClusterStatus struct {
 
 // ANCHOR_END: ClusterStatus
 
+// ClusterShardStatus is the assignment of a Cluster to a replica of a sharded manager.
+type ClusterShardStatus struct {
+	// Owner is the ID of the replica reconciling the Cluster, e.g. the name of its Pod.
+	Owner string `json:"owner"`
+
+	// Key is the key used to assign the Cluster to a replica, i.e. the value of the
+	// cluster.x-k8s.io/shard-key label, if any, or the namespace and name of the Cluster.
+	Key string `json:"key"`
+
+	// LastTransitionTime is the last time the Cluster has been assigned to another replica.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// ClusterRolloutStatus summarizes the rollouts in progress for a Cluster.
+type ClusterRolloutStatus struct {
+	// StartTime is the time the rollout started, i.e. the first time an object of the Cluster
+	// was observed rolling out since the last time all the objects were up to date.
+	StartTime metav1.Time `json:"startTime"`
+
+	// EstimatedCompletionTime is a rough estimate of the time the rollout completes, assuming the pending
+	// Machines are rolled out at the same rate of the Machines rolled out since the StartTime.
+	// It is not set until at least one Machine is rolled out.
+	// +optional
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+
+	// Replicas is the total number of Machines of the objects rolling out.
+	Replicas int32 `json:"replicas"`
+
+	// PendingReplicas is the total number of Machines of the objects rolling out which are not up to date yet.
+	PendingReplicas int32 `json:"pendingReplicas"`
+
+	// Objects lists the objects rolling out, i.e. the control plane, the MachineDeployments and the MachinePools
+	// with Machines which are not up to date yet.
+	// +optional
+	Objects []RolloutObject `json:"objects,omitempty"`
+}
+
+// RolloutObject summarizes the rollout of an object of a Cluster.
+type RolloutObject struct {
+	// Kind of the object, e.g. MachineDeployment.
+	Kind string `json:"kind"`
+
+	// Name of the object.
+	Name string `json:"name"`
+
+	// Version is the Kubernetes version the object is rolling out to, if any.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Replicas is the number of Machines of the object.
+	Replicas int32 `json:"replicas"`
+
+	// PendingReplicas is the number of Machines of the object which are not up to date yet.
+	PendingReplicas int32 `json:"pendingReplicas"`
+}
+
+// ClusterMachinesStatus summarizes the number of Machines of a Cluster.
+type ClusterMachinesStatus struct {
+	// ControlPlane is the number of Machines of the control plane.
+	// It is not set if the Cluster has no control plane, or if the control plane does not report its replicas.
+	// +optional
+	ControlPlane *MachineCounts `json:"controlPlane,omitempty"`
+
+	// Workers is the number of Machines of all the worker pools.
+	Workers MachineCounts `json:"workers"`
+
+	// WorkerPools is the number of Machines of each worker pool, i.e. of each MachineDeployment and MachinePool,
+	// sorted by kind and name.
+	// +optional
+	WorkerPools []WorkerPoolMachineCounts `json:"workerPools,omitempty"`
+}
+
+// MachineCounts is the number of Machines of a set of Machines, e.g. of the control plane.
+type MachineCounts struct {
+	// Desired is the desired number of Machines.
+	Desired int32 `json:"desired"`
+
+	// Ready is the number of Machines which are ready.
+	Ready int32 `json:"ready"`
+
+	// Available is the number of Machines which are available.
+	Available int32 `json:"available"`
+
+	// UpToDate is the number of Machines which are up to date with the desired spec.
+	UpToDate int32 `json:"upToDate"`
+}
+
+// WorkerPoolMachineCounts is the number of Machines of a worker pool of a Cluster.
+type WorkerPoolMachineCounts struct {
+	// Kind of the worker pool, i.e. MachineDeployment or MachinePool.
+	Kind string `json:"kind"`
+
+	// Name of the worker pool.
+	Name string `json:"name"`
+
+	// Desired is the desired number of Machines.
+	Desired int32 `json:"desired"`
+
+	// Ready is the number of Machines which are ready.
+	Ready int32 `json:"ready"`
+
+	// Available is the number of Machines which are available.
+	Available int32 `json:"available"`
+
+	// UpToDate is the number of Machines which are up to date with the desired spec.
+	UpToDate int32 `json:"upToDate"`
+}
+
 // SetTypedPhase sets the Phase field to the string representation of ClusterPhase.
 func (c *ClusterStatus) SetTypedPhase(p ClusterPhase) {
 	c.Phase = string(p)
@@ -74,6 +74,11 @@ type ClusterClassSpec struct {
 	// Note: Patches will be applied in the order of the array.
 	// +optional
 	Patches []ClusterClassPatch `json:"patches,omitempty"`
+
+	// ImageRegistry defines the image registry and the image digests used for the components
+	// of the Clusters using this ClusterClass; it is used as a default for Cluster.spec.imageRegistry.
+	// +optional
+	ImageRegistry *ImageRegistry `json:"imageRegistry,omitempty"`
 }
 
 // ControlPlaneClass defines the class for the control plane.
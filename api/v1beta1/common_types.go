@@ -98,6 +98,19 @@ const (
 	// on the reconciled object.
 	PausedAnnotation = "cluster.x-k8s.io/paused"
 
+	// NamespaceMaintenanceAnnotation is an annotation that can be applied to a Namespace to pause the reconciliation
+	// of all the Clusters and ClusterClasses in the Namespace at once, e.g. during a maintenance of the management
+	// cluster. The value of the annotation, if any, documents the reason of the maintenance.
+	NamespaceMaintenanceAnnotation = "cluster.x-k8s.io/maintenance"
+
+	// PausedByMaintenanceAnnotation is the annotation set on the Clusters and ClusterClasses paused because of the
+	// maintenance of their Namespace, so that only those objects are unpaused once the maintenance is over.
+	PausedByMaintenanceAnnotation = "cluster.x-k8s.io/paused-by-maintenance"
+
+	// ShardKeyLabel can be set on Clusters to assign them to the replicas of a sharded manager using the value of the
+	// label instead of their namespace and name, so that the Clusters with the same value are reconciled by the same replica.
+	ShardKeyLabel = "cluster.x-k8s.io/shard-key"
+
 	// DisableMachineCreateAnnotation is an annotation that can be used to signal a MachineSet to stop creating new machines.
 	// It is utilized in the OnDelete MachineDeploymentStrategy to allow the MachineDeployment controller to scale down
 	// older MachineSets when Machines are deleted and add the new replicas to the latest MachineSet.
@@ -121,6 +134,55 @@ const (
 	// that was cloned for the machine. This annotation is set only during cloning a template. Older/adopted machines will not have this annotation.
 	TemplateClonedFromGroupKindAnnotation = "cluster.x-k8s.io/cloned-from-groupkind"
 
+	// BootstrapDataDeliveryAnnotation is the infrastructure machine annotation that infrastructure providers can set
+	// to declare how the bootstrap data is delivered to the machine.
+	// Supported values are:
+	// - SecureChannel (the machine fetches the bootstrap data at first boot from the bootstrap provider over an
+	//   authenticated channel; the bootstrap data secret contains only the small payload required to do so)
+	// Note: The annotation must be set when the infrastructure machine is created, e.g. by the infrastructure machine
+	// template or by a defaulting webhook, because bootstrap providers read it when generating the bootstrap data.
+	// The kubeadm bootstrap provider reads the annotation only if its bootstrap data server is enabled.
+	BootstrapDataDeliveryAnnotation = "cluster.x-k8s.io/bootstrap-data-delivery"
+
+	// KubeconfigSignerAnnotation can be set on a Cluster to provide the name of a Secret, in the namespace of the Cluster,
+	// containing the certificate (tls.crt) and the key (tls.key) of the CA signing the client certificates of the
+	// kubeconfig of the Cluster when they are rotated; the CA must be trusted by the API server of the Cluster.
+	// If not set, the client certificates are signed by the cluster CA.
+	// Note: The annotation does not apply to kubeconfigs managed by the control plane provider, e.g. by KubeadmControlPlane.
+	KubeconfigSignerAnnotation = "cluster.x-k8s.io/kubeconfig-signer"
+
+	// ClusterCacheClientQPSAnnotation can be set on a Cluster to override the maximum queries per second
+	// from the clients of the controllers to the API server of the cluster.
+	// Note: Changes are applied when the connection to the cluster is established again.
+	ClusterCacheClientQPSAnnotation = "cluster-cache.cluster.x-k8s.io/client-qps"
+
+	// ClusterCacheClientBurstAnnotation can be set on a Cluster to override the maximum burst for throttle
+	// of the clients of the controllers to the API server of the cluster.
+	// Note: Changes are applied when the connection to the cluster is established again.
+	ClusterCacheClientBurstAnnotation = "cluster-cache.cluster.x-k8s.io/client-burst"
+
+	// ClusterCacheUncachedKindsAnnotation can be set on a Cluster to provide a comma-separated list of kinds,
+	// in the Kind or Kind.group form, which are read directly from the API server of the cluster instead of
+	// being cached by the controllers, in addition to the kinds never cached by the controllers.
+	// Example: "cluster-cache.cluster.x-k8s.io/uncached-kinds": "Node,Deployment.apps".
+	// Note: Changes are applied when the connection to the cluster is established again.
+	ClusterCacheUncachedKindsAnnotation = "cluster-cache.cluster.x-k8s.io/uncached-kinds"
+
+	// ClusterCacheNodeLabelSelectorAnnotation can be set on a Cluster to provide a label selector restricting
+	// the Nodes of the cluster cached by the controllers; Nodes not matching the selector are not visible to the
+	// controllers, so the selector must match all the Nodes of the Machines managed by Cluster API.
+	// Example: "cluster-cache.cluster.x-k8s.io/node-label-selector": "example.com/managed-by-capi=true".
+	// Note: Changes are applied when the connection to the cluster is established again.
+	ClusterCacheNodeLabelSelectorAnnotation = "cluster-cache.cluster.x-k8s.io/node-label-selector"
+
+	// ClusterCacheProxyAnnotation can be set on a Cluster to provide the URL of an HTTP CONNECT (http or https scheme)
+	// or SOCKS5 (socks5 scheme) proxy used by the controllers to reach the API server of the cluster, e.g. a bastion host.
+	// It takes precedence over the proxy configured for the controllers, while the tunnel of the Cluster takes
+	// precedence over it.
+	// Example: "cluster-cache.cluster.x-k8s.io/proxy": "socks5://bastion.example.com:1080".
+	// Note: Changes are applied when the connection to the cluster is established again.
+	ClusterCacheProxyAnnotation = "cluster-cache.cluster.x-k8s.io/proxy"
+
 	// MachineSkipRemediationAnnotation is the annotation used to mark the machines that should not be considered for remediation by MachineHealthCheck reconciler.
 	MachineSkipRemediationAnnotation = "cluster.x-k8s.io/skip-remediation"
 
@@ -224,6 +286,15 @@ const (
 	MachineSetPreflightCheckControlPlaneIsStable MachineSetPreflightCheck = "ControlPlaneIsStable"
 )
 
+// BootstrapDataDelivery defines how the bootstrap data is delivered to a machine.
+type BootstrapDataDelivery string
+
+const (
+	// SecureChannelBootstrapDataDelivery is the BootstrapDataDelivery where the machine fetches the bootstrap data
+	// at first boot from the bootstrap provider, authenticating with a one-time token.
+	SecureChannelBootstrapDataDelivery BootstrapDataDelivery = "SecureChannel"
+)
+
 // NodeUninitializedTaint can be added to Nodes at creation by the bootstrap provider, e.g. the
 // KubeadmBootstrap provider will add the taint.
 // This taint is used to prevent workloads to be scheduled on Nodes before the node is initialized by Cluster API.
@@ -268,6 +339,41 @@ type MachineAddress struct {
 // MachineAddresses is a slice of MachineAddress items to be used by infrastructure providers.
 type MachineAddresses []MachineAddress
 
+// MaxRolloutHistory is the maximum number of changes recorded in the rollout history of
+// MachineDeployments and control planes.
+const MaxRolloutHistory = 10
+
+// RolloutTrigger records a change which triggered a rollout of Machines.
+type RolloutTrigger struct {
+	// Time is the time the rollout was triggered.
+	Time metav1.Time `json:"time"`
+
+	// Kind of the object whose change triggered the rollout, e.g. MachineDeployment,
+	// or the kind of the infrastructure machine template it references.
+	Kind string `json:"kind"`
+
+	// Name of the object whose change triggered the rollout.
+	Name string `json:"name"`
+
+	// FieldPaths are the paths of the fields whose change triggered the rollout, e.g. spec.template.spec.version.
+	// +optional
+	FieldPaths []string `json:"fieldPaths,omitempty"`
+
+	// OldHash is the hash of the fields which trigger a rollout when changed, before the change.
+	// It is empty if it is not known.
+	// +optional
+	OldHash string `json:"oldHash,omitempty"`
+
+	// NewHash is the hash of the fields which trigger a rollout when changed, after the change.
+	NewHash string `json:"newHash"`
+
+	// Manager is the field manager which last changed the fields triggering the rollout, as recorded in the
+	// managed fields of the object, e.g. kubectl-edit or capi-topology.
+	// It is empty if it cannot be determined.
+	// +optional
+	Manager string `json:"manager,omitempty"`
+}
+
 // ObjectMeta is metadata that all persisted resources must have, which includes all objects
 // users must create. This is a copy of customizable fields from metav1.ObjectMeta.
 //
@@ -102,6 +102,23 @@ const (
 	// NOTE: Having the control plane machine available is a pre-condition for joining additional control planes
 	// or workers nodes.
 	WaitingForControlPlaneAvailableReason = "WaitingForControlPlaneAvailable"
+
+	// RolloutCompletedCondition reports if all the Machines of the control plane, the MachineDeployments and the
+	// MachinePools of a Cluster are up to date, i.e. there are no rollouts in progress. See Cluster.Status.Rollout
+	// for a summary of the rollouts in progress.
+	// NOTE: This condition is not part of the Cluster's Ready summary, because a rollout is part of the normal
+	// lifecycle of a Cluster.
+	RolloutCompletedCondition ConditionType = "RolloutCompleted"
+
+	// RolloutInProgressReason (Severity=Info) documents a Cluster with the control plane, at least one of the
+	// MachineDeployments or at least one of the MachinePools rolling out Machines.
+	RolloutInProgressReason = "RolloutInProgress"
+
+	// PausedCondition reports that the Cluster controller observed the Cluster being paused, i.e. that the
+	// reconciliation of the Cluster and of the objects in its hierarchy is stopped, e.g. because its Namespace is in
+	// maintenance. The condition is removed once the Cluster is unpaused.
+	// NOTE: This condition is not part of the Cluster's Ready summary.
+	PausedCondition ConditionType = "Paused"
 )
 
 // Conditions and condition Reasons for the Machine object.
@@ -142,6 +159,20 @@ const (
 
 	// WaitingForVolumeDetachReason (Severity=Info) provide evidence that a machine node waiting for volumes to be attached.
 	WaitingForVolumeDetachReason = "WaitingForVolumeDetach"
+
+	// APIServerLoadBalancerReadyCondition reports whether a control plane machine is registered and healthy in the
+	// external load balancer in front of the API server. This condition is copied from the infrastructure machine, if
+	// reported there; alternatively it can be set on the machine by an external component, e.g. a runtime extension
+	// managing the load balancer. When the condition exists, control plane providers wait for it to be true before
+	// considering the machine available and proceeding with a rollout.
+	//
+	// NOTE: Infrastructure providers implementing this condition should set it to false as soon as the infrastructure
+	// machine is created, so control plane providers do not proceed before the load balancer is reconciled.
+	APIServerLoadBalancerReadyCondition ConditionType = "APIServerLoadBalancerReady"
+
+	// WaitingForLoadBalancerRegistrationReason (Severity=Info) documents a machine waiting to be registered and healthy
+	// in the API server load balancer.
+	WaitingForLoadBalancerRegistrationReason = "WaitingForLoadBalancerRegistration"
 )
 
 const (
@@ -316,6 +347,10 @@ const (
 	// not yet completed because at least one of the lifecycle hooks is blocking.
 	TopologyReconciledHookBlockingReason = "LifecycleHookBlocking"
 
+	// TopologyReconciledClusterAddonsNotReadyReason (Severity=Info) documents reconciliation of a Cluster topology
+	// not yet completed because at least one of the ClusterAddons of the Cluster is not ready for the new version.
+	TopologyReconciledClusterAddonsNotReadyReason = "ClusterAddonsNotReady"
+
 	// TopologyReconciledClusterClassNotReconciledReason (Severity=Info) documents reconciliation of a Cluster topology not
 	// yet completed because the ClusterClass has not reconciled yet. If this condition persists there may be an issue
 	// with the ClusterClass surfaced in the ClusterClass status or controller logs.
@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// ANCHOR: EventAnnotations
+
+const (
+	// EventRelatedObjectKindAnnotation is the annotation set on events about an object different from the object the
+	// event is recorded on, e.g. the Machine created by a MachineSet, documenting the kind of the related object.
+	// NOTE: events recorded by the Cluster API controllers are also annotated with ClusterNameLabel, documenting the
+	// name of the Cluster the event belongs to.
+	EventRelatedObjectKindAnnotation = "cluster.x-k8s.io/related-object-kind"
+
+	// EventRelatedObjectNameAnnotation is the annotation set on events about an object different from the object the
+	// event is recorded on, documenting the name of the related object; the related object is in the same namespace of
+	// the object the event is recorded on, or it is cluster-scoped, e.g. a Node.
+	EventRelatedObjectNameAnnotation = "cluster.x-k8s.io/related-object-name"
+)
+
+// ANCHOR_END: EventAnnotations
+
+// ANCHOR: EventReasons
+
+// Event reasons for errors and for the lifecycle of the objects owned by a Cluster API object.
+const (
+	// EventReconcileError (Warning) documents an error reconciling an object.
+	EventReconcileError = "ReconcileError"
+
+	// EventSuccessfulCreate (Normal) documents an object owned by the object the event is recorded on being created.
+	EventSuccessfulCreate = "SuccessfulCreate"
+
+	// EventFailedCreate (Warning) documents an error creating an object owned by the object the event is recorded on.
+	EventFailedCreate = "FailedCreate"
+
+	// EventFailedUpdate (Warning) documents an error updating an object owned by the object the event is recorded on.
+	EventFailedUpdate = "FailedUpdate"
+
+	// EventSuccessfulDelete (Normal) documents an object owned by the object the event is recorded on being deleted.
+	EventSuccessfulDelete = "SuccessfulDelete"
+
+	// EventFailedDelete (Warning) documents an error deleting an object owned by the object the event is recorded on.
+	EventFailedDelete = "FailedDelete"
+
+	// EventSuccessfulScale (Normal) documents an object owned by the object the event is recorded on being scaled.
+	EventSuccessfulScale = "SuccessfulScale"
+
+	// EventFailedScale (Warning) documents an error scaling an object owned by the object the event is recorded on.
+	EventFailedScale = "FailedScale"
+
+	// EventSuccessfulAdopt (Normal) documents an orphan object being adopted by the object the event is recorded on.
+	EventSuccessfulAdopt = "SuccessfulAdopt"
+
+	// EventFailedAdopt (Warning) documents an orphan object which could not be adopted by the object the event is
+	// recorded on.
+	EventFailedAdopt = "FailedAdopt"
+)
+
+// Event reasons for rollouts, i.e. the replacement of the Machines of a MachineDeployment or of a control plane
+// after a change to their spec.
+const (
+	// EventRolloutStarted (Normal) documents a rollout being started.
+	EventRolloutStarted = "RolloutStarted"
+
+	// EventRolloutCompleted (Normal) documents a rollout being completed, i.e. all the Machines are up to date and
+	// available.
+	EventRolloutCompleted = "RolloutCompleted"
+)
+
+// Event reasons for the maintenance of Namespaces.
+const (
+	// EventPausedForMaintenance (Normal) documents an object being paused because its Namespace entered maintenance.
+	EventPausedForMaintenance = "PausedForMaintenance"
+
+	// EventUnpausedAfterMaintenance (Normal) documents an object being unpaused because its Namespace exited
+	// maintenance.
+	EventUnpausedAfterMaintenance = "UnpausedAfterMaintenance"
+)
+
+// Event reasons for control planes.
+const (
+	// EventControlPlaneUnhealthy (Warning) documents a control plane not passing the preflight checks required before
+	// scaling it, e.g. because some of its components are not healthy; the operation is retried.
+	EventControlPlaneUnhealthy = "ControlPlaneUnhealthy"
+)
+
+// Event reasons for the remediation of unhealthy Machines.
+const (
+	// EventMachineMarkedUnhealthy (Normal) documents a Machine being marked as unhealthy by a MachineHealthCheck.
+	EventMachineMarkedUnhealthy = "MachineMarkedUnhealthy"
+
+	// EventDetectedUnhealthy (Normal) documents a MachineHealthCheck detecting an unhealthy Node.
+	EventDetectedUnhealthy = "DetectedUnhealthy"
+
+	// EventRemediationRestricted (Warning) documents a MachineHealthCheck not remediating unhealthy Machines because
+	// too many are unhealthy.
+	EventRemediationRestricted = "RemediationRestricted"
+
+	// EventRemediationStarted (Normal) documents the owner of an unhealthy Machine starting its remediation,
+	// e.g. deleting it.
+	EventRemediationStarted = "RemediationStarted"
+
+	// EventRemediationBlocked (Warning) documents the owner of an unhealthy Machine not remediating it, e.g. because
+	// it is not safe or the maximum number of retries has been reached.
+	EventRemediationBlocked = "RemediationBlocked"
+)
+
+// Event reasons for the deletion of a Machine and of its Node.
+const (
+	// EventDrainStarted (Normal) documents the drain of the Node of a Machine being started.
+	EventDrainStarted = "DrainStarted"
+
+	// EventDrainInProgress (Normal) documents the drain of the Node of a Machine not being completed yet, e.g.
+	// because some Pods are not yet evicted; the drain is retried.
+	EventDrainInProgress = "DrainInProgress"
+
+	// EventSuccessfulDrainNode (Normal) documents the drain of the Node of a Machine being completed.
+	EventSuccessfulDrainNode = "SuccessfulDrainNode"
+
+	// EventFailedDrainNode (Warning) documents an error draining the Node of a Machine.
+	EventFailedDrainNode = "FailedDrainNode"
+
+	// EventNodeVolumesDetached (Normal) documents all the volumes of the Node of a Machine being detached.
+	EventNodeVolumesDetached = "NodeVolumesDetached"
+
+	// EventFailedWaitForVolumeDetach (Warning) documents an error waiting for the volumes of the Node of a Machine
+	// to be detached.
+	EventFailedWaitForVolumeDetach = "FailedWaitForVolumeDetach"
+
+	// EventFailedDeleteNode (Warning) documents an error deleting the Node of a Machine.
+	EventFailedDeleteNode = "FailedDeleteNode"
+)
+
+// Event reasons for the Nodes of Machines and MachinePools.
+const (
+	// EventSuccessfulSetNodeRef (Normal) documents the Node of a Machine being found.
+	EventSuccessfulSetNodeRef = "SuccessfulSetNodeRef"
+
+	// EventSuccessfulSetNodeRefs (Normal) documents the Nodes of a MachinePool being found.
+	EventSuccessfulSetNodeRefs = "SuccessfulSetNodeRefs"
+
+	// EventFailedSetNodeRef (Warning) documents an error finding the Node of a Machine or of a MachinePool.
+	EventFailedSetNodeRef = "FailedSetNodeRef"
+
+	// EventSuccessfulSetInterruptibleNodeLabel (Normal) documents the interruptible label being set on the Node of
+	// a Machine.
+	EventSuccessfulSetInterruptibleNodeLabel = "SuccessfulSetInterruptibleNodeLabel"
+)
+
+// Event reasons for hooks, i.e. Runtime SDK lifecycle hooks and Machine deletion hooks.
+const (
+	// EventHookBlocking (Normal) documents a hook blocking an operation, e.g. a BeforeClusterUpgrade Runtime
+	// Extension blocking the upgrade of a Cluster, or a pre-drain hook annotation blocking the deletion of a Machine.
+	EventHookBlocking = "HookBlocking"
+)
+
+// Event reasons for Clusters.
+// NOTE: the Cluster controller also records events with the phase of the Cluster as reason, e.g. Provisioned,
+// when the phase changes.
+const (
+	// EventInfrastructureReady (Normal) documents the InfrastructureReady status of a Cluster changing.
+	EventInfrastructureReady = "InfrastructureReady"
+
+	// EventControlPlaneReady (Normal) documents the ControlPlaneReady status of a Cluster changing.
+	EventControlPlaneReady = "ControlPlaneReady"
+
+	// EventDeleted (Normal) documents an object being deleted.
+	EventDeleted = "Deleted"
+
+	// EventTopologyCreate (Normal) documents an object of a Cluster with a managed topology being created.
+	EventTopologyCreate = "TopologyCreate"
+
+	// EventTopologyUpdate (Normal) documents an object of a Cluster with a managed topology being updated.
+	EventTopologyUpdate = "TopologyUpdate"
+
+	// EventTopologyDelete (Normal) documents an object of a Cluster with a managed topology being deleted.
+	EventTopologyDelete = "TopologyDelete"
+
+	// EventKubeconfigRotated (Normal) documents the client certificates of the kubeconfig of a Cluster being rotated.
+	EventKubeconfigRotated = "KubeconfigRotated"
+
+	// EventKubeconfigRotationFailed (Warning) documents an error rotating the client certificates of the kubeconfig
+	// of a Cluster.
+	EventKubeconfigRotationFailed = "KubeconfigRotationFailed"
+
+	// EventRemoteConnectionEstablished (Normal) documents the connection to the workload cluster being established.
+	EventRemoteConnectionEstablished = "RemoteConnectionEstablished"
+
+	// EventRemoteConnectionLost (Warning) documents the connection to the workload cluster being lost.
+	EventRemoteConnectionLost = "RemoteConnectionLost"
+
+	// EventRemoteConnectionRefreshed (Normal) documents the connection to the workload cluster being re-established
+	// with the credentials of an updated kubeconfig.
+	EventRemoteConnectionRefreshed = "RemoteConnectionRefreshed"
+)
+
+// ANCHOR_END: EventReasons
@@ -259,6 +259,12 @@ type MachineDeploymentStatus struct {
 	// Conditions defines current service state of the MachineDeployment.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
+
+	// RolloutHistory records the last changes which triggered a rollout of the Machines of the
+	// MachineDeployment, most recent first.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	RolloutHistory []RolloutTrigger `json:"rolloutHistory,omitempty"`
 }
 
 // ANCHOR_END: MachineDeploymentStatus
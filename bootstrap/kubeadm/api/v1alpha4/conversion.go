@@ -49,6 +49,15 @@ func (src *KubeadmConfig) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.Ignition = restored.Spec.Ignition
+	dst.Spec.Output = restored.Spec.Output
+	if restored.Spec.ClusterConfiguration != nil && dst.Spec.ClusterConfiguration != nil {
+		dst.Spec.ClusterConfiguration.APIServer.ExtraEnvs = restored.Spec.ClusterConfiguration.APIServer.ExtraEnvs
+		dst.Spec.ClusterConfiguration.ControllerManager.ExtraEnvs = restored.Spec.ClusterConfiguration.ControllerManager.ExtraEnvs
+		dst.Spec.ClusterConfiguration.Scheduler.ExtraEnvs = restored.Spec.ClusterConfiguration.Scheduler.ExtraEnvs
+		if restored.Spec.ClusterConfiguration.Etcd.Local != nil && dst.Spec.ClusterConfiguration.Etcd.Local != nil {
+			dst.Spec.ClusterConfiguration.Etcd.Local.ExtraEnvs = restored.Spec.ClusterConfiguration.Etcd.Local.ExtraEnvs
+		}
+	}
 	if restored.Spec.InitConfiguration != nil {
 		if dst.Spec.InitConfiguration == nil {
 			dst.Spec.InitConfiguration = &bootstrapv1.InitConfiguration{}
@@ -62,6 +71,12 @@ func (src *KubeadmConfig) ConvertTo(dstRaw conversion.Hub) error {
 		}
 		dst.Spec.JoinConfiguration.Patches = restored.Spec.JoinConfiguration.Patches
 		dst.Spec.JoinConfiguration.SkipPhases = restored.Spec.JoinConfiguration.SkipPhases
+		if restored.Spec.JoinConfiguration.Discovery.File != nil && restored.Spec.JoinConfiguration.Discovery.File.KubeConfig != nil {
+			if dst.Spec.JoinConfiguration.Discovery.File == nil {
+				dst.Spec.JoinConfiguration.Discovery.File = &bootstrapv1.FileDiscovery{}
+			}
+			dst.Spec.JoinConfiguration.Discovery.File.KubeConfig = restored.Spec.JoinConfiguration.Discovery.File.KubeConfig
+		}
 	}
 
 	if restored.Spec.JoinConfiguration != nil && restored.Spec.JoinConfiguration.NodeRegistration.ImagePullPolicy != "" {
@@ -78,6 +93,8 @@ func (src *KubeadmConfig) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.InitConfiguration.NodeRegistration.ImagePullPolicy = restored.Spec.InitConfiguration.NodeRegistration.ImagePullPolicy
 	}
 
+	dst.Status.FileSourcesHash = restored.Status.FileSourcesHash
+
 	return nil
 }
 
@@ -130,6 +147,15 @@ func (src *KubeadmConfigTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
 
 	dst.Spec.Template.Spec.Ignition = restored.Spec.Template.Spec.Ignition
+	dst.Spec.Template.Spec.Output = restored.Spec.Template.Spec.Output
+	if restored.Spec.Template.Spec.ClusterConfiguration != nil && dst.Spec.Template.Spec.ClusterConfiguration != nil {
+		dst.Spec.Template.Spec.ClusterConfiguration.APIServer.ExtraEnvs = restored.Spec.Template.Spec.ClusterConfiguration.APIServer.ExtraEnvs
+		dst.Spec.Template.Spec.ClusterConfiguration.ControllerManager.ExtraEnvs = restored.Spec.Template.Spec.ClusterConfiguration.ControllerManager.ExtraEnvs
+		dst.Spec.Template.Spec.ClusterConfiguration.Scheduler.ExtraEnvs = restored.Spec.Template.Spec.ClusterConfiguration.Scheduler.ExtraEnvs
+		if restored.Spec.Template.Spec.ClusterConfiguration.Etcd.Local != nil && dst.Spec.Template.Spec.ClusterConfiguration.Etcd.Local != nil {
+			dst.Spec.Template.Spec.ClusterConfiguration.Etcd.Local.ExtraEnvs = restored.Spec.Template.Spec.ClusterConfiguration.Etcd.Local.ExtraEnvs
+		}
+	}
 	if restored.Spec.Template.Spec.InitConfiguration != nil {
 		if dst.Spec.Template.Spec.InitConfiguration == nil {
 			dst.Spec.Template.Spec.InitConfiguration = &bootstrapv1.InitConfiguration{}
@@ -143,6 +169,12 @@ func (src *KubeadmConfigTemplate) ConvertTo(dstRaw conversion.Hub) error {
 		}
 		dst.Spec.Template.Spec.JoinConfiguration.Patches = restored.Spec.Template.Spec.JoinConfiguration.Patches
 		dst.Spec.Template.Spec.JoinConfiguration.SkipPhases = restored.Spec.Template.Spec.JoinConfiguration.SkipPhases
+		if restored.Spec.Template.Spec.JoinConfiguration.Discovery.File != nil && restored.Spec.Template.Spec.JoinConfiguration.Discovery.File.KubeConfig != nil {
+			if dst.Spec.Template.Spec.JoinConfiguration.Discovery.File == nil {
+				dst.Spec.Template.Spec.JoinConfiguration.Discovery.File = &bootstrapv1.FileDiscovery{}
+			}
+			dst.Spec.Template.Spec.JoinConfiguration.Discovery.File.KubeConfig = restored.Spec.Template.Spec.JoinConfiguration.Discovery.File.KubeConfig
+		}
 	}
 
 	if restored.Spec.Template.Spec.JoinConfiguration != nil && restored.Spec.Template.Spec.JoinConfiguration.NodeRegistration.ImagePullPolicy != "" {
@@ -186,7 +218,7 @@ func (dst *KubeadmConfigTemplateList) ConvertFrom(srcRaw conversion.Hub) error {
 
 // Convert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec is an autogenerated conversion function.
 func Convert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec(in *bootstrapv1.KubeadmConfigSpec, out *KubeadmConfigSpec, s apiconversion.Scope) error {
-	// KubeadmConfigSpec.Ignition does not exist in kubeadm v1alpha4 API.
+	// KubeadmConfigSpec.Ignition and KubeadmConfigSpec.Output do not exist in kubeadm v1alpha4 API.
 	return autoConvert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec(in, out, s)
 }
 
@@ -205,6 +237,33 @@ func Convert_v1beta1_File_To_v1alpha4_File(in *bootstrapv1.File, out *File, s ap
 	return autoConvert_v1beta1_File_To_v1alpha4_File(in, out, s)
 }
 
+func Convert_v1beta1_FileSource_To_v1alpha4_FileSource(in *bootstrapv1.FileSource, out *FileSource, s apiconversion.Scope) error {
+	// FileSource.ConfigMap does not exist in kubeadm v1alpha4 API.
+	// NOTE: If ConfigMap is set, Secret is empty, so the v1alpha4 file source has an empty secret; the
+	// ConfigMap is restored from the annotation on up-conversion, together with the other fields of the files.
+	return autoConvert_v1beta1_FileSource_To_v1alpha4_FileSource(in, out, s)
+}
+
+func Convert_v1beta1_FileDiscovery_To_v1alpha4_FileDiscovery(in *bootstrapv1.FileDiscovery, out *FileDiscovery, s apiconversion.Scope) error {
+	// FileDiscovery.KubeConfig does not exist in kubeadm v1alpha4 API.
+	return autoConvert_v1beta1_FileDiscovery_To_v1alpha4_FileDiscovery(in, out, s)
+}
+
+func Convert_v1beta1_ControlPlaneComponent_To_v1alpha4_ControlPlaneComponent(in *bootstrapv1.ControlPlaneComponent, out *ControlPlaneComponent, s apiconversion.Scope) error {
+	// ControlPlaneComponent.ExtraEnvs does not exist in kubeadm v1alpha4 API.
+	return autoConvert_v1beta1_ControlPlaneComponent_To_v1alpha4_ControlPlaneComponent(in, out, s)
+}
+
+func Convert_v1beta1_LocalEtcd_To_v1alpha4_LocalEtcd(in *bootstrapv1.LocalEtcd, out *LocalEtcd, s apiconversion.Scope) error {
+	// LocalEtcd.ExtraEnvs does not exist in kubeadm v1alpha4 API.
+	return autoConvert_v1beta1_LocalEtcd_To_v1alpha4_LocalEtcd(in, out, s)
+}
+
+func Convert_v1beta1_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus(in *bootstrapv1.KubeadmConfigStatus, out *KubeadmConfigStatus, s apiconversion.Scope) error {
+	// KubeadmConfigStatus.FileSourcesHash does not exist in kubeadm v1alpha4 API.
+	return autoConvert_v1beta1_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus(in, out, s)
+}
+
 func Convert_v1beta1_User_To_v1alpha4_User(in *bootstrapv1.User, out *User, s apiconversion.Scope) error {
 	// User.PasswdFrom does not exist in kubeadm v1alpha4 API.
 	return autoConvert_v1beta1_User_To_v1alpha4_User(in, out, s)
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
 	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 
@@ -45,6 +46,35 @@ func TestFuzzyConversion(t *testing.T) {
 	}))
 }
 
+func TestConvertConfigMapFileSource(t *testing.T) {
+	g := NewWithT(t)
+
+	hub := &bootstrapv1.KubeadmConfig{
+		Spec: bootstrapv1.KubeadmConfigSpec{
+			Files: []bootstrapv1.File{
+				{
+					Path: "/etc/foo",
+					ContentFrom: &bootstrapv1.FileSource{
+						ConfigMap: &bootstrapv1.ConfigMapFileSource{Name: "foo", Key: "bar"},
+					},
+				},
+			},
+		},
+	}
+
+	// The config map does not exist in v1alpha4, so the file source has an empty secret.
+	spoke := &KubeadmConfig{}
+	g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+	g.Expect(spoke.Spec.Files).To(HaveLen(1))
+	g.Expect(spoke.Spec.Files[0].ContentFrom.Secret).To(Equal(SecretFileSource{}))
+
+	// The config map is restored on up-conversion, and the secret stays empty.
+	restored := &bootstrapv1.KubeadmConfig{}
+	g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+	g.Expect(restored.Spec.Files).To(Equal(hub.Spec.Files))
+	g.Expect(restored.Spec.Files[0].ContentFrom.Secret).To(Equal(bootstrapv1.SecretFileSource{}))
+}
+
 func fuzzFuncs(_ runtimeserializer.CodecFactory) []interface{} {
 	return []interface{}{
 		// This custom functions are needed when ConvertTo/ConvertFrom functions
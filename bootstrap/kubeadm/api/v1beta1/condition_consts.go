@@ -35,6 +35,10 @@ const (
 	// the KubeadmConfig controller ensure this pre-condition is satisfied.
 	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
 
+	// WaitingForDiskSetupVariablesReason (Severity=Info) documents a bootstrap secret generation process
+	// waiting for the InfraMachine to publish the variables referenced by spec.diskSetup or spec.mounts.
+	WaitingForDiskSetupVariablesReason = "WaitingForDiskSetupVariables"
+
 	// DataSecretGenerationFailedReason (Severity=Warning) documents a KubeadmConfig controller detecting
 	// an error while generating a data secret; those kind of errors are usually due to misconfigurations
 	// and user intervention is required to get them fixed.
@@ -59,3 +63,35 @@ const (
 	// an error while retrieving certificates for a joining node.
 	CertificatesCorruptedReason = "CertificatesCorrupted"
 )
+
+const (
+	// FileSourcesUpToDateCondition documents that the content of the ConfigMaps and Secrets referenced by
+	// spec.files did not change after the bootstrap data has been generated.
+	//
+	// NOTE: Bootstrap data is generated only once, so changes to the referenced objects are applied only by
+	// replacing the Machine. KubeadmControlPlane rolls out its Machines automatically when this condition is false,
+	// while for other owners the rollout must be triggered explicitly, e.g. via spec.rolloutAfter.
+	// The condition is part of the KubeadmConfig Ready condition; it is not reported on the Machine, because
+	// the Machine stops mirroring the bootstrap conditions once the bootstrap data has been generated.
+	FileSourcesUpToDateCondition clusterv1.ConditionType = "FileSourcesUpToDate"
+
+	// FileSourcesChangedReason (Severity=Warning) documents the content of one of the ConfigMaps or Secrets
+	// referenced by spec.files being changed after the bootstrap data has been generated.
+	FileSourcesChangedReason = "FileSourcesChanged"
+
+	// FileSourcesResolutionFailedReason (Severity=Warning) documents a KubeadmConfig controller failing to
+	// read one of the ConfigMaps or Secrets referenced by spec.files after the bootstrap data has been generated.
+	FileSourcesResolutionFailedReason = "FileSourcesResolutionFailed"
+)
+
+const (
+	// SecureChannelAvailableCondition documents that the bootstrap data is delivered over the secure channel,
+	// as requested by the infrastructure machine using the cluster.x-k8s.io/bootstrap-data-delivery annotation.
+	//
+	// NOTE: The condition exists only if the infrastructure machine requests the secure channel.
+	SecureChannelAvailableCondition clusterv1.ConditionType = "SecureChannelAvailable"
+
+	// SecureChannelNotSupportedReason (Severity=Warning) documents the bootstrap data being delivered using the
+	// default delivery, because the secure channel is not supported for the bootstrap data format.
+	SecureChannelNotSupportedReason = "SecureChannelNotSupported"
+)
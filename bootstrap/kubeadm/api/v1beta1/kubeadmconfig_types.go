@@ -18,8 +18,14 @@ package v1beta1
 
 import (
 	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -27,7 +33,7 @@ import (
 )
 
 // Format specifies the output format of the bootstrap data
-// +kubebuilder:validation:Enum=cloud-config;ignition
+// +kubebuilder:validation:Enum=cloud-config;ignition;shell
 type Format string
 
 const (
@@ -36,15 +42,24 @@ const (
 
 	// Ignition make the bootstrap data to be of Ignition format.
 	Ignition Format = "ignition"
+
+	// Shell make the bootstrap data to be a self-contained shell script.
+	Shell Format = "shell"
 )
 
 var (
 	cannotUseWithIgnition                            = fmt.Sprintf("not supported when spec.format is set to: %q", Ignition)
+	cannotUseWithIgnitionVersion23                   = fmt.Sprintf("not supported when spec.format is set to: %q, unless spec.ignition.version is set to: %q", Ignition, IgnitionVersion34)
+	cannotUseWithShell                               = fmt.Sprintf("not supported when spec.format is set to: %q", Shell)
 	conflictingFileSourceMsg                         = "only one of content or contentFrom may be specified for a single file"
 	conflictingUserSourceMsg                         = "only one of passwd or passwdFrom may be specified for a single user"
 	kubeadmBootstrapFormatIgnitionFeatureDisabledMsg = "can be set only if the KubeadmBootstrapFormatIgnition feature gate is enabled"
+	conflictingFileContentFromMsg                    = "only one of secret or configMap may be specified for a single file source"
+	missingFileContentFromMsg                        = "one of secret or configMap must be specified for a single file source"
 	missingSecretNameMsg                             = "secret file source must specify non-empty secret name"
 	missingSecretKeyMsg                              = "secret file source must specify non-empty secret key"
+	missingConfigMapNameMsg                          = "configMap file source must specify non-empty configMap name"
+	missingConfigMapKeyMsg                           = "configMap file source must specify non-empty configMap key"
 	pathConflictMsg                                  = "path property must be unique among all files"
 )
 
@@ -68,10 +83,15 @@ type KubeadmConfigSpec struct {
 	Files []File `json:"files,omitempty"`
 
 	// DiskSetup specifies options for the creation of partition tables and file systems on devices.
+	// String fields can reference the variables published by the InfraMachine in status.bootstrapVariables
+	// using the Go template syntax, e.g. "{{ .ephemeralDevice }}", so the same configuration can be used
+	// for machines with different disk layouts.
 	// +optional
 	DiskSetup *DiskSetup `json:"diskSetup,omitempty"`
 
 	// Mounts specifies a list of mount points to be setup.
+	// Entries can reference the variables published by the InfraMachine in status.bootstrapVariables
+	// using the Go template syntax, e.g. "{{ .ephemeralDevice }}".
 	// +optional
 	Mounts []MountPoints `json:"mounts,omitempty"`
 
@@ -91,7 +111,9 @@ type KubeadmConfigSpec struct {
 	// +optional
 	NTP *NTP `json:"ntp,omitempty"`
 
-	// Format specifies the output format of the bootstrap data
+	// Format specifies the output format of the bootstrap data.
+	// Use "shell" for OS images executing the bootstrap data directly as a script,
+	// without cloud-init or Ignition.
 	// +optional
 	Format Format `json:"format,omitempty"`
 
@@ -119,6 +141,12 @@ type KubeadmConfigSpec struct {
 	// Ignition contains Ignition specific configuration.
 	// +optional
 	Ignition *IgnitionSpec `json:"ignition,omitempty"`
+
+	// Output specifies how the bootstrap data is packaged, e.g. as a multi-part MIME document
+	// or gzip compressed, for platforms with strict user data requirements or size limits.
+	// Output is supported only when spec.format is set to "cloud-config".
+	// +optional
+	Output *OutputSpec `json:"output,omitempty"`
 }
 
 // Default defaults a KubeadmConfigSpec.
@@ -136,11 +164,26 @@ func (c *KubeadmConfigSpec) Default() {
 
 // Validate ensures the KubeadmConfigSpec is valid.
 func (c *KubeadmConfigSpec) Validate(pathPrefix *field.Path) field.ErrorList {
+	return c.validate(nil, pathPrefix)
+}
+
+// ValidateUpdate ensures the KubeadmConfigSpec is valid when updating an object with oldSpec.
+// NOTE: The rules for the Ignition mounts are enforced only when the mounts or the format change,
+// so existing objects created before these rules have been introduced can still be updated.
+func (c *KubeadmConfigSpec) ValidateUpdate(oldSpec *KubeadmConfigSpec, pathPrefix *field.Path) field.ErrorList {
+	return c.validate(oldSpec, pathPrefix)
+}
+
+func (c *KubeadmConfigSpec) validate(oldSpec *KubeadmConfigSpec, pathPrefix *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
 	allErrs = append(allErrs, c.validateFiles(pathPrefix)...)
 	allErrs = append(allErrs, c.validateUsers(pathPrefix)...)
-	allErrs = append(allErrs, c.validateIgnition(pathPrefix)...)
+	allErrs = append(allErrs, c.validateIgnition(oldSpec, pathPrefix)...)
+	allErrs = append(allErrs, c.validateShell(pathPrefix)...)
+	allErrs = append(allErrs, c.validateOutput(pathPrefix)...)
+	allErrs = append(allErrs, c.validateJoinDiscovery(pathPrefix)...)
+	allErrs = append(allErrs, c.validateDiskSetupVariables(pathPrefix)...)
 
 	return allErrs
 }
@@ -162,10 +205,48 @@ func (c *KubeadmConfigSpec) validateFiles(pathPrefix *field.Path) field.ErrorLis
 				),
 			)
 		}
-		// n.b.: if we ever add types besides Secret as a ContentFrom
+		// n.b.: if we ever add types besides Secret and ConfigMap as a ContentFrom
 		// Source, we must add webhook validation here for one of the
 		// sources being non-nil.
-		if file.ContentFrom != nil {
+		if file.ContentFrom != nil && file.ContentFrom.ConfigMap != nil {
+			if file.ContentFrom.Secret != (SecretFileSource{}) {
+				allErrs = append(
+					allErrs,
+					field.Invalid(
+						pathPrefix.Child("files").Index(i).Child("contentFrom"),
+						file.ContentFrom,
+						conflictingFileContentFromMsg,
+					),
+				)
+			}
+			if file.ContentFrom.ConfigMap.Name == "" {
+				allErrs = append(
+					allErrs,
+					field.Required(
+						pathPrefix.Child("files").Index(i).Child("contentFrom", "configMap", "name"),
+						missingConfigMapNameMsg,
+					),
+				)
+			}
+			if file.ContentFrom.ConfigMap.Key == "" {
+				allErrs = append(
+					allErrs,
+					field.Required(
+						pathPrefix.Child("files").Index(i).Child("contentFrom", "configMap", "key"),
+						missingConfigMapKeyMsg,
+					),
+				)
+			}
+		} else if file.ContentFrom != nil && file.ContentFrom.Secret == (SecretFileSource{}) {
+			// NOTE: Secret is not a pointer, so an empty secret is the same as a secret not being set.
+			allErrs = append(
+				allErrs,
+				field.Required(
+					pathPrefix.Child("files").Index(i).Child("contentFrom"),
+					missingFileContentFromMsg,
+				),
+			)
+		} else if file.ContentFrom != nil {
 			if file.ContentFrom.Secret.Name == "" {
 				allErrs = append(
 					allErrs,
@@ -245,7 +326,7 @@ func (c *KubeadmConfigSpec) validateUsers(pathPrefix *field.Path) field.ErrorLis
 	return allErrs
 }
 
-func (c *KubeadmConfigSpec) validateIgnition(pathPrefix *field.Path) field.ErrorList {
+func (c *KubeadmConfigSpec) validateIgnition(oldSpec *KubeadmConfigSpec, pathPrefix *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
 	if !feature.Gates.Enabled(feature.KubeadmBootstrapFormatIgnition) {
@@ -277,18 +358,6 @@ func (c *KubeadmConfigSpec) validateIgnition(pathPrefix *field.Path) field.Error
 		return allErrs
 	}
 
-	for i, user := range c.Users {
-		if user.Inactive != nil && *user.Inactive {
-			allErrs = append(
-				allErrs,
-				field.Forbidden(
-					pathPrefix.Child("users").Index(i).Child("inactive"),
-					cannotUseWithIgnition,
-				),
-			)
-		}
-	}
-
 	if c.UseExperimentalRetryJoin {
 		allErrs = append(
 			allErrs,
@@ -299,18 +368,41 @@ func (c *KubeadmConfigSpec) validateIgnition(pathPrefix *field.Path) field.Error
 		)
 	}
 
-	for i, file := range c.Files {
-		if file.Encoding == Gzip || file.Encoding == GzipBase64 {
-			allErrs = append(
-				allErrs,
-				field.Forbidden(
-					pathPrefix.Child("files").Index(i).Child("encoding"),
-					cannotUseWithIgnition,
-				),
-			)
+	// Inactive users and compressed files are supported only by the Ignition v3 configuration.
+	if c.Ignition == nil || c.Ignition.Version != IgnitionVersion34 {
+		for i, user := range c.Users {
+			if user.Inactive != nil && *user.Inactive {
+				allErrs = append(
+					allErrs,
+					field.Forbidden(
+						pathPrefix.Child("users").Index(i).Child("inactive"),
+						cannotUseWithIgnitionVersion23,
+					),
+				)
+			}
+		}
+
+		for i, file := range c.Files {
+			if file.Encoding == Gzip || file.Encoding == GzipBase64 {
+				allErrs = append(
+					allErrs,
+					field.Forbidden(
+						pathPrefix.Child("files").Index(i).Child("encoding"),
+						cannotUseWithIgnitionVersion23,
+					),
+				)
+			}
 		}
 	}
 
+	if c.Ignition != nil {
+		allErrs = append(allErrs, c.Ignition.validateSystemdUnits(pathPrefix.Child("ignition", "systemdUnits"))...)
+	}
+
+	if oldSpec == nil || oldSpec.Format != c.Format || !reflect.DeepEqual(oldSpec.Mounts, c.Mounts) {
+		allErrs = append(allErrs, c.validateIgnitionMounts(pathPrefix)...)
+	}
+
 	if c.DiskSetup == nil {
 		return allErrs
 	}
@@ -343,12 +435,55 @@ func (c *KubeadmConfigSpec) validateIgnition(pathPrefix *field.Path) field.Error
 			)
 		}
 
-		if fs.Partition != nil {
+		// Variables are validated when the bootstrap data is generated.
+		if fs.Partition != nil && *fs.Partition != "none" && !strings.Contains(*fs.Partition, "{{") {
+			if _, err := strconv.Atoi(*fs.Partition); err != nil {
+				allErrs = append(
+					allErrs,
+					field.Invalid(
+						pathPrefix.Child("diskSetup", "filesystems").Index(i).Child("partition"),
+						*fs.Partition,
+						fmt.Sprintf("only %q or a partition number are supported when spec.format is set to %q", "none", Ignition),
+					),
+				)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateIgnitionMounts ensures the mounts reference the filesystems defined in spec.diskSetup, as required by Ignition.
+func (c *KubeadmConfigSpec) validateIgnitionMounts(pathPrefix *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	filesystemLabels := sets.Set[string]{}
+	if c.DiskSetup != nil {
+		for _, fs := range c.DiskSetup.Filesystems {
+			filesystemLabels.Insert(fs.Label)
+		}
+	}
+
+	for i, mount := range c.Mounts {
+		if len(mount) < 2 {
 			allErrs = append(
 				allErrs,
-				field.Forbidden(
-					pathPrefix.Child("diskSetup", "filesystems").Index(i).Child("partition"),
-					cannotUseWithIgnition,
+				field.Invalid(
+					pathPrefix.Child("mounts").Index(i),
+					mount,
+					fmt.Sprintf("must contain at least a filesystem label and a mount point when spec.format is set to %q", Ignition),
+				),
+			)
+			continue
+		}
+
+		if !filesystemLabels.Has(mount[0]) {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					pathPrefix.Child("mounts").Index(i).Index(0),
+					mount[0],
+					fmt.Sprintf("must reference the label of a filesystem defined in spec.diskSetup.filesystems when spec.format is set to %q", Ignition),
 				),
 			)
 		}
@@ -357,11 +492,238 @@ func (c *KubeadmConfigSpec) validateIgnition(pathPrefix *field.Path) field.Error
 	return allErrs
 }
 
+func (c *KubeadmConfigSpec) validateShell(pathPrefix *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if c.Format != Shell {
+		return allErrs
+	}
+
+	if c.DiskSetup != nil {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("diskSetup"), cannotUseWithShell))
+	}
+
+	if len(c.Mounts) > 0 {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("mounts"), cannotUseWithShell))
+	}
+
+	if c.NTP != nil {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("ntp"), cannotUseWithShell))
+	}
+
+	if c.UseExperimentalRetryJoin {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("useExperimentalRetryJoin"), cannotUseWithShell))
+	}
+
+	return allErrs
+}
+
+func (c *KubeadmConfigSpec) validateOutput(pathPrefix *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if c.Output == nil {
+		return allErrs
+	}
+
+	if c.Format != "" && c.Format != CloudConfig {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				pathPrefix.Child("format"),
+				c.Format,
+				fmt.Sprintf("must be set to %q if spec.output is set", CloudConfig),
+			),
+		)
+	}
+
+	if c.Output.MultiPart == nil {
+		return allErrs
+	}
+
+	for i, part := range c.Output.MultiPart.Parts {
+		if part.Content == "" {
+			allErrs = append(allErrs, field.Required(pathPrefix.Child("output", "multiPart", "parts").Index(i).Child("content"), "content is required"))
+		}
+	}
+
+	return allErrs
+}
+
+func (c *KubeadmConfigSpec) validateJoinDiscovery(pathPrefix *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if c.JoinConfiguration == nil || c.JoinConfiguration.Discovery.File == nil || c.JoinConfiguration.Discovery.File.KubeConfig == nil {
+		return allErrs
+	}
+
+	discoveryPath := pathPrefix.Child("joinConfiguration", "discovery")
+	file := c.JoinConfiguration.Discovery.File
+
+	if c.JoinConfiguration.Discovery.BootstrapToken != nil {
+		allErrs = append(allErrs,
+			field.Forbidden(
+				discoveryPath.Child("bootstrapToken"),
+				"cannot be set when discovery.file.kubeConfig is set",
+			),
+		)
+	}
+
+	if !path.IsAbs(file.KubeConfigPath) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				discoveryPath.Child("file", "kubeConfigPath"),
+				file.KubeConfigPath,
+				"must be an absolute file path when discovery.file.kubeConfig is set",
+			),
+		)
+	}
+
+	userPath := discoveryPath.Child("file", "kubeConfig", "user")
+	user := file.KubeConfig.User
+	switch {
+	case user.AuthProvider == nil && user.Exec == nil:
+		allErrs = append(allErrs, field.Required(userPath, "one of authProvider or exec must be set"))
+	case user.AuthProvider != nil && user.Exec != nil:
+		allErrs = append(allErrs, field.Forbidden(userPath, "only one of authProvider or exec can be set"))
+	}
+
+	return allErrs
+}
+
+// validateDiskSetupVariables ensures that the references to the variables published by the InfraMachine
+// in diskSetup and mounts are valid templates; the variables are resolved when generating the bootstrap data.
+func (c *KubeadmConfigSpec) validateDiskSetupVariables(pathPrefix *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	validate := func(fldPath *field.Path, value string) {
+		if !strings.Contains(value, "{{") {
+			return
+		}
+		if _, err := template.New(fldPath.String()).Parse(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("invalid variable reference: %v", err)))
+		}
+	}
+
+	if c.DiskSetup != nil {
+		for i, partition := range c.DiskSetup.Partitions {
+			partitionPath := pathPrefix.Child("diskSetup", "partitions").Index(i)
+			validate(partitionPath.Child("device"), partition.Device)
+		}
+		for i, fs := range c.DiskSetup.Filesystems {
+			fsPath := pathPrefix.Child("diskSetup", "filesystems").Index(i)
+			validate(fsPath.Child("device"), fs.Device)
+			validate(fsPath.Child("label"), fs.Label)
+			if fs.Partition != nil {
+				validate(fsPath.Child("partition"), *fs.Partition)
+			}
+			for j, opt := range fs.ExtraOpts {
+				validate(fsPath.Child("extraOpts").Index(j), opt)
+			}
+		}
+	}
+
+	for i, mount := range c.Mounts {
+		for j, entry := range mount {
+			validate(pathPrefix.Child("mounts").Index(i).Index(j), entry)
+		}
+	}
+
+	return allErrs
+}
+
+// reservedSystemdUnits are the systemd units generated by the bootstrap provider, which cannot
+// be redefined using spec.ignition.systemdUnits.
+var reservedSystemdUnits = sets.New[string]("kubeadm.service")
+
+// systemdUnitTypes are the unit types accepted in spec.ignition.systemdUnits.
+var systemdUnitTypes = sets.New[string](
+	".service", ".socket", ".device", ".mount", ".automount", ".swap",
+	".target", ".path", ".timer", ".slice", ".scope",
+)
+
+func (i *IgnitionSpec) validateSystemdUnits(pathPrefix *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := sets.Set[string]{}
+	for idx, unit := range i.SystemdUnits {
+		namePath := pathPrefix.Index(idx).Child("name")
+		switch {
+		case unit.Name == "":
+			allErrs = append(allErrs, field.Required(namePath, "name is required"))
+		case !systemdUnitTypes.Has(path.Ext(unit.Name)):
+			allErrs = append(allErrs, field.Invalid(namePath, unit.Name, fmt.Sprintf("must end with a valid unit type suffix, one of %v", sets.List(systemdUnitTypes))))
+		case reservedSystemdUnits.Has(unit.Name):
+			allErrs = append(allErrs, field.Forbidden(namePath, "unit is managed by the bootstrap provider"))
+		case names.Has(unit.Name):
+			allErrs = append(allErrs, field.Duplicate(namePath, unit.Name))
+		}
+		names.Insert(unit.Name)
+
+		for j, dropIn := range unit.DropIns {
+			if dropIn.Name == "" {
+				allErrs = append(allErrs, field.Required(pathPrefix.Index(idx).Child("dropIns").Index(j).Child("name"), "name is required"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// IgnitionVersion defines the Ignition specification version used for the generated bootstrap data.
+// +kubebuilder:validation:Enum="2.3";"3.4"
+type IgnitionVersion string
+
+const (
+	// IgnitionVersion23 generates bootstrap data using the Ignition 2.3 specification.
+	IgnitionVersion23 IgnitionVersion = "2.3"
+
+	// IgnitionVersion34 generates bootstrap data using the Ignition 3.4 specification.
+	IgnitionVersion34 IgnitionVersion = "3.4"
+)
+
 // IgnitionSpec contains Ignition specific configuration.
 type IgnitionSpec struct {
+	// Version is the Ignition specification version used for the generated bootstrap data.
+	// Use "3.4" for distributions which only support Ignition v3, e.g. Fedora CoreOS.
+	// Defaults to "2.3".
+	// +optional
+	Version IgnitionVersion `json:"version,omitempty"`
+
 	// ContainerLinuxConfig contains CLC specific configuration.
 	// +optional
 	ContainerLinuxConfig *ContainerLinuxConfig `json:"containerLinuxConfig,omitempty"`
+
+	// SystemdUnits specifies extra systemd units to be installed on the machine, in addition
+	// to the ones generated by the bootstrap provider.
+	// +optional
+	SystemdUnits []SystemdUnit `json:"systemdUnits,omitempty"`
+}
+
+// SystemdUnit defines a systemd unit to be installed using Ignition.
+type SystemdUnit struct {
+	// Name is the name of the unit, including its type suffix, e.g. "containerd.service".
+	Name string `json:"name"`
+
+	// Enabled specifies whether the unit should be enabled.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Contents is the contents of the unit file.
+	// +optional
+	Contents string `json:"contents,omitempty"`
+
+	// DropIns specifies drop-ins to be installed for the unit.
+	// +optional
+	DropIns []SystemdUnitDropIn `json:"dropIns,omitempty"`
+}
+
+// SystemdUnitDropIn defines a drop-in for a systemd unit.
+type SystemdUnitDropIn struct {
+	// Name is the name of the drop-in, e.g. "10-override.conf".
+	Name string `json:"name"`
+
+	// Contents is the contents of the drop-in.
+	Contents string `json:"contents"`
 }
 
 // ContainerLinuxConfig contains CLC-specific configuration.
@@ -380,6 +742,64 @@ type ContainerLinuxConfig struct {
 	Strict bool `json:"strict,omitempty"`
 }
 
+// OutputCompression defines the compression applied to the bootstrap data.
+// +kubebuilder:validation:Enum=gzip
+type OutputCompression string
+
+const (
+	// GzipOutputCompression compresses the bootstrap data with gzip.
+	GzipOutputCompression OutputCompression = "gzip"
+)
+
+// OutputSpec defines how the bootstrap data is packaged.
+type OutputSpec struct {
+	// MultiPart packages the bootstrap data as a multi-part MIME document, with the cloud-config
+	// generated by the bootstrap provider as the first part followed by the given parts.
+	// +optional
+	MultiPart *MultiPartOutput `json:"multiPart,omitempty"`
+
+	// Compression specifies the compression applied to the bootstrap data; if MultiPart is set,
+	// the whole multi-part MIME document is compressed.
+	// +optional
+	Compression OutputCompression `json:"compression,omitempty"`
+}
+
+// MultiPartOutput defines the additional parts of a multi-part MIME bootstrap data.
+type MultiPartOutput struct {
+	// Parts specifies the parts appended to the multi-part MIME document, in order.
+	// +optional
+	Parts []MIMEPart `json:"parts,omitempty"`
+}
+
+// MIMEPartContentType defines the content type of a part of a multi-part MIME bootstrap data.
+// +kubebuilder:validation:Enum="text/x-shellscript";"text/cloud-config";"text/cloud-boothook"
+type MIMEPartContentType string
+
+const (
+	// ShellScriptMIMEPartContentType is the content type of shell scripts executed once, when the instance is first booted.
+	ShellScriptMIMEPartContentType MIMEPartContentType = "text/x-shellscript"
+
+	// CloudConfigMIMEPartContentType is the content type of cloud-config documents, which are merged with the cloud-config
+	// generated by the bootstrap provider.
+	CloudConfigMIMEPartContentType MIMEPartContentType = "text/cloud-config"
+
+	// CloudBoothookMIMEPartContentType is the content type of scripts executed early on every boot.
+	CloudBoothookMIMEPartContentType MIMEPartContentType = "text/cloud-boothook"
+)
+
+// MIMEPart defines a part of a multi-part MIME bootstrap data.
+type MIMEPart struct {
+	// ContentType is the MIME content type of the part.
+	ContentType MIMEPartContentType `json:"contentType"`
+
+	// Filename is the filename of the part, used e.g. by cloud-init to name the scripts on disk.
+	// +optional
+	Filename string `json:"filename,omitempty"`
+
+	// Content is the content of the part.
+	Content string `json:"content"`
+}
+
 // KubeadmConfigStatus defines the observed state of KubeadmConfig.
 type KubeadmConfigStatus struct {
 	// Ready indicates the BootstrapData field is ready to be consumed
@@ -402,6 +822,12 @@ type KubeadmConfigStatus struct {
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// FileSourcesHash is the hash of the content resolved from the ConfigMaps and Secrets referenced
+	// by spec.files when the bootstrap data was generated. It is used to detect changes to the referenced
+	// objects after the bootstrap data has been consumed.
+	// +optional
+	FileSourcesHash string `json:"fileSourcesHash,omitempty"`
+
 	// Conditions defines current service state of the KubeadmConfig.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
@@ -490,11 +916,20 @@ type File struct {
 }
 
 // FileSource is a union of all possible external source types for file data.
-// Only one field may be populated in any given instance. Developers adding new
+// Exactly one of Secret or ConfigMap must be populated in any given instance. Developers adding new
 // sources of data for target systems should add them here.
 type FileSource struct {
 	// Secret represents a secret that should populate this file.
-	Secret SecretFileSource `json:"secret"`
+	// Exactly one of secret or configMap must be set.
+	// NOTE: For compatibility with previous versions, an empty secret, i.e. a secret with empty
+	// name and key, is treated as not set.
+	// +optional
+	Secret SecretFileSource `json:"secret,omitempty"`
+
+	// ConfigMap represents a config map that should populate this file.
+	// Exactly one of secret or configMap must be set.
+	// +optional
+	ConfigMap *ConfigMapFileSource `json:"configMap,omitempty"`
 }
 
 // SecretFileSource adapts a Secret into a FileSource.
@@ -509,6 +944,18 @@ type SecretFileSource struct {
 	Key string `json:"key"`
 }
 
+// ConfigMapFileSource adapts a ConfigMap into a FileSource.
+//
+// The value of the key is looked up in the ConfigMap's Data field first
+// and then in its BinaryData field.
+type ConfigMapFileSource struct {
+	// Name of the config map in the KubeadmBootstrapConfig's namespace to use.
+	Name string `json:"name"`
+
+	// Key is the key in the config map's data or binaryData map for this value.
+	Key string `json:"key"`
+}
+
 // PasswdSource is a union of all possible external source types for passwd data.
 // Only one field may be populated in any given instance. Developers adding new
 // sources of data for target systems should add them here.
@@ -25,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 
 	kubeadmbootstrapcontrollers "sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/controllers"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/securechannel"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 )
 
@@ -34,6 +35,9 @@ import (
 const (
 	// DefaultTokenTTL is the default TTL used for tokens.
 	DefaultTokenTTL = kubeadmbootstrapcontrollers.DefaultTokenTTL
+
+	// DefaultSecureChannelTokenTTL is the default TTL used for the one-time tokens of the secure channel.
+	DefaultSecureChannelTokenTTL = securechannel.DefaultTokenTTL
 )
 
 // KubeadmConfigReconciler reconciles a KubeadmConfig object.
@@ -48,8 +52,18 @@ type KubeadmConfigReconciler struct {
 
 	// TokenTTL is the amount of time a bootstrap token (and therefore a KubeadmConfig) will be valid.
 	TokenTTL time.Duration
+
+	// SecureChannel are the options for delivering the bootstrap data over the secure channel, when
+	// requested by the infrastructure machine.
+	SecureChannel SecureChannelOptions
 }
 
+// SecureChannelOptions are the options for delivering the bootstrap data over the secure channel.
+type SecureChannelOptions = securechannel.Options
+
+// BootstrapDataServer serves the bootstrap data to the machines requesting the SecureChannel bootstrap data delivery.
+type BootstrapDataServer = securechannel.Server
+
 // SetupWithManager sets up the reconciler with the Manager.
 func (r *KubeadmConfigReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&kubeadmbootstrapcontrollers.KubeadmConfigReconciler{
@@ -58,5 +72,6 @@ func (r *KubeadmConfigReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 		Tracker:             r.Tracker,
 		WatchFilterValue:    r.WatchFilterValue,
 		TokenTTL:            r.TokenTTL,
+		SecureChannel:       r.SecureChannel,
 	}).SetupWithManager(ctx, mgr, options)
 }
@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+
+	"github.com/pkg/errors"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+// jinjaMIMEPartContentType is the content type of the part containing the cloud-config generated
+// by the bootstrap provider; the cloud-config starts with the jinja template header, and cloud-init
+// renders the template before processing the resulting cloud-config.
+const jinjaMIMEPartContentType = "text/jinja2"
+
+// PackageOutput packages the cloud-config userData according to the given output spec.
+// If output is nil, userData is returned unchanged.
+func PackageOutput(userData []byte, output *bootstrapv1.OutputSpec) ([]byte, error) {
+	if output == nil {
+		return userData, nil
+	}
+
+	var err error
+	if output.MultiPart != nil {
+		userData, err = multiPart(userData, output.MultiPart.Parts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch output.Compression {
+	case "":
+	case bootstrapv1.GzipOutputCompression:
+		userData, err = gzipData(userData)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported output compression %q", output.Compression)
+	}
+
+	return userData, nil
+}
+
+// multiPart returns a multi-part MIME document with userData as first part, followed by parts.
+func multiPart(userData []byte, parts []bootstrapv1.MIMEPart) ([]byte, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	// Use a boundary derived from the content, so the generated document is stable across reconciles.
+	hash := sha256.New()
+	_, _ = hash.Write(userData)
+	for _, part := range parts {
+		_, _ = hash.Write([]byte(part.Content))
+	}
+	if err := w.SetBoundary(fmt.Sprintf("MIMEBOUNDARY-%s", hex.EncodeToString(hash.Sum(nil))[:32])); err != nil {
+		return nil, errors.Wrap(err, "failed to set multi-part MIME boundary")
+	}
+
+	if err := writeMIMEPart(w, jinjaMIMEPartContentType, "cloud-config.txt", userData); err != nil {
+		return nil, err
+	}
+	for i, part := range parts {
+		filename := part.Filename
+		if filename == "" {
+			filename = fmt.Sprintf("part-%03d", i+1)
+		}
+		if err := writeMIMEPart(w, string(part.ContentType), filename, []byte(part.Content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to generate multi-part MIME document")
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "Content-Type: multipart/mixed; boundary=%q\r\n", w.Boundary())
+	fmt.Fprint(&out, "MIME-Version: 1.0\r\n\r\n")
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+func writeMIMEPart(w *multipart.Writer, contentType, filename string, content []byte) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", fmt.Sprintf("%s; charset=\"utf-8\"", contentType))
+	header.Set("MIME-Version", "1.0")
+	header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	pw, err := w.CreatePart(header)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create multi-part MIME part %q", filename)
+	}
+	if _, err := pw.Write(content); err != nil {
+		return errors.Wrapf(err, "failed to write multi-part MIME part %q", filename)
+	}
+	return nil
+}
+
+func gzipData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, errors.Wrap(err, "failed to gzip bootstrap data")
+	}
+	if err := gz.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to gzip bootstrap data")
+	}
+	return buf.Bytes(), nil
+}
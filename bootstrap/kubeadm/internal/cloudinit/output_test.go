@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+func TestPackageOutput(t *testing.T) {
+	userData := []byte(cloudConfigHeader + "runcmd:\n  - echo hello\n")
+
+	multiPartOutput := &bootstrapv1.MultiPartOutput{
+		Parts: []bootstrapv1.MIMEPart{
+			{
+				ContentType: bootstrapv1.ShellScriptMIMEPartContentType,
+				Filename:    "setup.sh",
+				Content:     "#!/bin/bash\necho setup\n",
+			},
+			{
+				ContentType: bootstrapv1.CloudBoothookMIMEPartContentType,
+				Content:     "#!/bin/bash\necho boothook\n",
+			},
+		},
+	}
+
+	t.Run("returns the user data unchanged if output is not set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out, err := PackageOutput(userData, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(out).To(Equal(userData))
+	})
+
+	t.Run("generates a multi-part MIME document", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out, err := PackageOutput(userData, &bootstrapv1.OutputSpec{MultiPart: multiPartOutput})
+		g.Expect(err).ToNot(HaveOccurred())
+		assertMultiPart(g, out, userData, multiPartOutput.Parts)
+
+		// The output is stable across invocations.
+		again, err := PackageOutput(userData, &bootstrapv1.OutputSpec{MultiPart: multiPartOutput})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(again).To(Equal(out))
+	})
+
+	t.Run("compresses the user data", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out, err := PackageOutput(userData, &bootstrapv1.OutputSpec{Compression: bootstrapv1.GzipOutputCompression})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(gunzip(g, out)).To(Equal(userData))
+	})
+
+	t.Run("compresses the multi-part MIME document", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out, err := PackageOutput(userData, &bootstrapv1.OutputSpec{MultiPart: multiPartOutput, Compression: bootstrapv1.GzipOutputCompression})
+		g.Expect(err).ToNot(HaveOccurred())
+		assertMultiPart(g, gunzip(g, out), userData, multiPartOutput.Parts)
+	})
+
+	t.Run("fails for unknown compressions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := PackageOutput(userData, &bootstrapv1.OutputSpec{Compression: "zstd"})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func assertMultiPart(g *WithT, out, userData []byte, parts []bootstrapv1.MIMEPart) {
+	msg, err := mail.ReadMessage(bytes.NewReader(out))
+	g.Expect(err).ToNot(HaveOccurred())
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(mediaType).To(Equal("multipart/mixed"))
+
+	r := multipart.NewReader(msg.Body, params["boundary"])
+
+	part, err := r.NextPart()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(part.Header.Get("Content-Type")).To(HavePrefix(jinjaMIMEPartContentType))
+	content, err := io.ReadAll(part)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(content).To(Equal(userData))
+
+	for _, expected := range parts {
+		part, err := r.NextPart()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(part.Header.Get("Content-Type")).To(HavePrefix(string(expected.ContentType)))
+		if expected.Filename != "" {
+			g.Expect(part.FileName()).To(Equal(expected.Filename))
+		}
+		content, err := io.ReadAll(part)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(content)).To(Equal(expected.Content))
+	}
+
+	_, err = r.NextPart()
+	g.Expect(err).To(Equal(io.EOF))
+}
+
+func gunzip(g *WithT, data []byte) []byte {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	g.Expect(err).ToNot(HaveOccurred())
+	out, err := io.ReadAll(r)
+	g.Expect(err).ToNot(HaveOccurred())
+	return out
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+const (
+	discoveryKubeConfigOwner       = "root:root"
+	discoveryKubeConfigPermissions = "0640"
+)
+
+// discoveryKubeConfigFile returns the file with the kubeconfig used by kubeadm for file based discovery,
+// or nil if no kubeconfig should be generated.
+//
+// The kubeconfig embeds the cluster CA and the endpoint to reach the API server, so it does not contain
+// any secret; authentication relies on the auth provider or on the exec plugin defined in the KubeadmConfig,
+// which are expected to get credentials via the infrastructure provider.
+func discoveryKubeConfigFile(cluster *clusterv1.Cluster, config *bootstrapv1.KubeadmConfig, certificates secret.Certificates) (*bootstrapv1.File, error) {
+	if config.Spec.JoinConfiguration == nil || config.Spec.JoinConfiguration.Discovery.File == nil || config.Spec.JoinConfiguration.Discovery.File.KubeConfig == nil {
+		return nil, nil
+	}
+
+	fileDiscovery := config.Spec.JoinConfiguration.Discovery.File
+	kubeConfig := fileDiscovery.KubeConfig
+
+	apiCluster := &clientcmdapi.Cluster{}
+	if kubeConfig.Cluster != nil {
+		apiCluster.Server = kubeConfig.Cluster.Server
+		apiCluster.TLSServerName = kubeConfig.Cluster.TLSServerName
+		apiCluster.InsecureSkipTLSVerify = kubeConfig.Cluster.InsecureSkipTLSVerify
+		apiCluster.CertificateAuthorityData = kubeConfig.Cluster.CertificateAuthorityData
+		apiCluster.ProxyURL = kubeConfig.Cluster.ProxyURL
+	}
+
+	if apiCluster.Server == "" {
+		if !cluster.Spec.ControlPlaneEndpoint.IsValid() {
+			return nil, errors.New("failed to generate kubeconfig for file discovery: Cluster.Spec.ControlPlaneEndpoint is not set")
+		}
+		apiCluster.Server = fmt.Sprintf("https://%s", cluster.Spec.ControlPlaneEndpoint.String())
+	}
+
+	if len(apiCluster.CertificateAuthorityData) == 0 {
+		ca := certificates.GetByPurpose(secret.ClusterCA)
+		if ca == nil || ca.KeyPair == nil {
+			return nil, errors.New("failed to generate kubeconfig for file discovery: cluster CA certificate not found")
+		}
+		apiCluster.CertificateAuthorityData = ca.KeyPair.Cert
+	}
+
+	authInfo := &clientcmdapi.AuthInfo{}
+	if authProvider := kubeConfig.User.AuthProvider; authProvider != nil {
+		authInfo.AuthProvider = &clientcmdapi.AuthProviderConfig{
+			Name:   authProvider.Name,
+			Config: authProvider.Config,
+		}
+	}
+	if exec := kubeConfig.User.Exec; exec != nil {
+		authInfo.Exec = &clientcmdapi.ExecConfig{
+			Command:            exec.Command,
+			Args:               exec.Args,
+			APIVersion:         exec.APIVersion,
+			ProvideClusterInfo: exec.ProvideClusterInfo,
+			// The kubeconfig is used by kubeadm and the kubelet during the join, where no user can interact.
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		}
+		if authInfo.Exec.APIVersion == "" {
+			authInfo.Exec.APIVersion = "client.authentication.k8s.io/v1"
+		}
+		for _, env := range exec.Env {
+			authInfo.Exec.Env = append(authInfo.Exec.Env, clientcmdapi.ExecEnvVar{Name: env.Name, Value: env.Value})
+		}
+	}
+
+	const contextName = "default"
+	apiConfig := clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			cluster.Name: apiCluster,
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			contextName: authInfo,
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			contextName: {
+				Cluster:  cluster.Name,
+				AuthInfo: contextName,
+			},
+		},
+		CurrentContext: contextName,
+	}
+
+	content, err := clientcmd.Write(apiConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize kubeconfig for file discovery")
+	}
+
+	return &bootstrapv1.File{
+		Path:        fileDiscovery.KubeConfigPath,
+		Owner:       discoveryKubeConfigOwner,
+		Permissions: discoveryKubeConfigPermissions,
+		Content:     string(content),
+	}, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+func TestDiscoveryKubeConfigFile(t *testing.T) {
+	cluster := &clusterv1.Cluster{}
+	cluster.Name = "cluster"
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "example.com", Port: 6443}
+
+	certificates := secret.Certificates{
+		&secret.Certificate{
+			Purpose: secret.ClusterCA,
+			KeyPair: &certs.KeyPair{Cert: []byte("ca-cert")},
+		},
+	}
+
+	newConfig := func(kubeConfig *bootstrapv1.FileDiscoveryKubeConfig) *bootstrapv1.KubeadmConfig {
+		return &bootstrapv1.KubeadmConfig{
+			Spec: bootstrapv1.KubeadmConfigSpec{
+				JoinConfiguration: &bootstrapv1.JoinConfiguration{
+					Discovery: bootstrapv1.Discovery{
+						File: &bootstrapv1.FileDiscovery{
+							KubeConfigPath: "/etc/kubernetes/discovery.conf",
+							KubeConfig:     kubeConfig,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("returns nil when the kubeconfig should not be generated", func(t *testing.T) {
+		g := NewWithT(t)
+
+		file, err := discoveryKubeConfigFile(cluster, newConfig(nil), certificates)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(file).To(BeNil())
+	})
+
+	t.Run("defaults server and CA from the Cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := newConfig(&bootstrapv1.FileDiscoveryKubeConfig{
+			User: bootstrapv1.KubeConfigUser{
+				Exec: &bootstrapv1.KubeConfigAuthExec{
+					Command: "/usr/bin/credential-helper",
+					Args:    []string{"token"},
+					Env:     []bootstrapv1.KubeConfigAuthExecEnv{{Name: "FOO", Value: "bar"}},
+				},
+			},
+		})
+
+		file, err := discoveryKubeConfigFile(cluster, config, certificates)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(file.Path).To(Equal("/etc/kubernetes/discovery.conf"))
+		g.Expect(file.Permissions).To(Equal("0640"))
+
+		kubeConfig, err := clientcmd.Load([]byte(file.Content))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(kubeConfig.Clusters).To(HaveKey("cluster"))
+		g.Expect(kubeConfig.Clusters["cluster"].Server).To(Equal("https://example.com:6443"))
+		g.Expect(kubeConfig.Clusters["cluster"].CertificateAuthorityData).To(Equal([]byte("ca-cert")))
+
+		authInfo := kubeConfig.AuthInfos[kubeConfig.Contexts[kubeConfig.CurrentContext].AuthInfo]
+		g.Expect(authInfo.Exec.Command).To(Equal("/usr/bin/credential-helper"))
+		g.Expect(authInfo.Exec.Args).To(Equal([]string{"token"}))
+		g.Expect(authInfo.Exec.Env).To(Equal([]clientcmdapi.ExecEnvVar{{Name: "FOO", Value: "bar"}}))
+		g.Expect(authInfo.Exec.APIVersion).To(Equal("client.authentication.k8s.io/v1"))
+		g.Expect(authInfo.Exec.InteractiveMode).To(Equal(clientcmdapi.NeverExecInteractiveMode))
+	})
+
+	t.Run("respects the cluster information from the KubeadmConfig", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := newConfig(&bootstrapv1.FileDiscoveryKubeConfig{
+			Cluster: &bootstrapv1.KubeConfigCluster{
+				Server:                   "https://10.0.0.1:6443",
+				CertificateAuthorityData: []byte("other-ca-cert"),
+				TLSServerName:            "kubernetes",
+			},
+			User: bootstrapv1.KubeConfigUser{
+				AuthProvider: &bootstrapv1.KubeConfigAuthProvider{
+					Name:   "oidc",
+					Config: map[string]string{"client-id": "foo"},
+				},
+			},
+		})
+
+		file, err := discoveryKubeConfigFile(&clusterv1.Cluster{}, config, secret.Certificates{})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		kubeConfig, err := clientcmd.Load([]byte(file.Content))
+		g.Expect(err).ToNot(HaveOccurred())
+		apiCluster := kubeConfig.Clusters[kubeConfig.Contexts[kubeConfig.CurrentContext].Cluster]
+		g.Expect(apiCluster.Server).To(Equal("https://10.0.0.1:6443"))
+		g.Expect(apiCluster.CertificateAuthorityData).To(Equal([]byte("other-ca-cert")))
+		g.Expect(apiCluster.TLSServerName).To(Equal("kubernetes"))
+
+		authInfo := kubeConfig.AuthInfos[kubeConfig.Contexts[kubeConfig.CurrentContext].AuthInfo]
+		g.Expect(authInfo.AuthProvider.Name).To(Equal("oidc"))
+		g.Expect(authInfo.AuthProvider.Config).To(HaveKeyWithValue("client-id", "foo"))
+	})
+
+	t.Run("fails without control plane endpoint", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := newConfig(&bootstrapv1.FileDiscoveryKubeConfig{
+			User: bootstrapv1.KubeConfigUser{
+				Exec: &bootstrapv1.KubeConfigAuthExec{Command: "/usr/bin/credential-helper"},
+			},
+		})
+
+		_, err := discoveryKubeConfigFile(&clusterv1.Cluster{}, config, certificates)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
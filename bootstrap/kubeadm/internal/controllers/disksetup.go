@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+)
+
+// errDiskSetupVariablesNotAvailable is returned when the variables referenced by diskSetup or mounts
+// are not published by the InfraMachine yet.
+var errDiskSetupVariablesNotAvailable = errors.New("variables referenced by diskSetup or mounts are not available")
+
+// missingVariableError is the message text/template returns when executing a template with the
+// missingkey=error option references a key which does not exist in the map.
+const missingVariableError = "map has no entry for key"
+
+// resolveDiskSetup returns diskSetup and mounts with the references to variables resolved using the
+// variables published by the infrastructure object of the config owner in status.bootstrapVariables.
+func (r *KubeadmConfigReconciler) resolveDiskSetup(ctx context.Context, scope *Scope) (*bootstrapv1.DiskSetup, []bootstrapv1.MountPoints, error) {
+	diskSetup := scope.Config.Spec.DiskSetup
+	mounts := scope.Config.Spec.Mounts
+
+	if !diskSetupUsesVariables(diskSetup, mounts) {
+		return diskSetup, mounts, nil
+	}
+
+	infraRef := scope.ConfigOwner.InfrastructureRef()
+	if infraRef == nil {
+		return nil, nil, errors.Errorf("failed to resolve diskSetup and mounts: %s %s has no infrastructureRef", scope.ConfigOwner.GetKind(), scope.ConfigOwner.GetName())
+	}
+
+	infraObj, err := external.Get(ctx, r.Client, infraRef, scope.ConfigOwner.GetNamespace())
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to resolve diskSetup and mounts")
+	}
+
+	variables, _, err := unstructured.NestedStringMap(infraObj.Object, "status", "bootstrapVariables")
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read status.bootstrapVariables from %s %s", infraObj.GetKind(), infraObj.GetName())
+	}
+
+	return renderDiskSetup(diskSetup, mounts, variables)
+}
+
+// diskSetupUsesVariables returns true if diskSetup or mounts reference any variable.
+func diskSetupUsesVariables(diskSetup *bootstrapv1.DiskSetup, mounts []bootstrapv1.MountPoints) bool {
+	uses := false
+	_ = visitDiskSetup(diskSetup.DeepCopy(), deepCopyMounts(mounts), func(s string) (string, error) {
+		uses = uses || strings.Contains(s, "{{")
+		return s, nil
+	})
+	return uses
+}
+
+// renderDiskSetup returns a copy of diskSetup and mounts with the references to variables resolved.
+func renderDiskSetup(diskSetup *bootstrapv1.DiskSetup, mounts []bootstrapv1.MountPoints, variables map[string]string) (*bootstrapv1.DiskSetup, []bootstrapv1.MountPoints, error) {
+	diskSetup = diskSetup.DeepCopy()
+	mounts = deepCopyMounts(mounts)
+
+	err := visitDiskSetup(diskSetup, mounts, func(s string) (string, error) {
+		if !strings.Contains(s, "{{") {
+			return s, nil
+		}
+
+		// Errors other than a reference to a variable which is not published yet can only be fixed
+		// by changing the KubeadmConfig, so there is no point in retrying.
+		tpl, err := template.New("").Option("missingkey=error").Parse(s)
+		if err != nil {
+			return "", reconcile.TerminalError(errors.Wrapf(err, "failed to parse %q", s))
+		}
+
+		var out bytes.Buffer
+		if err := tpl.Execute(&out, variables); err != nil {
+			if strings.Contains(err.Error(), missingVariableError) {
+				return "", errors.Wrapf(errDiskSetupVariablesNotAvailable, "failed to resolve %q: %v", s, err)
+			}
+			return "", reconcile.TerminalError(errors.Wrapf(err, "failed to resolve %q", s))
+		}
+		return out.String(), nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return diskSetup, mounts, nil
+}
+
+// visitDiskSetup calls f for every string in diskSetup and mounts which can reference variables,
+// replacing it with the value returned by f.
+func visitDiskSetup(diskSetup *bootstrapv1.DiskSetup, mounts []bootstrapv1.MountPoints, f func(string) (string, error)) error {
+	visit := func(s *string) error {
+		out, err := f(*s)
+		if err != nil {
+			return err
+		}
+		*s = out
+		return nil
+	}
+
+	if diskSetup != nil {
+		for i := range diskSetup.Partitions {
+			if err := visit(&diskSetup.Partitions[i].Device); err != nil {
+				return err
+			}
+		}
+		for i := range diskSetup.Filesystems {
+			fs := &diskSetup.Filesystems[i]
+			if err := visit(&fs.Device); err != nil {
+				return err
+			}
+			if err := visit(&fs.Label); err != nil {
+				return err
+			}
+			if fs.Partition != nil {
+				if err := visit(fs.Partition); err != nil {
+					return err
+				}
+			}
+			for j := range fs.ExtraOpts {
+				if err := visit(&fs.ExtraOpts[j]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for i := range mounts {
+		for j := range mounts[i] {
+			if err := visit(&mounts[i][j]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func deepCopyMounts(mounts []bootstrapv1.MountPoints) []bootstrapv1.MountPoints {
+	if mounts == nil {
+		return nil
+	}
+	out := make([]bootstrapv1.MountPoints, len(mounts))
+	for i := range mounts {
+		out[i] = mounts[i].DeepCopy()
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	bsutil "sigs.k8s.io/cluster-api/bootstrap/util"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestRenderDiskSetup(t *testing.T) {
+	diskSetup := &bootstrapv1.DiskSetup{
+		Partitions: []bootstrapv1.Partition{
+			{Device: "{{ .ephemeralDevice }}", Layout: true},
+		},
+		Filesystems: []bootstrapv1.Filesystem{
+			{
+				Device:     "{{ .ephemeralDevice }}",
+				Filesystem: "ext4",
+				Label:      "ephemeral0",
+				Partition:  pointer.String("{{ .ephemeralPartition }}"),
+				ExtraOpts:  []string{"-E", "lazy_itable_init=1"},
+			},
+		},
+	}
+	mounts := []bootstrapv1.MountPoints{
+		{"ephemeral0", "/var/lib/containerd"},
+	}
+
+	t.Run("resolves variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		gotDiskSetup, gotMounts, err := renderDiskSetup(diskSetup, mounts, map[string]string{
+			"ephemeralDevice":    "/dev/nvme1n1",
+			"ephemeralPartition": "1",
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(gotDiskSetup.Partitions[0].Device).To(Equal("/dev/nvme1n1"))
+		g.Expect(gotDiskSetup.Filesystems[0].Device).To(Equal("/dev/nvme1n1"))
+		g.Expect(*gotDiskSetup.Filesystems[0].Partition).To(Equal("1"))
+		g.Expect(gotDiskSetup.Filesystems[0].ExtraOpts).To(Equal([]string{"-E", "lazy_itable_init=1"}))
+		g.Expect(gotMounts).To(Equal(mounts))
+
+		// The original objects are not modified.
+		g.Expect(diskSetup.Partitions[0].Device).To(Equal("{{ .ephemeralDevice }}"))
+	})
+
+	t.Run("fails if a variable is not available", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, _, err := renderDiskSetup(diskSetup, mounts, map[string]string{
+			"ephemeralDevice": "/dev/nvme1n1",
+		})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(errors.Is(err, errDiskSetupVariablesNotAvailable)).To(BeTrue())
+	})
+
+	t.Run("fails with a terminal error if a template is not valid", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, device := range []string{"{{ .ephemeralDevice ", "{{ .ephemeralDevice.name }}", "{{ len 3 }}"} {
+			_, _, err := renderDiskSetup(nil, []bootstrapv1.MountPoints{{device, "/data"}}, map[string]string{
+				"ephemeralDevice": "/dev/nvme1n1",
+			})
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(errors.Is(err, errDiskSetupVariablesNotAvailable)).To(BeFalse())
+			g.Expect(errors.Is(err, reconcile.TerminalError(nil))).To(BeTrue())
+		}
+	})
+
+	t.Run("detects references to variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(diskSetupUsesVariables(diskSetup, mounts)).To(BeTrue())
+		g.Expect(diskSetupUsesVariables(nil, mounts)).To(BeFalse())
+		g.Expect(diskSetupUsesVariables(nil, []bootstrapv1.MountPoints{{"{{ .label }}", "/data"}})).To(BeTrue())
+	})
+}
+
+func TestKubeadmConfigReconciler_ResolveDiskSetup(t *testing.T) {
+	g := NewWithT(t)
+
+	infraMachine := &unstructured.Unstructured{}
+	infraMachine.SetAPIVersion(builder.InfrastructureGroupVersion.String())
+	infraMachine.SetKind(builder.GenericInfrastructureMachineKind)
+	infraMachine.SetNamespace(metav1.NamespaceDefault)
+	infraMachine.SetName("infra-machine")
+	g.Expect(unstructured.SetNestedStringMap(infraMachine.Object, map[string]string{"ephemeralDevice": "/dev/nvme1n1"}, "status", "bootstrapVariables")).To(Succeed())
+
+	machine := builder.Machine(metav1.NamespaceDefault, "machine").Build()
+	machine.Spec.InfrastructureRef = corev1.ObjectReference{
+		APIVersion: infraMachine.GetAPIVersion(),
+		Kind:       infraMachine.GetKind(),
+		Name:       infraMachine.GetName(),
+	}
+	machineContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(machine)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	newScope := func(config *bootstrapv1.KubeadmConfig) *Scope {
+		return &Scope{
+			Config:      config,
+			ConfigOwner: &bsutil.ConfigOwner{Unstructured: &unstructured.Unstructured{Object: machineContent}},
+		}
+	}
+
+	myclient := fake.NewClientBuilder().WithObjects(infraMachine).Build()
+	k := &KubeadmConfigReconciler{Client: myclient}
+
+	t.Run("resolves variables published by the InfraMachine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := &bootstrapv1.KubeadmConfig{
+			Spec: bootstrapv1.KubeadmConfigSpec{
+				Mounts: []bootstrapv1.MountPoints{{"{{ .ephemeralDevice }}", "/var/lib/containerd"}},
+			},
+		}
+		_, mounts, err := k.resolveDiskSetup(ctx, newScope(config))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(mounts).To(Equal([]bootstrapv1.MountPoints{{"/dev/nvme1n1", "/var/lib/containerd"}}))
+	})
+
+	t.Run("waits for variables not published by the InfraMachine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := &bootstrapv1.KubeadmConfig{
+			Spec: bootstrapv1.KubeadmConfigSpec{
+				Mounts: []bootstrapv1.MountPoints{{"{{ .dataDevice }}", "/data"}},
+			},
+		}
+		_, _, err := k.resolveDiskSetup(ctx, newScope(config))
+		g.Expect(errors.Is(err, errDiskSetupVariablesNotAvailable)).To(BeTrue())
+	})
+}
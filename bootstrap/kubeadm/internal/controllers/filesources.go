@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// fileSourcesCheckInterval is the minimum interval between two checks of the content
+// referenced by the files of a KubeadmConfig.
+const fileSourcesCheckInterval = 5 * time.Minute
+
+// fileSourcesCheckCache records when the content referenced by the files of a KubeadmConfig
+// has last been found up to date, so the ConfigMaps and Secrets are not read from the API server
+// at every reconcile.
+type fileSourcesCheckCache struct {
+	lock    sync.Mutex
+	entries map[types.UID]fileSourcesCheck
+}
+
+type fileSourcesCheck struct {
+	key     string
+	expires time.Time
+}
+
+func newFileSourcesCheckCache() *fileSourcesCheckCache {
+	return &fileSourcesCheckCache{entries: map[types.UID]fileSourcesCheck{}}
+}
+
+// Has returns true if the KubeadmConfig with the given UID has been found up to date for the given key
+// within the check interval.
+func (c *fileSourcesCheckCache) Has(uid types.UID, key string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, ok := c.entries[uid]
+	return ok && e.key == key && time.Now().Before(e.expires)
+}
+
+// Add records that the KubeadmConfig with the given UID has been found up to date for the given key.
+// Expired entries, e.g. the ones of deleted KubeadmConfigs, are dropped at the same time.
+func (c *fileSourcesCheckCache) Add(uid types.UID, key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	for u, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, u)
+		}
+	}
+	c.entries[uid] = fileSourcesCheck{key: key, expires: now.Add(fileSourcesCheckInterval)}
+}
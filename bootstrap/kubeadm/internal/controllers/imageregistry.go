@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/kubeadm"
+	"sigs.k8s.io/cluster-api/util/version"
+)
+
+const (
+	// imageDigestPatchesDirectory is the directory the kubeadm patches pinning the image digests are written to,
+	// if the KubeadmConfig does not define a patches directory.
+	imageDigestPatchesDirectory = "/etc/kubernetes/cluster-api/patches"
+
+	// imageDigestPatchSuffix is the suffix of the kubeadm patches pinning the image digests; it ensures
+	// the patches are applied after the patches defined by the user for the same target.
+	imageDigestPatchSuffix = "zz-cluster-api-image-digest"
+)
+
+// minKubernetesVersionPatches is the first Kubernetes version supporting kubeadm patches.
+var minKubernetesVersionPatches = semver.MustParse("1.22.0")
+
+// imageDigestPatches returns the kubeadm patches pinning the images of the control plane components
+// to the digests defined in the image registry of the Cluster, and the files containing the patches.
+// It returns nil if no digest is defined for the control plane components.
+//
+// NOTE: kubeadm does not support image digests, so the static pod manifests generated by kubeadm are patched
+// to use the images with the digests; the digests of kube-proxy and CoreDNS are instead applied by the
+// control plane provider.
+func imageDigestPatches(cluster *clusterv1.Cluster, clusterConfiguration *bootstrapv1.ClusterConfiguration, patches *bootstrapv1.Patches, kubernetesVersion semver.Version) (*bootstrapv1.Patches, []bootstrapv1.File, error) {
+	if cluster.Spec.ImageRegistry == nil || cluster.Spec.ImageRegistry.Digests == nil {
+		return nil, nil, nil
+	}
+	digests := cluster.Spec.ImageRegistry.Digests
+
+	imageRepository := ""
+	if clusterConfiguration != nil {
+		imageRepository = clusterConfiguration.ImageRepository
+	}
+	if imageRepository == "" {
+		imageRepository = cluster.Spec.ImageRegistry.Repository
+	}
+	if imageRepository == "" {
+		imageRepository = kubeadm.GetDefaultRegistry(kubernetesVersion)
+	}
+
+	components := []struct {
+		name            string
+		imageRepository string
+		digest          string
+	}{
+		{name: "kube-apiserver", imageRepository: imageRepository, digest: digests.KubeAPIServer},
+		{name: "kube-controller-manager", imageRepository: imageRepository, digest: digests.KubeControllerManager},
+		{name: "kube-scheduler", imageRepository: imageRepository, digest: digests.KubeScheduler},
+	}
+	// The etcd image is pinned only if etcd is managed by kubeadm.
+	if clusterConfiguration == nil || clusterConfiguration.Etcd.External == nil {
+		etcdImageRepository := imageRepository
+		if clusterConfiguration != nil && clusterConfiguration.Etcd.Local != nil && clusterConfiguration.Etcd.Local.ImageRepository != "" {
+			etcdImageRepository = clusterConfiguration.Etcd.Local.ImageRepository
+		}
+		components = append(components, struct {
+			name            string
+			imageRepository string
+			digest          string
+		}{name: "etcd", imageRepository: etcdImageRepository, digest: digests.Etcd})
+	}
+
+	directory := imageDigestPatchesDirectory
+	if patches != nil && patches.Directory != "" {
+		directory = patches.Directory
+	}
+
+	files := []bootstrapv1.File{}
+	for _, c := range components {
+		if c.digest == "" {
+			continue
+		}
+		files = append(files, bootstrapv1.File{
+			Path:        path.Join(directory, fmt.Sprintf("%s%s+strategic.yaml", c.name, imageDigestPatchSuffix)),
+			Owner:       "root:root",
+			Permissions: "0644",
+			Content: fmt.Sprintf(`spec:
+  containers:
+  - name: %s
+    image: %s/%s@%s
+`, c.name, strings.TrimSuffix(c.imageRepository, "/"), c.name, c.digest),
+		})
+	}
+	if len(files) == 0 {
+		return nil, nil, nil
+	}
+
+	if version.Compare(kubernetesVersion, minKubernetesVersionPatches, version.WithoutPreReleases()) < 0 {
+		return nil, nil, errors.Errorf("image digests for the control plane components can be used only with Kubernetes version >= v%s, got v%s", minKubernetesVersionPatches, kubernetesVersion)
+	}
+
+	return &bootstrapv1.Patches{Directory: directory}, files, nil
+}
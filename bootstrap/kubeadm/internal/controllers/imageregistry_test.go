@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	. "github.com/onsi/gomega"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+func TestImageDigestPatches(t *testing.T) {
+	const (
+		apiServerDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		etcdDigest      = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+	version := semver.MustParse("1.28.0")
+
+	newCluster := func(imageRegistry *clusterv1.ImageRegistry) *clusterv1.Cluster {
+		cluster := &clusterv1.Cluster{}
+		cluster.Spec.ImageRegistry = imageRegistry
+		return cluster
+	}
+
+	t.Run("returns nil if no digest is defined", func(t *testing.T) {
+		g := NewWithT(t)
+
+		patches, files, err := imageDigestPatches(newCluster(&clusterv1.ImageRegistry{Repository: "registry.example.com"}), nil, nil, version)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(patches).To(BeNil())
+		g.Expect(files).To(BeEmpty())
+	})
+
+	t.Run("generates patches using the image repository of the Cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := newCluster(&clusterv1.ImageRegistry{
+			Repository: "registry.example.com/mirror",
+			Digests:    &clusterv1.ImageDigests{KubeAPIServer: apiServerDigest, Etcd: etcdDigest},
+		})
+		patches, files, err := imageDigestPatches(cluster, &bootstrapv1.ClusterConfiguration{}, nil, version)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(patches).To(Equal(&bootstrapv1.Patches{Directory: imageDigestPatchesDirectory}))
+		g.Expect(files).To(HaveLen(2))
+		g.Expect(files[0].Path).To(Equal("/etc/kubernetes/cluster-api/patches/kube-apiserverzz-cluster-api-image-digest+strategic.yaml"))
+		g.Expect(files[0].Content).To(Equal("spec:\n" +
+			"  containers:\n" +
+			"  - name: kube-apiserver\n" +
+			"    image: registry.example.com/mirror/kube-apiserver@" + apiServerDigest + "\n"))
+		g.Expect(files[1].Path).To(Equal("/etc/kubernetes/cluster-api/patches/etcdzz-cluster-api-image-digest+strategic.yaml"))
+		g.Expect(files[1].Content).To(ContainSubstring("image: registry.example.com/mirror/etcd@" + etcdDigest))
+	})
+
+	t.Run("uses the image repositories and the patches directory of the KubeadmConfig", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := newCluster(&clusterv1.ImageRegistry{
+			Repository: "registry.example.com/mirror",
+			Digests:    &clusterv1.ImageDigests{KubeAPIServer: apiServerDigest, Etcd: etcdDigest},
+		})
+		clusterConfiguration := &bootstrapv1.ClusterConfiguration{
+			ImageRepository: "registry.example.com/kubernetes",
+			Etcd: bootstrapv1.Etcd{
+				Local: &bootstrapv1.LocalEtcd{
+					ImageMeta: bootstrapv1.ImageMeta{ImageRepository: "registry.example.com/etcd"},
+				},
+			},
+		}
+		patches, files, err := imageDigestPatches(cluster, clusterConfiguration, &bootstrapv1.Patches{Directory: "/etc/kubeadm/patches"}, version)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(patches).To(Equal(&bootstrapv1.Patches{Directory: "/etc/kubeadm/patches"}))
+		g.Expect(files).To(HaveLen(2))
+		g.Expect(files[0].Path).To(HavePrefix("/etc/kubeadm/patches/"))
+		g.Expect(files[0].Content).To(ContainSubstring("image: registry.example.com/kubernetes/kube-apiserver@" + apiServerDigest))
+		g.Expect(files[1].Content).To(ContainSubstring("image: registry.example.com/etcd/etcd@" + etcdDigest))
+	})
+
+	t.Run("does not pin etcd when using an external etcd", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := newCluster(&clusterv1.ImageRegistry{
+			Digests: &clusterv1.ImageDigests{Etcd: etcdDigest},
+		})
+		clusterConfiguration := &bootstrapv1.ClusterConfiguration{
+			Etcd: bootstrapv1.Etcd{External: &bootstrapv1.ExternalEtcd{}},
+		}
+		patches, files, err := imageDigestPatches(cluster, clusterConfiguration, nil, version)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(patches).To(BeNil())
+		g.Expect(files).To(BeEmpty())
+	})
+
+	t.Run("uses the default registry of the Kubernetes version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := newCluster(&clusterv1.ImageRegistry{
+			Digests: &clusterv1.ImageDigests{KubeAPIServer: apiServerDigest},
+		})
+		_, files, err := imageDigestPatches(cluster, nil, nil, version)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(files).To(HaveLen(1))
+		g.Expect(files[0].Content).To(ContainSubstring("image: registry.k8s.io/kube-apiserver@" + apiServerDigest))
+	})
+
+	t.Run("fails for Kubernetes versions not supporting kubeadm patches", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := newCluster(&clusterv1.ImageRegistry{
+			Digests: &clusterv1.ImageDigests{KubeAPIServer: apiServerDigest},
+		})
+		_, _, err := imageDigestPatches(cluster, nil, nil, semver.MustParse("1.21.0"))
+		g.Expect(err).To(HaveOccurred())
+	})
+}
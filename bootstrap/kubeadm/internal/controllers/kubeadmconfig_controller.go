@@ -18,6 +18,8 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"strconv"
 	"time"
@@ -28,6 +30,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -44,8 +47,11 @@ import (
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/ignition"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/locking"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/securechannel"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/shell"
 	kubeadmtypes "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types"
 	bsutil "sigs.k8s.io/cluster-api/bootstrap/util"
+	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
@@ -54,14 +60,21 @@ import (
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	clog "sigs.k8s.io/cluster-api/util/log"
+	"sigs.k8s.io/cluster-api/util/metrics"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 const (
 	// DefaultTokenTTL is the default TTL used for tokens.
 	DefaultTokenTTL = 15 * time.Minute
+
+	// maxFileSourcesSize is the maximum size of the content resolved from the ConfigMaps and Secrets referenced
+	// by spec.files; it ensures the generated bootstrap data fits in the bootstrap data Secret, which is subject
+	// to the 1MiB object size limit of the API server.
+	maxFileSourcesSize = 512 * 1024
 )
 
 // InitLocker is a lock that is used around kubeadm init.
@@ -86,6 +99,14 @@ type KubeadmConfigReconciler struct {
 
 	// TokenTTL is the amount of time a bootstrap token (and therefore a KubeadmConfig) will be valid.
 	TokenTTL time.Duration
+
+	// SecureChannel are the options for delivering the bootstrap data over the secure channel, when
+	// requested by the infrastructure machine.
+	SecureChannel securechannel.Options
+
+	// fileSourcesCache records the KubeadmConfigs whose content referenced by files has recently been
+	// found up to date, so the ConfigMaps and Secrets are not read from the API server at every reconcile.
+	fileSourcesCache *fileSourcesCheckCache
 }
 
 // Scope is a scoped struct used during reconciliation.
@@ -104,6 +125,9 @@ func (r *KubeadmConfigReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 	if r.TokenTTL == 0 {
 		r.TokenTTL = DefaultTokenTTL
 	}
+	if r.fileSourcesCache == nil {
+		r.fileSourcesCache = newFileSourcesCheckCache()
+	}
 
 	b := ctrl.NewControllerManagedBy(mgr).
 		For(&bootstrapv1.KubeadmConfig{}).
@@ -131,7 +155,7 @@ func (r *KubeadmConfigReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 		),
 	)
 
-	if err := b.Complete(r); err != nil {
+	if err := b.Complete(tracing.Reconciler("kubeadmconfig", "KubeadmConfig", metrics.Reconciler("kubeadmconfig", "KubeadmConfig", r))); err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 	return nil
@@ -174,6 +198,8 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	log = log.WithValues(configOwner.GetKind(), klog.KRef(configOwner.GetNamespace(), configOwner.GetName()), "resourceVersion", configOwner.GetResourceVersion())
 
 	log = log.WithValues("Cluster", klog.KRef(configOwner.GetNamespace(), configOwner.ClusterName()))
+	tracing.SetCluster(ctx, configOwner.ClusterName())
+	metrics.SetCluster(ctx, configOwner.ClusterName())
 	ctx = ctrl.LoggerInto(ctx, log)
 
 	// Lookup the cluster the config owner is associated with
@@ -217,6 +243,7 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			conditions.WithConditions(
 				bootstrapv1.DataSecretAvailableCondition,
 				bootstrapv1.CertificatesAvailableCondition,
+				bootstrapv1.FileSourcesUpToDateCondition,
 			),
 		)
 		// Patch ObservedGeneration only if the reconciliation completed successfully
@@ -266,18 +293,30 @@ func (r *KubeadmConfigReconciler) reconcile(ctx context.Context, scope *Scope, c
 		return ctrl.Result{}, nil
 	// Status is ready means a config has been generated.
 	case config.Status.Ready:
-		if config.Spec.JoinConfiguration != nil && config.Spec.JoinConfiguration.Discovery.BootstrapToken != nil {
-			if !configOwner.HasNodeRefs() {
-				// If the BootstrapToken has been generated for a join but the config owner has no nodeRefs,
-				// this indicates that the node has not yet joined and the token in the join config has not
-				// been consumed and it may need a refresh.
-				return r.refreshBootstrapToken(ctx, config, cluster)
-			}
+		// Detect changes to the content referenced by spec.files after the bootstrap data has been generated.
+		r.reconcileFileSources(ctx, config)
+
+		if config.Spec.JoinConfiguration != nil && config.Spec.JoinConfiguration.Discovery.BootstrapToken != nil &&
+			config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token != "" {
 			if configOwner.IsMachinePool() {
+				if !configOwner.HasNodeRefs() {
+					// If the BootstrapToken has been generated for a join but the MachinePool has no nodeRefs,
+					// this indicates that no node has joined yet and the token may need a refresh.
+					return r.refreshBootstrapToken(ctx, config, cluster, scope)
+				}
 				// If the BootstrapToken has been generated and infrastructure is ready but the configOwner is a MachinePool,
 				// we rotate the token to keep it fresh for future scale ups.
 				return r.rotateMachinePoolBootstrapToken(ctx, config, cluster, scope)
 			}
+			if isProvisioning(configOwner) {
+				// If the BootstrapToken has been generated for a join but the Machine is still provisioning,
+				// this indicates that the node has not yet joined and the token in the join config has not
+				// been consumed and it may need a refresh.
+				return r.refreshBootstrapToken(ctx, config, cluster, scope)
+			}
+			// The node joined, or it will never join because the Machine failed or is being deleted, so the
+			// token is not required anymore.
+			return r.revokeBootstrapToken(ctx, config, cluster)
 		}
 		// In any other case just return as the config is already generated and need not be generated again.
 		return ctrl.Result{}, nil
@@ -309,7 +348,18 @@ func (r *KubeadmConfigReconciler) reconcile(ctx context.Context, scope *Scope, c
 	return r.joinWorker(ctx, scope)
 }
 
-func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+// isProvisioning returns true if the Machine owning a config is still expected to join the cluster.
+func isProvisioning(configOwner *bsutil.ConfigOwner) bool {
+	if configOwner.HasNodeRefs() || !configOwner.GetDeletionTimestamp().IsZero() {
+		return false
+	}
+	failureReason, _, _ := unstructured.NestedString(configOwner.Object, "status", "failureReason")
+	return failureReason == ""
+}
+
+// refreshBootstrapToken extends the TTL of the token as long as it is not consumed, so the infrastructure
+// has a chance to use it no matter how long provisioning takes.
+func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster, scope *Scope) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 	token := config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
 
@@ -318,6 +368,31 @@ func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, con
 		return ctrl.Result{}, err
 	}
 
+	secret, err := getToken(ctx, remoteClient, token)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get bootstrap token")
+	}
+
+	var expiration time.Time
+	if err == nil {
+		if expiration, err = tokenExpiration(secret); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if apierrors.IsNotFound(err) || expiration.Before(time.Now().UTC()) {
+		// The token expired before being consumed, and it is garbage collected by the workload cluster.
+		log.Info("Bootstrap token expired before the node joined the cluster")
+		bootstrapTokensExpiredTotal.With(clusterLabels(cluster)).Inc()
+		if scope.ConfigOwner.IsMachinePool() {
+			// MachinePools get a new token, so nodes can still join on scale up.
+			return r.rotateMachinePoolBootstrapToken(ctx, config, cluster, scope)
+		}
+		// Drop the token from the config, there is nothing left to refresh.
+		config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token = ""
+		return ctrl.Result{}, nil
+	}
+
 	log.Info("Refreshing token until the infrastructure has a chance to consume it")
 	if err := refreshToken(ctx, remoteClient, token, r.TokenTTL); err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to refresh bootstrap token")
@@ -327,6 +402,31 @@ func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, con
 	}, nil
 }
 
+// revokeBootstrapToken deletes the token from the workload cluster as soon as it is not required anymore,
+// instead of waiting for it to expire.
+func (r *KubeadmConfigReconciler) revokeBootstrapToken(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	token := config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	revoked, err := revokeToken(ctx, remoteClient, token)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to revoke bootstrap token")
+	}
+	if revoked {
+		log.Info("Revoked bootstrap token")
+		bootstrapTokensRevokedTotal.With(clusterLabels(cluster)).Inc()
+	}
+
+	// Drop the token from the config, so it is not revoked again at every reconcile.
+	config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token = ""
+	return ctrl.Result{}, nil
+}
+
 func (r *KubeadmConfigReconciler) rotateMachinePoolBootstrapToken(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster, scope *Scope) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 	log.V(2).Info("Config is owned by a MachinePool, checking if token should be rotated")
@@ -346,6 +446,7 @@ func (r *KubeadmConfigReconciler) rotateMachinePoolBootstrapToken(ctx context.Co
 		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to create new bootstrap token")
 		}
+		bootstrapTokensIssuedTotal.With(clusterLabels(cluster)).Inc()
 
 		config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token = token
 		log.V(3).Info("Altering JoinConfiguration.Discovery.BootstrapToken.Token")
@@ -421,12 +522,6 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 		}
 	}
 
-	initdata, err := kubeadmtypes.MarshalInitConfigurationForVersion(scope.Config.Spec.InitConfiguration, parsedVersion)
-	if err != nil {
-		scope.Error(err, "Failed to marshal init configuration")
-		return ctrl.Result{}, err
-	}
-
 	if scope.Config.Spec.ClusterConfiguration == nil {
 		scope.Config.Spec.ClusterConfiguration = &bootstrapv1.ClusterConfiguration{
 			TypeMeta: metav1.TypeMeta{
@@ -445,6 +540,25 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 		return ctrl.Result{}, err
 	}
 
+	// Pin the images of the control plane components to the digests defined in the image registry of the Cluster, if any.
+	// NOTE: the patches are added to a copy of the InitConfiguration, so they are not persisted in the KubeadmConfig.
+	initConfiguration := scope.Config.Spec.InitConfiguration
+	digestPatches, digestFiles, err := imageDigestPatches(scope.Cluster, scope.Config.Spec.ClusterConfiguration, initConfiguration.Patches, parsedVersion)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	if digestPatches != nil {
+		initConfiguration = initConfiguration.DeepCopy()
+		initConfiguration.Patches = digestPatches
+	}
+
+	initdata, err := kubeadmtypes.MarshalInitConfigurationForVersion(scope.Config.Spec.ClusterConfiguration, initConfiguration, parsedVersion)
+	if err != nil {
+		scope.Error(err, "Failed to marshal init configuration")
+		return ctrl.Result{}, err
+	}
+
 	certificates := secret.NewCertificatesForInitialControlPlane(scope.Config.Spec.ClusterConfiguration)
 
 	// If the Cluster does not have a ControlPlane reference look up and generate the certificates.
@@ -480,6 +594,14 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
+	files = append(files, digestFiles...)
+
+	tunnelFiles, tunnelCommands, err := r.tunnelAgentFiles(ctx, scope.Cluster)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	files = append(files, tunnelFiles...)
 
 	users, err := r.resolveUsers(ctx, scope.Config)
 	if err != nil {
@@ -487,15 +609,26 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 		return ctrl.Result{}, err
 	}
 
+	diskSetup, mounts, err := r.resolveDiskSetup(ctx, scope)
+	if err != nil {
+		if errors.Is(err, errDiskSetupVariablesNotAvailable) {
+			scope.Info("Waiting for the variables referenced by diskSetup or mounts to be published by the infrastructure provider", "reason", err.Error())
+			conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.WaitingForDiskSetupVariablesReason, clusterv1.ConditionSeverityInfo, err.Error())
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	controlPlaneInput := &cloudinit.ControlPlaneInput{
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:     files,
 			NTP:                 scope.Config.Spec.NTP,
 			PreKubeadmCommands:  scope.Config.Spec.PreKubeadmCommands,
-			PostKubeadmCommands: scope.Config.Spec.PostKubeadmCommands,
+			PostKubeadmCommands: append(tunnelCommands, scope.Config.Spec.PostKubeadmCommands...),
 			Users:               users,
-			Mounts:              scope.Config.Spec.Mounts,
-			DiskSetup:           scope.Config.Spec.DiskSetup,
+			Mounts:              mounts,
+			DiskSetup:           diskSetup,
 			KubeadmVerbosity:    verbosityFlag,
 		},
 		InitConfiguration:    initdata,
@@ -510,6 +643,8 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 			ControlPlaneInput: controlPlaneInput,
 			Ignition:          scope.Config.Spec.Ignition,
 		})
+	case bootstrapv1.Shell:
+		bootstrapInitData, err = shell.NewInitControlPlane(controlPlaneInput)
 	default:
 		bootstrapInitData, err = cloudinit.NewInitControlPlane(controlPlaneInput)
 	}
@@ -519,6 +654,14 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 		return ctrl.Result{}, err
 	}
 
+	// Package the bootstrap data as requested, e.g. as a multi-part MIME document or gzip compressed.
+	// NOTE: the output spec can be set only for the cloud-config format.
+	bootstrapInitData, err = cloudinit.PackageOutput(bootstrapInitData, scope.Config.Spec.Output)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	if err := r.storeBootstrapData(ctx, scope, bootstrapInitData); err != nil {
 		scope.Error(err, "Failed to store bootstrap data")
 		return ctrl.Result{}, err
@@ -569,7 +712,7 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 		joinConfiguration.NodeRegistration.Taints = append(joinConfiguration.NodeRegistration.Taints, clusterv1.NodeUninitializedTaint)
 	}
 
-	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(joinConfiguration, parsedVersion)
+	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(nil, joinConfiguration, parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal join configuration")
 		return ctrl.Result{}, err
@@ -590,12 +733,32 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 		return ctrl.Result{}, err
 	}
 
+	discoveryFile, err := discoveryKubeConfigFile(scope.Cluster, scope.Config, certificates)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	if discoveryFile != nil {
+		files = append(files, *discoveryFile)
+	}
+
 	users, err := r.resolveUsers(ctx, scope.Config)
 	if err != nil {
 		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
+	diskSetup, mounts, err := r.resolveDiskSetup(ctx, scope)
+	if err != nil {
+		if errors.Is(err, errDiskSetupVariablesNotAvailable) {
+			scope.Info("Waiting for the variables referenced by diskSetup or mounts to be published by the infrastructure provider", "reason", err.Error())
+			conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.WaitingForDiskSetupVariablesReason, clusterv1.ConditionSeverityInfo, err.Error())
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	nodeInput := &cloudinit.NodeInput{
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:      files,
@@ -603,8 +766,8 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 			PreKubeadmCommands:   scope.Config.Spec.PreKubeadmCommands,
 			PostKubeadmCommands:  scope.Config.Spec.PostKubeadmCommands,
 			Users:                users,
-			Mounts:               scope.Config.Spec.Mounts,
-			DiskSetup:            scope.Config.Spec.DiskSetup,
+			Mounts:               mounts,
+			DiskSetup:            diskSetup,
 			KubeadmVerbosity:     verbosityFlag,
 			UseExperimentalRetry: scope.Config.Spec.UseExperimentalRetryJoin,
 		},
@@ -618,6 +781,8 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 			NodeInput: nodeInput,
 			Ignition:  scope.Config.Spec.Ignition,
 		})
+	case bootstrapv1.Shell:
+		bootstrapJoinData, err = shell.NewNode(nodeInput)
 	default:
 		bootstrapJoinData, err = cloudinit.NewNode(nodeInput)
 	}
@@ -627,6 +792,14 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 		return ctrl.Result{}, err
 	}
 
+	// Package the bootstrap data as requested, e.g. as a multi-part MIME document or gzip compressed.
+	// NOTE: the output spec can be set only for the cloud-config format.
+	bootstrapJoinData, err = cloudinit.PackageOutput(bootstrapJoinData, scope.Config.Spec.Output)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	if err := r.storeBootstrapData(ctx, scope, bootstrapJoinData); err != nil {
 		scope.Error(err, "Failed to store bootstrap data")
 		return ctrl.Result{}, err
@@ -676,7 +849,20 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		return ctrl.Result{}, errors.Wrapf(err, "failed to parse kubernetes version %q", kubernetesVersion)
 	}
 
-	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(scope.Config.Spec.JoinConfiguration, parsedVersion)
+	// Pin the images of the control plane components to the digests defined in the image registry of the Cluster, if any.
+	// NOTE: the patches are added to a copy of the JoinConfiguration, so they are not persisted in the KubeadmConfig.
+	joinConfiguration := scope.Config.Spec.JoinConfiguration
+	digestPatches, digestFiles, err := imageDigestPatches(scope.Cluster, scope.Config.Spec.ClusterConfiguration, joinConfiguration.Patches, parsedVersion)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	if digestPatches != nil {
+		joinConfiguration = joinConfiguration.DeepCopy()
+		joinConfiguration.Patches = digestPatches
+	}
+
+	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(scope.Config.Spec.ClusterConfiguration, joinConfiguration, parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal join configuration")
 		return ctrl.Result{}, err
@@ -693,12 +879,40 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		return ctrl.Result{}, err
 	}
 
+	discoveryFile, err := discoveryKubeConfigFile(scope.Cluster, scope.Config, certificates)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	if discoveryFile != nil {
+		files = append(files, *discoveryFile)
+	}
+	files = append(files, digestFiles...)
+
+	tunnelFiles, tunnelCommands, err := r.tunnelAgentFiles(ctx, scope.Cluster)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	files = append(files, tunnelFiles...)
+
 	users, err := r.resolveUsers(ctx, scope.Config)
 	if err != nil {
 		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
 
+	diskSetup, mounts, err := r.resolveDiskSetup(ctx, scope)
+	if err != nil {
+		if errors.Is(err, errDiskSetupVariablesNotAvailable) {
+			scope.Info("Waiting for the variables referenced by diskSetup or mounts to be published by the infrastructure provider", "reason", err.Error())
+			conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.WaitingForDiskSetupVariablesReason, clusterv1.ConditionSeverityInfo, err.Error())
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	controlPlaneJoinInput := &cloudinit.ControlPlaneJoinInput{
 		JoinConfiguration: joinData,
 		Certificates:      certificates,
@@ -706,10 +920,10 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 			AdditionalFiles:      files,
 			NTP:                  scope.Config.Spec.NTP,
 			PreKubeadmCommands:   scope.Config.Spec.PreKubeadmCommands,
-			PostKubeadmCommands:  scope.Config.Spec.PostKubeadmCommands,
+			PostKubeadmCommands:  append(tunnelCommands, scope.Config.Spec.PostKubeadmCommands...),
 			Users:                users,
-			Mounts:               scope.Config.Spec.Mounts,
-			DiskSetup:            scope.Config.Spec.DiskSetup,
+			Mounts:               mounts,
+			DiskSetup:            diskSetup,
 			KubeadmVerbosity:     verbosityFlag,
 			UseExperimentalRetry: scope.Config.Spec.UseExperimentalRetryJoin,
 		},
@@ -722,6 +936,8 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 			ControlPlaneJoinInput: controlPlaneJoinInput,
 			Ignition:              scope.Config.Spec.Ignition,
 		})
+	case bootstrapv1.Shell:
+		bootstrapJoinData, err = shell.NewJoinControlPlane(controlPlaneJoinInput)
 	default:
 		bootstrapJoinData, err = cloudinit.NewJoinControlPlane(controlPlaneJoinInput)
 	}
@@ -731,6 +947,14 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		return ctrl.Result{}, err
 	}
 
+	// Package the bootstrap data as requested, e.g. as a multi-part MIME document or gzip compressed.
+	// NOTE: the output spec can be set only for the cloud-config format.
+	bootstrapJoinData, err = cloudinit.PackageOutput(bootstrapJoinData, scope.Config.Spec.Output)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	if err := r.storeBootstrapData(ctx, scope, bootstrapJoinData); err != nil {
 		scope.Error(err, "Failed to store bootstrap data")
 		return ctrl.Result{}, err
@@ -740,24 +964,52 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 }
 
 // resolveFiles maps .Spec.Files into cloudinit.Files, resolving any object references
-// along the way.
+// along the way. The hash of the resolved content is recorded in the KubeadmConfig status
+// so that changes to the referenced objects can be detected later on.
 func (r *KubeadmConfigReconciler) resolveFiles(ctx context.Context, cfg *bootstrapv1.KubeadmConfig) ([]bootstrapv1.File, error) {
+	collected, hash, err := r.resolveFileSources(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Status.FileSourcesHash = hash
+	return collected, nil
+}
+
+// resolveFileSources resolves the content of .Spec.Files referencing ConfigMaps or Secrets and
+// returns the resolved files together with a hash of the resolved content.
+func (r *KubeadmConfigReconciler) resolveFileSources(ctx context.Context, cfg *bootstrapv1.KubeadmConfig) ([]bootstrapv1.File, string, error) {
 	collected := make([]bootstrapv1.File, 0, len(cfg.Spec.Files))
+	resolved := [][]byte{}
+	size := 0
 
 	for i := range cfg.Spec.Files {
 		in := cfg.Spec.Files[i]
 		if in.ContentFrom != nil {
-			data, err := r.resolveSecretFileContent(ctx, cfg.Namespace, in)
+			var data []byte
+			var err error
+			if in.ContentFrom.ConfigMap != nil {
+				data, err = r.resolveConfigMapFileContent(ctx, cfg.Namespace, in)
+			} else {
+				data, err = r.resolveSecretFileContent(ctx, cfg.Namespace, in)
+			}
 			if err != nil {
-				return nil, errors.Wrapf(err, "failed to resolve file source")
+				return nil, "", errors.Wrapf(err, "failed to resolve file source")
+			}
+			size += len(data)
+			if size > maxFileSourcesSize {
+				return nil, "", errors.Errorf("failed to resolve file source: content referenced by files exceeds the maximum size of %d bytes", maxFileSourcesSize)
 			}
+			resolved = append(resolved, []byte(in.Path), data)
 			in.ContentFrom = nil
 			in.Content = string(data)
 		}
 		collected = append(collected, in)
 	}
 
-	return collected, nil
+	if len(resolved) == 0 {
+		return collected, "", nil
+	}
+	return collected, computeHash(resolved), nil
 }
 
 // resolveSecretFileContent returns file content fetched from a referenced secret object.
@@ -777,6 +1029,91 @@ func (r *KubeadmConfigReconciler) resolveSecretFileContent(ctx context.Context,
 	return data, nil
 }
 
+// resolveConfigMapFileContent returns file content fetched from a referenced config map object.
+func (r *KubeadmConfigReconciler) resolveConfigMapFileContent(ctx context.Context, ns string, source bootstrapv1.File) ([]byte, error) {
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: ns, Name: source.ContentFrom.ConfigMap.Name}
+	if err := r.Client.Get(ctx, key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "config map not found: %s", key)
+		}
+		return nil, errors.Wrapf(err, "failed to retrieve ConfigMap %q", key)
+	}
+	if data, ok := configMap.Data[source.ContentFrom.ConfigMap.Key]; ok {
+		return []byte(data), nil
+	}
+	if data, ok := configMap.BinaryData[source.ContentFrom.ConfigMap.Key]; ok {
+		return data, nil
+	}
+	return nil, errors.Errorf("config map references non-existent config map key: %q", source.ContentFrom.ConfigMap.Key)
+}
+
+// reconcileFileSources compares the content currently referenced by .Spec.Files with the content
+// used when generating the bootstrap data, and surfaces any difference using the FileSourcesUpToDate condition.
+// NOTE: The bootstrap data is not re-generated, because it might already have been consumed; KubeadmControlPlane
+// replaces its Machines, while for MachineDeployments and MachinePools it is up to the users to trigger a rollout.
+// NOTE: The content is read from the API server at most once every few minutes, given that ConfigMaps and
+// Secrets are not cached, so changes are not detected immediately.
+func (r *KubeadmConfigReconciler) reconcileFileSources(ctx context.Context, config *bootstrapv1.KubeadmConfig) {
+	log := ctrl.LoggerFrom(ctx)
+
+	hasFileSources := false
+	for i := range config.Spec.Files {
+		if config.Spec.Files[i].ContentFrom != nil {
+			hasFileSources = true
+			break
+		}
+	}
+	if !hasFileSources {
+		conditions.Delete(config, bootstrapv1.FileSourcesUpToDateCondition)
+		return
+	}
+
+	// Once the content changed, the Machine has to be replaced, so there is no need to check it again.
+	if conditions.GetReason(config, bootstrapv1.FileSourcesUpToDateCondition) == bootstrapv1.FileSourcesChangedReason {
+		return
+	}
+
+	cacheKey := fmt.Sprintf("%d.%s", config.Generation, config.Status.FileSourcesHash)
+	if r.fileSourcesCache != nil && r.fileSourcesCache.Has(config.UID, cacheKey) {
+		return
+	}
+
+	_, hash, err := r.resolveFileSources(ctx, config)
+	if err != nil {
+		log.Error(err, "Failed to check if the content referenced by files is up to date")
+		conditions.MarkFalse(config, bootstrapv1.FileSourcesUpToDateCondition, bootstrapv1.FileSourcesResolutionFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return
+	}
+
+	// If the hash is not known, e.g. because the bootstrap data was generated before the hash was introduced
+	// or because the status was lost during a move, assume the current content was used.
+	if config.Status.FileSourcesHash == "" {
+		config.Status.FileSourcesHash = hash
+	}
+
+	if config.Status.FileSourcesHash != hash {
+		conditions.MarkFalse(config, bootstrapv1.FileSourcesUpToDateCondition, bootstrapv1.FileSourcesChangedReason, clusterv1.ConditionSeverityWarning,
+			"Content referenced by files changed after the bootstrap data has been generated, the Machine must be replaced to pick up the change")
+		return
+	}
+	conditions.MarkTrue(config, bootstrapv1.FileSourcesUpToDateCondition)
+	if r.fileSourcesCache != nil {
+		r.fileSourcesCache.Add(config.UID, cacheKey)
+	}
+}
+
+// computeHash returns the hash of the given data; each item is prefixed with its length, so content
+// moving from one item to another, e.g. from one file to the next, changes the hash.
+func computeHash(dataArr [][]byte) string {
+	hash := sha256.New()
+	for i := range dataArr {
+		_ = binary.Write(hash, binary.BigEndian, uint64(len(dataArr[i])))
+		_, _ = hash.Write(dataArr[i])
+	}
+	return fmt.Sprintf("sha256:%x", hash.Sum(nil))
+}
+
 // resolveUsers maps .Spec.Users into cloudinit.Users, resolving any object references
 // along the way.
 func (r *KubeadmConfigReconciler) resolveUsers(ctx context.Context, cfg *bootstrapv1.KubeadmConfig) ([]bootstrapv1.User, error) {
@@ -906,6 +1243,13 @@ func (r *KubeadmConfigReconciler) reconcileDiscovery(ctx context.Context, cluste
 
 	// if config already contains a file discovery configuration, respect it without further validations
 	if config.Spec.JoinConfiguration.Discovery.File != nil {
+		// if the kubeconfig for file discovery must be generated, it requires the control plane endpoint
+		// unless the server is explicitly set.
+		kubeConfig := config.Spec.JoinConfiguration.Discovery.File.KubeConfig
+		if kubeConfig != nil && (kubeConfig.Cluster == nil || kubeConfig.Cluster.Server == "") && !cluster.Spec.ControlPlaneEndpoint.IsValid() {
+			log.V(1).Info("Waiting for Cluster Controller to set Cluster.Spec.ControlPlaneEndpoint")
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -948,6 +1292,7 @@ func (r *KubeadmConfigReconciler) reconcileDiscovery(ctx context.Context, cluste
 		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to create new bootstrap token")
 		}
+		bootstrapTokensIssuedTotal.With(clusterLabels(cluster)).Inc()
 
 		config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token = token
 		log.V(3).Info("Altering JoinConfiguration.Discovery.BootstrapToken.Token")
@@ -1006,6 +1351,12 @@ func (r *KubeadmConfigReconciler) reconcileTopLevelObjectSettings(ctx context.Co
 		config.Spec.ClusterConfiguration.KubernetesVersion = *machine.Spec.Version
 		log.V(3).Info("Altering ClusterConfiguration.KubernetesVersion", "KubernetesVersion", config.Spec.ClusterConfiguration.KubernetesVersion)
 	}
+
+	// If there is no ImageRepository defined in ClusterConfiguration, use the image registry of the Cluster, if defined
+	if config.Spec.ClusterConfiguration.ImageRepository == "" && cluster.Spec.ImageRegistry != nil && cluster.Spec.ImageRegistry.Repository != "" {
+		config.Spec.ClusterConfiguration.ImageRepository = cluster.Spec.ImageRegistry.Repository
+		log.V(3).Info("Altering ClusterConfiguration.ImageRepository", "ImageRepository", config.Spec.ClusterConfiguration.ImageRepository)
+	}
 }
 
 // storeBootstrapData creates a new secret with the data passed in as input,
@@ -1037,6 +1388,28 @@ func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope
 		Type: clusterv1.ClusterSecretType,
 	}
 
+	delivery, err := r.bootstrapDataDelivery(ctx, scope)
+	if err != nil {
+		return err
+	}
+	if delivery == clusterv1.SecureChannelBootstrapDataDelivery {
+		// Fall back to the default delivery instead of failing, because the format is set by the users
+		// while the secure channel is requested by the infrastructure provider.
+		if err := securechannel.Supported(scope.Config); err != nil {
+			log.Info("Using the default bootstrap data delivery", "reason", err.Error())
+			conditions.MarkFalse(scope.Config, bootstrapv1.SecureChannelAvailableCondition, bootstrapv1.SecureChannelNotSupportedReason, clusterv1.ConditionSeverityWarning,
+				"%s, using the default bootstrap data delivery", err.Error())
+			delivery = ""
+		}
+	}
+	if delivery == clusterv1.SecureChannelBootstrapDataDelivery {
+		secret.Data, err = securechannel.SecretData(r.SecureChannel, scope.Config, data, time.Now())
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate bootstrap data secret for KubeadmConfig %s/%s", scope.Config.Namespace, scope.Config.Name)
+		}
+		conditions.MarkTrue(scope.Config, bootstrapv1.SecureChannelAvailableCondition)
+	}
+
 	// as secret creation and scope.Config status patch are not atomic operations
 	// it is possible that secret creation happens but the config.Status patches are not applied
 	if err := r.Client.Create(ctx, secret); err != nil {
@@ -1044,6 +1417,14 @@ func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope
 			return errors.Wrapf(err, "failed to create bootstrap data secret for KubeadmConfig %s/%s", scope.Config.Namespace, scope.Config.Name)
 		}
 		log.Info("bootstrap data secret for KubeadmConfig already exists, updating", "Secret", klog.KObj(secret))
+		if delivery == clusterv1.SecureChannelBootstrapDataDelivery {
+			// Keep the one-time token, because the machine might already have read the payload.
+			existing := &corev1.Secret{}
+			if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secret), existing); err != nil {
+				return errors.Wrapf(err, "failed to get bootstrap data secret for KubeadmConfig %s/%s", scope.Config.Namespace, scope.Config.Name)
+			}
+			secret.Data = securechannel.KeepToken(existing.Data, secret.Data)
+		}
 		if err := r.Client.Update(ctx, secret); err != nil {
 			return errors.Wrapf(err, "failed to update bootstrap data secret for KubeadmConfig %s/%s", scope.Config.Namespace, scope.Config.Name)
 		}
@@ -1054,6 +1435,38 @@ func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope
 	return nil
 }
 
+// bootstrapDataDelivery returns the bootstrap data delivery requested by the infrastructure object of the config owner.
+// The infrastructure object is read only if the bootstrap data server is enabled, because otherwise the bootstrap
+// data can only be delivered using the default delivery.
+func (r *KubeadmConfigReconciler) bootstrapDataDelivery(ctx context.Context, scope *Scope) (clusterv1.BootstrapDataDelivery, error) {
+	if r.SecureChannel.URL == "" {
+		return "", nil
+	}
+
+	infraRef := scope.ConfigOwner.InfrastructureRef()
+	if infraRef == nil || infraRef.Name == "" {
+		return "", nil
+	}
+
+	infraObj, err := external.Get(ctx, r.Client, infraRef, scope.ConfigOwner.GetNamespace())
+	if err != nil {
+		// The infrastructure object is not required to generate the bootstrap data, e.g. it could have been
+		// deleted together with the Machine; fall back to the default delivery.
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to read bootstrap data delivery from %s %s", infraRef.Kind, infraRef.Name)
+	}
+
+	delivery := clusterv1.BootstrapDataDelivery(infraObj.GetAnnotations()[clusterv1.BootstrapDataDeliveryAnnotation])
+	switch delivery {
+	case "", clusterv1.SecureChannelBootstrapDataDelivery:
+		return delivery, nil
+	default:
+		return "", errors.Errorf("unsupported bootstrap data delivery %q requested by %s %s", delivery, infraRef.Kind, infraRef.Name)
+	}
+}
+
 // Ensure the bootstrap secret has the KubeadmConfig as a controller OwnerReference.
 func (r *KubeadmConfigReconciler) ensureBootstrapSecretOwnersRef(ctx context.Context, scope *Scope) error {
 	secret := &corev1.Secret{}
@@ -18,9 +18,12 @@ package controllers
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,6 +32,8 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
 	"k8s.io/utils/pointer"
@@ -42,6 +47,8 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	bootstrapbuilder "sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/builder"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/securechannel"
+	bsutil "sigs.k8s.io/cluster-api/bootstrap/util"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
@@ -828,6 +835,16 @@ func TestBootstrapDataFormat(t *testing.T) {
 			format:             bootstrapv1.Ignition,
 			clusterInitialized: true,
 		},
+		{
+			name:   "shell init config",
+			format: bootstrapv1.Shell,
+		},
+		{
+			name:               "shell worker join config",
+			isWorker:           true,
+			format:             bootstrapv1.Shell,
+			clusterInitialized: true,
+		},
 		{
 			name: "Empty format field",
 		},
@@ -919,11 +936,82 @@ func TestBootstrapDataFormat(t *testing.T) {
 				_, reports, err := ignition.Parse(data)
 				g.Expect(err).ToNot(HaveOccurred())
 				g.Expect(reports.IsFatal()).NotTo(BeTrue())
+			case bootstrapv1.Shell:
+				// Verify the bootstrap data is a shell script.
+				g.Expect(string(data)).To(HavePrefix("#!/bin/bash\n"))
 			}
 		})
 	}
 }
 
+// Ensure bootstrap data is packaged as defined by the output spec of the KubeadmConfig resource.
+func TestBootstrapDataOutput(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster").Build()
+	cluster.Status.InfrastructureReady = true
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	machine := newControlPlaneMachine(cluster, "machine")
+	config := newControlPlaneInitKubeadmConfig(metav1.NamespaceDefault, "cfg")
+	addKubeadmConfigToMachine(config, machine)
+	config.Spec.Format = bootstrapv1.CloudConfig
+	config.Spec.Output = &bootstrapv1.OutputSpec{
+		MultiPart: &bootstrapv1.MultiPartOutput{
+			Parts: []bootstrapv1.MIMEPart{
+				{
+					ContentType: bootstrapv1.ShellScriptMIMEPartContentType,
+					Content:     "#!/bin/bash\necho hello\n",
+				},
+			},
+		},
+		Compression: bootstrapv1.GzipOutputCompression,
+	}
+
+	objects := []client.Object{
+		cluster,
+		machine,
+		config,
+	}
+	objects = append(objects, createSecrets(t, cluster, config)...)
+
+	myclient := fake.NewClientBuilder().WithObjects(objects...).WithStatusSubresource(&bootstrapv1.KubeadmConfig{}).Build()
+
+	k := &KubeadmConfigReconciler{
+		Client:              myclient,
+		SecretCachingClient: myclient,
+		Tracker:             remote.NewTestClusterCacheTracker(logr.New(log.NullLogSink{}), myclient, myclient.Scheme(), client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}),
+		KubeadmInitLock:     &myInitLocker{},
+	}
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "cfg",
+		},
+	}
+
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, "cfg", metav1.NamespaceDefault)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+
+	secret := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: *cfg.Status.DataSecretName}, secret)).To(Succeed())
+	g.Expect(string(secret.Data["format"])).To(Equal(string(bootstrapv1.CloudConfig)))
+
+	// Verify the bootstrap data is a gzip compressed multi-part MIME document.
+	r, err := gzip.NewReader(bytes.NewReader(secret.Data["value"]))
+	g.Expect(err).ToNot(HaveOccurred())
+	data, err := io.ReadAll(r)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(HavePrefix("Content-Type: multipart/mixed; boundary="))
+	g.Expect(string(data)).To(ContainSubstring("#cloud-config"))
+	g.Expect(string(data)).To(ContainSubstring("echo hello"))
+}
+
 // during kubeadmconfig reconcile it is possible that bootstrap secret gets created
 // but kubeadmconfig is not patched, do not error if secret already exists.
 // ignore the alreadyexists error and update the status to ready.
@@ -1154,7 +1242,7 @@ func TestBootstrapTokenTTLExtension(t *testing.T) {
 		tokenExpires[i] = item.Data[bootstrapapi.BootstrapTokenExpirationKey]
 	}
 
-	// ...until the Nodes have actually joined the cluster and we get a nodeRef
+	// ...until the Nodes have actually joined the cluster and we get a nodeRef, then the token is revoked.
 	patchHelper, err = patch.NewHelper(workerMachine, myclient)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	workerMachine.Status.NodeRef = &corev1.ObjectReference{
@@ -1193,16 +1281,112 @@ func TestBootstrapTokenTTLExtension(t *testing.T) {
 		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(result.Requeue).To(BeFalse())
 		g.Expect(result.RequeueAfter).To(Equal(time.Duration(0)))
+
+		cfg, err := getKubeadmConfig(myclient, req.Name, req.Namespace)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token).To(BeEmpty())
 	}
 
 	l = &corev1.SecretList{}
 	err = myclient.List(ctx, l, client.ListOption(client.InNamespace(metav1.NamespaceSystem)))
 	g.Expect(err).ToNot(HaveOccurred())
-	g.Expect(l.Items).To(HaveLen(2))
+	g.Expect(l.Items).To(BeEmpty())
+}
 
-	for i, item := range l.Items {
-		g.Expect(bytes.Equal(tokenExpires[i], item.Data[bootstrapapi.BootstrapTokenExpirationKey])).To(BeTrue())
+func TestBootstrapTokenRevocation(t *testing.T) {
+	setup := func(t *testing.T, g *WithT) (*KubeadmConfigReconciler, client.Client, *clusterv1.Machine, ctrl.Request) {
+		t.Helper()
+
+		cluster := builder.Cluster(metav1.NamespaceDefault, "cluster").Build()
+		cluster.Status.InfrastructureReady = true
+		conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+		cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+		controlPlaneInitMachine := newControlPlaneMachine(cluster, "control-plane-init-machine")
+		initConfig := newControlPlaneInitKubeadmConfig(controlPlaneInitMachine.Namespace, "control-plane-init-config")
+		addKubeadmConfigToMachine(initConfig, controlPlaneInitMachine)
+
+		workerMachine := newWorkerMachineForCluster(cluster)
+		workerMachine.Finalizers = []string{clusterv1.MachineFinalizer}
+		workerJoinConfig := newWorkerJoinKubeadmConfig(metav1.NamespaceDefault, "worker-join-cfg")
+		addKubeadmConfigToMachine(workerJoinConfig, workerMachine)
+
+		objects := []client.Object{cluster, workerMachine, workerJoinConfig}
+		objects = append(objects, createSecrets(t, cluster, initConfig)...)
+		myclient := fake.NewClientBuilder().WithObjects(objects...).WithStatusSubresource(&bootstrapv1.KubeadmConfig{}, &clusterv1.Machine{}).Build()
+		k := &KubeadmConfigReconciler{
+			Client:              myclient,
+			SecretCachingClient: myclient,
+			Tracker:             remote.NewTestClusterCacheTracker(logr.New(log.NullLogSink{}), myclient, myclient.Scheme(), client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}),
+			KubeadmInitLock:     &myInitLocker{},
+			TokenTTL:            DefaultTokenTTL,
+		}
+		request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(workerJoinConfig)}
+
+		// Generate the bootstrap data, and with it the bootstrap token.
+		_, err := k.Reconcile(ctx, request)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		l := &corev1.SecretList{}
+		g.Expect(myclient.List(ctx, l, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+		g.Expect(l.Items).To(HaveLen(1))
+
+		return k, myclient, workerMachine, request
 	}
+
+	t.Run("revokes the token when the Machine is deleted", func(t *testing.T) {
+		g := NewWithT(t)
+		k, myclient, workerMachine, request := setup(t, g)
+
+		g.Expect(myclient.Delete(ctx, workerMachine)).To(Succeed())
+
+		result, err := k.Reconcile(ctx, request)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(time.Duration(0)))
+
+		cfg, err := getKubeadmConfig(myclient, request.Name, request.Namespace)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token).To(BeEmpty())
+
+		l := &corev1.SecretList{}
+		g.Expect(myclient.List(ctx, l, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+		g.Expect(l.Items).To(BeEmpty())
+	})
+
+	t.Run("does not revoke tokens not created by the bootstrap provider", func(t *testing.T) {
+		g := NewWithT(t)
+		k, myclient, workerMachine, request := setup(t, g)
+
+		l := &corev1.SecretList{}
+		g.Expect(myclient.List(ctx, l, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+		userToken := l.Items[0].DeepCopy()
+		userToken.Data[bootstrapapi.BootstrapTokenDescriptionKey] = []byte("token shared by all the workers")
+		g.Expect(myclient.Update(ctx, userToken)).To(Succeed())
+
+		g.Expect(myclient.Delete(ctx, workerMachine)).To(Succeed())
+
+		_, err := k.Reconcile(ctx, request)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(myclient.List(ctx, l, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+		g.Expect(l.Items).To(HaveLen(1))
+	})
+
+	t.Run("stops refreshing the token once it expired", func(t *testing.T) {
+		g := NewWithT(t)
+		k, myclient, _, request := setup(t, g)
+
+		// The token cleaner in the workload cluster deletes the expired tokens.
+		g.Expect(myclient.DeleteAllOf(ctx, &corev1.Secret{}, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+
+		result, err := k.Reconcile(ctx, request)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(time.Duration(0)))
+
+		cfg, err := getKubeadmConfig(myclient, request.Name, request.Namespace)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token).To(BeEmpty())
+	})
 }
 
 func TestBootstrapTokenRotationMachinePool(t *testing.T) {
@@ -1599,6 +1783,7 @@ func TestKubeadmConfigReconciler_Reconcile_DynamicDefaultsForClusterConfiguratio
 							DNSDomain:     "myDNSDomain",
 						},
 						ControlPlaneEndpoint: "myControlPlaneEndpoint:6443",
+						ImageRepository:      "myImageRepository",
 					},
 				},
 			},
@@ -1613,6 +1798,7 @@ func TestKubeadmConfigReconciler_Reconcile_DynamicDefaultsForClusterConfiguratio
 						ServiceDomain: "otherServiceDomain",
 					},
 					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "otherVersion", Port: 0},
+					ImageRegistry:        &clusterv1.ImageRegistry{Repository: "otherImageRepository"},
 				},
 			},
 			machine: &clusterv1.Machine{
@@ -1639,6 +1825,7 @@ func TestKubeadmConfigReconciler_Reconcile_DynamicDefaultsForClusterConfiguratio
 						ServiceDomain: "myDNSDomain",
 					},
 					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "myControlPlaneEndpoint", Port: 6443},
+					ImageRegistry:        &clusterv1.ImageRegistry{Repository: "myImageRepository"},
 				},
 			},
 			machine: &clusterv1.Machine{
@@ -1661,6 +1848,7 @@ func TestKubeadmConfigReconciler_Reconcile_DynamicDefaultsForClusterConfiguratio
 			g.Expect(tc.config.Spec.ClusterConfiguration.Networking.ServiceSubnet).To(Equal("myServiceSubnet"))
 			g.Expect(tc.config.Spec.ClusterConfiguration.Networking.DNSDomain).To(Equal("myDNSDomain"))
 			g.Expect(tc.config.Spec.ClusterConfiguration.KubernetesVersion).To(Equal("myversion"))
+			g.Expect(tc.config.Spec.ClusterConfiguration.ImageRepository).To(Equal("myImageRepository"))
 		})
 	}
 }
@@ -1962,6 +2150,17 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 			"key": []byte("foo"),
 		},
 	}
+	testConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "source",
+		},
+		Data: map[string]string{
+			"key": "baz",
+		},
+		BinaryData: map[string][]byte{
+			"binary-key": []byte("qux"),
+		},
+	}
 
 	cases := map[string]struct {
 		cfg     *bootstrapv1.KubeadmConfig
@@ -2018,6 +2217,51 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 			},
 			objects: []client.Object{testSecret},
 		},
+		"contentFrom a config map should convert correctly": {
+			cfg: &bootstrapv1.KubeadmConfig{
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Files: []bootstrapv1.File{
+						{
+							ContentFrom: &bootstrapv1.FileSource{
+								ConfigMap: &bootstrapv1.ConfigMapFileSource{
+									Name: "source",
+									Key:  "key",
+								},
+							},
+							Path:        "/path",
+							Owner:       "root:root",
+							Permissions: "0600",
+						},
+						{
+							ContentFrom: &bootstrapv1.FileSource{
+								ConfigMap: &bootstrapv1.ConfigMapFileSource{
+									Name: "source",
+									Key:  "binary-key",
+								},
+							},
+							Path:        "/binary-path",
+							Owner:       "root:root",
+							Permissions: "0600",
+						},
+					},
+				},
+			},
+			expect: []bootstrapv1.File{
+				{
+					Content:     "baz",
+					Path:        "/path",
+					Owner:       "root:root",
+					Permissions: "0600",
+				},
+				{
+					Content:     "qux",
+					Path:        "/binary-path",
+					Owner:       "root:root",
+					Permissions: "0600",
+				},
+			},
+			objects: []client.Object{testConfigMap},
+		},
 		"multiple files should work correctly": {
 			cfg: &bootstrapv1.KubeadmConfig{
 				Spec: bootstrapv1.KubeadmConfigSpec{
@@ -2095,6 +2339,157 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 	}
 }
 
+func TestKubeadmConfigReconciler_ResolveFilesExceedingMaxSize(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "source",
+		},
+		Data: map[string]string{
+			"key": strings.Repeat("a", maxFileSourcesSize/2+1),
+		},
+	}
+	cfg := &bootstrapv1.KubeadmConfig{
+		Spec: bootstrapv1.KubeadmConfigSpec{
+			Files: []bootstrapv1.File{
+				{
+					ContentFrom: &bootstrapv1.FileSource{
+						ConfigMap: &bootstrapv1.ConfigMapFileSource{Name: "source", Key: "key"},
+					},
+					Path: "/foo",
+				},
+				{
+					ContentFrom: &bootstrapv1.FileSource{
+						ConfigMap: &bootstrapv1.ConfigMapFileSource{Name: "source", Key: "key"},
+					},
+					Path: "/bar",
+				},
+			},
+		},
+	}
+
+	myclient := fake.NewClientBuilder().WithObjects(testConfigMap).Build()
+	k := &KubeadmConfigReconciler{
+		Client:              myclient,
+		SecretCachingClient: myclient,
+		KubeadmInitLock:     &myInitLocker{},
+	}
+
+	_, err := k.resolveFiles(ctx, cfg)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("exceeds the maximum size"))
+}
+
+func TestKubeadmConfigReconciler_ReconcileFileSources(t *testing.T) {
+	g := NewWithT(t)
+
+	testConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string]string{
+			"key": "foo",
+		},
+	}
+	cfg := &bootstrapv1.KubeadmConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cfg",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "cfg-uid",
+		},
+		Spec: bootstrapv1.KubeadmConfigSpec{
+			Files: []bootstrapv1.File{
+				{
+					ContentFrom: &bootstrapv1.FileSource{
+						ConfigMap: &bootstrapv1.ConfigMapFileSource{Name: "source", Key: "key"},
+					},
+					Path: "/foo",
+				},
+			},
+		},
+	}
+
+	myclient := fake.NewClientBuilder().WithObjects(testConfigMap).Build()
+	k := &KubeadmConfigReconciler{
+		Client:              myclient,
+		SecretCachingClient: myclient,
+		KubeadmInitLock:     &myInitLocker{},
+		fileSourcesCache:    newFileSourcesCheckCache(),
+	}
+
+	// Generating the bootstrap data records the hash of the resolved content.
+	_, err := k.resolveFiles(ctx, cfg)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cfg.Status.FileSourcesHash).ToNot(BeEmpty())
+
+	k.reconcileFileSources(ctx, cfg)
+	g.Expect(conditions.IsTrue(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(BeTrue())
+
+	// The content found up to date is not read again until the cache expires.
+	testConfigMap.Data["key"] = "bar"
+	g.Expect(myclient.Update(ctx, testConfigMap)).To(Succeed())
+
+	k.reconcileFileSources(ctx, cfg)
+	g.Expect(conditions.IsTrue(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(BeTrue())
+
+	// Changing the referenced content flags the KubeadmConfig.
+	k.fileSourcesCache = newFileSourcesCheckCache()
+	k.reconcileFileSources(ctx, cfg)
+	g.Expect(conditions.IsFalse(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(Equal(bootstrapv1.FileSourcesChangedReason))
+
+	// The KubeadmConfig stays flagged until the Machine is replaced.
+	g.Expect(myclient.Delete(ctx, testConfigMap)).To(Succeed())
+
+	k.reconcileFileSources(ctx, cfg)
+	g.Expect(conditions.GetReason(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(Equal(bootstrapv1.FileSourcesChangedReason))
+}
+
+func TestKubeadmConfigReconciler_ReconcileFileSourcesResolutionFailed(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := &bootstrapv1.KubeadmConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cfg",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: bootstrapv1.KubeadmConfigSpec{
+			Files: []bootstrapv1.File{
+				{
+					ContentFrom: &bootstrapv1.FileSource{
+						ConfigMap: &bootstrapv1.ConfigMapFileSource{Name: "source", Key: "key"},
+					},
+					Path: "/foo",
+				},
+			},
+		},
+	}
+
+	myclient := fake.NewClientBuilder().Build()
+	k := &KubeadmConfigReconciler{
+		Client:              myclient,
+		SecretCachingClient: myclient,
+		KubeadmInitLock:     &myInitLocker{},
+	}
+
+	// A missing referenced object flags the KubeadmConfig.
+	k.reconcileFileSources(ctx, cfg)
+	g.Expect(conditions.IsFalse(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(Equal(bootstrapv1.FileSourcesResolutionFailedReason))
+}
+
+func TestComputeHash(t *testing.T) {
+	g := NewWithT(t)
+
+	// Content moving from one file to the next must change the hash.
+	g.Expect(computeHash([][]byte{[]byte("/a"), []byte("x/b"), []byte("")})).
+		ToNot(Equal(computeHash([][]byte{[]byte("/a"), []byte("x"), []byte("/b")})))
+	g.Expect(computeHash([][]byte{[]byte("/a"), []byte("x")})).
+		To(Equal(computeHash([][]byte{[]byte("/a"), []byte("x")})))
+}
+
 func TestKubeadmConfigReconciler_ResolveUsers(t *testing.T) {
 	fakePasswd := "bar"
 	testSecret := &corev1.Secret{
@@ -2397,3 +2792,123 @@ func assertHasTrueCondition(g *WithT, myclient client.Client, req ctrl.Request,
 	g.Expect(c).ToNot(BeNil())
 	g.Expect(c.Status).To(Equal(corev1.ConditionTrue))
 }
+
+func TestKubeadmConfigReconciler_StoreBootstrapDataSecureChannel(t *testing.T) {
+	infraMachine := &unstructured.Unstructured{}
+	infraMachine.SetAPIVersion(builder.InfrastructureGroupVersion.String())
+	infraMachine.SetKind(builder.GenericInfrastructureMachineKind)
+	infraMachine.SetNamespace(metav1.NamespaceDefault)
+	infraMachine.SetName("infra-machine")
+	infraMachine.SetAnnotations(map[string]string{clusterv1.BootstrapDataDeliveryAnnotation: string(clusterv1.SecureChannelBootstrapDataDelivery)})
+
+	machine := builder.Machine(metav1.NamespaceDefault, "machine").Build()
+	machine.Spec.InfrastructureRef = corev1.ObjectReference{
+		APIVersion: infraMachine.GetAPIVersion(),
+		Kind:       infraMachine.GetKind(),
+		Name:       infraMachine.GetName(),
+	}
+	machineContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(machine)
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	newScope := func(format bootstrapv1.Format) *Scope {
+		return &Scope{
+			Logger: ctrl.LoggerFrom(ctx),
+			Config: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "config"},
+				Spec:       bootstrapv1.KubeadmConfigSpec{Format: format},
+			},
+			ConfigOwner: &bsutil.ConfigOwner{Unstructured: &unstructured.Unstructured{Object: machineContent}},
+			Cluster:     builder.Cluster(metav1.NamespaceDefault, "cluster").Build(),
+		}
+	}
+
+	t.Run("stores the payload fetching the bootstrap data", func(t *testing.T) {
+		g := NewWithT(t)
+
+		myclient := fake.NewClientBuilder().WithObjects(infraMachine.DeepCopy()).Build()
+		k := &KubeadmConfigReconciler{
+			Client:        myclient,
+			SecureChannel: securechannel.Options{URL: "https://bootstrap.example.com"},
+		}
+
+		scope := newScope(bootstrapv1.Shell)
+		g.Expect(k.storeBootstrapData(ctx, scope, []byte("kubeadm join"))).To(Succeed())
+
+		s := &corev1.Secret{}
+		g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "config"}, s)).To(Succeed())
+		g.Expect(s.Data).To(HaveKeyWithValue(securechannel.DataKey, []byte("kubeadm join")))
+		g.Expect(s.Data).To(HaveKey(securechannel.TokenHashKey))
+		g.Expect(string(s.Data["value"])).To(ContainSubstring("https://bootstrap.example.com/bootstrap-data/default/config"))
+	})
+
+	t.Run("keeps the one-time token if the secret already exists", func(t *testing.T) {
+		g := NewWithT(t)
+
+		myclient := fake.NewClientBuilder().WithObjects(infraMachine.DeepCopy()).Build()
+		k := &KubeadmConfigReconciler{
+			Client:        myclient,
+			SecureChannel: securechannel.Options{URL: "https://bootstrap.example.com"},
+		}
+
+		g.Expect(k.storeBootstrapData(ctx, newScope(bootstrapv1.Shell), []byte("kubeadm join"))).To(Succeed())
+		existing := &corev1.Secret{}
+		g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "config"}, existing)).To(Succeed())
+
+		g.Expect(k.storeBootstrapData(ctx, newScope(bootstrapv1.Shell), []byte("kubeadm join"))).To(Succeed())
+		s := &corev1.Secret{}
+		g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "config"}, s)).To(Succeed())
+		g.Expect(s.Data).To(HaveKeyWithValue("value", existing.Data["value"]))
+		g.Expect(s.Data).To(HaveKeyWithValue(securechannel.TokenHashKey, existing.Data[securechannel.TokenHashKey]))
+	})
+
+	t.Run("uses the default delivery if the format is not supported", func(t *testing.T) {
+		g := NewWithT(t)
+
+		myclient := fake.NewClientBuilder().WithObjects(infraMachine.DeepCopy()).Build()
+		k := &KubeadmConfigReconciler{
+			Client:        myclient,
+			SecureChannel: securechannel.Options{URL: "https://bootstrap.example.com"},
+		}
+
+		scope := newScope(bootstrapv1.CloudConfig)
+		g.Expect(k.storeBootstrapData(ctx, scope, []byte("kubeadm join"))).To(Succeed())
+		g.Expect(conditions.IsFalse(scope.Config, bootstrapv1.SecureChannelAvailableCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(scope.Config, bootstrapv1.SecureChannelAvailableCondition)).To(Equal(bootstrapv1.SecureChannelNotSupportedReason))
+
+		s := &corev1.Secret{}
+		g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "config"}, s)).To(Succeed())
+		g.Expect(s.Data).To(HaveKeyWithValue("value", []byte("kubeadm join")))
+		g.Expect(s.Data).ToNot(HaveKey(securechannel.TokenHashKey))
+	})
+
+	t.Run("uses the default delivery if the bootstrap data server is not enabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// The infrastructure machine is not read at all if the bootstrap data server is not enabled.
+		myclient := fake.NewClientBuilder().Build()
+		k := &KubeadmConfigReconciler{Client: myclient}
+
+		g.Expect(k.storeBootstrapData(ctx, newScope(bootstrapv1.Shell), []byte("kubeadm join"))).To(Succeed())
+
+		s := &corev1.Secret{}
+		g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "config"}, s)).To(Succeed())
+		g.Expect(s.Data).To(HaveKeyWithValue("value", []byte("kubeadm join")))
+		g.Expect(s.Data).ToNot(HaveKey(securechannel.TokenHashKey))
+	})
+
+	t.Run("uses the default delivery if the infrastructure machine does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		myclient := fake.NewClientBuilder().Build()
+		k := &KubeadmConfigReconciler{
+			Client:        myclient,
+			SecureChannel: securechannel.Options{URL: "https://bootstrap.example.com"},
+		}
+
+		g.Expect(k.storeBootstrapData(ctx, newScope(bootstrapv1.Shell), []byte("kubeadm join"))).To(Succeed())
+
+		s := &corev1.Secret{}
+		g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "config"}, s)).To(Succeed())
+		g.Expect(s.Data).To(HaveKeyWithValue("value", []byte("kubeadm join")))
+	})
+}
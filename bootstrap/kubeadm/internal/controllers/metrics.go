@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(bootstrapTokensIssuedTotal)
+	ctrlmetrics.Registry.MustRegister(bootstrapTokensExpiredTotal)
+	ctrlmetrics.Registry.MustRegister(bootstrapTokensRevokedTotal)
+}
+
+// Metrics subsystem for the bootstrap tokens managed by the KubeadmConfig controller.
+const bootstrapTokenSubsystem = "capi_kubeadm_bootstrap_token"
+
+var (
+	// bootstrapTokensIssuedTotal reports the number of bootstrap tokens created in workload clusters.
+	bootstrapTokensIssuedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: bootstrapTokenSubsystem,
+		Name:      "issued_total",
+		Help:      "Number of bootstrap tokens issued, partitioned by cluster.",
+	}, []string{"cluster_namespace", "cluster_name"})
+
+	// bootstrapTokensExpiredTotal reports the number of bootstrap tokens which expired before being used.
+	bootstrapTokensExpiredTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: bootstrapTokenSubsystem,
+		Name:      "expired_total",
+		Help:      "Number of bootstrap tokens which expired before the node joined, partitioned by cluster.",
+	}, []string{"cluster_namespace", "cluster_name"})
+
+	// bootstrapTokensRevokedTotal reports the number of bootstrap tokens deleted from workload clusters.
+	bootstrapTokensRevokedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: bootstrapTokenSubsystem,
+		Name:      "revoked_total",
+		Help:      "Number of bootstrap tokens revoked after the node joined or the machine was deleted, partitioned by cluster.",
+	}, []string{"cluster_namespace", "cluster_name"})
+)
+
+func clusterLabels(cluster *clusterv1.Cluster) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_namespace": cluster.Namespace,
+		"cluster_name":      cluster.Name,
+	}
+}
@@ -29,6 +29,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// tokenDescription is used to identify the tokens created by the bootstrap provider.
+const tokenDescription = "token generated by cluster-api-bootstrap-provider-kubeadm"
+
 // createToken attempts to create a token with the given ID.
 func createToken(ctx context.Context, c client.Client, ttl time.Duration) (string, error) {
 	token, err := bootstraputil.GenerateBootstrapToken()
@@ -57,7 +60,7 @@ func createToken(ctx context.Context, c client.Client, ttl time.Duration) (strin
 			bootstrapapi.BootstrapTokenUsageSigningKey:     []byte("true"),
 			bootstrapapi.BootstrapTokenUsageAuthentication: []byte("true"),
 			bootstrapapi.BootstrapTokenExtraGroupsKey:      []byte("system:bootstrappers:kubeadm:default-node-token"),
-			bootstrapapi.BootstrapTokenDescriptionKey:      []byte("token generated by cluster-api-bootstrap-provider-kubeadm"),
+			bootstrapapi.BootstrapTokenDescriptionKey:      []byte(tokenDescription),
 		},
 	}
 
@@ -111,9 +114,43 @@ func shouldRotate(ctx context.Context, c client.Client, token string, ttl time.D
 		return false, err
 	}
 
-	expiration, err := time.Parse(time.RFC3339, string(secret.Data[bootstrapapi.BootstrapTokenExpirationKey]))
+	expiration, err := tokenExpiration(secret)
 	if err != nil {
 		return false, err
 	}
 	return expiration.Before(time.Now().UTC().Add(ttl / 2)), nil
 }
+
+// tokenExpiration returns the expiration time of the token stored in the given Secret.
+func tokenExpiration(secret *corev1.Secret) (time.Time, error) {
+	expiration, err := time.Parse(time.RFC3339, string(secret.Data[bootstrapapi.BootstrapTokenExpirationKey]))
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to parse expiration of bootstrap secret %q", secret.Name)
+	}
+	return expiration, nil
+}
+
+// revokeToken deletes the token Secret, so the token can't be used anymore to join the cluster.
+// Tokens which have not been created by the bootstrap provider, e.g. a token provided by the user and
+// shared by many machines, are left untouched. It returns false if no Secret has been deleted.
+func revokeToken(ctx context.Context, c client.Client, token string) (bool, error) {
+	secret, err := getToken(ctx, c, token)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if string(secret.Data[bootstrapapi.BootstrapTokenDescriptionKey]) != tokenDescription {
+		return false, nil
+	}
+
+	if err := c.Delete(ctx, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"path"
+	"text/template"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+const (
+	// tunnelAgentDirectory is the directory the manifest and the certificates of the tunnel agent are written to.
+	tunnelAgentDirectory = "/etc/kubernetes/tunnel"
+
+	// tunnelAgentManifest is the name of the static pod manifest of the tunnel agent.
+	tunnelAgentManifest = "tunnel-agent.yaml"
+
+	// tunnelCACrtDataName is the key used to store the CA of the tunnel server in the tunnel secret.
+	tunnelCACrtDataName = "ca.crt"
+
+	// defaultTunnelAgentImage is the image of the tunnel agent used if the Cluster does not define one.
+	defaultTunnelAgentImage = "registry.k8s.io/kas-network-proxy/proxy-agent:v0.28.0"
+)
+
+var tunnelAgentManifestTemplate = template.Must(template.New("tunnel-agent").Parse(`apiVersion: v1
+kind: Pod
+metadata:
+  name: tunnel-agent
+  namespace: kube-system
+spec:
+  hostNetwork: true
+  priorityClassName: system-node-critical
+  containers:
+  - name: tunnel-agent
+    image: {{ .Image }}
+    command:
+    - /proxy-agent
+    args:
+    - --logtostderr=true
+    - --ca-cert={{ .Directory }}/ca.crt
+    - --agent-cert={{ .Directory }}/tls.crt
+    - --agent-key={{ .Directory }}/tls.key
+    - --proxy-server-host={{ .ServerHost }}
+    - --proxy-server-port={{ .ServerPort }}
+{{- if .APIServerHost }}
+    - --agent-identifiers=host={{ .APIServerHost }}
+{{- end }}
+    volumeMounts:
+    - name: tunnel
+      mountPath: {{ .Directory }}
+      readOnly: true
+  volumes:
+  - name: tunnel
+    hostPath:
+      path: {{ .Directory }}
+      type: Directory
+`))
+
+// tunnelAgentFiles returns the files and the commands deploying the tunnel agent on a control plane machine,
+// if the Cluster defines a tunnel with an agent. The agent runs as static pod and connects to the tunnel server
+// with the certificates stored in the tunnel secret of the Cluster; it registers with the host of the control plane
+// endpoint, so the tunnel server can route the connections to the API server of the Cluster through it.
+//
+// NOTE: The static pod manifest is moved to the kubelet manifests directory by a command run after kubeadm,
+// because kubeadm requires the manifests directory to be empty.
+func (r *KubeadmConfigReconciler) tunnelAgentFiles(ctx context.Context, cluster *clusterv1.Cluster) ([]bootstrapv1.File, []string, error) {
+	if cluster.Spec.Tunnel == nil || cluster.Spec.Tunnel.Agent == nil {
+		return nil, nil, nil
+	}
+	agent := cluster.Spec.Tunnel.Agent
+
+	host, port, err := net.SplitHostPort(agent.ServerAddress)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse the tunnel server address %q", agent.ServerAddress)
+	}
+
+	tunnelSecret := &corev1.Secret{}
+	secretKey := client.ObjectKey{Namespace: cluster.Namespace, Name: secret.Name(cluster.Name, secret.Tunnel)}
+	if err := r.Client.Get(ctx, secretKey, tunnelSecret); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get tunnel secret %s", secretKey)
+	}
+
+	image := agent.Image
+	if image == "" {
+		image = defaultTunnelAgentImage
+	}
+	manifest := &bytes.Buffer{}
+	if err := tunnelAgentManifestTemplate.Execute(manifest, map[string]string{
+		"Image":         image,
+		"Directory":     tunnelAgentDirectory,
+		"ServerHost":    host,
+		"ServerPort":    port,
+		"APIServerHost": cluster.Spec.ControlPlaneEndpoint.Host,
+	}); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate the tunnel agent manifest")
+	}
+
+	files := []bootstrapv1.File{
+		{
+			Path:        path.Join(tunnelAgentDirectory, tunnelAgentManifest),
+			Owner:       "root:root",
+			Permissions: "0644",
+			Content:     manifest.String(),
+		},
+	}
+	for _, key := range []string{tunnelCACrtDataName, secret.TLSCrtDataName, secret.TLSKeyDataName} {
+		data, ok := tunnelSecret.Data[key]
+		if !ok {
+			return nil, nil, errors.Errorf("tunnel secret %s does not contain key %q", secretKey, key)
+		}
+		permissions := "0644"
+		if key == secret.TLSKeyDataName {
+			permissions = "0600"
+		}
+		files = append(files, bootstrapv1.File{
+			Path:        path.Join(tunnelAgentDirectory, key),
+			Owner:       "root:root",
+			Permissions: permissions,
+			Content:     string(data),
+		})
+	}
+
+	commands := []string{
+		"cp " + path.Join(tunnelAgentDirectory, tunnelAgentManifest) + " /etc/kubernetes/manifests/" + tunnelAgentManifest,
+	}
+	return files, commands, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestTunnelAgentFiles(t *testing.T) {
+	newCluster := func(tunnel *clusterv1.ClusterTunnel) *clusterv1.Cluster {
+		cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cluster"}}
+		cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443}
+		cluster.Spec.Tunnel = tunnel
+		return cluster
+	}
+	tunnelSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cluster-tunnel"},
+		Data: map[string][]byte{
+			"ca.crt":  []byte("ca"),
+			"tls.crt": []byte("cert"),
+			"tls.key": []byte("key"),
+		},
+	}
+
+	t.Run("returns nil if the Cluster does not define a tunnel agent", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &KubeadmConfigReconciler{Client: fake.NewClientBuilder().Build()}
+		files, commands, err := r.tunnelAgentFiles(ctx, newCluster(&clusterv1.ClusterTunnel{ProxyURL: "http://tunnel:8090"}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(files).To(BeEmpty())
+		g.Expect(commands).To(BeEmpty())
+	})
+
+	t.Run("generates the tunnel agent manifest and certificates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &KubeadmConfigReconciler{Client: fake.NewClientBuilder().WithObjects(tunnelSecret).Build()}
+		files, commands, err := r.tunnelAgentFiles(ctx, newCluster(&clusterv1.ClusterTunnel{
+			ProxyURL: "http://tunnel:8090",
+			Agent:    &clusterv1.ClusterTunnelAgent{ServerAddress: "tunnel.example.com:8091"},
+		}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(files).To(HaveLen(4))
+		g.Expect(files[0].Path).To(Equal("/etc/kubernetes/tunnel/tunnel-agent.yaml"))
+		g.Expect(files[0].Content).To(ContainSubstring("image: " + defaultTunnelAgentImage))
+		g.Expect(files[0].Content).To(ContainSubstring("- --proxy-server-host=tunnel.example.com"))
+		g.Expect(files[0].Content).To(ContainSubstring("- --proxy-server-port=8091"))
+		g.Expect(files[0].Content).To(ContainSubstring("- --agent-identifiers=host=10.0.0.1"))
+		g.Expect(files[3].Path).To(Equal("/etc/kubernetes/tunnel/tls.key"))
+		g.Expect(files[3].Permissions).To(Equal("0600"))
+		g.Expect(files[3].Content).To(Equal("key"))
+		g.Expect(commands).To(ConsistOf("cp /etc/kubernetes/tunnel/tunnel-agent.yaml /etc/kubernetes/manifests/tunnel-agent.yaml"))
+	})
+
+	t.Run("fails if the tunnel secret does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &KubeadmConfigReconciler{Client: fake.NewClientBuilder().Build()}
+		_, _, err := r.tunnelAgentFiles(ctx, newCluster(&clusterv1.ClusterTunnel{
+			ProxyURL: "http://tunnel:8090",
+			Agent:    &clusterv1.ClusterTunnelAgent{ServerAddress: "tunnel.example.com:8091"},
+		}))
+		g.Expect(err).To(HaveOccurred())
+	})
+}
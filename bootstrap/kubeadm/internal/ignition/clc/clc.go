@@ -37,10 +37,12 @@ package clc
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strings"
 	"text/template"
+	"unicode"
 
 	clct "github.com/flatcar/container-linux-config-transpiler/config"
 	ignition "github.com/flatcar/ignition/config/v2_3"
@@ -126,6 +128,24 @@ systemd:
         [Install]
         WantedBy=multi-user.target
     {{- end }}
+    {{- range .SystemdUnits }}
+    - name: {{ .Name }}
+      {{- with .Enabled }}
+      enabled: {{ . }}
+      {{- end }}
+      {{- if .Contents }}
+      contents: |
+        {{ .Contents | Indent 8 }}
+      {{- end }}
+      {{- if .DropIns }}
+      dropins:
+        {{- range .DropIns }}
+        - name: {{ .Name }}
+          contents: |
+            {{ .Contents | Indent 12 }}
+        {{- end }}
+      {{- end }}
+    {{- end }}
 storage:
   {{- if .DiskSetup }}{{- if .DiskSetup.Partitions }}
   disks:
@@ -145,9 +165,11 @@ storage:
     {{- range .DiskSetup.Filesystems }}
     - name: {{ .Label }}
       mount:
-        device: {{ .Device }}
+        device: {{ . | FilesystemDevice }}
         format: {{ .Filesystem }}
-        wipe_filesystem: {{ .Overwrite }}
+        {{- with .Overwrite }}
+        wipe_filesystem: {{ . }}
+        {{- end }}
         label: {{ .Label }}
         {{- if .ExtraOpts }}
         options:
@@ -199,12 +221,18 @@ storage:
       mode: {{ .Permissions }}
       {{ end -}}
       contents:
+        {{ if or (eq .Encoding "gzip") (eq .Encoding "gzip+base64") -}}
+        remote:
+          url: "{{ . | GzipDataURL }}"
+          compression: gzip
+        {{- else -}}
         {{ if eq .Encoding "base64" -}}
         inline: !!binary |
         {{- else -}}
         inline: |
         {{- end }}
           {{ .Content | Indent 10 }}
+        {{- end }}
     {{- end }}
     - path: /etc/kubeadm.sh
       mode: 0700
@@ -212,6 +240,9 @@ storage:
         inline: |
           #!/bin/bash
           set -e
+          {{- range .InactiveUsers }}
+          usermod --expiredate 1 {{ . }}
+          {{- end }}
           {{ range .PreKubeadmCommands }}
           {{ . | Indent 10 }}
           {{- end }}
@@ -260,19 +291,53 @@ type render struct {
 
 	KubeadmConfig            string
 	UsersWithPasswordAuth    string
+	InactiveUsers            []string
 	FilesystemDevicesByLabel map[string]string
+	SystemdUnits             []bootstrapv1.SystemdUnit
 }
 
 func defaultTemplateFuncMap() template.FuncMap {
 	return template.FuncMap{
-		"Indent":         templateYAMLIndent,
-		"Split":          strings.Split,
-		"Join":           strings.Join,
-		"MountpointName": mountpointName,
-		"ParseOwner":     parseOwner,
+		"Indent":           templateYAMLIndent,
+		"Split":            strings.Split,
+		"Join":             strings.Join,
+		"MountpointName":   mountpointName,
+		"ParseOwner":       parseOwner,
+		"FilesystemDevice": filesystemDevice,
+		"GzipDataURL":      gzipDataURL,
 	}
 }
 
+// filesystemDevice returns the device on which the filesystem should be created, resolving
+// the partition number to the device name following the udev naming conventions.
+func filesystemDevice(fs bootstrapv1.Filesystem) string {
+	if fs.Partition == nil || *fs.Partition == "none" {
+		return fs.Device
+	}
+
+	switch {
+	case strings.HasPrefix(fs.Device, "/dev/disk/"):
+		// Persistent symlinks, e.g. /dev/disk/by-id/foo-part1.
+		return fmt.Sprintf("%s-part%s", fs.Device, *fs.Partition)
+	case fs.Device != "" && unicode.IsDigit(rune(fs.Device[len(fs.Device)-1])):
+		// Devices ending with a digit, e.g. /dev/nvme0n1p1.
+		return fmt.Sprintf("%sp%s", fs.Device, *fs.Partition)
+	default:
+		return fs.Device + *fs.Partition
+	}
+}
+
+// gzipDataURL returns a data URL for gzip compressed file content, which Ignition decompresses
+// on the node.
+func gzipDataURL(file bootstrapv1.File) string {
+	content := file.Content
+	if file.Encoding == bootstrapv1.Gzip {
+		content = base64.StdEncoding.EncodeToString([]byte(content))
+	}
+
+	return "data:;base64," + strings.Join(strings.Fields(content), "")
+}
+
 func mountpointName(name string) string {
 	return strings.TrimPrefix(strings.ReplaceAll(name, "/", "-"), "-")
 }
@@ -317,7 +382,7 @@ func parseOwner(ownerRaw string) owner {
 	}
 }
 
-func renderCLC(input *cloudinit.BaseUserData, kubeadmConfig string) ([]byte, error) {
+func renderCLC(input *cloudinit.BaseUserData, ignitionConfig *bootstrapv1.IgnitionSpec, kubeadmConfig string) ([]byte, error) {
 	t := template.Must(template.New("template").Funcs(defaultTemplateFuncMap()).Parse(clcTemplate))
 
 	usersWithPasswordAuth := []string{}
@@ -327,10 +392,17 @@ func renderCLC(input *cloudinit.BaseUserData, kubeadmConfig string) ([]byte, err
 		}
 	}
 
+	inactiveUsers := []string{}
+	for _, user := range input.Users {
+		if user.Inactive != nil && *user.Inactive {
+			inactiveUsers = append(inactiveUsers, user.Name)
+		}
+	}
+
 	filesystemDevicesByLabel := map[string]string{}
 	if input.DiskSetup != nil {
 		for _, filesystem := range input.DiskSetup.Filesystems {
-			filesystemDevicesByLabel[filesystem.Label] = filesystem.Device
+			filesystemDevicesByLabel[filesystem.Label] = filesystemDevice(filesystem)
 		}
 	}
 
@@ -338,8 +410,12 @@ func renderCLC(input *cloudinit.BaseUserData, kubeadmConfig string) ([]byte, err
 		BaseUserData:             input,
 		KubeadmConfig:            kubeadmConfig,
 		UsersWithPasswordAuth:    strings.Join(usersWithPasswordAuth, ","),
+		InactiveUsers:            inactiveUsers,
 		FilesystemDevicesByLabel: filesystemDevicesByLabel,
 	}
+	if ignitionConfig != nil {
+		data.SystemdUnits = ignitionConfig.SystemdUnits
+	}
 
 	var out bytes.Buffer
 	if err := t.Execute(&out, data); err != nil {
@@ -349,17 +425,23 @@ func renderCLC(input *cloudinit.BaseUserData, kubeadmConfig string) ([]byte, err
 	return out.Bytes(), nil
 }
 
-// Render renders the provided user data and CLC snippets into Ignition config.
-func Render(input *cloudinit.BaseUserData, clc *bootstrapv1.ContainerLinuxConfig, kubeadmConfig string) ([]byte, string, error) {
+// Render renders the provided user data and Ignition specific configuration, including CLC snippets,
+// into Ignition config.
+func Render(input *cloudinit.BaseUserData, ignitionConfig *bootstrapv1.IgnitionSpec, kubeadmConfig string) ([]byte, string, error) {
 	if input == nil {
 		return nil, "", errors.New("empty base user data")
 	}
 
-	clcBytes, err := renderCLC(input, kubeadmConfig)
+	clcBytes, err := renderCLC(input, ignitionConfig, kubeadmConfig)
 	if err != nil {
 		return nil, "", errors.Wrapf(err, "rendering CLC configuration")
 	}
 
+	var clc *bootstrapv1.ContainerLinuxConfig
+	if ignitionConfig != nil {
+		clc = ignitionConfig.ContainerLinuxConfig
+	}
+
 	userData, warnings, err := buildIgnitionConfig(clcBytes, clc)
 	if err != nil {
 		return nil, "", errors.Wrapf(err, "building Ignition config")
@@ -68,6 +68,7 @@ func TestRender(t *testing.T) {
 	tc := []struct {
 		desc         string
 		input        *cloudinit.BaseUserData
+		ignition     *bootstrapv1.IgnitionSpec
 		wantIgnition types.Config
 	}{
 		{
@@ -558,6 +559,144 @@ func TestRender(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "inactive users, gzip encoded content, partitions and systemd units",
+			input: &cloudinit.BaseUserData{
+				KubeadmCommand: "kubeadm join",
+				Users: []bootstrapv1.User{
+					{
+						Name:     "foo",
+						Inactive: pointer.Bool(true),
+					},
+				},
+				DiskSetup: &bootstrapv1.DiskSetup{
+					Filesystems: []bootstrapv1.Filesystem{
+						{
+							Device:     "/dev/nvme1n1",
+							Filesystem: "ext4",
+							Label:      "test_disk",
+							Partition:  pointer.String("1"),
+						},
+					},
+				},
+				Mounts: []bootstrapv1.MountPoints{
+					{
+						"test_disk", "/var/lib/testdir",
+					},
+				},
+				WriteFiles: []bootstrapv1.File{
+					{
+						Path:        "/etc/gzipbase64encodedcontent.yaml",
+						Encoding:    bootstrapv1.GzipBase64,
+						Content:     "H4sIAAAAAAAA/0rLz+cCAAAA//8DAKhlMn4EAAAA",
+						Permissions: "0600",
+					},
+				},
+			},
+			ignition: &bootstrapv1.IgnitionSpec{
+				SystemdUnits: []bootstrapv1.SystemdUnit{
+					{
+						Name:     "foo.service",
+						Enabled:  pointer.Bool(false),
+						Contents: "[Service]\nExecStart=/bin/true\n",
+						DropIns: []bootstrapv1.SystemdUnitDropIn{
+							{
+								Name:     "10-foo.conf",
+								Contents: "[Service]\nUser=foo\n",
+							},
+						},
+					},
+				},
+			},
+			wantIgnition: types.Config{
+				Ignition: types.Ignition{
+					Version: "2.3.0",
+				},
+				Passwd: types.Passwd{
+					Users: []types.PasswdUser{
+						{
+							Name: "foo",
+						},
+					},
+				},
+				Storage: types.Storage{
+					Files: []types.File{
+						{
+							Node: types.Node{
+								Filesystem: "root",
+								Path:       "/etc/gzipbase64encodedcontent.yaml",
+							},
+							FileEmbedded1: types.FileEmbedded1{
+								Contents: types.FileContents{
+									Compression: "gzip",
+									Source:      "data:;base64,H4sIAAAAAAAA/0rLz+cCAAAA//8DAKhlMn4EAAAA",
+								},
+								Mode: pointer.Int(384),
+							},
+						},
+						{
+							Node: types.Node{
+								Filesystem: "root",
+								Path:       "/etc/kubeadm.sh",
+							},
+							FileEmbedded1: types.FileEmbedded1{
+								Contents: types.FileContents{
+									Source: "data:,%23!%2Fbin%2Fbash%0Aset%20-e%0Ausermod%20--expiredate%201%20foo%0A%0A%0Akubeadm%20join%0Amkdir%20-p%20%2Frun%2Fcluster-api%20%26%26%20echo%20success%20%3E%20%2Frun%2Fcluster-api%2Fbootstrap-success.complete%0Amv%20%2Fetc%2Fkubeadm.yml%20%2Ftmp%2F%0A",
+								},
+								Mode: pointer.Int(448),
+							},
+						},
+						{
+							Node: types.Node{
+								Filesystem: "root",
+								Path:       "/etc/kubeadm.yml",
+							},
+							FileEmbedded1: types.FileEmbedded1{
+								Contents: types.FileContents{
+									Source: "data:,---%0Afoo%0A",
+								},
+								Mode: pointer.Int(384),
+							},
+						},
+					},
+					Filesystems: []types.Filesystem{
+						{
+							Mount: &types.Mount{
+								Device: "/dev/nvme1n1p1",
+								Format: "ext4",
+								Label:  pointer.String("test_disk"),
+							},
+							Name: "test_disk",
+						},
+					},
+				},
+				Systemd: types.Systemd{
+					Units: []types.Unit{
+						{
+							Contents: "[Unit]\nDescription=kubeadm\n# Run only once. After successful run, this file is moved to /tmp/.\nConditionPathExists=/etc/kubeadm.yml\nAfter=network.target\n[Service]\n# To not restart the unit when it exits, as it is expected.\nType=oneshot\nExecStart=/etc/kubeadm.sh\n[Install]\nWantedBy=multi-user.target\n",
+							Enabled:  pointer.Bool(true),
+							Name:     "kubeadm.service",
+						},
+						{
+							Contents: "[Unit]\nDescription = Mount test_disk\n\n[Mount]\nWhat=/dev/nvme1n1p1\nWhere=/var/lib/testdir\nOptions=\n\n[Install]\nWantedBy=multi-user.target\n",
+							Enabled:  pointer.Bool(true),
+							Name:     "var-lib-testdir.mount",
+						},
+						{
+							Contents: "[Service]\nExecStart=/bin/true\n",
+							Dropins: []types.SystemdDropin{
+								{
+									Contents: "[Service]\nUser=foo\n",
+									Name:     "10-foo.conf",
+								},
+							},
+							Enabled: pointer.Bool(false),
+							Name:    "foo.service",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tc {
@@ -566,7 +705,7 @@ func TestRender(t *testing.T) {
 		t.Run(tt.desc, func(t *testing.T) {
 			t.Parallel()
 
-			ignitionBytes, _, err := clc.Render(tt.input, &bootstrapv1.ContainerLinuxConfig{}, "foo")
+			ignitionBytes, _, err := clc.Render(tt.input, tt.ignition, "foo")
 			if err != nil {
 				t.Fatalf("rendering: %v", err)
 			}
@@ -589,7 +728,7 @@ func TestRender(t *testing.T) {
 	t.Run("validates input parameter", func(t *testing.T) {
 		t.Parallel()
 
-		if _, _, err := clc.Render(nil, &bootstrapv1.ContainerLinuxConfig{}, "foo"); err == nil {
+		if _, _, err := clc.Render(nil, &bootstrapv1.IgnitionSpec{}, "foo"); err == nil {
 			t.Fatal("expected error when passing empty input data")
 		}
 	})
@@ -608,7 +747,7 @@ func TestRender(t *testing.T) {
 			AdditionalConfig: configWithWarning,
 		}
 
-		if _, _, err := clc.Render(&cloudinit.BaseUserData{}, config, "foo"); err == nil {
+		if _, _, err := clc.Render(&cloudinit.BaseUserData{}, &bootstrapv1.IgnitionSpec{ContainerLinuxConfig: config}, "foo"); err == nil {
 			t.Fatalf("expected error")
 		}
 	})
@@ -618,7 +757,7 @@ func TestRender(t *testing.T) {
 			AdditionalConfig: configWithWarning,
 		}
 
-		data, warnings, err := clc.Render(&cloudinit.BaseUserData{}, config, "foo")
+		data, warnings, err := clc.Render(&cloudinit.BaseUserData{}, &bootstrapv1.IgnitionSpec{ContainerLinuxConfig: config}, "foo")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -637,7 +776,7 @@ func TestRender(t *testing.T) {
 			AdditionalConfig: configWithIgnitionWarning,
 		}
 
-		data, warnings, err := clc.Render(&cloudinit.BaseUserData{}, config, "foo")
+		data, warnings, err := clc.Render(&cloudinit.BaseUserData{}, &bootstrapv1.IgnitionSpec{ContainerLinuxConfig: config}, "foo")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -19,11 +19,16 @@ limitations under the License.
 package ignition
 
 import (
+	"encoding/json"
 	"fmt"
 
+	ignition "github.com/flatcar/ignition/config/v2_3"
+	"github.com/pkg/errors"
+
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/ignition/clc"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/ignition/translate"
 )
 
 const (
@@ -106,10 +111,29 @@ func NewInitControlPlane(input *ControlPlaneInput) ([]byte, string, error) {
 }
 
 func render(input *cloudinit.BaseUserData, ignitionConfig *bootstrapv1.IgnitionSpec, kubeadmConfig string) ([]byte, string, error) {
-	clcConfig := &bootstrapv1.ContainerLinuxConfig{}
-	if ignitionConfig != nil && ignitionConfig.ContainerLinuxConfig != nil {
-		clcConfig = ignitionConfig.ContainerLinuxConfig
+	userData, warnings, err := clc.Render(input, ignitionConfig, kubeadmConfig)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if ignitionConfig == nil || ignitionConfig.Version != bootstrapv1.IgnitionVersion34 {
+		return userData, warnings, nil
+	}
+
+	cfg, report, err := ignition.Parse(userData)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "parsing generated Ignition config: %s", report.String())
+	}
+
+	translated, err := translate.FromV23(cfg)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "translating Ignition config to version %s", translate.Version)
+	}
+
+	userData, err = json.Marshal(translated)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "marshaling translated Ignition config into JSON")
 	}
 
-	return clc.Render(input, clcConfig, kubeadmConfig)
+	return userData, warnings, nil
 }
@@ -146,6 +146,63 @@ func Test_NewNode(t *testing.T) {
 			t.Fatalf("Data should be returned with warnings but no errors")
 		}
 	})
+
+	t.Run("returns Ignition 3.4 when requested", func(t *testing.T) {
+		t.Parallel()
+
+		input := &ignition.NodeInput{
+			NodeInput: &cloudinit.NodeInput{},
+			Ignition: &bootstrapv1.IgnitionSpec{
+				Version: bootstrapv1.IgnitionVersion34,
+				SystemdUnits: []bootstrapv1.SystemdUnit{
+					{
+						Name:     "foo.service",
+						Contents: testString,
+					},
+				},
+			},
+		}
+
+		ignitionData, _, err := ignition.NewNode(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		decodedValue := struct {
+			Ignition struct {
+				Version string `json:"version"`
+			} `json:"ignition"`
+			Storage struct {
+				Files []struct {
+					Path       string `json:"path"`
+					Filesystem string `json:"filesystem"`
+				} `json:"files"`
+			} `json:"storage"`
+			Systemd struct {
+				Units []struct {
+					Name string `json:"name"`
+				} `json:"units"`
+			} `json:"systemd"`
+		}{}
+
+		if err := json.Unmarshal(ignitionData, &decodedValue); err != nil {
+			t.Fatalf("Decoding received Ignition data as JSON: %v", err)
+		}
+
+		if decodedValue.Ignition.Version != "3.4.0" {
+			t.Fatalf("Expected Ignition version %q, got %q", "3.4.0", decodedValue.Ignition.Version)
+		}
+
+		for _, file := range decodedValue.Storage.Files {
+			if file.Filesystem != "" {
+				t.Fatalf("Unexpected filesystem %q for file %q in Ignition 3.4 config", file.Filesystem, file.Path)
+			}
+		}
+
+		if len(decodedValue.Systemd.Units) != 2 || decodedValue.Systemd.Units[1].Name != "foo.service" {
+			t.Fatalf("Expected user-specified systemd unit to be included in %q", string(ignitionData))
+		}
+	})
 }
 
 func Test_NewJoinControlPlane(t *testing.T) {
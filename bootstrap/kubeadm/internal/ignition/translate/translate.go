@@ -0,0 +1,401 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package translate translates Ignition 2.3 configuration into Ignition 3.4 configuration.
+//
+// The bootstrap provider always generates Ignition 2.3 configuration using the Container Linux Config
+// Transpiler, so distributions requiring Ignition 3.x (e.g. Fedora CoreOS or Flatcar >= 3185.0.0)
+// get the same configuration translated following the rules described in
+// https://coreos.github.io/ignition/migrating-configs/#from-version-230-to-300.
+package translate
+
+import (
+	"encoding/base64"
+	"path"
+
+	ignitionTypes "github.com/flatcar/ignition/config/v2_3/types"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// Version is the Ignition specification version of the translated configuration.
+	Version = "3.4.0"
+
+	rootFilesystem    = "root"
+	networkdDirectory = "/etc/systemd/network"
+	sectorsPerMiB     = 2048
+)
+
+// FromV23 translates the given Ignition 2.3 configuration into Ignition 3.4 configuration.
+func FromV23(in ignitionTypes.Config) (*Config, error) {
+	out := &Config{
+		Ignition: translateIgnition(in.Ignition),
+		Passwd:   translatePasswd(in.Passwd),
+		Systemd:  translateSystemd(in.Systemd),
+	}
+
+	storage, err := translateStorage(in.Storage)
+	if err != nil {
+		return nil, err
+	}
+	out.Storage = *storage
+
+	out.Storage.Files = append(out.Storage.Files, translateNetworkd(in.Networkd)...)
+
+	return out, nil
+}
+
+func translateIgnition(in ignitionTypes.Ignition) Ignition {
+	out := Ignition{
+		Version: Version,
+		Timeouts: Timeouts{
+			HTTPResponseHeaders: in.Timeouts.HTTPResponseHeaders,
+			HTTPTotal:           in.Timeouts.HTTPTotal,
+		},
+	}
+
+	for _, ref := range in.Config.Append {
+		out.Config.Merge = append(out.Config.Merge, translateResource(ref.Source, ref.Verification))
+	}
+
+	if in.Config.Replace != nil {
+		out.Config.Replace = translateResource(in.Config.Replace.Source, in.Config.Replace.Verification)
+	}
+
+	for _, ca := range in.Security.TLS.CertificateAuthorities {
+		out.Security.TLS.CertificateAuthorities = append(out.Security.TLS.CertificateAuthorities, translateResource(ca.Source, ca.Verification))
+	}
+
+	return out
+}
+
+func translateResource(source string, verification ignitionTypes.Verification) Resource {
+	return Resource{
+		Source: pointer.String(source),
+		Verification: Verification{
+			Hash: verification.Hash,
+		},
+	}
+}
+
+func translatePasswd(in ignitionTypes.Passwd) Passwd {
+	out := Passwd{}
+
+	for _, group := range in.Groups {
+		out.Groups = append(out.Groups, PasswdGroup{
+			Gid:          group.Gid,
+			Name:         group.Name,
+			PasswordHash: stringOrNil(group.PasswordHash),
+			System:       boolOrNil(group.System),
+		})
+	}
+
+	for _, user := range in.Users {
+		u := PasswdUser{
+			Gecos:        stringOrNil(user.Gecos),
+			HomeDir:      stringOrNil(user.HomeDir),
+			Name:         user.Name,
+			NoCreateHome: boolOrNil(user.NoCreateHome),
+			NoLogInit:    boolOrNil(user.NoLogInit),
+			NoUserGroup:  boolOrNil(user.NoUserGroup),
+			PasswordHash: user.PasswordHash,
+			PrimaryGroup: stringOrNil(user.PrimaryGroup),
+			Shell:        stringOrNil(user.Shell),
+			System:       boolOrNil(user.System),
+			UID:          user.UID,
+		}
+
+		for _, group := range user.Groups {
+			u.Groups = append(u.Groups, string(group))
+		}
+
+		for _, key := range user.SSHAuthorizedKeys {
+			u.SSHAuthorizedKeys = append(u.SSHAuthorizedKeys, string(key))
+		}
+
+		out.Users = append(out.Users, u)
+	}
+
+	return out
+}
+
+func translateStorage(in ignitionTypes.Storage) (*Storage, error) {
+	out := &Storage{}
+
+	for _, disk := range in.Disks {
+		d := Disk{
+			Device:    disk.Device,
+			WipeTable: boolOrNil(disk.WipeTable),
+		}
+
+		for _, partition := range disk.Partitions {
+			p, err := translatePartition(disk.Device, partition)
+			if err != nil {
+				return nil, err
+			}
+
+			d.Partitions = append(d.Partitions, p)
+		}
+
+		out.Disks = append(out.Disks, d)
+	}
+
+	for _, raid := range in.Raid {
+		r := Raid{
+			Level:  stringOrNil(raid.Level),
+			Name:   raid.Name,
+			Spares: intOrNil(raid.Spares),
+		}
+
+		for _, device := range raid.Devices {
+			r.Devices = append(r.Devices, string(device))
+		}
+
+		for _, option := range raid.Options {
+			r.Options = append(r.Options, string(option))
+		}
+
+		out.Raid = append(out.Raid, r)
+	}
+
+	// Filesystems in Ignition 2.3 are referenced by name from files, directories and links, while
+	// Ignition 3.x uses absolute paths only, so keep track of where each named filesystem is mounted.
+	filesystemPaths := map[string]string{rootFilesystem: "/"}
+
+	for _, fs := range in.Filesystems {
+		if fs.Path != nil {
+			filesystemPaths[fs.Name] = *fs.Path
+		}
+
+		if fs.Mount == nil {
+			// Filesystems which are only referenced by path do not exist in Ignition 3.x.
+			continue
+		}
+
+		f := Filesystem{
+			Device:         fs.Mount.Device,
+			Format:         stringOrNil(fs.Mount.Format),
+			Label:          fs.Mount.Label,
+			Path:           fs.Path,
+			UUID:           fs.Mount.UUID,
+			WipeFilesystem: boolOrNil(fs.Mount.WipeFilesystem),
+		}
+
+		for _, option := range fs.Mount.Options {
+			f.Options = append(f.Options, string(option))
+		}
+
+		out.Filesystems = append(out.Filesystems, f)
+	}
+
+	for _, file := range in.Files {
+		node, err := translateNode(file.Node, filesystemPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		f := File{
+			Node: *node,
+			Mode: file.Mode,
+		}
+
+		contents := Resource{
+			Compression: stringOrNil(file.Contents.Compression),
+			Source:      pointer.String(file.Contents.Source),
+			Verification: Verification{
+				Hash: file.Contents.Verification.Hash,
+			},
+		}
+
+		if file.Append {
+			f.Append = []Resource{contents}
+		} else {
+			f.Contents = contents
+		}
+
+		out.Files = append(out.Files, f)
+	}
+
+	for _, dir := range in.Directories {
+		node, err := translateNode(dir.Node, filesystemPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Directories = append(out.Directories, Directory{
+			Node: *node,
+			Mode: dir.Mode,
+		})
+	}
+
+	for _, link := range in.Links {
+		node, err := translateNode(link.Node, filesystemPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Links = append(out.Links, Link{
+			Node:   *node,
+			Hard:   boolOrNil(link.Hard),
+			Target: pointer.String(link.Target),
+		})
+	}
+
+	return out, nil
+}
+
+func translatePartition(device string, in ignitionTypes.Partition) (Partition, error) {
+	out := Partition{
+		GUID:               stringOrNil(in.GUID),
+		Label:              in.Label,
+		Number:             in.Number,
+		ShouldExist:        in.ShouldExist,
+		SizeMiB:            in.SizeMiB,
+		StartMiB:           in.StartMiB,
+		TypeGUID:           stringOrNil(in.TypeGUID),
+		WipePartitionEntry: boolOrNil(in.WipePartitionEntry),
+	}
+
+	// Ignition 3.x only supports partition sizes and offsets expressed in MiB.
+	if in.Size != nil && in.SizeMiB == nil {
+		if *in.Size%sectorsPerMiB != 0 {
+			return Partition{}, errors.Errorf("partition %d on disk %q: size of %d sectors is not a multiple of 1 MiB", in.Number, device, *in.Size)
+		}
+		out.SizeMiB = pointer.Int(*in.Size / sectorsPerMiB)
+	}
+
+	if in.Start != nil && in.StartMiB == nil {
+		if *in.Start%sectorsPerMiB != 0 {
+			return Partition{}, errors.Errorf("partition %d on disk %q: start of %d sectors is not a multiple of 1 MiB", in.Number, device, *in.Start)
+		}
+		out.StartMiB = pointer.Int(*in.Start / sectorsPerMiB)
+	}
+
+	return out, nil
+}
+
+func translateNode(in ignitionTypes.Node, filesystemPaths map[string]string) (*Node, error) {
+	filesystem := in.Filesystem
+	if filesystem == "" {
+		filesystem = rootFilesystem
+	}
+
+	mountPath, ok := filesystemPaths[filesystem]
+	if !ok {
+		return nil, errors.Errorf("path %q references filesystem %q which has no path defined", in.Path, filesystem)
+	}
+
+	out := &Node{
+		Overwrite: in.Overwrite,
+		Path:      path.Join(mountPath, in.Path),
+	}
+
+	if in.User != nil {
+		out.User = NodeUser{
+			ID:   in.User.ID,
+			Name: stringOrNil(in.User.Name),
+		}
+	}
+
+	if in.Group != nil {
+		out.Group = NodeGroup{
+			ID:   in.Group.ID,
+			Name: stringOrNil(in.Group.Name),
+		}
+	}
+
+	return out, nil
+}
+
+func translateSystemd(in ignitionTypes.Systemd) Systemd {
+	out := Systemd{}
+
+	for _, unit := range in.Units {
+		u := Unit{
+			Contents: stringOrNil(unit.Contents),
+			Enabled:  unit.Enabled,
+			Mask:     boolOrNil(unit.Mask),
+			Name:     unit.Name,
+		}
+
+		// Enable is deprecated in Ignition 2.x and removed in Ignition 3.x.
+		if unit.Enable && u.Enabled == nil {
+			u.Enabled = pointer.Bool(true)
+		}
+
+		for _, dropin := range unit.Dropins {
+			u.Dropins = append(u.Dropins, Dropin{
+				Contents: stringOrNil(dropin.Contents),
+				Name:     dropin.Name,
+			})
+		}
+
+		out.Units = append(out.Units, u)
+	}
+
+	return out
+}
+
+// translateNetworkd converts networkd units, which are not supported by Ignition 3.x, into plain files.
+func translateNetworkd(in ignitionTypes.Networkd) []File {
+	files := []File{}
+
+	for _, unit := range in.Units {
+		if unit.Contents != "" {
+			files = append(files, networkdFile(path.Join(networkdDirectory, unit.Name), unit.Contents))
+		}
+
+		for _, dropin := range unit.Dropins {
+			files = append(files, networkdFile(path.Join(networkdDirectory, unit.Name+".d", dropin.Name), dropin.Contents))
+		}
+	}
+
+	return files
+}
+
+func networkdFile(filePath, contents string) File {
+	return File{
+		Node: Node{
+			Path: filePath,
+		},
+		Contents: Resource{
+			Source: pointer.String("data:;base64," + base64.StdEncoding.EncodeToString([]byte(contents))),
+		},
+		Mode: pointer.Int(0o644),
+	}
+}
+
+func stringOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return pointer.String(s)
+}
+
+func boolOrNil(b bool) *bool {
+	if !b {
+		return nil
+	}
+	return pointer.Bool(b)
+}
+
+func intOrNil(i int) *int {
+	if i == 0 {
+		return nil
+	}
+	return pointer.Int(i)
+}
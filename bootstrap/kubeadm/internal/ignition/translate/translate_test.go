@@ -0,0 +1,236 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translate
+
+import (
+	"testing"
+
+	ignitionTypes "github.com/flatcar/ignition/config/v2_3/types"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/pointer"
+)
+
+func TestFromV23(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		desc    string
+		input   ignitionTypes.Config
+		want    *Config
+		wantErr bool
+	}{
+		{
+			desc:  "sets the version",
+			input: ignitionTypes.Config{Ignition: ignitionTypes.Ignition{Version: "2.3.0"}},
+			want: &Config{
+				Ignition: Ignition{Version: Version},
+			},
+		},
+		{
+			desc: "translates users, files, filesystems and units",
+			input: ignitionTypes.Config{
+				Ignition: ignitionTypes.Ignition{
+					Version: "2.3.0",
+					Config: ignitionTypes.IgnitionConfig{
+						Append: []ignitionTypes.ConfigReference{{Source: "https://example.com/config.ign"}},
+					},
+				},
+				Passwd: ignitionTypes.Passwd{
+					Users: []ignitionTypes.PasswdUser{
+						{
+							Name:              "foo",
+							Groups:            []ignitionTypes.Group{"bar"},
+							HomeDir:           "/home/foo",
+							SSHAuthorizedKeys: []ignitionTypes.SSHAuthorizedKey{"ssh-rsa foo"},
+						},
+					},
+				},
+				Storage: ignitionTypes.Storage{
+					Disks: []ignitionTypes.Disk{
+						{
+							Device:     "/dev/sdb",
+							WipeTable:  true,
+							Partitions: []ignitionTypes.Partition{{Number: 1, Size: pointer.Int(4096)}},
+						},
+					},
+					Filesystems: []ignitionTypes.Filesystem{
+						{
+							Name: "data",
+							Mount: &ignitionTypes.Mount{
+								Device: "/dev/sdb1",
+								Format: "ext4",
+								Label:  pointer.String("data"),
+							},
+							Path: pointer.String("/var/lib/data"),
+						},
+					},
+					Files: []ignitionTypes.File{
+						{
+							Node: ignitionTypes.Node{Filesystem: "root", Path: "/etc/foo"},
+							FileEmbedded1: ignitionTypes.FileEmbedded1{
+								Contents: ignitionTypes.FileContents{Source: "data:,foo", Compression: "gzip"},
+								Mode:     pointer.Int(0o600),
+							},
+						},
+						{
+							Node: ignitionTypes.Node{
+								Filesystem: "data",
+								Path:       "/bar",
+								User:       &ignitionTypes.NodeUser{Name: "foo"},
+							},
+							FileEmbedded1: ignitionTypes.FileEmbedded1{
+								Append:   true,
+								Contents: ignitionTypes.FileContents{Source: "data:,bar"},
+							},
+						},
+					},
+				},
+				Systemd: ignitionTypes.Systemd{
+					Units: []ignitionTypes.Unit{
+						{
+							Name:     "foo.service",
+							Enable:   true,
+							Contents: "[Service]",
+							Dropins:  []ignitionTypes.SystemdDropin{{Name: "10-foo.conf", Contents: "[Service]"}},
+						},
+					},
+				},
+				Networkd: ignitionTypes.Networkd{
+					Units: []ignitionTypes.Networkdunit{{Name: "00-eth0.network", Contents: "foo"}},
+				},
+			},
+			want: &Config{
+				Ignition: Ignition{
+					Version: Version,
+					Config: IgnitionConfig{
+						Merge: []Resource{{Source: pointer.String("https://example.com/config.ign")}},
+					},
+				},
+				Passwd: Passwd{
+					Users: []PasswdUser{
+						{
+							Name:              "foo",
+							Groups:            []string{"bar"},
+							HomeDir:           pointer.String("/home/foo"),
+							SSHAuthorizedKeys: []string{"ssh-rsa foo"},
+						},
+					},
+				},
+				Storage: Storage{
+					Disks: []Disk{
+						{
+							Device:     "/dev/sdb",
+							WipeTable:  pointer.Bool(true),
+							Partitions: []Partition{{Number: 1, SizeMiB: pointer.Int(2)}},
+						},
+					},
+					Filesystems: []Filesystem{
+						{
+							Device: "/dev/sdb1",
+							Format: pointer.String("ext4"),
+							Label:  pointer.String("data"),
+							Path:   pointer.String("/var/lib/data"),
+						},
+					},
+					Files: []File{
+						{
+							Node:     Node{Path: "/etc/foo"},
+							Contents: Resource{Source: pointer.String("data:,foo"), Compression: pointer.String("gzip")},
+							Mode:     pointer.Int(0o600),
+						},
+						{
+							Node:   Node{Path: "/var/lib/data/bar", User: NodeUser{Name: pointer.String("foo")}},
+							Append: []Resource{{Source: pointer.String("data:,bar")}},
+						},
+						{
+							Node:     Node{Path: "/etc/systemd/network/00-eth0.network"},
+							Contents: Resource{Source: pointer.String("data:;base64,Zm9v")},
+							Mode:     pointer.Int(0o644),
+						},
+					},
+				},
+				Systemd: Systemd{
+					Units: []Unit{
+						{
+							Name:     "foo.service",
+							Enabled:  pointer.Bool(true),
+							Contents: pointer.String("[Service]"),
+							Dropins:  []Dropin{{Name: "10-foo.conf", Contents: pointer.String("[Service]")}},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "fails on partition size not aligned to MiB",
+			input: ignitionTypes.Config{
+				Storage: ignitionTypes.Storage{
+					Disks: []ignitionTypes.Disk{
+						{
+							Device:     "/dev/sdb",
+							Partitions: []ignitionTypes.Partition{{Number: 1, Size: pointer.Int(1000)}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on files in filesystems without path",
+			input: ignitionTypes.Config{
+				Storage: ignitionTypes.Storage{
+					Filesystems: []ignitionTypes.Filesystem{
+						{
+							Name:  "data",
+							Mount: &ignitionTypes.Mount{Device: "/dev/sdb1", Format: "ext4"},
+						},
+					},
+					Files: []ignitionTypes.File{
+						{
+							Node: ignitionTypes.Node{Filesystem: "data", Path: "/bar"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tc {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := FromV23(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("Ignition mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
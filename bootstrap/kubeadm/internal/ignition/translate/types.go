@@ -0,0 +1,218 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translate
+
+// The types below are a subset of the Ignition 3.4 specification covering all the fields which can be
+// translated from an Ignition 2.3 configuration. More info: https://coreos.github.io/ignition/configuration-v3_4/
+
+// Config is the root of an Ignition 3.4 configuration.
+type Config struct {
+	Ignition Ignition `json:"ignition"`
+	Passwd   Passwd   `json:"passwd,omitempty"`
+	Storage  Storage  `json:"storage,omitempty"`
+	Systemd  Systemd  `json:"systemd,omitempty"`
+}
+
+// Ignition contains metadata about the configuration itself.
+type Ignition struct {
+	Config   IgnitionConfig `json:"config,omitempty"`
+	Security Security       `json:"security,omitempty"`
+	Timeouts Timeouts       `json:"timeouts,omitempty"`
+	Version  string         `json:"version"`
+}
+
+// IgnitionConfig contains options related to the configuration.
+type IgnitionConfig struct {
+	Merge   []Resource `json:"merge,omitempty"`
+	Replace Resource   `json:"replace,omitempty"`
+}
+
+// Security contains options related to security.
+type Security struct {
+	TLS TLS `json:"tls,omitempty"`
+}
+
+// TLS contains options related to TLS when fetching resources over https.
+type TLS struct {
+	CertificateAuthorities []Resource `json:"certificateAuthorities,omitempty"`
+}
+
+// Timeouts contains options related to how long Ignition should wait while fetching resources.
+type Timeouts struct {
+	HTTPResponseHeaders *int `json:"httpResponseHeaders,omitempty"`
+	HTTPTotal           *int `json:"httpTotal,omitempty"`
+}
+
+// Resource describes a remote or inline resource.
+type Resource struct {
+	Compression  *string      `json:"compression,omitempty"`
+	HTTPHeaders  []HTTPHeader `json:"httpHeaders,omitempty"`
+	Source       *string      `json:"source,omitempty"`
+	Verification Verification `json:"verification,omitempty"`
+}
+
+// HTTPHeader is an HTTP header added to the request when fetching a remote resource.
+type HTTPHeader struct {
+	Name  string  `json:"name"`
+	Value *string `json:"value,omitempty"`
+}
+
+// Verification contains options related to the verification of a resource.
+type Verification struct {
+	Hash *string `json:"hash,omitempty"`
+}
+
+// Passwd contains options related to users and groups.
+type Passwd struct {
+	Groups []PasswdGroup `json:"groups,omitempty"`
+	Users  []PasswdUser  `json:"users,omitempty"`
+}
+
+// PasswdGroup describes a group to be added to the system.
+type PasswdGroup struct {
+	Gid          *int    `json:"gid,omitempty"`
+	Name         string  `json:"name"`
+	PasswordHash *string `json:"passwordHash,omitempty"`
+	System       *bool   `json:"system,omitempty"`
+}
+
+// PasswdUser describes a user to be added to the system.
+type PasswdUser struct {
+	Gecos             *string  `json:"gecos,omitempty"`
+	Groups            []string `json:"groups,omitempty"`
+	HomeDir           *string  `json:"homeDir,omitempty"`
+	Name              string   `json:"name"`
+	NoCreateHome      *bool    `json:"noCreateHome,omitempty"`
+	NoLogInit         *bool    `json:"noLogInit,omitempty"`
+	NoUserGroup       *bool    `json:"noUserGroup,omitempty"`
+	PasswordHash      *string  `json:"passwordHash,omitempty"`
+	PrimaryGroup      *string  `json:"primaryGroup,omitempty"`
+	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+	Shell             *string  `json:"shell,omitempty"`
+	System            *bool    `json:"system,omitempty"`
+	UID               *int     `json:"uid,omitempty"`
+}
+
+// Storage describes the desired state of the system's storage devices.
+type Storage struct {
+	Directories []Directory  `json:"directories,omitempty"`
+	Disks       []Disk       `json:"disks,omitempty"`
+	Files       []File       `json:"files,omitempty"`
+	Filesystems []Filesystem `json:"filesystems,omitempty"`
+	Links       []Link       `json:"links,omitempty"`
+	Raid        []Raid       `json:"raid,omitempty"`
+}
+
+// Disk describes a disk to be partitioned.
+type Disk struct {
+	Device     string      `json:"device"`
+	Partitions []Partition `json:"partitions,omitempty"`
+	WipeTable  *bool       `json:"wipeTable,omitempty"`
+}
+
+// Partition describes a partition on a disk.
+type Partition struct {
+	GUID               *string `json:"guid,omitempty"`
+	Label              *string `json:"label,omitempty"`
+	Number             int     `json:"number,omitempty"`
+	ShouldExist        *bool   `json:"shouldExist,omitempty"`
+	SizeMiB            *int    `json:"sizeMiB,omitempty"`
+	StartMiB           *int    `json:"startMiB,omitempty"`
+	TypeGUID           *string `json:"typeGuid,omitempty"`
+	WipePartitionEntry *bool   `json:"wipePartitionEntry,omitempty"`
+}
+
+// Raid describes a software RAID device.
+type Raid struct {
+	Devices []string `json:"devices,omitempty"`
+	Level   *string  `json:"level,omitempty"`
+	Name    string   `json:"name"`
+	Options []string `json:"options,omitempty"`
+	Spares  *int     `json:"spares,omitempty"`
+}
+
+// Filesystem describes a filesystem to be created.
+type Filesystem struct {
+	Device         string   `json:"device"`
+	Format         *string  `json:"format,omitempty"`
+	Label          *string  `json:"label,omitempty"`
+	Options        []string `json:"options,omitempty"`
+	Path           *string  `json:"path,omitempty"`
+	UUID           *string  `json:"uuid,omitempty"`
+	WipeFilesystem *bool    `json:"wipeFilesystem,omitempty"`
+}
+
+// Node contains the fields shared by files, directories and links.
+type Node struct {
+	Group     NodeGroup `json:"group,omitempty"`
+	Overwrite *bool     `json:"overwrite,omitempty"`
+	Path      string    `json:"path"`
+	User      NodeUser  `json:"user,omitempty"`
+}
+
+// NodeUser describes the owner of a node.
+type NodeUser struct {
+	ID   *int    `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+}
+
+// NodeGroup describes the group of a node.
+type NodeGroup struct {
+	ID   *int    `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+}
+
+// File describes a file to be written.
+type File struct {
+	Node
+	Append   []Resource `json:"append,omitempty"`
+	Contents Resource   `json:"contents,omitempty"`
+	Mode     *int       `json:"mode,omitempty"`
+}
+
+// Directory describes a directory to be created.
+type Directory struct {
+	Node
+	Mode *int `json:"mode,omitempty"`
+}
+
+// Link describes a link to be created.
+type Link struct {
+	Node
+	Hard   *bool   `json:"hard,omitempty"`
+	Target *string `json:"target,omitempty"`
+}
+
+// Systemd describes the desired state of the systemd units.
+type Systemd struct {
+	Units []Unit `json:"units,omitempty"`
+}
+
+// Unit describes a systemd unit.
+type Unit struct {
+	Contents *string  `json:"contents,omitempty"`
+	Dropins  []Dropin `json:"dropins,omitempty"`
+	Enabled  *bool    `json:"enabled,omitempty"`
+	Mask     *bool    `json:"mask,omitempty"`
+	Name     string   `json:"name"`
+}
+
+// Dropin describes a systemd unit drop-in.
+type Dropin struct {
+	Contents *string `json:"contents,omitempty"`
+	Name     string  `json:"name"`
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package securechannel implements the delivery of the bootstrap data over an authenticated channel.
+//
+// When the infrastructure machine requests the SecureChannel bootstrap data delivery, the bootstrap data
+// secret contains a small payload, which is passed to the machine as user data instead of the bootstrap data;
+// at first boot the payload fetches the bootstrap data from the bootstrap data server using a one-time token.
+// This keeps the user data small and avoids exposing secrets, e.g. the bootstrap token or the cluster
+// certificates, through the instance metadata of the machine.
+package securechannel
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/ignition/translate"
+)
+
+const (
+	// DataKey is the key of the bootstrap data secret storing the bootstrap data served to the machine.
+	DataKey = "secure-channel-value"
+
+	// TokenHashKey is the key of the bootstrap data secret storing the hash of the one-time token;
+	// the key is removed when the token is used.
+	TokenHashKey = "secure-channel-token-hash"
+
+	// TokenExpirationKey is the key of the bootstrap data secret storing the expiration of the one-time token,
+	// in RFC3339 format.
+	TokenExpirationKey = "secure-channel-token-expiration"
+
+	// DefaultTokenTTL is the default amount of time a one-time token is valid.
+	DefaultTokenTTL = 2 * time.Hour
+
+	// pathPrefix is the prefix of the path the bootstrap data is served from, followed by <namespace>/<name>
+	// of the bootstrap data secret.
+	pathPrefix = "/bootstrap-data/"
+
+	tokenBytes = 32
+
+	scriptTemplate = `#!/bin/bash
+# Bootstrap script generated by the Cluster API kubeadm bootstrap provider.
+# The bootstrap data is fetched at first boot using a one-time token.
+set -euo pipefail
+umask 077
+mkdir -p /run/cluster-api
+{{- if .CACert }}
+printf '%s' '{{ .CACert }}' | base64 -d > /run/cluster-api/bootstrap-data-ca.crt
+{{- end }}
+printf 'Authorization: Bearer %s\n' '{{ .Token }}' | curl --fail --silent --show-error --location \
+  --retry 30 --retry-delay 10 --retry-connrefused \
+  {{- if .CACert }}
+  --cacert /run/cluster-api/bootstrap-data-ca.crt \
+  {{- end }}
+  --header @- --output /run/cluster-api/bootstrap-data '{{ .URL }}'
+exec /bin/bash /run/cluster-api/bootstrap-data
+`
+)
+
+// Options are the options for delivering the bootstrap data over the secure channel.
+type Options struct {
+	// URL is the base URL of the bootstrap data server as reachable from the machines.
+	URL string
+
+	// CACert is the PEM encoded CA certificate used by the machines to verify the certificate of the
+	// bootstrap data server. If empty, the machines use their system trust store.
+	CACert []byte
+
+	// TokenTTL is the amount of time a one-time token is valid.
+	TokenTTL time.Duration
+}
+
+// SecretData returns the content of the bootstrap data secret for delivering data over the secure channel.
+// The value key contains the payload which fetches data from the bootstrap data server, which is generated
+// according to the bootstrap data format.
+func SecretData(opts Options, config *bootstrapv1.KubeadmConfig, data []byte, now time.Time) (map[string][]byte, error) {
+	if opts.URL == "" {
+		return nil, errors.New("the bootstrap data server is not enabled")
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	dataURL, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid bootstrap data server URL %q", opts.URL)
+	}
+	dataURL.Path = path.Join(dataURL.Path, pathPrefix, config.Namespace, config.Name)
+
+	if err := Supported(config); err != nil {
+		return nil, err
+	}
+
+	var payload []byte
+	if config.Spec.Format == bootstrapv1.Ignition {
+		payload, err = ignitionPayload(dataURL.String(), opts.CACert, token)
+	} else {
+		payload, err = shellPayload(dataURL.String(), opts.CACert, token)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := opts.TokenTTL
+	if ttl == 0 {
+		ttl = DefaultTokenTTL
+	}
+
+	return map[string][]byte{
+		"value":            payload,
+		"format":           []byte(config.Spec.Format),
+		DataKey:            data,
+		TokenHashKey:       []byte(hashToken(token)),
+		TokenExpirationKey: []byte(now.Add(ttl).UTC().Format(time.RFC3339)),
+	}, nil
+}
+
+// Supported returns an error if the bootstrap data of the given config can't be delivered over the secure channel,
+// because no payload can be generated for its bootstrap data format.
+func Supported(config *bootstrapv1.KubeadmConfig) error {
+	switch config.Spec.Format {
+	case bootstrapv1.Shell:
+		return nil
+	case bootstrapv1.Ignition:
+		if config.Spec.Ignition == nil || config.Spec.Ignition.Version != bootstrapv1.IgnitionVersion34 {
+			return errors.Errorf("bootstrap data delivery over the secure channel requires Ignition version %s", bootstrapv1.IgnitionVersion34)
+		}
+		return nil
+	default:
+		format := config.Spec.Format
+		if format == "" {
+			format = bootstrapv1.CloudConfig
+		}
+		return errors.Errorf("bootstrap data delivery over the secure channel is not supported for format %q", format)
+	}
+}
+
+// KeepToken returns the given content of the bootstrap data secret, with the payload and the one-time token of the existing
+// content of the secret, so a machine which already read the payload can still fetch the bootstrap data.
+// If the token has already been used, it stays removed.
+func KeepToken(existing, data map[string][]byte) map[string][]byte {
+	if _, ok := existing[DataKey]; !ok {
+		return data
+	}
+
+	data["value"] = existing["value"]
+	data[TokenExpirationKey] = existing[TokenExpirationKey]
+	if tokenHash, ok := existing[TokenHashKey]; ok {
+		data[TokenHashKey] = tokenHash
+	} else {
+		delete(data, TokenHashKey)
+	}
+	return data
+}
+
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate one-time token")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func shellPayload(dataURL string, caCert []byte, token string) ([]byte, error) {
+	t, err := template.New("payload").Parse(scriptTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse secure channel payload template")
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, map[string]string{
+		"URL":    dataURL,
+		"CACert": base64.StdEncoding.EncodeToString(caCert),
+		"Token":  token,
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to generate secure channel payload")
+	}
+	return out.Bytes(), nil
+}
+
+func ignitionPayload(dataURL string, caCert []byte, token string) ([]byte, error) {
+	authorization := fmt.Sprintf("Bearer %s", token)
+	config := translate.Config{
+		Ignition: translate.Ignition{
+			Version: translate.Version,
+			Config: translate.IgnitionConfig{
+				Replace: translate.Resource{
+					Source:      &dataURL,
+					HTTPHeaders: []translate.HTTPHeader{{Name: "Authorization", Value: &authorization}},
+				},
+			},
+		},
+	}
+	if len(caCert) > 0 {
+		source := "data:;base64," + base64.StdEncoding.EncodeToString(caCert)
+		config.Ignition.Security.TLS.CertificateAuthorities = []translate.Resource{{Source: &source}}
+	}
+
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal secure channel payload")
+	}
+	return payload, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securechannel
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/ignition/translate"
+)
+
+func TestSecretData(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	opts := Options{
+		URL:      "https://bootstrap.example.com:9445",
+		CACert:   []byte("ca-cert"),
+		TokenTTL: time.Hour,
+	}
+	newConfig := func(format bootstrapv1.Format, ignition *bootstrapv1.IgnitionSpec) *bootstrapv1.KubeadmConfig {
+		return &bootstrapv1.KubeadmConfig{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "config"},
+			Spec: bootstrapv1.KubeadmConfigSpec{
+				Format:   format,
+				Ignition: ignition,
+			},
+		}
+	}
+
+	t.Run("generates a shell payload", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data, err := SecretData(opts, newConfig(bootstrapv1.Shell, nil), []byte("kubeadm join"), now)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(data).To(HaveKeyWithValue("format", []byte(bootstrapv1.Shell)))
+		g.Expect(data).To(HaveKeyWithValue(DataKey, []byte("kubeadm join")))
+		g.Expect(data).To(HaveKeyWithValue(TokenExpirationKey, []byte("2023-01-01T01:00:00Z")))
+		g.Expect(data).To(HaveKey(TokenHashKey))
+
+		payload := string(data["value"])
+		g.Expect(payload).To(HavePrefix("#!/bin/bash\n"))
+		g.Expect(payload).To(ContainSubstring("'https://bootstrap.example.com:9445/bootstrap-data/ns/config'"))
+		g.Expect(payload).To(ContainSubstring("--cacert /run/cluster-api/bootstrap-data-ca.crt"))
+		g.Expect(payload).ToNot(ContainSubstring("kubeadm join"))
+	})
+
+	t.Run("generates an Ignition payload", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data, err := SecretData(opts, newConfig(bootstrapv1.Ignition, &bootstrapv1.IgnitionSpec{Version: bootstrapv1.IgnitionVersion34}), []byte("bootstrap-data"), now)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		config := translate.Config{}
+		g.Expect(json.Unmarshal(data["value"], &config)).To(Succeed())
+		g.Expect(config.Ignition.Version).To(Equal(translate.Version))
+		g.Expect(*config.Ignition.Config.Replace.Source).To(Equal("https://bootstrap.example.com:9445/bootstrap-data/ns/config"))
+		g.Expect(config.Ignition.Config.Replace.HTTPHeaders).To(HaveLen(1))
+		g.Expect(*config.Ignition.Config.Replace.HTTPHeaders[0].Value).To(HavePrefix("Bearer "))
+		g.Expect(config.Ignition.Security.TLS.CertificateAuthorities).To(HaveLen(1))
+	})
+
+	t.Run("the token is stored hashed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data, err := SecretData(opts, newConfig(bootstrapv1.Shell, nil), []byte("bootstrap-data"), now)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(data["value"])).ToNot(ContainSubstring(string(data[TokenHashKey])))
+	})
+
+	t.Run("fails for unsupported formats", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := SecretData(opts, newConfig(bootstrapv1.CloudConfig, nil), []byte("bootstrap-data"), now)
+		g.Expect(err).To(HaveOccurred())
+
+		_, err = SecretData(opts, newConfig(bootstrapv1.Ignition, nil), []byte("bootstrap-data"), now)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("fails if the bootstrap data server is not enabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := SecretData(Options{}, newConfig(bootstrapv1.Shell, nil), []byte("bootstrap-data"), now)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestKeepToken(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	opts := Options{URL: "https://bootstrap.example.com:9445"}
+	config := &bootstrapv1.KubeadmConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "config"},
+		Spec:       bootstrapv1.KubeadmConfigSpec{Format: bootstrapv1.Shell},
+	}
+
+	t.Run("keeps the payload and the token of the existing secret", func(t *testing.T) {
+		g := NewWithT(t)
+
+		existing, err := SecretData(opts, config, []byte("old"), now)
+		g.Expect(err).ToNot(HaveOccurred())
+		data, err := SecretData(opts, config, []byte("new"), now.Add(time.Hour))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		data = KeepToken(existing, data)
+		g.Expect(data).To(HaveKeyWithValue("value", existing["value"]))
+		g.Expect(data).To(HaveKeyWithValue(TokenHashKey, existing[TokenHashKey]))
+		g.Expect(data).To(HaveKeyWithValue(TokenExpirationKey, existing[TokenExpirationKey]))
+		g.Expect(data).To(HaveKeyWithValue(DataKey, []byte("new")))
+	})
+
+	t.Run("does not restore a used token", func(t *testing.T) {
+		g := NewWithT(t)
+
+		existing, err := SecretData(opts, config, []byte("old"), now)
+		g.Expect(err).ToNot(HaveOccurred())
+		delete(existing, TokenHashKey)
+		data, err := SecretData(opts, config, []byte("new"), now)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(KeepToken(existing, data)).ToNot(HaveKey(TokenHashKey))
+	})
+
+	t.Run("uses the new token if the existing secret was not delivered over the secure channel", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data, err := SecretData(opts, config, []byte("new"), now)
+		g.Expect(err).ToNot(HaveOccurred())
+		tokenHash := data[TokenHashKey]
+
+		data = KeepToken(map[string][]byte{"value": []byte("old")}, data)
+		g.Expect(data).To(HaveKeyWithValue(TokenHashKey, tokenHash))
+	})
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securechannel
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// Server serves the bootstrap data to the machines, authenticating the requests with the one-time tokens
+// stored in the bootstrap data secrets.
+type Server struct {
+	// Client is used to read and update the bootstrap data secrets; it must not use a cache, so the
+	// one-time tokens are consumed using optimistic locking on up-to-date secrets.
+	Client client.Client
+
+	// BindAddress is the address the server binds to.
+	BindAddress string
+
+	// CertDir is the directory containing the server certificate and key, named tls.crt and tls.key.
+	CertDir string
+
+	// TLSOpts is used to customize the TLS configuration of the server.
+	TLSOpts []func(*tls.Config)
+
+	// now is used to override the current time in tests.
+	now func() time.Time
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, so the bootstrap data
+// is served by all the replicas.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start runs the server until the context is done.
+func (s *Server) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("bootstrap-data-server")
+
+	watcher, err := certwatcher.New(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	if err != nil {
+		return errors.Wrap(err, "failed to load bootstrap data server certificate")
+	}
+	go func() {
+		if err := watcher.Start(ctx); err != nil {
+			log.Error(err, "Certificate watcher failed")
+		}
+	}()
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: watcher.GetCertificate,
+	}
+	for _, opt := range s.TLSOpts {
+		opt(tlsConfig)
+	}
+
+	listener, err := tls.Listen("tcp", s.BindAddress, tlsConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", s.BindAddress)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(pathPrefix, s)
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctrl.LoggerInto(ctx, log) },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "Failed to shutdown bootstrap data server")
+		}
+	}()
+
+	log.Info("Serving bootstrap data", "address", listener.Addr().String())
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP serves the bootstrap data stored in the secret identified by the request path, if the request
+// carries the one-time token of the secret; the token is then removed from the secret, so the bootstrap
+// data can be fetched only once.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	if req.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, pathPrefix), "/")
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || token == "" {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	key := types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+	log := ctrl.LoggerFrom(ctx).WithValues("Secret", key.String())
+
+	// Failures are reported to the client as unauthorized, so it is not possible to infer which secrets exist.
+	data, err := s.consumeToken(ctx, key, token)
+	if err != nil {
+		log.Info("Refusing to serve bootstrap data", "reason", err.Error())
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	log.Info("Serving bootstrap data")
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(data)
+}
+
+// consumeToken validates the token and removes it from the secret, returning the bootstrap data.
+func (s *Server) consumeToken(ctx context.Context, key types.NamespacedName, token string) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := s.Client.Get(ctx, key, secret); err != nil {
+		return nil, errors.Wrap(err, "failed to get bootstrap data secret")
+	}
+
+	if secret.Type != clusterv1.ClusterSecretType {
+		return nil, errors.Errorf("secret has type %q", secret.Type)
+	}
+	hash, ok := secret.Data[TokenHashKey]
+	if !ok {
+		return nil, errors.New("secret has no one-time token, or the token has already been used")
+	}
+	if subtle.ConstantTimeCompare(hash, []byte(hashToken(token))) != 1 {
+		return nil, errors.New("invalid one-time token")
+	}
+	expiration, err := time.Parse(time.RFC3339, string(secret.Data[TokenExpirationKey]))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid one-time token expiration")
+	}
+	if s.currentTime().After(expiration) {
+		return nil, errors.New("one-time token is expired")
+	}
+
+	// The update fails with a conflict if the secret has been changed in the meantime, e.g. because
+	// the token has been used by a concurrent request.
+	data := secret.Data[DataKey]
+	delete(secret.Data, TokenHashKey)
+	delete(secret.Data, TokenExpirationKey)
+	if err := s.Client.Update(ctx, secret); err != nil {
+		return nil, errors.Wrap(err, "failed to consume one-time token")
+	}
+	return data, nil
+}
+
+func (s *Server) currentTime() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
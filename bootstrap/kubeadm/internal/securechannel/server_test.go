@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securechannel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestServer(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	const token = "one-time-token"
+
+	newSecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "config"},
+			Type:       clusterv1.ClusterSecretType,
+			Data: map[string][]byte{
+				"value":            []byte("payload"),
+				DataKey:            []byte("bootstrap-data"),
+				TokenHashKey:       []byte(hashToken(token)),
+				TokenExpirationKey: []byte(now.Add(time.Hour).Format(time.RFC3339)),
+			},
+		}
+	}
+
+	get := func(s *Server, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("serves the bootstrap data only once", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithObjects(newSecret()).Build()
+		s := &Server{Client: c, now: func() time.Time { return now }}
+
+		rec := get(s, "/bootstrap-data/ns/config", token)
+		g.Expect(rec.Code).To(Equal(http.StatusOK))
+		g.Expect(rec.Body.String()).To(Equal("bootstrap-data"))
+
+		secret := &corev1.Secret{}
+		g.Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "config"}, secret)).To(Succeed())
+		g.Expect(secret.Data).ToNot(HaveKey(TokenHashKey))
+		g.Expect(secret.Data).ToNot(HaveKey(TokenExpirationKey))
+
+		rec = get(s, "/bootstrap-data/ns/config", token)
+		g.Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	t.Run("refuses invalid requests", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			path  string
+			token string
+			now   time.Time
+		}{
+			{name: "without token", path: "/bootstrap-data/ns/config", now: now},
+			{name: "with wrong token", path: "/bootstrap-data/ns/config", token: "wrong-token", now: now},
+			{name: "with expired token", path: "/bootstrap-data/ns/config", token: token, now: now.Add(2 * time.Hour)},
+			{name: "for a secret which does not exist", path: "/bootstrap-data/ns/other", token: token, now: now},
+			{name: "with invalid path", path: "/bootstrap-data/config", token: token, now: now},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				g := NewWithT(t)
+
+				c := fake.NewClientBuilder().WithObjects(newSecret()).Build()
+				s := &Server{Client: c, now: func() time.Time { return tt.now }}
+
+				rec := get(s, tt.path, tt.token)
+				g.Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+			})
+		}
+	})
+
+	t.Run("refuses secrets not created by Cluster API", func(t *testing.T) {
+		g := NewWithT(t)
+
+		secret := newSecret()
+		secret.Type = corev1.SecretTypeOpaque
+		c := fake.NewClientBuilder().WithObjects(secret).Build()
+		s := &Server{Client: c, now: func() time.Time { return now }}
+
+		rec := get(s, "/bootstrap-data/ns/config", token)
+		g.Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+	})
+}
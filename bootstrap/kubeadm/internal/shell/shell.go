@@ -0,0 +1,244 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package shell generates bootstrap data as a self-contained shell script by exposing an API
+// similar to 'internal/cloudinit' package.
+//
+// The generated script does not depend on cloud-init or Ignition being available on the machine, and
+// it is intended for minimal or appliance OS images which execute the user data directly. The script
+// creates users, writes files, runs the pre kubeadm commands, the kubeadm command and the post kubeadm
+// commands, in this order, and it stops at the first failing command.
+//
+// File contents are embedded base64 encoded, so the script only requires bash and coreutils, plus
+// gzip when files with gzip encodings are used.
+package shell
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+)
+
+const (
+	joinSubcommand         = "join"
+	initSubcommand         = "init"
+	kubeadmCommandTemplate = "kubeadm %s --config %s %s"
+	kubeadmInitConfigPath  = "/run/kubeadm/kubeadm.yaml"
+	kubeadmJoinConfigPath  = "/run/kubeadm/kubeadm-join-config.yaml"
+	kubeadmConfigOwner     = "root:root"
+	kubeadmConfigMode      = "0640"
+	sentinelFileCommand    = "mkdir -p /run/cluster-api && echo success > /run/cluster-api/bootstrap-success.complete"
+
+	scriptTemplate = `#!/bin/bash
+# Bootstrap script generated by the Cluster API kubeadm bootstrap provider.
+set -euo pipefail
+{{- range $user := .Users }}
+
+# User {{ .Name }}
+if ! id -u {{ Quote .Name }} >/dev/null 2>&1; then
+  useradd --create-home
+  {{- with .Gecos }} --comment {{ Quote . }}{{ end }}
+  {{- with .HomeDir }} --home-dir {{ Quote . }}{{ end }}
+  {{- with .Shell }} --shell {{ Quote . }}{{ end }}
+  {{- with .PrimaryGroup }} --gid {{ Quote . }}{{ end }}
+  {{- with .Groups }} --groups {{ Groups . | Quote }}{{ end }}
+  {{- with .Passwd }} --password {{ Quote . }}{{ end }} {{ Quote .Name }}
+fi
+{{- if LockPassword . }}
+passwd --lock {{ Quote .Name }} >/dev/null
+{{- end }}
+{{- if Inactive . }}
+usermod --expiredate 1 {{ Quote .Name }}
+{{- end }}
+{{- if .Sudo }}
+install -d -m 0750 /etc/sudoers.d
+printf '%s\n' {{ Sudoers $user | Quote }} > {{ printf "/etc/sudoers.d/%s" $user.Name | Quote }}
+chmod 0440 {{ printf "/etc/sudoers.d/%s" $user.Name | Quote }}
+{{- end }}
+{{- if .SSHAuthorizedKeys }}
+home="$(getent passwd {{ Quote .Name }} | cut -d: -f6)"
+install -d -m 0700 -o {{ Quote .Name }} "${home}/.ssh"
+{{- range .SSHAuthorizedKeys }}
+printf '%s\n' {{ Quote . }} >> "${home}/.ssh/authorized_keys"
+{{- end }}
+chmod 0600 "${home}/.ssh/authorized_keys"
+chown {{ Quote .Name }} "${home}/.ssh/authorized_keys"
+{{- end }}
+{{- end }}
+{{- range $file := .WriteFiles }}
+
+# File {{ .Path }}
+mkdir -p {{ Dir .Path | Quote }}
+printf '%s' {{ Content . | Quote }} | base64 -d{{ if Compressed . }} | gunzip{{ end }} {{ if .Append }}>>{{ else }}>{{ end }} {{ Quote .Path }}
+{{- with .Permissions }}
+chmod {{ Quote . }} {{ Quote $file.Path }}
+{{- end }}
+{{- with .Owner }}
+chown {{ Quote . }} {{ Quote $file.Path }}
+{{- end }}
+{{- end }}
+{{- if .PreKubeadmCommands }}
+
+# Pre kubeadm commands
+{{- range .PreKubeadmCommands }}
+{{ . }}
+{{- end }}
+{{- end }}
+
+# kubeadm
+{{ .KubeadmCommand }}
+{{ .SentinelFileCommand }}
+{{- if .PostKubeadmCommands }}
+
+# Post kubeadm commands
+{{- range .PostKubeadmCommands }}
+{{ . }}
+{{- end }}
+{{- end }}
+`
+)
+
+// NewNode returns a bootstrap script for new worker node joining the cluster.
+func NewNode(input *cloudinit.NodeInput) ([]byte, error) {
+	if input == nil {
+		return nil, errors.New("input can't be nil")
+	}
+
+	input.WriteFiles = append(input.WriteFiles, input.AdditionalFiles...)
+	input.WriteFiles = append(input.WriteFiles, kubeadmConfigFile(kubeadmJoinConfigPath, input.JoinConfiguration))
+	input.KubeadmCommand = fmt.Sprintf(kubeadmCommandTemplate, joinSubcommand, kubeadmJoinConfigPath, input.KubeadmVerbosity)
+
+	return render(&input.BaseUserData)
+}
+
+// NewJoinControlPlane returns a bootstrap script for new controlplane node joining the cluster.
+func NewJoinControlPlane(input *cloudinit.ControlPlaneJoinInput) ([]byte, error) {
+	if input == nil {
+		return nil, errors.New("input can't be nil")
+	}
+
+	input.WriteFiles = input.Certificates.AsFiles()
+	input.WriteFiles = append(input.WriteFiles, input.AdditionalFiles...)
+	input.WriteFiles = append(input.WriteFiles, kubeadmConfigFile(kubeadmJoinConfigPath, input.JoinConfiguration))
+	input.KubeadmCommand = fmt.Sprintf(kubeadmCommandTemplate, joinSubcommand, kubeadmJoinConfigPath, input.KubeadmVerbosity)
+	input.ControlPlane = true
+
+	return render(&input.BaseUserData)
+}
+
+// NewInitControlPlane returns a bootstrap script for bootstrapping new cluster.
+func NewInitControlPlane(input *cloudinit.ControlPlaneInput) ([]byte, error) {
+	if input == nil {
+		return nil, errors.New("input can't be nil")
+	}
+
+	kubeadmConfig := fmt.Sprintf("---\n%s\n---\n%s", input.ClusterConfiguration, input.InitConfiguration)
+
+	input.WriteFiles = input.Certificates.AsFiles()
+	input.WriteFiles = append(input.WriteFiles, input.AdditionalFiles...)
+	input.WriteFiles = append(input.WriteFiles, kubeadmConfigFile(kubeadmInitConfigPath, kubeadmConfig))
+	input.KubeadmCommand = fmt.Sprintf(kubeadmCommandTemplate, initSubcommand, kubeadmInitConfigPath, input.KubeadmVerbosity)
+
+	return render(&input.BaseUserData)
+}
+
+func kubeadmConfigFile(path, content string) bootstrapv1.File {
+	return bootstrapv1.File{
+		Path:        path,
+		Owner:       kubeadmConfigOwner,
+		Permissions: kubeadmConfigMode,
+		Content:     content,
+	}
+}
+
+func render(input *cloudinit.BaseUserData) ([]byte, error) {
+	input.KubeadmCommand = strings.TrimSpace(input.KubeadmCommand)
+	input.SentinelFileCommand = sentinelFileCommand
+
+	t, err := template.New("shell").Funcs(defaultTemplateFuncMap()).Parse(scriptTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse shell template")
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, input); err != nil {
+		return nil, errors.Wrap(err, "failed to generate shell template")
+	}
+
+	return out.Bytes(), nil
+}
+
+func defaultTemplateFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"Quote":        quote,
+		"Dir":          path.Dir,
+		"Groups":       groups,
+		"LockPassword": lockPassword,
+		"Inactive":     inactive,
+		"Sudoers":      sudoers,
+		"Content":      content,
+		"Compressed":   compressed,
+	}
+}
+
+// quote returns the input as a single-quoted shell word.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// groups converts the comma separated list of groups used in the API, which can contain
+// spaces, to the format expected by useradd.
+func groups(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, ",", " ")), ",")
+}
+
+// lockPassword returns true if the password of the user should be locked, defaulting to true
+// consistently with cloud-init.
+func lockPassword(user bootstrapv1.User) bool {
+	return user.LockPassword == nil || *user.LockPassword
+}
+
+func inactive(user bootstrapv1.User) bool {
+	return user.Inactive != nil && *user.Inactive
+}
+
+// sudoers returns the sudoers entry for the user.
+func sudoers(user bootstrapv1.User) string {
+	return fmt.Sprintf("%s %s", user.Name, *user.Sudo)
+}
+
+// content returns the base64 encoded content of the file, ready to be piped into base64 -d
+// and, for gzip encodings, gunzip.
+func content(file bootstrapv1.File) string {
+	switch file.Encoding {
+	case bootstrapv1.Base64, bootstrapv1.GzipBase64:
+		return strings.Join(strings.Fields(file.Content), "")
+	default:
+		return base64.StdEncoding.EncodeToString([]byte(file.Content))
+	}
+}
+
+func compressed(file bootstrapv1.File) bool {
+	return file.Encoding == bootstrapv1.Gzip || file.Encoding == bootstrapv1.GzipBase64
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+func TestNewNode(t *testing.T) {
+	g := NewWithT(t)
+
+	input := &cloudinit.NodeInput{
+		BaseUserData: cloudinit.BaseUserData{
+			AdditionalFiles: []bootstrapv1.File{
+				{
+					Path:        "/etc/foo.conf",
+					Content:     "bar",
+					Owner:       "root:root",
+					Permissions: "0644",
+				},
+				{
+					Path:     "/etc/bar.conf",
+					Content:  "H4sIAAAAAAAA/0rLz+cCAAAA//8DAKhlMn4EAAAA",
+					Encoding: bootstrapv1.GzipBase64,
+					Append:   true,
+				},
+			},
+			PreKubeadmCommands:  []string{"echo pre"},
+			PostKubeadmCommands: []string{"echo post"},
+			Users: []bootstrapv1.User{
+				{
+					Name:              "foo",
+					Groups:            pointer.String("wheel, docker"),
+					Sudo:              pointer.String("ALL=(ALL) NOPASSWD:ALL"),
+					Passwd:            pointer.String("$6$rounds=4096$foo"),
+					LockPassword:      pointer.Bool(false),
+					SSHAuthorizedKeys: []string{"ssh-rsa foo"},
+				},
+				{
+					Name:     "bar",
+					Inactive: pointer.Bool(true),
+				},
+			},
+			KubeadmVerbosity: "--v=5",
+		},
+		JoinConfiguration: "kind: JoinConfiguration",
+	}
+
+	out, err := NewNode(input)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	script := string(out)
+	g.Expect(script).To(HavePrefix("#!/bin/bash\n"))
+	g.Expect(script).To(ContainSubstring("set -euo pipefail\n"))
+
+	// Users.
+	g.Expect(script).To(ContainSubstring("useradd --create-home --groups 'wheel,docker' --password '$6$rounds=4096$foo' 'foo'\n"))
+	g.Expect(script).ToNot(ContainSubstring("passwd --lock 'foo'"))
+	g.Expect(script).To(ContainSubstring("printf '%s\\n' 'foo ALL=(ALL) NOPASSWD:ALL' > '/etc/sudoers.d/foo'\n"))
+	g.Expect(script).To(ContainSubstring("printf '%s\\n' 'ssh-rsa foo' >> \"${home}/.ssh/authorized_keys\"\n"))
+	g.Expect(script).To(ContainSubstring("passwd --lock 'bar' >/dev/null\n"))
+	g.Expect(script).To(ContainSubstring("usermod --expiredate 1 'bar'\n"))
+
+	// Files.
+	g.Expect(script).To(ContainSubstring("printf '%s' '" + base64.StdEncoding.EncodeToString([]byte("bar")) + "' | base64 -d > '/etc/foo.conf'\nchmod '0644' '/etc/foo.conf'\nchown 'root:root' '/etc/foo.conf'\n"))
+	g.Expect(script).To(ContainSubstring("printf '%s' 'H4sIAAAAAAAA/0rLz+cCAAAA//8DAKhlMn4EAAAA' | base64 -d | gunzip >> '/etc/bar.conf'\n"))
+	g.Expect(script).To(ContainSubstring("printf '%s' '" + base64.StdEncoding.EncodeToString([]byte("kind: JoinConfiguration")) + "' | base64 -d > '/run/kubeadm/kubeadm-join-config.yaml'\n"))
+
+	// Commands are executed in order.
+	pre := strings.Index(script, "echo pre\n")
+	kubeadm := strings.Index(script, "kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml --v=5\nmkdir -p /run/cluster-api && echo success > /run/cluster-api/bootstrap-success.complete\n")
+	post := strings.Index(script, "echo post\n")
+	g.Expect(pre).To(BeNumerically(">", 0))
+	g.Expect(kubeadm).To(BeNumerically(">", pre))
+	g.Expect(post).To(BeNumerically(">", kubeadm))
+}
+
+func TestNewInitControlPlane(t *testing.T) {
+	g := NewWithT(t)
+
+	input := &cloudinit.ControlPlaneInput{
+		Certificates: secret.Certificates{
+			&secret.Certificate{
+				Purpose:  secret.ClusterCA,
+				KeyPair:  &certs.KeyPair{Cert: []byte("ca-cert"), Key: []byte("ca-key")},
+				CertFile: "/etc/kubernetes/pki/ca.crt",
+				KeyFile:  "/etc/kubernetes/pki/ca.key",
+			},
+		},
+		ClusterConfiguration: "kind: ClusterConfiguration",
+		InitConfiguration:    "kind: InitConfiguration",
+	}
+
+	out, err := NewInitControlPlane(input)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	script := string(out)
+	g.Expect(script).To(ContainSubstring("'/etc/kubernetes/pki/ca.crt'\n"))
+	g.Expect(script).To(ContainSubstring("'/etc/kubernetes/pki/ca.key'\n"))
+	g.Expect(script).To(ContainSubstring(base64.StdEncoding.EncodeToString([]byte("---\nkind: ClusterConfiguration\n---\nkind: InitConfiguration"))))
+	g.Expect(script).To(ContainSubstring("\nkubeadm init --config /run/kubeadm/kubeadm.yaml\n"))
+}
+
+func TestNewJoinControlPlane(t *testing.T) {
+	g := NewWithT(t)
+
+	input := &cloudinit.ControlPlaneJoinInput{
+		JoinConfiguration: "kind: JoinConfiguration",
+	}
+
+	out, err := NewJoinControlPlane(input)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(out)).To(ContainSubstring("\nkubeadm join --config /run/kubeadm/kubeadm-join-config.yaml\n"))
+}
+
+func TestNilInput(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewNode(nil)
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = NewJoinControlPlane(nil)
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = NewInitControlPlane(nil)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestQuote(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(quote("foo")).To(Equal("'foo'"))
+	g.Expect(quote("it's")).To(Equal(`'it'"'"'s'`))
+	g.Expect(quote("$(rm -rf /)")).To(Equal("'$(rm -rf /)'"))
+}
@@ -66,17 +66,21 @@ func (webhook *KubeadmConfig) ValidateCreate(_ context.Context, obj runtime.Obje
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KubeadmConfig but got a %T", obj))
 	}
 
-	return nil, webhook.validate(c.Spec, c.Name)
+	return nil, webhook.validate(nil, c.Spec, c.Name)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *KubeadmConfig) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+func (webhook *KubeadmConfig) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldC, ok := oldObj.(*bootstrapv1.KubeadmConfig)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KubeadmConfig but got a %T", oldObj))
+	}
 	newC, ok := newObj.(*bootstrapv1.KubeadmConfig)
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KubeadmConfig but got a %T", newObj))
 	}
 
-	return nil, webhook.validate(newC.Spec, newC.Name)
+	return nil, webhook.validate(&oldC.Spec, newC.Spec, newC.Name)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -84,8 +88,8 @@ func (webhook *KubeadmConfig) ValidateDelete(_ context.Context, _ runtime.Object
 	return nil, nil
 }
 
-func (webhook *KubeadmConfig) validate(c bootstrapv1.KubeadmConfigSpec, name string) error {
-	allErrs := c.Validate(field.NewPath("spec"))
+func (webhook *KubeadmConfig) validate(oldSpec *bootstrapv1.KubeadmConfigSpec, c bootstrapv1.KubeadmConfigSpec, name string) error {
+	allErrs := c.ValidateUpdate(oldSpec, field.NewPath("spec"))
 
 	if len(allErrs) == 0 {
 		return nil
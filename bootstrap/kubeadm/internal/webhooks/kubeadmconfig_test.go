@@ -164,6 +164,111 @@ func TestKubeadmConfigValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"valid contentFrom config map": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Files: []bootstrapv1.File{
+						{
+							ContentFrom: &bootstrapv1.FileSource{
+								ConfigMap: &bootstrapv1.ConfigMapFileSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"invalid contentFrom with both secret and config map": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Files: []bootstrapv1.File{
+						{
+							ContentFrom: &bootstrapv1.FileSource{
+								Secret: bootstrapv1.SecretFileSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+								ConfigMap: &bootstrapv1.ConfigMapFileSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid contentFrom without secret and config map": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Files: []bootstrapv1.File{
+						{
+							ContentFrom: &bootstrapv1.FileSource{},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid contentFrom with partial secret and config map": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Files: []bootstrapv1.File{
+						{
+							ContentFrom: &bootstrapv1.FileSource{
+								Secret: bootstrapv1.SecretFileSource{
+									Key: "bar",
+								},
+								ConfigMap: &bootstrapv1.ConfigMapFileSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid contentFrom config map without key": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Files: []bootstrapv1.File{
+						{
+							ContentFrom: &bootstrapv1.FileSource{
+								ConfigMap: &bootstrapv1.ConfigMapFileSource{
+									Name: "foo",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
 		"invalid with duplicate file path": {
 			in: &bootstrapv1.KubeadmConfig{
 				ObjectMeta: metav1.ObjectMeta{
@@ -320,6 +425,26 @@ func TestKubeadmConfigValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"format is Ignition 3.4, user is inactive": {
+			enableIgnitionFeature: true,
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Ignition,
+					Ignition: &bootstrapv1.IgnitionSpec{
+						Version: bootstrapv1.IgnitionVersion34,
+					},
+					Users: []bootstrapv1.User{
+						{
+							Inactive: pointer.Bool(true),
+						},
+					},
+				},
+			},
+		},
 		"format is Ignition, non-GPT partition configured": {
 			enableIgnitionFeature: true,
 			in: &bootstrapv1.KubeadmConfig{
@@ -401,7 +526,7 @@ func TestKubeadmConfigValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
-		"filesystem partition specified with Ignition": {
+		"filesystem partition number specified with Ignition": {
 			enableIgnitionFeature: true,
 			in: &bootstrapv1.KubeadmConfig{
 				ObjectMeta: metav1.ObjectMeta{
@@ -419,6 +544,25 @@ func TestKubeadmConfigValidate(t *testing.T) {
 					},
 				},
 			},
+		},
+		"filesystem partition auto specified with Ignition": {
+			enableIgnitionFeature: true,
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Ignition,
+					DiskSetup: &bootstrapv1.DiskSetup{
+						Filesystems: []bootstrapv1.Filesystem{
+							{
+								Partition: pointer.String("auto"),
+							},
+						},
+					},
+				},
+			},
 			expectErr: true,
 		},
 		"file encoding gzip specified with Ignition": {
@@ -439,6 +583,26 @@ func TestKubeadmConfigValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"file encoding gzip specified with Ignition 3.4": {
+			enableIgnitionFeature: true,
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Ignition,
+					Ignition: &bootstrapv1.IgnitionSpec{
+						Version: bootstrapv1.IgnitionVersion34,
+					},
+					Files: []bootstrapv1.File{
+						{
+							Encoding: bootstrapv1.Gzip,
+						},
+					},
+				},
+			},
+		},
 		"file encoding gzip+base64 specified with Ignition": {
 			enableIgnitionFeature: true,
 			in: &bootstrapv1.KubeadmConfig{
@@ -457,6 +621,421 @@ func TestKubeadmConfigValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"file encoding gzip+base64 specified with Ignition 3.4": {
+			enableIgnitionFeature: true,
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Ignition,
+					Ignition: &bootstrapv1.IgnitionSpec{
+						Version: bootstrapv1.IgnitionVersion34,
+					},
+					Files: []bootstrapv1.File{
+						{
+							Encoding: bootstrapv1.GzipBase64,
+						},
+					},
+				},
+			},
+		},
+		"mount referencing a filesystem label specified with Ignition": {
+			enableIgnitionFeature: true,
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Ignition,
+					DiskSetup: &bootstrapv1.DiskSetup{
+						Filesystems: []bootstrapv1.Filesystem{
+							{
+								Label: "data",
+							},
+						},
+					},
+					Mounts: []bootstrapv1.MountPoints{
+						{"data", "/var/lib/data"},
+					},
+				},
+			},
+		},
+		"mount referencing an unknown filesystem label specified with Ignition": {
+			enableIgnitionFeature: true,
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Ignition,
+					Mounts: []bootstrapv1.MountPoints{
+						{"data", "/var/lib/data"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"mount without mount point specified with Ignition": {
+			enableIgnitionFeature: true,
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Ignition,
+					DiskSetup: &bootstrapv1.DiskSetup{
+						Filesystems: []bootstrapv1.Filesystem{
+							{
+								Label: "data",
+							},
+						},
+					},
+					Mounts: []bootstrapv1.MountPoints{
+						{"data"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"Ignition 3.4 with systemd units": {
+			enableIgnitionFeature: true,
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Ignition,
+					Ignition: &bootstrapv1.IgnitionSpec{
+						Version: bootstrapv1.IgnitionVersion34,
+						SystemdUnits: []bootstrapv1.SystemdUnit{
+							{
+								Name:    "foo.service",
+								DropIns: []bootstrapv1.SystemdUnitDropIn{{Name: "10-foo.conf"}},
+							},
+							{
+								Name: "foo.timer",
+							},
+						},
+					},
+				},
+			},
+		},
+		"systemd unit without type suffix": {
+			enableIgnitionFeature: true,
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Ignition,
+					Ignition: &bootstrapv1.IgnitionSpec{
+						SystemdUnits: []bootstrapv1.SystemdUnit{
+							{
+								Name: "foo",
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"duplicate systemd units": {
+			enableIgnitionFeature: true,
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Ignition,
+					Ignition: &bootstrapv1.IgnitionSpec{
+						SystemdUnits: []bootstrapv1.SystemdUnit{
+							{
+								Name: "foo.service",
+							},
+							{
+								Name: "foo.service",
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"format is shell": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Shell,
+					Users: []bootstrapv1.User{
+						{
+							Name: "foo",
+						},
+					},
+				},
+			},
+		},
+		"format is shell, disk setup and NTP configured": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format:    bootstrapv1.Shell,
+					DiskSetup: &bootstrapv1.DiskSetup{},
+					NTP:       &bootstrapv1.NTP{},
+				},
+			},
+			expectErr: true,
+		},
+		"multi-part MIME and gzip output": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.CloudConfig,
+					Output: &bootstrapv1.OutputSpec{
+						MultiPart: &bootstrapv1.MultiPartOutput{
+							Parts: []bootstrapv1.MIMEPart{
+								{
+									ContentType: bootstrapv1.ShellScriptMIMEPartContentType,
+									Content:     "#!/bin/bash\necho hello",
+								},
+							},
+						},
+						Compression: bootstrapv1.GzipOutputCompression,
+					},
+				},
+			},
+		},
+		"multi-part MIME part without content": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Output: &bootstrapv1.OutputSpec{
+						MultiPart: &bootstrapv1.MultiPartOutput{
+							Parts: []bootstrapv1.MIMEPart{
+								{
+									ContentType: bootstrapv1.ShellScriptMIMEPartContentType,
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"output with format shell": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Shell,
+					Output: &bootstrapv1.OutputSpec{
+						Compression: bootstrapv1.GzipOutputCompression,
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"systemd unit managed by the bootstrap provider": {
+			enableIgnitionFeature: true,
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Format: bootstrapv1.Ignition,
+					Ignition: &bootstrapv1.IgnitionSpec{
+						SystemdUnits: []bootstrapv1.SystemdUnit{
+							{
+								Name: "kubeadm.service",
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"file discovery with generated kubeconfig": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					JoinConfiguration: &bootstrapv1.JoinConfiguration{
+						Discovery: bootstrapv1.Discovery{
+							File: &bootstrapv1.FileDiscovery{
+								KubeConfigPath: "/etc/kubernetes/discovery.conf",
+								KubeConfig: &bootstrapv1.FileDiscoveryKubeConfig{
+									User: bootstrapv1.KubeConfigUser{
+										Exec: &bootstrapv1.KubeConfigAuthExec{
+											Command: "/usr/bin/credential-helper",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"file discovery with generated kubeconfig and bootstrap token": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					JoinConfiguration: &bootstrapv1.JoinConfiguration{
+						Discovery: bootstrapv1.Discovery{
+							BootstrapToken: &bootstrapv1.BootstrapTokenDiscovery{
+								Token: "abcdef.0123456789abcdef",
+							},
+							File: &bootstrapv1.FileDiscovery{
+								KubeConfigPath: "/etc/kubernetes/discovery.conf",
+								KubeConfig: &bootstrapv1.FileDiscoveryKubeConfig{
+									User: bootstrapv1.KubeConfigUser{
+										Exec: &bootstrapv1.KubeConfigAuthExec{
+											Command: "/usr/bin/credential-helper",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"file discovery with generated kubeconfig and URL": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					JoinConfiguration: &bootstrapv1.JoinConfiguration{
+						Discovery: bootstrapv1.Discovery{
+							File: &bootstrapv1.FileDiscovery{
+								KubeConfigPath: "https://example.com/discovery.conf",
+								KubeConfig: &bootstrapv1.FileDiscoveryKubeConfig{
+									User: bootstrapv1.KubeConfigUser{
+										Exec: &bootstrapv1.KubeConfigAuthExec{
+											Command: "/usr/bin/credential-helper",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"file discovery with generated kubeconfig without user": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					JoinConfiguration: &bootstrapv1.JoinConfiguration{
+						Discovery: bootstrapv1.Discovery{
+							File: &bootstrapv1.FileDiscovery{
+								KubeConfigPath: "/etc/kubernetes/discovery.conf",
+								KubeConfig:     &bootstrapv1.FileDiscoveryKubeConfig{},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"file discovery with generated kubeconfig with both auth provider and exec": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					JoinConfiguration: &bootstrapv1.JoinConfiguration{
+						Discovery: bootstrapv1.Discovery{
+							File: &bootstrapv1.FileDiscovery{
+								KubeConfigPath: "/etc/kubernetes/discovery.conf",
+								KubeConfig: &bootstrapv1.FileDiscoveryKubeConfig{
+									User: bootstrapv1.KubeConfigUser{
+										AuthProvider: &bootstrapv1.KubeConfigAuthProvider{
+											Name: "oidc",
+										},
+										Exec: &bootstrapv1.KubeConfigAuthExec{
+											Command: "/usr/bin/credential-helper",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"disk setup with variables": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					DiskSetup: &bootstrapv1.DiskSetup{
+						Filesystems: []bootstrapv1.Filesystem{
+							{
+								Device:     "{{ .ephemeralDevice }}",
+								Filesystem: "ext4",
+								Label:      "ephemeral0",
+							},
+						},
+					},
+					Mounts: []bootstrapv1.MountPoints{
+						{"ephemeral0", "/var/lib/containerd"},
+					},
+				},
+			},
+		},
+		"disk setup with invalid variable reference": {
+			in: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Mounts: []bootstrapv1.MountPoints{
+						{"{{ .ephemeralDevice", "/var/lib/containerd"},
+					},
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for name, tt := range cases {
@@ -474,17 +1053,49 @@ func TestKubeadmConfigValidate(t *testing.T) {
 				warnings, err := webhook.ValidateCreate(ctx, tt.in)
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(warnings).To(BeEmpty())
-				warnings, err = webhook.ValidateUpdate(ctx, nil, tt.in)
+				warnings, err = webhook.ValidateUpdate(ctx, &bootstrapv1.KubeadmConfig{}, tt.in)
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(warnings).To(BeEmpty())
 			} else {
 				warnings, err := webhook.ValidateCreate(ctx, tt.in)
 				g.Expect(err).ToNot(HaveOccurred())
 				g.Expect(warnings).To(BeEmpty())
-				warnings, err = webhook.ValidateUpdate(ctx, nil, tt.in)
+				warnings, err = webhook.ValidateUpdate(ctx, &bootstrapv1.KubeadmConfig{}, tt.in)
 				g.Expect(err).ToNot(HaveOccurred())
 				g.Expect(warnings).To(BeEmpty())
 			}
 		})
 	}
 }
+
+func TestKubeadmConfigValidateUpdateIgnitionMounts(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.KubeadmBootstrapFormatIgnition, true)()
+
+	g := NewWithT(t)
+
+	// A KubeadmConfig created before the mounts have been validated.
+	oldConfig := &bootstrapv1.KubeadmConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "baz",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: bootstrapv1.KubeadmConfigSpec{
+			Format: bootstrapv1.Ignition,
+			Mounts: []bootstrapv1.MountPoints{
+				{"/dev/sdb", "/var/lib/data"},
+			},
+		},
+	}
+	webhook := &KubeadmConfig{}
+
+	// Objects can be updated as long as the mounts don't change.
+	newConfig := oldConfig.DeepCopy()
+	newConfig.Spec.PreKubeadmCommands = []string{"echo hello"}
+	_, err := webhook.ValidateUpdate(ctx, oldConfig, newConfig)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Changed mounts are validated.
+	newConfig.Spec.Mounts = append(newConfig.Spec.Mounts, bootstrapv1.MountPoints{"/dev/sdc", "/var/lib/other"})
+	_, err = webhook.ValidateUpdate(ctx, oldConfig, newConfig)
+	g.Expect(err).To(HaveOccurred())
+}
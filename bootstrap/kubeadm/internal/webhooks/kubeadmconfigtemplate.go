@@ -62,17 +62,21 @@ func (webhook *KubeadmConfigTemplate) ValidateCreate(_ context.Context, obj runt
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KubeadmConfigTemplate but got a %T", obj))
 	}
 
-	return nil, webhook.validate(&c.Spec, c.Name)
+	return nil, webhook.validate(nil, &c.Spec, c.Name)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *KubeadmConfigTemplate) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+func (webhook *KubeadmConfigTemplate) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldC, ok := oldObj.(*bootstrapv1.KubeadmConfigTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KubeadmConfigTemplate but got a %T", oldObj))
+	}
 	newC, ok := newObj.(*bootstrapv1.KubeadmConfigTemplate)
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KubeadmConfigTemplate but got a %T", newObj))
 	}
 
-	return nil, webhook.validate(&newC.Spec, newC.Name)
+	return nil, webhook.validate(&oldC.Spec, &newC.Spec, newC.Name)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -80,10 +84,14 @@ func (webhook *KubeadmConfigTemplate) ValidateDelete(_ context.Context, _ runtim
 	return nil, nil
 }
 
-func (webhook *KubeadmConfigTemplate) validate(r *bootstrapv1.KubeadmConfigTemplateSpec, name string) error {
+func (webhook *KubeadmConfigTemplate) validate(oldSpec, r *bootstrapv1.KubeadmConfigTemplateSpec, name string) error {
 	var allErrs field.ErrorList
 
-	allErrs = append(allErrs, r.Template.Spec.Validate(field.NewPath("spec", "template", "spec"))...)
+	var oldConfigSpec *bootstrapv1.KubeadmConfigSpec
+	if oldSpec != nil {
+		oldConfigSpec = &oldSpec.Template.Spec
+	}
+	allErrs = append(allErrs, r.Template.Spec.ValidateUpdate(oldConfigSpec, field.NewPath("spec", "template", "spec"))...)
 	// Validate the metadata of the template.
 	allErrs = append(allErrs, r.Template.ObjectMeta.Validate(field.NewPath("spec", "template", "metadata"))...)
 
@@ -96,7 +96,7 @@ func TestKubeadmConfigTemplateValidation(t *testing.T) {
 				g.Expect(err).ToNot(HaveOccurred())
 			}
 			g.Expect(warnings).To(BeEmpty())
-			warnings, err = webhook.ValidateUpdate(ctx, nil, tt.in)
+			warnings, err = webhook.ValidateUpdate(ctx, &bootstrapv1.KubeadmConfigTemplate{}, tt.in)
 			if tt.expectErr {
 				g.Expect(err).To(HaveOccurred())
 			} else {
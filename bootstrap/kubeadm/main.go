@@ -21,6 +21,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
 	goruntime "runtime"
 	"time"
@@ -52,6 +53,7 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util/flags"
+	"sigs.k8s.io/cluster-api/util/tracing"
 	"sigs.k8s.io/cluster-api/version"
 )
 
@@ -77,12 +79,21 @@ var (
 	healthAddr                  string
 	tlsOptions                  = flags.TLSOptions{}
 	diagnosticsOptions          = flags.DiagnosticsOptions{}
+	tracingOptions              = flags.TracingOptions{}
 	logOptions                  = logs.NewOptions()
 	// CABPK specific flags.
 	clusterConcurrency             int
 	clusterCacheTrackerConcurrency int
+	clusterCacheTrackerClientQPS   float32
+	clusterCacheTrackerClientBurst int
+	clusterCacheTrackerProxy       string
 	kubeadmConfigConcurrency       int
 	tokenTTL                       time.Duration
+	bootstrapDataServerBindAddress string
+	bootstrapDataServerURL         string
+	bootstrapDataServerCertDir     string
+	bootstrapDataServerCAFile      string
+	bootstrapDataTokenTTL          time.Duration
 )
 
 func init() {
@@ -128,6 +139,15 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&clusterCacheTrackerConcurrency, "clustercachetracker-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
+	fs.Float32Var(&clusterCacheTrackerClientQPS, "clustercachetracker-client-qps", 5,
+		"Maximum queries per second from the controller clients to the Kubernetes API server of workload clusters. Defaults to 5")
+
+	fs.IntVar(&clusterCacheTrackerClientBurst, "clustercachetracker-client-burst", 10,
+		"Maximum number of queries that should be allowed in one burst from the controller clients to the Kubernetes API server of workload clusters. Default 10")
+
+	fs.StringVar(&clusterCacheTrackerProxy, "clustercachetracker-proxy", "",
+		"URL of the HTTP CONNECT (http or https scheme) or SOCKS5 (socks5 scheme) proxy used to reach the Kubernetes API server of workload clusters; it can be overridden per Cluster with the cluster-cache.cluster.x-k8s.io/proxy annotation")
+
 	fs.IntVar(&kubeadmConfigConcurrency, "kubeadmconfig-concurrency", 10,
 		"Number of kubeadm configs to process simultaneously")
 
@@ -143,6 +163,21 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&tokenTTL, "bootstrap-token-ttl", kubeadmbootstrapcontrollers.DefaultTokenTTL,
 		"The amount of time the bootstrap token will be valid")
 
+	fs.StringVar(&bootstrapDataServerBindAddress, "bootstrap-data-server-bind-address", "",
+		"The address the bootstrap data server binds to, used to deliver the bootstrap data to infrastructure machines requesting the SecureChannel bootstrap data delivery. If empty, the bootstrap data server is disabled.")
+
+	fs.StringVar(&bootstrapDataServerURL, "bootstrap-data-server-url", "",
+		"The URL of the bootstrap data server as reachable from the machines (e.g. https://capi-kubeadm-bootstrap.example.com:9445). Required if bootstrap-data-server-bind-address is set.")
+
+	fs.StringVar(&bootstrapDataServerCertDir, "bootstrap-data-server-cert-dir", "/tmp/bootstrap-data-server/serving-certs/",
+		"Bootstrap data server cert dir, containing tls.crt and tls.key.")
+
+	fs.StringVar(&bootstrapDataServerCAFile, "bootstrap-data-server-ca-file", "",
+		"The CA certificate used by the machines to verify the bootstrap data server certificate. If empty, the machines use their system trust store.")
+
+	fs.DurationVar(&bootstrapDataTokenTTL, "bootstrap-data-token-ttl", kubeadmbootstrapcontrollers.DefaultSecureChannelTokenTTL,
+		"The amount of time the one-time token used to fetch the bootstrap data from the bootstrap data server will be valid")
+
 	fs.IntVar(&webhookPort, "webhook-port", 9443,
 		"Webhook Server port")
 
@@ -154,6 +189,7 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	flags.AddDiagnosticsOptions(fs, &diagnosticsOptions)
 	flags.AddTLSOptions(fs, &tlsOptions)
+	flags.AddTracingOptions(fs, &tracingOptions)
 
 	feature.MutableGates.AddFlag(fs)
 }
@@ -257,6 +293,12 @@ func main() {
 	// Setup the context that's going to be used in controllers and for the manager.
 	ctx := ctrl.SetupSignalHandler()
 
+	shutdownTracing, err := tracing.Setup(ctx, "capi-kubeadm-bootstrap-controller-manager", tracingOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to setup tracing")
+		os.Exit(1)
+	}
+
 	setupChecks(mgr)
 	setupWebhooks(mgr)
 	setupReconcilers(ctx, mgr)
@@ -266,6 +308,10 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "problem flushing traces")
+	}
 }
 
 func setupChecks(mgr ctrl.Manager) {
@@ -292,6 +338,15 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	var clusterCacheTrackerProxyURL *url.URL
+	if clusterCacheTrackerProxy != "" {
+		clusterCacheTrackerProxyURL, err = remote.ParseProxyURL(clusterCacheTrackerProxy)
+		if err != nil {
+			setupLog.Error(err, "unable to parse the proxy URL of the ClusterCacheTracker")
+			os.Exit(1)
+		}
+	}
+
 	// Set up a ClusterCacheTracker and ClusterCacheReconciler to provide to controllers
 	// requiring a connection to a remote cluster
 	log := ctrl.Log.WithName("remote").WithName("ClusterCacheTracker")
@@ -300,6 +355,9 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		remote.ClusterCacheTrackerOptions{
 			SecretCachingClient: secretCachingClient,
 			ControllerName:      controllerName,
+			ClientQPS:           clusterCacheTrackerClientQPS,
+			ClientBurst:         clusterCacheTrackerClientBurst,
+			Proxy:               clusterCacheTrackerProxyURL,
 			Log:                 &log,
 		},
 	)
@@ -316,12 +374,49 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	secureChannel := kubeadmbootstrapcontrollers.SecureChannelOptions{}
+	if bootstrapDataServerBindAddress != "" {
+		if bootstrapDataServerURL == "" {
+			setupLog.Error(nil, "bootstrap-data-server-url is required when bootstrap-data-server-bind-address is set")
+			os.Exit(1)
+		}
+		var caCert []byte
+		if bootstrapDataServerCAFile != "" {
+			caCert, err = os.ReadFile(bootstrapDataServerCAFile)
+			if err != nil {
+				setupLog.Error(err, "unable to read bootstrap data server CA certificate")
+				os.Exit(1)
+			}
+		}
+		secureChannel = kubeadmbootstrapcontrollers.SecureChannelOptions{
+			URL:      bootstrapDataServerURL,
+			CACert:   caCert,
+			TokenTTL: bootstrapDataTokenTTL,
+		}
+
+		tlsOptionOverrides, err := flags.GetTLSOptionOverrideFuncs(tlsOptions)
+		if err != nil {
+			setupLog.Error(err, "unable to add TLS settings to the bootstrap data server")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&kubeadmbootstrapcontrollers.BootstrapDataServer{
+			Client:      mgr.GetClient(),
+			BindAddress: bootstrapDataServerBindAddress,
+			CertDir:     bootstrapDataServerCertDir,
+			TLSOpts:     tlsOptionOverrides,
+		}); err != nil {
+			setupLog.Error(err, "unable to create bootstrap data server")
+			os.Exit(1)
+		}
+	}
+
 	if err := (&kubeadmbootstrapcontrollers.KubeadmConfigReconciler{
 		Client:              mgr.GetClient(),
 		SecretCachingClient: secretCachingClient,
 		Tracker:             tracker,
 		WatchFilterValue:    watchFilterValue,
 		TokenTTL:            tokenTTL,
+		SecureChannel:       secureChannel,
 	}).SetupWithManager(ctx, mgr, concurrency(kubeadmConfigConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubeadmConfig")
 		os.Exit(1)
@@ -67,6 +67,9 @@ func fuzzFuncs(_ runtimeserializer.CodecFactory) []interface{} {
 		kubeadmInitConfigurationFuzzer,
 		kubeadmJoinConfigurationFuzzer,
 		kubeadmNodeRegistrationOptionsFuzzer,
+		fileDiscoveryFuzzer,
+		hubControlPlaneComponentFuzzer,
+		hubLocalEtcdFuzzer,
 	}
 }
 
@@ -130,3 +133,24 @@ func kubeadmNodeRegistrationOptionsFuzzer(obj *bootstrapv1.NodeRegistrationOptio
 	// avoid round trip errors.
 	obj.ImagePullPolicy = ""
 }
+
+func fileDiscoveryFuzzer(obj *bootstrapv1.FileDiscovery, c fuzz.Continue) {
+	c.FuzzNoCustom(obj)
+
+	// FileDiscovery.KubeConfig does not exists in kubeadm, so setting it to nil in order to avoid v1beta1 --> upstream --> v1beta1 round trip errors.
+	obj.KubeConfig = nil
+}
+
+func hubControlPlaneComponentFuzzer(obj *bootstrapv1.ControlPlaneComponent, c fuzz.Continue) {
+	c.FuzzNoCustom(obj)
+
+	// ControlPlaneComponent.ExtraEnvs does not exists in kubeadm v1beta2, so setting it to nil in order to avoid v1beta1 --> upstream --> v1beta1 round trip errors.
+	obj.ExtraEnvs = nil
+}
+
+func hubLocalEtcdFuzzer(obj *bootstrapv1.LocalEtcd, c fuzz.Continue) {
+	c.FuzzNoCustom(obj)
+
+	// LocalEtcd.ExtraEnvs does not exists in kubeadm v1beta2, so setting it to nil in order to avoid v1beta1 --> upstream --> v1beta1 round trip errors.
+	obj.ExtraEnvs = nil
+}
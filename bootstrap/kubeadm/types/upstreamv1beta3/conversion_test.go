@@ -67,6 +67,9 @@ func fuzzFuncs(_ runtimeserializer.CodecFactory) []interface{} {
 		initConfigurationFuzzer,
 		joinConfigurationFuzzer,
 		joinControlPlanesFuzzer,
+		fileDiscoveryFuzzer,
+		hubControlPlaneComponentFuzzer,
+		hubLocalEtcdFuzzer,
 	}
 }
 
@@ -100,3 +103,24 @@ func joinConfigurationFuzzer(obj *JoinConfiguration, c fuzz.Continue) {
 	// JoinConfiguration.SkipPhases does not exists in v1alpha4, so setting it to empty string in order to avoid v1beta3 --> v1alpha4 --> v1beta3 round trip errors.
 	obj.SkipPhases = nil
 }
+
+func fileDiscoveryFuzzer(obj *bootstrapv1.FileDiscovery, c fuzz.Continue) {
+	c.FuzzNoCustom(obj)
+
+	// FileDiscovery.KubeConfig does not exists in kubeadm, so setting it to nil in order to avoid v1beta1 --> upstream --> v1beta1 round trip errors.
+	obj.KubeConfig = nil
+}
+
+func hubControlPlaneComponentFuzzer(obj *bootstrapv1.ControlPlaneComponent, c fuzz.Continue) {
+	c.FuzzNoCustom(obj)
+
+	// ControlPlaneComponent.ExtraEnvs does not exists in kubeadm v1beta3, so setting it to nil in order to avoid v1beta1 --> upstream --> v1beta1 round trip errors.
+	obj.ExtraEnvs = nil
+}
+
+func hubLocalEtcdFuzzer(obj *bootstrapv1.LocalEtcd, c fuzz.Continue) {
+	c.FuzzNoCustom(obj)
+
+	// LocalEtcd.ExtraEnvs does not exists in kubeadm v1beta3, so setting it to nil in order to avoid v1beta1 --> upstream --> v1beta1 round trip errors.
+	obj.ExtraEnvs = nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upstreamv1beta4
+
+import (
+	"sort"
+
+	apimachineryconversion "k8s.io/apimachinery/pkg/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+// ConvertTo converts this ClusterConfiguration to the Hub version (v1beta1).
+func (src *ClusterConfiguration) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*bootstrapv1.ClusterConfiguration)
+	return Convert_upstreamv1beta4_ClusterConfiguration_To_v1beta1_ClusterConfiguration(src, dst, nil)
+}
+
+// ConvertFrom converts from the ClusterConfiguration Hub version (v1beta1) to this version.
+func (dst *ClusterConfiguration) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*bootstrapv1.ClusterConfiguration)
+	return Convert_v1beta1_ClusterConfiguration_To_upstreamv1beta4_ClusterConfiguration(src, dst, nil)
+}
+
+// ConvertTo converts this InitConfiguration to the Hub version (v1beta1).
+func (src *InitConfiguration) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*bootstrapv1.InitConfiguration)
+	return Convert_upstreamv1beta4_InitConfiguration_To_v1beta1_InitConfiguration(src, dst, nil)
+}
+
+// ConvertFrom converts from the InitConfiguration Hub version (v1beta1) to this version.
+func (dst *InitConfiguration) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*bootstrapv1.InitConfiguration)
+	return Convert_v1beta1_InitConfiguration_To_upstreamv1beta4_InitConfiguration(src, dst, nil)
+}
+
+// ConvertTo converts this JoinConfiguration to the Hub version (v1beta1).
+func (src *JoinConfiguration) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*bootstrapv1.JoinConfiguration)
+	return Convert_upstreamv1beta4_JoinConfiguration_To_v1beta1_JoinConfiguration(src, dst, nil)
+}
+
+// ConvertFrom converts from the JoinConfiguration Hub version (v1beta1) to this version.
+func (dst *JoinConfiguration) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*bootstrapv1.JoinConfiguration)
+	return Convert_v1beta1_JoinConfiguration_To_upstreamv1beta4_JoinConfiguration(src, dst, nil)
+}
+
+func Convert_upstreamv1beta4_InitConfiguration_To_v1beta1_InitConfiguration(in *InitConfiguration, out *bootstrapv1.InitConfiguration, s apimachineryconversion.Scope) error {
+	// InitConfiguration.CertificateKey exists in v1beta4 types but not in bootstrapv1.InitConfiguration (Cluster API does not uses automatic copy certs). Ignoring when converting.
+	// InitConfiguration.Timeouts exists in v1beta4 types but not in bootstrapv1.InitConfiguration; the timeout for the control plane is managed
+	// via ClusterConfiguration.APIServer.TimeoutForControlPlane in Cluster API. Ignoring when converting.
+	return autoConvert_upstreamv1beta4_InitConfiguration_To_v1beta1_InitConfiguration(in, out, s)
+}
+
+func Convert_upstreamv1beta4_JoinConfiguration_To_v1beta1_JoinConfiguration(in *JoinConfiguration, out *bootstrapv1.JoinConfiguration, s apimachineryconversion.Scope) error {
+	if err := autoConvert_upstreamv1beta4_JoinConfiguration_To_v1beta1_JoinConfiguration(in, out, s); err != nil {
+		return err
+	}
+
+	// JoinConfiguration.Timeouts exists in v1beta4 types but not in bootstrapv1.JoinConfiguration; the discovery timeout
+	// is managed via JoinConfiguration.Discovery.Timeout in Cluster API, while other timeouts are ignored when converting.
+	if in.Timeouts != nil {
+		out.Discovery.Timeout = in.Timeouts.Discovery
+	}
+	return nil
+}
+
+func Convert_v1beta1_JoinConfiguration_To_upstreamv1beta4_JoinConfiguration(in *bootstrapv1.JoinConfiguration, out *JoinConfiguration, s apimachineryconversion.Scope) error {
+	if err := autoConvert_v1beta1_JoinConfiguration_To_upstreamv1beta4_JoinConfiguration(in, out, s); err != nil {
+		return err
+	}
+
+	// JoinConfiguration.Discovery.Timeout has been moved to JoinConfiguration.Timeouts.Discovery in v1beta4.
+	if in.Discovery.Timeout != nil {
+		if out.Timeouts == nil {
+			out.Timeouts = &Timeouts{}
+		}
+		out.Timeouts.Discovery = in.Discovery.Timeout
+	}
+	return nil
+}
+
+func Convert_v1beta1_Discovery_To_upstreamv1beta4_Discovery(in *bootstrapv1.Discovery, out *Discovery, s apimachineryconversion.Scope) error {
+	// Discovery.Timeout does not exist in v1beta4 types; it is converted to JoinConfiguration.Timeouts.Discovery when converting the JoinConfiguration.
+	return autoConvert_v1beta1_Discovery_To_upstreamv1beta4_Discovery(in, out, s)
+}
+
+func Convert_v1beta1_APIServer_To_upstreamv1beta4_APIServer(in *bootstrapv1.APIServer, out *APIServer, s apimachineryconversion.Scope) error {
+	// APIServer.TimeoutForControlPlane does not exist in v1beta4 types; it is converted to InitConfiguration.Timeouts.ControlPlaneComponentHealthCheck
+	// and JoinConfiguration.Timeouts.ControlPlaneComponentHealthCheck when marshaling those objects for kubeadm.
+	return autoConvert_v1beta1_APIServer_To_upstreamv1beta4_APIServer(in, out, s)
+}
+
+func Convert_upstreamv1beta4_ControlPlaneComponent_To_v1beta1_ControlPlaneComponent(in *ControlPlaneComponent, out *bootstrapv1.ControlPlaneComponent, s apimachineryconversion.Scope) error {
+	out.ExtraArgs = convertFromArgs(in.ExtraArgs)
+	return autoConvert_upstreamv1beta4_ControlPlaneComponent_To_v1beta1_ControlPlaneComponent(in, out, s)
+}
+
+func Convert_v1beta1_ControlPlaneComponent_To_upstreamv1beta4_ControlPlaneComponent(in *bootstrapv1.ControlPlaneComponent, out *ControlPlaneComponent, s apimachineryconversion.Scope) error {
+	out.ExtraArgs = convertToArgs(in.ExtraArgs)
+	return autoConvert_v1beta1_ControlPlaneComponent_To_upstreamv1beta4_ControlPlaneComponent(in, out, s)
+}
+
+func Convert_upstreamv1beta4_LocalEtcd_To_v1beta1_LocalEtcd(in *LocalEtcd, out *bootstrapv1.LocalEtcd, s apimachineryconversion.Scope) error {
+	out.ExtraArgs = convertFromArgs(in.ExtraArgs)
+	return autoConvert_upstreamv1beta4_LocalEtcd_To_v1beta1_LocalEtcd(in, out, s)
+}
+
+func Convert_v1beta1_LocalEtcd_To_upstreamv1beta4_LocalEtcd(in *bootstrapv1.LocalEtcd, out *LocalEtcd, s apimachineryconversion.Scope) error {
+	out.ExtraArgs = convertToArgs(in.ExtraArgs)
+	return autoConvert_v1beta1_LocalEtcd_To_upstreamv1beta4_LocalEtcd(in, out, s)
+}
+
+func Convert_upstreamv1beta4_NodeRegistrationOptions_To_v1beta1_NodeRegistrationOptions(in *NodeRegistrationOptions, out *bootstrapv1.NodeRegistrationOptions, s apimachineryconversion.Scope) error {
+	out.KubeletExtraArgs = convertFromArgs(in.KubeletExtraArgs)
+	return autoConvert_upstreamv1beta4_NodeRegistrationOptions_To_v1beta1_NodeRegistrationOptions(in, out, s)
+}
+
+func Convert_v1beta1_NodeRegistrationOptions_To_upstreamv1beta4_NodeRegistrationOptions(in *bootstrapv1.NodeRegistrationOptions, out *NodeRegistrationOptions, s apimachineryconversion.Scope) error {
+	out.KubeletExtraArgs = convertToArgs(in.KubeletExtraArgs)
+	return autoConvert_v1beta1_NodeRegistrationOptions_To_upstreamv1beta4_NodeRegistrationOptions(in, out, s)
+}
+
+func Convert_upstreamv1beta4_JoinControlPlane_To_v1beta1_JoinControlPlane(in *JoinControlPlane, out *bootstrapv1.JoinControlPlane, s apimachineryconversion.Scope) error {
+	// JoinControlPlane.CertificateKey exists in v1beta4 types but not in bootstrapv1.JoinControlPlane (Cluster API does not uses automatic copy certs). Ignoring when converting.
+	return autoConvert_upstreamv1beta4_JoinControlPlane_To_v1beta1_JoinControlPlane(in, out, s)
+}
+
+func Convert_v1beta1_FileDiscovery_To_upstreamv1beta4_FileDiscovery(in *bootstrapv1.FileDiscovery, out *FileDiscovery, s apimachineryconversion.Scope) error {
+	// JoinConfiguration.Discovery.File.KubeConfig does not exist in kubeadm because it's internal to Cluster API, dropping those info.
+	return autoConvert_v1beta1_FileDiscovery_To_upstreamv1beta4_FileDiscovery(in, out, s)
+}
+
+// convertToArgs takes a argument map and converts it to a slice of arguments, sorted by name.
+func convertToArgs(in map[string]string) []Arg {
+	if len(in) == 0 {
+		return nil
+	}
+	args := make([]Arg, 0, len(in))
+	for name, value := range in {
+		args = append(args, Arg{Name: name, Value: value})
+	}
+	sort.Slice(args, func(i, j int) bool {
+		return args[i].Name < args[j].Name
+	})
+	return args
+}
+
+// convertFromArgs takes a slice of arguments and returns an argument map.
+// Duplicate argument keys will be de-duped, where later keys will take precedence.
+func convertFromArgs(in []Arg) map[string]string {
+	if len(in) == 0 {
+		return nil
+	}
+	args := make(map[string]string, len(in))
+	for _, arg := range in {
+		args[arg.Name] = arg.Value
+	}
+	return args
+}
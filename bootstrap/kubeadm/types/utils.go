@@ -18,25 +18,31 @@ limitations under the License.
 package utils
 
 import (
+	"fmt"
+
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
 
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/upstreamv1beta2"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/upstreamv1beta3"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/upstreamv1beta4"
 	"sigs.k8s.io/cluster-api/util/version"
 )
 
 var (
 	v1beta2KubeadmVersion = semver.MustParse("1.15.0")
 	v1beta3KubeadmVersion = semver.MustParse("1.22.0")
+	v1beta4KubeadmVersion = semver.MustParse("1.31.0")
 
 	clusterConfigurationVersionTypeMap = map[schema.GroupVersion]conversion.Convertible{
+		upstreamv1beta4.GroupVersion: &upstreamv1beta4.ClusterConfiguration{},
 		upstreamv1beta3.GroupVersion: &upstreamv1beta3.ClusterConfiguration{},
 		upstreamv1beta2.GroupVersion: &upstreamv1beta2.ClusterConfiguration{},
 	}
@@ -47,11 +53,13 @@ var (
 	}
 
 	initConfigurationVersionTypeMap = map[schema.GroupVersion]conversion.Convertible{
+		upstreamv1beta4.GroupVersion: &upstreamv1beta4.InitConfiguration{},
 		upstreamv1beta3.GroupVersion: &upstreamv1beta3.InitConfiguration{},
 		upstreamv1beta2.GroupVersion: &upstreamv1beta2.InitConfiguration{},
 	}
 
 	joinConfigurationVersionTypeMap = map[schema.GroupVersion]conversion.Convertible{
+		upstreamv1beta4.GroupVersion: &upstreamv1beta4.JoinConfiguration{},
 		upstreamv1beta3.GroupVersion: &upstreamv1beta3.JoinConfiguration{},
 		upstreamv1beta2.GroupVersion: &upstreamv1beta2.JoinConfiguration{},
 	}
@@ -65,14 +73,17 @@ func KubeVersionToKubeadmAPIGroupVersion(v semver.Version) (schema.GroupVersion,
 	case version.Compare(v, v1beta3KubeadmVersion, version.WithoutPreReleases()) < 0:
 		// NOTE: All the Kubernetes version >= v1.15 and < v1.22 should use the kubeadm API version v1beta2
 		return upstreamv1beta2.GroupVersion, nil
+	case version.Compare(v, v1beta4KubeadmVersion, version.WithoutPreReleases()) < 0:
+		// NOTE: All the Kubernetes version >= v1.22 and < v1.31 should use the kubeadm API version v1beta3
+		return upstreamv1beta3.GroupVersion, nil
 	default:
-		// NOTE: All the Kubernetes version greater or equal to v1.22 should use the kubeadm API version v1beta3.
-		// Also future Kubernetes versions (not yet released at the time of writing this code) are going to use v1beta3,
-		// no matter if kubeadm API versions newer than v1beta3 could be introduced by those release.
-		// This is acceptable because v1beta3 will be supported by kubeadm until the deprecation cycle completes
+		// NOTE: All the Kubernetes version greater or equal to v1.31 should use the kubeadm API version v1beta4.
+		// Also future Kubernetes versions (not yet released at the time of writing this code) are going to use v1beta4,
+		// no matter if kubeadm API versions newer than v1beta4 could be introduced by those release.
+		// This is acceptable because v1beta4 will be supported by kubeadm until the deprecation cycle completes
 		// (9 months minimum after the deprecation date, not yet announced now); this gives Cluster API project time to
 		// introduce support for newer releases without blocking users to deploy newer version of Kubernetes.
-		return upstreamv1beta3.GroupVersion, nil
+		return upstreamv1beta4.GroupVersion, nil
 	}
 }
 
@@ -80,9 +91,47 @@ func KubeVersionToKubeadmAPIGroupVersion(v semver.Version) (schema.GroupVersion,
 // for the given Kubernetes Version.
 // NOTE: This assumes Kubernetes Version equals to kubeadm version.
 func MarshalClusterConfigurationForVersion(obj *bootstrapv1.ClusterConfiguration, version semver.Version) (string, error) {
+	if errs := ValidateClusterConfigurationForVersion(obj, version, field.NewPath("clusterConfiguration")); len(errs) > 0 {
+		return "", errs.ToAggregate()
+	}
 	return marshalForVersion(obj, version, clusterConfigurationVersionTypeMap)
 }
 
+// ValidateClusterConfigurationForVersion checks that the Cluster API ClusterConfiguration type does not use
+// fields which can't be represented in the kubeadm API type for the given Kubernetes Version.
+func ValidateClusterConfigurationForVersion(obj *bootstrapv1.ClusterConfiguration, v semver.Version, fldPath *field.Path) field.ErrorList {
+	if obj == nil {
+		return nil
+	}
+
+	if version.Compare(v, v1beta4KubeadmVersion, version.WithoutPreReleases()) >= 0 {
+		return nil
+	}
+
+	extraEnvs := []struct {
+		path *field.Path
+		envs []bootstrapv1.EnvVar
+	}{
+		{fldPath.Child("apiServer", "extraEnvs"), obj.APIServer.ExtraEnvs},
+		{fldPath.Child("controllerManager", "extraEnvs"), obj.ControllerManager.ExtraEnvs},
+		{fldPath.Child("scheduler", "extraEnvs"), obj.Scheduler.ExtraEnvs},
+	}
+	if obj.Etcd.Local != nil {
+		extraEnvs = append(extraEnvs, struct {
+			path *field.Path
+			envs []bootstrapv1.EnvVar
+		}{fldPath.Child("etcd", "local", "extraEnvs"), obj.Etcd.Local.ExtraEnvs})
+	}
+
+	var allErrs field.ErrorList
+	for _, e := range extraEnvs {
+		if len(e.envs) > 0 {
+			allErrs = append(allErrs, field.Invalid(e.path, e.envs, fmt.Sprintf("can be used only with Kubernetes version >= v%s, got v%s", v1beta4KubeadmVersion, v)))
+		}
+	}
+	return allErrs
+}
+
 // MarshalClusterStatusForVersion converts a Cluster API ClusterStatus type to the kubeadm API type
 // for the given Kubernetes Version.
 // NOTE: This assumes Kubernetes Version equals to kubeadm version.
@@ -92,19 +141,47 @@ func MarshalClusterStatusForVersion(obj *bootstrapv1.ClusterStatus, version semv
 
 // MarshalInitConfigurationForVersion converts a Cluster API InitConfiguration type to the kubeadm API type
 // for the given Kubernetes Version.
+// The ClusterConfiguration is used to set the fields which have been moved from ClusterConfiguration to
+// InitConfiguration in newer kubeadm API versions, e.g. the timeout for the control plane in v1beta4; it can be nil.
 // NOTE: This assumes Kubernetes Version equals to kubeadm version.
-func MarshalInitConfigurationForVersion(obj *bootstrapv1.InitConfiguration, version semver.Version) (string, error) {
-	return marshalForVersion(obj, version, initConfigurationVersionTypeMap)
+func MarshalInitConfigurationForVersion(clusterConfiguration *bootstrapv1.ClusterConfiguration, obj *bootstrapv1.InitConfiguration, version semver.Version) (string, error) {
+	return marshalForVersion(obj, version, initConfigurationVersionTypeMap, controlPlaneTimeout(clusterConfiguration))
 }
 
 // MarshalJoinConfigurationForVersion converts a Cluster API JoinConfiguration type to the kubeadm API type
 // for the given Kubernetes Version.
+// The ClusterConfiguration is used to set the fields which have been moved from ClusterConfiguration to
+// JoinConfiguration in newer kubeadm API versions, e.g. the timeout for the control plane in v1beta4; it can be nil.
 // NOTE: This assumes Kubernetes Version equals to kubeadm version.
-func MarshalJoinConfigurationForVersion(obj *bootstrapv1.JoinConfiguration, version semver.Version) (string, error) {
-	return marshalForVersion(obj, version, joinConfigurationVersionTypeMap)
+func MarshalJoinConfigurationForVersion(clusterConfiguration *bootstrapv1.ClusterConfiguration, obj *bootstrapv1.JoinConfiguration, version semver.Version) (string, error) {
+	return marshalForVersion(obj, version, joinConfigurationVersionTypeMap, controlPlaneTimeout(clusterConfiguration))
 }
 
-func marshalForVersion(obj conversion.Hub, version semver.Version, kubeadmObjVersionTypeMap map[schema.GroupVersion]conversion.Convertible) (string, error) {
+// controlPlaneTimeout returns a function setting ClusterConfiguration.APIServer.TimeoutForControlPlane
+// into the timeouts of a kubeadm v1beta4 InitConfiguration or JoinConfiguration.
+func controlPlaneTimeout(clusterConfiguration *bootstrapv1.ClusterConfiguration) func(conversion.Convertible) {
+	return func(obj conversion.Convertible) {
+		if clusterConfiguration == nil || clusterConfiguration.APIServer.TimeoutForControlPlane == nil {
+			return
+		}
+
+		var timeouts **upstreamv1beta4.Timeouts
+		switch obj := obj.(type) {
+		case *upstreamv1beta4.InitConfiguration:
+			timeouts = &obj.Timeouts
+		case *upstreamv1beta4.JoinConfiguration:
+			timeouts = &obj.Timeouts
+		default:
+			return
+		}
+		if *timeouts == nil {
+			*timeouts = &upstreamv1beta4.Timeouts{}
+		}
+		(*timeouts).ControlPlaneComponentHealthCheck = clusterConfiguration.APIServer.TimeoutForControlPlane.DeepCopy()
+	}
+}
+
+func marshalForVersion(obj conversion.Hub, version semver.Version, kubeadmObjVersionTypeMap map[schema.GroupVersion]conversion.Convertible, mutators ...func(conversion.Convertible)) (string, error) {
 	kubeadmAPIGroupVersion, err := KubeVersionToKubeadmAPIGroupVersion(version)
 	if err != nil {
 		return "", err
@@ -119,6 +196,9 @@ func marshalForVersion(obj conversion.Hub, version semver.Version, kubeadmObjVer
 	if err := targetKubeadmObj.ConvertFrom(obj); err != nil {
 		return "", errors.Wrapf(err, "failed to convert to KubeadmAPI type for version %s", kubeadmAPIGroupVersion)
 	}
+	for _, mutate := range mutators {
+		mutate(targetKubeadmObj)
+	}
 
 	codecs, err := getCodecsFor(kubeadmAPIGroupVersion, targetKubeadmObj)
 	if err != nil {
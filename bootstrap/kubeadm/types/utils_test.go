@@ -18,15 +18,20 @@ package utils
 
 import (
 	"testing"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/upstreamv1beta2"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/upstreamv1beta3"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/upstreamv1beta4"
 )
 
 func TestKubeVersionToKubeadmAPIGroupVersion(t *testing.T) {
@@ -102,12 +107,28 @@ func TestKubeVersionToKubeadmAPIGroupVersion(t *testing.T) {
 			want:    upstreamv1beta3.GroupVersion,
 			wantErr: false,
 		},
+		{
+			name: "pass with minimum kubernetes alpha version for kubeadm API v1beta4",
+			args: args{
+				version: semver.MustParse("1.31.0-alpha.0.734+ba502ee555924a"),
+			},
+			want:    upstreamv1beta4.GroupVersion,
+			wantErr: false,
+		},
+		{
+			name: "pass with minimum kubernetes version for kubeadm API v1beta4",
+			args: args{
+				version: semver.MustParse("1.31.0"),
+			},
+			want:    upstreamv1beta4.GroupVersion,
+			wantErr: false,
+		},
 		{
 			name: "pass with future kubernetes version",
 			args: args{
 				version: semver.MustParse("99.99.99"),
 			},
-			want:    upstreamv1beta3.GroupVersion,
+			want:    upstreamv1beta4.GroupVersion,
 			wantErr: false,
 		},
 	}
@@ -169,6 +190,51 @@ func TestMarshalClusterConfigurationForVersion(t *testing.T) {
 				"scheduler: {}\n",
 			wantErr: false,
 		},
+		{
+			name: "Generates a v1beta4 kubeadm configuration",
+			args: args{
+				capiObj: &bootstrapv1.ClusterConfiguration{
+					APIServer: bootstrapv1.APIServer{
+						ControlPlaneComponent: bootstrapv1.ControlPlaneComponent{
+							ExtraArgs: map[string]string{"v": "2", "audit-log-maxage": "10"},
+							ExtraEnvs: []bootstrapv1.EnvVar{{EnvVar: corev1.EnvVar{Name: "HTTP_PROXY", Value: "http://proxy"}}},
+						},
+					},
+				},
+				version: semver.MustParse("1.31.0"),
+			},
+			want: "apiServer:\n" +
+				"  extraArgs:\n" +
+				"  - name: audit-log-maxage\n" +
+				"    value: \"10\"\n" +
+				"  - name: v\n" +
+				"    value: \"2\"\n" +
+				"  extraEnvs:\n" +
+				"  - name: HTTP_PROXY\n" +
+				"    value: http://proxy\n" +
+				"apiVersion: kubeadm.k8s.io/v1beta4\n" + "" +
+				"controllerManager: {}\n" +
+				"dns: {}\n" +
+				"etcd: {}\n" +
+				"kind: ClusterConfiguration\n" +
+				"networking: {}\n" +
+				"scheduler: {}\n",
+			wantErr: false,
+		},
+		{
+			name: "Fails generating a v1beta3 kubeadm configuration with extraEnvs",
+			args: args{
+				capiObj: &bootstrapv1.ClusterConfiguration{
+					Etcd: bootstrapv1.Etcd{
+						Local: &bootstrapv1.LocalEtcd{
+							ExtraEnvs: []bootstrapv1.EnvVar{{EnvVar: corev1.EnvVar{Name: "HTTP_PROXY", Value: "http://proxy"}}},
+						},
+					},
+				},
+				version: semver.MustParse("1.30.0"),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -185,6 +251,35 @@ func TestMarshalClusterConfigurationForVersion(t *testing.T) {
 	}
 }
 
+func TestValidateClusterConfigurationForVersion(t *testing.T) {
+	extraEnvs := []bootstrapv1.EnvVar{{EnvVar: corev1.EnvVar{Name: "HTTP_PROXY", Value: "http://proxy"}}}
+	clusterConfiguration := &bootstrapv1.ClusterConfiguration{
+		APIServer: bootstrapv1.APIServer{
+			ControlPlaneComponent: bootstrapv1.ControlPlaneComponent{ExtraEnvs: extraEnvs},
+		},
+		Etcd: bootstrapv1.Etcd{
+			Local: &bootstrapv1.LocalEtcd{ExtraEnvs: extraEnvs},
+		},
+	}
+
+	t.Run("reports the fields which are not supported by the kubeadm API version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		errs := ValidateClusterConfigurationForVersion(clusterConfiguration, semver.MustParse("1.30.0"), field.NewPath("spec", "clusterConfiguration"))
+		g.Expect(errs).To(HaveLen(2))
+		g.Expect(errs[0].Field).To(Equal("spec.clusterConfiguration.apiServer.extraEnvs"))
+		g.Expect(errs[0].BadValue).To(Equal(extraEnvs))
+		g.Expect(errs[1].Field).To(Equal("spec.clusterConfiguration.etcd.local.extraEnvs"))
+		g.Expect(errs[1].BadValue).To(Equal(extraEnvs))
+	})
+
+	t.Run("accepts the fields which are supported by the kubeadm API version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ValidateClusterConfigurationForVersion(clusterConfiguration, semver.MustParse("1.31.0"), field.NewPath("spec", "clusterConfiguration"))).To(BeEmpty())
+	})
+}
+
 func TestMarshalClusterStatusForVersion(t *testing.T) {
 	type args struct {
 		capiObj *bootstrapv1.ClusterStatus
@@ -233,8 +328,9 @@ func TestMarshalClusterStatusForVersion(t *testing.T) {
 
 func TestMarshalInitConfigurationForVersion(t *testing.T) {
 	type args struct {
-		capiObj *bootstrapv1.InitConfiguration
-		version semver.Version
+		clusterConfiguration *bootstrapv1.ClusterConfiguration
+		capiObj              *bootstrapv1.InitConfiguration
+		version              semver.Version
 	}
 	tests := []struct {
 		name    string
@@ -279,12 +375,42 @@ func TestMarshalInitConfigurationForVersion(t *testing.T) {
 				"  taints: null\n",
 			wantErr: false,
 		},
+		{
+			name: "Generates a v1beta4 kubeadm configuration",
+			args: args{
+				clusterConfiguration: &bootstrapv1.ClusterConfiguration{
+					APIServer: bootstrapv1.APIServer{
+						TimeoutForControlPlane: &metav1.Duration{Duration: 5 * time.Minute},
+					},
+				},
+				capiObj: &bootstrapv1.InitConfiguration{
+					NodeRegistration: bootstrapv1.NodeRegistrationOptions{
+						IgnorePreflightErrors: []string{"some-preflight-check"},
+						KubeletExtraArgs:      map[string]string{"v": "2"},
+					},
+				},
+				version: semver.MustParse("1.31.0"),
+			},
+			want: "apiVersion: kubeadm.k8s.io/v1beta4\n" +
+				"kind: InitConfiguration\n" +
+				"localAPIEndpoint: {}\n" +
+				"nodeRegistration:\n" +
+				"  ignorePreflightErrors:\n" +
+				"  - some-preflight-check\n" +
+				"  kubeletExtraArgs:\n" +
+				"  - name: v\n" +
+				"    value: \"2\"\n" +
+				"  taints: null\n" +
+				"timeouts:\n" +
+				"  controlPlaneComponentHealthCheck: 5m0s\n",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			got, err := MarshalInitConfigurationForVersion(tt.args.capiObj, tt.args.version)
+			got, err := MarshalInitConfigurationForVersion(tt.args.clusterConfiguration, tt.args.capiObj, tt.args.version)
 			if tt.wantErr {
 				g.Expect(err).To(HaveOccurred())
 				return
@@ -297,8 +423,9 @@ func TestMarshalInitConfigurationForVersion(t *testing.T) {
 
 func TestMarshalJoinConfigurationForVersion(t *testing.T) {
 	type args struct {
-		capiObj *bootstrapv1.JoinConfiguration
-		version semver.Version
+		clusterConfiguration *bootstrapv1.ClusterConfiguration
+		capiObj              *bootstrapv1.JoinConfiguration
+		version              semver.Version
 	}
 	tests := []struct {
 		name    string
@@ -343,12 +470,36 @@ func TestMarshalJoinConfigurationForVersion(t *testing.T) {
 				"  taints: null\n",
 			wantErr: false,
 		},
+		{
+			name: "Generates a v1beta4 kubeadm configuration",
+			args: args{
+				capiObj: &bootstrapv1.JoinConfiguration{
+					NodeRegistration: bootstrapv1.NodeRegistrationOptions{
+						IgnorePreflightErrors: []string{"some-preflight-check"},
+					},
+					Discovery: bootstrapv1.Discovery{
+						Timeout: &metav1.Duration{Duration: 5 * time.Minute},
+					},
+				},
+				version: semver.MustParse("1.31.0"),
+			},
+			want: "apiVersion: kubeadm.k8s.io/v1beta4\n" + "" +
+				"discovery: {}\n" +
+				"kind: JoinConfiguration\n" +
+				"nodeRegistration:\n" +
+				"  ignorePreflightErrors:\n" +
+				"  - some-preflight-check\n" +
+				"  taints: null\n" +
+				"timeouts:\n" +
+				"  discovery: 5m0s\n",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			got, err := MarshalJoinConfigurationForVersion(tt.args.capiObj, tt.args.version)
+			got, err := MarshalJoinConfigurationForVersion(tt.args.clusterConfiguration, tt.args.capiObj, tt.args.version)
 			if tt.wantErr {
 				g.Expect(err).To(HaveOccurred())
 				return
@@ -125,6 +125,26 @@ func (co ConfigOwner) KubernetesVersion() string {
 	return version
 }
 
+// InfrastructureRef returns the reference to the infrastructure object of the config owner object,
+// i.e. the InfraMachine for a Machine and the InfraMachinePool for a MachinePool.
+func (co ConfigOwner) InfrastructureRef() *corev1.ObjectReference {
+	fields := []string{"spec", "infrastructureRef"}
+	if co.IsMachinePool() {
+		fields = []string{"spec", "template", "spec", "infrastructureRef"}
+	}
+
+	ref, found, err := unstructured.NestedMap(co.Object, fields...)
+	if err != nil || !found {
+		return nil
+	}
+
+	objRef := &corev1.ObjectReference{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(ref, objRef); err != nil {
+		return nil
+	}
+	return objRef
+}
+
 // GetConfigOwner returns the Unstructured object owning the current resource
 // using the uncached unstructured client. For performance-sensitive uses,
 // consider GetTypedConfigOwner.
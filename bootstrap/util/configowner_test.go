@@ -372,3 +372,52 @@ func TestHasNodeRefs(t *testing.T) {
 		}
 	})
 }
+
+func TestInfrastructureRef(t *testing.T) {
+	infraRef := corev1.ObjectReference{
+		APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		Kind:       "GenericInfrastructureMachine",
+		Name:       "infra-name",
+	}
+
+	t.Run("should return the infrastructureRef of a Machine", func(t *testing.T) {
+		g := NewWithT(t)
+		machine := &clusterv1.Machine{
+			TypeMeta: metav1.TypeMeta{
+				Kind: "Machine",
+			},
+			Spec: clusterv1.MachineSpec{
+				InfrastructureRef: infraRef,
+			},
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&machine)
+		g.Expect(err).ToNot(HaveOccurred())
+		unstructuredOwner := unstructured.Unstructured{}
+		unstructuredOwner.SetUnstructuredContent(content)
+		co := ConfigOwner{&unstructuredOwner}
+
+		g.Expect(co.InfrastructureRef()).To(Equal(&infraRef))
+	})
+	t.Run("should return the infrastructureRef of a MachinePool", func(t *testing.T) {
+		g := NewWithT(t)
+		machinePool := &expv1.MachinePool{
+			TypeMeta: metav1.TypeMeta{
+				Kind: "MachinePool",
+			},
+			Spec: expv1.MachinePoolSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						InfrastructureRef: infraRef,
+					},
+				},
+			},
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&machinePool)
+		g.Expect(err).ToNot(HaveOccurred())
+		unstructuredOwner := unstructured.Unstructured{}
+		unstructuredOwner.SetUnstructuredContent(content)
+		co := ConfigOwner{&unstructuredOwner}
+
+		g.Expect(co.InfrastructureRef()).To(Equal(&infraRef))
+	})
+}
@@ -18,11 +18,16 @@ package client
 
 import (
 	"context"
+	"io"
+
+	"k8s.io/client-go/rest"
 
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/alpha"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/ipam"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/machine"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
 )
@@ -72,6 +77,30 @@ type Client interface {
 	// DescribeCluster returns the object tree representing the status of a Cluster API cluster.
 	DescribeCluster(ctx context.Context, options DescribeClusterOptions) (*tree.ObjectTree, error)
 
+	// GetIPPools returns the utilization of the InClusterIPPools of the management cluster.
+	GetIPPools(ctx context.Context, options GetIPPoolsOptions) ([]ipam.PoolSummary, error)
+
+	// DescribeIPPool returns the state of an InClusterIPPool, including the claims it fulfills and the conflicting or stale objects.
+	DescribeIPPool(ctx context.Context, options DescribeIPPoolOptions) (*ipam.PoolDescription, error)
+
+	// DescribeMachine returns the status of a Machine and of its infrastructure machine.
+	DescribeMachine(ctx context.Context, options DescribeMachineOptions) (*machine.Description, error)
+
+	// GetMachineLogs returns a stream of the console logs of a Machine, read from the URL exposed by the infrastructure provider.
+	GetMachineLogs(ctx context.Context, options GetMachineLogsOptions) (io.ReadCloser, error)
+
+	// SupportBundle writes a support bundle with the information required to troubleshoot a workload cluster.
+	SupportBundle(ctx context.Context, options SupportBundleOptions) error
+
+	// EnterMaintenance puts a namespace of the management cluster in maintenance, pausing the reconciliation of the Cluster API objects in it.
+	EnterMaintenance(ctx context.Context, options MaintenanceOptions) error
+
+	// ExitMaintenance ends the maintenance of a namespace of the management cluster, resuming the reconciliation of the Cluster API objects in it.
+	ExitMaintenance(ctx context.Context, options MaintenanceOptions) error
+
+	// MaintenanceStatus returns the maintenance status of a namespace of the management cluster.
+	MaintenanceStatus(ctx context.Context, options MaintenanceOptions) (*cluster.MaintenanceStatus, error)
+
 	// AlphaClient is an Interface for alpha features in clusterctl
 	AlphaClient
 }
@@ -106,6 +135,7 @@ type clusterctlClient struct {
 	repositoryClientFactory RepositoryClientFactory
 	clusterClientFactory    ClusterClientFactory
 	alphaClient             alpha.Client
+	warningHandler          rest.WarningHandler
 }
 
 // RepositoryClientFactoryInput represents the inputs required by the factory.
@@ -155,6 +185,14 @@ func InjectClusterClientFactory(factory ClusterClientFactory) Option {
 	}
 }
 
+// InjectWarningHandler sets the handler for the warnings returned by the API server of the management cluster,
+// e.g. by admission webhooks; it is ignored if a ClusterClientFactory is injected.
+func InjectWarningHandler(handler rest.WarningHandler) Option {
+	return func(c *clusterctlClient) {
+		c.warningHandler = handler
+	}
+}
+
 // New returns a configClient.
 func New(ctx context.Context, path string, options ...Option) (Client, error) {
 	return newClusterctlClient(ctx, path, options...)
@@ -183,7 +221,7 @@ func newClusterctlClient(ctx context.Context, path string, options ...Option) (*
 
 	// if there is an injected ClusterFactory, use it, otherwise use a default one.
 	if client.clusterClientFactory == nil {
-		client.clusterClientFactory = defaultClusterFactory(client.configClient)
+		client.clusterClientFactory = defaultClusterFactory(client.configClient, client.warningHandler)
 	}
 
 	// if there is an injected alphaClient, use it, otherwise use a default one.
@@ -208,13 +246,14 @@ func defaultRepositoryFactory(configClient config.Client) RepositoryClientFactor
 }
 
 // defaultClusterFactory is a ClusterClientFactory func the uses the default client provided by the cluster low level library.
-func defaultClusterFactory(configClient config.Client) ClusterClientFactory {
+func defaultClusterFactory(configClient config.Client, warningHandler rest.WarningHandler) ClusterClientFactory {
 	return func(input ClusterClientFactoryInput) (cluster.Client, error) {
 		return cluster.New(
 			// Kubeconfig is a type alias to cluster.Kubeconfig
 			cluster.Kubeconfig(input.Kubeconfig),
 			configClient,
 			cluster.InjectYamlProcessor(input.Processor),
+			cluster.InjectWarningHandler(warningHandler),
 		), nil
 	}
 }
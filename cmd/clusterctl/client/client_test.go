@@ -19,6 +19,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 
@@ -31,6 +32,8 @@ import (
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/ipam"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/machine"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
 	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
@@ -135,6 +138,38 @@ func (f fakeClient) DescribeCluster(ctx context.Context, options DescribeCluster
 	return f.internalClient.DescribeCluster(ctx, options)
 }
 
+func (f fakeClient) GetIPPools(ctx context.Context, options GetIPPoolsOptions) ([]ipam.PoolSummary, error) {
+	return f.internalClient.GetIPPools(ctx, options)
+}
+
+func (f fakeClient) DescribeIPPool(ctx context.Context, options DescribeIPPoolOptions) (*ipam.PoolDescription, error) {
+	return f.internalClient.DescribeIPPool(ctx, options)
+}
+
+func (f fakeClient) DescribeMachine(ctx context.Context, options DescribeMachineOptions) (*machine.Description, error) {
+	return f.internalClient.DescribeMachine(ctx, options)
+}
+
+func (f fakeClient) GetMachineLogs(ctx context.Context, options GetMachineLogsOptions) (io.ReadCloser, error) {
+	return f.internalClient.GetMachineLogs(ctx, options)
+}
+
+func (f fakeClient) SupportBundle(ctx context.Context, options SupportBundleOptions) error {
+	return f.internalClient.SupportBundle(ctx, options)
+}
+
+func (f fakeClient) EnterMaintenance(ctx context.Context, options MaintenanceOptions) error {
+	return f.internalClient.EnterMaintenance(ctx, options)
+}
+
+func (f fakeClient) ExitMaintenance(ctx context.Context, options MaintenanceOptions) error {
+	return f.internalClient.ExitMaintenance(ctx, options)
+}
+
+func (f fakeClient) MaintenanceStatus(ctx context.Context, options MaintenanceOptions) (*cluster.MaintenanceStatus, error) {
+	return f.internalClient.MaintenanceStatus(ctx, options)
+}
+
 func (f fakeClient) RolloutPause(ctx context.Context, options RolloutPauseOptions) error {
 	return f.internalClient.RolloutPause(ctx, options)
 }
@@ -325,6 +360,14 @@ func (f *fakeClusterClient) Topology() cluster.TopologyClient {
 	return f.internalclient.Topology()
 }
 
+func (f *fakeClusterClient) SupportBundle() cluster.SupportBundleClient {
+	return f.internalclient.SupportBundle()
+}
+
+func (f *fakeClusterClient) Maintenance() cluster.MaintenanceClient {
+	return f.internalclient.Maintenance()
+}
+
 func (f *fakeClusterClient) WithObjs(objs ...client.Object) *fakeClusterClient {
 	f.fakeProxy.WithObjs(objs...)
 	return f
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
@@ -82,6 +83,12 @@ type Client interface {
 
 	// Topology returns a TopologyClient that can be used for performing dry run executions of the topology reconciler.
 	Topology() TopologyClient
+
+	// SupportBundle returns a SupportBundleClient that can be used for collecting the information required to troubleshoot a workload cluster.
+	SupportBundle() SupportBundleClient
+
+	// Maintenance returns a MaintenanceClient that can be used for pausing the reconciliation of the Cluster API objects in a namespace.
+	Maintenance() MaintenanceClient
 }
 
 // PollImmediateWaiter tries a condition func until it returns true, an error, or the timeout is reached.
@@ -95,6 +102,7 @@ type clusterClient struct {
 	repositoryClientFactory RepositoryClientFactory
 	pollImmediateWaiter     PollImmediateWaiter
 	processor               yaml.Processor
+	warningHandler          rest.WarningHandler
 }
 
 // RepositoryClientFactory defines a function that returns a new repository.Client.
@@ -147,6 +155,14 @@ func (c *clusterClient) Topology() TopologyClient {
 	return newTopologyClient(c.proxy, c.ProviderInventory())
 }
 
+func (c *clusterClient) SupportBundle() SupportBundleClient {
+	return newSupportBundleClient(c.proxy, c.ProviderInventory())
+}
+
+func (c *clusterClient) Maintenance() MaintenanceClient {
+	return newMaintenanceClient(c.proxy)
+}
+
 // Option is a configuration option supplied to New.
 type Option func(*clusterClient)
 
@@ -183,6 +199,14 @@ func InjectYamlProcessor(p yaml.Processor) Option {
 	}
 }
 
+// InjectWarningHandler sets the handler for the warnings returned by the API server of the management cluster,
+// e.g. by admission webhooks; it is ignored if a proxy is injected.
+func InjectWarningHandler(handler rest.WarningHandler) Option {
+	return func(c *clusterClient) {
+		c.warningHandler = handler
+	}
+}
+
 // New returns a cluster.Client.
 func New(kubeconfig Kubeconfig, configClient config.Client, options ...Option) Client {
 	return newClusterClient(kubeconfig, configClient, options...)
@@ -200,7 +224,7 @@ func newClusterClient(kubeconfig Kubeconfig, configClient config.Client, options
 
 	// if there is an injected proxy, use it, otherwise use a default one
 	if client.proxy == nil {
-		client.proxy = newProxy(client.kubeconfig)
+		client.proxy = newProxy(client.kubeconfig, InjectProxyWarningHandler(client.warningHandler))
 	}
 
 	// if there is an injected repositoryClientFactory, use it, otherwise use the default one
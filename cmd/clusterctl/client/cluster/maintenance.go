@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// MaintenanceClient has methods to put a namespace of the management cluster in maintenance, pausing the
+// reconciliation of the Cluster API objects in it.
+type MaintenanceClient interface {
+	// Enter puts a namespace in maintenance, with an optional reason.
+	Enter(ctx context.Context, namespace, reason string) error
+
+	// Exit ends the maintenance of a namespace.
+	Exit(ctx context.Context, namespace string) error
+
+	// Status returns the maintenance status of a namespace and of the Clusters and ClusterClasses in it.
+	Status(ctx context.Context, namespace string) (*MaintenanceStatus, error)
+}
+
+// MaintenanceStatus is the maintenance status of a namespace.
+type MaintenanceStatus struct {
+	// Namespace is the name of the namespace.
+	Namespace string
+
+	// InMaintenance is true if the namespace is in maintenance.
+	InMaintenance bool
+
+	// Reason is the reason of the maintenance, if any.
+	Reason string
+
+	// Objects are the Clusters and ClusterClasses in the namespace.
+	Objects []MaintenanceObjectStatus
+}
+
+// MaintenanceObjectStatus is the maintenance status of a Cluster or of a ClusterClass.
+type MaintenanceObjectStatus struct {
+	// Kind of the object, either Cluster or ClusterClass.
+	Kind string
+
+	// Name of the object.
+	Name string
+
+	// Paused is true if the reconciliation of the object is paused.
+	Paused bool
+
+	// PausedByMaintenance is true if the object has been paused because of the maintenance, and thus
+	// it will be unpaused once the maintenance is over.
+	PausedByMaintenance bool
+
+	// Acknowledged is true if the controllers reported the object as paused; this applies only to Clusters,
+	// which report it using the Paused condition.
+	Acknowledged bool
+}
+
+// Paused returns true if all the objects in the namespace are paused, and all the Clusters acknowledged it.
+func (s *MaintenanceStatus) Paused() bool {
+	for _, o := range s.Objects {
+		if !o.Paused || (o.Kind == "Cluster" && !o.Acknowledged) {
+			return false
+		}
+	}
+	return true
+}
+
+// maintenanceClient implements MaintenanceClient.
+type maintenanceClient struct {
+	proxy Proxy
+}
+
+// ensure maintenanceClient implements MaintenanceClient.
+var _ MaintenanceClient = &maintenanceClient{}
+
+// newMaintenanceClient returns a maintenanceClient.
+func newMaintenanceClient(proxy Proxy) *maintenanceClient {
+	return &maintenanceClient{
+		proxy: proxy,
+	}
+}
+
+func (m *maintenanceClient) Enter(ctx context.Context, namespace, reason string) error {
+	log := logf.Log
+	log.Info("Entering maintenance", "Namespace", namespace)
+
+	return m.patchNamespace(ctx, namespace, func(ns *corev1.Namespace) {
+		if ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
+		}
+		ns.Annotations[clusterv1.NamespaceMaintenanceAnnotation] = reason
+	})
+}
+
+func (m *maintenanceClient) Exit(ctx context.Context, namespace string) error {
+	log := logf.Log
+	log.Info("Exiting maintenance", "Namespace", namespace)
+
+	return m.patchNamespace(ctx, namespace, func(ns *corev1.Namespace) {
+		delete(ns.Annotations, clusterv1.NamespaceMaintenanceAnnotation)
+	})
+}
+
+// patchNamespace patches a namespace with the given mutation.
+func (m *maintenanceClient) patchNamespace(ctx context.Context, namespace string, mutate func(ns *corev1.Namespace)) error {
+	c, err := m.proxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return errors.Wrapf(err, "failed to get namespace %s", namespace)
+	}
+
+	original := ns.DeepCopy()
+	mutate(ns)
+	if err := c.Patch(ctx, ns, client.MergeFrom(original)); err != nil {
+		return errors.Wrapf(err, "failed to patch namespace %s", namespace)
+	}
+	return nil
+}
+
+func (m *maintenanceClient) Status(ctx context.Context, namespace string) (*MaintenanceStatus, error) {
+	c, err := m.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return nil, errors.Wrapf(err, "failed to get namespace %s", namespace)
+	}
+
+	status := &MaintenanceStatus{Namespace: namespace}
+	status.Reason, status.InMaintenance = ns.Annotations[clusterv1.NamespaceMaintenanceAnnotation]
+
+	clusters := &clusterv1.ClusterList{}
+	if err := c.List(ctx, clusters, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list Clusters in namespace %s", namespace)
+	}
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		_, pausedByMaintenance := cluster.Annotations[clusterv1.PausedByMaintenanceAnnotation]
+		status.Objects = append(status.Objects, MaintenanceObjectStatus{
+			Kind:                "Cluster",
+			Name:                cluster.Name,
+			Paused:              cluster.Spec.Paused,
+			PausedByMaintenance: pausedByMaintenance,
+			Acknowledged:        conditions.IsTrue(cluster, clusterv1.PausedCondition),
+		})
+	}
+
+	clusterClasses := &clusterv1.ClusterClassList{}
+	if err := c.List(ctx, clusterClasses, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list ClusterClasses in namespace %s", namespace)
+	}
+	for i := range clusterClasses.Items {
+		clusterClass := &clusterClasses.Items[i]
+		_, paused := clusterClass.Annotations[clusterv1.PausedAnnotation]
+		_, pausedByMaintenance := clusterClass.Annotations[clusterv1.PausedByMaintenanceAnnotation]
+		status.Objects = append(status.Objects, MaintenanceObjectStatus{
+			Kind:                "ClusterClass",
+			Name:                clusterClass.Name,
+			Paused:              paused,
+			PausedByMaintenance: pausedByMaintenance,
+		})
+	}
+
+	sort.SliceStable(status.Objects, func(i, j int) bool {
+		if status.Objects[i].Kind != status.Objects[j].Kind {
+			return status.Objects[i].Kind < status.Objects[j].Kind
+		}
+		return status.Objects[i].Name < status.Objects[j].Name
+	})
+	return status, nil
+}
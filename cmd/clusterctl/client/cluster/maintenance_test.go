@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func TestMaintenanceClient_EnterExit(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	proxy := test.NewFakeProxy().WithObjs(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}})
+	m := newMaintenanceClient(proxy)
+
+	c, err := proxy.NewClient()
+	g.Expect(err).ToNot(HaveOccurred())
+	ns := &corev1.Namespace{}
+
+	g.Expect(m.Enter(ctx, "ns1", "etcd restore")).To(Succeed())
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: "ns1"}, ns)).To(Succeed())
+	g.Expect(ns.Annotations).To(HaveKeyWithValue(clusterv1.NamespaceMaintenanceAnnotation, "etcd restore"))
+
+	g.Expect(m.Exit(ctx, "ns1")).To(Succeed())
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: "ns1"}, ns)).To(Succeed())
+	g.Expect(ns.Annotations).ToNot(HaveKey(clusterv1.NamespaceMaintenanceAnnotation))
+
+	g.Expect(m.Enter(ctx, "ns2", "")).ToNot(Succeed())
+}
+
+func TestMaintenanceClient_Status(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	proxy := test.NewFakeProxy().WithObjs(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "ns1",
+			Annotations: map[string]string{clusterv1.NamespaceMaintenanceAnnotation: "etcd restore"},
+		}},
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster2",
+				Namespace:   "ns1",
+				Annotations: map[string]string{clusterv1.PausedByMaintenanceAnnotation: ""},
+			},
+			Spec: clusterv1.ClusterSpec{Paused: true},
+			Status: clusterv1.ClusterStatus{Conditions: clusterv1.Conditions{
+				{Type: clusterv1.PausedCondition, Status: corev1.ConditionTrue},
+			}},
+		},
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: "ns1"},
+			Spec:       clusterv1.ClusterSpec{Paused: true},
+		},
+		&clusterv1.ClusterClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "class1",
+				Namespace: "ns1",
+				Annotations: map[string]string{
+					clusterv1.PausedAnnotation:              "",
+					clusterv1.PausedByMaintenanceAnnotation: "",
+				},
+			},
+		},
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster3", Namespace: "ns2"},
+		},
+	)
+
+	status, err := newMaintenanceClient(proxy).Status(ctx, "ns1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(status.InMaintenance).To(BeTrue())
+	g.Expect(status.Reason).To(Equal("etcd restore"))
+	g.Expect(status.Objects).To(Equal([]MaintenanceObjectStatus{
+		{Kind: "Cluster", Name: "cluster1", Paused: true},
+		{Kind: "Cluster", Name: "cluster2", Paused: true, PausedByMaintenance: true, Acknowledged: true},
+		{Kind: "ClusterClass", Name: "class1", Paused: true, PausedByMaintenance: true},
+	}))
+	// cluster1 has not been acknowledged as paused yet.
+	g.Expect(status.Paused()).To(BeFalse())
+
+	status.Objects[0].Acknowledged = true
+	g.Expect(status.Paused()).To(BeTrue())
+}
@@ -82,6 +82,7 @@ type proxy struct {
 	kubeconfig         Kubeconfig
 	timeout            time.Duration
 	configLoadingRules *clientcmd.ClientConfigLoadingRules
+	warningHandler     rest.WarningHandler
 }
 
 var _ Proxy = &proxy{}
@@ -153,6 +154,11 @@ func (k *proxy) GetConfig() (*rest.Config, error) {
 	restConfig.QPS = 20
 	restConfig.Burst = 100
 
+	// Surface the warnings returned by the API server, e.g. by admission webhooks, to the injected handler, if any.
+	if k.warningHandler != nil {
+		restConfig.WarningHandler = k.warningHandler
+	}
+
 	return restConfig, nil
 }
 
@@ -167,7 +173,11 @@ func (k *proxy) NewClient() (client.Client, error) {
 	connectBackoff := newConnectBackoff()
 	if err := retryWithExponentialBackoff(connectBackoff, func() error {
 		var err error
-		c, err = client.New(config, client.Options{Scheme: localScheme})
+		c, err = client.New(config, client.Options{
+			Scheme: localScheme,
+			// Do not replace the injected warning handler with the default one logging warnings.
+			WarningHandler: client.WarningHandlerOptions{SuppressWarnings: k.warningHandler != nil},
+		})
 		if err != nil {
 			return err
 		}
@@ -374,6 +384,13 @@ func InjectKubeconfigPaths(paths []string) ProxyOption {
 	}
 }
 
+// InjectProxyWarningHandler sets the handler for the warnings returned by the API server.
+func InjectProxyWarningHandler(handler rest.WarningHandler) ProxyOption {
+	return func(p *proxy) {
+		p.warningHandler = handler
+	}
+}
+
 func newProxy(kubeconfig Kubeconfig, opts ...ProxyOption) Proxy {
 	// If a kubeconfig file isn't provided, find one in the standard locations.
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
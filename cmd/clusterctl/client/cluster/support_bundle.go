@@ -0,0 +1,588 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/version"
+)
+
+// SupportBundleRedacted is the value replacing the data of the Secrets included in a support bundle.
+const SupportBundleRedacted = "REDACTED"
+
+// SupportBundleClient has methods to collect the information required to troubleshoot a workload cluster.
+type SupportBundleClient interface {
+	// Collect writes a support bundle for a workload cluster to out, as a gzipped tarball.
+	Collect(ctx context.Context, options SupportBundleOptions, out io.Writer) error
+}
+
+// SupportBundleOptions defines the options for collecting a support bundle.
+type SupportBundleOptions struct {
+	// Namespace where the workload cluster is located.
+	Namespace string
+
+	// ClusterName is the name of the workload cluster.
+	ClusterName string
+
+	// LogsSince limits the logs of the providers to the given duration; if 0, all the logs are collected.
+	LogsSince time.Duration
+}
+
+// supportBundleVersions is the version information included in a support bundle.
+type supportBundleVersions struct {
+	Clusterctl version.Info            `json:"clusterctl"`
+	Kubernetes string                  `json:"kubernetes"`
+	Contract   string                  `json:"contract"`
+	Providers  []supportBundleProvider `json:"providers"`
+}
+
+// supportBundleProvider is a provider installed in the management cluster, as included in a support bundle.
+type supportBundleProvider struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Version   string `json:"version"`
+	Namespace string `json:"namespace"`
+}
+
+// supportBundleClient implements SupportBundleClient.
+type supportBundleClient struct {
+	proxy             Proxy
+	providerInventory InventoryClient
+
+	// newClientSet returns the client-go client used to read the logs of the providers and the version of the management cluster.
+	newClientSet func() (kubernetes.Interface, error)
+}
+
+// ensure supportBundleClient implements SupportBundleClient.
+var _ SupportBundleClient = &supportBundleClient{}
+
+// newSupportBundleClient returns a supportBundleClient.
+func newSupportBundleClient(proxy Proxy, providerInventory InventoryClient) *supportBundleClient {
+	return &supportBundleClient{
+		proxy:             proxy,
+		providerInventory: providerInventory,
+		newClientSet: func() (kubernetes.Interface, error) {
+			config, err := proxy.GetConfig()
+			if err != nil {
+				return nil, err
+			}
+			cs, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to create the client-go client")
+			}
+			return cs, nil
+		},
+	}
+}
+
+func (s *supportBundleClient) Collect(ctx context.Context, options SupportBundleOptions, out io.Writer) error {
+	log := logf.Log
+	log.Info("Collecting support bundle", "Cluster", options.ClusterName, "Namespace", options.Namespace)
+
+	graph := newObjectGraph(s.proxy, s.providerInventory)
+
+	// Gets all the types defined by the CRDs installed by clusterctl plus the ConfigMap/Secret core types.
+	if err := graph.getDiscoveryTypes(ctx); err != nil {
+		return errors.Wrap(err, "failed to retrieve discovery types")
+	}
+
+	// Discovery the object graph, so it is possible to identify the objects in the hierarchy of the Cluster
+	// like clusterctl move does.
+	if err := graph.Discovery(ctx, options.Namespace); err != nil {
+		return errors.Wrap(err, "failed to discover the object graph")
+	}
+
+	gzipWriter := gzip.NewWriter(out)
+	tarWriter := tar.NewWriter(gzipWriter)
+	bundle := &supportBundle{
+		writer: tarWriter,
+		root:   fmt.Sprintf("%s-support-bundle", options.ClusterName),
+		now:    time.Now(),
+	}
+	if err := s.collect(ctx, graph, options, bundle); err != nil {
+		return err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return errors.Wrap(err, "failed to write support bundle")
+	}
+	return errors.Wrap(gzipWriter.Close(), "failed to write support bundle")
+}
+
+// collect writes the support bundle for a Cluster existing in the object graph.
+// Failures collecting a part of the support bundle, e.g. the logs of a provider, don't fail the collection; they
+// are reported in the errors.txt file of the support bundle instead.
+func (s *supportBundleClient) collect(ctx context.Context, graph *objectGraph, options SupportBundleOptions, bundle *supportBundle) error {
+	log := logf.Log
+
+	nodes, err := getSupportBundleNodes(graph, options.Namespace, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	c, err := s.proxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	log.V(1).Info("Collecting objects", "Count", len(nodes))
+	uids := map[types.UID]bool{}
+	for _, n := range nodes {
+		uids[n.identity.UID] = true
+		if err := s.collectObject(ctx, c, n, bundle); err != nil {
+			bundle.addError(err)
+		}
+	}
+
+	log.V(1).Info("Collecting events")
+	if err := s.collectEvents(ctx, c, options.Namespace, uids, bundle); err != nil {
+		bundle.addError(err)
+	}
+
+	log.V(1).Info("Collecting webhook configurations")
+	if err := s.collectWebhookConfigurations(ctx, c, bundle); err != nil {
+		bundle.addError(err)
+	}
+
+	providers, err := s.providerInventory.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.V(1).Info("Collecting versions")
+	if err := s.collectVersions(providers, bundle); err != nil {
+		bundle.addError(err)
+	}
+
+	log.V(1).Info("Collecting provider logs")
+	for i := range providers.Items {
+		if err := s.collectProviderLogs(ctx, c, &providers.Items[i], options.LogsSince, bundle); err != nil {
+			bundle.addError(err)
+		}
+	}
+
+	return bundle.writeErrors()
+}
+
+// getSupportBundleNodes returns the nodes in the hierarchy of a Cluster, including the ClusterClass used by the
+// Cluster, if any, sorted by namespace, kind and name.
+func getSupportBundleNodes(graph *objectGraph, namespace, clusterName string) ([]*node, error) {
+	tenants := []*node{}
+	for _, cluster := range graph.getClusters() {
+		if cluster.identity.Namespace == namespace && cluster.identity.Name == clusterName {
+			tenants = append(tenants, cluster)
+			if class, ok := cluster.additionalInfo[clusterTopologyNameKey]; ok {
+				for _, clusterClass := range graph.getClusterClasses() {
+					if clusterClass.identity.Namespace == namespace && clusterClass.identity.Name == class {
+						tenants = append(tenants, clusterClass)
+					}
+				}
+			}
+		}
+	}
+	if len(tenants) == 0 {
+		return nil, errors.Errorf("Cluster %s/%s not found", namespace, clusterName)
+	}
+
+	nodes := []*node{}
+	for _, n := range graph.getNodes() {
+		if n.virtual {
+			continue
+		}
+		for _, tenant := range tenants {
+			if _, ok := n.tenant[tenant]; ok {
+				nodes = append(nodes, n)
+				break
+			}
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].identity.Namespace != nodes[j].identity.Namespace {
+			return nodes[i].identity.Namespace < nodes[j].identity.Namespace
+		}
+		if nodes[i].identity.Kind != nodes[j].identity.Kind {
+			return nodes[i].identity.Kind < nodes[j].identity.Kind
+		}
+		return nodes[i].identity.Name < nodes[j].identity.Name
+	})
+	return nodes, nil
+}
+
+// collectObject writes an object in the resources directory of the support bundle, after sanitizing it.
+func (s *supportBundleClient) collectObject(ctx context.Context, c client.Client, n *node, bundle *supportBundle) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(n.identity.APIVersion)
+	obj.SetKind(n.identity.Kind)
+	if err := c.Get(ctx, client.ObjectKey{Namespace: n.identity.Namespace, Name: n.identity.Name}, obj); err != nil {
+		return errors.Wrapf(err, "failed to get %s", n.identityStr())
+	}
+	sanitizeObject(obj)
+
+	namespace := n.identity.Namespace
+	if namespace == "" {
+		namespace = "cluster-scoped"
+	}
+	return bundle.writeYAML(path.Join("resources", namespace, n.identity.Kind, n.identity.Name+".yaml"), obj.Object)
+}
+
+// sanitizeObject removes the sensitive or noisy information from an object included in a support bundle:
+//   - the data of Secrets and ConfigMaps is redacted, while the keys are kept; ConfigMaps can contain sensitive
+//     information too, e.g. the manifests applied by ClusterResourceSets.
+//   - the content of the files of bootstrap configurations, e.g. KubeadmConfig and KubeadmControlPlane files[].content,
+//     is redacted.
+//   - the values of the fields which could contain inline credentials, e.g. password or token, are redacted.
+//   - the last applied configuration annotation, which can contain the data of Secrets, is removed.
+//   - managed fields are removed.
+func sanitizeObject(obj *unstructured.Unstructured) {
+	obj.SetManagedFields(nil)
+
+	annotations := obj.GetAnnotations()
+	delete(annotations, corev1.LastAppliedConfigAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+
+	var dataFields []string
+	switch obj.GroupVersionKind().GroupKind() {
+	case corev1.SchemeGroupVersion.WithKind("Secret").GroupKind():
+		dataFields = []string{"data", "stringData"}
+	case corev1.SchemeGroupVersion.WithKind("ConfigMap").GroupKind():
+		dataFields = []string{"data", "binaryData"}
+	}
+	if dataFields != nil {
+		for _, field := range dataFields {
+			data, ok := obj.Object[field].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for key := range data {
+				data[key] = SupportBundleRedacted
+			}
+		}
+		return
+	}
+
+	for field, value := range obj.Object {
+		if field == "metadata" {
+			continue
+		}
+		redactSensitiveFields(value)
+	}
+}
+
+// sensitiveFieldNames are the (lowercase) substrings of the names of the fields which could contain inline credentials.
+var sensitiveFieldNames = []string{"password", "passwd", "token", "secret", "credential", "privatekey", "certificatekey", "apikey", "accesskey"}
+
+// redactSensitiveFields recursively redacts the content of files, e.g. the files of a KubeadmConfig, and the values
+// of the fields whose name suggests they contain credentials.
+// NOTE: Fields referencing another object, e.g. secretRef or dataSecretName, are not redacted.
+func redactSensitiveFields(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for field, fieldValue := range v {
+			if field == "files" {
+				redactFilesContent(fieldValue)
+			}
+			if _, ok := fieldValue.(string); ok && isSensitiveField(field) {
+				v[field] = SupportBundleRedacted
+				continue
+			}
+			redactSensitiveFields(fieldValue)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactSensitiveFields(item)
+		}
+	}
+}
+
+// redactFilesContent redacts the inline content of a list of files, e.g. the files of a KubeadmConfig.
+func redactFilesContent(files interface{}) {
+	list, ok := files.([]interface{})
+	if !ok {
+		return
+	}
+	for _, item := range list {
+		file, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := file["content"]; ok {
+			file["content"] = SupportBundleRedacted
+		}
+	}
+}
+
+// isSensitiveField returns true if the name of a field suggests it contains credentials.
+func isSensitiveField(field string) bool {
+	field = strings.ToLower(field)
+	for _, suffix := range []string{"ref", "name", "namespace", "kind", "ttl"} {
+		if strings.HasSuffix(field, suffix) {
+			return false
+		}
+	}
+	for _, name := range sensitiveFieldNames {
+		if strings.Contains(field, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectEvents writes the events of the objects included in the support bundle, sorted by time.
+func (s *supportBundleClient) collectEvents(ctx context.Context, c client.Client, namespace string, uids map[types.UID]bool, bundle *supportBundle) error {
+	eventList := &corev1.EventList{}
+	if err := c.List(ctx, eventList, client.InNamespace(namespace)); err != nil {
+		return errors.Wrapf(err, "failed to list Events in namespace %s", namespace)
+	}
+
+	events := &corev1.EventList{}
+	events.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("EventList"))
+	for _, event := range eventList.Items {
+		if uids[event.InvolvedObject.UID] {
+			event.ManagedFields = nil
+			events.Items = append(events.Items, event)
+		}
+	}
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return eventTime(events.Items[i]).Before(eventTime(events.Items[j]))
+	})
+	return bundle.writeYAML("events.yaml", events)
+}
+
+// eventTime returns the last time an event was observed.
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// collectWebhookConfigurations writes the webhook configurations of the providers installed by clusterctl.
+func (s *supportBundleClient) collectWebhookConfigurations(ctx context.Context, c client.Client, bundle *supportBundle) error {
+	selector := client.HasLabels{clusterctlv1.ClusterctlLabel}
+
+	validatingWebhooks := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := c.List(ctx, validatingWebhooks, selector); err != nil {
+		return errors.Wrap(err, "failed to list ValidatingWebhookConfigurations")
+	}
+	for i := range validatingWebhooks.Items {
+		webhook := &validatingWebhooks.Items[i]
+		webhook.SetGroupVersionKind(admissionregistrationv1.SchemeGroupVersion.WithKind("ValidatingWebhookConfiguration"))
+		webhook.ManagedFields = nil
+		if err := bundle.writeYAML(path.Join("webhooks", "ValidatingWebhookConfiguration", webhook.Name+".yaml"), webhook); err != nil {
+			return err
+		}
+	}
+
+	mutatingWebhooks := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := c.List(ctx, mutatingWebhooks, selector); err != nil {
+		return errors.Wrap(err, "failed to list MutatingWebhookConfigurations")
+	}
+	for i := range mutatingWebhooks.Items {
+		webhook := &mutatingWebhooks.Items[i]
+		webhook.SetGroupVersionKind(admissionregistrationv1.SchemeGroupVersion.WithKind("MutatingWebhookConfiguration"))
+		webhook.ManagedFields = nil
+		if err := bundle.writeYAML(path.Join("webhooks", "MutatingWebhookConfiguration", webhook.Name+".yaml"), webhook); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectVersions writes the version of clusterctl, of the management cluster and of the providers.
+func (s *supportBundleClient) collectVersions(providers *clusterctlv1.ProviderList, bundle *supportBundle) error {
+	versions := supportBundleVersions{
+		Clusterctl: version.Get(),
+		Contract:   clusterv1.GroupVersion.Version,
+		Providers:  []supportBundleProvider{},
+	}
+	for _, p := range providers.Items {
+		versions.Providers = append(versions.Providers, supportBundleProvider{
+			Name:      p.ProviderName,
+			Type:      p.Type,
+			Version:   p.Version,
+			Namespace: p.Namespace,
+		})
+	}
+
+	// NOTE: The versions are written even if the version of the management cluster cannot be read.
+	if serverVersion, err := s.getServerVersion(); err != nil {
+		bundle.addError(errors.Wrap(err, "failed to get the version of the management cluster"))
+	} else {
+		versions.Kubernetes = serverVersion
+	}
+	return bundle.writeYAML("versions.yaml", versions)
+}
+
+// getServerVersion returns the Kubernetes version of the management cluster.
+func (s *supportBundleClient) getServerVersion() (string, error) {
+	cs, err := s.newClientSet()
+	if err != nil {
+		return "", err
+	}
+	serverVersion, err := cs.Discovery().ServerVersion()
+	if err != nil {
+		return "", err
+	}
+	return serverVersion.String(), nil
+}
+
+// collectProviderLogs writes the logs of the containers of the Deployments of a provider; the logs of the previous
+// instance of a container are written as well, if the container restarted.
+func (s *supportBundleClient) collectProviderLogs(ctx context.Context, c client.Client, provider *clusterctlv1.Provider, since time.Duration, bundle *supportBundle) error {
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments, client.InNamespace(provider.Namespace), client.MatchingLabels{clusterv1.ProviderNameLabel: provider.ManifestLabel()}); err != nil {
+		return errors.Wrapf(err, "failed to list Deployments for provider %s", provider.ManifestLabel())
+	}
+
+	cs, err := s.newClientSet()
+	if err != nil {
+		return err
+	}
+
+	var sinceSeconds *int64
+	if since > 0 {
+		seconds := int64(since.Seconds())
+		sinceSeconds = &seconds
+	}
+
+	for _, deployment := range deployments.Items {
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			bundle.addError(errors.Wrapf(err, "failed to get the selector of Deployment %s/%s", deployment.Namespace, deployment.Name))
+			continue
+		}
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(deployment.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			bundle.addError(errors.Wrapf(err, "failed to list Pods for Deployment %s/%s", deployment.Namespace, deployment.Name))
+			continue
+		}
+
+		for _, pod := range pods.Items {
+			restarted := map[string]bool{}
+			for _, status := range pod.Status.ContainerStatuses {
+				restarted[status.Name] = status.RestartCount > 0
+			}
+
+			for _, container := range pod.Spec.Containers {
+				logs := []bool{false}
+				if restarted[container.Name] {
+					logs = append(logs, true)
+				}
+				for _, previous := range logs {
+					file := container.Name + ".log"
+					if previous {
+						file = container.Name + ".previous.log"
+					}
+					data, err := cs.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+						Container:    container.Name,
+						Previous:     previous,
+						SinceSeconds: sinceSeconds,
+					}).DoRaw(ctx)
+					if err != nil {
+						bundle.addError(errors.Wrapf(err, "failed to get the logs of container %s of Pod %s/%s", container.Name, pod.Namespace, pod.Name))
+						continue
+					}
+					if err := bundle.writeFile(path.Join("logs", pod.Namespace, pod.Name, file), data); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// supportBundle writes the files of a support bundle to a tarball.
+type supportBundle struct {
+	writer *tar.Writer
+	root   string
+	now    time.Time
+	errors []error
+}
+
+// addError records a failure collecting a part of the support bundle.
+func (b *supportBundle) addError(err error) {
+	logf.Log.V(1).Info("Failed to collect support bundle item", "Error", err.Error())
+	b.errors = append(b.errors, err)
+}
+
+// writeYAML writes an object as a YAML file of the support bundle.
+func (b *supportBundle) writeYAML(name string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s", name)
+	}
+	return b.writeFile(name, data)
+}
+
+// writeFile writes a file of the support bundle.
+func (b *supportBundle) writeFile(name string, data []byte) error {
+	header := &tar.Header{
+		Name:    path.Join(b.root, name),
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: b.now,
+	}
+	if err := b.writer.WriteHeader(header); err != nil {
+		return errors.Wrapf(err, "failed to write %s to the support bundle", name)
+	}
+	if _, err := b.writer.Write(data); err != nil {
+		return errors.Wrapf(err, "failed to write %s to the support bundle", name)
+	}
+	return nil
+}
+
+// writeErrors writes the failures collecting the support bundle, if any.
+func (b *supportBundle) writeErrors() error {
+	if len(b.errors) == 0 {
+		return nil
+	}
+	data := []byte{}
+	for _, err := range b.errors {
+		data = append(data, []byte(err.Error()+"\n")...)
+	}
+	return b.writeFile("errors.txt", data)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func TestSupportBundleClient_collect(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	objs := test.NewFakeCluster("ns1", "cluster1").
+		WithMachines(test.NewFakeMachine("m1")).
+		Objs()
+	cluster := objs[0]
+	objs = append(objs, test.NewFakeCluster("ns1", "cluster2").Objs()...)
+	objs = append(objs,
+		&corev1.Secret{
+			TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1-credentials",
+				Namespace: "ns1",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "cluster1", UID: cluster.GetUID()},
+				},
+				Annotations: map[string]string{
+					corev1.LastAppliedConfigAnnotation: `{"data":{"value":"c2VjcmV0"}}`,
+				},
+			},
+			Data: map[string][]byte{"value": []byte("secret")},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "cluster1-event", Namespace: "ns1"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Cluster", Name: "cluster1", Namespace: "ns1", UID: cluster.GetUID()},
+			Reason:         "Provisioned",
+			LastTimestamp:  metav1.NewTime(time.Now()),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "cluster2-event", Namespace: "ns1"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Cluster", Name: "cluster2", Namespace: "ns1", UID: types.UID("cluster2")},
+			Reason:         "Provisioned",
+		},
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "infra1-validating-webhook", Labels: map[string]string{clusterctlv1.ClusterctlLabel: ""}},
+		},
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-validating-webhook"},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "infra1-controller-manager",
+				Namespace: "infra1-system",
+				Labels:    map[string]string{clusterv1.ProviderNameLabel: "infrastructure-infra1"},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"control-plane": "controller-manager"}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "infra1-controller-manager-abc",
+				Namespace: "infra1-system",
+				Labels:    map[string]string{"control-plane": "controller-manager"},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "manager"}, {Name: "sidecar"}},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "manager", RestartCount: 1}, {Name: "sidecar"}},
+			},
+		},
+	)
+
+	graph := getObjectGraphWithObjs(objs)
+	g.Expect(getFakeDiscoveryTypes(ctx, graph)).To(Succeed())
+	g.Expect(graph.Discovery(ctx, "ns1")).To(Succeed())
+
+	cs := fakeclientset.NewSimpleClientset()
+	cs.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.28.0"}
+	s := newSupportBundleClient(graph.proxy, graph.providerInventory)
+	s.newClientSet = func() (kubernetes.Interface, error) {
+		return cs, nil
+	}
+
+	buf := &bytes.Buffer{}
+	bundle := &supportBundle{writer: tar.NewWriter(buf), root: "cluster1-support-bundle", now: time.Now()}
+	g.Expect(s.collect(ctx, graph, SupportBundleOptions{Namespace: "ns1", ClusterName: "cluster1"}, bundle)).To(Succeed())
+	g.Expect(bundle.writer.Close()).To(Succeed())
+
+	files := readSupportBundle(g, buf)
+	g.Expect(files).To(HaveLen(16))
+	g.Expect(files).To(HaveKey("cluster1-support-bundle/resources/ns1/Cluster/cluster1.yaml"))
+	g.Expect(files).To(HaveKey("cluster1-support-bundle/resources/ns1/GenericInfrastructureCluster/cluster1.yaml"))
+	g.Expect(files).To(HaveKey("cluster1-support-bundle/resources/ns1/Machine/m1.yaml"))
+	g.Expect(files).To(HaveKey("cluster1-support-bundle/resources/ns1/Secret/cluster1-ca.yaml"))
+	g.Expect(files).ToNot(HaveKey("cluster1-support-bundle/resources/ns1/Cluster/cluster2.yaml"))
+	g.Expect(files).To(HaveKey("cluster1-support-bundle/webhooks/ValidatingWebhookConfiguration/infra1-validating-webhook.yaml"))
+	g.Expect(files).To(HaveKey("cluster1-support-bundle/versions.yaml"))
+	g.Expect(files).To(HaveKeyWithValue("cluster1-support-bundle/logs/infra1-system/infra1-controller-manager-abc/manager.log", "fake logs"))
+	g.Expect(files).To(HaveKeyWithValue("cluster1-support-bundle/logs/infra1-system/infra1-controller-manager-abc/manager.previous.log", "fake logs"))
+	g.Expect(files).To(HaveKeyWithValue("cluster1-support-bundle/logs/infra1-system/infra1-controller-manager-abc/sidecar.log", "fake logs"))
+	g.Expect(files).ToNot(HaveKey("cluster1-support-bundle/errors.txt"))
+
+	// The data of Secrets is redacted.
+	secret := &unstructured.Unstructured{}
+	g.Expect(yaml.Unmarshal([]byte(files["cluster1-support-bundle/resources/ns1/Secret/cluster1-credentials.yaml"]), &secret.Object)).To(Succeed())
+	g.Expect(secret.Object["data"]).To(Equal(map[string]interface{}{"value": SupportBundleRedacted}))
+	g.Expect(secret.GetAnnotations()).To(BeEmpty())
+
+	// Only the events of the objects in the support bundle are included.
+	events := &corev1.EventList{}
+	g.Expect(yaml.Unmarshal([]byte(files["cluster1-support-bundle/events.yaml"]), events)).To(Succeed())
+	g.Expect(events.Items).To(HaveLen(1))
+	g.Expect(events.Items[0].Name).To(Equal("cluster1-event"))
+
+	versions := &supportBundleVersions{}
+	g.Expect(yaml.Unmarshal([]byte(files["cluster1-support-bundle/versions.yaml"]), versions)).To(Succeed())
+	g.Expect(versions.Kubernetes).To(Equal("v1.28.0"))
+	g.Expect(versions.Contract).To(Equal(clusterv1.GroupVersion.Version))
+	g.Expect(versions.Providers).To(ConsistOf(supportBundleProvider{Name: "infra1", Type: "InfrastructureProvider", Version: "v1.2.3", Namespace: "infra1-system"}))
+}
+
+func TestSupportBundleClient_collectClusterNotFound(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	graph := getObjectGraphWithObjs(test.NewFakeCluster("ns1", "cluster1").Objs())
+	g.Expect(getFakeDiscoveryTypes(ctx, graph)).To(Succeed())
+	g.Expect(graph.Discovery(ctx, "ns1")).To(Succeed())
+
+	s := newSupportBundleClient(graph.proxy, graph.providerInventory)
+	bundle := &supportBundle{writer: tar.NewWriter(io.Discard)}
+	err := s.collect(ctx, graph, SupportBundleOptions{Namespace: "ns1", ClusterName: "cluster2"}, bundle)
+	g.Expect(err).To(MatchError("Cluster ns1/cluster2 not found"))
+}
+
+func TestSanitizeObject(t *testing.T) {
+	t.Run("redacts the data of ConfigMaps", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "crs-resources", "namespace": "ns1"},
+			"data":       map[string]interface{}{"manifests.yaml": "apiVersion: v1\nkind: Secret"},
+			"binaryData": map[string]interface{}{"archive": "Zm9v"},
+		}}
+		sanitizeObject(obj)
+		g.Expect(obj.Object["data"]).To(Equal(map[string]interface{}{"manifests.yaml": SupportBundleRedacted}))
+		g.Expect(obj.Object["binaryData"]).To(Equal(map[string]interface{}{"archive": SupportBundleRedacted}))
+	})
+
+	t.Run("redacts the content of files and inline credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "controlplane.cluster.x-k8s.io/v1beta1",
+			"kind":       "KubeadmControlPlane",
+			"metadata":   map[string]interface{}{"name": "kcp", "namespace": "ns1", "labels": map[string]interface{}{"token": "not-redacted"}},
+			"spec": map[string]interface{}{
+				"kubeadmConfigSpec": map[string]interface{}{
+					"files": []interface{}{
+						map[string]interface{}{"path": "/etc/kubernetes/cloud.conf", "content": "password=foo"},
+						map[string]interface{}{"path": "/etc/kubernetes/other.conf", "contentFrom": map[string]interface{}{"secret": map[string]interface{}{"name": "other"}}},
+					},
+					"users": []interface{}{
+						map[string]interface{}{"name": "capi", "passwd": "hashed"},
+					},
+					"joinConfiguration": map[string]interface{}{
+						"discovery": map[string]interface{}{
+							"bootstrapToken": map[string]interface{}{"token": "abcdef.0123456789abcdef", "apiServerEndpoint": "10.0.0.1:6443"},
+						},
+					},
+				},
+				"identity": map[string]interface{}{
+					"clientSecret":   "inline-secret",
+					"secretRef":      map[string]interface{}{"name": "credentials"},
+					"dataSecretName": "bootstrap-data",
+				},
+			},
+		}}
+		sanitizeObject(obj)
+		nestedString := func(fields ...string) string {
+			value, _, _ := unstructured.NestedString(obj.Object, fields...)
+			return value
+		}
+
+		files, _, _ := unstructured.NestedSlice(obj.Object, "spec", "kubeadmConfigSpec", "files")
+		g.Expect(files[0]).To(HaveKeyWithValue("content", SupportBundleRedacted))
+		g.Expect(files[0]).To(HaveKeyWithValue("path", "/etc/kubernetes/cloud.conf"))
+		g.Expect(files[1]).To(HaveKey("contentFrom"))
+		users, _, _ := unstructured.NestedSlice(obj.Object, "spec", "kubeadmConfigSpec", "users")
+		g.Expect(users[0]).To(HaveKeyWithValue("passwd", SupportBundleRedacted))
+		g.Expect(nestedString("spec", "kubeadmConfigSpec", "joinConfiguration", "discovery", "bootstrapToken", "token")).To(Equal(SupportBundleRedacted))
+		g.Expect(nestedString("spec", "kubeadmConfigSpec", "joinConfiguration", "discovery", "bootstrapToken", "apiServerEndpoint")).To(Equal("10.0.0.1:6443"))
+		g.Expect(nestedString("spec", "identity", "clientSecret")).To(Equal(SupportBundleRedacted))
+		g.Expect(nestedString("spec", "identity", "secretRef", "name")).To(Equal("credentials"))
+		g.Expect(nestedString("spec", "identity", "dataSecretName")).To(Equal("bootstrap-data"))
+		g.Expect(obj.GetLabels()).To(HaveKeyWithValue("token", "not-redacted"))
+	})
+}
+
+func readSupportBundle(g *WithT, r io.Reader) map[string]string {
+	files := map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		g.Expect(err).ToNot(HaveOccurred())
+		data, err := io.ReadAll(tr)
+		g.Expect(err).ToNot(HaveOccurred())
+		files[header.Name] = string(data)
+	}
+}
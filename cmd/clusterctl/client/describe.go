@@ -38,6 +38,10 @@ type DescribeClusterOptions struct {
 	// to signal to the presentation layer to show all the conditions for the objects.
 	ShowOtherConditions string
 
+	// ShowTimeline is a list of comma separated kind or kind/name for which we should add the ShowObjectTimelineAnnotation
+	// to signal to the presentation layer to show a timeline of the recent condition transitions for the objects.
+	ShowTimeline string
+
 	// ShowMachineSets instructs the discovery process to include machine sets in the ObjectTree.
 	ShowMachineSets bool
 
@@ -90,6 +94,7 @@ func (c *clusterctlClient) DescribeCluster(ctx context.Context, options Describe
 	// Gets the object tree representing the status of a Cluster API cluster.
 	return tree.Discovery(ctx, client, options.Namespace, options.ClusterName, tree.DiscoverOptions{
 		ShowOtherConditions:     options.ShowOtherConditions,
+		ShowTimeline:            options.ShowTimeline,
 		ShowMachineSets:         options.ShowMachineSets,
 		ShowClusterResourceSets: options.ShowClusterResourceSets,
 		ShowTemplates:           options.ShowTemplates,
@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/ipam"
+)
+
+// GetIPPoolsOptions carries the options supported by GetIPPools.
+type GetIPPoolsOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the pools are located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// AllNamespaces lists the pools across all namespaces, ignoring Namespace.
+	AllNamespaces bool
+}
+
+// DescribeIPPoolOptions carries the options supported by DescribeIPPool.
+type DescribeIPPoolOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the pool is located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// PoolName is the name of the InClusterIPPool.
+	PoolName string
+}
+
+// GetIPPools returns the utilization of the InClusterIPPools of the management cluster.
+func (c *clusterctlClient) GetIPPools(ctx context.Context, options GetIPPoolsOptions) ([]ipam.PoolSummary, error) {
+	// gets access to the management cluster
+	cluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract.
+	if err := cluster.ProviderInventory().CheckCAPIContract(ctx); err != nil {
+		return nil, err
+	}
+
+	namespace := ""
+	if !options.AllNamespaces {
+		// If the option specifying the Namespace is empty, try to detect it.
+		namespace = options.Namespace
+		if namespace == "" {
+			if namespace, err = cluster.Proxy().CurrentNamespace(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	client, err := cluster.Proxy().NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return ipam.ListPools(ctx, client, namespace)
+}
+
+// DescribeIPPool returns the state of an InClusterIPPool, including the claims it fulfills and the conflicting or stale objects.
+func (c *clusterctlClient) DescribeIPPool(ctx context.Context, options DescribeIPPoolOptions) (*ipam.PoolDescription, error) {
+	// gets access to the management cluster
+	cluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract.
+	if err := cluster.ProviderInventory().CheckCAPIContract(ctx); err != nil {
+		return nil, err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := cluster.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	client, err := cluster.Proxy().NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return ipam.DescribePool(ctx, client, options.Namespace, options.PoolName)
+}
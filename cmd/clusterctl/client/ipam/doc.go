@@ -0,0 +1,20 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ipam supports the inspection of the IPAM state of a management cluster, reporting the utilization of the
+// InClusterIPPools, the IPAddressClaims fulfilled by each pool grouped by cluster and machine, and the conflicting or
+// stale objects, which otherwise requires joining IPAddressClaims, IPAddresses, Machines and Clusters by hand.
+package ipam
@@ -0,0 +1,395 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// IssueType is the type of an issue found inspecting an InClusterIPPool.
+type IssueType string
+
+const (
+	// ConflictingAddressIssue reports an address of a pool allocated to more than one IPAddress.
+	ConflictingAddressIssue IssueType = "ConflictingAddress"
+
+	// ConflictingClaimIssue reports an IPAddressClaim referencing an IPAddress allocated for another claim.
+	ConflictingClaimIssue IssueType = "ConflictingClaim"
+
+	// OrphanedAddressIssue reports an IPAddress whose IPAddressClaim does not exist anymore.
+	OrphanedAddressIssue IssueType = "OrphanedAddress"
+
+	// StaleClaimIssue reports an IPAddressClaim which is not used anymore, e.g. because its cluster, machine or
+	// owners do not exist anymore, or because its lease expired.
+	StaleClaimIssue IssueType = "StaleClaim"
+)
+
+// Issue is a conflicting or stale object found inspecting an InClusterIPPool.
+type Issue struct {
+	Type IssueType
+
+	// Object is the kind and name of the object with the issue, e.g. IPAddressClaim/worker-0.
+	Object string
+
+	// Message is a human readable description of the issue.
+	Message string
+}
+
+// PoolSummary reports the utilization of an InClusterIPPool.
+type PoolSummary struct {
+	Namespace string
+	Name      string
+
+	// Total, Used, Free and Quarantined are the counts of the addresses of the pool, as reported by its status.
+	Total       int
+	Used        int
+	Free        int
+	Quarantined int
+
+	// Exhausted is true if the pool has no free addresses left.
+	Exhausted bool
+
+	// Claims is the number of IPAddressClaims referencing the pool.
+	Claims int
+
+	// Pending is the number of IPAddressClaims referencing the pool which are not fulfilled.
+	Pending int
+
+	// Issues is the number of conflicting or stale objects of the pool.
+	Issues int
+}
+
+// Claim is an IPAddressClaim referencing an InClusterIPPool.
+type Claim struct {
+	Name string
+
+	// ClusterName and MachineName are the names of the Cluster and the Machine the claim is for, if known.
+	ClusterName string
+	MachineName string
+
+	// Address is the address allocated for the claim from the pool; it is empty if the claim is not fulfilled.
+	Address string
+
+	// Reason and Message report why the claim is not fulfilled, if known.
+	Reason  string
+	Message string
+}
+
+// PoolDescription reports the state of an InClusterIPPool.
+type PoolDescription struct {
+	Summary PoolSummary
+
+	// Addresses are the addresses of the pool, as defined in its spec.
+	Addresses []string
+
+	// Claims are the IPAddressClaims referencing the pool, sorted by cluster, machine and name.
+	Claims []Claim
+
+	// Issues are the conflicting or stale objects of the pool, sorted by object.
+	Issues []Issue
+}
+
+// ListPools returns the summary of the InClusterIPPools in a namespace, or in all the namespaces if namespace is empty.
+func ListPools(ctx context.Context, c client.Client, namespace string) ([]PoolSummary, error) {
+	poolList := &ipamv1.InClusterIPPoolList{}
+	if err := c.List(ctx, poolList, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list InClusterIPPools")
+	}
+
+	inv, err := newInventory(ctx, c, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []PoolSummary{}
+	for i := range poolList.Items {
+		summaries = append(summaries, inv.describe(ctx, &poolList.Items[i]).Summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Namespace != summaries[j].Namespace {
+			return summaries[i].Namespace < summaries[j].Namespace
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries, nil
+}
+
+// DescribePool returns the description of an InClusterIPPool.
+func DescribePool(ctx context.Context, c client.Client, namespace, name string) (*PoolDescription, error) {
+	pool := &ipamv1.InClusterIPPool{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, pool); err != nil {
+		return nil, errors.Wrapf(err, "failed to get InClusterIPPool %s/%s", namespace, name)
+	}
+
+	inv, err := newInventory(ctx, c, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return inv.describe(ctx, pool), nil
+}
+
+// inventory holds the objects required to describe the InClusterIPPools of a namespace, or of all the namespaces.
+type inventory struct {
+	client    client.Client
+	claims    []ipamv1.IPAddressClaim
+	addresses []ipamv1.IPAddress
+	machines  map[client.ObjectKey]*clusterv1.Machine
+	clusters  map[client.ObjectKey]bool
+}
+
+func newInventory(ctx context.Context, c client.Client, namespace string) (*inventory, error) {
+	claimList := &ipamv1.IPAddressClaimList{}
+	if err := c.List(ctx, claimList, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list IPAddressClaims")
+	}
+	addressList := &ipamv1.IPAddressList{}
+	if err := c.List(ctx, addressList, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list IPAddresses")
+	}
+	machineList := &clusterv1.MachineList{}
+	if err := c.List(ctx, machineList, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list Machines")
+	}
+	clusterList := &clusterv1.ClusterList{}
+	if err := c.List(ctx, clusterList, client.InNamespace(namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list Clusters")
+	}
+
+	inv := &inventory{
+		client:    c,
+		claims:    claimList.Items,
+		addresses: addressList.Items,
+		machines:  map[client.ObjectKey]*clusterv1.Machine{},
+		clusters:  map[client.ObjectKey]bool{},
+	}
+	for i := range machineList.Items {
+		inv.machines[client.ObjectKeyFromObject(&machineList.Items[i])] = &machineList.Items[i]
+	}
+	for i := range clusterList.Items {
+		inv.clusters[client.ObjectKeyFromObject(&clusterList.Items[i])] = true
+	}
+	return inv, nil
+}
+
+// claimAddress is one of the addresses requested by a claim from a pool.
+type claimAddress struct {
+	addressRef corev1.LocalObjectReference
+	condition  clusterv1.ConditionType
+}
+
+// describe returns the description of a pool, joining the IPAddressClaims and the IPAddresses referencing it with
+// the Machines and the Clusters they are for.
+func (inv *inventory) describe(ctx context.Context, pool *ipamv1.InClusterIPPool) *PoolDescription {
+	d := &PoolDescription{
+		Summary: PoolSummary{
+			Namespace: pool.Namespace,
+			Name:      pool.Name,
+			Exhausted: conditions.IsTrue(pool, ipamv1.PoolExhaustedCondition),
+		},
+		Addresses: pool.Spec.Addresses,
+		Claims:    []Claim{},
+		Issues:    []Issue{},
+	}
+	if s := pool.Status.Addresses; s != nil {
+		d.Summary.Total = s.Total
+		d.Summary.Used = s.Used
+		d.Summary.Free = s.Free
+		d.Summary.Quarantined = s.Quarantined
+	}
+
+	poolAddresses := map[string]*ipamv1.IPAddress{}
+	addressNames := map[string][]string{}
+	for i := range inv.addresses {
+		address := &inv.addresses[i]
+		if address.Namespace == pool.Namespace && isPoolRef(address.Spec.PoolRef, pool.Name) {
+			poolAddresses[address.Name] = address
+			addressNames[address.Spec.Address] = append(addressNames[address.Spec.Address], address.Name)
+		}
+	}
+
+	claimNames := map[string]bool{}
+	for i := range inv.claims {
+		claim := &inv.claims[i]
+		if claim.Namespace != pool.Namespace {
+			continue
+		}
+		claimNames[claim.Name] = true
+
+		addresses := poolClaimAddresses(claim, pool.Name)
+		if len(addresses) == 0 {
+			continue
+		}
+		machineName := claimMachineName(claim)
+		clusterName := inv.claimClusterName(claim, machineName)
+		if message := inv.staleClaimMessage(ctx, claim, clusterName, machineName); message != "" {
+			d.Issues = append(d.Issues, Issue{Type: StaleClaimIssue, Object: "IPAddressClaim/" + claim.Name, Message: message})
+		}
+
+		for _, ca := range addresses {
+			c := Claim{Name: claim.Name, ClusterName: clusterName, MachineName: machineName}
+			if ca.addressRef.Name != "" {
+				address, ok := poolAddresses[ca.addressRef.Name]
+				switch {
+				case !ok:
+					d.Issues = append(d.Issues, Issue{Type: StaleClaimIssue, Object: "IPAddressClaim/" + claim.Name,
+						Message: fmt.Sprintf("references IPAddress %s, which does not exist", ca.addressRef.Name)})
+				case address.Spec.ClaimRef.Name != claim.Name:
+					d.Issues = append(d.Issues, Issue{Type: ConflictingClaimIssue, Object: "IPAddressClaim/" + claim.Name,
+						Message: fmt.Sprintf("references IPAddress %s, allocated for IPAddressClaim %s", address.Name, address.Spec.ClaimRef.Name)})
+				default:
+					c.Address = address.Spec.Address
+				}
+			}
+			if c.Address == "" {
+				d.Summary.Pending++
+				if condition := conditions.Get(claim, ca.condition); condition != nil && condition.Status != corev1.ConditionTrue {
+					c.Reason = condition.Reason
+					c.Message = condition.Message
+				}
+			}
+			d.Claims = append(d.Claims, c)
+		}
+	}
+
+	for _, address := range poolAddresses {
+		if !claimNames[address.Spec.ClaimRef.Name] {
+			d.Issues = append(d.Issues, Issue{Type: OrphanedAddressIssue, Object: "IPAddress/" + address.Name,
+				Message: fmt.Sprintf("IPAddressClaim %s does not exist", address.Spec.ClaimRef.Name)})
+		}
+		if names := addressNames[address.Spec.Address]; len(names) > 1 {
+			others := []string{}
+			for _, name := range names {
+				if name != address.Name {
+					others = append(others, name)
+				}
+			}
+			sort.Strings(others)
+			d.Issues = append(d.Issues, Issue{Type: ConflictingAddressIssue, Object: "IPAddress/" + address.Name,
+				Message: fmt.Sprintf("address %s is also allocated to IPAddress %s", address.Spec.Address, strings.Join(others, ", "))})
+		}
+	}
+
+	sort.Slice(d.Claims, func(i, j int) bool {
+		if d.Claims[i].ClusterName != d.Claims[j].ClusterName {
+			return d.Claims[i].ClusterName < d.Claims[j].ClusterName
+		}
+		if d.Claims[i].MachineName != d.Claims[j].MachineName {
+			return d.Claims[i].MachineName < d.Claims[j].MachineName
+		}
+		return d.Claims[i].Name < d.Claims[j].Name
+	})
+	sort.SliceStable(d.Issues, func(i, j int) bool {
+		if d.Issues[i].Object != d.Issues[j].Object {
+			return d.Issues[i].Object < d.Issues[j].Object
+		}
+		return d.Issues[i].Type < d.Issues[j].Type
+	})
+	d.Summary.Claims = len(d.Claims)
+	d.Summary.Issues = len(d.Issues)
+	return d
+}
+
+// staleClaimMessage returns why a claim is not used anymore, if it is stale.
+func (inv *inventory) staleClaimMessage(ctx context.Context, claim *ipamv1.IPAddressClaim, clusterName, machineName string) string {
+	if t := claim.Status.LeaseExpirationTime; t != nil && t.Time.Before(time.Now()) {
+		return fmt.Sprintf("the lease expired at %s", t.UTC().Format(time.RFC3339))
+	}
+	if clusterName != "" && !inv.clusters[client.ObjectKey{Namespace: claim.Namespace, Name: clusterName}] {
+		return fmt.Sprintf("Cluster %s does not exist", clusterName)
+	}
+	if machineName != "" && inv.machines[client.ObjectKey{Namespace: claim.Namespace, Name: machineName}] == nil {
+		return fmt.Sprintf("Machine %s does not exist", machineName)
+	}
+	if len(claim.OwnerReferences) > 0 && !inv.ownersExist(ctx, claim) {
+		return "the owners of the claim do not exist"
+	}
+	return ""
+}
+
+// ownersExist returns false only if none of the owners of an object exist; owners which cannot be read,
+// e.g. because of missing permissions, are assumed to exist.
+func (inv *inventory) ownersExist(ctx context.Context, obj client.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return true
+		}
+		owner := &metav1.PartialObjectMetadata{}
+		owner.SetGroupVersionKind(gv.WithKind(ref.Kind))
+		err = inv.client.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: ref.Name}, owner)
+		if (err == nil && owner.UID == ref.UID) || (err != nil && !apierrors.IsNotFound(err)) {
+			return true
+		}
+	}
+	return false
+}
+
+// claimClusterName returns the name of the Cluster a claim is for, if known.
+func (inv *inventory) claimClusterName(claim *ipamv1.IPAddressClaim, machineName string) string {
+	if name := claim.Labels[clusterv1.ClusterNameLabel]; name != "" {
+		return name
+	}
+	if machine := inv.machines[client.ObjectKey{Namespace: claim.Namespace, Name: machineName}]; machine != nil {
+		return machine.Spec.ClusterName
+	}
+	return ""
+}
+
+// claimMachineName returns the name of the Machine a claim is for, if known.
+func claimMachineName(claim *ipamv1.IPAddressClaim) string {
+	if name := claim.Labels[ipamv1.MachineNameLabel]; name != "" {
+		return name
+	}
+	for _, ref := range claim.OwnerReferences {
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil && gv.Group == clusterv1.GroupVersion.Group && ref.Kind == "Machine" {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+// poolClaimAddresses returns the addresses a claim requests from a pool.
+func poolClaimAddresses(claim *ipamv1.IPAddressClaim, poolName string) []claimAddress {
+	addresses := []claimAddress{}
+	if isPoolRef(claim.Spec.PoolRef, poolName) {
+		addresses = append(addresses, claimAddress{addressRef: claim.Status.AddressRef, condition: ipamv1.AllocatedCondition})
+	}
+	if ref := claim.Spec.SecondaryPoolRef; ref != nil && isPoolRef(*ref, poolName) {
+		addresses = append(addresses, claimAddress{addressRef: claim.Status.SecondaryAddressRef, condition: ipamv1.SecondaryAllocatedCondition})
+	}
+	return addresses
+}
+
+// isPoolRef returns true if a pool reference points to the InClusterIPPool with the given name.
+func isPoolRef(ref corev1.TypedLocalObjectReference, poolName string) bool {
+	return ref.APIGroup != nil && *ref.APIGroup == ipamv1.GroupVersion.Group && ref.Kind == ipamv1.InClusterIPPoolKind && ref.Name == poolName
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const namespace = "ns1"
+
+func poolRef(name string) corev1.TypedLocalObjectReference {
+	return corev1.TypedLocalObjectReference{
+		APIGroup: pointer.String(ipamv1.GroupVersion.Group),
+		Kind:     ipamv1.InClusterIPPoolKind,
+		Name:     name,
+	}
+}
+
+func newPool(name string) *ipamv1.InClusterIPPool {
+	return &ipamv1.InClusterIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: ipamv1.InClusterIPPoolSpec{
+			Addresses: []string{"10.0.0.10-10.0.0.13"},
+			Prefix:    24,
+		},
+		Status: ipamv1.InClusterIPPoolStatus{
+			Addresses: &ipamv1.InClusterIPPoolStatusAddresses{Total: 4, Used: 3, Free: 1},
+		},
+	}
+}
+
+func newClaim(name, machineName, addressName string) *ipamv1.IPAddressClaim {
+	claim := &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       ipamv1.IPAddressClaimSpec{PoolRef: poolRef("pool")},
+		Status:     ipamv1.IPAddressClaimStatus{AddressRef: corev1.LocalObjectReference{Name: addressName}},
+	}
+	if machineName != "" {
+		claim.Labels = map[string]string{ipamv1.MachineNameLabel: machineName}
+	}
+	return claim
+}
+
+func newAddress(name, claimName, address string) *ipamv1.IPAddress {
+	return &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: ipamv1.IPAddressSpec{
+			ClaimRef: corev1.LocalObjectReference{Name: claimName},
+			PoolRef:  poolRef("pool"),
+			Address:  address,
+			Prefix:   24,
+		},
+	}
+}
+
+func TestDescribePool(t *testing.T) {
+	g := NewWithT(t)
+
+	pending := newClaim("pending", "m2", "")
+	conditions.MarkFalse(pending, ipamv1.AllocatedCondition, ipamv1.PoolExhaustedReason, clusterv1.ConditionSeverityWarning, "no free addresses")
+	objs := []client.Object{
+		newPool("pool"),
+		newPool("other"),
+		&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: namespace}},
+		&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m1", Namespace: namespace}, Spec: clusterv1.MachineSpec{ClusterName: "cluster1"}},
+		&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m2", Namespace: namespace}, Spec: clusterv1.MachineSpec{ClusterName: "cluster1"}},
+		newClaim("allocated", "m1", "allocated"),
+		pending,
+		newClaim("stale", "deleted-machine", "stale"),
+		newClaim("conflicting", "", "allocated"),
+		newAddress("allocated", "allocated", "10.0.0.10"),
+		newAddress("stale", "stale", "10.0.0.11"),
+		newAddress("orphaned", "deleted-claim", "10.0.0.12"),
+		newAddress("duplicated", "deleted-claim", "10.0.0.10"),
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build()
+
+	got, err := DescribePool(context.Background(), c, namespace, "pool")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Summary).To(Equal(PoolSummary{
+		Namespace: namespace,
+		Name:      "pool",
+		Total:     4,
+		Used:      3,
+		Free:      1,
+		Claims:    4,
+		Pending:   2,
+		Issues:    6,
+	}))
+	g.Expect(got.Claims).To(Equal([]Claim{
+		{Name: "conflicting"},
+		{Name: "stale", MachineName: "deleted-machine", Address: "10.0.0.11"},
+		{Name: "allocated", ClusterName: "cluster1", MachineName: "m1", Address: "10.0.0.10"},
+		{Name: "pending", ClusterName: "cluster1", MachineName: "m2", Reason: ipamv1.PoolExhaustedReason, Message: "no free addresses"},
+	}))
+	g.Expect(got.Issues).To(Equal([]Issue{
+		{Type: ConflictingAddressIssue, Object: "IPAddress/allocated", Message: "address 10.0.0.10 is also allocated to IPAddress duplicated"},
+		{Type: ConflictingAddressIssue, Object: "IPAddress/duplicated", Message: "address 10.0.0.10 is also allocated to IPAddress allocated"},
+		{Type: OrphanedAddressIssue, Object: "IPAddress/duplicated", Message: "IPAddressClaim deleted-claim does not exist"},
+		{Type: OrphanedAddressIssue, Object: "IPAddress/orphaned", Message: "IPAddressClaim deleted-claim does not exist"},
+		{Type: ConflictingClaimIssue, Object: "IPAddressClaim/conflicting", Message: "references IPAddress allocated, allocated for IPAddressClaim allocated"},
+		{Type: StaleClaimIssue, Object: "IPAddressClaim/stale", Message: "Machine deleted-machine does not exist"},
+	}))
+
+	summaries, err := ListPools(context.Background(), c, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(summaries).To(HaveLen(2))
+	g.Expect(summaries[0].Name).To(Equal("other"))
+	g.Expect(summaries[0].Claims).To(Equal(0))
+	g.Expect(summaries[1]).To(Equal(got.Summary))
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/machine"
+)
+
+// DescribeMachineOptions carries the options supported by DescribeMachine.
+type DescribeMachineOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the Machine is located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// MachineName is the name of the Machine.
+	MachineName string
+}
+
+// GetMachineLogsOptions carries the options supported by GetMachineLogs.
+type GetMachineLogsOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the Machine is located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// MachineName is the name of the Machine.
+	MachineName string
+
+	// Source selects which logs to read, e.g. console or kubelet; the supported values depend on the infrastructure
+	// provider, which returns the console logs if empty.
+	Source string
+
+	// InsecureSkipTLSVerify skips the verification of the certificate of the endpoint serving the logs.
+	InsecureSkipTLSVerify bool
+}
+
+// DescribeMachine returns the status of a Machine and of its infrastructure machine.
+func (c *clusterctlClient) DescribeMachine(ctx context.Context, options DescribeMachineOptions) (*machine.Description, error) {
+	// gets access to the management cluster
+	cluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract.
+	if err := cluster.ProviderInventory().CheckCAPIContract(ctx); err != nil {
+		return nil, err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := cluster.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	client, err := cluster.Proxy().NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return machine.Describe(ctx, client, options.Namespace, options.MachineName)
+}
+
+// GetMachineLogs returns a stream of the console logs of a Machine, read from the URL exposed by the infrastructure provider.
+func (c *clusterctlClient) GetMachineLogs(ctx context.Context, options GetMachineLogsOptions) (io.ReadCloser, error) {
+	d, err := c.DescribeMachine(ctx, DescribeMachineOptions{
+		Kubeconfig:  options.Kubeconfig,
+		Namespace:   options.Namespace,
+		MachineName: options.MachineName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+	restConfig, err := cluster.Proxy().GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return machine.Logs(ctx, restConfig, d, machine.LogsOptions{
+		Source:                options.Source,
+		InsecureSkipTLSVerify: options.InsecureSkipTLSVerify,
+	})
+}
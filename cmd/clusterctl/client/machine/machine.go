@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+)
+
+// ConsoleLogsURLField is the path of the optional field of infrastructure machines reporting the URL where
+// the console logs of the machine can be read, see the machine infrastructure provider contract.
+var ConsoleLogsURLField = []string{"status", "consoleLogsURL"}
+
+// SSHField is the path of the optional field of infrastructure machines reporting the endpoint for SSH access to the
+// machine, see the machine infrastructure provider contract.
+var SSHField = []string{"status", "ssh"}
+
+// SSHEndpoint is the endpoint for SSH access to a machine.
+type SSHEndpoint struct {
+	User string
+	Host string
+	Port int64
+}
+
+// Description is the status of a Machine and of its infrastructure machine.
+type Description struct {
+	Machine *clusterv1.Machine
+
+	// InfrastructureMachine is the infrastructure machine of the Machine, nil if it does not exist.
+	InfrastructureMachine *unstructured.Unstructured
+
+	// ConsoleLogsURL is the URL where the console logs of the machine can be read, empty if the infrastructure
+	// provider does not expose console logs.
+	ConsoleLogsURL string
+
+	// SSHEndpoint is the endpoint for SSH access to the machine, nil if the infrastructure provider does not expose it.
+	SSHEndpoint *SSHEndpoint
+}
+
+// Describe returns the status of a Machine and of its infrastructure machine.
+func Describe(ctx context.Context, c client.Client, namespace, name string) (*Description, error) {
+	machine := &clusterv1.Machine{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, machine); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Machine %s/%s", namespace, name)
+	}
+
+	d := &Description{Machine: machine}
+	infraMachine, err := external.Get(ctx, c, &machine.Spec.InfrastructureRef, machine.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return d, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the infrastructure machine of Machine %s/%s", namespace, name)
+	}
+	d.InfrastructureMachine = infraMachine
+
+	logsURL, _, err := unstructured.NestedString(infraMachine.UnstructuredContent(), ConsoleLogsURLField...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s from %s %s", strings.Join(ConsoleLogsURLField, "."), infraMachine.GetKind(), infraMachine.GetName())
+	}
+	d.ConsoleLogsURL = logsURL
+
+	ssh, found, err := unstructured.NestedMap(infraMachine.UnstructuredContent(), SSHField...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s from %s %s", strings.Join(SSHField, "."), infraMachine.GetKind(), infraMachine.GetName())
+	}
+	if found {
+		d.SSHEndpoint = &SSHEndpoint{}
+		d.SSHEndpoint.User, _, _ = unstructured.NestedString(ssh, "user")
+		d.SSHEndpoint.Host, _, _ = unstructured.NestedString(ssh, "host")
+		d.SSHEndpoint.Port, _, _ = unstructured.NestedInt64(ssh, "port")
+	}
+	return d, nil
+}
+
+// SSHArgs returns the arguments of the ssh command for logging in to the machine described by d, followed by args.
+func SSHArgs(d *Description, args []string) ([]string, error) {
+	machine := fmt.Sprintf("%s/%s", d.Machine.Namespace, d.Machine.Name)
+	if d.SSHEndpoint == nil {
+		return nil, errors.Errorf("the infrastructure provider of Machine %s does not expose SSH access: %s is not set on the infrastructure machine", machine, strings.Join(SSHField, "."))
+	}
+	if d.SSHEndpoint.Host == "" || d.SSHEndpoint.Port == 0 {
+		return nil, errors.Errorf("invalid SSH endpoint for Machine %s: host and port must be set", machine)
+	}
+
+	destination := d.SSHEndpoint.Host
+	if d.SSHEndpoint.User != "" {
+		destination = d.SSHEndpoint.User + "@" + destination
+	}
+	return append([]string{"-p", strconv.FormatInt(d.SSHEndpoint.Port, 10), destination}, args...), nil
+}
+
+// LogsOptions carries the options supported by Logs.
+type LogsOptions struct {
+	// Source selects which logs to read, e.g. console or kubelet; the supported values depend on the infrastructure
+	// provider, which returns the console logs if empty.
+	Source string
+
+	// InsecureSkipTLSVerify skips the verification of the certificate of the endpoint serving the logs.
+	InsecureSkipTLSVerify bool
+}
+
+// Logs opens a stream of the console logs of the machine described by d.
+// The request is authenticated with the credentials used to access the management cluster, because infrastructure
+// providers are expected to authorize it with the RBAC rules of the management cluster; the certificate of the
+// endpoint serving the logs is verified using the system roots, unless InsecureSkipTLSVerify is set.
+func Logs(ctx context.Context, restConfig *rest.Config, d *Description, options LogsOptions) (io.ReadCloser, error) {
+	machine := fmt.Sprintf("%s/%s", d.Machine.Namespace, d.Machine.Name)
+	if d.ConsoleLogsURL == "" {
+		return nil, errors.Errorf("the infrastructure provider of Machine %s does not expose console logs: %s is not set on the infrastructure machine", machine, strings.Join(ConsoleLogsURLField, "."))
+	}
+
+	u, err := url.Parse(d.ConsoleLogsURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid console logs URL %q for Machine %s", d.ConsoleLogsURL, machine)
+	}
+	if !u.IsAbs() {
+		return nil, errors.Errorf("the console logs URL %q for Machine %s is not absolute; the infrastructure provider must be configured with the URL it is reachable at", d.ConsoleLogsURL, machine)
+	}
+	if options.Source != "" {
+		query := u.Query()
+		query.Set("source", options.Source)
+		u.RawQuery = query.Encode()
+	}
+
+	config := rest.CopyConfig(restConfig)
+	// NOTE: The endpoint serving the logs is not the API server, so its certificate is not signed by the CA of the cluster.
+	config.TLSClientConfig.CAFile = ""
+	config.TLSClientConfig.CAData = nil
+	config.TLSClientConfig.ServerName = ""
+	config.TLSClientConfig.Insecure = options.InsecureSkipTLSVerify
+	transport, err := rest.TransportFor(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the transport for reading console logs")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the request for reading console logs of Machine %s", machine)
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read console logs of Machine %s", machine)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, errors.Errorf("failed to read console logs of Machine %s: %s: %s", machine, resp.Status, string(body))
+	}
+	return resp.Body, nil
+}
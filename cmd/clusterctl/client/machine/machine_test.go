@@ -0,0 +1,244 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
+)
+
+const namespace = "ns1"
+
+func newMachine() *clusterv1.Machine {
+	return &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: namespace},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "cluster",
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Kind:       "GenericInfrastructureMachine",
+				Name:       "infra-machine",
+			},
+		},
+	}
+}
+
+func newInfraMachine(logsURL string, ssh ...map[string]interface{}) *unstructured.Unstructured {
+	infraMachine := &unstructured.Unstructured{}
+	infraMachine.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta1")
+	infraMachine.SetKind("GenericInfrastructureMachine")
+	infraMachine.SetNamespace(namespace)
+	infraMachine.SetName("infra-machine")
+	if logsURL != "" {
+		_ = unstructured.SetNestedField(infraMachine.Object, logsURL, ConsoleLogsURLField...)
+	}
+	for _, endpoint := range ssh {
+		_ = unstructured.SetNestedMap(infraMachine.Object, endpoint, SSHField...)
+	}
+	return infraMachine
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name               string
+		objs               []client.Object
+		wantErr            bool
+		wantInfraMachine   bool
+		wantConsoleLogsURL string
+		wantSSHEndpoint    *SSHEndpoint
+	}{
+		{
+			name:    "fails if the Machine does not exist",
+			wantErr: true,
+		},
+		{
+			name: "tolerates a missing infrastructure machine",
+			objs: []client.Object{newMachine()},
+		},
+		{
+			name:             "infrastructure machine without console logs",
+			objs:             []client.Object{newMachine(), newInfraMachine("")},
+			wantInfraMachine: true,
+		},
+		{
+			name:               "infrastructure machine with console logs",
+			objs:               []client.Object{newMachine(), newInfraMachine("https://logs.example.com/machine")},
+			wantInfraMachine:   true,
+			wantConsoleLogsURL: "https://logs.example.com/machine",
+		},
+		{
+			name:             "infrastructure machine with SSH access",
+			objs:             []client.Object{newMachine(), newInfraMachine("", map[string]interface{}{"user": "root", "host": "127.0.0.1", "port": int64(32768)})},
+			wantInfraMachine: true,
+			wantSSHEndpoint:  &SSHEndpoint{User: "root", Host: "127.0.0.1", Port: 32768},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tt.objs...).Build()
+			got, err := Describe(context.Background(), c, namespace, "machine")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Machine.Name).To(Equal("machine"))
+			g.Expect(got.InfrastructureMachine != nil).To(Equal(tt.wantInfraMachine))
+			g.Expect(got.ConsoleLogsURL).To(Equal(tt.wantConsoleLogsURL))
+			g.Expect(got.SSHEndpoint).To(Equal(tt.wantSSHEndpoint))
+		})
+	}
+}
+
+func TestSSHArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint *SSHEndpoint
+		args     []string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "logs in with the user of the endpoint",
+			endpoint: &SSHEndpoint{User: "root", Host: "127.0.0.1", Port: 32768},
+			want:     []string{"-p", "32768", "root@127.0.0.1"},
+		},
+		{
+			name:     "logs in with the default user",
+			endpoint: &SSHEndpoint{Host: "127.0.0.1", Port: 32768},
+			want:     []string{"-p", "32768", "127.0.0.1"},
+		},
+		{
+			name:     "appends the command to run",
+			endpoint: &SSHEndpoint{User: "root", Host: "127.0.0.1", Port: 32768},
+			args:     []string{"journalctl", "-u", "kubelet"},
+			want:     []string{"-p", "32768", "root@127.0.0.1", "journalctl", "-u", "kubelet"},
+		},
+		{
+			name:    "fails if the infrastructure provider does not expose SSH access",
+			wantErr: true,
+		},
+		{
+			name:     "fails if the endpoint is incomplete",
+			endpoint: &SSHEndpoint{User: "root"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := SSHArgs(&Description{Machine: newMachine(), SSHEndpoint: tt.endpoint}, tt.args)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestLogs(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("logs from " + r.URL.Query().Get("source")))
+	}))
+	defer server.Close()
+
+	restConfig := &rest.Config{Host: "https://api.example.com:6443", BearerToken: "token"}
+	newDescription := func(logsURL string) *Description {
+		return &Description{Machine: newMachine(), ConsoleLogsURL: logsURL}
+	}
+
+	tests := []struct {
+		name        string
+		description *Description
+		restConfig  *rest.Config
+		options     LogsOptions
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "reads the logs of the selected source",
+			description: newDescription(server.URL + "/logs/machines/ns1/infra-machine"),
+			restConfig:  restConfig,
+			options:     LogsOptions{Source: "kubelet", InsecureSkipTLSVerify: true},
+			want:        "logs from kubelet",
+		},
+		{
+			name:        "fails if the certificate cannot be verified",
+			description: newDescription(server.URL + "/logs/machines/ns1/infra-machine"),
+			restConfig:  restConfig,
+			wantErr:     true,
+		},
+		{
+			name:        "fails if the request is not authorized",
+			description: newDescription(server.URL + "/logs/machines/ns1/infra-machine"),
+			restConfig:  &rest.Config{Host: "https://api.example.com:6443", BearerToken: "another-token"},
+			options:     LogsOptions{InsecureSkipTLSVerify: true},
+			wantErr:     true,
+		},
+		{
+			name:        "fails if the infrastructure provider does not expose console logs",
+			description: newDescription(""),
+			restConfig:  restConfig,
+			wantErr:     true,
+		},
+		{
+			name:        "fails if the console logs URL is relative",
+			description: newDescription("/logs/machines/ns1/infra-machine"),
+			restConfig:  restConfig,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			logs, err := Logs(context.Background(), tt.restConfig, tt.description, tt.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			defer logs.Close()
+			got, err := io.ReadAll(logs)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+// MaintenanceOptions carries the options supported by EnterMaintenance, ExitMaintenance and MaintenanceStatus.
+type MaintenanceOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace to put in maintenance. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// Reason of the maintenance, used only by EnterMaintenance.
+	Reason string
+}
+
+// EnterMaintenance puts a namespace of the management cluster in maintenance, pausing the reconciliation of the Cluster API objects in it.
+func (c *clusterctlClient) EnterMaintenance(ctx context.Context, options MaintenanceOptions) error {
+	clusterClient, err := c.getMaintenanceClusterClient(ctx, &options)
+	if err != nil {
+		return err
+	}
+	return clusterClient.Maintenance().Enter(ctx, options.Namespace, options.Reason)
+}
+
+// ExitMaintenance ends the maintenance of a namespace of the management cluster, resuming the reconciliation of the Cluster API objects in it.
+func (c *clusterctlClient) ExitMaintenance(ctx context.Context, options MaintenanceOptions) error {
+	clusterClient, err := c.getMaintenanceClusterClient(ctx, &options)
+	if err != nil {
+		return err
+	}
+	return clusterClient.Maintenance().Exit(ctx, options.Namespace)
+}
+
+// MaintenanceStatus returns the maintenance status of a namespace of the management cluster.
+func (c *clusterctlClient) MaintenanceStatus(ctx context.Context, options MaintenanceOptions) (*cluster.MaintenanceStatus, error) {
+	clusterClient, err := c.getMaintenanceClusterClient(ctx, &options)
+	if err != nil {
+		return nil, err
+	}
+	return clusterClient.Maintenance().Status(ctx, options.Namespace)
+}
+
+// getMaintenanceClusterClient returns the client for the management cluster, defaulting the namespace in the options.
+func (c *clusterctlClient) getMaintenanceClusterClient(ctx context.Context, options *MaintenanceOptions) (cluster.Client, error) {
+	// gets access to the management cluster
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract.
+	if err := clusterClient.ProviderInventory().CheckCAPIContract(ctx); err != nil {
+		return nil, err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+	return clusterClient, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+// SupportBundleOptions carries the options supported by SupportBundle.
+type SupportBundleOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the workload cluster is located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// ClusterName is the name of the workload cluster.
+	ClusterName string
+
+	// LogsSince limits the logs of the providers to the given duration; if 0, all the logs are collected.
+	LogsSince time.Duration
+
+	// Output is where the support bundle is written, as a gzipped tarball.
+	Output io.Writer
+}
+
+// SupportBundle writes a support bundle with the information required to troubleshoot a workload cluster.
+func (c *clusterctlClient) SupportBundle(ctx context.Context, options SupportBundleOptions) error {
+	if options.ClusterName == "" {
+		return errors.New("cluster name must be specified")
+	}
+	if options.Output == nil {
+		return errors.New("output must be specified")
+	}
+
+	// gets access to the management cluster
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract.
+	if err := clusterClient.ProviderInventory().CheckCAPIContract(ctx); err != nil {
+		return err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	return clusterClient.SupportBundle().Collect(ctx, cluster.SupportBundleOptions{
+		Namespace:   options.Namespace,
+		ClusterName: options.ClusterName,
+		LogsSince:   options.LogsSince,
+	}, options.Output)
+}
@@ -26,6 +26,9 @@ const (
 	// ShowObjectConditionsAnnotation documents that the presentation layer should show all the conditions for the object.
 	ShowObjectConditionsAnnotation = "tree.cluster.x-k8s.io.io/show-conditions"
 
+	// ShowObjectTimelineAnnotation documents that the presentation layer should show a timeline of the recent condition transitions for the object.
+	ShowObjectTimelineAnnotation = "tree.cluster.x-k8s.io.io/show-timeline"
+
 	// ObjectMetaNameAnnotation contains the meta name that should be used for the object in the presentation layer,
 	// e.g. control plane for KCP.
 	ObjectMetaNameAnnotation = "tree.cluster.x-k8s.io.io/meta-name"
@@ -118,6 +121,14 @@ func IsShowConditionsObject(obj client.Object) bool {
 	return false
 }
 
+// IsShowTimelineObject returns true if the presentation layer should show a timeline of the recent condition transitions for the object.
+func IsShowTimelineObject(obj client.Object) bool {
+	if val, ok := getBoolAnnotation(obj, ShowObjectTimelineAnnotation); ok {
+		return val
+	}
+	return false
+}
+
 func getAnnotation(obj client.Object, annotation string) (string, bool) {
 	if obj == nil {
 		return "", false
@@ -38,6 +38,10 @@ type DiscoverOptions struct {
 	// to signal to the presentation layer to show all the conditions for the objects.
 	ShowOtherConditions string
 
+	// ShowTimeline is a list of comma separated kind or kind/name for which we should add the ShowObjectTimelineAnnotation
+	// to signal to the presentation layer to show a timeline of the recent condition transitions for the objects.
+	ShowTimeline string
+
 	// ShowMachineSets instructs the discovery process to include machine sets in the ObjectTree.
 	ShowMachineSets bool
 
@@ -171,6 +175,10 @@ func Discovery(ctx context.Context, c client.Client, namespace, name string, opt
 		}
 	}
 
+	if options.ShowTimeline != "" {
+		addTimelinesToObjectTree(tree)
+	}
+
 	return tree, nil
 }
 
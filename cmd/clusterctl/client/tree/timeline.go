@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// MaxTimelineEntries is the maximum number of entries in the timeline of an object.
+const MaxTimelineEntries = 5
+
+// TimelineEntry is an entry in the timeline of an object, i.e. the last transition of one of its conditions,
+// e.g. the Machine becoming Ready=False with reason Draining.
+type TimelineEntry struct {
+	// Time is the last transition time of the condition.
+	Time metav1.Time
+
+	// Condition is the type of the condition which transitioned.
+	Condition clusterv1.ConditionType
+
+	// Status of the condition after the transition, one of True, False, Unknown.
+	Status corev1.ConditionStatus
+
+	// Severity of the condition after the transition, if the condition is False.
+	Severity clusterv1.ConditionSeverity
+
+	// Reason is the reason of the condition after the transition, if any.
+	Reason string
+}
+
+// GetTimeline returns the timeline of the object with the given uid, oldest entry first.
+func (od ObjectTree) GetTimeline(id types.UID) []TimelineEntry { return od.timelines[id] }
+
+// SetTimeline sets the timeline of the object with the given uid.
+func (od ObjectTree) SetTimeline(id types.UID, timeline []TimelineEntry) {
+	od.timelines[id] = timeline
+}
+
+// addTimelinesToObjectTree sets the timeline of the objects for which it is requested to show a timeline,
+// using the most recent transitions of the conditions of each object.
+// NOTE: Conditions only store the time of their last transition, so the timeline includes at most one
+// transition for each condition, and transitions overridden by a following transition of the same condition
+// are not included.
+func addTimelinesToObjectTree(tree *ObjectTree) {
+	objs := []client.Object{tree.GetRoot()}
+	for _, obj := range tree.items {
+		objs = append(objs, obj)
+	}
+
+	for _, obj := range objs {
+		if !IsShowTimelineObject(obj) || IsVirtualObject(obj) {
+			continue
+		}
+		if timeline := conditionsTimeline(obj); len(timeline) > 0 {
+			tree.SetTimeline(obj.GetUID(), timeline)
+		}
+	}
+}
+
+// conditionsTimeline returns the most recent transitions of the conditions of an object, oldest first.
+func conditionsTimeline(obj client.Object) []TimelineEntry {
+	getter := objToGetter(obj)
+	if getter == nil {
+		return nil
+	}
+
+	timeline := []TimelineEntry{}
+	for _, c := range getter.GetConditions() {
+		if c.LastTransitionTime.IsZero() {
+			continue
+		}
+		timeline = append(timeline, TimelineEntry{
+			Time:      c.LastTransitionTime,
+			Condition: c.Type,
+			Status:    c.Status,
+			Severity:  c.Severity,
+			Reason:    c.Reason,
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		if timeline[i].Time.Equal(&timeline[j].Time) {
+			return timeline[i].Condition < timeline[j].Condition
+		}
+		return timeline[i].Time.Before(&timeline[j].Time)
+	})
+	if len(timeline) > MaxTimelineEntries {
+		timeline = timeline[len(timeline)-MaxTimelineEntries:]
+	}
+	return timeline
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func Test_addTimelinesToObjectTree(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now().Truncate(time.Second)
+	condition := func(conditionType clusterv1.ConditionType, status corev1.ConditionStatus, reason string, ago time.Duration) clusterv1.Condition {
+		return clusterv1.Condition{
+			Type:               conditionType,
+			Status:             status,
+			Reason:             reason,
+			LastTransitionTime: metav1.NewTime(now.Add(-ago)),
+		}
+	}
+
+	m1 := fakeMachine("m1")
+	for i := 0; i < MaxTimelineEntries+2; i++ {
+		m1.Status.Conditions = append(m1.Status.Conditions,
+			condition(clusterv1.ConditionType(fmt.Sprintf("Condition%d", i)), corev1.ConditionTrue, "", time.Duration(10-i)*time.Minute))
+	}
+	m2 := fakeMachine("m2")
+	m2.Status.Conditions = clusterv1.Conditions{
+		condition(clusterv1.ReadyCondition, corev1.ConditionFalse, "Draining", time.Minute),
+		condition(clusterv1.BootstrapReadyCondition, corev1.ConditionTrue, "", time.Hour),
+		{Type: clusterv1.InfrastructureReadyCondition, Status: corev1.ConditionUnknown},
+	}
+	cluster := fakeCluster("cluster")
+	cluster.Status.Conditions = clusterv1.Conditions{condition(clusterv1.ReadyCondition, corev1.ConditionTrue, "", time.Hour)}
+
+	tree := NewObjectTree(cluster, ObjectTreeOptions{ShowTimeline: "Machine"})
+	tree.Add(cluster, m1)
+	tree.Add(cluster, m2)
+
+	addTimelinesToObjectTree(tree)
+
+	// The timeline has the most recent transitions, oldest first.
+	timeline := tree.GetTimeline("m1")
+	g.Expect(timeline).To(HaveLen(MaxTimelineEntries))
+	g.Expect(timeline[0].Condition).To(Equal(clusterv1.ConditionType("Condition2")))
+	g.Expect(timeline[MaxTimelineEntries-1].Condition).To(Equal(clusterv1.ConditionType(fmt.Sprintf("Condition%d", MaxTimelineEntries+1))))
+	g.Expect(timeline[MaxTimelineEntries-1].Time.Time).To(BeTemporally("==", now.Add(-4*time.Minute)))
+
+	// Conditions without a transition time are not included.
+	g.Expect(tree.GetTimeline("m2")).To(HaveExactElements(
+		HaveField("Condition", clusterv1.BootstrapReadyCondition),
+		And(HaveField("Condition", clusterv1.ReadyCondition), HaveField("Status", corev1.ConditionFalse), HaveField("Reason", "Draining")),
+	))
+
+	// The timeline is not set for objects for which it is not requested.
+	g.Expect(tree.GetTimeline("cluster")).To(BeEmpty())
+}
@@ -38,6 +38,10 @@ type ObjectTreeOptions struct {
 	// to signal to the presentation layer to show all the conditions for the objects.
 	ShowOtherConditions string
 
+	// ShowTimeline is a list of comma separated kind or kind/name for which we should add the ShowObjectTimelineAnnotation
+	// to signal to the presentation layer to show a timeline of the recent condition transitions for the objects.
+	ShowTimeline string
+
 	// ShowMachineSets instructs the discovery process to include machine sets in the ObjectTree.
 	ShowMachineSets bool
 
@@ -65,6 +69,7 @@ type ObjectTree struct {
 	options   ObjectTreeOptions
 	items     map[types.UID]client.Object
 	ownership map[types.UID]map[types.UID]bool
+	timelines map[types.UID][]TimelineEntry
 }
 
 // NewObjectTree creates a new object tree with the given root and options.
@@ -75,11 +80,18 @@ func NewObjectTree(root client.Object, options ObjectTreeOptions) *ObjectTree {
 		addAnnotation(root, ShowObjectConditionsAnnotation, "True")
 	}
 
+	// If it is requested to show the timeline for the root, add
+	// the ShowObjectTimelineAnnotation to signal this to the presentation layer.
+	if isObjDebug(root, options.ShowTimeline) {
+		addAnnotation(root, ShowObjectTimelineAnnotation, "True")
+	}
+
 	return &ObjectTree{
 		root:      root,
 		options:   options,
 		items:     make(map[types.UID]client.Object),
 		ownership: make(map[types.UID]map[types.UID]bool),
+		timelines: make(map[types.UID][]TimelineEntry),
 	}
 }
 
@@ -100,6 +112,12 @@ func (od ObjectTree) Add(parent, obj client.Object, opts ...AddObjectOption) (ad
 		addAnnotation(obj, ShowObjectConditionsAnnotation, "True")
 	}
 
+	// If it is requested to show the timeline for the object, add
+	// the ShowObjectTimelineAnnotation to signal this to the presentation layer.
+	if isObjDebug(obj, od.options.ShowTimeline) {
+		addAnnotation(obj, ShowObjectTimelineAnnotation, "True")
+	}
+
 	// If the object should be hidden if the object's ready condition is true ot it has the
 	// same Status, Severity and Reason of the parent's object ready condition (it is an echo),
 	// return early.
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"k8s.io/client-go/rest"
+)
+
+// WarningCollector collects the warnings returned by the API server of the management cluster, e.g. by the
+// admission webhooks of the providers, so they can be displayed once the command completes instead of being
+// interleaved with its output.
+type WarningCollector struct {
+	lock     sync.Mutex
+	warnings []string
+	seen     map[string]bool
+}
+
+var _ rest.WarningHandler = &WarningCollector{}
+
+// NewWarningCollector returns a WarningCollector.
+func NewWarningCollector() *WarningCollector {
+	return &WarningCollector{
+		seen: map[string]bool{},
+	}
+}
+
+// HandleWarningHeader implements rest.WarningHandler.
+// Only warnings with code 299 are collected, given that other codes are not used for deprecation or admission
+// warnings; the same warning, e.g. returned for all the objects of a kind, is collected only once.
+func (w *WarningCollector) HandleWarningHeader(code int, _ string, text string) {
+	if code != 299 || text == "" {
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.seen[text] {
+		return
+	}
+	w.seen[text] = true
+	w.warnings = append(w.warnings, text)
+}
+
+// Warnings returns the collected warnings, in the order they have been returned by the API server.
+func (w *WarningCollector) Warnings() []string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return append([]string{}, w.warnings...)
+}
+
+// Print writes the collected warnings, if any.
+func (w *WarningCollector) Print(out io.Writer) {
+	warnings := w.Warnings()
+	if len(warnings) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "\nThe management cluster returned %d warning(s); review them before proceeding:\n", len(warnings))
+	for _, warning := range warnings {
+		fmt.Fprintf(out, "  WARNING: %s\n", warning)
+	}
+}
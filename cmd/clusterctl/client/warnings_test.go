@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWarningCollector(t *testing.T) {
+	t.Run("collects deduplicated warnings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		w := NewWarningCollector()
+		w.HandleWarningHeader(299, "", "spec.topology.rolloutAfter is deprecated")
+		w.HandleWarningHeader(299, "", "spec.strategy.rollingUpdate.maxUnavailable: 100% allows all the 3 Machines to be unavailable")
+		w.HandleWarningHeader(299, "", "spec.topology.rolloutAfter is deprecated")
+		// Warnings with codes other than 299 or without text are ignored.
+		w.HandleWarningHeader(199, "", "misc warning")
+		w.HandleWarningHeader(299, "", "")
+
+		g.Expect(w.Warnings()).To(Equal([]string{
+			"spec.topology.rolloutAfter is deprecated",
+			"spec.strategy.rollingUpdate.maxUnavailable: 100% allows all the 3 Machines to be unavailable",
+		}))
+
+		out := &bytes.Buffer{}
+		w.Print(out)
+		g.Expect(out.String()).To(Equal("\nThe management cluster returned 2 warning(s); review them before proceeding:\n" +
+			"  WARNING: spec.topology.rolloutAfter is deprecated\n" +
+			"  WARNING: spec.strategy.rollingUpdate.maxUnavailable: 100% allows all the 3 Machines to be unavailable\n"))
+	})
+
+	t.Run("prints nothing without warnings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out := &bytes.Buffer{}
+		NewWarningCollector().Print(out)
+		g.Expect(out.String()).To(BeEmpty())
+	})
+}
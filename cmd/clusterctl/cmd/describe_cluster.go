@@ -59,6 +59,7 @@ type describeClusterOptions struct {
 	kubeconfigContext       string
 	namespace               string
 	showOtherConditions     string
+	showTimeline            string
 	showMachineSets         bool
 	showClusterResourceSets bool
 	showTemplates           bool
@@ -88,6 +89,9 @@ var describeClusterClusterCmd = &cobra.Command{
 		# Describe the cluster named test-1 showing all the conditions for a specific machine.
 		clusterctl describe cluster test-1 --show-conditions Machine/m1
 
+		# Describe the cluster named test-1 showing a timeline of the recent condition transitions for all the machines.
+		clusterctl describe cluster test-1 --show-timeline Machine
+
 		# Describe the cluster named test-1 disabling automatic grouping of objects with the same ready condition
 		# e.g. un-group all the machines with Ready=true instead of showing a single group node.
 		clusterctl describe cluster test-1 --grouping=false
@@ -117,6 +121,8 @@ func init() {
 
 	describeClusterClusterCmd.Flags().StringVar(&dc.showOtherConditions, "show-conditions", "",
 		"list of comma separated kind or kind/name for which the command should show all the object's conditions (use 'all' to show conditions for everything).")
+	describeClusterClusterCmd.Flags().StringVar(&dc.showTimeline, "show-timeline", "",
+		"list of comma separated kind or kind/name for which the command should show a timeline of the object's recent condition transitions (use 'all' to show the timeline for everything).")
 	describeClusterClusterCmd.Flags().BoolVar(&dc.showMachineSets, "show-machinesets", false,
 		"Show MachineSet objects.")
 	describeClusterClusterCmd.Flags().BoolVar(&dc.showClusterResourceSets, "show-resourcesets", false,
@@ -159,6 +165,7 @@ func runDescribeCluster(cmd *cobra.Command, name string) error {
 		Namespace:               dc.namespace,
 		ClusterName:             name,
 		ShowOtherConditions:     dc.showOtherConditions,
+		ShowTimeline:            dc.showTimeline,
 		ShowClusterResourceSets: dc.showClusterResourceSets,
 		ShowTemplates:           dc.showTemplates,
 		ShowMachineSets:         dc.showMachineSets,
@@ -242,10 +249,23 @@ func addObjectRow(prefix string, tbl *tablewriter.Table, objectTree *tree.Object
 		readyDescriptor.age,
 		readyDescriptor.message})
 
-	// If it is required to show all the conditions for the object, add a row for each object's conditions.
+	// If it is required to show all the conditions for the object, add a row for each object's conditions;
+	// otherwise, add a row for the rollout in progress, if any, so it is possible to tell at a glance if a Cluster is
+	// being rolled out, e.g. upgraded.
+	var objConditions []*clusterv1.Condition
 	if tree.IsShowConditionsObject(obj) {
-		addOtherConditions(prefix, tbl, objectTree, obj)
+		objConditions = tree.GetOtherConditions(obj)
+	} else if rollout := getRolloutInProgressCondition(obj); rollout != nil {
+		objConditions = []*clusterv1.Condition{rollout}
+	}
+
+	// If it is required to show the timeline for the object, add a row with the object's recent condition transitions
+	// after the conditions, so it is possible to tell if an object just started e.g. provisioning or it is stuck since a while.
+	var timeline []tree.TimelineEntry
+	if tree.IsShowTimelineObject(obj) {
+		timeline = objectTree.GetTimeline(obj.GetUID())
 	}
+	addConditions(prefix, tbl, objectTree, obj, objConditions, timeline)
 
 	// Add a row for each object's children, taking care of updating the tree view prefix.
 	childrenObj := objectTree.GetObjectsByParent(obj.GetUID())
@@ -267,10 +287,19 @@ func addObjectRow(prefix string, tbl *tablewriter.Table, objectTree *tree.Object
 	}
 }
 
-// addOtherConditions adds a row for each object condition except the ready condition,
-// which is already represented on the object's main row.
-func addOtherConditions(prefix string, tbl *tablewriter.Table, objectTree *tree.ObjectTree, obj ctrlclient.Object) {
-	// Add a row for each other condition, taking care of updating the tree view prefix.
+// getRolloutInProgressCondition returns the RolloutCompleted condition of an object, if it reports a rollout in progress.
+func getRolloutInProgressCondition(obj ctrlclient.Object) *clusterv1.Condition {
+	for _, c := range tree.GetOtherConditions(obj) {
+		if c.Type == clusterv1.RolloutCompletedCondition && c.Status == corev1.ConditionFalse {
+			return c
+		}
+	}
+	return nil
+}
+
+// addConditions adds a row for each of the given object conditions, and a row for the object timeline, if any.
+func addConditions(prefix string, tbl *tablewriter.Table, objectTree *tree.ObjectTree, obj ctrlclient.Object, objConditions []*clusterv1.Condition, timeline []tree.TimelineEntry) {
+	// Add a row for each condition, taking care of updating the tree view prefix.
 	// In this case the tree prefix get a filler, to indent conditions from objects, and eventually a
 	// and additional pipe if the object has children that should be presented after the conditions.
 	filler := strings.Repeat(" ", 10)
@@ -279,18 +308,34 @@ func addOtherConditions(prefix string, tbl *tablewriter.Table, objectTree *tree.
 		childrenPipe = pipe
 	}
 
-	otherConditions := tree.GetOtherConditions(obj)
-	for i := range otherConditions {
-		otherCondition := otherConditions[i]
-		otherDescriptor := newConditionDescriptor(otherCondition)
-		otherConditionPrefix := getChildPrefix(prefix+childrenPipe+filler, i, len(otherConditions))
+	rowCount := len(objConditions)
+	if len(timeline) > 0 {
+		rowCount++
+	}
+
+	for i := range objConditions {
+		condition := objConditions[i]
+		descriptor := newConditionDescriptor(condition)
+		conditionPrefix := getChildPrefix(prefix+childrenPipe+filler, i, rowCount)
+		tbl.Append([]string{
+			fmt.Sprintf("%s%s", gray.Sprint(conditionPrefix), cyan.Sprint(condition.Type)),
+			descriptor.readyColor.Sprint(descriptor.status),
+			descriptor.readyColor.Sprint(descriptor.severity),
+			descriptor.readyColor.Sprint(descriptor.reason),
+			descriptor.age,
+			descriptor.message})
+	}
+
+	if len(timeline) > 0 {
+		descriptor := newTimelineDescriptor(timeline)
+		timelinePrefix := getChildPrefix(prefix+childrenPipe+filler, rowCount-1, rowCount)
 		tbl.Append([]string{
-			fmt.Sprintf("%s%s", gray.Sprint(otherConditionPrefix), cyan.Sprint(otherCondition.Type)),
-			otherDescriptor.readyColor.Sprint(otherDescriptor.status),
-			otherDescriptor.readyColor.Sprint(otherDescriptor.severity),
-			otherDescriptor.readyColor.Sprint(otherDescriptor.reason),
-			otherDescriptor.age,
-			otherDescriptor.message})
+			fmt.Sprintf("%s%s", gray.Sprint(timelinePrefix), cyan.Sprint("Timeline")),
+			"",
+			"",
+			"",
+			descriptor.age,
+			descriptor.message})
 	}
 }
 
@@ -394,3 +439,32 @@ func newConditionDescriptor(c *clusterv1.Condition) conditionDescriptor {
 
 	return v
 }
+
+// newTimelineDescriptor returns a conditionDescriptor for the given timeline, with a message
+// listing the timeline entries, e.g. 2h BootstrapReady=True → 3m Ready=False(Draining).
+// NOTE: The age of the timeline is the age of its most recent entry.
+func newTimelineDescriptor(timeline []tree.TimelineEntry) conditionDescriptor {
+	v := conditionDescriptor{readyColor: gray}
+
+	entries := make([]string, 0, len(timeline))
+	for _, entry := range timeline {
+		text := fmt.Sprintf("%s %s=%s", duration.HumanDuration(time.Since(entry.Time.Time)), entry.Condition, entry.Status)
+		if entry.Status != corev1.ConditionTrue && entry.Reason != "" {
+			text = fmt.Sprintf("%s(%s)", text, entry.Reason)
+		}
+		switch entry.Severity {
+		case clusterv1.ConditionSeverityError:
+			text = red.Sprint(text)
+		case clusterv1.ConditionSeverityWarning:
+			text = yellow.Sprint(text)
+		}
+		entries = append(entries, text)
+	}
+	v.message = strings.Join(entries, " → ")
+
+	if len(timeline) > 0 {
+		v.age = duration.HumanDuration(time.Since(timeline[len(timeline)-1].Time.Time))
+	}
+
+	return v
+}
@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	. "github.com/onsi/gomega"
 	gtype "github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -152,6 +154,23 @@ func Test_newConditionDescriptor_truncateMessages(t *testing.T) {
 	}
 }
 
+func Test_newTimelineDescriptor(t *testing.T) {
+	g := NewWithT(t)
+
+	// Disable colors to check the message.
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	now := time.Now()
+	descriptor := newTimelineDescriptor([]tree.TimelineEntry{
+		{Time: metav1.NewTime(now.Add(-2 * time.Hour)), Condition: clusterv1.BootstrapReadyCondition, Status: corev1.ConditionTrue},
+		{Time: metav1.NewTime(now.Add(-3 * time.Minute)), Condition: clusterv1.ReadyCondition, Status: corev1.ConditionFalse, Severity: clusterv1.ConditionSeverityWarning, Reason: "Draining"},
+	})
+	g.Expect(descriptor.message).To(Equal("120m BootstrapReady=True → 3m Ready=False(Draining)"))
+	g.Expect(descriptor.age).To(Equal("3m"))
+}
+
 func Test_TreePrefix(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -274,6 +293,56 @@ func Test_TreePrefix(t *testing.T) {
 				"  └─Object/child2.1",
 			},
 		},
+		{
+			name: "Rollout in progress should be shown without showing conditions",
+			objectTree: func() *tree.ObjectTree {
+				root := fakeObject("root",
+					withCondition(conditions.TrueCondition("C1")),
+					withCondition(conditions.FalseCondition(clusterv1.RolloutCompletedCondition, clusterv1.RolloutInProgressReason, clusterv1.ConditionSeverityInfo, "")),
+				)
+				obectjTree := tree.NewObjectTree(root, tree.ObjectTreeOptions{})
+
+				o1 := fakeObject("child1",
+					withCondition(conditions.TrueCondition(clusterv1.RolloutCompletedCondition)),
+				)
+				obectjTree.Add(root, o1)
+				return obectjTree
+			}(),
+			expectPrefix: []string{
+				"Object/root",
+				"│           └─RolloutCompleted", // only the rollout in progress is shown
+				"└─Object/child1",                // rollout completed is not shown
+			},
+		},
+		{
+			name: "Timeline should get the right prefix",
+			objectTree: func() *tree.ObjectTree {
+				root := fakeObject("root")
+				obectjTree := tree.NewObjectTree(root, tree.ObjectTreeOptions{})
+
+				o1 := fakeObject("child1",
+					withAnnotation(tree.ShowObjectConditionsAnnotation, "True"),
+					withAnnotation(tree.ShowObjectTimelineAnnotation, "True"),
+					withCondition(conditions.TrueCondition("C1.1")),
+				)
+				o2 := fakeObject("child2",
+					withAnnotation(tree.ShowObjectTimelineAnnotation, "True"),
+				)
+				obectjTree.Add(root, o1)
+				obectjTree.Add(root, o2)
+				obectjTree.SetTimeline(o1.GetUID(), []tree.TimelineEntry{{Reason: "R1"}})
+				obectjTree.SetTimeline(o2.GetUID(), []tree.TimelineEntry{{Reason: "R2"}})
+				return obectjTree
+			}(),
+			expectPrefix: []string{
+				"Object/root",
+				"├─Object/child1",
+				"│             ├─C1.1",     // conditions are shown before the timeline
+				"│             └─Timeline", // the timeline gets └─
+				"└─Object/child2",
+				"              └─Timeline",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/ipam"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+)
+
+type describeIPPoolOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+}
+
+var dip = &describeIPPoolOptions{}
+
+var describeIPPoolCmd = &cobra.Command{
+	Use:   "ippool NAME",
+	Short: "Describe an InClusterIPPool",
+	Long: LongDesc(`
+		Describe an InClusterIPPool, showing its utilization, the IPAddressClaims referencing it grouped
+		by cluster and machine, and the conflicting or stale objects, e.g. addresses allocated more than
+		once, addresses whose claim does not exist anymore, or claims whose cluster or machine does not
+		exist anymore.`),
+
+	Example: Examples(`
+		# Describe the InClusterIPPool named workers.
+		clusterctl describe ippool workers
+
+		# Describe the InClusterIPPool named workers in the namespace foo.
+		clusterctl describe ippool workers --namespace foo`),
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("please specify a pool name")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDescribeIPPool(os.Stdout, args[0])
+	},
+}
+
+func init() {
+	describeIPPoolCmd.Flags().StringVar(&dip.kubeconfig, "kubeconfig", "",
+		"Path to a kubeconfig file to use for the management cluster. If empty, default discovery rules apply.")
+	describeIPPoolCmd.Flags().StringVar(&dip.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	describeIPPoolCmd.Flags().StringVarP(&dip.namespace, "namespace", "n", "",
+		"The namespace where the pool is located. If unspecified, the current namespace will be used.")
+
+	// completions
+	describeIPPoolCmd.ValidArgsFunction = resourceNameCompletionFunc(
+		describeIPPoolCmd.Flags().Lookup("kubeconfig"),
+		describeIPPoolCmd.Flags().Lookup("kubeconfig-context"),
+		describeIPPoolCmd.Flags().Lookup("namespace"),
+		ipamv1.GroupVersion.String(),
+		ipamv1.InClusterIPPoolKind,
+	)
+
+	describeCmd.AddCommand(describeIPPoolCmd)
+}
+
+func runDescribeIPPool(out io.Writer, name string) error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	pool, err := c.DescribeIPPool(ctx, client.DescribeIPPoolOptions{
+		Kubeconfig: client.Kubeconfig{Path: dip.kubeconfig, Context: dip.kubeconfigContext},
+		Namespace:  dip.namespace,
+		PoolName:   name,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printIPPoolDescription(out, pool)
+}
+
+// printIPPoolDescription prints the utilization of a pool, followed by a table with its claims grouped by cluster
+// and machine, and a table with the conflicting or stale objects, if any.
+func printIPPoolDescription(out io.Writer, pool *ipam.PoolDescription) error {
+	s := pool.Summary
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", s.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", s.Namespace)
+	fmt.Fprintf(w, "Addresses:\t%s\n", strings.Join(pool.Addresses, ", "))
+	utilization := fmt.Sprintf("%d used, %d free, %d quarantined of %d addresses", s.Used, s.Free, s.Quarantined, s.Total)
+	if s.Exhausted {
+		utilization += " (exhausted)"
+	}
+	fmt.Fprintf(w, "Utilization:\t%s\n", utilization)
+	fmt.Fprintf(w, "Claims:\t%d, %d pending\n", s.Claims, s.Pending)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(pool.Claims) > 0 {
+		fmt.Fprintln(out, "")
+		w = tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+		fmt.Fprintln(w, "CLUSTER\tMACHINE\tCLAIM\tADDRESS\tREASON\tMESSAGE")
+		for _, claim := range pool.Claims {
+			address := claim.Address
+			if address == "" {
+				address = "<pending>"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", valueOrNone(claim.ClusterName), valueOrNone(claim.MachineName),
+				claim.Name, address, claim.Reason, claim.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(pool.Issues) > 0 {
+		fmt.Fprintln(out, "")
+		w = tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+		fmt.Fprintln(w, "ISSUE\tOBJECT\tMESSAGE")
+		for _, issue := range pool.Issues {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", issue.Type, issue.Object, issue.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/machine"
+)
+
+type describeMachineOptions struct {
+	kubeconfig            string
+	kubeconfigContext     string
+	namespace             string
+	logs                  bool
+	logsSource            string
+	insecureSkipTLSVerify bool
+}
+
+var dm = &describeMachineOptions{}
+
+var describeMachineCmd = &cobra.Command{
+	Use:   "machine NAME",
+	Short: "Describe a Machine",
+	Long: LongDesc(`
+		Describe a Machine, showing its status together with the status of its infrastructure machine.
+
+		If the infrastructure provider exposes the console logs of the machine, i.e. it sets status.consoleLogsURL
+		on the infrastructure machine, the logs can be read with the --logs flag; the request is authenticated with
+		the credentials used to access the management cluster.`),
+
+	Example: Examples(`
+		# Describe the Machine named cluster1-md-0-xyz.
+		clusterctl describe machine cluster1-md-0-xyz
+
+		# Read the console logs of the Machine named cluster1-md-0-xyz.
+		clusterctl describe machine cluster1-md-0-xyz --logs
+
+		# Read the kubelet logs of the Machine named cluster1-md-0-xyz, if supported by the infrastructure provider.
+		clusterctl describe machine cluster1-md-0-xyz --logs --logs-source kubelet`),
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("please specify a machine name")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDescribeMachine(os.Stdout, args[0])
+	},
+}
+
+func init() {
+	describeMachineCmd.Flags().StringVar(&dm.kubeconfig, "kubeconfig", "",
+		"Path to a kubeconfig file to use for the management cluster. If empty, default discovery rules apply.")
+	describeMachineCmd.Flags().StringVar(&dm.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	describeMachineCmd.Flags().StringVarP(&dm.namespace, "namespace", "n", "",
+		"The namespace where the machine is located. If unspecified, the current namespace will be used.")
+	describeMachineCmd.Flags().BoolVar(&dm.logs, "logs", false,
+		"Print the console logs of the machine, read from the URL exposed by the infrastructure provider, instead of its status.")
+	describeMachineCmd.Flags().StringVar(&dm.logsSource, "logs-source", "",
+		"The logs to read, e.g. console or kubelet; the supported values depend on the infrastructure provider. If empty, the console logs are read.")
+	describeMachineCmd.Flags().BoolVar(&dm.insecureSkipTLSVerify, "insecure-skip-tls-verify", false,
+		"Skip the verification of the certificate of the endpoint serving the logs.")
+
+	// completions
+	describeMachineCmd.ValidArgsFunction = resourceNameCompletionFunc(
+		describeMachineCmd.Flags().Lookup("kubeconfig"),
+		describeMachineCmd.Flags().Lookup("kubeconfig-context"),
+		describeMachineCmd.Flags().Lookup("namespace"),
+		clusterv1.GroupVersion.String(),
+		"machine",
+	)
+
+	describeCmd.AddCommand(describeMachineCmd)
+}
+
+func runDescribeMachine(out io.Writer, name string) error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	kubeconfig := client.Kubeconfig{Path: dm.kubeconfig, Context: dm.kubeconfigContext}
+	if dm.logs {
+		logs, err := c.GetMachineLogs(ctx, client.GetMachineLogsOptions{
+			Kubeconfig:            kubeconfig,
+			Namespace:             dm.namespace,
+			MachineName:           name,
+			Source:                dm.logsSource,
+			InsecureSkipTLSVerify: dm.insecureSkipTLSVerify,
+		})
+		if err != nil {
+			return err
+		}
+		defer logs.Close()
+		_, err = io.Copy(out, logs)
+		return err
+	}
+
+	d, err := c.DescribeMachine(ctx, client.DescribeMachineOptions{
+		Kubeconfig:  kubeconfig,
+		Namespace:   dm.namespace,
+		MachineName: name,
+	})
+	if err != nil {
+		return err
+	}
+	return printMachineDescription(out, d)
+}
+
+// printMachineDescription prints the status of a Machine and of its infrastructure machine, followed by a table
+// with the conditions of the Machine.
+func printMachineDescription(out io.Writer, d *machine.Description) error {
+	m := d.Machine
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", m.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", m.Namespace)
+	fmt.Fprintf(w, "Cluster:\t%s\n", m.Spec.ClusterName)
+	fmt.Fprintf(w, "Phase:\t%s\n", m.Status.Phase)
+	nodeName := ""
+	if m.Status.NodeRef != nil {
+		nodeName = m.Status.NodeRef.Name
+	}
+	fmt.Fprintf(w, "Node:\t%s\n", valueOrNone(nodeName))
+	providerID := ""
+	if m.Spec.ProviderID != nil {
+		providerID = *m.Spec.ProviderID
+	}
+	fmt.Fprintf(w, "Provider ID:\t%s\n", valueOrNone(providerID))
+	infrastructure := fmt.Sprintf("%s/%s", m.Spec.InfrastructureRef.Kind, m.Spec.InfrastructureRef.Name)
+	if d.InfrastructureMachine == nil {
+		infrastructure += " (not found)"
+	}
+	fmt.Fprintf(w, "Infrastructure:\t%s\n", infrastructure)
+	fmt.Fprintf(w, "Console logs:\t%s\n", valueOrNone(d.ConsoleLogsURL))
+	sshEndpoint := ""
+	if d.SSHEndpoint != nil {
+		sshEndpoint = fmt.Sprintf("%s:%d", d.SSHEndpoint.Host, d.SSHEndpoint.Port)
+		if d.SSHEndpoint.User != "" {
+			sshEndpoint = d.SSHEndpoint.User + "@" + sshEndpoint
+		}
+	}
+	fmt.Fprintf(w, "SSH:\t%s\n", valueOrNone(sshEndpoint))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(m.Status.Conditions) > 0 {
+		fmt.Fprintln(out, "")
+		w = tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+		fmt.Fprintln(w, "CONDITION\tSTATUS\tSEVERITY\tREASON\tMESSAGE")
+		for _, c := range m.Status.Conditions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Type, c.Status, c.Severity, c.Reason, c.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
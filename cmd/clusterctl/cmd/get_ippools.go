@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/ipam"
+)
+
+type getIPPoolsOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+	allNamespaces     bool
+}
+
+var gip = &getIPPoolsOptions{}
+
+var getIPPoolsCmd = &cobra.Command{
+	Use:     "ippools",
+	Aliases: []string{"ippool"},
+	Short:   "Gets the utilization of the InClusterIPPools of a management cluster",
+	Long: LongDesc(`
+		Gets the utilization of the InClusterIPPools of a management cluster, together with the number
+		of IPAddressClaims referencing each pool, the claims which are not fulfilled yet and the
+		conflicting or stale objects, which can be inspected with clusterctl describe ippool.`),
+
+	Example: Examples(`
+		# Get the InClusterIPPools in the current namespace.
+		clusterctl get ippools
+
+		# Get the InClusterIPPools in all the namespaces.
+		clusterctl get ippools --all-namespaces`),
+
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGetIPPools(os.Stdout)
+	},
+}
+
+func init() {
+	getIPPoolsCmd.Flags().StringVar(&gip.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	getIPPoolsCmd.Flags().StringVar(&gip.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	getIPPoolsCmd.Flags().StringVarP(&gip.namespace, "namespace", "n", "",
+		"The namespace where the pools are located. If unspecified, the current namespace will be used.")
+	getIPPoolsCmd.Flags().BoolVarP(&gip.allNamespaces, "all-namespaces", "A", false,
+		"Get the pools in all the namespaces.")
+
+	getCmd.AddCommand(getIPPoolsCmd)
+}
+
+func runGetIPPools(out io.Writer) error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	pools, err := c.GetIPPools(ctx, client.GetIPPoolsOptions{
+		Kubeconfig:    client.Kubeconfig{Path: gip.kubeconfig, Context: gip.kubeconfigContext},
+		Namespace:     gip.namespace,
+		AllNamespaces: gip.allNamespaces,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printIPPools(out, pools)
+}
+
+// printIPPools prints a table with the utilization of the pools.
+func printIPPools(out io.Writer, pools []ipam.PoolSummary) error {
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tTOTAL\tUSED\tFREE\tQUARANTINED\tCLAIMS\tPENDING\tISSUES")
+	for _, pool := range pools {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n", pool.Namespace, pool.Name,
+			pool.Total, pool.Used, pool.Free, pool.Quarantined, pool.Claims, pool.Pending, pool.Issues)
+	}
+	return w.Flush()
+}
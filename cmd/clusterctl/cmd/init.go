@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"context"
+	"os"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -123,7 +124,11 @@ func init() {
 func runInit() error {
 	ctx := context.Background()
 
-	c, err := client.New(ctx, cfgFile)
+	// Display the warnings returned by the management cluster, e.g. by the admission webhooks, once the command completes.
+	warnings := client.NewWarningCollector()
+	defer warnings.Print(os.Stderr)
+
+	c, err := client.New(ctx, cfgFile, client.InjectWarningHandler(warnings))
 	if err != nil {
 		return err
 	}
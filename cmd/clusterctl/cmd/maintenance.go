@@ -0,0 +1,203 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+type maintenanceOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+	reason            string
+}
+
+var mn = &maintenanceOptions{}
+
+var maintenanceCmd = &cobra.Command{
+	Use:   "maintenance",
+	Short: "Pause the reconciliation of the Cluster API objects in a namespace",
+	Long: LongDesc(`
+		Pause the reconciliation of the Cluster API objects in a namespace of the management cluster,
+		e.g. while restoring etcd or the infrastructure of the workload clusters.
+
+		While a namespace is in maintenance, its Clusters and ClusterClasses are paused; the objects
+		paused by the maintenance are unpaused once the maintenance is over, while the objects
+		paused before are left paused.`),
+}
+
+var maintenanceEnterCmd = &cobra.Command{
+	Use:   "enter",
+	Short: "Put a namespace in maintenance",
+	Long: LongDesc(`
+		Put a namespace in maintenance, pausing the reconciliation of the Cluster API objects in it.
+
+		The Clusters are paused asynchronously by the Cluster API controllers; use
+		clusterctl alpha maintenance status to check whether all of them are paused.`),
+
+	Example: Examples(`
+		# Put the namespace test in maintenance.
+		clusterctl alpha maintenance enter --namespace test --reason "etcd restore"`),
+
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMaintenanceEnter()
+	},
+}
+
+var maintenanceExitCmd = &cobra.Command{
+	Use:   "exit",
+	Short: "End the maintenance of a namespace",
+	Long: LongDesc(`
+		End the maintenance of a namespace, resuming the reconciliation of the Cluster API objects
+		paused by the maintenance.`),
+
+	Example: Examples(`
+		# End the maintenance of the namespace test.
+		clusterctl alpha maintenance exit --namespace test`),
+
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMaintenanceExit()
+	},
+}
+
+var maintenanceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Get the maintenance status of a namespace",
+	Long: LongDesc(`
+		Get the maintenance status of a namespace, together with the Clusters and ClusterClasses in it,
+		whether they are paused and whether the Cluster API controllers acknowledged it.`),
+
+	Example: Examples(`
+		# Get the maintenance status of the namespace test.
+		clusterctl alpha maintenance status --namespace test`),
+
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMaintenanceStatus(os.Stdout)
+	},
+}
+
+func init() {
+	maintenanceCmd.PersistentFlags().StringVar(&mn.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	maintenanceCmd.PersistentFlags().StringVar(&mn.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	maintenanceCmd.PersistentFlags().StringVarP(&mn.namespace, "namespace", "n", "",
+		"The namespace of the maintenance. If unspecified, the current namespace will be used.")
+
+	maintenanceEnterCmd.Flags().StringVar(&mn.reason, "reason", "",
+		"The reason of the maintenance, stored in the maintenance annotation of the namespace.")
+
+	maintenanceCmd.AddCommand(maintenanceEnterCmd)
+	maintenanceCmd.AddCommand(maintenanceExitCmd)
+	maintenanceCmd.AddCommand(maintenanceStatusCmd)
+}
+
+func runMaintenanceEnter() error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	if err := c.EnterMaintenance(ctx, maintenanceClientOptions()); err != nil {
+		return err
+	}
+
+	fmt.Println("Namespace put in maintenance; use clusterctl alpha maintenance status to check whether all the Clusters are paused.")
+	return nil
+}
+
+func runMaintenanceExit() error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	if err := c.ExitMaintenance(ctx, maintenanceClientOptions()); err != nil {
+		return err
+	}
+
+	fmt.Println("Maintenance of the namespace ended; the objects paused by the maintenance will be unpaused.")
+	return nil
+}
+
+func runMaintenanceStatus(out io.Writer) error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	status, err := c.MaintenanceStatus(ctx, maintenanceClientOptions())
+	if err != nil {
+		return err
+	}
+
+	return printMaintenanceStatus(out, status)
+}
+
+func maintenanceClientOptions() client.MaintenanceOptions {
+	return client.MaintenanceOptions{
+		Kubeconfig: client.Kubeconfig{Path: mn.kubeconfig, Context: mn.kubeconfigContext},
+		Namespace:  mn.namespace,
+		Reason:     mn.reason,
+	}
+}
+
+// printMaintenanceStatus prints the maintenance status of a namespace, followed by a table with the status of its objects.
+func printMaintenanceStatus(out io.Writer, status *cluster.MaintenanceStatus) error {
+	switch {
+	case !status.InMaintenance:
+		fmt.Fprintf(out, "Namespace %s is not in maintenance\n", status.Namespace)
+	case status.Reason != "":
+		fmt.Fprintf(out, "Namespace %s is in maintenance: %s\n", status.Namespace, status.Reason)
+	default:
+		fmt.Fprintf(out, "Namespace %s is in maintenance\n", status.Namespace)
+	}
+	if len(status.Objects) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tPAUSED\tPAUSED BY MAINTENANCE\tACKNOWLEDGED")
+	for _, o := range status.Objects {
+		acknowledged := "-"
+		if o.Kind == "Cluster" {
+			acknowledged = fmt.Sprintf("%t", o.Acknowledged)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%t\t%s\n", o.Kind, o.Name, o.Paused, o.PausedByMaintenance, acknowledged)
+	}
+	return w.Flush()
+}
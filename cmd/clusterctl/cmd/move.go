@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"context"
+	"os"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -98,7 +99,11 @@ func runMove() error {
 		return errors.New("please specify a target cluster using the --to-kubeconfig flag when not using --dry-run, --to-directory or --from-directory")
 	}
 
-	c, err := client.New(ctx, cfgFile)
+	// Display the warnings returned by the management cluster, e.g. by the admission webhooks, once the command completes.
+	warnings := client.NewWarningCollector()
+	defer warnings.Print(os.Stderr)
+
+	c, err := client.New(ctx, cfgFile, client.InjectWarningHandler(warnings))
 	if err != nil {
 		return err
 	}
@@ -19,6 +19,7 @@ package rollout
 
 import (
 	"context"
+	"os"
 
 	"github.com/spf13/cobra"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -76,7 +77,11 @@ func runPause(cfgFile string, args []string) error {
 
 	ctx := context.Background()
 
-	c, err := client.New(ctx, cfgFile)
+	// Display the warnings returned by the management cluster, e.g. by the admission webhooks, once the command completes.
+	warnings := client.NewWarningCollector()
+	defer warnings.Print(os.Stderr)
+
+	c, err := client.New(ctx, cfgFile, client.InjectWarningHandler(warnings))
 	if err != nil {
 		return err
 	}
@@ -18,6 +18,7 @@ package rollout
 
 import (
 	"context"
+	"os"
 
 	"github.com/spf13/cobra"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -75,7 +76,11 @@ func runRestart(cfgFile string, _ *cobra.Command, args []string) error {
 
 	ctx := context.Background()
 
-	c, err := client.New(ctx, cfgFile)
+	// Display the warnings returned by the management cluster, e.g. by the admission webhooks, once the command completes.
+	warnings := client.NewWarningCollector()
+	defer warnings.Print(os.Stderr)
+
+	c, err := client.New(ctx, cfgFile, client.InjectWarningHandler(warnings))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/machine"
+)
+
+type sshMachineOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+}
+
+var sm = &sshMachineOptions{}
+
+var sshMachineCmd = &cobra.Command{
+	Use:   "machine NAME [-- COMMAND [ARGS...]]",
+	Short: "SSH into a Machine",
+	Long: LongDesc(`
+		SSH into a Machine, using the ssh binary found in the PATH.
+
+		The infrastructure provider must expose SSH access to the machine, i.e. it must set status.ssh on the
+		infrastructure machine; authentication is handled by ssh, e.g. with the keys loaded in the SSH agent.`),
+
+	Example: Examples(`
+		# SSH into the Machine named cluster1-md-0-xyz.
+		clusterctl ssh machine cluster1-md-0-xyz
+
+		# Run a command on the Machine named cluster1-md-0-xyz.
+		clusterctl ssh machine cluster1-md-0-xyz -- journalctl -u kubelet`),
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("please specify a machine name")
+		}
+		if dash := cmd.ArgsLenAtDash(); dash != -1 && dash != 1 {
+			return errors.New("please specify a single machine name before --")
+		}
+		if cmd.ArgsLenAtDash() == -1 && len(args) > 1 {
+			return errors.New("please specify the command to run on the machine after --")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSSHMachine(args[0], args[1:])
+	},
+}
+
+func init() {
+	sshMachineCmd.Flags().StringVar(&sm.kubeconfig, "kubeconfig", "",
+		"Path to a kubeconfig file to use for the management cluster. If empty, default discovery rules apply.")
+	sshMachineCmd.Flags().StringVar(&sm.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	sshMachineCmd.Flags().StringVarP(&sm.namespace, "namespace", "n", "",
+		"The namespace where the machine is located. If unspecified, the current namespace will be used.")
+
+	// completions
+	sshMachineCmd.ValidArgsFunction = resourceNameCompletionFunc(
+		sshMachineCmd.Flags().Lookup("kubeconfig"),
+		sshMachineCmd.Flags().Lookup("kubeconfig-context"),
+		sshMachineCmd.Flags().Lookup("namespace"),
+		clusterv1.GroupVersion.String(),
+		"machine",
+	)
+
+	sshCmd.AddCommand(sshMachineCmd)
+}
+
+func runSSHMachine(name string, command []string) error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	d, err := c.DescribeMachine(ctx, client.DescribeMachineOptions{
+		Kubeconfig:  client.Kubeconfig{Path: sm.kubeconfig, Context: sm.kubeconfigContext},
+		Namespace:   sm.namespace,
+		MachineName: name,
+	})
+	if err != nil {
+		return err
+	}
+
+	args, err := machine.SSHArgs(d, command)
+	if err != nil {
+		return err
+	}
+
+	ssh, err := exec.LookPath("ssh")
+	if err != nil {
+		return errors.Wrap(err, "failed to find the ssh binary in the PATH")
+	}
+	sshCommand := exec.CommandContext(ctx, ssh, args...)
+	sshCommand.Stdin = os.Stdin
+	sshCommand.Stdout = os.Stdout
+	sshCommand.Stderr = os.Stderr
+	return sshCommand.Run()
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type supportBundleOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+	clusterName       string
+	logsSince         time.Duration
+	output            string
+}
+
+var sb = &supportBundleOptions{}
+
+var supportBundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Collect a support bundle for a workload cluster",
+	Long: LongDesc(`
+		Collect a support bundle for a workload cluster, i.e. a gzipped tarball for attaching to bug reports
+		and support cases, with:
+
+		- the Cluster API objects in the hierarchy of the Cluster, including the provider objects and the ClusterClass, if any.
+		- the recent events of those objects.
+		- the webhook configurations of the providers.
+		- the logs of the providers.
+		- the version of clusterctl, of the management cluster and of the providers.
+
+		The data of Secrets and ConfigMaps, the content of files and inline credentials are redacted;
+		the rest of the support bundle, e.g. logs, is not, so review the support bundle before sharing it.`),
+
+	Example: Examples(`
+		# Collect a support bundle for the workload cluster test-1.
+		clusterctl support bundle --cluster test-1
+
+		# Collect a support bundle including only the last hour of logs of the providers.
+		clusterctl support bundle --cluster test-1 --logs-since 1h
+
+		# Collect a support bundle and write it to a specific file.
+		clusterctl support bundle --cluster test-1 --output test-1.tar.gz`),
+
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSupportBundle()
+	},
+}
+
+func init() {
+	supportBundleCmd.Flags().StringVar(&sb.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	supportBundleCmd.Flags().StringVar(&sb.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	supportBundleCmd.Flags().StringVarP(&sb.namespace, "namespace", "n", "",
+		"The namespace where the workload cluster is located. If unspecified, the current namespace will be used.")
+	supportBundleCmd.Flags().StringVar(&sb.clusterName, "cluster", "",
+		"The name of the workload cluster.")
+	supportBundleCmd.Flags().DurationVar(&sb.logsSince, "logs-since", 24*time.Hour,
+		"Only collect the logs of the providers newer than a relative duration like 5s, 2m, or 3h. If 0, all the logs are collected.")
+	supportBundleCmd.Flags().StringVarP(&sb.output, "output", "o", "",
+		"The file the support bundle is written to. If unspecified, <cluster>-support-bundle-<timestamp>.tar.gz in the current directory will be used.")
+
+	_ = supportBundleCmd.MarkFlagRequired("cluster")
+
+	supportCmd.AddCommand(supportBundleCmd)
+}
+
+func runSupportBundle() error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	output := sb.output
+	if output == "" {
+		output = fmt.Sprintf("%s-support-bundle-%s.tar.gz", sb.clusterName, time.Now().UTC().Format("20060102150405"))
+	}
+
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) //nolint:gosec // The path is configured by the user.
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", output)
+	}
+
+	err = c.SupportBundle(ctx, client.SupportBundleOptions{
+		Kubeconfig:  client.Kubeconfig{Path: sb.kubeconfig, Context: sb.kubeconfigContext},
+		Namespace:   sb.namespace,
+		ClusterName: sb.clusterName,
+		LogsSince:   sb.logsSince,
+		Output:      f,
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Do not leave an incomplete support bundle behind.
+		_ = os.Remove(output)
+		return err
+	}
+
+	fmt.Printf("Support bundle written to %s\n", output)
+	fmt.Println("The data of Secrets and ConfigMaps, the content of files and inline credentials are redacted; review the rest of the support bundle, e.g. logs, before sharing it.")
+	return nil
+}
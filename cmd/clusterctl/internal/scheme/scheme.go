@@ -30,6 +30,7 @@ import (
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 )
 
 var (
@@ -48,4 +49,5 @@ func init() {
 	_ = addonsv1.AddToScheme(Scheme)
 	_ = controlplanev1.AddToScheme(Scheme)
 	_ = expv1.AddToScheme(Scheme)
+	_ = ipamv1.AddToScheme(Scheme)
 }
@@ -0,0 +1,257 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics contains tests ensuring the kube-state-metrics custom resource configuration for
+// Cluster API is kept in sync with the templates and with the Cluster API types.
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+const (
+	metricsConfigFile   = "crd-metrics-config.yaml"
+	metricsTemplatesDir = "templates"
+)
+
+// templateResources are the resources of the metrics configuration, in the same order as in the
+// generate-metrics-config make target.
+var templateResources = []string{"clusterclass", "cluster", "kubeadmcontrolplane", "kubeadmconfig", "machine", "machinedeployment", "machinehealthcheck", "machineset", "machinepool"}
+
+// crdDirs are the folders with the CRDs of the resources of the metrics configuration.
+var crdDirs = []string{
+	filepath.Join("..", "crd", "bases"),
+	filepath.Join("..", "..", "bootstrap", "kubeadm", "config", "crd", "bases"),
+	filepath.Join("..", "..", "controlplane", "kubeadm", "config", "crd", "bases"),
+}
+
+// customResourceStateMetrics is the subset of the kube-state-metrics custom resource configuration used by the tests.
+type customResourceStateMetrics struct {
+	Spec struct {
+		Resources []struct {
+			GroupVersionKind struct {
+				Group   string `json:"group"`
+				Version string `json:"version"`
+				Kind    string `json:"kind"`
+			} `json:"groupVersionKind"`
+			LabelsFromPath   map[string][]string `json:"labelsFromPath"`
+			MetricNamePrefix string              `json:"metricNamePrefix"`
+			Metrics          []struct {
+				Name string `json:"name"`
+				Each struct {
+					Gauge    *metricDefinition `json:"gauge"`
+					Info     *metricDefinition `json:"info"`
+					StateSet *metricDefinition `json:"stateSet"`
+				} `json:"each"`
+			} `json:"metrics"`
+		} `json:"resources"`
+	} `json:"spec"`
+}
+
+type metricDefinition struct {
+	Path           []string            `json:"path"`
+	LabelsFromPath map[string][]string `json:"labelsFromPath"`
+	ValueFrom      []string            `json:"valueFrom"`
+	List           []string            `json:"list"`
+}
+
+func TestMetricsConfigIsUpToDate(t *testing.T) {
+	g := NewWithT(t)
+
+	want := &strings.Builder{}
+	want.WriteString("# This file was auto-generated via: make generate-metrics-config\n")
+	want.WriteString(readFile(g, filepath.Join(metricsTemplatesDir, "header.yaml")))
+	for _, resource := range templateResources {
+		want.WriteString(readFile(g, filepath.Join(metricsTemplatesDir, resource+".yaml")))
+		want.WriteString(strings.ReplaceAll(readFile(g, filepath.Join(metricsTemplatesDir, "common_metrics.yaml")), "${RESOURCE}", resource))
+		if resource != "cluster" {
+			want.WriteString(readFile(g, filepath.Join(metricsTemplatesDir, "owner_metric.yaml")))
+		}
+	}
+
+	g.Expect(readFile(g, metricsConfigFile)).To(Equal(want.String()), "%s is not up to date, run make generate-metrics-config", metricsConfigFile)
+}
+
+func TestMetricsConfigPathsExistInCRDs(t *testing.T) {
+	g := NewWithT(t)
+
+	crds := map[string]*apiextensionsv1.CustomResourceDefinition{}
+	for _, dir := range crdDirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+		g.Expect(err).ToNot(HaveOccurred())
+		for _, file := range files {
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			g.Expect(yaml.Unmarshal([]byte(readFile(g, file)), crd)).To(Succeed())
+			crds[crd.Spec.Group+"/"+crd.Spec.Names.Kind] = crd
+		}
+	}
+
+	for _, resource := range readMetricsConfig(g).Spec.Resources {
+		gvk := resource.GroupVersionKind
+		crd, ok := crds[gvk.Group+"/"+gvk.Kind]
+		g.Expect(ok).To(BeTrue(), "CRD not found for %s/%s", gvk.Group, gvk.Kind)
+
+		var schema *apiextensionsv1.JSONSchemaProps
+		for _, version := range crd.Spec.Versions {
+			if version.Name == gvk.Version {
+				schema = version.Schema.OpenAPIV3Schema
+			}
+		}
+		g.Expect(schema).ToNot(BeNil(), "version %s not found in the CRD of %s/%s", gvk.Version, gvk.Group, gvk.Kind)
+
+		for label, path := range resource.LabelsFromPath {
+			_, err := schemaAt(schema, path)
+			g.Expect(err).ToNot(HaveOccurred(), "invalid path for label %s of %s", label, resource.MetricNamePrefix)
+		}
+
+		for _, metric := range resource.Metrics {
+			for _, definition := range []*metricDefinition{metric.Each.Gauge, metric.Each.Info, metric.Each.StateSet} {
+				if definition == nil {
+					continue
+				}
+				name := resource.MetricNamePrefix + "_" + metric.Name
+
+				base, err := schemaAt(schema, definition.Path)
+				g.Expect(err).ToNot(HaveOccurred(), "invalid path of %s", name)
+				if base == nil {
+					// The metric is read from metadata, which is not part of the CRD schema.
+					continue
+				}
+				for label, path := range definition.LabelsFromPath {
+					_, err := schemaAt(base, path)
+					g.Expect(err).ToNot(HaveOccurred(), "invalid path for label %s of %s", label, name)
+				}
+				_, err = schemaAt(base, definition.ValueFrom)
+				g.Expect(err).ToNot(HaveOccurred(), "invalid valueFrom of %s", name)
+			}
+		}
+	}
+}
+
+func TestMetricsConfigPhasesMatchAPI(t *testing.T) {
+	g := NewWithT(t)
+
+	phases := map[string][]string{
+		"capi_cluster": {
+			string(clusterv1.ClusterPhasePending),
+			string(clusterv1.ClusterPhaseProvisioning),
+			string(clusterv1.ClusterPhaseProvisioned),
+			string(clusterv1.ClusterPhaseDeleting),
+			string(clusterv1.ClusterPhaseFailed),
+			string(clusterv1.ClusterPhaseUnknown),
+		},
+		"capi_machine": {
+			string(clusterv1.MachinePhasePending),
+			string(clusterv1.MachinePhaseProvisioning),
+			string(clusterv1.MachinePhaseProvisioned),
+			string(clusterv1.MachinePhaseRunning),
+			string(clusterv1.MachinePhaseDeleting),
+			string(clusterv1.MachinePhaseDeleted),
+			string(clusterv1.MachinePhaseFailed),
+			string(clusterv1.MachinePhaseUnknown),
+		},
+		"capi_machinedeployment": {
+			string(clusterv1.MachineDeploymentPhaseScalingUp),
+			string(clusterv1.MachineDeploymentPhaseScalingDown),
+			string(clusterv1.MachineDeploymentPhaseRunning),
+			string(clusterv1.MachineDeploymentPhaseFailed),
+			string(clusterv1.MachineDeploymentPhaseUnknown),
+		},
+		"capi_machinepool": {
+			string(expv1.MachinePoolPhasePending),
+			string(expv1.MachinePoolPhaseProvisioning),
+			string(expv1.MachinePoolPhaseProvisioned),
+			string(expv1.MachinePoolPhaseRunning),
+			string(expv1.MachinePoolPhaseScalingUp),
+			string(expv1.MachinePoolPhaseScalingDown),
+			string(expv1.MachinePoolPhaseScaling),
+			string(expv1.MachinePoolPhaseDeleting),
+			string(expv1.MachinePoolPhaseFailed),
+			string(expv1.MachinePoolPhaseUnknown),
+		},
+	}
+
+	found := map[string]bool{}
+	for _, resource := range readMetricsConfig(g).Spec.Resources {
+		for _, metric := range resource.Metrics {
+			if metric.Name != "status_phase" {
+				continue
+			}
+			want, ok := phases[resource.MetricNamePrefix]
+			g.Expect(ok).To(BeTrue(), "phases not defined for %s", resource.MetricNamePrefix)
+			g.Expect(metric.Each.StateSet).ToNot(BeNil())
+			g.Expect(metric.Each.StateSet.List).To(ConsistOf(want), "phases of %s_status_phase do not match the API", resource.MetricNamePrefix)
+			found[resource.MetricNamePrefix] = true
+		}
+	}
+	for prefix := range phases {
+		g.Expect(found).To(HaveKey(prefix), "%s_status_phase metric not found", prefix)
+	}
+}
+
+func readFile(g *WithT, name string) string {
+	data, err := os.ReadFile(name) //nolint:gosec
+	g.Expect(err).ToNot(HaveOccurred())
+	return string(data)
+}
+
+func readMetricsConfig(g *WithT) *customResourceStateMetrics {
+	config := &customResourceStateMetrics{}
+	g.Expect(yaml.Unmarshal([]byte(readFile(g, metricsConfigFile)), config)).To(Succeed())
+	g.Expect(config.Spec.Resources).To(HaveLen(len(templateResources)))
+	return config
+}
+
+// schemaAt returns the schema at a kube-state-metrics path, descending into the items of lists as kube-state-metrics
+// does; it returns nil for paths in metadata, which is not part of the CRD schema.
+func schemaAt(schema *apiextensionsv1.JSONSchemaProps, path []string) (*apiextensionsv1.JSONSchemaProps, error) {
+	if len(path) > 0 && path[0] == "metadata" {
+		return nil, nil
+	}
+	for _, segment := range path {
+		if schema.Type == "array" && schema.Items != nil && schema.Items.Schema != nil {
+			schema = schema.Items.Schema
+		}
+		// Selectors like [kind=Cluster] filter the items of a list.
+		if strings.HasPrefix(segment, "[") {
+			continue
+		}
+		if property, ok := schema.Properties[segment]; ok {
+			schema = &property
+			continue
+		}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			schema = schema.AdditionalProperties.Schema
+			continue
+		}
+		return nil, fmt.Errorf("field %q of path %v not found", segment, path)
+	}
+	if schema.Type == "array" && schema.Items != nil && schema.Items.Schema != nil {
+		schema = schema.Items.Schema
+	}
+	return schema, nil
+}
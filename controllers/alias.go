@@ -27,14 +27,17 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	clustercontroller "sigs.k8s.io/cluster-api/internal/controllers/cluster"
 	clusterclasscontroller "sigs.k8s.io/cluster-api/internal/controllers/clusterclass"
+	kubeconfigrotationcontroller "sigs.k8s.io/cluster-api/internal/controllers/kubeconfigrotation"
 	machinecontroller "sigs.k8s.io/cluster-api/internal/controllers/machine"
 	machinedeploymentcontroller "sigs.k8s.io/cluster-api/internal/controllers/machinedeployment"
 	machinehealthcheckcontroller "sigs.k8s.io/cluster-api/internal/controllers/machinehealthcheck"
 	machinesetcontroller "sigs.k8s.io/cluster-api/internal/controllers/machineset"
+	maintenancecontroller "sigs.k8s.io/cluster-api/internal/controllers/maintenance"
 	clustertopologycontroller "sigs.k8s.io/cluster-api/internal/controllers/topology/cluster"
 	machinedeploymenttopologycontroller "sigs.k8s.io/cluster-api/internal/controllers/topology/machinedeployment"
 	machinesettopologycontroller "sigs.k8s.io/cluster-api/internal/controllers/topology/machineset"
 	runtimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
+	"sigs.k8s.io/cluster-api/util/shard"
 )
 
 // Following types provides access to reconcilers implemented in internal/controllers, thus
@@ -48,6 +51,9 @@ type ClusterReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// Sharder, if set, limits the reconciliation to the Clusters assigned to this replica of the manager.
+	Sharder *shard.Sharder
 }
 
 func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -56,6 +62,7 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		UnstructuredCachingClient: r.UnstructuredCachingClient,
 		APIReader:                 r.APIReader,
 		WatchFilterValue:          r.WatchFilterValue,
+		Sharder:                   r.Sharder,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -69,6 +76,9 @@ type MachineReconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// Sharder, if set, limits the reconciliation to the Clusters assigned to this replica of the manager.
+	Sharder *shard.Sharder
+
 	// NodeDrainClientTimeout timeout of the client used for draining nodes.
 	NodeDrainClientTimeout time.Duration
 }
@@ -80,6 +90,7 @@ func (r *MachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		APIReader:                 r.APIReader,
 		Tracker:                   r.Tracker,
 		WatchFilterValue:          r.WatchFilterValue,
+		Sharder:                   r.Sharder,
 		NodeDrainClientTimeout:    r.NodeDrainClientTimeout,
 	}).SetupWithManager(ctx, mgr, options)
 }
@@ -93,6 +104,9 @@ type MachineSetReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// Sharder, if set, limits the reconciliation to the Clusters assigned to this replica of the manager.
+	Sharder *shard.Sharder
 }
 
 func (r *MachineSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -102,6 +116,7 @@ func (r *MachineSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 		APIReader:                 r.APIReader,
 		Tracker:                   r.Tracker,
 		WatchFilterValue:          r.WatchFilterValue,
+		Sharder:                   r.Sharder,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -113,6 +128,9 @@ type MachineDeploymentReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// Sharder, if set, limits the reconciliation to the Clusters assigned to this replica of the manager.
+	Sharder *shard.Sharder
 }
 
 func (r *MachineDeploymentReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -121,6 +139,7 @@ func (r *MachineDeploymentReconciler) SetupWithManager(ctx context.Context, mgr
 		UnstructuredCachingClient: r.UnstructuredCachingClient,
 		APIReader:                 r.APIReader,
 		WatchFilterValue:          r.WatchFilterValue,
+		Sharder:                   r.Sharder,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -131,6 +150,9 @@ type MachineHealthCheckReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// Sharder, if set, limits the reconciliation to the Clusters assigned to this replica of the manager.
+	Sharder *shard.Sharder
 }
 
 func (r *MachineHealthCheckReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -138,6 +160,41 @@ func (r *MachineHealthCheckReconciler) SetupWithManager(ctx context.Context, mgr
 		Client:           r.Client,
 		Tracker:          r.Tracker,
 		WatchFilterValue: r.WatchFilterValue,
+		Sharder:          r.Sharder,
+	}).SetupWithManager(ctx, mgr, options)
+}
+
+// KubeconfigRotationReconciler rotates the client certificates of the kubeconfig Secrets of the Clusters.
+type KubeconfigRotationReconciler struct {
+	Client client.Client
+
+	// RotationThreshold is the time before the expiry of the client certificates at which they are rotated.
+	RotationThreshold time.Duration
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *KubeconfigRotationReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return (&kubeconfigrotationcontroller.Reconciler{
+		Client:            r.Client,
+		RotationThreshold: r.RotationThreshold,
+		WatchFilterValue:  r.WatchFilterValue,
+	}).SetupWithManager(ctx, mgr, options)
+}
+
+// MaintenanceReconciler pauses the Clusters and ClusterClasses of the Namespaces in maintenance.
+type MaintenanceReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *MaintenanceReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return (&maintenancecontroller.Reconciler{
+		Client:           r.Client,
+		WatchFilterValue: r.WatchFilterValue,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -151,6 +152,11 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 				_, ok := cct.loadAccessor(testClusterKey)
 				return ok
 			}, 5*time.Second, 1*time.Second).Should(BeTrue())
+
+			// The connection is reported as up.
+			g.Expect(testutil.ToFloat64(connectionUp.With(clusterLabels(testClusterKey)))).To(Equal(1.0))
+			g.Expect(testutil.ToFloat64(healthCheckConsecutiveFailures.With(clusterLabels(testClusterKey)))).To(Equal(0.0))
+			g.Expect(testutil.ToFloat64(lastSuccessfulHealthCheck.With(clusterLabels(testClusterKey)))).To(BeNumerically(">", 0))
 		})
 
 		t.Run("during creation of a new cluster accessor", func(t *testing.T) {
@@ -209,6 +215,10 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 				_, ok := cct.loadAccessor(testClusterKey)
 				return ok
 			}, 5*time.Second, 1*time.Second).Should(BeFalse())
+
+			// The connection is reported as down.
+			g.Expect(testutil.ToFloat64(connectionUp.With(clusterLabels(testClusterKey)))).To(Equal(0.0))
+			g.Expect(testutil.ToFloat64(healthCheckConsecutiveFailures.With(clusterLabels(testClusterKey)))).To(BeNumerically(">=", testUnhealthyThreshold))
 		})
 
 		t.Run("with an invalid config", func(t *testing.T) {
@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// clusterClientOptions are the options used to create the client and the cache for a workload cluster.
+type clusterClientOptions struct {
+	// qps and burst are the client rate limits; zero values mean the client-go defaults.
+	qps   float32
+	burst int
+
+	// uncachedObjects are the objects read directly from the API server of the workload cluster.
+	uncachedObjects []client.Object
+
+	// byObject restricts the objects cached per type.
+	byObject map[client.Object]cache.ByObject
+
+	// proxy is the URL of the proxy used to reach the API server of the workload cluster, if any.
+	proxy *url.URL
+}
+
+// clusterClientOptionsFor returns the options used to create the client and the cache for a workload cluster,
+// i.e. the options of the ClusterCacheTracker overridden by the cluster-cache annotations of the Cluster.
+func (t *ClusterCacheTracker) clusterClientOptionsFor(cluster *clusterv1.Cluster) (*clusterClientOptions, error) {
+	opts := &clusterClientOptions{
+		qps:             t.clientQPS,
+		burst:           t.clientBurst,
+		uncachedObjects: append([]client.Object{}, t.clientUncachedObjects...),
+		byObject:        map[client.Object]cache.ByObject{},
+		proxy:           t.proxy,
+	}
+	for obj, byObject := range t.cacheByObject {
+		opts.byObject[obj] = byObject
+	}
+
+	annotations := cluster.GetAnnotations()
+	if value, ok := annotations[clusterv1.ClusterCacheClientQPSAnnotation]; ok {
+		qps, err := strconv.ParseFloat(value, 32)
+		if err != nil || qps <= 0 {
+			return nil, errors.Errorf("invalid value %q for annotation %s: must be a positive number", value, clusterv1.ClusterCacheClientQPSAnnotation)
+		}
+		opts.qps = float32(qps)
+	}
+	if value, ok := annotations[clusterv1.ClusterCacheClientBurstAnnotation]; ok {
+		burst, err := strconv.Atoi(value)
+		if err != nil || burst <= 0 {
+			return nil, errors.Errorf("invalid value %q for annotation %s: must be a positive integer", value, clusterv1.ClusterCacheClientBurstAnnotation)
+		}
+		opts.burst = burst
+	}
+	if value, ok := annotations[clusterv1.ClusterCacheUncachedKindsAnnotation]; ok {
+		for _, kind := range strings.Split(value, ",") {
+			kind = strings.TrimSpace(kind)
+			if kind == "" {
+				continue
+			}
+			obj, err := newObjectForKind(t.scheme, kind)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid value %q for annotation %s", value, clusterv1.ClusterCacheUncachedKindsAnnotation)
+			}
+			opts.uncachedObjects = append(opts.uncachedObjects, obj)
+		}
+	}
+	if value, ok := annotations[clusterv1.ClusterCacheNodeLabelSelectorAnnotation]; ok {
+		selector, err := labels.Parse(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value %q for annotation %s", value, clusterv1.ClusterCacheNodeLabelSelectorAnnotation)
+		}
+		for obj := range opts.byObject {
+			if _, ok := obj.(*corev1.Node); ok {
+				delete(opts.byObject, obj)
+			}
+		}
+		opts.byObject[&corev1.Node{}] = cache.ByObject{Label: selector}
+	}
+	if value, ok := annotations[clusterv1.ClusterCacheProxyAnnotation]; ok {
+		proxy, err := ParseProxyURL(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value %q for annotation %s", value, clusterv1.ClusterCacheProxyAnnotation)
+		}
+		opts.proxy = proxy
+	}
+	return opts, nil
+}
+
+// ParseProxyURL parses the URL of a proxy used to reach the API server of workload clusters; the supported
+// schemes are http and https, for HTTP CONNECT proxies, and socks5, for SOCKS5 proxies.
+func ParseProxyURL(value string) (*url.URL, error) {
+	proxy, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.Errorf("unsupported proxy scheme %q, must be one of http, https or socks5", proxy.Scheme)
+	}
+	if proxy.Host == "" {
+		return nil, errors.New("proxy URL must have a host")
+	}
+	return proxy, nil
+}
+
+// newObjectForKind returns a new object for a kind in the Kind or Kind.group form, using the preferred
+// version of the group registered in the scheme; kinds without a group are looked up in the core group.
+func newObjectForKind(scheme *runtime.Scheme, kind string) (client.Object, error) {
+	group := ""
+	if i := strings.Index(kind, "."); i >= 0 {
+		kind, group = kind[:i], kind[i+1:]
+	}
+	for _, gv := range scheme.PrioritizedVersionsForGroup(group) {
+		gvk := gv.WithKind(kind)
+		if !scheme.Recognizes(gvk) {
+			continue
+		}
+		obj, err := scheme.New(gvk)
+		if err != nil {
+			return nil, err
+		}
+		clientObj, ok := obj.(client.Object)
+		if !ok {
+			return nil, errors.Errorf("kind %s is not an object", gvk)
+		}
+		return clientObj, nil
+	}
+	return nil, errors.Errorf("kind %q of group %q is not registered in the scheme", kind, group)
+}
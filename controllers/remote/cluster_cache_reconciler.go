@@ -20,37 +20,64 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/metrics"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/cluster-api/util/shard"
 )
 
 // ClusterCacheReconciler is responsible for stopping remote cluster caches when
-// the cluster for the remote cache is being deleted.
+// the cluster for the remote cache is being deleted, and for removing the metrics of the connection to the cluster.
+// It also refreshes the remote cluster caches when the kubeconfig of the cluster changes, e.g. when its client
+// certificates are rotated.
+// When the controllers are sharded, it runs on all the replicas and it also stops the remote cluster caches and removes
+// the metrics of the clusters which are not assigned to the replica anymore, unless the replica is the leader.
 type ClusterCacheReconciler struct {
 	Client  client.Client
 	Tracker *ClusterCacheTracker
 
+	// Sharder, if set, releases the remote cluster caches and the metrics of the clusters not assigned to this replica.
+	Sharder *shard.Sharder
+
+	// Elected is closed when this replica is elected as leader; the leader keeps the remote cluster caches of all the
+	// clusters, because the controllers which are not sharded run only on the leader.
+	Elected <-chan struct{}
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 }
 
 func (r *ClusterCacheReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
-	err := ctrl.NewControllerManagedBy(mgr).
+	c, err := ctrl.NewControllerManagedBy(mgr).
 		Named("remote/clustercache").
 		For(&clusterv1.Cluster{}).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(kubeconfigSecretToCluster),
+		).
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
-		Complete(r)
+		Build(r)
 
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
+
+	if r.Sharder != nil {
+		// Release the clusters which are not assigned to this replica anymore, e.g. after another replica joined.
+		if err := c.Watch(r.Sharder.ReleasedSource(), &handler.EnqueueRequestForObject{}); err != nil {
+			return errors.Wrap(err, "failed adding Watch for the Clusters released by the shard")
+		}
+	}
 	return nil
 }
 
@@ -65,6 +92,18 @@ func (r *ClusterCacheReconciler) Reconcile(ctx context.Context, req reconcile.Re
 	err := r.Client.Get(ctx, req.NamespacedName, &cluster)
 	if err == nil {
 		log.V(4).Info("Cluster still exists")
+		if !r.Sharder.Owns(&cluster) && !r.isLeader() {
+			log.V(4).Info("Cluster is assigned to another replica")
+			r.releaseCluster(ctx, req.NamespacedName)
+			return reconcile.Result{}, nil
+		}
+		if err := r.Tracker.refreshAccessorIfKubeconfigChanged(ctx, req.NamespacedName); err != nil {
+			// Requeue if another worker has the lock on the ClusterCacheTracker for the cluster.
+			if errors.Is(err, ErrClusterLocked) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, err
+		}
 		return reconcile.Result{}, nil
 	} else if !apierrors.IsNotFound(err) {
 		log.Error(err, "Error retrieving cluster")
@@ -72,8 +111,36 @@ func (r *ClusterCacheReconciler) Reconcile(ctx context.Context, req reconcile.Re
 	}
 
 	log.V(2).Info("Cluster no longer exists")
-
-	r.Tracker.deleteAccessor(ctx, req.NamespacedName)
+	r.releaseCluster(ctx, req.NamespacedName)
 
 	return reconcile.Result{}, nil
 }
+
+// releaseCluster stops the remote cluster cache and removes the metrics of a cluster.
+func (r *ClusterCacheReconciler) releaseCluster(ctx context.Context, cluster client.ObjectKey) {
+	r.Tracker.deleteAccessor(ctx, cluster)
+	deleteClusterMetrics(cluster)
+	metrics.DeleteClusterMetrics(cluster)
+}
+
+// isLeader returns true if this replica is the leader.
+func (r *ClusterCacheReconciler) isLeader() bool {
+	if r.Elected == nil {
+		return true
+	}
+	select {
+	case <-r.Elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// kubeconfigSecretToCluster maps kubeconfig Secrets to the Cluster they belong to.
+func kubeconfigSecretToCluster(_ context.Context, o client.Object) []reconcile.Request {
+	clusterName, purpose, err := secret.ParseSecretName(o.GetName())
+	if err != nil || purpose != secret.Kubeconfig {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: o.GetNamespace(), Name: clusterName}}}
+}
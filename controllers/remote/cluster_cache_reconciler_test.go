@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
@@ -154,3 +155,14 @@ func TestClusterCacheReconciler(t *testing.T) {
 		})
 	})
 }
+
+func TestKubeconfigSecretToCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(kubeconfigSecretToCluster(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "test-kubeconfig"},
+	})).To(ConsistOf(reconcile.Request{NamespacedName: client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "test"}}))
+	g.Expect(kubeconfigSecretToCluster(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "test-ca"},
+	})).To(BeEmpty())
+}
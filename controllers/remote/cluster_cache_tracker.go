@@ -17,10 +17,13 @@ limitations under the License.
 package remote
 
 import (
+	"bytes"
 	"context"
 	"crypto/rsa"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"sync"
 	"time"
@@ -37,6 +40,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -48,6 +52,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/util/certs"
 	"sigs.k8s.io/cluster-api/util/conditions"
 )
@@ -69,6 +74,19 @@ type ClusterCacheTracker struct {
 	log                   logr.Logger
 	clientUncachedObjects []client.Object
 
+	// clientQPS and clientBurst are the rate limits of the clients for workload clusters.
+	clientQPS   float32
+	clientBurst int
+
+	// cacheByObject restricts the objects cached per type for workload clusters.
+	cacheByObject map[client.Object]cache.ByObject
+
+	// proxy is the URL of the proxy used to reach the API server of workload clusters, if any.
+	proxy *url.URL
+
+	// dialContext is the dialer used to open connections to the API server of workload clusters, if any.
+	dialContext DialContextFunc
+
 	client client.Client
 
 	// SecretCachingClient is a client which caches secrets.
@@ -78,6 +96,10 @@ type ClusterCacheTracker struct {
 
 	scheme *runtime.Scheme
 
+	// recorder is used to record events on Clusters when the connection to the workload cluster is
+	// established or lost. Events are not recorded if it is nil.
+	recorder record.EventRecorder
+
 	// clusterAccessorsLock is used to lock the access to the clusterAccessors map.
 	clusterAccessorsLock sync.RWMutex
 	// clusterAccessors is the map of clusterAccessors by cluster.
@@ -99,6 +121,9 @@ type ClusterCacheTracker struct {
 	controllerPodMetadata *metav1.ObjectMeta
 }
 
+// DialContextFunc opens a connection to the given address of a workload cluster.
+type DialContextFunc func(ctx context.Context, cluster client.ObjectKey, network, address string) (net.Conn, error)
+
 // ClusterCacheTrackerOptions defines options to configure
 // a ClusterCacheTracker.
 type ClusterCacheTrackerOptions struct {
@@ -117,6 +142,32 @@ type ClusterCacheTrackerOptions struct {
 	ClientUncachedObjects []client.Object
 	Indexes               []Index
 
+	// ClientQPS is the maximum queries per second from the clients to the API server of workload clusters.
+	// If not set, the client-go default is used.
+	// It can be overridden per Cluster with the cluster-cache.cluster.x-k8s.io/client-qps annotation.
+	ClientQPS float32
+
+	// ClientBurst is the maximum burst for throttle of the clients to the API server of workload clusters.
+	// If not set, the client-go default is used.
+	// It can be overridden per Cluster with the cluster-cache.cluster.x-k8s.io/client-burst annotation.
+	ClientBurst int
+
+	// CacheByObject restricts the objects cached per type for workload clusters, e.g. to cache only
+	// the Nodes with a given label; see cache.Options.ByObject.
+	// The label selector for Nodes can be overridden per Cluster with the
+	// cluster-cache.cluster.x-k8s.io/node-label-selector annotation.
+	CacheByObject map[client.Object]cache.ByObject
+
+	// Proxy is the URL of an HTTP CONNECT (http or https scheme) or SOCKS5 (socks5 scheme) proxy used to reach
+	// the API server of workload clusters, e.g. a bastion host. If not set, the proxy defined by the environment
+	// variables is used.
+	// It can be overridden per Cluster with the cluster-cache.cluster.x-k8s.io/proxy annotation.
+	Proxy *url.URL
+
+	// DialContext is a custom dialer used to open the connections to the API server of workload clusters, or
+	// to the proxy if any, e.g. to connect through an SSH tunnel. If not set, the default dialer is used.
+	DialContext DialContextFunc
+
 	// ControllerName is the name of the controller.
 	// This is used to calculate the user agent string.
 	// If not set, it defaults to "cluster-cache-tracker".
@@ -166,9 +217,15 @@ func NewClusterCacheTracker(manager ctrl.Manager, options ClusterCacheTrackerOpt
 		controllerPodMetadata: controllerPodMetadata,
 		log:                   *options.Log,
 		clientUncachedObjects: options.ClientUncachedObjects,
+		clientQPS:             options.ClientQPS,
+		clientBurst:           options.ClientBurst,
+		cacheByObject:         options.CacheByObject,
+		proxy:                 options.Proxy,
+		dialContext:           options.DialContext,
 		client:                manager.GetClient(),
 		secretCachingClient:   options.SecretCachingClient,
 		scheme:                manager.GetScheme(),
+		recorder:              manager.GetEventRecorderFor(controllerName),
 		clusterAccessors:      make(map[client.ObjectKey]*clusterAccessor),
 		clusterLock:           newKeyedMutex(),
 		indexes:               options.Indexes,
@@ -210,10 +267,22 @@ type clusterAccessor struct {
 	cache                    *stoppableCache
 	client                   client.Client
 	watches                  sets.Set[string]
+	watchInputs              []WatchInput
 	config                   *rest.Config
 	etcdClientCertificateKey *rsa.PrivateKey
 }
 
+// ConnectionStatus returns the number of workload clusters connected, and the number of workload clusters
+// pending, i.e. the ones the ClusterCacheTracker is connecting to, or adding watches for.
+// NOTE: Requests for pending clusters fail with ErrClusterLocked, so a growing number of pending clusters
+// means that controllers are requeueing requests for the clusters.
+func (t *ClusterCacheTracker) ConnectionStatus() (connected int, pending int) {
+	t.clusterAccessorsLock.RLock()
+	defer t.clusterAccessorsLock.RUnlock()
+
+	return len(t.clusterAccessors), t.clusterLock.Len()
+}
+
 // clusterAccessorExists returns true if a clusterAccessor exists for cluster.
 func (t *ClusterCacheTracker) clusterAccessorExists(cluster client.ObjectKey) bool {
 	t.clusterAccessorsLock.RLock()
@@ -272,14 +341,32 @@ func (t *ClusterCacheTracker) getClusterAccessor(ctx context.Context, cluster cl
 	log.V(4).Info("Creating new cluster accessor")
 	accessor, err := t.newClusterAccessor(ctx, cluster, indexes...)
 	if err != nil {
+		connectionUp.With(clusterLabels(cluster)).Set(0)
 		return nil, errors.Wrap(err, "failed to create cluster accessor")
 	}
 
 	log.V(4).Info("Storing new cluster accessor")
 	t.storeAccessor(cluster, accessor)
+
+	connectionUp.With(clusterLabels(cluster)).Set(1)
+	recordKubeconfigCertificateExpiry(cluster, accessor.config)
+	t.recordEvent(ctx, cluster, corev1.EventTypeNormal, clusterv1.EventRemoteConnectionEstablished,
+		"Connected to the workload cluster using %q", accessor.config.Host)
 	return accessor, nil
 }
 
+// recordEvent records an event on a Cluster, if the ClusterCacheTracker has an event recorder.
+func (t *ClusterCacheTracker) recordEvent(ctx context.Context, clusterKey client.ObjectKey, eventtype, reason, messageFmt string, args ...interface{}) {
+	if t.recorder == nil {
+		return
+	}
+	cluster := &clusterv1.Cluster{}
+	if err := t.client.Get(ctx, clusterKey, cluster); err != nil {
+		return
+	}
+	t.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "", ""), eventtype, reason, messageFmt, args...)
+}
+
 // newClusterAccessor creates a new clusterAccessor.
 func (t *ClusterCacheTracker) newClusterAccessor(ctx context.Context, cluster client.ObjectKey, indexes ...Index) (*clusterAccessor, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -295,8 +382,34 @@ func (t *ClusterCacheTracker) newClusterAccessor(ctx context.Context, cluster cl
 		return nil, errors.Wrapf(err, "error fetching REST client config for remote cluster %q", cluster.String())
 	}
 
+	clusterObj := &clusterv1.Cluster{}
+	if err := t.client.Get(ctx, cluster, clusterObj); err != nil {
+		return nil, errors.Wrapf(err, "error getting remote cluster %q", cluster.String())
+	}
+
+	// Apply the client options of the tracker, overridden by the Cluster annotations.
+	clientOptions, err := t.clusterClientOptionsFor(clusterObj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error computing client options for remote cluster %q", cluster.String())
+	}
+	config.QPS = clientOptions.qps
+	config.Burst = clientOptions.burst
+	if clientOptions.proxy != nil {
+		config.Proxy = http.ProxyURL(clientOptions.proxy)
+	}
+	if t.dialContext != nil {
+		config.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+			return t.dialContext(ctx, cluster, network, address)
+		}
+	}
+
+	// Reach the API server through the tunnel server if the cluster defines a tunnel.
+	if err := configureTunnel(config, clusterObj); err != nil {
+		return nil, err
+	}
+
 	// Create a client and a cache for the cluster.
-	c, uncachedClient, cache, err := t.createClient(ctx, config, cluster, indexes)
+	c, uncachedClient, cache, err := t.createClient(ctx, config, cluster, clientOptions, indexes)
 	if err != nil {
 		return nil, err
 	}
@@ -320,9 +433,11 @@ func (t *ClusterCacheTracker) newClusterAccessor(ctx context.Context, cluster cl
 		config.CAData = nil
 		config.CAFile = inClusterConfig.CAFile
 		config.Host = inClusterConfig.Host
+		// The in-cluster service is reachable without going through the tunnel or the proxy.
+		config.Proxy = nil
 
 		// Create a new client and overwrite the previously created client.
-		c, _, cache, err = t.createClient(ctx, config, cluster, indexes)
+		c, _, cache, err = t.createClient(ctx, config, cluster, clientOptions, indexes)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating client for self-hosted cluster")
 		}
@@ -375,7 +490,7 @@ func (t *ClusterCacheTracker) runningOnWorkloadCluster(ctx context.Context, c cl
 }
 
 // createClient creates a cached client, and uncached client and a mapper based on a rest.Config.
-func (t *ClusterCacheTracker) createClient(ctx context.Context, config *rest.Config, cluster client.ObjectKey, indexes []Index) (client.Client, client.Client, *stoppableCache, error) {
+func (t *ClusterCacheTracker) createClient(ctx context.Context, config *rest.Config, cluster client.ObjectKey, clientOptions *clusterClientOptions, indexes []Index) (client.Client, client.Client, *stoppableCache, error) {
 	// Create a http client for the cluster.
 	httpClient, err := rest.HTTPClientFor(config)
 	if err != nil {
@@ -401,11 +516,12 @@ func (t *ClusterCacheTracker) createClient(ctx context.Context, config *rest.Con
 		HTTPClient: httpClient,
 		Scheme:     t.scheme,
 		Mapper:     mapper,
+		ByObject:   clientOptions.byObject,
 	}
-	remoteCache, err := cache.New(config, cacheOptions)
-	if err != nil {
-		return nil, nil, nil, errors.Wrapf(err, "error creating client for remote cluster %q: error creating cache", cluster.String())
-	}
+	// The informers are created lazily, per kind, when the controllers read or watch objects of the kind.
+	remoteCache := newLazyCache(cluster, t.scheme, func() (cache.Cache, error) {
+		return cache.New(config, cacheOptions)
+	})
 
 	cacheCtx, cacheCtxCancel := context.WithCancel(ctx)
 
@@ -428,7 +544,7 @@ func (t *ClusterCacheTracker) createClient(ctx context.Context, config *rest.Con
 		HTTPClient: httpClient,
 		Cache: &client.CacheOptions{
 			Reader:       cache,
-			DisableFor:   t.clientUncachedObjects,
+			DisableFor:   clientOptions.uncachedObjects,
 			Unstructured: true,
 		},
 	})
@@ -467,6 +583,7 @@ func (t *ClusterCacheTracker) createClient(ctx context.Context, config *rest.Con
 	// Start cluster healthcheck!!!
 	go t.healthCheckCluster(cacheCtx, &healthCheckInput{
 		cluster:    cluster,
+		cache:      cache,
 		cfg:        config,
 		httpClient: httpClient,
 	})
@@ -488,11 +605,93 @@ func (t *ClusterCacheTracker) deleteAccessor(_ context.Context, cluster client.O
 	log.V(2).Info("Deleting clusterAccessor")
 	log.V(4).Info("Stopping cache")
 	a.cache.Stop()
+	deleteCacheMetrics(cluster)
 	log.V(4).Info("Cache stopped")
 
 	delete(t.clusterAccessors, cluster)
 }
 
+// deleteAccessorWithCache stops a clusterAccessor's cache and removes the clusterAccessor from the tracker,
+// if the clusterAccessor is using the given cache.
+func (t *ClusterCacheTracker) deleteAccessorWithCache(ctx context.Context, cluster client.ObjectKey, cache *stoppableCache) {
+	t.clusterAccessorsLock.RLock()
+	a, exists := t.clusterAccessors[cluster]
+	t.clusterAccessorsLock.RUnlock()
+	if !exists || a.cache != cache {
+		return
+	}
+	t.deleteAccessor(ctx, cluster)
+}
+
+// refreshAccessorIfKubeconfigChanged replaces the clusterAccessor of a cluster if the credentials in the kubeconfig
+// of the cluster are not the ones used by the clusterAccessor anymore, e.g. because its client certificates
+// have been rotated.
+func (t *ClusterCacheTracker) refreshAccessorIfKubeconfigChanged(ctx context.Context, cluster client.ObjectKey) error {
+	accessor, ok := t.loadAccessor(cluster)
+	if !ok {
+		// The clusterAccessor will be created with the current kubeconfig when it is first used.
+		return nil
+	}
+
+	secretClient := t.client
+	if t.secretCachingClient != nil {
+		secretClient = t.secretCachingClient
+	}
+	config, err := RESTConfig(ctx, t.controllerName, secretClient, cluster)
+	if err != nil {
+		return errors.Wrapf(err, "error fetching REST client config for remote cluster %q", cluster.String())
+	}
+
+	if bytes.Equal(config.CertData, accessor.config.CertData) &&
+		bytes.Equal(config.KeyData, accessor.config.KeyData) &&
+		config.BearerToken == accessor.config.BearerToken {
+		return nil
+	}
+	return t.refreshAccessor(ctx, cluster)
+}
+
+// refreshAccessor replaces the clusterAccessor of a cluster with a new clusterAccessor created from the current
+// kubeconfig of the cluster. The new clusterAccessor is connected and the watches of the old one are added to it
+// before the old one is stopped, so there is no gap in the connection to the cluster and in the watches.
+// If the new clusterAccessor cannot be created, the old one is kept.
+func (t *ClusterCacheTracker) refreshAccessor(ctx context.Context, cluster client.ObjectKey) error {
+	log := ctrl.LoggerFrom(ctx, "cluster", klog.KRef(cluster.Namespace, cluster.Name))
+
+	if ok := t.clusterLock.TryLock(cluster); !ok {
+		return errors.Wrapf(ErrClusterLocked, "failed to refresh cluster accessor: failed to get lock for cluster")
+	}
+	defer t.clusterLock.Unlock(cluster)
+
+	oldAccessor, ok := t.loadAccessor(cluster)
+	if !ok {
+		return nil
+	}
+
+	log.V(4).Info("Creating new cluster accessor with the updated kubeconfig")
+	accessor, err := t.newClusterAccessor(ctx, cluster, t.indexes...)
+	if err != nil {
+		return errors.Wrap(err, "failed to refresh cluster accessor")
+	}
+
+	for _, input := range oldAccessor.watchInputs {
+		if err := input.Watcher.Watch(source.Kind(accessor.cache, input.Kind), input.EventHandler, input.Predicates...); err != nil {
+			accessor.cache.Stop()
+			return errors.Wrapf(err, "failed to refresh cluster accessor: failed to add %s watch", input.Kind)
+		}
+		accessor.watches.Insert(input.Name)
+		accessor.watchInputs = append(accessor.watchInputs, input)
+	}
+
+	log.V(4).Info("Replacing cluster accessor")
+	t.storeAccessor(cluster, accessor)
+	oldAccessor.cache.Stop()
+
+	recordKubeconfigCertificateExpiry(cluster, accessor.config)
+	t.recordEvent(ctx, cluster, corev1.EventTypeNormal, clusterv1.EventRemoteConnectionRefreshed,
+		"Reconnected to the workload cluster using %q with the updated kubeconfig", accessor.config.Host)
+	return nil
+}
+
 // Watcher is a scoped-down interface from Controller that only knows how to watch.
 type Watcher interface {
 	// Watch watches src for changes, sending events to eventHandler if they pass predicates.
@@ -550,6 +749,7 @@ func (t *ClusterCacheTracker) Watch(ctx context.Context, input WatchInput) error
 	}
 
 	accessor.watches.Insert(input.Name)
+	accessor.watchInputs = append(accessor.watchInputs, input)
 
 	return nil
 }
@@ -557,6 +757,7 @@ func (t *ClusterCacheTracker) Watch(ctx context.Context, input WatchInput) error
 // healthCheckInput provides the input for the healthCheckCluster method.
 type healthCheckInput struct {
 	cluster            client.ObjectKey
+	cache              *stoppableCache
 	httpClient         *http.Client
 	cfg                *rest.Config
 	interval           time.Duration
@@ -638,15 +839,20 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 				// Unauthorized means that the underlying kubeconfig is not authorizing properly anymore, which
 				// usually is the result of automatic kubeconfig refreshes, meaning that we have to throw away the
 				// clusterAccessor and rely on the creation of a new one (with a refreshed kubeconfig)
+				t.recordConnectionLost(cluster, "Lost connection to the workload cluster: %v", err)
 				return false, err
 			}
 			unhealthyCount++
 		} else {
 			unhealthyCount = 0
+			connectionUp.With(clusterLabels(in.cluster)).Set(1)
+			lastSuccessfulHealthCheck.With(clusterLabels(in.cluster)).SetToCurrentTime()
 		}
+		healthCheckConsecutiveFailures.With(clusterLabels(in.cluster)).Set(float64(unhealthyCount))
 
 		if unhealthyCount >= in.unhealthyThreshold {
 			// Cluster is now considered unhealthy.
+			t.recordConnectionLost(cluster, "Lost connection to the workload cluster after %d consecutive failed health checks: %v", unhealthyCount, err)
 			return false, err
 		}
 
@@ -665,9 +871,24 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 	// Ensure in any case that the accessor is deleted (even if it is a no-op).
 	// NB. It is crucial to ensure the accessor was deleted, so it can be later recreated when the
 	// cluster is reachable again
+	// NB. If the accessor has been replaced by refreshAccessor, the new accessor has its own health check
+	// and must not be deleted.
+	if in.cache != nil {
+		t.deleteAccessorWithCache(ctx, in.cluster, in.cache)
+		return
+	}
 	t.deleteAccessor(ctx, in.cluster)
 }
 
+// recordConnectionLost reports that the connection to a workload cluster is down, in the metrics and with an event
+// on the Cluster.
+func (t *ClusterCacheTracker) recordConnectionLost(cluster *clusterv1.Cluster, messageFmt string, args ...interface{}) {
+	connectionUp.With(clusterLabels(client.ObjectKeyFromObject(cluster))).Set(0)
+	if t.recorder != nil {
+		t.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "", ""), corev1.EventTypeWarning, clusterv1.EventRemoteConnectionLost, messageFmt, args...)
+	}
+}
+
 // newClientWithTimeout returns a new client which sets the specified timeout on all Get and List calls.
 // If we don't set timeouts here Get and List calls can get stuck if they lazily create a new informer
 // and the informer than doesn't sync because the workload cluster apiserver is not reachable.
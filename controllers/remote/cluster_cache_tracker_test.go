@@ -359,3 +359,96 @@ func cleanupTestClusters(ctx context.Context, c client.Client) error {
 	}
 	return nil
 }
+
+func TestRefreshAccessorIfKubeconfigChanged(t *testing.T) {
+	clusterKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "test"}
+	kubeconfigSecret := func(token string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: clusterKey.Namespace, Name: "test-kubeconfig"},
+			Data: map[string][]byte{
+				"value": []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:1
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: %s
+`, token)),
+			},
+		}
+	}
+	newTracker := func(objs ...client.Object) *ClusterCacheTracker {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build()
+		return &ClusterCacheTracker{
+			log:              ctrl.Log,
+			client:           c,
+			scheme:           scheme.Scheme,
+			clusterAccessors: map[client.ObjectKey]*clusterAccessor{},
+			clusterLock:      newKeyedMutex(),
+		}
+	}
+
+	t.Run("should not create an accessor if it does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+		tracker := newTracker(kubeconfigSecret("foo"))
+
+		g.Expect(tracker.refreshAccessorIfKubeconfigChanged(ctx, clusterKey)).To(Succeed())
+		g.Expect(tracker.clusterAccessorExists(clusterKey)).To(BeFalse())
+	})
+
+	t.Run("should keep the accessor if the kubeconfig did not change", func(t *testing.T) {
+		g := NewWithT(t)
+		tracker := newTracker(kubeconfigSecret("foo"))
+		config, err := RESTConfig(ctx, "test", tracker.client, clusterKey)
+		g.Expect(err).ToNot(HaveOccurred())
+		accessor := &clusterAccessor{config: config, cache: &stoppableCache{}}
+		tracker.storeAccessor(clusterKey, accessor)
+
+		g.Expect(tracker.refreshAccessorIfKubeconfigChanged(ctx, clusterKey)).To(Succeed())
+		current, ok := tracker.loadAccessor(clusterKey)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(current).To(BeIdenticalTo(accessor))
+	})
+
+	t.Run("should keep the accessor if the kubeconfig changed but a new accessor cannot be created", func(t *testing.T) {
+		g := NewWithT(t)
+		tracker := newTracker(kubeconfigSecret("bar"))
+		config, err := RESTConfig(ctx, "test", newTracker(kubeconfigSecret("foo")).client, clusterKey)
+		g.Expect(err).ToNot(HaveOccurred())
+		accessor := &clusterAccessor{config: config, cache: &stoppableCache{}}
+		tracker.storeAccessor(clusterKey, accessor)
+
+		g.Expect(tracker.refreshAccessorIfKubeconfigChanged(ctx, clusterKey)).ToNot(Succeed())
+		current, ok := tracker.loadAccessor(clusterKey)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(current).To(BeIdenticalTo(accessor))
+	})
+}
+
+func TestDeleteAccessorWithCache(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "test"}
+	tracker := &ClusterCacheTracker{
+		log:              ctrl.Log,
+		clusterAccessors: map[client.ObjectKey]*clusterAccessor{},
+	}
+	accessor := &clusterAccessor{cache: &stoppableCache{cancelFunc: func() {}}}
+	tracker.storeAccessor(clusterKey, accessor)
+
+	// The accessor is not deleted by the health check of a replaced accessor.
+	tracker.deleteAccessorWithCache(ctx, clusterKey, &stoppableCache{cancelFunc: func() {}})
+	g.Expect(tracker.clusterAccessorExists(clusterKey)).To(BeTrue())
+
+	tracker.deleteAccessorWithCache(ctx, clusterKey, accessor.cache)
+	g.Expect(tracker.clusterAccessorExists(clusterKey)).To(BeFalse())
+}
@@ -64,3 +64,11 @@ func (k *keyedMutex) Unlock(key client.ObjectKey) {
 	// Remove the lock if it exists.
 	delete(k.locks, key)
 }
+
+// Len returns the number of keys locked.
+func (k *keyedMutex) Len() int {
+	k.locksMtx.Lock()
+	defer k.locksMtx.Unlock()
+
+	return len(k.locks)
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// lazyCache is a cache.Cache creating a separate informer cache for each kind the first time objects of the kind
+// are read or watched. This way, connecting to a workload cluster does not start any informer, only the kinds
+// used by the controllers are cached, and the indexes of a kind are registered before its informers are started.
+type lazyCache struct {
+	cluster  client.ObjectKey
+	scheme   *runtime.Scheme
+	newCache func() (cache.Cache, error)
+
+	lock sync.Mutex
+	// ctx is the context the lazyCache has been started with; it is nil until the lazyCache is started.
+	ctx context.Context
+	// caches are the informer caches by kind.
+	caches map[schema.GroupVersionKind]cache.Cache
+	// indexes are the indexes to register on the informer caches when they are created.
+	indexes []lazyIndex
+	// sizes are the sizes of the cached objects by kind, reported in the cache metrics.
+	sizes map[string]*cacheSize
+	// informers are the informers of the informer caches whose objects are counted in sizes.
+	informers map[cache.Informer]struct{}
+}
+
+// lazyIndex is an index registered on the lazyCache, with the kind it applies to.
+type lazyIndex struct {
+	gvk          schema.GroupVersionKind
+	object       client.Object
+	field        string
+	extractValue client.IndexerFunc
+}
+
+var _ cache.Cache = &lazyCache{}
+
+// newLazyCache returns a lazyCache creating informer caches for a workload cluster with newCache.
+func newLazyCache(cluster client.ObjectKey, scheme *runtime.Scheme, newCache func() (cache.Cache, error)) *lazyCache {
+	return &lazyCache{
+		cluster:   cluster,
+		scheme:    scheme,
+		newCache:  newCache,
+		caches:    map[schema.GroupVersionKind]cache.Cache{},
+		sizes:     map[string]*cacheSize{},
+		informers: map[cache.Informer]struct{}{},
+	}
+}
+
+// Get retrieves an object from the informer cache of its kind, creating the informer cache if necessary.
+func (l *lazyCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c, err := l.cacheForObject(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return c.Get(ctx, key, obj, opts...)
+}
+
+// List retrieves a list of objects from the informer cache of their kind, creating the informer cache if necessary.
+func (l *lazyCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	gvk, err := apiutil.GVKForObject(list, l.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+
+	var obj client.Object
+	switch list.(type) {
+	case *unstructured.UnstructuredList:
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		obj = u
+	case *metav1.PartialObjectMetadataList:
+		m := &metav1.PartialObjectMetadata{}
+		m.SetGroupVersionKind(gvk)
+		obj = m
+	default:
+		o, err := l.scheme.New(gvk)
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if obj, ok = o.(client.Object); !ok {
+			return errors.Errorf("kind %s is not an object", gvk)
+		}
+	}
+
+	c, err := l.cacheForObject(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return c.List(ctx, list, opts...)
+}
+
+// GetInformer returns the informer for the kind of obj, creating the informer cache if necessary.
+func (l *lazyCache) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	gvk, err := apiutil.GVKForObject(obj, l.scheme)
+	if err != nil {
+		return nil, err
+	}
+	c, err := l.cacheFor(ctx, gvk)
+	if err != nil {
+		return nil, err
+	}
+	informer, err := c.GetInformer(ctx, obj, opts...)
+	if err != nil {
+		return nil, err
+	}
+	l.trackInformer(gvk, informer)
+	return informer, nil
+}
+
+// GetInformerForKind returns the informer for a kind, creating the informer cache if necessary.
+func (l *lazyCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	c, err := l.cacheFor(ctx, gvk)
+	if err != nil {
+		return nil, err
+	}
+	informer, err := c.GetInformerForKind(ctx, gvk, opts...)
+	if err != nil {
+		return nil, err
+	}
+	l.trackInformer(gvk, informer)
+	return informer, nil
+}
+
+// IndexField registers an index, which is added to the informer cache of the kind of obj when it is created.
+// Indexes cannot be added to informer caches which are already created.
+func (l *lazyCache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	gvk, err := apiutil.GVKForObject(obj, l.scheme)
+	if err != nil {
+		return err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if c, ok := l.caches[gvk]; ok {
+		return c.IndexField(ctx, obj, field, extractValue)
+	}
+	l.indexes = append(l.indexes, lazyIndex{gvk: gvk, object: obj, field: field, extractValue: extractValue})
+	return nil
+}
+
+// Start starts the informer caches created so far and the ones created later, and blocks until ctx is done.
+func (l *lazyCache) Start(ctx context.Context) error {
+	l.lock.Lock()
+	if l.ctx != nil {
+		l.lock.Unlock()
+		return errors.New("cache already started")
+	}
+	l.ctx = ctx
+	for _, c := range l.caches {
+		go c.Start(ctx) //nolint:errcheck
+	}
+	l.lock.Unlock()
+
+	<-ctx.Done()
+	return nil
+}
+
+// WaitForCacheSync waits for the informer caches created so far to be synced.
+func (l *lazyCache) WaitForCacheSync(ctx context.Context) bool {
+	l.lock.Lock()
+	caches := make([]cache.Cache, 0, len(l.caches))
+	for _, c := range l.caches {
+		caches = append(caches, c)
+	}
+	l.lock.Unlock()
+
+	for _, c := range caches {
+		if !c.WaitForCacheSync(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheForObject returns the informer cache of the kind of obj, creating it if necessary, and ensures the size
+// of the objects of the informer used to read obj is tracked.
+func (l *lazyCache) cacheForObject(ctx context.Context, obj client.Object) (cache.Cache, error) {
+	gvk, err := apiutil.GVKForObject(obj, l.scheme)
+	if err != nil {
+		return nil, err
+	}
+	c, err := l.cacheFor(ctx, gvk)
+	if err != nil {
+		return nil, err
+	}
+	informer, err := c.GetInformer(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	l.trackInformer(gvk, informer)
+	return c, nil
+}
+
+// cacheFor returns the informer cache of a kind, creating it with the registered indexes of the kind if necessary.
+func (l *lazyCache) cacheFor(ctx context.Context, gvk schema.GroupVersionKind) (cache.Cache, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if c, ok := l.caches[gvk]; ok {
+		return c, nil
+	}
+
+	c, err := l.newCache()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create cache for %s", gvk.Kind)
+	}
+	for _, index := range l.indexes {
+		if index.gvk != gvk {
+			continue
+		}
+		if err := c.IndexField(ctx, index.object, index.field, index.extractValue); err != nil {
+			return nil, errors.Wrapf(err, "failed to add index for field %q to cache for %s", index.field, gvk.Kind)
+		}
+	}
+	if l.ctx != nil {
+		go c.Start(l.ctx) //nolint:errcheck
+	}
+	l.caches[gvk] = c
+
+	cacheInformers.With(clusterLabels(l.cluster)).Set(float64(len(l.caches)))
+	return c, nil
+}
+
+// trackInformer ensures the size of the objects of an informer is reported in the cache metrics.
+func (l *lazyCache) trackInformer(gvk schema.GroupVersionKind, informer cache.Informer) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, ok := l.informers[informer]; ok {
+		return
+	}
+	size, ok := l.sizes[gvk.Kind]
+	if !ok {
+		size = &cacheSize{cluster: l.cluster, kind: gvk.Kind}
+		l.sizes[gvk.Kind] = size
+	}
+	if _, err := informer.AddEventHandler(size); err != nil {
+		return
+	}
+	l.informers[informer] = struct{}{}
+}
+
+// cacheSize tracks the number and the estimated size of the cached objects of a kind, reported in the cache metrics.
+// The size of an object is estimated with the size of its JSON serialization.
+type cacheSize struct {
+	cluster client.ObjectKey
+	kind    string
+
+	lock    sync.Mutex
+	objects int
+	bytes   int
+}
+
+var _ toolscache.ResourceEventHandler = &cacheSize{}
+
+func (s *cacheSize) OnAdd(obj interface{}, _ bool) {
+	s.update(1, objectSize(obj))
+}
+
+func (s *cacheSize) OnUpdate(oldObj, newObj interface{}) {
+	s.update(0, objectSize(newObj)-objectSize(oldObj))
+}
+
+func (s *cacheSize) OnDelete(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	s.update(-1, -objectSize(obj))
+}
+
+func (s *cacheSize) update(objects, bytes int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.objects += objects
+	s.bytes += bytes
+	labels := cacheLabels(s.cluster, s.kind)
+	cacheObjects.With(labels).Set(float64(s.objects))
+	cacheObjectBytes.With(labels).Set(float64(s.bytes))
+}
+
+// objectSize returns the size of the JSON serialization of an object, or 0 if it cannot be serialized.
+func objectSize(obj interface{}) int {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// indexRecordingCache is a fake cache recording the indexes added to it.
+type indexRecordingCache struct {
+	*informertest.FakeInformers
+	indexes []string
+	started atomic.Bool
+}
+
+func (c *indexRecordingCache) IndexField(_ context.Context, _ client.Object, field string, _ client.IndexerFunc) error {
+	c.indexes = append(c.indexes, field)
+	return nil
+}
+
+func (c *indexRecordingCache) Start(ctx context.Context) error {
+	c.started.Store(true)
+	return c.FakeInformers.Start(ctx)
+}
+
+func TestLazyCache(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "test-lazy-cache"}
+	defer deleteCacheMetrics(cluster)
+
+	var caches []*indexRecordingCache
+	l := newLazyCache(cluster, scheme.Scheme, func() (cache.Cache, error) {
+		c := &indexRecordingCache{FakeInformers: &informertest.FakeInformers{Scheme: scheme.Scheme}}
+		caches = append(caches, c)
+		return c, nil
+	})
+
+	// Indexes are registered before any informer cache is created.
+	g.Expect(l.IndexField(ctx, &corev1.Node{}, "spec.providerID", nil)).To(Succeed())
+	g.Expect(l.IndexField(ctx, &corev1.Pod{}, "spec.nodeName", nil)).To(Succeed())
+
+	cacheCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go l.Start(cacheCtx) //nolint:errcheck
+	g.Expect(l.WaitForCacheSync(ctx)).To(BeTrue())
+	g.Expect(caches).To(BeEmpty())
+
+	// An informer cache is created for Nodes on first read, with the Node index only.
+	g.Expect(l.Get(ctx, client.ObjectKey{Name: "node"}, &corev1.Node{})).To(Succeed())
+	g.Expect(l.List(ctx, &corev1.NodeList{})).To(Succeed())
+	g.Expect(caches).To(HaveLen(1))
+	g.Expect(caches[0].indexes).To(ConsistOf("spec.providerID"))
+	g.Eventually(func() bool { return caches[0].started.Load() }).Should(BeTrue())
+
+	// Another informer cache is created for another kind.
+	_, err := l.GetInformer(ctx, &corev1.Pod{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(caches).To(HaveLen(2))
+	g.Expect(caches[1].indexes).To(ConsistOf("spec.nodeName"))
+	g.Expect(testutil.ToFloat64(cacheInformers.With(clusterLabels(cluster)))).To(Equal(float64(2)))
+
+	// The cached objects are reported in the metrics.
+	informer, err := caches[0].FakeInformerFor(ctx, &corev1.Node{})
+	g.Expect(err).ToNot(HaveOccurred())
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+	informer.Add(node)
+	g.Expect(testutil.ToFloat64(cacheObjects.With(cacheLabels(cluster, "Node")))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(cacheObjectBytes.With(cacheLabels(cluster, "Node")))).To(Equal(float64(objectSize(node))))
+
+	updatedNode := node.DeepCopy()
+	updatedNode.Labels = map[string]string{"example.com/role": "worker"}
+	informer.Update(node, updatedNode)
+	g.Expect(testutil.ToFloat64(cacheObjects.With(cacheLabels(cluster, "Node")))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(cacheObjectBytes.With(cacheLabels(cluster, "Node")))).To(Equal(float64(objectSize(updatedNode))))
+
+	informer.Delete(updatedNode)
+	g.Expect(testutil.ToFloat64(cacheObjects.With(cacheLabels(cluster, "Node")))).To(Equal(float64(0)))
+	g.Expect(testutil.ToFloat64(cacheObjectBytes.With(cacheLabels(cluster, "Node")))).To(Equal(float64(0)))
+
+	// Indexes added after the informer cache is created are added to it directly.
+	g.Expect(l.IndexField(ctx, &corev1.Node{}, "metadata.name", nil)).To(Succeed())
+	g.Expect(caches[0].indexes).To(ConsistOf("spec.providerID", "metadata.name"))
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"sigs.k8s.io/cluster-api/util/certs"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(connectionUp)
+	ctrlmetrics.Registry.MustRegister(healthCheckConsecutiveFailures)
+	ctrlmetrics.Registry.MustRegister(lastSuccessfulHealthCheck)
+	ctrlmetrics.Registry.MustRegister(kubeconfigCertificateExpiry)
+	ctrlmetrics.Registry.MustRegister(cacheInformers)
+	ctrlmetrics.Registry.MustRegister(cacheObjects)
+	ctrlmetrics.Registry.MustRegister(cacheObjectBytes)
+}
+
+// Metrics subsystem for the connections of the ClusterCacheTracker to the workload clusters.
+const clusterCacheSubsystem = "capi_cluster_cache"
+
+var (
+	// connectionUp reports whether the ClusterCacheTracker is connected to a workload cluster.
+	connectionUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: clusterCacheSubsystem,
+		Name:      "connection_up",
+		Help:      "Whether the connection to the workload cluster is up (1) or down (0), partitioned by cluster.",
+	}, []string{"namespace", "cluster_name"})
+
+	// healthCheckConsecutiveFailures reports the number of consecutive failed health checks of a workload cluster;
+	// the connection is considered down when it reaches the unhealthy threshold.
+	healthCheckConsecutiveFailures = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: clusterCacheSubsystem,
+		Name:      "health_check_consecutive_failures",
+		Help:      "Number of consecutive failed health checks of the workload cluster, partitioned by cluster.",
+	}, []string{"namespace", "cluster_name"})
+
+	// lastSuccessfulHealthCheck reports the time of the last successful health check of a workload cluster.
+	lastSuccessfulHealthCheck = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: clusterCacheSubsystem,
+		Name:      "last_successful_health_check_timestamp_seconds",
+		Help:      "Unix time of the last successful health check of the workload cluster, partitioned by cluster.",
+	}, []string{"namespace", "cluster_name"})
+
+	// kubeconfigCertificateExpiry reports the expiry of the client certificate of the kubeconfig used to connect to
+	// a workload cluster; it is not reported when the kubeconfig does not use a client certificate.
+	kubeconfigCertificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: clusterCacheSubsystem,
+		Name:      "kubeconfig_certificate_expiry_timestamp_seconds",
+		Help:      "Unix time the client certificate of the kubeconfig used to connect to the workload cluster expires, partitioned by cluster.",
+	}, []string{"namespace", "cluster_name"})
+
+	// cacheInformers reports the number of kinds cached for a workload cluster.
+	cacheInformers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: clusterCacheSubsystem,
+		Name:      "informers",
+		Help:      "Number of kinds cached for the workload cluster, partitioned by cluster.",
+	}, []string{"namespace", "cluster_name"})
+
+	// cacheObjects reports the number of objects of a kind cached for a workload cluster.
+	cacheObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: clusterCacheSubsystem,
+		Name:      "objects",
+		Help:      "Number of objects cached for the workload cluster, partitioned by cluster and kind.",
+	}, []string{"namespace", "cluster_name", "kind"})
+
+	// cacheObjectBytes reports the estimated memory used by the objects of a kind cached for a workload cluster,
+	// i.e. the size of their JSON serialization.
+	cacheObjectBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: clusterCacheSubsystem,
+		Name:      "object_bytes",
+		Help:      "Estimated size in bytes of the objects cached for the workload cluster, partitioned by cluster and kind.",
+	}, []string{"namespace", "cluster_name", "kind"})
+)
+
+func clusterLabels(cluster client.ObjectKey) prometheus.Labels {
+	return prometheus.Labels{
+		"namespace":    cluster.Namespace,
+		"cluster_name": cluster.Name,
+	}
+}
+
+func cacheLabels(cluster client.ObjectKey, kind string) prometheus.Labels {
+	return prometheus.Labels{
+		"namespace":    cluster.Namespace,
+		"cluster_name": cluster.Name,
+		"kind":         kind,
+	}
+}
+
+// recordKubeconfigCertificateExpiry reports the expiry of the client certificate of a rest config, if any.
+func recordKubeconfigCertificateExpiry(cluster client.ObjectKey, config *rest.Config) {
+	if len(config.CertData) == 0 {
+		kubeconfigCertificateExpiry.Delete(clusterLabels(cluster))
+		return
+	}
+	cert, err := certs.DecodeCertPEM(config.CertData)
+	if err != nil {
+		kubeconfigCertificateExpiry.Delete(clusterLabels(cluster))
+		return
+	}
+	kubeconfigCertificateExpiry.With(clusterLabels(cluster)).Set(float64(cert.NotAfter.Unix()))
+}
+
+// deleteClusterMetrics removes the metrics of a deleted cluster.
+func deleteClusterMetrics(cluster client.ObjectKey) {
+	connectionUp.Delete(clusterLabels(cluster))
+	healthCheckConsecutiveFailures.Delete(clusterLabels(cluster))
+	lastSuccessfulHealthCheck.Delete(clusterLabels(cluster))
+	kubeconfigCertificateExpiry.Delete(clusterLabels(cluster))
+	deleteCacheMetrics(cluster)
+}
+
+// deleteCacheMetrics removes the metrics of the cache of a cluster, e.g. when the cache is stopped.
+func deleteCacheMetrics(cluster client.ObjectKey) {
+	cacheInformers.Delete(clusterLabels(cluster))
+	cacheObjects.DeletePartialMatch(clusterLabels(cluster))
+	cacheObjectBytes.DeletePartialMatch(clusterLabels(cluster))
+}
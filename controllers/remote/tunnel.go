@@ -0,0 +1,42 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	restclient "k8s.io/client-go/rest"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// configureTunnel configures a REST configuration to reach the API server of a Cluster through the
+// tunnel server defined in the Cluster spec, if any, which is used as HTTP CONNECT proxy.
+func configureTunnel(config *restclient.Config, cluster *clusterv1.Cluster) error {
+	if cluster.Spec.Tunnel == nil {
+		return nil
+	}
+
+	proxyURL, err := url.Parse(cluster.Spec.Tunnel.ProxyURL)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the tunnel proxy URL of Cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	config.Proxy = http.ProxyURL(proxyURL)
+	return nil
+}
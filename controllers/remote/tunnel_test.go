@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	restclient "k8s.io/client-go/rest"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestConfigureTunnel(t *testing.T) {
+	t.Run("does not set a proxy without a tunnel", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := &restclient.Config{Host: "https://test-cluster-api.nodomain.example.com:6443"}
+		g.Expect(configureTunnel(config, &clusterv1.Cluster{})).To(Succeed())
+		g.Expect(config.Proxy).To(BeNil())
+	})
+
+	t.Run("uses the tunnel server as proxy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := &restclient.Config{Host: "https://test-cluster-api.nodomain.example.com:6443"}
+		cluster := &clusterv1.Cluster{
+			Spec: clusterv1.ClusterSpec{
+				Tunnel: &clusterv1.ClusterTunnel{ProxyURL: "http://konnectivity-server.tunnel-system.svc:8090"},
+			},
+		}
+		g.Expect(configureTunnel(config, cluster)).To(Succeed())
+		g.Expect(config.Proxy).ToNot(BeNil())
+
+		req, err := http.NewRequest(http.MethodGet, config.Host, http.NoBody)
+		g.Expect(err).ToNot(HaveOccurred())
+		proxyURL, err := config.Proxy(req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(proxyURL.String()).To(Equal("http://konnectivity-server.tunnel-system.svc:8090"))
+	})
+
+	t.Run("fails with an invalid proxy URL", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := &restclient.Config{}
+		cluster := &clusterv1.Cluster{
+			Spec: clusterv1.ClusterSpec{
+				Tunnel: &clusterv1.ClusterTunnel{ProxyURL: "http://%zz"},
+			},
+		}
+		g.Expect(configureTunnel(config, cluster)).ToNot(Succeed())
+	})
+}
@@ -51,6 +51,15 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.KubeadmConfigSpec.Ignition = restored.Spec.KubeadmConfigSpec.Ignition
+	dst.Spec.KubeadmConfigSpec.Output = restored.Spec.KubeadmConfigSpec.Output
+	if restored.Spec.KubeadmConfigSpec.ClusterConfiguration != nil && dst.Spec.KubeadmConfigSpec.ClusterConfiguration != nil {
+		dst.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer.ExtraEnvs = restored.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer.ExtraEnvs
+		dst.Spec.KubeadmConfigSpec.ClusterConfiguration.ControllerManager.ExtraEnvs = restored.Spec.KubeadmConfigSpec.ClusterConfiguration.ControllerManager.ExtraEnvs
+		dst.Spec.KubeadmConfigSpec.ClusterConfiguration.Scheduler.ExtraEnvs = restored.Spec.KubeadmConfigSpec.ClusterConfiguration.Scheduler.ExtraEnvs
+		if restored.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local != nil && dst.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local != nil {
+			dst.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local.ExtraEnvs = restored.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local.ExtraEnvs
+		}
+	}
 	if restored.Spec.KubeadmConfigSpec.InitConfiguration != nil {
 		if dst.Spec.KubeadmConfigSpec.InitConfiguration == nil {
 			dst.Spec.KubeadmConfigSpec.InitConfiguration = &bootstrapv1.InitConfiguration{}
@@ -64,6 +73,12 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 		}
 		dst.Spec.KubeadmConfigSpec.JoinConfiguration.Patches = restored.Spec.KubeadmConfigSpec.JoinConfiguration.Patches
 		dst.Spec.KubeadmConfigSpec.JoinConfiguration.SkipPhases = restored.Spec.KubeadmConfigSpec.JoinConfiguration.SkipPhases
+		if restored.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File != nil && restored.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File.KubeConfig != nil {
+			if dst.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File == nil {
+				dst.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File = &bootstrapv1.FileDiscovery{}
+			}
+			dst.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File.KubeConfig = restored.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File.KubeConfig
+		}
 	}
 
 	dst.Spec.MachineTemplate.NodeDeletionTimeout = restored.Spec.MachineTemplate.NodeDeletionTimeout
@@ -90,6 +105,9 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Status.LastRemediation != nil {
 		dst.Status.LastRemediation = restored.Status.LastRemediation
 	}
+	dst.Spec.EncryptionAtRest = restored.Spec.EncryptionAtRest
+	dst.Status.EncryptionAtRest = restored.Status.EncryptionAtRest
+	dst.Status.RolloutHistory = restored.Status.RolloutHistory
 
 	return nil
 }
@@ -133,6 +151,15 @@ func (src *KubeadmControlPlaneTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.Template.Spec.KubeadmConfigSpec.Files = restored.Spec.Template.Spec.KubeadmConfigSpec.Files
 	dst.Spec.Template.Spec.KubeadmConfigSpec.Users = restored.Spec.Template.Spec.KubeadmConfigSpec.Users
 	dst.Spec.Template.Spec.KubeadmConfigSpec.Ignition = restored.Spec.Template.Spec.KubeadmConfigSpec.Ignition
+	dst.Spec.Template.Spec.KubeadmConfigSpec.Output = restored.Spec.Template.Spec.KubeadmConfigSpec.Output
+	if restored.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration != nil && dst.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration != nil {
+		dst.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer.ExtraEnvs = restored.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer.ExtraEnvs
+		dst.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration.ControllerManager.ExtraEnvs = restored.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration.ControllerManager.ExtraEnvs
+		dst.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration.Scheduler.ExtraEnvs = restored.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration.Scheduler.ExtraEnvs
+		if restored.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local != nil && dst.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local != nil {
+			dst.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local.ExtraEnvs = restored.Spec.Template.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local.ExtraEnvs
+		}
+	}
 	dst.Spec.Template.Spec.MachineTemplate = restored.Spec.Template.Spec.MachineTemplate
 
 	if restored.Spec.Template.Spec.KubeadmConfigSpec.Users != nil {
@@ -161,6 +188,12 @@ func (src *KubeadmControlPlaneTemplate) ConvertTo(dstRaw conversion.Hub) error {
 		}
 		dst.Spec.Template.Spec.KubeadmConfigSpec.JoinConfiguration.Patches = restored.Spec.Template.Spec.KubeadmConfigSpec.JoinConfiguration.Patches
 		dst.Spec.Template.Spec.KubeadmConfigSpec.JoinConfiguration.SkipPhases = restored.Spec.Template.Spec.KubeadmConfigSpec.JoinConfiguration.SkipPhases
+		if restored.Spec.Template.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File != nil && restored.Spec.Template.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File.KubeConfig != nil {
+			if dst.Spec.Template.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File == nil {
+				dst.Spec.Template.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File = &bootstrapv1.FileDiscovery{}
+			}
+			dst.Spec.Template.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File.KubeConfig = restored.Spec.Template.Spec.KubeadmConfigSpec.JoinConfiguration.Discovery.File.KubeConfig
+		}
 	}
 	if dst.Spec.Template.Spec.MachineTemplate == nil {
 		dst.Spec.Template.Spec.MachineTemplate = restored.Spec.Template.Spec.MachineTemplate
@@ -188,6 +221,7 @@ func (src *KubeadmControlPlaneTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.Template.Spec.RemediationStrategy != nil {
 		dst.Spec.Template.Spec.RemediationStrategy = restored.Spec.Template.Spec.RemediationStrategy
 	}
+	dst.Spec.Template.Spec.EncryptionAtRest = restored.Spec.Template.Spec.EncryptionAtRest
 
 	return nil
 }
@@ -279,11 +313,14 @@ func Convert_v1beta1_KubeadmControlPlaneMachineTemplate_To_v1alpha4_KubeadmContr
 func Convert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in *controlplanev1.KubeadmControlPlaneSpec, out *KubeadmControlPlaneSpec, scope apiconversion.Scope) error {
 	// .RolloutBefore was added in v1beta1.
 	// .RemediationStrategy was added in v1beta1.
+	// .EncryptionAtRest was added in v1beta1.
 	return autoConvert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in, out, scope)
 }
 
 func Convert_v1beta1_KubeadmControlPlaneStatus_To_v1alpha4_KubeadmControlPlaneStatus(in *controlplanev1.KubeadmControlPlaneStatus, out *KubeadmControlPlaneStatus, scope apiconversion.Scope) error {
 	// .LastRemediation was added in v1beta1.
+	// .EncryptionAtRest was added in v1beta1.
+	// .RolloutHistory was added in v1beta1.
 	return autoConvert_v1beta1_KubeadmControlPlaneStatus_To_v1alpha4_KubeadmControlPlaneStatus(in, out, scope)
 }
 
@@ -56,6 +56,21 @@ const (
 	RollingUpdateInProgressReason = "RollingUpdateInProgress"
 )
 
+const (
+	// EncryptionKeysUpToDateCondition documents that the keys used for encrypting resources at rest are up to date,
+	// i.e. there is no key rotation in progress.
+	EncryptionKeysUpToDateCondition clusterv1.ConditionType = "EncryptionKeysUpToDate"
+
+	// EncryptionKeyRotationInProgressReason (Severity=Info) documents a KubeadmControlPlane object executing
+	// a rotation of the keys used for encrypting resources at rest.
+	EncryptionKeyRotationInProgressReason = "EncryptionKeyRotationInProgress"
+
+	// EncryptionConfigurationReconciliationFailedReason (Severity=Warning) documents a KubeadmControlPlane controller
+	// detecting an error while reconciling the EncryptionConfiguration or rotating the encryption keys; those kind
+	// of errors are usually temporary and the controller automatically recover from them.
+	EncryptionConfigurationReconciliationFailedReason = "EncryptionConfigurationReconciliationFailed"
+)
+
 const (
 	// ResizedCondition documents a KubeadmControlPlane that is resizing the set of controlled machines.
 	ResizedCondition clusterv1.ConditionType = "Resized"
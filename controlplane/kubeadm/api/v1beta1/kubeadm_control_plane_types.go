@@ -65,6 +65,16 @@ const (
 	// failures in updating remediation retry (the counter restarts from zero).
 	RemediationForAnnotation = "controlplane.cluster.x-k8s.io/remediation-for"
 
+	// EncryptionConfigurationHashAnnotation is a machine annotation that stores the hash of the EncryptionConfiguration
+	// delivered to the machine when it was created.
+	// This annotation is used to detect changes in the EncryptionConfiguration, e.g. during a key rotation, and trigger machine rollout in KCP.
+	EncryptionConfigurationHashAnnotation = "controlplane.cluster.x-k8s.io/encryption-configuration-hash"
+
+	// ImageDigestsHashAnnotation is a machine annotation that stores the hash of the image digests of the control plane
+	// components defined in Cluster.spec.imageRegistry when the machine was created.
+	// This annotation is used to detect changes in the image digests and trigger machine rollout in KCP.
+	ImageDigestsHashAnnotation = "controlplane.cluster.x-k8s.io/image-digests-hash"
+
 	// DefaultMinHealthyPeriod defines the default minimum period before we consider a remediation on a
 	// machine unrelated from the previous remediation.
 	DefaultMinHealthyPeriod = 1 * time.Hour
@@ -117,6 +127,13 @@ type KubeadmControlPlaneSpec struct {
 	// The RemediationStrategy that controls how control plane machine remediation happens.
 	// +optional
 	RemediationStrategy *RemediationStrategy `json:"remediationStrategy,omitempty"`
+
+	// EncryptionAtRest configures the encryption at rest of resources stored in etcd.
+	// If set, KCP generates the encryption keys, delivers the corresponding EncryptionConfiguration
+	// to the control plane machines and configures the API server to use it.
+	// NOTE: EncryptionAtRest cannot be unset once set, because resources already encrypted would become unreadable.
+	// +optional
+	EncryptionAtRest *EncryptionAtRest `json:"encryptionAtRest,omitempty"`
 }
 
 // KubeadmControlPlaneMachineTemplate defines the template for Machines
@@ -228,6 +245,81 @@ type RemediationStrategy struct {
 	MinHealthyPeriod *metav1.Duration `json:"minHealthyPeriod,omitempty"`
 }
 
+// EncryptionProvider defines the provider used to encrypt resources at rest.
+type EncryptionProvider string
+
+const (
+	// AESCBCEncryptionProvider encrypts resources using AES-CBC with PKCS#7 padding.
+	AESCBCEncryptionProvider EncryptionProvider = "aescbc"
+
+	// AESGCMEncryptionProvider encrypts resources using AES-GCM with a random nonce.
+	AESGCMEncryptionProvider EncryptionProvider = "aesgcm"
+
+	// SecretboxEncryptionProvider encrypts resources using XSalsa20 and Poly1305.
+	SecretboxEncryptionProvider EncryptionProvider = "secretbox"
+)
+
+// EncryptionAtRest defines how resources are encrypted at rest in etcd.
+type EncryptionAtRest struct {
+	// Provider is the provider used to encrypt resources.
+	// Changing the provider triggers a key rotation, using a new key for the new provider.
+	// Defaults to aescbc.
+	// +optional
+	// +kubebuilder:default=aescbc
+	// +kubebuilder:validation:Enum=aescbc;aesgcm;secretbox
+	Provider EncryptionProvider `json:"provider,omitempty"`
+
+	// Resources is the list of resources to encrypt.
+	// Supported values are secrets and configmaps.
+	// Defaults to secrets.
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+
+	// RotateKeysAfter is a field to indicate the encryption key should be rotated
+	// after the specified time, if the key in use has been created before it.
+	// The key rotation is a multi-step process: the new key is added to the EncryptionConfiguration,
+	// then it is used for encrypting resources, all the encrypted resources are rewritten with
+	// the new key, and finally the old key is removed; each change to the EncryptionConfiguration
+	// rolls out the control plane machines.
+	// Example: In the YAML the time can be specified in the RFC3339 format.
+	// To specify the rotateKeysAfter target as March 9, 2023, at 9 am UTC
+	// use "2023-03-09T09:00:00Z".
+	// +optional
+	RotateKeysAfter *metav1.Time `json:"rotateKeysAfter,omitempty"`
+}
+
+// EncryptionKeyRotationPhase defines the phase of an encryption key rotation.
+type EncryptionKeyRotationPhase string
+
+const (
+	// EncryptionKeyDistributingPhase is the phase where a new key has been added to the EncryptionConfiguration,
+	// but it is not used for encrypting resources until all the control plane machines can use it for decrypting them.
+	EncryptionKeyDistributingPhase EncryptionKeyRotationPhase = "DistributingKey"
+
+	// EncryptionKeyRewritingResourcesPhase is the phase where the new key is used for encrypting resources,
+	// and all the encrypted resources are rewritten with the new key once all the control plane machines use it.
+	EncryptionKeyRewritingResourcesPhase EncryptionKeyRotationPhase = "RewritingResources"
+
+	// EncryptionKeyRetiringPhase is the phase where all the encrypted resources have been rewritten with
+	// the new key, and the old keys are removed from the EncryptionConfiguration.
+	EncryptionKeyRetiringPhase EncryptionKeyRotationPhase = "RetiringKeys"
+)
+
+// EncryptionAtRestStatus defines the observed state of the encryption at rest of resources stored in etcd.
+type EncryptionAtRestStatus struct {
+	// ConfigurationHash is the hash of the EncryptionConfiguration that control plane machines are expected to use.
+	// +optional
+	ConfigurationHash string `json:"configurationHash,omitempty"`
+
+	// ActiveKey is the name of the key used for encrypting resources.
+	// +optional
+	ActiveKey string `json:"activeKey,omitempty"`
+
+	// KeyRotationPhase is the phase of the key rotation in progress, if any.
+	// +optional
+	KeyRotationPhase EncryptionKeyRotationPhase `json:"keyRotationPhase,omitempty"`
+}
+
 // KubeadmControlPlaneStatus defines the observed state of KubeadmControlPlane.
 type KubeadmControlPlaneStatus struct {
 	// Selector is the label selector in string format to avoid introspection
@@ -297,6 +389,16 @@ type KubeadmControlPlaneStatus struct {
 	// LastRemediation stores info about last remediation performed.
 	// +optional
 	LastRemediation *LastRemediationStatus `json:"lastRemediation,omitempty"`
+
+	// EncryptionAtRest reports the status of the encryption at rest of resources stored in etcd.
+	// +optional
+	EncryptionAtRest *EncryptionAtRestStatus `json:"encryptionAtRest,omitempty"`
+
+	// RolloutHistory records the last changes which triggered a rollout of the control plane Machines,
+	// most recent first.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	RolloutHistory []clusterv1.RolloutTrigger `json:"rolloutHistory,omitempty"`
 }
 
 // LastRemediationStatus  stores info about last remediation performed.
@@ -0,0 +1,270 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// encryptionResourcesRewrittenAnnotation is an annotation on the EncryptionConfiguration secret tracking the key and
+// the resources used the last time all the encrypted resources have been rewritten.
+const encryptionResourcesRewrittenAnnotation = "controlplane.cluster.x-k8s.io/encryption-resources-rewritten"
+
+// reconcileEncryptionConfiguration ensures the EncryptionConfiguration for the control plane machines exists
+// and it is up to date with the resources to be encrypted, and reports its status.
+// NOTE: The hash of the EncryptionConfiguration in the status is used when creating machines and when
+// determining if machines need to be rolled out, so this func must be called before those operations.
+func (r *KubeadmControlPlaneReconciler) reconcileEncryptionConfiguration(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	kcp := controlPlane.KCP
+	if kcp.Spec.EncryptionAtRest == nil {
+		kcp.Status.EncryptionAtRest = nil
+		conditions.Delete(kcp, controlplanev1.EncryptionKeysUpToDateCondition)
+		return nil
+	}
+
+	configSecret, encryptionConfig, err := r.lookupOrGenerateEncryptionConfiguration(ctx, controlPlane)
+	if err != nil {
+		conditions.MarkFalse(kcp, controlplanev1.EncryptionKeysUpToDateCondition, controlplanev1.EncryptionConfigurationReconciliationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+
+	// Changes to the resources to be encrypted do not require a key rotation, so they are applied immediately.
+	// NOTE: Resources already existing are encrypted when they are rewritten at the end of the rollout.
+	if resources := internal.EncryptionResources(kcp.Spec.EncryptionAtRest); !reflect.DeepEqual(encryptionConfig.Resources, resources) {
+		encryptionConfig.Resources = resources
+		if err := r.updateEncryptionConfiguration(ctx, configSecret, encryptionConfig); err != nil {
+			conditions.MarkFalse(kcp, controlplanev1.EncryptionKeysUpToDateCondition, controlplanev1.EncryptionConfigurationReconciliationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+	}
+
+	setEncryptionAtRestStatus(kcp, configSecret, encryptionConfig)
+	return nil
+}
+
+// reconcileEncryptionKeyRotation moves the key rotation in progress, if any, to the next step.
+// Every step changes the EncryptionConfiguration, thus triggering the rollout of the control plane machines,
+// and the next step happens only once all the machines use the current EncryptionConfiguration; as a
+// consequence the key rotation can be resumed at any time.
+// NOTE: This func must be called only when no machine needs rollout and the control plane is not scaling.
+func (r *KubeadmControlPlaneReconciler) reconcileEncryptionKeyRotation(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	kcp := controlPlane.KCP
+	if kcp.Spec.EncryptionAtRest == nil {
+		return nil
+	}
+
+	// Wait for all the machines to have a node, so it is guaranteed they are using the current EncryptionConfiguration.
+	// NOTE: The EncryptionConfiguration is read when generating the bootstrap data for a machine.
+	if controlPlane.HasDeletingMachine() || len(controlPlane.Machines.Filter(func(m *clusterv1.Machine) bool { return m.Status.NodeRef == nil })) > 0 {
+		log.V(4).Info("Waiting for control plane machines to be provisioned before moving forward the encryption key rotation")
+		return nil
+	}
+
+	configSecret, encryptionConfig, err := r.lookupOrGenerateEncryptionConfiguration(ctx, controlPlane)
+	if err != nil {
+		return err
+	}
+
+	switch encryptionKeyRotationPhase(configSecret, encryptionConfig) {
+	case controlplanev1.EncryptionKeyDistributingPhase:
+		// All the API servers can decrypt resources with the new key, so it can be used for encrypting resources.
+		newestKey := encryptionConfig.NewestKey()
+		keys := []internal.EncryptionKey{newestKey}
+		for _, key := range encryptionConfig.Keys {
+			if key.Name != newestKey.Name {
+				keys = append(keys, key)
+			}
+		}
+		encryptionConfig.Keys = keys
+		log.Info("Using the new key for encrypting resources", "key", newestKey.Name)
+	case controlplanev1.EncryptionKeyRewritingResourcesPhase:
+		// All the API servers encrypt resources with the new key, so all the resources can be rewritten with it.
+		workloadCluster, err := controlPlane.GetWorkloadCluster(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to rewrite encrypted resources")
+		}
+		log.Info("Rewriting encrypted resources", "key", encryptionConfig.Keys[0].Name, "resources", strings.Join(encryptionConfig.Resources, ","))
+		if err := workloadCluster.RewriteEncryptedResources(ctx, encryptionConfig.Resources); err != nil {
+			conditions.MarkFalse(kcp, controlplanev1.EncryptionKeysUpToDateCondition, controlplanev1.EncryptionConfigurationReconciliationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+		if configSecret.Annotations == nil {
+			configSecret.Annotations = map[string]string{}
+		}
+		configSecret.Annotations[encryptionResourcesRewrittenAnnotation] = encryptionResourcesRewrittenValue(encryptionConfig)
+	case controlplanev1.EncryptionKeyRetiringPhase:
+		// All the resources are encrypted with the new key, so the old keys can be removed.
+		log.Info("Removing old encryption keys", "key", encryptionConfig.Keys[0].Name)
+		encryptionConfig.Keys = encryptionConfig.Keys[:1]
+	default:
+		rotationRequested, err := encryptionKeyRotationRequested(kcp.Spec.EncryptionAtRest, encryptionConfig, time.Now())
+		if err != nil || !rotationRequested {
+			return err
+		}
+		newKey, err := internal.NewEncryptionKey(internal.EncryptionProvider(kcp.Spec.EncryptionAtRest), time.Now())
+		if err != nil {
+			return err
+		}
+		// The new key is added as last, so it is not used for encrypting resources before all the API servers can use it for decrypting them.
+		encryptionConfig.Keys = append(encryptionConfig.Keys, newKey)
+		log.Info("Starting encryption key rotation", "key", newKey.Name)
+	}
+
+	if err := r.updateEncryptionConfiguration(ctx, configSecret, encryptionConfig); err != nil {
+		conditions.MarkFalse(kcp, controlplanev1.EncryptionKeysUpToDateCondition, controlplanev1.EncryptionConfigurationReconciliationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+
+	setEncryptionAtRestStatus(kcp, configSecret, encryptionConfig)
+	return nil
+}
+
+// lookupOrGenerateEncryptionConfiguration returns the secret containing the EncryptionConfiguration for
+// the control plane machines, generating it if it does not exist yet.
+func (r *KubeadmControlPlaneReconciler) lookupOrGenerateEncryptionConfiguration(ctx context.Context, controlPlane *internal.ControlPlane) (*corev1.Secret, *internal.EncryptionConfiguration, error) {
+	kcp := controlPlane.KCP
+
+	configSecret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: kcp.Namespace, Name: internal.EncryptionConfigurationSecretName(kcp.Name)}
+	if err := r.Client.Get(ctx, key, configSecret); err == nil {
+		encryptionConfig, err := internal.ParseEncryptionConfiguration(configSecret.Data[internal.EncryptionConfigurationSecretKey])
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read EncryptionConfiguration from Secret %s", key)
+		}
+		return configSecret, encryptionConfig, nil
+	} else if !apierrors.IsNotFound(err) {
+		return nil, nil, errors.Wrapf(err, "failed to get Secret %s", key)
+	}
+
+	newKey, err := internal.NewEncryptionKey(internal.EncryptionProvider(kcp.Spec.EncryptionAtRest), time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	encryptionConfig := &internal.EncryptionConfiguration{
+		Resources: internal.EncryptionResources(kcp.Spec.EncryptionAtRest),
+		Keys:      []internal.EncryptionKey{newKey},
+	}
+	data, err := encryptionConfig.Marshal()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel: controlPlane.Cluster.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind(kubeadmControlPlaneKind)),
+			},
+		},
+		Type: clusterv1.ClusterSecretType,
+		Data: map[string][]byte{
+			internal.EncryptionConfigurationSecretKey: data,
+		},
+	}
+	if err := r.Client.Create(ctx, configSecret); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to create Secret %s", key)
+	}
+	return configSecret, encryptionConfig, nil
+}
+
+// updateEncryptionConfiguration updates the secret containing the EncryptionConfiguration for the control plane machines.
+func (r *KubeadmControlPlaneReconciler) updateEncryptionConfiguration(ctx context.Context, configSecret *corev1.Secret, encryptionConfig *internal.EncryptionConfiguration) error {
+	data, err := encryptionConfig.Marshal()
+	if err != nil {
+		return err
+	}
+	configSecret.Data[internal.EncryptionConfigurationSecretKey] = data
+	if err := r.Client.Update(ctx, configSecret); err != nil {
+		return errors.Wrapf(err, "failed to update Secret %s", client.ObjectKeyFromObject(configSecret))
+	}
+	return nil
+}
+
+// setEncryptionAtRestStatus reports the status of the EncryptionConfiguration for the control plane machines.
+func setEncryptionAtRestStatus(kcp *controlplanev1.KubeadmControlPlane, configSecret *corev1.Secret, encryptionConfig *internal.EncryptionConfiguration) {
+	phase := encryptionKeyRotationPhase(configSecret, encryptionConfig)
+	kcp.Status.EncryptionAtRest = &controlplanev1.EncryptionAtRestStatus{
+		ConfigurationHash: internal.EncryptionConfigurationHash(configSecret.Data[internal.EncryptionConfigurationSecretKey]),
+		ActiveKey:         encryptionConfig.Keys[0].Name,
+		KeyRotationPhase:  phase,
+	}
+
+	if phase != "" {
+		conditions.MarkFalse(kcp, controlplanev1.EncryptionKeysUpToDateCondition, controlplanev1.EncryptionKeyRotationInProgressReason, clusterv1.ConditionSeverityInfo, "Encryption key rotation in progress: %s", phase)
+		return
+	}
+	conditions.MarkTrue(kcp, controlplanev1.EncryptionKeysUpToDateCondition)
+}
+
+// encryptionKeyRotationPhase returns the phase of the key rotation in progress, if any.
+// NOTE: The phase is computed from the EncryptionConfiguration, so it is not lost e.g. if the KCP status is lost.
+func encryptionKeyRotationPhase(configSecret *corev1.Secret, encryptionConfig *internal.EncryptionConfiguration) controlplanev1.EncryptionKeyRotationPhase {
+	switch {
+	case encryptionConfig.NewestKey().Name != encryptionConfig.Keys[0].Name:
+		return controlplanev1.EncryptionKeyDistributingPhase
+	case configSecret.Annotations[encryptionResourcesRewrittenAnnotation] != encryptionResourcesRewrittenValue(encryptionConfig):
+		return controlplanev1.EncryptionKeyRewritingResourcesPhase
+	case len(encryptionConfig.Keys) > 1:
+		return controlplanev1.EncryptionKeyRetiringPhase
+	default:
+		return ""
+	}
+}
+
+// encryptionKeyRotationRequested returns true if a new key is required, either because rotateKeysAfter
+// expired after the newest key has been created or because the encryption provider changed.
+func encryptionKeyRotationRequested(encryptionAtRest *controlplanev1.EncryptionAtRest, encryptionConfig *internal.EncryptionConfiguration, now time.Time) (bool, error) {
+	newestKey := encryptionConfig.NewestKey()
+	if newestKey.Provider != internal.EncryptionProvider(encryptionAtRest) {
+		return true, nil
+	}
+
+	if encryptionAtRest.RotateKeysAfter == nil || encryptionAtRest.RotateKeysAfter.Time.After(now) {
+		return false, nil
+	}
+	creationTime, err := newestKey.CreationTime()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to determine the creation time of the encryption key %q", newestKey.Name)
+	}
+	return creationTime.Before(encryptionAtRest.RotateKeysAfter.Time), nil
+}
+
+func encryptionResourcesRewrittenValue(encryptionConfig *internal.EncryptionConfiguration) string {
+	return fmt.Sprintf("%s/%s", encryptionConfig.Keys[0].Name, strings.Join(encryptionConfig.Resources, ","))
+}
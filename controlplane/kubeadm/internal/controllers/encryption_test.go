@@ -0,0 +1,368 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+var (
+	oldEncryptionKey = internal.EncryptionKey{Name: "key-20230101T000000Z", Provider: controlplanev1.AESCBCEncryptionProvider, Secret: "b2xk"}
+	newEncryptionKey = internal.EncryptionKey{Name: "key-20230601T000000Z", Provider: controlplanev1.AESCBCEncryptionProvider, Secret: "bmV3"}
+)
+
+func TestEncryptionKeyRotationPhase(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      []internal.EncryptionKey
+		rewritten string
+		want      controlplanev1.EncryptionKeyRotationPhase
+	}{
+		{
+			name:      "no rotation in progress",
+			keys:      []internal.EncryptionKey{newEncryptionKey},
+			rewritten: newEncryptionKey.Name + "/secrets",
+			want:      "",
+		},
+		{
+			name:      "new key not used for encrypting resources yet",
+			keys:      []internal.EncryptionKey{oldEncryptionKey, newEncryptionKey},
+			rewritten: oldEncryptionKey.Name + "/secrets",
+			want:      controlplanev1.EncryptionKeyDistributingPhase,
+		},
+		{
+			name:      "resources not rewritten with the new key yet",
+			keys:      []internal.EncryptionKey{newEncryptionKey, oldEncryptionKey},
+			rewritten: oldEncryptionKey.Name + "/secrets",
+			want:      controlplanev1.EncryptionKeyRewritingResourcesPhase,
+		},
+		{
+			name:      "resources never rewritten",
+			keys:      []internal.EncryptionKey{newEncryptionKey},
+			rewritten: "",
+			want:      controlplanev1.EncryptionKeyRewritingResourcesPhase,
+		},
+		{
+			name:      "resources to be encrypted changed",
+			keys:      []internal.EncryptionKey{newEncryptionKey},
+			rewritten: newEncryptionKey.Name + "/configmaps",
+			want:      controlplanev1.EncryptionKeyRewritingResourcesPhase,
+		},
+		{
+			name:      "old keys not removed yet",
+			keys:      []internal.EncryptionKey{newEncryptionKey, oldEncryptionKey},
+			rewritten: newEncryptionKey.Name + "/secrets",
+			want:      controlplanev1.EncryptionKeyRetiringPhase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			configSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{encryptionResourcesRewrittenAnnotation: tt.rewritten},
+				},
+			}
+			encryptionConfig := &internal.EncryptionConfiguration{
+				Resources: []string{"secrets"},
+				Keys:      tt.keys,
+			}
+			g.Expect(encryptionKeyRotationPhase(configSecret, encryptionConfig)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestEncryptionKeyRotationRequested(t *testing.T) {
+	now := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		encryptionAtRest *controlplanev1.EncryptionAtRest
+		newestKey        internal.EncryptionKey
+		want             bool
+		wantErr          bool
+	}{
+		{
+			name:             "not requested if rotateKeysAfter is not set",
+			encryptionAtRest: &controlplanev1.EncryptionAtRest{},
+			newestKey:        newEncryptionKey,
+			want:             false,
+		},
+		{
+			name:             "not requested if rotateKeysAfter is in the future",
+			encryptionAtRest: &controlplanev1.EncryptionAtRest{RotateKeysAfter: &metav1.Time{Time: now.Add(time.Hour)}},
+			newestKey:        oldEncryptionKey,
+			want:             false,
+		},
+		{
+			name:             "not requested if the newest key has been created after rotateKeysAfter",
+			encryptionAtRest: &controlplanev1.EncryptionAtRest{RotateKeysAfter: &metav1.Time{Time: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)}},
+			newestKey:        newEncryptionKey,
+			want:             false,
+		},
+		{
+			name:             "requested if the newest key has been created before rotateKeysAfter",
+			encryptionAtRest: &controlplanev1.EncryptionAtRest{RotateKeysAfter: &metav1.Time{Time: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)}},
+			newestKey:        oldEncryptionKey,
+			want:             true,
+		},
+		{
+			name:             "requested if the provider changed",
+			encryptionAtRest: &controlplanev1.EncryptionAtRest{Provider: controlplanev1.SecretboxEncryptionProvider},
+			newestKey:        newEncryptionKey,
+			want:             true,
+		},
+		{
+			name:             "fails if the creation time of the newest key is unknown",
+			encryptionAtRest: &controlplanev1.EncryptionAtRest{RotateKeysAfter: &metav1.Time{Time: now}},
+			newestKey:        internal.EncryptionKey{Name: "invalid", Provider: controlplanev1.AESCBCEncryptionProvider},
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			encryptionConfig := &internal.EncryptionConfiguration{
+				Resources: []string{"secrets"},
+				Keys:      []internal.EncryptionKey{tt.newestKey},
+			}
+			got, err := encryptionKeyRotationRequested(tt.encryptionAtRest, encryptionConfig, now)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestReconcileEncryptionKeyRotation(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+
+	provisionedMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "provisioned"},
+		Status:     clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Kind: "Node", Name: "provisioned"}},
+	}
+	provisioningMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "provisioning"},
+	}
+	deletingMachine := provisionedMachine.DeepCopy()
+	deletingMachine.Name = "deleting"
+	deletingMachine.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	tests := []struct {
+		name                         string
+		encryptionAtRest             *controlplanev1.EncryptionAtRest
+		machines                     []*clusterv1.Machine
+		keys                         []internal.EncryptionKey
+		rewritten                    string
+		rewriteEncryptedResourcesErr error
+		wantErr                      bool
+		wantKeys                     []string
+		wantNewKey                   bool
+		wantRewritten                string
+		wantPhase                    controlplanev1.EncryptionKeyRotationPhase
+	}{
+		{
+			name:          "no-op if no rotation is requested",
+			machines:      []*clusterv1.Machine{provisionedMachine},
+			keys:          []internal.EncryptionKey{newEncryptionKey},
+			rewritten:     newEncryptionKey.Name + "/secrets",
+			wantKeys:      []string{newEncryptionKey.Name},
+			wantRewritten: newEncryptionKey.Name + "/secrets",
+			wantPhase:     "",
+		},
+		{
+			name:             "starts the rotation adding the new key as last",
+			encryptionAtRest: &controlplanev1.EncryptionAtRest{RotateKeysAfter: &metav1.Time{Time: time.Now()}},
+			machines:         []*clusterv1.Machine{provisionedMachine},
+			keys:             []internal.EncryptionKey{oldEncryptionKey},
+			rewritten:        oldEncryptionKey.Name + "/secrets",
+			wantKeys:         []string{oldEncryptionKey.Name},
+			wantNewKey:       true,
+			wantRewritten:    oldEncryptionKey.Name + "/secrets",
+			wantPhase:        controlplanev1.EncryptionKeyDistributingPhase,
+		},
+		{
+			name:             "waits for the machines to be provisioned",
+			encryptionAtRest: &controlplanev1.EncryptionAtRest{RotateKeysAfter: &metav1.Time{Time: time.Now()}},
+			machines:         []*clusterv1.Machine{provisionedMachine, provisioningMachine},
+			keys:             []internal.EncryptionKey{oldEncryptionKey},
+			rewritten:        oldEncryptionKey.Name + "/secrets",
+			wantKeys:         []string{oldEncryptionKey.Name},
+			wantRewritten:    oldEncryptionKey.Name + "/secrets",
+		},
+		{
+			name:          "waits for the machines to be deleted",
+			machines:      []*clusterv1.Machine{provisionedMachine, deletingMachine},
+			keys:          []internal.EncryptionKey{oldEncryptionKey, newEncryptionKey},
+			rewritten:     oldEncryptionKey.Name + "/secrets",
+			wantKeys:      []string{oldEncryptionKey.Name, newEncryptionKey.Name},
+			wantRewritten: oldEncryptionKey.Name + "/secrets",
+		},
+		{
+			name:          "uses the new key for encrypting resources once distributed",
+			machines:      []*clusterv1.Machine{provisionedMachine},
+			keys:          []internal.EncryptionKey{oldEncryptionKey, newEncryptionKey},
+			rewritten:     oldEncryptionKey.Name + "/secrets",
+			wantKeys:      []string{newEncryptionKey.Name, oldEncryptionKey.Name},
+			wantRewritten: oldEncryptionKey.Name + "/secrets",
+			wantPhase:     controlplanev1.EncryptionKeyRewritingResourcesPhase,
+		},
+		{
+			name:          "rewrites the encrypted resources with the new key",
+			machines:      []*clusterv1.Machine{provisionedMachine},
+			keys:          []internal.EncryptionKey{newEncryptionKey, oldEncryptionKey},
+			rewritten:     oldEncryptionKey.Name + "/secrets",
+			wantKeys:      []string{newEncryptionKey.Name, oldEncryptionKey.Name},
+			wantRewritten: newEncryptionKey.Name + "/secrets",
+			wantPhase:     controlplanev1.EncryptionKeyRetiringPhase,
+		},
+		{
+			name:                         "fails if the encrypted resources cannot be rewritten",
+			machines:                     []*clusterv1.Machine{provisionedMachine},
+			keys:                         []internal.EncryptionKey{newEncryptionKey, oldEncryptionKey},
+			rewritten:                    oldEncryptionKey.Name + "/secrets",
+			rewriteEncryptedResourcesErr: errors.New("failed to rewrite secrets"),
+			wantErr:                      true,
+			wantKeys:                     []string{newEncryptionKey.Name, oldEncryptionKey.Name},
+			wantRewritten:                oldEncryptionKey.Name + "/secrets",
+		},
+		{
+			name:          "removes the old keys once all the resources are rewritten",
+			machines:      []*clusterv1.Machine{provisionedMachine},
+			keys:          []internal.EncryptionKey{newEncryptionKey, oldEncryptionKey},
+			rewritten:     newEncryptionKey.Name + "/secrets",
+			wantKeys:      []string{newEncryptionKey.Name},
+			wantRewritten: newEncryptionKey.Name + "/secrets",
+			wantPhase:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			encryptionAtRest := tt.encryptionAtRest
+			if encryptionAtRest == nil {
+				encryptionAtRest = &controlplanev1.EncryptionAtRest{}
+			}
+			kcp := &controlplanev1.KubeadmControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: "foo"},
+				Spec:       controlplanev1.KubeadmControlPlaneSpec{EncryptionAtRest: encryptionAtRest},
+			}
+
+			encryptionConfig := &internal.EncryptionConfiguration{
+				Resources: []string{"secrets"},
+				Keys:      tt.keys,
+			}
+			data, err := encryptionConfig.Marshal()
+			g.Expect(err).ToNot(HaveOccurred())
+			configSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   kcp.Namespace,
+					Name:        internal.EncryptionConfigurationSecretName(kcp.Name),
+					Annotations: map[string]string{encryptionResourcesRewrittenAnnotation: tt.rewritten},
+				},
+				Data: map[string][]byte{internal.EncryptionConfigurationSecretKey: data},
+			}
+
+			fakeClient := newFakeClient(configSecret)
+			r := &KubeadmControlPlaneReconciler{
+				Client: fakeClient,
+				managementCluster: &fakeManagementCluster{
+					Workload: fakeWorkloadCluster{RewriteEncryptedResourcesErr: tt.rewriteEncryptedResourcesErr},
+				},
+				recorder: record.NewFakeRecorder(32),
+			}
+			controlPlane := &internal.ControlPlane{
+				KCP:      kcp,
+				Cluster:  cluster,
+				Machines: collections.FromMachines(tt.machines...),
+			}
+			controlPlane.InjectTestManagementCluster(r.managementCluster)
+
+			err = r.reconcileEncryptionKeyRotation(ctx, controlPlane)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(conditions.IsFalse(kcp, controlplanev1.EncryptionKeysUpToDateCondition)).To(BeTrue())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(configSecret), configSecret)).To(Succeed())
+			g.Expect(configSecret.Annotations[encryptionResourcesRewrittenAnnotation]).To(Equal(tt.wantRewritten))
+			gotConfig, err := internal.ParseEncryptionConfiguration(configSecret.Data[internal.EncryptionConfigurationSecretKey])
+			g.Expect(err).ToNot(HaveOccurred())
+			gotKeys := []string{}
+			for _, key := range gotConfig.Keys {
+				gotKeys = append(gotKeys, key.Name)
+			}
+			if tt.wantNewKey {
+				g.Expect(gotKeys).To(HaveLen(len(tt.wantKeys) + 1))
+				g.Expect(gotKeys[:len(tt.wantKeys)]).To(Equal(tt.wantKeys))
+				g.Expect(gotConfig.NewestKey().Name).To(Equal(gotKeys[len(gotKeys)-1]))
+			} else {
+				g.Expect(gotKeys).To(Equal(tt.wantKeys))
+			}
+
+			// The status is updated only if the EncryptionConfiguration has been updated.
+			if kcp.Status.EncryptionAtRest != nil {
+				g.Expect(kcp.Status.EncryptionAtRest.KeyRotationPhase).To(Equal(tt.wantPhase))
+				g.Expect(kcp.Status.EncryptionAtRest.ConfigurationHash).To(Equal(internal.EncryptionConfigurationHash(configSecret.Data[internal.EncryptionConfigurationSecretKey])))
+			}
+		})
+	}
+}
+
+func TestReconcileEncryptionKeyRotationDisabled(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &KubeadmControlPlaneReconciler{Client: newFakeClient()}
+	controlPlane := &internal.ControlPlane{
+		KCP: &controlplanev1.KubeadmControlPlane{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "foo"}},
+	}
+
+	g.Expect(r.reconcileEncryptionKeyRotation(ctx, controlPlane)).To(Succeed())
+	g.Expect(controlPlane.KCP.Status.EncryptionAtRest).To(BeNil())
+
+	// The EncryptionConfiguration is not generated.
+	secrets := &corev1.SecretList{}
+	g.Expect(r.Client.List(ctx, secrets)).To(Succeed())
+	g.Expect(secrets.Items).To(BeEmpty())
+}
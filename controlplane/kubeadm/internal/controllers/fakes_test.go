@@ -68,9 +68,10 @@ func (f *fakeManagementCluster) GetMachinePoolsForCluster(c context.Context, clu
 
 type fakeWorkloadCluster struct {
 	*internal.Workload
-	Status                     internal.ClusterStatus
-	EtcdMembersResult          []string
-	APIServerCertificateExpiry *time.Time
+	Status                       internal.ClusterStatus
+	EtcdMembersResult            []string
+	APIServerCertificateExpiry   *time.Time
+	RewriteEncryptedResourcesErr error
 }
 
 func (f fakeWorkloadCluster) ForwardEtcdLeadership(_ context.Context, _ *clusterv1.Machine, leaderCandidate *clusterv1.Machine) error {
@@ -128,6 +129,10 @@ func (f fakeWorkloadCluster) RemoveMachineFromKubeadmConfigMap(_ context.Context
 	return nil
 }
 
+func (f fakeWorkloadCluster) RewriteEncryptedResources(_ context.Context, _ []string) error {
+	return f.RewriteEncryptedResourcesErr
+}
+
 func (f fakeWorkloadCluster) EtcdMembers(_ context.Context) ([]string, error) {
 	return f.EtcdMembersResult, nil
 }
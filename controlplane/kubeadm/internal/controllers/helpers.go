@@ -355,6 +355,20 @@ func (r *KubeadmControlPlaneReconciler) computeDesiredMachine(kcp *controlplanev
 		}
 		annotations[controlplanev1.KubeadmClusterConfigurationAnnotation] = string(clusterConfig)
 
+		// We store the hash of the EncryptionConfiguration generated by KCP as annotation here to detect changes, e.g.
+		// during a key rotation, and rollout the machine if any.
+		// NOTE: The EncryptionConfiguration is read when generating the bootstrap data for the machine, but KCP
+		// changes it only when all the machines are provisioned, so it is guaranteed that the machine uses it.
+		if kcp.Spec.EncryptionAtRest != nil && kcp.Status.EncryptionAtRest != nil && kcp.Status.EncryptionAtRest.ConfigurationHash != "" {
+			annotations[controlplanev1.EncryptionConfigurationHashAnnotation] = kcp.Status.EncryptionAtRest.ConfigurationHash
+		}
+
+		// We store the hash of the image digests of the control plane components as annotation here to detect changes
+		// to Cluster.spec.imageRegistry and rollout the machine if any.
+		if imageDigestsHash := internal.ImageDigestsHash(kcp, cluster); imageDigestsHash != "" {
+			annotations[controlplanev1.ImageDigestsHashAnnotation] = imageDigestsHash
+		}
+
 		// In case this machine is being created as a consequence of a remediation, then add an annotation
 		// tracking remediating data.
 		// NOTE: This is required in order to track remediation retries.
@@ -374,6 +388,16 @@ func (r *KubeadmControlPlaneReconciler) computeDesiredMachine(kcp *controlplanev
 			annotations[controlplanev1.KubeadmClusterConfigurationAnnotation] = clusterConfig
 		}
 
+		// If the machine already has the EncryptionConfiguration hash then preserve it.
+		if encryptionConfigHash, ok := existingMachine.Annotations[controlplanev1.EncryptionConfigurationHashAnnotation]; ok {
+			annotations[controlplanev1.EncryptionConfigurationHashAnnotation] = encryptionConfigHash
+		}
+
+		// If the machine already has the image digests hash then preserve it.
+		if imageDigestsHash, ok := existingMachine.Annotations[controlplanev1.ImageDigestsHashAnnotation]; ok {
+			annotations[controlplanev1.ImageDigestsHashAnnotation] = imageDigestsHash
+		}
+
 		// If the machine already has remediation data then preserve it.
 		// NOTE: This is required in order to track remediation retries.
 		if remediationData, ok := existingMachine.Annotations[controlplanev1.RemediationForAnnotation]; ok {
@@ -25,6 +25,7 @@ import (
 	"github.com/blang/semver/v4"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
@@ -33,6 +34,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
@@ -142,14 +144,14 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 		// The cluster MUST have more than one replica, because this is the smallest cluster size that allows any etcd failure tolerance.
 		if controlPlane.Machines.Len() <= 1 {
 			log.Info("A control plane machine needs remediation, but the number of current replicas is less or equal to 1. Skipping remediation", "Replicas", controlPlane.Machines.Len())
-			conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate if current replicas are less or equal to 1")
+			r.markRemediationBlocked(controlPlane, machineToBeRemediated, "KCP can't remediate if current replicas are less or equal to 1")
 			return ctrl.Result{}, nil
 		}
 
 		// The cluster MUST have no machines with a deletion timestamp. This rule prevents KCP taking actions while the cluster is in a transitional state.
 		if controlPlane.HasDeletingMachine() {
 			log.Info("A control plane machine needs remediation, but there are other control-plane machines being deleted. Skipping remediation")
-			conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP waiting for control plane machine deletion to complete before triggering remediation")
+			r.markRemediationBlocked(controlPlane, machineToBeRemediated, "KCP waiting for control plane machine deletion to complete before triggering remediation")
 			return ctrl.Result{}, nil
 		}
 
@@ -163,7 +165,7 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 			}
 			if !canSafelyRemediate {
 				log.Info("A control plane machine needs remediation, but removing this machine could result in etcd quorum loss. Skipping remediation")
-				conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because this could result in etcd loosing quorum")
+				r.markRemediationBlocked(controlPlane, machineToBeRemediated, "KCP can't remediate this machine because this could result in etcd loosing quorum")
 				return ctrl.Result{}, nil
 			}
 		}
@@ -221,6 +223,7 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 
 	// Surface the operation is in progress.
 	log.Info("Remediating unhealthy machine")
+	r.recorder.AnnotatedEventf(controlPlane.KCP, events.Annotations(machineToBeRemediated.Spec.ClusterName, "Machine", machineToBeRemediated.Name), corev1.EventTypeNormal, clusterv1.EventRemediationStarted, "Deleted unhealthy Machine %s", machineToBeRemediated.Name)
 	conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationInProgressReason, clusterv1.ConditionSeverityWarning, "")
 
 	// Prepare the info for tracking the remediation progress into the RemediationInProgressAnnotation.
@@ -307,7 +310,7 @@ func (r *KubeadmControlPlaneReconciler) checkRetryLimits(log logr.Logger, machin
 	// Check if remediation can happen because retryPeriod is passed.
 	if lastRemediationTime.Add(retryPeriod).After(reconciliationTime) {
 		log.Info(fmt.Sprintf("A control plane machine needs remediation, but the operation already failed in the latest %s. Skipping remediation", retryPeriod))
-		conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because the operation already failed in the latest %s (RetryPeriod)", retryPeriod)
+		r.markRemediationBlocked(controlPlane, machineToBeRemediated, "KCP can't remediate this machine because the operation already failed in the latest %s (RetryPeriod)", retryPeriod)
 		return remediationInProgressData, false, nil
 	}
 
@@ -316,7 +319,7 @@ func (r *KubeadmControlPlaneReconciler) checkRetryLimits(log logr.Logger, machin
 		maxRetry := int(*controlPlane.KCP.Spec.RemediationStrategy.MaxRetry)
 		if remediationInProgressData.RetryCount >= maxRetry {
 			log.Info(fmt.Sprintf("A control plane machine needs remediation, but the operation already failed %d times (MaxRetry %d). Skipping remediation", remediationInProgressData.RetryCount, maxRetry))
-			conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because the operation already failed %d times (MaxRetry)", maxRetry)
+			r.markRemediationBlocked(controlPlane, machineToBeRemediated, "KCP can't remediate this machine because the operation already failed %d times (MaxRetry)", maxRetry)
 			return remediationInProgressData, false, nil
 		}
 	}
@@ -327,6 +330,16 @@ func (r *KubeadmControlPlaneReconciler) checkRetryLimits(log logr.Logger, machin
 	return remediationInProgressData, true, nil
 }
 
+// markRemediationBlocked surfaces in the MachineOwnerRemediated condition of a Machine why KCP can't remediate it,
+// recording an event on KCP when the reason is new, so the event is not repeated at every reconcile.
+func (r *KubeadmControlPlaneReconciler) markRemediationBlocked(controlPlane *internal.ControlPlane, machineToBeRemediated *clusterv1.Machine, messageFormat string, messageArgs ...interface{}) {
+	message := fmt.Sprintf(messageFormat, messageArgs...)
+	if c := conditions.Get(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition); c == nil || c.Reason != clusterv1.WaitingForRemediationReason || c.Message != message {
+		r.recorder.AnnotatedEventf(controlPlane.KCP, events.Annotations(machineToBeRemediated.Spec.ClusterName, "Machine", machineToBeRemediated.Name), corev1.EventTypeWarning, clusterv1.EventRemediationBlocked, "Can't remediate Machine %s: %s", machineToBeRemediated.Name, message)
+	}
+	conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "%s", message)
+}
+
 // max calculates the maximum duration.
 func max(x, y time.Duration) time.Duration {
 	if x < y {
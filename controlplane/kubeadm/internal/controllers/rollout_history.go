@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/util/hash"
+	"sigs.k8s.io/cluster-api/internal/util/rollout"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// recordRolloutTrigger records the change triggering the rollout of the given machines in KCP.Status.RolloutHistory,
+// when the rollout starts and whenever the fields triggering a rollout change while rolling out.
+func recordRolloutTrigger(controlPlane *internal.ControlPlane, machinesNeedingRollout collections.Machines, now time.Time) error {
+	kcp := controlPlane.KCP
+
+	newHash, err := computeRolloutHash(kcp)
+	if err != nil {
+		return err
+	}
+
+	oldHash := ""
+	if len(kcp.Status.RolloutHistory) > 0 {
+		oldHash = kcp.Status.RolloutHistory[0].NewHash
+	}
+	rollingOut := conditions.GetReason(kcp, controlplanev1.MachinesSpecUpToDateCondition) == controlplanev1.RollingUpdateInProgressReason
+	if rollingOut && oldHash == newHash {
+		return nil
+	}
+
+	trigger := clusterv1.RolloutTrigger{
+		Time:       metav1.NewTime(now),
+		Kind:       "KubeadmControlPlane",
+		Name:       kcp.Name,
+		FieldPaths: controlPlane.RolloutFieldPaths(machinesNeedingRollout),
+		OldHash:    oldHash,
+		NewHash:    newHash,
+	}
+	trigger.Manager = rollout.Manager(kcp, trigger.FieldPaths)
+	if rollout.HasFieldPath(trigger.FieldPaths, "spec.machineTemplate.infrastructureRef") {
+		trigger.Kind = kcp.Spec.MachineTemplate.InfrastructureRef.Kind
+		trigger.Name = kcp.Spec.MachineTemplate.InfrastructureRef.Name
+	}
+
+	kcp.Status.RolloutHistory = rollout.RecordTrigger(kcp.Status.RolloutHistory, trigger)
+	return nil
+}
+
+// computeRolloutHash computes the hash of the KCP fields which trigger a rollout when changed.
+func computeRolloutHash(kcp *controlplanev1.KubeadmControlPlane) (string, error) {
+	encryptionConfigurationHash := ""
+	if kcp.Status.EncryptionAtRest != nil {
+		encryptionConfigurationHash = kcp.Status.EncryptionAtRest.ConfigurationHash
+	}
+
+	h, err := hash.Compute(struct {
+		Version                     string
+		InfrastructureRef           corev1.ObjectReference
+		KubeadmConfigSpec           bootstrapv1.KubeadmConfigSpec
+		RolloutAfter                *metav1.Time
+		EncryptionConfigurationHash string
+	}{
+		Version:                     kcp.Spec.Version,
+		InfrastructureRef:           kcp.Spec.MachineTemplate.InfrastructureRef,
+		KubeadmConfigSpec:           kcp.Spec.KubeadmConfigSpec,
+		RolloutAfter:                kcp.Spec.RolloutAfter,
+		EncryptionConfigurationHash: encryptionConfigurationHash,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to compute the rollout hash")
+	}
+	return fmt.Sprintf("%d", h), nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestRecordRolloutTrigger(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Date(2023, 10, 1, 3, 0, 0, 0, time.UTC)
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "kcp",
+			ManagedFields: []metav1.ManagedFieldsEntry{{
+				Manager:    "capi-topology",
+				Operation:  metav1.ManagedFieldsOperationApply,
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:version":{}}}`)},
+			}},
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Version: "v1.29.0",
+			MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+				InfrastructureRef: corev1.ObjectReference{Kind: "GenericInfrastructureMachineTemplate", Name: "infra-template"},
+			},
+		},
+	}
+	machines := collections.FromMachines(&clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine"},
+		Spec:       clusterv1.MachineSpec{Version: pointer.String("v1.28.0")},
+	})
+	controlPlane := &internal.ControlPlane{KCP: kcp, Machines: machines}
+
+	// The change triggering the rollout is recorded when the rollout starts.
+	g.Expect(recordRolloutTrigger(controlPlane, machines, now)).To(Succeed())
+	g.Expect(kcp.Status.RolloutHistory).To(HaveLen(1))
+	trigger := kcp.Status.RolloutHistory[0]
+	g.Expect(trigger.Time.Time).To(Equal(now))
+	g.Expect(trigger.Kind).To(Equal("KubeadmControlPlane"))
+	g.Expect(trigger.Name).To(Equal("kcp"))
+	g.Expect(trigger.FieldPaths).To(Equal([]string{"spec.version"}))
+	g.Expect(trigger.Manager).To(Equal("capi-topology"))
+	g.Expect(trigger.OldHash).To(BeEmpty())
+	g.Expect(trigger.NewHash).ToNot(BeEmpty())
+
+	// No change is recorded while the rollout is in progress without changes.
+	conditions.MarkFalse(kcp, controlplanev1.MachinesSpecUpToDateCondition, controlplanev1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityWarning, "")
+	g.Expect(recordRolloutTrigger(controlPlane, machines, now.Add(time.Minute))).To(Succeed())
+	g.Expect(kcp.Status.RolloutHistory).To(HaveLen(1))
+
+	// Changes while the rollout is in progress are recorded.
+	kcp.Spec.Version = "v1.29.1"
+	g.Expect(recordRolloutTrigger(controlPlane, machines, now.Add(2*time.Minute))).To(Succeed())
+	g.Expect(kcp.Status.RolloutHistory).To(HaveLen(2))
+	g.Expect(kcp.Status.RolloutHistory[0].OldHash).To(Equal(trigger.NewHash))
+	g.Expect(kcp.Status.RolloutHistory[0].NewHash).ToNot(Equal(trigger.NewHash))
+	g.Expect(kcp.Status.RolloutHistory[1]).To(Equal(trigger))
+}
@@ -31,6 +31,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 )
@@ -42,7 +43,7 @@ func (r *KubeadmControlPlaneReconciler) initializeControlPlane(ctx context.Conte
 	fd := controlPlane.NextFailureDomainForScaleUp()
 	if err := r.cloneConfigsAndGenerateMachine(ctx, controlPlane.Cluster, controlPlane.KCP, bootstrapSpec, fd); err != nil {
 		logger.Error(err, "Failed to create initial control plane Machine")
-		r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeWarning, "FailedInitialization", "Failed to create initial control plane Machine for cluster %s control plane: %v", klog.KObj(controlPlane.Cluster), err)
+		r.recorder.AnnotatedEventf(controlPlane.KCP, events.Annotations(controlPlane.Cluster.Name, "", ""), corev1.EventTypeWarning, clusterv1.EventFailedCreate, "Failed to create initial control plane Machine for cluster %s control plane: %v", klog.KObj(controlPlane.Cluster), err)
 		return ctrl.Result{}, err
 	}
 
@@ -63,7 +64,7 @@ func (r *KubeadmControlPlaneReconciler) scaleUpControlPlane(ctx context.Context,
 	fd := controlPlane.NextFailureDomainForScaleUp()
 	if err := r.cloneConfigsAndGenerateMachine(ctx, controlPlane.Cluster, controlPlane.KCP, bootstrapSpec, fd); err != nil {
 		logger.Error(err, "Failed to create additional control plane Machine")
-		r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeWarning, "FailedScaleUp", "Failed to create additional control plane Machine for cluster % control plane: %v", klog.KObj(controlPlane.Cluster), err)
+		r.recorder.AnnotatedEventf(controlPlane.KCP, events.Annotations(controlPlane.Cluster.Name, "", ""), corev1.EventTypeWarning, clusterv1.EventFailedCreate, "Failed to create additional control plane Machine for cluster %s control plane: %v", klog.KObj(controlPlane.Cluster), err)
 		return ctrl.Result{}, err
 	}
 
@@ -127,7 +128,7 @@ func (r *KubeadmControlPlaneReconciler) scaleDownControlPlane(
 	logger = logger.WithValues("Machine", klog.KObj(machineToDelete))
 	if err := r.Client.Delete(ctx, machineToDelete); err != nil && !apierrors.IsNotFound(err) {
 		logger.Error(err, "Failed to delete control plane machine")
-		r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeWarning, "FailedScaleDown",
+		r.recorder.AnnotatedEventf(controlPlane.KCP, events.Annotations(controlPlane.Cluster.Name, "Machine", machineToDelete.Name), corev1.EventTypeWarning, clusterv1.EventFailedDelete,
 			"Failed to delete control plane Machine %s for cluster %s control plane: %v", machineToDelete.Name, klog.KObj(controlPlane.Cluster), err)
 		return ctrl.Result{}, err
 	}
@@ -195,11 +196,18 @@ loopmachines:
 					machineErrors = append(machineErrors, err)
 				}
 			}
+			// If the readiness in the API server load balancer is reported for the machine, wait for the machine
+			// to be registered and healthy in the load balancer, so the rollout does not outrun the load balancer.
+			if conditions.Has(machine, clusterv1.APIServerLoadBalancerReadyCondition) {
+				if err := preflightCheckCondition("Machine", machine, clusterv1.APIServerLoadBalancerReadyCondition); err != nil {
+					machineErrors = append(machineErrors, err)
+				}
+			}
 		}
 	}
 	if len(machineErrors) > 0 {
 		aggregatedError := kerrors.NewAggregate(machineErrors)
-		r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeWarning, "ControlPlaneUnhealthy",
+		r.recorder.AnnotatedEventf(controlPlane.KCP, events.Annotations(controlPlane.Cluster.Name, "", ""), corev1.EventTypeWarning, clusterv1.EventControlPlaneUnhealthy,
 			"Waiting for control plane to pass preflight checks to continue reconciliation: %v", aggregatedError)
 		logger.Info("Waiting for control plane to pass preflight checks", "failures", aggregatedError.Error())
 
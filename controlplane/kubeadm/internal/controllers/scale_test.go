@@ -564,6 +564,66 @@ func TestPreflightChecks(t *testing.T) {
 			},
 			expectResult: ctrl.Result{},
 		},
+		{
+			name: "control plane with a machine not ready in the API server load balancer should requeue",
+			kcp: &controlplanev1.KubeadmControlPlane{
+				Status: controlplanev1.KubeadmControlPlaneStatus{
+					Conditions: clusterv1.Conditions{
+						*conditions.TrueCondition(controlplanev1.ControlPlaneComponentsHealthyCondition),
+						*conditions.TrueCondition(controlplanev1.EtcdClusterHealthyCondition),
+					},
+				},
+			},
+			machines: []*clusterv1.Machine{
+				{
+					Status: clusterv1.MachineStatus{
+						NodeRef: &corev1.ObjectReference{
+							Kind: "Node",
+							Name: "node-1",
+						},
+						Conditions: clusterv1.Conditions{
+							*conditions.TrueCondition(controlplanev1.MachineAPIServerPodHealthyCondition),
+							*conditions.TrueCondition(controlplanev1.MachineControllerManagerPodHealthyCondition),
+							*conditions.TrueCondition(controlplanev1.MachineSchedulerPodHealthyCondition),
+							*conditions.TrueCondition(controlplanev1.MachineEtcdPodHealthyCondition),
+							*conditions.TrueCondition(controlplanev1.MachineEtcdMemberHealthyCondition),
+							*conditions.FalseCondition(clusterv1.APIServerLoadBalancerReadyCondition, clusterv1.WaitingForLoadBalancerRegistrationReason, clusterv1.ConditionSeverityInfo, ""),
+						},
+					},
+				},
+			},
+			expectResult: ctrl.Result{RequeueAfter: preflightFailedRequeueAfter},
+		},
+		{
+			name: "control plane with a machine ready in the API server load balancer should pass",
+			kcp: &controlplanev1.KubeadmControlPlane{
+				Status: controlplanev1.KubeadmControlPlaneStatus{
+					Conditions: clusterv1.Conditions{
+						*conditions.TrueCondition(controlplanev1.ControlPlaneComponentsHealthyCondition),
+						*conditions.TrueCondition(controlplanev1.EtcdClusterHealthyCondition),
+					},
+				},
+			},
+			machines: []*clusterv1.Machine{
+				{
+					Status: clusterv1.MachineStatus{
+						NodeRef: &corev1.ObjectReference{
+							Kind: "Node",
+							Name: "node-1",
+						},
+						Conditions: clusterv1.Conditions{
+							*conditions.TrueCondition(controlplanev1.MachineAPIServerPodHealthyCondition),
+							*conditions.TrueCondition(controlplanev1.MachineControllerManagerPodHealthyCondition),
+							*conditions.TrueCondition(controlplanev1.MachineSchedulerPodHealthyCondition),
+							*conditions.TrueCondition(controlplanev1.MachineEtcdPodHealthyCondition),
+							*conditions.TrueCondition(controlplanev1.MachineEtcdMemberHealthyCondition),
+							*conditions.TrueCondition(clusterv1.APIServerLoadBalancerReadyCondition),
+						},
+					},
+				},
+			},
+			expectResult: ctrl.Result{},
+		},
 	}
 
 	for _, tt := range testCases {
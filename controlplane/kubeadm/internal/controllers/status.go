@@ -72,7 +72,7 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, contro
 		// make sure last resize operation is marked as completed.
 		// NOTE: we are checking the number of machines ready so we report resize completed only when the machines
 		// are actually provisioned (vs reporting completed immediately after the last machine object is created).
-		readyMachines := controlPlane.Machines.Filter(collections.IsReady())
+		readyMachines := controlPlane.Machines.Filter(collections.IsReady(), collections.Not(apiServerLoadBalancerNotReady))
 		if int32(len(readyMachines)) == replicas {
 			conditions.MarkTrue(controlPlane.KCP, controlplanev1.ResizedCondition)
 		}
@@ -89,8 +89,14 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, contro
 	if err != nil {
 		return err
 	}
-	controlPlane.KCP.Status.ReadyReplicas = status.ReadyNodes
-	controlPlane.KCP.Status.UnavailableReplicas = replicas - status.ReadyNodes
+	// Machines with a ready Node which are not yet registered and healthy in the API server load balancer are not
+	// counted as ready, consistently with the preflight checks gating scale up and scale down.
+	readyReplicas := status.ReadyNodes - int32(len(controlPlane.Machines.Filter(apiServerLoadBalancerNotReady, nodeHealthy)))
+	if readyReplicas < 0 {
+		readyReplicas = 0
+	}
+	controlPlane.KCP.Status.ReadyReplicas = readyReplicas
+	controlPlane.KCP.Status.UnavailableReplicas = replicas - readyReplicas
 
 	// This only gets initialized once and does not change if the kubeadm config map goes away.
 	if status.HasKubeadmConfig {
@@ -132,3 +138,14 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, contro
 	}
 	return nil
 }
+
+// apiServerLoadBalancerNotReady returns true if the Machine reports it is not registered and healthy in the
+// API server load balancer; Machines without the APIServerLoadBalancerReady condition are not considered.
+func apiServerLoadBalancerNotReady(machine *clusterv1.Machine) bool {
+	return conditions.IsFalse(machine, clusterv1.APIServerLoadBalancerReadyCondition)
+}
+
+// nodeHealthy returns true if the Node of the Machine is healthy.
+func nodeHealthy(machine *clusterv1.Machine) bool {
+	return conditions.IsTrue(machine, clusterv1.MachineNodeHealthyCondition)
+}
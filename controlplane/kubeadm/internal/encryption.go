@@ -0,0 +1,263 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiserverv1 "k8s.io/apiserver/pkg/apis/config/v1"
+	"sigs.k8s.io/yaml"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+const (
+	// EncryptionConfigurationSecretKey is the key of the secret data containing the EncryptionConfiguration.
+	EncryptionConfigurationSecretKey = "encryption-config.yaml"
+
+	// encryptionConfigurationDir is the directory containing the EncryptionConfiguration on control plane machines.
+	encryptionConfigurationDir = "/etc/kubernetes/encryption"
+
+	// encryptionConfigurationVolume is the name of the API server volume mounting encryptionConfigurationDir.
+	encryptionConfigurationVolume = "encryption-config"
+
+	// encryptionKeyPrefix is the prefix of the encryption key names; the rest of the name is the key creation time.
+	encryptionKeyPrefix = "key-"
+
+	// encryptionKeyTimeFormat is the format of the key creation time in the encryption key names.
+	encryptionKeyTimeFormat = "20060102T150405Z"
+
+	// encryptionKeySize is the size of the encryption keys; 32 bytes keys are supported by all the providers.
+	encryptionKeySize = 32
+)
+
+// EncryptionConfigurationSecretName returns the name of the secret containing the EncryptionConfiguration
+// generated for a KubeadmControlPlane.
+func EncryptionConfigurationSecretName(kcpName string) string {
+	return fmt.Sprintf("%s-encryption-config", kcpName)
+}
+
+// EncryptionResources returns the resources to be encrypted according to the given EncryptionAtRest.
+func EncryptionResources(encryptionAtRest *controlplanev1.EncryptionAtRest) []string {
+	if len(encryptionAtRest.Resources) == 0 {
+		return []string{"secrets"}
+	}
+	return encryptionAtRest.Resources
+}
+
+// EncryptionProvider returns the provider to be used for new keys according to the given EncryptionAtRest.
+func EncryptionProvider(encryptionAtRest *controlplanev1.EncryptionAtRest) controlplanev1.EncryptionProvider {
+	if encryptionAtRest.Provider == "" {
+		return controlplanev1.AESCBCEncryptionProvider
+	}
+	return encryptionAtRest.Provider
+}
+
+// EncryptionKey is a key used for encrypting resources at rest.
+type EncryptionKey struct {
+	// Name is the name of the key.
+	Name string
+
+	// Provider is the provider using the key.
+	Provider controlplanev1.EncryptionProvider
+
+	// Secret is the base64 encoded key.
+	Secret string
+}
+
+// NewEncryptionKey generates a new key for the given provider.
+func NewEncryptionKey(provider controlplanev1.EncryptionProvider, now time.Time) (EncryptionKey, error) {
+	secret := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(secret); err != nil {
+		return EncryptionKey{}, errors.Wrap(err, "failed to generate encryption key")
+	}
+	return EncryptionKey{
+		Name:     encryptionKeyPrefix + now.UTC().Format(encryptionKeyTimeFormat),
+		Provider: provider,
+		Secret:   base64.StdEncoding.EncodeToString(secret),
+	}, nil
+}
+
+// CreationTime returns the time the key has been created, as encoded in the key name.
+func (k EncryptionKey) CreationTime() (time.Time, error) {
+	return time.Parse(encryptionKeyTimeFormat, strings.TrimPrefix(k.Name, encryptionKeyPrefix))
+}
+
+// EncryptionConfiguration is the EncryptionConfiguration generated by KCP.
+type EncryptionConfiguration struct {
+	// Resources are the resources to be encrypted.
+	Resources []string
+
+	// Keys are the keys that can be used for decrypting resources.
+	// The first key is used for encrypting resources.
+	Keys []EncryptionKey
+}
+
+// NewestKey returns the most recently created key.
+func (c *EncryptionConfiguration) NewestKey() EncryptionKey {
+	newest := c.Keys[0]
+	for _, key := range c.Keys[1:] {
+		// Key names are sortable by creation time.
+		if key.Name > newest.Name {
+			newest = key
+		}
+	}
+	return newest
+}
+
+// ParseEncryptionConfiguration parses an EncryptionConfiguration generated by KCP.
+func ParseEncryptionConfiguration(data []byte) (*EncryptionConfiguration, error) {
+	encryptionConfig := &apiserverv1.EncryptionConfiguration{}
+	if err := yaml.UnmarshalStrict(data, encryptionConfig); err != nil {
+		return nil, errors.Wrap(err, "failed to parse EncryptionConfiguration")
+	}
+	if len(encryptionConfig.Resources) != 1 {
+		return nil, errors.Errorf("failed to parse EncryptionConfiguration: expected 1 resource configuration, got %d", len(encryptionConfig.Resources))
+	}
+
+	c := &EncryptionConfiguration{
+		Resources: encryptionConfig.Resources[0].Resources,
+	}
+	for _, provider := range encryptionConfig.Resources[0].Providers {
+		switch {
+		case provider.AESCBC != nil:
+			c.Keys = append(c.Keys, encryptionKeys(controlplanev1.AESCBCEncryptionProvider, provider.AESCBC.Keys)...)
+		case provider.AESGCM != nil:
+			c.Keys = append(c.Keys, encryptionKeys(controlplanev1.AESGCMEncryptionProvider, provider.AESGCM.Keys)...)
+		case provider.Secretbox != nil:
+			c.Keys = append(c.Keys, encryptionKeys(controlplanev1.SecretboxEncryptionProvider, provider.Secretbox.Keys)...)
+		case provider.Identity != nil:
+		default:
+			return nil, errors.New("failed to parse EncryptionConfiguration: unsupported provider")
+		}
+	}
+	if len(c.Keys) == 0 {
+		return nil, errors.New("failed to parse EncryptionConfiguration: no keys found")
+	}
+	return c, nil
+}
+
+func encryptionKeys(provider controlplanev1.EncryptionProvider, keys []apiserverv1.Key) []EncryptionKey {
+	ret := make([]EncryptionKey, 0, len(keys))
+	for _, key := range keys {
+		ret = append(ret, EncryptionKey{Name: key.Name, Provider: provider, Secret: key.Secret})
+	}
+	return ret
+}
+
+// Marshal returns the EncryptionConfiguration file to be used by the API server.
+// NOTE: The identity provider is always added as last provider, so resources stored before
+// enabling the encryption at rest can still be read.
+func (c *EncryptionConfiguration) Marshal() ([]byte, error) {
+	providers := make([]apiserverv1.ProviderConfiguration, 0, len(c.Keys)+1)
+	for _, key := range c.Keys {
+		keys := []apiserverv1.Key{{Name: key.Name, Secret: key.Secret}}
+		switch key.Provider {
+		case controlplanev1.AESCBCEncryptionProvider:
+			providers = append(providers, apiserverv1.ProviderConfiguration{AESCBC: &apiserverv1.AESConfiguration{Keys: keys}})
+		case controlplanev1.AESGCMEncryptionProvider:
+			providers = append(providers, apiserverv1.ProviderConfiguration{AESGCM: &apiserverv1.AESConfiguration{Keys: keys}})
+		case controlplanev1.SecretboxEncryptionProvider:
+			providers = append(providers, apiserverv1.ProviderConfiguration{Secretbox: &apiserverv1.SecretboxConfiguration{Keys: keys}})
+		default:
+			return nil, errors.Errorf("failed to generate EncryptionConfiguration: unsupported provider %q", key.Provider)
+		}
+	}
+	providers = append(providers, apiserverv1.ProviderConfiguration{Identity: &apiserverv1.IdentityConfiguration{}})
+
+	encryptionConfig := &apiserverv1.EncryptionConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiserverv1.SchemeGroupVersion.String(),
+			Kind:       "EncryptionConfiguration",
+		},
+		Resources: []apiserverv1.ResourceConfiguration{
+			{
+				Resources: c.Resources,
+				Providers: providers,
+			},
+		},
+	}
+	data, err := yaml.Marshal(encryptionConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate EncryptionConfiguration")
+	}
+	return data, nil
+}
+
+// EncryptionConfigurationHash returns the hash of an EncryptionConfiguration file.
+func EncryptionConfigurationHash(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
+
+// setEncryptionConfiguration adds to the KubeadmConfigSpec the EncryptionConfiguration file generated by KCP,
+// and configures the API server to use it.
+// NOTE: The file content is read from the secret when generating the bootstrap data, so each machine
+// uses the EncryptionConfiguration existing at the time the machine has been created.
+func setEncryptionConfiguration(kcp *controlplanev1.KubeadmControlPlane, spec *bootstrapv1.KubeadmConfigSpec) {
+	if kcp.Spec.EncryptionAtRest == nil {
+		return
+	}
+
+	path := encryptionConfigurationDir + "/" + EncryptionConfigurationSecretKey
+	spec.Files = append(spec.Files, bootstrapv1.File{
+		Path:        path,
+		Owner:       "root:root",
+		Permissions: "0600",
+		ContentFrom: &bootstrapv1.FileSource{
+			Secret: bootstrapv1.SecretFileSource{
+				Name: EncryptionConfigurationSecretName(kcp.Name),
+				Key:  EncryptionConfigurationSecretKey,
+			},
+		},
+	})
+
+	if spec.ClusterConfiguration == nil {
+		spec.ClusterConfiguration = &bootstrapv1.ClusterConfiguration{}
+	}
+	spec.ClusterConfiguration.APIServer = EncryptionAPIServer(kcp, spec.ClusterConfiguration.APIServer)
+}
+
+// EncryptionAPIServer returns the API server configuration extended with the settings required for
+// using the EncryptionConfiguration generated by KCP, if encryption at rest is enabled.
+func EncryptionAPIServer(kcp *controlplanev1.KubeadmControlPlane, apiServer bootstrapv1.APIServer) bootstrapv1.APIServer {
+	if kcp.Spec.EncryptionAtRest == nil {
+		return apiServer
+	}
+
+	apiServer = *apiServer.DeepCopy()
+	if apiServer.ExtraArgs == nil {
+		apiServer.ExtraArgs = map[string]string{}
+	}
+	apiServer.ExtraArgs["encryption-provider-config"] = encryptionConfigurationDir + "/" + EncryptionConfigurationSecretKey
+	apiServer.ExtraVolumes = append(apiServer.ExtraVolumes, bootstrapv1.HostPathMount{
+		Name:      encryptionConfigurationVolume,
+		HostPath:  encryptionConfigurationDir,
+		MountPath: encryptionConfigurationDir,
+		ReadOnly:  true,
+		PathType:  corev1.HostPathDirectoryOrCreate,
+	})
+	return apiServer
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+func TestEncryptionConfiguration(t *testing.T) {
+	now := time.Date(2023, 5, 4, 10, 11, 12, 0, time.UTC)
+
+	t.Run("generates keys named after their creation time", func(t *testing.T) {
+		g := NewWithT(t)
+
+		key, err := NewEncryptionKey(controlplanev1.AESCBCEncryptionProvider, now)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(key.Name).To(Equal("key-20230504T101112Z"))
+		g.Expect(key.Secret).ToNot(BeEmpty())
+
+		creationTime, err := key.CreationTime()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(creationTime).To(Equal(now))
+	})
+
+	t.Run("round trips through the EncryptionConfiguration file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		oldKey, err := NewEncryptionKey(controlplanev1.AESCBCEncryptionProvider, now)
+		g.Expect(err).ToNot(HaveOccurred())
+		newKey, err := NewEncryptionKey(controlplanev1.SecretboxEncryptionProvider, now.Add(time.Hour))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		c := &EncryptionConfiguration{
+			Resources: []string{"secrets", "configmaps"},
+			Keys:      []EncryptionKey{oldKey, newKey},
+		}
+		data, err := c.Marshal()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(data)).To(ContainSubstring("identity: {}"))
+
+		parsed, err := ParseEncryptionConfiguration(data)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(parsed).To(Equal(c))
+		g.Expect(parsed.NewestKey()).To(Equal(newKey))
+
+		again, err := parsed.Marshal()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(EncryptionConfigurationHash(again)).To(Equal(EncryptionConfigurationHash(data)))
+	})
+
+	t.Run("fails to parse a configuration without keys", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data, err := (&EncryptionConfiguration{Resources: []string{"secrets"}}).Marshal()
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = ParseEncryptionConfiguration(data)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestSetEncryptionConfiguration(t *testing.T) {
+	t.Run("does nothing if encryption at rest is not enabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		kcp := &controlplanev1.KubeadmControlPlane{ObjectMeta: metav1.ObjectMeta{Name: "kcp"}}
+		spec := &bootstrapv1.KubeadmConfigSpec{}
+		setEncryptionConfiguration(kcp, spec)
+		g.Expect(spec).To(Equal(&bootstrapv1.KubeadmConfigSpec{}))
+	})
+
+	t.Run("adds the EncryptionConfiguration file and the API server settings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		kcp := &controlplanev1.KubeadmControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Name: "kcp"},
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				EncryptionAtRest: &controlplanev1.EncryptionAtRest{},
+			},
+		}
+		spec := &bootstrapv1.KubeadmConfigSpec{
+			ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+				APIServer: bootstrapv1.APIServer{
+					ControlPlaneComponent: bootstrapv1.ControlPlaneComponent{
+						ExtraArgs: map[string]string{"foo": "bar"},
+					},
+				},
+			},
+		}
+		original := spec.DeepCopy()
+		setEncryptionConfiguration(kcp, spec)
+
+		g.Expect(spec.Files).To(HaveLen(1))
+		g.Expect(spec.Files[0].Path).To(Equal("/etc/kubernetes/encryption/encryption-config.yaml"))
+		g.Expect(spec.Files[0].ContentFrom.Secret.Name).To(Equal("kcp-encryption-config"))
+		g.Expect(spec.ClusterConfiguration.APIServer.ExtraArgs).To(HaveKeyWithValue("foo", "bar"))
+		g.Expect(spec.ClusterConfiguration.APIServer.ExtraArgs).To(HaveKeyWithValue("encryption-provider-config", "/etc/kubernetes/encryption/encryption-config.yaml"))
+		g.Expect(spec.ClusterConfiguration.APIServer.ExtraVolumes).To(HaveLen(1))
+
+		// The API server settings of the original spec are not changed.
+		g.Expect(original.ClusterConfiguration.APIServer.ExtraArgs).To(HaveLen(1))
+	})
+}
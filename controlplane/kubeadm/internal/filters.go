@@ -29,6 +29,7 @@ import (
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 // matchesMachineSpec checks if a Machine matches any of a set of KubeadmConfigs and a set of infra machine configs.
@@ -66,7 +67,7 @@ func matchesMachineSpec(infraConfigs map[string]*unstructured.Unstructured, mach
 }
 
 // NeedsRollout checks if a Machine needs to be rolled out and returns the reason why.
-func NeedsRollout(reconciliationTime, rolloutAfter *metav1.Time, rolloutBefore *controlplanev1.RolloutBefore, infraConfigs map[string]*unstructured.Unstructured, machineConfigs map[string]*bootstrapv1.KubeadmConfig, kcp *controlplanev1.KubeadmControlPlane, machine *clusterv1.Machine) (string, bool) {
+func NeedsRollout(reconciliationTime, rolloutAfter *metav1.Time, rolloutBefore *controlplanev1.RolloutBefore, infraConfigs map[string]*unstructured.Unstructured, machineConfigs map[string]*bootstrapv1.KubeadmConfig, kcp *controlplanev1.KubeadmControlPlane, cluster *clusterv1.Cluster, machine *clusterv1.Machine) (string, bool) {
 	rolloutReasons := []string{}
 
 	// Machines whose certificates are about to expire.
@@ -80,6 +81,16 @@ func NeedsRollout(reconciliationTime, rolloutAfter *metav1.Time, rolloutBefore *
 		rolloutReasons = append(rolloutReasons, "rolloutAfter expired")
 	}
 
+	// Machines that do not use the EncryptionConfiguration generated by KCP, e.g. because keys have been rotated.
+	if reason, matches := matchesEncryptionConfiguration(kcp, machine); !matches {
+		rolloutReasons = append(rolloutReasons, reason)
+	}
+
+	// Machines that do not use the image digests defined in the Cluster.
+	if reason, matches := matchesImageDigests(kcp, cluster, machine); !matches {
+		rolloutReasons = append(rolloutReasons, reason)
+	}
+
 	// Machines that do not match with KCP config.
 	if mismatchReason, matches := matchesMachineSpec(infraConfigs, machineConfigs, kcp, machine); !matches {
 		rolloutReasons = append(rolloutReasons, mismatchReason)
@@ -92,6 +103,67 @@ func NeedsRollout(reconciliationTime, rolloutAfter *metav1.Time, rolloutBefore *
 	return "", false
 }
 
+// RolloutFieldPaths returns the paths of the KCP fields which trigger the rollout of a Machine, e.g. spec.version;
+// it performs the same checks of NeedsRollout.
+func RolloutFieldPaths(reconciliationTime, rolloutAfter *metav1.Time, rolloutBefore *controlplanev1.RolloutBefore, infraConfigs map[string]*unstructured.Unstructured, machineConfigs map[string]*bootstrapv1.KubeadmConfig, kcp *controlplanev1.KubeadmControlPlane, cluster *clusterv1.Cluster, machine *clusterv1.Machine) []string {
+	fieldPaths := []string{}
+	if collections.ShouldRolloutBefore(reconciliationTime, rolloutBefore)(machine) {
+		fieldPaths = append(fieldPaths, "spec.rolloutBefore")
+	}
+	if collections.ShouldRolloutAfter(reconciliationTime, rolloutAfter)(machine) {
+		fieldPaths = append(fieldPaths, "spec.rolloutAfter")
+	}
+	if _, matches := matchesEncryptionConfiguration(kcp, machine); !matches {
+		fieldPaths = append(fieldPaths, "spec.encryptionAtRest")
+	}
+	if _, matches := matchesImageDigests(kcp, cluster, machine); !matches {
+		// NOTE: The image digests are defined in the Cluster, not in the KCP.
+		fieldPaths = append(fieldPaths, "cluster.spec.imageRegistry")
+	}
+	if !collections.MatchesKubernetesVersion(kcp.Spec.Version)(machine) {
+		fieldPaths = append(fieldPaths, "spec.version")
+	}
+	if _, matches := matchesKubeadmBootstrapConfig(machineConfigs, kcp, machine); !matches {
+		fieldPaths = append(fieldPaths, "spec.kubeadmConfigSpec")
+	}
+	if _, matches := matchesTemplateClonedFrom(infraConfigs, kcp, machine); !matches {
+		fieldPaths = append(fieldPaths, "spec.machineTemplate.infrastructureRef")
+	}
+	return fieldPaths
+}
+
+// matchesEncryptionConfiguration checks if a Machine uses the EncryptionConfiguration generated by KCP
+// and if it doesn't returns the reason why.
+// NOTE: Machines created when encryption at rest was disabled do not have the EncryptionConfigurationHashAnnotation,
+// so they are rolled out when encryption at rest is enabled.
+func matchesEncryptionConfiguration(kcp *controlplanev1.KubeadmControlPlane, machine *clusterv1.Machine) (string, bool) {
+	if kcp.Spec.EncryptionAtRest == nil || kcp.Status.EncryptionAtRest == nil || kcp.Status.EncryptionAtRest.ConfigurationHash == "" {
+		// The EncryptionConfiguration generated by KCP is not known yet; don't trigger a rollout.
+		return "", true
+	}
+	if machine == nil {
+		return "Machine EncryptionConfiguration cannot be compared: Machine is nil", false
+	}
+
+	if machine.GetAnnotations()[controlplanev1.EncryptionConfigurationHashAnnotation] != kcp.Status.EncryptionAtRest.ConfigurationHash {
+		return "Machine EncryptionConfiguration is outdated", false
+	}
+	return "", true
+}
+
+// matchesImageDigests checks if a Machine uses the image digests of the control plane components defined in the
+// Cluster and if it doesn't returns the reason why.
+func matchesImageDigests(kcp *controlplanev1.KubeadmControlPlane, cluster *clusterv1.Cluster, machine *clusterv1.Machine) (string, bool) {
+	if machine == nil {
+		return "Machine image digests cannot be compared: Machine is nil", false
+	}
+
+	if machine.GetAnnotations()[controlplanev1.ImageDigestsHashAnnotation] != ImageDigestsHash(kcp, cluster) {
+		return "Machine image digests are outdated", false
+	}
+	return "", true
+}
+
 // matchesTemplateClonedFrom checks if a Machine has a corresponding infrastructure machine that
 // matches a given KCP infra template and if it doesn't match returns the reason why.
 // Note: Differences to the labels and annotations on the infrastructure machine are not considered for matching
@@ -161,6 +233,13 @@ func matchesKubeadmBootstrapConfig(machineConfigs map[string]*bootstrapv1.Kubead
 		return "Machine InitConfiguration or JoinConfiguration are outdated", false
 	}
 
+	// Check if the content of the ConfigMaps and Secrets referenced by files changed after the machine's
+	// bootstrap data has been generated.
+	if conditions.IsFalse(machineConfig, bootstrapv1.FileSourcesUpToDateCondition) &&
+		conditions.GetReason(machineConfig, bootstrapv1.FileSourcesUpToDateCondition) == bootstrapv1.FileSourcesChangedReason {
+		return "Machine files content is outdated", false
+	}
+
 	return "", true
 }
 
@@ -242,6 +321,9 @@ func getAdjustedKcpConfig(kcp *controlplanev1.KubeadmControlPlane, machineConfig
 		kcpConfig.InitConfiguration = nil
 	}
 
+	// Machines get the EncryptionConfiguration generated by KCP, if encryption at rest is enabled.
+	setEncryptionConfiguration(kcp, kcpConfig)
+
 	return kcpConfig
 }
 
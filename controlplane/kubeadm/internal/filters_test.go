@@ -27,6 +27,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestMatchClusterConfiguration(t *testing.T) {
@@ -106,6 +107,125 @@ func TestMatchClusterConfiguration(t *testing.T) {
 	})
 }
 
+func TestMatchesEncryptionConfiguration(t *testing.T) {
+	kcp := &controlplanev1.KubeadmControlPlane{
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			EncryptionAtRest: &controlplanev1.EncryptionAtRest{},
+		},
+		Status: controlplanev1.KubeadmControlPlaneStatus{
+			EncryptionAtRest: &controlplanev1.EncryptionAtRestStatus{
+				ConfigurationHash: "sha256:new",
+			},
+		},
+	}
+	machineWithHash := func(hash string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					controlplanev1.EncryptionConfigurationHashAnnotation: hash,
+				},
+			},
+		}
+	}
+
+	t.Run("machine should match if encryption at rest is not enabled", func(t *testing.T) {
+		g := NewWithT(t)
+		_, matches := matchesEncryptionConfiguration(&controlplanev1.KubeadmControlPlane{}, &clusterv1.Machine{})
+		g.Expect(matches).To(BeTrue())
+	})
+	t.Run("machine should match if the EncryptionConfiguration is not generated yet", func(t *testing.T) {
+		g := NewWithT(t)
+		kcp := kcp.DeepCopy()
+		kcp.Status.EncryptionAtRest = nil
+		_, matches := matchesEncryptionConfiguration(kcp, &clusterv1.Machine{})
+		g.Expect(matches).To(BeTrue())
+	})
+	t.Run("machine should match if the EncryptionConfiguration hash is equal", func(t *testing.T) {
+		g := NewWithT(t)
+		_, matches := matchesEncryptionConfiguration(kcp, machineWithHash("sha256:new"))
+		g.Expect(matches).To(BeTrue())
+	})
+	t.Run("machine should not match if the EncryptionConfiguration hash is different", func(t *testing.T) {
+		g := NewWithT(t)
+		reason, matches := matchesEncryptionConfiguration(kcp, machineWithHash("sha256:old"))
+		g.Expect(matches).To(BeFalse())
+		g.Expect(reason).To(Equal("Machine EncryptionConfiguration is outdated"))
+	})
+	t.Run("machine should not match if it does not have the EncryptionConfiguration hash", func(t *testing.T) {
+		g := NewWithT(t)
+		_, matches := matchesEncryptionConfiguration(kcp, &clusterv1.Machine{})
+		g.Expect(matches).To(BeFalse())
+	})
+}
+
+func TestMatchesImageDigests(t *testing.T) {
+	kcp := &controlplanev1.KubeadmControlPlane{}
+	cluster := &clusterv1.Cluster{
+		Spec: clusterv1.ClusterSpec{
+			ImageRegistry: &clusterv1.ImageRegistry{
+				Repository: "registry.example.com",
+				Digests: &clusterv1.ImageDigests{
+					KubeAPIServer: "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+				},
+			},
+		},
+	}
+	machineWithHash := func(hash string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					controlplanev1.ImageDigestsHashAnnotation: hash,
+				},
+			},
+		}
+	}
+
+	t.Run("machine should match if no image digest is defined", func(t *testing.T) {
+		g := NewWithT(t)
+		_, matches := matchesImageDigests(kcp, &clusterv1.Cluster{}, &clusterv1.Machine{})
+		g.Expect(matches).To(BeTrue())
+	})
+	t.Run("machine should match if only the kube-proxy and CoreDNS image digests are defined", func(t *testing.T) {
+		g := NewWithT(t)
+		cluster := cluster.DeepCopy()
+		cluster.Spec.ImageRegistry.Digests = &clusterv1.ImageDigests{
+			KubeProxy: "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			CoreDNS:   "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		}
+		_, matches := matchesImageDigests(kcp, cluster, &clusterv1.Machine{})
+		g.Expect(matches).To(BeTrue())
+	})
+	t.Run("machine should match if the image digests hash is equal", func(t *testing.T) {
+		g := NewWithT(t)
+		_, matches := matchesImageDigests(kcp, cluster, machineWithHash(ImageDigestsHash(kcp, cluster)))
+		g.Expect(matches).To(BeTrue())
+	})
+	t.Run("machine should not match if the image digests changed", func(t *testing.T) {
+		g := NewWithT(t)
+		machine := machineWithHash(ImageDigestsHash(kcp, cluster))
+		cluster := cluster.DeepCopy()
+		cluster.Spec.ImageRegistry.Digests.KubeAPIServer = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		reason, matches := matchesImageDigests(kcp, cluster, machine)
+		g.Expect(matches).To(BeFalse())
+		g.Expect(reason).To(Equal("Machine image digests are outdated"))
+	})
+	t.Run("machine should not match if the image digests are removed", func(t *testing.T) {
+		g := NewWithT(t)
+		_, matches := matchesImageDigests(kcp, &clusterv1.Cluster{}, machineWithHash(ImageDigestsHash(kcp, cluster)))
+		g.Expect(matches).To(BeFalse())
+	})
+	t.Run("machine should match if the repository changed but the KCP defines the image repository", func(t *testing.T) {
+		g := NewWithT(t)
+		kcp := kcp.DeepCopy()
+		kcp.Spec.KubeadmConfigSpec.ClusterConfiguration = &bootstrapv1.ClusterConfiguration{ImageRepository: "registry.k8s.io"}
+		machine := machineWithHash(ImageDigestsHash(kcp, cluster))
+		cluster := cluster.DeepCopy()
+		cluster.Spec.ImageRegistry.Repository = "mirror.example.com"
+		_, matches := matchesImageDigests(kcp, cluster, machine)
+		g.Expect(matches).To(BeTrue())
+	})
+}
+
 func TestGetAdjustedKcpConfig(t *testing.T) {
 	t.Run("if the machine is the first control plane, kcp config should get InitConfiguration", func(t *testing.T) {
 		g := NewWithT(t)
@@ -888,6 +1008,61 @@ func TestMatchesKubeadmBootstrapConfig(t *testing.T) {
 		g.Expect(match).To(BeFalse())
 		g.Expect(reason).To(Equal("Machine InitConfiguration or JoinConfiguration are outdated"))
 	})
+	t.Run("returns false if the content referenced by files changed", func(t *testing.T) {
+		g := NewWithT(t)
+		kcp := &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+					ClusterConfiguration: &bootstrapv1.ClusterConfiguration{},
+					InitConfiguration:    &bootstrapv1.InitConfiguration{},
+					JoinConfiguration:    &bootstrapv1.JoinConfiguration{},
+				},
+			},
+		}
+		m := &clusterv1.Machine{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "KubeadmConfig",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "test",
+			},
+			Spec: clusterv1.MachineSpec{
+				Bootstrap: clusterv1.Bootstrap{
+					ConfigRef: &corev1.ObjectReference{
+						Kind:       "KubeadmConfig",
+						Namespace:  "default",
+						Name:       "test",
+						APIVersion: bootstrapv1.GroupVersion.String(),
+					},
+				},
+			},
+		}
+		machineConfigs := map[string]*bootstrapv1.KubeadmConfig{
+			m.Name: {
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "KubeadmConfig",
+					APIVersion: bootstrapv1.GroupVersion.String(),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					JoinConfiguration: &bootstrapv1.JoinConfiguration{},
+				},
+				Status: bootstrapv1.KubeadmConfigStatus{
+					Conditions: clusterv1.Conditions{
+						*conditions.FalseCondition(bootstrapv1.FileSourcesUpToDateCondition, bootstrapv1.FileSourcesChangedReason, clusterv1.ConditionSeverityWarning, ""),
+					},
+				},
+			},
+		}
+		reason, match := matchesKubeadmBootstrapConfig(machineConfigs, kcp, m)
+		g.Expect(match).To(BeFalse())
+		g.Expect(reason).To(Equal("Machine files content is outdated"))
+	})
 	t.Run("should match on labels and annotations", func(t *testing.T) {
 		kcp := &controlplanev1.KubeadmControlPlane{
 			Spec: controlplanev1.KubeadmControlPlaneSpec{
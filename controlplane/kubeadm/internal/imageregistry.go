@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+// ImageDigestsHash returns the hash of the image digests of the control plane components defined in the image
+// registry of the Cluster, which are applied to the static pod manifests when generating the bootstrap data of a
+// control plane machine; it returns an empty string if no digest is defined for the control plane components.
+// NOTE: The digests of kube-proxy and CoreDNS are not included, because KCP updates them in place.
+// NOTE: The image registry repository is included only if the KCP does not define an image repository, because
+// otherwise it is not used for the control plane components.
+func ImageDigestsHash(kcp *controlplanev1.KubeadmControlPlane, cluster *clusterv1.Cluster) string {
+	if cluster == nil || cluster.Spec.ImageRegistry == nil || cluster.Spec.ImageRegistry.Digests == nil {
+		return ""
+	}
+	digests := cluster.Spec.ImageRegistry.Digests
+	if digests.KubeAPIServer == "" && digests.KubeControllerManager == "" && digests.KubeScheduler == "" && digests.Etcd == "" {
+		return ""
+	}
+
+	repository := ""
+	if kcp.Spec.KubeadmConfigSpec.ClusterConfiguration == nil || kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.ImageRepository == "" {
+		repository = cluster.Spec.ImageRegistry.Repository
+	}
+	data := fmt.Sprintf("%s/%s/%s/%s/%s", repository, digests.KubeAPIServer, digests.KubeControllerManager, digests.KubeScheduler, digests.Etcd)
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(data)))
+}
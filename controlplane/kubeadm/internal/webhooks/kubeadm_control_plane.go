@@ -29,6 +29,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -36,6 +37,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	kubeadmtypes "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/util/kubeadm"
 	"sigs.k8s.io/cluster-api/util/container"
@@ -88,6 +90,21 @@ func defaultKubeadmControlPlaneSpec(s *controlplanev1.KubeadmControlPlaneSpec, n
 	s.KubeadmConfigSpec.Default()
 
 	s.RolloutStrategy = defaultRolloutStrategy(s.RolloutStrategy)
+
+	defaultEncryptionAtRest(s.EncryptionAtRest)
+}
+
+func defaultEncryptionAtRest(encryptionAtRest *controlplanev1.EncryptionAtRest) {
+	if encryptionAtRest == nil {
+		return
+	}
+
+	if encryptionAtRest.Provider == "" {
+		encryptionAtRest.Provider = controlplanev1.AESCBCEncryptionProvider
+	}
+	if len(encryptionAtRest.Resources) == 0 {
+		encryptionAtRest.Resources = []string{"secrets"}
+	}
 }
 
 func defaultRolloutStrategy(rolloutStrategy *controlplanev1.RolloutStrategy) *controlplanev1.RolloutStrategy {
@@ -150,6 +167,8 @@ const (
 	ntp                  = "ntp"
 	ignition             = "ignition"
 	diskSetup            = "diskSetup"
+	output               = "output"
+	encryptionAtRest     = "encryptionAtRest"
 )
 
 const minimumCertificatesExpiryDays = 7
@@ -196,6 +215,8 @@ func (webhook *KubeadmControlPlane) ValidateUpdate(_ context.Context, oldObj, ne
 		{spec, kubeadmConfigSpec, diskSetup},
 		{spec, kubeadmConfigSpec, diskSetup, "*"},
 		{spec, kubeadmConfigSpec, "format"},
+		{spec, kubeadmConfigSpec, output},
+		{spec, kubeadmConfigSpec, output, "*"},
 		{spec, kubeadmConfigSpec, "mounts"},
 		{spec, "machineTemplate", "metadata"},
 		{spec, "machineTemplate", "metadata", "*"},
@@ -214,6 +235,8 @@ func (webhook *KubeadmControlPlane) ValidateUpdate(_ context.Context, oldObj, ne
 		{spec, "rolloutBefore", "*"},
 		{spec, "rolloutStrategy"},
 		{spec, "rolloutStrategy", "*"},
+		{spec, encryptionAtRest},
+		{spec, encryptionAtRest, "*"},
 	}
 
 	oldK, ok := oldObj.(*controlplanev1.KubeadmControlPlane)
@@ -265,7 +288,8 @@ func (webhook *KubeadmControlPlane) ValidateUpdate(_ context.Context, oldObj, ne
 	allErrs = append(allErrs, webhook.validateVersion(oldK, newK)...)
 	allErrs = append(allErrs, validateClusterConfiguration(oldK.Spec.KubeadmConfigSpec.ClusterConfiguration, newK.Spec.KubeadmConfigSpec.ClusterConfiguration, field.NewPath("spec", "kubeadmConfigSpec", "clusterConfiguration"))...)
 	allErrs = append(allErrs, webhook.validateCoreDNSVersion(oldK, newK)...)
-	allErrs = append(allErrs, newK.Spec.KubeadmConfigSpec.Validate(field.NewPath("spec", "kubeadmConfigSpec"))...)
+	allErrs = append(allErrs, validateEncryptionAtRestUpdate(oldK.Spec.EncryptionAtRest, newK.Spec.EncryptionAtRest, field.NewPath("spec", "encryptionAtRest"))...)
+	allErrs = append(allErrs, newK.Spec.KubeadmConfigSpec.ValidateUpdate(&oldK.Spec.KubeadmConfigSpec, field.NewPath("spec", "kubeadmConfigSpec"))...)
 
 	if len(allErrs) > 0 {
 		return nil, apierrors.NewInvalid(clusterv1.GroupVersion.WithKind("KubeadmControlPlane").GroupKind(), newK.Name, allErrs)
@@ -363,10 +387,48 @@ func validateKubeadmControlPlaneSpec(s controlplanev1.KubeadmControlPlaneSpec, n
 
 	if !version.KubeSemver.MatchString(s.Version) {
 		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("version"), s.Version, "must be a valid semantic version"))
+	} else if parsedVersion, err := semver.ParseTolerant(s.Version); err == nil {
+		// Validate fields of the ClusterConfiguration which can be used only with some Kubernetes versions.
+		allErrs = append(allErrs, kubeadmtypes.ValidateClusterConfigurationForVersion(s.KubeadmConfigSpec.ClusterConfiguration, parsedVersion, pathPrefix.Child("kubeadmConfigSpec", "clusterConfiguration"))...)
 	}
 
 	allErrs = append(allErrs, validateRolloutBefore(s.RolloutBefore, pathPrefix.Child("rolloutBefore"))...)
 	allErrs = append(allErrs, validateRolloutStrategy(s.RolloutStrategy, s.Replicas, pathPrefix.Child("rolloutStrategy"))...)
+	allErrs = append(allErrs, validateEncryptionAtRest(s.EncryptionAtRest, pathPrefix.Child("encryptionAtRest"))...)
+
+	return allErrs
+}
+
+func validateEncryptionAtRest(encryptionAtRest *controlplanev1.EncryptionAtRest, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if encryptionAtRest == nil {
+		return allErrs
+	}
+
+	supportedResources := sets.New[string]("secrets", "configmaps")
+	seenResources := sets.Set[string]{}
+	for i, resource := range encryptionAtRest.Resources {
+		if !supportedResources.Has(resource) {
+			allErrs = append(allErrs, field.NotSupported(pathPrefix.Child("resources").Index(i), resource, sets.List(supportedResources)))
+			continue
+		}
+		if seenResources.Has(resource) {
+			allErrs = append(allErrs, field.Duplicate(pathPrefix.Child("resources").Index(i), resource))
+		}
+		seenResources.Insert(resource)
+	}
+
+	return allErrs
+}
+
+func validateEncryptionAtRestUpdate(oldEncryptionAtRest, newEncryptionAtRest *controlplanev1.EncryptionAtRest, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// Resources already encrypted would become unreadable if the encryption at rest is disabled.
+	if oldEncryptionAtRest != nil && newEncryptionAtRest == nil {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix, "cannot be unset"))
+	}
 
 	return allErrs
 }
@@ -76,6 +76,12 @@ func TestKubeadmControlPlaneDefault(t *testing.T) {
 	g.Expect(kcp.Spec.Version).To(Equal("v1.18.3"))
 	g.Expect(kcp.Spec.RolloutStrategy.Type).To(Equal(controlplanev1.RollingUpdateStrategyType))
 	g.Expect(kcp.Spec.RolloutStrategy.RollingUpdate.MaxSurge.IntVal).To(Equal(int32(1)))
+	g.Expect(kcp.Spec.EncryptionAtRest).To(BeNil())
+
+	kcp.Spec.EncryptionAtRest = &controlplanev1.EncryptionAtRest{}
+	g.Expect(webhook.Default(ctx, kcp)).To(Succeed())
+	g.Expect(kcp.Spec.EncryptionAtRest.Provider).To(Equal(controlplanev1.AESCBCEncryptionProvider))
+	g.Expect(kcp.Spec.EncryptionAtRest.Resources).To(Equal([]string{"secrets"}))
 }
 
 func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
@@ -149,11 +155,35 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 	invalidCoreDNSVersion := valid.DeepCopy()
 	invalidCoreDNSVersion.Spec.KubeadmConfigSpec.ClusterConfiguration.DNS.ImageTag = "v1.7" // not a valid semantic version
 
+	invalidExtraEnvsVersion := valid.DeepCopy()
+	invalidExtraEnvsVersion.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer.ExtraEnvs = []bootstrapv1.EnvVar{
+		{EnvVar: corev1.EnvVar{Name: "HTTP_PROXY", Value: "http://proxy"}},
+	}
+
+	validExtraEnvsVersion := invalidExtraEnvsVersion.DeepCopy()
+	validExtraEnvsVersion.Spec.Version = "v1.31.0"
+
 	invalidRolloutBeforeCertificateExpiryDays := valid.DeepCopy()
 	invalidRolloutBeforeCertificateExpiryDays.Spec.RolloutBefore = &controlplanev1.RolloutBefore{
 		CertificatesExpiryDays: pointer.Int32(5), // less than minimum
 	}
 
+	validEncryptionAtRest := valid.DeepCopy()
+	validEncryptionAtRest.Spec.EncryptionAtRest = &controlplanev1.EncryptionAtRest{
+		Provider:  controlplanev1.SecretboxEncryptionProvider,
+		Resources: []string{"secrets", "configmaps"},
+	}
+
+	invalidEncryptionAtRestResources := valid.DeepCopy()
+	invalidEncryptionAtRestResources.Spec.EncryptionAtRest = &controlplanev1.EncryptionAtRest{
+		Resources: []string{"secrets", "pods"},
+	}
+
+	duplicateEncryptionAtRestResources := valid.DeepCopy()
+	duplicateEncryptionAtRestResources.Spec.EncryptionAtRest = &controlplanev1.EncryptionAtRest{
+		Resources: []string{"secrets", "secrets"},
+	}
+
 	invalidIgnitionConfiguration := valid.DeepCopy()
 	invalidIgnitionConfiguration.Spec.KubeadmConfigSpec.Ignition = &bootstrapv1.IgnitionSpec{}
 
@@ -227,6 +257,16 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       invalidCoreDNSVersion,
 		},
+		{
+			name:      "should return error when extraEnvs are used with Kubernetes version < v1.31.0",
+			expectErr: true,
+			kcp:       invalidExtraEnvsVersion,
+		},
+		{
+			name:      "should succeed when extraEnvs are used with Kubernetes version >= v1.31.0",
+			expectErr: false,
+			kcp:       validExtraEnvsVersion,
+		},
 		{
 			name:      "should return error when maxSurge is not 1",
 			expectErr: true,
@@ -242,6 +282,21 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       invalidRolloutBeforeCertificateExpiryDays,
 		},
+		{
+			name:      "should succeed when given a valid encryptionAtRest",
+			expectErr: false,
+			kcp:       validEncryptionAtRest,
+		},
+		{
+			name:      "should return error when encryptionAtRest.resources contains an unsupported resource",
+			expectErr: true,
+			kcp:       invalidEncryptionAtRestResources,
+		},
+		{
+			name:      "should return error when encryptionAtRest.resources contains duplicates",
+			expectErr: true,
+			kcp:       duplicateEncryptionAtRestResources,
+		},
 
 		{
 			name:                  "should return error when Ignition configuration is invalid",
@@ -654,6 +709,16 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	unsetRolloutBefore := before.DeepCopy()
 	unsetRolloutBefore.Spec.RolloutBefore = nil
 
+	enableEncryptionAtRest := before.DeepCopy()
+	enableEncryptionAtRest.Spec.EncryptionAtRest = &controlplanev1.EncryptionAtRest{
+		Provider:  controlplanev1.AESCBCEncryptionProvider,
+		Resources: []string{"secrets"},
+	}
+
+	rotateEncryptionKeys := enableEncryptionAtRest.DeepCopy()
+	rotateEncryptionKeys.Spec.EncryptionAtRest.Provider = controlplanev1.SecretboxEncryptionProvider
+	rotateEncryptionKeys.Spec.EncryptionAtRest.RotateKeysAfter = &metav1.Time{Time: time.Date(2023, 3, 9, 9, 0, 0, 0, time.UTC)}
+
 	invalidIgnitionConfiguration := before.DeepCopy()
 	invalidIgnitionConfiguration.Spec.KubeadmConfigSpec.Ignition = &bootstrapv1.IgnitionSpec{}
 
@@ -694,8 +759,17 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 
 	switchFromCloudInitToIgnition := before.DeepCopy()
 	switchFromCloudInitToIgnition.Spec.KubeadmConfigSpec.Format = bootstrapv1.Ignition
+	switchFromCloudInitToIgnition.Spec.KubeadmConfigSpec.DiskSetup = &bootstrapv1.DiskSetup{
+		Filesystems: []bootstrapv1.Filesystem{
+			{
+				Device:     "/dev/sda",
+				Filesystem: "ext4",
+				Label:      "etcd_disk",
+			},
+		},
+	}
 	switchFromCloudInitToIgnition.Spec.KubeadmConfigSpec.Mounts = []bootstrapv1.MountPoints{
-		{"/var/lib/testdir", "/var/lib/etcd/data"},
+		{"etcd_disk", "/var/lib/etcd/data"},
 	}
 
 	invalidMetadata := before.DeepCopy()
@@ -1037,6 +1111,24 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 			before:    before,
 			kcp:       unsetRolloutBefore,
 		},
+		{
+			name:      "should allow enabling encryptionAtRest",
+			expectErr: false,
+			before:    before,
+			kcp:       enableEncryptionAtRest,
+		},
+		{
+			name:      "should allow changing encryptionAtRest",
+			expectErr: false,
+			before:    enableEncryptionAtRest,
+			kcp:       rotateEncryptionKeys,
+		},
+		{
+			name:      "should return error when unsetting encryptionAtRest",
+			expectErr: true,
+			before:    enableEncryptionAtRest,
+			kcp:       before,
+		},
 		{
 			name:                  "should return error when Ignition configuration is invalid",
 			enableIgnitionFeature: true,
@@ -63,6 +63,8 @@ func (webhook *KubeadmControlPlaneTemplate) Default(_ context.Context, obj runti
 
 	k.Spec.Template.Spec.RolloutStrategy = defaultRolloutStrategy(k.Spec.Template.Spec.RolloutStrategy)
 
+	defaultEncryptionAtRest(k.Spec.Template.Spec.EncryptionAtRest)
+
 	return nil
 }
 
@@ -132,6 +134,7 @@ func validateKubeadmControlPlaneTemplateResourceSpec(s controlplanev1.KubeadmCon
 
 	allErrs = append(allErrs, validateRolloutBefore(s.RolloutBefore, pathPrefix.Child("rolloutBefore"))...)
 	allErrs = append(allErrs, validateRolloutStrategy(s.RolloutStrategy, nil, pathPrefix.Child("rolloutStrategy"))...)
+	allErrs = append(allErrs, validateEncryptionAtRest(s.EncryptionAtRest, pathPrefix.Child("encryptionAtRest"))...)
 
 	if s.MachineTemplate != nil {
 		// Validate the metadata of the MachineTemplate
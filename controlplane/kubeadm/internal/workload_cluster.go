@@ -113,8 +113,8 @@ type WorkloadCluster interface {
 	UpdateControllerManagerInKubeadmConfigMap(ctx context.Context, controllerManager bootstrapv1.ControlPlaneComponent, version semver.Version) error
 	UpdateSchedulerInKubeadmConfigMap(ctx context.Context, scheduler bootstrapv1.ControlPlaneComponent, version semver.Version) error
 	UpdateKubeletConfigMap(ctx context.Context, version semver.Version) error
-	UpdateKubeProxyImageInfo(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, version semver.Version) error
-	UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, version semver.Version) error
+	UpdateKubeProxyImageInfo(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, imageRegistry *clusterv1.ImageRegistry, version semver.Version) error
+	UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, imageRegistry *clusterv1.ImageRegistry, version semver.Version) error
 	RemoveEtcdMemberForMachine(ctx context.Context, machine *clusterv1.Machine) error
 	RemoveMachineFromKubeadmConfigMap(ctx context.Context, machine *clusterv1.Machine, version semver.Version) error
 	RemoveNodeFromKubeadmConfigMap(ctx context.Context, nodeName string, version semver.Version) error
@@ -124,6 +124,9 @@ type WorkloadCluster interface {
 
 	// State recovery tasks.
 	ReconcileEtcdMembers(ctx context.Context, nodeNames []string, version semver.Version) ([]string, error)
+
+	// Encryption at rest related tasks.
+	RewriteEncryptedResources(ctx context.Context, resources []string) error
 }
 
 // Workload defines operations on workload clusters.
@@ -539,7 +542,8 @@ func staticPodName(component, nodeName string) string {
 }
 
 // UpdateKubeProxyImageInfo updates kube-proxy image in the kube-proxy DaemonSet.
-func (w *Workload) UpdateKubeProxyImageInfo(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, version semver.Version) error {
+// The image is pinned to the kube-proxy digest defined in the image registry of the Cluster, if any.
+func (w *Workload) UpdateKubeProxyImageInfo(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, imageRegistry *clusterv1.ImageRegistry, version semver.Version) error {
 	// Return early if we've been asked to skip kube-proxy upgrades entirely.
 	if _, ok := kcp.Annotations[controlplanev1.SkipKubeProxyAnnotation]; ok {
 		return nil
@@ -560,13 +564,19 @@ func (w *Workload) UpdateKubeProxyImageInfo(ctx context.Context, kcp *controlpla
 		return nil
 	}
 
-	newImageName, err := containerutil.ModifyImageTag(container.Image, kcp.Spec.Version)
+	// Drop the digest, if any, so the image can be re-tagged; the digest is set again below if still pinned.
+	newImageName, err := containerutil.ModifyImageDigest(container.Image, "")
+	if err != nil {
+		return err
+	}
+
+	newImageName, err = containerutil.ModifyImageTag(newImageName, kcp.Spec.Version)
 	if err != nil {
 		return err
 	}
 
 	// Modify the image repository if a value was explicitly set or an upgrade is required.
-	imageRepository := ImageRepositoryFromClusterConfig(kcp.Spec.KubeadmConfigSpec.ClusterConfiguration, version)
+	imageRepository := ImageRepositoryFromClusterConfig(kcp.Spec.KubeadmConfigSpec.ClusterConfiguration, imageRegistry, version)
 	if imageRepository != "" {
 		newImageName, err = containerutil.ModifyImageRepository(newImageName, imageRepository)
 		if err != nil {
@@ -574,6 +584,13 @@ func (w *Workload) UpdateKubeProxyImageInfo(ctx context.Context, kcp *controlpla
 		}
 	}
 
+	if imageRegistry != nil && imageRegistry.Digests != nil && imageRegistry.Digests.KubeProxy != "" {
+		newImageName, err = containerutil.ModifyImageDigest(newImageName, imageRegistry.Digests.KubeProxy)
+		if err != nil {
+			return err
+		}
+	}
+
 	if container.Image != newImageName {
 		helper, err := patch.NewHelper(ds, w.Client)
 		if err != nil {
@@ -614,6 +631,7 @@ func yamlToUnstructured(rawYAML []byte) (*unstructured.Unstructured, error) {
 
 // ImageRepositoryFromClusterConfig returns the image repository to use. It returns:
 //   - clusterConfig.ImageRepository if set.
+//   - imageRegistry.Repository if set, i.e. the image repository defined in the Cluster.
 //   - else either k8s.gcr.io or registry.k8s.io depending on the default registry of the kubeadm
 //     binary of the given kubernetes version. This is only done for Kubernetes versions >= v1.22.0
 //     and < v1.26.0 because in this version range the default registry was changed.
@@ -622,13 +640,18 @@ func yamlToUnstructured(rawYAML []byte) (*unstructured.Unstructured, error) {
 // tl;dr is that the imageRepository must be in sync with the default registry of kubeadm.
 // Otherwise kubeadm preflight checks will fail because kubeadm is trying to pull the CoreDNS image
 // from the wrong repository (<registry>/coredns instead of <registry>/coredns/coredns).
-func ImageRepositoryFromClusterConfig(clusterConfig *bootstrapv1.ClusterConfiguration, kubernetesVersion semver.Version) string {
+func ImageRepositoryFromClusterConfig(clusterConfig *bootstrapv1.ClusterConfiguration, imageRegistry *clusterv1.ImageRegistry, kubernetesVersion semver.Version) string {
 	// If ImageRepository is explicitly specified, return early.
 	if clusterConfig != nil &&
 		clusterConfig.ImageRepository != "" {
 		return clusterConfig.ImageRepository
 	}
 
+	// If the Cluster defines an image repository, return it.
+	if imageRegistry != nil && imageRegistry.Repository != "" {
+		return imageRegistry.Repository
+	}
+
 	// If v1.22.0 <= version < v1.26.0 return the default registry of the
 	// corresponding kubeadm binary.
 	if kubernetesVersion.GTE(kubeadm.MinKubernetesVersionImageRegistryMigration) &&
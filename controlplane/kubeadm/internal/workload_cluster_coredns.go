@@ -33,6 +33,7 @@ import (
 	"k8s.io/client-go/util/retry"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/util/kubeadm"
@@ -103,8 +104,8 @@ type coreDNSInfo struct {
 }
 
 // UpdateCoreDNS updates the kubeadm configmap, coredns corefile and coredns
-// deployment.
-func (w *Workload) UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, version semver.Version) error {
+// deployment. The CoreDNS image is pinned to the CoreDNS digest defined in the image registry of the Cluster, if any.
+func (w *Workload) UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, imageRegistry *clusterv1.ImageRegistry, version semver.Version) error {
 	// Return early if we've been asked to skip CoreDNS upgrades entirely.
 	if _, ok := kcp.Annotations[controlplanev1.SkipCoreDNSAnnotation]; ok {
 		return nil
@@ -118,7 +119,7 @@ func (w *Workload) UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.Kubead
 	clusterConfig := kcp.Spec.KubeadmConfigSpec.ClusterConfiguration
 
 	// Get the CoreDNS info needed for the upgrade.
-	info, err := w.getCoreDNSInfo(ctx, clusterConfig, version)
+	info, err := w.getCoreDNSInfo(ctx, clusterConfig, imageRegistry, version)
 	if err != nil {
 		// Return early if we get a not found error, this can happen if any of the CoreDNS components
 		// cannot be found, e.g. configmap, deployment.
@@ -159,7 +160,7 @@ func (w *Workload) UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.Kubead
 }
 
 // getCoreDNSInfo returns all necessary coredns based information.
-func (w *Workload) getCoreDNSInfo(ctx context.Context, clusterConfig *bootstrapv1.ClusterConfiguration, version semver.Version) (*coreDNSInfo, error) {
+func (w *Workload) getCoreDNSInfo(ctx context.Context, clusterConfig *bootstrapv1.ClusterConfiguration, imageRegistry *clusterv1.ImageRegistry, version semver.Version) (*coreDNSInfo, error) {
 	// Get the coredns configmap and corefile.
 	key := ctrlclient.ObjectKey{Name: coreDNSKey, Namespace: metav1.NamespaceSystem}
 	cm, err := w.getConfigMap(ctx, key)
@@ -197,7 +198,7 @@ func (w *Workload) getCoreDNSInfo(ctx context.Context, clusterConfig *bootstrapv
 	// Handle imageRepository.
 	toImageRepository := parsedImage.Repository
 	// Overwrite the image repository if a value was explicitly set or an upgrade is required.
-	if imageRegistryRepository := ImageRepositoryFromClusterConfig(clusterConfig, version); imageRegistryRepository != "" {
+	if imageRegistryRepository := ImageRepositoryFromClusterConfig(clusterConfig, imageRegistry, version); imageRegistryRepository != "" {
 		if imageRegistryRepository == kubeadm.DefaultImageRepository {
 			// Only patch to DefaultImageRepository if OldDefaultImageRepository is set as prefix.
 			if strings.HasPrefix(toImageRepository, kubeadm.OldDefaultImageRepository) {
@@ -238,6 +239,11 @@ func (w *Workload) getCoreDNSInfo(ctx context.Context, clusterConfig *bootstrapv
 		toImageName = coreDNSImageName
 	}
 
+	toImage := fmt.Sprintf("%s/%s:%s", toImageRepository, toImageName, toImageTag)
+	if imageRegistry != nil && imageRegistry.Digests != nil && imageRegistry.Digests.CoreDNS != "" {
+		toImage = fmt.Sprintf("%s@%s", toImage, imageRegistry.Digests.CoreDNS)
+	}
+
 	return &coreDNSInfo{
 		Corefile:               corefile,
 		Deployment:             deployment,
@@ -246,7 +252,7 @@ func (w *Workload) getCoreDNSInfo(ctx context.Context, clusterConfig *bootstrapv
 		FromImageTag:           parsedImage.Tag,
 		ToImageTag:             toImageTag,
 		FromImage:              container.Image,
-		ToImage:                fmt.Sprintf("%s/%s:%s", toImageRepository, toImageName, toImageTag),
+		ToImage:                toImage,
 	}, nil
 }
 
@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/yaml"
@@ -517,7 +518,7 @@ func TestUpdateCoreDNS(t *testing.T) {
 				Client:          env.GetClient(),
 				CoreDNSMigrator: tt.migrator,
 			}
-			err := w.UpdateCoreDNS(ctx, tt.kcp, tt.semver)
+			err := w.UpdateCoreDNS(ctx, tt.kcp, nil, tt.semver)
 
 			if tt.expectErr {
 				g.Expect(err).To(HaveOccurred())
@@ -1135,6 +1136,7 @@ func TestGetCoreDNSInfo(t *testing.T) {
 			expectErr         bool
 			objs              []client.Object
 			clusterConfig     *bootstrapv1.ClusterConfiguration
+			imageRegistry     *clusterv1.ImageRegistry
 			kubernetesVersion semver.Version
 			expectedInfo      coreDNSInfo
 		}{
@@ -1192,6 +1194,31 @@ func TestGetCoreDNSInfo(t *testing.T) {
 					ToImageTag:             "1.7.2-foobar.1",
 				},
 			},
+			{
+				name: "uses the image registry of the Cluster if neither global nor DNS-level ImageRepository are set",
+				objs: []client.Object{newCoreDNSInfoDeploymentWithimage(imageSomeFolder162), cm},
+				clusterConfig: &bootstrapv1.ClusterConfiguration{
+					DNS: bootstrapv1.DNS{
+						ImageMeta: bootstrapv1.ImageMeta{
+							ImageTag: "1.7.2-foobar.1",
+						},
+					},
+				},
+				imageRegistry: &clusterv1.ImageRegistry{
+					Repository: "mirror/sub-path",
+					Digests: &clusterv1.ImageDigests{
+						CoreDNS: "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+					},
+				},
+				expectedInfo: coreDNSInfo{
+					CurrentMajorMinorPatch: "1.6.2",
+					FromImageTag:           "1.6.2",
+					TargetMajorMinorPatch:  "1.7.2",
+					FromImage:              imageSomeFolder162,
+					ToImage:                "mirror/sub-path/coredns:1.7.2-foobar.1@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+					ToImageTag:             "1.7.2-foobar.1",
+				},
+			},
 			{
 				name: "patches ImageRepository to registry.k8s.io if it's set on neither global nor DNS-level and kubernetesVersion >= v1.25",
 				objs: []client.Object{newCoreDNSInfoDeploymentWithimage(imageSomeFolder162), cm},
@@ -1386,7 +1413,7 @@ func TestGetCoreDNSInfo(t *testing.T) {
 					}
 				}
 
-				actualInfo, err := w.getCoreDNSInfo(ctx, tt.clusterConfig, tt.kubernetesVersion)
+				actualInfo, err := w.getCoreDNSInfo(ctx, tt.clusterConfig, tt.imageRegistry, tt.kubernetesVersion)
 				if tt.expectErr {
 					g.Expect(err).To(HaveOccurred())
 					return
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rewriteEncryptedResourcesPageSize is the number of resources listed at once when rewriting encrypted resources.
+const rewriteEncryptedResourcesPageSize = 100
+
+// RewriteEncryptedResources rewrites all the instances of the given resources, so they are stored
+// in etcd encrypted with the key currently used by the API servers.
+// NOTE: This requires the workload cluster client not to cache the given resources.
+func (w *Workload) RewriteEncryptedResources(ctx context.Context, resources []string) error {
+	for _, resource := range resources {
+		var err error
+		switch resource {
+		case "secrets":
+			err = w.rewriteSecrets(ctx)
+		case "configmaps":
+			err = w.rewriteConfigMaps(ctx)
+		default:
+			err = errors.Errorf("unsupported resource %q", resource)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to rewrite encrypted %s", resource)
+		}
+	}
+	return nil
+}
+
+func (w *Workload) rewriteSecrets(ctx context.Context) error {
+	secrets := &corev1.SecretList{}
+	for {
+		if err := w.Client.List(ctx, secrets, ctrlclient.Limit(rewriteEncryptedResourcesPageSize), ctrlclient.Continue(secrets.Continue)); err != nil {
+			return err
+		}
+		for i := range secrets.Items {
+			if err := w.rewriteResource(ctx, &secrets.Items[i]); err != nil {
+				return err
+			}
+		}
+		if secrets.Continue == "" {
+			return nil
+		}
+	}
+}
+
+func (w *Workload) rewriteConfigMaps(ctx context.Context) error {
+	configMaps := &corev1.ConfigMapList{}
+	for {
+		if err := w.Client.List(ctx, configMaps, ctrlclient.Limit(rewriteEncryptedResourcesPageSize), ctrlclient.Continue(configMaps.Continue)); err != nil {
+			return err
+		}
+		for i := range configMaps.Items {
+			if err := w.rewriteResource(ctx, &configMaps.Items[i]); err != nil {
+				return err
+			}
+		}
+		if configMaps.Continue == "" {
+			return nil
+		}
+	}
+}
+
+// rewriteResource rewrites a resource without changing it.
+// NOTE: Resources deleted or changed in the meantime are ignored, because they are already
+// stored encrypted with the key currently used by the API servers.
+func (w *Workload) rewriteResource(ctx context.Context, obj ctrlclient.Object) error {
+	if err := w.Client.Update(ctx, obj); err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		return errors.Wrapf(err, "failed to rewrite %s", ctrlclient.ObjectKeyFromObject(obj))
+	}
+	return nil
+}
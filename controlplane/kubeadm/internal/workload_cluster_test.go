@@ -112,13 +112,14 @@ func TestGetControlPlaneNodes(t *testing.T) {
 
 func TestUpdateKubeProxyImageInfo(t *testing.T) {
 	tests := []struct {
-		name        string
-		ds          appsv1.DaemonSet
-		expectErr   bool
-		expectImage string
-		clientGet   map[string]interface{}
-		patchErr    error
-		KCP         *controlplanev1.KubeadmControlPlane
+		name          string
+		ds            appsv1.DaemonSet
+		expectErr     bool
+		expectImage   string
+		clientGet     map[string]interface{}
+		patchErr      error
+		KCP           *controlplanev1.KubeadmControlPlane
+		imageRegistry *clusterv1.ImageRegistry
 	}{
 		{
 			name:        "succeeds if patch correctly",
@@ -162,6 +163,19 @@ func TestUpdateKubeProxyImageInfo(t *testing.T) {
 					},
 				}},
 		},
+		{
+			name:        "updates image repository and digest if defined in the image registry of the Cluster",
+			ds:          newKubeProxyDSWithImage("k8s.gcr.io/kube-proxy:v1.16.2@sha256:1111111111111111111111111111111111111111111111111111111111111111"),
+			expectErr:   false,
+			expectImage: "foo.bar.example/mirror/kube-proxy:v1.16.3@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			KCP:         &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{Version: "v1.16.3"}},
+			imageRegistry: &clusterv1.ImageRegistry{
+				Repository: "foo.bar.example/mirror",
+				Digests: &clusterv1.ImageDigests{
+					KubeProxy: "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+				},
+			},
+		},
 		{
 			name:        "does not update image repository if it is blank",
 			ds:          newKubeProxyDS(),
@@ -232,7 +246,7 @@ func TestUpdateKubeProxyImageInfo(t *testing.T) {
 			}
 			kubernetesVersion, err := version.ParseMajorMinorPatchTolerant(tt.KCP.Spec.Version)
 			gs.Expect(err).ToNot(HaveOccurred())
-			err = w.UpdateKubeProxyImageInfo(ctx, tt.KCP, kubernetesVersion)
+			err = w.UpdateKubeProxyImageInfo(ctx, tt.KCP, tt.imageRegistry, kubernetesVersion)
 			if tt.expectErr {
 				gs.Expect(err).To(HaveOccurred())
 			} else {
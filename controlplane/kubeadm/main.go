@@ -21,6 +21,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
 	goruntime "runtime"
 	"time"
@@ -55,6 +56,7 @@ import (
 	kcpwebhooks "sigs.k8s.io/cluster-api/controlplane/kubeadm/webhooks"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util/flags"
+	"sigs.k8s.io/cluster-api/util/tracing"
 	"sigs.k8s.io/cluster-api/version"
 )
 
@@ -80,10 +82,14 @@ var (
 	healthAddr                  string
 	tlsOptions                  = flags.TLSOptions{}
 	diagnosticsOptions          = flags.DiagnosticsOptions{}
+	tracingOptions              = flags.TracingOptions{}
 	logOptions                  = logs.NewOptions()
 	// KCP specific flags.
 	kubeadmControlPlaneConcurrency int
 	clusterCacheTrackerConcurrency int
+	clusterCacheTrackerClientQPS   float32
+	clusterCacheTrackerClientBurst int
+	clusterCacheTrackerProxy       string
 	etcdDialTimeout                time.Duration
 	etcdCallTimeout                time.Duration
 )
@@ -131,6 +137,15 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&clusterCacheTrackerConcurrency, "clustercachetracker-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
+	fs.Float32Var(&clusterCacheTrackerClientQPS, "clustercachetracker-client-qps", 5,
+		"Maximum queries per second from the controller clients to the Kubernetes API server of workload clusters. Defaults to 5")
+
+	fs.IntVar(&clusterCacheTrackerClientBurst, "clustercachetracker-client-burst", 10,
+		"Maximum number of queries that should be allowed in one burst from the controller clients to the Kubernetes API server of workload clusters. Default 10")
+
+	fs.StringVar(&clusterCacheTrackerProxy, "clustercachetracker-proxy", "",
+		"URL of the HTTP CONNECT (http or https scheme) or SOCKS5 (socks5 scheme) proxy used to reach the Kubernetes API server of workload clusters; it can be overridden per Cluster with the cluster-cache.cluster.x-k8s.io/proxy annotation")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
@@ -157,6 +172,7 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	flags.AddDiagnosticsOptions(fs, &diagnosticsOptions)
 	flags.AddTLSOptions(fs, &tlsOptions)
+	flags.AddTracingOptions(fs, &tracingOptions)
 
 	feature.MutableGates.AddFlag(fs)
 }
@@ -265,6 +281,12 @@ func main() {
 	// Setup the context that's going to be used in controllers and for the manager.
 	ctx := ctrl.SetupSignalHandler()
 
+	shutdownTracing, err := tracing.Setup(ctx, "capi-kubeadm-control-plane-controller-manager", tracingOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to setup tracing")
+		os.Exit(1)
+	}
+
 	setupChecks(mgr)
 	setupReconcilers(ctx, mgr)
 	setupWebhooks(mgr)
@@ -274,6 +296,10 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "problem flushing traces")
+	}
 }
 
 func setupChecks(mgr ctrl.Manager) {
@@ -300,12 +326,24 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	var clusterCacheTrackerProxyURL *url.URL
+	if clusterCacheTrackerProxy != "" {
+		clusterCacheTrackerProxyURL, err = remote.ParseProxyURL(clusterCacheTrackerProxy)
+		if err != nil {
+			setupLog.Error(err, "unable to parse the proxy URL of the ClusterCacheTracker")
+			os.Exit(1)
+		}
+	}
+
 	// Set up a ClusterCacheTracker to provide to controllers
 	// requiring a connection to a remote cluster
 	log := ctrl.Log.WithName("remote").WithName("ClusterCacheTracker")
 	tracker, err := remote.NewClusterCacheTracker(mgr, remote.ClusterCacheTrackerOptions{
 		SecretCachingClient: secretCachingClient,
 		ControllerName:      controllerName,
+		ClientQPS:           clusterCacheTrackerClientQPS,
+		ClientBurst:         clusterCacheTrackerClientBurst,
+		Proxy:               clusterCacheTrackerProxyURL,
 		Log:                 &log,
 		ClientUncachedObjects: []client.Object{
 			&corev1.ConfigMap{},
@@ -314,6 +352,10 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 			&appsv1.Deployment{},
 			&appsv1.DaemonSet{},
 		},
+		// KCP only reads the control plane Nodes of the workload clusters, so the other Nodes are not cached.
+		CacheByObject: map[client.Object]cache.ByObject{
+			&corev1.Node{}: {Label: labels.SelectorFromSet(labels.Set{"node-role.kubernetes.io/control-plane": ""})},
+		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to create cluster cache tracker")
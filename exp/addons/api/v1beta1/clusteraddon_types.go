@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ANCHOR: ClusterAddonSpec
+
+// ClusterAddonSpec defines the desired state of ClusterAddon.
+type ClusterAddonSpec struct {
+	// ClusterName is the name of the Cluster the addon is installed in.
+	ClusterName string `json:"clusterName"`
+
+	// AddonSetName is the name of the ClusterAddonSet defining the addon.
+	AddonSetName string `json:"addonSetName"`
+
+	// UpgradePhase defines when the addon is upgraded during the upgrade of the Cluster.
+	// It is kept in sync with the ClusterAddonSet, so the upgrade of Clusters with a managed topology can be
+	// held for the addon without reading the ClusterAddonSet.
+	// +kubebuilder:validation:Enum=BeforeControlPlaneUpgrade;AfterControlPlaneUpgrade;AfterWorkersUpgrade
+	UpgradePhase AddonUpgradePhase `json:"upgradePhase"`
+}
+
+// ANCHOR_END: ClusterAddonSpec
+
+// AddonVersionStatus defines a version of an addon applied to a Cluster.
+type AddonVersionStatus struct {
+	// Version is the version of the addon.
+	Version string `json:"version"`
+
+	// KubernetesVersion is the Kubernetes version of the Cluster the version of the addon has been selected for.
+	KubernetesVersion string `json:"kubernetesVersion"`
+
+	// Hash is the hash of the objects of the version of the addon, used to detect changes in its resources.
+	Hash string `json:"hash"`
+
+	// AppliedTime is the time the version of the addon has been applied to the Cluster.
+	// +optional
+	AppliedTime *metav1.Time `json:"appliedTime,omitempty"`
+}
+
+// ANCHOR: ClusterAddonStatus
+
+// ClusterAddonStatus defines the observed state of ClusterAddon.
+type ClusterAddonStatus struct {
+	// Current is the version of the addon applied to the Cluster and ready.
+	// +optional
+	Current *AddonVersionStatus `json:"current,omitempty"`
+
+	// Target is the version of the addon applied to the Cluster and not ready yet.
+	// +optional
+	Target *AddonVersionStatus `json:"target,omitempty"`
+
+	// Failed is the last version of the addon which did not become ready within the readiness timeout.
+	// It is applied again 5 minutes after it failed, or as soon as its resources change.
+	// +optional
+	Failed *AddonVersionStatus `json:"failed,omitempty"`
+
+	// ObservedGeneration reflects the generation of the most recently observed ClusterAddon.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions defines current state of the ClusterAddon.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// ANCHOR_END: ClusterAddonStatus
+
+// GetConditions returns the set of conditions for this object.
+func (m *ClusterAddon) GetConditions() clusterv1.Conditions {
+	return m.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (m *ClusterAddon) SetConditions(conditions clusterv1.Conditions) {
+	m.Status.Conditions = conditions
+}
+
+// IsReadyFor returns true if the version of the addon selected for the given Kubernetes version is applied to
+// the Cluster and ready.
+func (m *ClusterAddon) IsReadyFor(kubernetesVersion string) bool {
+	if m.Status.Target != nil || m.Status.Current == nil || m.Status.Current.KubernetesVersion != kubernetesVersion {
+		return false
+	}
+	for _, condition := range m.Status.Conditions {
+		if condition.Type == clusterv1.ReadyCondition {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=clusteraddons,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Cluster the addon is installed in"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.current.version",description="Version of the addon applied to the Cluster and ready"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".status.target.version",description="Version of the addon being upgraded to"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status",description="Addon ready"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of ClusterAddon"
+
+// ClusterAddon is the Schema for the clusteraddons API.
+// A ClusterAddon is created by a ClusterAddonSet for each matching Cluster, and tracks the version of the addon
+// installed in the Cluster.
+type ClusterAddon struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterAddonSpec   `json:"spec,omitempty"`
+	Status ClusterAddonStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterAddonList contains a list of ClusterAddon.
+type ClusterAddonList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterAddon `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &ClusterAddon{}, &ClusterAddonList{})
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestClusterAddonIsReadyFor(t *testing.T) {
+	ready := clusterv1.Conditions{{Type: clusterv1.ReadyCondition, Status: corev1.ConditionTrue}}
+	notReady := clusterv1.Conditions{{Type: clusterv1.ReadyCondition, Status: corev1.ConditionFalse}}
+
+	tests := []struct {
+		name   string
+		status ClusterAddonStatus
+		want   bool
+	}{
+		{
+			name: "ready for the Kubernetes version",
+			status: ClusterAddonStatus{
+				Current:    &AddonVersionStatus{Version: "v1", KubernetesVersion: "v1.28.0"},
+				Conditions: ready,
+			},
+			want: true,
+		},
+		{
+			name: "ready for another Kubernetes version",
+			status: ClusterAddonStatus{
+				Current:    &AddonVersionStatus{Version: "v1", KubernetesVersion: "v1.27.0"},
+				Conditions: ready,
+			},
+			want: false,
+		},
+		{
+			name: "a version is being rolled out",
+			status: ClusterAddonStatus{
+				Current:    &AddonVersionStatus{Version: "v1", KubernetesVersion: "v1.28.0"},
+				Target:     &AddonVersionStatus{Version: "v2", KubernetesVersion: "v1.28.0"},
+				Conditions: ready,
+			},
+			want: false,
+		},
+		{
+			name: "not ready",
+			status: ClusterAddonStatus{
+				Current:    &AddonVersionStatus{Version: "v1", KubernetesVersion: "v1.28.0"},
+				Conditions: notReady,
+			},
+			want: false,
+		},
+		{
+			name:   "not installed",
+			status: ClusterAddonStatus{},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			addon := &ClusterAddon{Status: tt.status}
+			g.Expect(addon.IsReadyFor("v1.28.0")).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// ClusterAddonSetNameLabel is the label set on ClusterAddons with the name of the ClusterAddonSet they are created for.
+	ClusterAddonSetNameLabel = "addons.cluster.x-k8s.io/cluster-addon-set-name"
+
+	// DefaultAddonReadinessTimeout is the default time a new version of an addon has to become ready.
+	DefaultAddonReadinessTimeout = 10 * time.Minute
+)
+
+// AddonUpgradePhase defines when an addon is upgraded during the upgrade of a Cluster.
+type AddonUpgradePhase string
+
+const (
+	// AddonUpgradePhaseBeforeControlPlaneUpgrade upgrades the addon before the control plane: the version of the addon
+	// is selected using the desired Kubernetes version of the Cluster, and for Clusters with a managed topology the
+	// upgrade of the control plane is held until the addon is ready.
+	AddonUpgradePhaseBeforeControlPlaneUpgrade AddonUpgradePhase = "BeforeControlPlaneUpgrade"
+
+	// AddonUpgradePhaseAfterControlPlaneUpgrade upgrades the addon after the control plane: the version of the addon
+	// is selected using the Kubernetes version of the control plane, and for Clusters with a managed topology the
+	// upgrade of the workers is held until the addon is ready.
+	AddonUpgradePhaseAfterControlPlaneUpgrade AddonUpgradePhase = "AfterControlPlaneUpgrade"
+
+	// AddonUpgradePhaseAfterWorkersUpgrade upgrades the addon after the control plane and all the workers: the version
+	// of the addon is selected using the oldest Kubernetes version of the control plane and the Machines of the Cluster.
+	AddonUpgradePhaseAfterWorkersUpgrade AddonUpgradePhase = "AfterWorkersUpgrade"
+)
+
+// ANCHOR: ClusterAddonSetSpec
+
+// ClusterAddonSetSpec defines the desired state of ClusterAddonSet.
+type ClusterAddonSetSpec struct {
+	// ClusterSelector is the label selector for the Clusters the addon is installed in.
+	// Label selector cannot be empty.
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector"`
+
+	// UpgradePhase defines when the addon is upgraded during the upgrade of a Cluster, and which Kubernetes version
+	// is used to select the version of the addon. Defaults to AfterControlPlaneUpgrade.
+	// +kubebuilder:validation:Enum=BeforeControlPlaneUpgrade;AfterControlPlaneUpgrade;AfterWorkersUpgrade
+	// +optional
+	UpgradePhase AddonUpgradePhase `json:"upgradePhase,omitempty"`
+
+	// Versions is the list of versions of the addon, with the Kubernetes versions each of them supports.
+	// The first version supporting the Kubernetes version of a Cluster is installed in the Cluster.
+	// +kubebuilder:validation:MinItems=1
+	Versions []AddonVersion `json:"versions"`
+
+	// ReadinessTimeout is the time a version of the addon has to become ready in a Cluster once applied,
+	// i.e. for all its objects to be ready. Defaults to 10m.
+	// +optional
+	ReadinessTimeout *metav1.Duration `json:"readinessTimeout,omitempty"`
+
+	// Rollback enables re-applying the previous version of the addon when a new version doesn't become ready
+	// within the readiness timeout.
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// ANCHOR_END: ClusterAddonSetSpec
+
+// AddonVersion defines a version of an addon.
+type AddonVersion struct {
+	// Version is the version of the addon, e.g. v3.26.1.
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// KubernetesVersions is the range of Kubernetes versions supported by this version of the addon, e.g. ">=1.27.0 <1.29.0".
+	// +kubebuilder:validation:MinLength=1
+	KubernetesVersions string `json:"kubernetesVersions"`
+
+	// Resources is a list of Secrets/ConfigMaps in the namespace of the ClusterAddonSet, where each contains 1 or
+	// more objects of this version of the addon.
+	// +kubebuilder:validation:MinItems=1
+	Resources []ResourceRef `json:"resources"`
+}
+
+// GetReadinessTimeout returns the time a version of the addon has to become ready in a Cluster once applied.
+func (s *ClusterAddonSetSpec) GetReadinessTimeout() time.Duration {
+	if s.ReadinessTimeout == nil {
+		return DefaultAddonReadinessTimeout
+	}
+	return s.ReadinessTimeout.Duration
+}
+
+// GetVersion returns the version of the addon with the given name, or nil if it does not exist.
+func (s *ClusterAddonSetSpec) GetVersion(version string) *AddonVersion {
+	for i := range s.Versions {
+		if s.Versions[i].Version == version {
+			return &s.Versions[i]
+		}
+	}
+	return nil
+}
+
+// ANCHOR: ClusterAddonSetStatus
+
+// ClusterAddonSetStatus defines the observed state of ClusterAddonSet.
+type ClusterAddonSetStatus struct {
+	// ObservedGeneration reflects the generation of the most recently observed ClusterAddonSet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions defines current state of the ClusterAddonSet.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// ANCHOR_END: ClusterAddonSetStatus
+
+// GetConditions returns the set of conditions for this object.
+func (m *ClusterAddonSet) GetConditions() clusterv1.Conditions {
+	return m.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (m *ClusterAddonSet) SetConditions(conditions clusterv1.Conditions) {
+	m.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=clusteraddonsets,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".spec.upgradePhase",description="Phase of the Cluster upgrade the addon is upgraded at"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='AddonsReady')].status",description="Addon ready in all the matching Clusters"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of ClusterAddonSet"
+
+// ClusterAddonSet is the Schema for the clusteraddonsets API.
+// A ClusterAddonSet defines the versions of an addon, e.g. a CNI, a CSI or a cloud-controller-manager, and installs
+// in each matching Cluster the version supporting its Kubernetes version, upgrading it along with the Cluster.
+type ClusterAddonSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterAddonSetSpec   `json:"spec,omitempty"`
+	Status ClusterAddonSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterAddonSetList contains a list of ClusterAddonSet.
+type ClusterAddonSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterAddonSet `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &ClusterAddonSet{}, &ClusterAddonSetList{})
+}
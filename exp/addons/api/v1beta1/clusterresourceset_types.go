@@ -45,10 +45,48 @@ type ClusterResourceSetSpec struct {
 	// +optional
 	Resources []ResourceRef `json:"resources,omitempty"`
 
+	// OCIArtifacts is a list of OCI artifacts where each contains 1 or more resources to be applied to remote clusters,
+	// after the resources.
+	// +optional
+	OCIArtifacts []OCIArtifactSource `json:"ociArtifacts,omitempty"`
+
+	// HelmCharts is a list of Helm charts to be rendered and applied to remote clusters, after the resources
+	// and the OCI artifacts.
+	// +optional
+	HelmCharts []HelmChartSource `json:"helmCharts,omitempty"`
+
+	// TemplateResources enables rendering the resources and the OCI artifacts as Go templates for each Cluster before
+	// applying them, so that per-cluster values can be used without a resource for each Cluster.
+	// The builtin variables of the Cluster are available as .builtin, e.g. {{ .builtin.cluster.name }},
+	// {{ .builtin.cluster.network.pods }} or {{ .builtin.cluster.controlPlaneEndpoint.host }}, and the variables of
+	// Clusters using a ClusterClass are available by name, e.g. {{ .cniConfig.mtu }}.
+	// +optional
+	TemplateResources bool `json:"templateResources,omitempty"`
+
+	// ResourceDependencies declares dependencies between the resources, the OCI artifacts and the Helm charts of
+	// the ClusterResourceSet; the objects of a resource are applied to a Cluster only once the objects of the resources
+	// it depends on are applied and ready, e.g. to apply a CNI only once its CRDs are established.
+	// +optional
+	ResourceDependencies []ResourceDependency `json:"resourceDependencies,omitempty"`
+
+	// DependsOn is a list of names of ClusterResourceSets in the same namespace which must be applied to a Cluster
+	// before this ClusterResourceSet; the ClusterResourceSet is applied to a Cluster only once all the resources of the
+	// ClusterResourceSets it depends on are applied and their objects are ready.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+
 	// Strategy is the strategy to be used during applying resources. Defaults to ApplyOnce. This field is immutable.
 	// +kubebuilder:validation:Enum=ApplyOnce;Reconcile
 	// +optional
 	Strategy string `json:"strategy,omitempty"`
+
+	// ConflictPolicy defines how conflicts with other field managers, e.g. GitOps tools, are handled when applying
+	// objects to a Cluster with server-side apply. Force takes the ownership of the conflicting fields, overwriting
+	// the changes of other field managers; Fail leaves the object unchanged and reports the conflict, so fields managed
+	// by other tools are never overwritten. Defaults to Force.
+	// +kubebuilder:validation:Enum=Force;Fail
+	// +optional
+	ConflictPolicy string `json:"conflictPolicy,omitempty"`
 }
 
 // ANCHOR_END: ClusterResourceSetSpec
@@ -60,6 +98,14 @@ type ClusterResourceSetResourceKind string
 const (
 	SecretClusterResourceSetResourceKind    ClusterResourceSetResourceKind = "Secret"
 	ConfigMapClusterResourceSetResourceKind ClusterResourceSetResourceKind = "ConfigMap"
+
+	// HelmChartClusterResourceSetResourceKind identifies the Helm charts of a ClusterResourceSet in
+	// ClusterResourceSetBindings; it can't be used in the resources of a ClusterResourceSet.
+	HelmChartClusterResourceSetResourceKind ClusterResourceSetResourceKind = "HelmChart"
+
+	// OCIArtifactClusterResourceSetResourceKind identifies the OCI artifacts of a ClusterResourceSet in
+	// ClusterResourceSetBindings; it can't be used in the resources of a ClusterResourceSet.
+	OCIArtifactClusterResourceSetResourceKind ClusterResourceSetResourceKind = "OCIArtifact"
 )
 
 // ResourceRef specifies a resource.
@@ -68,9 +114,123 @@ type ResourceRef struct {
 	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
 
+	// Kind of the resource. Supported kinds are: Secrets and ConfigMaps.
+	// HelmChart and OCIArtifact are used in ClusterResourceSetBindings to refer to the Helm charts and
+	// the OCI artifacts of a ClusterResourceSet.
+	// +kubebuilder:validation:Enum=Secret;ConfigMap;HelmChart;OCIArtifact
+	Kind string `json:"kind"`
+}
+
+// ResourceDependency specifies the dependencies of a resource, an OCI artifact or a Helm chart of a ClusterResourceSet.
+// Objects are ready once they exist in the Cluster, except CustomResourceDefinitions, which are ready once established,
+// and Deployments, which are ready once available.
+type ResourceDependency struct {
+	// Resource is the resource, the OCI artifact or the Helm chart with dependencies; the kind is one of Secret, ConfigMap,
+	// OCIArtifact or HelmChart.
+	Resource ResourceRef `json:"resource"`
+
+	// DependsOn is a list of resources, OCI artifacts or Helm charts of the ClusterResourceSet whose objects must be
+	// applied and ready before the objects of Resource are applied.
+	// +kubebuilder:validation:MinItems=1
+	DependsOn []ResourceRef `json:"dependsOn"`
+}
+
+// OCIArtifactSource specifies an OCI artifact with resources to be applied to remote clusters.
+// Each layer of the artifact is either a YAML/JSON document, or a gzipped tar archive of YAML/JSON files,
+// e.g. as pushed by `flux push artifact` or `oras push`.
+type OCIArtifactSource struct {
+	// Name identifies the OCI artifact in the ClusterResourceSet and in the ClusterResourceSetBindings.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Reference is the reference to the artifact, which must be pinned by digest,
+	// e.g. registry.example.com/addons/cni@sha256:0123456789abcdef...
+	// +kubebuilder:validation:MinLength=1
+	Reference string `json:"reference"`
+
+	// Verify defines how to verify the signature of the artifact. If not set, the signature is not verified.
+	// +optional
+	Verify *OCIArtifactVerification `json:"verify,omitempty"`
+}
+
+// OCIArtifactVerification specifies how to verify the cosign signature of an OCI artifact.
+type OCIArtifactVerification struct {
+	// SecretRef is the name of a Secret in the same namespace with ClusterResourceSet object, with the cosign
+	// public keys in PEM format in keys with the .pub suffix; the artifact must have a signature which can be
+	// verified by at least one of the keys.
+	// +kubebuilder:validation:MinLength=1
+	SecretRef string `json:"secretRef"`
+}
+
+// ResourceRef returns the reference used for the OCI artifact in ClusterResourceSetBindings.
+func (o *OCIArtifactSource) ResourceRef() ResourceRef {
+	return ResourceRef{
+		Name: o.Name,
+		Kind: string(OCIArtifactClusterResourceSetResourceKind),
+	}
+}
+
+// HelmChartSource specifies a Helm chart to be rendered and applied to remote clusters.
+type HelmChartSource struct {
+	// Name identifies the Helm chart in the ClusterResourceSet and in the ClusterResourceSetBindings.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// RepoURL is the URL of the Helm chart repository, e.g. https://charts.example.com,
+	// or of the OCI repository hosting the chart, e.g. oci://registry.example.com/charts.
+	// +kubebuilder:validation:MinLength=1
+	RepoURL string `json:"repoURL"`
+
+	// Chart is the name of the Helm chart.
+	// +kubebuilder:validation:MinLength=1
+	Chart string `json:"chart"`
+
+	// Version is the version of the Helm chart.
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// ReleaseName is the name of the release used when rendering the chart. Defaults to Name.
+	// +optional
+	ReleaseName string `json:"releaseName,omitempty"`
+
+	// ReleaseNamespace is the namespace used when rendering the chart; namespaced objects without a namespace
+	// are applied to this namespace, which is created in remote clusters if it does not exist. Defaults to default.
+	// +optional
+	ReleaseNamespace string `json:"releaseNamespace,omitempty"`
+
+	// ValuesFrom is a list of Secrets/ConfigMaps with values for the chart.
+	// Values are merged in order on top of the default values of the chart.
+	// +optional
+	ValuesFrom []HelmValuesReference `json:"valuesFrom,omitempty"`
+
+	// ValuesTemplate is a Go template of values for the chart, rendered for each Cluster and merged on top of
+	// the values from ValuesFrom, to provide per-cluster overrides. The Cluster is available in the template
+	// as .Cluster, e.g. {{ .Cluster.metadata.name }} or {{ .Cluster.spec.clusterNetwork.pods.cidrBlocks }}.
+	// +optional
+	ValuesTemplate string `json:"valuesTemplate,omitempty"`
+}
+
+// HelmValuesReference specifies a Secret/ConfigMap with values for a Helm chart.
+type HelmValuesReference struct {
+	// Name of the resource that is in the same namespace with ClusterResourceSet object.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
 	// Kind of the resource. Supported kinds are: Secrets and ConfigMaps.
 	// +kubebuilder:validation:Enum=Secret;ConfigMap
 	Kind string `json:"kind"`
+
+	// Key of the resource with the values, in YAML format. Defaults to values.yaml.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// ResourceRef returns the reference used for the Helm chart in ClusterResourceSetBindings.
+func (h *HelmChartSource) ResourceRef() ResourceRef {
+	return ResourceRef{
+		Name: h.Name,
+		Kind: string(HelmChartClusterResourceSetResourceKind),
+	}
 }
 
 // ClusterResourceSetStrategy is a string representation of a ClusterResourceSet Strategy.
@@ -90,6 +250,18 @@ func (c *ClusterResourceSetSpec) SetTypedStrategy(p ClusterResourceSetStrategy)
 	c.Strategy = string(p)
 }
 
+// ClusterResourceSetConflictPolicy is a string representation of a ClusterResourceSet ConflictPolicy.
+type ClusterResourceSetConflictPolicy string
+
+const (
+	// ClusterResourceSetConflictPolicyForce takes the ownership of the fields managed by other field managers
+	// when applying objects; it is the default conflict policy.
+	ClusterResourceSetConflictPolicyForce ClusterResourceSetConflictPolicy = "Force"
+	// ClusterResourceSetConflictPolicyFail fails applying objects with fields managed by other field managers,
+	// leaving the objects unchanged.
+	ClusterResourceSetConflictPolicyFail ClusterResourceSetConflictPolicy = "Fail"
+)
+
 // ANCHOR: ClusterResourceSetStatus
 
 // ClusterResourceSetStatus defines the observed state of ClusterResourceSet.
@@ -21,6 +21,8 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // ANCHOR: ResourceBinding
@@ -96,7 +98,8 @@ func (c *ClusterResourceSetBinding) GetOrCreateBinding(clusterResourceSet *Clust
 	return binding
 }
 
-// RemoveBinding removes the ClusterResourceSet from the ClusterResourceSetBinding Bindings list.
+// RemoveBinding removes the ClusterResourceSet from the ClusterResourceSetBinding Bindings list,
+// and its status from the ClusterResourceSetBinding ResourceSets list.
 func (c *ClusterResourceSetBinding) RemoveBinding(clusterResourceSet *ClusterResourceSet) {
 	for i, binding := range c.Spec.Bindings {
 		if binding.ClusterResourceSetName == clusterResourceSet.Name {
@@ -105,6 +108,83 @@ func (c *ClusterResourceSetBinding) RemoveBinding(clusterResourceSet *ClusterRes
 			break
 		}
 	}
+	for i, resourceSet := range c.Status.ResourceSets {
+		if resourceSet.ClusterResourceSetName == clusterResourceSet.Name {
+			c.Status.ResourceSets = append(c.Status.ResourceSets[:i], c.Status.ResourceSets[i+1:]...)
+			break
+		}
+	}
+}
+
+// GetOrCreateResourceSetStatus returns the ResourceSetStatus for a given ClusterResourceSet if exists,
+// otherwise creates one and adds it to the ClusterResourceSetBinding status.
+func (c *ClusterResourceSetBinding) GetOrCreateResourceSetStatus(clusterResourceSet *ClusterResourceSet) *ResourceSetStatus {
+	for i := range c.Status.ResourceSets {
+		if c.Status.ResourceSets[i].ClusterResourceSetName == clusterResourceSet.Name {
+			return &c.Status.ResourceSets[i]
+		}
+	}
+	c.Status.ResourceSets = append(c.Status.ResourceSets, ResourceSetStatus{ClusterResourceSetName: clusterResourceSet.Name})
+	return &c.Status.ResourceSets[len(c.Status.ResourceSets)-1]
+}
+
+// GetObjects returns the objects applied to the Cluster from a resource.
+func (r *ResourceSetStatus) GetObjects(resourceRef ResourceRef) []AppliedObject {
+	objects := []AppliedObject{}
+	for _, object := range r.Objects {
+		if object.Resource == resourceRef {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+// GetResourceStatus returns the ResourceStatus for a resource if present.
+func (r *ResourceSetStatus) GetResourceStatus(resourceRef ResourceRef) *ResourceStatus {
+	for i := range r.Resources {
+		if r.Resources[i].ResourceRef == resourceRef {
+			return &r.Resources[i]
+		}
+	}
+	return nil
+}
+
+// SetResourceApplied records that a resource has been applied to the Cluster.
+func (r *ResourceSetStatus) SetResourceApplied(resourceRef ResourceRef, hash string, t metav1.Time) {
+	status := r.getOrCreateResourceStatus(resourceRef)
+	status.LastAppliedHash = hash
+	status.LastAppliedTime = &t
+	status.LastAttemptTime = &t
+	status.Reason = ""
+	status.Message = ""
+}
+
+// SetResourceNotApplied records the reason why the last attempt to apply a resource to the Cluster did not succeed,
+// preserving the details of the last successful apply.
+func (r *ResourceSetStatus) SetResourceNotApplied(resourceRef ResourceRef, reason, message string, t metav1.Time) {
+	status := r.getOrCreateResourceStatus(resourceRef)
+	status.LastAttemptTime = &t
+	status.Reason = reason
+	status.Message = message
+}
+
+func (r *ResourceSetStatus) getOrCreateResourceStatus(resourceRef ResourceRef) *ResourceStatus {
+	if status := r.GetResourceStatus(resourceRef); status != nil {
+		return status
+	}
+	r.Resources = append(r.Resources, ResourceStatus{ResourceRef: resourceRef})
+	return &r.Resources[len(r.Resources)-1]
+}
+
+// SetObjects sets the objects applied to the Cluster from a resource, replacing the existing ones.
+func (r *ResourceSetStatus) SetObjects(resourceRef ResourceRef, objects []AppliedObject) {
+	newObjects := []AppliedObject{}
+	for _, object := range r.Objects {
+		if object.Resource != resourceRef {
+			newObjects = append(newObjects, object)
+		}
+	}
+	r.Objects = append(newObjects, objects...)
 }
 
 // DeleteBinding removes the ClusterResourceSet from the ClusterResourceSetBinding Bindings list.
@@ -175,7 +255,8 @@ func referSameObject(a, b metav1.OwnerReference) bool {
 type ClusterResourceSetBinding struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              ClusterResourceSetBindingSpec `json:"spec,omitempty"`
+	Spec              ClusterResourceSetBindingSpec   `json:"spec,omitempty"`
+	Status            ClusterResourceSetBindingStatus `json:"status,omitempty"`
 }
 
 // ANCHOR: ClusterResourceSetBindingSpec
@@ -194,6 +275,84 @@ type ClusterResourceSetBindingSpec struct {
 
 // ANCHOR_END: ClusterResourceSetBindingSpec
 
+// ANCHOR: ClusterResourceSetBindingStatus
+
+// ClusterResourceSetBindingStatus defines the observed state of ClusterResourceSetBinding.
+type ClusterResourceSetBindingStatus struct {
+	// ResourceSets reports the outcome of applying the resources of ClusterResourceSets to the Cluster, and the state
+	// of the objects applied to the Cluster by ClusterResourceSets with the Reconcile strategy.
+	// +optional
+	ResourceSets []ResourceSetStatus `json:"resourceSets,omitempty"`
+}
+
+// ANCHOR_END: ClusterResourceSetBindingStatus
+
+// ResourceSetStatus reports the state of the objects applied to a Cluster by a ClusterResourceSet.
+type ResourceSetStatus struct {
+	// ClusterResourceSetName is the name of the ClusterResourceSet that applied the objects to the owner cluster of the binding.
+	ClusterResourceSetName string `json:"clusterResourceSetName"`
+
+	// Resources reports the outcome of applying each of the resources of the ClusterResourceSet to the Cluster.
+	// +optional
+	Resources []ResourceStatus `json:"resources,omitempty"`
+
+	// Objects is the list of objects applied to the Cluster from the resources of the ClusterResourceSet.
+	// It is used to prune objects that are removed from the resources.
+	// +optional
+	Objects []AppliedObject `json:"objects,omitempty"`
+
+	// Conditions defines current state of the objects applied to the Cluster.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// ResourceStatus reports the outcome of applying a resource of a ClusterResourceSet to a Cluster.
+type ResourceStatus struct {
+	// ResourceRef specifies a resource.
+	ResourceRef `json:",inline"`
+
+	// LastAppliedHash is the hash of the resource's data when it was last applied successfully.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// LastAppliedTime identifies when the resource was last applied successfully.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// LastAttemptTime identifies when the resource was last reconciled, successfully or not.
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+
+	// Reason is a brief CamelCase string explaining why the last attempt to apply the resource, or to keep
+	// its objects in sync, did not succeed; it is empty if the last attempt succeeded.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable message with the details of why the last attempt to apply the resource,
+	// or to keep its objects in sync, did not succeed; it is empty if the last attempt succeeded.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// AppliedObject identifies an object applied to a Cluster from a resource of a ClusterResourceSet.
+type AppliedObject struct {
+	// Resource is the resource the object is defined in.
+	Resource ResourceRef `json:"resource"`
+
+	// APIVersion of the object.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the object.
+	Kind string `json:"kind"`
+
+	// Namespace of the object; it is empty for cluster-scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the object.
+	Name string `json:"name"`
+}
+
 // +kubebuilder:object:root=true
 
 // ClusterResourceSetBindingList contains a list of ClusterResourceSetBinding.
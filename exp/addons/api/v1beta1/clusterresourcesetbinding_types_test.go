@@ -216,3 +216,41 @@ func TestSetResourceBinding(t *testing.T) {
 		})
 	}
 }
+
+func TestSetResourceStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	resourceRef := ResourceRef{Name: "cni", Kind: "ConfigMap"}
+	appliedTime := metav1.NewTime(time.Now().UTC().Truncate(time.Second))
+	failedTime := metav1.NewTime(appliedTime.Add(time.Minute))
+
+	status := &ResourceSetStatus{ClusterResourceSetName: "test-clusterResourceSet"}
+	g.Expect(status.GetResourceStatus(resourceRef)).To(BeNil())
+
+	status.SetResourceApplied(resourceRef, "xyz", appliedTime)
+	g.Expect(status.Resources).To(HaveLen(1))
+	g.Expect(*status.GetResourceStatus(resourceRef)).To(Equal(ResourceStatus{
+		ResourceRef:     resourceRef,
+		LastAppliedHash: "xyz",
+		LastAppliedTime: &appliedTime,
+		LastAttemptTime: &appliedTime,
+	}))
+
+	// The details of the last successful apply are preserved on failures.
+	status.SetResourceNotApplied(resourceRef, "ApplyFailed", "failed to apply", failedTime)
+	g.Expect(status.Resources).To(HaveLen(1))
+	g.Expect(*status.GetResourceStatus(resourceRef)).To(Equal(ResourceStatus{
+		ResourceRef:     resourceRef,
+		LastAppliedHash: "xyz",
+		LastAppliedTime: &appliedTime,
+		LastAttemptTime: &failedTime,
+		Reason:          "ApplyFailed",
+		Message:         "failed to apply",
+	}))
+
+	// The failure is cleared once the resource is applied again.
+	status.SetResourceApplied(resourceRef, "abc", failedTime)
+	g.Expect(status.GetResourceStatus(resourceRef).Reason).To(BeEmpty())
+	g.Expect(status.GetResourceStatus(resourceRef).Message).To(BeEmpty())
+	g.Expect(status.GetResourceStatus(resourceRef).LastAppliedHash).To(Equal("abc"))
+}
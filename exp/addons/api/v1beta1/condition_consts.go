@@ -39,4 +39,65 @@ const (
 
 	// WrongSecretTypeReason (Severity=Warning) documents at least one of the Secret's type in the resource list is not supported.
 	WrongSecretTypeReason = "WrongSecretType"
+
+	// RetrievingOCIArtifactFailedReason (Severity=Warning) documents at least one of the OCI artifacts is not successfully retrieved.
+	RetrievingOCIArtifactFailedReason = "RetrievingOCIArtifactFailed"
+
+	// OCIArtifactVerificationFailedReason (Severity=Warning) documents the signature of at least one of the OCI artifacts can't be verified.
+	OCIArtifactVerificationFailedReason = "OCIArtifactVerificationFailed"
+
+	// HelmChartRenderFailedReason (Severity=Warning) documents at least one of the Helm charts is not successfully fetched or rendered.
+	HelmChartRenderFailedReason = "HelmChartRenderFailed"
+
+	// WaitingForDependenciesReason (Severity=Info) documents at least one of the resources, or the ClusterResourceSet,
+	// is waiting for its dependencies to be applied and ready before being applied to one of the matching clusters.
+	WaitingForDependenciesReason = "WaitingForDependencies"
+)
+
+// Conditions and condition Reasons for the objects applied to a Cluster by a ClusterResourceSet,
+// reported in the ClusterResourceSetBinding object.
+
+const (
+	// ResourcesInSyncCondition documents that the objects applied to a Cluster by a ClusterResourceSet with the Reconcile strategy
+	// match the resources of the ClusterResourceSet. Objects are checked for drift every time the ClusterResourceSet is reconciled.
+	ResourcesInSyncCondition clusterv1.ConditionType = "ResourcesInSync"
+
+	// DriftCorrectedReason (Severity=Info) documents that at least one of the objects applied to a Cluster was changed or
+	// deleted in the Cluster since the last check, and it has been re-applied.
+	DriftCorrectedReason = "DriftCorrected"
+
+	// DriftCorrectionFailedReason (Severity=Warning) documents a failure re-applying the objects applied to a Cluster.
+	DriftCorrectionFailedReason = "DriftCorrectionFailed"
+
+	// PruneFailedReason (Severity=Warning) documents a failure deleting from a Cluster the objects that have been removed
+	// from the resources of the ClusterResourceSet.
+	PruneFailedReason = "PruneFailed"
+)
+
+// Conditions and condition Reasons for the ClusterAddon and ClusterAddonSet objects.
+
+const (
+	// AddonsReadyCondition documents that the addon defined by a ClusterAddonSet is ready in all the matching Clusters,
+	// summarizing the Ready condition of its ClusterAddons.
+	AddonsReadyCondition clusterv1.ConditionType = "AddonsReady"
+
+	// UpgradingReason (Severity=Info) documents that a new version of the addon has been applied to a Cluster
+	// and is not ready yet.
+	UpgradingReason = "Upgrading"
+
+	// NoCompatibleVersionReason (Severity=Warning) documents that none of the versions of the addon supports the
+	// Kubernetes version of a Cluster.
+	NoCompatibleVersionReason = "NoCompatibleVersion"
+
+	// WaitingForKubernetesVersionReason (Severity=Info) documents that the Kubernetes version used to select the
+	// version of the addon is not yet known, e.g. because the control plane is still being provisioned.
+	WaitingForKubernetesVersionReason = "WaitingForKubernetesVersion"
+
+	// UpgradeFailedReason (Severity=Error) documents that a new version of the addon did not become ready within
+	// the readiness timeout.
+	UpgradeFailedReason = "UpgradeFailed"
+
+	// RolledBackReason (Severity=Warning) documents that a new version of the addon did not become ready within
+	// the readiness timeout, and the previous version has been applied again.
+	RolledBackReason = "RolledBack"
 )
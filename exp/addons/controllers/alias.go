@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,13 +35,33 @@ type ClusterResourceSetReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// DriftDetectionInterval is the interval at which ClusterResourceSets with the Reconcile strategy
+	// re-apply their resources to the matching clusters, correcting drift; zero disables periodic drift detection.
+	DriftDetectionInterval time.Duration
+
+	// HelmRepositoryAllowList is the list of the URLs of the repositories Helm charts can be fetched from;
+	// if empty, charts can be fetched from any repository.
+	HelmRepositoryAllowList []string
+
+	// RegistryAllowList is the list of the OCI registries artifacts can be fetched from, e.g. registry.example.com
+	// or registry.example.com/addons; if empty, artifacts can be fetched from any registry.
+	RegistryAllowList []string
+
+	// RegistryCredentialsFile is the path of a Docker config file with the credentials to access private OCI registries,
+	// for both OCI artifacts and Helm charts; if empty, registries are accessed anonymously.
+	RegistryCredentialsFile string
 }
 
 func (r *ClusterResourceSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&clusterresourcesets.ClusterResourceSetReconciler{
-		Client:           r.Client,
-		Tracker:          r.Tracker,
-		WatchFilterValue: r.WatchFilterValue,
+		Client:                  r.Client,
+		Tracker:                 r.Tracker,
+		WatchFilterValue:        r.WatchFilterValue,
+		DriftDetectionInterval:  r.DriftDetectionInterval,
+		HelmRepositoryAllowList: r.HelmRepositoryAllowList,
+		RegistryAllowList:       r.RegistryAllowList,
+		RegistryCredentialsFile: r.RegistryCredentialsFile,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -58,3 +79,35 @@ func (r *ClusterResourceSetBindingReconciler) SetupWithManager(ctx context.Conte
 		WatchFilterValue: r.WatchFilterValue,
 	}).SetupWithManager(ctx, mgr, options)
 }
+
+// ClusterAddonSetReconciler reconciles a ClusterAddonSet object.
+type ClusterAddonSetReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *ClusterAddonSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return (&clusterresourcesets.ClusterAddonSetReconciler{
+		Client:           r.Client,
+		WatchFilterValue: r.WatchFilterValue,
+	}).SetupWithManager(ctx, mgr, options)
+}
+
+// ClusterAddonReconciler reconciles a ClusterAddon object.
+type ClusterAddonReconciler struct {
+	Client  client.Client
+	Tracker *remote.ClusterCacheTracker
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *ClusterAddonReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return (&clusterresourcesets.ClusterAddonReconciler{
+		Client:           r.Client,
+		Tracker:          r.Tracker,
+		WatchFilterValue: r.WatchFilterValue,
+	}).SetupWithManager(ctx, mgr, options)
+}
@@ -0,0 +1,538 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	resourcepredicates "sigs.k8s.io/cluster-api/exp/addons/internal/controllers/predicates"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/version"
+)
+
+const (
+	// clusterAddonManagerName is the field manager used when applying the objects of an addon to a Cluster.
+	clusterAddonManagerName = "capi-clusteraddon"
+
+	// clusterAddonRequeueInterval is the interval at which a ClusterAddon is reconciled while waiting for a
+	// version of the addon to become ready, or for the Kubernetes version of the Cluster to be known.
+	clusterAddonRequeueInterval = 10 * time.Second
+
+	// clusterAddonFailedRetryInterval is the interval after which a version of the addon which did not become ready
+	// within the readiness timeout is applied again.
+	clusterAddonFailedRetryInterval = 5 * time.Minute
+)
+
+// ClusterAddonReconciler reconciles a ClusterAddon object, installing in the Cluster the version of the addon
+// supporting the Kubernetes version of the Cluster and upgrading it along with the Cluster.
+type ClusterAddonReconciler struct {
+	Client  client.Client
+	Tracker *remote.ClusterCacheTracker
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *ClusterAddonReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&addonsv1.ClusterAddon{}).
+		Watches(
+			&addonsv1.ClusterAddonSet{},
+			handler.EnqueueRequestsFromMapFunc(r.clusterAddonSetToClusterAddons),
+		).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(r.clusterToClusterAddons),
+		).
+		Watches(
+			&clusterv1.Machine{},
+			handler.EnqueueRequestsFromMapFunc(r.machineToClusterAddons),
+		).
+		WatchesMetadata(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.resourceToClusterAddons),
+			builder.WithPredicates(
+				resourcepredicates.ResourceCreateOrUpdate(ctrl.LoggerFrom(ctx)),
+			),
+		).
+		WatchesMetadata(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.resourceToClusterAddons),
+			builder.WithPredicates(
+				resourcepredicates.ResourceCreateOrUpdate(ctrl.LoggerFrom(ctx)),
+			),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	return nil
+}
+
+func (r *ClusterAddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	// Fetch the ClusterAddon instance.
+	addon := &addonsv1.ClusterAddon{}
+	if err := r.Client.Get(ctx, req.NamespacedName, addon); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	// The objects of the addon are not deleted from the Cluster.
+	if !addon.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	addonSet := &addonsv1.ClusterAddonSet{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: addon.Namespace, Name: addon.Spec.AddonSetName}, addonSet); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The ClusterAddon is garbage collected with the ClusterAddonSet.
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	cluster, err := util.GetClusterByName(ctx, r.Client, addon.Namespace, addon.Spec.ClusterName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The ClusterAddon is garbage collected with the Cluster.
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	log := ctrl.LoggerFrom(ctx, "Cluster", klog.KObj(cluster), "ClusterAddonSet", klog.KObj(addonSet))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	// Initialize the patch helper.
+	patchHelper, err := patch.NewHelper(addon, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defer func() {
+		// Always attempt to Patch the ClusterAddon object and status after each reconciliation.
+		if err := patchHelper.Patch(ctx, addon, patch.WithStatusObservedGeneration{}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	res, err := r.reconcileNormal(ctx, cluster, addonSet, addon)
+	if errors.Is(err, remote.ErrClusterLocked) {
+		// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
+		// the current cluster because of concurrent access.
+		log.V(5).Info("Requeuing because another worker has the lock on the ClusterCacheTracker")
+		return ctrl.Result{Requeue: true}, nil
+	}
+	return res, err
+}
+
+// reconcileNormal selects the version of the addon supporting the Kubernetes version of the Cluster and applies it
+// to the Cluster, waiting for it to become ready; if a new version does not become ready within the readiness timeout,
+// the previous version is applied again if rollback is enabled.
+func (r *ClusterAddonReconciler) reconcileNormal(ctx context.Context, cluster *clusterv1.Cluster, addonSet *addonsv1.ClusterAddonSet, addon *addonsv1.ClusterAddon) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	kubernetesVersion, err := r.getKubernetesVersion(ctx, cluster, addon.Spec.UpgradePhase)
+	if err != nil {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.WaitingForKubernetesVersionReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	if kubernetesVersion == "" {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.WaitingForKubernetesVersionReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for the Kubernetes version of the Cluster to select the version of the addon")
+		return ctrl.Result{RequeueAfter: clusterAddonRequeueInterval}, nil
+	}
+
+	addonVersion, err := selectAddonVersion(addonSet.Spec.Versions, kubernetesVersion)
+	if err != nil {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.NoCompatibleVersionReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	if addonVersion == nil {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.NoCompatibleVersionReason, clusterv1.ConditionSeverityWarning,
+			"None of the versions of the addon supports Kubernetes version %s", kubernetesVersion)
+		return ctrl.Result{}, nil
+	}
+
+	objs, hash, err := r.getAddonObjects(ctx, addonSet.Namespace, addonVersion)
+	if err != nil {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.RetrievingResourceFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.RemoteClusterClientFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	status := &addon.Status
+	switch {
+	case isAddonVersion(status.Target, addonVersion.Version, hash):
+		// The version is being rolled out; wait for it to become ready.
+		status.Target.KubernetesVersion = kubernetesVersion
+		return r.reconcileTarget(ctx, remoteClient, addonSet, addon, objs)
+	case isAddonVersion(status.Failed, addonVersion.Version, hash):
+		// The version did not become ready within the readiness timeout; it is applied again after the retry
+		// interval, and until then the conditions set when it failed are preserved.
+		if retryAfter := failedVersionRetryAfter(status.Failed, addonSet.Spec.GetReadinessTimeout(), time.Now()); retryAfter > 0 {
+			return ctrl.Result{RequeueAfter: retryAfter}, nil
+		}
+		log.Info("Retrying failed addon version", "version", addonVersion.Version, "kubernetesVersion", kubernetesVersion)
+	case isAddonVersion(status.Current, addonVersion.Version, hash):
+		// The version is already installed; this happens when the same version of the addon supports
+		// both the previous and the new Kubernetes version of the Cluster.
+		status.Current.KubernetesVersion = kubernetesVersion
+		status.Target = nil
+		conditions.MarkTrue(addon, clusterv1.ReadyCondition)
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("Applying addon version", "version", addonVersion.Version, "kubernetesVersion", kubernetesVersion)
+	if err := applyAddonObjects(ctx, remoteClient, objs); err != nil {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	status.Target = &addonsv1.AddonVersionStatus{
+		Version:           addonVersion.Version,
+		KubernetesVersion: kubernetesVersion,
+		Hash:              hash,
+		AppliedTime:       &metav1.Time{Time: time.Now().UTC()},
+	}
+	conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.UpgradingReason, clusterv1.ConditionSeverityInfo,
+		"Waiting for version %s of the addon to be ready", addonVersion.Version)
+	return ctrl.Result{RequeueAfter: clusterAddonRequeueInterval}, nil
+}
+
+// reconcileTarget checks if the version of the addon being rolled out is ready and, if the readiness timeout expired,
+// marks it as failed, rolling back to the previous version if enabled.
+func (r *ClusterAddonReconciler) reconcileTarget(ctx context.Context, remoteClient client.Client, addonSet *addonsv1.ClusterAddonSet, addon *addonsv1.ClusterAddon, objs []unstructured.Unstructured) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	status := &addon.Status
+
+	notReady, err := getNotReadyObjects(ctx, remoteClient, objs)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(notReady) == 0 {
+		log.Info("Addon version is ready", "version", status.Target.Version)
+		status.Current = status.Target
+		status.Target = nil
+		status.Failed = nil
+		conditions.MarkTrue(addon, clusterv1.ReadyCondition)
+		return ctrl.Result{}, nil
+	}
+
+	timeout := addonSet.Spec.GetReadinessTimeout()
+	if status.Target.AppliedTime != nil && time.Since(status.Target.AppliedTime.Time) < timeout {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.UpgradingReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for version %s of the addon to be ready: %s", status.Target.Version, strings.Join(notReady, ", "))
+		return ctrl.Result{RequeueAfter: clusterAddonRequeueInterval}, nil
+	}
+
+	log.Info("Addon version did not become ready within the readiness timeout", "version", status.Target.Version, "timeout", timeout)
+	status.Failed = status.Target
+	status.Target = nil
+	message := fmt.Sprintf("Version %s of the addon did not become ready within %s: %s", status.Failed.Version, timeout, strings.Join(notReady, ", "))
+
+	if !addonSet.Spec.Rollback || status.Current == nil {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.UpgradeFailedReason, clusterv1.ConditionSeverityError, message)
+		return ctrl.Result{}, nil
+	}
+
+	previousVersion := addonSet.Spec.GetVersion(status.Current.Version)
+	if previousVersion == nil {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.UpgradeFailedReason, clusterv1.ConditionSeverityError,
+			"%s; version %s can't be rolled back to because it is not defined in the ClusterAddonSet anymore", message, status.Current.Version)
+		return ctrl.Result{}, nil
+	}
+	previousObjs, hash, err := r.getAddonObjects(ctx, addonSet.Namespace, previousVersion)
+	if err != nil {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.UpgradeFailedReason, clusterv1.ConditionSeverityError,
+			"%s; failed to roll back to version %s: %v", message, status.Current.Version, err)
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Rolling back addon version", "version", status.Current.Version)
+	if err := applyAddonObjects(ctx, remoteClient, previousObjs); err != nil {
+		conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.UpgradeFailedReason, clusterv1.ConditionSeverityError,
+			"%s; failed to roll back to version %s: %v", message, status.Current.Version, err)
+		return ctrl.Result{}, err
+	}
+	status.Current.Hash = hash
+	status.Current.AppliedTime = &metav1.Time{Time: time.Now().UTC()}
+	conditions.MarkFalse(addon, clusterv1.ReadyCondition, addonsv1.RolledBackReason, clusterv1.ConditionSeverityWarning,
+		"%s; rolled back to version %s", message, status.Current.Version)
+	return ctrl.Result{}, nil
+}
+
+// failedVersionRetryAfter returns how long to wait before applying again a version of the addon which did not
+// become ready within the readiness timeout; the version failed when the readiness timeout expired after it
+// has been applied.
+func failedVersionRetryAfter(failed *addonsv1.AddonVersionStatus, readinessTimeout time.Duration, now time.Time) time.Duration {
+	if failed.AppliedTime == nil {
+		return 0
+	}
+	retryAfter := failed.AppliedTime.Add(readinessTimeout + clusterAddonFailedRetryInterval).Sub(now)
+	if retryAfter < 0 {
+		return 0
+	}
+	return retryAfter
+}
+
+// getKubernetesVersion returns the Kubernetes version used to select the version of the addon for the upgrade phase;
+// an empty string is returned if the version is not known yet.
+//   - BeforeControlPlaneUpgrade uses the desired version of the Cluster, i.e. the version of the topology or of the control plane spec.
+//   - AfterControlPlaneUpgrade uses the version the control plane is running.
+//   - AfterWorkersUpgrade uses the oldest of the version the control plane is running and the versions of the Machines.
+func (r *ClusterAddonReconciler) getKubernetesVersion(ctx context.Context, cluster *clusterv1.Cluster, phase addonsv1.AddonUpgradePhase) (string, error) {
+	if phase == addonsv1.AddonUpgradePhaseBeforeControlPlaneUpgrade && cluster.Spec.Topology != nil {
+		return cluster.Spec.Topology.Version, nil
+	}
+	if cluster.Spec.ControlPlaneRef == nil {
+		return "", errors.New("clusters without a control plane object are not supported")
+	}
+
+	controlPlane, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the control plane of the Cluster to get its Kubernetes version")
+	}
+
+	var controlPlaneVersion *string
+	if phase == addonsv1.AddonUpgradePhaseBeforeControlPlaneUpgrade {
+		controlPlaneVersion, err = contract.ControlPlane().Version().Get(controlPlane)
+	} else {
+		controlPlaneVersion, err = contract.ControlPlane().StatusVersion().Get(controlPlane)
+	}
+	if err != nil {
+		if errors.Is(err, contract.ErrFieldNotFound) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get the Kubernetes version of the Cluster from its control plane")
+	}
+	if phase != addonsv1.AddonUpgradePhaseAfterWorkersUpgrade {
+		return *controlPlaneVersion, nil
+	}
+
+	machineList := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machineList, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return "", errors.Wrap(err, "failed to list the Machines of the Cluster")
+	}
+	versions := []string{*controlPlaneVersion}
+	for i := range machineList.Items {
+		if machineList.Items[i].Spec.Version != nil {
+			versions = append(versions, *machineList.Items[i].Spec.Version)
+		}
+	}
+	return oldestVersion(versions)
+}
+
+// oldestVersion returns the oldest of a list of Kubernetes versions.
+func oldestVersion(versions []string) (string, error) {
+	oldest := ""
+	var oldestSemver semver.Version
+	for _, v := range versions {
+		parsed, err := version.ParseMajorMinorPatchTolerant(v)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse Kubernetes version %q", v)
+		}
+		if oldest == "" || version.Compare(parsed, oldestSemver) < 0 {
+			oldest = v
+			oldestSemver = parsed
+		}
+	}
+	return oldest, nil
+}
+
+// selectAddonVersion returns the first version of the addon supporting the Kubernetes version, or nil if there is none.
+func selectAddonVersion(versions []addonsv1.AddonVersion, kubernetesVersion string) (*addonsv1.AddonVersion, error) {
+	v, err := version.ParseMajorMinorPatchTolerant(kubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse Kubernetes version %q", kubernetesVersion)
+	}
+	for i := range versions {
+		supported, err := semver.ParseRange(versions[i].KubernetesVersions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid Kubernetes versions %q for version %s of the addon", versions[i].KubernetesVersions, versions[i].Version)
+		}
+		if supported(v) {
+			return &versions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// isAddonVersion returns true if the status refers to the given version of the addon, with the same objects.
+func isAddonVersion(status *addonsv1.AddonVersionStatus, version, hash string) bool {
+	return status != nil && status.Version == version && status.Hash == hash
+}
+
+// getAddonObjects returns the objects defined by the resources of a version of an addon, and their hash.
+func (r *ClusterAddonReconciler) getAddonObjects(ctx context.Context, namespace string, addonVersion *addonsv1.AddonVersion) ([]unstructured.Unstructured, string, error) {
+	data := [][]byte{}
+	for _, resource := range addonVersion.Resources {
+		resourceName := types.NamespacedName{Namespace: namespace, Name: resource.Name}
+
+		var resourceObj interface{}
+		switch resource.Kind {
+		case string(addonsv1.ConfigMapClusterResourceSetResourceKind):
+			configMap, err := getConfigMap(ctx, r.Client, resourceName)
+			if err != nil {
+				return nil, "", errors.Wrapf(err, "failed to get ConfigMap %s", resource.Name)
+			}
+			resourceObj = configMap
+		case string(addonsv1.SecretClusterResourceSetResourceKind):
+			secret, err := getSecret(ctx, r.Client, resourceName)
+			if err != nil {
+				return nil, "", errors.Wrapf(err, "failed to get Secret %s", resource.Name)
+			}
+			if secret.Type != addonsv1.ClusterResourceSetSecretType {
+				return nil, "", errors.Wrapf(ErrSecretTypeNotSupported, "Secret %s", resource.Name)
+			}
+			resourceObj = secret
+		default:
+			return nil, "", errors.Errorf("unsupported resource kind %s", resource.Kind)
+		}
+
+		raw := &unstructured.Unstructured{}
+		if err := r.Client.Scheme().Convert(resourceObj, raw, nil); err != nil {
+			return nil, "", err
+		}
+		normalizedData, err := normalizeData(raw)
+		if err != nil {
+			return nil, "", err
+		}
+		data = append(data, normalizedData...)
+	}
+
+	objs, err := objsFromYamlData(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return objs, computeHash(data), nil
+}
+
+// applyAddonObjects applies the objects of an addon to the Cluster using server-side apply, taking ownership of
+// the fields managed by other field managers.
+func applyAddonObjects(ctx context.Context, c client.Client, objs []unstructured.Unstructured) error {
+	errList := []error{}
+	for i := range objs {
+		obj := objs[i].DeepCopy()
+		if err := c.Patch(ctx, obj, client.Apply, client.FieldOwner(clusterAddonManagerName), client.ForceOwnership); err != nil {
+			errList = append(errList, errors.Wrapf(err, "applying object %s %s", obj.GroupVersionKind(), klog.KObj(obj)))
+		}
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// clusterAddonSetToClusterAddons is mapper function that maps ClusterAddonSets to their ClusterAddons.
+func (r *ClusterAddonReconciler) clusterAddonSetToClusterAddons(ctx context.Context, o client.Object) []ctrl.Request {
+	return r.listClusterAddons(ctx, o.GetNamespace(), client.MatchingLabels{addonsv1.ClusterAddonSetNameLabel: o.GetName()})
+}
+
+// clusterToClusterAddons is mapper function that maps Clusters to their ClusterAddons.
+func (r *ClusterAddonReconciler) clusterToClusterAddons(ctx context.Context, o client.Object) []ctrl.Request {
+	return r.listClusterAddons(ctx, o.GetNamespace(), client.MatchingLabels{clusterv1.ClusterNameLabel: o.GetName()})
+}
+
+// machineToClusterAddons is mapper function that maps Machines to the ClusterAddons of their Cluster.
+func (r *ClusterAddonReconciler) machineToClusterAddons(ctx context.Context, o client.Object) []ctrl.Request {
+	clusterName, ok := o.GetLabels()[clusterv1.ClusterNameLabel]
+	if !ok {
+		return nil
+	}
+	return r.listClusterAddons(ctx, o.GetNamespace(), client.MatchingLabels{clusterv1.ClusterNameLabel: clusterName})
+}
+
+// resourceToClusterAddons is mapper function that maps Secrets/ConfigMaps to the ClusterAddons of the
+// ClusterAddonSets referencing them.
+func (r *ClusterAddonReconciler) resourceToClusterAddons(ctx context.Context, o client.Object) []ctrl.Request {
+	addonSetList := &addonsv1.ClusterAddonSetList{}
+	if err := r.Client.List(ctx, addonSetList, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	result := []ctrl.Request{}
+	for i := range addonSetList.Items {
+		addonSet := &addonSetList.Items[i]
+		if addonSetReferencesResource(addonSet, o.GetName()) {
+			result = append(result, r.listClusterAddons(ctx, o.GetNamespace(), client.MatchingLabels{addonsv1.ClusterAddonSetNameLabel: addonSet.Name})...)
+		}
+	}
+	return result
+}
+
+// addonSetReferencesResource returns true if a version of the addon references a Secret/ConfigMap with the given name.
+// NOTE: The kind is not checked, because metadata-only objects might not have it.
+func addonSetReferencesResource(addonSet *addonsv1.ClusterAddonSet, name string) bool {
+	for _, v := range addonSet.Spec.Versions {
+		for _, resource := range v.Resources {
+			if resource.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *ClusterAddonReconciler) listClusterAddons(ctx context.Context, namespace string, selector client.MatchingLabels) []ctrl.Request {
+	addonList := &addonsv1.ClusterAddonList{}
+	if err := r.Client.List(ctx, addonList, client.InNamespace(namespace), selector); err != nil {
+		return nil
+	}
+
+	result := []ctrl.Request{}
+	for i := range addonList.Items {
+		result = append(result, ctrl.Request{NamespacedName: util.ObjectKey(&addonList.Items[i])})
+	}
+	return result
+}
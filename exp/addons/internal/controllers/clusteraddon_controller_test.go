@@ -0,0 +1,317 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestSelectAddonVersion(t *testing.T) {
+	versions := []addonsv1.AddonVersion{
+		{Version: "v3.26.1", KubernetesVersions: ">=1.27.0 <1.29.0"},
+		{Version: "v3.25.0", KubernetesVersions: ">=1.25.0 <1.28.0"},
+	}
+
+	tests := []struct {
+		name              string
+		versions          []addonsv1.AddonVersion
+		kubernetesVersion string
+		want              string
+		wantErr           bool
+	}{
+		{
+			name:              "selects the version supporting the Kubernetes version",
+			versions:          versions,
+			kubernetesVersion: "v1.28.3",
+			want:              "v3.26.1",
+		},
+		{
+			name:              "selects the first version supporting the Kubernetes version",
+			versions:          versions,
+			kubernetesVersion: "v1.27.0",
+			want:              "v3.26.1",
+		},
+		{
+			name:              "tolerates pre-release Kubernetes versions",
+			versions:          versions,
+			kubernetesVersion: "v1.26.0-rc.1",
+			want:              "v3.25.0",
+		},
+		{
+			name:              "returns nil if no version supports the Kubernetes version",
+			versions:          versions,
+			kubernetesVersion: "v1.29.0",
+			want:              "",
+		},
+		{
+			name:              "fails for invalid Kubernetes versions",
+			versions:          versions,
+			kubernetesVersion: "foo",
+			wantErr:           true,
+		},
+		{
+			name:              "fails for invalid ranges",
+			versions:          []addonsv1.AddonVersion{{Version: "v1.0.0", KubernetesVersions: "foo"}},
+			kubernetesVersion: "v1.28.0",
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := selectAddonVersion(tt.versions, tt.kubernetesVersion)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.want == "" {
+				g.Expect(got).To(BeNil())
+				return
+			}
+			g.Expect(got).ToNot(BeNil())
+			g.Expect(got.Version).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestOldestVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	got, err := oldestVersion([]string{"v1.28.3", "v1.27.7", "v1.28.0"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal("v1.27.7"))
+
+	_, err = oldestVersion([]string{"v1.28.3", "foo"})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestFailedVersionRetryAfter(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	failed := func(appliedTime time.Time) *addonsv1.AddonVersionStatus {
+		return &addonsv1.AddonVersionStatus{Version: "v2", Hash: "v2-hash", AppliedTime: &metav1.Time{Time: appliedTime}}
+	}
+
+	// The version failed when the readiness timeout expired, and it is retried after the retry interval.
+	g.Expect(failedVersionRetryAfter(failed(now.Add(-time.Minute)), time.Minute, now)).To(Equal(clusterAddonFailedRetryInterval))
+	g.Expect(failedVersionRetryAfter(failed(now.Add(-time.Minute-clusterAddonFailedRetryInterval)), time.Minute, now)).To(BeZero())
+	g.Expect(failedVersionRetryAfter(failed(now.Add(-time.Hour)), time.Minute, now)).To(BeZero())
+	g.Expect(failedVersionRetryAfter(&addonsv1.AddonVersionStatus{Version: "v2"}, time.Minute, now)).To(BeZero())
+}
+
+func TestClusterAddonGetKubernetesVersion(t *testing.T) {
+	controlPlane := builder.ControlPlane(metav1.NamespaceDefault, "cp").
+		WithSpecFields(map[string]interface{}{"spec.version": "v1.28.0"}).
+		WithStatusFields(map[string]interface{}{"status.version": "v1.27.3"}).
+		Build()
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: metav1.NamespaceDefault},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: controlPlane.GetAPIVersion(),
+				Kind:       controlPlane.GetKind(),
+				Name:       controlPlane.GetName(),
+				Namespace:  controlPlane.GetNamespace(),
+			},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "worker",
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: cluster.Name},
+		},
+		Spec: clusterv1.MachineSpec{ClusterName: cluster.Name, Version: pointer.String("v1.26.9")},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(controlPlane, machine).Build()
+	r := &ClusterAddonReconciler{Client: c}
+
+	tests := []struct {
+		phase addonsv1.AddonUpgradePhase
+		want  string
+	}{
+		{phase: addonsv1.AddonUpgradePhaseBeforeControlPlaneUpgrade, want: "v1.28.0"},
+		{phase: addonsv1.AddonUpgradePhaseAfterControlPlaneUpgrade, want: "v1.27.3"},
+		{phase: addonsv1.AddonUpgradePhaseAfterWorkersUpgrade, want: "v1.26.9"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.phase), func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := r.getKubernetesVersion(ctx, cluster, tt.phase)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+
+	t.Run("BeforeControlPlaneUpgrade uses the version of the topology", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterWithTopology := cluster.DeepCopy()
+		clusterWithTopology.Spec.Topology = &clusterv1.Topology{Version: "v1.29.0"}
+		got, err := r.getKubernetesVersion(ctx, clusterWithTopology, addonsv1.AddonUpgradePhaseBeforeControlPlaneUpgrade)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(Equal("v1.29.0"))
+	})
+}
+
+func TestClusterAddonReconcileTarget(t *testing.T) {
+	addonConfigMap := &unstructured.Unstructured{}
+	addonConfigMap.SetAPIVersion("v1")
+	addonConfigMap.SetKind("ConfigMap")
+	addonConfigMap.SetName("addon-config")
+	addonConfigMap.SetNamespace(metav1.NamespaceSystem)
+
+	previousResource := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "addon-v1", Namespace: metav1.NamespaceDefault},
+		Data: map[string]string{
+			"cm": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: addon-config\n  namespace: kube-system\n",
+		},
+	}
+	addonSet := &addonsv1.ClusterAddonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "addon", Namespace: metav1.NamespaceDefault},
+		Spec: addonsv1.ClusterAddonSetSpec{
+			ReadinessTimeout: &metav1.Duration{Duration: time.Minute},
+			Versions: []addonsv1.AddonVersion{
+				{Version: "v2", KubernetesVersions: ">=1.28.0", Resources: []addonsv1.ResourceRef{{Name: "addon-v2", Kind: "ConfigMap"}}},
+				{Version: "v1", KubernetesVersions: "<1.28.0", Resources: []addonsv1.ResourceRef{{Name: previousResource.Name, Kind: "ConfigMap"}}},
+			},
+		},
+	}
+
+	newAddon := func(appliedTime time.Time) *addonsv1.ClusterAddon {
+		return &addonsv1.ClusterAddon{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-addon", Namespace: metav1.NamespaceDefault},
+			Status: addonsv1.ClusterAddonStatus{
+				Current: &addonsv1.AddonVersionStatus{Version: "v1", KubernetesVersion: "v1.27.0", Hash: "v1-hash"},
+				Target:  &addonsv1.AddonVersionStatus{Version: "v2", KubernetesVersion: "v1.28.0", Hash: "v2-hash", AppliedTime: &metav1.Time{Time: appliedTime}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		appliedTime     time.Time
+		rollback        bool
+		existingObjs    []client.Object
+		wantCurrent     string
+		wantTarget      bool
+		wantFailed      bool
+		wantReason      string
+		wantRolledBack  bool
+		wantRequeue     bool
+		wantReadyStatus corev1.ConditionStatus
+	}{
+		{
+			name:            "the target version becomes current once ready",
+			appliedTime:     time.Now(),
+			existingObjs:    []client.Object{addonConfigMap.DeepCopy()},
+			wantCurrent:     "v2",
+			wantReadyStatus: corev1.ConditionTrue,
+		},
+		{
+			name:            "waits for the target version to be ready within the readiness timeout",
+			appliedTime:     time.Now(),
+			wantCurrent:     "v1",
+			wantTarget:      true,
+			wantReason:      addonsv1.UpgradingReason,
+			wantRequeue:     true,
+			wantReadyStatus: corev1.ConditionFalse,
+		},
+		{
+			name:            "marks the target version as failed after the readiness timeout",
+			appliedTime:     time.Now().Add(-2 * time.Minute),
+			wantCurrent:     "v1",
+			wantFailed:      true,
+			wantReason:      addonsv1.UpgradeFailedReason,
+			wantReadyStatus: corev1.ConditionFalse,
+		},
+		{
+			name:            "rolls back to the current version after the readiness timeout",
+			appliedTime:     time.Now().Add(-2 * time.Minute),
+			rollback:        true,
+			wantCurrent:     "v1",
+			wantFailed:      true,
+			wantReason:      addonsv1.RolledBackReason,
+			wantRolledBack:  true,
+			wantReadyStatus: corev1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			r := &ClusterAddonReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(previousResource).Build()}
+
+			// NOTE: The fake client does not support server-side apply, so applied objects are recorded instead.
+			applied := []string{}
+			remoteClient := fake.NewClientBuilder().WithObjects(tt.existingObjs...).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+					applied = append(applied, obj.GetName())
+					return nil
+				},
+			}).Build()
+
+			set := addonSet.DeepCopy()
+			set.Spec.Rollback = tt.rollback
+			addon := newAddon(tt.appliedTime)
+
+			res, err := r.reconcileTarget(ctx, remoteClient, set, addon, []unstructured.Unstructured{*addonConfigMap})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(res.RequeueAfter > 0).To(Equal(tt.wantRequeue))
+
+			g.Expect(addon.Status.Current.Version).To(Equal(tt.wantCurrent))
+			g.Expect(addon.Status.Target != nil).To(Equal(tt.wantTarget))
+			g.Expect(addon.Status.Failed != nil).To(Equal(tt.wantFailed))
+			g.Expect(conditions.Get(addon, clusterv1.ReadyCondition).Status).To(Equal(tt.wantReadyStatus))
+			if tt.wantReason != "" {
+				g.Expect(conditions.GetReason(addon, clusterv1.ReadyCondition)).To(Equal(tt.wantReason))
+			}
+			if tt.wantRolledBack {
+				g.Expect(applied).To(ConsistOf(addonConfigMap.GetName()))
+				g.Expect(addon.Status.Current.Hash).ToNot(Equal("v1-hash"))
+			} else {
+				g.Expect(applied).To(BeEmpty())
+			}
+		})
+	}
+}
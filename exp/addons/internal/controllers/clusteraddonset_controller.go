@@ -0,0 +1,274 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=addons.cluster.x-k8s.io,resources=clusteraddons/status;clusteraddonsets/status,verbs=get;update;patch
+
+// ClusterAddonSetReconciler reconciles a ClusterAddonSet object, creating a ClusterAddon for each matching Cluster.
+type ClusterAddonSetReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *ClusterAddonSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&addonsv1.ClusterAddonSet{}).
+		Owns(&addonsv1.ClusterAddon{}).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(r.clusterToClusterAddonSet),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	return nil
+}
+
+func (r *ClusterAddonSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	// Fetch the ClusterAddonSet instance.
+	addonSet := &addonsv1.ClusterAddonSet{}
+	if err := r.Client.Get(ctx, req.NamespacedName, addonSet); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Object not found, return. ClusterAddons are garbage collected using owner references.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	// ClusterAddons are garbage collected using owner references.
+	if !addonSet.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper.
+	patchHelper, err := patch.NewHelper(addonSet, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defer func() {
+		// Always attempt to Patch the ClusterAddonSet object and status after each reconciliation.
+		if err := patchHelper.Patch(ctx, addonSet, patch.WithStatusObservedGeneration{}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	return ctrl.Result{}, r.reconcileClusterAddons(ctx, addonSet)
+}
+
+// reconcileClusterAddons ensures a ClusterAddon exists for each Cluster matching the ClusterAddonSet, deletes the
+// ClusterAddons of the Clusters not matching anymore, and summarizes the Ready condition of the ClusterAddons.
+func (r *ClusterAddonSetReconciler) reconcileClusterAddons(ctx context.Context, addonSet *addonsv1.ClusterAddonSet) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	clusters, err := getClustersBySelector(ctx, r.Client, addonSet.Namespace, &addonSet.Spec.ClusterSelector)
+	if err != nil {
+		return err
+	}
+
+	addonList := &addonsv1.ClusterAddonList{}
+	if err := r.Client.List(ctx, addonList, client.InNamespace(addonSet.Namespace), client.MatchingLabels{addonsv1.ClusterAddonSetNameLabel: addonSet.Name}); err != nil {
+		return errors.Wrap(err, "failed to list ClusterAddons")
+	}
+	addonsByCluster := map[string]*addonsv1.ClusterAddon{}
+	for i := range addonList.Items {
+		addonsByCluster[addonList.Items[i].Spec.ClusterName] = &addonList.Items[i]
+	}
+
+	errList := []error{}
+	getters := []conditions.Getter{}
+	for _, cluster := range clusters {
+		addon, ok := addonsByCluster[cluster.Name]
+		delete(addonsByCluster, cluster.Name)
+		if !ok {
+			addon, err = r.createClusterAddon(ctx, cluster, addonSet)
+			if err != nil {
+				errList = append(errList, err)
+				continue
+			}
+		} else if addon.Spec.UpgradePhase != addonSet.Spec.UpgradePhase {
+			patchHelper, err := patch.NewHelper(addon, r.Client)
+			if err != nil {
+				errList = append(errList, err)
+				continue
+			}
+			addon.Spec.UpgradePhase = addonSet.Spec.UpgradePhase
+			if err := patchHelper.Patch(ctx, addon); err != nil {
+				errList = append(errList, errors.Wrapf(err, "failed to patch ClusterAddon %s", klog.KObj(addon)))
+				continue
+			}
+		}
+		getters = append(getters, addon)
+	}
+
+	// Delete the ClusterAddons of the Clusters not matching the ClusterAddonSet anymore.
+	// NOTE: The objects of the addon are not deleted from the Cluster.
+	for _, addon := range addonsByCluster {
+		log.Info("Deleting ClusterAddon because the Cluster does not match the ClusterAddonSet anymore", "ClusterAddon", klog.KObj(addon), "Cluster", addon.Spec.ClusterName)
+		if err := r.Client.Delete(ctx, addon); err != nil && !apierrors.IsNotFound(err) {
+			errList = append(errList, errors.Wrapf(err, "failed to delete ClusterAddon %s", klog.KObj(addon)))
+		}
+	}
+
+	if len(getters) == 0 {
+		conditions.MarkTrue(addonSet, addonsv1.AddonsReadyCondition)
+	} else {
+		conditions.SetAggregate(addonSet, addonsv1.AddonsReadyCondition, getters, conditions.AddSourceRef(), conditions.WithStepCounterIf(false))
+	}
+
+	return kerrors.NewAggregate(errList)
+}
+
+// createClusterAddon creates the ClusterAddon for a Cluster matching a ClusterAddonSet.
+func (r *ClusterAddonSetReconciler) createClusterAddon(ctx context.Context, cluster *clusterv1.Cluster, addonSet *addonsv1.ClusterAddonSet) (*addonsv1.ClusterAddon, error) {
+	addon := &addonsv1.ClusterAddon{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", cluster.Name, addonSet.Name),
+			Namespace: addonSet.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel:        cluster.Name,
+				addonsv1.ClusterAddonSetNameLabel: addonSet.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(addonSet, addonsv1.GroupVersion.WithKind("ClusterAddonSet")),
+				{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+					UID:        cluster.UID,
+				},
+			},
+		},
+		Spec: addonsv1.ClusterAddonSpec{
+			ClusterName:  cluster.Name,
+			AddonSetName: addonSet.Name,
+			UpgradePhase: addonSet.Spec.UpgradePhase,
+		},
+	}
+	if err := r.Client.Create(ctx, addon); err != nil {
+		return nil, errors.Wrapf(err, "failed to create ClusterAddon for Cluster %s", klog.KObj(cluster))
+	}
+	return addon, nil
+}
+
+// clusterToClusterAddonSet is mapper function that maps clusters to ClusterAddonSets.
+func (r *ClusterAddonSetReconciler) clusterToClusterAddonSet(ctx context.Context, o client.Object) []ctrl.Request {
+	cluster, ok := o.(*clusterv1.Cluster)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Cluster but got a %T", o))
+	}
+
+	addonSetList := &addonsv1.ClusterAddonSetList{}
+	if err := r.Client.List(ctx, addonSetList, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil
+	}
+
+	result := []ctrl.Request{}
+	clusterLabels := labels.Set(cluster.GetLabels())
+	for i := range addonSetList.Items {
+		addonSet := &addonSetList.Items[i]
+		if matchesClusterSelector(&addonSet.Spec.ClusterSelector, clusterLabels) {
+			result = append(result, ctrl.Request{NamespacedName: util.ObjectKey(addonSet)})
+		}
+	}
+
+	// Reconcile also the ClusterAddonSets the Cluster does not match anymore, so their ClusterAddons are deleted.
+	addonList := &addonsv1.ClusterAddonList{}
+	if err := r.Client.List(ctx, addonList, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return result
+	}
+	for i := range addonList.Items {
+		name := client.ObjectKey{Namespace: cluster.Namespace, Name: addonList.Items[i].Spec.AddonSetName}
+		if !containsRequest(result, name) {
+			result = append(result, ctrl.Request{NamespacedName: name})
+		}
+	}
+	return result
+}
+
+func containsRequest(requests []ctrl.Request, name client.ObjectKey) bool {
+	for _, req := range requests {
+		if req.NamespacedName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesClusterSelector returns true if the selector matches the labels; a nil or empty selector matches nothing.
+func matchesClusterSelector(clusterSelector *metav1.LabelSelector, clusterLabels labels.Set) bool {
+	selector, err := metav1.LabelSelectorAsSelector(clusterSelector)
+	if err != nil || selector.Empty() {
+		return false
+	}
+	return selector.Matches(clusterLabels)
+}
+
+// getClustersBySelector fetches the Clusters in a namespace matching a label selector, excluding the Clusters being deleted.
+// A nil or empty selector matches no Clusters.
+func getClustersBySelector(ctx context.Context, c client.Client, namespace string, clusterSelector *metav1.LabelSelector) ([]*clusterv1.Cluster, error) {
+	selector, err := metav1.LabelSelectorAsSelector(clusterSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to convert selector")
+	}
+	if selector.Empty() {
+		return nil, nil
+	}
+
+	clusterList := &clusterv1.ClusterList{}
+	if err := c.List(ctx, clusterList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, errors.Wrap(err, "failed to list clusters")
+	}
+
+	clusters := []*clusterv1.Cluster{}
+	for i := range clusterList.Items {
+		if clusterList.Items[i].DeletionTimestamp.IsZero() {
+			clusters = append(clusters, &clusterList.Items[i])
+		}
+	}
+	return clusters, nil
+}
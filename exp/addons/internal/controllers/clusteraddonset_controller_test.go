@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestClusterAddonSetReconcileClusterAddons(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = addonsv1.AddToScheme(scheme)
+
+	matchingCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "matching", Namespace: metav1.NamespaceDefault, UID: "matching-uid", Labels: map[string]string{"cni": "calico"}},
+	}
+	otherCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: metav1.NamespaceDefault, UID: "other-uid"},
+	}
+	addonSet := &addonsv1.ClusterAddonSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: addonsv1.GroupVersion.String(), Kind: "ClusterAddonSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: "calico", Namespace: metav1.NamespaceDefault, UID: "calico-uid"},
+		Spec: addonsv1.ClusterAddonSetSpec{
+			ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"cni": "calico"}},
+			UpgradePhase:    addonsv1.AddonUpgradePhaseBeforeControlPlaneUpgrade,
+		},
+	}
+	// The ClusterAddon of a Cluster which does not match the ClusterAddonSet anymore.
+	staleAddon := &addonsv1.ClusterAddon{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-calico",
+			Namespace: metav1.NamespaceDefault,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel:        otherCluster.Name,
+				addonsv1.ClusterAddonSetNameLabel: addonSet.Name,
+			},
+		},
+		Spec: addonsv1.ClusterAddonSpec{ClusterName: otherCluster.Name, AddonSetName: addonSet.Name},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(matchingCluster, otherCluster, addonSet, staleAddon).WithStatusSubresource(&addonsv1.ClusterAddon{}).Build()
+	r := &ClusterAddonSetReconciler{Client: c}
+
+	g.Expect(r.reconcileClusterAddons(ctx, addonSet)).To(Succeed())
+
+	addonList := &addonsv1.ClusterAddonList{}
+	g.Expect(c.List(ctx, addonList, client.InNamespace(metav1.NamespaceDefault))).To(Succeed())
+	g.Expect(addonList.Items).To(HaveLen(1))
+	addon := addonList.Items[0]
+	g.Expect(addon.Name).To(Equal("matching-calico"))
+	g.Expect(addon.Spec.ClusterName).To(Equal(matchingCluster.Name))
+	g.Expect(addon.Spec.AddonSetName).To(Equal(addonSet.Name))
+	g.Expect(addon.Spec.UpgradePhase).To(Equal(addonsv1.AddonUpgradePhaseBeforeControlPlaneUpgrade))
+	g.Expect(addon.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, matchingCluster.Name))
+	g.Expect(addon.Labels).To(HaveKeyWithValue(addonsv1.ClusterAddonSetNameLabel, addonSet.Name))
+	g.Expect(addon.OwnerReferences).To(HaveLen(2))
+
+	// The ClusterAddon did not report its readiness yet, so the readiness of the ClusterAddonSet is unknown.
+	g.Expect(conditions.Has(addonSet, addonsv1.AddonsReadyCondition)).To(BeFalse())
+
+	// Changing the upgrade phase of the ClusterAddonSet is propagated to the ClusterAddons.
+	addonSet.Spec.UpgradePhase = addonsv1.AddonUpgradePhaseAfterWorkersUpgrade
+	conditions.MarkTrue(&addon, clusterv1.ReadyCondition)
+	g.Expect(c.Status().Update(ctx, &addon)).To(Succeed())
+	g.Expect(r.reconcileClusterAddons(ctx, addonSet)).To(Succeed())
+
+	updated := &addonsv1.ClusterAddon{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(&addon), updated)).To(Succeed())
+	g.Expect(updated.Spec.UpgradePhase).To(Equal(addonsv1.AddonUpgradePhaseAfterWorkersUpgrade))
+	g.Expect(conditions.Get(addonSet, addonsv1.AddonsReadyCondition).Status).To(Equal(corev1.ConditionTrue))
+}
@@ -42,6 +42,8 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 	resourcepredicates "sigs.k8s.io/cluster-api/exp/addons/internal/controllers/predicates"
+	"sigs.k8s.io/cluster-api/exp/addons/internal/helm"
+	"sigs.k8s.io/cluster-api/exp/addons/internal/oci"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
@@ -54,7 +56,7 @@ var ErrSecretTypeNotSupported = errors.New("unsupported secret type")
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=addons.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=addons.cluster.x-k8s.io,resources=clusterresourcesets/status;clusterresourcesets/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=addons.cluster.x-k8s.io,resources=clusterresourcesets/status;clusterresourcesets/finalizers;clusterresourcesetbindings/status,verbs=get;update;patch
 
 // ClusterResourceSetReconciler reconciles a ClusterResourceSet object.
 type ClusterResourceSetReconciler struct {
@@ -63,9 +65,47 @@ type ClusterResourceSetReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// DriftDetectionInterval is the interval at which ClusterResourceSets with the Reconcile strategy
+	// re-apply their resources to the matching clusters, correcting drift; zero disables periodic drift detection.
+	DriftDetectionInterval time.Duration
+
+	// HelmRepositoryAllowList is the list of the URLs of the repositories Helm charts can be fetched from;
+	// if empty, charts can be fetched from any repository.
+	HelmRepositoryAllowList []string
+
+	// RegistryAllowList is the list of the OCI registries artifacts can be fetched from, e.g. registry.example.com
+	// or registry.example.com/addons; if empty, artifacts can be fetched from any registry.
+	RegistryAllowList []string
+
+	// RegistryCredentialsFile is the path of a Docker config file with the credentials to access private OCI registries,
+	// for both OCI artifacts and Helm charts; if empty, registries are accessed anonymously.
+	RegistryCredentialsFile string
+
+	ociArtifactGetter *oci.ArtifactGetter
+	helmChartGetter   *helm.ChartGetter
 }
 
 func (r *ClusterResourceSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	var registryCredential oci.CredentialFunc
+	if r.RegistryCredentialsFile != "" {
+		registryCredential = oci.DockerConfigCredential(r.RegistryCredentialsFile)
+	}
+	if r.ociArtifactGetter == nil {
+		r.ociArtifactGetter = &oci.ArtifactGetter{
+			Client: &oci.Client{
+				AllowedRegistries: r.RegistryAllowList,
+				Credential:        registryCredential,
+			},
+		}
+	}
+	if r.helmChartGetter == nil {
+		r.helmChartGetter = &helm.ChartGetter{
+			AllowedRepositories: r.HelmRepositoryAllowList,
+			RegistryCredential:  registryCredential,
+		}
+	}
+
 	err := ctrl.NewControllerManagedBy(mgr).
 		For(&addonsv1.ClusterResourceSet{}).
 		Watches(
@@ -145,19 +185,42 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	errs := []error{}
 	errClusterLockedOccurred := false
+	errDependenciesNotReadyOccurred := false
+	// Keep track of the ResourcesApplied condition reported for each cluster, so the condition of the
+	// ClusterResourceSet summarizes the outcome for all the clusters instead of only the last one.
+	previousCondition := conditions.Get(clusterResourceSet, addonsv1.ResourcesAppliedCondition)
+	clusterConditions := []clusterCondition{}
 	for _, cluster := range clusters {
-		if err := r.ApplyClusterResourceSet(ctx, cluster, clusterResourceSet); err != nil {
+		conditions.Delete(clusterResourceSet, addonsv1.ResourcesAppliedCondition)
+		err := r.ApplyClusterResourceSet(ctx, cluster, clusterResourceSet)
+		if !errors.Is(err, remote.ErrClusterLocked) {
+			condition := conditions.Get(clusterResourceSet, addonsv1.ResourcesAppliedCondition)
+			if condition == nil {
+				if err != nil {
+					condition = conditions.FalseCondition(addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+				} else {
+					condition = conditions.TrueCondition(addonsv1.ResourcesAppliedCondition)
+				}
+			}
+			clusterConditions = append(clusterConditions, clusterCondition{cluster: cluster.Name, condition: condition})
+		}
+		if err != nil {
 			// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
 			// the current cluster because of concurrent access.
 			if errors.Is(err, remote.ErrClusterLocked) {
 				log.V(5).Info("Requeuing because another worker has the lock on the ClusterCacheTracker")
 				errClusterLockedOccurred = true
+			} else if errors.Is(err, ErrDependenciesNotReady) {
+				// Requeue if the ClusterResourceSet, or some of its resources, are waiting for their dependencies.
+				log.V(4).Info("Requeuing because dependencies are not ready", "Cluster", klog.KObj(cluster), "reason", err.Error())
+				errDependenciesNotReadyOccurred = true
 			} else {
 				// Append the error if the error is not ErrClusterLocked.
 				errs = append(errs, err)
 			}
 		}
 	}
+	setResourcesAppliedCondition(clusterResourceSet, previousCondition, clusterConditions)
 
 	// Return an aggregated error if errors occurred.
 	if len(errs) > 0 {
@@ -169,6 +232,16 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Requeue to check the dependencies again if ErrDependenciesNotReady was returned for one of the clusters.
+	if errDependenciesNotReadyOccurred {
+		return ctrl.Result{RequeueAfter: dependenciesRequeueInterval}, nil
+	}
+
+	// Requeue to detect drift on the objects applied to the clusters, if required by the strategy.
+	if clusterResourceSet.Spec.Strategy == string(addonsv1.ClusterResourceSetStrategyReconcile) && r.DriftDetectionInterval > 0 {
+		return ctrl.Result{RequeueAfter: r.DriftDetectionInterval}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -254,7 +327,14 @@ func (r *ClusterResourceSetReconciler) getClustersByClusterResourceSetSelector(c
 // In ApplyOnce strategy, resources are applied only once to a particular cluster. ClusterResourceSetBinding is used to check if a resource is applied before.
 // It applies resources best effort and continue on scenarios like: unsupported resource types, failure during creation, missing resources.
 // In Reconcile strategy, resources are re-applied to a particular cluster when their definition changes. The hash in ClusterResourceSetBinding is used to check
-// if a resource has changed or not.
+// if a resource has changed or not. Unchanged resources are re-applied as well, to detect and correct drift on the objects in the cluster, and objects
+// removed from a resource are deleted from the cluster; the outcome is reported by the ResourcesInSync condition in the ClusterResourceSetBinding status.
+// If templating is enabled, resources and OCI artifacts are rendered for the cluster before being applied.
+// The objects in OCI artifacts are applied after the resources, and Helm charts are rendered for the cluster and the resulting
+// objects are applied last, following the same strategy.
+// Resources with dependencies are applied after the resources they depend on, once their objects are ready, and the
+// ClusterResourceSet is applied only once the ClusterResourceSets it depends on are applied and ready; an error wrapping
+// ErrDependenciesNotReady is returned while waiting for dependencies.
 // TODO: If a resource already exists in the cluster but not applied by ClusterResourceSet, the resource will be updated ?
 func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet) error {
 	log := ctrl.LoggerFrom(ctx, "Cluster", klog.KObj(cluster))
@@ -299,17 +379,51 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 		Name:       clusterResourceSet.Name,
 		UID:        clusterResourceSet.UID,
 	}))
+
+	// Wait for the ClusterResourceSets this ClusterResourceSet depends on to be applied and ready.
+	if err := r.checkClusterResourceSetDependencies(ctx, remoteClient, clusterResourceSet, clusterResourceSetBinding); err != nil {
+		if errors.Is(err, ErrDependenciesNotReady) {
+			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.WaitingForDependenciesReason, clusterv1.ConditionSeverityInfo, err.Error())
+		}
+		return err
+	}
+
 	errList := []error{}
 	resourceSetBinding := clusterResourceSetBinding.GetOrCreateBinding(clusterResourceSet)
 
-	// Iterate all resources and apply them to the cluster and update the resource status in the ClusterResourceSetBinding object.
+	// Keep track of the outcome of applying each resource and, if supported by the strategy, of drift corrected
+	// and of errors while keeping objects in sync.
+	resourceSetStatus := clusterResourceSetBinding.GetOrCreateResourceSetStatus(clusterResourceSet)
+	resourceRefs := getResourceRefs(clusterResourceSet)
+	resourceSetStatus.Resources = filterResourceStatuses(resourceSetStatus.Resources, resourceRefs)
+	isReconcileStrategy := clusterResourceSet.Spec.Strategy == string(addonsv1.ClusterResourceSetStrategyReconcile)
+	if isReconcileStrategy {
+		resourceSetStatus.Objects = filterAppliedObjects(resourceSetStatus.Objects, resourceRefs)
+	}
+	drifted := []string{}
+	driftErrList := []error{}
+	pruneErrList := []error{}
+
+	// Get the values used to render the resources and the OCI artifacts for the cluster, if templating is enabled.
+	var templateData map[string]interface{}
+	if clusterResourceSet.Spec.TemplateResources {
+		templateData, err = getTemplateData(cluster)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Iterate all resources and get the scopes to apply their objects to the cluster.
+	resourceScopes := []resourceReconcileScope{}
 	for _, resource := range clusterResourceSet.Spec.Resources {
 		unstructuredObj, err := r.getResource(ctx, resource, cluster.GetNamespace())
 		if err != nil {
 			if err == ErrSecretTypeNotSupported {
 				conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.WrongSecretTypeReason, clusterv1.ConditionSeverityWarning, err.Error())
+				setResourceNotApplied(cluster, clusterResourceSet, resourceSetStatus, resource, addonsv1.WrongSecretTypeReason, err)
 			} else {
 				conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.RetrievingResourceFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+				setResourceNotApplied(cluster, clusterResourceSet, resourceSetStatus, resource, addonsv1.RetrievingResourceFailedReason, err)
 
 				// Continue without adding the error to the aggregate if we can't find the resource.
 				if apierrors.IsNotFound(err) {
@@ -327,7 +441,7 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			errList = append(errList, err)
 		}
 
-		resourceScope, err := reconcileScopeForResource(clusterResourceSet, resource, resourceSetBinding, unstructuredObj)
+		resourceScope, err := reconcileScopeForResource(clusterResourceSet, resource, resourceSetBinding, unstructuredObj, templateData)
 		if err != nil {
 			resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
 				ResourceRef:     resource,
@@ -335,15 +449,105 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 				Applied:         false,
 				LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
 			})
+			setResourceNotApplied(cluster, clusterResourceSet, resourceSetStatus, resource, addonsv1.ApplyFailedReason, err)
+
+			errList = append(errList, err)
+			continue
+		}
+
+		resourceScopes = append(resourceScopes, resourceScope)
+	}
+
+	// Iterate all OCI artifacts and get the scopes to apply their objects to the cluster, after the resources.
+	for _, ociArtifact := range clusterResourceSet.Spec.OCIArtifacts {
+		resourceScope, err := r.reconcileScopeForOCIArtifact(ctx, clusterResourceSet, ociArtifact, resourceSetBinding, templateData)
+		if err != nil {
+			log.Error(err, "failed to get ClusterResourceSet OCI artifact", "OCI artifact", ociArtifact.Name)
+			reason := addonsv1.RetrievingOCIArtifactFailedReason
+			if errors.Is(err, oci.ErrSignatureVerificationFailed) {
+				reason = addonsv1.OCIArtifactVerificationFailedReason
+			}
+			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, reason, clusterv1.ConditionSeverityWarning, err.Error())
+			setResourceNotApplied(cluster, clusterResourceSet, resourceSetStatus, ociArtifact.ResourceRef(), reason, err)
+			errList = append(errList, err)
+			continue
+		}
+
+		resourceScopes = append(resourceScopes, resourceScope)
+	}
 
+	// Iterate all Helm charts and render them for the cluster, after the resources and the OCI artifacts.
+	for _, helmChart := range clusterResourceSet.Spec.HelmCharts {
+		resourceScope, err := r.reconcileScopeForHelmChart(ctx, remoteClient, cluster, clusterResourceSet, helmChart, resourceSetBinding)
+		if err != nil {
+			log.Error(err, "failed to render ClusterResourceSet Helm chart", "Helm chart", helmChart.Name)
+			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.HelmChartRenderFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			setResourceNotApplied(cluster, clusterResourceSet, resourceSetStatus, helmChart.ResourceRef(), addonsv1.HelmChartRenderFailedReason, err)
 			errList = append(errList, err)
 			continue
 		}
 
+		resourceScopes = append(resourceScopes, resourceScope)
+	}
+
+	// Sort the scopes so that resources are applied after the resources they depend on.
+	resourceScopes = sortResourceScopes(resourceScopes, clusterResourceSet.Spec.ResourceDependencies)
+	scopesByResource := map[addonsv1.ResourceRef]resourceReconcileScope{}
+	for _, resourceScope := range resourceScopes {
+		scopesByResource[resourceScope.resource()] = resourceScope
+	}
+	waitingErrList := []error{}
+
+	// Iterate all scopes and apply the objects to the cluster and update the resource status in the ClusterResourceSetBinding object.
+	for _, resourceScope := range resourceScopes {
+		resource := resourceScope.resource()
+		driftScope, syncObjects := resourceScope.(driftReconcileScope)
 		if !resourceScope.needsApply() {
+			if !syncObjects {
+				continue
+			}
+
+			// Re-apply the objects to correct drift.
+			changed, err := driftScope.correctDrift(ctx, remoteClient)
+			drifted = append(drifted, changed...)
+			if err != nil {
+				log.Error(err, "failed to correct drift for ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name)
+				driftErrList = append(driftErrList, err)
+				errList = append(errList, err)
+				setResourceNotApplied(cluster, clusterResourceSet, resourceSetStatus, resource, addonsv1.DriftCorrectionFailedReason, err)
+				continue
+			}
+
+			// Retry pruning objects if it failed in a previous reconcile.
+			if err := pruneObjects(ctx, remoteClient, resource, driftScope, resourceSetStatus); err != nil {
+				log.Error(err, "failed to prune objects removed from ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name)
+				pruneErrList = append(pruneErrList, err)
+				errList = append(errList, err)
+				setResourceNotApplied(cluster, clusterResourceSet, resourceSetStatus, resource, addonsv1.PruneFailedReason, err)
+				continue
+			}
+
+			// Clear errors reported in a previous reconcile, once the objects are in sync again.
+			if resourceStatus := resourceSetStatus.GetResourceStatus(resource); resourceStatus != nil && resourceStatus.Reason != "" {
+				setResourceApplied(cluster, clusterResourceSet, resourceSetStatus, resource, resourceScope.hash())
+			}
 			continue
 		}
 
+		// Wait for the resources this resource depends on to be applied and ready.
+		if dependsOn := getResourceDependencies(clusterResourceSet, resource); len(dependsOn) > 0 {
+			if err := checkResourceDependencies(ctx, remoteClient, resource, dependsOn, scopesByResource, resourceSetBinding); err != nil {
+				if errors.Is(err, ErrDependenciesNotReady) {
+					waitingErrList = append(waitingErrList, err)
+					setResourceNotApplied(cluster, clusterResourceSet, resourceSetStatus, resource, addonsv1.WaitingForDependenciesReason, err)
+				} else {
+					errList = append(errList, err)
+					setResourceNotApplied(cluster, clusterResourceSet, resourceSetStatus, resource, addonsv1.ApplyFailedReason, err)
+				}
+				continue
+			}
+		}
+
 		// Set status in ClusterResourceSetBinding in case of early continue due to a failure.
 		// Set only when resource is retrieved successfully.
 		resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
@@ -361,6 +565,9 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			log.Error(err, "failed to apply ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name)
 			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 			errList = append(errList, err)
+			setResourceNotApplied(cluster, clusterResourceSet, resourceSetStatus, resource, addonsv1.ApplyFailedReason, err)
+		} else {
+			setResourceApplied(cluster, clusterResourceSet, resourceSetStatus, resource, resourceScope.hash())
 		}
 
 		resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
@@ -369,11 +576,32 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			Applied:         isSuccessful,
 			LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
 		})
+
+		// Delete the objects removed from the resource, once the new definition has been applied.
+		if syncObjects && isSuccessful {
+			if err := pruneObjects(ctx, remoteClient, resource, driftScope, resourceSetStatus); err != nil {
+				log.Error(err, "failed to prune objects removed from ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name)
+				pruneErrList = append(pruneErrList, err)
+				errList = append(errList, err)
+				setResourceNotApplied(cluster, clusterResourceSet, resourceSetStatus, resource, addonsv1.PruneFailedReason, err)
+			}
+		}
 	}
+
+	if isReconcileStrategy {
+		setResourcesInSyncCondition(resourceSetStatus, drifted, driftErrList, pruneErrList)
+	}
+
 	if len(errList) > 0 {
 		return kerrors.NewAggregate(errList)
 	}
 
+	if len(waitingErrList) > 0 {
+		err := kerrors.NewAggregate(waitingErrList)
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.WaitingForDependenciesReason, clusterv1.ConditionSeverityInfo, err.Error())
+		return err
+	}
+
 	conditions.MarkTrue(clusterResourceSet, addonsv1.ResourcesAppliedCondition)
 
 	return nil
@@ -501,15 +729,36 @@ func (r *ClusterResourceSetReconciler) resourceToClusterResourceSet(ctx context.
 	if err != nil {
 		return nil
 	}
-	for _, crs := range crsList.Items {
-		for _, resource := range crs.Spec.Resources {
-			if resource.Kind == objKind.Kind && resource.Name == o.GetName() {
-				name := client.ObjectKey{Namespace: o.GetNamespace(), Name: crs.Name}
-				result = append(result, ctrl.Request{NamespacedName: name})
-				break
-			}
+	for i := range crsList.Items {
+		crs := &crsList.Items[i]
+		if referencesResource(crs, objKind.Kind, o.GetName()) {
+			name := client.ObjectKey{Namespace: o.GetNamespace(), Name: crs.Name}
+			result = append(result, ctrl.Request{NamespacedName: name})
 		}
 	}
 
 	return result
 }
+
+// referencesResource returns true if a ClusterResourceSet references a Secret/ConfigMap in its resources,
+// as public keys to verify its OCI artifacts, or as values of its Helm charts.
+func referencesResource(crs *addonsv1.ClusterResourceSet, kind, name string) bool {
+	for _, resource := range crs.Spec.Resources {
+		if resource.Kind == kind && resource.Name == name {
+			return true
+		}
+	}
+	for _, ociArtifact := range crs.Spec.OCIArtifacts {
+		if ociArtifact.Verify != nil && kind == string(addonsv1.SecretClusterResourceSetResourceKind) && ociArtifact.Verify.SecretRef == name {
+			return true
+		}
+	}
+	for _, helmChart := range crs.Spec.HelmCharts {
+		for _, valuesRef := range helmChart.ValuesFrom {
+			if valuesRef.Kind == kind && valuesRef.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
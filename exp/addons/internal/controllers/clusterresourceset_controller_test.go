@@ -722,6 +722,189 @@ metadata:
 		g.Eventually(configMapHasBeenUpdated(env, resourceConfigMap2Key, resourceConfigMap2), timeout).Should(Succeed())
 	})
 
+	t.Run("Should correct drift and prune objects removed from the resources of a ClusterResourceSet with Reconcile strategy", func(t *testing.T) {
+		g := NewWithT(t)
+		ns := setup(t, g)
+		defer teardown(t, g, ns)
+
+		t.Log("Updating the cluster with labels")
+		testCluster.SetLabels(labels)
+		g.Expect(env.Update(ctx, testCluster)).To(Succeed())
+
+		t.Log("Creating a ClusterResourceSet instance that has same labels as selector")
+		clusterResourceSet := &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterResourceSetName,
+				Namespace: ns.Name,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				Strategy: string(addonsv1.ClusterResourceSetStrategyReconcile),
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: labels,
+				},
+				Resources: []addonsv1.ResourceRef{{Name: configmapName, Kind: "ConfigMap"}},
+			},
+		}
+		g.Expect(env.Create(ctx, clusterResourceSet)).To(Succeed())
+
+		clusterResourceSetBindingKey := client.ObjectKey{
+			Namespace: testCluster.Namespace,
+			Name:      testCluster.Name,
+		}
+		resourceConfigMap1Key := client.ObjectKey{
+			Namespace: resourceConfigMapsNamespace,
+			Name:      resourceConfigMap1Name,
+		}
+		resourceConfigMap2Key := client.ObjectKey{
+			Namespace: resourceConfigMapsNamespace,
+			Name:      resourceConfigMap2Name,
+		}
+
+		t.Log("Verifying resource ConfigMap 1 has been created and recorded in the ClusterResourceSetBinding")
+		g.Eventually(func() error {
+			return env.Get(ctx, resourceConfigMap1Key, &corev1.ConfigMap{})
+		}, timeout).Should(Succeed())
+		g.Eventually(func(g Gomega) {
+			binding := &addonsv1.ClusterResourceSetBinding{}
+			g.Expect(env.Get(ctx, clusterResourceSetBindingKey, binding)).To(Succeed())
+			g.Expect(binding.Status.ResourceSets).To(HaveLen(1))
+			g.Expect(binding.Status.ResourceSets[0].Objects).To(ConsistOf(HaveField("Name", resourceConfigMap1Name)))
+			g.Expect(binding.Status.ResourceSets[0].Conditions).To(ContainElement(And(
+				HaveField("Type", addonsv1.ResourcesInSyncCondition),
+				HaveField("Status", corev1.ConditionTrue),
+			)))
+		}, timeout).Should(Succeed())
+
+		t.Log("Deleting resource ConfigMap 1 from the cluster")
+		g.Expect(env.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceConfigMap1Name,
+			Namespace: resourceConfigMapsNamespace,
+		}})).To(Succeed())
+
+		t.Log("Verifying resource ConfigMap 1 has been re-created")
+		g.Eventually(func() error {
+			return env.Get(ctx, resourceConfigMap1Key, &corev1.ConfigMap{})
+		}, timeout).Should(Succeed())
+
+		resourceConfigMap2 := configMap(
+			resourceConfigMap2Name,
+			resourceConfigMapsNamespace,
+			map[string]string{
+				"my_new_config": "some_value",
+			},
+		)
+		resourceConfigMap2Content, err := yaml.Marshal(resourceConfigMap2)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		t.Log("Replacing resource ConfigMap 1 with resource ConfigMap 2 in the ConfigMap data field")
+		g.Expect(env.Update(ctx, configMap(
+			configmapName,
+			ns.Name,
+			map[string]string{
+				"cm": string(resourceConfigMap2Content),
+			},
+		))).To(Succeed())
+
+		t.Log("Verifying resource ConfigMap 2 has been created and resource ConfigMap 1 has been deleted")
+		g.Eventually(configMapHasBeenUpdated(env, resourceConfigMap2Key, resourceConfigMap2), timeout).Should(Succeed())
+		g.Eventually(func() bool {
+			return apierrors.IsNotFound(env.Get(ctx, resourceConfigMap1Key, &corev1.ConfigMap{}))
+		}, timeout).Should(BeTrue())
+		g.Eventually(func(g Gomega) {
+			binding := &addonsv1.ClusterResourceSetBinding{}
+			g.Expect(env.Get(ctx, clusterResourceSetBindingKey, binding)).To(Succeed())
+			g.Expect(binding.Status.ResourceSets).To(HaveLen(1))
+			g.Expect(binding.Status.ResourceSets[0].Objects).To(ConsistOf(HaveField("Name", resourceConfigMap2Name)))
+		}, timeout).Should(Succeed())
+	})
+
+	t.Run("Should apply the objects rendered from a Helm chart of a ClusterResourceSet", func(t *testing.T) {
+		g := NewWithT(t)
+		ns := setup(t, g)
+		defer teardown(t, g, ns)
+
+		t.Log("Serving a Helm chart with a ConfigMap template")
+		repository := newHelmChartRepository(t, "test", "1.0.0", map[string]string{
+			"Chart.yaml":  "apiVersion: v2\nname: test\nversion: 1.0.0\n",
+			"values.yaml": "name: foo\ncluster: unknown\n",
+			"templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Values.name }}
+data:
+  cluster: {{ .Values.cluster }}
+  release: {{ .Release.Name }}
+`,
+		})
+		defer repository.Close()
+
+		t.Log("Creating a ConfigMap with values for the Helm chart")
+		g.Expect(env.Create(ctx, configMap("helm-values", ns.Name, map[string]string{
+			"values.yaml": fmt.Sprintf("name: %s\n", resourceConfigMap1Name),
+		}))).To(Succeed())
+
+		t.Log("Updating the cluster with labels")
+		testCluster.SetLabels(labels)
+		g.Expect(env.Update(ctx, testCluster)).To(Succeed())
+
+		t.Log("Creating a ClusterResourceSet instance with a Helm chart")
+		clusterResourceSet := &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterResourceSetName,
+				Namespace: ns.Name,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				Strategy: string(addonsv1.ClusterResourceSetStrategyReconcile),
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: labels,
+				},
+				HelmCharts: []addonsv1.HelmChartSource{{
+					Name:             "test",
+					RepoURL:          repository.URL,
+					Chart:            "test",
+					Version:          "1.0.0",
+					ReleaseName:      "release",
+					ReleaseNamespace: resourceConfigMapsNamespace,
+					ValuesFrom: []addonsv1.HelmValuesReference{{
+						Name: "helm-values",
+						Kind: "ConfigMap",
+						Key:  "values.yaml",
+					}},
+					ValuesTemplate: "cluster: {{ .Cluster.metadata.name }}",
+				}},
+			},
+		}
+		g.Expect(env.Create(ctx, clusterResourceSet)).To(Succeed())
+
+		t.Log("Verifying the ConfigMap rendered from the Helm chart has been created")
+		g.Eventually(configMapHasBeenUpdated(env, client.ObjectKey{Namespace: resourceConfigMapsNamespace, Name: resourceConfigMap1Name}, configMap(
+			resourceConfigMap1Name,
+			resourceConfigMapsNamespace,
+			map[string]string{
+				"cluster": testCluster.Name,
+				"release": "release",
+			},
+		)), timeout).Should(Succeed())
+
+		t.Log("Verifying the Helm chart has been recorded in the ClusterResourceSetBinding")
+		g.Eventually(func(g Gomega) {
+			binding := &addonsv1.ClusterResourceSetBinding{}
+			g.Expect(env.Get(ctx, client.ObjectKeyFromObject(testCluster), binding)).To(Succeed())
+			g.Expect(binding.Spec.Bindings).To(HaveLen(1))
+			g.Expect(binding.Spec.Bindings[0].Resources).To(ConsistOf(And(
+				HaveField("ResourceRef", addonsv1.ResourceRef{Name: "test", Kind: "HelmChart"}),
+				HaveField("Applied", true),
+			)))
+		}, timeout).Should(Succeed())
+
+		t.Log("Verifying the ClusterResourceSet is an owner of the ConfigMap with values")
+		g.Eventually(func(g Gomega) {
+			values := &corev1.ConfigMap{}
+			g.Expect(env.Get(ctx, client.ObjectKey{Namespace: ns.Name, Name: "helm-values"}, values)).To(Succeed())
+			g.Expect(values.OwnerReferences).To(ContainElement(HaveField("Name", clusterResourceSetName)))
+		}, timeout).Should(Succeed())
+	})
+
 	t.Run("Should reconcile a ClusterResourceSet with ApplyOnce strategy even when one of the resources already exist", func(t *testing.T) {
 		g := NewWithT(t)
 		ns := setup(t, g)
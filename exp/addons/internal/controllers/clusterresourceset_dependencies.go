@@ -0,0 +1,229 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+// dependenciesRequeueInterval is the interval at which a ClusterResourceSet is reconciled
+// while waiting for its dependencies, or for the dependencies of one of its resources, to be ready.
+const dependenciesRequeueInterval = 10 * time.Second
+
+// ErrDependenciesNotReady signals that the dependencies of a ClusterResourceSet, or of one of its resources,
+// are not applied or not ready yet.
+var ErrDependenciesNotReady = errors.New("dependencies not ready")
+
+var (
+	customResourceDefinitionGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	deploymentGroupKind               = schema.GroupKind{Group: "apps", Kind: "Deployment"}
+)
+
+// sortResourceScopes returns the scopes sorted so that each resource comes after the resources it depends on,
+// preserving the order of the resources otherwise.
+// NOTE: Cycles are rejected by the validation webhook; the resources in a cycle are left in their order.
+func sortResourceScopes(scopes []resourceReconcileScope, dependencies []addonsv1.ResourceDependency) []resourceReconcileScope {
+	dependsOn := map[addonsv1.ResourceRef][]addonsv1.ResourceRef{}
+	for _, dependency := range dependencies {
+		dependsOn[dependency.Resource] = append(dependsOn[dependency.Resource], dependency.DependsOn...)
+	}
+	inScopes := map[addonsv1.ResourceRef]bool{}
+	for _, scope := range scopes {
+		inScopes[scope.resource()] = true
+	}
+
+	sorted := make([]resourceReconcileScope, 0, len(scopes))
+	added := map[addonsv1.ResourceRef]bool{}
+	for len(sorted) < len(scopes) {
+		progress := false
+		for _, scope := range scopes {
+			resource := scope.resource()
+			if added[resource] {
+				continue
+			}
+			ready := true
+			for _, dependency := range dependsOn[resource] {
+				if inScopes[dependency] && !added[dependency] && dependency != resource {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				continue
+			}
+			sorted = append(sorted, scope)
+			added[resource] = true
+			progress = true
+			// Restart from the first resource, to preserve the order of the resources as much as possible.
+			break
+		}
+		if !progress {
+			for _, scope := range scopes {
+				if !added[scope.resource()] {
+					sorted = append(sorted, scope)
+					added[scope.resource()] = true
+				}
+			}
+		}
+	}
+	return sorted
+}
+
+// getResourceDependencies returns the resources a resource of a ClusterResourceSet depends on.
+func getResourceDependencies(crs *addonsv1.ClusterResourceSet, resource addonsv1.ResourceRef) []addonsv1.ResourceRef {
+	dependsOn := []addonsv1.ResourceRef{}
+	for _, dependency := range crs.Spec.ResourceDependencies {
+		if dependency.Resource == resource {
+			dependsOn = append(dependsOn, dependency.DependsOn...)
+		}
+	}
+	return dependsOn
+}
+
+// checkResourceDependencies returns an error wrapping ErrDependenciesNotReady if any of the resources a resource
+// depends on is not applied to the cluster, or if any of its objects is not ready.
+func checkResourceDependencies(ctx context.Context, c client.Client, resource addonsv1.ResourceRef, dependsOn []addonsv1.ResourceRef, scopes map[addonsv1.ResourceRef]resourceReconcileScope, resourceSetBinding *addonsv1.ResourceSetBinding) error {
+	for _, dependency := range dependsOn {
+		scope, ok := scopes[dependency]
+		if !ok || !resourceSetBinding.IsApplied(dependency) {
+			return errors.Wrapf(ErrDependenciesNotReady, "%s %s is waiting for %s %s to be applied", resource.Kind, resource.Name, dependency.Kind, dependency.Name)
+		}
+		notReady, err := getNotReadyObjects(ctx, c, scope.objs())
+		if err != nil {
+			return err
+		}
+		if len(notReady) > 0 {
+			return errors.Wrapf(ErrDependenciesNotReady, "%s %s is waiting for the objects of %s %s to be ready: %s", resource.Kind, resource.Name, dependency.Kind, dependency.Name, strings.Join(notReady, ", "))
+		}
+	}
+	return nil
+}
+
+// checkClusterResourceSetDependencies returns an error wrapping ErrDependenciesNotReady if any of the ClusterResourceSets
+// a ClusterResourceSet depends on is not applied to the cluster, or if any of their objects is not ready.
+// NOTE: The objects applied by a ClusterResourceSet are known only with the Reconcile strategy, so the objects of
+// ClusterResourceSets with the ApplyOnce strategy are not checked for readiness.
+func (r *ClusterResourceSetReconciler) checkClusterResourceSetDependencies(ctx context.Context, c client.Client, crs *addonsv1.ClusterResourceSet, clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding) error {
+	for _, name := range crs.Spec.DependsOn {
+		dependency := &addonsv1.ClusterResourceSet{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: crs.Namespace, Name: name}, dependency); err != nil {
+			if apierrors.IsNotFound(err) {
+				return errors.Wrapf(ErrDependenciesNotReady, "waiting for ClusterResourceSet %s to be created", name)
+			}
+			return errors.Wrapf(err, "failed to get ClusterResourceSet %s", name)
+		}
+
+		var resourceSetBinding *addonsv1.ResourceSetBinding
+		for _, binding := range clusterResourceSetBinding.Spec.Bindings {
+			if binding.ClusterResourceSetName == name {
+				resourceSetBinding = binding
+				break
+			}
+		}
+		for _, resource := range getResourceRefs(dependency) {
+			if resourceSetBinding == nil || !resourceSetBinding.IsApplied(resource) {
+				return errors.Wrapf(ErrDependenciesNotReady, "waiting for ClusterResourceSet %s to be applied", name)
+			}
+		}
+
+		objs := []unstructured.Unstructured{}
+		for _, resourceSet := range clusterResourceSetBinding.Status.ResourceSets {
+			if resourceSet.ClusterResourceSetName != name {
+				continue
+			}
+			for _, o := range resourceSet.Objects {
+				obj := unstructured.Unstructured{}
+				obj.SetAPIVersion(o.APIVersion)
+				obj.SetKind(o.Kind)
+				obj.SetNamespace(o.Namespace)
+				obj.SetName(o.Name)
+				objs = append(objs, obj)
+			}
+		}
+		notReady, err := getNotReadyObjects(ctx, c, objs)
+		if err != nil {
+			return err
+		}
+		if len(notReady) > 0 {
+			return errors.Wrapf(ErrDependenciesNotReady, "waiting for the objects of ClusterResourceSet %s to be ready: %s", name, strings.Join(notReady, ", "))
+		}
+	}
+	return nil
+}
+
+// getNotReadyObjects returns the objects which do not exist in the cluster, or are not ready yet.
+func getNotReadyObjects(ctx context.Context, c client.Client, objs []unstructured.Unstructured) ([]string, error) {
+	notReady := []string{}
+	for i := range objs {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(objs[i].GetAPIVersion())
+		obj.SetKind(objs[i].GetKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(&objs[i]), obj); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "reading object %s %s", objs[i].GroupVersionKind(), klog.KObj(&objs[i]))
+			}
+			notReady = append(notReady, fmt.Sprintf("%s %s", objs[i].GetKind(), klog.KObj(&objs[i])))
+			continue
+		}
+		if !isObjectReady(obj) {
+			notReady = append(notReady, fmt.Sprintf("%s %s", objs[i].GetKind(), klog.KObj(&objs[i])))
+		}
+	}
+	return notReady, nil
+}
+
+// isObjectReady returns true if an object is ready: CustomResourceDefinitions must be established, and Deployments
+// must be available with their latest spec observed; other objects are ready once they exist.
+func isObjectReady(obj *unstructured.Unstructured) bool {
+	switch obj.GroupVersionKind().GroupKind() {
+	case customResourceDefinitionGroupKind:
+		return hasTrueCondition(obj, "Established")
+	case deploymentGroupKind:
+		observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+		return observedGeneration >= obj.GetGeneration() && hasTrueCondition(obj, "Available")
+	default:
+		return true
+	}
+}
+
+// hasTrueCondition returns true if an object has a status condition of the given type with status True.
+func hasTrueCondition(obj *unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == conditionType {
+			return condition["status"] == "True"
+		}
+	}
+	return false
+}
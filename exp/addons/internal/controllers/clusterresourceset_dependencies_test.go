@@ -0,0 +1,367 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func TestSortResourceScopes(t *testing.T) {
+	crds := addonsv1.ResourceRef{Name: "crds", Kind: "ConfigMap"}
+	cni := addonsv1.ResourceRef{Name: "cni", Kind: "Secret"}
+	csi := addonsv1.ResourceRef{Name: "csi", Kind: "OCIArtifact"}
+	operator := addonsv1.ResourceRef{Name: "operator", Kind: "HelmChart"}
+
+	scopeFor := func(resourceRef addonsv1.ResourceRef) resourceReconcileScope {
+		return &reconcileApplyOnceScope{baseResourceReconcileScope{resourceRef: resourceRef}}
+	}
+	resources := func(scopes []resourceReconcileScope) []addonsv1.ResourceRef {
+		refs := []addonsv1.ResourceRef{}
+		for _, scope := range scopes {
+			refs = append(refs, scope.resource())
+		}
+		return refs
+	}
+
+	tests := []struct {
+		name         string
+		scopes       []addonsv1.ResourceRef
+		dependencies []addonsv1.ResourceDependency
+		want         []addonsv1.ResourceRef
+	}{
+		{
+			name:   "no dependencies",
+			scopes: []addonsv1.ResourceRef{cni, crds, csi},
+			want:   []addonsv1.ResourceRef{cni, crds, csi},
+		},
+		{
+			name:   "resources are moved after their dependencies",
+			scopes: []addonsv1.ResourceRef{cni, csi, crds, operator},
+			dependencies: []addonsv1.ResourceDependency{
+				{Resource: cni, DependsOn: []addonsv1.ResourceRef{crds}},
+			},
+			want: []addonsv1.ResourceRef{csi, crds, cni, operator},
+		},
+		{
+			name:   "transitive dependencies",
+			scopes: []addonsv1.ResourceRef{operator, cni, crds},
+			dependencies: []addonsv1.ResourceDependency{
+				{Resource: operator, DependsOn: []addonsv1.ResourceRef{cni}},
+				{Resource: cni, DependsOn: []addonsv1.ResourceRef{crds}},
+			},
+			want: []addonsv1.ResourceRef{crds, cni, operator},
+		},
+		{
+			name:   "dependencies on resources which failed to be retrieved are ignored",
+			scopes: []addonsv1.ResourceRef{cni, csi},
+			dependencies: []addonsv1.ResourceDependency{
+				{Resource: cni, DependsOn: []addonsv1.ResourceRef{crds}},
+			},
+			want: []addonsv1.ResourceRef{cni, csi},
+		},
+		{
+			name:   "resources in a cycle are left in their order",
+			scopes: []addonsv1.ResourceRef{cni, crds, csi},
+			dependencies: []addonsv1.ResourceDependency{
+				{Resource: cni, DependsOn: []addonsv1.ResourceRef{crds}},
+				{Resource: crds, DependsOn: []addonsv1.ResourceRef{cni}},
+			},
+			want: []addonsv1.ResourceRef{csi, cni, crds},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scopes := []resourceReconcileScope{}
+			for _, resourceRef := range tt.scopes {
+				scopes = append(scopes, scopeFor(resourceRef))
+			}
+			g.Expect(resources(sortResourceScopes(scopes, tt.dependencies))).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestIsObjectReady(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  map[string]interface{}
+		want bool
+	}{
+		{
+			name: "established CustomResourceDefinition",
+			obj: map[string]interface{}{
+				"apiVersion": "apiextensions.k8s.io/v1",
+				"kind":       "CustomResourceDefinition",
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+						map[string]interface{}{"type": "Established", "status": "True"},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "CustomResourceDefinition not established",
+			obj: map[string]interface{}{
+				"apiVersion": "apiextensions.k8s.io/v1",
+				"kind":       "CustomResourceDefinition",
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Established", "status": "False"},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "available Deployment",
+			obj: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"generation": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(2),
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Available", "status": "True"},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Deployment with a spec not observed yet",
+			obj: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"generation": int64(3)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(2),
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Available", "status": "True"},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Deployment without status",
+			obj: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"generation": int64(1)},
+			},
+			want: false,
+		},
+		{
+			name: "other objects",
+			obj: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(isObjectReady(&unstructured.Unstructured{Object: tt.obj})).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestCheckResourceDependencies(t *testing.T) {
+	crds := addonsv1.ResourceRef{Name: "crds", Kind: "ConfigMap"}
+	cni := addonsv1.ResourceRef{Name: "cni", Kind: "Secret"}
+
+	crd := func(established string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata":   map[string]interface{}{"name": "foos.example.com"},
+		}}
+		if established != "" {
+			obj.Object["status"] = map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Established", "status": established},
+				},
+			}
+		}
+		return obj
+	}
+	scopes := map[addonsv1.ResourceRef]resourceReconcileScope{
+		crds: &reconcileApplyOnceScope{baseResourceReconcileScope{resourceRef: crds, normalizedObjs: []unstructured.Unstructured{*crd("")}}},
+	}
+	applied := &addonsv1.ResourceSetBinding{Resources: []addonsv1.ResourceBinding{{ResourceRef: crds, Applied: true}}}
+
+	tests := []struct {
+		name               string
+		existingObjs       []client.Object
+		resourceSetBinding *addonsv1.ResourceSetBinding
+		wantNotReady       bool
+	}{
+		{
+			name:               "dependencies applied and ready",
+			existingObjs:       []client.Object{crd("True")},
+			resourceSetBinding: applied,
+		},
+		{
+			name:               "dependencies not applied",
+			existingObjs:       []client.Object{crd("True")},
+			resourceSetBinding: &addonsv1.ResourceSetBinding{Resources: []addonsv1.ResourceBinding{{ResourceRef: crds, Applied: false}}},
+			wantNotReady:       true,
+		},
+		{
+			name:               "objects of the dependencies not ready",
+			existingObjs:       []client.Object{crd("False")},
+			resourceSetBinding: applied,
+			wantNotReady:       true,
+		},
+		{
+			name:               "objects of the dependencies not found",
+			resourceSetBinding: applied,
+			wantNotReady:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fake.NewClientBuilder().WithObjects(tt.existingObjs...).Build()
+			err := checkResourceDependencies(ctx, c, cni, []addonsv1.ResourceRef{crds}, scopes, tt.resourceSetBinding)
+			if !tt.wantNotReady {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(errors.Is(err, ErrDependenciesNotReady)).To(BeTrue())
+		})
+	}
+}
+
+func TestCheckClusterResourceSetDependencies(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = addonsv1.AddToScheme(scheme)
+
+	crdsRef := addonsv1.ResourceRef{Name: "crds", Kind: "ConfigMap"}
+	dependency := &addonsv1.ClusterResourceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "calico-crds", Namespace: "ns"},
+		Spec: addonsv1.ClusterResourceSetSpec{
+			Resources: []addonsv1.ResourceRef{crdsRef},
+			Strategy:  string(addonsv1.ClusterResourceSetStrategyReconcile),
+		},
+	}
+	crs := &addonsv1.ClusterResourceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "calico", Namespace: "ns"},
+		Spec:       addonsv1.ClusterResourceSetSpec{DependsOn: []string{"calico-crds"}},
+	}
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "foos.example.com"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Established", "status": "True"},
+			},
+		},
+	}}
+	bindingWith := func(applied bool) *addonsv1.ClusterResourceSetBinding {
+		return &addonsv1.ClusterResourceSetBinding{
+			Spec: addonsv1.ClusterResourceSetBindingSpec{
+				Bindings: []*addonsv1.ResourceSetBinding{
+					{ClusterResourceSetName: "calico-crds", Resources: []addonsv1.ResourceBinding{{ResourceRef: crdsRef, Applied: applied}}},
+				},
+			},
+			Status: addonsv1.ClusterResourceSetBindingStatus{
+				ResourceSets: []addonsv1.ResourceSetStatus{
+					{
+						ClusterResourceSetName: "calico-crds",
+						Objects: []addonsv1.AppliedObject{
+							{Resource: crdsRef, APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "foos.example.com"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name                      string
+		dependencies              []client.Object
+		existingObjs              []client.Object
+		clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding
+		wantNotReady              bool
+	}{
+		{
+			name:                      "dependencies applied and ready",
+			dependencies:              []client.Object{dependency},
+			existingObjs:              []client.Object{crd},
+			clusterResourceSetBinding: bindingWith(true),
+		},
+		{
+			name:                      "dependencies not found",
+			existingObjs:              []client.Object{crd},
+			clusterResourceSetBinding: bindingWith(true),
+			wantNotReady:              true,
+		},
+		{
+			name:                      "dependencies not applied",
+			dependencies:              []client.Object{dependency},
+			existingObjs:              []client.Object{crd},
+			clusterResourceSetBinding: bindingWith(false),
+			wantNotReady:              true,
+		},
+		{
+			name:                      "dependencies not applied to the cluster yet",
+			dependencies:              []client.Object{dependency},
+			existingObjs:              []client.Object{crd},
+			clusterResourceSetBinding: &addonsv1.ClusterResourceSetBinding{},
+			wantNotReady:              true,
+		},
+		{
+			name:                      "objects of the dependencies not ready",
+			dependencies:              []client.Object{dependency},
+			clusterResourceSetBinding: bindingWith(true),
+			wantNotReady:              true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &ClusterResourceSetReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.dependencies...).Build()}
+			remoteClient := fake.NewClientBuilder().WithObjects(tt.existingObjs...).Build()
+			err := r.checkClusterResourceSetDependencies(ctx, remoteClient, crs, tt.clusterResourceSetBinding)
+			if !tt.wantNotReady {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(errors.Is(err, ErrDependenciesNotReady)).To(BeTrue())
+		})
+	}
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"text/template"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/exp/addons/internal/helm"
+	"sigs.k8s.io/cluster-api/internal/contract"
+)
+
+// reconcileScopeForHelmChart renders a Helm chart for a Cluster and returns the scope to apply the resulting objects.
+// The objects rendered from a chart are handled like the objects of a resource, using the reference of the Helm chart
+// in the ClusterResourceSetBinding; namespaced objects without a namespace are applied to the release namespace.
+func (r *ClusterResourceSetReconciler) reconcileScopeForHelmChart(
+	ctx context.Context,
+	remoteClient client.Client,
+	cluster *clusterv1.Cluster,
+	crs *addonsv1.ClusterResourceSet,
+	helmChart addonsv1.HelmChartSource,
+	resourceSetBinding *addonsv1.ResourceSetBinding,
+) (resourceReconcileScope, error) {
+	manifests, err := r.renderHelmChart(ctx, cluster, crs, helmChart)
+	if err != nil {
+		return nil, err
+	}
+
+	objs, err := objsFromYamlData(manifests)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the objects rendered from Helm chart %s", helmChart.Name)
+	}
+
+	normalizedData := make([][]byte, 0, len(objs))
+	for i := range objs {
+		namespaced, err := isNamespaced(remoteClient, objs, &objs[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the scope of %s rendered from Helm chart %s", objs[i].GroupVersionKind(), helmChart.Name)
+		}
+		if namespaced && objs[i].GetNamespace() == "" {
+			objs[i].SetNamespace(releaseNamespace(helmChart))
+		}
+
+		data, err := yaml.Marshal(objs[i].Object)
+		if err != nil {
+			return nil, err
+		}
+		normalizedData = append(normalizedData, data)
+	}
+
+	scope := newResourceReconcileScope(crs, helmChart.ResourceRef(), resourceSetBinding, normalizedData, objs)
+	if _, syncObjects := scope.(driftReconcileScope); syncObjects || scope.needsApply() {
+		if err := ensureNamespace(ctx, remoteClient, releaseNamespace(helmChart)); err != nil {
+			return nil, err
+		}
+	}
+	return scope, nil
+}
+
+// renderHelmChart fetches a Helm chart and renders it for a Cluster.
+func (r *ClusterResourceSetReconciler) renderHelmChart(ctx context.Context, cluster *clusterv1.Cluster, crs *addonsv1.ClusterResourceSet, helmChart addonsv1.HelmChartSource) ([][]byte, error) {
+	chart, err := r.helmChartGetter.Get(ctx, helmChart.RepoURL, helmChart.Chart, helmChart.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	for _, valuesRef := range helmChart.ValuesFrom {
+		data, err := r.getHelmValues(ctx, crs, valuesRef)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get values for Helm chart %s", helmChart.Name)
+		}
+		v, err := helm.ReadValues(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse values for Helm chart %s from %s %s", helmChart.Name, valuesRef.Kind, valuesRef.Name)
+		}
+		values = helm.MergeValues(values, v)
+	}
+
+	if helmChart.ValuesTemplate != "" {
+		data, err := renderValuesTemplate(r.Client.Scheme(), cluster, helmChart.ValuesTemplate)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render values template for Helm chart %s", helmChart.Name)
+		}
+		v, err := helm.ReadValues(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse values rendered from the template for Helm chart %s", helmChart.Name)
+		}
+		values = helm.MergeValues(values, v)
+	}
+
+	kubeVersion, err := r.getKubernetesVersion(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, err := helm.Render(chart, helm.RenderOptions{
+		ReleaseName:      releaseName(helmChart),
+		ReleaseNamespace: releaseNamespace(helmChart),
+		KubeVersion:      kubeVersion,
+		Values:           values,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render Helm chart %s", helmChart.Name)
+	}
+	return manifests, nil
+}
+
+// getHelmValues returns the values in a Secret/ConfigMap referenced by a Helm chart, ensuring an ownerReference
+// to the ClusterResourceSet is on the Secret/ConfigMap, so the ClusterResourceSet is reconciled when values change.
+func (r *ClusterResourceSetReconciler) getHelmValues(ctx context.Context, crs *addonsv1.ClusterResourceSet, valuesRef addonsv1.HelmValuesReference) ([]byte, error) {
+	key := types.NamespacedName{Namespace: crs.Namespace, Name: valuesRef.Name}
+
+	var obj runtime.Object
+	var data []byte
+	var ok bool
+	switch valuesRef.Kind {
+	case string(addonsv1.ConfigMapClusterResourceSetResourceKind):
+		configMap, err := getConfigMap(ctx, r.Client, key)
+		if err != nil {
+			return nil, err
+		}
+		var s string
+		s, ok = configMap.Data[valuesRef.Key]
+		data = []byte(s)
+		obj = configMap
+	case string(addonsv1.SecretClusterResourceSetResourceKind):
+		secret, err := getSecret(ctx, r.Client, key)
+		if err != nil {
+			return nil, err
+		}
+		data, ok = secret.Data[valuesRef.Key]
+		obj = secret
+	default:
+		return nil, errors.Errorf("unsupported kind %q", valuesRef.Kind)
+	}
+	if !ok {
+		return nil, errors.Errorf("key %q not found in %s %s", valuesRef.Key, valuesRef.Kind, valuesRef.Name)
+	}
+
+	raw := &unstructured.Unstructured{}
+	if err := r.Client.Scheme().Convert(obj, raw, nil); err != nil {
+		return nil, err
+	}
+	if err := r.ensureResourceOwnerRef(ctx, crs, raw); err != nil {
+		return nil, errors.Wrapf(err, "failed to add ClusterResourceSet as owner reference of %s %s", valuesRef.Kind, valuesRef.Name)
+	}
+	return data, nil
+}
+
+// getKubernetesVersion returns the Kubernetes version of a Cluster, used as .Capabilities.KubeVersion when rendering
+// Helm charts; an empty string is returned if the version is not defined, e.g. for clusters without a control plane object.
+func (r *ClusterResourceSetReconciler) getKubernetesVersion(ctx context.Context, cluster *clusterv1.Cluster) (string, error) {
+	if cluster.Spec.Topology != nil {
+		return cluster.Spec.Topology.Version, nil
+	}
+	if cluster.Spec.ControlPlaneRef == nil {
+		return "", nil
+	}
+
+	controlPlane, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the control plane of the Cluster to get its Kubernetes version")
+	}
+	version, err := contract.ControlPlane().Version().Get(controlPlane)
+	if err != nil {
+		if errors.Is(err, contract.ErrFieldNotFound) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get the Kubernetes version of the Cluster from its control plane")
+	}
+	return *version, nil
+}
+
+// renderValuesTemplate renders the values template of a Helm chart for a Cluster.
+func renderValuesTemplate(scheme *runtime.Scheme, cluster *clusterv1.Cluster, valuesTemplate string) ([]byte, error) {
+	t, err := template.New("values").Funcs(templateFuncs()).Option("missingkey=zero").Parse(valuesTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{}
+	if err := scheme.Convert(cluster, u, nil); err != nil {
+		return nil, err
+	}
+	u.SetManagedFields(nil)
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, map[string]interface{}{"Cluster": u.Object}); err != nil {
+		return nil, err
+	}
+	return bytes.ReplaceAll(buf.Bytes(), []byte("<no value>"), nil), nil
+}
+
+// isNamespaced returns true if an object is namespaced; the scope of custom resources is read from the CRDs
+// rendered from the same chart, if any, given that CRDs are not known to the cluster before being applied.
+func isNamespaced(c client.Client, objs []unstructured.Unstructured, obj *unstructured.Unstructured) (bool, error) {
+	gvk := obj.GroupVersionKind()
+	for i := range objs {
+		if objs[i].GroupVersionKind().GroupKind() != (schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}) {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(objs[i].Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(objs[i].Object, "spec", "names", "kind")
+		if group != gvk.Group || kind != gvk.Kind {
+			continue
+		}
+		scope, _, _ := unstructured.NestedString(objs[i].Object, "spec", "scope")
+		return scope == "Namespaced", nil
+	}
+
+	return c.IsObjectNamespaced(obj)
+}
+
+// ensureNamespace creates a namespace in the cluster, if it does not exist.
+func ensureNamespace(ctx context.Context, c client.Client, name string) error {
+	namespace := &corev1.Namespace{}
+	err := c.Get(ctx, client.ObjectKey{Name: name}, namespace)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get Namespace %s", name)
+	}
+
+	namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if err := c.Create(ctx, namespace); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create Namespace %s", name)
+	}
+	return nil
+}
+
+func releaseName(helmChart addonsv1.HelmChartSource) string {
+	if helmChart.ReleaseName != "" {
+		return helmChart.ReleaseName
+	}
+	return helmChart.Name
+}
+
+func releaseNamespace(helmChart addonsv1.HelmChartSource) string {
+	if helmChart.ReleaseNamespace != "" {
+		return helmChart.ReleaseNamespace
+	}
+	return metav1.NamespaceDefault
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func TestRenderValuesTemplate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"},
+		Spec: clusterv1.ClusterSpec{
+			ClusterNetwork: &clusterv1.ClusterNetwork{
+				Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+			},
+		},
+	}
+
+	t.Run("renders the template with the Cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		values, err := renderValuesTemplate(scheme, cluster, `clusterName: {{ .Cluster.metadata.name }}
+podCIDRs:
+  {{- toYaml .Cluster.spec.clusterNetwork.pods.cidrBlocks | nindent 2 }}
+missing: "{{ .Cluster.spec.foo }}"`)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(values)).To(Equal(`clusterName: cluster
+podCIDRs:
+  - 192.168.0.0/16
+missing: ""`))
+	})
+	t.Run("fails for invalid templates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := renderValuesTemplate(scheme, cluster, `{{ .Cluster.metadata.name `)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestIsNamespaced(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	c := fake.NewClientBuilder().WithRESTMapper(restMapper).Build()
+
+	crd := func(kind, scope string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"spec": map[string]interface{}{
+				"group": "example.com",
+				"names": map[string]interface{}{"kind": kind},
+				"scope": scope,
+			},
+		}}
+	}
+	obj := func(apiVersion, kind string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		return u
+	}
+	objs := []unstructured.Unstructured{
+		crd("Foo", "Namespaced"),
+		crd("Bar", "Cluster"),
+	}
+
+	tests := []struct {
+		name    string
+		obj     *unstructured.Unstructured
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "namespaced kind known to the cluster",
+			obj:  obj("v1", "ConfigMap"),
+			want: true,
+		},
+		{
+			name: "cluster scoped kind known to the cluster",
+			obj:  obj("v1", "Namespace"),
+			want: false,
+		},
+		{
+			name: "namespaced kind defined by a CRD in the chart",
+			obj:  obj("example.com/v1", "Foo"),
+			want: true,
+		},
+		{
+			name: "cluster scoped kind defined by a CRD in the chart",
+			obj:  obj("example.com/v1", "Bar"),
+			want: false,
+		},
+		{
+			name:    "unknown kind",
+			obj:     obj("example.com/v1", "Baz"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := isNamespaced(c, objs, tt.obj)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestReferencesResource(t *testing.T) {
+	crs := &addonsv1.ClusterResourceSet{
+		Spec: addonsv1.ClusterResourceSetSpec{
+			Resources: []addonsv1.ResourceRef{{Name: "resource", Kind: "ConfigMap"}},
+			OCIArtifacts: []addonsv1.OCIArtifactSource{{
+				Name:   "artifact",
+				Verify: &addonsv1.OCIArtifactVerification{SecretRef: "keys"},
+			}},
+			HelmCharts: []addonsv1.HelmChartSource{{
+				Name:       "chart",
+				ValuesFrom: []addonsv1.HelmValuesReference{{Name: "values", Kind: "Secret"}},
+			}},
+		},
+	}
+
+	g := NewWithT(t)
+	g.Expect(referencesResource(crs, "ConfigMap", "resource")).To(BeTrue())
+	g.Expect(referencesResource(crs, "Secret", "values")).To(BeTrue())
+	g.Expect(referencesResource(crs, "Secret", "keys")).To(BeTrue())
+	g.Expect(referencesResource(crs, "ConfigMap", "keys")).To(BeFalse())
+	g.Expect(referencesResource(crs, "Secret", "resource")).To(BeFalse())
+	g.Expect(referencesResource(crs, "ConfigMap", "values")).To(BeFalse())
+	g.Expect(referencesResource(crs, "ConfigMap", "chart")).To(BeFalse())
+}
+
+func TestEnsureNamespace(t *testing.T) {
+	g := NewWithT(t)
+
+	c := fake.NewClientBuilder().WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "existing"}}).Build()
+
+	g.Expect(ensureNamespace(ctx, c, "existing")).To(Succeed())
+	g.Expect(ensureNamespace(ctx, c, "new")).To(Succeed())
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: "new"}, &corev1.Namespace{})).To(Succeed())
+}
+
+// newHelmChartRepository serves a Helm chart repository with a single chart.
+func newHelmChartRepository(t *testing.T, name, version string, files map[string]string) *httptest.Server {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for fileName, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name + "/" + fileName, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	archive := buf.Bytes()
+	archiveName := fmt.Sprintf("%s-%s.tgz", name, version)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			fmt.Fprintf(w, "apiVersion: v1\nentries:\n  %s:\n  - version: %s\n    urls: [%s]\n", name, version, archiveName)
+		case "/" + archiveName:
+			_, _ = w.Write(archive)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
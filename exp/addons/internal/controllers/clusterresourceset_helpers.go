@@ -25,6 +25,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 	"unicode"
 
 	"github.com/pkg/errors"
@@ -34,11 +36,13 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	utilresource "sigs.k8s.io/cluster-api/util/resource"
 	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
 )
@@ -93,19 +97,6 @@ func isJSONList(data []byte) (bool, error) {
 	return bytes.HasPrefix(trim, jsonListPrefix), nil
 }
 
-func createUnstructured(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
-	if err := c.Create(ctx, obj); err != nil {
-		return errors.Wrapf(
-			err,
-			"creating object %s %s",
-			obj.GroupVersionKind(),
-			klog.KObj(obj),
-		)
-	}
-
-	return nil
-}
-
 // getOrCreateClusterResourceSetBinding retrieves ClusterResourceSetBinding resource owned by the cluster or create a new one if not found.
 func (r *ClusterResourceSetReconciler) getOrCreateClusterResourceSetBinding(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet) (*addonsv1.ClusterResourceSetBinding, error) {
 	clusterResourceSetBinding := &addonsv1.ClusterResourceSetBinding{}
@@ -254,3 +245,173 @@ func ensureKubernetesServiceCreated(ctx context.Context, client client.Client) e
 	}
 	return nil
 }
+
+// pruneObjects deletes from the cluster the objects previously applied from a resource which are not defined by
+// the resource anymore, and records the objects currently defined by the resource in the ResourceSetStatus.
+// NOTE: Objects are recorded only if pruning succeeds, so pruning is retried at the next reconcile.
+func pruneObjects(ctx context.Context, c client.Client, resourceRef addonsv1.ResourceRef, scope driftReconcileScope, resourceSetStatus *addonsv1.ResourceSetStatus) error {
+	if err := scope.prune(ctx, c, resourceSetStatus.GetObjects(resourceRef)); err != nil {
+		return err
+	}
+	resourceSetStatus.SetObjects(resourceRef, scope.appliedObjects())
+	return nil
+}
+
+// filterAppliedObjects returns the applied objects of the resources which are still in the ClusterResourceSet.
+// NOTE: Objects applied from a resource removed from the ClusterResourceSet are not deleted from the cluster,
+// consistently with removing a ClusterResourceSet, so they are not tracked anymore.
+func filterAppliedObjects(objects []addonsv1.AppliedObject, resources []addonsv1.ResourceRef) []addonsv1.AppliedObject {
+	filtered := []addonsv1.AppliedObject{}
+	for _, o := range objects {
+		for _, resource := range resources {
+			if o.Resource == resource {
+				filtered = append(filtered, o)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// getResourceRefs returns the references to the resources, the OCI artifacts and the Helm charts of a ClusterResourceSet,
+// as recorded in the ClusterResourceSetBinding.
+func getResourceRefs(crs *addonsv1.ClusterResourceSet) []addonsv1.ResourceRef {
+	resourceRefs := append([]addonsv1.ResourceRef{}, crs.Spec.Resources...)
+	for i := range crs.Spec.OCIArtifacts {
+		resourceRefs = append(resourceRefs, crs.Spec.OCIArtifacts[i].ResourceRef())
+	}
+	for i := range crs.Spec.HelmCharts {
+		resourceRefs = append(resourceRefs, crs.Spec.HelmCharts[i].ResourceRef())
+	}
+	return resourceRefs
+}
+
+// filterResourceStatuses returns the statuses of the resources which are still in the ClusterResourceSet.
+func filterResourceStatuses(statuses []addonsv1.ResourceStatus, resources []addonsv1.ResourceRef) []addonsv1.ResourceStatus {
+	filtered := []addonsv1.ResourceStatus{}
+	for _, s := range statuses {
+		for _, resource := range resources {
+			if s.ResourceRef == resource {
+				filtered = append(filtered, s)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// setResourceApplied records in the ResourceSetStatus that a resource has been applied to a cluster.
+func setResourceApplied(cluster *clusterv1.Cluster, crs *addonsv1.ClusterResourceSet, resourceSetStatus *addonsv1.ResourceSetStatus, resource addonsv1.ResourceRef, hash string) {
+	resourceSetStatus.SetResourceApplied(resource, hash, metav1.NewTime(time.Now().UTC()))
+	resourcesAppliedTotal.With(resourceLabels(cluster, crs, resource)).Inc()
+}
+
+// setResourceNotApplied records in the ResourceSetStatus that a resource has not been applied to a cluster, or that
+// its objects could not be kept in sync, and counts the failure.
+// NOTE: Resources waiting for their dependencies are not counted as failures.
+func setResourceNotApplied(cluster *clusterv1.Cluster, crs *addonsv1.ClusterResourceSet, resourceSetStatus *addonsv1.ResourceSetStatus, resource addonsv1.ResourceRef, reason string, err error) {
+	resourceSetStatus.SetResourceNotApplied(resource, reason, err.Error(), metav1.NewTime(time.Now().UTC()))
+	if reason == addonsv1.WaitingForDependenciesReason {
+		return
+	}
+	labels := resourceLabels(cluster, crs, resource)
+	labels["reason"] = reason
+	resourceApplyFailuresTotal.With(labels).Inc()
+}
+
+// setResourcesInSyncCondition sets the ResourcesInSync condition of a ResourceSetStatus, reporting the objects
+// whose drift has been corrected and the errors that occurred while keeping objects in sync.
+func setResourcesInSyncCondition(resourceSetStatus *addonsv1.ResourceSetStatus, drifted []string, driftErrList, pruneErrList []error) {
+	var condition *clusterv1.Condition
+	switch {
+	case len(driftErrList) > 0:
+		condition = conditions.FalseCondition(addonsv1.ResourcesInSyncCondition, addonsv1.DriftCorrectionFailedReason, clusterv1.ConditionSeverityWarning, kerrors.NewAggregate(driftErrList).Error())
+	case len(pruneErrList) > 0:
+		condition = conditions.FalseCondition(addonsv1.ResourcesInSyncCondition, addonsv1.PruneFailedReason, clusterv1.ConditionSeverityWarning, kerrors.NewAggregate(pruneErrList).Error())
+	case len(drifted) > 0:
+		condition = conditions.FalseCondition(addonsv1.ResourcesInSyncCondition, addonsv1.DriftCorrectedReason, clusterv1.ConditionSeverityInfo, "Drift corrected for %s", strings.Join(drifted, ", "))
+	default:
+		condition = conditions.TrueCondition(addonsv1.ResourcesInSyncCondition)
+	}
+
+	// Preserve the last transition time if the status of the condition did not change.
+	condition.LastTransitionTime = metav1.NewTime(time.Now().UTC().Truncate(time.Second))
+	for i := range resourceSetStatus.Conditions {
+		existing := &resourceSetStatus.Conditions[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+		*existing = *condition
+		return
+	}
+	resourceSetStatus.Conditions = append(resourceSetStatus.Conditions, *condition)
+}
+
+// maxClusterConditionMessages is the maximum number of clusters whose messages are reported in the ResourcesApplied
+// condition of a ClusterResourceSet.
+const maxClusterConditionMessages = 3
+
+// clusterCondition is the ResourcesApplied condition reported while applying a ClusterResourceSet to a cluster.
+type clusterCondition struct {
+	cluster   string
+	condition *clusterv1.Condition
+}
+
+// setResourcesAppliedCondition sets the ResourcesApplied condition of a ClusterResourceSet summarizing the conditions
+// reported for each cluster: the condition is true if the resources have been applied to all the clusters, otherwise
+// it reports the reason and severity of the most severe failure, and the messages for the first failed clusters.
+// If no cluster has been reconciled, the previous condition is preserved.
+func setResourcesAppliedCondition(crs *addonsv1.ClusterResourceSet, previous *clusterv1.Condition, clusterConditions []clusterCondition) {
+	// Restore the previous condition, so its last transition time is preserved if the status did not change.
+	conditions.Delete(crs, addonsv1.ResourcesAppliedCondition)
+	if previous != nil {
+		conditions.Set(crs, previous)
+	}
+	if len(clusterConditions) == 0 {
+		return
+	}
+
+	failed := []clusterCondition{}
+	var worst *clusterv1.Condition
+	for _, c := range clusterConditions {
+		if c.condition.Status == corev1.ConditionTrue {
+			continue
+		}
+		failed = append(failed, c)
+		if worst == nil || severityRank(c.condition.Severity) > severityRank(worst.Severity) {
+			worst = c.condition
+		}
+	}
+	if len(failed) == 0 {
+		conditions.MarkTrue(crs, addonsv1.ResourcesAppliedCondition)
+		return
+	}
+
+	messages := []string{}
+	for i, c := range failed {
+		if i == maxClusterConditionMessages {
+			messages = append(messages, fmt.Sprintf("and %d more", len(failed)-i))
+			break
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s", c.cluster, c.condition.Message))
+	}
+	conditions.MarkFalse(crs, addonsv1.ResourcesAppliedCondition, worst.Reason, worst.Severity,
+		"%d of %d clusters not applied: %s", len(failed), len(clusterConditions), strings.Join(messages, "; "))
+}
+
+// severityRank returns a rank to compare the severity of conditions, higher for more severe conditions.
+func severityRank(severity clusterv1.ConditionSeverity) int {
+	switch severity {
+	case clusterv1.ConditionSeverityError:
+		return 3
+	case clusterv1.ConditionSeverityWarning:
+		return 2
+	case clusterv1.ConditionSeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -22,6 +22,8 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +33,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 const (
@@ -271,3 +274,178 @@ func TestEnsureKubernetesServiceCreated(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterAppliedObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	cm := addonsv1.ResourceRef{Name: "cm", Kind: "ConfigMap"}
+	secret := addonsv1.ResourceRef{Name: "secret", Kind: "Secret"}
+	removed := addonsv1.ResourceRef{Name: "removed", Kind: "ConfigMap"}
+	objects := []addonsv1.AppliedObject{
+		{Resource: cm, APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "a"},
+		{Resource: removed, APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "b"},
+		{Resource: secret, APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "c"},
+	}
+
+	g.Expect(filterAppliedObjects(objects, []addonsv1.ResourceRef{cm, secret})).To(Equal([]addonsv1.AppliedObject{objects[0], objects[2]}))
+	g.Expect(filterAppliedObjects(objects, nil)).To(BeEmpty())
+}
+
+func TestSetResourcesInSyncCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	resourceSetStatus := &addonsv1.ResourceSetStatus{ClusterResourceSetName: "crs"}
+
+	setResourcesInSyncCondition(resourceSetStatus, []string{}, nil, nil)
+	g.Expect(resourceSetStatus.Conditions).To(HaveLen(1))
+	g.Expect(resourceSetStatus.Conditions[0].Type).To(Equal(addonsv1.ResourcesInSyncCondition))
+	g.Expect(resourceSetStatus.Conditions[0].Status).To(Equal(corev1.ConditionTrue))
+
+	// Keep the last transition time if the status does not change.
+	lastTransitionTime := metav1.NewTime(time.Now().Add(-time.Hour).UTC().Truncate(time.Second))
+	resourceSetStatus.Conditions[0].LastTransitionTime = lastTransitionTime
+	setResourcesInSyncCondition(resourceSetStatus, []string{}, nil, nil)
+	g.Expect(resourceSetStatus.Conditions[0].LastTransitionTime).To(Equal(lastTransitionTime))
+
+	setResourcesInSyncCondition(resourceSetStatus, []string{"ConfigMap default/a"}, nil, nil)
+	g.Expect(resourceSetStatus.Conditions).To(HaveLen(1))
+	g.Expect(resourceSetStatus.Conditions[0].Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(resourceSetStatus.Conditions[0].Reason).To(Equal(addonsv1.DriftCorrectedReason))
+	g.Expect(resourceSetStatus.Conditions[0].Severity).To(Equal(clusterv1.ConditionSeverityInfo))
+	g.Expect(resourceSetStatus.Conditions[0].Message).To(ContainSubstring("ConfigMap default/a"))
+	g.Expect(resourceSetStatus.Conditions[0].LastTransitionTime).ToNot(Equal(lastTransitionTime))
+
+	setResourcesInSyncCondition(resourceSetStatus, []string{}, nil, []error{errors.New("failed to delete")})
+	g.Expect(resourceSetStatus.Conditions[0].Reason).To(Equal(addonsv1.PruneFailedReason))
+	g.Expect(resourceSetStatus.Conditions[0].Severity).To(Equal(clusterv1.ConditionSeverityWarning))
+
+	setResourcesInSyncCondition(resourceSetStatus, []string{"ConfigMap default/a"}, []error{errors.New("failed to apply")}, []error{errors.New("failed to delete")})
+	g.Expect(resourceSetStatus.Conditions[0].Reason).To(Equal(addonsv1.DriftCorrectionFailedReason))
+	g.Expect(resourceSetStatus.Conditions[0].Message).To(Equal("failed to apply"))
+}
+
+func TestSetResourceStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: metav1.NamespaceDefault}}
+	crs := &addonsv1.ClusterResourceSet{ObjectMeta: metav1.ObjectMeta{Name: "crs", Namespace: metav1.NamespaceDefault}}
+	cm := addonsv1.ResourceRef{Name: "cm", Kind: "ConfigMap"}
+	resourceSetStatus := &addonsv1.ResourceSetStatus{ClusterResourceSetName: crs.Name}
+
+	labels := resourceLabels(cluster, crs, cm)
+	applied := testutil.ToFloat64(resourcesAppliedTotal.With(labels))
+	labels["reason"] = addonsv1.ApplyFailedReason
+	failed := testutil.ToFloat64(resourceApplyFailuresTotal.With(labels))
+	labels["reason"] = addonsv1.WaitingForDependenciesReason
+	waiting := testutil.ToFloat64(resourceApplyFailuresTotal.With(labels))
+
+	setResourceApplied(cluster, crs, resourceSetStatus, cm, "hash")
+	g.Expect(resourceSetStatus.Resources).To(HaveLen(1))
+	g.Expect(resourceSetStatus.Resources[0].LastAppliedHash).To(Equal("hash"))
+	g.Expect(testutil.ToFloat64(resourcesAppliedTotal.With(resourceLabels(cluster, crs, cm)))).To(Equal(applied + 1))
+
+	setResourceNotApplied(cluster, crs, resourceSetStatus, cm, addonsv1.ApplyFailedReason, errors.New("failed to apply"))
+	g.Expect(resourceSetStatus.Resources).To(HaveLen(1))
+	g.Expect(resourceSetStatus.Resources[0].LastAppliedHash).To(Equal("hash"))
+	g.Expect(resourceSetStatus.Resources[0].Reason).To(Equal(addonsv1.ApplyFailedReason))
+	g.Expect(resourceSetStatus.Resources[0].Message).To(Equal("failed to apply"))
+	labels["reason"] = addonsv1.ApplyFailedReason
+	g.Expect(testutil.ToFloat64(resourceApplyFailuresTotal.With(labels))).To(Equal(failed + 1))
+
+	// Resources waiting for their dependencies are not counted as failures.
+	setResourceNotApplied(cluster, crs, resourceSetStatus, cm, addonsv1.WaitingForDependenciesReason, errors.New("waiting"))
+	g.Expect(resourceSetStatus.Resources[0].Reason).To(Equal(addonsv1.WaitingForDependenciesReason))
+	labels["reason"] = addonsv1.WaitingForDependenciesReason
+	g.Expect(testutil.ToFloat64(resourceApplyFailuresTotal.With(labels))).To(Equal(waiting))
+}
+
+func TestFilterResourceStatuses(t *testing.T) {
+	g := NewWithT(t)
+
+	cm := addonsv1.ResourceRef{Name: "cm", Kind: "ConfigMap"}
+	removed := addonsv1.ResourceRef{Name: "removed", Kind: "ConfigMap"}
+	statuses := []addonsv1.ResourceStatus{
+		{ResourceRef: cm, LastAppliedHash: "a"},
+		{ResourceRef: removed, LastAppliedHash: "b"},
+	}
+
+	g.Expect(filterResourceStatuses(statuses, []addonsv1.ResourceRef{cm})).To(Equal([]addonsv1.ResourceStatus{statuses[0]}))
+	g.Expect(filterResourceStatuses(statuses, nil)).To(BeEmpty())
+}
+
+func TestSetResourcesAppliedCondition(t *testing.T) {
+	trueCondition := conditions.TrueCondition(addonsv1.ResourcesAppliedCondition)
+	applyFailed := conditions.FalseCondition(addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, "failed to apply")
+	clientFailed := conditions.FalseCondition(addonsv1.ResourcesAppliedCondition, addonsv1.RemoteClusterClientFailedReason, clusterv1.ConditionSeverityError, "failed to connect")
+	waiting := conditions.FalseCondition(addonsv1.ResourcesAppliedCondition, addonsv1.WaitingForDependenciesReason, clusterv1.ConditionSeverityInfo, "waiting")
+
+	tests := []struct {
+		name              string
+		previous          *clusterv1.Condition
+		clusterConditions []clusterCondition
+		wantStatus        corev1.ConditionStatus
+		wantReason        string
+		wantSeverity      clusterv1.ConditionSeverity
+		wantMessage       string
+	}{
+		{
+			name:              "no clusters preserves the previous condition",
+			previous:          applyFailed,
+			clusterConditions: nil,
+			wantStatus:        corev1.ConditionFalse,
+			wantReason:        addonsv1.ApplyFailedReason,
+			wantSeverity:      clusterv1.ConditionSeverityWarning,
+			wantMessage:       "failed to apply",
+		},
+		{
+			name:              "applied to all the clusters",
+			clusterConditions: []clusterCondition{{cluster: "a", condition: trueCondition}, {cluster: "b", condition: trueCondition}},
+			wantStatus:        corev1.ConditionTrue,
+		},
+		{
+			name: "reports the most severe failure",
+			clusterConditions: []clusterCondition{
+				{cluster: "a", condition: waiting},
+				{cluster: "b", condition: trueCondition},
+				{cluster: "c", condition: clientFailed},
+				{cluster: "d", condition: applyFailed},
+			},
+			wantStatus:   corev1.ConditionFalse,
+			wantReason:   addonsv1.RemoteClusterClientFailedReason,
+			wantSeverity: clusterv1.ConditionSeverityError,
+			wantMessage:  "3 of 4 clusters not applied: a: waiting; c: failed to connect; d: failed to apply",
+		},
+		{
+			name: "limits the number of cluster messages",
+			clusterConditions: []clusterCondition{
+				{cluster: "a", condition: applyFailed},
+				{cluster: "b", condition: applyFailed},
+				{cluster: "c", condition: applyFailed},
+				{cluster: "d", condition: applyFailed},
+				{cluster: "e", condition: applyFailed},
+			},
+			wantStatus:   corev1.ConditionFalse,
+			wantReason:   addonsv1.ApplyFailedReason,
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+			wantMessage:  "5 of 5 clusters not applied: a: failed to apply; b: failed to apply; c: failed to apply; and 2 more",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			crs := &addonsv1.ClusterResourceSet{}
+			if tt.previous != nil {
+				conditions.Set(crs, tt.previous)
+			}
+			setResourcesAppliedCondition(crs, conditions.Get(crs, addonsv1.ResourcesAppliedCondition), tt.clusterConditions)
+
+			condition := conditions.Get(crs, addonsv1.ResourcesAppliedCondition)
+			g.Expect(condition).ToNot(BeNil())
+			g.Expect(condition.Status).To(Equal(tt.wantStatus))
+			g.Expect(condition.Reason).To(Equal(tt.wantReason))
+			g.Expect(condition.Severity).To(Equal(tt.wantSeverity))
+			g.Expect(condition.Message).To(Equal(tt.wantMessage))
+		})
+	}
+}
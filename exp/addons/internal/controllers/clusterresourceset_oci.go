@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+// cosignPublicKeySuffix is the suffix of the keys of the Secrets with the cosign public keys used to verify OCI artifacts.
+const cosignPublicKeySuffix = ".pub"
+
+// reconcileScopeForOCIArtifact gets the manifests in an OCI artifact and returns the scope to apply the resulting objects.
+// The objects in an artifact are handled like the objects of a resource, using the reference of the OCI artifact
+// in the ClusterResourceSetBinding.
+func (r *ClusterResourceSetReconciler) reconcileScopeForOCIArtifact(
+	ctx context.Context,
+	crs *addonsv1.ClusterResourceSet,
+	ociArtifact addonsv1.OCIArtifactSource,
+	resourceSetBinding *addonsv1.ResourceSetBinding,
+	templateData map[string]interface{},
+) (resourceReconcileScope, error) {
+	var publicKeys [][]byte
+	if ociArtifact.Verify != nil {
+		var err error
+		publicKeys, err = r.getCosignPublicKeys(ctx, crs, ociArtifact.Verify.SecretRef)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the public keys to verify OCI artifact %s", ociArtifact.Name)
+		}
+	}
+
+	manifests, err := r.ociArtifactGetter.Get(ctx, ociArtifact.Reference, publicKeys)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get OCI artifact %s", ociArtifact.Name)
+	}
+
+	if templateData != nil {
+		manifests, err = renderTemplates(manifests, templateData)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the manifests in OCI artifact %s", ociArtifact.Name)
+		}
+	}
+
+	objs, err := objsFromYamlData(manifests)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the objects in OCI artifact %s", ociArtifact.Name)
+	}
+
+	return newResourceReconcileScope(crs, ociArtifact.ResourceRef(), resourceSetBinding, manifests, objs), nil
+}
+
+// getCosignPublicKeys returns the public keys in a Secret, ensuring an ownerReference to the ClusterResourceSet is on
+// the Secret, so the ClusterResourceSet is reconciled when keys change.
+func (r *ClusterResourceSetReconciler) getCosignPublicKeys(ctx context.Context, crs *addonsv1.ClusterResourceSet, secretName string) ([][]byte, error) {
+	secret, err := getSecret(ctx, r.Client, types.NamespacedName{Namespace: crs.Namespace, Name: secretName})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for key := range secret.Data {
+		if strings.HasSuffix(key, cosignPublicKeySuffix) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.Errorf("no keys with the %s suffix found in Secret %s", cosignPublicKeySuffix, secretName)
+	}
+	sort.Strings(keys)
+
+	publicKeys := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		publicKeys = append(publicKeys, secret.Data[key])
+	}
+
+	raw := &unstructured.Unstructured{}
+	if err := r.Client.Scheme().Convert(secret, raw, nil); err != nil {
+		return nil, err
+	}
+	if err := r.ensureResourceOwnerRef(ctx, crs, raw); err != nil {
+		return nil, errors.Wrapf(err, "failed to add ClusterResourceSet as owner reference of Secret %s", secretName)
+	}
+	return publicKeys, nil
+}
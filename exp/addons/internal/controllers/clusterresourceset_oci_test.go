@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func TestGetCosignPublicKeys(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	crs := &addonsv1.ClusterResourceSet{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterResourceSet", APIVersion: addonsv1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: "crs", Namespace: "ns", UID: "uid"},
+	}
+
+	t.Run("returns the public keys sorted by key", func(t *testing.T) {
+		g := NewWithT(t)
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "ns"},
+			Data: map[string][]byte{
+				"b.pub":      []byte("b"),
+				"a.pub":      []byte("a"),
+				"cosign.key": []byte("private"),
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+		r := &ClusterResourceSetReconciler{Client: c}
+
+		keys, err := r.getCosignPublicKeys(ctx, crs, "keys")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(keys).To(Equal([][]byte{[]byte("a"), []byte("b")}))
+
+		// The ClusterResourceSet is an owner of the Secret.
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		g.Expect(secret.OwnerReferences).To(ContainElement(HaveField("Name", "crs")))
+	})
+	t.Run("fails if the Secret has no public keys", func(t *testing.T) {
+		g := NewWithT(t)
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "ns"},
+			Data:       map[string][]byte{"cosign.key": []byte("private")},
+		}
+		r := &ClusterResourceSetReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()}
+
+		_, err := r.getCosignPublicKeys(ctx, crs, "keys")
+		g.Expect(err).To(HaveOccurred())
+	})
+	t.Run("fails if the Secret does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &ClusterResourceSetReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+
+		_, err := r.getCosignPublicKeys(ctx, crs, "keys")
+		g.Expect(err).To(HaveOccurred())
+	})
+}
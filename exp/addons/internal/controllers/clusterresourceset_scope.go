@@ -18,17 +18,24 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/ssa"
 )
 
+// clusterResourceSetManagerName is the field manager used when applying objects to the target cluster
+// with server-side apply.
+const clusterResourceSetManagerName = "capi-clusterresourceset"
+
 // resourceReconcileScope contains the scope for a CRS's resource
 // reconciliation request.
 type resourceReconcileScope interface {
@@ -40,6 +47,24 @@ type resourceReconcileScope interface {
 	// hash returns a computed hash of the defined objects in the resource. It is consistent
 	// between runs.
 	hash() string
+	// resource returns the reference to the resource, as recorded in the ClusterResourceSetBinding.
+	resource() addonsv1.ResourceRef
+	// objs returns the objects defined by the resource.
+	objs() []unstructured.Unstructured
+}
+
+// driftReconcileScope is implemented by the resourceReconcileScopes of the strategies keeping
+// the objects applied to the target cluster in sync with the resource.
+type driftReconcileScope interface {
+	resourceReconcileScope
+	// correctDrift re-applies the objects defined by the resource and returns the objects
+	// that have been changed or deleted in the target cluster since they were last applied.
+	correctDrift(ctx context.Context, c client.Client) ([]string, error)
+	// prune deletes from the target cluster the previously applied objects which are not
+	// defined by the resource anymore.
+	prune(ctx context.Context, c client.Client, applied []addonsv1.AppliedObject) error
+	// appliedObjects returns the references to the objects defined by the resource.
+	appliedObjects() []addonsv1.AppliedObject
 }
 
 func reconcileScopeForResource(
@@ -47,12 +72,20 @@ func reconcileScopeForResource(
 	resourceRef addonsv1.ResourceRef,
 	resourceSetBinding *addonsv1.ResourceSetBinding,
 	resource *unstructured.Unstructured,
+	templateData map[string]interface{},
 ) (resourceReconcileScope, error) {
 	normalizedData, err := normalizeData(resource)
 	if err != nil {
 		return nil, err
 	}
 
+	if templateData != nil {
+		normalizedData, err = renderTemplates(normalizedData, templateData)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render %s %s", resource.GetKind(), klog.KObj(resource))
+		}
+	}
+
 	objs, err := objsFromYamlData(normalizedData)
 	if err != nil {
 		return nil, err
@@ -104,6 +137,16 @@ func (b baseResourceReconcileScope) hash() string {
 	return b.computedHash
 }
 
+func (b baseResourceReconcileScope) resource() addonsv1.ResourceRef {
+	return b.resourceRef
+}
+
+// forceOwnership returns true if the ownership of the fields managed by other field managers must be taken
+// when applying objects, according to the conflict policy of the ClusterResourceSet.
+func (b baseResourceReconcileScope) forceOwnership() bool {
+	return b.clusterResourceSet == nil || b.clusterResourceSet.Spec.ConflictPolicy != string(addonsv1.ClusterResourceSetConflictPolicyFail)
+}
+
 type reconcileStrategyScope struct {
 	baseResourceReconcileScope
 }
@@ -119,34 +162,77 @@ func (r *reconcileStrategyScope) apply(ctx context.Context, c client.Client) err
 }
 
 func (r *reconcileStrategyScope) applyObj(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
-	currentObj := &unstructured.Unstructured{}
-	currentObj.SetAPIVersion(obj.GetAPIVersion())
-	currentObj.SetKind(obj.GetKind())
-	err := c.Get(ctx, client.ObjectKeyFromObject(obj), currentObj)
-	if apierrors.IsNotFound(err) {
-		return createUnstructured(ctx, c, obj)
+	_, err := serverSideApply(ctx, c, obj, r.forceOwnership())
+	return err
+}
+
+func (r *reconcileStrategyScope) correctDrift(ctx context.Context, c client.Client) ([]string, error) {
+	drifted := []string{}
+	errList := []error{}
+	objs := r.objs()
+	for i := range objs {
+		changed, err := serverSideApply(ctx, c, &objs[i], r.forceOwnership())
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+		if changed {
+			drifted = append(drifted, fmt.Sprintf("%s %s", objs[i].GetKind(), klog.KObj(&objs[i])))
+		}
 	}
-	if err != nil {
-		return errors.Wrapf(
-			err,
-			"reading object %s %s",
-			obj.GroupVersionKind(),
-			klog.KObj(obj),
-		)
+
+	return drifted, kerrors.NewAggregate(errList)
+}
+
+func (r *reconcileStrategyScope) prune(ctx context.Context, c client.Client, applied []addonsv1.AppliedObject) error {
+	// Objects are compared ignoring the version, so changing the apiVersion of an object
+	// in the resource does not delete it.
+	objKey := func(o addonsv1.AppliedObject) string {
+		gk := schema.FromAPIVersionAndKind(o.APIVersion, o.Kind).GroupKind()
+		return fmt.Sprintf("%s %s/%s", gk, o.Namespace, o.Name)
+	}
+	current := map[string]bool{}
+	for _, o := range r.appliedObjects() {
+		current[objKey(o)] = true
 	}
 
-	patch := client.MergeFrom(currentObj.DeepCopy())
-	obj.SetResourceVersion(currentObj.GetResourceVersion())
-	if err = c.Patch(ctx, obj, patch); err != nil {
-		return errors.Wrapf(
-			err,
-			"patching object %s %s",
-			obj.GroupVersionKind(),
-			klog.KObj(obj),
-		)
+	errList := []error{}
+	for _, o := range applied {
+		if current[objKey(o)] {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(o.APIVersion)
+		obj.SetKind(o.Kind)
+		obj.SetNamespace(o.Namespace)
+		obj.SetName(o.Name)
+		if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			errList = append(errList, errors.Wrapf(
+				err,
+				"deleting object %s %s",
+				obj.GroupVersionKind(),
+				klog.KObj(obj),
+			))
+		}
 	}
 
-	return nil
+	return kerrors.NewAggregate(errList)
+}
+
+func (r *reconcileStrategyScope) appliedObjects() []addonsv1.AppliedObject {
+	objs := r.objs()
+	applied := make([]addonsv1.AppliedObject, 0, len(objs))
+	for i := range objs {
+		applied = append(applied, addonsv1.AppliedObject{
+			Resource:   r.resourceRef,
+			APIVersion: objs[i].GetAPIVersion(),
+			Kind:       objs[i].GetKind(),
+			Namespace:  objs[i].GetNamespace(),
+			Name:       objs[i].GetName(),
+		})
+	}
+	return applied
 }
 
 type reconcileApplyOnceScope struct {
@@ -162,13 +248,27 @@ func (r *reconcileApplyOnceScope) apply(ctx context.Context, c client.Client) er
 }
 
 func (r *reconcileApplyOnceScope) applyObj(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
-	// The create call is idempotent, so if the object already exists
-	// then do not consider it to be an error.
-	if err := createUnstructured(ctx, c, obj); err != nil && !apierrors.IsAlreadyExists(err) {
-		return err
+	// Objects which already exist are left unchanged.
+	currentObj := &unstructured.Unstructured{}
+	currentObj.SetAPIVersion(obj.GetAPIVersion())
+	currentObj.SetKind(obj.GetKind())
+	err := c.Get(ctx, client.ObjectKeyFromObject(obj), currentObj)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return errors.Wrapf(
+			err,
+			"reading object %s %s",
+			obj.GroupVersionKind(),
+			klog.KObj(obj),
+		)
 	}
 
-	return nil
+	// Create the object with server-side apply, so its fields are owned by the ClusterResourceSet field manager,
+	// without forcing the ownership, so an object concurrently created by another field manager is not overwritten.
+	_, err = applyObject(ctx, c, obj, false)
+	return err
 }
 
 type applyObj func(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error
@@ -184,3 +284,67 @@ func apply(ctx context.Context, c client.Client, applyObj applyObj, objs []unstr
 
 	return kerrors.NewAggregate(errList)
 }
+
+// serverSideApply applies obj to the target cluster using server-side apply, and returns true if the object
+// has been created or changed by the apply call. If force is false and some of the fields defined in obj are
+// managed by other field managers, the object is left unchanged and a conflict error is returned.
+// NOTE: The fields set by other field managers and not defined in obj are preserved; the fields previously
+// applied by the ClusterResourceSet controller and not defined in obj anymore are removed.
+func serverSideApply(ctx context.Context, c client.Client, obj *unstructured.Unstructured, force bool) (bool, error) {
+	currentObj := &unstructured.Unstructured{}
+	currentObj.SetAPIVersion(obj.GetAPIVersion())
+	currentObj.SetKind(obj.GetKind())
+	err := c.Get(ctx, client.ObjectKeyFromObject(obj), currentObj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, errors.Wrapf(
+			err,
+			"reading object %s %s",
+			obj.GroupVersionKind(),
+			klog.KObj(obj),
+		)
+	}
+	exists := err == nil
+
+	// Objects created or updated without server-side apply, e.g. by previous versions of the ClusterResourceSet
+	// controller, have fields owned by the "manager" field manager; drop them, so the fields removed from obj
+	// are removed from the object and are not reported as conflicts.
+	if exists {
+		if err := ssa.CleanUpManagedFieldsForSSAAdoption(ctx, c, currentObj, clusterResourceSetManagerName); err != nil {
+			return false, errors.Wrapf(
+				err,
+				"cleaning up managed fields of object %s %s",
+				obj.GroupVersionKind(),
+				klog.KObj(obj),
+			)
+		}
+	}
+
+	applied, err := applyObject(ctx, c, obj, force)
+	if err != nil {
+		return false, err
+	}
+
+	return !exists || currentObj.GetResourceVersion() != applied.GetResourceVersion(), nil
+}
+
+// applyObject applies obj to the target cluster using server-side apply with the ClusterResourceSet field manager,
+// and returns the applied object as returned by the API server.
+// NOTE: A copy of obj is applied, so the response of the API server does not change the objects in the scope.
+func applyObject(ctx context.Context, c client.Client, obj *unstructured.Unstructured, force bool) (*unstructured.Unstructured, error) {
+	applyObj := obj.DeepCopy()
+	applyObj.SetResourceVersion("")
+	applyObj.SetManagedFields(nil)
+	opts := []client.PatchOption{client.FieldOwner(clusterResourceSetManagerName)}
+	if force {
+		opts = append(opts, client.ForceOwnership)
+	}
+	if err := c.Patch(ctx, applyObj, client.Apply, opts...); err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"applying object %s %s",
+			obj.GroupVersionKind(),
+			klog.KObj(obj),
+		)
+	}
+	return applyObj, nil
+}
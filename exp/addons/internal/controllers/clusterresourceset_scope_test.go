@@ -22,10 +22,12 @@ import (
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 )
@@ -197,10 +199,12 @@ func TestReconcileApplyOnceScopeApplyObj(t *testing.T) {
 		name         string
 		existingObjs []client.Object
 		obj          *unstructured.Unstructured
+		wantApplied  bool
 		wantErr      string
 	}{
 		{
-			name: "object doesn't exist",
+			name:        "object doesn't exist",
+			wantApplied: true,
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
 					"apiVersion": "v1",
@@ -238,7 +242,19 @@ func TestReconcileApplyOnceScopeApplyObj(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gs := NewWithT(t)
 			ctx := context.Background()
-			client := fake.NewClientBuilder().WithObjects(tt.existingObjs...).Build()
+			// The fake client does not support server-side apply, so objects applied are created.
+			applied := false
+			client := fake.NewClientBuilder().WithObjects(tt.existingObjs...).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					gs.Expect(patch).To(Equal(client.Apply))
+					patchOptions := &client.PatchOptions{}
+					patchOptions.ApplyOptions(opts)
+					gs.Expect(patchOptions.FieldManager).To(Equal(clusterResourceSetManagerName))
+					gs.Expect(patchOptions.Force).To(BeNil())
+					applied = true
+					return c.Create(ctx, obj)
+				},
+			}).Build()
 			scope := &reconcileApplyOnceScope{}
 			err := scope.applyObj(ctx, client, tt.obj)
 			if tt.wantErr == "" {
@@ -246,6 +262,174 @@ func TestReconcileApplyOnceScopeApplyObj(t *testing.T) {
 			} else {
 				gs.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
 			}
+			gs.Expect(applied).To(Equal(tt.wantApplied))
+		})
+	}
+}
+
+func TestReconcileStrategyScopePrune(t *testing.T) {
+	resourceRef := addonsv1.ResourceRef{Name: "cp", Kind: "ConfigMap"}
+	appliedObject := func(apiVersion, kind, name string) addonsv1.AppliedObject {
+		return addonsv1.AppliedObject{
+			Resource:   resourceRef,
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Namespace:  "that-ns",
+			Name:       name,
+		}
+	}
+	scope := &reconcileStrategyScope{
+		baseResourceReconcileScope: baseResourceReconcileScope{
+			resourceRef: resourceRef,
+			normalizedObjs: []unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata": map[string]interface{}{
+							"name":      "my-cm",
+							"namespace": "that-ns",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		existingObjs []client.Object
+		applied      []addonsv1.AppliedObject
+		wantDeleted  []string
+		wantKept     []string
+	}{
+		{
+			name: "nothing to prune",
+			existingObjs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "my-cm", Namespace: "that-ns"}},
+			},
+			applied:  []addonsv1.AppliedObject{appliedObject("v1", "ConfigMap", "my-cm")},
+			wantKept: []string{"my-cm"},
+		},
+		{
+			name: "objects removed from the resource are deleted",
+			existingObjs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "my-cm", Namespace: "that-ns"}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "old-cm", Namespace: "that-ns"}},
+			},
+			applied: []addonsv1.AppliedObject{
+				appliedObject("v1", "ConfigMap", "my-cm"),
+				appliedObject("v1", "ConfigMap", "old-cm"),
+			},
+			wantDeleted: []string{"old-cm"},
+			wantKept:    []string{"my-cm"},
+		},
+		{
+			name: "objects already deleted are ignored",
+			existingObjs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "my-cm", Namespace: "that-ns"}},
+			},
+			applied: []addonsv1.AppliedObject{
+				appliedObject("v1", "ConfigMap", "my-cm"),
+				appliedObject("v1", "ConfigMap", "old-cm"),
+			},
+			wantKept: []string{"my-cm"},
+		},
+		{
+			name: "objects applied with a different version are not deleted",
+			existingObjs: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "my-cm", Namespace: "that-ns"}},
+			},
+			applied:  []addonsv1.AppliedObject{appliedObject("v2", "ConfigMap", "my-cm")},
+			wantKept: []string{"my-cm"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := NewWithT(t)
+			ctx := context.Background()
+			c := fake.NewClientBuilder().WithObjects(tt.existingObjs...).Build()
+			gs.Expect(scope.prune(ctx, c, tt.applied)).To(Succeed())
+			for _, name := range tt.wantDeleted {
+				err := c.Get(ctx, client.ObjectKey{Namespace: "that-ns", Name: name}, &corev1.ConfigMap{})
+				gs.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			}
+			for _, name := range tt.wantKept {
+				gs.Expect(c.Get(ctx, client.ObjectKey{Namespace: "that-ns", Name: name}, &corev1.ConfigMap{})).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestReconcileStrategyScopeAppliedObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	resourceRef := addonsv1.ResourceRef{Name: "cp", Kind: "ConfigMap"}
+	scope := &reconcileStrategyScope{
+		baseResourceReconcileScope: baseResourceReconcileScope{
+			resourceRef: resourceRef,
+			normalizedObjs: []unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata": map[string]interface{}{
+							"name":      "my-cm",
+							"namespace": "that-ns",
+						},
+					},
+				},
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "rbac.authorization.k8s.io/v1",
+						"kind":       "ClusterRole",
+						"metadata": map[string]interface{}{
+							"name": "my-role",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(scope.appliedObjects()).To(Equal([]addonsv1.AppliedObject{
+		{Resource: resourceRef, APIVersion: "v1", Kind: "ConfigMap", Namespace: "that-ns", Name: "my-cm"},
+		{Resource: resourceRef, APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "my-role"},
+	}))
+}
+
+func TestBaseResourceReconcileScopeForceOwnership(t *testing.T) {
+	tests := []struct {
+		name           string
+		conflictPolicy string
+		want           bool
+	}{
+		{
+			name:           "ownership is forced by default",
+			conflictPolicy: "",
+			want:           true,
+		},
+		{
+			name:           "ownership is forced with the Force conflict policy",
+			conflictPolicy: string(addonsv1.ClusterResourceSetConflictPolicyForce),
+			want:           true,
+		},
+		{
+			name:           "ownership is not forced with the Fail conflict policy",
+			conflictPolicy: string(addonsv1.ClusterResourceSetConflictPolicyFail),
+			want:           false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scope := baseResourceReconcileScope{
+				clusterResourceSet: &addonsv1.ClusterResourceSet{
+					Spec: addonsv1.ClusterResourceSetSpec{ConflictPolicy: tt.conflictPolicy},
+				},
+			}
+			g.Expect(scope.forceOwnership()).To(Equal(tt.want))
 		})
 	}
 }
@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// builtinTemplateVariable is the name of the variable with the builtin variables of a Cluster,
+// consistently with the builtin variables of ClusterClass patches.
+const builtinTemplateVariable = "builtin"
+
+// templateBuiltins are the builtin variables available when rendering the resources of a ClusterResourceSet.
+type templateBuiltins struct {
+	Cluster templateClusterBuiltins `json:"cluster"`
+}
+
+// templateClusterBuiltins are the builtin variables of a Cluster.
+type templateClusterBuiltins struct {
+	// Name is the name of the Cluster.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Cluster.
+	Namespace string `json:"namespace"`
+
+	// ControlPlaneEndpoint is the endpoint of the control plane of the Cluster.
+	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
+
+	// Network represents the network of the Cluster.
+	Network templateClusterNetworkBuiltins `json:"network"`
+
+	// Topology represents the topology of the Cluster; it is set only for Clusters using a ClusterClass.
+	Topology *templateClusterTopologyBuiltins `json:"topology,omitempty"`
+}
+
+// templateClusterNetworkBuiltins are the builtin variables of the network of a Cluster.
+type templateClusterNetworkBuiltins struct {
+	// Pods is the network ranges from which Pod networks are allocated.
+	Pods []string `json:"pods"`
+
+	// Services is the network ranges from which service VIPs are allocated.
+	Services []string `json:"services"`
+
+	// ServiceDomain is the domain name for services.
+	ServiceDomain string `json:"serviceDomain"`
+}
+
+// templateClusterTopologyBuiltins are the builtin variables of the topology of a Cluster.
+type templateClusterTopologyBuiltins struct {
+	// Version is the Kubernetes version of the Cluster.
+	Version string `json:"version"`
+
+	// Class is the name of the ClusterClass of the Cluster.
+	Class string `json:"class"`
+}
+
+// getTemplateData returns the values available when rendering the resources of a ClusterResourceSet for a Cluster:
+// the builtin variables of the Cluster and, for Clusters using a ClusterClass, the variables of the Cluster by name.
+// NOTE: Variables defined for all the patches take precedence over the variables defined for a specific patch.
+func getTemplateData(cluster *clusterv1.Cluster) (map[string]interface{}, error) {
+	builtins := templateBuiltins{
+		Cluster: templateClusterBuiltins{
+			Name:                 cluster.Name,
+			Namespace:            cluster.Namespace,
+			ControlPlaneEndpoint: cluster.Spec.ControlPlaneEndpoint,
+			Network: templateClusterNetworkBuiltins{
+				Pods:     []string{},
+				Services: []string{},
+			},
+		},
+	}
+	if network := cluster.Spec.ClusterNetwork; network != nil {
+		if network.Pods != nil && network.Pods.CIDRBlocks != nil {
+			builtins.Cluster.Network.Pods = network.Pods.CIDRBlocks
+		}
+		if network.Services != nil && network.Services.CIDRBlocks != nil {
+			builtins.Cluster.Network.Services = network.Services.CIDRBlocks
+		}
+		builtins.Cluster.Network.ServiceDomain = network.ServiceDomain
+	}
+
+	variables := map[string]interface{}{}
+	if cluster.Spec.Topology != nil {
+		builtins.Cluster.Topology = &templateClusterTopologyBuiltins{
+			Version: cluster.Spec.Topology.Version,
+			Class:   cluster.Spec.Topology.Class,
+		}
+		for _, variable := range cluster.Spec.Topology.Variables {
+			if variable.Name == builtinTemplateVariable {
+				continue
+			}
+			if _, ok := variables[variable.Name]; ok && variable.DefinitionFrom != "" {
+				continue
+			}
+			variables[variable.Name] = variable.Value
+		}
+	}
+	variables[builtinTemplateVariable] = builtins
+
+	// Convert the variables to their Go types, so they can be used in templates.
+	data, err := json.Marshal(variables)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal template variables")
+	}
+	templateData := map[string]interface{}{}
+	if err := json.Unmarshal(data, &templateData); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal template variables")
+	}
+	return templateData, nil
+}
+
+// renderTemplates renders each of the documents of a resource as a Go template.
+// NOTE: Referring to missing variables is an error, so that invalid objects are not applied to the Cluster.
+func renderTemplates(docs [][]byte, templateData map[string]interface{}) ([][]byte, error) {
+	rendered := make([][]byte, 0, len(docs))
+	for _, doc := range docs {
+		t, err := template.New("resource").Funcs(templateFuncs()).Option("missingkey=error").Parse(string(doc))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse template")
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, templateData); err != nil {
+			return nil, errors.Wrap(err, "failed to render template")
+		}
+		rendered = append(rendered, buf.Bytes())
+	}
+	return rendered, nil
+}
+
+// templateFuncs returns the functions available in templates; only hermetic functions are available,
+// so the rendered objects do not change between reconciles.
+func templateFuncs() template.FuncMap {
+	funcs := sprig.HermeticTxtFuncMap()
+	funcs["toYaml"] = func(v interface{}) string {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(bytes.TrimSuffix(data, []byte("\n")))
+	}
+	return funcs
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestGetTemplateData(t *testing.T) {
+	t.Run("returns the builtin variables of a Cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: "ns1"},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443},
+				ClusterNetwork: &clusterv1.ClusterNetwork{
+					Pods:          &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+					ServiceDomain: "cluster.local",
+				},
+			},
+		}
+		data, err := getTemplateData(cluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(data).To(Equal(map[string]interface{}{
+			"builtin": map[string]interface{}{
+				"cluster": map[string]interface{}{
+					"name":      "cluster1",
+					"namespace": "ns1",
+					"controlPlaneEndpoint": map[string]interface{}{
+						"host": "10.0.0.1",
+						"port": float64(6443),
+					},
+					"network": map[string]interface{}{
+						"pods":          []interface{}{"192.168.0.0/16"},
+						"services":      []interface{}{},
+						"serviceDomain": "cluster.local",
+					},
+				},
+			},
+		}))
+	})
+	t.Run("returns the topology variables of a Cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: "ns1"},
+			Spec: clusterv1.ClusterSpec{
+				Topology: &clusterv1.Topology{
+					Class:   "quick-start",
+					Version: "v1.28.0",
+					Variables: []clusterv1.ClusterVariable{
+						{Name: "cniConfig", DefinitionFrom: "patch1", Value: apiextensionsv1.JSON{Raw: []byte(`{"mtu": 1400}`)}},
+						{Name: "cniConfig", Value: apiextensionsv1.JSON{Raw: []byte(`{"mtu": 1500}`)}},
+						{Name: "cniConfig", DefinitionFrom: "patch2", Value: apiextensionsv1.JSON{Raw: []byte(`{"mtu": 1600}`)}},
+						{Name: "builtin", Value: apiextensionsv1.JSON{Raw: []byte(`"ignored"`)}},
+					},
+				},
+			},
+		}
+		data, err := getTemplateData(cluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(data).To(HaveKeyWithValue("cniConfig", map[string]interface{}{"mtu": float64(1500)}))
+		g.Expect(data).To(HaveKeyWithValue("builtin", HaveKeyWithValue("cluster", HaveKeyWithValue("topology", map[string]interface{}{
+			"class":   "quick-start",
+			"version": "v1.28.0",
+		}))))
+	})
+}
+
+func TestRenderTemplates(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: "ns1"},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443},
+			ClusterNetwork: &clusterv1.ClusterNetwork{
+				Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16", "fd00::/48"}},
+			},
+			Topology: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "cniConfig", Value: apiextensionsv1.JSON{Raw: []byte(`{"mtu": 1400}`)}},
+				},
+			},
+		},
+	}
+	templateData, err := getTemplateData(cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		docs    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "renders builtin and topology variables",
+			docs: []string{
+				"kind: ConfigMap\nmetadata:\n  name: {{ .builtin.cluster.name }}-config\n",
+				"data:\n  endpoint: {{ .builtin.cluster.controlPlaneEndpoint.host }}:{{ .builtin.cluster.controlPlaneEndpoint.port }}\n  pods: {{ join \",\" .builtin.cluster.network.pods }}\n  mtu: \"{{ .cniConfig.mtu }}\"\n",
+			},
+			want: []string{
+				"kind: ConfigMap\nmetadata:\n  name: cluster1-config\n",
+				"data:\n  endpoint: 10.0.0.1:6443\n  pods: 192.168.0.0/16,fd00::/48\n  mtu: \"1400\"\n",
+			},
+		},
+		{
+			name: "documents without templates are unchanged",
+			docs: []string{"kind: ConfigMap\n"},
+			want: []string{"kind: ConfigMap\n"},
+		},
+		{
+			name:    "fails for missing variables",
+			docs:    []string{"name: {{ .foo }}\n"},
+			wantErr: true,
+		},
+		{
+			name:    "fails for invalid templates",
+			docs:    []string{"name: {{ .builtin.cluster.name\n"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			docs := [][]byte{}
+			for _, doc := range tt.docs {
+				docs = append(docs, []byte(doc))
+			}
+			rendered, err := renderTemplates(docs, templateData)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			got := []string{}
+			for _, doc := range rendered {
+				got = append(got, string(doc))
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
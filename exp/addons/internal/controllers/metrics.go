@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(resourcesAppliedTotal)
+	ctrlmetrics.Registry.MustRegister(resourceApplyFailuresTotal)
+}
+
+// Metrics subsystem for the resources applied by the ClusterResourceSet controller.
+const clusterResourceSetSubsystem = "capi_clusterresourceset"
+
+var (
+	// resourcesAppliedTotal reports the number of times the resources of ClusterResourceSets have been applied to clusters.
+	resourcesAppliedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: clusterResourceSetSubsystem,
+		Name:      "resources_applied_total",
+		Help:      "Number of times resources have been applied to clusters, partitioned by ClusterResourceSet, cluster and resource.",
+	}, []string{"namespace", "clusterresourceset", "cluster_name", "resource_kind", "resource_name"})
+
+	// resourceApplyFailuresTotal reports the number of failures applying the resources of ClusterResourceSets to clusters,
+	// or keeping the applied objects in sync.
+	resourceApplyFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: clusterResourceSetSubsystem,
+		Name:      "resource_apply_failures_total",
+		Help:      "Number of failures applying resources to clusters, partitioned by ClusterResourceSet, cluster, resource and reason.",
+	}, []string{"namespace", "clusterresourceset", "cluster_name", "resource_kind", "resource_name", "reason"})
+)
+
+func resourceLabels(cluster *clusterv1.Cluster, crs *addonsv1.ClusterResourceSet, resource addonsv1.ResourceRef) prometheus.Labels {
+	return prometheus.Labels{
+		"namespace":          crs.Namespace,
+		"clusterresourceset": crs.Name,
+		"cluster_name":       cluster.Name,
+		"resource_kind":      resource.Kind,
+		"resource_name":      resource.Name,
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -52,8 +53,9 @@ func TestMain(m *testing.M) {
 		}
 
 		reconciler := ClusterResourceSetReconciler{
-			Client:  mgr.GetClient(),
-			Tracker: tracker,
+			Client:                 mgr.GetClient(),
+			Tracker:                tracker,
+			DriftDetectionInterval: time.Second,
 		}
 		if err = reconciler.SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: 1}); err != nil {
 			panic(fmt.Sprintf("Failed to set up cluster resource set reconciler: %v", err))
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// maxChartSize is the maximum size of the files in a chart archive, once decompressed.
+const maxChartSize = 100 * 1024 * 1024
+
+// LoadArchive loads a chart from a gzipped tar archive, as published in chart repositories.
+// Subcharts must be vendored in the charts directory of the archive, like `helm package` does.
+func LoadArchive(archive []byte) (*chart.Chart, error) {
+	// Check the size of the decompressed archive before loading it, given that the Helm loader reads it in memory.
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read chart archive")
+	}
+	defer gz.Close()
+	size, err := io.Copy(io.Discard, io.LimitReader(gz, maxChartSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read chart archive")
+	}
+	if size > maxChartSize {
+		return nil, errors.Errorf("chart archive exceeds the maximum size of %d bytes", maxChartSize)
+	}
+
+	c, err := loader.LoadArchive(bytes.NewReader(archive))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid chart")
+	}
+	return c, nil
+}
+
+// ReadValues parses values in YAML format.
+func ReadValues(data []byte) (map[string]interface{}, error) {
+	values, err := chartutil.ReadValues(data)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// MergeValues merges the override values on top of the base values, and returns the result, like Helm does when
+// values are passed with multiple --values flags.
+// Maps are merged recursively, while other values are replaced; a null value removes the key when rendering the chart.
+func MergeValues(base, override map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		if overrideMap, ok := v.(map[string]interface{}); ok {
+			if baseMap, ok := out[k].(map[string]interface{}); ok {
+				out[k] = MergeValues(baseMap, overrideMap)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+	"k8s.io/utils/lru"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cluster-api/exp/addons/internal/oci"
+)
+
+const (
+	// ociScheme is the scheme of the URLs of OCI repositories hosting charts.
+	ociScheme = "oci://"
+
+	helmChartLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+	maxRepositoryIndexSize  = 50 * 1024 * 1024
+	maxChartArchiveSize     = 20 * 1024 * 1024
+
+	// defaultTimeout is the timeout of the requests to fetch charts, if the HTTP client is not set.
+	defaultTimeout = 30 * time.Second
+
+	// maxCachedCharts is the maximum number of charts cached in memory.
+	maxCachedCharts = 50
+)
+
+// ChartGetter fetches charts from Helm chart repositories and OCI registries.
+// Charts are cached in memory by repository, name and version, given that published chart versions are immutable;
+// when the cache is full, the least recently used chart is evicted.
+// NOTE: Only public Helm chart repositories are supported; private OCI registries are supported using RegistryCredential.
+type ChartGetter struct {
+	// Client is the HTTP client used to fetch charts. Defaults to a client with a 30 seconds timeout.
+	Client *http.Client
+
+	// AllowedRepositories is the list of the URLs of the repositories charts can be fetched from, e.g.
+	// https://charts.example.com or oci://registry.example.com/charts; a URL allows all the URLs with the same
+	// scheme and host, and with a path below its path. If empty, charts can be fetched from any repository.
+	// NOTE: The URLs of chart archives listed in the index of a Helm chart repository, and the targets
+	// of redirects, must be allowed as well.
+	AllowedRepositories []string
+
+	// RegistryCredential returns the credentials to access private OCI registries.
+	// If nil, registries are accessed anonymously.
+	RegistryCredential oci.CredentialFunc
+
+	once     sync.Once
+	cache    *lru.Cache
+	client   *http.Client
+	registry *oci.Client
+}
+
+// Get returns a chart, fetching it from the repository if not cached.
+// The repository URL can be the URL of a Helm chart repository, e.g. https://charts.example.com, or
+// the URL of an OCI repository, e.g. oci://registry.example.com/charts.
+func (g *ChartGetter) Get(ctx context.Context, repoURL, name, version string) (*chart.Chart, error) {
+	g.once.Do(g.init)
+
+	key := fmt.Sprintf("%s %s %s", repoURL, name, version)
+	if c, ok := g.cache.Get(key); ok {
+		return c.(*chart.Chart), nil
+	}
+
+	if !g.isAllowed(repoURL) {
+		return nil, errors.Errorf("repository %s is not allowed", repoURL)
+	}
+
+	var archive []byte
+	var err error
+	if strings.HasPrefix(repoURL, ociScheme) {
+		archive, err = g.fetchFromRegistry(ctx, strings.TrimPrefix(repoURL, ociScheme), name, version)
+	} else {
+		archive, err = g.fetchFromRepository(ctx, repoURL, name, version)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch chart %s version %s from %s", name, version, repoURL)
+	}
+
+	c, err := LoadArchive(archive)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load chart %s version %s from %s", name, version, repoURL)
+	}
+
+	g.cache.Add(key, c)
+	return c, nil
+}
+
+func (g *ChartGetter) init() {
+	g.cache = lru.New(maxCachedCharts)
+
+	client := &http.Client{Timeout: defaultTimeout}
+	if g.Client != nil {
+		c := *g.Client
+		client = &c
+	}
+	checkRedirect := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !g.isAllowed(req.URL.String()) {
+			return errors.Errorf("redirect to %s is not allowed", req.URL.Redacted())
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	g.client = client
+	g.registry = &oci.Client{HTTPClient: client, Credential: g.RegistryCredential}
+}
+
+// isAllowed returns true if a URL matches one of the allowed repositories, or if there are no allowed repositories.
+func (g *ChartGetter) isAllowed(rawURL string) bool {
+	if len(g.AllowedRepositories) == 0 {
+		return true
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range g.AllowedRepositories {
+		a, err := url.Parse(allowed)
+		if err != nil || a.Scheme != u.Scheme || a.Host != u.Host {
+			continue
+		}
+		prefix := strings.TrimSuffix(a.Path, "/")
+		if prefix == "" || u.Path == prefix || strings.HasPrefix(u.Path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchFromRepository fetches a chart archive from a Helm chart repository, using the repository index
+// to find the URL of the archive.
+func (g *ChartGetter) fetchFromRepository(ctx context.Context, repoURL, name, version string) ([]byte, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(repoURL, "/") + "/")
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid repository URL")
+	}
+
+	indexURL := baseURL.ResolveReference(&url.URL{Path: "index.yaml"})
+	data, err := oci.Get(ctx, g.client, indexURL.String(), maxRepositoryIndexSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get repository index")
+	}
+
+	index := &repo.IndexFile{}
+	if err := yaml.Unmarshal(data, index); err != nil {
+		return nil, errors.Wrap(err, "failed to parse repository index")
+	}
+	index.SortEntries()
+	entry, err := index.Get(name, version)
+	if err != nil {
+		return nil, errors.Wrap(err, "chart version not found in repository index")
+	}
+	if len(entry.URLs) == 0 {
+		return nil, errors.New("repository index has no URLs for the chart")
+	}
+
+	archiveURL, err := baseURL.Parse(entry.URLs[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid chart URL %q", entry.URLs[0])
+	}
+	if !g.isAllowed(archiveURL.String()) {
+		return nil, errors.Errorf("chart URL %s is not allowed", archiveURL.Redacted())
+	}
+	archive, err := oci.Get(ctx, g.client, archiveURL.String(), maxChartArchiveSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get chart archive")
+	}
+	if entry.Digest != "" {
+		if err := oci.VerifyDigest(archive, "sha256:"+entry.Digest); err != nil {
+			return nil, err
+		}
+	}
+	return archive, nil
+}
+
+// fetchFromRegistry fetches a chart archive from an OCI registry, as pushed by `helm push`.
+func (g *ChartGetter) fetchFromRegistry(ctx context.Context, repository, name, version string) ([]byte, error) {
+	host, path, _ := strings.Cut(strings.TrimSuffix(repository, "/")+"/"+name, "/")
+	ref := oci.Reference{
+		Registry:   host,
+		Repository: path,
+		// Helm replaces + with _ in tags, because + is not allowed in OCI tags.
+		Tag: strings.ReplaceAll(version, "+", "_"),
+	}
+
+	manifest, _, err := g.registry.GetManifest(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != helmChartLayerMediaType {
+			continue
+		}
+		return g.registry.GetBlob(ctx, ref, layer, maxChartArchiveSize)
+	}
+
+	return nil, errors.Errorf("chart manifest has no layer of type %s", helmChartLayerMediaType)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestChartGetterRepository(t *testing.T) {
+	archive := chartArchive(t, map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: test\nversion: 1.0.0\n",
+	})
+	digest := sha256.Sum256(archive)
+
+	requests := map[string]int{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/charts/index.yaml":
+			fmt.Fprintf(w, `apiVersion: v1
+entries:
+  test:
+  - version: 1.0.0
+    urls: [test-1.0.0.tgz]
+    digest: %s
+  - version: 0.9.0
+    urls: [test-0.9.0.tgz]
+    digest: %s
+`, hex.EncodeToString(digest[:]), strings.Repeat("a", 64))
+		case "/charts/test-1.0.0.tgz", "/charts/test-0.9.0.tgz":
+			_, _ = w.Write(archive)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("gets a chart", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ChartGetter{Client: server.Client()}
+		chart, err := getter.Get(context.Background(), server.URL+"/charts", "test", "1.0.0")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(chart.Metadata.Name).To(Equal("test"))
+
+		// The chart is cached.
+		cached, err := getter.Get(context.Background(), server.URL+"/charts", "test", "1.0.0")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cached).To(BeIdenticalTo(chart))
+		g.Expect(requests["/charts/test-1.0.0.tgz"]).To(Equal(1))
+	})
+	t.Run("fails for unknown versions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ChartGetter{Client: server.Client()}
+		_, err := getter.Get(context.Background(), server.URL+"/charts", "test", "2.0.0")
+		g.Expect(err).To(MatchError(ContainSubstring("chart version not found")))
+	})
+	t.Run("fails if the digest does not match", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ChartGetter{Client: server.Client()}
+		_, err := getter.Get(context.Background(), server.URL+"/charts", "test", "0.9.0")
+		g.Expect(err).To(MatchError(ContainSubstring("digest mismatch")))
+	})
+	t.Run("gets a chart from an allowed repository", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ChartGetter{Client: server.Client(), AllowedRepositories: []string{server.URL + "/charts/"}}
+		_, err := getter.Get(context.Background(), server.URL+"/charts", "test", "1.0.0")
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+	t.Run("fails for repositories not allowed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ChartGetter{Client: server.Client(), AllowedRepositories: []string{server.URL + "/chart", "https://charts.example.com"}}
+		_, err := getter.Get(context.Background(), server.URL+"/charts", "test", "1.0.0")
+		g.Expect(err).To(MatchError(ContainSubstring("is not allowed")))
+	})
+	t.Run("fails if the repository does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ChartGetter{Client: server.Client()}
+		_, err := getter.Get(context.Background(), server.URL+"/other", "test", "1.0.0")
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestChartGetterRegistry(t *testing.T) {
+	archive := chartArchive(t, map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: test\nversion: 1.0.0+build\n",
+	})
+	sum := sha256.Sum256(archive)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:charts/test:pull" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token": "anonymous"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:charts/test:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/charts/test/manifests/1.0.0_build":
+			if !strings.Contains(r.Header.Get("Accept"), ocispec.MediaTypeImageManifest) {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			fmt.Fprintf(w, `{"layers": [{"mediaType": "application/vnd.cncf.helm.chart.provenance.v1.prov", "digest": "sha256:0000", "size": 4}, {"mediaType": %q, "digest": %q, "size": %d}]}`, helmChartLayerMediaType, digest, len(archive))
+		case "/v2/charts/test/blobs/" + digest:
+			_, _ = w.Write(archive)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repoURL := "oci://" + strings.TrimPrefix(server.URL, "https://") + "/charts"
+
+	t.Run("gets a chart", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ChartGetter{Client: server.Client()}
+		chart, err := getter.Get(context.Background(), repoURL, "test", "1.0.0+build")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(chart.Metadata.Version).To(Equal("1.0.0+build"))
+	})
+	t.Run("fails for unknown versions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ChartGetter{Client: server.Client()}
+		_, err := getter.Get(context.Background(), repoURL, "test", "2.0.0")
+		g.Expect(err).To(HaveOccurred())
+	})
+}
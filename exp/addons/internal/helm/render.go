@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultKubeVersion is the Kubernetes version used when rendering charts, if not specified.
+const defaultKubeVersion = "v1.28.0"
+
+// RenderOptions are the options used to render a chart.
+type RenderOptions struct {
+	// ReleaseName is the name of the release, available to templates as .Release.Name.
+	ReleaseName string
+
+	// ReleaseNamespace is the namespace of the release, available to templates as .Release.Namespace.
+	ReleaseNamespace string
+
+	// KubeVersion is the Kubernetes version of the target cluster, available to templates
+	// as .Capabilities.KubeVersion. Defaults to v1.28.0.
+	KubeVersion string
+
+	// Values are merged on top of the default values of the chart.
+	Values map[string]interface{}
+}
+
+// Render renders a chart and returns the resulting manifests, one per object; the CRDs of the chart and of its
+// subcharts come first, followed by the objects defined by the templates, sorted in the order Helm installs them.
+// Rendering uses the Helm engine, like `helm template` does, with the following differences:
+//   - The lookup function always returns an empty result, given that there is no connection to the cluster.
+//   - .Capabilities.APIVersions contains the API versions built into Kubernetes.
+//   - Hooks are rendered as regular objects if they run on install, and are dropped otherwise.
+//
+// NOTE: Templates using functions returning random values or the current time render different objects
+// on every call.
+func Render(c *chart.Chart, opts RenderOptions) ([][]byte, error) {
+	if c.Metadata.Type != "" && c.Metadata.Type != "application" {
+		return nil, errors.Errorf("chart %s of type %q can't be installed", c.Name(), c.Metadata.Type)
+	}
+
+	kubeVersionString := opts.KubeVersion
+	if kubeVersionString == "" {
+		kubeVersionString = defaultKubeVersion
+	}
+	kubeVersion, err := chartutil.ParseKubeVersion(kubeVersionString)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid Kubernetes version %q", kubeVersionString)
+	}
+	if c.Metadata.KubeVersion != "" && !chartutil.IsCompatibleRange(c.Metadata.KubeVersion, kubeVersion.String()) {
+		return nil, errors.Errorf("chart %s requires Kubernetes version %s, which is incompatible with Kubernetes %s", c.Name(), c.Metadata.KubeVersion, kubeVersion)
+	}
+	capabilities := chartutil.DefaultCapabilities.Copy()
+	capabilities.KubeVersion = *kubeVersion
+
+	// Rendering modifies the chart, e.g. to remove disabled subcharts, so it is done on a copy.
+	c = copyChart(c)
+
+	values := opts.Values
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	if err := chartutil.ProcessDependenciesWithMerge(c, values); err != nil {
+		return nil, errors.Wrapf(err, "failed to process the dependencies of chart %s", c.Name())
+	}
+	renderValues, err := chartutil.ToRenderValues(c, values, chartutil.ReleaseOptions{
+		Name:      opts.ReleaseName,
+		Namespace: opts.ReleaseNamespace,
+		Revision:  1,
+		IsInstall: true,
+	}, capabilities)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := engine.Render(c, renderValues)
+	if err != nil {
+		return nil, err
+	}
+	for name := range files {
+		if strings.HasSuffix(name, "NOTES.txt") {
+			delete(files, name)
+		}
+	}
+	hooks, objs, err := releaseutil.SortManifests(files, capabilities.APIVersions, releaseutil.InstallOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := [][]byte{}
+	for _, crd := range c.CRDObjects() {
+		manifests = appendManifests(manifests, releaseutil.SplitManifests(string(crd.File.Data)))
+	}
+	manifests = appendHooks(manifests, hooks, release.HookPreInstall)
+	for _, obj := range objs {
+		manifests = appendManifests(manifests, map[string]string{obj.Name: obj.Content})
+	}
+	manifests = appendHooks(manifests, hooks, release.HookPostInstall)
+	return manifests, nil
+}
+
+// copyChart returns a copy of a chart and of its subcharts; templates and files are shared, given that they are
+// not modified when rendering.
+func copyChart(c *chart.Chart) *chart.Chart {
+	out := *c
+	metadata := *c.Metadata
+	metadata.Dependencies = make([]*chart.Dependency, 0, len(c.Metadata.Dependencies))
+	for _, d := range c.Metadata.Dependencies {
+		dependency := *d
+		metadata.Dependencies = append(metadata.Dependencies, &dependency)
+	}
+	out.Metadata = &metadata
+
+	dependencies := make([]*chart.Chart, 0, len(c.Dependencies()))
+	for _, d := range c.Dependencies() {
+		dependencies = append(dependencies, copyChart(d))
+	}
+	out.SetDependencies(dependencies...)
+	return &out
+}
+
+// appendHooks appends the hooks running on an event to manifests, sorted by weight.
+func appendHooks(manifests [][]byte, hooks []*release.Hook, event release.HookEvent) [][]byte {
+	selected := []*release.Hook{}
+	for _, h := range hooks {
+		for _, e := range h.Events {
+			if e == event {
+				selected = append(selected, h)
+				break
+			}
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Weight < selected[j].Weight
+	})
+	for _, h := range selected {
+		manifests = appendManifests(manifests, map[string]string{h.Name: h.Manifest})
+	}
+	return manifests
+}
+
+// appendManifests appends the documents returned by releaseutil.SplitManifests to manifests, in order,
+// dropping documents without objects, e.g. with comments only.
+func appendManifests(manifests [][]byte, docs map[string]string) [][]byte {
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+	for _, k := range keys {
+		doc := strings.TrimSpace(docs[k])
+		var obj interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err == nil && obj == nil {
+			continue
+		}
+		manifests = append(manifests, []byte(doc+"\n"))
+	}
+	return manifests
+}
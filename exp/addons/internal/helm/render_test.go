@@ -0,0 +1,307 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"sort"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestLoadArchive(t *testing.T) {
+	t.Run("loads a chart", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c, err := LoadArchive(chartArchive(t, map[string]string{
+			"Chart.yaml":             "apiVersion: v2\nname: test\nversion: 1.0.0\n",
+			"values.yaml":            "replicas: 1\n",
+			"templates/cm.yaml":      "kind: ConfigMap\n",
+			"templates/_helpers.tpl": "",
+			"crds/crd.yaml":          "kind: CustomResourceDefinition\n",
+			"files/config.ini":       "",
+		}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c.Metadata.Name).To(Equal("test"))
+		g.Expect(c.Metadata.Version).To(Equal("1.0.0"))
+		g.Expect(c.Values).To(Equal(map[string]interface{}{"replicas": float64(1)}))
+		g.Expect(templateNames(c)).To(Equal([]string{"templates/_helpers.tpl", "templates/cm.yaml"}))
+		g.Expect(c.CRDObjects()).To(HaveLen(1))
+	})
+	t.Run("loads a chart with subcharts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c, err := LoadArchive(chartArchive(t, map[string]string{
+			"Chart.yaml":                    "apiVersion: v2\nname: test\nversion: 1.0.0\ndependencies:\n- name: other\n  version: 1.0.0\n",
+			"charts/other/Chart.yaml":       "apiVersion: v2\nname: other\nversion: 1.0.0\n",
+			"charts/other/templates/a.yaml": "",
+		}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c.Dependencies()).To(HaveLen(1))
+		g.Expect(c.Dependencies()[0].Name()).To(Equal("other"))
+	})
+	t.Run("fails without Chart.yaml", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := LoadArchive(chartArchive(t, map[string]string{
+			"values.yaml": "",
+		}))
+		g.Expect(err).To(HaveOccurred())
+	})
+	t.Run("fails for files outside of the chart", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := LoadArchive(chartArchive(t, map[string]string{
+			"Chart.yaml":    "apiVersion: v2\nname: test\nversion: 1.0.0\n",
+			"../../etc/foo": "",
+		}))
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestMergeValues(t *testing.T) {
+	g := NewWithT(t)
+
+	base := map[string]interface{}{
+		"image":    map[string]interface{}{"repository": "nginx", "tag": "1.0"},
+		"replicas": 1,
+		"debug":    true,
+	}
+	override := map[string]interface{}{
+		"image":    map[string]interface{}{"tag": "2.0"},
+		"replicas": 3,
+		"debug":    nil,
+	}
+	g.Expect(MergeValues(base, override)).To(Equal(map[string]interface{}{
+		"image":    map[string]interface{}{"repository": "nginx", "tag": "2.0"},
+		"replicas": 3,
+		"debug":    nil,
+	}))
+	// Base values are not modified.
+	g.Expect(base["replicas"]).To(Equal(1))
+	g.Expect(base["image"]).To(HaveKeyWithValue("tag", "1.0"))
+}
+
+func TestRender(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: "v2", Name: "test", Version: "1.0.0", AppVersion: "2.0.0"},
+		Values:   map[string]interface{}{"replicas": 1, "image": "nginx"},
+		Templates: []*chart.File{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{- define "test.labels" -}}app: {{ .Release.Name }}{{- end }}`)},
+			{Name: "templates/NOTES.txt", Data: []byte(`Thanks for installing {{ .Chart.Name }}`)},
+			{Name: "templates/deployment.yaml", Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+  namespace: {{ .Release.Namespace }}
+  labels:
+    {{- include "test.labels" . | nindent 4 }}
+spec:
+  replicas: {{ .Values.replicas }}
+  template:
+    spec:
+      containers:
+      - name: app
+        image: {{ .Values.image }}:{{ .Chart.AppVersion }}
+`)},
+			{Name: "templates/resources.yaml", Data: []byte(`# A comment only document.
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+data:
+  kube: {{ .Capabilities.KubeVersion.Minor | quote }}
+  {{- if .Capabilities.APIVersions.Has "apps/v1" }}
+  apps: "true"
+  {{- end }}
+{{- if .Values.serviceAccount }}
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ .Release.Name }}
+{{- end }}
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ .Release.Name }}-install
+  annotations:
+    helm.sh/hook: pre-install,pre-upgrade
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ .Release.Name }}-test
+  annotations:
+    helm.sh/hook: test
+`)},
+		},
+		Files: []*chart.File{
+			{Name: "crds/crd.yaml", Data: []byte("apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\n")},
+		},
+	}
+
+	t.Run("renders a chart", func(t *testing.T) {
+		g := NewWithT(t)
+
+		manifests, err := Render(c, RenderOptions{
+			ReleaseName:      "release",
+			ReleaseNamespace: "ns",
+			KubeVersion:      "v1.27.3",
+			Values:           map[string]interface{}{"replicas": 3},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(toStrings(manifests)).To(Equal([]string{
+			"apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\n",
+			"apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: release-install\n  annotations:\n    helm.sh/hook: pre-install,pre-upgrade\n",
+			"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: release-config\ndata:\n  kube: \"27\"\n  apps: \"true\"\n",
+			"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: release\n  namespace: ns\n  labels:\n    app: release\nspec:\n  replicas: 3\n  template:\n    spec:\n      containers:\n      - name: app\n        image: nginx:2.0.0\n",
+		}))
+	})
+	t.Run("renders the same manifests on every call", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts := RenderOptions{ReleaseName: "release", ReleaseNamespace: "ns"}
+		first, err := Render(c, opts)
+		g.Expect(err).ToNot(HaveOccurred())
+		second, err := Render(c, opts)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(second).To(Equal(first))
+	})
+	t.Run("renders subcharts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		parent := &chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: "v2", Name: "parent", Version: "1.0.0", Dependencies: []*chart.Dependency{
+				{Name: "enabled", Version: "1.0.0"},
+				{Name: "disabled", Version: "1.0.0", Condition: "disabled.enabled"},
+			}},
+			Values: map[string]interface{}{"enabled": map[string]interface{}{"name": "from-parent"}},
+		}
+		for _, name := range []string{"enabled", "disabled"} {
+			parent.AddDependency(&chart.Chart{
+				Metadata: &chart.Metadata{APIVersion: "v2", Name: name, Version: "1.0.0"},
+				Values:   map[string]interface{}{"name": name},
+				Templates: []*chart.File{
+					{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Values.name }}\n")},
+				},
+				Files: []*chart.File{
+					{Name: "crds/crd.yaml", Data: []byte("apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: " + name + "\n")},
+				},
+			})
+		}
+
+		opts := RenderOptions{Values: map[string]interface{}{"disabled": map[string]interface{}{"enabled": false}}}
+		manifests, err := Render(parent, opts)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(toStrings(manifests)).To(Equal([]string{
+			"apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: enabled\n",
+			"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: from-parent\n",
+		}))
+
+		// Rendering does not modify the chart.
+		g.Expect(parent.Dependencies()).To(HaveLen(2))
+		again, err := Render(parent, opts)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(again).To(Equal(manifests))
+	})
+	t.Run("fails if a required value is missing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := Render(&chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: "v2", Name: "test", Version: "1.0.0"},
+			Templates: []*chart.File{
+				{Name: "templates/cm.yaml", Data: []byte(`{{ required "foo is required" .Values.foo }}`)},
+			},
+		}, RenderOptions{})
+		g.Expect(err).To(MatchError(ContainSubstring("foo is required")))
+	})
+	t.Run("fails for incompatible Kubernetes versions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := Render(&chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: "v2", Name: "test", Version: "1.0.0", KubeVersion: ">= 1.29.0"},
+		}, RenderOptions{KubeVersion: "v1.28.3"})
+		g.Expect(err).To(MatchError(ContainSubstring("incompatible")))
+	})
+	t.Run("fails for library charts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := Render(&chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: "v2", Name: "test", Version: "1.0.0", Type: "library"},
+		}, RenderOptions{})
+		g.Expect(err).To(HaveOccurred())
+	})
+	t.Run("fails for invalid Kubernetes versions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := Render(c, RenderOptions{KubeVersion: "foo"})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func chartArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		if err := tw.WriteHeader(&tar.Header{Name: "test/" + name, Mode: 0600, Size: int64(len(files[name])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func templateNames(c *chart.Chart) []string {
+	names := []string{}
+	for _, f := range c.Templates {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func toStrings(manifests [][]byte) []string {
+	s := []string{}
+	for _, m := range manifests {
+		s = append(s, string(m))
+	}
+	return s
+}
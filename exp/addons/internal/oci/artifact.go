@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/lru"
+)
+
+const (
+	// maxLayerSize is the maximum size of a layer of an artifact.
+	maxLayerSize = 50 * 1024 * 1024
+
+	// maxArtifactSize is the maximum size of the manifests in an artifact, once decompressed.
+	maxArtifactSize = 100 * 1024 * 1024
+
+	// maxCachedArtifacts is the maximum number of artifacts, and of signature verifications, cached in memory.
+	maxCachedArtifacts = 100
+)
+
+// ArtifactGetter gets the Kubernetes manifests stored in artifacts in OCI registries, e.g. pushed with
+// `oras push` or `flux push artifact`.
+// Each layer of an artifact is either a YAML/JSON document, or a gzipped tar archive of YAML/JSON files;
+// other layers are ignored.
+// Artifacts are cached in memory by digest, given that the content of an artifact with a given digest is immutable;
+// when the cache is full, the least recently used artifact is evicted.
+type ArtifactGetter struct {
+	// Client is the client used to access registries.
+	Client *Client
+
+	once     sync.Once
+	cache    *lru.Cache
+	verified *lru.Cache
+}
+
+// Get returns the manifests stored in an artifact; the reference must be pinned by digest.
+// If public keys are provided, the artifact must have a cosign signature which can be verified by at least one of the keys,
+// otherwise an error wrapping ErrSignatureVerificationFailed is returned.
+func (g *ArtifactGetter) Get(ctx context.Context, reference string, publicKeys [][]byte) ([][]byte, error) {
+	g.once.Do(func() {
+		g.cache = lru.New(maxCachedArtifacts)
+		g.verified = lru.New(maxCachedArtifacts)
+	})
+
+	ref, err := ParseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Digest == "" {
+		return nil, errors.Errorf("invalid reference %q: artifacts must be pinned by digest", reference)
+	}
+	if !g.Client.IsAllowed(ref) {
+		return nil, errors.Errorf("registry %s/%s is not allowed", ref.Registry, ref.Repository)
+	}
+
+	if len(publicKeys) > 0 {
+		verificationKey := verificationKey(ref, publicKeys)
+		if _, ok := g.verified.Get(verificationKey); !ok {
+			if err := VerifySignature(ctx, g.Client, ref, publicKeys); err != nil {
+				return nil, err
+			}
+			g.verified.Add(verificationKey, true)
+		}
+	}
+
+	if manifests, ok := g.cache.Get(ref.Digest); ok {
+		return manifests.([][]byte), nil
+	}
+
+	manifest, _, err := g.Client.GetManifest(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := [][]byte{}
+	remaining := int64(maxArtifactSize)
+	for _, layer := range manifest.Layers {
+		var docs [][]byte
+		switch {
+		case isArchive(layer.MediaType):
+			data, err := g.Client.GetBlob(ctx, ref, layer, maxLayerSize)
+			if err != nil {
+				return nil, err
+			}
+			docs, err = readArchive(data, &remaining)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read layer %s of artifact %s", layer.Digest, ref)
+			}
+		case isManifest(layer.MediaType):
+			data, err := g.Client.GetBlob(ctx, ref, layer, maxLayerSize)
+			if err != nil {
+				return nil, err
+			}
+			docs = [][]byte{data}
+		default:
+			continue
+		}
+		manifests = append(manifests, docs...)
+	}
+	if len(manifests) == 0 {
+		return nil, errors.Errorf("artifact %s has no manifests", ref)
+	}
+
+	g.cache.Add(ref.Digest, manifests)
+	return manifests, nil
+}
+
+// isArchive returns true for the media types of gzipped tar layers, e.g. application/vnd.oci.image.layer.v1.tar+gzip.
+func isArchive(mediaType string) bool {
+	return strings.HasSuffix(mediaType, ".tar+gzip") || strings.HasSuffix(mediaType, ".tar.gzip")
+}
+
+// isManifest returns true for the media types of YAML/JSON layers, e.g. application/yaml.
+func isManifest(mediaType string) bool {
+	for _, suffix := range []string{"yaml", "json"} {
+		if strings.HasSuffix(mediaType, "/"+suffix) || strings.HasSuffix(mediaType, "+"+suffix) || strings.HasSuffix(mediaType, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readArchive returns the content of the YAML/JSON files in a gzipped tar archive, sorted by path.
+func readArchive(data []byte, remaining *int64) ([][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		switch path.Ext(hdr.Name) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+
+		content, err := io.ReadAll(io.LimitReader(tr, *remaining+1))
+		if err != nil {
+			return nil, err
+		}
+		*remaining -= int64(len(content))
+		if *remaining < 0 {
+			return nil, errors.Errorf("artifact exceeds the maximum size of %d bytes", maxArtifactSize)
+		}
+		files[path.Clean(hdr.Name)] = content
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	docs := make([][]byte, 0, len(names))
+	for _, name := range names {
+		docs = append(docs, files[name])
+	}
+	return docs, nil
+}
+
+// verificationKey identifies the verification of an artifact with a set of public keys.
+func verificationKey(ref Reference, publicKeys [][]byte) string {
+	hash := sha256.New()
+	for _, key := range publicKeys {
+		_, _ = hash.Write(key)
+		_, _ = hash.Write([]byte{0})
+	}
+	return fmt.Sprintf("%s/%s@%s %x", ref.Registry, ref.Repository, ref.Digest, hash.Sum(nil))
+}
@@ -0,0 +1,371 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+func TestArtifactGetter(t *testing.T) {
+	registry := newFakeRegistry(t)
+	defer registry.Close()
+
+	archive := tarGzip(t, map[string]string{
+		"manifests/b.yaml":  "kind: ConfigMap\nmetadata:\n  name: b\n",
+		"manifests/a.yaml":  "kind: ConfigMap\nmetadata:\n  name: a\n",
+		"manifests/README":  "not a manifest",
+		"manifests/c.json":  `{"kind": "ConfigMap", "metadata": {"name": "c"}}`,
+		"manifests/d.yml":   "kind: ConfigMap\nmetadata:\n  name: d\n",
+		"manifests/e.yamlx": "not a manifest",
+	})
+	digest := registry.push("addons/cni", "v1.0.0",
+		registry.blob("addons/cni", "application/vnd.cncf.flux.content.v1.tar+gzip", archive),
+		registry.blob("addons/cni", "application/yaml", []byte("kind: Namespace\n")),
+		registry.blob("addons/cni", "application/octet-stream", []byte("ignored")),
+	)
+	reference := fmt.Sprintf("%s/addons/cni@%s", registry.host(), digest)
+
+	key, publicKey := generateKey(t)
+	otherKey, otherPublicKey := generateKey(t)
+	registry.sign("addons/cni", reference, digest, otherKey, key)
+
+	t.Run("gets the manifests in an artifact", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ArtifactGetter{Client: registry.client()}
+		manifests, err := getter.Get(context.Background(), reference, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(toStrings(manifests)).To(Equal([]string{
+			"kind: ConfigMap\nmetadata:\n  name: a\n",
+			"kind: ConfigMap\nmetadata:\n  name: b\n",
+			`{"kind": "ConfigMap", "metadata": {"name": "c"}}`,
+			"kind: ConfigMap\nmetadata:\n  name: d\n",
+			"kind: Namespace\n",
+		}))
+
+		// The artifact is cached.
+		requests := registry.requests
+		cached, err := getter.Get(context.Background(), reference, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cached).To(Equal(manifests))
+		g.Expect(registry.requests).To(Equal(requests))
+	})
+	t.Run("verifies the signature of an artifact", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ArtifactGetter{Client: registry.client()}
+		_, err := getter.Get(context.Background(), reference, [][]byte{publicKey})
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = getter.Get(context.Background(), reference, [][]byte{otherPublicKey, publicKey})
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+	t.Run("fails if the signature can't be verified", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, unknownPublicKey := generateKey(t)
+		getter := &ArtifactGetter{Client: registry.client()}
+		_, err := getter.Get(context.Background(), reference, [][]byte{unknownPublicKey})
+		g.Expect(errors.Is(err, ErrSignatureVerificationFailed)).To(BeTrue())
+	})
+	t.Run("fails if the artifact is not signed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		unsignedDigest := registry.push("addons/cni", "v2.0.0",
+			registry.blob("addons/cni", "application/yaml", []byte("kind: Namespace\n")),
+		)
+		getter := &ArtifactGetter{Client: registry.client()}
+		_, err := getter.Get(context.Background(), fmt.Sprintf("%s/addons/cni@%s", registry.host(), unsignedDigest), [][]byte{publicKey})
+		g.Expect(errors.Is(err, ErrSignatureVerificationFailed)).To(BeTrue())
+	})
+	t.Run("fails if the signature is for another artifact", func(t *testing.T) {
+		g := NewWithT(t)
+
+		otherDigest := registry.push("addons/cni", "v3.0.0",
+			registry.blob("addons/cni", "application/yaml", []byte("kind: ConfigMap\n")),
+		)
+		// Sign the other artifact with a payload referring to the first artifact.
+		registry.sign("addons/cni", reference, otherDigest, key)
+		getter := &ArtifactGetter{Client: registry.client()}
+		_, err := getter.Get(context.Background(), fmt.Sprintf("%s/addons/cni@%s", registry.host(), otherDigest), [][]byte{publicKey})
+		g.Expect(errors.Is(err, ErrSignatureVerificationFailed)).To(BeTrue())
+	})
+	t.Run("fails if the reference is not pinned by digest", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ArtifactGetter{Client: registry.client()}
+		_, err := getter.Get(context.Background(), fmt.Sprintf("%s/addons/cni:v1.0.0", registry.host()), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+	t.Run("fails if the manifest does not match the digest", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ArtifactGetter{Client: registry.client()}
+		_, err := getter.Get(context.Background(), fmt.Sprintf("%s/addons/cni@sha256:%s", registry.host(), strings.Repeat("0", 64)), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+	t.Run("gets the manifests in an artifact from an allowed registry", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := registry.client()
+		client.AllowedRegistries = []string{"registry.example.com", registry.host() + "/addons"}
+		getter := &ArtifactGetter{Client: client}
+		_, err := getter.Get(context.Background(), reference, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+	t.Run("fails if the registry is not allowed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := registry.client()
+		client.AllowedRegistries = []string{"registry.example.com", registry.host() + "/other", registry.host() + "/add"}
+		getter := &ArtifactGetter{Client: client}
+		_, err := getter.Get(context.Background(), reference, nil)
+		g.Expect(err).To(MatchError(ContainSubstring("is not allowed")))
+	})
+}
+
+func TestArtifactGetterPrivateRegistry(t *testing.T) {
+	registry := newFakeRegistry(t)
+	defer registry.Close()
+	registry.username, registry.password = "user", "secret"
+
+	digest := registry.push("addons/cni", "v1.0.0",
+		registry.blob("addons/cni", "application/yaml", []byte("kind: Namespace\n")),
+	)
+	reference := fmt.Sprintf("%s/addons/cni@%s", registry.host(), digest)
+
+	writeConfig := func(t *testing.T, username, password string) string {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+		config := fmt.Sprintf(`{"auths": {"https://index.docker.io/v1/": {"auth": "Zm9vOmJhcg=="}, %q: {"auth": %q}}}`, registry.host(), auth)
+		if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("gets the manifests in an artifact using the credentials in a Docker config file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := registry.client()
+		client.Credential = DockerConfigCredential(writeConfig(t, "user", "secret"))
+		getter := &ArtifactGetter{Client: client}
+		manifests, err := getter.Get(context.Background(), reference, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(toStrings(manifests)).To(Equal([]string{"kind: Namespace\n"}))
+	})
+	t.Run("fails with invalid credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := registry.client()
+		client.Credential = DockerConfigCredential(writeConfig(t, "user", "wrong"))
+		getter := &ArtifactGetter{Client: client}
+		_, err := getter.Get(context.Background(), reference, nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+	t.Run("fails without credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		getter := &ArtifactGetter{Client: registry.client()}
+		_, err := getter.Get(context.Background(), reference, nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+	t.Run("fails if the Docker config file does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := registry.client()
+		client.Credential = DockerConfigCredential(filepath.Join(t.TempDir(), "config.json"))
+		getter := &ArtifactGetter{Client: client}
+		_, err := getter.Get(context.Background(), reference, nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+// fakeRegistry is an OCI registry serving artifacts from memory, requiring tokens; tokens are anonymous,
+// unless username and password are set.
+type fakeRegistry struct {
+	*httptest.Server
+	t         *testing.T
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	requests  int
+	username  string
+	password  string
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	t.Helper()
+
+	r := &fakeRegistry{t: t, manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+	r.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.requests++
+		if req.URL.Path == "/token" {
+			if username, password, _ := req.BasicAuth(); username != r.username || password != r.password {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token": "token"}`))
+			return
+		}
+		if req.Header.Get("Authorization") != "Bearer token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, r.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if data, ok := r.manifests[req.URL.Path]; ok {
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			_, _ = w.Write(data)
+			return
+		}
+		if data, ok := r.blobs[req.URL.Path]; ok {
+			_, _ = w.Write(data)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	return r
+}
+
+func (r *fakeRegistry) host() string {
+	return strings.TrimPrefix(r.URL, "https://")
+}
+
+func (r *fakeRegistry) client() *Client {
+	return &Client{HTTPClient: r.Server.Client()}
+}
+
+// blob stores a blob, and returns its descriptor.
+func (r *fakeRegistry) blob(repository, mediaType string, data []byte) ocispec.Descriptor {
+	digest := sha256Digest(data)
+	r.blobs[fmt.Sprintf("/v2/%s/blobs/%s", repository, digest)] = data
+	return ocispec.Descriptor{MediaType: mediaType, Digest: godigest.Digest(digest), Size: int64(len(data))}
+}
+
+// push stores a manifest with the given layers, and returns its digest.
+func (r *fakeRegistry) push(repository, tag string, layers ...ocispec.Descriptor) string {
+	data, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+		Layers:    layers,
+	})
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	digest := sha256Digest(data)
+	r.manifests[fmt.Sprintf("/v2/%s/manifests/%s", repository, tag)] = data
+	r.manifests[fmt.Sprintf("/v2/%s/manifests/%s", repository, digest)] = data
+	return digest
+}
+
+// sign stores cosign signatures for the artifact with the given digest, with a payload referring to reference.
+func (r *fakeRegistry) sign(repository, reference, digest string, keys ...*ecdsa.PrivateKey) {
+	ref, err := ParseReference(reference)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s/%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, ref.Registry, ref.Repository, ref.Digest))
+	hash := sha256.Sum256(payload)
+
+	layers := []ocispec.Descriptor{}
+	for _, key := range keys {
+		signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+		if err != nil {
+			r.t.Fatal(err)
+		}
+		layer := r.blob(repository, cosignSignatureMediaType, payload)
+		layer.Annotations = map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature)}
+		layers = append(layers, layer)
+	}
+	r.push(repository, strings.Replace(digest, ":", "-", 1)+".sig", layers...)
+}
+
+func generateKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func tarGzip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func toStrings(manifests [][]byte) []string {
+	s := []string{}
+	for _, m := range manifests {
+		s = append(s, string(m))
+	}
+	return s
+}
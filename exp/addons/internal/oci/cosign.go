@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	// cosignSignatureMediaType is the media type of the layers of cosign signatures.
+	cosignSignatureMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+	// cosignSignatureAnnotation is the annotation of the layers of cosign signatures with the base64 encoded signature.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	maxSignaturePayloadSize = 1024 * 1024
+)
+
+// ErrSignatureVerificationFailed signals that the signature of an artifact can't be verified.
+var ErrSignatureVerificationFailed = errors.New("signature verification failed")
+
+// VerifySignature verifies that an artifact pinned by digest has a cosign signature, as created by `cosign sign --key`,
+// which can be verified by at least one of the public keys in PEM format. ECDSA, RSA and Ed25519 keys are supported.
+// NOTE: Only signatures stored in the same repository of the artifact, with the default cosign tag
+// (sha256-<digest>.sig), are supported; keyless signatures and transparency logs are not supported.
+func VerifySignature(ctx context.Context, c *Client, ref Reference, publicKeys [][]byte) error {
+	verifiers := []signature.Verifier{}
+	for i, data := range publicKeys {
+		verifier, err := LoadVerifier(data)
+		if err != nil {
+			return errors.Wrapf(err, "invalid public key at index %d", i)
+		}
+		verifiers = append(verifiers, verifier)
+	}
+
+	signatureRef := Reference{
+		Registry:   ref.Registry,
+		Repository: ref.Repository,
+		Tag:        strings.Replace(ref.Digest, ":", "-", 1) + ".sig",
+	}
+	manifest, _, err := c.GetManifest(ctx, signatureRef)
+	if err != nil {
+		return errors.Wrapf(ErrSignatureVerificationFailed, "failed to get the signatures of artifact %s: %v", ref, err)
+	}
+
+	errList := []error{}
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != cosignSignatureMediaType {
+			continue
+		}
+		if err := verifySignatureLayer(ctx, c, ref, layer, verifiers); err != nil {
+			errList = append(errList, err)
+			continue
+		}
+		return nil
+	}
+	if len(errList) == 0 {
+		return errors.Wrapf(ErrSignatureVerificationFailed, "no signatures found for artifact %s", ref)
+	}
+	return errors.Wrapf(ErrSignatureVerificationFailed, "no valid signatures found for artifact %s: %v", ref, kerrors.NewAggregate(errList))
+}
+
+// verifySignatureLayer verifies a cosign signature, checking that the signed payload refers to the artifact.
+func verifySignatureLayer(ctx context.Context, c *Client, ref Reference, layer ocispec.Descriptor, verifiers []signature.Verifier) error {
+	sig, err := base64.StdEncoding.DecodeString(layer.Annotations[cosignSignatureAnnotation])
+	if err != nil || len(sig) == 0 {
+		return errors.Errorf("invalid signature in layer %s", layer.Digest)
+	}
+
+	data, err := c.GetBlob(ctx, ref, layer, maxSignaturePayloadSize)
+	if err != nil {
+		return err
+	}
+
+	verified := false
+	for _, verifier := range verifiers {
+		if err := verifier.VerifySignature(bytes.NewReader(sig), bytes.NewReader(data)); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return errors.Errorf("signature in layer %s can't be verified by any of the public keys", layer.Digest)
+	}
+
+	simpleSigning := payload.SimpleContainerImage{}
+	if err := json.Unmarshal(data, &simpleSigning); err != nil {
+		return errors.Wrapf(err, "invalid signature payload in layer %s", layer.Digest)
+	}
+	if simpleSigning.Critical.Type != payload.CosignSignatureType {
+		return errors.Errorf("signature in layer %s has an invalid type %q", layer.Digest, simpleSigning.Critical.Type)
+	}
+	if simpleSigning.Critical.Image.DockerManifestDigest != ref.Digest {
+		return errors.Errorf("signature in layer %s is for %s", layer.Digest, simpleSigning.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+// LoadVerifier loads a verifier for a public key in PEM format, as generated by `cosign generate-key-pair`.
+func LoadVerifier(data []byte) (signature.Verifier, error) {
+	key, err := cryptoutils.UnmarshalPEMToPublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return signature.LoadVerifier(key, crypto.SHA256)
+}
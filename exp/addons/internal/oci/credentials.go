@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"os"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/credentials"
+	"github.com/pkg/errors"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// dockerHubConfigKey is the key of the credentials for Docker Hub in Docker config files.
+const dockerHubConfigKey = "https://index.docker.io/v1/"
+
+// DockerConfigCredential returns a CredentialFunc reading the credentials to access registries from a Docker config file,
+// e.g. the .dockerconfigjson key of a kubernetes.io/dockerconfigjson Secret mounted in the controller Pod.
+// The file is read every time credentials are required, so updates to the file are picked up without restarting;
+// credential helpers and credential stores are not supported.
+func DockerConfigCredential(path string) CredentialFunc {
+	return func(_ context.Context, hostport string) (auth.Credential, error) {
+		f, err := os.Open(path) //nolint:gosec // The path is set by the operator.
+		if err != nil {
+			return auth.EmptyCredential, errors.Wrapf(err, "failed to read registry credentials from %s", path)
+		}
+		defer f.Close()
+
+		configFile, err := config.LoadFromReader(f)
+		if err != nil {
+			return auth.EmptyCredential, errors.Wrapf(err, "failed to parse registry credentials from %s", path)
+		}
+
+		serverAddress := hostport
+		if hostport == dockerHubRegistryHost {
+			serverAddress = dockerHubConfigKey
+		}
+		authConfig, err := credentials.NewFileStore(configFile).Get(serverAddress)
+		if err != nil {
+			return auth.EmptyCredential, err
+		}
+		return auth.Credential{
+			Username:     authConfig.Username,
+			Password:     authConfig.Password,
+			RefreshToken: authConfig.IdentityToken,
+			AccessToken:  authConfig.RegistryToken,
+		}, nil
+	}
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+const (
+	// defaultTimeout is the timeout of the requests to registries, if the HTTP client is not set.
+	defaultTimeout = 30 * time.Second
+
+	// dockerManifestMediaType is the media type of Docker image manifests, still used by some tools to push artifacts.
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+
+	maxManifestSize = 4 * 1024 * 1024
+
+	dockerHubRegistry     = "docker.io"
+	dockerHubRegistryHost = "registry-1.docker.io"
+)
+
+// digestRegexp matches sha256 digests, the only algorithm supported in references.
+var digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// Reference is a reference to an artifact in an OCI registry,
+// e.g. registry.example.com/addons/cni:v1.0.0 or registry.example.com/addons/cni@sha256:0123...
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses a reference to an artifact in an OCI registry; like for images, the registry
+// defaults to docker.io if the first component of the reference is not a host.
+func ParseReference(s string) (Reference, error) {
+	ref := Reference{}
+	name := s
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+		if !digestRegexp.MatchString(ref.Digest) {
+			return Reference{}, errors.Errorf("invalid reference %q: digest must be in the sha256:<hex> format", s)
+		}
+	}
+	if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name[i:], "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+		if ref.Tag == "" {
+			return Reference{}, errors.Errorf("invalid reference %q: tag is empty", s)
+		}
+	}
+
+	ref.Registry = dockerHubRegistry
+	if host, path, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		ref.Registry = host
+		name = path
+	}
+	ref.Repository = name
+	if ref.Repository == "" || strings.HasPrefix(ref.Repository, "/") || strings.HasSuffix(ref.Repository, "/") || strings.Contains(ref.Repository, "//") {
+		return Reference{}, errors.Errorf("invalid reference %q: repository is invalid", s)
+	}
+	if ref.Registry == dockerHubRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		return Reference{}, errors.Errorf("invalid reference %q: tag or digest is required", s)
+	}
+	return ref, nil
+}
+
+// String returns the reference in the canonical format.
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// CredentialFunc returns the credentials to access a registry, given its host and port, e.g. registry.example.com:5000;
+// auth.EmptyCredential is returned to access the registry anonymously.
+type CredentialFunc func(ctx context.Context, hostport string) (auth.Credential, error)
+
+// Client gets manifests and blobs from OCI registries, using the OCI distribution API.
+// The client supports the Docker registry token authentication and basic authentication, using the credentials
+// returned by Credential; tokens are cached until they expire.
+type Client struct {
+	// HTTPClient is the HTTP client used to access registries. Defaults to a client with a 30 seconds timeout.
+	HTTPClient *http.Client
+
+	// Credential returns the credentials to access private registries. If nil, registries are accessed anonymously.
+	Credential CredentialFunc
+
+	// AllowedRegistries is the list of the registries artifacts can be fetched from, e.g. registry.example.com
+	// or registry.example.com:5000/addons; a registry with a path allows all the repositories below the path.
+	// If empty, artifacts can be fetched from any registry.
+	// NOTE: Redirects, e.g. to the storage backend of a registry, and authorization servers are not checked.
+	AllowedRegistries []string
+
+	once   sync.Once
+	client *auth.Client
+}
+
+func (c *Client) init() {
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	c.client = &auth.Client{
+		Client: httpClient,
+		Cache:  auth.NewCache(),
+	}
+	if c.Credential != nil {
+		c.client.Credential = c.Credential
+	}
+}
+
+// IsAllowed returns true if the repository of a reference matches one of the allowed registries,
+// or if there are no allowed registries.
+func (c *Client) IsAllowed(ref Reference) bool {
+	if len(c.AllowedRegistries) == 0 {
+		return true
+	}
+	for _, allowed := range c.AllowedRegistries {
+		host, path, _ := strings.Cut(strings.TrimSuffix(allowed, "/"), "/")
+		if host != ref.Registry {
+			continue
+		}
+		if path == "" || ref.Repository == path || strings.HasPrefix(ref.Repository, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// repository returns the client for the repository of a reference.
+func (c *Client) repository(ref Reference) (*remote.Repository, error) {
+	c.once.Do(c.init)
+
+	if !c.IsAllowed(ref) {
+		return nil, errors.Errorf("registry %s/%s is not allowed", ref.Registry, ref.Repository)
+	}
+	repositoryRef := registry.Reference{Registry: registryHost(ref.Registry), Repository: ref.Repository}
+	if err := repositoryRef.ValidateRepository(); err != nil {
+		return nil, err
+	}
+	return &remote.Repository{
+		Client:             c.client,
+		Reference:          repositoryRef,
+		ManifestMediaTypes: []string{ocispec.MediaTypeImageManifest, dockerManifestMediaType},
+		MaxMetadataBytes:   maxManifestSize,
+	}, nil
+}
+
+// GetManifest gets an image manifest by tag or digest, and returns the manifest together with its digest.
+// If the reference has a digest, the digest of the manifest is verified.
+func (c *Client) GetManifest(ctx context.Context, ref Reference) (*ocispec.Manifest, string, error) {
+	repository, err := c.repository(ref)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tagOrDigest := ref.Digest
+	if tagOrDigest == "" {
+		tagOrDigest = ref.Tag
+	}
+	desc, rc, err := repository.FetchReference(ctx, tagOrDigest)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to get manifest %s", ref)
+	}
+	defer rc.Close()
+
+	if desc.Size > maxManifestSize {
+		return nil, "", errors.Errorf("manifest %s exceeds the maximum size of %d bytes", ref, maxManifestSize)
+	}
+	data, err := content.ReadAll(rc, desc)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to read manifest %s", ref)
+	}
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageManifest, dockerManifestMediaType:
+	default:
+		return nil, "", errors.Errorf("unsupported media type %s for manifest %s", desc.MediaType, ref)
+	}
+
+	manifest := &ocispec.Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, "", errors.Wrapf(err, "failed to parse manifest %s", ref)
+	}
+	return manifest, desc.Digest.String(), nil
+}
+
+// GetBlob gets a blob, e.g. a layer of a manifest, verifying its size and digest.
+func (c *Client) GetBlob(ctx context.Context, ref Reference, desc ocispec.Descriptor, maxSize int64) ([]byte, error) {
+	repository, err := c.repository(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if desc.Size > maxSize {
+		return nil, errors.Errorf("blob %s exceeds the maximum size of %d bytes", desc.Digest, maxSize)
+	}
+	data, err := content.FetchAll(ctx, repository.Blobs(), desc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get blob %s from %s/%s", desc.Digest, ref.Registry, ref.Repository)
+	}
+	return data, nil
+}
+
+// Get gets a resource over HTTP, failing if the size of the resource exceeds maxSize.
+func Get(ctx context.Context, client *http.Client, resourceURL string, maxSize int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resourceURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get %s: %s", resourceURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", resourceURL)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, errors.Errorf("%s exceeds the maximum size of %d bytes", resourceURL, maxSize)
+	}
+	return data, nil
+}
+
+// VerifyDigest verifies the digest of data, e.g. sha256:0123456789abcdef.
+func VerifyDigest(data []byte, d string) error {
+	expected, err := digest.Parse(d)
+	if err != nil {
+		return errors.Wrapf(err, "invalid digest %q", d)
+	}
+	if actual := expected.Algorithm().FromBytes(data); actual != expected {
+		return errors.Errorf("digest mismatch: expected %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+func registryHost(registry string) string {
+	if registry == dockerHubRegistry {
+		return dockerHubRegistryHost
+	}
+	return registry
+}
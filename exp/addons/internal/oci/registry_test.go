@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	tests := []struct {
+		name      string
+		reference string
+		want      Reference
+		wantErr   bool
+	}{
+		{
+			name:      "reference with tag",
+			reference: "registry.example.com/addons/cni:v1.0.0",
+			want:      Reference{Registry: "registry.example.com", Repository: "addons/cni", Tag: "v1.0.0"},
+		},
+		{
+			name:      "reference with digest",
+			reference: "registry.example.com/addons/cni@" + digest,
+			want:      Reference{Registry: "registry.example.com", Repository: "addons/cni", Digest: digest},
+		},
+		{
+			name:      "reference with tag and digest",
+			reference: "registry.example.com/addons/cni:v1.0.0@" + digest,
+			want:      Reference{Registry: "registry.example.com", Repository: "addons/cni", Tag: "v1.0.0", Digest: digest},
+		},
+		{
+			name:      "reference to a registry with a port",
+			reference: "localhost:5000/cni@" + digest,
+			want:      Reference{Registry: "localhost:5000", Repository: "cni", Digest: digest},
+		},
+		{
+			name:      "reference to docker hub",
+			reference: "cni:v1.0.0",
+			want:      Reference{Registry: "docker.io", Repository: "library/cni", Tag: "v1.0.0"},
+		},
+		{
+			name:      "reference to docker hub with an organization",
+			reference: "addons/cni:v1.0.0",
+			want:      Reference{Registry: "docker.io", Repository: "addons/cni", Tag: "v1.0.0"},
+		},
+		{
+			name:      "reference without tag and digest",
+			reference: "registry.example.com/addons/cni",
+			wantErr:   true,
+		},
+		{
+			name:      "reference with an invalid digest",
+			reference: "registry.example.com/addons/cni@sha256:foo",
+			wantErr:   true,
+		},
+		{
+			name:      "reference with an empty repository",
+			reference: "registry.example.com/:v1.0.0",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := ParseReference(tt.reference)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
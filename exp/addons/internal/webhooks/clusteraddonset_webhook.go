@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blang/semver/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+// ClusterAddonSet implements a validation and defaulting webhook for ClusterAddonSet.
+type ClusterAddonSet struct{}
+
+func (webhook *ClusterAddonSet) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&addonsv1.ClusterAddonSet{}).
+		WithDefaulter(webhook).
+		WithValidator(webhook).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-addons-cluster-x-k8s-io-v1beta1-clusteraddonset,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=addons.cluster.x-k8s.io,resources=clusteraddonsets,versions=v1beta1,name=validation.clusteraddonset.addons.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-addons-cluster-x-k8s-io-v1beta1-clusteraddonset,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=addons.cluster.x-k8s.io,resources=clusteraddonsets,versions=v1beta1,name=default.clusteraddonset.addons.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+var _ webhook.CustomDefaulter = &ClusterAddonSet{}
+var _ webhook.CustomValidator = &ClusterAddonSet{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+func (webhook *ClusterAddonSet) Default(_ context.Context, obj runtime.Object) error {
+	addonSet, ok := obj.(*addonsv1.ClusterAddonSet)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a ClusterAddonSet but got a %T", obj))
+	}
+	// ClusterAddonSet UpgradePhase defaults to AfterControlPlaneUpgrade.
+	if addonSet.Spec.UpgradePhase == "" {
+		addonSet.Spec.UpgradePhase = addonsv1.AddonUpgradePhaseAfterControlPlaneUpgrade
+	}
+	return nil
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *ClusterAddonSet) ValidateCreate(_ context.Context, newObj runtime.Object) (admission.Warnings, error) {
+	newAddonSet, ok := newObj.(*addonsv1.ClusterAddonSet)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a ClusterAddonSet but got a %T", newObj))
+	}
+	return nil, webhook.validate(newAddonSet)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *ClusterAddonSet) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	newAddonSet, ok := newObj.(*addonsv1.ClusterAddonSet)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a ClusterAddonSet but got a %T", newObj))
+	}
+	return nil, webhook.validate(newAddonSet)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *ClusterAddonSet) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (webhook *ClusterAddonSet) validate(newAddonSet *addonsv1.ClusterAddonSet) error {
+	// NOTE: ClusterAddonSet is behind ClusterAddon feature gate flag; the web hook
+	// must prevent creating new objects when the feature flag is disabled.
+	if !feature.Gates.Enabled(feature.ClusterAddon) {
+		return field.Forbidden(
+			field.NewPath("spec"),
+			"can be set only if the ClusterAddon feature flag is enabled",
+		)
+	}
+	var allErrs field.ErrorList
+
+	// Validate selector parses as Selector, and that it isn't empty as null selectors do not select any objects.
+	selector, err := metav1.LabelSelectorAsSelector(&newAddonSet.Spec.ClusterSelector)
+	if err != nil {
+		allErrs = append(
+			allErrs,
+			field.Invalid(field.NewPath("spec", "clusterSelector"), newAddonSet.Spec.ClusterSelector, err.Error()),
+		)
+	} else if selector.Empty() {
+		allErrs = append(
+			allErrs,
+			field.Invalid(field.NewPath("spec", "clusterSelector"), newAddonSet.Spec.ClusterSelector, "selector must not be empty"),
+		)
+	}
+
+	if newAddonSet.Spec.ReadinessTimeout != nil && newAddonSet.Spec.ReadinessTimeout.Duration <= 0 {
+		allErrs = append(
+			allErrs,
+			field.Invalid(field.NewPath("spec", "readinessTimeout"), newAddonSet.Spec.ReadinessTimeout.Duration.String(), "must be greater than zero"),
+		)
+	}
+
+	versions := sets.Set[string]{}
+	for i, version := range newAddonSet.Spec.Versions {
+		fldPath := field.NewPath("spec", "versions").Index(i)
+		if versions.Has(version.Version) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("version"), version.Version))
+		}
+		versions.Insert(version.Version)
+
+		if _, err := semver.ParseRange(version.KubernetesVersions); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("kubernetesVersions"), version.KubernetesVersions, fmt.Sprintf("must be a valid semver range: %v", err)))
+		}
+
+		for j, resource := range version.Resources {
+			if resource.Kind != string(addonsv1.SecretClusterResourceSetResourceKind) && resource.Kind != string(addonsv1.ConfigMapClusterResourceSetResourceKind) {
+				allErrs = append(
+					allErrs,
+					field.NotSupported(fldPath.Child("resources").Index(j).Child("kind"), resource.Kind,
+						[]string{string(addonsv1.SecretClusterResourceSetResourceKind), string(addonsv1.ConfigMapClusterResourceSetResourceKind)}),
+				)
+			}
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(addonsv1.GroupVersion.WithKind("ClusterAddonSet").GroupKind(), newAddonSet.Name, allErrs)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/webhooks/util"
+)
+
+func newClusterAddonSet() *addonsv1.ClusterAddonSet {
+	return &addonsv1.ClusterAddonSet{
+		Spec: addonsv1.ClusterAddonSetSpec{
+			ClusterSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"foo": "bar"},
+			},
+			Versions: []addonsv1.AddonVersion{
+				{
+					Version:            "v3.26.1",
+					KubernetesVersions: ">=1.27.0 <1.29.0",
+					Resources:          []addonsv1.ResourceRef{{Name: "calico-v3.26.1", Kind: "ConfigMap"}},
+				},
+			},
+		},
+	}
+}
+
+func TestClusterAddonSetDefault(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterAddon, true)()
+	g := NewWithT(t)
+
+	addonSet := newClusterAddonSet()
+	webhook := ClusterAddonSet{}
+	t.Run("for ClusterAddonSet", util.CustomDefaultValidateTest(ctx, addonSet.DeepCopy(), &webhook))
+	g.Expect(webhook.Default(ctx, addonSet)).To(Succeed())
+
+	g.Expect(addonSet.Spec.UpgradePhase).To(Equal(addonsv1.AddonUpgradePhaseAfterControlPlaneUpgrade))
+}
+
+func TestClusterAddonSetValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		mutate    func(*addonsv1.ClusterAddonSet)
+		expectErr bool
+	}{
+		{
+			name:   "should accept a valid ClusterAddonSet",
+			mutate: func(*addonsv1.ClusterAddonSet) {},
+		},
+		{
+			name: "should return error for invalid selector",
+			mutate: func(s *addonsv1.ClusterAddonSet) {
+				s.Spec.ClusterSelector.MatchLabels = map[string]string{"-123-foo": "bar"}
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error for empty selector",
+			mutate: func(s *addonsv1.ClusterAddonSet) {
+				s.Spec.ClusterSelector = metav1.LabelSelector{}
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error for a negative readiness timeout",
+			mutate: func(s *addonsv1.ClusterAddonSet) {
+				s.Spec.ReadinessTimeout = &metav1.Duration{Duration: -time.Minute}
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error for duplicate versions",
+			mutate: func(s *addonsv1.ClusterAddonSet) {
+				s.Spec.Versions = append(s.Spec.Versions, s.Spec.Versions[0])
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error for an invalid Kubernetes versions range",
+			mutate: func(s *addonsv1.ClusterAddonSet) {
+				s.Spec.Versions[0].KubernetesVersions = "foo"
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error for unsupported resource kinds",
+			mutate: func(s *addonsv1.ClusterAddonSet) {
+				s.Spec.Versions[0].Resources[0].Kind = "HelmChart"
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterAddon, true)()
+			g := NewWithT(t)
+
+			addonSet := newClusterAddonSet()
+			tt.mutate(addonSet)
+			webhook := ClusterAddonSet{}
+
+			warnings, err := webhook.ValidateCreate(ctx, addonSet)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(warnings).To(BeEmpty())
+
+			warnings, err = webhook.ValidateUpdate(ctx, addonSet, addonSet)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(warnings).To(BeEmpty())
+		})
+	}
+}
+
+func TestClusterAddonSetFeatureGateDisabled(t *testing.T) {
+	// NOTE: ClusterAddon feature flag is disabled by default, thus preventing to create ClusterAddonSets.
+	g := NewWithT(t)
+
+	webhook := ClusterAddonSet{}
+	warnings, err := webhook.ValidateCreate(ctx, newClusterAddonSet())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(warnings).To(BeEmpty())
+}
@@ -19,20 +19,29 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"reflect"
+	"text/template"
 
+	"github.com/Masterminds/sprig/v3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/exp/addons/internal/oci"
 	"sigs.k8s.io/cluster-api/feature"
 )
 
+// defaultHelmValuesKey is the default key of the Secrets/ConfigMaps with values for Helm charts.
+const defaultHelmValuesKey = "values.yaml"
+
 // ClusterResourceSet implements a validation and defaulting webhook for ClusterResourceSet.
 type ClusterResourceSet struct{}
 
@@ -60,6 +69,20 @@ func (webhook *ClusterResourceSet) Default(_ context.Context, obj runtime.Object
 	if crs.Spec.Strategy == "" {
 		crs.Spec.Strategy = string(addonsv1.ClusterResourceSetStrategyApplyOnce)
 	}
+	for i := range crs.Spec.HelmCharts {
+		helmChart := &crs.Spec.HelmCharts[i]
+		if helmChart.ReleaseName == "" {
+			helmChart.ReleaseName = helmChart.Name
+		}
+		if helmChart.ReleaseNamespace == "" {
+			helmChart.ReleaseNamespace = metav1.NamespaceDefault
+		}
+		for j := range helmChart.ValuesFrom {
+			if helmChart.ValuesFrom[j].Key == "" {
+				helmChart.ValuesFrom[j].Key = defaultHelmValuesKey
+			}
+		}
+	}
 	return nil
 }
 
@@ -132,9 +155,153 @@ func (webhook *ClusterResourceSet) validate(oldCRS, newCRS *addonsv1.ClusterReso
 		)
 	}
 
+	for i, resource := range newCRS.Spec.Resources {
+		if resource.Kind == string(addonsv1.HelmChartClusterResourceSetResourceKind) || resource.Kind == string(addonsv1.OCIArtifactClusterResourceSetResourceKind) {
+			allErrs = append(
+				allErrs,
+				field.NotSupported(field.NewPath("spec", "resources").Index(i).Child("kind"), resource.Kind,
+					[]string{string(addonsv1.SecretClusterResourceSetResourceKind), string(addonsv1.ConfigMapClusterResourceSetResourceKind)}),
+			)
+		}
+	}
+
+	allErrs = append(allErrs, validateOCIArtifacts(newCRS.Spec.OCIArtifacts, field.NewPath("spec", "ociArtifacts"))...)
+	allErrs = append(allErrs, validateHelmCharts(newCRS.Spec.HelmCharts, field.NewPath("spec", "helmCharts"))...)
+	allErrs = append(allErrs, validateResourceDependencies(newCRS, field.NewPath("spec", "resourceDependencies"))...)
+
+	dependsOn := sets.Set[string]{}
+	for i, name := range newCRS.Spec.DependsOn {
+		fldPath := field.NewPath("spec", "dependsOn").Index(i)
+		switch {
+		case name == newCRS.Name:
+			allErrs = append(allErrs, field.Invalid(fldPath, name, "a ClusterResourceSet can't depend on itself"))
+		case dependsOn.Has(name):
+			allErrs = append(allErrs, field.Duplicate(fldPath, name))
+		}
+		dependsOn.Insert(name)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 
 	return apierrors.NewInvalid(addonsv1.GroupVersion.WithKind("ClusterResourceSet").GroupKind(), newCRS.Name, allErrs)
 }
+
+func validateOCIArtifacts(ociArtifacts []addonsv1.OCIArtifactSource, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := sets.Set[string]{}
+	for i, ociArtifact := range ociArtifacts {
+		if names.Has(ociArtifact.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), ociArtifact.Name))
+		}
+		names.Insert(ociArtifact.Name)
+
+		ref, err := oci.ParseReference(ociArtifact.Reference)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("reference"), ociArtifact.Reference, err.Error()))
+		} else if ref.Digest == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("reference"), ociArtifact.Reference, "must be pinned by digest"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateHelmCharts(helmCharts []addonsv1.HelmChartSource, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := sets.Set[string]{}
+	for i, helmChart := range helmCharts {
+		if names.Has(helmChart.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), helmChart.Name))
+		}
+		names.Insert(helmChart.Name)
+
+		repoURL, err := url.Parse(helmChart.RepoURL)
+		if err != nil || repoURL.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("repoURL"), helmChart.RepoURL, "must be a valid URL"))
+		} else if repoURL.Scheme != "http" && repoURL.Scheme != "https" && repoURL.Scheme != "oci" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("repoURL"), helmChart.RepoURL, "scheme must be one of http, https or oci"))
+		}
+
+		if helmChart.ReleaseNamespace != "" {
+			for _, msg := range validation.IsDNS1123Label(helmChart.ReleaseNamespace) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("releaseNamespace"), helmChart.ReleaseNamespace, msg))
+			}
+		}
+
+		if helmChart.ValuesTemplate != "" {
+			if _, err := template.New("values").Funcs(sprig.HermeticTxtFuncMap()).Parse(helmChart.ValuesTemplate); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("valuesTemplate"), helmChart.ValuesTemplate, fmt.Sprintf("failed to parse template: %v", err)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateResourceDependencies(crs *addonsv1.ClusterResourceSet, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	resources := sets.Set[addonsv1.ResourceRef]{}
+	resources.Insert(crs.Spec.Resources...)
+	for i := range crs.Spec.OCIArtifacts {
+		resources.Insert(crs.Spec.OCIArtifacts[i].ResourceRef())
+	}
+	for i := range crs.Spec.HelmCharts {
+		resources.Insert(crs.Spec.HelmCharts[i].ResourceRef())
+	}
+
+	dependencies := map[addonsv1.ResourceRef][]addonsv1.ResourceRef{}
+	for i, dependency := range crs.Spec.ResourceDependencies {
+		if !resources.Has(dependency.Resource) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("resource"), dependency.Resource, "must refer to a resource, an OCI artifact or a Helm chart of the ClusterResourceSet"))
+		}
+		if _, ok := dependencies[dependency.Resource]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("resource"), dependency.Resource))
+		}
+		for j, dependsOn := range dependency.DependsOn {
+			switch {
+			case dependsOn == dependency.Resource:
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("dependsOn").Index(j), dependsOn, "a resource can't depend on itself"))
+			case !resources.Has(dependsOn):
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("dependsOn").Index(j), dependsOn, "must refer to a resource, an OCI artifact or a Helm chart of the ClusterResourceSet"))
+			}
+		}
+		dependencies[dependency.Resource] = append(dependencies[dependency.Resource], dependency.DependsOn...)
+	}
+
+	// Check for cycles, visiting the dependencies depth-first.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[addonsv1.ResourceRef]int{}
+	var hasCycle func(resource addonsv1.ResourceRef) bool
+	hasCycle = func(resource addonsv1.ResourceRef) bool {
+		switch state[resource] {
+		case visiting:
+			return true
+		case visited:
+			return false
+		}
+		state[resource] = visiting
+		for _, dependsOn := range dependencies[resource] {
+			if dependsOn != resource && hasCycle(dependsOn) {
+				return true
+			}
+		}
+		state[resource] = visited
+		return false
+	}
+	for i, dependency := range crs.Spec.ResourceDependencies {
+		if hasCycle(dependency.Resource) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("resource"), dependency.Resource, "dependencies must not have cycles"))
+			break
+		}
+	}
+
+	return allErrs
+}
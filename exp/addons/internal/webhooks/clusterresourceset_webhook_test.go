@@ -17,6 +17,7 @@ limitations under the License.
 package webhooks
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -213,3 +214,295 @@ func TestClusterResourceSetSelectorNotEmptyValidation(t *testing.T) {
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(err.Error()).To(ContainSubstring("selector must not be empty"))
 }
+
+func TestClusterResourceSetHelmChartsDefault(t *testing.T) {
+	g := NewWithT(t)
+	clusterResourceSet := &addonsv1.ClusterResourceSet{
+		Spec: addonsv1.ClusterResourceSetSpec{
+			HelmCharts: []addonsv1.HelmChartSource{
+				{
+					Name:       "cni",
+					RepoURL:    "https://charts.example.com",
+					Chart:      "cni",
+					Version:    "1.0.0",
+					ValuesFrom: []addonsv1.HelmValuesReference{{Name: "cni-values", Kind: "ConfigMap"}},
+				},
+				{
+					Name:             "csi",
+					RepoURL:          "oci://registry.example.com/charts",
+					Chart:            "csi",
+					Version:          "1.0.0",
+					ReleaseName:      "my-csi",
+					ReleaseNamespace: "kube-system",
+					ValuesFrom:       []addonsv1.HelmValuesReference{{Name: "csi-values", Kind: "Secret", Key: "csi.yaml"}},
+				},
+			},
+		},
+	}
+	webhook := ClusterResourceSet{}
+	g.Expect(webhook.Default(ctx, clusterResourceSet)).To(Succeed())
+
+	g.Expect(clusterResourceSet.Spec.HelmCharts[0].ReleaseName).To(Equal("cni"))
+	g.Expect(clusterResourceSet.Spec.HelmCharts[0].ReleaseNamespace).To(Equal(metav1.NamespaceDefault))
+	g.Expect(clusterResourceSet.Spec.HelmCharts[0].ValuesFrom[0].Key).To(Equal("values.yaml"))
+	g.Expect(clusterResourceSet.Spec.HelmCharts[1].ReleaseName).To(Equal("my-csi"))
+	g.Expect(clusterResourceSet.Spec.HelmCharts[1].ReleaseNamespace).To(Equal("kube-system"))
+	g.Expect(clusterResourceSet.Spec.HelmCharts[1].ValuesFrom[0].Key).To(Equal("csi.yaml"))
+}
+
+func TestClusterResourceSetHelmChartsValidation(t *testing.T) {
+	helmChart := func(name string) addonsv1.HelmChartSource {
+		return addonsv1.HelmChartSource{
+			Name:    name,
+			RepoURL: "https://charts.example.com",
+			Chart:   name,
+			Version: "1.0.0",
+		}
+	}
+
+	tests := []struct {
+		name       string
+		resources  []addonsv1.ResourceRef
+		helmCharts func() []addonsv1.HelmChartSource
+		wantErr    string
+	}{
+		{
+			name: "should not return error for valid Helm charts",
+			helmCharts: func() []addonsv1.HelmChartSource {
+				oci := helmChart("csi")
+				oci.RepoURL = "oci://registry.example.com/charts"
+				oci.ValuesTemplate = "clusterName: {{ .Cluster.metadata.name }}"
+				return []addonsv1.HelmChartSource{helmChart("cni"), oci}
+			},
+		},
+		{
+			name: "should return error for duplicate names",
+			helmCharts: func() []addonsv1.HelmChartSource {
+				return []addonsv1.HelmChartSource{helmChart("cni"), helmChart("cni")}
+			},
+			wantErr: "spec.helmCharts[1].name: Duplicate value",
+		},
+		{
+			name: "should return error for unsupported repository URL",
+			helmCharts: func() []addonsv1.HelmChartSource {
+				c := helmChart("cni")
+				c.RepoURL = "ftp://charts.example.com"
+				return []addonsv1.HelmChartSource{c}
+			},
+			wantErr: "scheme must be one of http, https or oci",
+		},
+		{
+			name: "should return error for invalid release namespace",
+			helmCharts: func() []addonsv1.HelmChartSource {
+				c := helmChart("cni")
+				c.ReleaseNamespace = "Kube_System"
+				return []addonsv1.HelmChartSource{c}
+			},
+			wantErr: "spec.helmCharts[0].releaseNamespace",
+		},
+		{
+			name: "should return error for invalid values template",
+			helmCharts: func() []addonsv1.HelmChartSource {
+				c := helmChart("cni")
+				c.ValuesTemplate = "clusterName: {{ .Cluster.metadata.name"
+				return []addonsv1.HelmChartSource{c}
+			},
+			wantErr: "failed to parse template",
+		},
+		{
+			name:      "should return error for HelmChart resources",
+			resources: []addonsv1.ResourceRef{{Name: "cni", Kind: "HelmChart"}},
+			helmCharts: func() []addonsv1.HelmChartSource {
+				return nil
+			},
+			wantErr: "spec.resources[0].kind: Unsupported value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterResourceSet := &addonsv1.ClusterResourceSet{
+				Spec: addonsv1.ClusterResourceSetSpec{
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					Resources:  tt.resources,
+					HelmCharts: tt.helmCharts(),
+				},
+			}
+			webhook := ClusterResourceSet{}
+			err := webhook.validate(nil, clusterResourceSet)
+			if tt.wantErr == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
+
+func TestClusterResourceSetOCIArtifactsValidation(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	tests := []struct {
+		name         string
+		resources    []addonsv1.ResourceRef
+		ociArtifacts []addonsv1.OCIArtifactSource
+		wantErr      string
+	}{
+		{
+			name: "should not return error for valid OCI artifacts",
+			ociArtifacts: []addonsv1.OCIArtifactSource{
+				{Name: "cni", Reference: "registry.example.com/addons/cni@" + digest},
+				{Name: "csi", Reference: "registry.example.com/addons/csi:v1.0.0@" + digest, Verify: &addonsv1.OCIArtifactVerification{SecretRef: "cosign-keys"}},
+			},
+		},
+		{
+			name: "should return error for duplicate names",
+			ociArtifacts: []addonsv1.OCIArtifactSource{
+				{Name: "cni", Reference: "registry.example.com/addons/cni@" + digest},
+				{Name: "cni", Reference: "registry.example.com/addons/csi@" + digest},
+			},
+			wantErr: "spec.ociArtifacts[1].name: Duplicate value",
+		},
+		{
+			name: "should return error for references not pinned by digest",
+			ociArtifacts: []addonsv1.OCIArtifactSource{
+				{Name: "cni", Reference: "registry.example.com/addons/cni:v1.0.0"},
+			},
+			wantErr: "must be pinned by digest",
+		},
+		{
+			name: "should return error for invalid references",
+			ociArtifacts: []addonsv1.OCIArtifactSource{
+				{Name: "cni", Reference: "registry.example.com/addons/cni@sha256:foo"},
+			},
+			wantErr: "spec.ociArtifacts[0].reference: Invalid value",
+		},
+		{
+			name:      "should return error for OCIArtifact resources",
+			resources: []addonsv1.ResourceRef{{Name: "cni", Kind: "OCIArtifact"}},
+			wantErr:   "spec.resources[0].kind: Unsupported value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterResourceSet := &addonsv1.ClusterResourceSet{
+				Spec: addonsv1.ClusterResourceSetSpec{
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					Resources:    tt.resources,
+					OCIArtifacts: tt.ociArtifacts,
+				},
+			}
+			webhook := ClusterResourceSet{}
+			err := webhook.validate(nil, clusterResourceSet)
+			if tt.wantErr == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
+
+func TestClusterResourceSetDependenciesValidation(t *testing.T) {
+	configMap := addonsv1.ResourceRef{Name: "crds", Kind: "ConfigMap"}
+	secret := addonsv1.ResourceRef{Name: "cni", Kind: "Secret"}
+	helmChart := addonsv1.ResourceRef{Name: "csi", Kind: "HelmChart"}
+
+	tests := []struct {
+		name                 string
+		resourceDependencies []addonsv1.ResourceDependency
+		dependsOn            []string
+		wantErr              string
+	}{
+		{
+			name: "should not return error for valid dependencies",
+			resourceDependencies: []addonsv1.ResourceDependency{
+				{Resource: secret, DependsOn: []addonsv1.ResourceRef{configMap}},
+				{Resource: helmChart, DependsOn: []addonsv1.ResourceRef{configMap, secret}},
+			},
+			dependsOn: []string{"calico-crds"},
+		},
+		{
+			name: "should return error for dependencies of unknown resources",
+			resourceDependencies: []addonsv1.ResourceDependency{
+				{Resource: addonsv1.ResourceRef{Name: "foo", Kind: "Secret"}, DependsOn: []addonsv1.ResourceRef{configMap}},
+			},
+			wantErr: "spec.resourceDependencies[0].resource: Invalid value",
+		},
+		{
+			name: "should return error for dependencies on unknown resources",
+			resourceDependencies: []addonsv1.ResourceDependency{
+				{Resource: secret, DependsOn: []addonsv1.ResourceRef{{Name: "crds", Kind: "Secret"}}},
+			},
+			wantErr: "spec.resourceDependencies[0].dependsOn[0]: Invalid value",
+		},
+		{
+			name: "should return error for resources depending on themselves",
+			resourceDependencies: []addonsv1.ResourceDependency{
+				{Resource: secret, DependsOn: []addonsv1.ResourceRef{secret}},
+			},
+			wantErr: "a resource can't depend on itself",
+		},
+		{
+			name: "should return error for duplicate resources",
+			resourceDependencies: []addonsv1.ResourceDependency{
+				{Resource: secret, DependsOn: []addonsv1.ResourceRef{configMap}},
+				{Resource: secret, DependsOn: []addonsv1.ResourceRef{helmChart}},
+			},
+			wantErr: "spec.resourceDependencies[1].resource: Duplicate value",
+		},
+		{
+			name: "should return error for cycles",
+			resourceDependencies: []addonsv1.ResourceDependency{
+				{Resource: secret, DependsOn: []addonsv1.ResourceRef{configMap}},
+				{Resource: helmChart, DependsOn: []addonsv1.ResourceRef{secret}},
+				{Resource: configMap, DependsOn: []addonsv1.ResourceRef{helmChart}},
+			},
+			wantErr: "dependencies must not have cycles",
+		},
+		{
+			name:      "should return error for ClusterResourceSets depending on themselves",
+			dependsOn: []string{"cni"},
+			wantErr:   "a ClusterResourceSet can't depend on itself",
+		},
+		{
+			name:      "should return error for duplicate ClusterResourceSets",
+			dependsOn: []string{"calico-crds", "calico-crds"},
+			wantErr:   "spec.dependsOn[1]: Duplicate value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterResourceSet := &addonsv1.ClusterResourceSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "cni"},
+				Spec: addonsv1.ClusterResourceSetSpec{
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					Resources: []addonsv1.ResourceRef{configMap, secret},
+					HelmCharts: []addonsv1.HelmChartSource{
+						{Name: "csi", RepoURL: "https://charts.example.com", Chart: "csi", Version: "1.0.0"},
+					},
+					ResourceDependencies: tt.resourceDependencies,
+					DependsOn:            tt.dependsOn,
+				},
+			}
+			webhook := ClusterResourceSet{}
+			err := webhook.validate(nil, clusterResourceSet)
+			if tt.wantErr == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
@@ -37,3 +37,11 @@ type ClusterResourceSetBinding struct{}
 func (webhook *ClusterResourceSetBinding) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return (&webhooks.ClusterResourceSetBinding{}).SetupWebhookWithManager(mgr)
 }
+
+// ClusterAddonSet implements a validating and defaulting webhook for ClusterAddonSet.
+type ClusterAddonSet struct{}
+
+// SetupWebhookWithManager sets up ClusterAddonSet webhooks.
+func (webhook *ClusterAddonSet) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return (&webhooks.ClusterAddonSet{}).SetupWebhookWithManager(mgr)
+}
@@ -18,7 +18,6 @@ package controllers
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/pkg/errors"
@@ -28,6 +27,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/internal/util/taints"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -88,7 +88,7 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 			// No need to requeue here. Nodes emit an event that triggers reconciliation.
 			return ctrl.Result{}, nil
 		}
-		r.recorder.Event(mp, corev1.EventTypeWarning, "FailedSetNodeRef", err.Error())
+		r.recorder.AnnotatedEventf(mp, events.Annotations(cluster.Name, "", ""), corev1.EventTypeWarning, clusterv1.EventFailedSetNodeRef, "%v", err)
 		return ctrl.Result{}, errors.Wrapf(err, "failed to get node references")
 	}
 
@@ -98,7 +98,7 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 	mp.Status.NodeRefs = nodeRefsResult.references
 
 	log.Info("Set MachinePools's NodeRefs", "noderefs", mp.Status.NodeRefs)
-	r.recorder.Event(mp, corev1.EventTypeNormal, "SuccessfulSetNodeRefs", fmt.Sprintf("%+v", mp.Status.NodeRefs))
+	r.recorder.AnnotatedEventf(mp, events.Annotations(cluster.Name, "", ""), corev1.EventTypeNormal, clusterv1.EventSuccessfulSetNodeRefs, "%+v", mp.Status.NodeRefs)
 
 	// Reconcile node annotations and taints.
 	err = r.patchNodes(ctx, clusterClient, nodeRefsResult.references, mp)
@@ -17,9 +17,11 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	ipamv1beta1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 )
 
 func (src *IPAddress) ConvertTo(dstRaw conversion.Hub) error {
@@ -49,13 +51,34 @@ func (dst *IPAddressList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *IPAddressClaim) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*ipamv1beta1.IPAddressClaim)
 
-	return Convert_v1alpha1_IPAddressClaim_To_v1beta1_IPAddressClaim(src, dst, nil)
+	if err := Convert_v1alpha1_IPAddressClaim_To_v1beta1_IPAddressClaim(src, dst, nil); err != nil {
+		return err
+	}
+	// Manually restore data.
+	restored := &ipamv1beta1.IPAddressClaim{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.SecondaryPoolRef = restored.Spec.SecondaryPoolRef
+	dst.Spec.Lease = restored.Spec.Lease
+	dst.Status.SecondaryAddressRef = restored.Status.SecondaryAddressRef
+	dst.Status.LeaseExpirationTime = restored.Status.LeaseExpirationTime
+	return nil
 }
 
 func (dst *IPAddressClaim) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*ipamv1beta1.IPAddressClaim)
 
-	return Convert_v1beta1_IPAddressClaim_To_v1alpha1_IPAddressClaim(src, dst, nil)
+	if err := Convert_v1beta1_IPAddressClaim_To_v1alpha1_IPAddressClaim(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *IPAddressClaimList) ConvertTo(dstRaw conversion.Hub) error {
@@ -69,3 +92,15 @@ func (dst *IPAddressClaimList) ConvertFrom(srcRaw conversion.Hub) error {
 
 	return Convert_v1beta1_IPAddressClaimList_To_v1alpha1_IPAddressClaimList(src, dst, nil)
 }
+
+// Convert_v1beta1_IPAddressClaimSpec_To_v1alpha1_IPAddressClaimSpec is a conversion function.
+func Convert_v1beta1_IPAddressClaimSpec_To_v1alpha1_IPAddressClaimSpec(in *ipamv1beta1.IPAddressClaimSpec, out *IPAddressClaimSpec, s apiconversion.Scope) error {
+	// Spec.SecondaryPoolRef and Spec.Lease do not exist in IPAddressClaim v1alpha1 API.
+	return autoConvert_v1beta1_IPAddressClaimSpec_To_v1alpha1_IPAddressClaimSpec(in, out, s)
+}
+
+// Convert_v1beta1_IPAddressClaimStatus_To_v1alpha1_IPAddressClaimStatus is a conversion function.
+func Convert_v1beta1_IPAddressClaimStatus_To_v1alpha1_IPAddressClaimStatus(in *ipamv1beta1.IPAddressClaimStatus, out *IPAddressClaimStatus, s apiconversion.Scope) error {
+	// Status.SecondaryAddressRef and Status.LeaseExpirationTime do not exist in IPAddressClaim v1alpha1 API.
+	return autoConvert_v1beta1_IPAddressClaimStatus_To_v1alpha1_IPAddressClaimStatus(in, out, s)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+// Conditions and condition Reasons for the IPAddressClaim object.
+
+const (
+	// AllocatedCondition documents whether an address has been allocated for an IPAddressClaim.
+	AllocatedCondition clusterv1.ConditionType = "Allocated"
+
+	// SecondaryAllocatedCondition documents whether an address has been allocated from the secondary pool of a
+	// dual-stack IPAddressClaim. A dual-stack claim is fulfilled only when both AllocatedCondition and
+	// SecondaryAllocatedCondition are true; when only one of them is, the claim is partially fulfilled.
+	SecondaryAllocatedCondition clusterv1.ConditionType = "SecondaryAllocated"
+
+	// LeaseValidCondition documents whether the lease of an IPAddressClaim with a lease is valid, i.e. it has been
+	// renewed before expiring.
+	LeaseValidCondition clusterv1.ConditionType = "LeaseValid"
+
+	// LeaseExpiredReason (Severity=Warning) documents an IPAddressClaim whose lease has expired, so its addresses
+	// have been released.
+	LeaseExpiredReason = "LeaseExpired"
+
+	// PoolNotFoundReason (Severity=Warning) documents an IPAddressClaim referencing a pool which does not exist.
+	PoolNotFoundReason = "PoolNotFound"
+
+	// PoolDeletingReason (Severity=Warning) documents an IPAddressClaim referencing a pool which is being deleted.
+	PoolDeletingReason = "PoolDeleting"
+
+	// PoolExhaustedReason (Severity=Warning) documents an IPAddressClaim referencing a pool with no free addresses.
+	PoolExhaustedReason = "PoolExhausted"
+
+	// AllocationFailedReason (Severity=Warning) documents an IPAddressClaim for which an address could not be allocated.
+	AllocationFailedReason = "AllocationFailed"
+)
+
+// Conditions and condition Reasons for the InClusterIPPool object.
+
+const (
+	// PoolExhaustedCondition documents whether an InClusterIPPool has no free addresses left, so the IPAddressClaims
+	// referencing it cannot be fulfilled. Unlike most conditions, it is True when the pool is in a degraded state.
+	PoolExhaustedCondition clusterv1.ConditionType = "PoolExhausted"
+
+	// FreeAddressesAvailableReason documents an InClusterIPPool which has free addresses.
+	FreeAddressesAvailableReason = "FreeAddressesAvailable"
+)
+
+// Conditions and condition Reasons set on Machines by the in-cluster IPAM provider.
+
+const (
+	// IPAddressesAllocatedCondition documents whether the IPAddressClaims created for a Machine, e.g. by the
+	// infrastructure provider, have been fulfilled by an InClusterIPPool; when a claim cannot be fulfilled, the
+	// condition reports its reason, e.g. PoolExhaustedReason, so provisioning does not stall silently.
+	IPAddressesAllocatedCondition clusterv1.ConditionType = "IPAddressesAllocated"
+
+	// WaitingForAllocationReason (Severity=Info) documents a Machine with IPAddressClaims which have not been
+	// reconciled by the in-cluster IPAM provider yet.
+	WaitingForAllocationReason = "WaitingForAllocation"
+)
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// InClusterIPPoolKind is the kind of the InClusterIPPool, used in the pool reference of IPAddressClaims.
+	InClusterIPPoolKind = "InClusterIPPool"
+
+	// InClusterIPPoolProtectionFinalizer is set on InClusterIPPools to prevent their deletion while
+	// addresses allocated from them are still in use.
+	InClusterIPPoolProtectionFinalizer = "ipam.cluster.x-k8s.io/in-cluster-ip-pool-protection"
+
+	// ReleaseAddressFinalizer is set on IPAddressClaims fulfilled by an InClusterIPPool to release the allocated
+	// address when the claim is deleted.
+	ReleaseAddressFinalizer = "ipam.cluster.x-k8s.io/release-address"
+
+	// MachineNameLabel can be set on IPAddressClaims by their consumers to the name of the Machine the address is
+	// requested for; it is used to key the sticky reservations of InClusterIPPools. When not set, the name of the
+	// Machine owning the claim is used, if any.
+	MachineNameLabel = "ipam.cluster.x-k8s.io/machine-name"
+)
+
+// InClusterIPPoolSpec defines the desired state of InClusterIPPool.
+type InClusterIPPoolSpec struct {
+	// Addresses is a list of IP addresses that can be allocated by the pool. Each item can be a single address
+	// (e.g. 10.0.0.10), a range (e.g. 10.0.0.10-10.0.0.20) or a CIDR (e.g. 10.0.0.0/24).
+	// All the addresses must be of the same IP family.
+	// +kubebuilder:validation:MinItems=1
+	Addresses []string `json:"addresses"`
+
+	// Prefix is the network prefix set on the allocated addresses.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=128
+	Prefix int `json:"prefix"`
+
+	// Gateway is the network gateway set on the allocated addresses; it is never allocated.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// AllocateReservedIPAddresses allows the allocation of the network and broadcast addresses of the subnet
+	// defined by the prefix. They are not allocated by default.
+	// +optional
+	AllocateReservedIPAddresses bool `json:"allocateReservedIPAddresses,omitempty"`
+
+	// ReleasePolicy defines how the addresses released by deleted IPAddressClaims are handled.
+	// +optional
+	ReleasePolicy *InClusterIPPoolReleasePolicy `json:"releasePolicy,omitempty"`
+}
+
+// InClusterIPPoolReleasePolicy defines how the addresses released by deleted IPAddressClaims are handled.
+type InClusterIPPoolReleasePolicy struct {
+	// ReuseDelay is the time a released address is quarantined before it can be allocated to another claim,
+	// to avoid ARP or DNS conflicts with stale entries for the previous owner of the address.
+	// If not set, released addresses can be allocated again immediately.
+	// +optional
+	ReuseDelay *metav1.Duration `json:"reuseDelay,omitempty"`
+
+	// StickyReservations reserves a quarantined address for the machine it was released by, so it is allocated
+	// again to a claim for a machine with the same name, e.g. a re-provisioned bare metal host, until ReuseDelay expires.
+	// Claims are matched to machines using the ipam.cluster.x-k8s.io/machine-name label or the Machine owning them.
+	// Requires ReuseDelay.
+	// +optional
+	StickyReservations bool `json:"stickyReservations,omitempty"`
+
+	// DeleteOrphanedClaims enables the deletion of the IPAddressClaims referencing the pool whose owners do not
+	// exist anymore, releasing their addresses.
+	// +optional
+	DeleteOrphanedClaims bool `json:"deleteOrphanedClaims,omitempty"`
+}
+
+// InClusterIPPoolReleasedAddress is an address released by a deleted IPAddressClaim.
+type InClusterIPPoolReleasedAddress struct {
+	// Address is the released address.
+	Address string `json:"address"`
+
+	// ReleasedAt is the time the address was released.
+	ReleasedAt metav1.Time `json:"releasedAt"`
+
+	// MachineName is the name of the machine the address was allocated for, if known.
+	// +optional
+	MachineName string `json:"machineName,omitempty"`
+}
+
+// InClusterIPPoolStatus defines the observed state of InClusterIPPool.
+type InClusterIPPoolStatus struct {
+	// Addresses reports the count of the addresses of the pool.
+	// +optional
+	Addresses *InClusterIPPoolStatusAddresses `json:"ipAddresses,omitempty"`
+
+	// ReleasedAddresses lists the addresses released by deleted IPAddressClaims while the pool has a ReuseDelay;
+	// they are not allocated to other claims until the ReuseDelay expires.
+	// +optional
+	ReleasedAddresses []InClusterIPPoolReleasedAddress `json:"releasedAddresses,omitempty"`
+
+	// Conditions defines current service state of the InClusterIPPool.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// ObservedGeneration reflects the generation of the most recently observed InClusterIPPool.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// InClusterIPPoolStatusAddresses reports the count of the addresses of an InClusterIPPool.
+type InClusterIPPoolStatusAddresses struct {
+	// Total is the number of addresses of the pool which can be allocated.
+	Total int `json:"total"`
+
+	// Used is the number of addresses of the pool which are allocated.
+	Used int `json:"used"`
+
+	// Free is the number of addresses of the pool which can still be allocated.
+	Free int `json:"free"`
+
+	// Quarantined is the number of released addresses of the pool which cannot be allocated until the ReuseDelay expires.
+	// +optional
+	Quarantined int `json:"quarantined,omitempty"`
+
+	// OutOfRange is the number of allocated addresses which are not part of the pool anymore.
+	// +optional
+	OutOfRange int `json:"outOfRange,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=inclusterippools,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Addresses",type="string",JSONPath=".spec.addresses",description="List of addresses of the pool"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.ipAddresses.total",description="Count of addresses of the pool"
+// +kubebuilder:printcolumn:name="Free",type="integer",JSONPath=".status.ipAddresses.free",description="Count of addresses of the pool which can be allocated"
+// +kubebuilder:printcolumn:name="Used",type="integer",JSONPath=".status.ipAddresses.used",description="Count of addresses of the pool which are allocated"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of InClusterIPPool"
+
+// InClusterIPPool is the Schema for the inclusterippools API.
+// An InClusterIPPool fulfills the IPAddressClaims referencing it by allocating addresses from a static list of
+// addresses, without any external IPAM system.
+type InClusterIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InClusterIPPoolSpec   `json:"spec,omitempty"`
+	Status InClusterIPPoolStatus `json:"status,omitempty"`
+}
+
+// GetReuseDelay returns the time a released address is quarantined before it can be allocated to another claim.
+func (p *InClusterIPPool) GetReuseDelay() time.Duration {
+	if p.Spec.ReleasePolicy == nil || p.Spec.ReleasePolicy.ReuseDelay == nil {
+		return 0
+	}
+	return p.Spec.ReleasePolicy.ReuseDelay.Duration
+}
+
+// GetConditions returns the set of conditions for this object.
+func (p *InClusterIPPool) GetConditions() clusterv1.Conditions {
+	return p.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (p *InClusterIPPool) SetConditions(conditions clusterv1.Conditions) {
+	p.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// InClusterIPPoolList is a list of InClusterIPPools.
+type InClusterIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InClusterIPPool `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &InClusterIPPool{}, &InClusterIPPoolList{})
+}
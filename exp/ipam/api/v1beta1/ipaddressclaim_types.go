@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -27,6 +29,28 @@ import (
 type IPAddressClaimSpec struct {
 	// PoolRef is a reference to the pool from which an IP address should be created.
 	PoolRef corev1.TypedLocalObjectReference `json:"poolRef"`
+
+	// SecondaryPoolRef is a reference to the pool from which a second IP address should be created, making
+	// this a dual-stack claim; it is expected to reference a pool of the other IP family of PoolRef, so a
+	// single claim gets both an IPv4 and an IPv6 address.
+	// +optional
+	SecondaryPoolRef *corev1.TypedLocalObjectReference `json:"secondaryPoolRef,omitempty"`
+
+	// Lease makes the allocation of the addresses time bound: the consumer of the claim must renew the lease
+	// before it expires, otherwise the addresses are released by the IPAM provider.
+	// +optional
+	Lease *IPAddressClaimLease `json:"lease,omitempty"`
+}
+
+// IPAddressClaimLease defines the lease of the addresses allocated for an IPAddressClaim.
+type IPAddressClaimLease struct {
+	// Duration is the duration of the lease, starting from the creation of the claim or from RenewTime.
+	Duration metav1.Duration `json:"duration"`
+
+	// RenewTime is the time the lease was last renewed by the consumer of the claim.
+	// It is the only field of the spec which can be changed, and it must not be in the future.
+	// +optional
+	RenewTime *metav1.Time `json:"renewTime,omitempty"`
 }
 
 // IPAddressClaimStatus is the observed status of a IPAddressClaim.
@@ -35,6 +59,14 @@ type IPAddressClaimStatus struct {
 	// +optional
 	AddressRef corev1.LocalObjectReference `json:"addressRef,omitempty"`
 
+	// SecondaryAddressRef is a reference to the address that was created for this claim from the SecondaryPoolRef.
+	// +optional
+	SecondaryAddressRef corev1.LocalObjectReference `json:"secondaryAddressRef,omitempty"`
+
+	// LeaseExpirationTime is the time the lease of the claim expires, if the claim has a lease.
+	// +optional
+	LeaseExpirationTime *metav1.Time `json:"leaseExpirationTime,omitempty"`
+
 	// Conditions summarises the current state of the IPAddressClaim
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
@@ -46,6 +78,7 @@ type IPAddressClaimStatus struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Pool Name",type="string",JSONPath=".spec.poolRef.name",description="Name of the pool to allocate an address from"
 // +kubebuilder:printcolumn:name="Pool Kind",type="string",JSONPath=".spec.poolRef.kind",description="Kind of the pool to allocate an address from"
+// +kubebuilder:printcolumn:name="Secondary Pool Name",type="string",JSONPath=".spec.secondaryPoolRef.name",description="Name of the pool to allocate a second address from",priority=10
 
 // IPAddressClaim is the Schema for the ipaddressclaim API.
 type IPAddressClaim struct {
@@ -68,6 +101,24 @@ func (m *IPAddressClaim) SetConditions(conditions clusterv1.Conditions) {
 
 // +kubebuilder:object:root=true
 
+// IsDualStack returns true if the claim requests an address from a second pool.
+func (m *IPAddressClaim) IsDualStack() bool {
+	return m.Spec.SecondaryPoolRef != nil
+}
+
+// GetLeaseExpirationTime returns the time the lease of the claim expires, computed from the creation of the claim
+// or from the last renewal; false is returned if the claim has no lease.
+func (m *IPAddressClaim) GetLeaseExpirationTime() (time.Time, bool) {
+	if m.Spec.Lease == nil {
+		return time.Time{}, false
+	}
+	start := m.CreationTimestamp.Time
+	if m.Spec.Lease.RenewTime != nil && m.Spec.Lease.RenewTime.After(start) {
+		start = m.Spec.Lease.RenewTime.Time
+	}
+	return start.Add(m.Spec.Lease.Duration.Duration), true
+}
+
 // IPAddressClaimList is a list of IPAddressClaims.
 type IPAddressClaimList struct {
 	metav1.TypeMeta `json:",inline"`
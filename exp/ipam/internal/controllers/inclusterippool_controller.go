@@ -0,0 +1,229 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controllers implements the controllers of the in-cluster IPAM provider.
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/exp/ipam/internal/ippool"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=inclusterippools;inclusterippools/status;inclusterippools/finalizers,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch;create;delete
+
+// InClusterIPPoolReconciler reconciles an InClusterIPPool object, reporting the usage of its addresses and
+// preventing its deletion while addresses allocated from it are in use.
+type InClusterIPPoolReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *InClusterIPPoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&ipamv1.InClusterIPPool{}).
+		Watches(
+			&ipamv1.IPAddress{},
+			handler.EnqueueRequestsFromMapFunc(ipAddressToInClusterIPPool),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
+
+func (r *InClusterIPPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	pool := &ipamv1.InClusterIPPool{}
+	if err := r.Client.Get(ctx, req.NamespacedName, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			deletePoolMetrics(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	patchHelper, err := patch.NewHelper(pool, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, pool,
+			patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{ipamv1.PoolExhaustedCondition}},
+			patch.WithStatusObservedGeneration{},
+		); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	addresses, err := listPoolAddresses(ctx, r.Client, pool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !pool.DeletionTimestamp.IsZero() {
+		if len(addresses) > 0 {
+			// The finalizer is removed once the last address is released, which triggers a new reconcile.
+			log.Info(fmt.Sprintf("Waiting for %d addresses allocated from the pool to be released", len(addresses)))
+			return ctrl.Result{}, nil
+		}
+		controllerutil.RemoveFinalizer(pool, ipamv1.InClusterIPPoolProtectionFinalizer)
+		deletePoolMetrics(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(pool, ipamv1.InClusterIPPoolProtectionFinalizer) {
+		controllerutil.AddFinalizer(pool, ipamv1.InClusterIPPoolProtectionFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileStatus(pool, addresses)
+}
+
+// reconcileStatus computes the count of the addresses of the pool, reporting it in the pool metrics and in the
+// PoolExhausted condition; when addresses are quarantined, it requeues when the first quarantine expires, so the
+// count of free addresses is updated and the claims waiting for an address are reconciled again.
+func (r *InClusterIPPoolReconciler) reconcileStatus(pool *ipamv1.InClusterIPPool, addresses []ipamv1.IPAddress) (ctrl.Result, error) {
+	ranges, err := ippool.ParseAddresses(pool.Spec.Addresses)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to parse the addresses of the pool")
+	}
+	reserved := ippool.ReservedAddresses(ranges, pool.Spec.Prefix, pool.Spec.Gateway, pool.Spec.AllocateReservedIPAddresses)
+
+	used := 0
+	inUse := map[netip.Addr]struct{}{}
+	for i := range addresses {
+		addr, err := netip.ParseAddr(addresses[i].Spec.Address)
+		if err == nil && ippool.Contains(ranges, addr) {
+			used++
+			inUse[addr] = struct{}{}
+		}
+	}
+
+	quarantined := 0
+	result := ctrl.Result{}
+	now := time.Now()
+	for _, released := range pool.Status.ReleasedAddresses {
+		addr, err := netip.ParseAddr(released.Address)
+		if err != nil || !isQuarantined(pool, released, now) || !ippool.Contains(ranges, addr) {
+			continue
+		}
+		if _, ok := inUse[addr]; ok {
+			continue
+		}
+		if _, ok := reserved[addr]; ok {
+			continue
+		}
+		quarantined++
+		if expiresIn := released.ReleasedAt.Add(pool.GetReuseDelay()).Sub(now); result.RequeueAfter == 0 || expiresIn < result.RequeueAfter {
+			result.RequeueAfter = expiresIn
+		}
+	}
+
+	total := ippool.Count(ranges, reserved)
+	free := total - used - quarantined
+	if free < 0 {
+		free = 0
+	}
+	pool.Status.Addresses = &ipamv1.InClusterIPPoolStatusAddresses{
+		Total:       total,
+		Used:        used,
+		Free:        free,
+		Quarantined: quarantined,
+		OutOfRange:  len(addresses) - used,
+	}
+
+	labels := poolLabels(client.ObjectKeyFromObject(pool))
+	poolAddresses.With(labels).Set(float64(total))
+	poolAllocatedAddresses.With(labels).Set(float64(used))
+	poolFreeAddresses.With(labels).Set(float64(free))
+
+	if free == 0 {
+		conditions.Set(pool, &clusterv1.Condition{
+			Type:    ipamv1.PoolExhaustedCondition,
+			Status:  corev1.ConditionTrue,
+			Reason:  ipamv1.PoolExhaustedReason,
+			Message: fmt.Sprintf("No free addresses: %d of %d addresses used, %d quarantined", used, total, quarantined),
+		})
+	} else {
+		conditions.MarkFalse(pool, ipamv1.PoolExhaustedCondition, ipamv1.FreeAddressesAvailableReason, clusterv1.ConditionSeverityNone,
+			"%d of %d addresses free", free, total)
+	}
+	return result, nil
+}
+
+// isQuarantined returns true if a released address cannot be allocated to other claims yet.
+func isQuarantined(pool *ipamv1.InClusterIPPool, released ipamv1.InClusterIPPoolReleasedAddress, now time.Time) bool {
+	return now.Before(released.ReleasedAt.Add(pool.GetReuseDelay()))
+}
+
+// ipAddressToInClusterIPPool is mapper function that maps IPAddresses to the InClusterIPPool they are allocated from.
+func ipAddressToInClusterIPPool(_ context.Context, o client.Object) []ctrl.Request {
+	address, ok := o.(*ipamv1.IPAddress)
+	if !ok {
+		panic(fmt.Sprintf("Expected an IPAddress but got a %T", o))
+	}
+	if !isInClusterIPPoolRef(address.Spec.PoolRef.APIGroup, address.Spec.PoolRef.Kind) {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: address.Namespace, Name: address.Spec.PoolRef.Name}}}
+}
+
+// isInClusterIPPoolRef returns true if a pool reference points to an InClusterIPPool.
+func isInClusterIPPoolRef(apiGroup *string, kind string) bool {
+	return apiGroup != nil && *apiGroup == ipamv1.GroupVersion.Group && kind == ipamv1.InClusterIPPoolKind
+}
+
+// listPoolAddresses returns the IPAddresses allocated from a pool.
+func listPoolAddresses(ctx context.Context, c client.Reader, pool *ipamv1.InClusterIPPool) ([]ipamv1.IPAddress, error) {
+	addressList := &ipamv1.IPAddressList{}
+	if err := c.List(ctx, addressList, client.InNamespace(pool.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list IPAddresses")
+	}
+
+	addresses := []ipamv1.IPAddress{}
+	for _, address := range addressList.Items {
+		if isInClusterIPPoolRef(address.Spec.PoolRef.APIGroup, address.Spec.PoolRef.Kind) && address.Spec.PoolRef.Name == pool.Name {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
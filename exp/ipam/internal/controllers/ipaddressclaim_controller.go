@@ -0,0 +1,563 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/exp/ipam/internal/ippool"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims;ipaddressclaims/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch;patch
+
+// IPAddressClaimReconciler reconciles the IPAddressClaims referencing an InClusterIPPool, allocating an IPAddress
+// from the pool for each of them; for dual-stack claims, an IPAddress is allocated from each referenced pool.
+type IPAddressClaimReconciler struct {
+	Client client.Client
+
+	// APIReader is used to list the addresses allocated from a pool without using the cache, so an address
+	// allocated by a previous reconcile is never allocated again.
+	APIReader client.Reader
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+
+	// allocationLock serializes the allocation of addresses across concurrent reconciles.
+	allocationLock sync.Mutex
+}
+
+func (r *IPAddressClaimReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&ipamv1.IPAddressClaim{}, builder.WithPredicates(predicate.NewPredicateFuncs(claimReferencesInClusterIPPool))).
+		Owns(&ipamv1.IPAddress{}).
+		Watches(
+			&ipamv1.InClusterIPPool{},
+			handler.EnqueueRequestsFromMapFunc(r.inClusterIPPoolToIPAddressClaims),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
+
+func (r *IPAddressClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	claim := &ipamv1.IPAddressClaim{}
+	if err := r.Client.Get(ctx, req.NamespacedName, claim); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	patchHelper, err := patch.NewHelper(claim, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, claim, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			ipamv1.AllocatedCondition,
+			ipamv1.SecondaryAllocatedCondition,
+			ipamv1.LeaseValidCondition,
+		}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	if !claim.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDelete(ctx, claim)
+	}
+
+	if !controllerutil.ContainsFinalizer(claim, ipamv1.ReleaseAddressFinalizer) {
+		controllerutil.AddFinalizer(claim, ipamv1.ReleaseAddressFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	if deleted, err := r.reconcileOrphaned(ctx, claim); err != nil || deleted {
+		return ctrl.Result{}, err
+	}
+
+	result, err := r.reconcileAllocation(ctx, claim)
+	return result, kerrors.NewAggregate([]error{err, r.reconcileMachineCondition(ctx, claim)})
+}
+
+// reconcileAllocation allocates the addresses of the claim or, if the lease of the claim has expired, releases them.
+func (r *IPAddressClaimReconciler) reconcileAllocation(ctx context.Context, claim *ipamv1.IPAddressClaim) (ctrl.Result, error) {
+	if expiresAt, ok := claim.GetLeaseExpirationTime(); ok {
+		claim.Status.LeaseExpirationTime = &metav1.Time{Time: expiresAt}
+		remaining := time.Until(expiresAt)
+		if remaining <= 0 {
+			return ctrl.Result{}, r.reconcileLeaseExpired(ctx, claim)
+		}
+		conditions.MarkTrue(claim, ipamv1.LeaseValidCondition)
+		// The claim is reconciled again when the lease expires, unless it is renewed before.
+		return ctrl.Result{RequeueAfter: remaining}, r.reconcileNormal(ctx, claim)
+	}
+
+	return ctrl.Result{}, r.reconcileNormal(ctx, claim)
+}
+
+// reconcileMachineCondition sets the IPAddressesAllocated condition on the Machine the claim is for, if any,
+// considering all the claims for the Machine; when one of them cannot be fulfilled, e.g. because its pool is
+// exhausted, the reason is reported on the Machine.
+func (r *IPAddressClaimReconciler) reconcileMachineCondition(ctx context.Context, claim *ipamv1.IPAddressClaim) error {
+	machineName := claimMachineName(claim)
+	if machineName == "" {
+		return nil
+	}
+
+	machine := &clusterv1.Machine{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: machineName}, machine); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get Machine %s", machineName)
+	}
+
+	claimList := &ipamv1.IPAddressClaimList{}
+	if err := r.Client.List(ctx, claimList, client.InNamespace(claim.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list IPAddressClaims")
+	}
+	// The claim being reconciled is used instead of the one in the cache, whose conditions may not be up to date.
+	claims := []*ipamv1.IPAddressClaim{claim}
+	for i := range claimList.Items {
+		c := &claimList.Items[i]
+		if c.Name != claim.Name && c.DeletionTimestamp.IsZero() && claimMachineName(c) == machineName && claimReferencesInClusterIPPool(c) {
+			claims = append(claims, c)
+		}
+	}
+	sort.Slice(claims, func(i, j int) bool { return claims[i].Name < claims[j].Name })
+
+	patchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return err
+	}
+
+	switch c, condition := firstUnfulfilledClaim(claims); {
+	case c == nil:
+		conditions.MarkTrue(machine, ipamv1.IPAddressesAllocatedCondition)
+	case condition == nil:
+		conditions.MarkFalse(machine, ipamv1.IPAddressesAllocatedCondition, ipamv1.WaitingForAllocationReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for IPAddressClaim %s to be fulfilled", c.Name)
+	default:
+		conditions.MarkFalse(machine, ipamv1.IPAddressesAllocatedCondition, condition.Reason, condition.Severity,
+			"IPAddressClaim %s cannot be fulfilled: %s", c.Name, condition.Message)
+	}
+
+	if err := patchHelper.Patch(ctx, machine, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+		ipamv1.IPAddressesAllocatedCondition,
+	}}); err != nil {
+		return errors.Wrapf(err, "failed to patch Machine %s", machineName)
+	}
+	return nil
+}
+
+// reconcileLeaseExpired releases the addresses of a claim whose lease has expired, so addresses are not leaked by
+// consumers which stopped renewing the lease; addresses are allocated again if the lease is renewed.
+func (r *IPAddressClaimReconciler) reconcileLeaseExpired(ctx context.Context, claim *ipamv1.IPAddressClaim) error {
+	if err := r.releaseAddresses(ctx, claim); err != nil {
+		return err
+	}
+
+	conditions.MarkFalse(claim, ipamv1.LeaseValidCondition, ipamv1.LeaseExpiredReason, clusterv1.ConditionSeverityWarning,
+		"The lease expired at %s", claim.Status.LeaseExpirationTime.UTC().Format(time.RFC3339))
+	for _, ca := range inClusterClaimAddresses(claim) {
+		*ca.addressRef = corev1.LocalObjectReference{}
+		conditions.MarkFalse(claim, ca.condition, ipamv1.LeaseExpiredReason, clusterv1.ConditionSeverityWarning,
+			"The address has been released because the lease expired")
+	}
+	return nil
+}
+
+// reconcileOrphaned deletes the claim if all its owners do not exist anymore and one of the pools it references
+// has the DeleteOrphanedClaims release policy; the addresses of the claim are then released by reconcileDelete.
+func (r *IPAddressClaimReconciler) reconcileOrphaned(ctx context.Context, claim *ipamv1.IPAddressClaim) (bool, error) {
+	if len(claim.OwnerReferences) == 0 {
+		return false, nil
+	}
+
+	deleteOrphaned := false
+	for _, ca := range inClusterClaimAddresses(claim) {
+		pool := &ipamv1.InClusterIPPool{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: ca.poolRef.Name}, pool); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return false, errors.Wrapf(err, "failed to get InClusterIPPool %s", ca.poolRef.Name)
+		}
+		if pool.Spec.ReleasePolicy != nil && pool.Spec.ReleasePolicy.DeleteOrphanedClaims {
+			deleteOrphaned = true
+			break
+		}
+	}
+	if !deleteOrphaned {
+		return false, nil
+	}
+
+	for _, ref := range claim.OwnerReferences {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse the API version of owner %s %s", ref.Kind, ref.Name)
+		}
+		owner := &metav1.PartialObjectMetadata{}
+		owner.SetGroupVersionKind(gv.WithKind(ref.Kind))
+		// The owners are read without using the cache, to avoid watching arbitrary kinds.
+		err = r.APIReader.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: ref.Name}, owner)
+		if err == nil && owner.UID == ref.UID {
+			return false, nil
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false, errors.Wrapf(err, "failed to get owner %s %s", ref.Kind, ref.Name)
+		}
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Deleting IPAddressClaim because its owners do not exist anymore")
+	if err := r.Client.Delete(ctx, claim); err != nil && !apierrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "failed to delete orphaned IPAddressClaim %s", klog.KObj(claim))
+	}
+	return true, nil
+}
+
+// firstUnfulfilledClaim returns the first claim with an address which is not allocated, together with the condition
+// reporting why; the condition is nil if the claim has not been reconciled yet.
+func firstUnfulfilledClaim(claims []*ipamv1.IPAddressClaim) (*ipamv1.IPAddressClaim, *clusterv1.Condition) {
+	for _, claim := range claims {
+		for _, ca := range inClusterClaimAddresses(claim) {
+			condition := conditions.Get(claim, ca.condition)
+			if condition == nil || condition.Status != corev1.ConditionTrue {
+				return claim, condition
+			}
+		}
+	}
+	return nil, nil
+}
+
+// claimAddress is one of the addresses requested by a claim: the address from spec.poolRef or, for dual-stack
+// claims, the address from spec.secondaryPoolRef.
+type claimAddress struct {
+	// name is the name of the IPAddress allocated for the claim.
+	name       string
+	poolRef    corev1.TypedLocalObjectReference
+	addressRef *corev1.LocalObjectReference
+	condition  clusterv1.ConditionType
+}
+
+// inClusterClaimAddresses returns the addresses of a claim which are requested from an InClusterIPPool.
+func inClusterClaimAddresses(claim *ipamv1.IPAddressClaim) []claimAddress {
+	addresses := []claimAddress{}
+	if isInClusterIPPoolRef(claim.Spec.PoolRef.APIGroup, claim.Spec.PoolRef.Kind) {
+		addresses = append(addresses, claimAddress{
+			// The IPAddress of a claim has the same name of the claim.
+			name:       claim.Name,
+			poolRef:    claim.Spec.PoolRef,
+			addressRef: &claim.Status.AddressRef,
+			condition:  ipamv1.AllocatedCondition,
+		})
+	}
+	if ref := claim.Spec.SecondaryPoolRef; ref != nil && isInClusterIPPoolRef(ref.APIGroup, ref.Kind) {
+		addresses = append(addresses, claimAddress{
+			name:       fmt.Sprintf("%s-secondary", claim.Name),
+			poolRef:    *ref,
+			addressRef: &claim.Status.SecondaryAddressRef,
+			condition:  ipamv1.SecondaryAllocatedCondition,
+		})
+	}
+	return addresses
+}
+
+// reconcileNormal allocates the addresses for the claim from the referenced pools, if not already allocated.
+// Each address is reconciled independently, so a dual-stack claim can be partially fulfilled, e.g. when only
+// one of its pools is exhausted.
+func (r *IPAddressClaimReconciler) reconcileNormal(ctx context.Context, claim *ipamv1.IPAddressClaim) error {
+	errs := []error{}
+	for _, ca := range inClusterClaimAddresses(claim) {
+		if err := r.reconcileAddress(ctx, claim, ca); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// reconcileAddress allocates one of the addresses of the claim, if not already allocated.
+func (r *IPAddressClaimReconciler) reconcileAddress(ctx context.Context, claim *ipamv1.IPAddressClaim, ca claimAddress) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	address := &ipamv1.IPAddress{}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: ca.name}, address)
+	if err == nil {
+		*ca.addressRef = corev1.LocalObjectReference{Name: address.Name}
+		conditions.MarkTrue(claim, ca.condition)
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get IPAddress %s for claim %s", ca.name, klog.KObj(claim))
+	}
+
+	pool := &ipamv1.InClusterIPPool{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: ca.poolRef.Name}, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			conditions.MarkFalse(claim, ca.condition, ipamv1.PoolNotFoundReason, clusterv1.ConditionSeverityWarning,
+				"InClusterIPPool %s not found", ca.poolRef.Name)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get InClusterIPPool %s", ca.poolRef.Name)
+	}
+	if !pool.DeletionTimestamp.IsZero() {
+		conditions.MarkFalse(claim, ca.condition, ipamv1.PoolDeletingReason, clusterv1.ConditionSeverityWarning,
+			"InClusterIPPool %s is being deleted", pool.Name)
+		return nil
+	}
+
+	r.allocationLock.Lock()
+	defer r.allocationLock.Unlock()
+
+	addr, err := r.allocateAddress(ctx, pool, claimMachineName(claim))
+	if err != nil {
+		conditions.MarkFalse(claim, ca.condition, ipamv1.AllocationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+	if !addr.IsValid() {
+		conditions.MarkFalse(claim, ca.condition, ipamv1.PoolExhaustedReason, clusterv1.ConditionSeverityWarning,
+			"InClusterIPPool %s has no free addresses", pool.Name)
+		return nil
+	}
+
+	address = &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ca.name,
+			Namespace: claim.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(claim, ipamv1.GroupVersion.WithKind("IPAddressClaim")),
+				{
+					APIVersion: ipamv1.GroupVersion.String(),
+					Kind:       ipamv1.InClusterIPPoolKind,
+					Name:       pool.Name,
+					UID:        pool.UID,
+				},
+			},
+		},
+		Spec: ipamv1.IPAddressSpec{
+			ClaimRef: corev1.LocalObjectReference{Name: claim.Name},
+			PoolRef: corev1.TypedLocalObjectReference{
+				APIGroup: pointer.String(ipamv1.GroupVersion.Group),
+				Kind:     ipamv1.InClusterIPPoolKind,
+				Name:     pool.Name,
+			},
+			Address: addr.String(),
+			Prefix:  pool.Spec.Prefix,
+			Gateway: pool.Spec.Gateway,
+		},
+	}
+	if err := r.Client.Create(ctx, address); err != nil {
+		conditions.MarkFalse(claim, ca.condition, ipamv1.AllocationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return errors.Wrapf(err, "failed to create IPAddress %s for claim %s", ca.name, klog.KObj(claim))
+	}
+
+	log.Info("Allocated address", "address", address.Spec.Address, "InClusterIPPool", klog.KObj(pool))
+	*ca.addressRef = corev1.LocalObjectReference{Name: address.Name}
+	conditions.MarkTrue(claim, ca.condition)
+	return nil
+}
+
+// allocateAddress returns the first free address of a pool; an invalid address is returned if the pool is exhausted.
+// Released addresses are not allocated until the ReuseDelay of the pool expires, unless they are reserved for
+// the machine the address is allocated for.
+func (r *IPAddressClaimReconciler) allocateAddress(ctx context.Context, pool *ipamv1.InClusterIPPool, machineName string) (netip.Addr, error) {
+	// The pool is read without using the cache, so addresses released by a previous reconcile are always quarantined.
+	if err := r.APIReader.Get(ctx, client.ObjectKeyFromObject(pool), pool); err != nil {
+		return netip.Addr{}, errors.Wrapf(err, "failed to get InClusterIPPool %s", pool.Name)
+	}
+
+	ranges, err := ippool.ParseAddresses(pool.Spec.Addresses)
+	if err != nil {
+		return netip.Addr{}, errors.Wrapf(err, "failed to parse the addresses of InClusterIPPool %s", pool.Name)
+	}
+
+	addresses, err := listPoolAddresses(ctx, r.APIReader, pool)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	inUse := map[netip.Addr]struct{}{}
+	for i := range addresses {
+		if addr, err := netip.ParseAddr(addresses[i].Spec.Address); err == nil {
+			inUse[addr] = struct{}{}
+		}
+	}
+
+	reserved := ippool.ReservedAddresses(ranges, pool.Spec.Prefix, pool.Spec.Gateway, pool.Spec.AllocateReservedIPAddresses)
+
+	sticky := pool.Spec.ReleasePolicy != nil && pool.Spec.ReleasePolicy.StickyReservations && machineName != ""
+	now := time.Now()
+	for _, released := range pool.Status.ReleasedAddresses {
+		addr, err := netip.ParseAddr(released.Address)
+		if err != nil || !isQuarantined(pool, released, now) {
+			continue
+		}
+		_, used := inUse[addr]
+		_, isReserved := reserved[addr]
+		if sticky && released.MachineName == machineName && !used && !isReserved && ippool.Contains(ranges, addr) {
+			return addr, nil
+		}
+		inUse[addr] = struct{}{}
+	}
+
+	addr, _ := ippool.FindFreeAddress(ranges, inUse, reserved)
+	return addr, nil
+}
+
+// recordReleasedAddress adds an address to the released addresses of a pool with a ReuseDelay, so it is not
+// allocated to other claims until the ReuseDelay expires; the addresses whose quarantine is expired are removed.
+func (r *IPAddressClaimReconciler) recordReleasedAddress(ctx context.Context, claim *ipamv1.IPAddressClaim, poolName, address string) error {
+	pool := &ipamv1.InClusterIPPool{}
+	if err := r.APIReader.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: poolName}, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get InClusterIPPool %s", poolName)
+	}
+	if pool.GetReuseDelay() <= 0 {
+		return nil
+	}
+
+	original := pool.DeepCopy()
+	now := time.Now()
+	released := []ipamv1.InClusterIPPoolReleasedAddress{}
+	for _, a := range pool.Status.ReleasedAddresses {
+		if a.Address != address && isQuarantined(pool, a, now) {
+			released = append(released, a)
+		}
+	}
+	pool.Status.ReleasedAddresses = append(released, ipamv1.InClusterIPPoolReleasedAddress{
+		Address:     address,
+		ReleasedAt:  metav1.NewTime(now),
+		MachineName: claimMachineName(claim),
+	})
+	if err := r.Client.Status().Patch(ctx, pool, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return errors.Wrapf(err, "failed to record released address %s in InClusterIPPool %s", address, poolName)
+	}
+	return nil
+}
+
+// claimMachineName returns the name of the machine an IPAddressClaim is for, if known.
+func claimMachineName(claim *ipamv1.IPAddressClaim) string {
+	if name := claim.Labels[ipamv1.MachineNameLabel]; name != "" {
+		return name
+	}
+	for _, ref := range claim.OwnerReferences {
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil && gv.Group == clusterv1.GroupVersion.Group && ref.Kind == "Machine" {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+// reconcileDelete releases the addresses allocated for the claim.
+func (r *IPAddressClaimReconciler) reconcileDelete(ctx context.Context, claim *ipamv1.IPAddressClaim) error {
+	if err := r.releaseAddresses(ctx, claim); err != nil {
+		return err
+	}
+
+	controllerutil.RemoveFinalizer(claim, ipamv1.ReleaseAddressFinalizer)
+	return nil
+}
+
+// releaseAddresses deletes the IPAddresses allocated for the claim.
+func (r *IPAddressClaimReconciler) releaseAddresses(ctx context.Context, claim *ipamv1.IPAddressClaim) error {
+	r.allocationLock.Lock()
+	defer r.allocationLock.Unlock()
+
+	for _, ca := range inClusterClaimAddresses(claim) {
+		address := &ipamv1.IPAddress{}
+		err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: ca.name}, address)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get IPAddress %s for claim %s", ca.name, klog.KObj(claim))
+		}
+		if err == nil && metav1.IsControlledBy(address, claim) {
+			// The address is recorded as released before deleting the IPAddress, so it is never allocated again
+			// before being quarantined.
+			if err := r.recordReleasedAddress(ctx, claim, ca.poolRef.Name, address.Spec.Address); err != nil {
+				return err
+			}
+			if err := r.Client.Delete(ctx, address); err != nil && !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to delete IPAddress %s for claim %s", ca.name, klog.KObj(claim))
+			}
+			ctrl.LoggerFrom(ctx).Info("Released address", "address", address.Spec.Address)
+		}
+	}
+	return nil
+}
+
+// inClusterIPPoolToIPAddressClaims is mapper function that maps InClusterIPPools to the IPAddressClaims referencing
+// them still waiting for an address, e.g. because the pool was exhausted or did not exist yet.
+func (r *IPAddressClaimReconciler) inClusterIPPoolToIPAddressClaims(ctx context.Context, o client.Object) []ctrl.Request {
+	claimList := &ipamv1.IPAddressClaimList{}
+	if err := r.Client.List(ctx, claimList, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	result := []ctrl.Request{}
+	for i := range claimList.Items {
+		claim := &claimList.Items[i]
+		for _, ca := range inClusterClaimAddresses(claim) {
+			if ca.poolRef.Name == o.GetName() && ca.addressRef.Name == "" {
+				result = append(result, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+				break
+			}
+		}
+	}
+	return result
+}
+
+// claimReferencesInClusterIPPool returns true if the object is an IPAddressClaim referencing an InClusterIPPool,
+// either in spec.poolRef or in spec.secondaryPoolRef.
+func claimReferencesInClusterIPPool(o client.Object) bool {
+	claim, ok := o.(*ipamv1.IPAddressClaim)
+	if !ok {
+		panic(fmt.Sprintf("Expected an IPAddressClaim but got a %T", o))
+	}
+	return len(inClusterClaimAddresses(claim)) > 0
+}
@@ -0,0 +1,488 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+var ctx = context.Background()
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = ipamv1.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	return scheme
+}
+
+func newPool(addresses ...string) *ipamv1.InClusterIPPool {
+	return &ipamv1.InClusterIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: metav1.NamespaceDefault, UID: "pool-uid"},
+		Spec: ipamv1.InClusterIPPoolSpec{
+			Addresses: addresses,
+			Prefix:    24,
+			Gateway:   "10.0.0.1",
+		},
+	}
+}
+
+func newClaim(name string) *ipamv1.IPAddressClaim {
+	return &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  metav1.NamespaceDefault,
+			UID:        "claim-uid",
+			Finalizers: []string{ipamv1.ReleaseAddressFinalizer},
+		},
+		Spec: ipamv1.IPAddressClaimSpec{
+			PoolRef: corev1.TypedLocalObjectReference{
+				APIGroup: pointer.String(ipamv1.GroupVersion.Group),
+				Kind:     ipamv1.InClusterIPPoolKind,
+				Name:     "pool",
+			},
+		},
+	}
+}
+
+// reconcileClaim reconciles a claim, returning the IPAddress allocated for it, if any.
+func reconcileClaim(g *WithT, r *IPAddressClaimReconciler, claim *ipamv1.IPAddressClaim) *ipamv1.IPAddress {
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+	g.Expect(err).ToNot(HaveOccurred())
+	address := &ipamv1.IPAddress{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(claim), address); err != nil {
+		return nil
+	}
+	return address
+}
+
+func TestIPAddressClaimReconcile(t *testing.T) {
+	t.Run("allocates the first free address of the pool", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pool := newPool("10.0.0.1-10.0.0.3")
+		claims := []*ipamv1.IPAddressClaim{newClaim("claim-1"), newClaim("claim-2"), newClaim("claim-3")}
+		c := fake.NewClientBuilder().WithScheme(newScheme()).
+			WithObjects(pool, claims[0], claims[1], claims[2]).
+			WithStatusSubresource(&ipamv1.IPAddressClaim{}).
+			Build()
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		// 10.0.0.1 is the gateway, so it is never allocated.
+		for i, want := range []string{"10.0.0.2", "10.0.0.3"} {
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claims[i])})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			address := &ipamv1.IPAddress{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claims[i]), address)).To(Succeed())
+			g.Expect(address.Spec.Address).To(Equal(want))
+			g.Expect(address.Spec.Prefix).To(Equal(24))
+			g.Expect(address.Spec.Gateway).To(Equal("10.0.0.1"))
+			g.Expect(address.Spec.ClaimRef.Name).To(Equal(claims[i].Name))
+			g.Expect(metav1.IsControlledBy(address, claims[i])).To(BeTrue())
+
+			claim := &ipamv1.IPAddressClaim{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claims[i]), claim)).To(Succeed())
+			g.Expect(claim.Status.AddressRef.Name).To(Equal(claims[i].Name))
+			g.Expect(conditions.IsTrue(claim, ipamv1.AllocatedCondition)).To(BeTrue())
+		}
+
+		// The pool is exhausted.
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claims[2])})
+		g.Expect(err).ToNot(HaveOccurred())
+		claim := &ipamv1.IPAddressClaim{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claims[2]), claim)).To(Succeed())
+		g.Expect(claim.Status.AddressRef.Name).To(BeEmpty())
+		g.Expect(conditions.GetReason(claim, ipamv1.AllocatedCondition)).To(Equal(ipamv1.PoolExhaustedReason))
+
+		// The claim waiting for an address is reconciled when the pool changes.
+		g.Expect(r.inClusterIPPoolToIPAddressClaims(ctx, pool)).To(ConsistOf(ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claims[2])}))
+	})
+
+	t.Run("reports claims referencing a pool which does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(newScheme()).
+			WithObjects(newClaim("claim")).
+			WithStatusSubresource(&ipamv1.IPAddressClaim{}).
+			Build()
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "claim"}})
+		g.Expect(err).ToNot(HaveOccurred())
+		claim := &ipamv1.IPAddressClaim{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "claim"}, claim)).To(Succeed())
+		g.Expect(conditions.GetReason(claim, ipamv1.AllocatedCondition)).To(Equal(ipamv1.PoolNotFoundReason))
+	})
+
+	t.Run("allocates an address from each pool of a dual-stack claim", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pool := newPool("10.0.0.10")
+		v6Pool := &ipamv1.InClusterIPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-v6", Namespace: metav1.NamespaceDefault, UID: "pool-v6-uid"},
+			Spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"fd00::10"},
+				Prefix:    64,
+				Gateway:   "fd00::1",
+			},
+		}
+		claim := newClaim("claim")
+		claim.Spec.SecondaryPoolRef = &corev1.TypedLocalObjectReference{
+			APIGroup: pointer.String(ipamv1.GroupVersion.Group),
+			Kind:     ipamv1.InClusterIPPoolKind,
+			Name:     v6Pool.Name,
+		}
+		c := fake.NewClientBuilder().WithScheme(newScheme()).
+			WithObjects(pool, claim).
+			WithStatusSubresource(&ipamv1.IPAddressClaim{}).
+			Build()
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		// The IPv6 pool does not exist yet, so the claim is partially fulfilled.
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+		g.Expect(err).ToNot(HaveOccurred())
+		got := &ipamv1.IPAddressClaim{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claim), got)).To(Succeed())
+		g.Expect(got.Status.AddressRef.Name).To(Equal("claim"))
+		g.Expect(got.Status.SecondaryAddressRef.Name).To(BeEmpty())
+		g.Expect(conditions.IsTrue(got, ipamv1.AllocatedCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(got, ipamv1.SecondaryAllocatedCondition)).To(Equal(ipamv1.PoolNotFoundReason))
+
+		g.Expect(c.Create(ctx, v6Pool)).To(Succeed())
+		g.Expect(r.inClusterIPPoolToIPAddressClaims(ctx, v6Pool)).To(ConsistOf(ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)}))
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claim), got)).To(Succeed())
+		g.Expect(got.Status.SecondaryAddressRef.Name).To(Equal("claim-secondary"))
+		g.Expect(conditions.IsTrue(got, ipamv1.SecondaryAllocatedCondition)).To(BeTrue())
+
+		address := &ipamv1.IPAddress{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: "claim-secondary"}, address)).To(Succeed())
+		g.Expect(address.Spec.Address).To(Equal("fd00::10"))
+		g.Expect(address.Spec.PoolRef.Name).To(Equal(v6Pool.Name))
+
+		// Both addresses are released when the claim is deleted.
+		g.Expect(c.Delete(ctx, got)).To(Succeed())
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: "claim"}, &ipamv1.IPAddress{})).ToNot(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: "claim-secondary"}, &ipamv1.IPAddress{})).ToNot(Succeed())
+	})
+
+	t.Run("releases the address when the claim is deleted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pool := newPool("10.0.0.10")
+		claim := newClaim("claim")
+		c := fake.NewClientBuilder().WithScheme(newScheme()).
+			WithObjects(pool, claim).
+			WithStatusSubresource(&ipamv1.IPAddressClaim{}).
+			Build()
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claim), &ipamv1.IPAddress{})).To(Succeed())
+
+		g.Expect(c.Delete(ctx, claim)).To(Succeed())
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claim), &ipamv1.IPAddress{})).ToNot(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claim), &ipamv1.IPAddressClaim{})).ToNot(Succeed())
+	})
+}
+
+func TestIPAddressClaimReconcileReleasePolicy(t *testing.T) {
+	deleteClaim := func(g *WithT, r *IPAddressClaimReconciler, claim *ipamv1.IPAddressClaim) {
+		got := &ipamv1.IPAddressClaim{}
+		g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(claim), got)).To(Succeed())
+		g.Expect(r.Client.Delete(ctx, got)).To(Succeed())
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	t.Run("quarantines released addresses", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pool := newPool("10.0.0.10-10.0.0.11")
+		pool.Spec.ReleasePolicy = &ipamv1.InClusterIPPoolReleasePolicy{ReuseDelay: &metav1.Duration{Duration: time.Hour}}
+		c := fake.NewClientBuilder().WithScheme(newScheme()).
+			WithObjects(pool, newClaim("claim-1"), newClaim("claim-2")).
+			WithStatusSubresource(&ipamv1.IPAddressClaim{}, &ipamv1.InClusterIPPool{}).
+			Build()
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		g.Expect(reconcileClaim(g, r, newClaim("claim-1")).Spec.Address).To(Equal("10.0.0.10"))
+		deleteClaim(g, r, newClaim("claim-1"))
+
+		got := &ipamv1.InClusterIPPool{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pool), got)).To(Succeed())
+		g.Expect(got.Status.ReleasedAddresses).To(HaveLen(1))
+		g.Expect(got.Status.ReleasedAddresses[0].Address).To(Equal("10.0.0.10"))
+
+		// The released address is not allocated to other claims.
+		g.Expect(reconcileClaim(g, r, newClaim("claim-2")).Spec.Address).To(Equal("10.0.0.11"))
+		g.Expect(c.Create(ctx, newClaim("claim-3"))).To(Succeed())
+		g.Expect(reconcileClaim(g, r, newClaim("claim-3"))).To(BeNil())
+
+		// The address can be allocated again once the quarantine expires.
+		got.Status.ReleasedAddresses[0].ReleasedAt = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		g.Expect(c.Status().Update(ctx, got)).To(Succeed())
+		g.Expect(reconcileClaim(g, r, newClaim("claim-3")).Spec.Address).To(Equal("10.0.0.10"))
+	})
+
+	t.Run("allocates reserved addresses to the same machine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pool := newPool("10.0.0.10-10.0.0.20")
+		pool.Spec.ReleasePolicy = &ipamv1.InClusterIPPoolReleasePolicy{
+			ReuseDelay:         &metav1.Duration{Duration: time.Hour},
+			StickyReservations: true,
+		}
+		claimForMachine := func(name, machineName string) *ipamv1.IPAddressClaim {
+			claim := newClaim(name)
+			claim.Labels = map[string]string{ipamv1.MachineNameLabel: machineName}
+			return claim
+		}
+		c := fake.NewClientBuilder().WithScheme(newScheme()).
+			WithObjects(pool, claimForMachine("claim-1", "machine-a"), claimForMachine("claim-2", "machine-b")).
+			WithStatusSubresource(&ipamv1.IPAddressClaim{}, &ipamv1.InClusterIPPool{}).
+			Build()
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		g.Expect(reconcileClaim(g, r, claimForMachine("claim-1", "machine-a")).Spec.Address).To(Equal("10.0.0.10"))
+		g.Expect(reconcileClaim(g, r, claimForMachine("claim-2", "machine-b")).Spec.Address).To(Equal("10.0.0.11"))
+		deleteClaim(g, r, claimForMachine("claim-1", "machine-a"))
+		deleteClaim(g, r, claimForMachine("claim-2", "machine-b"))
+
+		// A new claim for machine-b gets the address machine-b released, a claim for another machine gets a new one.
+		g.Expect(c.Create(ctx, claimForMachine("claim-3", "machine-b"))).To(Succeed())
+		g.Expect(reconcileClaim(g, r, claimForMachine("claim-3", "machine-b")).Spec.Address).To(Equal("10.0.0.11"))
+		g.Expect(c.Create(ctx, claimForMachine("claim-4", "machine-c"))).To(Succeed())
+		g.Expect(reconcileClaim(g, r, claimForMachine("claim-4", "machine-c")).Spec.Address).To(Equal("10.0.0.12"))
+	})
+
+	t.Run("deletes orphaned claims", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pool := newPool("10.0.0.10-10.0.0.20")
+		pool.Spec.ReleasePolicy = &ipamv1.InClusterIPPoolReleasePolicy{DeleteOrphanedClaims: true}
+		machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: metav1.NamespaceDefault, UID: "machine-uid"}}
+		ownedClaim := func(name string, ownerUID types.UID) *ipamv1.IPAddressClaim {
+			claim := newClaim(name)
+			claim.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Machine",
+				Name:       machine.Name,
+				UID:        ownerUID,
+			}}
+			return claim
+		}
+		c := fake.NewClientBuilder().WithScheme(newScheme()).
+			WithObjects(pool, machine, ownedClaim("claim", machine.UID), ownedClaim("orphaned", "old-machine-uid")).
+			WithStatusSubresource(&ipamv1.IPAddressClaim{}, &ipamv1.InClusterIPPool{}, &clusterv1.Machine{}).
+			Build()
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		g.Expect(reconcileClaim(g, r, newClaim("claim"))).ToNot(BeNil())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(newClaim("claim")), &ipamv1.IPAddressClaim{})).To(Succeed())
+
+		// The claim owned by a Machine which does not exist anymore is deleted.
+		g.Expect(reconcileClaim(g, r, newClaim("orphaned"))).To(BeNil())
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(newClaim("orphaned"))})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(newClaim("orphaned")), &ipamv1.IPAddressClaim{})).ToNot(Succeed())
+	})
+}
+
+func TestIPAddressClaimReconcileLease(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := newPool("10.0.0.10-10.0.0.20")
+	claim := newClaim("claim")
+	claim.CreationTimestamp = metav1.NewTime(time.Now().Add(-3 * time.Hour))
+	claim.Spec.Lease = &ipamv1.IPAddressClaimLease{Duration: metav1.Duration{Duration: time.Hour}}
+	c := fake.NewClientBuilder().WithScheme(newScheme()).
+		WithObjects(pool, claim).
+		WithStatusSubresource(&ipamv1.IPAddressClaim{}).
+		Build()
+	r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+	key := client.ObjectKeyFromObject(claim)
+
+	// The lease of the claim is already expired, so no address is allocated.
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).ToNot(HaveOccurred())
+	got := &ipamv1.IPAddressClaim{}
+	g.Expect(c.Get(ctx, key, got)).To(Succeed())
+	g.Expect(got.Status.LeaseExpirationTime).ToNot(BeNil())
+	g.Expect(conditions.GetReason(got, ipamv1.LeaseValidCondition)).To(Equal(ipamv1.LeaseExpiredReason))
+	g.Expect(c.Get(ctx, key, &ipamv1.IPAddress{})).ToNot(Succeed())
+
+	// An address is allocated once the lease is renewed, and the claim is reconciled again when the lease expires.
+	got.Spec.Lease.RenewTime = &metav1.Time{Time: time.Now()}
+	g.Expect(c.Update(ctx, got)).To(Succeed())
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+	g.Expect(c.Get(ctx, key, got)).To(Succeed())
+	g.Expect(conditions.IsTrue(got, ipamv1.LeaseValidCondition)).To(BeTrue())
+	g.Expect(got.Status.AddressRef.Name).To(Equal("claim"))
+	g.Expect(c.Get(ctx, key, &ipamv1.IPAddress{})).To(Succeed())
+
+	// The address is released when the lease expires.
+	got.Spec.Lease.RenewTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+	g.Expect(c.Update(ctx, got)).To(Succeed())
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.Get(ctx, key, got)).To(Succeed())
+	g.Expect(got.Status.AddressRef.Name).To(BeEmpty())
+	g.Expect(conditions.GetReason(got, ipamv1.AllocatedCondition)).To(Equal(ipamv1.LeaseExpiredReason))
+	g.Expect(conditions.GetReason(got, ipamv1.LeaseValidCondition)).To(Equal(ipamv1.LeaseExpiredReason))
+	g.Expect(c.Get(ctx, key, &ipamv1.IPAddress{})).ToNot(Succeed())
+	g.Expect(got.Finalizers).To(ContainElement(ipamv1.ReleaseAddressFinalizer))
+}
+
+func TestInClusterIPPoolReconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := newPool("10.0.0.0/29")
+	pool.Finalizers = []string{ipamv1.InClusterIPPoolProtectionFinalizer}
+	address := func(name, addr string) *ipamv1.IPAddress {
+		return &ipamv1.IPAddress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+			Spec: ipamv1.IPAddressSpec{
+				PoolRef: corev1.TypedLocalObjectReference{
+					APIGroup: pointer.String(ipamv1.GroupVersion.Group),
+					Kind:     ipamv1.InClusterIPPoolKind,
+					Name:     pool.Name,
+				},
+				Address: addr,
+				Prefix:  24,
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(newScheme()).
+		WithObjects(pool, address("in-range", "10.0.0.2"), address("out-of-range", "10.0.1.2")).
+		WithStatusSubresource(&ipamv1.InClusterIPPool{}).
+		Build()
+	r := &InClusterIPPoolReconciler{Client: c}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pool)})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	got := &ipamv1.InClusterIPPool{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pool), got)).To(Succeed())
+	// 10.0.0.0/29 has 8 addresses: the network address and the gateway are reserved.
+	g.Expect(got.Status.Addresses).To(Equal(&ipamv1.InClusterIPPoolStatusAddresses{
+		Total:      6,
+		Used:       1,
+		Free:       5,
+		OutOfRange: 1,
+	}))
+	g.Expect(conditions.IsFalse(got, ipamv1.PoolExhaustedCondition)).To(BeTrue())
+	labels := poolLabels(client.ObjectKeyFromObject(pool))
+	g.Expect(testutil.ToFloat64(poolAddresses.With(labels))).To(Equal(6.0))
+	g.Expect(testutil.ToFloat64(poolAllocatedAddresses.With(labels))).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(poolFreeAddresses.With(labels))).To(Equal(5.0))
+
+	// Quarantined addresses are not free, the pool is reconciled again when the quarantine expires.
+	got.Spec.ReleasePolicy = &ipamv1.InClusterIPPoolReleasePolicy{ReuseDelay: &metav1.Duration{Duration: time.Hour}}
+	g.Expect(c.Update(ctx, got)).To(Succeed())
+	got.Status.ReleasedAddresses = []ipamv1.InClusterIPPoolReleasedAddress{
+		{Address: "10.0.0.3", ReleasedAt: metav1.Now()},
+		{Address: "10.0.0.4", ReleasedAt: metav1.NewTime(time.Now().Add(-2 * time.Hour))},
+	}
+	g.Expect(c.Status().Update(ctx, got)).To(Succeed())
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pool)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pool), got)).To(Succeed())
+	g.Expect(got.Status.Addresses.Quarantined).To(Equal(1))
+	g.Expect(got.Status.Addresses.Free).To(Equal(4))
+
+	// The pool is not deleted while addresses allocated from it exist.
+	g.Expect(c.Delete(ctx, got)).To(Succeed())
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pool)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pool), got)).To(Succeed())
+	g.Expect(got.Finalizers).To(ContainElement(ipamv1.InClusterIPPoolProtectionFinalizer))
+
+	g.Expect(c.Delete(ctx, address("in-range", "10.0.0.2"))).To(Succeed())
+	g.Expect(c.Delete(ctx, address("out-of-range", "10.0.1.2"))).To(Succeed())
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pool)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pool), got)).ToNot(Succeed())
+}
+
+func TestInClusterIPPoolReconcileExhausted(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := newPool("10.0.0.10-10.0.0.11")
+	pool.Finalizers = []string{ipamv1.InClusterIPPoolProtectionFinalizer}
+	machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: metav1.NamespaceDefault}}
+	claimForMachine := func(name string) *ipamv1.IPAddressClaim {
+		claim := newClaim(name)
+		claim.Labels = map[string]string{ipamv1.MachineNameLabel: machine.Name}
+		return claim
+	}
+	c := fake.NewClientBuilder().WithScheme(newScheme()).
+		WithObjects(pool, machine, claimForMachine("claim-1"), claimForMachine("claim-2"), claimForMachine("claim-3")).
+		WithStatusSubresource(&ipamv1.IPAddressClaim{}, &ipamv1.InClusterIPPool{}, &clusterv1.Machine{}).
+		Build()
+	claimReconciler := &IPAddressClaimReconciler{Client: c, APIReader: c}
+	poolReconciler := &InClusterIPPoolReconciler{Client: c}
+
+	// The Machine reports whether all its claims are fulfilled.
+	g.Expect(reconcileClaim(g, claimReconciler, claimForMachine("claim-1"))).ToNot(BeNil())
+	g.Expect(reconcileClaim(g, claimReconciler, claimForMachine("claim-2"))).ToNot(BeNil())
+	gotMachine := &clusterv1.Machine{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), gotMachine)).To(Succeed())
+	g.Expect(conditions.IsFalse(gotMachine, ipamv1.IPAddressesAllocatedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(gotMachine, ipamv1.IPAddressesAllocatedCondition)).To(Equal(ipamv1.WaitingForAllocationReason))
+
+	_, err := poolReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pool)})
+	g.Expect(err).ToNot(HaveOccurred())
+	gotPool := &ipamv1.InClusterIPPool{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pool), gotPool)).To(Succeed())
+	g.Expect(conditions.IsTrue(gotPool, ipamv1.PoolExhaustedCondition)).To(BeTrue())
+	g.Expect(testutil.ToFloat64(poolFreeAddresses.With(poolLabels(client.ObjectKeyFromObject(pool))))).To(Equal(0.0))
+
+	// A claim which cannot be fulfilled because the pool is exhausted is reported on the Machine.
+	g.Expect(reconcileClaim(g, claimReconciler, claimForMachine("claim-3"))).To(BeNil())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), gotMachine)).To(Succeed())
+	g.Expect(conditions.IsFalse(gotMachine, ipamv1.IPAddressesAllocatedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(gotMachine, ipamv1.IPAddressesAllocatedCondition)).To(Equal(ipamv1.PoolExhaustedReason))
+	g.Expect(conditions.GetMessage(gotMachine, ipamv1.IPAddressesAllocatedCondition)).To(ContainSubstring("claim-3"))
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(poolAddresses)
+	ctrlmetrics.Registry.MustRegister(poolAllocatedAddresses)
+	ctrlmetrics.Registry.MustRegister(poolFreeAddresses)
+}
+
+// Metrics subsystem for the utilization of the InClusterIPPools.
+const inClusterIPPoolSubsystem = "capi_inclusterippool"
+
+var (
+	// poolAddresses reports the number of addresses of InClusterIPPools which can be allocated.
+	poolAddresses = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: inClusterIPPoolSubsystem,
+		Name:      "addresses",
+		Help:      "Number of addresses which can be allocated, partitioned by InClusterIPPool.",
+	}, []string{"namespace", "pool"})
+
+	// poolAllocatedAddresses reports the number of allocated addresses of InClusterIPPools.
+	poolAllocatedAddresses = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: inClusterIPPoolSubsystem,
+		Name:      "allocated_addresses",
+		Help:      "Number of allocated addresses, partitioned by InClusterIPPool.",
+	}, []string{"namespace", "pool"})
+
+	// poolFreeAddresses reports the number of addresses of InClusterIPPools which are still free.
+	poolFreeAddresses = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: inClusterIPPoolSubsystem,
+		Name:      "free_addresses",
+		Help:      "Number of free addresses, partitioned by InClusterIPPool.",
+	}, []string{"namespace", "pool"})
+)
+
+func poolLabels(pool types.NamespacedName) prometheus.Labels {
+	return prometheus.Labels{
+		"namespace": pool.Namespace,
+		"pool":      pool.Name,
+	}
+}
+
+// deletePoolMetrics removes the metrics of a deleted InClusterIPPool.
+func deletePoolMetrics(pool types.NamespacedName) {
+	poolAddresses.Delete(poolLabels(pool))
+	poolAllocatedAddresses.Delete(poolLabels(pool))
+	poolFreeAddresses.Delete(poolLabels(pool))
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ippool implements the address arithmetic of the in-cluster IP pools.
+package ippool
+
+import (
+	"math"
+	"math/big"
+	"net/netip"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Range is an inclusive range of IP addresses of the same family.
+type Range struct {
+	From netip.Addr
+	To   netip.Addr
+}
+
+// Contains returns true if the address is part of the range.
+func (r Range) Contains(addr netip.Addr) bool {
+	return addr.BitLen() == r.From.BitLen() && r.From.Compare(addr) <= 0 && addr.Compare(r.To) <= 0
+}
+
+// Overlaps returns true if the two ranges have at least an address in common.
+func (r Range) Overlaps(other Range) bool {
+	return r.From.BitLen() == other.From.BitLen() && r.From.Compare(other.To) <= 0 && other.From.Compare(r.To) <= 0
+}
+
+// String returns the string representation of the range.
+func (r Range) String() string {
+	if r.From == r.To {
+		return r.From.String()
+	}
+	return r.From.String() + "-" + r.To.String()
+}
+
+// ParseAddress parses an item of the addresses of a pool: a single address (e.g. 10.0.0.10), a range
+// (e.g. 10.0.0.10-10.0.0.20) or a CIDR (e.g. 10.0.0.0/24).
+func ParseAddress(address string) (Range, error) {
+	switch {
+	case strings.Contains(address, "-"):
+		from, to, _ := strings.Cut(address, "-")
+		fromAddr, err := netip.ParseAddr(strings.TrimSpace(from))
+		if err != nil {
+			return Range{}, errors.Wrapf(err, "invalid start of range %q", address)
+		}
+		toAddr, err := netip.ParseAddr(strings.TrimSpace(to))
+		if err != nil {
+			return Range{}, errors.Wrapf(err, "invalid end of range %q", address)
+		}
+		if fromAddr.BitLen() != toAddr.BitLen() {
+			return Range{}, errors.Errorf("start and end of range %q must be of the same IP family", address)
+		}
+		if fromAddr.Compare(toAddr) > 0 {
+			return Range{}, errors.Errorf("start of range %q must not be greater than its end", address)
+		}
+		return Range{From: fromAddr, To: toAddr}, nil
+	case strings.Contains(address, "/"):
+		prefix, err := netip.ParsePrefix(address)
+		if err != nil {
+			return Range{}, errors.Wrapf(err, "invalid CIDR %q", address)
+		}
+		prefix = prefix.Masked()
+		return Range{From: prefix.Addr(), To: lastAddr(prefix)}, nil
+	default:
+		addr, err := netip.ParseAddr(address)
+		if err != nil {
+			return Range{}, errors.Wrapf(err, "invalid address %q", address)
+		}
+		return Range{From: addr, To: addr}, nil
+	}
+}
+
+// ParseAddresses parses the addresses of a pool.
+func ParseAddresses(addresses []string) ([]Range, error) {
+	ranges := make([]Range, 0, len(addresses))
+	for _, address := range addresses {
+		r, err := ParseAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// ReservedAddresses returns the addresses of a pool which must never be allocated: the gateway and, unless
+// allowed, the network and broadcast addresses of the subnets defined by the prefix the ranges belong to.
+// NOTE: IPv4 subnets with a prefix of 31 or 32 and IPv6 subnets don't have a broadcast address.
+func ReservedAddresses(ranges []Range, prefixLength int, gateway string, allocateReserved bool) map[netip.Addr]struct{} {
+	reserved := map[netip.Addr]struct{}{}
+	if gw, err := netip.ParseAddr(gateway); err == nil {
+		reserved[gw] = struct{}{}
+	}
+	if allocateReserved {
+		return reserved
+	}
+	for _, r := range ranges {
+		for _, addr := range []netip.Addr{r.From, r.To} {
+			prefix, err := addr.Prefix(prefixLength)
+			if err != nil {
+				continue
+			}
+			reserved[prefix.Addr()] = struct{}{}
+			if addr.Is4() && prefixLength < 31 {
+				reserved[lastAddr(prefix)] = struct{}{}
+			}
+		}
+	}
+	return reserved
+}
+
+// Count returns the number of addresses in the ranges, excluding the reserved addresses; the count is capped at
+// math.MaxInt to support large IPv6 ranges.
+// NOTE: The ranges are expected not to overlap.
+func Count(ranges []Range, reserved map[netip.Addr]struct{}) int {
+	total := big.NewInt(0)
+	for _, r := range ranges {
+		size := new(big.Int).Sub(new(big.Int).SetBytes(r.To.AsSlice()), new(big.Int).SetBytes(r.From.AsSlice()))
+		total.Add(total, size.Add(size, big.NewInt(1)))
+	}
+	for addr := range reserved {
+		if Contains(ranges, addr) {
+			total.Sub(total, big.NewInt(1))
+		}
+	}
+	if !total.IsInt64() || total.Int64() > math.MaxInt {
+		return math.MaxInt
+	}
+	return int(total.Int64())
+}
+
+// FindFreeAddress returns the first address in the ranges which is neither in use nor reserved; false is returned
+// if there are no free addresses.
+func FindFreeAddress(ranges []Range, inUse, reserved map[netip.Addr]struct{}) (netip.Addr, bool) {
+	for _, r := range ranges {
+		for addr := r.From; addr.IsValid() && addr.Compare(r.To) <= 0; addr = addr.Next() {
+			if _, ok := inUse[addr]; ok {
+				continue
+			}
+			if _, ok := reserved[addr]; ok {
+				continue
+			}
+			return addr, true
+		}
+	}
+	return netip.Addr{}, false
+}
+
+// Contains returns true if the address is part of any of the ranges.
+func Contains(ranges []Range, addr netip.Addr) bool {
+	for _, r := range ranges {
+		if r.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastAddr returns the last address of a prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	bytes := prefix.Masked().Addr().AsSlice()
+	for i := prefix.Bits(); i < len(bytes)*8; i++ {
+		bytes[i/8] |= 1 << (7 - uint(i%8))
+	}
+	addr, _ := netip.AddrFromSlice(bytes)
+	return addr
+}
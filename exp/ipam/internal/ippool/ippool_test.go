@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ippool
+
+import (
+	"math"
+	"net/netip"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+		wantErr bool
+	}{
+		{address: "10.0.0.10", want: "10.0.0.10"},
+		{address: "10.0.0.10-10.0.0.20", want: "10.0.0.10-10.0.0.20"},
+		{address: "10.0.0.10 - 10.0.0.20", want: "10.0.0.10-10.0.0.20"},
+		{address: "10.0.0.5/30", want: "10.0.0.4-10.0.0.7"},
+		{address: "fd00::/126", want: "fd00::-fd00::3"},
+		{address: "10.0.0.20-10.0.0.10", wantErr: true},
+		{address: "10.0.0.10-fd00::1", wantErr: true},
+		{address: "10.0.0.0/33", wantErr: true},
+		{address: "foo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := ParseAddress(tt.address)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.String()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestRangeOverlaps(t *testing.T) {
+	g := NewWithT(t)
+
+	a, _ := ParseAddress("10.0.0.0/24")
+	b, _ := ParseAddress("10.0.0.255-10.0.1.10")
+	c, _ := ParseAddress("10.0.1.11")
+	d, _ := ParseAddress("::a00:0/120")
+
+	g.Expect(a.Overlaps(b)).To(BeTrue())
+	g.Expect(b.Overlaps(a)).To(BeTrue())
+	g.Expect(a.Overlaps(c)).To(BeFalse())
+	g.Expect(a.Overlaps(d)).To(BeFalse())
+}
+
+func TestCountAndFindFreeAddress(t *testing.T) {
+	g := NewWithT(t)
+
+	ranges, err := ParseAddresses([]string{"10.0.0.0/29", "10.0.1.10"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The gateway, and the network and broadcast addresses of 10.0.0.0/24 and 10.0.1.0/24 are reserved.
+	reserved := ReservedAddresses(ranges, 24, "10.0.0.1", false)
+	g.Expect(reserved).To(HaveLen(5))
+	g.Expect(reserved).To(HaveKey(netip.MustParseAddr("10.0.0.0")))
+	g.Expect(reserved).To(HaveKey(netip.MustParseAddr("10.0.0.1")))
+	g.Expect(reserved).To(HaveKey(netip.MustParseAddr("10.0.0.255")))
+	g.Expect(reserved).To(HaveKey(netip.MustParseAddr("10.0.1.0")))
+	g.Expect(reserved).To(HaveKey(netip.MustParseAddr("10.0.1.255")))
+	g.Expect(Count(ranges, reserved)).To(Equal(7))
+
+	inUse := map[netip.Addr]struct{}{
+		netip.MustParseAddr("10.0.0.2"): {},
+		netip.MustParseAddr("10.0.0.3"): {},
+	}
+	addr, ok := FindFreeAddress(ranges, inUse, reserved)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(addr.String()).To(Equal("10.0.0.4"))
+
+	for _, a := range []string{"10.0.0.4", "10.0.0.5", "10.0.0.6", "10.0.0.7"} {
+		inUse[netip.MustParseAddr(a)] = struct{}{}
+	}
+	addr, ok = FindFreeAddress(ranges, inUse, reserved)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(addr.String()).To(Equal("10.0.1.10"))
+
+	inUse[addr] = struct{}{}
+	_, ok = FindFreeAddress(ranges, inUse, reserved)
+	g.Expect(ok).To(BeFalse())
+
+	// Reserved addresses can be allocated when allowed, but never the gateway.
+	reserved = ReservedAddresses(ranges, 24, "10.0.0.1", true)
+	g.Expect(reserved).To(HaveLen(1))
+	g.Expect(Count(ranges, reserved)).To(Equal(8))
+}
+
+func TestCountLargeRanges(t *testing.T) {
+	g := NewWithT(t)
+
+	ranges, err := ParseAddresses([]string{"fd00::/32"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(Count(ranges, nil)).To(Equal(math.MaxInt))
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/exp/ipam/internal/ippool"
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+// SetupWebhookWithManager sets up InClusterIPPool webhooks.
+func (webhook *InClusterIPPool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&ipamv1.InClusterIPPool{}).
+		WithValidator(webhook).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-ipam-cluster-x-k8s-io-v1beta1-inclusterippool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=ipam.cluster.x-k8s.io,resources=inclusterippools,versions=v1beta1,name=validation.inclusterippool.ipam.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// InClusterIPPool implements a validating webhook for InClusterIPPool.
+type InClusterIPPool struct {
+}
+
+var _ webhook.CustomValidator = &InClusterIPPool{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (webhook *InClusterIPPool) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pool, ok := obj.(*ipamv1.InClusterIPPool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an InClusterIPPool but got a %T", obj))
+	}
+	return nil, webhook.validate(pool)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (webhook *InClusterIPPool) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	pool, ok := newObj.(*ipamv1.InClusterIPPool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an InClusterIPPool but got a %T", newObj))
+	}
+	return nil, webhook.validate(pool)
+}
+
+// ValidateDelete implements webhook.CustomValidator.
+func (webhook *InClusterIPPool) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (webhook *InClusterIPPool) validate(pool *ipamv1.InClusterIPPool) error {
+	// NOTE: InClusterIPPool is behind the InClusterIPAM feature gate flag; the webhook
+	// must prevent creating new objects when the feature flag is disabled.
+	if !feature.Gates.Enabled(feature.InClusterIPAM) {
+		return field.Forbidden(
+			field.NewPath("spec"),
+			"can be set only if the InClusterIPAM feature flag is enabled",
+		)
+	}
+
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if len(pool.Spec.Addresses) == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("addresses"), "at least one address is required"))
+	}
+
+	ranges := []ippool.Range{}
+	bitLen := 0
+	for i, address := range pool.Spec.Addresses {
+		fldPath := specPath.Child("addresses").Index(i)
+		r, err := ippool.ParseAddress(address)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, address, err.Error()))
+			continue
+		}
+		if bitLen == 0 {
+			bitLen = r.From.BitLen()
+		} else if r.From.BitLen() != bitLen {
+			allErrs = append(allErrs, field.Invalid(fldPath, address, "all the addresses must be of the same IP family"))
+			continue
+		}
+		for _, other := range ranges {
+			if r.Overlaps(other) {
+				allErrs = append(allErrs, field.Invalid(fldPath, address, fmt.Sprintf("overlaps with %s", other)))
+				break
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	if pool.Spec.Prefix < 0 || (bitLen != 0 && pool.Spec.Prefix > bitLen) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("prefix"), pool.Spec.Prefix, fmt.Sprintf("must be between 0 and %d", bitLen)))
+	}
+
+	if pool.Spec.Gateway != "" {
+		gateway, err := netip.ParseAddr(pool.Spec.Gateway)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("gateway"), pool.Spec.Gateway, "not a valid IP address"))
+		} else if bitLen != 0 && gateway.BitLen() != bitLen {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("gateway"), pool.Spec.Gateway, "must be of the same IP family of the addresses"))
+		}
+	}
+
+	if policy := pool.Spec.ReleasePolicy; policy != nil {
+		policyPath := specPath.Child("releasePolicy")
+		if policy.ReuseDelay != nil && policy.ReuseDelay.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(policyPath.Child("reuseDelay"), policy.ReuseDelay.Duration.String(), "must be greater than or equal to 0"))
+		}
+		if policy.StickyReservations && pool.GetReuseDelay() <= 0 {
+			allErrs = append(allErrs, field.Invalid(policyPath.Child("stickyReservations"), policy.StickyReservations, "requires reuseDelay to be set"))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(ipamv1.GroupVersion.WithKind(ipamv1.InClusterIPPoolKind).GroupKind(), pool.Name, allErrs)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+func TestInClusterIPPoolValidate(t *testing.T) {
+	getPool := func(fn func(pool *ipamv1.InClusterIPPool)) ipamv1.InClusterIPPool {
+		pool := ipamv1.InClusterIPPool{
+			Spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.0.10-10.0.0.20", "10.0.0.128/28", "10.0.0.200"},
+				Prefix:    24,
+				Gateway:   "10.0.0.1",
+			},
+		}
+		fn(&pool)
+		return pool
+	}
+
+	tests := []struct {
+		name      string
+		pool      ipamv1.InClusterIPPool
+		expectErr bool
+	}{
+		{
+			name:      "should accept a valid pool",
+			pool:      getPool(func(pool *ipamv1.InClusterIPPool) {}),
+			expectErr: false,
+		},
+		{
+			name: "should accept a valid IPv6 pool",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Addresses = []string{"fd00::10-fd00::20"}
+				pool.Spec.Prefix = 64
+				pool.Spec.Gateway = "fd00::1"
+			}),
+			expectErr: false,
+		},
+		{
+			name: "should reject a pool without addresses",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Addresses = nil
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject invalid addresses",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Addresses = []string{"10.0.0.20-10.0.0.10"}
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject addresses of different IP families",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Addresses = append(pool.Spec.Addresses, "fd00::10")
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject overlapping addresses",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Addresses = append(pool.Spec.Addresses, "10.0.0.15")
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject a prefix too large for the IP family",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Prefix = 33
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject an invalid gateway",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Gateway = "10.0.0"
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should accept a valid release policy",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.ReleasePolicy = &ipamv1.InClusterIPPoolReleasePolicy{
+					ReuseDelay:           &metav1.Duration{Duration: time.Hour},
+					StickyReservations:   true,
+					DeleteOrphanedClaims: true,
+				}
+			}),
+			expectErr: false,
+		},
+		{
+			name: "should reject a negative reuse delay",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.ReleasePolicy = &ipamv1.InClusterIPPoolReleasePolicy{
+					ReuseDelay: &metav1.Duration{Duration: -time.Hour},
+				}
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject sticky reservations without a reuse delay",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.ReleasePolicy = &ipamv1.InClusterIPPoolReleasePolicy{
+					StickyReservations: true,
+				}
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject a gateway of another IP family",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Gateway = "fd00::1"
+			}),
+			expectErr: true,
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.InClusterIPAM, true)()
+			g := NewWithT(t)
+			wh := InClusterIPPool{}
+
+			warnings, err := wh.ValidateCreate(context.Background(), &tt.pool)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(warnings).To(BeEmpty())
+
+			warnings, err = wh.ValidateUpdate(context.Background(), &tt.pool, &tt.pool)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(warnings).To(BeEmpty())
+		})
+	}
+}
+
+func TestInClusterIPPoolFeatureGateDisabled(t *testing.T) {
+	// NOTE: InClusterIPAM feature flag is disabled by default, thus preventing to create InClusterIPPools.
+	g := NewWithT(t)
+
+	wh := InClusterIPPool{}
+	warnings, err := wh.ValidateCreate(context.Background(), &ipamv1.InClusterIPPool{
+		Spec: ipamv1.InClusterIPPoolSpec{Addresses: []string{"10.0.0.10"}, Prefix: 24},
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(warnings).To(BeEmpty())
+}
@@ -23,6 +23,7 @@ import (
 	"reflect"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -156,17 +157,23 @@ func (webhook *IPAddress) validate(ctx context.Context, ip *ipamv1.IPAddress) er
 	}
 
 	if claim.Name != "" && // only report non-matching pool if the claim exists
-		!(ip.Spec.PoolRef.APIGroup != nil && claim.Spec.PoolRef.APIGroup != nil &&
-			*ip.Spec.PoolRef.APIGroup == *claim.Spec.PoolRef.APIGroup &&
-			ip.Spec.PoolRef.Kind == claim.Spec.PoolRef.Kind &&
-			ip.Spec.PoolRef.Name == claim.Spec.PoolRef.Name) {
+		!isSamePool(ip.Spec.PoolRef, claim.Spec.PoolRef) &&
+		(claim.Spec.SecondaryPoolRef == nil || !isSamePool(ip.Spec.PoolRef, *claim.Spec.SecondaryPoolRef)) {
 		allErrs = append(allErrs,
 			field.Invalid(
 				specPath.Child("poolRef"),
 				ip.Spec.PoolRef,
-				"the referenced pool is different from the pool referenced by the claim this address should fulfill",
+				"the referenced pool is different from the pools referenced by the claim this address should fulfill",
 			))
 	}
 
 	return allErrs.ToAggregate()
 }
+
+// isSamePool returns true if two pool references point to the same pool.
+func isSamePool(a, b corev1.TypedLocalObjectReference) bool {
+	return a.APIGroup != nil && b.APIGroup != nil &&
+		*a.APIGroup == *b.APIGroup &&
+		a.Kind == b.Kind &&
+		a.Name == b.Name
+}
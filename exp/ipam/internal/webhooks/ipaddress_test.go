@@ -50,6 +50,13 @@ func TestIPAddressValidateCreate(t *testing.T) {
 		},
 	}
 
+	dualStackClaim := claim.DeepCopy()
+	dualStackClaim.Spec.SecondaryPoolRef = &corev1.TypedLocalObjectReference{
+		Kind:     "TestPool",
+		Name:     "secondary-pool",
+		APIGroup: pointer.String("ipam.cluster.x-k8s.io"),
+	}
+
 	getAddress := func(v6 bool, fn func(addr *ipamv1.IPAddress)) ipamv1.IPAddress {
 		addr := ipamv1.IPAddress{
 			ObjectMeta: metav1.ObjectMeta{
@@ -146,6 +153,22 @@ func TestIPAddressValidateCreate(t *testing.T) {
 			extraObjs: []client.Object{claim},
 			expectErr: true,
 		},
+		{
+			name: "a pool reference that matches the secondary pool of the claim should be accepted",
+			ip: getAddress(true, func(addr *ipamv1.IPAddress) {
+				addr.Spec.PoolRef = *dualStackClaim.Spec.SecondaryPoolRef
+			}),
+			extraObjs: []client.Object{dualStackClaim},
+			expectErr: false,
+		},
+		{
+			name: "a pool reference that does not match any of the pools of the claim should be rejected",
+			ip: getAddress(true, func(addr *ipamv1.IPAddress) {
+				addr.Spec.PoolRef.Name = "nothing"
+			}),
+			extraObjs: []client.Object{dualStackClaim},
+			expectErr: true,
+		},
 		{
 			name: "a pool reference that does not contain a group should be rejected",
 			ip: getAddress(false, func(addr *ipamv1.IPAddress) {
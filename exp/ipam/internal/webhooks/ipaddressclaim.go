@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +32,10 @@ import (
 	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 )
 
+// leaseRenewTimeMaxClockSkew is the clock skew allowed between the consumer of a claim renewing its lease
+// and the webhook; a renewTime further in the future would extend the lease beyond its duration.
+const leaseRenewTimeMaxClockSkew = time.Minute
+
 // SetupWebhookWithManager sets up IPAddressClaim webhooks.
 func (webhook *IPAddressClaim) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -61,6 +66,32 @@ func (webhook *IPAddressClaim) ValidateCreate(_ context.Context, obj runtime.Obj
 			"the pool reference needs to contain a group")
 	}
 
+	if claim.Spec.SecondaryPoolRef != nil {
+		if claim.Spec.SecondaryPoolRef.APIGroup == nil {
+			return nil, field.Invalid(
+				field.NewPath("spec.secondaryPoolRef.apiGroup"),
+				claim.Spec.SecondaryPoolRef.APIGroup,
+				"the pool reference needs to contain a group")
+		}
+		if reflect.DeepEqual(claim.Spec.PoolRef, *claim.Spec.SecondaryPoolRef) {
+			return nil, field.Invalid(
+				field.NewPath("spec.secondaryPoolRef"),
+				claim.Spec.SecondaryPoolRef,
+				"the secondary pool reference must be different from the pool reference")
+		}
+	}
+
+	if claim.Spec.Lease != nil && claim.Spec.Lease.Duration.Duration <= 0 {
+		return nil, field.Invalid(
+			field.NewPath("spec.lease.duration"),
+			claim.Spec.Lease.Duration.Duration.String(),
+			"the lease duration must be greater than 0")
+	}
+
+	if err := validateLeaseRenewTime(claim); err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -75,15 +106,39 @@ func (webhook *IPAddressClaim) ValidateUpdate(_ context.Context, oldObj, newObj
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an IPAddressClaim but got a %T", newObj))
 	}
 
-	if !reflect.DeepEqual(oldClaim.Spec, newClaim.Spec) {
+	// The lease can be renewed by the consumer of the claim, everything else is immutable.
+	oldSpec := oldClaim.Spec.DeepCopy()
+	newSpec := newClaim.Spec.DeepCopy()
+	if oldSpec.Lease != nil && newSpec.Lease != nil {
+		oldSpec.Lease.RenewTime = nil
+		newSpec.Lease.RenewTime = nil
+	}
+	if !reflect.DeepEqual(oldSpec, newSpec) {
 		return nil, field.Forbidden(
 			field.NewPath("spec"),
-			"the spec of IPAddressClaim is immutable",
+			"the spec of IPAddressClaim is immutable, except for lease.renewTime",
 		)
 	}
+	if err := validateLeaseRenewTime(newClaim); err != nil {
+		return nil, err
+	}
 	return nil, nil
 }
 
+// validateLeaseRenewTime rejects a lease renewed in the future, beyond the allowed clock skew.
+func validateLeaseRenewTime(claim *ipamv1.IPAddressClaim) error {
+	if claim.Spec.Lease == nil || claim.Spec.Lease.RenewTime == nil {
+		return nil
+	}
+	if claim.Spec.Lease.RenewTime.After(time.Now().Add(leaseRenewTimeMaxClockSkew)) {
+		return field.Invalid(
+			field.NewPath("spec.lease.renewTime"),
+			claim.Spec.Lease.RenewTime.String(),
+			fmt.Sprintf("the lease renew time must not be in the future, allowing for a clock skew of %s", leaseRenewTimeMaxClockSkew))
+	}
+	return nil
+}
+
 // ValidateDelete implements webhook.CustomValidator.
 func (webhook *IPAddressClaim) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
 	return nil, nil
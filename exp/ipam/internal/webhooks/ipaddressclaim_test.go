@@ -19,9 +19,11 @@ package webhooks
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
 	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
@@ -59,6 +61,55 @@ func TestIPAddressClaimValidateCreate(t *testing.T) {
 			}),
 			expectErr: true,
 		},
+		{
+			name: "should accept a valid dual-stack claim",
+			claim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.SecondaryPoolRef = &corev1.TypedLocalObjectReference{
+					Name:     "secondary",
+					Kind:     "TestPool",
+					APIGroup: pointer.String("ipam.cluster.x-k8s.io"),
+				}
+			}),
+			expectErr: false,
+		},
+		{
+			name: "should reject a secondary pool reference without a group",
+			claim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.SecondaryPoolRef = &corev1.TypedLocalObjectReference{
+					Name: "secondary",
+					Kind: "TestPool",
+				}
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject a secondary pool reference identical to the pool reference",
+			claim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.SecondaryPoolRef = addr.Spec.PoolRef.DeepCopy()
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should accept a claim with a lease",
+			claim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.Lease = &ipamv1.IPAddressClaimLease{Duration: metav1.Duration{Duration: time.Hour}}
+			}),
+			expectErr: false,
+		},
+		{
+			name: "should reject a lease without a duration",
+			claim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.Lease = &ipamv1.IPAddressClaimLease{}
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject a lease renewed in the future",
+			claim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.Lease = &ipamv1.IPAddressClaimLease{Duration: metav1.Duration{Duration: time.Hour}, RenewTime: &metav1.Time{Time: time.Now().Add(time.Hour)}}
+			}),
+			expectErr: true,
+		},
 	}
 
 	for i := range tests {
@@ -110,6 +161,46 @@ func TestIPAddressClaimValidateUpdate(t *testing.T) {
 			}),
 			expectErr: true,
 		},
+		{
+			name: "should accept the renewal of the lease",
+			oldClaim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.Lease = &ipamv1.IPAddressClaimLease{Duration: metav1.Duration{Duration: time.Hour}}
+			}),
+			newClaim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.Lease = &ipamv1.IPAddressClaimLease{Duration: metav1.Duration{Duration: time.Hour}, RenewTime: &metav1.Time{Time: time.Now()}}
+			}),
+			expectErr: false,
+		},
+		{
+			name: "should reject changes to the lease duration",
+			oldClaim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.Lease = &ipamv1.IPAddressClaimLease{Duration: metav1.Duration{Duration: time.Hour}}
+			}),
+			newClaim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.Lease = &ipamv1.IPAddressClaimLease{Duration: metav1.Duration{Duration: 2 * time.Hour}}
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should accept the renewal of the lease within the allowed clock skew",
+			oldClaim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.Lease = &ipamv1.IPAddressClaimLease{Duration: metav1.Duration{Duration: time.Hour}}
+			}),
+			newClaim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.Lease = &ipamv1.IPAddressClaimLease{Duration: metav1.Duration{Duration: time.Hour}, RenewTime: &metav1.Time{Time: time.Now().Add(10 * time.Second)}}
+			}),
+			expectErr: false,
+		},
+		{
+			name: "should reject the renewal of the lease in the future",
+			oldClaim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.Lease = &ipamv1.IPAddressClaimLease{Duration: metav1.Duration{Duration: time.Hour}}
+			}),
+			newClaim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.Lease = &ipamv1.IPAddressClaimLease{Duration: metav1.Duration{Duration: time.Hour}, RenewTime: &metav1.Time{Time: time.Now().Add(24 * time.Hour)}}
+			}),
+			expectErr: true,
+		},
 	}
 
 	for i := range tests {
@@ -43,3 +43,12 @@ type IPAddressClaim struct {
 func (webhook *IPAddressClaim) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return (&webhooks.IPAddressClaim{}).SetupWebhookWithManager(mgr)
 }
+
+// InClusterIPPool implements a validating webhook for InClusterIPPool.
+type InClusterIPPool struct {
+}
+
+// SetupWebhookWithManager sets up InClusterIPPool webhooks.
+func (webhook *InClusterIPPool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return (&webhooks.InClusterIPPool{}).SetupWebhookWithManager(mgr)
+}
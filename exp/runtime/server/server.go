@@ -36,6 +36,7 @@ import (
 
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 // DefaultPort is the default port that the webhook server serves.
@@ -232,7 +233,7 @@ func (s *Server) Start(ctx context.Context) error {
 		handler := h
 
 		wrappedHandler := s.wrapHandler(handler)
-		s.server.Register(handlerPath, http.HandlerFunc(wrappedHandler))
+		s.server.Register(handlerPath, tracing.WrapHandler(http.HandlerFunc(wrappedHandler), runtimecatalog.HookName(handler.Hook)))
 	}
 
 	return s.server.Start(ctx)
@@ -60,6 +60,16 @@ const (
 	//
 	// alpha: v1.5
 	MachineSetPreflightChecks featuregate.Feature = "MachineSetPreflightChecks"
+
+	// ClusterAddon is a feature gate for the ClusterAddon and ClusterAddonSet functionality.
+	//
+	// alpha: v1.6
+	ClusterAddon featuregate.Feature = "ClusterAddon"
+
+	// InClusterIPAM is a feature gate for the in-cluster IPAM provider functionality.
+	//
+	// alpha: v1.6
+	InClusterIPAM featuregate.Feature = "InClusterIPAM"
 )
 
 func init() {
@@ -76,4 +86,6 @@ var defaultClusterAPIFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 	KubeadmBootstrapFormatIgnition: {Default: false, PreRelease: featuregate.Alpha},
 	RuntimeSDK:                     {Default: false, PreRelease: featuregate.Alpha},
 	MachineSetPreflightChecks:      {Default: false, PreRelease: featuregate.Alpha},
+	ClusterAddon:                   {Default: false, PreRelease: featuregate.Alpha},
+	InClusterIPAM:                  {Default: false, PreRelease: featuregate.Alpha},
 }
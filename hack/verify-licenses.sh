@@ -34,8 +34,10 @@ REPO_ROOT=$(git rev-parse --show-toplevel)
 TRIVY="${REPO_ROOT}/hack/tools/bin/trivy/${VERSION}/trivy"
 $TRIVY filesystem . --license-full --ignored-licenses ${CNCF_LICENSE_ALLOWLIST} --scanners license --severity UNKNOWN,LOW,MEDIUM,HIGH,CRITICAL -f json | \
 # Specifically ignore 'github.com/hashicorp/hcl'. This is a known indirect dependency that we should remove where possible.
-# This query ensures we only skip github.com/hashicorp/hcl for as long as the license remains MPL-2.0
-jq  '.Results[] | select( .Licenses[]?.PkgName == "github.com/hashicorp/hcl" and .Licenses[]?.Name == "MPL-2.0" | not) | if . == {} then .  else error(.) end'
+# Specifically ignore 'github.com/letsencrypt/boulder'. This is an indirect dependency of 'github.com/sigstore/sigstore',
+# which is used unmodified to verify the cosign signatures of the OCI artifacts referenced by ClusterResourceSets.
+# This query ensures we only skip these dependencies for as long as their license remains MPL-2.0
+jq  '.Results[] | .Licenses = ((.Licenses // []) | map(select((.PkgName == "github.com/hashicorp/hcl" or .PkgName == "github.com/letsencrypt/boulder") and .Name == "MPL-2.0" | not))) | select(.Licenses != []) | error(.)'
 
 
 
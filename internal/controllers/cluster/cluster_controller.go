@@ -27,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
@@ -42,12 +43,16 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/hooks"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/metrics"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/shard"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 const (
@@ -72,25 +77,46 @@ type Reconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// Sharder, if set, limits the reconciliation to the Clusters assigned to this replica of the manager.
+	Sharder *shard.Sharder
+
 	recorder        record.EventRecorder
 	externalTracker external.ObjectTracker
 }
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
-	c, err := ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.Cluster{}).
 		Watches(
 			&clusterv1.Machine{},
 			handler.EnqueueRequestsFromMapFunc(r.controlPlaneMachineToCluster),
 		).
+		Watches(
+			&clusterv1.MachineDeployment{},
+			handler.EnqueueRequestsFromMapFunc(r.machineDeploymentToCluster),
+		)
+	if feature.Gates.Enabled(feature.MachinePool) {
+		b = b.Watches(
+			&expv1.MachinePool{},
+			handler.EnqueueRequestsFromMapFunc(r.machinePoolToCluster),
+		)
+	}
+	c, err := b.
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
-		Build(r)
+		Build(tracing.Reconciler("cluster", "Cluster", metrics.Reconciler("cluster", "Cluster", r)))
 
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
+	if r.Sharder != nil {
+		// Reconcile the Clusters assigned to this replica, e.g. after another replica left.
+		if err := c.Watch(r.Sharder.Source(), &handler.EnqueueRequestForObject{}); err != nil {
+			return errors.Wrap(err, "failed adding Watch for the Clusters assigned to the shard")
+		}
+	}
+
 	r.recorder = mgr.GetEventRecorderFor("cluster-controller")
 	r.externalTracker = external.ObjectTracker{
 		Controller: c,
@@ -115,10 +141,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
-	// Return early if the object or Cluster is paused.
+	// Return early if the Cluster is reconciled by another replica.
+	if !r.Sharder.Owns(cluster) {
+		log.V(4).Info("Reconciliation is skipped, the Cluster is assigned to another replica")
+		return ctrl.Result{}, nil
+	}
+
+	// Return early if the object or Cluster is paused, after acknowledging it.
 	if annotations.IsPaused(cluster, cluster) {
 		log.Info("Reconciliation is paused for this object")
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.reconcilePaused(ctx, cluster)
 	}
 
 	// Initialize the patch helper.
@@ -127,6 +159,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	// The Cluster is not paused anymore, if it ever was.
+	conditions.Delete(cluster, clusterv1.PausedCondition)
+
+	r.reconcileShard(cluster)
+
 	defer func() {
 		// Always reconcile the Status.Phase field.
 		r.reconcilePhase(ctx, cluster)
@@ -175,11 +212,48 @@ func patchCluster(ctx context.Context, patchHelper *patch.Helper, cluster *clust
 			clusterv1.ReadyCondition,
 			clusterv1.ControlPlaneReadyCondition,
 			clusterv1.InfrastructureReadyCondition,
+			clusterv1.RolloutCompletedCondition,
+			clusterv1.PausedCondition,
 		}},
 	)
 	return patchHelper.Patch(ctx, cluster, options...)
 }
 
+// reconcilePaused acknowledges that the Cluster is paused by setting the Paused condition, so users and tools like
+// clusterctl can tell when the reconciliation of the Cluster stopped, e.g. during the maintenance of its Namespace.
+func (r *Reconciler) reconcilePaused(ctx context.Context, cluster *clusterv1.Cluster) error {
+	if conditions.IsTrue(cluster, clusterv1.PausedCondition) {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(cluster, r.Client)
+	if err != nil {
+		return err
+	}
+	conditions.MarkTrue(cluster, clusterv1.PausedCondition)
+	return patchHelper.Patch(ctx, cluster, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+		clusterv1.PausedCondition,
+	}})
+}
+
+// reconcileShard publishes the replica reconciling the Cluster, if the controllers are sharded.
+func (r *Reconciler) reconcileShard(cluster *clusterv1.Cluster) {
+	if r.Sharder == nil {
+		cluster.Status.Shard = nil
+		return
+	}
+
+	status := &clusterv1.ClusterShardStatus{
+		Owner:              r.Sharder.ID,
+		Key:                shard.Key(cluster),
+		LastTransitionTime: metav1.Now(),
+	}
+	if cluster.Status.Shard != nil && cluster.Status.Shard.Owner == status.Owner {
+		status.LastTransitionTime = cluster.Status.Shard.LastTransitionTime
+	}
+	cluster.Status.Shard = status
+}
+
 // reconcile handles cluster reconciliation.
 func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -197,6 +271,8 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster)
 		r.reconcileControlPlane,
 		r.reconcileKubeconfig,
 		r.reconcileControlPlaneInitialized,
+		r.reconcileRollout,
+		r.reconcileMachines,
 	}
 
 	res := ctrl.Result{}
@@ -334,7 +410,7 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 	}
 
 	controllerutil.RemoveFinalizer(cluster, clusterv1.ClusterFinalizer)
-	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "Deleted", "Cluster %s has been deleted", cluster.Name)
+	r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "", ""), corev1.EventTypeNormal, clusterv1.EventDeleted, "Cluster %s has been deleted", cluster.Name)
 	return ctrl.Result{}, nil
 }
 
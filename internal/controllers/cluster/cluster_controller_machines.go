@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/contract"
+)
+
+// reconcileMachines summarizes the number of Machines of the control plane and of the worker pools,
+// i.e. the MachineDeployments and the MachinePools, of a Cluster in Cluster.Status.Machines.
+func (r *Reconciler) reconcileMachines(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	var controlPlaneCounts *clusterv1.MachineCounts
+	if cluster.Spec.ControlPlaneRef != nil {
+		controlPlane, err := external.Get(ctx, r.UnstructuredCachingClient, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		// NOTE: The control plane not being found is surfaced by reconcileControlPlane.
+		if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+			return ctrl.Result{}, err
+		}
+		if err == nil {
+			controlPlaneCounts, err = controlPlaneMachineCounts(controlPlane)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	var workerPools []clusterv1.WorkerPoolMachineCounts
+
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, machineDeployments, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list MachineDeployments for Cluster %s", cluster.Name)
+	}
+	for i := range machineDeployments.Items {
+		workerPools = append(workerPools, machineDeploymentMachineCounts(&machineDeployments.Items[i]))
+	}
+
+	if feature.Gates.Enabled(feature.MachinePool) {
+		machinePools := &expv1.MachinePoolList{}
+		if err := r.Client.List(ctx, machinePools, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to list MachinePools for Cluster %s", cluster.Name)
+		}
+		for i := range machinePools.Items {
+			workerPools = append(workerPools, machinePoolMachineCounts(&machinePools.Items[i]))
+		}
+	}
+
+	setMachinesStatus(cluster, controlPlaneCounts, workerPools)
+	return ctrl.Result{}, nil
+}
+
+// controlPlaneMachineCounts returns the number of Machines of a control plane, if it reports its replicas.
+// The replica counts are optional in the control plane contract, so missing counts are reported as 0.
+func controlPlaneMachineCounts(controlPlane *unstructured.Unstructured) (*clusterv1.MachineCounts, error) {
+	desired, err := contract.ControlPlane().Replicas().Get(controlPlane)
+	if err != nil {
+		if errors.Is(err, contract.ErrFieldNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get spec.replicas from %s %s", controlPlane.GetKind(), controlPlane.GetName())
+	}
+
+	getOptional := func(field *contract.Int64) (int64, error) {
+		value, err := field.Get(controlPlane)
+		if err != nil {
+			if errors.Is(err, contract.ErrFieldNotFound) {
+				return 0, nil
+			}
+			return 0, errors.Wrapf(err, "failed to get %s from %s %s", field.Path(), controlPlane.GetKind(), controlPlane.GetName())
+		}
+		return *value, nil
+	}
+	replicas, err := getOptional(contract.ControlPlane().StatusReplicas())
+	if err != nil {
+		return nil, err
+	}
+	ready, err := getOptional(contract.ControlPlane().ReadyReplicas())
+	if err != nil {
+		return nil, err
+	}
+	unavailable, err := getOptional(contract.ControlPlane().UnavailableReplicas())
+	if err != nil {
+		return nil, err
+	}
+	upToDate, err := getOptional(contract.ControlPlane().UpdatedReplicas())
+	if err != nil {
+		return nil, err
+	}
+
+	available := replicas - unavailable
+	if available < 0 {
+		available = 0
+	}
+	return &clusterv1.MachineCounts{
+		Desired:   int32(*desired),
+		Ready:     int32(ready),
+		Available: int32(available),
+		UpToDate:  int32(upToDate),
+	}, nil
+}
+
+// machineDeploymentMachineCounts returns the number of Machines of a MachineDeployment.
+func machineDeploymentMachineCounts(md *clusterv1.MachineDeployment) clusterv1.WorkerPoolMachineCounts {
+	counts := clusterv1.WorkerPoolMachineCounts{
+		Kind:      "MachineDeployment",
+		Name:      md.Name,
+		Ready:     md.Status.ReadyReplicas,
+		Available: md.Status.AvailableReplicas,
+		UpToDate:  md.Status.UpdatedReplicas,
+	}
+	if md.Spec.Replicas != nil {
+		counts.Desired = *md.Spec.Replicas
+	}
+	return counts
+}
+
+// machinePoolMachineCounts returns the number of Machines of a MachinePool.
+// MachinePools don't report which replicas are up to date, so the replicas are considered up to date
+// unless the MachinePool is rolling out, consistently with Cluster.Status.Rollout.
+func machinePoolMachineCounts(mp *expv1.MachinePool) clusterv1.WorkerPoolMachineCounts {
+	counts := clusterv1.WorkerPoolMachineCounts{
+		Kind:      "MachinePool",
+		Name:      mp.Name,
+		Ready:     mp.Status.ReadyReplicas,
+		Available: mp.Status.AvailableReplicas,
+		UpToDate:  mp.Status.Replicas,
+	}
+	if mp.Spec.Replicas != nil {
+		counts.Desired = *mp.Spec.Replicas
+	}
+	if rollout := machinePoolRollout(mp); rollout != nil {
+		counts.UpToDate = rollout.Replicas - rollout.PendingReplicas
+	}
+	return counts
+}
+
+// setMachinesStatus sets Cluster.Status.Machines according to the number of Machines of the control plane and of the worker pools.
+func setMachinesStatus(cluster *clusterv1.Cluster, controlPlane *clusterv1.MachineCounts, workerPools []clusterv1.WorkerPoolMachineCounts) {
+	sort.Slice(workerPools, func(i, j int) bool {
+		if workerPools[i].Kind != workerPools[j].Kind {
+			return workerPools[i].Kind < workerPools[j].Kind
+		}
+		return workerPools[i].Name < workerPools[j].Name
+	})
+
+	machines := &clusterv1.ClusterMachinesStatus{
+		ControlPlane: controlPlane,
+		WorkerPools:  workerPools,
+	}
+	for _, pool := range workerPools {
+		machines.Workers.Desired += pool.Desired
+		machines.Workers.Ready += pool.Ready
+		machines.Workers.Available += pool.Available
+		machines.Workers.UpToDate += pool.UpToDate
+	}
+	cluster.Status.Machines = machines
+}
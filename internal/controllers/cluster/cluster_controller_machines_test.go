@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestClusterReconcileMachines(t *testing.T) {
+	machineDeployment := func(name string, status clusterv1.MachineDeploymentStatus) *clusterv1.MachineDeployment {
+		return builder.MachineDeployment("test-namespace", name).
+			WithClusterName("test-cluster").
+			WithLabels(map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}).
+			WithReplicas(3).
+			WithStatus(status).
+			Build()
+	}
+
+	tests := []struct {
+		name    string
+		objects []client.Object
+		want    *clusterv1.ClusterMachinesStatus
+	}{
+		{
+			name: "control plane and MachineDeployments",
+			objects: []client.Object{
+				builder.ControlPlane("test-namespace", "cp").
+					WithReplicas(3).
+					WithStatusFields(map[string]interface{}{
+						"status.replicas":            int64(4),
+						"status.readyReplicas":       int64(3),
+						"status.unavailableReplicas": int64(1),
+						"status.updatedReplicas":     int64(2),
+					}).
+					Build(),
+				machineDeployment("md-1", clusterv1.MachineDeploymentStatus{Replicas: 3, ReadyReplicas: 2, AvailableReplicas: 1, UpdatedReplicas: 1}),
+				machineDeployment("md-0", clusterv1.MachineDeploymentStatus{Replicas: 3, ReadyReplicas: 3, AvailableReplicas: 3, UpdatedReplicas: 3}),
+			},
+			want: &clusterv1.ClusterMachinesStatus{
+				ControlPlane: &clusterv1.MachineCounts{Desired: 3, Ready: 3, Available: 3, UpToDate: 2},
+				Workers:      clusterv1.MachineCounts{Desired: 6, Ready: 5, Available: 4, UpToDate: 4},
+				WorkerPools: []clusterv1.WorkerPoolMachineCounts{
+					{Kind: "MachineDeployment", Name: "md-0", Desired: 3, Ready: 3, Available: 3, UpToDate: 3},
+					{Kind: "MachineDeployment", Name: "md-1", Desired: 3, Ready: 2, Available: 1, UpToDate: 1},
+				},
+			},
+		},
+		{
+			name: "control plane without replicas",
+			objects: []client.Object{
+				builder.ControlPlane("test-namespace", "cp").Build(),
+			},
+			want: &clusterv1.ClusterMachinesStatus{},
+		},
+		{
+			name: "control plane without status",
+			objects: []client.Object{
+				builder.ControlPlane("test-namespace", "cp").WithReplicas(1).Build(),
+			},
+			want: &clusterv1.ClusterMachinesStatus{
+				ControlPlane: &clusterv1.MachineCounts{Desired: 1},
+			},
+		},
+		{
+			name: "MachineDeployments of other Clusters are ignored",
+			objects: func() []client.Object {
+				md := machineDeployment("md-0", clusterv1.MachineDeploymentStatus{})
+				md.Labels[clusterv1.ClusterNameLabel] = "other-cluster"
+				return []client.Object{md}
+			}(),
+			want: &clusterv1.ClusterMachinesStatus{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef: &corev1.ObjectReference{
+						APIVersion: builder.ControlPlaneGroupVersion.String(),
+						Kind:       builder.GenericControlPlaneKind,
+						Name:       "cp",
+					},
+				},
+			}
+
+			c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).Build()
+			r := &Reconciler{
+				Client:                    c,
+				UnstructuredCachingClient: c,
+			}
+
+			_, err := r.reconcileMachines(ctx, cluster)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cluster.Status.Machines).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachinePoolMachineCounts(t *testing.T) {
+	g := NewWithT(t)
+
+	mp := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "mp"},
+		Spec:       expv1.MachinePoolSpec{Replicas: pointer.Int32(3)},
+		Status:     expv1.MachinePoolStatus{InfrastructureReady: true, Replicas: 3, ReadyReplicas: 3, AvailableReplicas: 3},
+	}
+
+	// All the replicas are up to date if the MachinePool is not rolling out.
+	g.Expect(machinePoolMachineCounts(mp)).To(Equal(clusterv1.WorkerPoolMachineCounts{
+		Kind: "MachinePool", Name: "mp", Desired: 3, Ready: 3, Available: 3, UpToDate: 3,
+	}))
+
+	// The replicas pending a rollout are not up to date.
+	mp.Status.AvailableReplicas = 2
+	mp.Status.UnavailableReplicas = 1
+	g.Expect(machinePoolMachineCounts(mp)).To(Equal(clusterv1.WorkerPoolMachineCounts{
+		Kind: "MachinePool", Name: "mp", Desired: 3, Ready: 3, Available: 2, UpToDate: 2,
+	}))
+}
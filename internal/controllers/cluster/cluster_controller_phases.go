@@ -32,6 +32,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -68,9 +69,9 @@ func (r *Reconciler) reconcilePhase(_ context.Context, cluster *clusterv1.Cluste
 	if preReconcilePhase != cluster.Status.GetTypedPhase() {
 		// Failed clusters should get a Warning event
 		if cluster.Status.GetTypedPhase() == clusterv1.ClusterPhaseFailed {
-			r.recorder.Eventf(cluster, corev1.EventTypeWarning, string(cluster.Status.GetTypedPhase()), "Cluster %s is %s: %s", cluster.Name, string(cluster.Status.GetTypedPhase()), pointer.StringDeref(cluster.Status.FailureMessage, "unknown"))
+			r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "", ""), corev1.EventTypeWarning, string(cluster.Status.GetTypedPhase()), "Cluster %s is %s: %s", cluster.Name, string(cluster.Status.GetTypedPhase()), pointer.StringDeref(cluster.Status.FailureMessage, "unknown"))
 		} else {
-			r.recorder.Eventf(cluster, corev1.EventTypeNormal, string(cluster.Status.GetTypedPhase()), "Cluster %s is %s", cluster.Name, string(cluster.Status.GetTypedPhase()))
+			r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "", ""), corev1.EventTypeNormal, string(cluster.Status.GetTypedPhase()), "Cluster %s is %s", cluster.Name, string(cluster.Status.GetTypedPhase()))
 		}
 	}
 }
@@ -183,7 +184,7 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 	cluster.Status.InfrastructureReady = ready
 	// Only record the event if the status has changed
 	if preReconcileInfrastructureReady != cluster.Status.InfrastructureReady {
-		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "InfrastructureReady", "Cluster %s InfrastructureReady is now %t", cluster.Name, cluster.Status.InfrastructureReady)
+		r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "", ""), corev1.EventTypeNormal, clusterv1.EventInfrastructureReady, "Cluster %s InfrastructureReady is now %t", cluster.Name, cluster.Status.InfrastructureReady)
 	}
 
 	// Report a summary of current status of the infrastructure object defined for this cluster.
@@ -251,7 +252,7 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, cluster *cluster
 	cluster.Status.ControlPlaneReady = ready
 	// Only record the event if the status has changed
 	if preReconcileControlPlaneReady != cluster.Status.ControlPlaneReady {
-		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "ControlPlaneReady", "Cluster %s ControlPlaneReady is now %t", cluster.Name, cluster.Status.ControlPlaneReady)
+		r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "", ""), corev1.EventTypeNormal, clusterv1.EventControlPlaneReady, "Cluster %s ControlPlaneReady is now %t", cluster.Name, cluster.Status.ControlPlaneReady)
 	}
 
 	// Report a summary of current status of the control plane object defined for this cluster.
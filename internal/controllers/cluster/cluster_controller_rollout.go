@@ -0,0 +1,268 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// maxRolloutObjectsInMessage is the maximum number of objects rolling out listed in the message of the
+// RolloutCompleted condition; all the objects are listed in Cluster.Status.Rollout.
+const maxRolloutObjectsInMessage = 3
+
+// reconcileRollout summarizes the rollouts in progress for the control plane, the MachineDeployments and
+// the MachinePools of a Cluster in the RolloutCompleted condition and in Cluster.Status.Rollout.
+func (r *Reconciler) reconcileRollout(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	objects := []clusterv1.RolloutObject{}
+
+	if cluster.Spec.ControlPlaneRef != nil {
+		controlPlane, err := external.Get(ctx, r.UnstructuredCachingClient, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		// NOTE: The control plane not being found is surfaced by reconcileControlPlane.
+		if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+			return ctrl.Result{}, err
+		}
+		if err == nil {
+			object, err := controlPlaneRollout(controlPlane)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if object != nil {
+				objects = append(objects, *object)
+			}
+		}
+	}
+
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, machineDeployments, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list MachineDeployments for Cluster %s", cluster.Name)
+	}
+	sort.Slice(machineDeployments.Items, func(i, j int) bool { return machineDeployments.Items[i].Name < machineDeployments.Items[j].Name })
+	for i := range machineDeployments.Items {
+		if object := machineDeploymentRollout(&machineDeployments.Items[i]); object != nil {
+			objects = append(objects, *object)
+		}
+	}
+
+	if feature.Gates.Enabled(feature.MachinePool) {
+		machinePools := &expv1.MachinePoolList{}
+		if err := r.Client.List(ctx, machinePools, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to list MachinePools for Cluster %s", cluster.Name)
+		}
+		sort.Slice(machinePools.Items, func(i, j int) bool { return machinePools.Items[i].Name < machinePools.Items[j].Name })
+		for i := range machinePools.Items {
+			if object := machinePoolRollout(&machinePools.Items[i]); object != nil {
+				objects = append(objects, *object)
+			}
+		}
+	}
+
+	setRolloutStatus(cluster, objects, time.Now())
+	return ctrl.Result{}, nil
+}
+
+// controlPlaneRollout returns the rollout of a control plane, if any.
+// A control plane is rolling out if it is upgrading, or if it reports less updated replicas than replicas;
+// the replica counts are optional in the control plane contract, so they are reported only if available.
+func controlPlaneRollout(controlPlane *unstructured.Unstructured) (*clusterv1.RolloutObject, error) {
+	object := &clusterv1.RolloutObject{
+		Kind: controlPlane.GetKind(),
+		Name: controlPlane.GetName(),
+	}
+	rollingOut := false
+
+	version, err := contract.ControlPlane().Version().Get(controlPlane)
+	if err != nil && !errors.Is(err, contract.ErrFieldNotFound) {
+		return nil, errors.Wrapf(err, "failed to get version from %s %s", object.Kind, object.Name)
+	}
+	if version != nil {
+		object.Version = *version
+		upgrading, err := contract.ControlPlane().IsUpgrading(controlPlane)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check if %s %s is upgrading", object.Kind, object.Name)
+		}
+		rollingOut = upgrading
+	}
+
+	replicas, err := contract.ControlPlane().StatusReplicas().Get(controlPlane)
+	if err != nil && !errors.Is(err, contract.ErrFieldNotFound) {
+		return nil, errors.Wrapf(err, "failed to get status.replicas from %s %s", object.Kind, object.Name)
+	}
+	updatedReplicas, err := contract.ControlPlane().UpdatedReplicas().Get(controlPlane)
+	if err != nil && !errors.Is(err, contract.ErrFieldNotFound) {
+		return nil, errors.Wrapf(err, "failed to get status.updatedReplicas from %s %s", object.Kind, object.Name)
+	}
+	if replicas != nil && updatedReplicas != nil {
+		object.Replicas = int32(*replicas)
+		if *updatedReplicas < *replicas {
+			object.PendingReplicas = int32(*replicas - *updatedReplicas)
+			rollingOut = true
+		}
+	}
+
+	if !rollingOut {
+		return nil, nil
+	}
+	return object, nil
+}
+
+// machineDeploymentRollout returns the rollout of a MachineDeployment, if any.
+// A MachineDeployment is rolling out if it has Machines which are not up to date.
+func machineDeploymentRollout(md *clusterv1.MachineDeployment) *clusterv1.RolloutObject {
+	if !md.DeletionTimestamp.IsZero() || md.Status.UpdatedReplicas >= md.Status.Replicas {
+		return nil
+	}
+
+	object := &clusterv1.RolloutObject{
+		Kind:            "MachineDeployment",
+		Name:            md.Name,
+		Replicas:        md.Status.Replicas,
+		PendingReplicas: md.Status.Replicas - md.Status.UpdatedReplicas,
+	}
+	if md.Spec.Template.Spec.Version != nil {
+		object.Version = *md.Spec.Template.Spec.Version
+	}
+	return object
+}
+
+// machinePoolRollout returns the rollout of a MachinePool, if any.
+// MachinePools don't report which replicas are up to date, so a MachinePool is considered rolling out while
+// it has unavailable replicas without scaling up, e.g. while the infrastructure provider is replacing them.
+// NOTE: As a consequence, MachinePools provisioning their replicas for the first time are reported as rolling out.
+func machinePoolRollout(mp *expv1.MachinePool) *clusterv1.RolloutObject {
+	if !mp.DeletionTimestamp.IsZero() || !mp.Status.InfrastructureReady || mp.Status.UnavailableReplicas == 0 {
+		return nil
+	}
+	if mp.Spec.Replicas != nil && mp.Status.Replicas < *mp.Spec.Replicas {
+		return nil
+	}
+
+	object := &clusterv1.RolloutObject{
+		Kind:            "MachinePool",
+		Name:            mp.Name,
+		Replicas:        mp.Status.Replicas,
+		PendingReplicas: mp.Status.UnavailableReplicas,
+	}
+	if mp.Spec.Template.Spec.Version != nil {
+		object.Version = *mp.Spec.Template.Spec.Version
+	}
+	return object
+}
+
+// setRolloutStatus sets the RolloutCompleted condition and Cluster.Status.Rollout according to the objects rolling out.
+func setRolloutStatus(cluster *clusterv1.Cluster, objects []clusterv1.RolloutObject, now time.Time) {
+	if len(objects) == 0 {
+		cluster.Status.Rollout = nil
+		conditions.MarkTrue(cluster, clusterv1.RolloutCompletedCondition)
+		return
+	}
+
+	previous := cluster.Status.Rollout
+	rollout := &clusterv1.ClusterRolloutStatus{
+		StartTime: metav1.NewTime(now),
+		Objects:   objects,
+	}
+	if previous != nil {
+		rollout.StartTime = previous.StartTime
+	}
+	for _, object := range objects {
+		rollout.Replicas += object.Replicas
+		rollout.PendingReplicas += object.PendingReplicas
+	}
+
+	// Estimate the completion time assuming the pending Machines are rolled out at the same rate of the Machines
+	// rolled out since the start time.
+	// NOTE: The estimate is computed only when the replica counts change, so it is not changed on every
+	// reconcile, which would trigger a new reconcile due to the Cluster status changing.
+	switch {
+	case previous != nil && previous.Replicas == rollout.Replicas && previous.PendingReplicas == rollout.PendingReplicas:
+		rollout.EstimatedCompletionTime = previous.EstimatedCompletionTime
+	case rollout.PendingReplicas > 0 && rollout.Replicas > rollout.PendingReplicas && now.After(rollout.StartTime.Time):
+		elapsed := now.Sub(rollout.StartTime.Time)
+		remaining := time.Duration(float64(elapsed) * float64(rollout.PendingReplicas) / float64(rollout.Replicas-rollout.PendingReplicas))
+		estimatedCompletionTime := metav1.NewTime(now.Add(remaining).Truncate(time.Second))
+		rollout.EstimatedCompletionTime = &estimatedCompletionTime
+	}
+	cluster.Status.Rollout = rollout
+
+	names := make([]string, 0, maxRolloutObjectsInMessage)
+	for i, object := range objects {
+		if i == maxRolloutObjectsInMessage {
+			names = append(names, fmt.Sprintf("%d more", len(objects)-maxRolloutObjectsInMessage))
+			break
+		}
+		names = append(names, fmt.Sprintf("%s %s", object.Kind, object.Name))
+	}
+	conditions.MarkFalse(cluster, clusterv1.RolloutCompletedCondition, clusterv1.RolloutInProgressReason, clusterv1.ConditionSeverityInfo,
+		"Rolling out %s; %d of %d Machines pending", strings.Join(names, ", "), rollout.PendingReplicas, rollout.Replicas)
+}
+
+// machineDeploymentToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update its rollout status when one of its MachineDeployments gets updated.
+func (r *Reconciler) machineDeploymentToCluster(_ context.Context, o client.Object) []ctrl.Request {
+	md, ok := o.(*clusterv1.MachineDeployment)
+	if !ok {
+		panic(fmt.Sprintf("Expected a MachineDeployment but got a %T", o))
+	}
+	if md.Spec.ClusterName == "" {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: md.Namespace,
+			Name:      md.Spec.ClusterName,
+		},
+	}}
+}
+
+// machinePoolToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update its rollout status when one of its MachinePools gets updated.
+func (r *Reconciler) machinePoolToCluster(_ context.Context, o client.Object) []ctrl.Request {
+	mp, ok := o.(*expv1.MachinePool)
+	if !ok {
+		panic(fmt.Sprintf("Expected a MachinePool but got a %T", o))
+	}
+	if mp.Spec.ClusterName == "" {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: mp.Namespace,
+			Name:      mp.Spec.ClusterName,
+		},
+	}}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestClusterReconcileRollout(t *testing.T) {
+	controlPlaneStable := builder.ControlPlane("test-namespace", "cp").
+		WithVersion("v1.28.0").
+		WithStatusFields(map[string]interface{}{
+			"status.version":         "v1.28.0",
+			"status.replicas":        int64(3),
+			"status.updatedReplicas": int64(3),
+		}).
+		Build()
+	controlPlaneUpgrading := builder.ControlPlane("test-namespace", "cp").
+		WithVersion("v1.29.0").
+		WithStatusFields(map[string]interface{}{
+			"status.version":         "v1.28.0",
+			"status.replicas":        int64(4),
+			"status.updatedReplicas": int64(1),
+		}).
+		Build()
+
+	machineDeployment := func(name string, replicas, updatedReplicas int32) *clusterv1.MachineDeployment {
+		return builder.MachineDeployment("test-namespace", name).
+			WithClusterName("test-cluster").
+			WithLabels(map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}).
+			WithVersion("v1.29.0").
+			WithStatus(clusterv1.MachineDeploymentStatus{Replicas: replicas, UpdatedReplicas: updatedReplicas}).
+			Build()
+	}
+
+	tests := []struct {
+		name           string
+		objects        []client.Object
+		wantCompleted  bool
+		wantObjects    []clusterv1.RolloutObject
+		wantPending    int32
+		wantReplicas   int32
+		wantMessage    string
+		previousStatus *clusterv1.ClusterRolloutStatus
+	}{
+		{
+			name: "rollout completed",
+			objects: []client.Object{
+				controlPlaneStable,
+				machineDeployment("md-0", 3, 3),
+			},
+			previousStatus: &clusterv1.ClusterRolloutStatus{Replicas: 3, PendingReplicas: 1},
+			wantCompleted:  true,
+		},
+		{
+			name: "control plane and MachineDeployment rolling out",
+			objects: []client.Object{
+				controlPlaneUpgrading,
+				machineDeployment("md-1", 3, 1),
+				machineDeployment("md-0", 3, 3),
+			},
+			wantObjects: []clusterv1.RolloutObject{
+				{Kind: builder.GenericControlPlaneKind, Name: "cp", Version: "v1.29.0", Replicas: 4, PendingReplicas: 3},
+				{Kind: "MachineDeployment", Name: "md-1", Version: "v1.29.0", Replicas: 3, PendingReplicas: 2},
+			},
+			wantReplicas: 7,
+			wantPending:  5,
+			wantMessage:  "Rolling out GenericControlPlane cp, MachineDeployment md-1; 5 of 7 Machines pending",
+		},
+		{
+			name: "MachineDeployments of other Clusters are ignored",
+			objects: func() []client.Object {
+				md := machineDeployment("md-0", 3, 1)
+				md.Labels[clusterv1.ClusterNameLabel] = "other-cluster"
+				return []client.Object{controlPlaneStable, md}
+			}(),
+			wantCompleted: true,
+		},
+		{
+			name: "more objects than the ones listed in the message",
+			objects: []client.Object{
+				controlPlaneUpgrading,
+				machineDeployment("md-0", 1, 0),
+				machineDeployment("md-1", 1, 0),
+				machineDeployment("md-2", 1, 0),
+			},
+			wantObjects: []clusterv1.RolloutObject{
+				{Kind: builder.GenericControlPlaneKind, Name: "cp", Version: "v1.29.0", Replicas: 4, PendingReplicas: 3},
+				{Kind: "MachineDeployment", Name: "md-0", Version: "v1.29.0", Replicas: 1, PendingReplicas: 1},
+				{Kind: "MachineDeployment", Name: "md-1", Version: "v1.29.0", Replicas: 1, PendingReplicas: 1},
+				{Kind: "MachineDeployment", Name: "md-2", Version: "v1.29.0", Replicas: 1, PendingReplicas: 1},
+			},
+			wantReplicas: 7,
+			wantPending:  6,
+			wantMessage:  "Rolling out GenericControlPlane cp, MachineDeployment md-0, MachineDeployment md-1, 1 more; 6 of 7 Machines pending",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef: &corev1.ObjectReference{
+						APIVersion: builder.ControlPlaneGroupVersion.String(),
+						Kind:       builder.GenericControlPlaneKind,
+						Name:       "cp",
+					},
+				},
+				Status: clusterv1.ClusterStatus{
+					Rollout: tt.previousStatus,
+				},
+			}
+
+			c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).Build()
+			r := &Reconciler{
+				Client:                    c,
+				UnstructuredCachingClient: c,
+			}
+
+			_, err := r.reconcileRollout(ctx, cluster)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			if tt.wantCompleted {
+				g.Expect(conditions.IsTrue(cluster, clusterv1.RolloutCompletedCondition)).To(BeTrue())
+				g.Expect(cluster.Status.Rollout).To(BeNil())
+				return
+			}
+			g.Expect(conditions.IsFalse(cluster, clusterv1.RolloutCompletedCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(cluster, clusterv1.RolloutCompletedCondition)).To(Equal(clusterv1.RolloutInProgressReason))
+			g.Expect(conditions.GetMessage(cluster, clusterv1.RolloutCompletedCondition)).To(Equal(tt.wantMessage))
+			g.Expect(cluster.Status.Rollout).ToNot(BeNil())
+			g.Expect(cluster.Status.Rollout.Objects).To(Equal(tt.wantObjects))
+			g.Expect(cluster.Status.Rollout.Replicas).To(Equal(tt.wantReplicas))
+			g.Expect(cluster.Status.Rollout.PendingReplicas).To(Equal(tt.wantPending))
+		})
+	}
+}
+
+func TestSetRolloutStatus(t *testing.T) {
+	start := time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC)
+	objects := func(replicas, pendingReplicas int32) []clusterv1.RolloutObject {
+		return []clusterv1.RolloutObject{{Kind: "MachineDeployment", Name: "md", Replicas: replicas, PendingReplicas: pendingReplicas}}
+	}
+
+	g := NewWithT(t)
+	cluster := &clusterv1.Cluster{}
+
+	// The start time is set when the rollout is first observed, without an estimate until Machines are rolled out.
+	setRolloutStatus(cluster, objects(4, 4), start)
+	g.Expect(cluster.Status.Rollout.StartTime.Time).To(Equal(start))
+	g.Expect(cluster.Status.Rollout.EstimatedCompletionTime).To(BeNil())
+
+	// The estimate assumes the pending Machines are rolled out at the same rate of the Machines rolled out so far,
+	// i.e. 1 Machine every 10 minutes.
+	setRolloutStatus(cluster, objects(4, 3), start.Add(10*time.Minute))
+	g.Expect(cluster.Status.Rollout.StartTime.Time).To(Equal(start))
+	g.Expect(cluster.Status.Rollout.EstimatedCompletionTime).ToNot(BeNil())
+	g.Expect(cluster.Status.Rollout.EstimatedCompletionTime.Time).To(Equal(start.Add(40 * time.Minute)))
+
+	// The estimate doesn't change until the replica counts change.
+	setRolloutStatus(cluster, objects(4, 3), start.Add(15*time.Minute))
+	g.Expect(cluster.Status.Rollout.EstimatedCompletionTime.Time).To(Equal(start.Add(40 * time.Minute)))
+
+	setRolloutStatus(cluster, objects(4, 1), start.Add(30*time.Minute))
+	g.Expect(cluster.Status.Rollout.EstimatedCompletionTime.Time).To(Equal(start.Add(40 * time.Minute)))
+	g.Expect(conditions.IsFalse(cluster, clusterv1.RolloutCompletedCondition)).To(BeTrue())
+
+	// The rollout status is removed when the rollout completes, and the next rollout gets a new start time.
+	setRolloutStatus(cluster, nil, start.Add(40*time.Minute))
+	g.Expect(cluster.Status.Rollout).To(BeNil())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.RolloutCompletedCondition)).To(BeTrue())
+
+	setRolloutStatus(cluster, objects(4, 4), start.Add(time.Hour))
+	g.Expect(cluster.Status.Rollout.StartTime.Time).To(Equal(start.Add(time.Hour)))
+}
+
+func TestMachinePoolRollout(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      expv1.MachinePoolStatus
+		replicas    int32
+		wantPending int32
+		wantRollout bool
+	}{
+		{
+			name:     "all replicas available",
+			status:   expv1.MachinePoolStatus{InfrastructureReady: true, Replicas: 3, AvailableReplicas: 3},
+			replicas: 3,
+		},
+		{
+			name:     "scaling up",
+			status:   expv1.MachinePoolStatus{InfrastructureReady: true, Replicas: 2, AvailableReplicas: 2, UnavailableReplicas: 1},
+			replicas: 3,
+		},
+		{
+			name:     "infrastructure not ready",
+			status:   expv1.MachinePoolStatus{Replicas: 3, UnavailableReplicas: 3},
+			replicas: 3,
+		},
+		{
+			name:        "replacing replicas",
+			status:      expv1.MachinePoolStatus{InfrastructureReady: true, Replicas: 3, AvailableReplicas: 2, UnavailableReplicas: 1},
+			replicas:    3,
+			wantRollout: true,
+			wantPending: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mp := &expv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "mp"},
+				Spec:       expv1.MachinePoolSpec{Replicas: pointer.Int32(tt.replicas)},
+				Status:     tt.status,
+			}
+			object := machinePoolRollout(mp)
+			if !tt.wantRollout {
+				g.Expect(object).To(BeNil())
+				return
+			}
+			g.Expect(object).ToNot(BeNil())
+			g.Expect(object.Kind).To(Equal("MachinePool"))
+			g.Expect(object.PendingReplicas).To(Equal(tt.wantPending))
+		})
+	}
+}
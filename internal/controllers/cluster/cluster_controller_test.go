@@ -18,6 +18,7 @@ package cluster
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -37,6 +38,7 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/shard"
 )
 
 const (
@@ -402,6 +404,71 @@ func TestClusterReconciler_reconcileDelete(t *testing.T) {
 	}
 }
 
+func TestClusterReconciler_reconcilePaused(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster("test-ns", "test-cluster").Build()
+	cluster.Spec.Paused = true
+	fakeClient := fake.NewClientBuilder().WithObjects(cluster).WithStatusSubresource(&clusterv1.Cluster{}).Build()
+	r := &Reconciler{
+		Client:                    fakeClient,
+		UnstructuredCachingClient: fakeClient,
+		APIReader:                 fakeClient,
+	}
+
+	// The Paused condition acknowledges that the Cluster is paused.
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.PausedCondition)).To(BeTrue())
+	g.Expect(cluster.Finalizers).To(BeEmpty())
+}
+
+func TestClusterReconciler_reconcileShard(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster("test-ns", "test-cluster").Build()
+	cluster.Status.Shard = &clusterv1.ClusterShardStatus{Owner: "old"}
+
+	// Without sharding, no shard is published.
+	r := &Reconciler{}
+	r.reconcileShard(cluster)
+	g.Expect(cluster.Status.Shard).To(BeNil())
+
+	// With sharding, the replica reconciling the Cluster is published.
+	r.Sharder = &shard.Sharder{ID: "replica-1"}
+	r.reconcileShard(cluster)
+	g.Expect(cluster.Status.Shard).ToNot(BeNil())
+	g.Expect(cluster.Status.Shard.Owner).To(Equal("replica-1"))
+	g.Expect(cluster.Status.Shard.Key).To(Equal("test-ns/test-cluster"))
+
+	// The transition time changes only when the owner changes.
+	transition := metav1.NewTime(cluster.Status.Shard.LastTransitionTime.Add(-time.Hour))
+	cluster.Status.Shard.LastTransitionTime = transition
+	r.reconcileShard(cluster)
+	g.Expect(cluster.Status.Shard.LastTransitionTime).To(Equal(transition))
+}
+
+func TestClusterReconciler_notOwned(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster("test-ns", "test-cluster").Build()
+	fakeClient := fake.NewClientBuilder().WithObjects(cluster).WithStatusSubresource(&clusterv1.Cluster{}).Build()
+	r := &Reconciler{
+		Client:                    fakeClient,
+		UnstructuredCachingClient: fakeClient,
+		APIReader:                 fakeClient,
+		// The Sharder has not renewed its Lease yet, so it doesn't own any Cluster.
+		Sharder: &shard.Sharder{ID: "replica-1"},
+	}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+	g.Expect(cluster.Finalizers).To(BeEmpty())
+	g.Expect(cluster.Status.Shard).To(BeNil())
+}
+
 func TestClusterReconcilerNodeRef(t *testing.T) {
 	t.Run("machine to cluster", func(t *testing.T) {
 		cluster := &clusterv1.Cluster{
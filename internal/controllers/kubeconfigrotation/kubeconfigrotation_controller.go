@@ -0,0 +1,212 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfigrotation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/events"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
+
+// Reconciler rotates the client certificates of the kubeconfig Secrets generated by Cluster API ahead of their
+// expiry, so the controllers never lose access to the workload clusters.
+// Kubeconfig Secrets controlled by the control plane, e.g. by a KubeadmControlPlane, are rotated by the
+// control plane provider and are not rotated by this controller.
+type Reconciler struct {
+	Client client.Client
+
+	// RotationThreshold is the time before the expiry of the client certificates of a kubeconfig
+	// at which they are rotated. Defaults to certs.ClientCertificateRenewalDuration.
+	RotationThreshold time.Duration
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+
+	recorder record.EventRecorder
+}
+
+func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named("kubeconfigrotation").
+		For(&clusterv1.Cluster{}).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(kubeconfigSecretToCluster),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	r.recorder = mgr.GetEventRecorderFor("kubeconfig-rotation-controller")
+	return nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	cluster := &clusterv1.Cluster{}
+	if err := r.Client.Get(ctx, req.NamespacedName, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Return early if the Cluster is paused or deleted.
+	if annotations.IsPaused(cluster, cluster) || !cluster.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	configSecret, err := secret.Get(ctx, r.Client, util.ObjectKey(cluster), secret.Kubeconfig)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The kubeconfig is created by the control plane provider or by the Cluster controller.
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Wrap(err, "failed to get kubeconfig Secret")
+	}
+
+	// Only rotate the kubeconfig Secrets generated by Cluster API, user-provided Secrets are managed by the users.
+	if configSecret.Type != clusterv1.ClusterSecretType {
+		log.V(4).Info("Skipping rotation of user-provided kubeconfig Secret", "Secret", klog.KObj(configSecret))
+		return ctrl.Result{}, nil
+	}
+
+	// Only rotate the kubeconfig Secrets not managed by the control plane provider; if the control plane is the
+	// controller of the Secret, it is responsible for rotating the client certificates.
+	if controllerRef := metav1.GetControllerOf(configSecret); controllerRef != nil && !isClusterRef(controllerRef) {
+		log.V(4).Info("Skipping rotation of kubeconfig Secret managed by the control plane", "Secret", klog.KObj(configSecret), "controller", controllerRef.Kind)
+		return ctrl.Result{}, nil
+	}
+
+	expiry, err := kubeconfig.ClientCertExpiry(configSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if expiry.IsZero() {
+		// The kubeconfig does not use client certificates.
+		return ctrl.Result{}, nil
+	}
+
+	rotateAt := expiry.Add(-r.rotationThreshold())
+	if now := time.Now(); now.Before(rotateAt) {
+		return ctrl.Result{RequeueAfter: rotateAt.Sub(now)}, nil
+	}
+
+	log.Info(fmt.Sprintf("Rotating the client certificates of the kubeconfig, expiring at %s", expiry.Format(time.RFC3339)), "Secret", klog.KObj(configSecret))
+	if err := r.rotate(ctx, cluster, configSecret); err != nil {
+		r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "Secret", configSecret.Name), corev1.EventTypeWarning, clusterv1.EventKubeconfigRotationFailed, "Failed to rotate the client certificates of the kubeconfig: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	expiry, err = kubeconfig.ClientCertExpiry(configSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "Secret", configSecret.Name), corev1.EventTypeNormal, clusterv1.EventKubeconfigRotated, "Rotated the client certificates of the kubeconfig, now expiring at %s", expiry.Format(time.RFC3339))
+	return ctrl.Result{RequeueAfter: time.Until(expiry.Add(-r.rotationThreshold()))}, nil
+}
+
+// rotate replaces the client certificates of the kubeconfig of a Cluster with new certificates signed by the signer
+// of the Cluster, i.e. the CA in the Secret defined by the kubeconfig-signer annotation or the cluster CA.
+func (r *Reconciler) rotate(ctx context.Context, cluster *clusterv1.Cluster, configSecret *corev1.Secret) error {
+	signerKey := client.ObjectKey{Namespace: cluster.Namespace, Name: secret.Name(cluster.Name, secret.ClusterCA)}
+	if name, ok := cluster.GetAnnotations()[clusterv1.KubeconfigSignerAnnotation]; ok {
+		signerKey.Name = name
+	}
+
+	signer := &corev1.Secret{}
+	if err := r.Client.Get(ctx, signerKey, signer); err != nil {
+		return errors.Wrapf(err, "failed to get signer Secret %s", signerKey)
+	}
+	caCert, err := certs.DecodeCertPEM(signer.Data[secret.TLSCrtDataName])
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode the certificate of signer Secret %s", signerKey)
+	}
+	if caCert == nil {
+		return errors.Errorf("signer Secret %s does not contain a certificate", signerKey)
+	}
+	caKey, err := certs.DecodePrivateKeyPEM(signer.Data[secret.TLSKeyDataName])
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode the key of signer Secret %s", signerKey)
+	}
+	if caKey == nil {
+		return errors.Errorf("signer Secret %s does not contain a key", signerKey)
+	}
+
+	original := configSecret.DeepCopy()
+	if err := kubeconfig.RotateClientCerts(configSecret, caCert, caKey); err != nil {
+		return err
+	}
+	if err := r.Client.Patch(ctx, configSecret, client.MergeFrom(original)); err != nil {
+		return errors.Wrap(err, "failed to patch kubeconfig Secret")
+	}
+	return nil
+}
+
+func (r *Reconciler) rotationThreshold() time.Duration {
+	if r.RotationThreshold > 0 {
+		return r.RotationThreshold
+	}
+	return certs.ClientCertificateRenewalDuration
+}
+
+// isClusterRef returns true if the owner reference refers to a Cluster.
+func isClusterRef(ref *metav1.OwnerReference) bool {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false
+	}
+	return gv.Group == clusterv1.GroupVersion.Group && ref.Kind == "Cluster"
+}
+
+// kubeconfigSecretToCluster maps kubeconfig Secrets to the Cluster they belong to.
+func kubeconfigSecretToCluster(_ context.Context, o client.Object) []ctrl.Request {
+	clusterName, purpose, err := secret.ParseSecretName(o.GetName())
+	if err != nil || purpose != secret.Kubeconfig {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: o.GetNamespace(), Name: clusterName}}}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfigrotation
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+var ctx = ctrl.SetupSignalHandler()
+
+func TestReconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+	caCert, err := newTestCACert(caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	signerKey, err := certs.NewPrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+	signerCert, err := newTestCACert(signerKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	newCluster := func(annotations map[string]string) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: metav1.NamespaceDefault, Annotations: annotations},
+		}
+	}
+	newCASecret := func(name string, cert *x509.Certificate, key *rsa.PrivateKey) *corev1.Secret {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+			Data: map[string][]byte{
+				secret.TLSCrtDataName: certs.EncodeCertPEM(cert),
+			},
+		}
+		if key != nil {
+			s.Data[secret.TLSKeyDataName] = certs.EncodePrivateKeyPEM(key)
+		}
+		return s
+	}
+	newKubeconfigSecret := func(secretType corev1.SecretType, owner ...metav1.OwnerReference) *corev1.Secret {
+		config, err := kubeconfig.New("test", "https://127.0.0.1:6443", caCert, caKey)
+		g.Expect(err).ToNot(HaveOccurred())
+		out, err := clientcmd.Write(*config)
+		g.Expect(err).ToNot(HaveOccurred())
+		s := kubeconfig.GenerateSecretWithOwner(client.ObjectKey{Name: "test", Namespace: metav1.NamespaceDefault}, out, metav1.OwnerReference{})
+		s.Type = secretType
+		s.OwnerReferences = owner
+		return s
+	}
+
+	tests := []struct {
+		name              string
+		objects           []client.Object
+		rotationThreshold time.Duration
+		expectErr         bool
+		expectRotated     bool
+		expectSigner      *x509.Certificate
+		expectEvent       string
+	}{
+		{
+			name:    "should not rotate certificates not close to expiry",
+			objects: []client.Object{newCluster(nil), newCASecret("test-ca", caCert, caKey), newKubeconfigSecret(clusterv1.ClusterSecretType)},
+		},
+		{
+			name:              "should rotate certificates close to expiry with the cluster CA",
+			objects:           []client.Object{newCluster(nil), newCASecret("test-ca", caCert, caKey), newKubeconfigSecret(clusterv1.ClusterSecretType)},
+			rotationThreshold: 2 * certs.DefaultCertDuration,
+			expectRotated:     true,
+			expectSigner:      caCert,
+			expectEvent:       clusterv1.EventKubeconfigRotated,
+		},
+		{
+			name: "should rotate certificates close to expiry with the configured signer",
+			objects: []client.Object{
+				newCluster(map[string]string{clusterv1.KubeconfigSignerAnnotation: "signer"}),
+				newCASecret("test-ca", caCert, caKey),
+				newCASecret("signer", signerCert, signerKey),
+				newKubeconfigSecret(clusterv1.ClusterSecretType),
+			},
+			rotationThreshold: 2 * certs.DefaultCertDuration,
+			expectRotated:     true,
+			expectSigner:      signerCert,
+			expectEvent:       clusterv1.EventKubeconfigRotated,
+		},
+		{
+			name:              "should not rotate user-provided kubeconfig Secrets",
+			objects:           []client.Object{newCluster(nil), newCASecret("test-ca", caCert, caKey), newKubeconfigSecret(corev1.SecretTypeOpaque)},
+			rotationThreshold: 2 * certs.DefaultCertDuration,
+		},
+		{
+			name: "should rotate kubeconfig Secrets controlled by the Cluster",
+			objects: []client.Object{
+				newCluster(nil),
+				newCASecret("test-ca", caCert, caKey),
+				newKubeconfigSecret(clusterv1.ClusterSecretType, *metav1.NewControllerRef(newCluster(nil), clusterv1.GroupVersion.WithKind("Cluster"))),
+			},
+			rotationThreshold: 2 * certs.DefaultCertDuration,
+			expectRotated:     true,
+			expectSigner:      caCert,
+			expectEvent:       clusterv1.EventKubeconfigRotated,
+		},
+		{
+			name: "should not rotate kubeconfig Secrets controlled by the control plane",
+			objects: []client.Object{
+				newCluster(nil),
+				newCASecret("test-ca", caCert, caKey),
+				newKubeconfigSecret(clusterv1.ClusterSecretType, metav1.OwnerReference{
+					APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+					Kind:       "KubeadmControlPlane",
+					Name:       "test",
+					Controller: pointer.Bool(true),
+				}),
+			},
+			rotationThreshold: 2 * certs.DefaultCertDuration,
+		},
+		{
+			name:              "should fail when the signer has no key",
+			objects:           []client.Object{newCluster(nil), newCASecret("test-ca", caCert, nil), newKubeconfigSecret(clusterv1.ClusterSecretType)},
+			rotationThreshold: 2 * certs.DefaultCertDuration,
+			expectErr:         true,
+			expectEvent:       clusterv1.EventKubeconfigRotationFailed,
+		},
+		{
+			name:              "should not fail when the kubeconfig Secret does not exist",
+			objects:           []client.Object{newCluster(nil), newCASecret("test-ca", caCert, caKey)},
+			rotationThreshold: 2 * certs.DefaultCertDuration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				Client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
+				RotationThreshold: tt.rotationThreshold,
+				recorder:          recorder,
+			}
+
+			var before []byte
+			configSecret := &corev1.Secret{}
+			configKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: secret.Name("test", secret.Kubeconfig)}
+			if err := r.Client.Get(ctx, configKey, configSecret); err == nil {
+				before = configSecret.Data[secret.KubeconfigDataName]
+			}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "test"}})
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			if tt.expectEvent != "" {
+				g.Expect(recorder.Events).To(Receive(ContainSubstring(tt.expectEvent)))
+			} else {
+				g.Expect(recorder.Events).ToNot(Receive())
+			}
+
+			if before == nil {
+				return
+			}
+			g.Expect(r.Client.Get(ctx, configKey, configSecret)).To(Succeed())
+			if !tt.expectRotated {
+				g.Expect(configSecret.Data[secret.KubeconfigDataName]).To(Equal(before))
+				return
+			}
+			g.Expect(configSecret.Data[secret.KubeconfigDataName]).ToNot(Equal(before))
+			config, err := clientcmd.Load(configSecret.Data[secret.KubeconfigDataName])
+			g.Expect(err).ToNot(HaveOccurred())
+			cert, err := certs.DecodeCertPEM(config.AuthInfos["test-admin"].ClientCertificateData)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cert.CheckSignatureFrom(tt.expectSigner)).To(Succeed())
+			g.Expect(config.Clusters["test"].CertificateAuthorityData).To(Equal(certs.EncodeCertPEM(caCert)))
+		})
+	}
+}
+
+func TestKubeconfigSecretToCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(kubeconfigSecretToCluster(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-kubeconfig", Namespace: metav1.NamespaceDefault},
+	})).To(ConsistOf(ctrl.Request{NamespacedName: client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "test"}}))
+	g.Expect(kubeconfigSecretToCluster(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ca", Namespace: metav1.NamespaceDefault},
+	})).To(BeEmpty())
+}
+
+func newTestCACert(key *rsa.PrivateKey) (*x509.Certificate, error) {
+	now := time.Now().UTC()
+	tmpl := x509.Certificate{
+		SerialNumber:          new(big.Int).SetInt64(0),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.Add(10 * certs.DefaultCertDuration),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	b, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(b)
+}
@@ -47,14 +47,18 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	clog "sigs.k8s.io/cluster-api/util/log"
+	"sigs.k8s.io/cluster-api/util/metrics"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/shard"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 var (
@@ -82,6 +86,9 @@ type Reconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// Sharder, if set, limits the reconciliation to the Machines of the Clusters assigned to this replica of the manager.
+	Sharder *shard.Sharder
+
 	// NodeDrainClientTimeout timeout of the client used for draining nodes.
 	NodeDrainClientTimeout time.Duration
 
@@ -122,11 +129,18 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			)).
-		Build(r)
+		Build(tracing.Reconciler("machine", "Machine", metrics.Reconciler("machine", "Machine", r)))
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
+	if r.Sharder != nil {
+		// Reconcile the Machines of the Clusters assigned to this replica, e.g. after another replica left.
+		if err := c.Watch(r.Sharder.Source(), handler.EnqueueRequestsFromMapFunc(clusterToMachines)); err != nil {
+			return errors.Wrap(err, "failed adding Watch for the Clusters assigned to the shard")
+		}
+	}
+
 	r.controller = c
 	r.recorder = mgr.GetEventRecorderFor("machine-controller")
 	r.externalTracker = external.ObjectTracker{
@@ -159,6 +173,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	}
 
 	log = log.WithValues("Cluster", klog.KRef(m.ObjectMeta.Namespace, m.Spec.ClusterName))
+	tracing.SetCluster(ctx, m.Spec.ClusterName)
+	metrics.SetCluster(ctx, m.Spec.ClusterName)
 	ctx = ctrl.LoggerInto(ctx, log)
 
 	cluster, err := util.GetClusterByName(ctx, r.Client, m.ObjectMeta.Namespace, m.Spec.ClusterName)
@@ -167,6 +183,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 			m.Spec.ClusterName, m.Name, m.Namespace)
 	}
 
+	// Return early if the Cluster is reconciled by another replica.
+	if !r.Sharder.Owns(cluster) {
+		log.V(4).Info("Reconciliation is skipped, the Cluster is assigned to another replica")
+		return ctrl.Result{}, nil
+	}
+
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, m) {
 		log.Info("Reconciliation is paused for this object")
@@ -261,6 +283,7 @@ func patchMachine(ctx context.Context, patchHelper *patch.Helper, machine *clust
 			clusterv1.DrainingSucceededCondition,
 			clusterv1.MachineHealthCheckSucceededCondition,
 			clusterv1.MachineOwnerRemediatedCondition,
+			clusterv1.APIServerLoadBalancerReadyCondition,
 		}},
 	)
 
@@ -347,6 +370,9 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 		// pre-drain.delete lifecycle hook
 		// Return early without error, will requeue if/when the hook owner removes the annotation.
 		if annotations.HasWithPrefix(clusterv1.PreDrainDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations) {
+			if conditions.GetReason(m, clusterv1.PreDrainDeleteHookSucceededCondition) != clusterv1.WaitingExternalHookReason {
+				r.recorder.AnnotatedEventf(m, events.Annotations(cluster.Name, "", ""), corev1.EventTypeNormal, clusterv1.EventHookBlocking, "Waiting for pre-drain hooks to be removed before draining Machine's node")
+			}
 			conditions.MarkFalse(m, clusterv1.PreDrainDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "")
 			return ctrl.Result{}, nil
 		}
@@ -365,6 +391,7 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 			// This `if` condition prevents the transition time to be changed more than once.
 			if conditions.Get(m, clusterv1.DrainingSucceededCondition) == nil {
 				conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo, "Draining the node before deletion")
+				r.recorder.AnnotatedEventf(m, events.Annotations(cluster.Name, "Node", m.Status.NodeRef.Name), corev1.EventTypeNormal, clusterv1.EventDrainStarted, "Draining Machine's node %q", m.Status.NodeRef.Name)
 			}
 
 			if err := patchMachine(ctx, patchHelper, m); err != nil {
@@ -374,13 +401,15 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 			if result, err := r.drainNode(ctx, cluster, m.Status.NodeRef.Name); !result.IsZero() || err != nil {
 				if err != nil {
 					conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
-					r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedDrainNode", "error draining Machine's node %q: %v", m.Status.NodeRef.Name, err)
+					r.recorder.AnnotatedEventf(m, events.Annotations(cluster.Name, "Node", m.Status.NodeRef.Name), corev1.EventTypeWarning, clusterv1.EventFailedDrainNode, "error draining Machine's node %q: %v", m.Status.NodeRef.Name, err)
+				} else if result.RequeueAfter > 0 {
+					r.recorder.AnnotatedEventf(m, events.Annotations(cluster.Name, "Node", m.Status.NodeRef.Name), corev1.EventTypeNormal, clusterv1.EventDrainInProgress, "Draining Machine's node %q not completed yet, retrying in %s", m.Status.NodeRef.Name, result.RequeueAfter)
 				}
 				return result, err
 			}
 
 			conditions.MarkTrue(m, clusterv1.DrainingSucceededCondition)
-			r.recorder.Eventf(m, corev1.EventTypeNormal, "SuccessfulDrainNode", "success draining Machine's node %q", m.Status.NodeRef.Name)
+			r.recorder.AnnotatedEventf(m, events.Annotations(cluster.Name, "Node", m.Status.NodeRef.Name), corev1.EventTypeNormal, clusterv1.EventSuccessfulDrainNode, "success draining Machine's node %q", m.Status.NodeRef.Name)
 		}
 
 		// After node draining is completed, and if isNodeVolumeDetachingAllowed returns True, make sure all
@@ -395,20 +424,23 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 
 			if ok, err := r.shouldWaitForNodeVolumes(ctx, cluster, m.Status.NodeRef.Name); ok || err != nil {
 				if err != nil {
-					r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedWaitForVolumeDetach", "error waiting for node volumes detaching, Machine's node %q: %v", m.Status.NodeRef.Name, err)
+					r.recorder.AnnotatedEventf(m, events.Annotations(cluster.Name, "Node", m.Status.NodeRef.Name), corev1.EventTypeWarning, clusterv1.EventFailedWaitForVolumeDetach, "error waiting for node volumes detaching, Machine's node %q: %v", m.Status.NodeRef.Name, err)
 					return ctrl.Result{}, err
 				}
 				log.Info("Waiting for node volumes to be detached", "Node", klog.KRef("", m.Status.NodeRef.Name))
 				return ctrl.Result{}, nil
 			}
 			conditions.MarkTrue(m, clusterv1.VolumeDetachSucceededCondition)
-			r.recorder.Eventf(m, corev1.EventTypeNormal, "NodeVolumesDetached", "success waiting for node volumes detaching Machine's node %q", m.Status.NodeRef.Name)
+			r.recorder.AnnotatedEventf(m, events.Annotations(cluster.Name, "Node", m.Status.NodeRef.Name), corev1.EventTypeNormal, clusterv1.EventNodeVolumesDetached, "success waiting for node volumes detaching Machine's node %q", m.Status.NodeRef.Name)
 		}
 	}
 
 	// pre-term.delete lifecycle hook
 	// Return early without error, will requeue if/when the hook owner removes the annotation.
 	if annotations.HasWithPrefix(clusterv1.PreTerminateDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations) {
+		if conditions.GetReason(m, clusterv1.PreTerminateDeleteHookSucceededCondition) != clusterv1.WaitingExternalHookReason {
+			r.recorder.AnnotatedEventf(m, events.Annotations(cluster.Name, "", ""), corev1.EventTypeNormal, clusterv1.EventHookBlocking, "Waiting for pre-terminate hooks to be removed before deleting Machine's infrastructure")
+		}
 		conditions.MarkFalse(m, clusterv1.PreTerminateDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "")
 		return ctrl.Result{}, nil
 	}
@@ -460,7 +492,7 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 		if waitErr != nil {
 			log.Error(deleteNodeErr, "Timed out deleting node", "Node", klog.KRef("", m.Status.NodeRef.Name))
 			conditions.MarkFalse(m, clusterv1.MachineNodeHealthyCondition, clusterv1.DeletionFailedReason, clusterv1.ConditionSeverityWarning, "")
-			r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedDeleteNode", "error deleting Machine's node: %v", deleteNodeErr)
+			r.recorder.AnnotatedEventf(m, events.Annotations(cluster.Name, "Node", m.Status.NodeRef.Name), corev1.EventTypeWarning, clusterv1.EventFailedDeleteNode, "error deleting Machine's node: %v", deleteNodeErr)
 
 			// If the node deletion timeout is not expired yet, requeue the Machine for reconciliation.
 			if m.Spec.NodeDeletionTimeout == nil || m.Spec.NodeDeletionTimeout.Nanoseconds() == 0 || m.DeletionTimestamp.Add(m.Spec.NodeDeletionTimeout.Duration).After(time.Now()) {
@@ -31,6 +31,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/internal/util/taints"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -80,7 +81,7 @@ func (r *Reconciler) reconcileNode(ctx context.Context, s *scope) (ctrl.Result,
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "Failed to retrieve Node by ProviderID")
-		r.recorder.Event(machine, corev1.EventTypeWarning, "Failed to retrieve Node by ProviderID", err.Error())
+		r.recorder.AnnotatedEventf(machine, events.Annotations(cluster.Name, "", ""), corev1.EventTypeWarning, clusterv1.EventFailedSetNodeRef, "Failed to retrieve Node by ProviderID: %v", err)
 		return ctrl.Result{}, err
 	}
 
@@ -93,7 +94,7 @@ func (r *Reconciler) reconcileNode(ctx context.Context, s *scope) (ctrl.Result,
 			UID:        node.UID,
 		}
 		log.Info("Infrastructure provider reporting spec.providerID, Kubernetes node is now available", machine.Spec.InfrastructureRef.Kind, klog.KRef(machine.Spec.InfrastructureRef.Namespace, machine.Spec.InfrastructureRef.Name), "providerID", *machine.Spec.ProviderID, "node", klog.KRef("", machine.Status.NodeRef.Name))
-		r.recorder.Event(machine, corev1.EventTypeNormal, "SuccessfulSetNodeRef", machine.Status.NodeRef.Name)
+		r.recorder.AnnotatedEventf(machine, events.Annotations(cluster.Name, "Node", node.Name), corev1.EventTypeNormal, clusterv1.EventSuccessfulSetNodeRef, "%s", node.Name)
 	}
 
 	// Set the NodeSystemInfo.
@@ -140,7 +141,7 @@ func (r *Reconciler) reconcileNode(ctx context.Context, s *scope) (ctrl.Result,
 		// If the interruptible label is added to the node then record the event.
 		// Nb. Only record the event if the node previously did not have the label to avoid recording
 		// the event during every reconcile.
-		r.recorder.Event(machine, corev1.EventTypeNormal, "SuccessfulSetInterruptibleNodeLabel", node.Name)
+		r.recorder.AnnotatedEventf(machine, events.Annotations(cluster.Name, "Node", node.Name), corev1.EventTypeNormal, clusterv1.EventSuccessfulSetInterruptibleNodeLabel, "%s", node.Name)
 	}
 
 	// Do the remaining node health checks, then set the node health to true if all checks pass.
@@ -287,6 +287,14 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, s *scope) (ctr
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
 	)
 
+	// Copy the API server load balancer readiness reported by the infrastructure provider, if any;
+	// the condition is removed when the infrastructure provider stops reporting it.
+	if lbReady := conditions.Get(conditions.UnstructuredGetter(infraConfig), clusterv1.APIServerLoadBalancerReadyCondition); lbReady != nil {
+		conditions.Set(m, lbReady)
+	} else {
+		conditions.Delete(m, clusterv1.APIServerLoadBalancerReadyCondition)
+	}
+
 	// If the infrastructure provider is not ready, return early.
 	if !ready {
 		log.Info("Waiting for infrastructure provider to create machine infrastructure and report status.ready", infraConfig.GetKind(), klog.KObj(infraConfig))
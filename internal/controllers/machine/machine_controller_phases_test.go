@@ -1066,6 +1066,69 @@ func TestReconcileInfrastructure(t *testing.T) {
 				g.Expect(m.Status.GetTypedPhase()).To(Equal(clusterv1.MachinePhaseFailed))
 			},
 		},
+		{
+			name: "new machine, infrastructure config reporting the API server load balancer readiness",
+			infraConfig: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerID": "test://id-1",
+				},
+				"status": map[string]interface{}{
+					"ready": true,
+					"conditions": []interface{}{
+						map[string]interface{}{
+							"type":               string(clusterv1.APIServerLoadBalancerReadyCondition),
+							"status":             string(corev1.ConditionFalse),
+							"severity":           string(clusterv1.ConditionSeverityInfo),
+							"reason":             clusterv1.WaitingForLoadBalancerRegistrationReason,
+							"lastTransitionTime": "2023-01-01T00:00:00Z",
+						},
+					},
+				},
+			},
+			expectResult:  ctrl.Result{},
+			expectError:   false,
+			expectChanged: true,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.InfrastructureReady).To(BeTrue())
+				g.Expect(conditions.IsFalse(m, clusterv1.APIServerLoadBalancerReadyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(m, clusterv1.APIServerLoadBalancerReadyCondition)).To(Equal(clusterv1.WaitingForLoadBalancerRegistrationReason))
+			},
+		},
+		{
+			name: "infrastructure config no longer reporting the API server load balancer readiness",
+			machine: func() *clusterv1.Machine {
+				m := defaultMachine.DeepCopy()
+				conditions.MarkFalse(m, clusterv1.APIServerLoadBalancerReadyCondition, clusterv1.WaitingForLoadBalancerRegistrationReason, clusterv1.ConditionSeverityInfo, "")
+				return m
+			}(),
+			infraConfig: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerID": "test://id-1",
+				},
+				"status": map[string]interface{}{
+					"ready": true,
+				},
+			},
+			expectResult:  ctrl.Result{},
+			expectError:   false,
+			expectChanged: true,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.InfrastructureReady).To(BeTrue())
+				g.Expect(conditions.Has(m, clusterv1.APIServerLoadBalancerReadyCondition)).To(BeFalse())
+			},
+		},
 		{
 			name: "infrastructure ref is paused",
 			infraConfig: map[string]interface{}{
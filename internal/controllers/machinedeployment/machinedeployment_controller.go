@@ -37,13 +37,17 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/cluster-api/util/metrics"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/shard"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 var (
@@ -70,6 +74,9 @@ type Reconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// Sharder, if set, limits the reconciliation to the MachineDeployments of the Clusters assigned to this replica of the manager.
+	Sharder *shard.Sharder
+
 	recorder record.EventRecorder
 	ssaCache ssa.Cache
 }
@@ -80,7 +87,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		return err
 	}
 
-	err = ctrl.NewControllerManagedBy(mgr).
+	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.MachineDeployment{}).
 		Owns(&clusterv1.MachineSet{}).
 		Watches(
@@ -99,11 +106,18 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			),
-		).Complete(r)
+		).Build(tracing.Reconciler("machinedeployment", "MachineDeployment", metrics.Reconciler("machinedeployment", "MachineDeployment", r)))
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
+	if r.Sharder != nil {
+		// Reconcile the MachineDeployments of the Clusters assigned to this replica, e.g. after another replica left.
+		if err := c.Watch(r.Sharder.Source(), handler.EnqueueRequestsFromMapFunc(clusterToMachineDeployments)); err != nil {
+			return errors.Wrap(err, "failed adding Watch for the Clusters assigned to the shard")
+		}
+	}
+
 	r.recorder = mgr.GetEventRecorderFor("machinedeployment-controller")
 	r.ssaCache = ssa.NewCache()
 	return nil
@@ -125,6 +139,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	}
 
 	log = log.WithValues("Cluster", klog.KRef(deployment.Namespace, deployment.Spec.ClusterName))
+	tracing.SetCluster(ctx, deployment.Spec.ClusterName)
+	metrics.SetCluster(ctx, deployment.Spec.ClusterName)
 	ctx = ctrl.LoggerInto(ctx, log)
 
 	cluster, err := util.GetClusterByName(ctx, r.Client, deployment.Namespace, deployment.Spec.ClusterName)
@@ -132,6 +148,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	// Return early if the Cluster is reconciled by another replica.
+	if !r.Sharder.Owns(cluster) {
+		log.V(4).Info("Reconciliation is skipped, the Cluster is assigned to another replica")
+		return ctrl.Result{}, nil
+	}
+
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, deployment) {
 		log.Info("Reconciliation is paused for this object")
@@ -165,7 +187,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	err = r.reconcile(ctx, cluster, deployment)
 	if err != nil {
 		log.Error(err, "Failed to reconcile MachineDeployment")
-		r.recorder.Eventf(deployment, corev1.EventTypeWarning, "ReconcileError", "%v", err)
+		r.recorder.AnnotatedEventf(deployment, events.Annotations(deployment.Spec.ClusterName, "", ""), corev1.EventTypeWarning, clusterv1.EventReconcileError, "%v", err)
 	}
 	return ctrl.Result{}, err
 }
@@ -321,11 +343,11 @@ func (r *Reconciler) getMachineSetsForDeployment(ctx context.Context, md *cluste
 		if metav1.GetControllerOf(ms) == nil {
 			if err := r.adoptOrphan(ctx, md, ms); err != nil {
 				log.Error(err, "Failed to adopt MachineSet into MachineDeployment")
-				r.recorder.Eventf(md, corev1.EventTypeWarning, "FailedAdopt", "Failed to adopt MachineSet %q: %v", ms.Name, err)
+				r.recorder.AnnotatedEventf(md, events.Annotations(md.Spec.ClusterName, "MachineSet", ms.Name), corev1.EventTypeWarning, clusterv1.EventFailedAdopt, "Failed to adopt MachineSet %q: %v", ms.Name, err)
 				continue
 			}
 			log.Info("Adopted MachineSet into MachineDeployment")
-			r.recorder.Eventf(md, corev1.EventTypeNormal, "SuccessfulAdopt", "Adopted MachineSet %q", ms.Name)
+			r.recorder.AnnotatedEventf(md, events.Annotations(md.Spec.ClusterName, "MachineSet", ms.Name), corev1.EventTypeNormal, clusterv1.EventSuccessfulAdopt, "Adopted MachineSet %q", ms.Name)
 		}
 
 		if !metav1.IsControlledBy(ms, md) {
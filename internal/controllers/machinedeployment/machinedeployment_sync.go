@@ -40,7 +40,9 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/internal/util/hash"
+	"sigs.k8s.io/cluster-api/internal/util/rollout"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -158,7 +160,7 @@ func (r *Reconciler) updateMachineSet(ctx context.Context, deployment *clusterv1
 	// Update the MachineSet to propagate in-place mutable fields from the MachineDeployment.
 	err = ssa.Patch(ctx, r.Client, machineDeploymentManagerName, updatedMS, ssa.WithCachingProxy{Cache: r.ssaCache, Original: ms})
 	if err != nil {
-		r.recorder.Eventf(deployment, corev1.EventTypeWarning, "FailedUpdate", "Failed to update MachineSet %s: %v", klog.KObj(updatedMS), err)
+		r.recorder.AnnotatedEventf(deployment, events.Annotations(deployment.Spec.ClusterName, "MachineSet", updatedMS.Name), corev1.EventTypeWarning, clusterv1.EventFailedUpdate, "Failed to update MachineSet %s: %v", klog.KObj(updatedMS), err)
 		return nil, errors.Wrapf(err, "failed to update MachineSet %s", klog.KObj(updatedMS))
 	}
 
@@ -179,11 +181,22 @@ func (r *Reconciler) createMachineSetAndWait(ctx context.Context, deployment *cl
 
 	// Create the MachineSet.
 	if err := ssa.Patch(ctx, r.Client, machineDeploymentManagerName, newMS); err != nil {
-		r.recorder.Eventf(deployment, corev1.EventTypeWarning, "FailedCreate", "Failed to create MachineSet %s: %v", klog.KObj(newMS), err)
+		r.recorder.AnnotatedEventf(deployment, events.Annotations(deployment.Spec.ClusterName, "MachineSet", newMS.Name), corev1.EventTypeWarning, clusterv1.EventFailedCreate, "Failed to create MachineSet %s: %v", klog.KObj(newMS), err)
 		return nil, errors.Wrapf(err, "failed to create new MachineSet %s", klog.KObj(newMS))
 	}
 	log.V(4).Info("Created new MachineSet", "MachineSet", klog.KObj(newMS))
-	r.recorder.Eventf(deployment, corev1.EventTypeNormal, "SuccessfulCreate", "Created MachineSet %s", klog.KObj(newMS))
+	r.recorder.AnnotatedEventf(deployment, events.Annotations(deployment.Spec.ClusterName, "MachineSet", newMS.Name), corev1.EventTypeNormal, clusterv1.EventSuccessfulCreate, "Created MachineSet %s", klog.KObj(newMS))
+
+	// A new MachineSet replacing MachineSets which still have Machines starts a rollout.
+	if mdutil.GetActualReplicaCountForMachineSets(oldMSs) > 0 || mdutil.GetReplicaCountForMachineSets(oldMSs) > 0 {
+		r.recorder.AnnotatedEventf(deployment, events.Annotations(deployment.Spec.ClusterName, "MachineSet", newMS.Name), corev1.EventTypeNormal, clusterv1.EventRolloutStarted, "Rolling out Machines to MachineSet %s", klog.KObj(newMS))
+
+		trigger, err := computeRolloutTrigger(deployment, oldMSs, time.Now())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to record the rollout to MachineSet %s", klog.KObj(newMS))
+		}
+		deployment.Status.RolloutHistory = rollout.RecordTrigger(deployment.Status.RolloutHistory, trigger)
+	}
 
 	// Keep trying to get the MachineSet. This will force the cache to update and prevent any future reconciliation of
 	// the MachineDeployment to reconcile with an outdated list of MachineSets which could lead to unwanted creation of
@@ -205,6 +218,64 @@ func (r *Reconciler) createMachineSetAndWait(ctx context.Context, deployment *cl
 	return newMS, nil
 }
 
+// computeRolloutTrigger computes the record of the change triggering a rollout to a new MachineSet, by comparing
+// the machine template of the MachineDeployment with the one of the latest old MachineSet.
+// The triggering object is the template referenced by the MachineDeployment if the reference changed, e.g. to rotate
+// the infrastructure machine template, otherwise the MachineDeployment itself.
+func computeRolloutTrigger(deployment *clusterv1.MachineDeployment, oldMSs []*clusterv1.MachineSet, now time.Time) (clusterv1.RolloutTrigger, error) {
+	newTemplate := mdutil.MachineTemplateDeepCopyRolloutFields(&deployment.Spec.Template)
+	newHash, err := hash.Compute(newTemplate)
+	if err != nil {
+		return clusterv1.RolloutTrigger{}, errors.Wrap(err, "failed to compute machine template hash")
+	}
+	trigger := clusterv1.RolloutTrigger{
+		Time:    metav1.NewTime(now),
+		Kind:    "MachineDeployment",
+		Name:    deployment.Name,
+		NewHash: fmt.Sprintf("%d", newHash),
+	}
+
+	var latestMS *clusterv1.MachineSet
+	var latestRevision int64
+	for _, ms := range oldMSs {
+		revision, err := mdutil.Revision(ms)
+		if err != nil {
+			continue
+		}
+		if latestMS == nil || revision > latestRevision {
+			latestMS, latestRevision = ms, revision
+		}
+	}
+	if latestMS != nil {
+		oldTemplate := mdutil.MachineTemplateDeepCopyRolloutFields(&latestMS.Spec.Template)
+		oldHash, err := hash.Compute(oldTemplate)
+		if err != nil {
+			return clusterv1.RolloutTrigger{}, errors.Wrap(err, "failed to compute machine template hash")
+		}
+		trigger.OldHash = fmt.Sprintf("%d", oldHash)
+
+		trigger.FieldPaths, err = rollout.ChangedFieldPaths("spec.template", oldTemplate, newTemplate)
+		if err != nil {
+			return clusterv1.RolloutTrigger{}, err
+		}
+	}
+	// A new MachineSet with the same machine template is created when rolloutAfter expires.
+	if len(trigger.FieldPaths) == 0 && deployment.Spec.RolloutAfter != nil {
+		trigger.FieldPaths = []string{"spec.rolloutAfter"}
+	}
+	trigger.Manager = rollout.Manager(deployment, trigger.FieldPaths)
+
+	switch {
+	case rollout.HasFieldPath(trigger.FieldPaths, "spec.template.spec.infrastructureRef"):
+		trigger.Kind = deployment.Spec.Template.Spec.InfrastructureRef.Kind
+		trigger.Name = deployment.Spec.Template.Spec.InfrastructureRef.Name
+	case rollout.HasFieldPath(trigger.FieldPaths, "spec.template.spec.bootstrap.configRef") && deployment.Spec.Template.Spec.Bootstrap.ConfigRef != nil:
+		trigger.Kind = deployment.Spec.Template.Spec.Bootstrap.ConfigRef.Kind
+		trigger.Name = deployment.Spec.Template.Spec.Bootstrap.ConfigRef.Name
+	}
+	return trigger, nil
+}
+
 // computeDesiredMachineSet computes the desired MachineSet.
 // This MachineSet will be used during reconciliation to:
 // * create a MachineSet
@@ -489,8 +560,18 @@ func (r *Reconciler) scale(ctx context.Context, deployment *clusterv1.MachineDep
 
 // syncDeploymentStatus checks if the status is up-to-date and sync it if necessary.
 func (r *Reconciler) syncDeploymentStatus(allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet, md *clusterv1.MachineDeployment) error {
+	// A rollout is in progress while there are Machines not up to date.
+	rolloutInProgress := md.Status.Replicas > md.Status.UpdatedReplicas
 	md.Status = calculateStatus(allMSs, newMS, md)
 
+	// The rollout is completed when all the Machines are up to date and available.
+	if rolloutInProgress && newMS != nil &&
+		md.Status.UpdatedReplicas == *md.Spec.Replicas &&
+		md.Status.Replicas == *md.Spec.Replicas &&
+		md.Status.AvailableReplicas == *md.Spec.Replicas {
+		r.recorder.AnnotatedEventf(md, events.Annotations(md.Spec.ClusterName, "MachineSet", newMS.Name), corev1.EventTypeNormal, clusterv1.EventRolloutCompleted, "Rolled out Machines to MachineSet %s", klog.KObj(newMS))
+	}
+
 	// minReplicasNeeded will be equal to md.Spec.Replicas when the strategy is not RollingUpdateMachineDeploymentStrategyType.
 	minReplicasNeeded := *(md.Spec.Replicas) - mdutil.MaxUnavailable(*md)
 
@@ -528,6 +609,7 @@ func calculateStatus(allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet
 		AvailableReplicas:   availableReplicas,
 		UnavailableReplicas: unavailableReplicas,
 		Conditions:          deployment.Status.Conditions,
+		RolloutHistory:      deployment.Status.RolloutHistory,
 	}
 
 	if *deployment.Spec.Replicas == status.ReadyReplicas {
@@ -586,12 +668,12 @@ func (r *Reconciler) scaleMachineSet(ctx context.Context, ms *clusterv1.MachineS
 	mdutil.SetReplicasAnnotations(ms, *(deployment.Spec.Replicas), *(deployment.Spec.Replicas)+mdutil.MaxSurge(*deployment))
 
 	if err := patchHelper.Patch(ctx, ms); err != nil {
-		r.recorder.Eventf(deployment, corev1.EventTypeWarning, "FailedScale", "Failed to scale MachineSet %v: %v",
+		r.recorder.AnnotatedEventf(deployment, events.Annotations(deployment.Spec.ClusterName, "MachineSet", ms.Name), corev1.EventTypeWarning, clusterv1.EventFailedScale, "Failed to scale MachineSet %v: %v",
 			client.ObjectKeyFromObject(ms), err)
 		return err
 	}
 
-	r.recorder.Eventf(deployment, corev1.EventTypeNormal, "SuccessfulScale", "Scaled MachineSet %v: %d -> %d",
+	r.recorder.AnnotatedEventf(deployment, events.Annotations(deployment.Spec.ClusterName, "MachineSet", ms.Name), corev1.EventTypeNormal, clusterv1.EventSuccessfulScale, "Scaled MachineSet %v: %d -> %d",
 		client.ObjectKeyFromObject(ms), originalReplicas, *ms.Spec.Replicas)
 
 	return nil
@@ -637,10 +719,10 @@ func (r *Reconciler) cleanupDeployment(ctx context.Context, oldMSs []*clusterv1.
 		if err := r.Client.Delete(ctx, ms); err != nil && !apierrors.IsNotFound(err) {
 			// Return error instead of aggregating and continuing DELETEs on the theory
 			// that we may be overloading the api server.
-			r.recorder.Eventf(deployment, corev1.EventTypeWarning, "FailedDelete", "Failed to delete MachineSet %q: %v", ms.Name, err)
+			r.recorder.AnnotatedEventf(deployment, events.Annotations(deployment.Spec.ClusterName, "MachineSet", ms.Name), corev1.EventTypeWarning, clusterv1.EventFailedDelete, "Failed to delete MachineSet %q: %v", ms.Name, err)
 			return err
 		}
-		r.recorder.Eventf(deployment, corev1.EventTypeNormal, "SuccessfulDelete", "Deleted MachineSet %q", ms.Name)
+		r.recorder.AnnotatedEventf(deployment, events.Annotations(deployment.Spec.ClusterName, "MachineSet", ms.Name), corev1.EventTypeNormal, clusterv1.EventSuccessfulDelete, "Deleted MachineSet %q", ms.Name)
 	}
 
 	return nil
@@ -456,6 +456,7 @@ func TestSyncDeploymentStatus(t *testing.T) {
 		oldMachineSets     []*clusterv1.MachineSet
 		newMachineSet      *clusterv1.MachineSet
 		expectedConditions []*clusterv1.Condition
+		expectedEvent      string
 	}{
 		{
 			name:           "Deployment not available: MachineDeploymentAvailableCondition should exist and be false",
@@ -483,19 +484,50 @@ func TestSyncDeploymentStatus(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:           "Rollout completed: RolloutCompleted event should be recorded",
+			d:              newTestMachineDeployment(&pds, 3, 4, 3, 3, clusterv1.Conditions{}),
+			oldMachineSets: []*clusterv1.MachineSet{newTestMachinesetWithReplicas("bar", 0, 0, 0)},
+			newMachineSet:  newTestMachinesetWithReplicas("foo", 3, 3, 3),
+			expectedConditions: []*clusterv1.Condition{
+				{
+					Type:   clusterv1.MachineDeploymentAvailableCondition,
+					Status: corev1.ConditionTrue,
+				},
+			},
+			expectedEvent: clusterv1.EventRolloutCompleted,
+		},
+		{
+			name:           "Rollout in progress: RolloutCompleted event should not be recorded",
+			d:              newTestMachineDeployment(&pds, 3, 4, 2, 3, clusterv1.Conditions{}),
+			oldMachineSets: []*clusterv1.MachineSet{newTestMachinesetWithReplicas("bar", 1, 1, 1)},
+			newMachineSet:  newTestMachinesetWithReplicas("foo", 3, 3, 2),
+			expectedConditions: []*clusterv1.Condition{
+				{
+					Type:   clusterv1.MachineDeploymentAvailableCondition,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			g := NewWithT(t)
+			recorder := record.NewFakeRecorder(32)
 			r := &Reconciler{
 				Client:   fake.NewClientBuilder().Build(),
-				recorder: record.NewFakeRecorder(32),
+				recorder: recorder,
 			}
 			allMachineSets := append(test.oldMachineSets, test.newMachineSet)
 			err := r.syncDeploymentStatus(allMachineSets, test.newMachineSet, test.d)
 			g.Expect(err).ToNot(HaveOccurred())
 			assertConditions(t, test.d, test.expectedConditions...)
+			if test.expectedEvent != "" {
+				g.Expect(recorder.Events).To(Receive(ContainSubstring(test.expectedEvent)))
+			} else {
+				g.Expect(recorder.Events).ToNot(Receive())
+			}
 		})
 	}
 }
@@ -801,6 +833,122 @@ func assertCondition(t *testing.T, from conditions.Getter, condition *clusterv1.
 	}
 }
 
+func TestComputeRolloutTrigger(t *testing.T) {
+	now := time.Date(2023, 10, 1, 3, 0, 0, 0, time.UTC)
+	template := func(version, infraTemplate string) clusterv1.MachineTemplateSpec {
+		return clusterv1.MachineTemplateSpec{
+			Spec: clusterv1.MachineSpec{
+				Version:           pointer.String(version),
+				InfrastructureRef: corev1.ObjectReference{Kind: "GenericInfrastructureMachineTemplate", Name: infraTemplate},
+				Bootstrap: clusterv1.Bootstrap{
+					ConfigRef: &corev1.ObjectReference{Kind: "GenericBootstrapConfigTemplate", Name: "bootstrap-template"},
+				},
+				NodeDrainTimeout: &metav1.Duration{Duration: time.Second},
+			},
+		}
+	}
+	machineSet := func(revision string, template clusterv1.MachineTemplateSpec) *clusterv1.MachineSet {
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{clusterv1.RevisionAnnotation: revision}},
+			Spec:       clusterv1.MachineSetSpec{Template: template},
+		}
+	}
+	deployment := func(template clusterv1.MachineTemplateSpec) *clusterv1.MachineDeployment {
+		return &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "md",
+				ManagedFields: []metav1.ManagedFieldsEntry{{
+					Manager:    "kubectl-edit",
+					Operation:  metav1.ManagedFieldsOperationUpdate,
+					FieldsType: "FieldsV1",
+					FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:version":{}}}}}`)},
+				}},
+			},
+			Spec: clusterv1.MachineDeploymentSpec{Template: template},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		deployment     *clusterv1.MachineDeployment
+		oldMSs         []*clusterv1.MachineSet
+		wantKind       string
+		wantName       string
+		wantFieldPaths []string
+		wantManager    string
+	}{
+		{
+			name:       "changes compared with the latest MachineSet",
+			deployment: deployment(template("v1.29.0", "infra-template-1")),
+			oldMSs: []*clusterv1.MachineSet{
+				machineSet("1", template("v1.27.0", "infra-template-0")),
+				machineSet("2", template("v1.28.0", "infra-template-1")),
+			},
+			wantKind:       "MachineDeployment",
+			wantName:       "md",
+			wantFieldPaths: []string{"spec.template.spec.version"},
+			wantManager:    "kubectl-edit",
+		},
+		{
+			name:       "in-place mutable fields are ignored",
+			deployment: deployment(template("v1.29.0", "infra-template-1")),
+			oldMSs: []*clusterv1.MachineSet{
+				func() *clusterv1.MachineSet {
+					ms := machineSet("1", template("v1.28.0", "infra-template-1"))
+					ms.Spec.Template.Spec.NodeDrainTimeout = nil
+					return ms
+				}(),
+			},
+			wantKind:       "MachineDeployment",
+			wantName:       "md",
+			wantFieldPaths: []string{"spec.template.spec.version"},
+			wantManager:    "kubectl-edit",
+		},
+		{
+			name:       "changes to the infrastructure machine template",
+			deployment: deployment(template("v1.28.0", "infra-template-2")),
+			oldMSs: []*clusterv1.MachineSet{
+				machineSet("1", template("v1.28.0", "infra-template-1")),
+			},
+			wantKind:       "GenericInfrastructureMachineTemplate",
+			wantName:       "infra-template-2",
+			wantFieldPaths: []string{"spec.template.spec.infrastructureRef.name"},
+		},
+		{
+			name: "rolloutAfter",
+			deployment: func() *clusterv1.MachineDeployment {
+				md := deployment(template("v1.28.0", "infra-template-1"))
+				md.Spec.RolloutAfter = &metav1.Time{Time: now}
+				return md
+			}(),
+			oldMSs: []*clusterv1.MachineSet{
+				machineSet("1", template("v1.28.0", "infra-template-1")),
+			},
+			wantKind:       "MachineDeployment",
+			wantName:       "md",
+			wantFieldPaths: []string{"spec.rolloutAfter"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			trigger, err := computeRolloutTrigger(tt.deployment, tt.oldMSs, now)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(trigger.Time.Time).To(Equal(now))
+			g.Expect(trigger.Kind).To(Equal(tt.wantKind))
+			g.Expect(trigger.Name).To(Equal(tt.wantName))
+			g.Expect(trigger.FieldPaths).To(Equal(tt.wantFieldPaths))
+			g.Expect(trigger.Manager).To(Equal(tt.wantManager))
+			g.Expect(trigger.OldHash).ToNot(BeEmpty())
+			g.Expect(trigger.NewHash).ToNot(BeEmpty())
+			if len(tt.wantFieldPaths) > 0 && tt.wantFieldPaths[0] != "spec.rolloutAfter" {
+				g.Expect(trigger.OldHash).ToNot(Equal(trigger.NewHash))
+			}
+		})
+	}
+}
+
 func Test_computeNewMachineSetName(t *testing.T) {
 	tests := []struct {
 		base       string
@@ -47,20 +47,18 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/internal/controllers/machine"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/metrics"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/shard"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 const (
-	// Event types.
-
-	// EventRemediationRestricted is emitted in case when machine remediation
-	// is restricted by remediation circuit shorting logic.
-	EventRemediationRestricted string = "RemediationRestricted"
-
 	maxUnhealthyKeyLog     = "max unhealthy"
 	unhealthyTargetsKeyLog = "unhealthy targets"
 	unhealthyRangeKeyLog   = "unhealthy range"
@@ -80,6 +78,9 @@ type Reconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// Sharder, if set, limits the reconciliation to the MachineHealthChecks of the Clusters assigned to this replica of the manager.
+	Sharder *shard.Sharder
+
 	controller controller.Controller
 	recorder   record.EventRecorder
 }
@@ -103,11 +104,18 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			),
-		).Build(r)
+		).Build(tracing.Reconciler("machinehealthcheck", "MachineHealthCheck", metrics.Reconciler("machinehealthcheck", "MachineHealthCheck", r)))
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
+	if r.Sharder != nil {
+		// Reconcile the MachineHealthChecks of the Clusters assigned to this replica, e.g. after another replica left.
+		if err := c.Watch(r.Sharder.Source(), handler.EnqueueRequestsFromMapFunc(r.clusterToMachineHealthCheck)); err != nil {
+			return errors.Wrap(err, "failed adding Watch for the Clusters assigned to the shard")
+		}
+	}
+
 	r.controller = c
 	r.recorder = mgr.GetEventRecorderFor("machinehealthcheck-controller")
 	return nil
@@ -131,6 +139,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	}
 
 	log = log.WithValues("Cluster", klog.KRef(m.Namespace, m.Spec.ClusterName))
+	tracing.SetCluster(ctx, m.Spec.ClusterName)
+	metrics.SetCluster(ctx, m.Spec.ClusterName)
 	ctx = ctrl.LoggerInto(ctx, log)
 
 	cluster, err := util.GetClusterByName(ctx, r.Client, m.Namespace, m.Spec.ClusterName)
@@ -139,6 +149,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	// Return early if the Cluster is reconciled by another replica.
+	if !r.Sharder.Owns(cluster) {
+		log.V(4).Info("Reconciliation is skipped, the Cluster is assigned to another replica")
+		return ctrl.Result{}, nil
+	}
+
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, m) {
 		log.Info("Reconciliation is paused for this object")
@@ -179,7 +195,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 			return ctrl.Result{Requeue: true}, nil
 		}
 		log.Error(err, "Failed to reconcile MachineHealthCheck")
-		r.recorder.Eventf(m, corev1.EventTypeWarning, "ReconcileError", "%v", err)
+		r.recorder.AnnotatedEventf(m, events.Annotations(m.Spec.ClusterName, "", ""), corev1.EventTypeWarning, clusterv1.EventReconcileError, "%v", err)
 
 		// Requeue immediately if any errors occurred
 		return ctrl.Result{}, err
@@ -280,10 +296,11 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 			Message:  message,
 		})
 
-		r.recorder.Event(
+		r.recorder.AnnotatedEventf(
 			m,
+			events.Annotations(m.Spec.ClusterName, "", ""),
 			corev1.EventTypeWarning,
-			EventRemediationRestricted,
+			clusterv1.EventRemediationRestricted,
 			message,
 		)
 		errList := []error{}
@@ -443,10 +460,11 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 			errList = append(errList, errors.Wrapf(err, "failed to patch unhealthy machine status for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
 			continue
 		}
-		r.recorder.Eventf(
+		r.recorder.AnnotatedEventf(
 			t.Machine,
+			events.Annotations(t.Machine.Spec.ClusterName, "", ""),
 			corev1.EventTypeNormal,
-			EventMachineMarkedUnhealthy,
+			clusterv1.EventMachineMarkedUnhealthy,
 			"Machine %v has been marked as unhealthy",
 			t.string(),
 		)
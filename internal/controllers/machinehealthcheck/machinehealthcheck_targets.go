@@ -31,22 +31,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
 
-const (
-	// Event types.
-
-	// EventMachineMarkedUnhealthy is emitted when machine was successfully marked as unhealthy.
-	EventMachineMarkedUnhealthy string = "MachineMarkedUnhealthy"
-	// EventDetectedUnhealthy is emitted in case a node associated with a
-	// machine was detected unhealthy.
-	EventDetectedUnhealthy string = "DetectedUnhealthy"
-)
-
 var (
 	// We allow users to disable the nodeStartupTimeout by setting the duration to 0.
 	disabledNodeStartupTimeout = clusterv1.ZeroDuration
@@ -297,10 +288,11 @@ func (r *Reconciler) healthCheckTargets(targets []healthCheckTarget, logger logr
 
 		if nextCheck > 0 {
 			logger.V(3).Info("Target is likely to go unhealthy", "timeUntilUnhealthy", nextCheck.Truncate(time.Second).String())
-			r.recorder.Eventf(
+			r.recorder.AnnotatedEventf(
 				t.Machine,
+				events.Annotations(t.Machine.Spec.ClusterName, "Node", t.nodeName()),
 				corev1.EventTypeNormal,
-				EventDetectedUnhealthy,
+				clusterv1.EventDetectedUnhealthy,
 				"Machine %v has unhealthy node %v",
 				t.string(),
 				t.nodeName(),
@@ -45,6 +45,7 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/machine"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -53,8 +54,11 @@ import (
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/cluster-api/util/labels/format"
 	clog "sigs.k8s.io/cluster-api/util/log"
+	"sigs.k8s.io/cluster-api/util/metrics"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/shard"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 var (
@@ -87,6 +91,9 @@ type Reconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// Sharder, if set, limits the reconciliation to the MachineSets of the Clusters assigned to this replica of the manager.
+	Sharder *shard.Sharder
+
 	ssaCache ssa.Cache
 	recorder record.EventRecorder
 }
@@ -97,7 +104,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		return err
 	}
 
-	err = ctrl.NewControllerManagedBy(mgr).
+	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.MachineSet{}).
 		Owns(&clusterv1.Machine{}).
 		Watches(
@@ -116,11 +123,18 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			),
-		).Complete(r)
+		).Build(tracing.Reconciler("machineset", "MachineSet", metrics.Reconciler("machineset", "MachineSet", r)))
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
+	if r.Sharder != nil {
+		// Reconcile the MachineSets of the Clusters assigned to this replica, e.g. after another replica left.
+		if err := c.Watch(r.Sharder.Source(), handler.EnqueueRequestsFromMapFunc(clusterToMachineSets)); err != nil {
+			return errors.Wrap(err, "failed adding Watch for the Clusters assigned to the shard")
+		}
+	}
+
 	r.recorder = mgr.GetEventRecorderFor("machineset-controller")
 	r.ssaCache = ssa.NewCache()
 	return nil
@@ -146,6 +160,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	}
 
 	log = log.WithValues("Cluster", klog.KRef(machineSet.ObjectMeta.Namespace, machineSet.Spec.ClusterName))
+	tracing.SetCluster(ctx, machineSet.Spec.ClusterName)
+	metrics.SetCluster(ctx, machineSet.Spec.ClusterName)
 	ctx = ctrl.LoggerInto(ctx, log)
 
 	cluster, err := util.GetClusterByName(ctx, r.Client, machineSet.ObjectMeta.Namespace, machineSet.Spec.ClusterName)
@@ -153,6 +169,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	// Return early if the Cluster is reconciled by another replica.
+	if !r.Sharder.Owns(cluster) {
+		log.V(4).Info("Reconciliation is skipped, the Cluster is assigned to another replica")
+		return ctrl.Result{}, nil
+	}
+
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, machineSet) {
 		log.Info("Reconciliation is paused for this object")
@@ -186,7 +208,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 			log.V(5).Info("Requeuing because another worker has the lock on the ClusterCacheTracker")
 			return ctrl.Result{Requeue: true}, nil
 		}
-		r.recorder.Eventf(machineSet, corev1.EventTypeWarning, "ReconcileError", "%v", err)
+		r.recorder.AnnotatedEventf(machineSet, events.Annotations(machineSet.Spec.ClusterName, "", ""), corev1.EventTypeWarning, clusterv1.EventReconcileError, "%v", err)
 	}
 	return result, err
 }
@@ -286,11 +308,11 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 		if metav1.GetControllerOf(machine) == nil {
 			if err := r.adoptOrphan(ctx, machineSet, machine); err != nil {
 				log.Error(err, "Failed to adopt Machine")
-				r.recorder.Eventf(machineSet, corev1.EventTypeWarning, "FailedAdopt", "Failed to adopt Machine %q: %v", machine.Name, err)
+				r.recorder.AnnotatedEventf(machineSet, events.Annotations(machineSet.Spec.ClusterName, "Machine", machine.Name), corev1.EventTypeWarning, clusterv1.EventFailedAdopt, "Failed to adopt Machine %q: %v", machine.Name, err)
 				continue
 			}
 			log.Info("Adopted Machine")
-			r.recorder.Eventf(machineSet, corev1.EventTypeNormal, "SuccessfulAdopt", "Adopted Machine %q", machine.Name)
+			r.recorder.AnnotatedEventf(machineSet, events.Annotations(machineSet.Spec.ClusterName, "Machine", machine.Name), corev1.EventTypeNormal, clusterv1.EventSuccessfulAdopt, "Adopted Machine %q", machine.Name)
 		}
 
 		filteredMachines = append(filteredMachines, machine)
@@ -522,7 +544,7 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 			// Create the Machine.
 			if err := ssa.Patch(ctx, r.Client, machineSetManagerName, machine); err != nil {
 				log.Error(err, "Error while creating a machine")
-				r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedCreate", "Failed to create machine: %v", err)
+				r.recorder.AnnotatedEventf(ms, events.Annotations(ms.Spec.ClusterName, "Machine", machine.Name), corev1.EventTypeWarning, clusterv1.EventFailedCreate, "Failed to create machine: %v", err)
 				errs = append(errs, err)
 				conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.MachineCreationFailedReason,
 					clusterv1.ConditionSeverityError, err.Error())
@@ -540,7 +562,7 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 			}
 
 			log.Info(fmt.Sprintf("Created machine %d of %d", i+1, diff), "Machine", klog.KObj(machine))
-			r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulCreate", "Created machine %q", machine.Name)
+			r.recorder.AnnotatedEventf(ms, events.Annotations(ms.Spec.ClusterName, "Machine", machine.Name), corev1.EventTypeNormal, clusterv1.EventSuccessfulCreate, "Created machine %q", machine.Name)
 			machineList = append(machineList, machine)
 		}
 
@@ -564,11 +586,11 @@ func (r *Reconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluste
 				log.Info(fmt.Sprintf("Deleting machine %d of %d", i+1, diff))
 				if err := r.Client.Delete(ctx, machine); err != nil {
 					log.Error(err, "Unable to delete Machine")
-					r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedDelete", "Failed to delete machine %q: %v", machine.Name, err)
+					r.recorder.AnnotatedEventf(ms, events.Annotations(ms.Spec.ClusterName, "Machine", machine.Name), corev1.EventTypeWarning, clusterv1.EventFailedDelete, "Failed to delete machine %q: %v", machine.Name, err)
 					errs = append(errs, err)
 					continue
 				}
-				r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulDelete", "Deleted machine %q", machine.Name)
+				r.recorder.AnnotatedEventf(ms, events.Annotations(ms.Spec.ClusterName, "Machine", machine.Name), corev1.EventTypeNormal, clusterv1.EventSuccessfulDelete, "Deleted machine %q", machine.Name)
 			} else {
 				log.Info(fmt.Sprintf("Waiting for machine %d of %d to be deleted", i+1, diff))
 			}
@@ -996,6 +1018,10 @@ func (r *Reconciler) reconcileUnhealthyMachines(ctx context.Context, cluster *cl
 				errs = append(errs, errors.Wrapf(err, "failed to create patch helper for Machine %s", klog.KObj(m)))
 				continue
 			}
+			// Record an event only when the reason why remediation is blocked changes, to avoid recording an event at every reconcile.
+			if c := conditions.Get(m, clusterv1.MachineOwnerRemediatedCondition); c == nil || c.Reason != clusterv1.WaitingForRemediationReason || c.Message != preflightCheckErrMessage {
+				r.recorder.AnnotatedEventf(ms, events.Annotations(ms.Spec.ClusterName, "Machine", m.Name), corev1.EventTypeWarning, clusterv1.EventRemediationBlocked, "Remediation of Machine %s is blocked: %s", m.Name, preflightCheckErrMessage)
+			}
 			conditions.MarkFalse(m, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, preflightCheckErrMessage)
 			if err := patchHelper.Patch(ctx, m); err != nil {
 				errs = append(errs, errors.Wrapf(err, "failed to patch Machine %s", klog.KObj(m)))
@@ -1018,6 +1044,7 @@ func (r *Reconciler) reconcileUnhealthyMachines(ctx context.Context, cluster *cl
 			errs = append(errs, errors.Wrapf(err, "failed to delete Machine %s", klog.KObj(m)))
 			continue
 		}
+		r.recorder.AnnotatedEventf(ms, events.Annotations(ms.Spec.ClusterName, "Machine", m.Name), corev1.EventTypeNormal, clusterv1.EventRemediationStarted, "Deleted unhealthy Machine %s", m.Name)
 		conditions.MarkTrue(m, clusterv1.MachineOwnerRemediatedCondition)
 		if err := r.Client.Status().Patch(ctx, m, patch); err != nil && !apierrors.IsNotFound(err) {
 			errs = append(errs, errors.Wrapf(err, "failed to update status of Machine %s", klog.KObj(m)))
@@ -1396,12 +1396,15 @@ func TestMachineSetReconciler_reconcileUnhealthyMachines(t *testing.T) {
 		machines := []*clusterv1.Machine{unhealthyMachine, healthyMachine}
 
 		fakeClient := fake.NewClientBuilder().WithObjects(controlPlaneStable, unhealthyMachine, healthyMachine).Build()
+		recorder := record.NewFakeRecorder(32)
 		r := &Reconciler{
 			Client:                    fakeClient,
 			UnstructuredCachingClient: fakeClient,
+			recorder:                  recorder,
 		}
 		_, err := r.reconcileUnhealthyMachines(ctx, cluster, machineSet, machines)
 		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(clusterv1.EventRemediationStarted)))
 		// Verify the unhealthy machine is deleted.
 		m := &clusterv1.Machine{}
 		err = r.Client.Get(ctx, client.ObjectKeyFromObject(unhealthyMachine), m)
@@ -1457,12 +1460,15 @@ func TestMachineSetReconciler_reconcileUnhealthyMachines(t *testing.T) {
 
 		machines := []*clusterv1.Machine{unhealthyMachine, healthyMachine}
 		fakeClient := fake.NewClientBuilder().WithObjects(controlPlaneUpgrading, unhealthyMachine, healthyMachine).WithStatusSubresource(&clusterv1.Machine{}).Build()
+		recorder := record.NewFakeRecorder(32)
 		r := &Reconciler{
 			Client:                    fakeClient,
 			UnstructuredCachingClient: fakeClient,
+			recorder:                  recorder,
 		}
 		_, err := r.reconcileUnhealthyMachines(ctx, cluster, machineSet, machines)
 		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(clusterv1.EventRemediationBlocked)))
 
 		// Verify the unhealthy machine has the updated condition.
 		condition := clusterv1.MachineOwnerRemediatedCondition
@@ -0,0 +1,198 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/labels"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusterclasses,verbs=get;list;watch;patch
+
+// Reconciler pauses the Clusters and ClusterClasses of the Namespaces with the maintenance annotation, and unpauses
+// them once the annotation is removed.
+// Clusters are paused using spec.paused, which is honored by the controllers of all the objects in the hierarchy of
+// a Cluster, including the ones of the providers; ClusterClasses are paused using the paused annotation.
+// The objects paused because of the maintenance are marked with the paused-by-maintenance annotation, so that
+// objects paused by the users are left paused once the maintenance is over.
+type Reconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+
+	recorder record.EventRecorder
+}
+
+func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	// Clusters and ClusterClasses are watched so they are paused if created or unpaused during the maintenance.
+	objectPredicates := builder.WithPredicates(
+		predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
+		predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+	)
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named("maintenance").
+		For(&corev1.Namespace{}).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(objectToNamespace),
+			objectPredicates,
+		).
+		Watches(
+			&clusterv1.ClusterClass{},
+			handler.EnqueueRequestsFromMapFunc(objectToNamespace),
+			objectPredicates,
+		).
+		WithOptions(options).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	r.recorder = mgr.GetEventRecorderFor("maintenance-controller")
+	return nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, req.NamespacedName, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// NOTE: The objects of a Namespace being deleted are unpaused, otherwise they would block the deletion.
+	_, inMaintenance := namespace.Annotations[clusterv1.NamespaceMaintenanceAnnotation]
+	inMaintenance = inMaintenance && namespace.DeletionTimestamp.IsZero()
+
+	clusters := &clusterv1.ClusterList{}
+	if err := r.Client.List(ctx, clusters, client.InNamespace(namespace.Name)); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list Clusters in namespace %s", namespace.Name)
+	}
+	clusterClasses := &clusterv1.ClusterClassList{}
+	if err := r.Client.List(ctx, clusterClasses, client.InNamespace(namespace.Name)); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list ClusterClasses in namespace %s", namespace.Name)
+	}
+
+	errs := []error{}
+	for i := range clusters.Items {
+		if err := r.reconcileCluster(ctx, &clusters.Items[i], inMaintenance); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for i := range clusterClasses.Items {
+		if err := r.reconcileClusterClass(ctx, &clusterClasses.Items[i], inMaintenance); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return ctrl.Result{}, kerrors.NewAggregate(errs)
+}
+
+// reconcileCluster pauses a Cluster during the maintenance of its Namespace, and unpauses it after.
+func (r *Reconciler) reconcileCluster(ctx context.Context, cluster *clusterv1.Cluster, inMaintenance bool) error {
+	if r.WatchFilterValue != "" && !labels.HasWatchLabel(cluster, r.WatchFilterValue) {
+		return nil
+	}
+	_, pausedByMaintenance := cluster.Annotations[clusterv1.PausedByMaintenanceAnnotation]
+
+	original := cluster.DeepCopy()
+	switch {
+	case inMaintenance && !cluster.Spec.Paused:
+		cluster.Spec.Paused = true
+		setAnnotation(cluster, clusterv1.PausedByMaintenanceAnnotation)
+	case !inMaintenance && pausedByMaintenance:
+		cluster.Spec.Paused = false
+		delete(cluster.Annotations, clusterv1.PausedByMaintenanceAnnotation)
+	default:
+		return nil
+	}
+	return r.patch(ctx, original, cluster, "Cluster", inMaintenance)
+}
+
+// reconcileClusterClass pauses a ClusterClass during the maintenance of its Namespace, and unpauses it after.
+func (r *Reconciler) reconcileClusterClass(ctx context.Context, clusterClass *clusterv1.ClusterClass, inMaintenance bool) error {
+	if r.WatchFilterValue != "" && !labels.HasWatchLabel(clusterClass, r.WatchFilterValue) {
+		return nil
+	}
+	_, paused := clusterClass.Annotations[clusterv1.PausedAnnotation]
+	_, pausedByMaintenance := clusterClass.Annotations[clusterv1.PausedByMaintenanceAnnotation]
+
+	original := clusterClass.DeepCopy()
+	switch {
+	case inMaintenance && !paused:
+		setAnnotation(clusterClass, clusterv1.PausedAnnotation)
+		setAnnotation(clusterClass, clusterv1.PausedByMaintenanceAnnotation)
+	case !inMaintenance && pausedByMaintenance:
+		delete(clusterClass.Annotations, clusterv1.PausedAnnotation)
+		delete(clusterClass.Annotations, clusterv1.PausedByMaintenanceAnnotation)
+	default:
+		return nil
+	}
+	return r.patch(ctx, original, clusterClass, "ClusterClass", inMaintenance)
+}
+
+// patch patches an object paused or unpaused because of the maintenance of its Namespace.
+func (r *Reconciler) patch(ctx context.Context, original, obj client.Object, kind string, inMaintenance bool) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if err := r.Client.Patch(ctx, obj, client.MergeFrom(original)); err != nil {
+		return errors.Wrapf(err, "failed to patch %s %s", kind, klog.KObj(obj))
+	}
+
+	if inMaintenance {
+		log.Info("Paused for the maintenance of the namespace", kind, klog.KObj(obj))
+		r.recorder.Eventf(obj, corev1.EventTypeNormal, clusterv1.EventPausedForMaintenance, "Paused because namespace %s is in maintenance", obj.GetNamespace())
+		return nil
+	}
+	log.Info("Unpaused after the maintenance of the namespace", kind, klog.KObj(obj))
+	r.recorder.Eventf(obj, corev1.EventTypeNormal, clusterv1.EventUnpausedAfterMaintenance, "Unpaused because namespace %s is no longer in maintenance", obj.GetNamespace())
+	return nil
+}
+
+// setAnnotation sets an annotation with an empty value.
+func setAnnotation(obj client.Object, key string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = ""
+	obj.SetAnnotations(annotations)
+}
+
+// objectToNamespace maps Clusters and ClusterClasses to their Namespace.
+func objectToNamespace(_ context.Context, o client.Object) []ctrl.Request {
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Name: o.GetNamespace()}}}
+}
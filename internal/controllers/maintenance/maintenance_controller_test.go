@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var ctx = ctrl.SetupSignalHandler()
+
+func TestReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+
+	newNamespace := func(annotations map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: annotations},
+		}
+	}
+	newCluster := func(name string, paused bool, annotations map[string]string) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", Annotations: annotations},
+			Spec:       clusterv1.ClusterSpec{Paused: paused},
+		}
+	}
+	newClusterClass := func(name string, annotations map[string]string) *clusterv1.ClusterClass {
+		return &clusterv1.ClusterClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", Annotations: annotations},
+		}
+	}
+	inMaintenance := map[string]string{clusterv1.NamespaceMaintenanceAnnotation: "etcd restore"}
+	pausedByMaintenance := map[string]string{clusterv1.PausedByMaintenanceAnnotation: ""}
+
+	tests := []struct {
+		name                     string
+		namespace                *corev1.Namespace
+		clusters                 []*clusterv1.Cluster
+		clusterClasses           []*clusterv1.ClusterClass
+		wantPausedClusters       []string
+		wantPausedClusterClasses []string
+		wantPausedByMaintenance  []string
+		wantEvents               int
+	}{
+		{
+			name:      "objects are not paused if the namespace is not in maintenance",
+			namespace: newNamespace(nil),
+			clusters: []*clusterv1.Cluster{
+				newCluster("cluster1", false, nil),
+				newCluster("cluster2", true, nil),
+			},
+			clusterClasses: []*clusterv1.ClusterClass{
+				newClusterClass("class1", nil),
+			},
+			wantPausedClusters: []string{"cluster2"},
+		},
+		{
+			name:      "objects are paused if the namespace is in maintenance",
+			namespace: newNamespace(inMaintenance),
+			clusters: []*clusterv1.Cluster{
+				newCluster("cluster1", false, nil),
+				newCluster("cluster2", true, nil),
+			},
+			clusterClasses: []*clusterv1.ClusterClass{
+				newClusterClass("class1", nil),
+			},
+			wantPausedClusters:       []string{"cluster1", "cluster2"},
+			wantPausedClusterClasses: []string{"class1"},
+			// Objects already paused by the users are not marked as paused by the maintenance.
+			wantPausedByMaintenance: []string{"cluster1", "class1"},
+			wantEvents:              2,
+		},
+		{
+			name:      "objects paused by the maintenance are unpaused if the namespace is no longer in maintenance",
+			namespace: newNamespace(nil),
+			clusters: []*clusterv1.Cluster{
+				newCluster("cluster1", true, pausedByMaintenance),
+				newCluster("cluster2", true, nil),
+			},
+			clusterClasses: []*clusterv1.ClusterClass{
+				newClusterClass("class1", map[string]string{
+					clusterv1.PausedAnnotation:              "",
+					clusterv1.PausedByMaintenanceAnnotation: "",
+				}),
+				newClusterClass("class2", map[string]string{clusterv1.PausedAnnotation: ""}),
+			},
+			wantPausedClusters:       []string{"cluster2"},
+			wantPausedClusterClasses: []string{"class2"},
+			wantEvents:               2,
+		},
+		{
+			name: "objects are unpaused if the namespace is being deleted",
+			namespace: func() *corev1.Namespace {
+				ns := newNamespace(inMaintenance)
+				ns.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+				ns.Finalizers = []string{"kubernetes"}
+				return ns
+			}(),
+			clusters: []*clusterv1.Cluster{
+				newCluster("cluster1", true, pausedByMaintenance),
+			},
+			wantEvents: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs := []client.Object{tt.namespace}
+			for _, c := range tt.clusters {
+				objs = append(objs, c)
+			}
+			for _, c := range tt.clusterClasses {
+				objs = append(objs, c)
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				Client:   c,
+				recorder: recorder,
+			}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "test"}})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			pausedByMaintenance := []string{}
+			clusters := &clusterv1.ClusterList{}
+			g.Expect(c.List(ctx, clusters)).To(Succeed())
+			pausedClusters := []string{}
+			for _, cluster := range clusters.Items {
+				if cluster.Spec.Paused {
+					pausedClusters = append(pausedClusters, cluster.Name)
+				}
+				if _, ok := cluster.Annotations[clusterv1.PausedByMaintenanceAnnotation]; ok {
+					pausedByMaintenance = append(pausedByMaintenance, cluster.Name)
+				}
+			}
+			g.Expect(pausedClusters).To(ConsistOf(tt.wantPausedClusters))
+
+			clusterClasses := &clusterv1.ClusterClassList{}
+			g.Expect(c.List(ctx, clusterClasses)).To(Succeed())
+			pausedClusterClasses := []string{}
+			for _, clusterClass := range clusterClasses.Items {
+				if _, ok := clusterClass.Annotations[clusterv1.PausedAnnotation]; ok {
+					pausedClusterClasses = append(pausedClusterClasses, clusterClass.Name)
+				}
+				if _, ok := clusterClass.Annotations[clusterv1.PausedByMaintenanceAnnotation]; ok {
+					pausedByMaintenance = append(pausedByMaintenance, clusterClass.Name)
+				}
+			}
+			g.Expect(pausedClusterClasses).To(ConsistOf(tt.wantPausedClusterClasses))
+			g.Expect(pausedByMaintenance).To(ConsistOf(tt.wantPausedByMaintenance))
+			g.Expect(recorder.Events).To(HaveLen(tt.wantEvents))
+		})
+	}
+}
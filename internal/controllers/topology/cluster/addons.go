@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+)
+
+// getNotReadyClusterAddons returns the names of the ClusterAddons of the Cluster upgraded at the given phase
+// which are not ready for the given Kubernetes version.
+func (r *Reconciler) getNotReadyClusterAddons(ctx context.Context, cluster *clusterv1.Cluster, phase addonsv1.AddonUpgradePhase, version string) ([]string, error) {
+	addonList := &addonsv1.ClusterAddonList{}
+	if err := r.Client.List(ctx, addonList, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return nil, errors.Wrap(err, "failed to list ClusterAddons")
+	}
+
+	names := []string{}
+	for i := range addonList.Items {
+		addon := &addonList.Items[i]
+		if addon.Spec.UpgradePhase == phase && !addon.IsReadyFor(version) {
+			names = append(names, addon.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// clusterAddonToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for the Cluster of a ClusterAddon, so the upgrade of the Cluster resumes as soon as the ClusterAddon is ready.
+func (r *Reconciler) clusterAddonToCluster(_ context.Context, o client.Object) []ctrl.Request {
+	addon, ok := o.(*addonsv1.ClusterAddon)
+	if !ok {
+		panic(fmt.Sprintf("Expected a ClusterAddon but got a %T", o))
+	}
+	if addon.Spec.ClusterName == "" {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: addon.Namespace,
+			Name:      addon.Spec.ClusterName,
+		},
+	}}
+}
+
+// isWorkersUpgradePending returns true if any of the MachineDeployments or MachinePools of the Cluster is not
+// at the given version.
+func isWorkersUpgradePending(s *scope.Scope, version string) bool {
+	for _, md := range s.Current.MachineDeployments {
+		if md.Object.Spec.Template.Spec.Version == nil || *md.Object.Spec.Template.Spec.Version != version {
+			return true
+		}
+	}
+	for _, mp := range s.Current.MachinePools {
+		if mp.Object.Spec.Template.Spec.Version == nil || *mp.Object.Spec.Template.Spec.Version != version {
+			return true
+		}
+	}
+	return false
+}
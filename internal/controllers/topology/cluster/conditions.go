@@ -21,10 +21,12 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
@@ -93,6 +95,10 @@ func (r *Reconciler) reconcileTopologyReconciledCondition(s *scope.Scope, cluste
 	// If any of the lifecycle hooks are blocking any part of the reconciliation then topology
 	// is not considered as fully reconciled.
 	if s.HookResponseTracker.AggregateRetryAfter() != 0 {
+		// Record an event only when the hooks start blocking, so the event is not repeated at every reconcile.
+		if conditions.GetReason(cluster, clusterv1.TopologyReconciledCondition) != clusterv1.TopologyReconciledHookBlockingReason {
+			r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "", ""), corev1.EventTypeNormal, clusterv1.EventHookBlocking, "Topology reconciliation blocked by lifecycle hooks: %s", s.HookResponseTracker.AggregateMessage())
+		}
 		conditions.Set(
 			cluster,
 			conditions.FalseCondition(
@@ -106,6 +112,35 @@ func (r *Reconciler) reconcileTopologyReconciledCondition(s *scope.Scope, cluste
 		return nil
 	}
 
+	// If any of the ClusterAddons are not ready for the new version then the upgrade is held and
+	// topology is not considered as fully reconciled.
+	if s.UpgradeTracker.ClusterAddons.IsBlockingControlPlane() || s.UpgradeTracker.ClusterAddons.IsBlockingWorkers() {
+		var msg string
+		if s.UpgradeTracker.ClusterAddons.IsBlockingControlPlane() {
+			msg = fmt.Sprintf("Control plane rollout and upgrade to version %s on hold. ClusterAddon(s) %s are not ready for version %s",
+				s.Blueprint.Topology.Version,
+				computeNameList(s.UpgradeTracker.ClusterAddons.ControlPlaneBlockingNames),
+				s.Blueprint.Topology.Version,
+			)
+		} else {
+			msg = fmt.Sprintf("MachineDeployment(s) and MachinePool(s) rollout and upgrade to version %s on hold. ClusterAddon(s) %s are not ready for version %s",
+				s.Blueprint.Topology.Version,
+				computeNameList(s.UpgradeTracker.ClusterAddons.WorkersBlockingNames),
+				s.Blueprint.Topology.Version,
+			)
+		}
+		conditions.Set(
+			cluster,
+			conditions.FalseCondition(
+				clusterv1.TopologyReconciledCondition,
+				clusterv1.TopologyReconciledClusterAddonsNotReadyReason,
+				clusterv1.ConditionSeverityInfo,
+				msg,
+			),
+		)
+		return nil
+	}
+
 	// The topology is not considered as fully reconciled if one of the following is true:
 	// * either the Control Plane or any of the MachineDeployments/MachinePools are still pending to pick up the new version
 	//  (generally happens when upgrading the cluster)
@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -106,6 +107,27 @@ func TestReconcileTopologyReconciledCondition(t *testing.T) {
 			wantConditionReason:  clusterv1.TopologyReconciledHookBlockingReason,
 			wantConditionMessage: "hook \"BeforeClusterUpgrade\" is blocking: msg",
 		},
+		{
+			name:         "should set the condition to false if ClusterAddons are not ready for the new version",
+			reconcileErr: nil,
+			cluster:      &clusterv1.Cluster{},
+			s: &scope.Scope{
+				Blueprint: &scope.ClusterBlueprint{
+					Topology: &clusterv1.Topology{
+						Version: "v1.22.0",
+					},
+				},
+				UpgradeTracker: func() *scope.UpgradeTracker {
+					ut := scope.NewUpgradeTracker()
+					ut.ClusterAddons.ControlPlaneBlockingNames = []string{"cni"}
+					return ut
+				}(),
+				HookResponseTracker: scope.NewHookResponseTracker(),
+			},
+			wantConditionStatus:  corev1.ConditionFalse,
+			wantConditionReason:  clusterv1.TopologyReconciledClusterAddonsNotReadyReason,
+			wantConditionMessage: "Control plane rollout and upgrade to version v1.22.0 on hold. ClusterAddon(s) cni are not ready for version v1.22.0",
+		},
 		{
 			name:         "should set the condition to false if new version is not picked up because control plane is provisioning",
 			reconcileErr: nil,
@@ -970,7 +992,10 @@ func TestReconcileTopologyReconciledCondition(t *testing.T) {
 			}
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
 
-			r := &Reconciler{Client: fakeClient}
+			r := &Reconciler{
+				Client:   fakeClient,
+				recorder: record.NewFakeRecorder(32),
+			}
 			err := r.reconcileTopologyReconciledCondition(tt.s, tt.cluster, tt.reconcileErr)
 			if tt.wantErr {
 				g.Expect(err).To(HaveOccurred())
@@ -19,6 +19,7 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -30,6 +31,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
@@ -451,6 +453,20 @@ func (r *Reconciler) computeControlPlaneVersion(ctx context.Context, s *scope.Sc
 			}
 		}
 
+		// Hold off the creation and the upgrade of MachineDeployments/MachinePools until the ClusterAddons upgraded
+		// after the control plane are ready for the new version.
+		// NOTE: The Cluster is reconciled again when the ClusterAddons change, so there is no need to requeue.
+		if feature.Gates.Enabled(feature.ClusterAddon) && isWorkersUpgradePending(s, desiredVersion) {
+			notReady, err := r.getNotReadyClusterAddons(ctx, s.Current.Cluster, addonsv1.AddonUpgradePhaseAfterControlPlaneUpgrade, desiredVersion)
+			if err != nil {
+				return "", err
+			}
+			if len(notReady) > 0 {
+				s.UpgradeTracker.ClusterAddons.WorkersBlockingNames = notReady
+				log.Infof("MachineDeployments/MachinePools upgrade to version %q are blocked by ClusterAddons %s", desiredVersion, strings.Join(notReady, ", "))
+			}
+		}
+
 		return *currentVersion, nil
 	}
 
@@ -476,6 +492,21 @@ func (r *Reconciler) computeControlPlaneVersion(ctx context.Context, s *scope.Sc
 		return *currentVersion, nil
 	}
 
+	// Hold off the upgrade of the control plane until the ClusterAddons upgraded before the control plane
+	// are ready for the new version.
+	// NOTE: The Cluster is reconciled again when the ClusterAddons change, so there is no need to requeue.
+	if feature.Gates.Enabled(feature.ClusterAddon) {
+		notReady, err := r.getNotReadyClusterAddons(ctx, s.Current.Cluster, addonsv1.AddonUpgradePhaseBeforeControlPlaneUpgrade, desiredVersion)
+		if err != nil {
+			return "", err
+		}
+		if len(notReady) > 0 {
+			s.UpgradeTracker.ClusterAddons.ControlPlaneBlockingNames = notReady
+			log.Infof("Cluster upgrade to version %q is blocked by ClusterAddons %s", desiredVersion, strings.Join(notReady, ", "))
+			return *currentVersion, nil
+		}
+	}
+
 	if feature.Gates.Enabled(feature.RuntimeSDK) {
 		// At this point the control plane and the machine deployments are stable and we are almost ready to pick
 		// up the desiredVersion. Call the BeforeClusterUpgrade hook before picking up the desired version.
@@ -812,7 +843,7 @@ func computeMachineDeploymentVersion(s *scope.Scope, machineDeploymentTopology c
 	// Example: join could fail if the load balancers are slow in detecting when CP machines are
 	// being deleted.
 	if currentMDState == nil || currentMDState.Object == nil {
-		if !isControlPlaneStable(s) || s.HookResponseTracker.IsBlocking(runtimehooksv1.AfterControlPlaneUpgrade) {
+		if !isControlPlaneStable(s) || s.HookResponseTracker.IsBlocking(runtimehooksv1.AfterControlPlaneUpgrade) || s.UpgradeTracker.ClusterAddons.IsBlockingWorkers() {
 			s.UpgradeTracker.MachineDeployments.MarkPendingCreate(machineDeploymentTopology.Name)
 		}
 		return desiredVersion
@@ -840,6 +871,12 @@ func computeMachineDeploymentVersion(s *scope.Scope, machineDeploymentTopology c
 		return currentVersion
 	}
 
+	// Return early if ClusterAddons are not ready for the new version yet.
+	if s.UpgradeTracker.ClusterAddons.IsBlockingWorkers() {
+		s.UpgradeTracker.MachineDeployments.MarkPendingUpgrade(currentMDState.Object.Name)
+		return currentVersion
+	}
+
 	// Return early if the upgrade concurrency is reached.
 	if s.UpgradeTracker.MachineDeployments.UpgradeConcurrencyReached() {
 		s.UpgradeTracker.MachineDeployments.MarkPendingUpgrade(currentMDState.Object.Name)
@@ -1142,7 +1179,7 @@ func computeMachinePoolVersion(s *scope.Scope, machinePoolTopology clusterv1.Mac
 	// Example: join could fail if the load balancers are slow in detecting when CP machines are
 	// being deleted.
 	if currentMPState == nil || currentMPState.Object == nil {
-		if !isControlPlaneStable(s) || s.HookResponseTracker.IsBlocking(runtimehooksv1.AfterControlPlaneUpgrade) {
+		if !isControlPlaneStable(s) || s.HookResponseTracker.IsBlocking(runtimehooksv1.AfterControlPlaneUpgrade) || s.UpgradeTracker.ClusterAddons.IsBlockingWorkers() {
 			s.UpgradeTracker.MachinePools.MarkPendingCreate(machinePoolTopology.Name)
 		}
 		return desiredVersion
@@ -1170,6 +1207,12 @@ func computeMachinePoolVersion(s *scope.Scope, machinePoolTopology clusterv1.Mac
 		return currentVersion
 	}
 
+	// Return early if ClusterAddons are not ready for the new version yet.
+	if s.UpgradeTracker.ClusterAddons.IsBlockingWorkers() {
+		s.UpgradeTracker.MachinePools.MarkPendingUpgrade(currentMPState.Object.Name)
+		return currentVersion
+	}
+
 	// Return early if the upgrade concurrency is reached.
 	if s.UpgradeTracker.MachinePools.UpgradeConcurrencyReached() {
 		s.UpgradeTracker.MachinePools.MarkPendingUpgrade(currentMPState.Object.Name)
@@ -29,9 +29,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
@@ -1259,6 +1261,134 @@ func TestComputeControlPlaneVersion(t *testing.T) {
 		g.Expect(hooks.IsPending(runtimehooksv1.AfterControlPlaneUpgrade, s.Current.Cluster)).To(BeTrue())
 		g.Expect(hooks.IsPending(runtimehooksv1.AfterClusterUpgrade, s.Current.Cluster)).To(BeTrue())
 	})
+
+	t.Run("Holding the upgrade for ClusterAddons", func(t *testing.T) {
+		defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterAddon, true)()
+
+		readyAddon := func(name string, phase addonsv1.AddonUpgradePhase, kubernetesVersion string) *addonsv1.ClusterAddon {
+			return &addonsv1.ClusterAddon{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "test-ns",
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+				},
+				Spec: addonsv1.ClusterAddonSpec{
+					ClusterName:  "test-cluster",
+					UpgradePhase: phase,
+				},
+				Status: addonsv1.ClusterAddonStatus{
+					Current: &addonsv1.AddonVersionStatus{Version: "v1.0.0", KubernetesVersion: kubernetesVersion},
+					Conditions: clusterv1.Conditions{
+						{Type: clusterv1.ReadyCondition, Status: corev1.ConditionTrue},
+					},
+				},
+			}
+		}
+
+		stableControlPlane := func(version string) *unstructured.Unstructured {
+			return builder.ControlPlane("test-ns", "cp1").
+				WithSpecFields(map[string]interface{}{
+					"spec.version": version,
+				}).
+				WithStatusFields(map[string]interface{}{
+					"status.version": version,
+				}).
+				Build()
+		}
+
+		tests := []struct {
+			name                              string
+			controlPlaneObj                   *unstructured.Unstructured
+			machineDeployments                scope.MachineDeploymentsStateMap
+			addons                            []*addonsv1.ClusterAddon
+			expectedVersion                   string
+			expectedControlPlaneBlockingNames []string
+			expectedWorkersBlockingNames      []string
+		}{
+			{
+				name:            "should pick up the new version if the ClusterAddons upgraded before the control plane are ready",
+				controlPlaneObj: stableControlPlane("v1.2.2"),
+				addons: []*addonsv1.ClusterAddon{
+					readyAddon("cni", addonsv1.AddonUpgradePhaseBeforeControlPlaneUpgrade, "v1.2.3"),
+					readyAddon("csi", addonsv1.AddonUpgradePhaseAfterControlPlaneUpgrade, "v1.2.2"),
+				},
+				expectedVersion: "v1.2.3",
+			},
+			{
+				name:            "should hold the control plane upgrade if the ClusterAddons upgraded before the control plane are not ready",
+				controlPlaneObj: stableControlPlane("v1.2.2"),
+				addons: []*addonsv1.ClusterAddon{
+					readyAddon("cni", addonsv1.AddonUpgradePhaseBeforeControlPlaneUpgrade, "v1.2.2"),
+				},
+				expectedVersion:                   "v1.2.2",
+				expectedControlPlaneBlockingNames: []string{"cni"},
+			},
+			{
+				name:            "should hold the workers upgrade if the ClusterAddons upgraded after the control plane are not ready",
+				controlPlaneObj: stableControlPlane("v1.2.3"),
+				machineDeployments: scope.MachineDeploymentsStateMap{
+					"md1": &scope.MachineDeploymentState{Object: builder.MachineDeployment("test-ns", "md1").WithVersion("v1.2.2").Build()},
+				},
+				addons: []*addonsv1.ClusterAddon{
+					readyAddon("csi", addonsv1.AddonUpgradePhaseAfterControlPlaneUpgrade, "v1.2.2"),
+				},
+				expectedVersion:              "v1.2.3",
+				expectedWorkersBlockingNames: []string{"csi"},
+			},
+			{
+				name:            "should not hold the workers if they are already at the new version",
+				controlPlaneObj: stableControlPlane("v1.2.3"),
+				machineDeployments: scope.MachineDeploymentsStateMap{
+					"md1": &scope.MachineDeploymentState{Object: builder.MachineDeployment("test-ns", "md1").WithVersion("v1.2.3").Build()},
+				},
+				addons: []*addonsv1.ClusterAddon{
+					readyAddon("csi", addonsv1.AddonUpgradePhaseAfterControlPlaneUpgrade, "v1.2.2"),
+				},
+				expectedVersion: "v1.2.3",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				g := NewWithT(t)
+
+				s := &scope.Scope{
+					Blueprint: &scope.ClusterBlueprint{Topology: &clusterv1.Topology{
+						Version: "v1.2.3",
+					}},
+					Current: &scope.ClusterState{
+						Cluster: &clusterv1.Cluster{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "test-cluster",
+								Namespace: "test-ns",
+							},
+						},
+						ControlPlane:       &scope.ControlPlaneState{Object: tt.controlPlaneObj},
+						MachineDeployments: tt.machineDeployments,
+					},
+					UpgradeTracker:      scope.NewUpgradeTracker(),
+					HookResponseTracker: scope.NewHookResponseTracker(),
+				}
+
+				objs := []client.Object{s.Current.Cluster}
+				for _, addon := range tt.addons {
+					objs = append(objs, addon)
+				}
+				fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(objs...).Build()
+
+				r := &Reconciler{
+					Client:    fakeClient,
+					APIReader: fakeClient,
+				}
+				version, err := r.computeControlPlaneVersion(ctx, s)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(version).To(Equal(tt.expectedVersion))
+				g.Expect(s.UpgradeTracker.ClusterAddons.ControlPlaneBlockingNames).To(Equal(tt.expectedControlPlaneBlockingNames))
+				g.Expect(s.UpgradeTracker.ClusterAddons.WorkersBlockingNames).To(Equal(tt.expectedWorkersBlockingNames))
+				// ClusterAddons are not reported as lifecycle hooks.
+				g.Expect(s.HookResponseTracker.AggregateRetryAfter()).To(BeZero())
+			})
+		}
+	})
 }
 
 func TestComputeCluster(t *testing.T) {
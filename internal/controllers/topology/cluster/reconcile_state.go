@@ -45,12 +45,7 @@ import (
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	tlog "sigs.k8s.io/cluster-api/internal/log"
 	"sigs.k8s.io/cluster-api/internal/topology/check"
-)
-
-const (
-	createEventReason = "TopologyCreate"
-	updateEventReason = "TopologyUpdate"
-	deleteEventReason = "TopologyDelete"
+	"sigs.k8s.io/cluster-api/internal/util/events"
 )
 
 // reconcileState reconciles the current and desired state of the managed Cluster topology.
@@ -390,7 +385,7 @@ func (r *Reconciler) reconcileMachineHealthCheck(ctx context.Context, current, d
 		if err := helper.Patch(ctx); err != nil {
 			return errors.Wrapf(err, "failed to create %s", tlog.KObj{Obj: desired})
 		}
-		r.recorder.Eventf(desired, corev1.EventTypeNormal, createEventReason, "Created %q", tlog.KObj{Obj: desired})
+		r.recorder.AnnotatedEventf(desired, events.Annotations(desired.Spec.ClusterName, "", ""), corev1.EventTypeNormal, clusterv1.EventTopologyCreate, "Created %q", tlog.KObj{Obj: desired})
 		return nil
 	}
 
@@ -403,7 +398,7 @@ func (r *Reconciler) reconcileMachineHealthCheck(ctx context.Context, current, d
 				return errors.Wrapf(err, "failed to delete %s", tlog.KObj{Obj: current})
 			}
 		}
-		r.recorder.Eventf(current, corev1.EventTypeNormal, deleteEventReason, "Deleted %q", tlog.KObj{Obj: current})
+		r.recorder.AnnotatedEventf(current, events.Annotations(current.Spec.ClusterName, "", ""), corev1.EventTypeNormal, clusterv1.EventTopologyDelete, "Deleted %q", tlog.KObj{Obj: current})
 		return nil
 	}
 
@@ -425,7 +420,7 @@ func (r *Reconciler) reconcileMachineHealthCheck(ctx context.Context, current, d
 	if err := patchHelper.Patch(ctx); err != nil {
 		return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: current})
 	}
-	r.recorder.Eventf(current, corev1.EventTypeNormal, updateEventReason, "Updated %q", tlog.KObj{Obj: current})
+	r.recorder.AnnotatedEventf(current, events.Annotations(current.Spec.ClusterName, "", ""), corev1.EventTypeNormal, clusterv1.EventTopologyUpdate, "Updated %q", tlog.KObj{Obj: current})
 	return nil
 }
 
@@ -450,7 +445,7 @@ func (r *Reconciler) reconcileCluster(ctx context.Context, s *scope.Scope) error
 	if err := patchHelper.Patch(ctx); err != nil {
 		return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: s.Current.Cluster})
 	}
-	r.recorder.Eventf(s.Current.Cluster, corev1.EventTypeNormal, updateEventReason, "Updated %q", tlog.KObj{Obj: s.Current.Cluster})
+	r.recorder.AnnotatedEventf(s.Current.Cluster, events.Annotations(s.Current.Cluster.Name, "", ""), corev1.EventTypeNormal, clusterv1.EventTopologyUpdate, "Updated %q", tlog.KObj{Obj: s.Current.Cluster})
 
 	// Wait until Cluster is updated in the cache.
 	// Note: We have to do this because otherwise using a cached client in the Reconcile func could
@@ -590,7 +585,7 @@ func (r *Reconciler) createMachineDeployment(ctx context.Context, s *scope.Scope
 	if err := helper.Patch(ctx); err != nil {
 		return createErrorWithoutObjectName(ctx, err, md.Object)
 	}
-	r.recorder.Eventf(cluster, corev1.EventTypeNormal, createEventReason, "Created %q", tlog.KObj{Obj: md.Object})
+	r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "MachineDeployment", md.Object.GetName()), corev1.EventTypeNormal, clusterv1.EventTopologyCreate, "Created %q", tlog.KObj{Obj: md.Object})
 
 	// Wait until MachineDeployment is visible in the cache.
 	// Note: We have to do this because otherwise using a cached client in current state could
@@ -678,7 +673,7 @@ func (r *Reconciler) updateMachineDeployment(ctx context.Context, s *scope.Scope
 	if err := patchHelper.Patch(ctx); err != nil {
 		return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: currentMD.Object})
 	}
-	r.recorder.Eventf(cluster, corev1.EventTypeNormal, updateEventReason, "Updated %q%s", tlog.KObj{Obj: currentMD.Object}, logMachineDeploymentVersionChange(currentMD.Object, desiredMD.Object))
+	r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "MachineDeployment", currentMD.Object.GetName()), corev1.EventTypeNormal, clusterv1.EventTopologyUpdate, "Updated %q%s", tlog.KObj{Obj: currentMD.Object}, logMachineDeploymentVersionChange(currentMD.Object, desiredMD.Object))
 
 	// Wait until MachineDeployment is updated in the cache.
 	// Note: We have to do this because otherwise using a cached client in current state could
@@ -727,7 +722,7 @@ func (r *Reconciler) deleteMachineDeployment(ctx context.Context, cluster *clust
 	if err := r.Client.Delete(ctx, md.Object); err != nil && !apierrors.IsNotFound(err) {
 		return errors.Wrapf(err, "failed to delete %s", tlog.KObj{Obj: md.Object})
 	}
-	r.recorder.Eventf(cluster, corev1.EventTypeNormal, deleteEventReason, "Deleted %q", tlog.KObj{Obj: md.Object})
+	r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "MachineDeployment", md.Object.GetName()), corev1.EventTypeNormal, clusterv1.EventTopologyDelete, "Deleted %q", tlog.KObj{Obj: md.Object})
 	return nil
 }
 
@@ -848,7 +843,7 @@ func (r *Reconciler) createMachinePool(ctx context.Context, s *scope.Scope, mp *
 	if err := helper.Patch(ctx); err != nil {
 		return createErrorWithoutObjectName(ctx, err, mp.Object)
 	}
-	r.recorder.Eventf(cluster, corev1.EventTypeNormal, createEventReason, "Created %q", tlog.KObj{Obj: mp.Object})
+	r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "MachinePool", mp.Object.GetName()), corev1.EventTypeNormal, clusterv1.EventTopologyCreate, "Created %q", tlog.KObj{Obj: mp.Object})
 
 	// Wait until MachinePool is visible in the cache.
 	// Note: We have to do this because otherwise using a cached client in current state could
@@ -915,7 +910,7 @@ func (r *Reconciler) updateMachinePool(ctx context.Context, s *scope.Scope, curr
 	if err := patchHelper.Patch(ctx); err != nil {
 		return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: currentMP.Object})
 	}
-	r.recorder.Eventf(cluster, corev1.EventTypeNormal, updateEventReason, "Updated %q%s", tlog.KObj{Obj: currentMP.Object}, logMachinePoolVersionChange(currentMP.Object, desiredMP.Object))
+	r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "MachinePool", currentMP.Object.GetName()), corev1.EventTypeNormal, clusterv1.EventTopologyUpdate, "Updated %q%s", tlog.KObj{Obj: currentMP.Object}, logMachinePoolVersionChange(currentMP.Object, desiredMP.Object))
 
 	// Wait until MachinePool is updated in the cache.
 	// Note: We have to do this because otherwise using a cached client in current state could
@@ -957,7 +952,7 @@ func (r *Reconciler) deleteMachinePool(ctx context.Context, cluster *clusterv1.C
 	if err := r.Client.Delete(ctx, mp.Object); err != nil && !apierrors.IsNotFound(err) {
 		return errors.Wrapf(err, "failed to delete %s", tlog.KObj{Obj: mp.Object})
 	}
-	r.recorder.Eventf(cluster, corev1.EventTypeNormal, deleteEventReason, "Deleted %q", tlog.KObj{Obj: mp.Object})
+	r.recorder.AnnotatedEventf(cluster, events.Annotations(cluster.Name, "MachinePool", mp.Object.GetName()), corev1.EventTypeNormal, clusterv1.EventTopologyDelete, "Deleted %q", tlog.KObj{Obj: mp.Object})
 	return nil
 }
 
@@ -1035,7 +1030,7 @@ func (r *Reconciler) reconcileReferencedObject(ctx context.Context, in reconcile
 		if err := helper.Patch(ctx); err != nil {
 			return createErrorWithoutObjectName(ctx, err, in.desired)
 		}
-		r.recorder.Eventf(in.cluster, corev1.EventTypeNormal, createEventReason, "Created %q", tlog.KObj{Obj: in.desired})
+		r.recorder.AnnotatedEventf(in.cluster, events.Annotations(in.cluster.Name, in.desired.GetKind(), in.desired.GetName()), corev1.EventTypeNormal, clusterv1.EventTopologyCreate, "Created %q", tlog.KObj{Obj: in.desired})
 		return nil
 	}
 
@@ -1058,7 +1053,7 @@ func (r *Reconciler) reconcileReferencedObject(ctx context.Context, in reconcile
 	if err := patchHelper.Patch(ctx); err != nil {
 		return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: in.current})
 	}
-	r.recorder.Eventf(in.cluster, corev1.EventTypeNormal, updateEventReason, "Updated %q%s", tlog.KObj{Obj: in.desired}, logUnstructuredVersionChange(in.current, in.desired, in.versionGetter))
+	r.recorder.AnnotatedEventf(in.cluster, events.Annotations(in.cluster.Name, in.desired.GetKind(), in.desired.GetName()), corev1.EventTypeNormal, clusterv1.EventTopologyUpdate, "Updated %q%s", tlog.KObj{Obj: in.desired}, logUnstructuredVersionChange(in.current, in.desired, in.versionGetter))
 	return nil
 }
 
@@ -1112,7 +1107,7 @@ func (r *Reconciler) reconcileReferencedTemplate(ctx context.Context, in reconci
 		if err := helper.Patch(ctx); err != nil {
 			return createErrorWithoutObjectName(ctx, err, in.desired)
 		}
-		r.recorder.Eventf(in.cluster, corev1.EventTypeNormal, createEventReason, "Created %q", tlog.KObj{Obj: in.desired})
+		r.recorder.AnnotatedEventf(in.cluster, events.Annotations(in.cluster.Name, in.desired.GetKind(), in.desired.GetName()), corev1.EventTypeNormal, clusterv1.EventTopologyCreate, "Created %q", tlog.KObj{Obj: in.desired})
 		return nil
 	}
 
@@ -1144,7 +1139,7 @@ func (r *Reconciler) reconcileReferencedTemplate(ctx context.Context, in reconci
 		if err := patchHelper.Patch(ctx); err != nil {
 			return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: in.desired})
 		}
-		r.recorder.Eventf(in.cluster, corev1.EventTypeNormal, updateEventReason, "Updated %q (metadata changes)", tlog.KObj{Obj: in.desired})
+		r.recorder.AnnotatedEventf(in.cluster, events.Annotations(in.cluster.Name, in.desired.GetKind(), in.desired.GetName()), corev1.EventTypeNormal, clusterv1.EventTopologyUpdate, "Updated %q (metadata changes)", tlog.KObj{Obj: in.desired})
 		return nil
 	}
 
@@ -1164,7 +1159,7 @@ func (r *Reconciler) reconcileReferencedTemplate(ctx context.Context, in reconci
 	if err := helper.Patch(ctx); err != nil {
 		return createErrorWithoutObjectName(ctx, err, in.desired)
 	}
-	r.recorder.Eventf(in.cluster, corev1.EventTypeNormal, createEventReason, "Created %q as a replacement for %q (template rotation)", tlog.KObj{Obj: in.desired}, in.ref.Name)
+	r.recorder.AnnotatedEventf(in.cluster, events.Annotations(in.cluster.Name, in.desired.GetKind(), in.desired.GetName()), corev1.EventTypeNormal, clusterv1.EventTopologyCreate, "Created %q as a replacement for %q (template rotation)", tlog.KObj{Obj: in.desired}, in.ref.Name)
 
 	// Update the reference with the new name.
 	// NOTE: Updating the object hosting reference to the template is executed outside this func.
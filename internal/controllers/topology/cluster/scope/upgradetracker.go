@@ -23,6 +23,7 @@ type UpgradeTracker struct {
 	ControlPlane       ControlPlaneUpgradeTracker
 	MachineDeployments WorkerUpgradeTracker
 	MachinePools       WorkerUpgradeTracker
+	ClusterAddons      ClusterAddonsUpgradeTracker
 }
 
 // ControlPlaneUpgradeTracker holds the current upgrade status of the Control Plane.
@@ -65,6 +66,29 @@ type ControlPlaneUpgradeTracker struct {
 	IsScaling bool
 }
 
+// ClusterAddonsUpgradeTracker holds the ClusterAddons the upgrade of the Cluster is waiting for.
+type ClusterAddonsUpgradeTracker struct {
+	// ControlPlaneBlockingNames is the list of the names of the ClusterAddons upgraded before the Control Plane
+	// which are not ready for the new version; the Control Plane does not pick up the new version until they are ready.
+	ControlPlaneBlockingNames []string
+
+	// WorkersBlockingNames is the list of the names of the ClusterAddons upgraded after the Control Plane
+	// which are not ready for the new version; MachineDeployments/MachinePools are not created and do not pick up
+	// the new version until they are ready.
+	WorkersBlockingNames []string
+}
+
+// IsBlockingControlPlane returns true if ClusterAddons are blocking the upgrade of the Control Plane.
+func (t *ClusterAddonsUpgradeTracker) IsBlockingControlPlane() bool {
+	return len(t.ControlPlaneBlockingNames) > 0
+}
+
+// IsBlockingWorkers returns true if ClusterAddons are blocking the creation and the upgrade of
+// MachineDeployments/MachinePools.
+func (t *ClusterAddonsUpgradeTracker) IsBlockingWorkers() bool {
+	return len(t.WorkersBlockingNames) > 0
+}
+
 // WorkerUpgradeTracker holds the current upgrade status of MachineDeployments or MachinePools.
 type WorkerUpgradeTracker struct {
 	// pendingCreateTopologyNames is the set of MachineDeployment/MachinePool topology names that are newly added to the
@@ -50,6 +50,7 @@ import (
 	runtimemetrics "sigs.k8s.io/cluster-api/internal/runtime/metrics"
 	runtimeregistry "sigs.k8s.io/cluster-api/internal/runtime/registry"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/tracing"
 )
 
 type errCallingExtensionHandler error
@@ -288,9 +289,14 @@ func aggregateSuccessfulResponses(aggregatedResponse runtimehooksv1.ResponseObje
 // Nb. FailurePolicy does not affect the following kinds of errors:
 // - Internal errors. Examples: hooks is incompatible with ExtensionHandler, ExtensionHandler information is missing.
 // - Error when ExtensionHandler returns a response with `Status` set to `Failure`.
-func (c *client) CallExtension(ctx context.Context, hook runtimecatalog.Hook, forObject metav1.Object, name string, request runtimehooksv1.RequestObject, response runtimehooksv1.ResponseObject) error {
+func (c *client) CallExtension(ctx context.Context, hook runtimecatalog.Hook, forObject metav1.Object, name string, request runtimehooksv1.RequestObject, response runtimehooksv1.ResponseObject) (reterr error) {
 	log := ctrl.LoggerFrom(ctx).WithValues("extensionHandler", name, "hook", runtimecatalog.HookName(hook))
 	ctx = ctrl.LoggerInto(ctx, log)
+	ctx, span := tracing.Start(ctx, "Call "+runtimecatalog.HookName(hook),
+		tracing.HookKey.String(runtimecatalog.HookName(hook)),
+		tracing.ExtensionHandlerKey.String(name),
+	)
+	defer func() { tracing.End(span, reterr) }()
 	hookGVH, err := c.catalog.GroupVersionHook(hook)
 	if err != nil {
 		return errors.Wrapf(err, "failed to call extension handler %q: failed to compute GroupVersionHook", name)
@@ -487,9 +493,10 @@ func httpCall(ctx context.Context, request, response runtime.Object, opts *httpC
 		return errors.Wrap(err, "http call failed: failed to create tls config")
 	}
 	// This also adds http2
-	client.Transport = utilnet.SetTransportDefaults(&http.Transport{
+	// The transport is wrapped to propagate the trace context to the extension.
+	client.Transport = tracing.WrapTransport(utilnet.SetTransportDefaults(&http.Transport{
 		TLSClientConfig: tlsConfig,
-	})
+	}))
 
 	resp, err := client.Do(httpRequest)
 
@@ -0,0 +1,37 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package events implements event helper functions.
+package events
+
+import (
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// Annotations returns the annotations of an event recorded on an object of a Cluster, referencing the Cluster and,
+// if kind and name are not empty, the object the event is about when different from the object the event is
+// recorded on, e.g. the Machine created by a MachineSet.
+func Annotations(clusterName, kind, name string) map[string]string {
+	annotations := map[string]string{}
+	if clusterName != "" {
+		annotations[clusterv1.ClusterNameLabel] = clusterName
+	}
+	if kind != "" && name != "" {
+		annotations[clusterv1.EventRelatedObjectKindAnnotation] = kind
+		annotations[clusterv1.EventRelatedObjectNameAnnotation] = name
+	}
+	return annotations
+}
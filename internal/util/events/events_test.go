@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterName string
+		kind        string
+		objName     string
+		want        map[string]string
+	}{
+		{
+			name:        "Cluster and related object",
+			clusterName: "cluster1",
+			kind:        "Machine",
+			objName:     "machine1",
+			want: map[string]string{
+				clusterv1.ClusterNameLabel:                 "cluster1",
+				clusterv1.EventRelatedObjectKindAnnotation: "Machine",
+				clusterv1.EventRelatedObjectNameAnnotation: "machine1",
+			},
+		},
+		{
+			name:        "Cluster only",
+			clusterName: "cluster1",
+			want: map[string]string{
+				clusterv1.ClusterNameLabel: "cluster1",
+			},
+		},
+		{
+			name:    "Related object without name is ignored",
+			kind:    "Machine",
+			want:    map[string]string{},
+			objName: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(Annotations(tt.clusterName, tt.kind, tt.objName)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rollout implements utilities to record the changes triggering a rollout of Machines
+// in the rollout history of the objects owning them.
+package rollout
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// RecordTrigger adds a trigger at the beginning of a rollout history, dropping the oldest triggers
+// if the history has more than clusterv1.MaxRolloutHistory triggers.
+func RecordTrigger(history []clusterv1.RolloutTrigger, trigger clusterv1.RolloutTrigger) []clusterv1.RolloutTrigger {
+	history = append([]clusterv1.RolloutTrigger{trigger}, history...)
+	if len(history) > clusterv1.MaxRolloutHistory {
+		history = history[:clusterv1.MaxRolloutHistory]
+	}
+	return history
+}
+
+// ChangedFieldPaths returns the paths of the fields which are different between two objects, e.g.
+// spec.template.spec.version, sorted alphabetically; prefix is prepended to all the paths.
+// NOTE: Lists are compared as a whole, so the path of a changed list is the path of the list.
+func ChangedFieldPaths(prefix string, oldObj, newObj interface{}) ([]string, error) {
+	oldMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(oldObj)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert the old object to unstructured")
+	}
+	newMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newObj)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert the new object to unstructured")
+	}
+
+	paths := []string{}
+	changedFieldPaths(prefix, oldMap, newMap, &paths)
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func changedFieldPaths(path string, oldMap, newMap map[string]interface{}, paths *[]string) {
+	keys := map[string]bool{}
+	for k := range oldMap {
+		keys[k] = true
+	}
+	for k := range newMap {
+		keys[k] = true
+	}
+
+	for k := range keys {
+		fieldPath := k
+		if path != "" {
+			fieldPath = path + "." + k
+		}
+
+		oldValue, newValue := oldMap[k], newMap[k]
+		oldFields, oldIsMap := oldValue.(map[string]interface{})
+		newFields, newIsMap := newValue.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			changedFieldPaths(fieldPath, oldFields, newFields, paths)
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			*paths = append(*paths, fieldPath)
+		}
+	}
+}
+
+// HasFieldPath returns true if any of the field paths is the given path, or a path of one of its fields.
+func HasFieldPath(fieldPaths []string, path string) bool {
+	for _, fieldPath := range fieldPaths {
+		if fieldPath == path || strings.HasPrefix(fieldPath, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Manager returns the field manager which last changed any of the fields with the given paths, e.g.
+// spec.template.spec.version, according to the managed fields of an object.
+// It returns an empty string if none of the fields is managed.
+// NOTE: The managed fields record the last time a manager changed any field, so this is the most likely
+// manager of the change, not necessarily the actual one.
+func Manager(obj metav1.Object, fieldPaths []string) string {
+	manager := ""
+	lastTime := metav1.Time{}
+	for _, entry := range obj.GetManagedFields() {
+		// Ignore changes to subresources, e.g. status, which do not trigger rollouts.
+		if entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		if !managesAny(fields, fieldPaths) {
+			continue
+		}
+
+		entryTime := metav1.Time{}
+		if entry.Time != nil {
+			entryTime = *entry.Time
+		}
+		if manager == "" || lastTime.Before(&entryTime) {
+			manager = entry.Manager
+			lastTime = entryTime
+		}
+	}
+	return manager
+}
+
+// managesAny returns true if the fields of a managed fields entry include any of the given paths.
+func managesAny(fields map[string]interface{}, fieldPaths []string) bool {
+	for _, fieldPath := range fieldPaths {
+		current := fields
+		found := true
+		for _, key := range strings.Split(fieldPath, ".") {
+			next, ok := current["f:"+key].(map[string]interface{})
+			if !ok {
+				found = false
+				break
+			}
+			current = next
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
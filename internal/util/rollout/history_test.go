@@ -0,0 +1,162 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestRecordTrigger(t *testing.T) {
+	g := NewWithT(t)
+
+	var history []clusterv1.RolloutTrigger
+	for i := 0; i < clusterv1.MaxRolloutHistory+2; i++ {
+		history = RecordTrigger(history, clusterv1.RolloutTrigger{NewHash: fmt.Sprintf("%d", i)})
+	}
+
+	// The most recent triggers are kept, most recent first.
+	g.Expect(history).To(HaveLen(clusterv1.MaxRolloutHistory))
+	g.Expect(history[0].NewHash).To(Equal(fmt.Sprintf("%d", clusterv1.MaxRolloutHistory+1)))
+	g.Expect(history[clusterv1.MaxRolloutHistory-1].NewHash).To(Equal("2"))
+}
+
+func TestChangedFieldPaths(t *testing.T) {
+	template := func(version string, infrastructureRef string, labels map[string]string) *clusterv1.MachineTemplateSpec {
+		return &clusterv1.MachineTemplateSpec{
+			ObjectMeta: clusterv1.ObjectMeta{Labels: labels},
+			Spec: clusterv1.MachineSpec{
+				Version:           pointer.String(version),
+				InfrastructureRef: corev1.ObjectReference{Kind: "InfrastructureMachineTemplate", Name: infrastructureRef},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		oldObj *clusterv1.MachineTemplateSpec
+		newObj *clusterv1.MachineTemplateSpec
+		want   []string
+	}{
+		{
+			name:   "no changes",
+			oldObj: template("v1.28.0", "infra-1", nil),
+			newObj: template("v1.28.0", "infra-1", nil),
+			want:   []string{},
+		},
+		{
+			name:   "changed fields",
+			oldObj: template("v1.28.0", "infra-1", nil),
+			newObj: template("v1.29.0", "infra-2", nil),
+			want:   []string{"spec.template.spec.infrastructureRef.name", "spec.template.spec.version"},
+		},
+		{
+			name:   "added fields",
+			oldObj: template("v1.28.0", "infra-1", nil),
+			newObj: template("v1.28.0", "infra-1", map[string]string{"foo": "bar"}),
+			want:   []string{"spec.template.metadata.labels"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := ChangedFieldPaths("spec.template", tt.oldObj, tt.newObj)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestHasFieldPath(t *testing.T) {
+	g := NewWithT(t)
+
+	fieldPaths := []string{"spec.template.spec.infrastructureRef.name", "spec.version"}
+	g.Expect(HasFieldPath(fieldPaths, "spec.template.spec.infrastructureRef")).To(BeTrue())
+	g.Expect(HasFieldPath(fieldPaths, "spec.version")).To(BeTrue())
+	g.Expect(HasFieldPath(fieldPaths, "spec.ver")).To(BeFalse())
+	g.Expect(HasFieldPath(fieldPaths, "spec.template.spec.bootstrap")).To(BeFalse())
+}
+
+func TestManager(t *testing.T) {
+	now := time.Now()
+	entry := func(manager, subresource string, t time.Time, fields string) metav1.ManagedFieldsEntry {
+		entryTime := metav1.NewTime(t)
+		return metav1.ManagedFieldsEntry{
+			Manager:     manager,
+			Operation:   metav1.ManagedFieldsOperationUpdate,
+			Subresource: subresource,
+			Time:        &entryTime,
+			FieldsType:  "FieldsV1",
+			FieldsV1:    &metav1.FieldsV1{Raw: []byte(fields)},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		managedFields []metav1.ManagedFieldsEntry
+		fieldPaths    []string
+		want          string
+	}{
+		{
+			name: "no managed fields",
+		},
+		{
+			name: "the manager of the field",
+			managedFields: []metav1.ManagedFieldsEntry{
+				entry("capi-topology", "", now, `{"f:spec":{"f:replicas":{}}}`),
+				entry("kubectl-edit", "", now.Add(-time.Minute), `{"f:spec":{"f:version":{}}}`),
+			},
+			fieldPaths: []string{"spec.version"},
+			want:       "kubectl-edit",
+		},
+		{
+			name: "the manager which changed one of the fields last",
+			managedFields: []metav1.ManagedFieldsEntry{
+				entry("capi-topology", "", now.Add(-time.Minute), `{"f:spec":{"f:version":{}}}`),
+				entry("kubectl-edit", "", now, `{"f:spec":{"f:kubeadmConfigSpec":{"f:files":{}}}}`),
+			},
+			fieldPaths: []string{"spec.version", "spec.kubeadmConfigSpec"},
+			want:       "kubectl-edit",
+		},
+		{
+			name: "managers of subresources are ignored",
+			managedFields: []metav1.ManagedFieldsEntry{
+				entry("capi-topology", "", now.Add(-time.Minute), `{"f:spec":{"f:version":{}}}`),
+				entry("manager", "status", now, `{"f:spec":{"f:version":{}}}`),
+			},
+			fieldPaths: []string{"spec.version"},
+			want:       "capi-topology",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &clusterv1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{ManagedFields: tt.managedFields}}
+			g.Expect(Manager(obj, tt.fieldPaths)).To(Equal(tt.want))
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -103,6 +104,11 @@ func (webhook *Cluster) Default(ctx context.Context, obj runtime.Object) error {
 		// different in the defaulting and validating webhook.
 		allErrs = append(allErrs, DefaultAndValidateVariables(cluster, clusterClass)...)
 
+		// Default the image registry from the ClusterClass if not set on the Cluster.
+		if cluster.Spec.ImageRegistry == nil && clusterClass.Spec.ImageRegistry != nil {
+			cluster.Spec.ImageRegistry = clusterClass.Spec.ImageRegistry.DeepCopy()
+		}
+
 		if len(allErrs) > 0 {
 			return apierrors.NewInvalid(clusterv1.GroupVersion.WithKind("Cluster").GroupKind(), cluster.Name, allErrs)
 		}
@@ -188,6 +194,10 @@ func (webhook *Cluster) validate(ctx context.Context, oldCluster, newCluster *cl
 		}
 	}
 
+	if newCluster.Spec.Tunnel != nil {
+		allErrs = append(allErrs, validateTunnel(specPath.Child("tunnel"), newCluster.Spec.Tunnel)...)
+	}
+
 	topologyPath := specPath.Child("topology")
 
 	// Validate the managed topology, if defined.
@@ -195,6 +205,9 @@ func (webhook *Cluster) validate(ctx context.Context, oldCluster, newCluster *cl
 		topologyWarnings, topologyErrs := webhook.validateTopology(ctx, oldCluster, newCluster, topologyPath)
 		allWarnings = append(allWarnings, topologyWarnings...)
 		allErrs = append(allErrs, topologyErrs...)
+		if len(topologyErrs) == 0 {
+			allWarnings = append(allWarnings, webhook.topologyWarnings(ctx, oldCluster, newCluster, topologyPath)...)
+		}
 	}
 
 	// On update.
@@ -395,6 +408,43 @@ func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newClu
 	return allWarnings, allErrs
 }
 
+// topologyWarnings returns the warnings for a valid managed topology, i.e. for deprecated fields, for rolling update
+// strategies allowing all the Machines of a MachineDeployment to be unavailable and, on upgrade, for MachineDeployments
+// which are going to be at or outside the limits of the Kubernetes version skew policy.
+func (webhook *Cluster) topologyWarnings(ctx context.Context, oldCluster, newCluster *clusterv1.Cluster, fldPath *field.Path) admission.Warnings {
+	var allWarnings admission.Warnings
+
+	if newCluster.Spec.Topology.RolloutAfter != nil {
+		allWarnings = append(allWarnings,
+			fmt.Sprintf("%s is deprecated: the field has no function and is going to be removed in the next apiVersion", fldPath.Child("rolloutAfter")),
+		)
+	}
+
+	if newCluster.Spec.Topology.Workers != nil {
+		for i, md := range newCluster.Spec.Topology.Workers.MachineDeployments {
+			allWarnings = append(allWarnings, rollingUpdateWarnings(md.Strategy, md.Replicas, fldPath.Child("workers", "machineDeployments").Index(i).Child("strategy"))...)
+		}
+	}
+
+	// On upgrade, check the version skew between the new version of the control plane and the MachineDeployments.
+	if oldCluster == nil || oldCluster.Spec.Topology == nil || oldCluster.Spec.Topology.Version == newCluster.Spec.Topology.Version {
+		return allWarnings
+	}
+	mds := &clusterv1.MachineDeploymentList{}
+	if err := webhook.Client.List(ctx, mds, client.InNamespace(newCluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: newCluster.Name}); err != nil {
+		// NOTE: Failures listing the MachineDeployments are ignored given that warnings are best effort.
+		ctrl.LoggerFrom(ctx).V(4).Info("Skipping Kubernetes version skew warning", "err", err.Error())
+		return allWarnings
+	}
+	for _, md := range mds.Items {
+		if md.Spec.Template.Spec.Version == nil {
+			continue
+		}
+		allWarnings = append(allWarnings, kubeletVersionSkewWarnings(newCluster.Spec.Topology.Version, *md.Spec.Template.Spec.Version, fldPath.Child("version"), fmt.Sprintf("MachineDeployment %s", md.Name))...)
+	}
+	return allWarnings
+}
+
 func validateMachineHealthChecks(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -491,6 +541,25 @@ func validateCIDRBlocks(fldPath *field.Path, cidrs []string) field.ErrorList {
 	return allErrs
 }
 
+// validateTunnel validates the tunnel used to reach the API server of a Cluster.
+func validateTunnel(fldPath *field.Path, tunnel *clusterv1.ClusterTunnel) field.ErrorList {
+	var allErrs field.ErrorList
+	proxyURL, err := url.Parse(tunnel.ProxyURL)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("proxyURL"), tunnel.ProxyURL, err.Error()))
+	} else if (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || proxyURL.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("proxyURL"), tunnel.ProxyURL, "must be an http or https URL"))
+	}
+	if tunnel.Agent != nil {
+		if _, port, err := net.SplitHostPort(tunnel.Agent.ServerAddress); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("agent", "serverAddress"), tunnel.Agent.ServerAddress, err.Error()))
+		} else if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("agent", "serverAddress"), tunnel.Agent.ServerAddress, "must have a valid port"))
+		}
+	}
+	return allErrs
+}
+
 // DefaultAndValidateVariables defaults and validates variables in the Cluster and MachineDeployment/MachinePool topologies based
 // on the definitions in the ClusterClass.
 func DefaultAndValidateVariables(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
@@ -26,6 +26,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -808,9 +809,69 @@ func TestClusterDefaultTopologyVersion(t *testing.T) {
 	g.Expect(c.Spec.Topology.Version).To(HavePrefix("v"))
 }
 
+func TestClusterDefaultImageRegistry(t *testing.T) {
+	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to set Cluster.Topologies.
+	// Enabling the feature flag temporarily for this test.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	classImageRegistry := &clusterv1.ImageRegistry{
+		Repository: "registry.example.com/class",
+		Digests: &clusterv1.ImageDigests{
+			Etcd: "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		imageRegistry *clusterv1.ImageRegistry
+		want          *clusterv1.ImageRegistry
+	}{
+		{
+			name: "defaults the image registry from the ClusterClass",
+			want: classImageRegistry,
+		},
+		{
+			name:          "preserves the image registry of the Cluster",
+			imageRegistry: &clusterv1.ImageRegistry{Repository: "registry.example.com/cluster"},
+			want:          &clusterv1.ImageRegistry{Repository: "registry.example.com/cluster"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.19.1").
+					Build()).
+				Build()
+			c.Spec.ImageRegistry = tt.imageRegistry
+
+			clusterClass := builder.ClusterClass("fooboo", "foo").Build()
+			clusterClass.Spec.ImageRegistry = classImageRegistry
+			conditions.MarkTrue(clusterClass, clusterv1.ClusterClassVariablesReconciledCondition)
+			fakeClient := fake.NewClientBuilder().
+				WithObjects(clusterClass).
+				WithScheme(fakeScheme).
+				Build()
+
+			webhook := &Cluster{Client: fakeClient}
+			g.Expect(webhook.Default(ctx, c)).To(Succeed())
+			g.Expect(c.Spec.ImageRegistry).To(BeComparableTo(tt.want))
+		})
+	}
+}
+
 func TestClusterValidation(t *testing.T) {
 	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to set Cluster.Topologies.
 
+	clusterWithTunnel := func(tunnel *clusterv1.ClusterTunnel) *clusterv1.Cluster {
+		cluster := builder.Cluster("fooNamespace", "cluster1").Build()
+		cluster.Spec.Tunnel = tunnel
+		return cluster
+	}
+
 	var (
 		tests = []struct {
 			name      string
@@ -998,6 +1059,27 @@ func TestClusterValidation(t *testing.T) {
 				in:        builder.Cluster("fooNamespace", "thisNameContainsInvalid!@NonAlphanumerics").Build(),
 				expectErr: true,
 			},
+			{
+				name:      "pass with a valid tunnel",
+				expectErr: false,
+				in: clusterWithTunnel(&clusterv1.ClusterTunnel{
+					ProxyURL: "http://konnectivity-server.tunnel-system.svc:8090",
+					Agent:    &clusterv1.ClusterTunnelAgent{ServerAddress: "tunnel.example.com:8091"},
+				}),
+			},
+			{
+				name:      "fails if the tunnel proxy URL is not an http URL",
+				expectErr: true,
+				in:        clusterWithTunnel(&clusterv1.ClusterTunnel{ProxyURL: "konnectivity-server:8090"}),
+			},
+			{
+				name:      "fails if the tunnel agent server address has no port",
+				expectErr: true,
+				in: clusterWithTunnel(&clusterv1.ClusterTunnel{
+					ProxyURL: "http://konnectivity-server.tunnel-system.svc:8090",
+					Agent:    &clusterv1.ClusterTunnelAgent{ServerAddress: "tunnel.example.com"},
+				}),
+			},
 		}
 	)
 	for _, tt := range tests {
@@ -2321,6 +2403,114 @@ func TestClusterClassPollingErrors(t *testing.T) {
 	}
 }
 
+func TestClusterTopologyWarnings(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	ref := &corev1.ObjectReference{
+		APIVersion: "group.test.io/foo",
+		Kind:       "barTemplate",
+		Name:       "baz",
+		Namespace:  "default",
+	}
+	clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").
+		WithInfrastructureClusterTemplate(refToUnstructured(ref)).
+		WithControlPlaneTemplate(refToUnstructured(ref)).
+		WithControlPlaneInfrastructureMachineTemplate(refToUnstructured(ref)).
+		WithWorkerMachineDeploymentClasses(
+			*builder.MachineDeploymentClass("default-worker").
+				WithInfrastructureTemplate(refToUnstructured(ref)).
+				WithBootstrapTemplate(refToUnstructured(ref)).
+				Build(),
+		).
+		Build()
+	conditions.MarkTrue(clusterClass, clusterv1.ClusterClassVariablesReconciledCondition)
+
+	md := builder.MachineDeployment(metav1.NamespaceDefault, "md1").
+		WithClusterName("cluster1").
+		WithLabels(map[string]string{clusterv1.ClusterNameLabel: "cluster1"}).
+		WithVersion("v1.25.3").
+		Build()
+
+	maxUnavailable := intstr.FromString("100%")
+	workers := clusterv1.MachineDeploymentTopology{
+		Class:    "default-worker",
+		Name:     "md1",
+		Replicas: pointer.Int32(3),
+		Strategy: &clusterv1.MachineDeploymentStrategy{
+			Type:          clusterv1.RollingUpdateMachineDeploymentStrategyType,
+			RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{MaxUnavailable: &maxUnavailable},
+		},
+	}
+	rolloutAfter := builder.ClusterTopology().WithClass("class1").WithVersion("v1.27.3").Build()
+	rolloutAfter.RolloutAfter = &metav1.Time{}
+
+	tests := []struct {
+		name         string
+		cluster      *clusterv1.Cluster
+		oldCluster   *clusterv1.Cluster
+		wantWarnings []string
+	}{
+		{
+			name: "no warnings",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.27.3").Build()).
+				Build(),
+		},
+		{
+			name: "warning for the deprecated rolloutAfter field",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(rolloutAfter).
+				Build(),
+			wantWarnings: []string{"spec.topology.rolloutAfter is deprecated"},
+		},
+		{
+			name: "warning for maxUnavailable 100%",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.27.3").WithMachineDeployment(workers).Build()).
+				Build(),
+			wantWarnings: []string{"spec.topology.workers.machineDeployments[0].strategy.rollingUpdate.maxUnavailable"},
+		},
+		{
+			name: "no warning for version skew within the limits on upgrade",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.26.3").Build()).
+				Build(),
+			oldCluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.25.3").Build()).
+				Build(),
+		},
+		{
+			name: "warning for version skew at the limit on upgrade",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.27.3").Build()).
+				Build(),
+			oldCluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.26.3").Build()).
+				Build(),
+			wantWarnings: []string{"spec.topology.version: MachineDeployment md1 version v1.25.3 is 2 minor versions older"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			webhook := &Cluster{Client: fake.NewClientBuilder().
+				WithScheme(fakeScheme).
+				WithObjects(clusterClass, md).
+				Build(),
+			}
+
+			warnings, err := webhook.validate(ctx, tt.oldCluster, tt.cluster)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(warnings).To(HaveLen(len(tt.wantWarnings)))
+			for i := range tt.wantWarnings {
+				g.Expect(warnings[i]).To(HavePrefix(tt.wantWarnings[i]))
+			}
+		})
+	}
+}
+
 func refToUnstructured(ref *corev1.ObjectReference) *unstructured.Unstructured {
 	gvk := ref.GetObjectKind().GroupVersionKind()
 	output := &unstructured.Unstructured{}
@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -59,6 +60,12 @@ func (webhook *MachineDeployment) SetupWebhookWithManager(mgr ctrl.Manager) erro
 // MachineDeployment implements a validation and defaulting webhook for MachineDeployment.
 type MachineDeployment struct {
 	Decoder *admission.Decoder
+
+	// Client is used to read the version of the control plane of the Cluster, for warning about the Kubernetes
+	// version skew; the warning is skipped if Client is not set.
+	// NOTE: The control plane is read as unstructured object, so Client should cache unstructured objects to
+	// avoid reading it from the API server at every request.
+	Client client.Reader
 }
 
 var _ webhook.CustomDefaulter = &MachineDeployment{}
@@ -162,17 +169,17 @@ func (webhook *MachineDeployment) Default(ctx context.Context, obj runtime.Objec
 }
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
-func (webhook *MachineDeployment) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (webhook *MachineDeployment) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	m, ok := obj.(*clusterv1.MachineDeployment)
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a MachineDeployment but got a %T", obj))
 	}
 
-	return nil, webhook.validate(nil, m)
+	return webhook.validate(ctx, nil, m)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *MachineDeployment) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+func (webhook *MachineDeployment) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	oldMD, ok := oldObj.(*clusterv1.MachineDeployment)
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a MachineDeployment but got a %T", oldObj))
@@ -183,7 +190,7 @@ func (webhook *MachineDeployment) ValidateUpdate(_ context.Context, oldObj, newO
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a MachineDeployment but got a %T", newObj))
 	}
 
-	return nil, webhook.validate(oldMD, newMD)
+	return webhook.validate(ctx, oldMD, newMD)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -191,7 +198,7 @@ func (webhook *MachineDeployment) ValidateDelete(_ context.Context, _ runtime.Ob
 	return nil, nil
 }
 
-func (webhook *MachineDeployment) validate(oldMD, newMD *clusterv1.MachineDeployment) error {
+func (webhook *MachineDeployment) validate(ctx context.Context, oldMD, newMD *clusterv1.MachineDeployment) (admission.Warnings, error) {
 	var allErrs field.ErrorList
 	// The MachineDeployment name is used as a label value. This check ensures names which are not be valid label values are rejected.
 	if errs := validation.IsValidLabelValue(newMD.Name); len(errs) != 0 {
@@ -277,11 +284,29 @@ func (webhook *MachineDeployment) validate(oldMD, newMD *clusterv1.MachineDeploy
 	// Validate the metadata of the template.
 	allErrs = append(allErrs, newMD.Spec.Template.ObjectMeta.Validate(specPath.Child("template", "metadata"))...)
 
-	if len(allErrs) == 0 {
-		return nil
+	if len(allErrs) != 0 {
+		return nil, apierrors.NewInvalid(clusterv1.GroupVersion.WithKind("MachineDeployment").GroupKind(), newMD.Name, allErrs)
 	}
 
-	return apierrors.NewInvalid(clusterv1.GroupVersion.WithKind("MachineDeployment").GroupKind(), newMD.Name, allErrs)
+	return webhook.warnings(ctx, newMD), nil
+}
+
+// warnings returns the warnings for a valid MachineDeployment.
+func (webhook *MachineDeployment) warnings(ctx context.Context, md *clusterv1.MachineDeployment) admission.Warnings {
+	specPath := field.NewPath("spec")
+	allWarnings := rollingUpdateWarnings(md.Spec.Strategy, md.Spec.Replicas, specPath.Child("strategy"))
+
+	if webhook.Client == nil || md.Spec.Template.Spec.Version == nil {
+		return allWarnings
+	}
+	// NOTE: Failures reading the version of the control plane, e.g. because the Cluster doesn't exist yet, are
+	// ignored given that warnings are best effort.
+	controlPlaneVersion, err := getControlPlaneVersion(ctx, webhook.Client, md.Namespace, md.Spec.ClusterName)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).V(4).Info("Skipping Kubernetes version skew warning", "err", err.Error())
+		return allWarnings
+	}
+	return append(allWarnings, kubeletVersionSkewWarnings(controlPlaneVersion, *md.Spec.Template.Spec.Version, specPath.Child("template", "spec", "version"), "MachineDeployment")...)
 }
 
 // calculateMachineDeploymentReplicas calculates the default value of the replicas field.
@@ -27,9 +27,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
 	"sigs.k8s.io/cluster-api/internal/webhooks/util"
 )
 
@@ -611,3 +613,87 @@ func TestMachineDeploymentTemplateMetadataValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineDeploymentWarnings(t *testing.T) {
+	maxUnavailable := intstr.FromString("100%")
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+		WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.28.0").Build()).
+		Build()
+
+	tests := []struct {
+		name         string
+		md           *clusterv1.MachineDeployment
+		withClient   bool
+		wantWarnings []string
+	}{
+		{
+			name: "no warnings",
+			md: builder.MachineDeployment(metav1.NamespaceDefault, "md1").
+				WithClusterName("cluster1").
+				WithVersion("v1.28.0").
+				WithReplicas(3).
+				Build(),
+			withClient: true,
+		},
+		{
+			name: "warning for maxUnavailable 100%",
+			md: func() *clusterv1.MachineDeployment {
+				md := builder.MachineDeployment(metav1.NamespaceDefault, "md1").
+					WithClusterName("cluster1").
+					WithReplicas(3).
+					Build()
+				md.Spec.Strategy = &clusterv1.MachineDeploymentStrategy{
+					Type:          clusterv1.RollingUpdateMachineDeploymentStrategyType,
+					RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{MaxUnavailable: &maxUnavailable},
+				}
+				return md
+			}(),
+			wantWarnings: []string{"spec.strategy.rollingUpdate.maxUnavailable"},
+		},
+		{
+			name: "warning for version skew at the limit",
+			md: builder.MachineDeployment(metav1.NamespaceDefault, "md1").
+				WithClusterName("cluster1").
+				WithVersion("v1.25.0").
+				WithReplicas(3).
+				Build(),
+			withClient:   true,
+			wantWarnings: []string{"spec.template.spec.version"},
+		},
+		{
+			name: "no warning for version skew without client",
+			md: builder.MachineDeployment(metav1.NamespaceDefault, "md1").
+				WithClusterName("cluster1").
+				WithVersion("v1.25.0").
+				WithReplicas(3).
+				Build(),
+		},
+		{
+			name: "no warning for version skew if the Cluster doesn't exist",
+			md: builder.MachineDeployment(metav1.NamespaceDefault, "md1").
+				WithClusterName("cluster2").
+				WithVersion("v1.25.0").
+				WithReplicas(3).
+				Build(),
+			withClient: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			webhook := MachineDeployment{}
+			if tt.withClient {
+				webhook.Client = fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(cluster).Build()
+			}
+
+			warnings, err := webhook.ValidateCreate(ctx, tt.md)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(warnings).To(HaveLen(len(tt.wantWarnings)))
+			for i := range tt.wantWarnings {
+				g.Expect(warnings[i]).To(HavePrefix(tt.wantWarnings[i]))
+			}
+		})
+	}
+}
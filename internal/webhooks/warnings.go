@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/contract"
+)
+
+// The warnings returned by the webhooks don't block the request; they are surfaced to the users, e.g. by kubectl
+// and clusterctl, to highlight deprecated fields, risky configurations or upcoming failures.
+
+// minVerKubeletVersionSkewThree is the minimum version of the control plane supporting kubelets three minor versions older.
+var minVerKubeletVersionSkewThree = semver.MustParse("1.28.0")
+
+// rollingUpdateWarnings returns warnings if the rolling update strategy of a MachineDeployment allows all its Machines
+// to be unavailable at the same time, e.g. with maxUnavailable set to 100%.
+func rollingUpdateWarnings(strategy *clusterv1.MachineDeploymentStrategy, replicas *int32, fldPath *field.Path) admission.Warnings {
+	if strategy == nil || strategy.RollingUpdate == nil || strategy.RollingUpdate.MaxUnavailable == nil {
+		return nil
+	}
+	// Machines can be unavailable at the same time only if there is more than one.
+	if replicas == nil || *replicas < 2 {
+		return nil
+	}
+
+	// NOTE: maxUnavailable is rounded down, consistently with the MachineDeployment controller; invalid values are
+	// rejected by the validation.
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(strategy.RollingUpdate.MaxUnavailable, int(*replicas), false)
+	if err != nil || maxUnavailable < int(*replicas) {
+		return nil
+	}
+	return admission.Warnings{
+		fmt.Sprintf("%s: %s allows all the %d Machines to be unavailable at the same time during a rollout",
+			fldPath.Child("rollingUpdate", "maxUnavailable"), strategy.RollingUpdate.MaxUnavailable.String(), *replicas),
+	}
+}
+
+// kubeletVersionSkewWarnings returns warnings if the Kubernetes version of a set of Machines is at or outside the
+// limits of the Kubernetes version skew policy for kubelets, with respect to the version of the control plane.
+// Machines at the limit must be upgraded before the control plane is upgraded again, while the creation of Machines
+// outside the limits is blocked by the MachineSet preflight checks.
+// See https://kubernetes.io/releases/version-skew-policy/#kubelet.
+func kubeletVersionSkewWarnings(controlPlaneVersion, machineVersion string, fldPath *field.Path, subject string) admission.Warnings {
+	cpSemver, err := semver.ParseTolerant(controlPlaneVersion)
+	if err != nil {
+		return nil
+	}
+	machineSemver, err := semver.ParseTolerant(machineVersion)
+	if err != nil || machineSemver.Major != cpSemver.Major {
+		return nil
+	}
+
+	maxSkew := uint64(3)
+	if cpSemver.LT(minVerKubeletVersionSkewThree) {
+		maxSkew = 2
+	}
+
+	switch {
+	case machineSemver.Minor > cpSemver.Minor:
+		return admission.Warnings{
+			fmt.Sprintf("%s: %s version %s is newer than the control plane version %s, which is not supported by the Kubernetes version skew policy",
+				fldPath, subject, machineVersion, controlPlaneVersion),
+		}
+	case cpSemver.Minor-machineSemver.Minor > maxSkew:
+		return admission.Warnings{
+			fmt.Sprintf("%s: %s version %s is more than %d minor versions older than the control plane version %s, which is not supported by the Kubernetes version skew policy",
+				fldPath, subject, machineVersion, maxSkew, controlPlaneVersion),
+		}
+	case cpSemver.Minor-machineSemver.Minor == maxSkew:
+		return admission.Warnings{
+			fmt.Sprintf("%s: %s version %s is %d minor versions older than the control plane version %s, which is the limit of the Kubernetes version skew policy; it must be upgraded before the control plane is upgraded again",
+				fldPath, subject, machineVersion, maxSkew, controlPlaneVersion),
+		}
+	}
+	return nil
+}
+
+// getControlPlaneVersion returns the desired Kubernetes version of the control plane of a Cluster, if any.
+func getControlPlaneVersion(ctx context.Context, c client.Reader, namespace, clusterName string) (string, error) {
+	cluster := &clusterv1.Cluster{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, cluster); err != nil {
+		return "", errors.Wrapf(err, "failed to get Cluster %s/%s", namespace, clusterName)
+	}
+	if cluster.Spec.Topology != nil {
+		return cluster.Spec.Topology.Version, nil
+	}
+	if cluster.Spec.ControlPlaneRef == nil {
+		return "", nil
+	}
+
+	controlPlane := &unstructured.Unstructured{}
+	controlPlane.SetGroupVersionKind(cluster.Spec.ControlPlaneRef.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: cluster.Spec.ControlPlaneRef.Name}, controlPlane); err != nil {
+		return "", errors.Wrapf(err, "failed to get %s %s/%s", cluster.Spec.ControlPlaneRef.Kind, namespace, cluster.Spec.ControlPlaneRef.Name)
+	}
+	version, err := contract.ControlPlane().Version().Get(controlPlane)
+	if err != nil {
+		// NOTE: The version is optional in the control plane contract.
+		if errors.Is(err, contract.ErrFieldNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return *version, nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestRollingUpdateWarnings(t *testing.T) {
+	maxUnavailable := func(v intstr.IntOrString) *clusterv1.MachineDeploymentStrategy {
+		return &clusterv1.MachineDeploymentStrategy{
+			Type:          clusterv1.RollingUpdateMachineDeploymentStrategyType,
+			RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{MaxUnavailable: &v},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		strategy    *clusterv1.MachineDeploymentStrategy
+		replicas    *int32
+		wantWarning bool
+	}{
+		{
+			name:     "no warning without strategy",
+			replicas: pointer.Int32(3),
+		},
+		{
+			name:     "no warning without replicas",
+			strategy: maxUnavailable(intstr.FromString("100%")),
+		},
+		{
+			name:     "no warning with a single replica",
+			strategy: maxUnavailable(intstr.FromString("100%")),
+			replicas: pointer.Int32(1),
+		},
+		{
+			name:     "no warning if some Machines are always available",
+			strategy: maxUnavailable(intstr.FromString("99%")),
+			replicas: pointer.Int32(3),
+		},
+		{
+			name:        "warning with maxUnavailable 100%",
+			strategy:    maxUnavailable(intstr.FromString("100%")),
+			replicas:    pointer.Int32(3),
+			wantWarning: true,
+		},
+		{
+			name:        "warning with maxUnavailable equal to replicas",
+			strategy:    maxUnavailable(intstr.FromInt(3)),
+			replicas:    pointer.Int32(3),
+			wantWarning: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			warnings := rollingUpdateWarnings(tt.strategy, tt.replicas, field.NewPath("spec", "strategy"))
+			if tt.wantWarning {
+				g.Expect(warnings).To(HaveLen(1))
+				g.Expect(warnings[0]).To(HavePrefix("spec.strategy.rollingUpdate.maxUnavailable: "))
+				return
+			}
+			g.Expect(warnings).To(BeEmpty())
+		})
+	}
+}
+
+func TestKubeletVersionSkewWarnings(t *testing.T) {
+	tests := []struct {
+		name                string
+		controlPlaneVersion string
+		machineVersion      string
+		wantWarning         string
+	}{
+		{
+			name:                "no warning for the same version",
+			controlPlaneVersion: "v1.28.0",
+			machineVersion:      "v1.28.0",
+		},
+		{
+			name:                "no warning within the limits",
+			controlPlaneVersion: "v1.28.0",
+			machineVersion:      "v1.26.3",
+		},
+		{
+			name:                "no warning for invalid versions",
+			controlPlaneVersion: "v1.28.0",
+			machineVersion:      "foo",
+		},
+		{
+			name:                "warning at the limit of three minor versions",
+			controlPlaneVersion: "v1.28.0",
+			machineVersion:      "v1.25.0",
+			wantWarning:         "which is the limit of the Kubernetes version skew policy",
+		},
+		{
+			name:                "warning at the limit of two minor versions before v1.28",
+			controlPlaneVersion: "v1.27.0",
+			machineVersion:      "v1.25.0",
+			wantWarning:         "which is the limit of the Kubernetes version skew policy",
+		},
+		{
+			name:                "warning outside the limits",
+			controlPlaneVersion: "v1.27.0",
+			machineVersion:      "v1.24.0",
+			wantWarning:         "is more than 2 minor versions older",
+		},
+		{
+			name:                "warning for a version newer than the control plane",
+			controlPlaneVersion: "v1.27.0",
+			machineVersion:      "v1.28.0",
+			wantWarning:         "is newer than the control plane version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			warnings := kubeletVersionSkewWarnings(tt.controlPlaneVersion, tt.machineVersion, field.NewPath("spec", "version"), "MachineDeployment")
+			if tt.wantWarning != "" {
+				g.Expect(warnings).To(HaveLen(1))
+				g.Expect(warnings[0]).To(ContainSubstring(tt.wantWarning))
+				return
+			}
+			g.Expect(warnings).To(BeEmpty())
+		})
+	}
+}
+
+func TestGetControlPlaneVersion(t *testing.T) {
+	controlPlane := builder.ControlPlane(metav1.NamespaceDefault, "cp1").WithVersion("v1.28.0").Build()
+
+	tests := []struct {
+		name        string
+		cluster     *clusterv1.Cluster
+		wantVersion string
+		wantErr     bool
+	}{
+		{
+			name: "version of the managed topology",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.27.0").Build()).
+				Build(),
+			wantVersion: "v1.27.0",
+		},
+		{
+			name: "version of the control plane",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithControlPlane(controlPlane).
+				Build(),
+			wantVersion: "v1.28.0",
+		},
+		{
+			name:        "no version without control plane",
+			cluster:     builder.Cluster(metav1.NamespaceDefault, "cluster1").Build(),
+			wantVersion: "",
+		},
+		{
+			name: "error if the control plane doesn't exist",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithControlPlane(builder.ControlPlane(metav1.NamespaceDefault, "cp2").Build()).
+				Build(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(fakeScheme).
+				WithObjects(tt.cluster, controlPlane).
+				Build()
+
+			version, err := getControlPlaneVersion(ctx, fakeClient, metav1.NamespaceDefault, "cluster1")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(version).To(Equal(tt.wantVersion))
+		})
+	}
+
+	t.Run("error if the Cluster doesn't exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).Build()
+		_, err := getControlPlaneVersion(ctx, fakeClient, metav1.NamespaceDefault, "cluster1")
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -22,8 +22,12 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	goruntime "runtime"
+	"sort"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -39,6 +43,7 @@ import (
 	logsv1 "k8s.io/component-base/logs/api/v1"
 	_ "k8s.io/component-base/logs/json/register"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -58,6 +63,7 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	expcontrollers "sigs.k8s.io/cluster-api/exp/controllers"
 	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	ipamcontrollers "sigs.k8s.io/cluster-api/exp/ipam/controllers"
 	expipamwebhooks "sigs.k8s.io/cluster-api/exp/ipam/webhooks"
 	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
@@ -68,7 +74,11 @@ import (
 	runtimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
 	runtimeregistry "sigs.k8s.io/cluster-api/internal/runtime/registry"
 	runtimewebhooks "sigs.k8s.io/cluster-api/internal/webhooks/runtime"
+	"sigs.k8s.io/cluster-api/util/certs"
 	"sigs.k8s.io/cluster-api/util/flags"
+	"sigs.k8s.io/cluster-api/util/health"
+	"sigs.k8s.io/cluster-api/util/shard"
+	"sigs.k8s.io/cluster-api/util/tracing"
 	"sigs.k8s.io/cluster-api/version"
 	"sigs.k8s.io/cluster-api/webhooks"
 )
@@ -96,10 +106,15 @@ var (
 	healthAddr                  string
 	tlsOptions                  = flags.TLSOptions{}
 	diagnosticsOptions          = flags.DiagnosticsOptions{}
+	tracingOptions              = flags.TracingOptions{}
 	logOptions                  = logs.NewOptions()
 	// core Cluster API specific flags.
 	clusterTopologyConcurrency     int
 	clusterCacheTrackerConcurrency int
+	clusterCacheTrackerClientQPS   float32
+	clusterCacheTrackerClientBurst int
+	clusterCacheTrackerProxy       string
+	clusterCacheTrackerMaxBacklog  int
 	clusterClassConcurrency        int
 	clusterConcurrency             int
 	extensionConfigConcurrency     int
@@ -108,8 +123,17 @@ var (
 	machineDeploymentConcurrency   int
 	machinePoolConcurrency         int
 	clusterResourceSetConcurrency  int
+	clusterAddonConcurrency        int
+	inClusterIPPoolConcurrency     int
 	machineHealthCheckConcurrency  int
 	nodeDrainClientTimeout         time.Duration
+	crsDriftDetectionInterval      time.Duration
+	crsHelmRepositoryAllowList     []string
+	crsRegistryAllowList           []string
+	crsRegistryCredentialsFile     string
+	kubeconfigRotationThreshold    time.Duration
+	enableSharding                 bool
+	shardingLeaseDuration          time.Duration
 )
 
 func init() {
@@ -173,6 +197,18 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&clusterCacheTrackerConcurrency, "clustercachetracker-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
+	fs.Float32Var(&clusterCacheTrackerClientQPS, "clustercachetracker-client-qps", 5,
+		"Maximum queries per second from the controller clients to the Kubernetes API server of workload clusters. Defaults to 5")
+
+	fs.IntVar(&clusterCacheTrackerClientBurst, "clustercachetracker-client-burst", 10,
+		"Maximum number of queries that should be allowed in one burst from the controller clients to the Kubernetes API server of workload clusters. Default 10")
+
+	fs.StringVar(&clusterCacheTrackerProxy, "clustercachetracker-proxy", "",
+		"URL of the HTTP CONNECT (http or https scheme) or SOCKS5 (socks5 scheme) proxy used to reach the Kubernetes API server of workload clusters; it can be overridden per Cluster with the cluster-cache.cluster.x-k8s.io/proxy annotation")
+
+	fs.IntVar(&clusterCacheTrackerMaxBacklog, "clustercachetracker-max-backlog", 10,
+		"Maximum number of workload clusters the ClusterCacheTracker can be connecting to before the cluster-cache health check fails; the check is reported by the "+health.DetailsPath+" diagnostics endpoint and does not affect the healthz and readyz probes")
+
 	fs.IntVar(&extensionConfigConcurrency, "extensionconfig-concurrency", 10,
 		"Number of extension configs to process simultaneously")
 
@@ -191,9 +227,36 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&clusterResourceSetConcurrency, "clusterresourceset-concurrency", 10,
 		"Number of cluster resource sets to process simultaneously")
 
+	fs.DurationVar(&crsDriftDetectionInterval, "clusterresourceset-drift-detection-interval", 5*time.Minute,
+		"Interval at which cluster resource sets with the Reconcile strategy detect and correct drift in the workload clusters; 0 disables periodic drift detection")
+
+	fs.StringSliceVar(&crsHelmRepositoryAllowList, "clusterresourceset-helm-repository-allow-list", nil,
+		"Comma-separated list of the URLs of the repositories Helm charts of cluster resource sets can be fetched from, e.g. https://charts.example.com,oci://registry.example.com/charts; if empty, charts can be fetched from any repository")
+
+	fs.StringSliceVar(&crsRegistryAllowList, "clusterresourceset-registry-allow-list", nil,
+		"Comma-separated list of the OCI registries the OCI artifacts of cluster resource sets can be fetched from, e.g. registry.example.com,registry.example.com:5000/addons; if empty, artifacts can be fetched from any registry")
+
+	fs.StringVar(&crsRegistryCredentialsFile, "clusterresourceset-registry-credentials-file", "",
+		"Path of a Docker config file with the credentials to access private OCI registries, used for the OCI artifacts and the Helm charts of cluster resource sets, e.g. mounted from a kubernetes.io/dockerconfigjson Secret; if empty, registries are accessed anonymously")
+
+	fs.IntVar(&clusterAddonConcurrency, "clusteraddon-concurrency", 10,
+		"Number of cluster addons to process simultaneously")
+
+	fs.IntVar(&inClusterIPPoolConcurrency, "inclusterippool-concurrency", 10,
+		"Number of in-cluster IP pools and IP address claims to process simultaneously")
+
 	fs.IntVar(&machineHealthCheckConcurrency, "machinehealthcheck-concurrency", 10,
 		"Number of machine health checks to process simultaneously")
 
+	fs.DurationVar(&kubeconfigRotationThreshold, "kubeconfig-rotation-threshold", certs.ClientCertificateRenewalDuration,
+		"The time before the expiry of the client certificates of the cluster kubeconfig Secrets at which they are rotated (e.g. 720h)")
+
+	fs.BoolVar(&enableSharding, "sharding", false,
+		"Enable sharding of the Cluster, Machine, MachineSet, MachineDeployment and MachineHealthCheck controllers across the replicas of the manager; it requires the POD_NAME and POD_NAMESPACE environment variables.")
+
+	fs.DurationVar(&shardingLeaseDuration, "sharding-lease-duration", shard.DefaultLeaseDuration,
+		"The duration of the Leases of the replicas of the manager when sharding is enabled; it is also the time before the Clusters moved to another replica are reconciled by it.")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
@@ -217,6 +280,7 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	flags.AddDiagnosticsOptions(fs, &diagnosticsOptions)
 	flags.AddTLSOptions(fs, &tlsOptions)
+	flags.AddTracingOptions(fs, &tracingOptions)
 
 	feature.MutableGates.AddFlag(fs)
 }
@@ -272,6 +336,13 @@ func main() {
 
 	diagnosticsOpts := flags.GetDiagnosticsOptions(diagnosticsOptions)
 
+	// Serve the details of the health checks from the diagnostics endpoint.
+	healthChecks := &health.Checks{}
+	if diagnosticsOpts.ExtraHandlers == nil {
+		diagnosticsOpts.ExtraHandlers = map[string]http.Handler{}
+	}
+	diagnosticsOpts.ExtraHandlers[health.DetailsPath] = healthChecks
+
 	var watchNamespaces map[string]cache.Config
 	if watchNamespace != "" {
 		watchNamespaces = map[string]cache.Config{
@@ -335,26 +406,76 @@ func main() {
 	// Setup the context that's going to be used in controllers and for the manager.
 	ctx := ctrl.SetupSignalHandler()
 
-	setupChecks(mgr)
+	shutdownTracing, err := tracing.Setup(ctx, "capi-controller-manager", tracingOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to setup tracing")
+		os.Exit(1)
+	}
+
+	// The unstructured caching client is shared by the controllers and the webhooks reading
+	// the external objects, e.g. the control plane, which are not cached by the default client.
+	unstructuredCachingClient, err := client.New(mgr.GetConfig(), client.Options{
+		HTTPClient: mgr.GetHTTPClient(),
+		Cache: &client.CacheOptions{
+			Reader:       mgr.GetCache(),
+			Unstructured: true,
+		},
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to create unstructured caching client")
+		os.Exit(1)
+	}
+
 	setupIndexes(ctx, mgr)
-	setupReconcilers(ctx, mgr)
-	setupWebhooks(mgr)
+	setupReconcilers(ctx, mgr, healthChecks, unstructuredCachingClient)
+	setupWebhooks(mgr, unstructuredCachingClient)
+	setupChecks(mgr, healthChecks)
 
 	setupLog.Info("starting manager", "version", version.Get().String())
 	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "problem flushing traces")
+	}
 }
 
-func setupChecks(mgr ctrl.Manager) {
-	if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
-		setupLog.Error(err, "unable to create ready check")
-		os.Exit(1)
+func setupChecks(mgr ctrl.Manager, healthChecks *health.Checks) {
+	healthChecks.Add("webhook", health.FromHealthz(mgr.GetWebhookServer().StartedChecker()), health.Readyz, health.Healthz)
+	healthChecks.Add("webhook-certificate", health.CertificateValid(filepath.Join(webhookCertDir, "tls.crt")), health.Readyz)
+	healthChecks.Add("leader-election", health.LeaderElection(mgr.Elected(), enableLeaderElection))
+
+	// Add a check for the informers of the objects watched by each controller.
+	informers := map[string][]client.Object{
+		"cluster":            {&clusterv1.Cluster{}, &clusterv1.Machine{}, &clusterv1.MachineDeployment{}},
+		"machine":            {&clusterv1.Machine{}},
+		"machineset":         {&clusterv1.MachineSet{}, &clusterv1.Machine{}},
+		"machinedeployment":  {&clusterv1.MachineDeployment{}, &clusterv1.MachineSet{}},
+		"machinehealthcheck": {&clusterv1.MachineHealthCheck{}, &clusterv1.Machine{}},
+	}
+	if feature.Gates.Enabled(feature.ClusterTopology) {
+		informers["clusterclass"] = []client.Object{&clusterv1.ClusterClass{}}
+		informers["topology-cluster"] = []client.Object{&clusterv1.Cluster{}, &clusterv1.ClusterClass{}, &clusterv1.MachineDeployment{}}
+	}
+	if feature.Gates.Enabled(feature.MachinePool) {
+		informers["machinepool"] = []client.Object{&expv1.MachinePool{}}
+	}
+	if feature.Gates.Enabled(feature.ClusterResourceSet) {
+		informers["clusterresourceset"] = []client.Object{&addonsv1.ClusterResourceSet{}}
+	}
+	controllers := make([]string, 0, len(informers))
+	for controller := range informers {
+		controllers = append(controllers, controller)
+	}
+	sort.Strings(controllers)
+	for _, controller := range controllers {
+		healthChecks.Add("informers-"+controller, health.InformersSynced(mgr.GetCache(), informers[controller]...), health.Readyz)
 	}
 
-	if err := mgr.AddHealthzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
-		setupLog.Error(err, "unable to create health check")
+	if err := healthChecks.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create health checks")
 		os.Exit(1)
 	}
 }
@@ -366,7 +487,7 @@ func setupIndexes(ctx context.Context, mgr ctrl.Manager) {
 	}
 }
 
-func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
+func setupReconcilers(ctx context.Context, mgr ctrl.Manager, healthChecks *health.Checks, unstructuredCachingClient client.Client) {
 	secretCachingClient, err := client.New(mgr.GetConfig(), client.Options{
 		HTTPClient: mgr.GetHTTPClient(),
 		Cache: &client.CacheOptions{
@@ -378,6 +499,35 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	var sharder *shard.Sharder
+	if enableSharding {
+		sharder = &shard.Sharder{
+			Client:        mgr.GetClient(),
+			APIReader:     mgr.GetAPIReader(),
+			ID:            os.Getenv("POD_NAME"),
+			Group:         controllerName,
+			Namespace:     os.Getenv("POD_NAMESPACE"),
+			LeaseDuration: shardingLeaseDuration,
+		}
+		if sharder.ID == "" || sharder.Namespace == "" {
+			setupLog.Error(errors.New("the POD_NAME and POD_NAMESPACE environment variables must be set"), "unable to setup sharding")
+			os.Exit(1)
+		}
+		if err := mgr.Add(sharder); err != nil {
+			setupLog.Error(err, "unable to setup sharding")
+			os.Exit(1)
+		}
+	}
+
+	var clusterCacheTrackerProxyURL *url.URL
+	if clusterCacheTrackerProxy != "" {
+		clusterCacheTrackerProxyURL, err = remote.ParseProxyURL(clusterCacheTrackerProxy)
+		if err != nil {
+			setupLog.Error(err, "unable to parse the proxy URL of the ClusterCacheTracker")
+			os.Exit(1)
+		}
+	}
+
 	// Set up a ClusterCacheTracker and ClusterCacheReconciler to provide to controllers
 	// requiring a connection to a remote cluster
 	log := ctrl.Log.WithName("remote").WithName("ClusterCacheTracker")
@@ -386,6 +536,9 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		remote.ClusterCacheTrackerOptions{
 			SecretCachingClient: secretCachingClient,
 			ControllerName:      controllerName,
+			ClientQPS:           clusterCacheTrackerClientQPS,
+			ClientBurst:         clusterCacheTrackerClientBurst,
+			Proxy:               clusterCacheTrackerProxyURL,
 			Log:                 &log,
 			Indexes:             []remote.Index{remote.NodeProviderIDIndex},
 		},
@@ -394,12 +547,15 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create cluster cache tracker")
 		os.Exit(1)
 	}
+	healthChecks.Add("cluster-cache", health.ClusterCacheBacklog(tracker, clusterCacheTrackerMaxBacklog))
 
 	if err := (&remote.ClusterCacheReconciler{
 		Client:           mgr.GetClient(),
 		Tracker:          tracker,
+		Sharder:          sharder,
+		Elected:          mgr.Elected(),
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(clusterCacheTrackerConcurrency)); err != nil {
+	}).SetupWithManager(ctx, mgr, sharded(concurrency(clusterCacheTrackerConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterCacheReconciler")
 		os.Exit(1)
 	}
@@ -414,18 +570,6 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		})
 	}
 
-	unstructuredCachingClient, err := client.New(mgr.GetConfig(), client.Options{
-		HTTPClient: mgr.GetHTTPClient(),
-		Cache: &client.CacheOptions{
-			Reader:       mgr.GetCache(),
-			Unstructured: true,
-		},
-	})
-	if err != nil {
-		setupLog.Error(err, "unable to create unstructured caching client")
-		os.Exit(1)
-	}
-
 	if feature.Gates.Enabled(feature.ClusterTopology) {
 		if err := (&controllers.ClusterClassReconciler{
 			Client:                    mgr.GetClient(),
@@ -486,7 +630,8 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		UnstructuredCachingClient: unstructuredCachingClient,
 		APIReader:                 mgr.GetAPIReader(),
 		WatchFilterValue:          watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(clusterConcurrency)); err != nil {
+		Sharder:                   sharder,
+	}).SetupWithManager(ctx, mgr, sharded(concurrency(clusterConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		os.Exit(1)
 	}
@@ -496,8 +641,9 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		APIReader:                 mgr.GetAPIReader(),
 		Tracker:                   tracker,
 		WatchFilterValue:          watchFilterValue,
+		Sharder:                   sharder,
 		NodeDrainClientTimeout:    nodeDrainClientTimeout,
-	}).SetupWithManager(ctx, mgr, concurrency(machineConcurrency)); err != nil {
+	}).SetupWithManager(ctx, mgr, sharded(concurrency(machineConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Machine")
 		os.Exit(1)
 	}
@@ -507,7 +653,8 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		APIReader:                 mgr.GetAPIReader(),
 		Tracker:                   tracker,
 		WatchFilterValue:          watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(machineSetConcurrency)); err != nil {
+		Sharder:                   sharder,
+	}).SetupWithManager(ctx, mgr, sharded(concurrency(machineSetConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MachineSet")
 		os.Exit(1)
 	}
@@ -516,7 +663,8 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		UnstructuredCachingClient: unstructuredCachingClient,
 		APIReader:                 mgr.GetAPIReader(),
 		WatchFilterValue:          watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(machineDeploymentConcurrency)); err != nil {
+		Sharder:                   sharder,
+	}).SetupWithManager(ctx, mgr, sharded(concurrency(machineDeploymentConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MachineDeployment")
 		os.Exit(1)
 	}
@@ -535,9 +683,13 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 
 	if feature.Gates.Enabled(feature.ClusterResourceSet) {
 		if err := (&addonscontrollers.ClusterResourceSetReconciler{
-			Client:           mgr.GetClient(),
-			Tracker:          tracker,
-			WatchFilterValue: watchFilterValue,
+			Client:                  mgr.GetClient(),
+			Tracker:                 tracker,
+			WatchFilterValue:        watchFilterValue,
+			DriftDetectionInterval:  crsDriftDetectionInterval,
+			HelmRepositoryAllowList: crsHelmRepositoryAllowList,
+			RegistryAllowList:       crsRegistryAllowList,
+			RegistryCredentialsFile: crsRegistryCredentialsFile,
 		}).SetupWithManager(ctx, mgr, concurrency(clusterResourceSetConcurrency)); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "ClusterResourceSet")
 			os.Exit(1)
@@ -551,17 +703,72 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		}
 	}
 
+	if feature.Gates.Enabled(feature.ClusterAddon) {
+		if err := (&addonscontrollers.ClusterAddonSetReconciler{
+			Client:           mgr.GetClient(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(clusterAddonConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterAddonSet")
+			os.Exit(1)
+		}
+		if err := (&addonscontrollers.ClusterAddonReconciler{
+			Client:           mgr.GetClient(),
+			Tracker:          tracker,
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(clusterAddonConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterAddon")
+			os.Exit(1)
+		}
+	}
+
+	if feature.Gates.Enabled(feature.InClusterIPAM) {
+		if err := (&ipamcontrollers.InClusterIPPoolReconciler{
+			Client:           mgr.GetClient(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(inClusterIPPoolConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "InClusterIPPool")
+			os.Exit(1)
+		}
+		if err := (&ipamcontrollers.IPAddressClaimReconciler{
+			Client: mgr.GetClient(),
+			// We are using GetAPIReader here to always see the addresses allocated by previous claims.
+			APIReader:        mgr.GetAPIReader(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(inClusterIPPoolConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "IPAddressClaim")
+			os.Exit(1)
+		}
+	}
+
 	if err := (&controllers.MachineHealthCheckReconciler{
 		Client:           mgr.GetClient(),
 		Tracker:          tracker,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(machineHealthCheckConcurrency)); err != nil {
+		Sharder:          sharder,
+	}).SetupWithManager(ctx, mgr, sharded(concurrency(machineHealthCheckConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MachineHealthCheck")
 		os.Exit(1)
 	}
+
+	if err := (&controllers.KubeconfigRotationReconciler{
+		Client:            mgr.GetClient(),
+		RotationThreshold: kubeconfigRotationThreshold,
+		WatchFilterValue:  watchFilterValue,
+	}).SetupWithManager(ctx, mgr, concurrency(clusterConcurrency)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KubeconfigRotation")
+		os.Exit(1)
+	}
+
+	if err := (&controllers.MaintenanceReconciler{
+		Client:           mgr.GetClient(),
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, concurrency(clusterConcurrency)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Maintenance")
+		os.Exit(1)
+	}
 }
 
-func setupWebhooks(mgr ctrl.Manager) {
+func setupWebhooks(mgr ctrl.Manager, unstructuredCachingClient client.Client) {
 	// NOTE: ClusterClass and managed topologies are behind ClusterTopology feature gate flag; the webhook
 	// is going to prevent creating or updating new objects in case the feature flag is disabled.
 	if err := (&webhooks.ClusterClass{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
@@ -586,7 +793,7 @@ func setupWebhooks(mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
-	if err := (&webhooks.MachineDeployment{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&webhooks.MachineDeployment{Client: unstructuredCachingClient}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "MachineDeployment")
 		os.Exit(1)
 	}
@@ -610,6 +817,12 @@ func setupWebhooks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "ClusterResourceSetBinding")
 		os.Exit(1)
 	}
+	// NOTE: ClusterAddonSet is behind ClusterAddon feature gate flag; the webhook
+	// is going to prevent creating or updating new objects in case the feature flag is disabled
+	if err := (&addonswebhooks.ClusterAddonSet{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ClusterAddonSet")
+		os.Exit(1)
+	}
 
 	if err := (&webhooks.MachineHealthCheck{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "MachineHealthCheck")
@@ -634,8 +847,23 @@ func setupWebhooks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "IPAddressClaim")
 		os.Exit(1)
 	}
+	// NOTE: InClusterIPPool is behind the InClusterIPAM feature gate flag; the webhook
+	// is going to prevent creating or updating new objects in case the feature flag is disabled
+	if err := (&expipamwebhooks.InClusterIPPool{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "InClusterIPPool")
+		os.Exit(1)
+	}
 }
 
 func concurrency(c int) controller.Options {
 	return controller.Options{MaxConcurrentReconciles: c}
 }
+
+// sharded returns the options of a controller sharded across the replicas of the manager, which runs on all the
+// replicas instead of only on the leader when sharding is enabled.
+func sharded(options controller.Options) controller.Options {
+	if enableSharding {
+		options.NeedLeaderElection = pointer.Bool(false)
+	}
+	return options
+}
@@ -126,6 +126,50 @@ type ClusterctlUpgradeSpecInput struct {
 	IPAMProviders             []string
 	RuntimeExtensionProviders []string
 	AddonProviders            []string
+	// Upgrades defines the upgrade path of the management cluster, with one item for each clusterctl upgrade to run,
+	// e.g. v1alpha4 -> v1beta1 (v1.4) -> v1beta1 (latest). If set, CoreProvider, BootstrapProviders, ControlPlaneProviders,
+	// InfrastructureProviders, IPAMProviders, RuntimeExtensionProviders, AddonProviders and UpgradeClusterctlVariables are ignored.
+	// If not set, the management cluster is upgraded once, to the custom providers if any, otherwise to the latest version
+	// of the providers for the current contract.
+	Upgrades []ClusterctlUpgradeSpecInputUpgrade
+}
+
+// ClusterctlUpgradeSpecInputUpgrade defines an upgrade of the management cluster in the upgrade path of ClusterctlUpgradeSpec.
+type ClusterctlUpgradeSpecInputUpgrade struct {
+	// WithBinary can be used to run this upgrade with the clusterctl binary at the given URL, e.g. to upgrade to an
+	// intermediate version of the providers; the strings `{OS}` and `{ARCH}` are interpolated like in InitWithBinary.
+	// If not set, this upgrade is run with the current version of clusterctl.
+	WithBinary string
+	// Contract is the contract to upgrade the providers to, e.g. `v1beta1`; it must be empty if custom providers are set.
+	// If neither the contract nor custom providers are set, the providers are upgraded to the current contract.
+	Contract string
+	// Custom providers can be specified to upgrade to a specific version, e.g. `cluster-api:v1.4.0`, instead of upgrading
+	// to the latest version for a contract.
+	CoreProvider              string
+	BootstrapProviders        []string
+	ControlPlaneProviders     []string
+	InfrastructureProviders   []string
+	IPAMProviders             []string
+	RuntimeExtensionProviders []string
+	AddonProviders            []string
+	// ClusterctlVariables can be used to set additional variables for this upgrade.
+	ClusterctlVariables map[string]string
+	// PreUpgrade is a hook called before this upgrade.
+	PreUpgrade func(managementClusterProxy framework.ClusterProxy)
+	// PostUpgrade is a hook called after this upgrade, e.g. to validate the objects after a contract change; it is called
+	// before checking that the upgrade did not trigger any rollout and that the objects of the providers were migrated from
+	// the versions of the CRDs which are not served anymore.
+	PostUpgrade func(managementClusterProxy framework.ClusterProxy, clusterNamespace, clusterName string)
+}
+
+func (u ClusterctlUpgradeSpecInputUpgrade) isCustomUpgrade() bool {
+	return u.CoreProvider != "" ||
+		len(u.BootstrapProviders) > 0 ||
+		len(u.ControlPlaneProviders) > 0 ||
+		len(u.InfrastructureProviders) > 0 ||
+		len(u.IPAMProviders) > 0 ||
+		len(u.RuntimeExtensionProviders) > 0 ||
+		len(u.AddonProviders) > 0
 }
 
 // ClusterctlUpgradeSpec implements a test that verifies clusterctl upgrade of a management cluster.
@@ -138,6 +182,10 @@ type ClusterctlUpgradeSpecInput struct {
 // then run clusterctl upgrade to the latest version of Cluster API and ensure correct operation by
 // scaling a MachineDeployment.
 //
+// The management cluster can also be upgraded through a multi-hop upgrade path, e.g. across many contracts,
+// by setting Upgrades; after each upgrade the spec checks that the upgrade did not trigger any rollout and that
+// the objects of the providers were migrated from the versions of the CRDs which are not served anymore.
+//
 // To use this spec the variables INIT_WITH_BINARY and INIT_WITH_PROVIDERS_CONTRACT must be set or specified directly
 // in the spec input. See ClusterctlUpgradeSpecInput for further information.
 //
@@ -335,11 +383,6 @@ func ClusterctlUpgradeSpec(ctx context.Context, inputGetter func() ClusterctlUpg
 
 		By("THE MANAGEMENT CLUSTER WITH THE OLDER VERSION OF PROVIDERS IS UP&RUNNING!")
 
-		machineCRD := &apiextensionsv1.CustomResourceDefinition{}
-		if err := managementClusterProxy.GetClient().Get(ctx, client.ObjectKey{Name: "machines.cluster.x-k8s.io"}, machineCRD); err != nil {
-			Expect(err).ToNot(HaveOccurred(), "failed to retrieve a machine CRD")
-		}
-
 		Byf("Creating a namespace for hosting the %s test workload cluster", specName)
 		testNamespace, testCancelWatches = framework.CreateNamespaceAndWatchEvents(ctx, framework.CreateNamespaceAndWatchEventsInput{
 			Creator:   managementClusterProxy.GetClient(),
@@ -394,18 +437,7 @@ func ClusterctlUpgradeSpec(ctx context.Context, inputGetter func() ClusterctlUpg
 		}
 
 		// Build GroupVersionKind for Machine resources
-		machineListGVK := schema.GroupVersionKind{
-			Group: machineCRD.Spec.Group,
-			Kind:  machineCRD.Spec.Names.ListKind,
-		}
-
-		// Pick the storage version
-		for _, version := range machineCRD.Spec.Versions {
-			if version.Storage {
-				machineListGVK.Version = version.Name
-				break
-			}
-		}
+		machineListGVK := getMachineListGVK(ctx, managementClusterProxy)
 
 		By("Waiting for the machines to exist")
 		Eventually(func() (int64, error) {
@@ -435,32 +467,9 @@ func ClusterctlUpgradeSpec(ctx context.Context, inputGetter func() ClusterctlUpg
 			input.PreUpgrade(managementClusterProxy)
 		}
 
-		// Get the workloadCluster before the management cluster is upgraded to make sure that the upgrade did not trigger
-		// any unexpected rollouts.
-		preUpgradeMachineList := &unstructured.UnstructuredList{}
-		preUpgradeMachineList.SetGroupVersionKind(machineListGVK)
-		err = managementClusterProxy.GetClient().List(
-			ctx,
-			preUpgradeMachineList,
-			client.InNamespace(testNamespace.Name),
-			client.MatchingLabels{clusterv1.ClusterNameLabel: workLoadClusterName},
-		)
-		Expect(err).ToNot(HaveOccurred())
-		// Check if the user want a custom upgrade
-		isCustomUpgrade := input.CoreProvider != "" ||
-			len(input.BootstrapProviders) > 0 ||
-			len(input.ControlPlaneProviders) > 0 ||
-			len(input.InfrastructureProviders) > 0 ||
-			len(input.IPAMProviders) > 0 ||
-			len(input.RuntimeExtensionProviders) > 0 ||
-			len(input.AddonProviders) > 0
-
-		if isCustomUpgrade {
-			By("Upgrading providers to custom versions")
-			clusterctl.UpgradeManagementClusterAndWait(ctx, clusterctl.UpgradeManagementClusterAndWaitInput{
-				ClusterctlConfigPath:      input.ClusterctlConfigPath,
-				ClusterctlVariables:       input.UpgradeClusterctlVariables,
-				ClusterProxy:              managementClusterProxy,
+		upgrades := input.Upgrades
+		if len(upgrades) == 0 {
+			upgrade := ClusterctlUpgradeSpecInputUpgrade{
 				CoreProvider:              input.CoreProvider,
 				BootstrapProviders:        input.BootstrapProviders,
 				ControlPlaneProviders:     input.ControlPlaneProviders,
@@ -468,40 +477,105 @@ func ClusterctlUpgradeSpec(ctx context.Context, inputGetter func() ClusterctlUpg
 				IPAMProviders:             input.IPAMProviders,
 				RuntimeExtensionProviders: input.RuntimeExtensionProviders,
 				AddonProviders:            input.AddonProviders,
-				LogFolder:                 filepath.Join(input.ArtifactFolder, "clusters", cluster.Name),
-			}, input.E2EConfig.GetIntervals(specName, "wait-controllers")...)
-		} else {
-			By("Upgrading providers to the latest version available")
-			clusterctl.UpgradeManagementClusterAndWait(ctx, clusterctl.UpgradeManagementClusterAndWaitInput{
-				ClusterctlConfigPath: input.ClusterctlConfigPath,
-				ClusterctlVariables:  input.UpgradeClusterctlVariables,
-				ClusterProxy:         managementClusterProxy,
-				Contract:             clusterv1.GroupVersion.Version,
-				LogFolder:            filepath.Join(input.ArtifactFolder, "clusters", cluster.Name),
-			}, input.E2EConfig.GetIntervals(specName, "wait-controllers")...)
+				ClusterctlVariables:       input.UpgradeClusterctlVariables,
+			}
+			upgrades = []ClusterctlUpgradeSpecInputUpgrade{upgrade}
 		}
 
-		By("THE MANAGEMENT CLUSTER WAS SUCCESSFULLY UPGRADED!")
+		for i, upgrade := range upgrades {
+			Byf("Upgrading the management cluster (upgrade %d of %d)", i+1, len(upgrades))
 
-		if input.PostUpgrade != nil {
-			By("Running Post-upgrade steps against the management cluster")
-			input.PostUpgrade(managementClusterProxy, testNamespace.Name, managementClusterName)
-		}
+			if upgrade.PreUpgrade != nil {
+				By("Running Pre-upgrade steps for this upgrade against the management cluster")
+				upgrade.PreUpgrade(managementClusterProxy)
+			}
 
-		// After the upgrade check that there were no unexpected rollouts.
-		log.Logf("Verify there are no unexpected rollouts")
-		Consistently(func() bool {
-			postUpgradeMachineList := &unstructured.UnstructuredList{}
-			postUpgradeMachineList.SetGroupVersionKind(clusterv1.GroupVersion.WithKind("MachineList"))
+			// Get the workloadCluster before the management cluster is upgraded to make sure that the upgrade did not trigger
+			// any unexpected rollouts.
+			preUpgradeMachineList := &unstructured.UnstructuredList{}
+			preUpgradeMachineList.SetGroupVersionKind(machineListGVK)
 			err = managementClusterProxy.GetClient().List(
 				ctx,
-				postUpgradeMachineList,
+				preUpgradeMachineList,
 				client.InNamespace(testNamespace.Name),
 				client.MatchingLabels{clusterv1.ClusterNameLabel: workLoadClusterName},
 			)
 			Expect(err).ToNot(HaveOccurred())
-			return validateMachineRollout(preUpgradeMachineList, postUpgradeMachineList)
-		}, "3m", "30s").Should(BeTrue(), "Machines should remain the same after the upgrade")
+
+			upgradeInput := clusterctl.UpgradeManagementClusterAndWaitInput{
+				ClusterctlConfigPath:      input.ClusterctlConfigPath,
+				ClusterctlVariables:       upgrade.ClusterctlVariables,
+				ClusterProxy:              managementClusterProxy,
+				Contract:                  upgrade.Contract,
+				CoreProvider:              upgrade.CoreProvider,
+				BootstrapProviders:        upgrade.BootstrapProviders,
+				ControlPlaneProviders:     upgrade.ControlPlaneProviders,
+				InfrastructureProviders:   upgrade.InfrastructureProviders,
+				IPAMProviders:             upgrade.IPAMProviders,
+				RuntimeExtensionProviders: upgrade.RuntimeExtensionProviders,
+				AddonProviders:            upgrade.AddonProviders,
+				LogFolder:                 filepath.Join(input.ArtifactFolder, "clusters", cluster.Name),
+			}
+			if len(upgrades) > 1 {
+				upgradeInput.LogFolder = filepath.Join(upgradeInput.LogFolder, fmt.Sprintf("upgrade-%d", i+1))
+			}
+			if upgrade.WithBinary != "" {
+				upgradeBinaryURL := strings.NewReplacer("{OS}", runtime.GOOS, "{ARCH}", runtime.GOARCH).Replace(upgrade.WithBinary)
+				log.Logf("Downloading clusterctl binary from %s", upgradeBinaryURL)
+				upgradeInput.ClusterctlBinaryPath = downloadToTmpFile(ctx, upgradeBinaryURL)
+				defer os.Remove(upgradeInput.ClusterctlBinaryPath)                                                        // clean up
+				Expect(os.Chmod(upgradeInput.ClusterctlBinaryPath, 0744)).To(Succeed(), "failed to chmod temporary file") //nolint:gosec
+				upgradeInput.ClusterctlConfigPath = clusterctl.AdjustConfigPathForBinary(upgradeInput.ClusterctlBinaryPath, input.ClusterctlConfigPath)
+			}
+
+			switch {
+			case upgrade.isCustomUpgrade():
+				By("Upgrading providers to custom versions")
+			case upgrade.Contract != "":
+				Byf("Upgrading providers to the latest version available for the %s contract", upgrade.Contract)
+			default:
+				By("Upgrading providers to the latest version available")
+				upgradeInput.Contract = clusterv1.GroupVersion.Version
+			}
+			clusterctl.UpgradeManagementClusterAndWait(ctx, upgradeInput, input.E2EConfig.GetIntervals(specName, "wait-controllers")...)
+
+			if upgrade.PostUpgrade != nil {
+				By("Running Post-upgrade steps for this upgrade against the management cluster")
+				upgrade.PostUpgrade(managementClusterProxy, testNamespace.Name, workLoadClusterName)
+			}
+
+			// The Machine CRD could have a new storage version after the upgrade.
+			machineListGVK = getMachineListGVK(ctx, managementClusterProxy)
+
+			// After the upgrade check that there were no unexpected rollouts.
+			log.Logf("Verify there are no unexpected rollouts")
+			Consistently(func() bool {
+				postUpgradeMachineList := &unstructured.UnstructuredList{}
+				postUpgradeMachineList.SetGroupVersionKind(machineListGVK)
+				err = managementClusterProxy.GetClient().List(
+					ctx,
+					postUpgradeMachineList,
+					client.InNamespace(testNamespace.Name),
+					client.MatchingLabels{clusterv1.ClusterNameLabel: workLoadClusterName},
+				)
+				Expect(err).ToNot(HaveOccurred())
+				return validateMachineRollout(preUpgradeMachineList, postUpgradeMachineList)
+			}, "3m", "30s").Should(BeTrue(), "Machines should remain the same after the upgrade")
+
+			if upgrade.WithBinary == "" {
+				// NOTE: the storage version migration is checked only for the current version of clusterctl, given that
+				// older versions could not migrate the objects of the providers.
+				log.Logf("Verify the objects of the providers were migrated from the versions of the CRDs which are not served anymore")
+				verifyCRDStoredVersions(ctx, managementClusterProxy)
+			}
+		}
+
+		By("THE MANAGEMENT CLUSTER WAS SUCCESSFULLY UPGRADED!")
+
+		if input.PostUpgrade != nil {
+			By("Running Post-upgrade steps against the management cluster")
+			input.PostUpgrade(managementClusterProxy, testNamespace.Name, managementClusterName)
+		}
 
 		// After upgrading we are sure the version is the latest version of the API,
 		// so it is possible to use the standard helpers
@@ -743,3 +817,45 @@ func getValueOrFallback(value []string, fallback []string) []string {
 	}
 	return fallback
 }
+
+// getMachineListGVK returns the GroupVersionKind of the Machine list for the storage version of the Machine CRD.
+func getMachineListGVK(ctx context.Context, clusterProxy framework.ClusterProxy) schema.GroupVersionKind {
+	machineCRD := &apiextensionsv1.CustomResourceDefinition{}
+	if err := clusterProxy.GetClient().Get(ctx, client.ObjectKey{Name: "machines.cluster.x-k8s.io"}, machineCRD); err != nil {
+		Expect(err).ToNot(HaveOccurred(), "failed to retrieve a machine CRD")
+	}
+
+	machineListGVK := schema.GroupVersionKind{
+		Group: machineCRD.Spec.Group,
+		Kind:  machineCRD.Spec.Names.ListKind,
+	}
+
+	// Pick the storage version
+	for _, version := range machineCRD.Spec.Versions {
+		if version.Storage {
+			machineListGVK.Version = version.Name
+			break
+		}
+	}
+	return machineListGVK
+}
+
+// verifyCRDStoredVersions verifies that the objects of all the CRDs of the providers are stored only with versions which
+// are still served, thus ensuring clusterctl upgrade migrated the objects stored with versions which are not served anymore.
+func verifyCRDStoredVersions(ctx context.Context, clusterProxy framework.ClusterProxy) {
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+	Eventually(func() error {
+		return clusterProxy.GetClient().List(ctx, crdList, client.HasLabels{clusterv1.ProviderNameLabel})
+	}, "1m", "10s").Should(Succeed(), "failed to list the CRDs of the providers")
+
+	for _, crd := range crdList.Items {
+		servedVersions := sets.Set[string]{}
+		for _, version := range crd.Spec.Versions {
+			if version.Served {
+				servedVersions.Insert(version.Name)
+			}
+		}
+		Expect(sets.List(sets.New(crd.Status.StoredVersions...).Difference(servedVersions))).To(BeEmpty(),
+			"the objects of CRD %s should be migrated from the versions which are not served anymore", crd.Name)
+	}
+}
@@ -63,6 +63,9 @@ var (
 
 	// skipCleanup prevents cleanup of test resources e.g. for debug purposes.
 	skipCleanup bool
+
+	// artifactsURL is the bucket where to stream the e2e test artifacts instead of storing them in the artifact folder.
+	artifactsURL string
 )
 
 // Test suite global vars.
@@ -94,6 +97,7 @@ func init() {
 	flag.BoolVar(&skipCleanup, "e2e.skip-resource-cleanup", false, "if true, the resource cleanup after tests will be skipped")
 	flag.StringVar(&clusterctlConfig, "e2e.clusterctl-config", "", "file which tests will use as a clusterctl config. If it is not set, a local clusterctl repository (including a clusterctl config) will be created automatically.")
 	flag.BoolVar(&useExistingCluster, "e2e.use-existing-cluster", false, "if true, the test uses the current cluster instead of creating a new one (default discovery rules apply)")
+	flag.StringVar(&artifactsURL, "e2e.artifacts-url", "", "if set, logs and resource dumps are streamed to this bucket instead of being stored in the artifacts folder, e.g. s3://bucket/prefix, gs://bucket/prefix or azblob://account/container/prefix")
 }
 
 func TestE2E(t *testing.T) {
@@ -133,6 +137,8 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	Byf("Loading the e2e test configuration from %q", configPath)
 	e2eConfig = loadE2EConfig(configPath)
 
+	artifactsRunID := setupArtifactStore("")
+
 	if clusterctlConfig == "" {
 		Byf("Creating a clusterctl local repository into %q", artifactFolder)
 		clusterctlConfigPath = createClusterctlLocalRepository(e2eConfig, filepath.Join(artifactFolder, "repository"))
@@ -153,18 +159,20 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 			configPath,
 			clusterctlConfigPath,
 			bootstrapClusterProxy.GetKubeconfigPath(),
+			artifactsRunID,
 		}, ","),
 	)
 }, func(data []byte) {
 	// Before each ParallelNode.
 
 	parts := strings.Split(string(data), ",")
-	Expect(parts).To(HaveLen(4))
+	Expect(parts).To(HaveLen(5))
 
 	artifactFolder = parts[0]
 	configPath = parts[1]
 	clusterctlConfigPath = parts[2]
 	kubeconfigPath := parts[3]
+	setupArtifactStore(parts[4])
 
 	e2eConfig = loadE2EConfig(configPath)
 	bootstrapClusterProxy = framework.NewClusterProxy("bootstrap", kubeconfigPath, initScheme(), framework.WithMachineLogCollector(framework.DockerLogCollector{}))
@@ -187,6 +195,12 @@ var _ = SynchronizedAfterSuite(func() {
 	}
 })
 
+// Using a ReportAfterSuite for writing the structured results of the specs of all the ParallelNodes, i.e. the JUnit
+// report with the step-level timings and the metrics file, so release dashboards can track performance regressions.
+var _ = ReportAfterSuite("Writing the structured results of the specs", func(report Report) {
+	Expect(framework.WriteSpecResults(ctx, report, artifactFolder)).To(Succeed(), "Failed to write the structured results of the specs")
+})
+
 func initScheme() *runtime.Scheme {
 	sc := runtime.NewScheme()
 	framework.TryAddDefaultSchemes(sc)
@@ -303,3 +317,22 @@ func tearDown(bootstrapClusterProvider bootstrap.ClusterProvider, bootstrapClust
 		bootstrapClusterProvider.Dispose(ctx)
 	}
 }
+
+// setupArtifactStore configures the framework to stream the artifacts to the bucket defined by e2e.artifacts-url, if any,
+// using the given run ID as a prefix; if runID is empty, a new run ID is generated.
+// It returns the run ID, so all the ParallelNodes can use the same prefix.
+func setupArtifactStore(runID string) string {
+	if artifactsURL == "" {
+		return runID
+	}
+	store, err := framework.NewObjectStorageArtifactStore(framework.ObjectStorageArtifactStoreInput{
+		URL:            artifactsURL,
+		ArtifactFolder: artifactFolder,
+		RunID:          runID,
+	})
+	Expect(err).ToNot(HaveOccurred(), "Invalid test suite argument. e2e.artifacts-url is not valid")
+	framework.SetArtifactStore(store)
+
+	Byf("Streaming artifacts to %s/%s", strings.TrimSuffix(artifactsURL, "/"), store.RunID())
+	return store.RunID()
+}
@@ -51,6 +51,18 @@ type K8SConformanceSpecInput struct {
 
 	Flavor              string
 	ControlPlaneWaiters clusterctl.ControlPlaneWaiters
+
+	// ConformanceRunner, if set, runs the conformance tests with the given runner, i.e. hydrophone or kubetest2,
+	// instead of running the conformance image with kubetest; in this case the tests to run are defined by
+	// ConformanceFocus and ConformanceSkip, and the KUBETEST_CONFIGURATION variable is not required.
+	ConformanceRunner kubetest.ConformanceRunner
+
+	// ConformanceFocus is the regular expression selecting the tests run by ConformanceRunner.
+	// If not specified, all the conformance tests are run.
+	ConformanceFocus string
+
+	// ConformanceSkip is the regular expression selecting the tests skipped by ConformanceRunner.
+	ConformanceSkip string
 }
 
 // K8SConformanceSpec implements a spec that creates a cluster and runs Kubernetes conformance suite.
@@ -78,9 +90,11 @@ func K8SConformanceSpec(ctx context.Context, inputGetter func() K8SConformanceSp
 		Expect(os.MkdirAll(input.ArtifactFolder, 0750)).To(Succeed(), "Invalid argument. input.ArtifactFolder can't be created for %s spec", specName)
 
 		Expect(input.E2EConfig.Variables).To(HaveKey(KubernetesVersion))
-		Expect(input.E2EConfig.Variables).To(HaveKey(kubetestConfigurationVariable), "% spec requires a %s variable to be defined in the config file", specName, kubetestConfigurationVariable)
-		kubetestConfigFilePath = input.E2EConfig.GetVariable(kubetestConfigurationVariable)
-		Expect(kubetestConfigFilePath).To(BeAnExistingFile(), "%s should be a valid kubetest config file")
+		if input.ConformanceRunner == "" {
+			Expect(input.E2EConfig.Variables).To(HaveKey(kubetestConfigurationVariable), "% spec requires a %s variable to be defined in the config file", specName, kubetestConfigurationVariable)
+			kubetestConfigFilePath = input.E2EConfig.GetVariable(kubetestConfigurationVariable)
+			Expect(kubetestConfigFilePath).To(BeAnExistingFile(), "%s should be a valid kubetest config file")
+		}
 
 		// Setup a Namespace where to host objects for this spec and create a watcher for the namespace events.
 		namespace, cancelWatches = setupSpecNamespace(ctx, specName, input.BootstrapClusterProxy, input.ArtifactFolder)
@@ -135,6 +149,21 @@ func K8SConformanceSpec(ctx context.Context, inputGetter func() K8SConformanceSp
 			Expect(err).ToNot(HaveOccurred(), "Failed to parse kubetestGinkgoNodesVariable to int")
 		}
 
+		if input.ConformanceRunner != "" {
+			err = kubetest.RunConformance(ctx, kubetest.RunConformanceInput{
+				ClusterProxy:       workloadProxy,
+				ArtifactsDirectory: input.ArtifactFolder,
+				Runner:             input.ConformanceRunner,
+				Focus:              input.ConformanceFocus,
+				Skip:               input.ConformanceSkip,
+				Parallel:           ginkgoNodes,
+			})
+			Expect(err).ToNot(HaveOccurred(), "Failed to run Kubernetes conformance")
+
+			By("PASSED!")
+			return
+		}
+
 		// Start running conformance test suites.
 		err = kubetest.Run(
 			ctx,
@@ -59,8 +59,8 @@ const (
 	scaleClusterNamespacePlaceholder = "scale-cluster-namespace-placeholder"
 )
 
-// scaleSpecInput is the input for scaleSpec.
-type scaleSpecInput struct {
+// ScaleSpecInput is the input for ScaleSpec.
+type ScaleSpecInput struct {
 	E2EConfig             *clusterctl.E2EConfig
 	ClusterctlConfigPath  string
 	BootstrapClusterProxy framework.ClusterProxy
@@ -131,13 +131,21 @@ type scaleSpecInput struct {
 	// If set to true, the test will create the workload clusters and immediately continue without waiting
 	// for the clusters to be fully provisioned.
 	SkipWaitForCreation bool
+
+	// SLOs are the service level objectives checked against the measurements of the test, i.e. the time
+	// for creating and deleting the clusters, the reconcile time of the controllers and the number of requests
+	// they send to the API server; the test fails if any of them is not met.
+	// If not specified, the measurements are only stored in the artifacts folder as scale-measurements.json.
+	SLOs *framework.ScaleSLOs
 }
 
-// scaleSpec implements a scale test.
-func scaleSpec(ctx context.Context, inputGetter func() scaleSpecInput) {
+// ScaleSpec implements a scale test, creating and deleting many workload clusters, e.g. with the in-memory
+// infrastructure provider; the measurements of the test are stored as JSON in the artifacts folder and
+// checked against the SLOs, if any.
+func ScaleSpec(ctx context.Context, inputGetter func() ScaleSpecInput) {
 	var (
 		specName      = "scale"
-		input         scaleSpecInput
+		input         ScaleSpecInput
 		namespace     *corev1.Namespace
 		cancelWatches context.CancelFunc
 	)
@@ -284,6 +292,13 @@ func scaleSpec(ctx context.Context, inputGetter func() scaleSpecInput) {
 			}
 		}
 
+		By("Scrape the metrics of the controllers before creating the workload clusters")
+		metricsInput := framework.GetControllerMetricsInput{
+			Lister:    input.BootstrapClusterProxy.GetClient(),
+			ClientSet: input.BootstrapClusterProxy.GetClientSet(),
+		}
+		metricsBefore := framework.GetControllerMetrics(ctx, metricsInput)
+
 		By("Create workload clusters concurrently")
 		// Create multiple clusters concurrently from the same base cluster template.
 
@@ -340,13 +355,20 @@ func scaleSpec(ctx context.Context, inputGetter func() scaleSpecInput) {
 			clusterNamesToDelete = append(clusterNamesToDelete, result.clusterName)
 		}
 
+		measurements := framework.ScaleMeasurements{
+			ClusterCount:       clusterCount,
+			MachinesPerCluster: *controlPlaneMachineCount + (*machineDeploymentCount)*(*workerMachineCount),
+			ClusterCreation:    framework.NewDurationSummary(resultDurations(clusterCreateResults)),
+		}
+
 		if input.SkipCleanup {
+			checkScaleMeasurements(ctx, input, measurements, metricsBefore, metricsInput)
 			return
 		}
 
 		By("Delete the workload clusters concurrently")
 		// Now delete all the workload clusters.
-		_, err = workConcurrentlyAndWait(ctx, workConcurrentlyAndWaitInput{
+		clusterDeleteResults, err := workConcurrentlyAndWait(ctx, workConcurrentlyAndWaitInput{
 			ClusterNames: clusterNamesToDelete,
 			Concurrency:  concurrency,
 			FailFast:     input.FailFast,
@@ -368,6 +390,9 @@ func scaleSpec(ctx context.Context, inputGetter func() scaleSpecInput) {
 
 		// TODO(ykakarap): Follow-up: Dump resources for the failed clusters (deletion).
 
+		measurements.ClusterDeletion = framework.NewDurationSummary(resultDurations(clusterDeleteResults))
+		checkScaleMeasurements(ctx, input, measurements, metricsBefore, metricsInput)
+
 		By("PASSED!")
 	})
 
@@ -376,6 +401,40 @@ func scaleSpec(ctx context.Context, inputGetter func() scaleSpecInput) {
 	})
 }
 
+// checkScaleMeasurements adds the metrics of the controllers for the operations executed since metricsBefore
+// to the measurements, stores them in the artifacts folder and checks them against the SLOs, if any.
+func checkScaleMeasurements(ctx context.Context, input ScaleSpecInput, measurements framework.ScaleMeasurements, metricsBefore map[string]*framework.ControllerMetrics, metricsInput framework.GetControllerMetricsInput) {
+	By("Scrape the metrics of the controllers and store the measurements")
+	measurements.Controllers = map[string]framework.ControllerMeasurements{}
+	for deployment, metrics := range framework.GetControllerMetrics(ctx, metricsInput) {
+		// Note: If a controller restarted during the test its metrics were reset, so they are not subtracted.
+		if before, ok := metricsBefore[deployment]; ok {
+			metrics.Sub(before)
+		}
+		measurements.Controllers[deployment] = framework.NewControllerMeasurements(metrics)
+	}
+
+	measurementsPath := filepath.Join(input.ArtifactFolder, "scale-measurements.json")
+	Expect(framework.WriteScaleMeasurements(ctx, measurementsPath, measurements)).To(Succeed(), "Failed to store the scale measurements")
+	log.Logf("Stored the scale measurements in %s", measurementsPath)
+
+	if input.SLOs != nil {
+		By("Check the measurements against the SLOs")
+		Expect(input.SLOs.Check(measurements)).To(Succeed(), "Scale SLOs are not met")
+	}
+}
+
+// resultDurations returns the durations of the successful operations.
+func resultDurations(results []workResult) []time.Duration {
+	durations := []time.Duration{}
+	for _, result := range results {
+		if result.err == nil {
+			durations = append(durations, result.duration)
+		}
+	}
+	return durations
+}
+
 func extractClusterClassAndClusterFromTemplate(rawYAML []byte) ([]byte, []byte) {
 	objs, err := yaml.ToUnstructured(rawYAML)
 	Expect(err).ToNot(HaveOccurred())
@@ -535,11 +594,13 @@ func createClusterWorker(ctx context.Context, clusterProxy framework.ClusterProx
 
 				// This defer will catch ginkgo failures and record them.
 				// The recorded panics are then handled by the parent goroutine.
+				start := time.Now()
 				defer func() {
 					e := recover()
 					resultChan <- workResult{
 						clusterName: clusterName,
 						err:         e,
+						duration:    time.Since(start),
 					}
 				}()
 
@@ -602,11 +663,13 @@ func deleteClusterAndWaitWorker(ctx context.Context, inputChan <-chan string, re
 
 				// This defer will catch ginkgo failures and record them.
 				// The recorded panics are then handled by the parent goroutine.
+				start := time.Now()
 				defer func() {
 					e := recover()
 					resultChan <- workResult{
 						clusterName: clusterName,
 						err:         e,
+						duration:    time.Since(start),
 					}
 				}()
 
@@ -651,6 +714,7 @@ func deleteClusterAndWaitWorker(ctx context.Context, inputChan <-chan string, re
 type workResult struct {
 	clusterName string
 	err         any
+	duration    time.Duration
 }
 
 func modifyMachineDeployments(baseClusterTemplateYAML []byte, count int) []byte {
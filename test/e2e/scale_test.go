@@ -25,8 +25,8 @@ import (
 )
 
 var _ = Describe("When testing the machinery for scale testing using in-memory provider", func() {
-	scaleSpec(ctx, func() scaleSpecInput {
-		return scaleSpecInput{
+	ScaleSpec(ctx, func() ScaleSpecInput {
+		return ScaleSpecInput{
 			E2EConfig:                e2eConfig,
 			ClusterctlConfigPath:     clusterctlConfigPath,
 			InfrastructureProvider:   pointer.String("in-memory"),
@@ -44,8 +44,8 @@ var _ = Describe("When testing the machinery for scale testing using in-memory p
 })
 
 var _ = Describe("When scale testing using in-memory provider  [Scale]", func() {
-	scaleSpec(ctx, func() scaleSpecInput {
-		return scaleSpecInput{
+	ScaleSpec(ctx, func() ScaleSpecInput {
+		return ScaleSpecInput{
 			E2EConfig:                e2eConfig,
 			ClusterctlConfigPath:     clusterctlConfigPath,
 			InfrastructureProvider:   pointer.String("in-memory"),
@@ -0,0 +1,268 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package behavior allows to reconfigure at runtime how the test extension answers to each hook, e.g. blocking,
+// failing, delaying or adding patches to the responses, so E2E and provider tests can simulate different
+// Runtime Extension behaviors without rebuilding the test extension image.
+//
+// The behaviors are stored in a ConfigMap in the namespace of the test extension, with a key for each hook name
+// and a Behavior in YAML as value; hooks without a behavior keep their default implementation.
+package behavior
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+)
+
+// ConfigMapName is the name of the ConfigMap storing the behaviors of the test extension.
+const ConfigMapName = "test-extension-behaviors"
+
+// Behavior is how the test extension answers to a hook.
+type Behavior struct {
+	// Clusters are the Clusters, as namespace/name, the behavior applies to; if empty, the behavior applies to
+	// all the Clusters. It is only considered for the hooks with a Cluster in the request, i.e. lifecycle hooks.
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Delay is the time the test extension waits before answering.
+	Delay metav1.Duration `json:"delay,omitempty"`
+
+	// Status is the status of the response; if set to Failure, the hook fails with Message and the default
+	// implementation of the hook is not called.
+	Status runtimehooksv1.ResponseStatus `json:"status,omitempty"`
+
+	// Message is the message of the response.
+	Message string `json:"message,omitempty"`
+
+	// RetryAfterSeconds blocks the hook, for the hooks supporting it; the default implementation of the hook is not
+	// called.
+	RetryAfterSeconds int32 `json:"retryAfterSeconds,omitempty"`
+
+	// Patches are added to the response of the GeneratePatches hook, after the patches of the default implementation.
+	Patches []Patch `json:"patches,omitempty"`
+}
+
+// Patch is a JSON patch added to the GeneratePatches response for the request items of a kind.
+type Patch struct {
+	// APIVersion is the apiVersion of the objects to patch.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the kind of the objects to patch.
+	Kind string `json:"kind"`
+
+	// JSONPatch is the JSON patch to apply to the objects.
+	JSONPatch json.RawMessage `json:"jsonPatch"`
+}
+
+// Behaviors reads the behaviors of the test extension from the ConfigMap and applies them to the hooks.
+type Behaviors struct {
+	client    client.Reader
+	namespace string
+}
+
+// New returns Behaviors reading the behaviors from the ConfigMap in the given namespace.
+func New(client client.Reader, namespace string) *Behaviors {
+	return &Behaviors{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+// Wrap wraps the handler of a hook, so the behavior configured for the hook, if any, is applied.
+func Wrap[Req any, Resp runtimehooksv1.ResponseObject](b *Behaviors, hook runtimecatalog.Hook, handler func(context.Context, Req, Resp)) func(context.Context, Req, Resp) {
+	return func(ctx context.Context, request Req, response Resp) {
+		behavior, err := b.get(ctx, hook, requestCluster(request))
+		if err != nil {
+			response.SetStatus(runtimehooksv1.ResponseStatusFailure)
+			response.SetMessage(err.Error())
+			return
+		}
+		if behavior == nil {
+			handler(ctx, request, response)
+			return
+		}
+
+		log := ctrl.LoggerFrom(ctx)
+		log.Info(fmt.Sprintf("Applying behavior for %s", runtimecatalog.HookName(hook)), "delay", behavior.Delay.Duration, "status", behavior.Status, "retryAfterSeconds", behavior.RetryAfterSeconds, "patches", len(behavior.Patches))
+
+		if behavior.Delay.Duration > 0 {
+			select {
+			case <-ctx.Done():
+				response.SetStatus(runtimehooksv1.ResponseStatusFailure)
+				response.SetMessage(ctx.Err().Error())
+				return
+			case <-time.After(behavior.Delay.Duration):
+			}
+		}
+
+		if behavior.Status == runtimehooksv1.ResponseStatusFailure {
+			response.SetStatus(runtimehooksv1.ResponseStatusFailure)
+			response.SetMessage(behavior.Message)
+			return
+		}
+		if r, ok := any(response).(runtimehooksv1.RetryResponseObject); ok && behavior.RetryAfterSeconds > 0 {
+			r.SetStatus(runtimehooksv1.ResponseStatusSuccess)
+			r.SetMessage(behavior.Message)
+			r.SetRetryAfterSeconds(behavior.RetryAfterSeconds)
+			return
+		}
+
+		handler(ctx, request, response)
+		if behavior.Message != "" {
+			response.SetMessage(behavior.Message)
+		}
+		if err := addPatches(any(request), any(response), behavior.Patches); err != nil {
+			response.SetStatus(runtimehooksv1.ResponseStatusFailure)
+			response.SetMessage(err.Error())
+		}
+	}
+}
+
+// get returns the behavior configured for a hook and a Cluster, or nil if none applies.
+func (b *Behaviors) get(ctx context.Context, hook runtimecatalog.Hook, cluster *clusterv1.Cluster) (*Behavior, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := b.client.Get(ctx, client.ObjectKey{Namespace: b.namespace, Name: ConfigMapName}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read the ConfigMap %s", klog.KRef(b.namespace, ConfigMapName))
+	}
+
+	data, ok := configMap.Data[runtimecatalog.HookName(hook)]
+	if !ok {
+		return nil, nil
+	}
+	behavior := &Behavior{}
+	if err := yaml.UnmarshalStrict([]byte(data), behavior); err != nil {
+		return nil, errors.Wrapf(err, "failed to read the behavior of %s from the ConfigMap %s", runtimecatalog.HookName(hook), klog.KRef(b.namespace, ConfigMapName))
+	}
+
+	if len(behavior.Clusters) == 0 || cluster == nil {
+		return behavior, nil
+	}
+	for _, c := range behavior.Clusters {
+		if c == klog.KObj(cluster).String() {
+			return behavior, nil
+		}
+	}
+	return nil, nil
+}
+
+// requestCluster returns the Cluster of a request, or nil if the request does not have a Cluster.
+func requestCluster(request any) *clusterv1.Cluster {
+	v := reflect.Indirect(reflect.ValueOf(request))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	f := v.FieldByName("Cluster")
+	if !f.IsValid() || !f.CanAddr() {
+		return nil
+	}
+	cluster, ok := f.Addr().Interface().(*clusterv1.Cluster)
+	if !ok {
+		return nil
+	}
+	return cluster
+}
+
+// addPatches adds the patches to a GeneratePatches response, for each item of the request of the patch kind.
+func addPatches(request, response any, patches []Patch) error {
+	if len(patches) == 0 {
+		return nil
+	}
+	req, ok := request.(*runtimehooksv1.GeneratePatchesRequest)
+	if !ok {
+		return nil
+	}
+	resp := response.(*runtimehooksv1.GeneratePatchesResponse)
+
+	for _, item := range req.Items {
+		obj := &metav1.TypeMeta{}
+		if err := json.Unmarshal(item.Object.Raw, obj); err != nil {
+			return errors.Wrapf(err, "failed to read the kind of the request item %s", item.UID)
+		}
+		for _, p := range patches {
+			if p.APIVersion != obj.APIVersion || p.Kind != obj.Kind {
+				continue
+			}
+			resp.Items = append(resp.Items, runtimehooksv1.GeneratePatchesResponseItem{
+				UID:       item.UID,
+				PatchType: runtimehooksv1.JSONPatchType,
+				Patch:     p.JSONPatch,
+			})
+		}
+	}
+	return nil
+}
+
+// Set sets the behavior of the test extension for a hook, e.g. from a test; the behavior is applied to the requests
+// received after the ConfigMap is updated.
+func Set(ctx context.Context, c client.Client, namespace string, hook runtimecatalog.Hook, behavior Behavior) error {
+	data, err := yaml.Marshal(behavior)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the behavior of %s", runtimecatalog.HookName(hook))
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: namespace, Name: ConfigMapName}
+	if err := c.Get(ctx, key, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to read the ConfigMap %s", klog.KRef(namespace, ConfigMapName))
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: ConfigMapName},
+			Data:       map[string]string{runtimecatalog.HookName(hook): string(data)},
+		}
+		return errors.Wrapf(c.Create(ctx, configMap), "failed to create the ConfigMap %s", klog.KRef(namespace, ConfigMapName))
+	}
+
+	patch := client.MergeFrom(configMap.DeepCopy())
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[runtimecatalog.HookName(hook)] = string(data)
+	return errors.Wrapf(c.Patch(ctx, configMap, patch), "failed to update the ConfigMap %s", klog.KRef(namespace, ConfigMapName))
+}
+
+// Reset removes the behavior of the test extension for a hook, so the default implementation of the hook is used.
+func Reset(ctx context.Context, c client.Client, namespace string, hook runtimecatalog.Hook) error {
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ConfigMapName}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to read the ConfigMap %s", klog.KRef(namespace, ConfigMapName))
+	}
+
+	patch := client.MergeFrom(configMap.DeepCopy())
+	delete(configMap.Data, runtimecatalog.HookName(hook))
+	return errors.Wrapf(c.Patch(ctx, configMap, patch), "failed to update the ConfigMap %s", klog.KRef(namespace, ConfigMapName))
+}
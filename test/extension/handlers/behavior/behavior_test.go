@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package behavior
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+)
+
+func TestWrap(t *testing.T) {
+	ctx := context.Background()
+	namespace := "test-extension-system"
+
+	defaultBeforeClusterCreate := func(_ context.Context, _ *runtimehooksv1.BeforeClusterCreateRequest, response *runtimehooksv1.BeforeClusterCreateResponse) {
+		response.SetStatus(runtimehooksv1.ResponseStatusSuccess)
+		response.SetMessage("default")
+	}
+	beforeClusterCreateRequest := func(namespace, name string) *runtimehooksv1.BeforeClusterCreateRequest {
+		return &runtimehooksv1.BeforeClusterCreateRequest{Cluster: clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}}
+	}
+
+	t.Run("Default implementation without behaviors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		b := New(fake.NewClientBuilder().Build(), namespace)
+		response := &runtimehooksv1.BeforeClusterCreateResponse{}
+		Wrap(b, runtimehooksv1.BeforeClusterCreate, defaultBeforeClusterCreate)(ctx, beforeClusterCreateRequest("ns1", "cluster1"), response)
+		g.Expect(response.GetMessage()).To(Equal("default"))
+	})
+
+	t.Run("Block and fail for a Cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().Build()
+		g.Expect(Set(ctx, c, namespace, runtimehooksv1.BeforeClusterCreate, Behavior{Clusters: []string{"ns1/cluster1"}, RetryAfterSeconds: 10})).To(Succeed())
+		b := New(c, namespace)
+		hook := Wrap(b, runtimehooksv1.BeforeClusterCreate, defaultBeforeClusterCreate)
+
+		response := &runtimehooksv1.BeforeClusterCreateResponse{}
+		hook(ctx, beforeClusterCreateRequest("ns1", "cluster1"), response)
+		g.Expect(response.GetStatus()).To(Equal(runtimehooksv1.ResponseStatusSuccess))
+		g.Expect(response.GetRetryAfterSeconds()).To(Equal(int32(10)))
+
+		// Other Clusters are not affected.
+		response = &runtimehooksv1.BeforeClusterCreateResponse{}
+		hook(ctx, beforeClusterCreateRequest("ns1", "cluster2"), response)
+		g.Expect(response.GetMessage()).To(Equal("default"))
+		g.Expect(response.GetRetryAfterSeconds()).To(BeZero())
+
+		// Behaviors can be changed at runtime.
+		g.Expect(Set(ctx, c, namespace, runtimehooksv1.BeforeClusterCreate, Behavior{Status: runtimehooksv1.ResponseStatusFailure, Message: "injected failure", Delay: metav1.Duration{Duration: 10 * time.Millisecond}})).To(Succeed())
+		response = &runtimehooksv1.BeforeClusterCreateResponse{}
+		hook(ctx, beforeClusterCreateRequest("ns1", "cluster2"), response)
+		g.Expect(response.GetStatus()).To(Equal(runtimehooksv1.ResponseStatusFailure))
+		g.Expect(response.GetMessage()).To(Equal("injected failure"))
+
+		g.Expect(Reset(ctx, c, namespace, runtimehooksv1.BeforeClusterCreate)).To(Succeed())
+		response = &runtimehooksv1.BeforeClusterCreateResponse{}
+		hook(ctx, beforeClusterCreateRequest("ns1", "cluster2"), response)
+		g.Expect(response.GetMessage()).To(Equal("default"))
+	})
+
+	t.Run("Add patches", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().Build()
+		g.Expect(Set(ctx, c, namespace, runtimehooksv1.GeneratePatches, Behavior{Patches: []Patch{{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+			Kind:       "DockerMachineTemplate",
+			JSONPatch:  json.RawMessage(`[{"op":"add","path":"/spec/template/spec/customImage","value":"image"}]`),
+		}}})).To(Succeed())
+		b := New(c, namespace)
+
+		request := &runtimehooksv1.GeneratePatchesRequest{Items: []runtimehooksv1.GeneratePatchesRequestItem{
+			{UID: "1", Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"infrastructure.cluster.x-k8s.io/v1beta1","kind":"DockerClusterTemplate"}`)}},
+			{UID: "2", Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"infrastructure.cluster.x-k8s.io/v1beta1","kind":"DockerMachineTemplate"}`)}},
+		}}
+		response := &runtimehooksv1.GeneratePatchesResponse{}
+		Wrap(b, runtimehooksv1.GeneratePatches, func(_ context.Context, _ *runtimehooksv1.GeneratePatchesRequest, response *runtimehooksv1.GeneratePatchesResponse) {
+			response.SetStatus(runtimehooksv1.ResponseStatusSuccess)
+		})(ctx, request, response)
+
+		g.Expect(response.GetStatus()).To(Equal(runtimehooksv1.ResponseStatusSuccess))
+		g.Expect(response.Items).To(HaveLen(1))
+		g.Expect(response.Items[0].UID).To(BeEquivalentTo("2"))
+		g.Expect(string(response.Items[0].Patch)).To(Equal(`[{"op":"add","path":"/spec/template/spec/customImage","value":"image"}]`))
+	})
+}
@@ -21,6 +21,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
@@ -42,9 +43,12 @@ import (
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/exp/runtime/server"
+	"sigs.k8s.io/cluster-api/test/extension/handlers/behavior"
 	"sigs.k8s.io/cluster-api/test/extension/handlers/lifecycle"
 	"sigs.k8s.io/cluster-api/test/extension/handlers/topologymutation"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/flags"
+	"sigs.k8s.io/cluster-api/util/tracing"
 	"sigs.k8s.io/cluster-api/version"
 )
 
@@ -58,10 +62,12 @@ var (
 	scheme = runtime.NewScheme()
 
 	// Flags.
-	profilerAddress string
-	webhookPort     int
-	webhookCertDir  string
-	logOptions      = logs.NewOptions()
+	profilerAddress    string
+	webhookPort        int
+	webhookCertDir     string
+	behaviorsNamespace string
+	tracingOptions     = flags.TracingOptions{}
+	logOptions         = logs.NewOptions()
 )
 
 func init() {
@@ -94,6 +100,13 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs/",
 		"Webhook cert dir, only used when webhook-port is specified.")
+
+	fs.StringVar(&behaviorsNamespace, "behaviors-namespace", os.Getenv("POD_NAMESPACE"),
+		"Namespace of the ConfigMap defining the behavior of each hook; defaults to the namespace of the test extension Pod.")
+
+	// NOTE: tracing is opt-in; when enabled, the spans of the hook calls are children of the spans of the
+	// Cluster API controllers calling the hooks.
+	flags.AddTracingOptions(fs, &tracingOptions)
 }
 
 func main() {
@@ -159,6 +172,25 @@ func main() {
 	//  of them for ensuring proper test coverage
 	// ****************************************************
 
+	// Gets a client to access the Kubernetes cluster where this RuntimeExtension will be deployed to;
+	// this is a requirement specific of the lifecycle hooks implementation and of the behaviors for Cluster APIs E2E tests.
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		setupLog.Error(err, "error getting config for the cluster")
+		os.Exit(1)
+	}
+
+	client, err := client.New(restConfig, client.Options{})
+	if err != nil {
+		setupLog.Error(err, "error creating client to the cluster")
+		os.Exit(1)
+	}
+
+	// Create the Behaviors allowing E2E tests to reconfigure at runtime how each hook answers, e.g. blocking, failing,
+	// delaying or adding patches to the responses; each handler is wrapped so the behavior of its hook is applied.
+	// NOTE: this is specific of the CAPI test-extension, custom RuntimeExtension do not need it.
+	behaviors := behavior.New(client, behaviorsNamespace)
+
 	// Topology Mutation Hooks (Runtime Patches)
 
 	// Create the ExtensionHandlers for the Topology Mutation Hooks.
@@ -169,7 +201,7 @@ func main() {
 	if err := webhookServer.AddExtensionHandler(server.ExtensionHandler{
 		Hook:        runtimehooksv1.GeneratePatches,
 		Name:        "generate-patches",
-		HandlerFunc: topologyMutationExtensionHandlers.GeneratePatches,
+		HandlerFunc: behavior.Wrap(behaviors, runtimehooksv1.GeneratePatches, topologyMutationExtensionHandlers.GeneratePatches),
 	}); err != nil {
 		setupLog.Error(err, "error adding handler")
 		os.Exit(1)
@@ -178,7 +210,7 @@ func main() {
 	if err := webhookServer.AddExtensionHandler(server.ExtensionHandler{
 		Hook:        runtimehooksv1.ValidateTopology,
 		Name:        "validate-topology",
-		HandlerFunc: topologyMutationExtensionHandlers.ValidateTopology,
+		HandlerFunc: behavior.Wrap(behaviors, runtimehooksv1.ValidateTopology, topologyMutationExtensionHandlers.ValidateTopology),
 	}); err != nil {
 		setupLog.Error(err, "error adding handler")
 		os.Exit(1)
@@ -187,7 +219,7 @@ func main() {
 	if err := webhookServer.AddExtensionHandler(server.ExtensionHandler{
 		Hook:        runtimehooksv1.DiscoverVariables,
 		Name:        "discover-variables",
-		HandlerFunc: topologyMutationExtensionHandlers.DiscoverVariables,
+		HandlerFunc: behavior.Wrap(behaviors, runtimehooksv1.DiscoverVariables, topologyMutationExtensionHandlers.DiscoverVariables),
 	}); err != nil {
 		setupLog.Error(err, "error adding handler")
 		os.Exit(1)
@@ -195,20 +227,6 @@ func main() {
 
 	// Lifecycle Hooks
 
-	// Gets a client to access the Kubernetes cluster where this RuntimeExtension will be deployed to;
-	// this is a requirement specific of the lifecycle hooks implementation for Cluster APIs E2E tests.
-	restConfig, err := ctrl.GetConfig()
-	if err != nil {
-		setupLog.Error(err, "error getting config for the cluster")
-		os.Exit(1)
-	}
-
-	client, err := client.New(restConfig, client.Options{})
-	if err != nil {
-		setupLog.Error(err, "error creating client to the cluster")
-		os.Exit(1)
-	}
-
 	// Create the ExtensionHandlers for the lifecycle hooks
 	// NOTE: it is not mandatory to group all the ExtensionHandlers using a struct, what is important
 	// is to have HandlerFunc with the signature defined in sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1.
@@ -217,7 +235,7 @@ func main() {
 	if err := webhookServer.AddExtensionHandler(server.ExtensionHandler{
 		Hook:        runtimehooksv1.BeforeClusterCreate,
 		Name:        "before-cluster-create",
-		HandlerFunc: lifecycleExtensionHandlers.DoBeforeClusterCreate,
+		HandlerFunc: behavior.Wrap(behaviors, runtimehooksv1.BeforeClusterCreate, lifecycleExtensionHandlers.DoBeforeClusterCreate),
 	}); err != nil {
 		setupLog.Error(err, "error adding handler")
 		os.Exit(1)
@@ -226,7 +244,7 @@ func main() {
 	if err := webhookServer.AddExtensionHandler(server.ExtensionHandler{
 		Hook:        runtimehooksv1.AfterControlPlaneInitialized,
 		Name:        "after-control-plane-initialized",
-		HandlerFunc: lifecycleExtensionHandlers.DoAfterControlPlaneInitialized,
+		HandlerFunc: behavior.Wrap(behaviors, runtimehooksv1.AfterControlPlaneInitialized, lifecycleExtensionHandlers.DoAfterControlPlaneInitialized),
 	}); err != nil {
 		setupLog.Error(err, "error adding handler")
 		os.Exit(1)
@@ -235,7 +253,7 @@ func main() {
 	if err := webhookServer.AddExtensionHandler(server.ExtensionHandler{
 		Hook:        runtimehooksv1.BeforeClusterUpgrade,
 		Name:        "before-cluster-upgrade",
-		HandlerFunc: lifecycleExtensionHandlers.DoBeforeClusterUpgrade,
+		HandlerFunc: behavior.Wrap(behaviors, runtimehooksv1.BeforeClusterUpgrade, lifecycleExtensionHandlers.DoBeforeClusterUpgrade),
 	}); err != nil {
 		setupLog.Error(err, "error adding handler")
 		os.Exit(1)
@@ -244,7 +262,7 @@ func main() {
 	if err := webhookServer.AddExtensionHandler(server.ExtensionHandler{
 		Hook:        runtimehooksv1.AfterControlPlaneUpgrade,
 		Name:        "after-control-plane-upgrade",
-		HandlerFunc: lifecycleExtensionHandlers.DoAfterControlPlaneUpgrade,
+		HandlerFunc: behavior.Wrap(behaviors, runtimehooksv1.AfterControlPlaneUpgrade, lifecycleExtensionHandlers.DoAfterControlPlaneUpgrade),
 	}); err != nil {
 		setupLog.Error(err, "error adding handler")
 		os.Exit(1)
@@ -253,7 +271,7 @@ func main() {
 	if err := webhookServer.AddExtensionHandler(server.ExtensionHandler{
 		Hook:        runtimehooksv1.AfterClusterUpgrade,
 		Name:        "after-cluster-upgrade",
-		HandlerFunc: lifecycleExtensionHandlers.DoAfterClusterUpgrade,
+		HandlerFunc: behavior.Wrap(behaviors, runtimehooksv1.AfterClusterUpgrade, lifecycleExtensionHandlers.DoAfterClusterUpgrade),
 	}); err != nil {
 		setupLog.Error(err, "error adding handler")
 		os.Exit(1)
@@ -262,7 +280,7 @@ func main() {
 	if err := webhookServer.AddExtensionHandler(server.ExtensionHandler{
 		Hook:        runtimehooksv1.BeforeClusterDelete,
 		Name:        "before-cluster-delete",
-		HandlerFunc: lifecycleExtensionHandlers.DoBeforeClusterDelete,
+		HandlerFunc: behavior.Wrap(behaviors, runtimehooksv1.BeforeClusterDelete, lifecycleExtensionHandlers.DoBeforeClusterDelete),
 	}); err != nil {
 		setupLog.Error(err, "error adding handler")
 		os.Exit(1)
@@ -275,9 +293,19 @@ func main() {
 	// Setup a context listening for SIGINT.
 	ctx := ctrl.SetupSignalHandler()
 
+	shutdownTracing, err := tracing.Setup(ctx, "test-extension", tracingOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to setup tracing")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting RuntimeExtension", "version", version.Get().String())
 	if err := webhookServer.Start(ctx); err != nil {
 		setupLog.Error(err, "error running webhook server")
 		os.Exit(1)
 	}
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "problem flushing traces")
+	}
 }
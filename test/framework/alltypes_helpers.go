@@ -20,12 +20,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"path"
 	"path/filepath"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
@@ -55,7 +55,19 @@ func GetCAPIResources(ctx context.Context, input GetCAPIResourcesInput) []*unstr
 	Expect(input.Lister).NotTo(BeNil(), "input.Lister is required for GetCAPIResources")
 	Expect(input.Namespace).NotTo(BeEmpty(), "input.Namespace is required for GetCAPIResources")
 
-	types := getClusterAPITypes(ctx, input.Lister)
+	objList, err := getCAPIResources(ctx, input.Lister, input.Namespace)
+	if err != nil {
+		Fail(err.Error())
+	}
+	return objList
+}
+
+// getCAPIResources reads all the CAPI resources in a namespace, returning an error instead of failing the spec.
+func getCAPIResources(ctx context.Context, lister Lister, namespace string) ([]*unstructured.Unstructured, error) {
+	types, err := getClusterAPITypes(ctx, lister)
+	if err != nil {
+		return nil, err
+	}
 
 	objList := []*unstructured.Unstructured{}
 	for i := range types {
@@ -64,11 +76,11 @@ func GetCAPIResources(ctx context.Context, input GetCAPIResourcesInput) []*unstr
 		typeList.SetAPIVersion(typeMeta.APIVersion)
 		typeList.SetKind(typeMeta.Kind)
 
-		if err := input.Lister.List(ctx, typeList, client.InNamespace(input.Namespace)); err != nil {
+		if err := lister.List(ctx, typeList, client.InNamespace(namespace)); err != nil {
 			if apierrors.IsNotFound(err) {
 				continue
 			}
-			Fail(fmt.Sprintf("failed to list %q resources: %v", typeList.GroupVersionKind(), err))
+			return nil, errors.Wrapf(err, "failed to list %q resources", typeList.GroupVersionKind())
 		}
 		for i := range typeList.Items {
 			obj := typeList.Items[i]
@@ -76,18 +88,25 @@ func GetCAPIResources(ctx context.Context, input GetCAPIResourcesInput) []*unstr
 		}
 	}
 
-	return objList
+	return objList, nil
 }
 
 // getClusterAPITypes returns the list of TypeMeta to be considered for the move discovery phase.
 // This list includes all the types belonging to CAPI providers.
-func getClusterAPITypes(ctx context.Context, lister Lister) []metav1.TypeMeta {
+func getClusterAPITypes(ctx context.Context, lister Lister) ([]metav1.TypeMeta, error) {
 	discoveredTypes := []metav1.TypeMeta{}
 
 	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
-	Eventually(func() error {
-		return lister.List(ctx, crdList, capiProviderOptions()...)
-	}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "failed to list CRDs for CAPI providers")
+	var listErr error
+	_ = wait.PollUntilContextTimeout(ctx, retryableOperationInterval, retryableOperationTimeout, true, func(ctx context.Context) (bool, error) {
+		if listErr = lister.List(ctx, crdList, capiProviderOptions()...); listErr != nil {
+			return false, nil //nolint:nilerr
+		}
+		return true, nil
+	})
+	if listErr != nil {
+		return nil, errors.Wrap(listErr, "failed to list CRDs for CAPI providers")
+	}
 
 	for _, crd := range crdList.Items {
 		for _, version := range crd.Spec.Versions {
@@ -104,7 +123,7 @@ func getClusterAPITypes(ctx context.Context, lister Lister) []metav1.TypeMeta {
 			})
 		}
 	}
-	return discoveredTypes
+	return discoveredTypes, nil
 }
 
 // DumpAllResourcesInput is the input for DumpAllResources.
@@ -121,15 +140,22 @@ func DumpAllResources(ctx context.Context, input DumpAllResourcesInput) {
 	Expect(input.Lister).NotTo(BeNil(), "input.Lister is required for DumpAllResources")
 	Expect(input.Namespace).NotTo(BeEmpty(), "input.Namespace is required for DumpAllResources")
 
-	resources := GetCAPIResources(ctx, GetCAPIResourcesInput{
-		Lister:    input.Lister,
-		Namespace: input.Namespace,
-	})
+	Expect(dumpAllResources(ctx, input.Lister, input.Namespace, input.LogPath)).To(Succeed())
+}
+
+// dumpAllResources dumps Cluster API related resources to YAML, returning an error instead of failing the spec.
+func dumpAllResources(ctx context.Context, lister Lister, namespace, logPath string) error {
+	resources, err := getCAPIResources(ctx, lister, namespace)
+	if err != nil {
+		return err
+	}
 
 	for i := range resources {
-		r := resources[i]
-		dumpObject(r, input.LogPath)
+		if err := dumpObject(ctx, resources[i], logPath); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // DumpNamespaceAndGVK specifies a GVK and namespace to be dumped.
@@ -152,6 +178,11 @@ func DumpResourcesForCluster(ctx context.Context, input DumpResourcesForClusterI
 	Expect(input.Lister).NotTo(BeNil(), "input.Lister is required for DumpResourcesForCluster")
 	Expect(input.Cluster).NotTo(BeNil(), "input.Cluster is required for DumpResourcesForCluster")
 
+	Expect(dumpResourcesForCluster(ctx, input)).To(Succeed())
+}
+
+// dumpResourcesForCluster dumps specified resources to yaml, returning an error instead of failing the spec.
+func dumpResourcesForCluster(ctx context.Context, input DumpResourcesForClusterInput) error {
 	for _, resource := range input.Resources {
 		resourceList := new(unstructured.UnstructuredList)
 		resourceList.SetGroupVersionKind(resource.GVK)
@@ -168,30 +199,31 @@ func DumpResourcesForCluster(ctx context.Context, input DumpResourcesForClusterI
 			continue
 		}
 		for i := range resourceList.Items {
-			dumpObject(&resourceList.Items[i], input.LogPath)
+			if err := dumpObject(ctx, &resourceList.Items[i], input.LogPath); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
-func dumpObject(resource runtime.Object, logPath string) {
+func dumpObject(ctx context.Context, resource runtime.Object, logPath string) error {
 	resourceYAML, err := yaml.Marshal(resource)
-	Expect(err).ToNot(HaveOccurred(), "Failed to marshal %s", resource.GetObjectKind().GroupVersionKind().String())
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s", resource.GetObjectKind().GroupVersionKind().String())
+	}
 
 	metaObj, err := apimeta.Accessor(resource)
-	Expect(err).ToNot(HaveOccurred(), "Failed to get accessor for %s", resource.GetObjectKind().GroupVersionKind().String())
+	if err != nil {
+		return errors.Wrapf(err, "failed to get accessor for %s", resource.GetObjectKind().GroupVersionKind().String())
+	}
 
 	kind := resource.GetObjectKind().GroupVersionKind().Kind
 	namespace := metaObj.GetNamespace()
 	name := metaObj.GetName()
 
 	resourceFilePath := filepath.Clean(path.Join(logPath, namespace, kind, name+".yaml"))
-	Expect(os.MkdirAll(filepath.Dir(resourceFilePath), 0750)).To(Succeed(), "Failed to create folder %s", filepath.Dir(resourceFilePath))
-
-	f, err := os.OpenFile(resourceFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-	Expect(err).ToNot(HaveOccurred(), "Failed to open %s", resourceFilePath)
-	defer f.Close()
-
-	Expect(os.WriteFile(f.Name(), resourceYAML, 0600)).To(Succeed(), "Failed to write %s", resourceFilePath)
+	return errors.Wrapf(writeArtifact(ctx, resourceFilePath, resourceYAML), "failed to write %s", resourceFilePath)
 }
 
 // capiProviderOptions returns a set of ListOptions that allows to identify all the objects belonging to Cluster API providers.
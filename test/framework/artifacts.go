@@ -0,0 +1,247 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	osExec "os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api/util"
+)
+
+// ArtifactStore stores the artifacts collected by the framework, e.g. the logs of the controllers, the dumps of the
+// resources and the logs of the machines, instead of writing them to the local artifacts folder.
+type ArtifactStore interface {
+	// Create returns a writer for the artifact with the given path in the local artifacts folder;
+	// the artifact is stored when the writer is closed.
+	Create(ctx context.Context, artifactPath string) (io.WriteCloser, error)
+}
+
+var (
+	artifactStoreLock sync.RWMutex
+	artifactStore     ArtifactStore
+)
+
+// SetArtifactStore sets the store used for all the artifacts collected by the framework in the current process;
+// if not set, or set to nil, the artifacts are written to the local artifacts folder.
+// NOTE: when running specs in parallel, the store must be set in each Ginkgo parallel process, e.g. in the second
+// func of SynchronizedBeforeSuite.
+func SetArtifactStore(store ArtifactStore) {
+	artifactStoreLock.Lock()
+	defer artifactStoreLock.Unlock()
+	artifactStore = store
+}
+
+// getArtifactStore returns the artifact store, or nil if the artifacts are written to the local artifacts folder.
+func getArtifactStore() ArtifactStore {
+	artifactStoreLock.RLock()
+	defer artifactStoreLock.RUnlock()
+	return artifactStore
+}
+
+// createArtifact returns a writer for the artifact with the given path, using the artifact store if set, otherwise
+// creating a file at path, including its parent directories.
+func createArtifact(ctx context.Context, artifactPath string) (io.WriteCloser, error) {
+	if store := getArtifactStore(); store != nil {
+		return store.Create(ctx, artifactPath)
+	}
+	return fileOnHost(artifactPath)
+}
+
+// writeArtifact writes data to the artifact with the given path.
+func writeArtifact(ctx context.Context, artifactPath string, data []byte) error {
+	w, err := createArtifact(ctx, artifactPath)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// writeTarToArtifacts stores each regular file of a tar stream as an artifact, with its path relative to outputDir.
+func writeTarToArtifacts(ctx context.Context, r io.Reader, outputDir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read tar stream")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		w, err := createArtifact(ctx, filepath.Join(outputDir, filepath.Clean(header.Name)))
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, tr); err != nil { //nolint:gosec // The size of the artifacts is bounded by the size of the logs.
+			_ = w.Close()
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+}
+
+// ObjectStorageArtifactStoreInput is the input for NewObjectStorageArtifactStore.
+type ObjectStorageArtifactStoreInput struct {
+	// URL is the bucket where to store the artifacts, optionally with a prefix, e.g. s3://bucket/prefix for Amazon S3,
+	// gs://bucket/prefix for Google Cloud Storage, or azblob://account/container/prefix for Azure Blob Storage.
+	URL string
+
+	// ArtifactFolder is the local artifacts folder; the artifacts are stored with their path relative to this folder.
+	ArtifactFolder string
+
+	// RunID is added to the prefix of all the artifacts, so the artifacts of different test runs do not overwrite
+	// each other. If not set, a RunID is generated from the current time.
+	RunID string
+}
+
+// ObjectStorageArtifactStore is an ArtifactStore streaming the artifacts to a bucket of an object storage service,
+// without writing them to the local disk.
+// The artifacts are uploaded with the CLI of the object storage service, i.e. aws for Amazon S3, gcloud for Google Cloud
+// Storage and az for Azure Blob Storage, which must be installed and authenticated.
+type ObjectStorageArtifactStore struct {
+	scheme         string
+	bucket         string
+	account        string
+	prefix         string
+	runID          string
+	artifactFolder string
+}
+
+// NewObjectStorageArtifactStore returns an ArtifactStore streaming the artifacts to a bucket of an object storage service.
+func NewObjectStorageArtifactStore(input ObjectStorageArtifactStoreInput) (*ObjectStorageArtifactStore, error) {
+	u, err := url.Parse(input.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid artifacts URL %q", input.URL)
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("invalid artifacts URL %q: the bucket is required", input.URL)
+	}
+
+	runID := input.RunID
+	if runID == "" {
+		runID = fmt.Sprintf("%s-%s", time.Now().UTC().Format("20060102-150405"), util.RandomString(6))
+	}
+
+	s := &ObjectStorageArtifactStore{
+		scheme:         u.Scheme,
+		bucket:         u.Host,
+		prefix:         strings.Trim(u.Path, "/"),
+		runID:          runID,
+		artifactFolder: input.ArtifactFolder,
+	}
+	switch u.Scheme {
+	case "s3", "gs":
+	case "azblob":
+		// For Azure the host is the storage account and the first element of the path is the container.
+		container, prefix, _ := strings.Cut(s.prefix, "/")
+		if container == "" {
+			return nil, errors.Errorf("invalid artifacts URL %q: the container is required", input.URL)
+		}
+		s.account, s.bucket, s.prefix = u.Host, container, prefix
+	default:
+		return nil, errors.Errorf("invalid artifacts URL %q: the scheme must be one of s3, gs or azblob", input.URL)
+	}
+	s.prefix = path.Join(s.prefix, runID)
+	return s, nil
+}
+
+// RunID returns the ID of the test run added to the prefix of all the artifacts.
+func (s *ObjectStorageArtifactStore) RunID() string {
+	return s.runID
+}
+
+// Key returns the key of the object storing the artifact with the given path.
+func (s *ObjectStorageArtifactStore) Key(artifactPath string) string {
+	rel := filepath.Clean(artifactPath)
+	if s.artifactFolder != "" {
+		if r, err := filepath.Rel(s.artifactFolder, artifactPath); err == nil && !strings.HasPrefix(r, "..") {
+			rel = r
+		}
+	}
+	return path.Join(s.prefix, strings.TrimPrefix(filepath.ToSlash(rel), "/"))
+}
+
+// Create returns a writer streaming the artifact with the given path to the bucket.
+// NOTE: the upload is not bound to ctx, so artifacts streamed until the end of the test, e.g. the logs of the controllers,
+// are stored even if the context used for watching them is cancelled.
+func (s *ObjectStorageArtifactStore) Create(_ context.Context, artifactPath string) (io.WriteCloser, error) {
+	key := s.Key(artifactPath)
+
+	var cmd *osExec.Cmd
+	switch s.scheme {
+	case "s3":
+		cmd = osExec.Command("aws", "s3", "cp", "--only-show-errors", "-", fmt.Sprintf("s3://%s/%s", s.bucket, key)) //nolint:gosec // We don't care about command injection here.
+	case "gs":
+		cmd = osExec.Command("gcloud", "storage", "cp", "-", fmt.Sprintf("gs://%s/%s", s.bucket, key)) //nolint:gosec // We don't care about command injection here.
+	case "azblob":
+		cmd = osExec.Command("az", "storage", "blob", "upload", "--only-show-errors", "--overwrite", //nolint:gosec // We don't care about command injection here.
+			"--account-name", s.account, "--container-name", s.bucket, "--name", key, "--file", "/dev/stdin")
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create artifact %s", key)
+	}
+	stdErr := &bytes.Buffer{}
+	cmd.Stderr = stdErr
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "failed to create artifact %s", key)
+	}
+	return &uploadWriter{WriteCloser: stdin, cmd: cmd, stdErr: stdErr, key: key}, nil
+}
+
+// uploadWriter streams an artifact to the stdin of the command uploading it.
+type uploadWriter struct {
+	io.WriteCloser
+	cmd    *osExec.Cmd
+	stdErr *bytes.Buffer
+	key    string
+}
+
+// Close completes the upload of the artifact and waits for the upload command to exit.
+func (w *uploadWriter) Close() error {
+	if err := w.WriteCloser.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return errors.Wrapf(err, "failed to upload artifact %s", w.key)
+	}
+	if err := w.cmd.Wait(); err != nil {
+		return errors.Wrapf(err, "failed to upload artifact %s: %s", w.key, w.stdErr.String())
+	}
+	return nil
+}
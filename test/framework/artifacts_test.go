@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/test/framework"
+)
+
+func TestObjectStorageArtifactStoreKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		artifactPath string
+		wantKey      string
+		wantErr      bool
+	}{
+		{
+			name:         "S3 bucket with prefix",
+			url:          "s3://bucket/e2e/",
+			artifactPath: "/artifacts/clusters/bootstrap/resources/Cluster/test.yaml",
+			wantKey:      "e2e/run/clusters/bootstrap/resources/Cluster/test.yaml",
+		},
+		{
+			name:         "GCS bucket without prefix",
+			url:          "gs://bucket",
+			artifactPath: "/artifacts/clusters/test/machines/m1/journal.log",
+			wantKey:      "run/clusters/test/machines/m1/journal.log",
+		},
+		{
+			name:         "Azure container with prefix",
+			url:          "azblob://account/container/e2e",
+			artifactPath: "/artifacts/clusters/bootstrap/logs/capi-system/capi-controller-manager/manager.log",
+			wantKey:      "e2e/run/clusters/bootstrap/logs/capi-system/capi-controller-manager/manager.log",
+		},
+		{
+			name:         "Path outside of the artifact folder",
+			url:          "s3://bucket",
+			artifactPath: "/tmp/other/file.log",
+			wantKey:      "run/tmp/other/file.log",
+		},
+		{
+			name:    "Azure URL without container",
+			url:     "azblob://account",
+			wantErr: true,
+		},
+		{
+			name:    "URL without bucket",
+			url:     "s3:///prefix",
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported scheme",
+			url:     "ftp://bucket/prefix",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			store, err := framework.NewObjectStorageArtifactStore(framework.ObjectStorageArtifactStoreInput{
+				URL:            tt.url,
+				ArtifactFolder: "/artifacts",
+				RunID:          "run",
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(store.RunID()).To(Equal("run"))
+			g.Expect(store.Key(tt.artifactPath)).To(Equal(tt.wantKey))
+		})
+	}
+}
+
+func TestObjectStorageArtifactStoreRunID(t *testing.T) {
+	g := NewWithT(t)
+
+	store, err := framework.NewObjectStorageArtifactStore(framework.ObjectStorageArtifactStoreInput{
+		URL: "s3://bucket",
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(store.RunID()).ToNot(BeEmpty())
+	g.Expect(store.Key("file.log")).To(Equal(store.RunID() + "/file.log"))
+}
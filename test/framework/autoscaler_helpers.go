@@ -23,15 +23,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
@@ -39,6 +42,8 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	. "sigs.k8s.io/cluster-api/test/framework/ginkgoextensions"
 	"sigs.k8s.io/cluster-api/test/framework/internal/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
@@ -68,6 +73,11 @@ type ApplyAutoscalerToWorkloadClusterInput struct {
 	WorkloadYamlPath  string
 	AutoscalerVersion string
 
+	// InfrastructureMachinePoolKind should be the plural form of the InfraMachinePool kind, in lower case, e.g.
+	// dockermachinepools; it is required only when MachinePools are autoscaled from zero, so the autoscaler can read
+	// the capacity of their machines.
+	InfrastructureMachinePoolKind string
+
 	ManagementClusterProxy ClusterProxy
 	Cluster                *clusterv1.Cluster
 	WorkloadClusterProxy   ClusterProxy
@@ -89,7 +99,11 @@ func ApplyAutoscalerToWorkloadCluster(ctx context.Context, input ApplyAutoscaler
 	// This address should be accessible from the workload cluster.
 	serverAddr, mgtClusterCA := getServerAddrAndCA(ctx, input.ManagementClusterProxy)
 	// Generate a token with the required permission that can be used by the autoscaler.
-	token := getAuthenticationTokenForAutoscaler(ctx, input.ManagementClusterProxy, input.Cluster.Namespace, input.Cluster.Name, input.InfrastructureMachineTemplateKind)
+	infraKinds := []string{input.InfrastructureMachineTemplateKind}
+	if input.InfrastructureMachinePoolKind != "" {
+		infraKinds = append(infraKinds, input.InfrastructureMachinePoolKind)
+	}
+	token := getAuthenticationTokenForAutoscaler(ctx, input.ManagementClusterProxy, input.Cluster.Namespace, input.Cluster.Name, infraKinds)
 
 	workloadYaml, err := ProcessYAML(&ProcessYAMLInput{
 		Template:             workloadYamlTemplate,
@@ -213,6 +227,240 @@ func AddScaleUpDeploymentAndWait(ctx context.Context, input AddScaleUpDeployment
 	}, intervals...)
 }
 
+// autoscalerCapacityAnnotationPrefix is the prefix of the annotations defining the capacity of the nodes of a node group
+// for the autoscaler, e.g. capacity.cluster-autoscaler.kubernetes.io/memory.
+const autoscalerCapacityAnnotationPrefix = "capacity.cluster-autoscaler.kubernetes.io/"
+
+// scaleFromZeroDeploymentName is the name of the deployment forcing a node group to scale from zero.
+const scaleFromZeroDeploymentName = "scale-from-zero"
+
+// AssertScaleFromZeroCapacityInput is the input for AssertScaleFromZeroCapacity.
+type AssertScaleFromZeroCapacityInput struct {
+	Getter Getter
+
+	// NodeGroup is the MachineDeployment or the MachinePool to be scaled from zero.
+	NodeGroup client.Object
+}
+
+// AssertScaleFromZeroCapacity verifies that the autoscaler can get the capacity of the nodes of a node group
+// scaled from zero, i.e. that the capacity of cpu and memory is defined either by the capacity annotations of the
+// node group or by the status.capacity of its infrastructure template, and returns the capacity.
+func AssertScaleFromZeroCapacity(ctx context.Context, input AssertScaleFromZeroCapacityInput, intervals ...interface{}) corev1.ResourceList {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for AssertScaleFromZeroCapacity")
+	Expect(input.Getter).ToNot(BeNil(), "Invalid argument. input.Getter can't be nil when calling AssertScaleFromZeroCapacity")
+	Expect(input.NodeGroup).ToNot(BeNil(), "Invalid argument. input.NodeGroup can't be nil when calling AssertScaleFromZeroCapacity")
+
+	Byf("Checking the capacity of the nodes of %s is defined for scaling from zero", klog.KObj(input.NodeGroup))
+	var capacity corev1.ResourceList
+	Eventually(func(g Gomega) {
+		nodeGroup := input.NodeGroup.DeepCopyObject().(client.Object)
+		g.Expect(input.Getter.Get(ctx, client.ObjectKeyFromObject(input.NodeGroup), nodeGroup)).To(Succeed())
+
+		// The capacity annotations of the node group take precedence over the capacity of the infrastructure template.
+		capacity = corev1.ResourceList{}
+		g.Expect(addScaleFromZeroCapacity(capacity, capacityAnnotations(nodeGroup.GetAnnotations()))).To(Succeed(), "invalid capacity annotations on %s", klog.KObj(nodeGroup))
+
+		if len(capacity) < 2 {
+			_, infraRef := nodeGroupReplicasAndInfrastructureRef(nodeGroup)
+			infraTemplate := &unstructured.Unstructured{}
+			infraTemplate.SetAPIVersion(infraRef.APIVersion)
+			infraTemplate.SetKind(infraRef.Kind)
+			g.Expect(input.Getter.Get(ctx, client.ObjectKey{Namespace: nodeGroup.GetNamespace(), Name: infraRef.Name}, infraTemplate)).To(Succeed())
+
+			status, _, err := unstructured.NestedStringMap(infraTemplate.Object, "status", "capacity")
+			g.Expect(err).ToNot(HaveOccurred(), "invalid status.capacity of %s %s", infraRef.Kind, klog.KObj(infraTemplate))
+			g.Expect(addScaleFromZeroCapacity(capacity, status)).To(Succeed(), "invalid status.capacity of %s %s", infraRef.Kind, klog.KObj(infraTemplate))
+		}
+
+		g.Expect(capacity).To(HaveKey(corev1.ResourceCPU), "the cpu capacity of the nodes of %s is not defined", klog.KObj(nodeGroup))
+		g.Expect(capacity).To(HaveKey(corev1.ResourceMemory), "the memory capacity of the nodes of %s is not defined", klog.KObj(nodeGroup))
+	}, intervals...).Should(Succeed(), "Failed to get the capacity of the nodes of %s for scaling from zero", klog.KObj(input.NodeGroup))
+	return capacity
+}
+
+// capacityAnnotations returns the values of the capacity annotations, by resource name.
+func capacityAnnotations(annotations map[string]string) map[string]string {
+	values := map[string]string{}
+	for key, value := range annotations {
+		if name, ok := strings.CutPrefix(key, autoscalerCapacityAnnotationPrefix); ok {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// addScaleFromZeroCapacity adds to capacity the cpu and memory defined in values, by resource name, unless they are
+// already defined.
+func addScaleFromZeroCapacity(capacity corev1.ResourceList, values map[string]string) error {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if _, ok := capacity[name]; ok {
+			continue
+		}
+		value, ok := values[string(name)]
+		if !ok {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s capacity %q", name, value)
+		}
+		capacity[name] = quantity
+	}
+	return nil
+}
+
+// nodeGroupReplicasAndInfrastructureRef returns the replicas and the reference to the infrastructure template of a
+// MachineDeployment or of a MachinePool.
+func nodeGroupReplicasAndInfrastructureRef(nodeGroup client.Object) (int32, corev1.ObjectReference) {
+	switch ng := nodeGroup.(type) {
+	case *clusterv1.MachineDeployment:
+		return pointer.Int32Deref(ng.Spec.Replicas, 0), ng.Spec.Template.Spec.InfrastructureRef
+	case *expv1.MachinePool:
+		return pointer.Int32Deref(ng.Spec.Replicas, 0), ng.Spec.Template.Spec.InfrastructureRef
+	default:
+		Fail(fmt.Sprintf("node group %s must be a MachineDeployment or a MachinePool, got %T", klog.KObj(nodeGroup), nodeGroup))
+		return 0, corev1.ObjectReference{}
+	}
+}
+
+// AddScaleFromZeroDeploymentAndWaitInput is the input for AddScaleFromZeroDeploymentAndWait.
+type AddScaleFromZeroDeploymentAndWaitInput struct {
+	ClusterProxy ClusterProxy
+
+	// Capacity is the capacity of the nodes of the node group scaled from zero, as returned by AssertScaleFromZeroCapacity.
+	Capacity corev1.ResourceList
+
+	// NodeSelector, if set, is the node selector of the deployment, e.g. to target the node group scaled from zero
+	// when the cluster has many node groups.
+	NodeSelector map[string]string
+}
+
+// AddScaleFromZeroDeploymentAndWait creates a deployment with a Pod requesting 60% of the memory of a node of the node
+// group scaled from zero, which forces the autoscaler to create the first machine of the node group, and waits for the
+// deployment to be available, i.e. for the machine to be created and for its node to be ready.
+func AddScaleFromZeroDeploymentAndWait(ctx context.Context, input AddScaleFromZeroDeploymentAndWaitInput, intervals ...interface{}) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for AddScaleFromZeroDeploymentAndWait")
+	Expect(input.ClusterProxy).ToNot(BeNil(), "Invalid argument. input.ClusterProxy can't be nil when calling AddScaleFromZeroDeploymentAndWait")
+	Expect(input.Capacity).To(HaveKey(corev1.ResourceMemory), "Invalid argument. input.Capacity must define the memory when calling AddScaleFromZeroDeploymentAndWait")
+
+	scaleFromZeroDeployment := newScaleFromZeroDeployment(input.Capacity, input.NodeSelector)
+
+	By("Create scale from zero deployment")
+	Expect(input.ClusterProxy.GetClient().Create(ctx, scaleFromZeroDeployment)).To(Succeed(), "failed to create the scale from zero deployment")
+
+	By("Wait for the scale from zero deployment to become ready (this implies the first machine to be created)")
+	WaitForDeploymentsAvailable(ctx, WaitForDeploymentsAvailableInput{
+		Getter:     input.ClusterProxy.GetClient(),
+		Deployment: scaleFromZeroDeployment,
+	}, intervals...)
+}
+
+// newScaleFromZeroDeployment returns the deployment created by AddScaleFromZeroDeploymentAndWait, with a Pod requesting
+// 60% of the memory of a node of the node group scaled from zero.
+func newScaleFromZeroDeployment(capacity corev1.ResourceList, nodeSelector map[string]string) *appsv1.Deployment {
+	memory := capacity[corev1.ResourceMemory]
+	podMemory := resource.NewQuantity(int64(float64(memory.Value())*0.6), resource.BinarySI)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scaleFromZeroDeploymentName,
+			Namespace: metav1.NamespaceDefault,
+			Labels: map[string]string{
+				"app": scaleFromZeroDeploymentName,
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(1),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": scaleFromZeroDeploymentName,
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": scaleFromZeroDeploymentName,
+					},
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector: nodeSelector,
+					Containers: []corev1.Container{
+						{
+							Name:  "busybox",
+							Image: "busybox",
+							Resources: corev1.ResourceRequirements{
+								Requests: map[corev1.ResourceName]resource.Quantity{
+									corev1.ResourceMemory: *podMemory,
+								},
+							},
+							Command: []string{"/bin/sh", "-c", "echo \"up\" & sleep infinity"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// DeleteScaleFromZeroDeploymentInput is the input for DeleteScaleFromZeroDeployment.
+type DeleteScaleFromZeroDeploymentInput struct {
+	ClusterProxy ClusterProxy
+}
+
+// DeleteScaleFromZeroDeployment deletes the deployment created by AddScaleFromZeroDeploymentAndWait, so the nodes of
+// the node group scaled from zero are not needed anymore and the autoscaler can scale it back to zero.
+func DeleteScaleFromZeroDeployment(ctx context.Context, input DeleteScaleFromZeroDeploymentInput) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for DeleteScaleFromZeroDeployment")
+	Expect(input.ClusterProxy).ToNot(BeNil(), "Invalid argument. input.ClusterProxy can't be nil when calling DeleteScaleFromZeroDeployment")
+
+	By("Delete scale from zero deployment")
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scaleFromZeroDeploymentName,
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	Expect(client.IgnoreNotFound(input.ClusterProxy.GetClient().Delete(ctx, deployment))).To(Succeed(), "failed to delete the scale from zero deployment")
+}
+
+// WaitForNodeGroupReplicasInput is the input for WaitForNodeGroupReplicas.
+type WaitForNodeGroupReplicasInput struct {
+	Getter Getter
+
+	// NodeGroup is the MachineDeployment or the MachinePool scaled by the autoscaler.
+	NodeGroup client.Object
+
+	Replicas int32
+}
+
+// WaitForNodeGroupReplicas waits for the autoscaler to scale a node group, i.e. a MachineDeployment or a MachinePool, to
+// the given replicas and for all of them to be ready, e.g. for the first machine of a node group scaled from zero,
+// or for all the machines to be gone when it is scaled back to zero.
+func WaitForNodeGroupReplicas(ctx context.Context, input WaitForNodeGroupReplicasInput, intervals ...interface{}) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for WaitForNodeGroupReplicas")
+	Expect(input.Getter).ToNot(BeNil(), "Invalid argument. input.Getter can't be nil when calling WaitForNodeGroupReplicas")
+	Expect(input.NodeGroup).ToNot(BeNil(), "Invalid argument. input.NodeGroup can't be nil when calling WaitForNodeGroupReplicas")
+
+	Byf("Waiting for %s to be scaled to %d replicas", klog.KObj(input.NodeGroup), input.Replicas)
+	history := NewConditionHistory()
+	Eventually(func(g Gomega) {
+		nodeGroup := input.NodeGroup.DeepCopyObject().(client.Object)
+		g.Expect(input.Getter.Get(ctx, client.ObjectKeyFromObject(input.NodeGroup), nodeGroup)).To(Succeed())
+		history.Record(nodeGroup)
+
+		replicas, _ := nodeGroupReplicasAndInfrastructureRef(nodeGroup)
+		g.Expect(replicas).To(Equal(input.Replicas), "%s replicas should match expected replicas", klog.KObj(nodeGroup))
+		switch ng := nodeGroup.(type) {
+		case *clusterv1.MachineDeployment:
+			g.Expect(ng.Status.Replicas).To(Equal(input.Replicas))
+			g.Expect(ng.Status.ReadyReplicas).To(Equal(input.Replicas))
+		case *expv1.MachinePool:
+			g.Expect(ng.Status.Replicas).To(Equal(input.Replicas))
+			g.Expect(ng.Status.ReadyReplicas).To(Equal(input.Replicas))
+		}
+	}, intervals...).Should(Succeed(), history.Describe("Timed out waiting for %s to be scaled to %d replicas", klog.KObj(input.NodeGroup), input.Replicas))
+}
+
 type ProcessYAMLInput struct {
 	Template             []byte
 	ClusterctlConfigPath string
@@ -355,7 +603,7 @@ func EnableAutoscalerForMachineDeploymentTopologyAndWait(ctx context.Context, in
 
 // getAuthenticationTokenForAutoscaler returns a bearer authenticationToken with minimal RBAC permissions that will be used
 // by the autoscaler running on the workload cluster to access the management cluster.
-func getAuthenticationTokenForAutoscaler(ctx context.Context, managementClusterProxy ClusterProxy, namespace string, cluster string, infraMachineTemplateKind string) string {
+func getAuthenticationTokenForAutoscaler(ctx context.Context, managementClusterProxy ClusterProxy, namespace string, cluster string, infraKinds []string) string {
 	name := fmt.Sprintf("cluster-%s", cluster)
 	sa := &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
@@ -379,7 +627,7 @@ func getAuthenticationTokenForAutoscaler(ctx context.Context, managementClusterP
 			{
 				Verbs:     []string{"get", "list"},
 				APIGroups: []string{"infrastructure.cluster.x-k8s.io"},
-				Resources: []string{infraMachineTemplateKind},
+				Resources: infraKinds,
 			},
 		},
 	}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+func TestScaleFromZeroCapacity(t *testing.T) {
+	t.Run("annotations take precedence over the infrastructure template", func(t *testing.T) {
+		g := NewWithT(t)
+
+		capacity := corev1.ResourceList{}
+		g.Expect(addScaleFromZeroCapacity(capacity, capacityAnnotations(map[string]string{
+			"capacity.cluster-autoscaler.kubernetes.io/memory":            "4G",
+			"capacity.cluster-autoscaler.kubernetes.io/ephemeral-storage": "20G",
+			"cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size": "0",
+		}))).To(Succeed())
+		g.Expect(capacity).To(Equal(corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4G")}))
+
+		g.Expect(addScaleFromZeroCapacity(capacity, map[string]string{"cpu": "2", "memory": "8G"})).To(Succeed())
+		g.Expect(capacity).To(Equal(corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("4G"),
+		}))
+	})
+
+	t.Run("missing capacity is not defined", func(t *testing.T) {
+		g := NewWithT(t)
+
+		capacity := corev1.ResourceList{}
+		g.Expect(addScaleFromZeroCapacity(capacity, capacityAnnotations(nil))).To(Succeed())
+		g.Expect(addScaleFromZeroCapacity(capacity, map[string]string{"cpu": "2"})).To(Succeed())
+		g.Expect(capacity).To(Equal(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}))
+	})
+
+	t.Run("invalid capacity", func(t *testing.T) {
+		g := NewWithT(t)
+
+		capacity := corev1.ResourceList{}
+		g.Expect(addScaleFromZeroCapacity(capacity, map[string]string{"memory": "lots"})).ToNot(Succeed())
+		g.Expect(capacity).To(BeEmpty())
+	})
+}
+
+func TestNewScaleFromZeroDeployment(t *testing.T) {
+	g := NewWithT(t)
+
+	deployment := newScaleFromZeroDeployment(
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("10Gi")},
+		map[string]string{"node-group": "md-0"},
+	)
+	g.Expect(deployment.Name).To(Equal(scaleFromZeroDeploymentName))
+	g.Expect(deployment.Spec.Selector.MatchLabels).To(Equal(deployment.Spec.Template.Labels))
+	g.Expect(deployment.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"node-group": "md-0"}))
+	g.Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+
+	// The Pod requests 60% of the memory of a node of the node group scaled from zero.
+	memory := deployment.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceMemory]
+	g.Expect(memory.Value()).To(Equal(int64(6 * 1024 * 1024 * 1024)))
+}
+
+func TestNodeGroupReplicasAndInfrastructureRef(t *testing.T) {
+	g := NewWithT(t)
+
+	infraRef := corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "DockerMachineTemplate", Name: "md-0"}
+	md := &clusterv1.MachineDeployment{}
+	md.Spec.Replicas = pointer.Int32(2)
+	md.Spec.Template.Spec.InfrastructureRef = infraRef
+
+	replicas, ref := nodeGroupReplicasAndInfrastructureRef(md)
+	g.Expect(replicas).To(Equal(int32(2)))
+	g.Expect(ref).To(Equal(infraRef))
+
+	// Node groups scaled to zero by the autoscaler have no replicas.
+	infraRef = corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "DockerMachinePool", Name: "mp-0"}
+	mp := &expv1.MachinePool{}
+	mp.Spec.Template.Spec.InfrastructureRef = infraRef
+
+	replicas, ref = nodeGroupReplicasAndInfrastructureRef(mp)
+	g.Expect(replicas).To(BeZero())
+	g.Expect(ref).To(Equal(infraRef))
+}
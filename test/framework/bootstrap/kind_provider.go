@@ -30,6 +30,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/framework/internal/log"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
 )
 
 const (
@@ -164,7 +165,7 @@ func (k *KindClusterProvider) createKindCluster() {
 		kind.CreateWithNodeImage(nodeImage),
 		kind.CreateWithRetain(true))
 
-	provider := kind.NewProvider(kind.ProviderWithLogger(cmd.NewLogger()))
+	provider := kind.NewProvider(kindProviderOptions(kind.ProviderWithLogger(cmd.NewLogger()))...)
 	err := provider.Create(k.name, kindCreateOptions...)
 	if err != nil {
 		// if requested, dump kind logs
@@ -184,14 +185,15 @@ func (k *KindClusterProvider) createKindCluster() {
 	}
 }
 
-// setDockerSockConfig returns a kind config for mounting /var/run/docker.sock into the kind node.
+// setDockerSockConfig returns a kind config for mounting the container runtime socket into the kind node
+// as /var/run/docker.sock.
 func setDockerSockConfig(cfg *kindv1.Cluster) {
 	cfg.Nodes = []kindv1.Node{
 		{
 			Role: kindv1.ControlPlaneRole,
 			ExtraMounts: []kindv1.Mount{
 				{
-					HostPath:      "/var/run/docker.sock",
+					HostPath:      container.RuntimeSocket(),
 					ContainerPath: "/var/run/docker.sock",
 				},
 			},
@@ -199,6 +201,15 @@ func setDockerSockConfig(cfg *kindv1.Cluster) {
 	}
 }
 
+// kindProviderOptions returns the options for a kind provider using the container runtime selected
+// using the container.RuntimeEnvVar environment variable.
+func kindProviderOptions(options ...kind.ProviderOption) []kind.ProviderOption {
+	if container.RuntimeName() == container.PodmanRuntime {
+		options = append(options, kind.ProviderWithPodman())
+	}
+	return options
+}
+
 // GetKubeconfigPath returns the path to the kubeconfig file for the cluster.
 func (k *KindClusterProvider) GetKubeconfigPath() string {
 	return k.kubeconfigPath
@@ -208,7 +219,7 @@ func (k *KindClusterProvider) GetKubeconfigPath() string {
 func (k *KindClusterProvider) Dispose(ctx context.Context) {
 	Expect(ctx).NotTo(BeNil(), "ctx is required for Dispose")
 
-	if err := kind.NewProvider().Delete(k.name, k.kubeconfigPath); err != nil {
+	if err := kind.NewProvider(kindProviderOptions()...).Delete(k.name, k.kubeconfigPath); err != nil {
 		log.Logf("Deleting the kind cluster %q failed. You may need to remove this by hand.", k.name)
 	}
 	if err := os.Remove(k.kubeconfigPath); err != nil {
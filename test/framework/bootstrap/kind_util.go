@@ -116,9 +116,9 @@ func LoadImagesToKindCluster(ctx context.Context, input LoadImagesToKindClusterI
 		return errors.New("Invalid argument. Name can't be empty when calling LoadImagesToKindCluster")
 	}
 
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntimeClient("")
 	if err != nil {
-		return errors.Wrap(err, "failed to get Docker runtime client")
+		return errors.Wrap(err, "failed to get container runtime client")
 	}
 	ctx = container.RuntimeInto(ctx, containerRuntime)
 
@@ -172,7 +172,7 @@ func loadImage(ctx context.Context, cluster, image string) error {
 	}
 
 	// Gets the nodes in the cluster
-	provider := kind.NewProvider()
+	provider := kind.NewProvider(kindProviderOptions()...)
 	nodeList, err := provider.ListInternalNodes(cluster)
 	if err != nil {
 		return err
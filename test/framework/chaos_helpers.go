@@ -0,0 +1,410 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	. "sigs.k8s.io/cluster-api/test/framework/ginkgoextensions"
+	"sigs.k8s.io/cluster-api/test/framework/internal/log"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	"sigs.k8s.io/cluster-api/util/annotations"
+)
+
+// The helpers in this file inject failures in a management or in a workload cluster, so test specs can validate that
+// Cluster API and the providers recover from them.
+// Each helper returns a func restoring the original state, which is also registered with DeferCleanup, so the state is
+// restored at the end of the spec even if the spec fails; for this reason the helpers must be called from within
+// a Ginkgo setup or subject node, e.g. an It. Calling the restore func more than once is a no-op.
+
+// kindClusterLabelKey is the label applied to the containers of kind clusters and of CAPD clusters, with the cluster name as value.
+const kindClusterLabelKey = "io.x-k8s.kind.cluster"
+
+// corruptedSecretValue is the value used to replace the data of the corrupted Secrets.
+var corruptedSecretValue = []byte("corrupted-by-e2e-test")
+
+// deferRestore registers restore with DeferCleanup and returns a func calling it once.
+func deferRestore(restore func()) func() {
+	once := sync.Once{}
+	restoreOnce := func() { once.Do(restore) }
+	DeferCleanup(restoreOnce)
+	return restoreOnce
+}
+
+// RestartControllerPodsInput is the input for RestartControllerPods.
+type RestartControllerPodsInput struct {
+	Client     client.Client
+	Deployment *appsv1.Deployment
+}
+
+// RestartControllerPods kills all the Pods of a controller Deployment, e.g. a provider's controller manager,
+// and waits for the Deployment to be available again with new Pods.
+func RestartControllerPods(ctx context.Context, input RestartControllerPodsInput, intervals ...interface{}) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for RestartControllerPods")
+	Expect(input.Client).ToNot(BeNil(), "Invalid argument. input.Client can't be nil when calling RestartControllerPods")
+	Expect(input.Deployment).ToNot(BeNil(), "Invalid argument. input.Deployment can't be nil when calling RestartControllerPods")
+
+	Byf("Killing the Pods of the Deployment %s", klog.KObj(input.Deployment))
+	Expect(input.Deployment.Spec.Selector).ToNot(BeNil(), "Deployment %s has no selector", klog.KObj(input.Deployment))
+	podList := &corev1.PodList{}
+	Eventually(func() error {
+		return input.Client.List(ctx, podList, client.InNamespace(input.Deployment.Namespace), client.MatchingLabels(input.Deployment.Spec.Selector.MatchLabels))
+	}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to list the Pods of the Deployment %s", klog.KObj(input.Deployment))
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		Eventually(func() error {
+			if err := input.Client.Delete(ctx, pod, client.GracePeriodSeconds(0)); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			return nil
+		}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to delete Pod %s", klog.KObj(pod))
+	}
+
+	Eventually(func() error {
+		pods := &corev1.PodList{}
+		if err := input.Client.List(ctx, pods, client.InNamespace(input.Deployment.Namespace), client.MatchingLabels(input.Deployment.Spec.Selector.MatchLabels)); err != nil {
+			return err
+		}
+		for i := range pods.Items {
+			for j := range podList.Items {
+				if pods.Items[i].UID == podList.Items[j].UID {
+					return errors.Errorf("Pod %s is not deleted yet", klog.KObj(&pods.Items[i]))
+				}
+			}
+		}
+		return nil
+	}, intervals...).Should(Succeed(), "Failed to wait for the Pods of the Deployment %s to be deleted", klog.KObj(input.Deployment))
+
+	WaitForDeploymentsAvailable(ctx, WaitForDeploymentsAvailableInput{
+		Getter:     input.Client,
+		Deployment: input.Deployment,
+	}, intervals...)
+}
+
+// StopControllerInput is the input for StopController.
+type StopControllerInput struct {
+	Client     client.Client
+	Deployment *appsv1.Deployment
+}
+
+// StopController scales a controller Deployment, e.g. a provider's controller manager, to zero replicas and waits for
+// all its Pods to be gone. The returned func scales the Deployment back to the original replicas and waits for
+// it to be available, using the same intervals.
+func StopController(ctx context.Context, input StopControllerInput, intervals ...interface{}) func() {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for StopController")
+	Expect(input.Client).ToNot(BeNil(), "Invalid argument. input.Client can't be nil when calling StopController")
+	Expect(input.Deployment).ToNot(BeNil(), "Invalid argument. input.Deployment can't be nil when calling StopController")
+
+	deployment := &appsv1.Deployment{}
+	Eventually(func() error {
+		return input.Client.Get(ctx, client.ObjectKeyFromObject(input.Deployment), deployment)
+	}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to get Deployment %s", klog.KObj(input.Deployment))
+	replicas := pointer.Int32Deref(deployment.Spec.Replicas, 1)
+
+	Byf("Stopping the Deployment %s", klog.KObj(input.Deployment))
+	scaleDeployment(ctx, input.Client, deployment, 0)
+	Eventually(func() (int32, error) {
+		if err := input.Client.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+			return 0, err
+		}
+		return deployment.Status.Replicas, nil
+	}, intervals...).Should(BeZero(), "Failed to wait for the Pods of the Deployment %s to be deleted", klog.KObj(input.Deployment))
+
+	return deferRestore(func() {
+		Byf("Restarting the Deployment %s with %d replicas", klog.KObj(input.Deployment), replicas)
+		scaleDeployment(ctx, input.Client, deployment, replicas)
+		WaitForDeploymentsAvailable(ctx, WaitForDeploymentsAvailableInput{
+			Getter:     input.Client,
+			Deployment: deployment,
+		}, intervals...)
+	})
+}
+
+func scaleDeployment(ctx context.Context, c client.Client, deployment *appsv1.Deployment, replicas int32) {
+	Eventually(func() error {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+			return err
+		}
+		patch := client.MergeFrom(deployment.DeepCopy())
+		deployment.Spec.Replicas = pointer.Int32(replicas)
+		return c.Patch(ctx, deployment, patch)
+	}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to scale Deployment %s to %d replicas", klog.KObj(deployment), replicas)
+}
+
+// PauseResourcesInput is the input for PauseResources.
+type PauseResourcesInput struct {
+	Client    client.Client
+	GVK       schema.GroupVersionKind
+	Namespace string
+}
+
+// PauseResources pauses the reconciliation of all the objects of a kind in a namespace, e.g. all the DockerMachines,
+// by adding the cluster.x-k8s.io/paused annotation to them. The returned func removes the annotation from the objects
+// it was added to.
+func PauseResources(ctx context.Context, input PauseResourcesInput) func() {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for PauseResources")
+	Expect(input.Client).ToNot(BeNil(), "Invalid argument. input.Client can't be nil when calling PauseResources")
+	Expect(input.GVK.Kind).ToNot(BeEmpty(), "Invalid argument. input.GVK can't be empty when calling PauseResources")
+	Expect(input.Namespace).ToNot(BeEmpty(), "Invalid argument. input.Namespace can't be empty when calling PauseResources")
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(input.GVK.GroupVersion().WithKind(input.GVK.Kind + "List"))
+	Eventually(func() error {
+		return input.Client.List(ctx, list, client.InNamespace(input.Namespace))
+	}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to list %s in namespace %s", input.GVK.Kind, input.Namespace)
+
+	Byf("Pausing %d %s in namespace %s", len(list.Items), input.GVK.Kind, input.Namespace)
+	paused := []*unstructured.Unstructured{}
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if annotations.HasPaused(obj) {
+			continue
+		}
+		setPausedAnnotation(ctx, input.Client, obj, true)
+		paused = append(paused, obj)
+	}
+
+	return deferRestore(func() {
+		Byf("Unpausing %d %s in namespace %s", len(paused), input.GVK.Kind, input.Namespace)
+		for _, obj := range paused {
+			setPausedAnnotation(ctx, input.Client, obj, false)
+		}
+	})
+}
+
+func setPausedAnnotation(ctx context.Context, c client.Client, obj *unstructured.Unstructured, paused bool) {
+	Eventually(func() error {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if apierrors.IsNotFound(err) && !paused {
+				return nil
+			}
+			return err
+		}
+		patch := client.MergeFrom(obj.DeepCopy())
+		objAnnotations := obj.GetAnnotations()
+		if paused {
+			if objAnnotations == nil {
+				objAnnotations = map[string]string{}
+			}
+			objAnnotations[clusterv1.PausedAnnotation] = ""
+		} else {
+			delete(objAnnotations, clusterv1.PausedAnnotation)
+		}
+		obj.SetAnnotations(objAnnotations)
+		return c.Patch(ctx, obj, patch)
+	}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to set the paused annotation to %t on %s %s", paused, obj.GetKind(), klog.KObj(obj))
+}
+
+// CorruptSecretInput is the input for CorruptSecret.
+type CorruptSecretInput struct {
+	Client client.Client
+	Secret client.ObjectKey
+
+	// Keys are the keys of the Secret data to be corrupted; all the keys are corrupted if not set.
+	Keys []string
+}
+
+// CorruptSecret replaces the data of a Secret, e.g. the kubeconfig or the CA of a workload cluster, with invalid values.
+// The returned func restores the original data of the Secret.
+func CorruptSecret(ctx context.Context, input CorruptSecretInput) func() {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for CorruptSecret")
+	Expect(input.Client).ToNot(BeNil(), "Invalid argument. input.Client can't be nil when calling CorruptSecret")
+	Expect(input.Secret.Name).ToNot(BeEmpty(), "Invalid argument. input.Secret can't be empty when calling CorruptSecret")
+
+	secret := &corev1.Secret{}
+	Eventually(func() error {
+		return input.Client.Get(ctx, input.Secret, secret)
+	}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to get Secret %s", input.Secret)
+
+	keys := input.Keys
+	if len(keys) == 0 {
+		keys = sets.List(sets.KeySet(secret.Data))
+	}
+	original := map[string][]byte{}
+	for _, key := range keys {
+		original[key] = secret.Data[key]
+	}
+
+	Byf("Corrupting the keys %v of Secret %s", keys, input.Secret)
+	patchSecretData(ctx, input.Client, secret, func(data map[string][]byte) {
+		corruptSecretData(data, keys)
+	})
+
+	return deferRestore(func() {
+		Byf("Restoring the keys %v of Secret %s", keys, input.Secret)
+		patchSecretData(ctx, input.Client, secret, func(data map[string][]byte) {
+			restoreSecretData(data, original)
+		})
+	})
+}
+
+// corruptSecretData replaces the values of the given keys with corruptedSecretValue.
+func corruptSecretData(data map[string][]byte, keys []string) {
+	for _, key := range keys {
+		data[key] = corruptedSecretValue
+	}
+}
+
+// restoreSecretData restores the original values of the corrupted keys; keys which did not exist originally are removed.
+func restoreSecretData(data, original map[string][]byte) {
+	for key, value := range original {
+		// Values changed after the corruption, e.g. by a controller fixing the Secret, are preserved.
+		if !bytes.Equal(data[key], corruptedSecretValue) {
+			continue
+		}
+		if value == nil {
+			delete(data, key)
+			continue
+		}
+		data[key] = value
+	}
+}
+
+func patchSecretData(ctx context.Context, c client.Client, secret *corev1.Secret, mutate func(data map[string][]byte)) {
+	Eventually(func() error {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+			return err
+		}
+		patch := client.MergeFrom(secret.DeepCopy())
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		mutate(secret.Data)
+		return c.Patch(ctx, secret, patch)
+	}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to patch Secret %s", klog.KObj(secret))
+}
+
+// PartitionWorkloadClusterNetworkInput is the input for PartitionWorkloadClusterNetwork.
+type PartitionWorkloadClusterNetworkInput struct {
+	// ManagementClusterName is the name of the kind cluster used as management cluster.
+	ManagementClusterName string
+
+	// Cluster is the CAPD workload cluster to be isolated from the management cluster.
+	Cluster *clusterv1.Cluster
+}
+
+// PartitionWorkloadClusterNetwork drops all the traffic between the nodes of a kind management cluster and the
+// containers of a CAPD workload cluster, including its load balancer, by adding iptables rules to the nodes of the
+// management cluster; the workload cluster keeps working, but it is not reachable by the controllers.
+// The returned func removes the iptables rules.
+// NOTE: this helper only works with CAPD workload clusters running in the same container runtime as the management cluster.
+func PartitionWorkloadClusterNetwork(ctx context.Context, input PartitionWorkloadClusterNetworkInput) func() {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for PartitionWorkloadClusterNetwork")
+	Expect(input.ManagementClusterName).ToNot(BeEmpty(), "Invalid argument. input.ManagementClusterName can't be empty when calling PartitionWorkloadClusterNetwork")
+	Expect(input.Cluster).ToNot(BeNil(), "Invalid argument. input.Cluster can't be nil when calling PartitionWorkloadClusterNetwork")
+
+	containerRuntime, err := container.NewRuntimeClient("")
+	Expect(err).ToNot(HaveOccurred(), "Failed to get the container runtime")
+	ctx = container.RuntimeInto(ctx, containerRuntime)
+
+	managementNodes := listClusterContainers(ctx, containerRuntime, input.ManagementClusterName)
+	Expect(managementNodes).ToNot(BeEmpty(), "Failed to find the nodes of the management cluster %s", input.ManagementClusterName)
+	workloadContainers := listClusterContainers(ctx, containerRuntime, input.Cluster.Name)
+	Expect(workloadContainers).ToNot(BeEmpty(), "Failed to find the containers of Cluster %s", klog.KObj(input.Cluster))
+
+	type ipAddress struct {
+		address  string
+		iptables string
+	}
+	addresses := []ipAddress{}
+	for _, name := range workloadContainers {
+		ipv4, ipv6, err := containerRuntime.GetContainerIPs(ctx, name)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the IP addresses of container %s", name)
+		if ipv4 != "" {
+			addresses = append(addresses, ipAddress{address: ipv4, iptables: "iptables"})
+		}
+		if ipv6 != "" {
+			addresses = append(addresses, ipAddress{address: ipv6, iptables: "ip6tables"})
+		}
+	}
+
+	rules := [][]string{}
+	for _, a := range addresses {
+		rules = append(rules, networkPartitionRules(a.iptables, a.address)...)
+	}
+
+	Byf("Partitioning the network between the management cluster %s and Cluster %s", input.ManagementClusterName, klog.KObj(input.Cluster))
+	for _, node := range managementNodes {
+		for _, rule := range rules {
+			Expect(execIPTables(ctx, containerRuntime, node, "-I", rule)).To(Succeed())
+		}
+	}
+
+	return deferRestore(func() {
+		Byf("Restoring the network between the management cluster %s and Cluster %s", input.ManagementClusterName, klog.KObj(input.Cluster))
+		for _, node := range managementNodes {
+			for _, rule := range rules {
+				Expect(execIPTables(ctx, containerRuntime, node, "-D", rule)).To(Succeed())
+			}
+		}
+	})
+}
+
+// listClusterContainers returns the names of the containers of a kind or CAPD cluster.
+func listClusterContainers(ctx context.Context, containerRuntime container.Runtime, clusterName string) []string {
+	filters := container.FilterBuilder{}
+	filters.AddKeyNameValue("label", kindClusterLabelKey, clusterName)
+	containers, err := containerRuntime.ListContainers(ctx, filters)
+	Expect(err).ToNot(HaveOccurred(), "Failed to list the containers of cluster %s", clusterName)
+
+	names := make([]string, 0, len(containers))
+	for _, c := range containers {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// networkPartitionRules returns the iptables rules dropping the traffic from and to an address, each rule starting
+// with the iptables command to be used, i.e. iptables or ip6tables.
+// Traffic from the controllers goes through the FORWARD chain when they run in Pods, and through the OUTPUT chain
+// when they use the host network.
+func networkPartitionRules(iptables, address string) [][]string {
+	return [][]string{
+		{iptables, "FORWARD", "-d", address, "-j", "DROP"},
+		{iptables, "FORWARD", "-s", address, "-j", "DROP"},
+		{iptables, "OUTPUT", "-d", address, "-j", "DROP"},
+		{iptables, "INPUT", "-s", address, "-j", "DROP"},
+	}
+}
+
+// execIPTables runs iptables in a container, adding or deleting a rule depending on the operation.
+func execIPTables(ctx context.Context, containerRuntime container.Runtime, containerName, operation string, rule []string) error {
+	var stdErr bytes.Buffer
+	args := append([]string{operation}, rule[1:]...)
+	if err := containerRuntime.ExecContainer(ctx, containerName, &container.ExecContainerInput{ErrorBuffer: &stdErr}, rule[0], args...); err != nil {
+		return errors.Wrapf(err, "failed to run %s %v in container %s: %s", rule[0], args, containerName, stdErr.String())
+	}
+	log.Logf("Ran %s %v in container %s", rule[0], args, containerName)
+	return nil
+}
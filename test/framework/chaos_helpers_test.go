@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+)
+
+var (
+	restoredAtCleanup  int
+	restoredExplicitly int
+)
+
+var _ = Describe("deferRestore", func() {
+	It("restores the state at cleanup when the restore func is not called", func() {
+		deferRestore(func() { restoredAtCleanup++ })
+	})
+
+	It("restores the state only once when the restore func is called", func() {
+		restore := deferRestore(func() { restoredExplicitly++ })
+		restore()
+		restore()
+		Expect(restoredExplicitly).To(Equal(1))
+	})
+})
+
+func TestDeferRestore(t *testing.T) {
+	g := NewWithT(t)
+
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "deferRestore")
+
+	g.Expect(restoredAtCleanup).To(Equal(1))
+	g.Expect(restoredExplicitly).To(Equal(1))
+}
+
+func TestNetworkPartitionRules(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(networkPartitionRules("iptables", "172.18.0.3")).To(Equal([][]string{
+		{"iptables", "FORWARD", "-d", "172.18.0.3", "-j", "DROP"},
+		{"iptables", "FORWARD", "-s", "172.18.0.3", "-j", "DROP"},
+		{"iptables", "OUTPUT", "-d", "172.18.0.3", "-j", "DROP"},
+		{"iptables", "INPUT", "-s", "172.18.0.3", "-j", "DROP"},
+	}))
+	g.Expect(networkPartitionRules("ip6tables", "fc00:f853:ccd:e793::3")).To(HaveEach(HaveExactElements(
+		"ip6tables", Not(BeEmpty()), Not(BeEmpty()), "fc00:f853:ccd:e793::3", "-j", "DROP",
+	)))
+}
+
+func TestExecIPTables(t *testing.T) {
+	g := NewWithT(t)
+
+	containerRuntime := &container.FakeRuntime{}
+	containerRuntime.ResetExecContainerCallLogs()
+
+	rule := []string{"ip6tables", "FORWARD", "-d", "fc00:f853:ccd:e793::3", "-j", "DROP"}
+	g.Expect(execIPTables(context.Background(), containerRuntime, "mgmt-control-plane", "-I", rule)).To(Succeed())
+	g.Expect(execIPTables(context.Background(), containerRuntime, "mgmt-control-plane", "-D", rule)).To(Succeed())
+
+	calls := containerRuntime.ExecContainerCalls()
+	g.Expect(calls).To(HaveLen(2))
+	g.Expect(calls[0].ContainerName).To(Equal("mgmt-control-plane"))
+	g.Expect(calls[0].Command).To(Equal("ip6tables"))
+	g.Expect(calls[0].Args).To(Equal([]string{"-I", "FORWARD", "-d", "fc00:f853:ccd:e793::3", "-j", "DROP"}))
+	g.Expect(calls[1].Command).To(Equal("ip6tables"))
+	g.Expect(calls[1].Args).To(Equal([]string{"-D", "FORWARD", "-d", "fc00:f853:ccd:e793::3", "-j", "DROP"}))
+
+	// The rule is not modified, so it can be used again to delete it.
+	g.Expect(rule).To(Equal([]string{"ip6tables", "FORWARD", "-d", "fc00:f853:ccd:e793::3", "-j", "DROP"}))
+}
+
+func TestCorruptAndRestoreSecretData(t *testing.T) {
+	t.Run("corrupts and restores the given keys", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data := map[string][]byte{"value": []byte("kubeconfig"), "other": []byte("other")}
+		original := map[string][]byte{"value": data["value"]}
+
+		corruptSecretData(data, []string{"value"})
+		g.Expect(data).To(Equal(map[string][]byte{"value": corruptedSecretValue, "other": []byte("other")}))
+
+		restoreSecretData(data, original)
+		g.Expect(data).To(Equal(map[string][]byte{"value": []byte("kubeconfig"), "other": []byte("other")}))
+	})
+
+	t.Run("removes the keys which did not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data := map[string][]byte{"tls.crt": []byte("crt")}
+		original := map[string][]byte{"tls.crt": data["tls.crt"], "tls.key": data["tls.key"]}
+
+		corruptSecretData(data, []string{"tls.crt", "tls.key"})
+		g.Expect(data).To(Equal(map[string][]byte{"tls.crt": corruptedSecretValue, "tls.key": corruptedSecretValue}))
+
+		restoreSecretData(data, original)
+		g.Expect(data).To(Equal(map[string][]byte{"tls.crt": []byte("crt")}))
+	})
+
+	t.Run("preserves the values changed after the corruption", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data := map[string][]byte{"tls.crt": []byte("crt"), "tls.key": []byte("key")}
+		original := map[string][]byte{"tls.crt": data["tls.crt"], "tls.key": data["tls.key"]}
+
+		corruptSecretData(data, []string{"tls.crt", "tls.key"})
+		data["tls.crt"] = []byte("regenerated-crt")
+
+		restoreSecretData(data, original)
+		g.Expect(data).To(Equal(map[string][]byte{"tls.crt": []byte("regenerated-crt"), "tls.key": []byte("key")}))
+	})
+}
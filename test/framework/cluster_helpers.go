@@ -131,6 +131,7 @@ type WaitForClusterToProvisionInput struct {
 // WaitForClusterToProvision will wait for a cluster to have a phase status of provisioned.
 func WaitForClusterToProvision(ctx context.Context, input WaitForClusterToProvisionInput, intervals ...interface{}) *clusterv1.Cluster {
 	cluster := &clusterv1.Cluster{}
+	history := NewConditionHistory()
 	By("Waiting for cluster to enter the provisioned phase")
 	Eventually(func() (string, error) {
 		key := client.ObjectKey{
@@ -140,8 +141,9 @@ func WaitForClusterToProvision(ctx context.Context, input WaitForClusterToProvis
 		if err := input.Getter.Get(ctx, key, cluster); err != nil {
 			return "", err
 		}
+		history.Record(cluster)
 		return cluster.Status.Phase, nil
-	}, intervals...).Should(Equal(string(clusterv1.ClusterPhaseProvisioned)), "Timed out waiting for Cluster %s to provision", klog.KObj(input.Cluster))
+	}, intervals...).Should(Equal(string(clusterv1.ClusterPhaseProvisioned)), history.Describe("Timed out waiting for Cluster %s to provision", klog.KObj(input.Cluster)))
 	return cluster
 }
 
@@ -166,14 +168,19 @@ type WaitForClusterDeletedInput struct {
 // WaitForClusterDeleted waits until the cluster object has been deleted.
 func WaitForClusterDeleted(ctx context.Context, input WaitForClusterDeletedInput, intervals ...interface{}) {
 	Byf("Waiting for cluster %s to be deleted", klog.KObj(input.Cluster))
+	history := NewConditionHistory()
 	Eventually(func() bool {
 		cluster := &clusterv1.Cluster{}
 		key := client.ObjectKey{
 			Namespace: input.Cluster.GetNamespace(),
 			Name:      input.Cluster.GetName(),
 		}
-		return apierrors.IsNotFound(input.Getter.Get(ctx, key, cluster))
-	}, intervals...).Should(BeTrue())
+		if err := input.Getter.Get(ctx, key, cluster); err != nil {
+			return apierrors.IsNotFound(err)
+		}
+		history.Record(cluster)
+		return false
+	}, intervals...).Should(BeTrue(), history.Describe("Timed out waiting for Cluster %s to be deleted", klog.KObj(input.Cluster)))
 }
 
 // DiscoveryAndWaitForClusterInput is the input type for DiscoveryAndWaitForCluster.
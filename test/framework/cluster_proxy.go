@@ -406,8 +406,8 @@ func (p *clusterProxy) isDockerCluster(ctx context.Context, namespace string, na
 }
 
 func (p *clusterProxy) fixConfig(ctx context.Context, name string, config *api.Config) {
-	containerRuntime, err := container.NewDockerClient()
-	Expect(err).ToNot(HaveOccurred(), "Failed to get Docker runtime client")
+	containerRuntime, err := container.NewRuntimeClient("")
+	Expect(err).ToNot(HaveOccurred(), "Failed to get container runtime client")
 	ctx = container.RuntimeInto(ctx, containerRuntime)
 
 	lbContainerName := name + "-lb"
@@ -19,6 +19,7 @@ package framework
 import (
 	"context"
 	"strconv"
+	"time"
 
 	. "github.com/onsi/gomega"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -89,6 +90,7 @@ func UpgradeClusterTopologyAndWaitForUpgrade(ctx context.Context, input UpgradeC
 	Expect(input.KubernetesUpgradeVersion).ToNot(BeNil(), "Invalid argument. input.KubernetesUpgradeVersion can't be empty when calling UpgradeClusterTopologyAndWaitForUpgrade")
 
 	mgmtClient := input.ClusterProxy.GetClient()
+	start := time.Now()
 
 	log.Logf("Patching the new Kubernetes version to Cluster topology")
 	patchHelper, err := patch.NewHelper(input.Cluster, mgmtClient)
@@ -190,4 +192,6 @@ func UpgradeClusterTopologyAndWaitForUpgrade(ctx context.Context, input UpgradeC
 			}, input.WaitForMachinePoolToBeUpgraded...)
 		}
 	}
+
+	RecordSpecMetric("cluster_upgrade_duration_seconds", time.Since(start).Seconds(), map[string]string{"cluster": klog.KObj(input.Cluster).String(), "version": input.KubernetesUpgradeVersion})
 }
@@ -216,6 +216,63 @@ func Upgrade(ctx context.Context, input UpgradeInput) {
 	Expect(err).ToNot(HaveOccurred(), "failed to run clusterctl upgrade")
 }
 
+// UpgradeWithBinary uses clusterctl binary to run upgrade apply with the list of providers defined in the local repository.
+func UpgradeWithBinary(ctx context.Context, binary string, input UpgradeInput) {
+	if len(input.ClusterctlVariables) > 0 {
+		outputPath := filepath.Join(filepath.Dir(input.ClusterctlConfigPath), fmt.Sprintf("clusterctl-upgrade-config-%s.yaml", input.ClusterName))
+		copyAndAmendClusterctlConfig(ctx, copyAndAmendClusterctlConfigInput{
+			ClusterctlConfigPath: input.ClusterctlConfigPath,
+			OutputPath:           outputPath,
+			Variables:            input.ClusterctlVariables,
+		})
+		input.ClusterctlConfigPath = outputPath
+	}
+
+	args := calculateClusterCtlUpgradeArgs(input)
+	log.Logf("clusterctl %s", strings.Join(args, " "))
+
+	cmd := exec.Command(binary, args...) //nolint:gosec // We don't care about command injection here.
+
+	out, err := cmd.CombinedOutput()
+	_ = os.WriteFile(filepath.Join(input.LogFolder, "clusterctl-upgrade.log"), out, 0644) //nolint:gosec // this is a log file to be shared via prow artifacts
+	var stdErr string
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stdErr = string(exitErr.Stderr)
+		}
+	}
+	Expect(err).ToNot(HaveOccurred(), "failed to run clusterctl upgrade apply:\nstdout:\n%s\nstderr:\n%s", string(out), stdErr)
+}
+
+func calculateClusterCtlUpgradeArgs(input UpgradeInput) []string {
+	args := []string{"upgrade", "apply", "--config", input.ClusterctlConfigPath, "--kubeconfig", input.KubeconfigPath, "--wait-providers"}
+	if input.Contract != "" {
+		return append(args, "--contract", input.Contract)
+	}
+	if input.CoreProvider != "" {
+		args = append(args, "--core", input.CoreProvider)
+	}
+	if len(input.BootstrapProviders) > 0 {
+		args = append(args, "--bootstrap", strings.Join(input.BootstrapProviders, ","))
+	}
+	if len(input.ControlPlaneProviders) > 0 {
+		args = append(args, "--control-plane", strings.Join(input.ControlPlaneProviders, ","))
+	}
+	if len(input.InfrastructureProviders) > 0 {
+		args = append(args, "--infrastructure", strings.Join(input.InfrastructureProviders, ","))
+	}
+	if len(input.IPAMProviders) > 0 {
+		args = append(args, "--ipam", strings.Join(input.IPAMProviders, ","))
+	}
+	if len(input.RuntimeExtensionProviders) > 0 {
+		args = append(args, "--runtime-extension", strings.Join(input.RuntimeExtensionProviders, ","))
+	}
+	if len(input.AddonProviders) > 0 {
+		args = append(args, "--addon", strings.Join(input.AddonProviders, ","))
+	}
+	return args
+}
+
 // DeleteInput is the input for Delete.
 type DeleteInput struct {
 	LogFolder            string
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterctl
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCalculateClusterCtlUpgradeArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    UpgradeInput
+		wantArgs []string
+	}{
+		{
+			name: "contract",
+			input: UpgradeInput{
+				ClusterctlConfigPath: "/clusterctl.yaml",
+				KubeconfigPath:       "/kubeconfig",
+				Contract:             "v1beta1",
+			},
+			wantArgs: []string{
+				"upgrade", "apply", "--config", "/clusterctl.yaml", "--kubeconfig", "/kubeconfig", "--wait-providers",
+				"--contract", "v1beta1",
+			},
+		},
+		{
+			name: "contract takes precedence over providers",
+			input: UpgradeInput{
+				ClusterctlConfigPath: "/clusterctl.yaml",
+				KubeconfigPath:       "/kubeconfig",
+				Contract:             "v1beta1",
+				CoreProvider:         "capi-system/cluster-api:v1.5.0",
+			},
+			wantArgs: []string{
+				"upgrade", "apply", "--config", "/clusterctl.yaml", "--kubeconfig", "/kubeconfig", "--wait-providers",
+				"--contract", "v1beta1",
+			},
+		},
+		{
+			name: "custom upgrade",
+			input: UpgradeInput{
+				ClusterctlConfigPath:      "/clusterctl.yaml",
+				KubeconfigPath:            "/kubeconfig",
+				CoreProvider:              "capi-system/cluster-api:v1.5.0",
+				BootstrapProviders:        []string{"capi-kubeadm-bootstrap-system/kubeadm:v1.5.0"},
+				ControlPlaneProviders:     []string{"capi-kubeadm-control-plane-system/kubeadm:v1.5.0"},
+				InfrastructureProviders:   []string{"capd-system/docker:v1.5.0", "capim-system/in-memory:v1.5.0"},
+				IPAMProviders:             []string{"ipam-system/in-cluster:v0.1.0"},
+				RuntimeExtensionProviders: []string{"test-extension-system/test:v1.5.0"},
+				AddonProviders:            []string{"caaph-system/helm:v0.1.0"},
+			},
+			wantArgs: []string{
+				"upgrade", "apply", "--config", "/clusterctl.yaml", "--kubeconfig", "/kubeconfig", "--wait-providers",
+				"--core", "capi-system/cluster-api:v1.5.0",
+				"--bootstrap", "capi-kubeadm-bootstrap-system/kubeadm:v1.5.0",
+				"--control-plane", "capi-kubeadm-control-plane-system/kubeadm:v1.5.0",
+				"--infrastructure", "capd-system/docker:v1.5.0,capim-system/in-memory:v1.5.0",
+				"--ipam", "ipam-system/in-cluster:v0.1.0",
+				"--runtime-extension", "test-extension-system/test:v1.5.0",
+				"--addon", "caaph-system/helm:v0.1.0",
+			},
+		},
+		{
+			name: "custom upgrade of some providers",
+			input: UpgradeInput{
+				ClusterctlConfigPath:    "/clusterctl.yaml",
+				KubeconfigPath:          "/kubeconfig",
+				InfrastructureProviders: []string{"capd-system/docker:v1.5.0"},
+			},
+			wantArgs: []string{
+				"upgrade", "apply", "--config", "/clusterctl.yaml", "--kubeconfig", "/kubeconfig", "--wait-providers",
+				"--infrastructure", "capd-system/docker:v1.5.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(calculateClusterCtlUpgradeArgs(tt.input)).To(Equal(tt.wantArgs))
+		})
+	}
+}
@@ -133,6 +133,7 @@ func InitManagementClusterAndWatchControllerLogs(ctx context.Context, input Init
 // UpgradeManagementClusterAndWaitInput is the input type for UpgradeManagementClusterAndWait.
 type UpgradeManagementClusterAndWaitInput struct {
 	ClusterProxy              framework.ClusterProxy
+	ClusterctlBinaryPath      string
 	ClusterctlConfigPath      string
 	ClusterctlVariables       map[string]string
 	Contract                  string
@@ -165,7 +166,9 @@ func UpgradeManagementClusterAndWait(ctx context.Context, input UpgradeManagemen
 
 	Expect(os.MkdirAll(input.LogFolder, 0750)).To(Succeed(), "Invalid argument. input.LogFolder can't be created for UpgradeManagementClusterAndWait")
 
-	Upgrade(ctx, UpgradeInput{
+	start := time.Now()
+
+	upgradeInput := UpgradeInput{
 		ClusterctlConfigPath:      input.ClusterctlConfigPath,
 		ClusterctlVariables:       input.ClusterctlVariables,
 		ClusterName:               input.ClusterProxy.GetName(),
@@ -179,7 +182,13 @@ func UpgradeManagementClusterAndWait(ctx context.Context, input UpgradeManagemen
 		RuntimeExtensionProviders: input.RuntimeExtensionProviders,
 		AddonProviders:            input.AddonProviders,
 		LogFolder:                 input.LogFolder,
-	})
+	}
+
+	if input.ClusterctlBinaryPath != "" {
+		UpgradeWithBinary(ctx, input.ClusterctlBinaryPath, upgradeInput)
+	} else {
+		Upgrade(ctx, upgradeInput)
+	}
 
 	client := input.ClusterProxy.GetClient()
 
@@ -202,6 +211,8 @@ func UpgradeManagementClusterAndWait(ctx context.Context, input UpgradeManagemen
 			MetricsPath: filepath.Join(input.LogFolder, "metrics", deployment.GetNamespace()),
 		})
 	}
+
+	framework.RecordSpecMetric("management_cluster_upgrade_duration_seconds", time.Since(start).Seconds(), map[string]string{"cluster": input.ClusterProxy.GetName()})
 }
 
 // ApplyClusterTemplateAndWaitInput is the input type for ApplyClusterTemplateAndWait.
@@ -373,6 +384,7 @@ func ApplyCustomClusterTemplateAndWait(ctx context.Context, input ApplyCustomClu
 	}
 
 	log.Logf("Applying the cluster template yaml of cluster %s", klog.KRef(input.Namespace, input.ClusterName))
+	start := time.Now()
 	Eventually(func() error {
 		return input.ClusterProxy.Apply(ctx, input.CustomTemplateYAML, input.Args...)
 	}, 1*time.Minute).Should(Succeed(), "Failed to apply the cluster template")
@@ -429,12 +441,37 @@ func ApplyCustomClusterTemplateAndWait(ctx context.Context, input ApplyCustomClu
 		Cluster: result.Cluster,
 	}, input.WaitForMachinePools...)
 
+	recordClusterProvisioningMetrics(result, time.Since(start))
+
 	if input.PostMachinesProvisioned != nil {
 		log.Logf("Calling PostMachinesProvisioned for cluster %s", klog.KRef(input.Namespace, input.ClusterName))
 		input.PostMachinesProvisioned()
 	}
 }
 
+// recordClusterProvisioningMetrics records the time it took to provision a cluster and the number of machines
+// created, so they are included in the results of the spec.
+func recordClusterProvisioningMetrics(result *ApplyCustomClusterTemplateAndWaitResult, duration time.Duration) {
+	machines := int32(0)
+	if result.ControlPlane != nil && result.ControlPlane.Spec.Replicas != nil {
+		machines += *result.ControlPlane.Spec.Replicas
+	}
+	for _, md := range result.MachineDeployments {
+		if md.Spec.Replicas != nil {
+			machines += *md.Spec.Replicas
+		}
+	}
+	for _, mp := range result.MachinePools {
+		if mp.Spec.Replicas != nil {
+			machines += *mp.Spec.Replicas
+		}
+	}
+
+	labels := map[string]string{"cluster": klog.KObj(result.Cluster).String()}
+	framework.RecordSpecMetric("cluster_provisioning_duration_seconds", duration.Seconds(), labels)
+	framework.RecordSpecMetric("machines_created", float64(machines), labels)
+}
+
 // setDefaults sets the default values for ApplyCustomClusterTemplateAndWaitInput if not set.
 // Currently, we set the default ControlPlaneWaiters here, which are implemented for KubeadmControlPlane.
 func setDefaults(input *ApplyCustomClusterTemplateAndWaitInput) {
@@ -457,3 +494,69 @@ func setDefaults(input *ApplyCustomClusterTemplateAndWaitInput) {
 		}
 	}
 }
+
+// ApplyClusterTemplatesAndWaitInput is the input type for ApplyClusterTemplatesAndWait.
+type ApplyClusterTemplatesAndWaitInput struct {
+	// Clusters are the inputs for creating each of the clusters; the cluster names must be unique
+	// within each namespace.
+	Clusters []ApplyClusterTemplateAndWaitInput
+
+	// Concurrency is the maximum number of clusters created at the same time; all the clusters are created
+	// at the same time if not set.
+	Concurrency int
+}
+
+// ApplyClusterTemplatesAndWaitResult is the output type for ApplyClusterTemplatesAndWait.
+type ApplyClusterTemplatesAndWaitResult struct {
+	// Results are the results of the creation of each cluster, in the same order as the input clusters.
+	Results []*ApplyClusterTemplateAndWaitResult
+
+	// Failures are the errors of the clusters which failed to be created, keyed by cluster namespace/name.
+	Failures map[string]error
+}
+
+// Clusters returns all the clusters, including the ones which failed to be created, e.g. for dump and cleanup steps.
+func (r *ApplyClusterTemplatesAndWaitResult) Clusters() []*clusterv1.Cluster {
+	clusters := []*clusterv1.Cluster{}
+	for _, result := range r.Results {
+		if result != nil && result.Cluster != nil {
+			clusters = append(clusters, result.Cluster)
+		}
+	}
+	return clusters
+}
+
+// ApplyClusterTemplatesAndWait creates many clusters concurrently using ApplyClusterTemplateAndWait, and waits for all of them
+// to be ready. A failure creating a cluster does not stop the creation of the other clusters; once all the clusters are
+// processed, the spec fails with the list of the clusters which failed to be created.
+func ApplyClusterTemplatesAndWait(ctx context.Context, input ApplyClusterTemplatesAndWaitInput, result *ApplyClusterTemplatesAndWaitResult) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for ApplyClusterTemplatesAndWait")
+	Expect(input.Clusters).ToNot(BeEmpty(), "Invalid argument. input.Clusters can't be empty when calling ApplyClusterTemplatesAndWait")
+	Expect(result).ToNot(BeNil(), "Invalid argument. result can't be nil when calling ApplyClusterTemplatesAndWait")
+
+	log.Logf("Creating %d workload clusters (concurrency %d)", len(input.Clusters), input.Concurrency)
+
+	result.Results = make([]*ApplyClusterTemplateAndWaitResult, len(input.Clusters))
+	for i := range result.Results {
+		result.Results[i] = &ApplyClusterTemplateAndWaitResult{}
+	}
+
+	start := time.Now()
+	errs := framework.RunConcurrently(len(input.Clusters), input.Concurrency, func(i int) {
+		ApplyClusterTemplateAndWait(ctx, input.Clusters[i], result.Results[i])
+	})
+
+	result.Failures = map[string]error{}
+	all := map[string]error{}
+	for i, err := range errs {
+		key := klog.KRef(input.Clusters[i].ConfigCluster.Namespace, input.Clusters[i].ConfigCluster.ClusterName).String()
+		all[key] = err
+		if err != nil {
+			result.Failures[key] = err
+			log.Logf("Failed to create the workload cluster %s: %v", key, err)
+		}
+	}
+	log.Logf("Created %d of %d workload clusters in %s", len(input.Clusters)-len(result.Failures), len(input.Clusters), time.Since(start).Round(time.Second))
+
+	framework.FailOnConcurrentFailures("Creating workload clusters", all)
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// recentControllerLogLines is the number of lines of each controller container log kept in memory, so they can
+	// be reported when a wait times out.
+	recentControllerLogLines = 5000
+
+	// maxReportedControllerLogLines is the maximum number of lines of each controller container log reported
+	// when a wait times out.
+	maxReportedControllerLogLines = 50
+
+	// maxConditionMessageLength is the maximum length of the condition messages reported in a timeline.
+	maxConditionMessageLength = 200
+)
+
+// ConditionHistory records the transitions of the conditions of the objects observed while waiting for them,
+// so when the wait times out the failure can report how the objects got to their current state, and the recent
+// controller logs about them, instead of only reporting that the wait timed out.
+type ConditionHistory struct {
+	lock    sync.Mutex
+	start   time.Time
+	objects map[string]*objectConditionHistory
+	order   []string
+}
+
+// objectConditionHistory is the condition history of an object.
+type objectConditionHistory struct {
+	ref         string
+	kind        string
+	conditions  map[clusterv1.ConditionType]string
+	transitions []conditionTransition
+	deleted     bool
+}
+
+// conditionTransition is a change of a condition of an object, or of the existence of the object.
+type conditionTransition struct {
+	after         time.Duration
+	conditionType clusterv1.ConditionType
+	from          string
+	to            string
+}
+
+// NewConditionHistory returns a ConditionHistory; the transitions are timed from now.
+func NewConditionHistory() *ConditionHistory {
+	return &ConditionHistory{
+		start:   time.Now(),
+		objects: map[string]*objectConditionHistory{},
+	}
+}
+
+// Record records the conditions of the given objects, which must implement Cluster API conditions, e.g. Clusters,
+// Machines or KubeadmControlPlanes, or be Unstructured objects with Cluster API conditions.
+func (h *ConditionHistory) Record(objs ...client.Object) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for _, obj := range objs {
+		var getter conditions.Getter
+		switch o := obj.(type) {
+		case conditions.Getter:
+			getter = o
+		case *unstructured.Unstructured:
+			getter = conditions.UnstructuredGetter(o)
+		default:
+			continue
+		}
+
+		current := map[clusterv1.ConditionType]string{}
+		for _, c := range getter.GetConditions() {
+			current[c.Type] = formatCondition(c)
+		}
+
+		o := h.object(obj)
+		if o.deleted {
+			o.transitions = append(o.transitions, conditionTransition{after: h.elapsed(), from: "deleted", to: "exists"})
+			o.deleted = false
+		}
+		types := make([]string, 0, len(current)+len(o.conditions))
+		for t := range current {
+			types = append(types, string(t))
+		}
+		for t := range o.conditions {
+			if _, ok := current[t]; !ok {
+				types = append(types, string(t))
+			}
+		}
+		sort.Strings(types)
+		for _, t := range types {
+			from, to := o.conditions[clusterv1.ConditionType(t)], current[clusterv1.ConditionType(t)]
+			if from == to {
+				continue
+			}
+			o.transitions = append(o.transitions, conditionTransition{after: h.elapsed(), conditionType: clusterv1.ConditionType(t), from: from, to: to})
+		}
+		o.conditions = current
+	}
+}
+
+// RecordDeleted records that the given object does not exist anymore.
+func (h *ConditionHistory) RecordDeleted(obj client.Object) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	o := h.object(obj)
+	if o.deleted {
+		return
+	}
+	o.transitions = append(o.transitions, conditionTransition{after: h.elapsed(), from: "exists", to: "deleted"})
+	o.deleted = true
+}
+
+func (h *ConditionHistory) object(obj client.Object) *objectConditionHistory {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	}
+	ref := klog.KObj(obj).String()
+	key := kind + " " + ref
+	o, ok := h.objects[key]
+	if !ok {
+		o = &objectConditionHistory{ref: ref, kind: kind, conditions: map[clusterv1.ConditionType]string{}}
+		h.objects[key] = o
+		h.order = append(h.order, key)
+	}
+	return o
+}
+
+func (h *ConditionHistory) elapsed() time.Duration {
+	return time.Since(h.start).Round(time.Second)
+}
+
+// formatCondition returns a short description of a condition, including its reason, severity and message.
+func formatCondition(c clusterv1.Condition) string {
+	s := string(c.Status)
+	if c.Reason != "" {
+		s += ", " + c.Reason
+	}
+	if c.Severity != clusterv1.ConditionSeverityNone {
+		s += fmt.Sprintf(" (%s)", c.Severity)
+	}
+	if c.Message != "" {
+		msg := c.Message
+		if len(msg) > maxConditionMessageLength {
+			msg = msg[:maxConditionMessageLength] + "..."
+		}
+		s += ": " + msg
+	}
+	return s
+}
+
+// Timeline returns the transitions of the conditions of each object, with the time of each transition relative to
+// the creation of the history.
+func (h *ConditionHistory) Timeline() string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if len(h.order) == 0 {
+		return "No objects observed.\n"
+	}
+
+	out := &strings.Builder{}
+	for _, key := range h.order {
+		o := h.objects[key]
+		fmt.Fprintf(out, "%s %s:\n", o.kind, o.ref)
+		if len(o.transitions) == 0 {
+			fmt.Fprintf(out, "  no conditions observed\n")
+			continue
+		}
+		for _, t := range o.transitions {
+			fmt.Fprintf(out, "  +%-8s", t.after)
+			if t.conditionType == "" {
+				fmt.Fprintf(out, " %s -> %s\n", t.from, t.to)
+				continue
+			}
+			from, to := t.from, t.to
+			if from == "" {
+				from = "<unset>"
+			}
+			if to == "" {
+				to = "<unset>"
+			}
+			fmt.Fprintf(out, " %s: %s -> %s\n", t.conditionType, from, to)
+		}
+	}
+	return out.String()
+}
+
+// Describe returns a description for a failed wait, to be passed to Gomega's Should, so it is computed only if the
+// wait fails: the description includes the given message, the timeline of the conditions of the objects and the
+// recent controller logs about them.
+func (h *ConditionHistory) Describe(format string, args ...interface{}) func() string {
+	return func() string {
+		out := &strings.Builder{}
+		fmt.Fprintf(out, format, args...)
+		fmt.Fprintf(out, "\n\nCondition history:\n%s", h.Timeline())
+
+		h.lock.Lock()
+		refs := make([]string, 0, len(h.order))
+		for _, key := range h.order {
+			refs = append(refs, h.objects[key].ref)
+		}
+		start := h.start
+		h.lock.Unlock()
+
+		if logs := recentControllerLogs.grep(refs, start, maxReportedControllerLogLines); logs != "" {
+			fmt.Fprintf(out, "\nRecent controller logs:\n%s", logs)
+		}
+		return out.String()
+	}
+}
+
+// recentControllerLogs keeps the recent lines of the logs of the controllers watched by the framework.
+var recentControllerLogs = &controllerLogs{containers: map[string]*logRing{}}
+
+// controllerLogs keeps the recent lines of the logs of controller containers, keyed by namespace/deployment/pod/container.
+type controllerLogs struct {
+	lock       sync.Mutex
+	containers map[string]*logRing
+}
+
+// writer returns a writer for the log of the given container.
+func (c *controllerLogs) writer(container string) io.Writer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	r, ok := c.containers[container]
+	if !ok {
+		r = &logRing{lines: make([]logLine, recentControllerLogLines)}
+		c.containers[container] = r
+	}
+	return r
+}
+
+// grep returns, for each container, the last max lines received since the given time and containing any of the
+// given patterns.
+func (c *controllerLogs) grep(patterns []string, since time.Time, max int) string {
+	c.lock.Lock()
+	names := make([]string, 0, len(c.containers))
+	for name := range c.containers {
+		names = append(names, name)
+	}
+	c.lock.Unlock()
+	sort.Strings(names)
+
+	out := &strings.Builder{}
+	for _, name := range names {
+		c.lock.Lock()
+		r := c.containers[name]
+		c.lock.Unlock()
+
+		matches := []string{}
+		for _, l := range r.since(since) {
+			for _, p := range patterns {
+				if strings.Contains(l, p) {
+					matches = append(matches, l)
+					break
+				}
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		if len(matches) > max {
+			matches = matches[len(matches)-max:]
+		}
+		fmt.Fprintf(out, "%s:\n", name)
+		for _, l := range matches {
+			fmt.Fprintf(out, "  %s\n", l)
+		}
+	}
+	return out.String()
+}
+
+// logRing is a writer keeping the last lines written to it, with the time they were received.
+type logRing struct {
+	lock    sync.Mutex
+	lines   []logLine
+	next    int
+	full    bool
+	partial []byte
+}
+
+type logLine struct {
+	received time.Time
+	text     string
+}
+
+// Write implements io.Writer.
+func (r *logRing) Write(p []byte) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	data := append(r.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		r.lines[r.next] = logLine{received: now, text: string(data[:i])}
+		r.next = (r.next + 1) % len(r.lines)
+		if r.next == 0 {
+			r.full = true
+		}
+		data = data[i+1:]
+	}
+	r.partial = append([]byte{}, data...)
+	return len(p), nil
+}
+
+// since returns the lines received since the given time, oldest first.
+func (r *logRing) since(t time.Time) []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	lines := r.lines[:r.next]
+	if r.full {
+		lines = append(append([]logLine{}, r.lines[r.next:]...), r.lines[:r.next]...)
+	}
+	ret := []string{}
+	for _, l := range lines {
+		if !l.received.Before(t) {
+			ret = append(ret, l.text)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestConditionHistory(t *testing.T) {
+	g := NewWithT(t)
+
+	history := framework.NewConditionHistory()
+	g.Expect(history.Timeline()).To(Equal("No objects observed.\n"))
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cluster1"}}
+	conditions.MarkFalse(cluster, clusterv1.ControlPlaneReadyCondition, clusterv1.WaitingForControlPlaneProviderInitializedReason, clusterv1.ConditionSeverityInfo, "")
+	history.Record(cluster)
+	// Recording the same conditions again does not add transitions.
+	history.Record(cluster)
+
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneReadyCondition)
+	conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, "WaitingForLoadBalancer", clusterv1.ConditionSeverityWarning, "load balancer %s not ready", "lb1")
+	history.Record(cluster)
+
+	history.RecordDeleted(cluster)
+	history.RecordDeleted(cluster)
+
+	// Objects without Cluster API conditions are ignored.
+	history.Record(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "config1"}})
+
+	g.Expect(history.Timeline()).To(Equal(`Cluster ns1/cluster1:
+  +0s       ControlPlaneReady: <unset> -> False, WaitingForControlPlaneProviderInitialized (Info)
+  +0s       ControlPlaneReady: False, WaitingForControlPlaneProviderInitialized (Info) -> True
+  +0s       InfrastructureReady: <unset> -> False, WaitingForLoadBalancer (Warning): load balancer lb1 not ready
+  +0s       exists -> deleted
+`))
+
+	description := history.Describe("Timed out waiting for Cluster %s", "ns1/cluster1")()
+	g.Expect(description).To(HavePrefix("Timed out waiting for Cluster ns1/cluster1\n\nCondition history:\nCluster ns1/cluster1:\n"))
+}
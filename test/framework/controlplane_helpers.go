@@ -19,6 +19,7 @@ package framework
 import (
 	"context"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -100,6 +101,7 @@ func WaitForKubeadmControlPlaneMachinesToExist(ctx context.Context, input WaitFo
 		clusterv1.ClusterNameLabel:         input.Cluster.Name,
 	}
 
+	history := NewConditionHistory()
 	Eventually(func() (int, error) {
 		machineList := &clusterv1.MachineList{}
 		if err := input.Lister.List(ctx, machineList, inClustersNamespaceListOption, matchClusterListOption); err != nil {
@@ -107,13 +109,15 @@ func WaitForKubeadmControlPlaneMachinesToExist(ctx context.Context, input WaitFo
 			return 0, err
 		}
 		count := 0
-		for _, machine := range machineList.Items {
+		for i := range machineList.Items {
+			machine := &machineList.Items[i]
+			history.Record(machine)
 			if machine.Status.NodeRef != nil {
 				count++
 			}
 		}
 		return count, nil
-	}, intervals...).Should(Equal(int(*input.ControlPlane.Spec.Replicas)), "Timed out waiting for %d control plane machines to exist", int(*input.ControlPlane.Spec.Replicas))
+	}, intervals...).Should(Equal(int(*input.ControlPlane.Spec.Replicas)), history.Describe("Timed out waiting for %d control plane machines to exist", int(*input.ControlPlane.Spec.Replicas)))
 }
 
 // WaitForOneKubeadmControlPlaneMachineToExistInput is the input for WaitForKubeadmControlPlaneMachinesToExist.
@@ -137,6 +141,7 @@ func WaitForOneKubeadmControlPlaneMachineToExist(ctx context.Context, input Wait
 		clusterv1.ClusterNameLabel:         input.Cluster.Name,
 	}
 
+	history := NewConditionHistory()
 	Eventually(func() (bool, error) {
 		machineList := &clusterv1.MachineList{}
 		if err := input.Lister.List(ctx, machineList, inClustersNamespaceListOption, matchClusterListOption); err != nil {
@@ -144,13 +149,15 @@ func WaitForOneKubeadmControlPlaneMachineToExist(ctx context.Context, input Wait
 			return false, err
 		}
 		count := 0
-		for _, machine := range machineList.Items {
+		for i := range machineList.Items {
+			machine := &machineList.Items[i]
+			history.Record(machine)
 			if machine.Status.NodeRef != nil {
 				count++
 			}
 		}
 		return count > 0, nil
-	}, intervals...).Should(BeTrue(), "No Control Plane machines came into existence. ")
+	}, intervals...).Should(BeTrue(), history.Describe("No Control Plane machines came into existence."))
 }
 
 // WaitForControlPlaneToBeReadyInput is the input for WaitForControlPlaneToBeReady.
@@ -163,6 +170,7 @@ type WaitForControlPlaneToBeReadyInput struct {
 func WaitForControlPlaneToBeReady(ctx context.Context, input WaitForControlPlaneToBeReadyInput, intervals ...interface{}) {
 	By("Waiting for the control plane to be ready")
 	controlplane := &controlplanev1.KubeadmControlPlane{}
+	history := NewConditionHistory()
 	Eventually(func() (bool, error) {
 		key := client.ObjectKey{
 			Namespace: input.ControlPlane.GetNamespace(),
@@ -171,6 +179,7 @@ func WaitForControlPlaneToBeReady(ctx context.Context, input WaitForControlPlane
 		if err := input.Getter.Get(ctx, key, controlplane); err != nil {
 			return false, errors.Wrapf(err, "failed to get KCP")
 		}
+		history.Record(controlplane)
 
 		desiredReplicas := controlplane.Spec.Replicas
 		statusReplicas := controlplane.Status.Replicas
@@ -190,7 +199,7 @@ func WaitForControlPlaneToBeReady(ctx context.Context, input WaitForControlPlane
 		}
 
 		return true, nil
-	}, intervals...).Should(BeTrue(), PrettyPrint(controlplane)+"\n")
+	}, intervals...).Should(BeTrue(), history.Describe("Timed out waiting for KubeadmControlPlane %s to be ready", klog.KObj(input.ControlPlane)))
 }
 
 // AssertControlPlaneFailureDomainsInput is the input for AssertControlPlaneFailureDomains.
@@ -330,6 +339,7 @@ func UpgradeControlPlaneAndWaitForUpgrade(ctx context.Context, input UpgradeCont
 	Expect(input.DNSImageTag).ToNot(BeNil(), "Invalid argument. input.DNSImageTag can't be empty when calling UpgradeControlPlaneAndWaitForUpgrade")
 
 	mgmtClient := input.ClusterProxy.GetClient()
+	start := time.Now()
 
 	log.Logf("Patching the new kubernetes version to KCP")
 	patchHelper, err := patch.NewHelper(input.ControlPlane, mgmtClient)
@@ -385,6 +395,8 @@ func UpgradeControlPlaneAndWaitForUpgrade(ctx context.Context, input UpgradeCont
 		ListOptions: &client.ListOptions{LabelSelector: lblSelector},
 		Condition:   EtcdImageTagCondition(input.EtcdImageTag, int(*input.ControlPlane.Spec.Replicas)),
 	}, input.WaitForEtcdUpgrade...)
+
+	RecordSpecMetric("control_plane_upgrade_duration_seconds", time.Since(start).Seconds(), map[string]string{"cluster": klog.KObj(input.Cluster).String(), "version": input.KubernetesUpgradeVersion})
 }
 
 // controlPlaneMachineOptions returns a set of ListOptions that allows to get all machine objects belonging to control plane.
@@ -22,7 +22,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -255,7 +254,6 @@ func (eh *watchPodLogsEventHandler) streamPodLogs(pod *corev1.Pod) {
 
 		// Create log metadata file.
 		logMetadataFile := filepath.Clean(path.Join(eh.input.LogPath, eh.input.DeploymentName, pod.Name, container.Name+"-log-metadata.json"))
-		Expect(os.MkdirAll(filepath.Dir(logMetadataFile), 0750)).To(Succeed())
 
 		metadata := logMetadata{
 			Job:       eh.input.Namespace + "/" + eh.input.DeploymentName,
@@ -268,16 +266,15 @@ func (eh *watchPodLogsEventHandler) streamPodLogs(pod *corev1.Pod) {
 		}
 		metadataBytes, err := json.Marshal(&metadata)
 		Expect(err).ToNot(HaveOccurred())
-		Expect(os.WriteFile(logMetadataFile, metadataBytes, 0600)).To(Succeed())
+		Expect(writeArtifact(eh.ctx, logMetadataFile, metadataBytes)).To(Succeed())
 
 		// Watch each container's logs in a goroutine so we can stream them all concurrently.
 		go func(pod *corev1.Pod, container corev1.Container) {
 			defer GinkgoRecover()
 
 			logFile := filepath.Clean(path.Join(eh.input.LogPath, eh.input.DeploymentName, pod.Name, container.Name+".log"))
-			Expect(os.MkdirAll(filepath.Dir(logFile), 0750)).To(Succeed())
 
-			f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			f, err := createArtifact(eh.ctx, logFile)
 			Expect(err).ToNot(HaveOccurred())
 			defer f.Close()
 
@@ -311,9 +308,12 @@ func (eh *watchPodLogsEventHandler) streamPodLogs(pod *corev1.Pod) {
 				}
 				defer podLogs.Close()
 
+				// The recent lines of the logs are also kept in memory, so they can be reported when a wait times out.
+				recentLogs := recentControllerLogs.writer(path.Join(eh.input.Namespace, eh.input.DeploymentName, pod.Name, container.Name))
+
 				out := bufio.NewWriter(f)
 				defer out.Flush()
-				_, err = out.ReadFrom(podLogs)
+				_, err = out.ReadFrom(io.TeeReader(podLogs, recentLogs))
 				if err != nil && err != io.ErrUnexpectedEOF {
 					// Failing to stream logs should not cause the test to fail
 					log.Logf("Got error while streaming logs for pod %s, container %s: %v", klog.KRef(pod.Namespace, pod.Name), container.Name, err)
@@ -387,7 +387,6 @@ func dumpPodMetrics(ctx context.Context, client *kubernetes.Clientset, metricsPa
 	for _, pod := range pods.Items {
 		metricsDir := path.Join(metricsPath, deploymentName, pod.Name)
 		metricsFile := path.Join(metricsDir, "metrics.txt")
-		Expect(os.MkdirAll(metricsDir, 0750)).To(Succeed())
 
 		res := client.CoreV1().RESTClient().Get().
 			Namespace(pod.Namespace).
@@ -404,7 +403,7 @@ func dumpPodMetrics(ctx context.Context, client *kubernetes.Clientset, metricsPa
 			metricsFile = path.Join(metricsDir, "metrics-error.txt")
 		}
 
-		if err := os.WriteFile(metricsFile, data, 0600); err != nil {
+		if err := writeArtifact(ctx, metricsFile, data); err != nil {
 			// Failing to dump metrics should not cause the test to fail
 			log.Logf("Error writing metrics for pod %s: %v", klog.KRef(pod.Namespace, pod.Name), err)
 		}
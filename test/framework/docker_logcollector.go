@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	osExec "os/exec"
 	"path/filepath"
@@ -49,7 +50,7 @@ func machineContainerName(cluster, machine string) string {
 
 func (k DockerLogCollector) CollectMachineLog(ctx context.Context, _ client.Client, m *clusterv1.Machine, outputPath string) error {
 	containerName := machineContainerName(m.Spec.ClusterName, m.Name)
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntimeClient("")
 	if err != nil {
 		return err
 	}
@@ -58,7 +59,7 @@ func (k DockerLogCollector) CollectMachineLog(ctx context.Context, _ client.Clie
 }
 
 func (k DockerLogCollector) CollectMachinePoolLog(ctx context.Context, _ client.Client, m *expv1.MachinePool, outputPath string) error {
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntimeClient("")
 	if err != nil {
 		return err
 	}
@@ -77,7 +78,7 @@ func (k DockerLogCollector) CollectMachinePoolLog(ctx context.Context, _ client.
 }
 
 func (k DockerLogCollector) CollectInfrastructureLogs(ctx context.Context, _ client.Client, c *clusterv1.Cluster, outputPath string) error {
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntimeClient("")
 	if err != nil {
 		return err
 	}
@@ -85,7 +86,7 @@ func (k DockerLogCollector) CollectInfrastructureLogs(ctx context.Context, _ cli
 
 	lbContainerName := fmt.Sprintf("%s-lb", c.GetName())
 
-	f, err := fileOnHost(filepath.Join(outputPath, fmt.Sprintf("%s.log", lbContainerName)))
+	f, err := createArtifact(ctx, filepath.Join(outputPath, fmt.Sprintf("%s.log", lbContainerName)))
 	if err != nil {
 		return err
 	}
@@ -103,7 +104,7 @@ func (k DockerLogCollector) collectLogsFromNode(ctx context.Context, outputPath
 
 	execToPathFn := func(outputFileName, command string, args ...string) func() error {
 		return func() error {
-			f, err := fileOnHost(filepath.Join(outputPath, outputFileName))
+			f, err := createArtifact(ctx, filepath.Join(outputPath, outputFileName))
 			if err != nil {
 				return err
 			}
@@ -116,6 +117,25 @@ func (k DockerLogCollector) collectLogsFromNode(ctx context.Context, outputPath
 	}
 	copyDirFn := func(containerDir, dirName string) func() error {
 		return func() error {
+			if getArtifactStore() != nil {
+				// Stream the files to the artifact store without extracting them on the local disk.
+				pr, pw := io.Pipe()
+				go func() {
+					execConfig := container.ExecContainerInput{
+						OutputBuffer: pw,
+					}
+					_ = pw.CloseWithError(containerRuntime.ExecContainer(
+						ctx,
+						containerName,
+						&execConfig,
+						"tar", "--hard-dereference", "--dereference", "--directory", containerDir, "--create", "--file", "-", ".",
+					))
+				}()
+				err := writeTarToArtifacts(ctx, pr, filepath.Join(outputPath, dirName))
+				_ = pr.CloseWithError(err)
+				return err
+			}
+
 			f, err := os.CreateTemp("", containerName)
 			if err != nil {
 				return err
@@ -149,7 +169,18 @@ func (k DockerLogCollector) collectLogsFromNode(ctx context.Context, outputPath
 			return osExec.Command("tar", "--extract", "--file", tempfileName, "--directory", outputDir).Run() //nolint:gosec // We don't care about command injection here.
 		}
 	}
+	consoleLogsFn := func(outputFileName string) func() error {
+		return func() error {
+			f, err := createArtifact(ctx, filepath.Join(outputPath, outputFileName))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return containerRuntime.ContainerLogs(ctx, containerName, f)
+		}
+	}
 	return errors.AggregateConcurrent([]func() error{
+		consoleLogsFn("console.log"),
 		execToPathFn(
 			"journal.log",
 			"journalctl", "--no-pager", "--output=short-precise",
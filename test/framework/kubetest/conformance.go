@@ -0,0 +1,262 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubetest
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/cluster-api/test/framework/ginkgoextensions"
+)
+
+// ConformanceRunner is a tool running the Kubernetes conformance tests.
+type ConformanceRunner string
+
+const (
+	// HydrophoneRunner runs the conformance tests with hydrophone, i.e. in a Pod of the workload cluster.
+	HydrophoneRunner ConformanceRunner = "hydrophone"
+
+	// Kubetest2Runner runs the conformance tests with the ginkgo tester of kubetest2, i.e. on the host running the
+	// tests, using the noop deployer.
+	Kubetest2Runner ConformanceRunner = "kubetest2"
+)
+
+// DefaultConformanceFocus is the focus used to run the conformance tests if not specified.
+const DefaultConformanceFocus = `\[Conformance\]`
+
+// RunConformanceInput is the input for RunConformance.
+type RunConformanceInput struct {
+	// ClusterProxy is the proxy for the workload cluster to run the conformance tests against.
+	ClusterProxy framework.ClusterProxy
+
+	// ArtifactsDirectory is where the output and the JUnit reports of the conformance tests go.
+	ArtifactsDirectory string
+
+	// Runner is the tool running the conformance tests; if not specified, hydrophone is used.
+	Runner ConformanceRunner
+
+	// RunnerBinary is the path of the binary of the runner; if not specified, the binary named after the runner is
+	// looked up in PATH. When using kubetest2, the kubetest2-noop and kubetest2-tester-ginkgo binaries must be in PATH.
+	RunnerBinary string
+
+	// Focus is the regular expression selecting the tests to run; if not specified, all the conformance tests are run.
+	Focus string
+
+	// Skip is the regular expression selecting the tests to skip.
+	Skip string
+
+	// Parallel is the number of tests run in parallel; if not specified, tests are run serially.
+	Parallel int
+
+	// KubernetesVersion is the version of the conformance tests to run; if not specified, the version of the
+	// workload cluster is used.
+	KubernetesVersion string
+
+	// ConformanceImage is the image running the conformance tests when using hydrophone; if not specified, the
+	// conformance image for KubernetesVersion is used.
+	ConformanceImage string
+
+	// ExtraArgs are additional args for the runner.
+	ExtraArgs []string
+}
+
+// RunConformance runs the Kubernetes conformance tests against a workload cluster with hydrophone or kubetest2,
+// streaming their output to a log file in the artifacts directory and gathering their JUnit reports.
+// An error is returned if the conformance tests cannot be run, or listing the tests which failed.
+func RunConformance(ctx context.Context, input RunConformanceInput) error {
+	if input.ClusterProxy == nil {
+		return errors.New("ClusterProxy must be provided")
+	}
+	if input.Runner == "" {
+		input.Runner = HydrophoneRunner
+	}
+	if input.RunnerBinary == "" {
+		input.RunnerBinary = string(input.Runner)
+	}
+	if input.Focus == "" {
+		input.Focus = DefaultConformanceFocus
+	}
+	if input.Parallel == 0 {
+		input.Parallel = 1
+	}
+	if input.KubernetesVersion == "" {
+		discoveredVersion, err := discoverClusterKubernetesVersion(input.ClusterProxy)
+		if err != nil {
+			return errors.Wrap(err, "Unable to discover server's Kubernetes version")
+		}
+		input.KubernetesVersion = discoveredVersion
+	}
+	if input.ConformanceImage == "" {
+		input.ConformanceImage = versionToConformanceImage(input.KubernetesVersion)
+	}
+
+	input.ArtifactsDirectory = framework.ResolveArtifactsDirectory(input.ArtifactsDirectory)
+	reportDir := path.Join(input.ArtifactsDirectory, "conformance")
+	if err := os.MkdirAll(reportDir, 0o750); err != nil {
+		return err
+	}
+
+	args, err := conformanceRunnerArgs(input, input.ClusterProxy.GetKubeconfigPath(), reportDir)
+	if err != nil {
+		return err
+	}
+
+	logFile, err := os.Create(path.Join(reportDir, fmt.Sprintf("%s.log", input.Runner))) //nolint:gosec // No security issue: the path is in the artifacts directory.
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	cmd := exec.CommandContext(ctx, input.RunnerBinary, args...) //nolint:gosec // We don't care about command injection here.
+	cmd.Stdout = io.MultiWriter(logFile, ginkgo.GinkgoWriter)
+	cmd.Stderr = cmd.Stdout
+	ginkgoextensions.Byf("Running conformance tests with %s: command=%q", input.Runner, cmd.String())
+	runErr := cmd.Run()
+
+	failures, err := conformanceFailures(reportDir)
+	if err != nil {
+		return err
+	}
+	if err := framework.GatherJUnitReports(reportDir, input.ArtifactsDirectory); err != nil {
+		return err
+	}
+	if len(failures) > 0 {
+		return errors.Errorf("%d conformance tests failed, see %s for details:\n- %s", len(failures), reportDir, strings.Join(failures, "\n- "))
+	}
+	if runErr != nil {
+		return errors.Wrapf(runErr, "failed to run conformance tests with %s, see %s for details", input.Runner, logFile.Name())
+	}
+	return nil
+}
+
+// conformanceRunnerArgs returns the args for running the conformance tests with the runner of the input.
+func conformanceRunnerArgs(input RunConformanceInput, kubeconfigPath, reportDir string) ([]string, error) {
+	var args []string
+	switch input.Runner {
+	case HydrophoneRunner:
+		args = []string{
+			"--kubeconfig", kubeconfigPath,
+			"--output-dir", reportDir,
+			"--conformance-image", input.ConformanceImage,
+			"--parallel", strconv.Itoa(input.Parallel),
+			"--focus", input.Focus,
+		}
+		if input.Skip != "" {
+			args = append(args, "--skip", input.Skip)
+		}
+	case Kubetest2Runner:
+		args = []string{
+			"noop",
+			"--kubeconfig=" + kubeconfigPath,
+			"--artifacts=" + reportDir,
+			"--test=ginkgo",
+			"--",
+			"--test-package-version=" + versionToTestPackageVersion(input.KubernetesVersion),
+			"--parallel=" + strconv.Itoa(input.Parallel),
+			"--focus-regex=" + input.Focus,
+		}
+		if input.Skip != "" {
+			args = append(args, "--skip-regex="+input.Skip)
+		}
+	default:
+		return nil, errors.Errorf("unknown conformance runner %q, must be one of %s or %s", input.Runner, HydrophoneRunner, Kubetest2Runner)
+	}
+	return append(args, input.ExtraArgs...), nil
+}
+
+// versionToTestPackageVersion returns the version of the test package of kubetest2 for a Kubernetes version,
+// dropping the build metadata, e.g. v1.28.0 for v1.28.0+k3s1.
+func versionToTestPackageVersion(kubernetesVersion string) string {
+	version, _, _ := strings.Cut(kubernetesVersion, "+")
+	return version
+}
+
+// junitTestCase is a test case of a JUnit report.
+type junitTestCase struct {
+	Name     string     `xml:"name,attr"`
+	Failures []struct{} `xml:"failure"`
+	Errors   []struct{} `xml:"error"`
+}
+
+// conformanceFailures returns the names of the test cases failed in the JUnit reports in the given directory and in
+// its sub directories.
+func conformanceFailures(reportDir string) ([]string, error) {
+	failures := []string{}
+	err := filepath.WalkDir(reportDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasPrefix(d.Name(), "junit") || filepath.Ext(p) != ".xml" {
+			return nil
+		}
+		f, err := os.Open(p) //nolint:gosec // No security issue: the path is in the artifacts directory.
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		reportFailures, err := junitFailures(f)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse JUnit report %s", p)
+		}
+		failures = append(failures, reportFailures...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(failures)
+	return failures, nil
+}
+
+// junitFailures returns the names of the failed test cases of a JUnit report.
+func junitFailures(r io.Reader) ([]string, error) {
+	failures := []string{}
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return failures, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "testcase" {
+			continue
+		}
+		testCase := &junitTestCase{}
+		if err := decoder.DecodeElement(testCase, &start); err != nil {
+			return nil, err
+		}
+		if len(testCase.Failures) > 0 || len(testCase.Errors) > 0 {
+			failures = append(failures, testCase.Name)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubetest
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestConformanceRunnerArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    RunConformanceInput
+		wantArgs []string
+		wantErr  bool
+	}{
+		{
+			name: "hydrophone",
+			input: RunConformanceInput{
+				Runner:           HydrophoneRunner,
+				Focus:            DefaultConformanceFocus,
+				Skip:             `\[Serial\]`,
+				Parallel:         4,
+				ConformanceImage: "registry.k8s.io/conformance:v1.28.0",
+				ExtraArgs:        []string{"--verbosity", "4"},
+			},
+			wantArgs: []string{
+				"--kubeconfig", "/kubeconfig",
+				"--output-dir", "/artifacts/conformance",
+				"--conformance-image", "registry.k8s.io/conformance:v1.28.0",
+				"--parallel", "4",
+				"--focus", `\[Conformance\]`,
+				"--skip", `\[Serial\]`,
+				"--verbosity", "4",
+			},
+		},
+		{
+			name: "kubetest2",
+			input: RunConformanceInput{
+				Runner:            Kubetest2Runner,
+				Focus:             DefaultConformanceFocus,
+				Parallel:          1,
+				KubernetesVersion: "v1.28.0+k3s1",
+			},
+			wantArgs: []string{
+				"noop",
+				"--kubeconfig=/kubeconfig",
+				"--artifacts=/artifacts/conformance",
+				"--test=ginkgo",
+				"--",
+				"--test-package-version=v1.28.0",
+				"--parallel=1",
+				`--focus-regex=\[Conformance\]`,
+			},
+		},
+		{
+			name:    "unknown runner",
+			input:   RunConformanceInput{Runner: "sonobuoy"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			args, err := conformanceRunnerArgs(tt.input, "/kubeconfig", "/artifacts/conformance")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(args).To(Equal(tt.wantArgs))
+		})
+	}
+}
+
+func TestJUnitFailures(t *testing.T) {
+	g := NewWithT(t)
+
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="4" failures="2">
+  <testsuite name="Kubernetes e2e suite" tests="4">
+    <testcase name="[sig-apps] Deployment should run the lifecycle of a Deployment [Conformance]" classname="Kubernetes e2e suite" time="10"></testcase>
+    <testcase name="[sig-network] DNS should provide DNS for services [Conformance]" classname="Kubernetes e2e suite" time="30">
+      <failure message="timed out" type="failed">timed out waiting for the condition</failure>
+    </testcase>
+    <testcase name="[sig-node] Pods should be submitted and removed [Conformance]" classname="Kubernetes e2e suite" time="5">
+      <error message="panic" type="panicked">panic</error>
+    </testcase>
+    <testcase name="[sig-storage] Volumes should be mountable [Conformance]" classname="Kubernetes e2e suite" time="0">
+      <skipped message="skipped"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+	failures, err := junitFailures(strings.NewReader(report))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(failures).To(Equal([]string{
+		"[sig-network] DNS should provide DNS for services [Conformance]",
+		"[sig-node] Pods should be submitted and removed [Conformance]",
+	}))
+
+	_, err = junitFailures(strings.NewReader("<testsuites><testcase"))
+	g.Expect(err).To(HaveOccurred())
+}
@@ -175,7 +175,7 @@ func Run(ctx context.Context, input RunInput) error {
 	cwd, _ := os.Getwd()
 	ginkgoextensions.Byf("Running e2e test: dir=%s, command=%q", cwd, args)
 
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntimeClient("")
 	if err != nil {
 		return errors.Wrap(err, "Unable to run conformance tests")
 	}
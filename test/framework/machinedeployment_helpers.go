@@ -104,6 +104,7 @@ func WaitForMachineDeploymentNodesToExist(ctx context.Context, input WaitForMach
 	Expect(input.MachineDeployment).ToNot(BeNil(), "Invalid argument. input.MachineDeployment can't be nil when calling WaitForMachineDeploymentNodesToExist")
 
 	By("Waiting for the workload nodes to exist")
+	history := NewConditionHistory()
 	Eventually(func(g Gomega) {
 		selectorMap, err := metav1.LabelSelectorAsMap(&input.MachineDeployment.Spec.Selector)
 		g.Expect(err).ToNot(HaveOccurred())
@@ -112,19 +113,22 @@ func WaitForMachineDeploymentNodesToExist(ctx context.Context, input WaitForMach
 		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(ms.Items).NotTo(BeEmpty())
 		machineSet := ms.Items[0]
+		history.Record(&machineSet)
 		selectorMap, err = metav1.LabelSelectorAsMap(&machineSet.Spec.Selector)
 		g.Expect(err).ToNot(HaveOccurred())
 		machines := &clusterv1.MachineList{}
 		err = input.Lister.List(ctx, machines, client.InNamespace(machineSet.Namespace), client.MatchingLabels(selectorMap))
 		g.Expect(err).ToNot(HaveOccurred())
 		count := 0
-		for _, machine := range machines.Items {
+		for i := range machines.Items {
+			machine := &machines.Items[i]
+			history.Record(machine)
 			if machine.Status.NodeRef != nil {
 				count++
 			}
 		}
 		g.Expect(count).To(Equal(int(*input.MachineDeployment.Spec.Replicas)))
-	}, intervals...).Should(Succeed(), "Timed out waiting for %d nodes to be created for MachineDeployment %s", int(*input.MachineDeployment.Spec.Replicas), klog.KObj(input.MachineDeployment))
+	}, intervals...).Should(Succeed(), history.Describe("Timed out waiting for %d nodes to be created for MachineDeployment %s", int(*input.MachineDeployment.Spec.Replicas), klog.KObj(input.MachineDeployment)))
 }
 
 // AssertMachineDeploymentFailureDomainsInput is the input for AssertMachineDeploymentFailureDomains.
@@ -0,0 +1,285 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/framework/internal/log"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+// RunConcurrently calls fn for each index in [0, count), running at most concurrency calls at the same time
+// (all of them if concurrency is zero or negative), and waits for all of them to complete.
+// A failed assertion or a panic in fn only stops the call it happens in; the failures are returned indexed
+// like the calls, with a nil error for each call which succeeded.
+// NOTE: a failed assertion in fn is still recorded by Ginkgo, so the spec fails once the calls are completed;
+// the returned errors only report where each call failed, so the failures can be aggregated by the caller.
+func RunConcurrently(count, concurrency int, fn func(i int)) []error {
+	if concurrency <= 0 || concurrency > count {
+		concurrency = count
+	}
+
+	failures := make([]error, count)
+	sem := make(chan struct{}, concurrency)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					failures[i] = panicToError(r)
+				}
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+	return failures
+}
+
+// panicToError converts the value recovered from a panic into an error; Ginkgo's Fail panics with the location of the
+// failed assertion, while the failure message itself is recorded by Ginkgo for the spec.
+func panicToError(r interface{}) error {
+	switch v := r.(type) {
+	case types.GinkgoError:
+		return errors.Errorf("assertion failed at %s", v.CodeLocation)
+	case error:
+		return errors.Wrap(v, "panic")
+	default:
+		return errors.Errorf("panic: %v", v)
+	}
+}
+
+// FailOnConcurrentFailures fails the spec if any of the given failures, keyed by the name of the item which failed
+// (e.g. a Cluster), is not nil, reporting all of them in a single failure message.
+func FailOnConcurrentFailures(operation string, failures map[string]error) {
+	names := []string{}
+	for name, err := range failures {
+		if err != nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+
+	msg := &strings.Builder{}
+	fmt.Fprintf(msg, "%s failed for %d of %d items:", operation, len(names), len(failures))
+	for _, name := range names {
+		fmt.Fprintf(msg, "\n- %s: %v", name, failures[name])
+	}
+	Fail(msg.String(), 1)
+}
+
+// DumpResourcesForClustersInput is the input for DumpResourcesForClusters.
+type DumpResourcesForClustersInput struct {
+	ClusterProxy   ClusterProxy
+	Clusters       []*clusterv1.Cluster
+	ArtifactFolder string
+
+	// Concurrency is the maximum number of clusters dumped at the same time; all the clusters are dumped at the same
+	// time if not set.
+	Concurrency int
+}
+
+// DumpResourcesForClusters dumps, for each of the given clusters, the logs of its machines, all the Cluster API resources
+// in its namespace and the Pods and Nodes of the workload cluster, if it still exists.
+// Dumping is best effort: a failure dumping one cluster is logged, it does not fail the spec and it does not prevent
+// dumping the other clusters.
+func DumpResourcesForClusters(ctx context.Context, input DumpResourcesForClustersInput) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for DumpResourcesForClusters")
+	Expect(input.ClusterProxy).ToNot(BeNil(), "Invalid argument. input.ClusterProxy can't be nil when calling DumpResourcesForClusters")
+	Expect(input.ArtifactFolder).ToNot(BeEmpty(), "Invalid argument. input.ArtifactFolder can't be empty when calling DumpResourcesForClusters")
+
+	// NOTE: fn only uses helpers returning errors, so failures are reported without failing the spec.
+	managementClusterClient := input.ClusterProxy.GetClient()
+
+	// Resources are dumped once per namespace, even when the namespace hosts many clusters.
+	namespaces := map[string]bool{}
+	for _, cluster := range input.Clusters {
+		namespaces[cluster.Namespace] = true
+	}
+	dumpedNamespaces := sync.Map{}
+
+	failures := make([]error, len(input.Clusters))
+	panics := RunConcurrently(len(input.Clusters), input.Concurrency, func(i int) {
+		cluster := input.Clusters[i]
+
+		log.Logf("Dumping logs from the workload cluster %s", klog.KObj(cluster))
+		collectWorkloadClusterLogs(ctx, managementClusterClient, input.ClusterProxy.GetLogCollector(), cluster, filepath.Join(input.ArtifactFolder, "clusters", cluster.Name))
+
+		if _, dumped := dumpedNamespaces.LoadOrStore(cluster.Namespace, true); !dumped {
+			log.Logf("Dumping all the Cluster API resources in the %q namespace", cluster.Namespace)
+			if err := dumpAllResources(ctx, managementClusterClient, cluster.Namespace, filepath.Join(input.ArtifactFolder, "clusters", input.ClusterProxy.GetName(), "resources")); err != nil {
+				failures[i] = err
+				return
+			}
+		}
+
+		if err := managementClusterClient.Get(ctx, client.ObjectKeyFromObject(cluster), &clusterv1.Cluster{}); err != nil {
+			return
+		}
+		workloadClusterClient, err := getWorkloadClusterClient(ctx, managementClusterClient, input.ClusterProxy.GetScheme(), cluster)
+		if err != nil {
+			failures[i] = err
+			return
+		}
+		log.Logf("Dumping Pods and Nodes of Cluster %s", klog.KObj(cluster))
+		failures[i] = dumpResourcesForCluster(ctx, DumpResourcesForClusterInput{
+			Lister:  workloadClusterClient,
+			Cluster: cluster,
+			LogPath: filepath.Join(input.ArtifactFolder, "clusters", cluster.Name, "resources"),
+			Resources: []DumpNamespaceAndGVK{
+				{GVK: schema.GroupVersionKind{Version: corev1.SchemeGroupVersion.Version, Kind: "Pod"}},
+				{GVK: schema.GroupVersionKind{Version: corev1.SchemeGroupVersion.Version, Kind: "Node"}},
+			},
+		})
+	})
+
+	for i := range input.Clusters {
+		err := failures[i]
+		if panics[i] != nil {
+			err = panics[i]
+		}
+		if err != nil {
+			// NB. we are treating failures in collecting resources as a non-blocking operation (best effort)
+			fmt.Printf("Failed to dump resources for Cluster %s: %v\n", klog.KObj(input.Clusters[i]), err)
+		}
+	}
+	log.Logf("Dumped resources for %d clusters in %d namespaces", len(input.Clusters), len(namespaces))
+}
+
+// collectWorkloadClusterLogs collects the logs of the machines and of the infrastructure of a workload cluster,
+// like ClusterProxy.CollectWorkloadClusterLogs, logging failures instead of failing the spec.
+func collectWorkloadClusterLogs(ctx context.Context, managementClusterClient client.Client, logCollector ClusterLogCollector, cluster *clusterv1.Cluster, outputPath string) {
+	if logCollector == nil {
+		fmt.Printf("Unable to get logs for workload Cluster %s: log collector is nil.\n", klog.KObj(cluster))
+		return
+	}
+
+	machines, err := getMachinesInCluster(ctx, managementClusterClient, cluster.Namespace, cluster.Name)
+	if err != nil {
+		fmt.Printf("Failed to get Machines for Cluster %s: %v\n", klog.KObj(cluster), err)
+	} else {
+		for i := range machines.Items {
+			m := &machines.Items[i]
+			if err := logCollector.CollectMachineLog(ctx, managementClusterClient, m, path.Join(outputPath, "machines", m.GetName())); err != nil {
+				fmt.Printf("Failed to get logs for Machine %s, Cluster %s: %v\n", m.GetName(), klog.KObj(cluster), err)
+			}
+		}
+	}
+
+	machinePools, err := getMachinePoolsInCluster(ctx, managementClusterClient, cluster.Namespace, cluster.Name)
+	if err != nil {
+		fmt.Printf("Failed to get MachinePools for Cluster %s: %v\n", klog.KObj(cluster), err)
+	} else {
+		for i := range machinePools.Items {
+			mp := &machinePools.Items[i]
+			if err := logCollector.CollectMachinePoolLog(ctx, managementClusterClient, mp, path.Join(outputPath, "machine-pools", mp.GetName())); err != nil {
+				fmt.Printf("Failed to get logs for MachinePool %s, Cluster %s: %v\n", mp.GetName(), klog.KObj(cluster), err)
+			}
+		}
+	}
+
+	if err := logCollector.CollectInfrastructureLogs(ctx, managementClusterClient, cluster, path.Join(outputPath, "infrastructure")); err != nil {
+		fmt.Printf("Failed to get infrastructure logs for Cluster %s: %v\n", klog.KObj(cluster), err)
+	}
+}
+
+// getWorkloadClusterClient returns a client for a workload cluster using its kubeconfig Secret, returning an error
+// instead of failing the spec.
+// NOTE: unlike ClusterProxy.GetWorkloadCluster, the address of the control plane of DockerClusters is not fixed up
+// when running on macOS; dumping the Pods and Nodes of those clusters fails and it is reported as a failure.
+func getWorkloadClusterClient(ctx context.Context, managementClusterClient client.Client, scheme *runtime.Scheme, cluster *clusterv1.Cluster) (client.Client, error) {
+	kubeconfig, err := secret.GetFromNamespacedName(ctx, managementClusterClient, client.ObjectKeyFromObject(cluster), secret.Kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the kubeconfig of Cluster %s", klog.KObj(cluster))
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig.Data[secret.KubeconfigDataName])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the kubeconfig of Cluster %s", klog.KObj(cluster))
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create a client for Cluster %s", klog.KObj(cluster))
+	}
+	return c, nil
+}
+
+// DeleteClustersInNamespacesAndWaitInput is the input for DeleteClustersInNamespacesAndWait.
+type DeleteClustersInNamespacesAndWaitInput struct {
+	ClusterProxy ClusterProxy
+	Namespaces   []*corev1.Namespace
+
+	// DeleteNamespaces if set to true deletes the namespaces once all the clusters in them are gone.
+	DeleteNamespaces bool
+
+	// Concurrency is the maximum number of namespaces cleaned up at the same time; all the namespaces are cleaned up
+	// at the same time if not set.
+	Concurrency int
+}
+
+// DeleteClustersInNamespacesAndWait deletes all the clusters in the given namespaces concurrently and waits for them to be gone;
+// the spec fails with the list of the namespaces which could not be cleaned up, after all of them have been processed.
+func DeleteClustersInNamespacesAndWait(ctx context.Context, input DeleteClustersInNamespacesAndWaitInput, intervals ...interface{}) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for DeleteClustersInNamespacesAndWait")
+	Expect(input.ClusterProxy).ToNot(BeNil(), "Invalid argument. input.ClusterProxy can't be nil when calling DeleteClustersInNamespacesAndWait")
+
+	errs := RunConcurrently(len(input.Namespaces), input.Concurrency, func(i int) {
+		namespace := input.Namespaces[i]
+		DeleteAllClustersAndWait(ctx, DeleteAllClustersAndWaitInput{
+			Client:    input.ClusterProxy.GetClient(),
+			Namespace: namespace.Name,
+		}, intervals...)
+
+		if input.DeleteNamespaces {
+			DeleteNamespace(ctx, DeleteNamespaceInput{
+				Deleter: input.ClusterProxy.GetClient(),
+				Name:    namespace.Name,
+			})
+		}
+	})
+
+	failures := map[string]error{}
+	for i, err := range errs {
+		failures[input.Namespaces[i].Name] = err
+	}
+	FailOnConcurrentFailures("Deleting clusters", failures)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/test/framework"
+)
+
+func TestRunConcurrently(t *testing.T) {
+	t.Run("limits the number of concurrent calls", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var running, maxRunning, calls int32
+		errs := framework.RunConcurrently(10, 3, func(i int) {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				observed := atomic.LoadInt32(&maxRunning)
+				if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&calls, 1)
+		})
+
+		g.Expect(errs).To(HaveLen(10))
+		g.Expect(errs).To(HaveEach(BeNil()))
+		g.Expect(calls).To(Equal(int32(10)))
+		g.Expect(maxRunning).To(BeNumerically("<=", 3))
+	})
+
+	t.Run("reports the failures of each call without stopping the others", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls int32
+		errs := framework.RunConcurrently(4, 0, func(i int) {
+			atomic.AddInt32(&calls, 1)
+			if i%2 == 1 {
+				panic("boom")
+			}
+		})
+
+		g.Expect(calls).To(Equal(int32(4)))
+		g.Expect(errs[0]).ToNot(HaveOccurred())
+		g.Expect(errs[1]).To(MatchError(ContainSubstring("boom")))
+		g.Expect(errs[2]).ToNot(HaveOccurred())
+		g.Expect(errs[3]).To(MatchError(ContainSubstring("boom")))
+	})
+}
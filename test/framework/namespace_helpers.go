@@ -143,9 +143,8 @@ func WatchNamespaceEvents(ctx context.Context, input WatchNamespaceEventsInput)
 	Expect(input.Name).NotTo(BeEmpty(), "input.Name is required for WatchNamespaceEvents")
 
 	logFile := filepath.Clean(path.Join(input.LogFolder, "resources", input.Name, "events.log"))
-	Expect(os.MkdirAll(filepath.Dir(logFile), 0750)).To(Succeed())
 
-	f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	f, err := createArtifact(ctx, logFile)
 	Expect(err).ToNot(HaveOccurred())
 	defer f.Close()
 
@@ -213,3 +212,40 @@ func CreateNamespaceAndWatchEvents(ctx context.Context, input CreateNamespaceAnd
 	}()
 	return namespace, cancelWatches
 }
+
+// CreateNamespacesAndWatchEventsInput is the input type for CreateNamespacesAndWatchEvents.
+type CreateNamespacesAndWatchEventsInput struct {
+	Creator    Creator
+	ClientSet  *kubernetes.Clientset
+	NamePrefix string
+	Count      int
+	LogFolder  string
+}
+
+// CreateNamespacesAndWatchEvents creates Count namespaces named "<NamePrefix>-<index>-<random suffix>", e.g. for hosting
+// one cluster each in specs provisioning many clusters, and setups a watch for the events of each namespace.
+// The returned cancel func stops all the watches.
+func CreateNamespacesAndWatchEvents(ctx context.Context, input CreateNamespacesAndWatchEventsInput) ([]*corev1.Namespace, context.CancelFunc) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for CreateNamespacesAndWatchEvents")
+	Expect(input.NamePrefix).ToNot(BeEmpty(), "Invalid argument. input.NamePrefix can't be empty when calling CreateNamespacesAndWatchEvents")
+	Expect(input.Count).To(BeNumerically(">", 0), "Invalid argument. input.Count must be greater than zero when calling CreateNamespacesAndWatchEvents")
+
+	namespaces := make([]*corev1.Namespace, 0, input.Count)
+	cancelFuncs := make([]context.CancelFunc, 0, input.Count)
+	cancelWatches := func() {
+		for _, cancel := range cancelFuncs {
+			cancel()
+		}
+	}
+	for i := 0; i < input.Count; i++ {
+		namespace, cancel := CreateNamespaceAndWatchEvents(ctx, CreateNamespaceAndWatchEventsInput{
+			Creator:   input.Creator,
+			ClientSet: input.ClientSet,
+			Name:      fmt.Sprintf("%s-%d-%s", input.NamePrefix, i, util.RandomString(6)),
+			LogFolder: input.LogFolder,
+		})
+		namespaces = append(namespaces, namespace)
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+	return namespaces, cancelWatches
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// SpecStepsJUnitReportFile is the name of the JUnit report with the step-level timings of each spec, written
+	// by WriteSpecResults into the artifacts folder.
+	SpecStepsJUnitReportFile = "junit.e2e-steps.xml"
+
+	// SpecMetricsFile is the name of the file with the metrics of each spec in the Prometheus text format, written
+	// by WriteSpecResults into the artifacts folder; it can be pushed as is to a Prometheus Pushgateway.
+	SpecMetricsFile = "e2e-metrics.prom"
+
+	// specMetricsPrefix is the prefix of the names of the metrics in SpecMetricsFile.
+	specMetricsPrefix = "capi_e2e_"
+
+	// specMetricReportEntryName is the name of the Ginkgo report entries storing the metrics recorded by a spec.
+	specMetricReportEntryName = "capi-e2e-metric"
+)
+
+// SpecMetric is a metric recorded by a spec, e.g. the time it took to provision a cluster.
+type SpecMetric struct {
+	// Name is the name of the metric, e.g. cluster_provisioning_duration_seconds.
+	Name string `json:"name"`
+
+	// Value is the value of the metric.
+	Value float64 `json:"value"`
+
+	// Labels are additional labels of the metric, e.g. the cluster it refers to.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// RecordSpecMetric records a metric of the current spec, to be written by WriteSpecResults into the metrics file.
+// When the same metric is recorded more than once with the same labels, the last value is kept.
+func RecordSpecMetric(name string, value float64, labels map[string]string) {
+	ginkgo.AddReportEntry(specMetricReportEntryName, SpecMetric{Name: name, Value: value, Labels: labels}, ginkgo.ReportEntryVisibilityNever)
+}
+
+// WriteSpecResults writes the structured results of the specs in the given report to the artifacts folder, so
+// release dashboards can track the performance of the providers under test:
+//   - a JUnit report with a test suite for each spec, and a test case with the duration of each step of the spec.
+//   - a file with the metrics of each spec in the Prometheus text format, including the spec duration and result
+//     and the metrics recorded with RecordSpecMetric, e.g. cluster provisioning or upgrade duration.
+//
+// It is intended to be called from ReportAfterSuite, so the results of all the Ginkgo parallel processes are included.
+func WriteSpecResults(ctx context.Context, report types.Report, artifactFolder string) error {
+	data, err := specStepsJUnitReport(report)
+	if err != nil {
+		return err
+	}
+	if err := writeArtifact(ctx, filepath.Join(artifactFolder, SpecStepsJUnitReportFile), data); err != nil {
+		return errors.Wrapf(err, "failed to write %s", SpecStepsJUnitReportFile)
+	}
+
+	data, err = specMetrics(report)
+	if err != nil {
+		return err
+	}
+	if err := writeArtifact(ctx, filepath.Join(artifactFolder, SpecMetricsFile), data); err != nil {
+		return errors.Wrapf(err, "failed to write %s", SpecMetricsFile)
+	}
+	return nil
+}
+
+// specsWithResults returns the reports of the specs which ran, i.e. excluding suite nodes and skipped or pending specs.
+func specsWithResults(report types.Report) types.SpecReports {
+	return report.SpecReports.WithLeafNodeType(types.NodeTypeIt).WithState(types.SpecStatePassed | types.SpecStateFailureStates)
+}
+
+// specStepsJUnitReport returns a JUnit report with a test suite for each spec, and a test case for each step of the
+// spec; the duration of a step is the time until the next step starts, or the spec ends.
+func specStepsJUnitReport(report types.Report) ([]byte, error) {
+	suites := reporters.JUnitTestSuites{}
+	for _, spec := range specsWithResults(report) {
+		suite := reporters.JUnitTestSuite{
+			Name:      spec.FullText(),
+			Package:   report.SuitePath,
+			Time:      spec.RunTime.Seconds(),
+			Timestamp: spec.StartTime.Format("2006-01-02T15:04:05"),
+		}
+
+		steps := []types.SpecEvent{}
+		for _, event := range spec.SpecEvents {
+			if event.SpecEventType == types.SpecEventByStart {
+				steps = append(steps, event)
+			}
+		}
+		if len(steps) == 0 {
+			// Report specs without steps as a single step, so all the specs have at least a test case.
+			steps = append(steps, types.SpecEvent{Message: spec.LeafNodeText, TimelineLocation: types.TimelineLocation{Time: spec.StartTime}})
+		}
+
+		// The spec failed during the last step started before the failure.
+		failedStep := -1
+		if spec.State.Is(types.SpecStateFailureStates) {
+			failedStep = len(steps) - 1
+			if failureTime := spec.Failure.TimelineLocation.Time; !failureTime.IsZero() {
+				for i := range steps {
+					if !failureTime.Before(steps[i].TimelineLocation.Time) {
+						failedStep = i
+					}
+				}
+			}
+		}
+
+		for i, step := range steps {
+			end := spec.EndTime
+			if i+1 < len(steps) {
+				end = steps[i+1].TimelineLocation.Time
+			}
+			testCase := reporters.JUnitTestCase{
+				Name:      step.Message,
+				Classname: spec.FullText(),
+				Status:    "passed",
+				Time:      end.Sub(step.TimelineLocation.Time).Seconds(),
+			}
+			if i == failedStep {
+				testCase.Status = spec.State.String()
+				testCase.Failure = &reporters.JUnitFailure{
+					Message:     spec.Failure.Message,
+					Type:        spec.State.String(),
+					Description: spec.Failure.Location.String(),
+				}
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+			suite.Tests++
+		}
+
+		suites.TestSuites = append(suites.TestSuites, suite)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Time += suite.Time
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the JUnit report of the spec steps")
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// specMetrics returns the metrics of each spec in the Prometheus text format; all the metrics are gauges, with a spec
+// label in addition to the labels they were recorded with.
+func specMetrics(report types.Report) ([]byte, error) {
+	metrics := map[string]map[string]SpecMetric{}
+	add := func(spec types.SpecReport, m SpecMetric) {
+		labels := map[string]string{}
+		for k, v := range m.Labels {
+			labels[k] = v
+		}
+		labels["spec"] = spec.FullText()
+		m.Labels = labels
+
+		name := specMetricsPrefix + m.Name
+		if _, ok := metrics[name]; !ok {
+			metrics[name] = map[string]SpecMetric{}
+		}
+		metrics[name][labelsKey(labels)] = m
+	}
+
+	for _, spec := range specsWithResults(report) {
+		succeeded := 0.0
+		if spec.State == types.SpecStatePassed {
+			succeeded = 1
+		}
+		add(spec, SpecMetric{Name: "spec_duration_seconds", Value: spec.RunTime.Seconds()})
+		add(spec, SpecMetric{Name: "spec_succeeded", Value: succeeded})
+
+		for _, entry := range spec.ReportEntries {
+			if entry.Name != specMetricReportEntryName {
+				continue
+			}
+			// NOTE: the value is the SpecMetric recorded by this process, or its JSON representation decoded into
+			// a map when it was recorded by another Ginkgo parallel process.
+			raw, err := json.Marshal(entry.Value.GetRawValue())
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to marshal metric of spec %q", spec.FullText())
+			}
+			m := SpecMetric{}
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal metric of spec %q", spec.FullText())
+			}
+			add(spec, m)
+		}
+	}
+
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := &bytes.Buffer{}
+	for _, name := range names {
+		keys := make([]string, 0, len(metrics[name]))
+		for key := range metrics[name] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		family := &dto.MetricFamily{
+			Name: pointer.String(name),
+			Type: dto.MetricType_GAUGE.Enum(),
+		}
+		for _, key := range keys {
+			m := metrics[name][key]
+			labelNames := make([]string, 0, len(m.Labels))
+			for k := range m.Labels {
+				labelNames = append(labelNames, k)
+			}
+			sort.Strings(labelNames)
+
+			metric := &dto.Metric{Gauge: &dto.Gauge{Value: pointer.Float64(m.Value)}}
+			for _, k := range labelNames {
+				metric.Label = append(metric.Label, &dto.LabelPair{Name: pointer.String(k), Value: pointer.String(m.Labels[k])})
+			}
+			family.Metric = append(family.Metric, metric)
+		}
+		if _, err := expfmt.MetricFamilyToText(out, family); err != nil {
+			return nil, errors.Wrapf(err, "failed to write metric %s", name)
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// labelsKey returns a key identifying a set of labels.
+func labelsKey(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
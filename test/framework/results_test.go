@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	. "github.com/onsi/gomega"
+)
+
+func TestSpecResults(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) types.TimelineLocation {
+		return types.TimelineLocation{Time: start.Add(time.Duration(seconds) * time.Second)}
+	}
+	step := func(seconds int, message string) types.SpecEvent {
+		return types.SpecEvent{SpecEventType: types.SpecEventByStart, Message: message, TimelineLocation: at(seconds)}
+	}
+	metric := func(m SpecMetric) types.ReportEntry {
+		return types.ReportEntry{Name: specMetricReportEntryName, Value: types.WrapEntryValue(m)}
+	}
+	// Metrics recorded by other Ginkgo parallel processes are decoded from JSON.
+	remoteMetric := func(m SpecMetric) types.ReportEntry {
+		data, err := json.Marshal(metric(m).Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value := types.ReportEntryValue{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			t.Fatal(err)
+		}
+		return types.ReportEntry{Name: specMetricReportEntryName, Value: value}
+	}
+
+	report := types.Report{
+		SuitePath: "/test/e2e",
+		SpecReports: types.SpecReports{
+			{
+				LeafNodeType: types.NodeTypeSynchronizedBeforeSuite,
+				State:        types.SpecStatePassed,
+			},
+			{
+				ContainerHierarchyTexts: []string{"When testing upgrades"},
+				LeafNodeType:            types.NodeTypeIt,
+				LeafNodeText:            "Should upgrade",
+				State:                   types.SpecStatePassed,
+				StartTime:               start,
+				EndTime:                 start.Add(100 * time.Second),
+				RunTime:                 100 * time.Second,
+				SpecEvents: types.SpecEvents{
+					step(0, "Creating a workload cluster"),
+					step(60, "Upgrading the cluster"),
+				},
+				ReportEntries: types.ReportEntries{
+					{Name: "other entry", Value: types.WrapEntryValue("ignored")},
+					metric(SpecMetric{Name: "cluster_provisioning_duration_seconds", Value: 30, Labels: map[string]string{"cluster": "ns/c1"}}),
+					metric(SpecMetric{Name: "cluster_provisioning_duration_seconds", Value: 55, Labels: map[string]string{"cluster": "ns/c1"}}),
+					remoteMetric(SpecMetric{Name: "machines_created", Value: 3, Labels: map[string]string{"cluster": "ns/c1"}}),
+				},
+			},
+			{
+				LeafNodeType: types.NodeTypeIt,
+				LeafNodeText: "Should fail",
+				State:        types.SpecStateFailed,
+				StartTime:    start,
+				EndTime:      start.Add(30 * time.Second),
+				RunTime:      30 * time.Second,
+				SpecEvents: types.SpecEvents{
+					step(0, "Creating a workload cluster"),
+					step(10, "Scaling the cluster"),
+					step(20, "Deleting the cluster"),
+				},
+				Failure: types.Failure{Message: "timed out", TimelineLocation: at(15)},
+			},
+			{
+				LeafNodeType: types.NodeTypeIt,
+				LeafNodeText: "Should be skipped",
+				State:        types.SpecStateSkipped,
+			},
+		},
+	}
+
+	t.Run("JUnit report with step-level timings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data, err := specStepsJUnitReport(report)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		suites := reporters.JUnitTestSuites{}
+		g.Expect(xml.Unmarshal(data, &suites)).To(Succeed())
+		g.Expect(suites.Tests).To(Equal(5))
+		g.Expect(suites.Failures).To(Equal(1))
+		g.Expect(suites.TestSuites).To(HaveLen(2))
+
+		upgrade := suites.TestSuites[0]
+		g.Expect(upgrade.Name).To(Equal("When testing upgrades Should upgrade"))
+		g.Expect(upgrade.TestCases).To(HaveLen(2))
+		g.Expect(upgrade.TestCases[0].Name).To(Equal("Creating a workload cluster"))
+		g.Expect(upgrade.TestCases[0].Time).To(Equal(60.0))
+		g.Expect(upgrade.TestCases[1].Name).To(Equal("Upgrading the cluster"))
+		g.Expect(upgrade.TestCases[1].Time).To(Equal(40.0))
+
+		failed := suites.TestSuites[1]
+		g.Expect(failed.Failures).To(Equal(1))
+		g.Expect(failed.TestCases[0].Failure).To(BeNil())
+		g.Expect(failed.TestCases[1].Failure).ToNot(BeNil())
+		g.Expect(failed.TestCases[1].Failure.Message).To(Equal("timed out"))
+		g.Expect(failed.TestCases[2].Failure).To(BeNil())
+	})
+
+	t.Run("Metrics in the Prometheus text format", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data, err := specMetrics(report)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(data)).To(Equal(`# TYPE capi_e2e_cluster_provisioning_duration_seconds gauge
+capi_e2e_cluster_provisioning_duration_seconds{cluster="ns/c1",spec="When testing upgrades Should upgrade"} 55
+# TYPE capi_e2e_machines_created gauge
+capi_e2e_machines_created{cluster="ns/c1",spec="When testing upgrades Should upgrade"} 3
+# TYPE capi_e2e_spec_duration_seconds gauge
+capi_e2e_spec_duration_seconds{spec="Should fail"} 30
+capi_e2e_spec_duration_seconds{spec="When testing upgrades Should upgrade"} 100
+# TYPE capi_e2e_spec_succeeded gauge
+capi_e2e_spec_succeeded{spec="Should fail"} 0
+capi_e2e_spec_succeeded{spec="When testing upgrades Should upgrade"} 1
+`))
+	})
+}
@@ -0,0 +1,427 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/cluster-api/test/framework/internal/log"
+)
+
+const (
+	reconcileTimeMetric   = "controller_runtime_reconcile_time_seconds"
+	reconcileErrorsMetric = "controller_runtime_reconcile_errors_total"
+	apiRequestsMetric     = "rest_client_requests_total"
+)
+
+// Histogram is a Prometheus histogram.
+type Histogram struct {
+	// Buckets are the buckets of the histogram, sorted by upper bound.
+	Buckets []HistogramBucket
+
+	// Count is the number of observations.
+	Count float64
+
+	// Sum is the sum of the observations.
+	Sum float64
+}
+
+// HistogramBucket is a bucket of a Prometheus histogram.
+type HistogramBucket struct {
+	// UpperBound is the upper bound of the bucket.
+	UpperBound float64
+
+	// CumulativeCount is the number of observations lower or equal to the upper bound.
+	CumulativeCount float64
+}
+
+// add returns the sum of h and other, or h minus other if sign is negative.
+func (h *Histogram) add(other *Histogram, sign float64) *Histogram {
+	if h == nil {
+		h = &Histogram{}
+	}
+	if other == nil {
+		other = &Histogram{}
+	}
+
+	counts := map[float64]float64{}
+	for _, b := range h.Buckets {
+		counts[b.UpperBound] += b.CumulativeCount
+	}
+	for _, b := range other.Buckets {
+		counts[b.UpperBound] += sign * b.CumulativeCount
+	}
+	ret := &Histogram{
+		Count: h.Count + sign*other.Count,
+		Sum:   h.Sum + sign*other.Sum,
+	}
+	for upperBound, count := range counts {
+		ret.Buckets = append(ret.Buckets, HistogramBucket{UpperBound: upperBound, CumulativeCount: count})
+	}
+	sort.Slice(ret.Buckets, func(i, j int) bool { return ret.Buckets[i].UpperBound < ret.Buckets[j].UpperBound })
+	return ret
+}
+
+// Quantile returns the q-quantile of the observations of the histogram, using a linear interpolation within the
+// bucket of the quantile like the histogram_quantile function of Prometheus.
+// If the quantile is in the +Inf bucket, the upper bound of the previous bucket is returned.
+func (h *Histogram) Quantile(q float64) float64 {
+	if h == nil || h.Count <= 0 || len(h.Buckets) == 0 {
+		return 0
+	}
+
+	rank := q * h.Count
+	lowerBound, lowerCount := 0.0, 0.0
+	for _, b := range h.Buckets {
+		if b.CumulativeCount >= rank {
+			if math.IsInf(b.UpperBound, 1) {
+				return lowerBound
+			}
+			if b.CumulativeCount == lowerCount {
+				return b.UpperBound
+			}
+			return lowerBound + (b.UpperBound-lowerBound)*(rank-lowerCount)/(b.CumulativeCount-lowerCount)
+		}
+		lowerBound, lowerCount = b.UpperBound, b.CumulativeCount
+	}
+	return lowerBound
+}
+
+// ControllerMetrics are the metrics of a controller manager which are relevant for measuring its performance.
+type ControllerMetrics struct {
+	// ReconcileTime is the histogram of the reconcile durations, in seconds, by controller.
+	ReconcileTime map[string]*Histogram
+
+	// ReconcileErrors is the number of reconciles which returned an error, by controller.
+	ReconcileErrors map[string]float64
+
+	// APIRequests is the number of requests sent to the API server, by HTTP method.
+	APIRequests map[string]float64
+}
+
+func newControllerMetrics() *ControllerMetrics {
+	return &ControllerMetrics{
+		ReconcileTime:   map[string]*Histogram{},
+		ReconcileErrors: map[string]float64{},
+		APIRequests:     map[string]float64{},
+	}
+}
+
+// ParseControllerMetrics parses the metrics of a controller manager in the Prometheus text format.
+func ParseControllerMetrics(r io.Reader) (*ControllerMetrics, error) {
+	parser := &expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse metrics")
+	}
+
+	m := newControllerMetrics()
+	if family, ok := families[reconcileTimeMetric]; ok {
+		for _, metric := range family.GetMetric() {
+			controller := labelValue(metric, "controller")
+			h := &Histogram{
+				Count: float64(metric.GetHistogram().GetSampleCount()),
+				Sum:   metric.GetHistogram().GetSampleSum(),
+			}
+			for _, b := range metric.GetHistogram().GetBucket() {
+				h.Buckets = append(h.Buckets, HistogramBucket{UpperBound: b.GetUpperBound(), CumulativeCount: float64(b.GetCumulativeCount())})
+			}
+			// The +Inf bucket is implicit in the text format.
+			h.Buckets = append(h.Buckets, HistogramBucket{UpperBound: math.Inf(1), CumulativeCount: h.Count})
+			m.ReconcileTime[controller] = m.ReconcileTime[controller].add(h, 1)
+		}
+	}
+	if family, ok := families[reconcileErrorsMetric]; ok {
+		for _, metric := range family.GetMetric() {
+			m.ReconcileErrors[labelValue(metric, "controller")] += metric.GetCounter().GetValue()
+		}
+	}
+	if family, ok := families[apiRequestsMetric]; ok {
+		for _, metric := range family.GetMetric() {
+			m.APIRequests[labelValue(metric, "method")] += metric.GetCounter().GetValue()
+		}
+	}
+	return m, nil
+}
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, l := range metric.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
+
+// Add adds other to the metrics, e.g. for aggregating the metrics of all the replicas of a controller manager.
+func (m *ControllerMetrics) Add(other *ControllerMetrics) {
+	m.merge(other, 1)
+}
+
+// Sub subtracts other from the metrics, e.g. for computing the metrics of the operations executed in a time frame.
+func (m *ControllerMetrics) Sub(other *ControllerMetrics) {
+	m.merge(other, -1)
+}
+
+func (m *ControllerMetrics) merge(other *ControllerMetrics, sign float64) {
+	if other == nil {
+		return
+	}
+	for controller, h := range other.ReconcileTime {
+		m.ReconcileTime[controller] = m.ReconcileTime[controller].add(h, sign)
+	}
+	for controller, v := range other.ReconcileErrors {
+		m.ReconcileErrors[controller] += sign * v
+	}
+	for method, v := range other.APIRequests {
+		m.APIRequests[method] += sign * v
+	}
+}
+
+// GetControllerMetricsInput is the input for GetControllerMetrics.
+type GetControllerMetricsInput struct {
+	Lister            Lister
+	ClientSet         *kubernetes.Clientset
+	ExcludeNamespaces []string
+}
+
+// GetControllerMetrics scrapes the metrics of the pods of all the Cluster API controllers existing in a management
+// cluster, and returns them aggregated by controller Deployment, keyed by namespace/name.
+// NOTE: Scraping the metrics is best effort; pods whose metrics cannot be scraped are logged and ignored.
+func GetControllerMetrics(ctx context.Context, input GetControllerMetricsInput) map[string]*ControllerMetrics {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for GetControllerMetrics")
+	Expect(input.Lister).ToNot(BeNil(), "Invalid argument. input.Lister can't be nil when calling GetControllerMetrics")
+	Expect(input.ClientSet).ToNot(BeNil(), "Invalid argument. input.ClientSet can't be nil when calling GetControllerMetrics")
+
+	ret := map[string]*ControllerMetrics{}
+	for _, deployment := range GetControllerDeployments(ctx, GetControllerDeploymentsInput{
+		Lister:            input.Lister,
+		ExcludeNamespaces: input.ExcludeNamespaces,
+	}) {
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		Expect(err).ToNot(HaveOccurred(), "Failed to convert the selector of Deployment %s", klog.KObj(deployment))
+		pods, err := input.ClientSet.CoreV1().Pods(deployment.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			log.Logf("Failed to list the pods of Deployment %s: %v", klog.KObj(deployment), err)
+			continue
+		}
+
+		metrics := newControllerMetrics()
+		for _, pod := range pods.Items {
+			data, err := input.ClientSet.CoreV1().RESTClient().Get().
+				Namespace(pod.Namespace).
+				Resource("pods").
+				Name(fmt.Sprintf("%s:8080", pod.Name)).
+				SubResource("proxy").
+				Suffix("metrics").
+				Do(ctx).
+				Raw()
+			if err != nil {
+				log.Logf("Failed to scrape the metrics of pod %s: %v", klog.KObj(&pod), err)
+				continue
+			}
+			podMetrics, err := ParseControllerMetrics(bytes.NewReader(data))
+			if err != nil {
+				log.Logf("Failed to parse the metrics of pod %s: %v", klog.KObj(&pod), err)
+				continue
+			}
+			metrics.Add(podMetrics)
+		}
+		ret[klog.KObj(deployment).String()] = metrics
+	}
+	return ret
+}
+
+// ScaleMeasurements are the measurements of a scale test; they are stored as JSON, so they can be compared
+// across runs and releases.
+type ScaleMeasurements struct {
+	// ClusterCount is the number of clusters created by the test.
+	ClusterCount int64 `json:"clusterCount"`
+
+	// MachinesPerCluster is the number of machines of each cluster.
+	MachinesPerCluster int64 `json:"machinesPerCluster"`
+
+	// ClusterCreation summarizes the time for creating a cluster and waiting for it to be provisioned.
+	ClusterCreation DurationSummary `json:"clusterCreation"`
+
+	// ClusterDeletion summarizes the time for deleting a cluster and waiting for it to be gone.
+	ClusterDeletion DurationSummary `json:"clusterDeletion,omitempty"`
+
+	// Controllers are the measurements of each controller Deployment, keyed by namespace/name.
+	Controllers map[string]ControllerMeasurements `json:"controllers,omitempty"`
+}
+
+// DurationSummary summarizes a set of durations.
+type DurationSummary struct {
+	Count      int     `json:"count"`
+	P50Seconds float64 `json:"p50Seconds"`
+	P90Seconds float64 `json:"p90Seconds"`
+	P99Seconds float64 `json:"p99Seconds"`
+	MaxSeconds float64 `json:"maxSeconds"`
+}
+
+// NewDurationSummary returns the summary of the given durations, with nearest-rank percentiles.
+func NewDurationSummary(durations []time.Duration) DurationSummary {
+	if len(durations) == 0 {
+		return DurationSummary{}
+	}
+
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p * float64(len(sorted))))
+		if rank < 1 {
+			rank = 1
+		}
+		return sorted[rank-1].Seconds()
+	}
+	return DurationSummary{
+		Count:      len(sorted),
+		P50Seconds: percentile(0.5),
+		P90Seconds: percentile(0.9),
+		P99Seconds: percentile(0.99),
+		MaxSeconds: sorted[len(sorted)-1].Seconds(),
+	}
+}
+
+// ControllerMeasurements are the measurements of a controller Deployment.
+type ControllerMeasurements struct {
+	// Reconciles are the measurements of the reconciles, by controller.
+	Reconciles map[string]ReconcileMeasurements `json:"reconciles,omitempty"`
+
+	// APIRequests is the number of requests sent to the API server.
+	APIRequests float64 `json:"apiRequests"`
+
+	// APIRequestsByMethod is the number of requests sent to the API server, by HTTP method.
+	APIRequestsByMethod map[string]float64 `json:"apiRequestsByMethod,omitempty"`
+}
+
+// ReconcileMeasurements are the measurements of the reconciles of a controller.
+type ReconcileMeasurements struct {
+	Count      float64 `json:"count"`
+	Errors     float64 `json:"errors"`
+	P50Seconds float64 `json:"p50Seconds"`
+	P90Seconds float64 `json:"p90Seconds"`
+	P99Seconds float64 `json:"p99Seconds"`
+}
+
+// NewControllerMeasurements returns the measurements of a controller Deployment from its metrics.
+func NewControllerMeasurements(metrics *ControllerMetrics) ControllerMeasurements {
+	m := ControllerMeasurements{
+		Reconciles:          map[string]ReconcileMeasurements{},
+		APIRequestsByMethod: map[string]float64{},
+	}
+	for controller, h := range metrics.ReconcileTime {
+		if h.Count <= 0 {
+			continue
+		}
+		m.Reconciles[controller] = ReconcileMeasurements{
+			Count:      h.Count,
+			Errors:     metrics.ReconcileErrors[controller],
+			P50Seconds: h.Quantile(0.5),
+			P90Seconds: h.Quantile(0.9),
+			P99Seconds: h.Quantile(0.99),
+		}
+	}
+	for method, v := range metrics.APIRequests {
+		if v <= 0 {
+			continue
+		}
+		m.APIRequests += v
+		m.APIRequestsByMethod[method] = v
+	}
+	return m
+}
+
+// ScaleSLOs are the service level objectives of a scale test; a zero value for an objective means it is not checked.
+type ScaleSLOs struct {
+	// ClusterCreationP99 is the maximum 99th percentile of the time for creating a cluster and waiting for it to be provisioned.
+	ClusterCreationP99 time.Duration
+
+	// ClusterDeletionP99 is the maximum 99th percentile of the time for deleting a cluster and waiting for it to be gone.
+	ClusterDeletionP99 time.Duration
+
+	// ReconcileP99 is the maximum 99th percentile of the reconcile time of each controller.
+	ReconcileP99 time.Duration
+
+	// APIRequestsPerCluster is the maximum number of requests sent to the API server by each controller Deployment,
+	// divided by the number of clusters.
+	APIRequestsPerCluster float64
+}
+
+// Check returns an error listing all the objectives which are not met by the given measurements.
+func (s ScaleSLOs) Check(m ScaleMeasurements) error {
+	errs := []error{}
+	if s.ClusterCreationP99 > 0 && m.ClusterCreation.P99Seconds > s.ClusterCreationP99.Seconds() {
+		errs = append(errs, errors.Errorf("cluster creation p99 %.1fs exceeds %s", m.ClusterCreation.P99Seconds, s.ClusterCreationP99))
+	}
+	if s.ClusterDeletionP99 > 0 && m.ClusterDeletion.P99Seconds > s.ClusterDeletionP99.Seconds() {
+		errs = append(errs, errors.Errorf("cluster deletion p99 %.1fs exceeds %s", m.ClusterDeletion.P99Seconds, s.ClusterDeletionP99))
+	}
+
+	deployments := make([]string, 0, len(m.Controllers))
+	for deployment := range m.Controllers {
+		deployments = append(deployments, deployment)
+	}
+	sort.Strings(deployments)
+	for _, deployment := range deployments {
+		c := m.Controllers[deployment]
+		if s.ReconcileP99 > 0 {
+			controllers := make([]string, 0, len(c.Reconciles))
+			for controller := range c.Reconciles {
+				controllers = append(controllers, controller)
+			}
+			sort.Strings(controllers)
+			for _, controller := range controllers {
+				if p99 := c.Reconciles[controller].P99Seconds; p99 > s.ReconcileP99.Seconds() {
+					errs = append(errs, errors.Errorf("reconcile p99 %.3fs of controller %s in %s exceeds %s", p99, controller, deployment, s.ReconcileP99))
+				}
+			}
+		}
+		if s.APIRequestsPerCluster > 0 && m.ClusterCount > 0 {
+			if perCluster := c.APIRequests / float64(m.ClusterCount); perCluster > s.APIRequestsPerCluster {
+				errs = append(errs, errors.Errorf("%.1f API requests per cluster of %s exceed %.1f", perCluster, deployment, s.APIRequestsPerCluster))
+			}
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// WriteScaleMeasurements writes the measurements of a scale test as JSON to the artifact with the given path.
+func WriteScaleMeasurements(ctx context.Context, artifactPath string, m ScaleMeasurements) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal scale measurements")
+	}
+	return writeArtifact(ctx, artifactPath, data)
+}
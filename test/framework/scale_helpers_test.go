@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/test/framework"
+)
+
+const controllerMetrics = `# HELP controller_runtime_reconcile_time_seconds Length of time per reconciliation per controller
+# TYPE controller_runtime_reconcile_time_seconds histogram
+controller_runtime_reconcile_time_seconds_bucket{controller="machine",le="0.1"} 50
+controller_runtime_reconcile_time_seconds_bucket{controller="machine",le="1"} 90
+controller_runtime_reconcile_time_seconds_bucket{controller="machine",le="+Inf"} 100
+controller_runtime_reconcile_time_seconds_sum{controller="machine"} 42
+controller_runtime_reconcile_time_seconds_count{controller="machine"} 100
+# HELP controller_runtime_reconcile_errors_total Total number of reconciliation errors per controller
+# TYPE controller_runtime_reconcile_errors_total counter
+controller_runtime_reconcile_errors_total{controller="machine"} 3
+# HELP rest_client_requests_total Number of HTTP requests, partitioned by status code, method, and host.
+# TYPE rest_client_requests_total counter
+rest_client_requests_total{code="200",host="10.96.0.1:443",method="GET"} 120
+rest_client_requests_total{code="404",host="10.96.0.1:443",method="GET"} 5
+rest_client_requests_total{code="200",host="10.96.0.1:443",method="PATCH"} 30
+`
+
+func TestParseControllerMetrics(t *testing.T) {
+	g := NewWithT(t)
+
+	m, err := framework.ParseControllerMetrics(strings.NewReader(controllerMetrics))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m.ReconcileTime).To(HaveKey("machine"))
+	g.Expect(m.ReconcileTime["machine"].Count).To(Equal(100.0))
+	g.Expect(m.ReconcileErrors).To(HaveKeyWithValue("machine", 3.0))
+	g.Expect(m.APIRequests).To(Equal(map[string]float64{"GET": 125, "PATCH": 30}))
+
+	// Quantiles are interpolated within the bucket, like histogram_quantile does.
+	g.Expect(m.ReconcileTime["machine"].Quantile(0.5)).To(BeNumerically("~", 0.1, 1e-9))
+	g.Expect(m.ReconcileTime["machine"].Quantile(0.7)).To(BeNumerically("~", 0.55, 1e-9))
+	// Quantiles in the +Inf bucket return the highest finite upper bound.
+	g.Expect(m.ReconcileTime["machine"].Quantile(0.99)).To(BeNumerically("~", 1, 1e-9))
+
+	// The metrics of a time frame are the difference between the metrics at its end and at its start.
+	before, err := framework.ParseControllerMetrics(strings.NewReader(controllerMetrics))
+	g.Expect(err).ToNot(HaveOccurred())
+	m.Add(before)
+	m.Sub(before)
+	m.Sub(before)
+	g.Expect(m.ReconcileTime["machine"].Count).To(BeZero())
+	g.Expect(m.ReconcileTime["machine"].Quantile(0.99)).To(BeZero())
+	g.Expect(m.APIRequests).To(Equal(map[string]float64{"GET": 0, "PATCH": 0}))
+}
+
+func TestNewDurationSummary(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(framework.NewDurationSummary(nil)).To(Equal(framework.DurationSummary{}))
+
+	durations := []time.Duration{}
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Second)
+	}
+	g.Expect(framework.NewDurationSummary(durations)).To(Equal(framework.DurationSummary{
+		Count:      100,
+		P50Seconds: 50,
+		P90Seconds: 90,
+		P99Seconds: 99,
+		MaxSeconds: 100,
+	}))
+}
+
+func TestScaleSLOsCheck(t *testing.T) {
+	m := framework.ScaleMeasurements{
+		ClusterCount:    10,
+		ClusterCreation: framework.DurationSummary{Count: 10, P99Seconds: 120},
+		ClusterDeletion: framework.DurationSummary{Count: 10, P99Seconds: 30},
+		Controllers: map[string]framework.ControllerMeasurements{
+			"capi-system/capi-controller-manager": {
+				Reconciles: map[string]framework.ReconcileMeasurements{
+					"machine": {Count: 100, P99Seconds: 0.5},
+					"cluster": {Count: 100, P99Seconds: 2},
+				},
+				APIRequests: 5000,
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		slos     framework.ScaleSLOs
+		wantErrs []string
+	}{
+		{
+			name: "No objectives",
+			slos: framework.ScaleSLOs{},
+		},
+		{
+			name: "All objectives met",
+			slos: framework.ScaleSLOs{
+				ClusterCreationP99:    3 * time.Minute,
+				ClusterDeletionP99:    time.Minute,
+				ReconcileP99:          5 * time.Second,
+				APIRequestsPerCluster: 1000,
+			},
+		},
+		{
+			name: "All objectives violated",
+			slos: framework.ScaleSLOs{
+				ClusterCreationP99:    time.Minute,
+				ClusterDeletionP99:    10 * time.Second,
+				ReconcileP99:          time.Second,
+				APIRequestsPerCluster: 100,
+			},
+			wantErrs: []string{
+				"cluster creation p99 120.0s exceeds 1m0s",
+				"cluster deletion p99 30.0s exceeds 10s",
+				"reconcile p99 2.000s of controller cluster in capi-system/capi-controller-manager exceeds 1s",
+				"500.0 API requests per cluster of capi-system/capi-controller-manager exceed 100.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tt.slos.Check(m)
+			if len(tt.wantErrs) == 0 {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(HaveOccurred())
+			for _, want := range tt.wantErrs {
+				g.Expect(err.Error()).To(ContainSubstring(want))
+			}
+		})
+	}
+}
@@ -0,0 +1,374 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	. "sigs.k8s.io/cluster-api/test/framework/ginkgoextensions"
+	"sigs.k8s.io/cluster-api/test/framework/internal/log"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+)
+
+// ManagementClusterSnapshotMethod is the method used to snapshot and restore the state of a management cluster.
+type ManagementClusterSnapshotMethod string
+
+const (
+	// ObjectsManagementClusterSnapshot exports the components installed by clusterctl, i.e. the objects with the
+	// clusterctl label, and re-applies them on restore; it works with any management cluster.
+	ObjectsManagementClusterSnapshot ManagementClusterSnapshotMethod = "objects"
+
+	// EtcdManagementClusterSnapshot takes a snapshot of etcd and restores it; it only works with kind management
+	// clusters with a single control plane node, but it restores the exact state of the cluster.
+	EtcdManagementClusterSnapshot ManagementClusterSnapshotMethod = "etcd"
+)
+
+const (
+	// etcdSnapshotPath is the path of the etcd snapshot in the control plane node of a kind management cluster.
+	etcdSnapshotPath = "/var/lib/e2e-etcd-snapshot.db"
+
+	// kindRoleLabelKey is the label applied to the containers of kind clusters, with the role of the node as value.
+	kindRoleLabelKey = "io.x-k8s.kind.role"
+
+	// snapshotRestoreFieldManager is the field manager used to re-apply the objects of a snapshot.
+	snapshotRestoreFieldManager = "capi-e2e-snapshot-restore"
+)
+
+// ManagementClusterSnapshot is the state of a management cluster taken with SnapshotManagementCluster,
+// to be restored with RestoreManagementCluster.
+type ManagementClusterSnapshot struct {
+	method          ManagementClusterSnapshotMethod
+	kindClusterName string
+	namespaces      sets.Set[string]
+	objects         []*unstructured.Unstructured
+}
+
+// SnapshotManagementClusterInput is the input for SnapshotManagementCluster.
+type SnapshotManagementClusterInput struct {
+	ClusterProxy ClusterProxy
+
+	// Method is the method used to snapshot the management cluster; if not specified, objects is used.
+	Method ManagementClusterSnapshotMethod
+
+	// KindClusterName is the name of the kind management cluster when using the etcd method; if not specified,
+	// the name of the cluster proxy is used.
+	KindClusterName string
+}
+
+// SnapshotManagementCluster takes a snapshot of the state of a management cluster, usually after installing the
+// providers, so it can be restored between specs with RestoreManagementCluster instead of re-creating the
+// management cluster, e.g. before retrying a flaky spec from a clean known state.
+func SnapshotManagementCluster(ctx context.Context, input SnapshotManagementClusterInput) *ManagementClusterSnapshot {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for SnapshotManagementCluster")
+	Expect(input.ClusterProxy).ToNot(BeNil(), "Invalid argument. input.ClusterProxy can't be nil when calling SnapshotManagementCluster")
+	if input.Method == "" {
+		input.Method = ObjectsManagementClusterSnapshot
+	}
+	Expect(input.Method).To(BeElementOf(ObjectsManagementClusterSnapshot, EtcdManagementClusterSnapshot), "Invalid argument. input.Method must be one of %s or %s when calling SnapshotManagementCluster", ObjectsManagementClusterSnapshot, EtcdManagementClusterSnapshot)
+	if input.KindClusterName == "" {
+		input.KindClusterName = input.ClusterProxy.GetName()
+	}
+
+	snapshot := &ManagementClusterSnapshot{
+		method:          input.Method,
+		kindClusterName: input.KindClusterName,
+		namespaces:      listNamespaceNames(ctx, input.ClusterProxy.GetClient()),
+	}
+
+	Byf("Taking a snapshot of the management cluster %s with method %s", input.ClusterProxy.GetName(), input.Method)
+	switch input.Method {
+	case ObjectsManagementClusterSnapshot:
+		snapshot.objects = listClusterctlComponents(ctx, input.ClusterProxy)
+		log.Logf("Exported %d objects installed by clusterctl", len(snapshot.objects))
+	case EtcdManagementClusterSnapshot:
+		node := kindControlPlaneNode(ctx, input.KindClusterName)
+		Expect(execKindNode(ctx, node, etcdSnapshotScript())).To(Succeed(), "Failed to take a snapshot of etcd of the management cluster %s", input.KindClusterName)
+	}
+	return snapshot
+}
+
+// RestoreManagementClusterInput is the input for RestoreManagementCluster.
+type RestoreManagementClusterInput struct {
+	ClusterProxy ClusterProxy
+	Snapshot     *ManagementClusterSnapshot
+}
+
+// RestoreManagementCluster restores the state of a management cluster from a snapshot taken with SnapshotManagementCluster:
+//   - the Clusters in the namespaces created after the snapshot are deleted, so their infrastructure is cleaned up, and
+//     then the namespaces are deleted.
+//   - with the objects method, the components installed by clusterctl after the snapshot are deleted, and the ones in
+//     the snapshot are re-applied, e.g. undoing a provider upgrade or a Deployment scaled down by a spec.
+//   - with the etcd method, etcd is restored from the snapshot and the API server is restarted.
+//
+// Then it waits for the provider controllers to be available.
+// NOTE: the state is shared by all the specs using the management cluster, so it should be restored only when no
+// other spec is running, e.g. when running specs serially.
+func RestoreManagementCluster(ctx context.Context, input RestoreManagementClusterInput, intervals ...interface{}) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for RestoreManagementCluster")
+	Expect(input.ClusterProxy).ToNot(BeNil(), "Invalid argument. input.ClusterProxy can't be nil when calling RestoreManagementCluster")
+	Expect(input.Snapshot).ToNot(BeNil(), "Invalid argument. input.Snapshot can't be nil when calling RestoreManagementCluster")
+
+	c := input.ClusterProxy.GetClient()
+	Byf("Restoring the management cluster %s from a snapshot with method %s", input.ClusterProxy.GetName(), input.Snapshot.method)
+
+	newNamespaces := sets.List(listNamespaceNames(ctx, c).Difference(input.Snapshot.namespaces))
+	for _, namespace := range newNamespaces {
+		log.Logf("Deleting the Clusters in namespace %s, created after the snapshot", namespace)
+		DeleteAllClustersAndWait(ctx, DeleteAllClustersAndWaitInput{
+			Client:    c,
+			Namespace: namespace,
+		}, intervals...)
+	}
+
+	switch input.Snapshot.method {
+	case ObjectsManagementClusterSnapshot:
+		for _, namespace := range newNamespaces {
+			DeleteNamespace(ctx, DeleteNamespaceInput{Deleter: c, Name: namespace}, intervals...)
+		}
+		restoreClusterctlComponents(ctx, input.ClusterProxy, input.Snapshot.objects, intervals...)
+	case EtcdManagementClusterSnapshot:
+		node := kindControlPlaneNode(ctx, input.Snapshot.kindClusterName)
+		Expect(execKindNode(ctx, node, etcdRestoreScript())).To(Succeed(), "Failed to restore etcd of the management cluster %s", input.Snapshot.kindClusterName)
+
+		log.Logf("Waiting for the API server of the management cluster %s to be available", input.ClusterProxy.GetName())
+		Eventually(func() error {
+			return c.List(ctx, &corev1.NamespaceList{})
+		}, intervals...).Should(Succeed(), "Failed to wait for the API server of the management cluster %s to be available", input.ClusterProxy.GetName())
+	}
+
+	for _, deployment := range GetControllerDeployments(ctx, GetControllerDeploymentsInput{Lister: c}) {
+		if input.Snapshot.method == EtcdManagementClusterSnapshot {
+			// The caches of the controllers are newer than the restored state, so the controllers must be restarted.
+			RestartControllerPods(ctx, RestartControllerPodsInput{Client: c, Deployment: deployment}, intervals...)
+			continue
+		}
+		WaitForDeploymentsAvailable(ctx, WaitForDeploymentsAvailableInput{
+			Getter:     c,
+			Deployment: deployment,
+		}, intervals...)
+	}
+}
+
+// listNamespaceNames returns the names of the namespaces of a cluster.
+func listNamespaceNames(ctx context.Context, c client.Client) sets.Set[string] {
+	namespaceList := &corev1.NamespaceList{}
+	Eventually(func() error {
+		return c.List(ctx, namespaceList)
+	}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to list namespaces")
+
+	names := sets.Set[string]{}
+	for _, ns := range namespaceList.Items {
+		names.Insert(ns.Name)
+	}
+	return names
+}
+
+// listClusterctlComponents returns the objects of all the types served by a cluster with the clusterctl label, i.e. the
+// components installed by clusterctl, without the fields set by the API server.
+func listClusterctlComponents(ctx context.Context, clusterProxy ClusterProxy) []*unstructured.Unstructured {
+	resourceLists, err := clusterProxy.GetClientSet().Discovery().ServerPreferredResources()
+	// Types of API groups that cannot be discovered, e.g. aggregated APIs not available, are ignored.
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		Expect(err).ToNot(HaveOccurred(), "Failed to discover the types served by cluster %s", clusterProxy.GetName())
+	}
+
+	objects := []*unstructured.Unstructured{}
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		Expect(err).ToNot(HaveOccurred())
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || !sets.New[string](resource.Verbs...).HasAll("list", "patch") {
+				continue
+			}
+
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gv.WithKind(resource.Kind + "List"))
+			Eventually(func() error {
+				return clusterProxy.GetClient().List(ctx, list, client.HasLabels{clusterctlv1.ClusterctlLabel})
+			}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to list %s", resource.Kind)
+
+			for i := range list.Items {
+				obj := &list.Items[i]
+				obj.SetGroupVersionKind(gv.WithKind(resource.Kind))
+				removeServerFields(obj)
+				objects = append(objects, obj)
+			}
+		}
+	}
+
+	sortForRestore(objects)
+	return objects
+}
+
+// removeServerFields removes from an object the fields set by the API server, so it can be re-applied.
+func removeServerFields(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "generation")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "uid")
+}
+
+// sortForRestore sorts the objects of a snapshot in the order they are restored in: Namespaces and
+// CustomResourceDefinitions are restored first, because the other objects depend on them.
+func sortForRestore(objects []*unstructured.Unstructured) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return snapshotRestoreOrder(objects[i]) < snapshotRestoreOrder(objects[j])
+	})
+}
+
+// snapshotRestoreOrder returns the order an object is restored in.
+func snapshotRestoreOrder(obj *unstructured.Unstructured) int {
+	switch obj.GetKind() {
+	case "Namespace":
+		return 0
+	case "CustomResourceDefinition":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// snapshotObjectKey returns a key identifying an object of a snapshot.
+func snapshotObjectKey(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s %s", obj.GroupVersionKind().GroupKind(), klog.KObj(obj))
+}
+
+// restoreClusterctlComponents deletes the components installed by clusterctl which are not in the snapshot, and
+// re-applies the ones in the snapshot.
+func restoreClusterctlComponents(ctx context.Context, clusterProxy ClusterProxy, objects []*unstructured.Unstructured, intervals ...interface{}) {
+	c := clusterProxy.GetClient()
+
+	inSnapshot := sets.Set[string]{}
+	for _, obj := range objects {
+		inSnapshot.Insert(snapshotObjectKey(obj))
+	}
+	for _, obj := range listClusterctlComponents(ctx, clusterProxy) {
+		if inSnapshot.Has(snapshotObjectKey(obj)) {
+			continue
+		}
+		log.Logf("Deleting %s, installed after the snapshot", snapshotObjectKey(obj))
+		Eventually(func() error {
+			if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			return nil
+		}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to delete %s", snapshotObjectKey(obj))
+	}
+
+	log.Logf("Re-applying %d objects installed by clusterctl", len(objects))
+	// Objects are re-applied until they all succeed, because e.g. webhooks could not be available until their
+	// Deployments are restored.
+	Eventually(func() error {
+		errs := []string{}
+		for _, obj := range objects {
+			if err := c.Patch(ctx, obj.DeepCopy(), client.Apply, client.FieldOwner(snapshotRestoreFieldManager), client.ForceOwnership); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", snapshotObjectKey(obj), err))
+			}
+		}
+		if len(errs) > 0 {
+			return errors.Errorf("failed to re-apply %d objects:\n%s", len(errs), strings.Join(errs, "\n"))
+		}
+		return nil
+	}, intervals...).Should(Succeed(), "Failed to re-apply the objects of the snapshot")
+}
+
+// kindControlPlaneNode returns the name of the control plane node of a kind cluster, which must have only one.
+func kindControlPlaneNode(ctx context.Context, kindClusterName string) string {
+	containerRuntime, err := container.NewRuntimeClient("")
+	Expect(err).ToNot(HaveOccurred(), "Failed to get the container runtime")
+
+	filters := container.FilterBuilder{}
+	filters.AddKeyNameValue("label", kindClusterLabelKey, kindClusterName)
+	filters.AddKeyNameValue("label", kindRoleLabelKey, "control-plane")
+	containers, err := containerRuntime.ListContainers(ctx, filters)
+	Expect(err).ToNot(HaveOccurred(), "Failed to list the containers of the kind cluster %s", kindClusterName)
+	Expect(containers).To(HaveLen(1), "The etcd method requires the kind cluster %s to have exactly one control plane node", kindClusterName)
+	return containers[0].Name
+}
+
+// execKindNode runs a shell script in a node of a kind cluster.
+func execKindNode(ctx context.Context, node, script string) error {
+	containerRuntime, err := container.NewRuntimeClient("")
+	if err != nil {
+		return errors.Wrap(err, "failed to get the container runtime")
+	}
+	ctx = container.RuntimeInto(ctx, containerRuntime)
+
+	var stdout, stderr bytes.Buffer
+	if err := containerRuntime.ExecContainer(ctx, node, &container.ExecContainerInput{OutputBuffer: &stdout, ErrorBuffer: &stderr}, "sh", "-c", script); err != nil {
+		return errors.Wrapf(err, "failed to run script in node %s: %s%s", node, stdout.String(), stderr.String())
+	}
+	return nil
+}
+
+// etcdctlInEtcdContainer is a script fragment running etcdctl in the etcd container of a kind control plane node.
+const etcdctlInEtcdContainer = `crictl exec "$(crictl ps --name '^etcd$' -q)" etcdctl --endpoints=https://127.0.0.1:2379 ` +
+	`--cacert=/etc/kubernetes/pki/etcd/ca.crt --cert=/etc/kubernetes/pki/etcd/server.crt --key=/etc/kubernetes/pki/etcd/server.key`
+
+// etcdSnapshotScript returns a script taking a snapshot of etcd in a kind control plane node; the snapshot is written
+// in the etcd data dir, which is a host path of the etcd Pod, and then moved out of it so it survives the restore.
+func etcdSnapshotScript() string {
+	return fmt.Sprintf(`set -e
+%[1]s snapshot save /var/lib/etcd/e2e-etcd-snapshot.db
+mv /var/lib/etcd/e2e-etcd-snapshot.db %[2]s
+`, etcdctlInEtcdContainer, etcdSnapshotPath)
+}
+
+// etcdRestoreScript returns a script restoring etcd from a snapshot in a kind control plane node: the etcd and API
+// server static Pods are stopped, the etcd data dir is replaced with the one restored from the snapshot using etcdutl
+// in the etcd image, with the same member configuration, and then the static Pods are started again.
+func etcdRestoreScript() string {
+	return fmt.Sprintf(`set -e
+manifests=/etc/kubernetes/manifests
+image=$(sed -n 's/^ *image: *//p' $manifests/etcd.yaml)
+flag() { sed -n "s/^ *- --$1=//p" $manifests/etcd.yaml; }
+name=$(flag name)
+initial_cluster=$(flag initial-cluster)
+peer_urls=$(flag initial-advertise-peer-urls)
+
+mv $manifests/etcd.yaml $manifests/kube-apiserver.yaml /etc/kubernetes/
+while [ -n "$(crictl ps --name '^(etcd|kube-apiserver)$' -q)" ]; do sleep 1; done
+
+rm -rf /var/lib/etcd-restore
+ctr -n k8s.io run --rm --mount type=bind,src=/var/lib,dst=/var/lib,options=rbind:rw "$image" e2e-etcd-restore \
+  etcdutl snapshot restore %[1]s --data-dir /var/lib/etcd-restore \
+  --name "$name" --initial-cluster "$initial_cluster" --initial-advertise-peer-urls "$peer_urls"
+rm -rf /var/lib/etcd/member
+mv /var/lib/etcd-restore/member /var/lib/etcd/member
+rm -rf /var/lib/etcd-restore
+
+mv /etc/kubernetes/etcd.yaml /etc/kubernetes/kube-apiserver.yaml $manifests/
+`, etcdSnapshotPath)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRemoveServerFields(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":              "capi-controller-manager",
+			"namespace":         "capi-system",
+			"labels":            map[string]interface{}{"clusterctl.cluster.x-k8s.io": ""},
+			"creationTimestamp": "2023-01-01T00:00:00Z",
+			"generation":        int64(2),
+			"managedFields":     []interface{}{map[string]interface{}{"manager": "clusterctl"}},
+			"resourceVersion":   "42",
+			"uid":               "5b1e9a3c-1f4e-4d6a-9c1b-7c1f0e0f3a2d",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+		},
+		"status": map[string]interface{}{
+			"availableReplicas": int64(1),
+		},
+	}}
+
+	removeServerFields(obj)
+	g.Expect(obj.Object).To(Equal(map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "capi-controller-manager",
+			"namespace": "capi-system",
+			"labels":    map[string]interface{}{"clusterctl.cluster.x-k8s.io": ""},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+		},
+	}))
+}
+
+func TestSortForRestore(t *testing.T) {
+	g := NewWithT(t)
+
+	newObject := func(kind, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetKind(kind)
+		obj.SetName(name)
+		return obj
+	}
+	objects := []*unstructured.Unstructured{
+		newObject("Deployment", "capi-controller-manager"),
+		newObject("CustomResourceDefinition", "clusters.cluster.x-k8s.io"),
+		newObject("Service", "capi-webhook-service"),
+		newObject("Namespace", "capi-system"),
+		newObject("CustomResourceDefinition", "machines.cluster.x-k8s.io"),
+	}
+
+	sortForRestore(objects)
+
+	names := []string{}
+	for _, obj := range objects {
+		names = append(names, obj.GetName())
+	}
+	// The order of the objects of the same kind is preserved.
+	g.Expect(names).To(Equal([]string{
+		"capi-system",
+		"clusters.cluster.x-k8s.io",
+		"machines.cluster.x-k8s.io",
+		"capi-controller-manager",
+		"capi-webhook-service",
+	}))
+}
+
+func TestSnapshotObjectKey(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind("Deployment")
+	obj.SetNamespace("capi-system")
+	obj.SetName("capi-controller-manager")
+	g.Expect(snapshotObjectKey(obj)).To(Equal("Deployment.apps capi-system/capi-controller-manager"))
+
+	// The key does not depend on the version, so objects are matched across versions of their types.
+	obj.SetAPIVersion("apps/v1beta2")
+	g.Expect(snapshotObjectKey(obj)).To(Equal("Deployment.apps capi-system/capi-controller-manager"))
+
+	crd := &unstructured.Unstructured{}
+	crd.SetAPIVersion("apiextensions.k8s.io/v1")
+	crd.SetKind("CustomResourceDefinition")
+	crd.SetName("clusters.cluster.x-k8s.io")
+	g.Expect(snapshotObjectKey(crd)).To(Equal("CustomResourceDefinition.apiextensions.k8s.io clusters.cluster.x-k8s.io"))
+}
+
+func TestEtcdScripts(t *testing.T) {
+	g := NewWithT(t)
+
+	// The snapshot is moved out of the etcd data dir, which is replaced on restore.
+	snapshot := etcdSnapshotScript()
+	g.Expect(snapshot).To(HavePrefix("set -e\n"))
+	g.Expect(snapshot).To(ContainSubstring("snapshot save /var/lib/etcd/e2e-etcd-snapshot.db"))
+	g.Expect(snapshot).To(ContainSubstring("mv /var/lib/etcd/e2e-etcd-snapshot.db " + etcdSnapshotPath))
+
+	// The static Pods are stopped before the data dir is replaced, and started again afterwards.
+	restore := etcdRestoreScript()
+	g.Expect(restore).To(HavePrefix("set -e\n"))
+	stop := strings.Index(restore, "mv $manifests/etcd.yaml $manifests/kube-apiserver.yaml /etc/kubernetes/")
+	restoreSnapshot := strings.Index(restore, "etcdutl snapshot restore "+etcdSnapshotPath)
+	replace := strings.Index(restore, "mv /var/lib/etcd-restore/member /var/lib/etcd/member")
+	start := strings.Index(restore, "mv /etc/kubernetes/etcd.yaml /etc/kubernetes/kube-apiserver.yaml $manifests/")
+	g.Expect(stop).To(BeNumerically(">", 0))
+	g.Expect(restoreSnapshot).To(BeNumerically(">", stop))
+	g.Expect(replace).To(BeNumerically(">", restoreSnapshot))
+	g.Expect(start).To(BeNumerically(">", replace))
+}
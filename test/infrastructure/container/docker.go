@@ -56,6 +56,9 @@ const (
 
 type dockerRuntime struct {
 	dockerClient *client.Client
+
+	// podman is true if the Docker compatible API is served by Podman.
+	podman bool
 }
 
 // NewDockerClient gets a client for interacting with a Docker container runtime.
@@ -137,6 +140,11 @@ func (d *dockerRuntime) PullContainerImage(ctx context.Context, image string) er
 func (d *dockerRuntime) ImageExistsLocally(ctx context.Context, image string) (bool, error) {
 	filters := dockerfilters.NewArgs()
 	filters.Add("reference", image)
+	if d.podman {
+		for _, name := range podmanImageNames(image)[1:] {
+			filters.Add("reference", name)
+		}
+	}
 	images, err := d.dockerClient.ImageList(ctx, types.ImageListOptions{
 		Filters: filters,
 	})
@@ -166,7 +174,7 @@ func (d *dockerRuntime) GetHostPort(ctx context.Context, containerName, portAndP
 		}
 	}
 
-	return "", fmt.Errorf("no host port found for load balancer %q", containerName)
+	return "", fmt.Errorf("no host port found for %s in container %q", portAndProtocol, containerName)
 }
 
 // ExecContainer executes a command in a running container and writes any output to the provided writer.
@@ -311,6 +319,45 @@ func (d *dockerRuntime) KillContainer(ctx context.Context, containerName, signal
 	return d.dockerClient.ContainerKill(ctx, containerName, signal)
 }
 
+// CreateNetwork creates a bridge network; if a network with the same name already exists, it is not changed.
+func (d *dockerRuntime) CreateNetwork(ctx context.Context, input *CreateNetworkInput) error {
+	filters := dockerfilters.NewArgs()
+	filters.Add("name", fmt.Sprintf("^%s$", input.Name))
+	networks, err := d.dockerClient.NetworkList(ctx, types.NetworkListOptions{Filters: filters})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list networks")
+	}
+	if len(networks) > 0 {
+		return nil
+	}
+
+	ipam := &network.IPAM{}
+	for _, subnet := range input.Subnets {
+		ipam.Config = append(ipam.Config, network.IPAMConfig{Subnet: subnet})
+	}
+	if _, err := d.dockerClient.NetworkCreate(ctx, input.Name, types.NetworkCreate{
+		CheckDuplicate: true,
+		Driver:         "bridge",
+		EnableIPv6:     input.EnableIPv6,
+		IPAM:           ipam,
+		Options: map[string]string{
+			"com.docker.network.bridge.enable_ip_masquerade": "true",
+		},
+		Labels: input.Labels,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create network %q", input.Name)
+	}
+	return nil
+}
+
+// DeleteNetwork deletes a network; it is not an error if the network does not exist.
+func (d *dockerRuntime) DeleteNetwork(ctx context.Context, name string) error {
+	if err := d.dockerClient.NetworkRemove(ctx, name); err != nil && !client.IsErrNotFound(err) {
+		return errors.Wrapf(err, "failed to delete network %q", name)
+	}
+	return nil
+}
+
 // GetContainerIPs inspects a container to get its IPv4 and IPv6 IP addresses.
 // Will not error if there is no IP address assigned. Calling code will need to
 // determine whether that is an issue or not.
@@ -320,6 +367,10 @@ func (d *dockerRuntime) GetContainerIPs(ctx context.Context, containerName strin
 		return "", "", errors.Wrap(err, "failed to get container details")
 	}
 
+	// Prefer the network the container has been created with, if the container is connected to more than one network.
+	if net, ok := containerInfo.NetworkSettings.Networks[string(containerInfo.HostConfig.NetworkMode)]; ok {
+		return net.IPAddress, net.GlobalIPv6Address, nil
+	}
 	for _, net := range containerInfo.NetworkSettings.Networks {
 		return net.IPAddress, net.GlobalIPv6Address, nil
 	}
@@ -327,6 +378,20 @@ func (d *dockerRuntime) GetContainerIPs(ctx context.Context, containerName strin
 	return "", "", nil
 }
 
+// GetContainerNetworkIPs inspects a container to get its IPv4 and IPv6 IP addresses in a network.
+// Will not error if the container is not connected to the network or there is no IP address assigned.
+func (d *dockerRuntime) GetContainerNetworkIPs(ctx context.Context, containerName, network string) (string, string, error) {
+	containerInfo, err := d.dockerClient.ContainerInspect(ctx, containerName)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to get container details")
+	}
+
+	if net, ok := containerInfo.NetworkSettings.Networks[network]; ok {
+		return net.IPAddress, net.GlobalIPv6Address, nil
+	}
+	return "", "", nil
+}
+
 // ContainerDebugInfo gets the container metadata and logs from the runtime (docker inspect, docker logs).
 func (d *dockerRuntime) ContainerDebugInfo(ctx context.Context, containerName string, w io.Writer) error {
 	containerInfo, err := d.dockerClient.ContainerInspect(ctx, containerName)
@@ -360,6 +425,25 @@ func (d *dockerRuntime) ContainerDebugInfo(ctx context.Context, containerName st
 	return nil
 }
 
+// ContainerLogs writes the logs of a container (docker logs) to w.
+// NOTE: Containers are created with a tty, so stdout and stderr are not multiplexed.
+func (d *dockerRuntime) ContainerLogs(ctx context.Context, containerName string, w io.Writer) error {
+	options := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	}
+	responseBody, err := d.dockerClient.ContainerLogs(ctx, containerName, options)
+	if err != nil {
+		return errors.Wrapf(err, "error getting container logs for %q", containerName)
+	}
+	defer responseBody.Close()
+
+	if _, err := io.Copy(w, responseBody); err != nil {
+		return errors.Wrapf(err, "error reading logs from container %q", containerName)
+	}
+	return nil
+}
+
 // dockerContainerToContainer converts a Docker API container instance to our local
 // generic container type.
 func dockerContainerToContainer(container *types.Container) Container {
@@ -367,7 +451,50 @@ func dockerContainerToContainer(container *types.Container) Container {
 		Name:   strings.Trim(container.Names[0], "/"),
 		Image:  container.Image,
 		Status: container.Status,
+		Labels: container.Labels,
+	}
+}
+
+// GetContainerState returns the state of a container.
+func (d *dockerRuntime) GetContainerState(ctx context.Context, containerName string) (*ContainerState, error) {
+	containerInfo, err := d.dockerClient.ContainerInspect(ctx, containerName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to inspect container %q", containerName)
+	}
+	if containerInfo.ContainerJSONBase == nil || containerInfo.State == nil {
+		return nil, errors.Errorf("no state reported for container %q", containerName)
+	}
+
+	return &ContainerState{
+		Status:       containerInfo.State.Status,
+		Running:      containerInfo.State.Running,
+		Restarting:   containerInfo.State.Restarting,
+		OOMKilled:    containerInfo.State.OOMKilled,
+		ExitCode:     containerInfo.State.ExitCode,
+		Error:        containerInfo.State.Error,
+		RestartCount: containerInfo.RestartCount,
+	}, nil
+}
+
+// GetHostInfo returns information about the host the container runtime is running on.
+func (d *dockerRuntime) GetHostInfo(ctx context.Context) (*HostInfo, error) {
+	info, err := d.dockerClient.Info(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get container runtime info")
 	}
+
+	cgroupVersion := info.CgroupVersion
+	if cgroupVersion == "" {
+		// Docker engines older than 20.10 do not report the cgroup version, and they only support cgroup v1.
+		cgroupVersion = "1"
+	}
+	return &HostInfo{
+		Rootless:      d.rootless(info),
+		CgroupVersion: cgroupVersion,
+		MemoryLimit:   info.MemoryLimit,
+		PidsLimit:     info.PidsLimit,
+		CPUShares:     info.CPUShares,
+	}, nil
 }
 
 // RunContainer will run a docker container with the given settings and arguments, returning any errors.
@@ -407,11 +534,24 @@ func (d *dockerRuntime) RunContainer(ctx context.Context, runConfig *RunContaine
 		PortBindings:  nat.PortMap{},
 		RestartPolicy: dockercontainer.RestartPolicy{Name: restartPolicy, MaximumRetryCount: restartMaximumRetryCount},
 		Init:          pointer.Bool(false),
+		Resources: dockercontainer.Resources{
+			NanoCPUs: runConfig.NanoCPUs,
+			Memory:   runConfig.Memory,
+		},
 	}
 	networkConfig := network.NetworkingConfig{}
 
+	info, err := d.dockerClient.Info(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get Docker engine info, failed to create container %q", runConfig.Name)
+	}
+
 	// NOTE: starting from Kind 0.20 kind requires CgroupnsMode to be set to private.
-	if runConfig.KindMode != kind.ModeNone && runConfig.KindMode != kind.Mode0_19 {
+	// NOTE: Kind always sets CgroupnsMode to private when using Podman.
+	// NOTE: on cgroup v2 only hosts (e.g. with rootless Docker) the node must get its own cgroup namespace, otherwise
+	// the kubelet inside the container can't manage the cgroup hierarchy delegated to it; this is also the
+	// default for Docker on cgroup v2, but we make it explicit for engines with a different default.
+	if runConfig.KindMode != kind.ModeNone && (runConfig.KindMode != kind.Mode0_19 || d.podman || info.CgroupVersion == "2") {
 		hostConfig.CgroupnsMode = "private"
 	}
 
@@ -422,11 +562,6 @@ func (d *dockerRuntime) RunContainer(ctx context.Context, runConfig *RunContaine
 		}
 	}
 
-	info, err := d.dockerClient.Info(ctx)
-	if err != nil {
-		return errors.Wrapf(err, "unable to get Docker engine info, failed to create container %q", runConfig.Name)
-	}
-
 	// mount /dev/mapper if docker storage driver if Btrfs or ZFS
 	// https://github.com/kubernetes-sigs/kind/pull/1464
 	if d.needsDevMapper(info) {
@@ -465,7 +600,8 @@ func (d *dockerRuntime) RunContainer(ctx context.Context, runConfig *RunContaine
 
 	// enable /dev/fuse explicitly for fuse-overlayfs
 	// (Rootless Docker does not automatically mount /dev/fuse with --privileged)
-	if d.mountFuse(info) {
+	// NOTE: Kind always mounts /dev/fuse when using Podman.
+	if d.mountFuse(info) || d.podman {
 		hostConfig.Devices = append(hostConfig.Devices, dockercontainer.DeviceMapping{PathOnHost: "/dev/fuse"})
 	}
 
@@ -487,6 +623,16 @@ func (d *dockerRuntime) RunContainer(ctx context.Context, runConfig *RunContaine
 		return errors.Wrapf(err, "error creating container %q", runConfig.Name)
 	}
 
+	for _, networkName := range runConfig.AdditionalNetworks {
+		if err := d.dockerClient.NetworkConnect(ctx, networkName, resp.ID, nil); err != nil {
+			err := errors.Wrapf(err, "error connecting container %q to network %q", runConfig.Name, networkName)
+			if reterr := d.dockerClient.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true}); reterr != nil {
+				return kerrors.NewAggregate([]error{err, errors.Wrapf(reterr, "error deleting container")})
+			}
+			return err
+		}
+	}
+
 	var containerOutput types.HijackedResponse
 	if output != nil {
 		// Read out any output from the container
@@ -717,6 +863,11 @@ func (d *dockerRuntime) mountDevMapper(info types.Info) bool {
 // rootless: use fuse-overlayfs by default
 // https://github.com/kubernetes-sigs/kind/issues/2275
 func (d *dockerRuntime) mountFuse(info types.Info) bool {
+	return d.rootless(info)
+}
+
+// rootless checks if the container runtime is running as a non-root user.
+func (d *dockerRuntime) rootless(info types.Info) bool {
 	for _, o := range info.SecurityOptions {
 		// o is like "name=seccomp,profile=default", or "name=rootless",
 		csvReader := csv.NewReader(strings.NewReader(o))
@@ -25,6 +25,8 @@ var runContainerCallLog []RunContainerArgs
 var deleteContainerCallLog []string
 var killContainerCallLog []KillContainerArgs
 var execContainerCallLog []ExecContainerArgs
+var hostInfo *HostInfo
+var containerStates = map[string]*ContainerState{}
 
 // RunContainerArgs contains the arguments passed to calls to RunContainer.
 type RunContainerArgs struct {
@@ -150,11 +152,31 @@ func (f *FakeRuntime) GetContainerIPs(_ context.Context, containerName string) (
 	return containerName + "IPv4", containerName + "IPv6", nil
 }
 
+// GetContainerNetworkIPs inspects a container to get its IPv4 and IPv6 IP addresses in a network.
+func (f *FakeRuntime) GetContainerNetworkIPs(_ context.Context, containerName, network string) (string, string, error) {
+	return containerName + network + "IPv4", containerName + network + "IPv6", nil
+}
+
 // ContainerDebugInfo gets the container metadata and logs from the runtime (docker inspect, docker logs).
 func (f *FakeRuntime) ContainerDebugInfo(_ context.Context, _ string, _ io.Writer) error {
 	return nil
 }
 
+// ContainerLogs gets the container logs from the runtime (docker logs).
+func (f *FakeRuntime) ContainerLogs(_ context.Context, _ string, _ io.Writer) error {
+	return nil
+}
+
+// CreateNetwork creates a network.
+func (f *FakeRuntime) CreateNetwork(_ context.Context, _ *CreateNetworkInput) error {
+	return nil
+}
+
+// DeleteNetwork deletes a network.
+func (f *FakeRuntime) DeleteNetwork(_ context.Context, _ string) error {
+	return nil
+}
+
 // RunContainer will run a docker container with the given settings and arguments, returning any errors.
 func (f *FakeRuntime) RunContainer(_ context.Context, runConfig *RunContainerInput, output io.Writer) error {
 	runContainerCallLog = append(runContainerCallLog, RunContainerArgs{runConfig, output})
@@ -170,3 +192,33 @@ func (f *FakeRuntime) RunContainerCalls() []RunContainerArgs {
 func (f *FakeRuntime) ResetRunContainerCallLogs() {
 	runContainerCallLog = []RunContainerArgs{}
 }
+
+// GetHostInfo returns the HostInfo set with SetHostInfo; if not set, it returns a rootful cgroup v2 host.
+func (f *FakeRuntime) GetHostInfo(_ context.Context) (*HostInfo, error) {
+	if hostInfo != nil {
+		return hostInfo, nil
+	}
+	return &HostInfo{CgroupVersion: "2", MemoryLimit: true, PidsLimit: true, CPUShares: true}, nil
+}
+
+// SetHostInfo sets the HostInfo returned by GetHostInfo; use nil to reset to the default.
+func (f *FakeRuntime) SetHostInfo(info *HostInfo) {
+	hostInfo = info
+}
+
+// GetContainerState returns the ContainerState set with SetContainerState; if not set, it returns a running container.
+func (f *FakeRuntime) GetContainerState(_ context.Context, containerName string) (*ContainerState, error) {
+	if state, ok := containerStates[containerName]; ok {
+		return state, nil
+	}
+	return &ContainerState{Status: "running", Running: true}, nil
+}
+
+// SetContainerState sets the ContainerState returned by GetContainerState for a container; use nil to reset to the default.
+func (f *FakeRuntime) SetContainerState(containerName string, state *ContainerState) {
+	if state == nil {
+		delete(containerStates, containerName)
+		return
+	}
+	containerStates[containerName] = state
+}
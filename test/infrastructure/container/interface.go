@@ -18,8 +18,11 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/kind"
@@ -28,6 +31,17 @@ import (
 // providerKey is the key type for accessing the runtime provider in passed contexts.
 type providerKey struct{}
 
+const (
+	// RuntimeEnvVar is the environment variable used for selecting the container runtime.
+	RuntimeEnvVar = "CAPD_CONTAINER_RUNTIME"
+
+	// DockerRuntime is the name of the Docker container runtime; this is the default container runtime.
+	DockerRuntime = "docker"
+
+	// PodmanRuntime is the name of the Podman container runtime.
+	PodmanRuntime = "podman"
+)
+
 // Runtime defines the interface for interacting with a container runtime.
 type Runtime interface {
 	SaveContainerImage(ctx context.Context, image, dest string) error
@@ -36,12 +50,18 @@ type Runtime interface {
 	ImageExistsLocally(ctx context.Context, image string) (bool, error)
 	GetHostPort(ctx context.Context, containerName, portAndProtocol string) (string, error)
 	GetContainerIPs(ctx context.Context, containerName string) (string, string, error)
+	GetContainerNetworkIPs(ctx context.Context, containerName, network string) (string, string, error)
 	ExecContainer(ctx context.Context, containerName string, config *ExecContainerInput, command string, args ...string) error
 	RunContainer(ctx context.Context, runConfig *RunContainerInput, output io.Writer) error
 	ListContainers(ctx context.Context, filters FilterBuilder) ([]Container, error)
 	ContainerDebugInfo(ctx context.Context, containerName string, w io.Writer) error
+	ContainerLogs(ctx context.Context, containerName string, w io.Writer) error
 	DeleteContainer(ctx context.Context, containerName string) error
 	KillContainer(ctx context.Context, containerName, signal string) error
+	CreateNetwork(ctx context.Context, input *CreateNetworkInput) error
+	DeleteNetwork(ctx context.Context, name string) error
+	GetHostInfo(ctx context.Context) (*HostInfo, error)
+	GetContainerState(ctx context.Context, containerName string) (*ContainerState, error)
 }
 
 // Mount contains mount details.
@@ -74,6 +94,8 @@ type RunContainerInput struct {
 	Name string
 	// Network is the name of the network to connect to.
 	Network string
+	// AdditionalNetworks are the names of other networks to connect to, after Network.
+	AdditionalNetworks []string
 	// User is the user name to run as.
 	User string
 	// Group is the user group to run as.
@@ -92,6 +114,12 @@ type RunContainerInput struct {
 	Entrypoint []string
 	// Labels to apply to the container.
 	Labels map[string]string
+	// NanoCPUs is the CPU limit for the container, in units of 10^-9 CPUs.
+	// If not set, the container can use all the CPUs of the host.
+	NanoCPUs int64
+	// Memory is the memory limit for the container, in bytes.
+	// If not set, the container can use all the memory of the host.
+	Memory int64
 	// PortMappings contains host<>container ports to map.
 	PortMappings []PortMapping
 	// IPFamily is the IP version to use.
@@ -103,6 +131,19 @@ type RunContainerInput struct {
 	KindMode kind.Mode
 }
 
+// CreateNetworkInput holds the configuration settings for creating a network.
+type CreateNetworkInput struct {
+	// Name is the name of the network.
+	Name string
+	// Subnets are the subnets of the network, in CIDR notation.
+	// If not set, the subnets are allocated by the container runtime.
+	Subnets []string
+	// EnableIPv6 enables IPv6 on the network.
+	EnableIPv6 bool
+	// Labels to apply to the network.
+	Labels map[string]string
+}
+
 // ExecContainerInput contains values for running exec on a container.
 type ExecContainerInput struct {
 	// OutputBuffer receives the stdout of the execution.
@@ -139,6 +180,71 @@ type Container struct {
 	Image string
 	// Status is the status of the container
 	Status string
+	// Labels are the labels of the container
+	Labels map[string]string
+}
+
+// ContainerState describes the state of a container.
+type ContainerState struct {
+	// Status is the status of the container, e.g. "running", "restarting" or "exited".
+	Status string
+	// Running is true if the container is running.
+	Running bool
+	// Restarting is true if the container is being restarted by the container runtime.
+	Restarting bool
+	// OOMKilled is true if the last run of the container has been killed because it ran out of memory.
+	OOMKilled bool
+	// ExitCode is the exit code of the last run of the container.
+	ExitCode int
+	// Error is the error reported by the container runtime for the last run of the container, if any.
+	Error string
+	// RestartCount is the number of times the container has been restarted by the container runtime.
+	RestartCount int
+}
+
+// HostInfo describes the host the container runtime is running on.
+type HostInfo struct {
+	// Rootless is true if the container runtime is running as a non-root user.
+	Rootless bool
+	// CgroupVersion is the cgroup version of the host, either "1" or "2".
+	CgroupVersion string
+	// MemoryLimit is true if the memory cgroup controller is available to the container runtime.
+	MemoryLimit bool
+	// PidsLimit is true if the pids cgroup controller is available to the container runtime.
+	PidsLimit bool
+	// CPUShares is true if the cpu cgroup controller is available to the container runtime.
+	CPUShares bool
+}
+
+// CgroupV2 returns true if the host uses the unified cgroup v2 hierarchy.
+func (h *HostInfo) CgroupV2() bool {
+	return h.CgroupVersion == "2"
+}
+
+// ValidateNestedKubelet returns an error explaining how to fix the host setup if it
+// can't support running a kubelet inside a container.
+// NOTE: the checks are the same performed by kind, see https://kind.sigs.k8s.io/docs/user/rootless/.
+func (h *HostInfo) ValidateNestedKubelet() error {
+	if !h.Rootless {
+		return nil
+	}
+	if !h.CgroupV2() {
+		return errors.New("running nodes with a rootless container runtime requires cgroup v2; enable the unified cgroup hierarchy on the host, e.g. by booting with systemd.unified_cgroup_hierarchy=1")
+	}
+	missing := []string{}
+	if !h.CPUShares {
+		missing = append(missing, "cpu")
+	}
+	if !h.MemoryLimit {
+		missing = append(missing, "memory")
+	}
+	if !h.PidsLimit {
+		missing = append(missing, "pids")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("running nodes with a rootless container runtime requires the %s cgroup controllers to be delegated to the user running the container runtime; set the systemd property \"Delegate=yes\" for the user@.service unit and restart the container runtime", strings.Join(missing, ", "))
+	}
+	return nil
 }
 
 // RuntimeFrom is used to extract the container runtime client from a
@@ -155,3 +261,38 @@ func RuntimeFrom(ctx context.Context) (Runtime, error) {
 func RuntimeInto(ctx context.Context, runtime Runtime) context.Context {
 	return context.WithValue(ctx, providerKey{}, runtime)
 }
+
+// NewRuntimeClient gets a client for interacting with the container runtime with the given name.
+// If name is empty, the container runtime is selected using the RuntimeEnvVar environment variable,
+// defaulting to Docker.
+func NewRuntimeClient(name string) (Runtime, error) {
+	if name == "" {
+		name = RuntimeName()
+	}
+	switch name {
+	case DockerRuntime:
+		return NewDockerClient()
+	case PodmanRuntime:
+		return NewPodmanClient()
+	default:
+		return nil, fmt.Errorf("unsupported container runtime %q, supported values are %q and %q", name, DockerRuntime, PodmanRuntime)
+	}
+}
+
+// RuntimeName returns the name of the container runtime selected using the RuntimeEnvVar environment variable,
+// defaulting to Docker.
+func RuntimeName() string {
+	if name := os.Getenv(RuntimeEnvVar); name != "" {
+		return name
+	}
+	return DockerRuntime
+}
+
+// RuntimeSocket returns the path of the socket of the container runtime selected using the RuntimeEnvVar
+// environment variable.
+func RuntimeSocket() string {
+	if RuntimeName() == PodmanRuntime {
+		return PodmanSocket()
+	}
+	return dockerSocket
+}
@@ -70,3 +70,42 @@ func TestInvalidContext(t *testing.T) {
 	_, err := RuntimeFrom(context.Background())
 	g.Expect(err).Should(HaveOccurred())
 }
+
+func TestHostInfoValidateNestedKubelet(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    HostInfo
+		wantErr string
+	}{
+		{
+			name: "rootful cgroup v1",
+			info: HostInfo{CgroupVersion: "1"},
+		},
+		{
+			name: "rootless cgroup v2 with delegated controllers",
+			info: HostInfo{Rootless: true, CgroupVersion: "2", CPUShares: true, MemoryLimit: true, PidsLimit: true},
+		},
+		{
+			name:    "rootless cgroup v1",
+			info:    HostInfo{Rootless: true, CgroupVersion: "1", CPUShares: true, MemoryLimit: true, PidsLimit: true},
+			wantErr: "requires cgroup v2",
+		},
+		{
+			name:    "rootless cgroup v2 without delegated controllers",
+			info:    HostInfo{Rootless: true, CgroupVersion: "2", MemoryLimit: true},
+			wantErr: "requires the cpu, pids cgroup controllers to be delegated",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tt.info.ValidateNestedKubelet()
+			if tt.wantErr == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
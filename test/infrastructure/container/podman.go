@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+const (
+	// podmanHostEnvVar is the environment variable used by the Podman CLI for the address of the Podman API service.
+	podmanHostEnvVar = "CONTAINER_HOST"
+
+	// podmanRootfulSocket is the default socket of the Podman API service when running as root.
+	podmanRootfulSocket = "/run/podman/podman.sock"
+
+	// podmanRootlessSocket is the default socket of the Podman API service when running rootless,
+	// relative to XDG_RUNTIME_DIR.
+	podmanRootlessSocket = "podman/podman.sock"
+
+	// dockerSocket is the default socket of the Docker engine; this is also the path where the podman-docker
+	// package exposes the Podman API service, and where the socket is mounted into the CAPD pod.
+	dockerSocket = "/var/run/docker.sock"
+)
+
+// NewPodmanClient gets a client for interacting with a Podman container runtime, either rootful or rootless.
+// NOTE: Podman is accessed using its Docker compatible API, so the Podman API service must be running,
+// e.g. by using `systemctl enable --now podman.socket` (add `--user` for rootless Podman).
+func NewPodmanClient() (Runtime, error) {
+	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithHost(podmanHost()), client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to created podman runtime client")
+	}
+	return &dockerRuntime{
+		dockerClient: dockerClient,
+		podman:       true,
+	}, nil
+}
+
+// podmanHost returns the address of the Podman API service.
+func podmanHost() string {
+	if host := os.Getenv(podmanHostEnvVar); host != "" {
+		return host
+	}
+	if host := os.Getenv(client.EnvOverrideHost); host != "" {
+		return host
+	}
+	return "unix://" + PodmanSocket()
+}
+
+// PodmanSocket returns the path of the socket of the Podman API service for the current user;
+// if the socket does not exist, the Docker socket is returned.
+func PodmanSocket() string {
+	socket := podmanRootfulSocket
+	if os.Geteuid() != 0 {
+		runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+		if runtimeDir == "" {
+			runtimeDir = fmt.Sprintf("/run/user/%d", os.Geteuid())
+		}
+		socket = filepath.Join(runtimeDir, podmanRootlessSocket)
+	}
+	if _, err := os.Stat(socket); err != nil {
+		return dockerSocket
+	}
+	return socket
+}
+
+// podmanImageNames returns the names an image could be stored with by Podman.
+// NOTE: Podman stores images using fully qualified names, e.g. docker.io/kindest/node instead of kindest/node.
+func podmanImageNames(image string) []string {
+	firstComponent, _, hasPath := strings.Cut(image, "/")
+	switch {
+	case !hasPath:
+		return []string{image, "docker.io/library/" + image, "localhost/" + image}
+	case strings.ContainsAny(firstComponent, ".:") || firstComponent == "localhost":
+		// The image name already includes the registry.
+		return []string{image}
+	default:
+		return []string{image, "docker.io/" + image, "localhost/" + image}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPodmanImageNames(t *testing.T) {
+	tests := []struct {
+		image string
+		want  []string
+	}{
+		{
+			image: "busybox:latest",
+			want:  []string{"busybox:latest", "docker.io/library/busybox:latest", "localhost/busybox:latest"},
+		},
+		{
+			image: "kindest/node:v1.28.0",
+			want:  []string{"kindest/node:v1.28.0", "docker.io/kindest/node:v1.28.0", "localhost/kindest/node:v1.28.0"},
+		},
+		{
+			image: "gcr.io/k8s-staging-cluster-api/capd-manager:dev",
+			want:  []string{"gcr.io/k8s-staging-cluster-api/capd-manager:dev"},
+		},
+		{
+			image: "localhost/capd-manager:dev",
+			want:  []string{"localhost/capd-manager:dev"},
+		},
+		{
+			image: "registry:5000/capd-manager:dev",
+			want:  []string{"registry:5000/capd-manager:dev"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(podmanImageNames(tt.image)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNewRuntimeClient(t *testing.T) {
+	t.Run("defaults to Docker", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv(RuntimeEnvVar, "")
+
+		g.Expect(RuntimeName()).To(Equal(DockerRuntime))
+		rtc, err := NewRuntimeClient("")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(rtc.(*dockerRuntime).podman).To(BeFalse())
+	})
+
+	t.Run("selects Podman using the environment variable", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv(RuntimeEnvVar, PodmanRuntime)
+
+		rtc, err := NewRuntimeClient("")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(rtc.(*dockerRuntime).podman).To(BeTrue())
+	})
+
+	t.Run("uses the given name instead of the environment variable", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv(RuntimeEnvVar, PodmanRuntime)
+
+		rtc, err := NewRuntimeClient(DockerRuntime)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(rtc.(*dockerRuntime).podman).To(BeFalse())
+	})
+
+	t.Run("fails for unsupported container runtimes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := NewRuntimeClient("containerd")
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -40,6 +40,11 @@ func (src *DockerCluster) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.LoadBalancer.CustomHAProxyConfigTemplateRef != nil {
 		dst.Spec.LoadBalancer.CustomHAProxyConfigTemplateRef = restored.Spec.LoadBalancer.CustomHAProxyConfigTemplateRef
 	}
+	dst.Spec.LoadBalancer.Type = restored.Spec.LoadBalancer.Type
+	dst.Spec.LoadBalancer.Settings = restored.Spec.LoadBalancer.Settings
+	dst.Spec.PreLoadImages = restored.Spec.PreLoadImages
+	dst.Spec.RegistryMirrors = restored.Spec.RegistryMirrors
+	dst.Spec.Network = restored.Spec.Network
 
 	return nil
 }
@@ -88,6 +93,11 @@ func (src *DockerClusterTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.Template.Spec.LoadBalancer.CustomHAProxyConfigTemplateRef != nil {
 		dst.Spec.Template.Spec.LoadBalancer.CustomHAProxyConfigTemplateRef = restored.Spec.Template.Spec.LoadBalancer.CustomHAProxyConfigTemplateRef
 	}
+	dst.Spec.Template.Spec.LoadBalancer.Type = restored.Spec.Template.Spec.LoadBalancer.Type
+	dst.Spec.Template.Spec.LoadBalancer.Settings = restored.Spec.Template.Spec.LoadBalancer.Settings
+	dst.Spec.Template.Spec.PreLoadImages = restored.Spec.Template.Spec.PreLoadImages
+	dst.Spec.Template.Spec.RegistryMirrors = restored.Spec.Template.Spec.RegistryMirrors
+	dst.Spec.Template.Spec.Network = restored.Spec.Template.Spec.Network
 
 	return nil
 }
@@ -122,13 +132,41 @@ func (dst *DockerClusterTemplateList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *DockerMachine) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*infrav1.DockerMachine)
 
-	return Convert_v1alpha4_DockerMachine_To_v1beta1_DockerMachine(src, dst, nil)
+	if err := Convert_v1alpha4_DockerMachine_To_v1beta1_DockerMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &infrav1.DockerMachine{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.InstanceName = restored.Spec.InstanceName
+	dst.Spec.Resources = restored.Spec.Resources
+	dst.Spec.SSH = restored.Spec.SSH
+	dst.Status.ConsoleLogsURL = restored.Status.ConsoleLogsURL
+	dst.Status.SSH = restored.Status.SSH
+	dst.Status.ContainerRestartCount = restored.Status.ContainerRestartCount
+	dst.Status.FailureReason = restored.Status.FailureReason
+	dst.Status.FailureMessage = restored.Status.FailureMessage
+
+	return nil
 }
 
 func (dst *DockerMachine) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*infrav1.DockerMachine)
 
-	return Convert_v1beta1_DockerMachine_To_v1alpha4_DockerMachine(src, dst, nil)
+	if err := Convert_v1beta1_DockerMachine_To_v1alpha4_DockerMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *DockerMachineList) ConvertTo(dstRaw conversion.Hub) error {
@@ -157,6 +195,10 @@ func (src *DockerMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
+	dst.Spec.Template.Spec.InstanceName = restored.Spec.Template.Spec.InstanceName
+	dst.Spec.Template.Spec.Resources = restored.Spec.Template.Spec.Resources
+	dst.Spec.Template.Spec.SSH = restored.Spec.Template.Spec.SSH
+	dst.Status = restored.Status
 
 	return nil
 }
@@ -188,6 +230,11 @@ func (dst *DockerMachineTemplateList) ConvertFrom(srcRaw conversion.Hub) error {
 	return Convert_v1beta1_DockerMachineTemplateList_To_v1alpha4_DockerMachineTemplateList(src, dst, nil)
 }
 
+func Convert_v1beta1_DockerClusterSpec_To_v1alpha4_DockerClusterSpec(in *infrav1.DockerClusterSpec, out *DockerClusterSpec, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because spec.preLoadImages, spec.registryMirrors and spec.network have been added in v1beta1.
+	return autoConvert_v1beta1_DockerClusterSpec_To_v1alpha4_DockerClusterSpec(in, out, s)
+}
+
 func Convert_v1beta1_DockerClusterTemplateResource_To_v1alpha4_DockerClusterTemplateResource(in *infrav1.DockerClusterTemplateResource, out *DockerClusterTemplateResource, s apiconversion.Scope) error {
 	// NOTE: custom conversion func is required because spec.template.metadata has been added in v1beta1.
 	return autoConvert_v1beta1_DockerClusterTemplateResource_To_v1alpha4_DockerClusterTemplateResource(in, out, s)
@@ -198,6 +245,21 @@ func Convert_v1beta1_DockerMachineTemplateResource_To_v1alpha4_DockerMachineTemp
 	return autoConvert_v1beta1_DockerMachineTemplateResource_To_v1alpha4_DockerMachineTemplateResource(in, out, s)
 }
 
+func Convert_v1beta1_DockerMachineTemplate_To_v1alpha4_DockerMachineTemplate(in *infrav1.DockerMachineTemplate, out *DockerMachineTemplate, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because status has been added in v1beta1.
+	return autoConvert_v1beta1_DockerMachineTemplate_To_v1alpha4_DockerMachineTemplate(in, out, s)
+}
+
+func Convert_v1beta1_DockerMachineSpec_To_v1alpha4_DockerMachineSpec(in *infrav1.DockerMachineSpec, out *DockerMachineSpec, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because spec.instanceName and spec.resources have been added in v1beta1.
+	return autoConvert_v1beta1_DockerMachineSpec_To_v1alpha4_DockerMachineSpec(in, out, s)
+}
+
+func Convert_v1beta1_DockerMachineStatus_To_v1alpha4_DockerMachineStatus(in *infrav1.DockerMachineStatus, out *DockerMachineStatus, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because status.consoleLogsURL has been added in v1beta1.
+	return autoConvert_v1beta1_DockerMachineStatus_To_v1alpha4_DockerMachineStatus(in, out, s)
+}
+
 func Convert_v1beta1_DockerLoadBalancer_To_v1alpha4_DockerLoadBalancer(in *infrav1.DockerLoadBalancer, out *DockerLoadBalancer, s apiconversion.Scope) error {
 	return autoConvert_v1beta1_DockerLoadBalancer_To_v1alpha4_DockerLoadBalancer(in, out, s)
 }
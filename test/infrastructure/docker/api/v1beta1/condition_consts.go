@@ -46,6 +46,29 @@ const (
 	// ContainerDeletedReason (Severity=Error) documents a DockerMachine controller detecting
 	// the underlying container has been deleted unexpectedly.
 	ContainerDeletedReason = "ContainerDeleted"
+
+	// HostNotSupportedReason (Severity=Error) documents a DockerMachine controller detecting that the host
+	// of the container runtime can't run nested kubelets, e.g. rootless Docker on a cgroup v1 host or without
+	// the required cgroup controllers delegated; the condition message explains how to fix the host setup.
+	HostNotSupportedReason = "HostNotSupported"
+)
+
+const (
+	// ContainerHealthyCondition documents the state of the container backing a provisioned DockerMachine, as reported
+	// by the container runtime.
+	ContainerHealthyCondition clusterv1.ConditionType = "ContainerHealthy"
+
+	// ContainerRestartingReason (Severity=Warning) documents the container backing a DockerMachine being restarted
+	// by the container runtime after it stopped unexpectedly.
+	ContainerRestartingReason = "ContainerRestarting"
+
+	// ContainerOOMKilledReason (Severity=Error) documents the container backing a DockerMachine being killed because
+	// it ran out of memory, e.g. because of the memory limit set in DockerMachine.Spec.Resources.
+	ContainerOOMKilledReason = "ContainerOOMKilled"
+
+	// ContainerExitedReason (Severity=Error) documents the container backing a DockerMachine being stopped
+	// unexpectedly, e.g. because it crashed or because it has been stopped outside of CAPD.
+	ContainerExitedReason = "ContainerExited"
 )
 
 const (
@@ -48,13 +48,82 @@ type DockerClusterSpec struct {
 	// LoadBalancer allows defining configurations for the cluster load balancer.
 	// +optional
 	LoadBalancer DockerLoadBalancer `json:"loadBalancer,omitempty"`
+
+	// PreLoadImages allows to pre-load images in all the machines of the cluster, in addition to the images
+	// defined in the DockerMachine or DockerMachinePool spec. Images are pulled once on the host, if not
+	// already present, and then loaded into each machine, so they are not pulled again by each node.
+	// +optional
+	PreLoadImages []string `json:"preLoadImages,omitempty"`
+
+	// RegistryMirrors allows configuring registry mirrors, e.g. a local pull-through cache, for the
+	// container runtime of all the machines of the cluster.
+	// +optional
+	RegistryMirrors []RegistryMirror `json:"registryMirrors,omitempty"`
+
+	// Network allows creating a dedicated docker network for the cluster, instead of using the
+	// network shared with all the other clusters (the kind network).
+	// +optional
+	Network *DockerClusterNetwork `json:"network,omitempty"`
 }
 
+// DockerClusterNetwork defines the dedicated docker network of a cluster.
+type DockerClusterNetwork struct {
+	// CIDRBlocks are the subnets of the network, e.g. 172.30.0.0/16; at most one IPv4 and one IPv6 subnet
+	// can be defined. If not set, the subnets are allocated by the container runtime.
+	// +optional
+	// +kubebuilder:validation:MaxItems=2
+	CIDRBlocks []string `json:"cidrBlocks,omitempty"`
+
+	// Isolated disconnects the machines of the cluster from the network shared with the other clusters, so
+	// they can't reach machines in other clusters; if not set, the machines are connected to both networks.
+	// NOTE: The load balancer is always connected to the shared network, so the management cluster can reach the API server.
+	// +optional
+	Isolated bool `json:"isolated,omitempty"`
+}
+
+// RegistryMirror defines a mirror for a container image registry.
+type RegistryMirror struct {
+	// Registry is the name of the registry to be mirrored, e.g. docker.io.
+	Registry string `json:"registry"`
+
+	// Endpoints are the endpoints of the mirror, e.g. http://kind-registry:5000;
+	// endpoints are tried in order, falling back to the registry itself.
+	// +kubebuilder:validation:MinItems=1
+	Endpoints []string `json:"endpoints"`
+}
+
+// DockerLoadBalancerType defines the implementation of the cluster load balancer.
+// +kubebuilder:validation:Enum=haproxy;nginx;vip
+type DockerLoadBalancerType string
+
+const (
+	// HAProxyLoadBalancerType is a load balancer based on HAProxy; this is the default.
+	HAProxyLoadBalancerType DockerLoadBalancerType = "haproxy"
+
+	// NginxLoadBalancerType is a load balancer based on nginx; please note that nginx only supports
+	// passive health checks, so unavailable control plane nodes are detected only when a connection fails.
+	NginxLoadBalancerType DockerLoadBalancerType = "nginx"
+
+	// VIPLoadBalancerType simulates a kube-vip style virtual IP, where all the traffic is sent to a single
+	// control plane node at a time, failing over to another control plane node when it becomes unavailable.
+	VIPLoadBalancerType DockerLoadBalancerType = "vip"
+)
+
 // DockerLoadBalancer allows defining configurations for the cluster load balancer.
 type DockerLoadBalancer struct {
+	// Type is the implementation of the cluster load balancer.
+	// If not set, haproxy will be used. This field is immutable.
+	// +optional
+	Type DockerLoadBalancerType `json:"type,omitempty"`
+
 	// ImageMeta allows customizing the image used for the cluster load balancer.
 	ImageMeta `json:",inline"`
 
+	// Settings allows tuning timeouts and health checks of the cluster load balancer.
+	// Settings are not applied when using CustomHAProxyConfigTemplateRef, unless referenced by the custom template.
+	// +optional
+	Settings *DockerLoadBalancerSettings `json:"settings,omitempty"`
+
 	// CustomHAProxyConfigTemplateRef allows you to replace the default HAProxy config file.
 	// This field is a reference to a config map that contains the configuration template. The key of the config map should be equal to 'value'.
 	// The content of the config map will be processed and will replace the default HAProxy config file. Please use it with caution, as there are
@@ -67,6 +136,31 @@ type DockerLoadBalancer struct {
 	CustomHAProxyConfigTemplateRef *corev1.LocalObjectReference `json:"customHAProxyConfigTemplateRef,omitempty"`
 }
 
+// DockerLoadBalancerSettings allows tuning timeouts and health checks of the cluster load balancer.
+type DockerLoadBalancerSettings struct {
+	// ConnectTimeout is the maximum time to wait for a connection to a control plane node to succeed.
+	// If not set, 5s will be used.
+	// +optional
+	ConnectTimeout *metav1.Duration `json:"connectTimeout,omitempty"`
+
+	// ClientTimeout is the maximum inactivity time on the client side.
+	// If not set, 50s will be used.
+	// +optional
+	ClientTimeout *metav1.Duration `json:"clientTimeout,omitempty"`
+
+	// ServerTimeout is the maximum inactivity time on the control plane node side.
+	// If not set, 50s will be used.
+	// +optional
+	ServerTimeout *metav1.Duration `json:"serverTimeout,omitempty"`
+
+	// HealthCheckInterval is the interval between two consecutive health checks of a control plane node;
+	// for nginx, which only supports passive health checks, it is the time a control plane node is
+	// considered unavailable after a failed connection.
+	// If not set, 2s will be used.
+	// +optional
+	HealthCheckInterval *metav1.Duration `json:"healthCheckInterval,omitempty"`
+}
+
 // ImageMeta allows customizing the image used for components that are not
 // originated from the Kubernetes/Kubernetes release process.
 type ImageMeta struct {
@@ -17,9 +17,11 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 )
 
 const (
@@ -34,6 +36,12 @@ type DockerMachineSpec struct {
 	// +optional
 	ProviderID *string `json:"providerID,omitempty"`
 
+	// InstanceName is the name of the container backing the DockerMachine, when the container is not
+	// created by the DockerMachine controller, e.g. for the DockerMachines of a DockerMachinePool.
+	// If not set, the container name is derived from the name of the owner Machine.
+	// +optional
+	InstanceName string `json:"instanceName,omitempty"`
+
 	// CustomImage allows customizing the container image that is used for
 	// running the machine
 	// +optional
@@ -49,6 +57,18 @@ type DockerMachineSpec struct {
 	// +optional
 	ExtraMounts []Mount `json:"extraMounts,omitempty"`
 
+	// Resources allows to limit the CPU and memory available to the container backing the machine, e.g. to
+	// avoid a multi-node cluster starving the host.
+	// If not set, the container can use all the resources of the host.
+	// +optional
+	Resources *DockerMachineResources `json:"resources,omitempty"`
+
+	// SSH enables SSH access to the machine; when set, an SSH server is injected into the container backing the
+	// machine and the SSH port is published on the host, see DockerMachineStatus.SSH.
+	// NOTE: SSH access can only be enabled when the container is created, so this field is immutable.
+	// +optional
+	SSH *DockerMachineSSH `json:"ssh,omitempty"`
+
 	// Bootstrapped is true when the kubeadm bootstrapping has been run
 	// against this machine
 	//
@@ -74,6 +94,36 @@ type Mount struct {
 	Readonly bool `json:"readOnly,omitempty"`
 }
 
+// DockerMachineResources defines the resource limits of the container backing a DockerMachine.
+type DockerMachineResources struct {
+	// CPU is the number of CPUs the container can use, e.g. "2" or "1500m".
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory is the amount of memory the container can use, e.g. "4Gi".
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+}
+
+// DockerMachineSSH defines the SSH access to a DockerMachine.
+type DockerMachineSSH struct {
+	// AuthorizedKeys are the public keys allowed to log in to the machine as root.
+	// +kubebuilder:validation:MinItems=1
+	AuthorizedKeys []string `json:"authorizedKeys"`
+}
+
+// DockerMachineSSHEndpoint is the endpoint for SSH access to a DockerMachine.
+type DockerMachineSSHEndpoint struct {
+	// User is the user to log in to the machine as.
+	User string `json:"user"`
+
+	// Host is the address on the container runtime host where the SSH port of the machine is published.
+	Host string `json:"host"`
+
+	// Port is the port on the container runtime host where the SSH port of the machine is published.
+	Port int32 `json:"port"`
+}
+
 // DockerMachineStatus defines the observed state of DockerMachine.
 type DockerMachineStatus struct {
 	// Ready denotes that the machine (docker container) is ready
@@ -92,6 +142,38 @@ type DockerMachineStatus struct {
 	// Conditions defines current service state of the DockerMachine.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// ConsoleLogsURL is the URL where the console logs of the node container can be retrieved; logs are served
+	// by the CAPD controller manager on the diagnostics endpoint, so the same authorization rules of metrics apply.
+	// The kubelet logs can be retrieved by adding the `source=kubelet` query parameter to the URL.
+	// +optional
+	ConsoleLogsURL string `json:"consoleLogsURL,omitempty"`
+
+	// SSH is the endpoint for SSH access to the machine, e.g. `ssh -p <port> <user>@<host>`; it is set only if
+	// SSH access is enabled in the DockerMachine spec.
+	// +optional
+	SSH *DockerMachineSSHEndpoint `json:"ssh,omitempty"`
+
+	// ContainerRestartCount is the number of times the container backing the machine has been restarted by the
+	// container runtime, e.g. after a crash.
+	// +optional
+	ContainerRestartCount int32 `json:"containerRestartCount,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the DockerMachine and will contain a succinct value suitable
+	// for machine interpretation.
+	//
+	// CAPD sets this field when the container backing a provisioned machine is stopped unexpectedly, e.g.
+	// because it crashed or it has been killed because it ran out of memory, so MachineHealthChecks can
+	// remediate the machine.
+	// +optional
+	FailureReason *capierrors.MachineStatusError `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the DockerMachine and will contain a more verbose string suitable
+	// for logging and human consumption.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
 }
 
 // +kubebuilder:resource:path=dockermachines,scope=Namespaced,categories=cluster-api
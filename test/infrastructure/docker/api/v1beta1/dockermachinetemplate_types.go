@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -27,9 +28,18 @@ type DockerMachineTemplateSpec struct {
 	Template DockerMachineTemplateResource `json:"template"`
 }
 
+// DockerMachineTemplateStatus defines the observed state of DockerMachineTemplate.
+type DockerMachineTemplateStatus struct {
+	// Capacity defines the resource capacity of the machines created from this template, as derived from
+	// spec.template.spec.resources. This is used by the cluster autoscaler to scale node groups from zero.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=dockermachinetemplates,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
+// +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of DockerMachineTemplate"
 
 // DockerMachineTemplate is the Schema for the dockermachinetemplates API.
@@ -37,7 +47,8 @@ type DockerMachineTemplate struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec DockerMachineTemplateSpec `json:"spec,omitempty"`
+	Spec   DockerMachineTemplateSpec   `json:"spec,omitempty"`
+	Status DockerMachineTemplateStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -22,8 +22,10 @@ package v1beta1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/errors"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -100,6 +102,26 @@ func (in *DockerClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerClusterNetwork) DeepCopyInto(out *DockerClusterNetwork) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerClusterNetwork.
+func (in *DockerClusterNetwork) DeepCopy() *DockerClusterNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerClusterNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DockerClusterSpec) DeepCopyInto(out *DockerClusterSpec) {
 	*out = *in
@@ -112,6 +134,23 @@ func (in *DockerClusterSpec) DeepCopyInto(out *DockerClusterSpec) {
 		}
 	}
 	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
+	if in.PreLoadImages != nil {
+		in, out := &in.PreLoadImages, &out.PreLoadImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]RegistryMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(DockerClusterNetwork)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerClusterSpec.
@@ -248,6 +287,11 @@ func (in *DockerClusterTemplateSpec) DeepCopy() *DockerClusterTemplateSpec {
 func (in *DockerLoadBalancer) DeepCopyInto(out *DockerLoadBalancer) {
 	*out = *in
 	out.ImageMeta = in.ImageMeta
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(DockerLoadBalancerSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CustomHAProxyConfigTemplateRef != nil {
 		in, out := &in.CustomHAProxyConfigTemplateRef, &out.CustomHAProxyConfigTemplateRef
 		*out = new(v1.LocalObjectReference)
@@ -265,6 +309,41 @@ func (in *DockerLoadBalancer) DeepCopy() *DockerLoadBalancer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerLoadBalancerSettings) DeepCopyInto(out *DockerLoadBalancerSettings) {
+	*out = *in
+	if in.ConnectTimeout != nil {
+		in, out := &in.ConnectTimeout, &out.ConnectTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ClientTimeout != nil {
+		in, out := &in.ClientTimeout, &out.ClientTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ServerTimeout != nil {
+		in, out := &in.ServerTimeout, &out.ServerTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.HealthCheckInterval != nil {
+		in, out := &in.HealthCheckInterval, &out.HealthCheckInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerLoadBalancerSettings.
+func (in *DockerLoadBalancerSettings) DeepCopy() *DockerLoadBalancerSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerLoadBalancerSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DockerMachine) DeepCopyInto(out *DockerMachine) {
 	*out = *in
@@ -324,6 +403,66 @@ func (in *DockerMachineList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerMachineResources) DeepCopyInto(out *DockerMachineResources) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineResources.
+func (in *DockerMachineResources) DeepCopy() *DockerMachineResources {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerMachineResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerMachineSSH) DeepCopyInto(out *DockerMachineSSH) {
+	*out = *in
+	if in.AuthorizedKeys != nil {
+		in, out := &in.AuthorizedKeys, &out.AuthorizedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineSSH.
+func (in *DockerMachineSSH) DeepCopy() *DockerMachineSSH {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerMachineSSH)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerMachineSSHEndpoint) DeepCopyInto(out *DockerMachineSSHEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineSSHEndpoint.
+func (in *DockerMachineSSHEndpoint) DeepCopy() *DockerMachineSSHEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerMachineSSHEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DockerMachineSpec) DeepCopyInto(out *DockerMachineSpec) {
 	*out = *in
@@ -342,6 +481,16 @@ func (in *DockerMachineSpec) DeepCopyInto(out *DockerMachineSpec) {
 		*out = make([]Mount, len(*in))
 		copy(*out, *in)
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(DockerMachineResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(DockerMachineSSH)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineSpec.
@@ -369,6 +518,21 @@ func (in *DockerMachineStatus) DeepCopyInto(out *DockerMachineStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(DockerMachineSSHEndpoint)
+		**out = **in
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachineStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineStatus.
@@ -387,6 +551,7 @@ func (in *DockerMachineTemplate) DeepCopyInto(out *DockerMachineTemplate) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineTemplate.
@@ -472,6 +637,28 @@ func (in *DockerMachineTemplateSpec) DeepCopy() *DockerMachineTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerMachineTemplateStatus) DeepCopyInto(out *DockerMachineTemplateStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineTemplateStatus.
+func (in *DockerMachineTemplateStatus) DeepCopy() *DockerMachineTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerMachineTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageMeta) DeepCopyInto(out *ImageMeta) {
 	*out = *in
@@ -501,3 +688,23 @@ func (in *Mount) DeepCopy() *Mount {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
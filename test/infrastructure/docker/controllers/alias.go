@@ -19,6 +19,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,15 +41,23 @@ type DockerMachineReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// ConsoleLogsBaseURL is the base URL of the CAPD diagnostics endpoint serving the logs of the machines.
+	ConsoleLogsBaseURL string
+
+	// ContainerHealthCheckInterval is the interval at which the containers backing provisioned machines are checked.
+	ContainerHealthCheckInterval time.Duration
 }
 
 // SetupWithManager sets up the reconciler with the Manager.
 func (r *DockerMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&dockercontrollers.DockerMachineReconciler{
-		Client:           r.Client,
-		ContainerRuntime: r.ContainerRuntime,
-		Tracker:          r.Tracker,
-		WatchFilterValue: r.WatchFilterValue,
+		Client:                       r.Client,
+		ContainerRuntime:             r.ContainerRuntime,
+		Tracker:                      r.Tracker,
+		WatchFilterValue:             r.WatchFilterValue,
+		ConsoleLogsBaseURL:           r.ConsoleLogsBaseURL,
+		ContainerHealthCheckInterval: r.ContainerHealthCheckInterval,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -69,3 +78,19 @@ func (r *DockerClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 		WatchFilterValue: r.WatchFilterValue,
 	}).SetupWithManager(ctx, mgr, options)
 }
+
+// DockerMachineTemplateReconciler reconciles a DockerMachineTemplate object.
+type DockerMachineTemplateReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+// SetupWithManager sets up the reconciler with the Manager.
+func (r *DockerMachineTemplateReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return (&dockercontrollers.DockerMachineTemplateReconciler{
+		Client:           r.Client,
+		WatchFilterValue: r.WatchFilterValue,
+	}).SetupWithManager(ctx, mgr, options)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+	infraexpv1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/internal/docker"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// reconcileDockerMachines creates a DockerMachine for each instance of the DockerMachinePool, so the MachinePool
+// controller can surface them as MachinePool Machines, and deletes the DockerMachines whose instance does not exist anymore.
+//
+// NOTE: Deleting a MachinePool Machine deletes the corresponding DockerMachine, and this in turn deletes the instance;
+// the node pool then creates a new instance if required to satisfy the desired replica count.
+func (r *DockerMachinePoolReconciler) reconcileDockerMachines(ctx context.Context, cluster *clusterv1.Cluster, machinePool *expv1.MachinePool, dockerMachinePool *infraexpv1.DockerMachinePool) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	dockerMachineList := &infrav1.DockerMachineList{}
+	if err := r.Client.List(ctx, dockerMachineList, client.InNamespace(dockerMachinePool.Namespace), client.MatchingLabels(docker.MachinePoolMachineLabels(cluster, machinePool))); err != nil {
+		return errors.Wrapf(err, "failed to list DockerMachines for DockerMachinePool %s", klog.KObj(dockerMachinePool))
+	}
+	dockerMachines := make(map[string]*infrav1.DockerMachine, len(dockerMachineList.Items))
+	for i := range dockerMachineList.Items {
+		dockerMachine := &dockerMachineList.Items[i]
+		dockerMachines[dockerMachine.Spec.InstanceName] = dockerMachine
+	}
+
+	// Create a DockerMachine for each instance, or update it with the instance ProviderID once known.
+	instances := map[string]bool{}
+	for _, instance := range dockerMachinePool.Status.Instances {
+		instances[instance.InstanceName] = true
+
+		dockerMachine, ok := dockerMachines[instance.InstanceName]
+		if !ok {
+			dockerMachine = &infrav1.DockerMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      instance.InstanceName,
+					Namespace: dockerMachinePool.Namespace,
+					Labels:    docker.MachinePoolMachineLabels(cluster, machinePool),
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: infraexpv1.GroupVersion.String(),
+							Kind:       "DockerMachinePool",
+							Name:       dockerMachinePool.Name,
+							UID:        dockerMachinePool.UID,
+						},
+					},
+				},
+				Spec: infrav1.DockerMachineSpec{
+					InstanceName:  instance.InstanceName,
+					CustomImage:   dockerMachinePool.Spec.Template.CustomImage,
+					PreLoadImages: dockerMachinePool.Spec.Template.PreLoadImages,
+					ExtraMounts:   dockerMachinePool.Spec.Template.ExtraMounts,
+				},
+			}
+			log.Info("Creating DockerMachine", "DockerMachine", klog.KObj(dockerMachine))
+			if err := r.Client.Create(ctx, dockerMachine); err != nil {
+				return errors.Wrapf(err, "failed to create DockerMachine %s", klog.KObj(dockerMachine))
+			}
+			continue
+		}
+
+		if dockerMachine.Spec.ProviderID != nil || instance.ProviderID == nil || !instance.Ready {
+			continue
+		}
+		patchHelper, err := patch.NewHelper(dockerMachine, r.Client)
+		if err != nil {
+			return err
+		}
+		dockerMachine.Spec.ProviderID = instance.ProviderID
+		dockerMachine.Spec.Bootstrapped = instance.Bootstrapped
+		if err := patchHelper.Patch(ctx, dockerMachine); err != nil {
+			return errors.Wrapf(err, "failed to patch DockerMachine %s", klog.KObj(dockerMachine))
+		}
+	}
+
+	// Delete DockerMachines whose instance does not exist anymore; if there is a MachinePool Machine,
+	// it is deleted instead, and the DockerMachine is deleted as part of the Machine deletion.
+	for name, dockerMachine := range dockerMachines {
+		if instances[name] || !dockerMachine.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		owner, err := util.GetOwnerMachine(ctx, r.Client, dockerMachine.ObjectMeta)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get the owner Machine for DockerMachine %s", klog.KObj(dockerMachine))
+		}
+		if owner != nil {
+			if !owner.DeletionTimestamp.IsZero() {
+				continue
+			}
+			log.Info("Deleting Machine for a DockerMachinePool instance that does not exist anymore", "Machine", klog.KObj(owner))
+			if err := r.Client.Delete(ctx, owner); err != nil && !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to delete Machine %s", klog.KObj(owner))
+			}
+			continue
+		}
+
+		log.Info("Deleting DockerMachine for a DockerMachinePool instance that does not exist anymore", "DockerMachine", klog.KObj(dockerMachine))
+		if err := r.Client.Delete(ctx, dockerMachine); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete DockerMachine %s", klog.KObj(dockerMachine))
+		}
+	}
+
+	dockerMachinePool.Status.InfrastructureMachineKind = "DockerMachine"
+	return nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+	infraexpv1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/internal/docker"
+)
+
+func TestReconcileDockerMachines(t *testing.T) {
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: metav1.NamespaceDefault}}
+	machinePool := &expv1.MachinePool{ObjectMeta: metav1.ObjectMeta{Name: "test-mp", Namespace: metav1.NamespaceDefault}}
+
+	newDockerMachinePool := func(instances ...infraexpv1.DockerMachinePoolInstanceStatus) *infraexpv1.DockerMachinePool {
+		return &infraexpv1.DockerMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-dmp", Namespace: metav1.NamespaceDefault, UID: "dmp-uid"},
+			Spec: infraexpv1.DockerMachinePoolSpec{
+				Template: infraexpv1.DockerMachinePoolMachineTemplate{
+					CustomImage:   "kindest/node:v1.28.0",
+					PreLoadImages: []string{"image:v1"},
+				},
+			},
+			Status: infraexpv1.DockerMachinePoolStatus{Instances: instances},
+		}
+	}
+	newDockerMachine := func(instanceName string, owner *clusterv1.Machine) *infrav1.DockerMachine {
+		dockerMachine := &infrav1.DockerMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      instanceName,
+				Namespace: metav1.NamespaceDefault,
+				Labels:    docker.MachinePoolMachineLabels(cluster, machinePool),
+			},
+			Spec: infrav1.DockerMachineSpec{InstanceName: instanceName},
+		}
+		if owner != nil {
+			dockerMachine.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Machine",
+				Name:       owner.Name,
+			}}
+		}
+		return dockerMachine
+	}
+	newMachine := func(name string, deleting bool) *clusterv1.Machine {
+		machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault}}
+		if deleting {
+			machine.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			machine.Finalizers = []string{"test"}
+		}
+		return machine
+	}
+
+	t.Run("creates a DockerMachine for each instance", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dockerMachinePool := newDockerMachinePool(
+			infraexpv1.DockerMachinePoolInstanceStatus{InstanceName: "worker-1"},
+			infraexpv1.DockerMachinePoolInstanceStatus{InstanceName: "worker-2"},
+		)
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		r := &DockerMachinePoolReconciler{Client: c}
+
+		g.Expect(r.reconcileDockerMachines(ctx, cluster, machinePool, dockerMachinePool)).To(Succeed())
+		g.Expect(dockerMachinePool.Status.InfrastructureMachineKind).To(Equal("DockerMachine"))
+
+		dockerMachineList := &infrav1.DockerMachineList{}
+		g.Expect(c.List(ctx, dockerMachineList, client.MatchingLabels(docker.MachinePoolMachineLabels(cluster, machinePool)))).To(Succeed())
+		g.Expect(dockerMachineList.Items).To(HaveLen(2))
+		for _, dockerMachine := range dockerMachineList.Items {
+			g.Expect(dockerMachine.Spec.InstanceName).To(Equal(dockerMachine.Name))
+			g.Expect(dockerMachine.Spec.CustomImage).To(Equal("kindest/node:v1.28.0"))
+			g.Expect(dockerMachine.Spec.PreLoadImages).To(Equal([]string{"image:v1"}))
+			g.Expect(dockerMachine.OwnerReferences).To(HaveLen(1))
+			g.Expect(dockerMachine.OwnerReferences[0].Kind).To(Equal("DockerMachinePool"))
+			g.Expect(dockerMachine.OwnerReferences[0].Name).To(Equal(dockerMachinePool.Name))
+		}
+	})
+
+	t.Run("sets the ProviderID once the instance is ready", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dockerMachinePool := newDockerMachinePool(
+			infraexpv1.DockerMachinePoolInstanceStatus{InstanceName: "worker-1", ProviderID: pointer.String("docker:////worker-1"), Bootstrapped: true, Ready: true},
+			infraexpv1.DockerMachinePoolInstanceStatus{InstanceName: "worker-2", ProviderID: pointer.String("docker:////worker-2"), Bootstrapped: true},
+		)
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newDockerMachine("worker-1", nil),
+			newDockerMachine("worker-2", nil),
+		).Build()
+		r := &DockerMachinePoolReconciler{Client: c}
+
+		g.Expect(r.reconcileDockerMachines(ctx, cluster, machinePool, dockerMachinePool)).To(Succeed())
+
+		ready := &infrav1.DockerMachine{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "worker-1"}, ready)).To(Succeed())
+		g.Expect(ready.Spec.ProviderID).To(Equal(pointer.String("docker:////worker-1")))
+		g.Expect(ready.Spec.Bootstrapped).To(BeTrue())
+
+		notReady := &infrav1.DockerMachine{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "worker-2"}, notReady)).To(Succeed())
+		g.Expect(notReady.Spec.ProviderID).To(BeNil())
+	})
+
+	t.Run("deletes the Machine of an instance that does not exist anymore", func(t *testing.T) {
+		g := NewWithT(t)
+
+		owner := newMachine("machine-1", false)
+		dockerMachinePool := newDockerMachinePool(infraexpv1.DockerMachinePoolInstanceStatus{InstanceName: "worker-2"})
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			owner,
+			newDockerMachine("worker-1", owner),
+			newDockerMachine("worker-2", nil),
+		).Build()
+		r := &DockerMachinePoolReconciler{Client: c}
+
+		g.Expect(r.reconcileDockerMachines(ctx, cluster, machinePool, dockerMachinePool)).To(Succeed())
+
+		err := c.Get(ctx, client.ObjectKeyFromObject(owner), &clusterv1.Machine{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		// The DockerMachine is deleted as part of the Machine deletion.
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "worker-1"}, &infrav1.DockerMachine{})).To(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "worker-2"}, &infrav1.DockerMachine{})).To(Succeed())
+	})
+
+	t.Run("does not delete again a Machine which is already being deleted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		owner := newMachine("machine-1", true)
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			owner,
+			newDockerMachine("worker-1", owner),
+		).Build()
+		r := &DockerMachinePoolReconciler{Client: c}
+
+		g.Expect(r.reconcileDockerMachines(ctx, cluster, machinePool, newDockerMachinePool())).To(Succeed())
+
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(owner), &clusterv1.Machine{})).To(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "worker-1"}, &infrav1.DockerMachine{})).To(Succeed())
+	})
+
+	t.Run("deletes the DockerMachine of an instance that does not exist anymore if there is no Machine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newDockerMachine("worker-1", nil),
+			// The owner Machine is referenced but does not exist.
+			newDockerMachine("worker-2", newMachine("machine-2", false)),
+		).Build()
+		r := &DockerMachinePoolReconciler{Client: c}
+
+		g.Expect(r.reconcileDockerMachines(ctx, cluster, machinePool, newDockerMachinePool())).To(Succeed())
+
+		dockerMachineList := &infrav1.DockerMachineList{}
+		g.Expect(c.List(ctx, dockerMachineList)).To(Succeed())
+		g.Expect(dockerMachineList.Items).To(BeEmpty())
+	})
+
+	t.Run("deletes all the Machines when scaling to zero", func(t *testing.T) {
+		g := NewWithT(t)
+
+		owner1 := newMachine("machine-1", false)
+		owner2 := newMachine("machine-2", false)
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			owner1,
+			owner2,
+			newDockerMachine("worker-1", owner1),
+			newDockerMachine("worker-2", owner2),
+		).Build()
+		r := &DockerMachinePoolReconciler{Client: c}
+
+		g.Expect(r.reconcileDockerMachines(ctx, cluster, machinePool, newDockerMachinePool())).To(Succeed())
+
+		machineList := &clusterv1.MachineList{}
+		g.Expect(c.List(ctx, machineList)).To(Succeed())
+		g.Expect(machineList.Items).To(BeEmpty())
+	})
+}
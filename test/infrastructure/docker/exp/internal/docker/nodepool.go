@@ -22,12 +22,14 @@ import (
 	"encoding/base64"
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,10 +38,12 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	infraexpv1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker"
 	"sigs.k8s.io/cluster-api/test/infrastructure/kind"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/labels/format"
 )
 
 const (
@@ -56,6 +60,8 @@ type NodePool struct {
 	dockerMachinePool *infraexpv1.DockerMachinePool
 	labelFilters      map[string]string
 	machines          []*docker.Machine
+	dockerMachines    map[string]*infrav1.DockerMachine
+	ownerMachines     map[string]*clusterv1.Machine
 }
 
 // NewNodePool creates a new node pool instances.
@@ -85,17 +91,17 @@ func (np *NodePool) ReconcileMachines(ctx context.Context, remoteClient client.C
 	desiredReplicas := int(*np.machinePool.Spec.Replicas)
 
 	// Delete all the machines in excess (outdated machines or machines exceeding desired replica count).
+	// NOTE: Machines already being deleted, e.g. because the corresponding MachinePool Machine has been deleted,
+	// are not counted, so they will be replaced if required to satisfy the desired replica count.
+	// NOTE: Machines whose MachinePool Machine has been marked with the delete machine annotation are deleted first.
+	machines := np.machinesByDeletePriority()
 	machineDeleted := false
 	totalNumberOfMachines := 0
-	for _, machine := range np.machines {
+	for _, machine := range machines {
 		totalNumberOfMachines++
 		if totalNumberOfMachines > desiredReplicas || !np.isMachineMatchingInfrastructureSpec(machine) {
-			externalMachine, err := docker.NewMachine(ctx, np.cluster, machine.Name(), np.labelFilters)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalMachine named %s", machine.Name())
-			}
-			if err := externalMachine.Delete(ctx); err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to delete machine %s", machine.Name())
+			if err := np.deleteMachine(ctx, machine); err != nil {
+				return ctrl.Result{}, err
 			}
 			machineDeleted = true
 			totalNumberOfMachines-- // remove deleted machine from the count
@@ -183,11 +189,12 @@ func (np *NodePool) isMachineMatchingInfrastructureSpec(machine *docker.Machine)
 	return machine.ContainerImage() == kindMapping.Image
 }
 
-// machinesMatchingInfrastructureSpec returns all of the docker.Machines which match the machine pool / docker machine pool spec.
+// machinesMatchingInfrastructureSpec returns all of the docker.Machines which match the machine pool / docker machine pool spec
+// and are not being deleted.
 func (np *NodePool) machinesMatchingInfrastructureSpec() []*docker.Machine {
 	var matchingMachines []*docker.Machine
 	for _, machine := range np.machines {
-		if np.isMachineMatchingInfrastructureSpec(machine) {
+		if np.isMachineMatchingInfrastructureSpec(machine) && !np.isMachineDeleting(machine) {
 			matchingMachines = append(matchingMachines, machine)
 		}
 	}
@@ -195,6 +202,64 @@ func (np *NodePool) machinesMatchingInfrastructureSpec() []*docker.Machine {
 	return matchingMachines
 }
 
+// machinesByDeletePriority returns the docker.Machines which are not being deleted, sorted so the ones that should be
+// deleted first when scaling down are at the end of the list.
+func (np *NodePool) machinesByDeletePriority() []*docker.Machine {
+	machines := make([]*docker.Machine, 0, len(np.machines))
+	for _, machine := range np.machines {
+		if !np.isMachineDeleting(machine) {
+			machines = append(machines, machine)
+		}
+	}
+
+	sort.SliceStable(machines, func(i, j int) bool {
+		return !np.isMachineMarkedForDeletion(machines[i]) && np.isMachineMarkedForDeletion(machines[j])
+	})
+	return machines
+}
+
+// isMachineDeleting returns true if the DockerMachine or the MachinePool Machine corresponding to the docker.Machine
+// are being deleted.
+func (np *NodePool) isMachineDeleting(machine *docker.Machine) bool {
+	if dockerMachine, ok := np.dockerMachines[machine.Name()]; ok && !dockerMachine.DeletionTimestamp.IsZero() {
+		return true
+	}
+	if owner, ok := np.ownerMachines[machine.Name()]; ok && !owner.DeletionTimestamp.IsZero() {
+		return true
+	}
+	return false
+}
+
+// isMachineMarkedForDeletion returns true if the MachinePool Machine corresponding to the docker.Machine has the
+// delete machine annotation.
+func (np *NodePool) isMachineMarkedForDeletion(machine *docker.Machine) bool {
+	if owner, ok := np.ownerMachines[machine.Name()]; ok {
+		_, marked := owner.Annotations[clusterv1.DeleteMachineAnnotation]
+		return marked
+	}
+	return false
+}
+
+// deleteMachine deletes a machine from the node pool. If the machine has a MachinePool Machine, the Machine is
+// deleted instead, so the corresponding node is drained before the docker.Machine is deleted.
+func (np *NodePool) deleteMachine(ctx context.Context, machine *docker.Machine) error {
+	if owner, ok := np.ownerMachines[machine.Name()]; ok {
+		if err := np.client.Delete(ctx, owner); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete Machine %s", klog.KObj(owner))
+		}
+		return nil
+	}
+
+	externalMachine, err := docker.NewMachine(ctx, np.cluster, machine.Name(), np.labelFilters)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create helper for managing the externalMachine named %s", machine.Name())
+	}
+	if err := externalMachine.Delete(ctx); err != nil {
+		return errors.Wrapf(err, "failed to delete machine %s", machine.Name())
+	}
+	return nil
+}
+
 // addMachine will add a new machine to the node pool and update the docker machine pool status.
 func (np *NodePool) addMachine(ctx context.Context) error {
 	instanceName := fmt.Sprintf("worker-%s", util.RandomString(6))
@@ -219,7 +284,12 @@ func (np *NodePool) addMachine(ctx context.Context) error {
 		}
 	}
 
-	if err := externalMachine.Create(ctx, np.dockerMachinePool.Spec.Template.CustomImage, constants.WorkerNodeRoleValue, np.machinePool.Spec.Template.Spec.Version, labels, np.dockerMachinePool.Spec.Template.ExtraMounts); err != nil {
+	dockerCluster, err := np.getDockerCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := externalMachine.Create(ctx, np.dockerMachinePool.Spec.Template.CustomImage, constants.WorkerNodeRoleValue, np.machinePool.Spec.Template.Spec.Version, labels, np.dockerMachinePool.Spec.Template.ExtraMounts, nil, docker.MachineNetworks(np.cluster.Name, dockerCluster.Spec.Network), nil); err != nil {
 		return errors.Wrapf(err, "failed to create docker machine with instance name %s", instanceName)
 	}
 	return nil
@@ -241,9 +311,36 @@ func (np *NodePool) refresh(ctx context.Context) error {
 			np.machines = append(np.machines, machine)
 		}
 	}
+
+	dockerMachineList := &infrav1.DockerMachineList{}
+	if err := np.client.List(ctx, dockerMachineList, client.InNamespace(np.dockerMachinePool.Namespace), client.MatchingLabels(MachinePoolMachineLabels(np.cluster, np.machinePool))); err != nil {
+		return errors.Wrapf(err, "failed to list DockerMachines for DockerMachinePool %s", klog.KObj(np.dockerMachinePool))
+	}
+	np.dockerMachines = make(map[string]*infrav1.DockerMachine, len(dockerMachineList.Items))
+	np.ownerMachines = map[string]*clusterv1.Machine{}
+	for i := range dockerMachineList.Items {
+		dockerMachine := &dockerMachineList.Items[i]
+		np.dockerMachines[dockerMachine.Spec.InstanceName] = dockerMachine
+
+		owner, err := util.GetOwnerMachine(ctx, np.client, dockerMachine.ObjectMeta)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get the owner Machine for DockerMachine %s", klog.KObj(dockerMachine))
+		}
+		if owner != nil {
+			np.ownerMachines[dockerMachine.Spec.InstanceName] = owner
+		}
+	}
 	return nil
 }
 
+// MachinePoolMachineLabels returns the labels identifying the DockerMachines of a machine pool.
+func MachinePoolMachineLabels(cluster *clusterv1.Cluster, machinePool *expv1.MachinePool) map[string]string {
+	return map[string]string{
+		clusterv1.ClusterNameLabel:     cluster.Name,
+		clusterv1.MachinePoolNameLabel: format.MustFormatValue(machinePool.Name),
+	}
+}
+
 // reconcileMachine will build and provision a docker machine and update the docker machine pool status for that instance.
 func (np *NodePool) reconcileMachine(ctx context.Context, machine *docker.Machine, remoteClient client.Client) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -291,7 +388,18 @@ func (np *NodePool) reconcileMachine(ctx context.Context, machine *docker.Machin
 		// is not already bootstrapped.
 		if err := externalMachine.CheckForBootstrapSuccess(timeoutCtx, false); err != nil {
 			log.Info("Bootstrapping instance", "instance", machine.Name())
-			if err := externalMachine.PreloadLoadImages(timeoutCtx, np.dockerMachinePool.Spec.Template.PreLoadImages); err != nil {
+			dockerCluster, err := np.getDockerCluster(ctx)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+
+			if err := externalMachine.ConfigureRegistryMirrors(timeoutCtx, dockerCluster.Spec.RegistryMirrors); err != nil {
+				return ctrl.Result{}, errors.Wrapf(err, "failed to configure registry mirrors into the docker machine with instance name %s", machine.Name())
+			}
+
+			// NOTE: Images defined in the DockerCluster are pre-loaded into all the machines of the cluster.
+			preLoadImages := append(append([]string{}, dockerCluster.Spec.PreLoadImages...), np.dockerMachinePool.Spec.Template.PreLoadImages...)
+			if err := externalMachine.PreloadLoadImages(timeoutCtx, preLoadImages); err != nil {
 				return ctrl.Result{}, errors.Wrapf(err, "failed to pre-load images into the docker machine with instance name %s", machine.Name())
 			}
 
@@ -362,6 +470,20 @@ func (np *NodePool) reconcileMachine(ctx context.Context, machine *docker.Machin
 	return ctrl.Result{}, nil
 }
 
+// getDockerCluster fetches the DockerCluster of the cluster the node pool belongs to.
+func (np *NodePool) getDockerCluster(ctx context.Context) (*infrav1.DockerCluster, error) {
+	if np.cluster.Spec.InfrastructureRef == nil {
+		return nil, errors.Errorf("infrastructureRef is not set for Cluster %s", klog.KObj(np.cluster))
+	}
+
+	dockerCluster := &infrav1.DockerCluster{}
+	key := client.ObjectKey{Namespace: np.cluster.Namespace, Name: np.cluster.Spec.InfrastructureRef.Name}
+	if err := np.client.Get(ctx, key, dockerCluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to get DockerCluster for Cluster %s", klog.KObj(np.cluster))
+	}
+	return dockerCluster, nil
+}
+
 // getBootstrapData fetches the bootstrap data for the machine pool.
 func getBootstrapData(ctx context.Context, c client.Client, machinePool *expv1.MachinePool) (string, bootstrapv1.Format, error) {
 	if machinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
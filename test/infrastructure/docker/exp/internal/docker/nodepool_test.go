@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+	infraexpv1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker"
+)
+
+func TestMachinesByDeletePriority(t *testing.T) {
+	ctx := container.RuntimeInto(context.Background(), &container.FakeRuntime{})
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: metav1.NamespaceDefault}}
+
+	tests := []struct {
+		name           string
+		machines       []string
+		dockerMachines map[string]*infrav1.DockerMachine
+		ownerMachines  map[string]*clusterv1.Machine
+		want           []string
+	}{
+		{
+			name:     "preserves the order if no machine is marked for deletion",
+			machines: []string{"worker-1", "worker-2", "worker-3"},
+			ownerMachines: map[string]*clusterv1.Machine{
+				"worker-1": newMachine("machine-1", nil, false),
+			},
+			want: []string{"worker-1", "worker-2", "worker-3"},
+		},
+		{
+			name:     "moves the machines marked for deletion to the end",
+			machines: []string{"worker-1", "worker-2", "worker-3", "worker-4"},
+			ownerMachines: map[string]*clusterv1.Machine{
+				"worker-1": newMachine("machine-1", map[string]string{clusterv1.DeleteMachineAnnotation: ""}, false),
+				"worker-2": newMachine("machine-2", nil, false),
+				"worker-3": newMachine("machine-3", map[string]string{clusterv1.DeleteMachineAnnotation: ""}, false),
+			},
+			want: []string{"worker-2", "worker-4", "worker-1", "worker-3"},
+		},
+		{
+			name:     "excludes the machines being deleted",
+			machines: []string{"worker-1", "worker-2", "worker-3"},
+			dockerMachines: map[string]*infrav1.DockerMachine{
+				"worker-1": {ObjectMeta: metav1.ObjectMeta{Name: "worker-1", DeletionTimestamp: &metav1.Time{Time: time.Now()}}},
+				"worker-2": {ObjectMeta: metav1.ObjectMeta{Name: "worker-2"}},
+			},
+			ownerMachines: map[string]*clusterv1.Machine{
+				"worker-3": newMachine("machine-3", map[string]string{clusterv1.DeleteMachineAnnotation: ""}, true),
+			},
+			want: []string{"worker-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			np := &NodePool{
+				cluster:        cluster,
+				dockerMachines: tt.dockerMachines,
+				ownerMachines:  tt.ownerMachines,
+			}
+			for _, name := range tt.machines {
+				machine, err := docker.NewMachine(ctx, cluster, name, nil)
+				g.Expect(err).ToNot(HaveOccurred())
+				np.machines = append(np.machines, machine)
+			}
+
+			got := []string{}
+			for _, machine := range np.machinesByDeletePriority() {
+				got = append(got, machine.Name())
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestDeleteMachine(t *testing.T) {
+	ctx := container.RuntimeInto(context.Background(), &container.FakeRuntime{})
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: metav1.NamespaceDefault}}
+
+	t.Run("deletes the MachinePool Machine if it exists", func(t *testing.T) {
+		g := NewWithT(t)
+
+		owner := newMachine("machine-1", nil, false)
+		c := fake.NewClientBuilder().WithScheme(newScheme(g)).WithObjects(owner).Build()
+		np := &NodePool{
+			client:        c,
+			cluster:       cluster,
+			ownerMachines: map[string]*clusterv1.Machine{"worker-1": owner},
+		}
+		machine, err := docker.NewMachine(ctx, cluster, "worker-1", nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(np.deleteMachine(ctx, machine)).To(Succeed())
+
+		err = c.Get(ctx, client.ObjectKeyFromObject(owner), &clusterv1.Machine{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	t.Run("tolerates a MachinePool Machine already deleted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(newScheme(g)).Build()
+		np := &NodePool{
+			client:        c,
+			cluster:       cluster,
+			ownerMachines: map[string]*clusterv1.Machine{"worker-1": newMachine("machine-1", nil, false)},
+		}
+		machine, err := docker.NewMachine(ctx, cluster, "worker-1", nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(np.deleteMachine(ctx, machine)).To(Succeed())
+	})
+
+	t.Run("deletes the docker machine if there is no MachinePool Machine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(newScheme(g)).Build()
+		np := &NodePool{
+			client:  c,
+			cluster: cluster,
+		}
+		machine, err := docker.NewMachine(ctx, cluster, "worker-1", nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(np.deleteMachine(ctx, machine)).To(Succeed())
+	})
+}
+
+func TestReconcileMachinesScaleToZero(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := container.RuntimeInto(context.Background(), &container.FakeRuntime{})
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: metav1.NamespaceDefault}}
+	machinePool := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-mp", Namespace: metav1.NamespaceDefault},
+		Spec: expv1.MachinePoolSpec{
+			Replicas: pointer.Int32(0),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{Version: pointer.String("v1.28.0")},
+			},
+		},
+	}
+	dockerMachinePool := &infraexpv1.DockerMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-dmp", Namespace: metav1.NamespaceDefault},
+		Status: infraexpv1.DockerMachinePoolStatus{
+			Instances: []infraexpv1.DockerMachinePoolInstanceStatus{
+				{InstanceName: "worker-1", Ready: true},
+				{InstanceName: "worker-2", Ready: true},
+			},
+		},
+	}
+	owner1 := newMachine("machine-1", nil, false)
+	owner2 := newMachine("machine-2", map[string]string{clusterv1.DeleteMachineAnnotation: ""}, false)
+	c := fake.NewClientBuilder().WithScheme(newScheme(g)).WithObjects(owner1, owner2).Build()
+
+	np := &NodePool{
+		client:            c,
+		cluster:           cluster,
+		machinePool:       machinePool,
+		dockerMachinePool: dockerMachinePool,
+		ownerMachines: map[string]*clusterv1.Machine{
+			"worker-1": owner1,
+			"worker-2": owner2,
+		},
+	}
+	for _, name := range []string{"worker-1", "worker-2"} {
+		machine, err := docker.NewMachine(ctx, cluster, name, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		np.machines = append(np.machines, machine)
+	}
+
+	res, err := np.ReconcileMachines(ctx, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.IsZero()).To(BeTrue())
+
+	machineList := &clusterv1.MachineList{}
+	g.Expect(c.List(ctx, machineList)).To(Succeed())
+	g.Expect(machineList.Items).To(BeEmpty())
+	g.Expect(np.machines).To(BeEmpty())
+	g.Expect(dockerMachinePool.Status.Instances).To(BeEmpty())
+}
+
+func newMachine(name string, annotations map[string]string, deleting bool) *clusterv1.Machine {
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   metav1.NamespaceDefault,
+			Annotations: annotations,
+		},
+	}
+	if deleting {
+		machine.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	}
+	return machine
+}
+
+func newScheme(g *WithT) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
@@ -144,6 +144,14 @@ func patchDockerCluster(ctx context.Context, patchHelper *patch.Helper, dockerCl
 }
 
 func (r *DockerClusterReconciler) reconcileNormal(ctx context.Context, dockerCluster *infrav1.DockerCluster, externalLoadBalancer *docker.LoadBalancer) error {
+	// Create the dedicated network for the cluster, if required.
+	if dockerCluster.Spec.Network != nil {
+		if err := docker.CreateClusterNetwork(ctx, externalLoadBalancer.ClusterName(), dockerCluster.Spec.Network); err != nil {
+			conditions.MarkFalse(dockerCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return errors.Wrap(err, "failed to create cluster network")
+		}
+	}
+
 	// Create the docker container hosting the load balancer.
 	if err := externalLoadBalancer.Create(ctx); err != nil {
 		conditions.MarkFalse(dockerCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
@@ -160,6 +168,8 @@ func (r *DockerClusterReconciler) reconcileNormal(ctx context.Context, dockerClu
 	if dockerCluster.Spec.ControlPlaneEndpoint.Host == "" {
 		// Surface the control plane endpoint
 		// Note: the control plane port is already set by the user or defaulted by the dockerCluster webhook.
+		// Note: when the cluster has a dedicated network, this is the IP of the load balancer in the shared network, so the
+		// management cluster can reach the control plane endpoint.
 		dockerCluster.Spec.ControlPlaneEndpoint.Host = lbIP
 	}
 
@@ -189,6 +199,12 @@ func (r *DockerClusterReconciler) reconcileDelete(ctx context.Context, dockerClu
 		return errors.Wrap(err, "failed to delete load balancer")
 	}
 
+	// Delete the dedicated network of the cluster, if any.
+	// NOTE: The network is deleted even if spec.network has been removed in the meantime.
+	if err := docker.DeleteClusterNetwork(ctx, externalLoadBalancer.ClusterName()); err != nil {
+		return errors.Wrap(err, "failed to delete cluster network")
+	}
+
 	// Cluster is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(dockerCluster, infrav1.ClusterFinalizer)
 
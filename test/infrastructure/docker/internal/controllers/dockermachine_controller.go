@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -41,6 +42,7 @@ import (
 	"sigs.k8s.io/cluster-api/test/infrastructure/container"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/logs"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -57,6 +59,16 @@ type DockerMachineReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// ConsoleLogsBaseURL is the base URL of the CAPD diagnostics endpoint serving the logs of the machines;
+	// if empty, DockerMachine.Status.ConsoleLogsURL is relative to the diagnostics endpoint.
+	ConsoleLogsBaseURL string
+
+	// ContainerHealthCheckInterval is the interval at which the containers backing provisioned machines are checked;
+	// if zero, containers are checked only when DockerMachines are reconciled for other reasons.
+	ContainerHealthCheckInterval time.Duration
+
+	recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockermachines,verbs=get;list;watch;create;update;patch;delete
@@ -64,6 +76,7 @@ type DockerMachineReconciler struct {
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machinesets;machines,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile handles DockerMachine events.
 func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, rerr error) {
@@ -158,10 +171,19 @@ func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	// Create a helper for managing the docker container hosting the machine.
-	externalMachine, err := docker.NewMachine(ctx, cluster, machine.Name, nil)
+	// NOTE: DockerMachines belonging to a DockerMachinePool are backed by a container created by the DockerMachinePool
+	// controller, which is identified by instanceName.
+	instanceName := machine.Name
+	if dockerMachine.Spec.InstanceName != "" {
+		instanceName = dockerMachine.Spec.InstanceName
+	}
+	externalMachine, err := docker.NewMachine(ctx, cluster, instanceName, nil)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalMachine")
 	}
+	if externalMachine.Exists() {
+		dockerMachine.Status.ConsoleLogsURL = logs.MachineURL(r.ConsoleLogsBaseURL, dockerMachine)
+	}
 
 	// Create a helper for managing a docker container hosting the loadbalancer.
 	// NB. the machine controller has to manage the cluster load balancer because the current implementation of the
@@ -195,6 +217,7 @@ func patchDockerMachine(ctx context.Context, patchHelper *patch.Helper, dockerMa
 		conditions.WithConditions(
 			infrav1.ContainerProvisionedCondition,
 			infrav1.BootstrapExecSucceededCondition,
+			infrav1.ContainerHealthyCondition,
 		),
 		conditions.WithStepCounterIf(dockerMachine.ObjectMeta.DeletionTimestamp.IsZero() && dockerMachine.Spec.ProviderID == nil),
 	)
@@ -207,6 +230,7 @@ func patchDockerMachine(ctx context.Context, patchHelper *patch.Helper, dockerMa
 			clusterv1.ReadyCondition,
 			infrav1.ContainerProvisionedCondition,
 			infrav1.BootstrapExecSucceededCondition,
+			infrav1.ContainerHealthyCondition,
 		}},
 	)
 }
@@ -227,15 +251,32 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 		// This is required after move, because status is not moved to the target cluster.
 		dockerMachine.Status.Ready = true
 
-		if externalMachine.Exists() {
-			conditions.MarkTrue(dockerMachine, infrav1.ContainerProvisionedCondition)
+		if !externalMachine.Exists() {
+			conditions.MarkFalse(dockerMachine, infrav1.ContainerProvisionedCondition, infrav1.ContainerDeletedReason, clusterv1.ConditionSeverityError, fmt.Sprintf("Container %s does not exists anymore", externalMachine.Name()))
+			return ctrl.Result{}, nil
+		}
+
+		conditions.MarkTrue(dockerMachine, infrav1.ContainerProvisionedCondition)
+		if err := r.reconcileContainerHealth(ctx, machine, dockerMachine, externalMachine); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to check the health of the DockerMachine container")
+		}
+		if conditions.IsTrue(dockerMachine, infrav1.ContainerHealthyCondition) {
 			// Setting machine address is required after move, because status.Address field is not retained during move.
 			if err := setMachineAddress(ctx, dockerMachine, externalMachine); err != nil {
 				return ctrl.Result{}, errors.Wrap(err, "failed to set the machine address")
 			}
-		} else {
-			conditions.MarkFalse(dockerMachine, infrav1.ContainerProvisionedCondition, infrav1.ContainerDeletedReason, clusterv1.ConditionSeverityError, fmt.Sprintf("Container %s does not exists anymore", externalMachine.Name()))
+			// Setting the SSH endpoint is required after move, because status.SSH field is not retained during move.
+			if err := setMachineSSHEndpoint(ctx, dockerMachine, externalMachine); err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to set the machine SSH endpoint")
+			}
 		}
+		return ctrl.Result{RequeueAfter: r.ContainerHealthCheckInterval}, nil
+	}
+
+	// DockerMachines belonging to a DockerMachinePool are provisioned by the DockerMachinePool controller,
+	// which also takes care of setting the ProviderID; return and wait for it.
+	if _, ok := dockerMachine.Labels[clusterv1.MachinePoolNameLabel]; ok {
+		log.Info("Waiting for the DockerMachinePool controller to provision the DockerMachine")
 		return ctrl.Result{}, nil
 	}
 
@@ -260,16 +301,55 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 
 	// Create the machine if not existing yet
 	if !externalMachine.Exists() {
+		// Check the host of the container runtime can run nested kubelets, and surface actionable
+		// information to the users when this is not the case.
+		hostInfo, err := r.ContainerRuntime.GetHostInfo(ctx)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to get container runtime host info")
+		}
+		if err := hostInfo.ValidateNestedKubelet(); err != nil {
+			log.Info("The container runtime host does not support running nested kubelets", "reason", err.Error())
+			conditions.MarkFalse(dockerMachine, infrav1.ContainerProvisionedCondition, infrav1.HostNotSupportedReason, clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+		}
+
 		// NOTE: FailureDomains don't mean much in CAPD since it's all local, but we are setting a label on
 		// each container, so we can check placement.
-		if err := externalMachine.Create(ctx, dockerMachine.Spec.CustomImage, role, machine.Spec.Version, docker.FailureDomainLabel(machine.Spec.FailureDomain), dockerMachine.Spec.ExtraMounts); err != nil {
+		if err := externalMachine.Create(ctx, dockerMachine.Spec.CustomImage, role, machine.Spec.Version, docker.FailureDomainLabel(machine.Spec.FailureDomain), dockerMachine.Spec.ExtraMounts, dockerMachine.Spec.Resources, docker.MachineNetworks(cluster.Name, dockerCluster.Spec.Network), dockerMachine.Spec.SSH); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to create worker DockerMachine")
 		}
 	}
 
+	// Configure registry mirrors into the container
+	if err := externalMachine.ConfigureRegistryMirrors(ctx, dockerCluster.Spec.RegistryMirrors); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to configure registry mirrors into the DockerMachine")
+	}
+
+	// Redirect the control plane endpoint to the load balancer address in the dedicated network of the cluster, if the machine
+	// is not connected to the shared network.
+	if dockerCluster.Spec.Network != nil && dockerCluster.Spec.Network.Isolated && dockerCluster.Spec.ControlPlaneEndpoint.Host != "" {
+		lbIP, err := externalLoadBalancer.ClusterNetworkIP(ctx)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to get the load balancer IP in the cluster network")
+		}
+		if err := externalMachine.RedirectControlPlaneEndpoint(ctx, dockerCluster.Spec.ControlPlaneEndpoint.Host, dockerCluster.Spec.ControlPlaneEndpoint.Port, lbIP); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to redirect the control plane endpoint in the DockerMachine")
+		}
+	}
+
+	// Inject the SSH server into the container, if SSH access is enabled
+	if err := externalMachine.ConfigureSSH(ctx, dockerMachine.Spec.SSH); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to configure SSH access to the DockerMachine")
+	}
+	if err := setMachineSSHEndpoint(ctx, dockerMachine, externalMachine); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to set the machine SSH endpoint")
+	}
+
 	// Preload images into the container
-	if len(dockerMachine.Spec.PreLoadImages) > 0 {
-		if err := externalMachine.PreloadLoadImages(ctx, dockerMachine.Spec.PreLoadImages); err != nil {
+	// NOTE: Images defined in the DockerCluster are pre-loaded into all the machines of the cluster.
+	preLoadImages := append(append([]string{}, dockerCluster.Spec.PreLoadImages...), dockerMachine.Spec.PreLoadImages...)
+	if len(preLoadImages) > 0 {
+		if err := externalMachine.PreloadLoadImages(ctx, preLoadImages); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to pre-load images into the DockerMachine")
 		}
 	}
@@ -436,6 +516,55 @@ func (r *DockerMachineReconciler) reconcileDelete(ctx context.Context, dockerClu
 	return nil
 }
 
+// reconcileContainerHealth reflects the state of the container backing a provisioned DockerMachine into the
+// ContainerHealthyCondition, and records events on the Machine when the container stops or restarts unexpectedly.
+// NOTE: A container that has been stopped is not restarted by CAPD, and it is reported only with the condition, which
+// is surfaced on the Machine through the DockerMachine Ready condition; failureReason and failureMessage are not set,
+// because they are terminal and they would not be cleared if the container is started again.
+func (r *DockerMachineReconciler) reconcileContainerHealth(ctx context.Context, machine *clusterv1.Machine, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	state, err := externalMachine.ContainerState(ctx)
+	if err != nil {
+		return err
+	}
+
+	if restartCount := int32(state.RestartCount); restartCount > dockerMachine.Status.ContainerRestartCount {
+		r.recorder.Eventf(machine, corev1.EventTypeWarning, "ContainerRestarted", "Container %s has been restarted by the container runtime, restart count: %d", externalMachine.ContainerName(), restartCount)
+		dockerMachine.Status.ContainerRestartCount = restartCount
+	}
+
+	wasHealthy := !conditions.IsFalse(dockerMachine, infrav1.ContainerHealthyCondition)
+	switch {
+	case state.Restarting:
+		conditions.MarkFalse(dockerMachine, infrav1.ContainerHealthyCondition, infrav1.ContainerRestartingReason, clusterv1.ConditionSeverityWarning, "Container %s is being restarted, last exit code: %d", externalMachine.ContainerName(), state.ExitCode)
+		return nil
+	case state.Running:
+		if !wasHealthy {
+			r.recorder.Eventf(machine, corev1.EventTypeNormal, "ContainerRecovered", "Container %s is running again", externalMachine.ContainerName())
+		}
+		conditions.MarkTrue(dockerMachine, infrav1.ContainerHealthyCondition)
+		return nil
+	}
+
+	reason := infrav1.ContainerExitedReason
+	message := fmt.Sprintf("Container %s is %s, exit code: %d", externalMachine.ContainerName(), state.Status, state.ExitCode)
+	if state.OOMKilled {
+		reason = infrav1.ContainerOOMKilledReason
+		message = fmt.Sprintf("Container %s has been killed because it ran out of memory", externalMachine.ContainerName())
+	}
+	if state.Error != "" {
+		message = fmt.Sprintf("%s: %s", message, state.Error)
+	}
+
+	if wasHealthy {
+		log.Info("Container backing the DockerMachine stopped unexpectedly", "reason", reason, "message", message)
+		r.recorder.Event(machine, corev1.EventTypeWarning, reason, message)
+	}
+	conditions.MarkFalse(dockerMachine, infrav1.ContainerHealthyCondition, reason, clusterv1.ConditionSeverityError, message)
+	return nil
+}
+
 // SetupWithManager will add watches for this controller.
 func (r *DockerMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	clusterToDockerMachines, err := util.ClusterToTypedObjectsMapper(mgr.GetClient(), &infrav1.DockerMachineList{}, mgr.GetScheme())
@@ -443,6 +572,8 @@ func (r *DockerMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 		return err
 	}
 
+	r.recorder = mgr.GetEventRecorderFor("dockermachine-controller")
+
 	err = ctrl.NewControllerManagedBy(mgr).
 		For(&infrav1.DockerMachine{}).
 		WithOptions(options).
@@ -569,3 +700,18 @@ func setMachineAddress(ctx context.Context, dockerMachine *infrav1.DockerMachine
 
 	return nil
 }
+
+// setMachineSSHEndpoint sets the endpoint for SSH access to the DockerMachine, if SSH access is enabled.
+func setMachineSSHEndpoint(ctx context.Context, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine) error {
+	if dockerMachine.Spec.SSH == nil {
+		dockerMachine.Status.SSH = nil
+		return nil
+	}
+
+	sshEndpoint, err := externalMachine.SSHEndpoint(ctx)
+	if err != nil {
+		return err
+	}
+	dockerMachine.Status.SSH = sshEndpoint
+	return nil
+}
@@ -23,11 +23,16 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 var (
@@ -66,6 +71,91 @@ func TestDockerMachineReconciler_DockerClusterToDockerMachines(t *testing.T) {
 	g.Expect(machineNames).To(ConsistOf("my-machine-0", "my-machine-1"))
 }
 
+func TestDockerMachineReconciler_HostNotSupported(t *testing.T) {
+	g := NewWithT(t)
+
+	fakeRuntime := &container.FakeRuntime{}
+	fakeRuntime.SetHostInfo(&container.HostInfo{Rootless: true, CgroupVersion: "1"})
+	defer fakeRuntime.SetHostInfo(nil)
+	ctx := container.RuntimeInto(context.Background(), fakeRuntime)
+
+	readyCluster := cluster.DeepCopy()
+	readyCluster.Status.InfrastructureReady = true
+	bootstrappedMachine := machine.DeepCopy()
+	bootstrappedMachine.Spec.Bootstrap.DataSecretName = pointer.String("bootstrap-data")
+	dm := dockerMachine.DeepCopy()
+
+	externalMachine, err := docker.NewMachine(ctx, readyCluster, dm.Name, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r := DockerMachineReconciler{ContainerRuntime: fakeRuntime}
+	res, err := r.reconcileNormal(ctx, readyCluster, dockerCluster, bootstrappedMachine, dm, externalMachine, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.RequeueAfter).ToNot(BeZero())
+	g.Expect(fakeRuntime.RunContainerCalls()).To(BeEmpty())
+
+	condition := conditions.Get(dm, infrav1.ContainerProvisionedCondition)
+	g.Expect(condition).ToNot(BeNil())
+	g.Expect(condition.Reason).To(Equal(infrav1.HostNotSupportedReason))
+	g.Expect(condition.Severity).To(Equal(clusterv1.ConditionSeverityError))
+	g.Expect(condition.Message).To(ContainSubstring("cgroup v2"))
+}
+
+func TestDockerMachineReconciler_ReconcileContainerHealth(t *testing.T) {
+	g := NewWithT(t)
+
+	fakeRuntime := &container.FakeRuntime{}
+	ctx := container.RuntimeInto(context.Background(), fakeRuntime)
+
+	dm := dockerMachine.DeepCopy()
+	externalMachine, err := docker.NewMachine(ctx, cluster, machine.Name, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer fakeRuntime.SetContainerState(externalMachine.ContainerName(), nil)
+
+	recorder := record.NewFakeRecorder(10)
+	r := DockerMachineReconciler{ContainerRuntime: fakeRuntime, recorder: recorder}
+
+	// A running container is healthy.
+	g.Expect(r.reconcileContainerHealth(ctx, machine, dm, externalMachine)).To(Succeed())
+	g.Expect(conditions.IsTrue(dm, infrav1.ContainerHealthyCondition)).To(BeTrue())
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	// A container restarted by the container runtime is reported with an event.
+	fakeRuntime.SetContainerState(externalMachine.ContainerName(), &container.ContainerState{Status: "restarting", Running: true, Restarting: true, ExitCode: 255, RestartCount: 1})
+	g.Expect(r.reconcileContainerHealth(ctx, machine, dm, externalMachine)).To(Succeed())
+	g.Expect(conditions.GetReason(dm, infrav1.ContainerHealthyCondition)).To(Equal(infrav1.ContainerRestartingReason))
+	g.Expect(conditions.GetSeverity(dm, infrav1.ContainerHealthyCondition)).To(HaveValue(Equal(clusterv1.ConditionSeverityWarning)))
+	g.Expect(dm.Status.ContainerRestartCount).To(Equal(int32(1)))
+	g.Expect(dm.Status.FailureReason).To(BeNil())
+	g.Expect(<-recorder.Events).To(ContainSubstring("ContainerRestarted"))
+
+	fakeRuntime.SetContainerState(externalMachine.ContainerName(), &container.ContainerState{Status: "running", Running: true, RestartCount: 1})
+	g.Expect(r.reconcileContainerHealth(ctx, machine, dm, externalMachine)).To(Succeed())
+	g.Expect(conditions.IsTrue(dm, infrav1.ContainerHealthyCondition)).To(BeTrue())
+	g.Expect(<-recorder.Events).To(ContainSubstring("ContainerRecovered"))
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	// A container killed because it ran out of memory is reported with the condition only.
+	fakeRuntime.SetContainerState(externalMachine.ContainerName(), &container.ContainerState{Status: "exited", OOMKilled: true, ExitCode: 137, RestartCount: 1})
+	g.Expect(r.reconcileContainerHealth(ctx, machine, dm, externalMachine)).To(Succeed())
+	g.Expect(conditions.GetReason(dm, infrav1.ContainerHealthyCondition)).To(Equal(infrav1.ContainerOOMKilledReason))
+	g.Expect(conditions.GetSeverity(dm, infrav1.ContainerHealthyCondition)).To(HaveValue(Equal(clusterv1.ConditionSeverityError)))
+	g.Expect(conditions.GetMessage(dm, infrav1.ContainerHealthyCondition)).To(ContainSubstring("ran out of memory"))
+	g.Expect(dm.Status.FailureReason).To(BeNil())
+	g.Expect(dm.Status.FailureMessage).To(BeNil())
+	g.Expect(<-recorder.Events).To(ContainSubstring("ContainerOOMKilled"))
+
+	// Events are recorded only when the container stops.
+	g.Expect(r.reconcileContainerHealth(ctx, machine, dm, externalMachine)).To(Succeed())
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	// A container started again is healthy.
+	fakeRuntime.SetContainerState(externalMachine.ContainerName(), &container.ContainerState{Status: "running", Running: true, RestartCount: 1})
+	g.Expect(r.reconcileContainerHealth(ctx, machine, dm, externalMachine)).To(Succeed())
+	g.Expect(conditions.IsTrue(dm, infrav1.ContainerHealthyCondition)).To(BeTrue())
+	g.Expect(<-recorder.Events).To(ContainSubstring("ContainerRecovered"))
+}
+
 func newCluster(clusterName string, dockerCluster *infrav1.DockerCluster) *clusterv1.Cluster {
 	cluster := &clusterv1.Cluster{
 		TypeMeta: metav1.TypeMeta{},
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// DockerMachineTemplateReconciler reconciles a DockerMachineTemplate object.
+type DockerMachineTemplateReconciler struct {
+	client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockermachinetemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockermachinetemplates/status,verbs=get;update;patch
+
+// Reconcile sets the capacity of the machines created from a DockerMachineTemplate in its status, so the
+// cluster autoscaler can scale node groups using the DockerMachineTemplate from zero.
+func (r *DockerMachineTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	dockerMachineTemplate := &infrav1.DockerMachineTemplate{}
+	if err := r.Client.Get(ctx, req.NamespacedName, dockerMachineTemplate); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !dockerMachineTemplate.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(dockerMachineTemplate, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	dockerMachineTemplate.Status.Capacity = capacityFromResources(dockerMachineTemplate.Spec.Template.Spec.Resources)
+
+	if err := patchHelper.Patch(ctx, dockerMachineTemplate); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to patch DockerMachineTemplate")
+	}
+	return ctrl.Result{}, nil
+}
+
+// capacityFromResources returns the capacity of a machine with the given resource limits.
+// NOTE: A machine without limits can use all the resources of the host, which is not a meaningful capacity
+// for the cluster autoscaler; in this case no capacity is reported.
+func capacityFromResources(resources *infrav1.DockerMachineResources) corev1.ResourceList {
+	if resources == nil {
+		return nil
+	}
+
+	capacity := corev1.ResourceList{}
+	if resources.CPU != nil {
+		capacity[corev1.ResourceCPU] = resources.CPU.DeepCopy()
+	}
+	if resources.Memory != nil {
+		capacity[corev1.ResourceMemory] = resources.Memory.DeepCopy()
+	}
+	if len(capacity) == 0 {
+		return nil
+	}
+	return capacity
+}
+
+// SetupWithManager will add watches for this controller.
+func (r *DockerMachineTemplateReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.DockerMachineTemplate{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+func TestDockerMachineTemplateReconciler_Reconcile(t *testing.T) {
+	cpu := resource.MustParse("2")
+	memory := resource.MustParse("4Gi")
+
+	tests := []struct {
+		name      string
+		resources *infrav1.DockerMachineResources
+		want      corev1.ResourceList
+	}{
+		{
+			name:      "no capacity without resources",
+			resources: nil,
+			want:      nil,
+		},
+		{
+			name:      "no capacity with empty resources",
+			resources: &infrav1.DockerMachineResources{},
+			want:      nil,
+		},
+		{
+			name:      "capacity with cpu only",
+			resources: &infrav1.DockerMachineResources{CPU: &cpu},
+			want:      corev1.ResourceList{corev1.ResourceCPU: cpu},
+		},
+		{
+			name:      "capacity with cpu and memory",
+			resources: &infrav1.DockerMachineResources{CPU: &cpu, Memory: &memory},
+			want:      corev1.ResourceList{corev1.ResourceCPU: cpu, corev1.ResourceMemory: memory},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dockerMachineTemplate := &infrav1.DockerMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-docker-machine-template",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: infrav1.DockerMachineTemplateSpec{
+					Template: infrav1.DockerMachineTemplateResource{
+						Spec: infrav1.DockerMachineSpec{
+							Resources: tt.resources,
+						},
+					},
+				},
+			}
+			c := fake.NewClientBuilder().WithObjects(dockerMachineTemplate).WithStatusSubresource(dockerMachineTemplate).Build()
+			r := DockerMachineTemplateReconciler{
+				Client: c,
+			}
+
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(dockerMachineTemplate)})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got := &infrav1.DockerMachineTemplate{}
+			g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(dockerMachineTemplate), got)).To(Succeed())
+			g.Expect(got.Status.Capacity).To(HaveLen(len(tt.want)))
+			for name, quantity := range tt.want {
+				g.Expect(got.Status.Capacity).To(HaveKey(name))
+				g.Expect(got.Status.Capacity[name].Equal(quantity)).To(BeTrue())
+			}
+		})
+	}
+}
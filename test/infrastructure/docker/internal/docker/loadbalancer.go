@@ -22,6 +22,7 @@ import (
 	"strconv"
 
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/kind/pkg/cluster/constants"
 
@@ -33,7 +34,7 @@ import (
 )
 
 type lbCreator interface {
-	CreateExternalLoadBalancerNode(ctx context.Context, name, image, clusterName, listenAddress string, port int32, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error)
+	CreateExternalLoadBalancerNode(ctx context.Context, name, image string, entrypoint []string, clusterName, listenAddress string, port int32, networks []string, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error)
 }
 
 // LoadBalancer manages the load balancer for a specific docker cluster.
@@ -45,6 +46,9 @@ type LoadBalancer struct {
 	lbCreator                lbCreator
 	backendControlPlanePort  string
 	frontendControlPlanePort string
+	implementation           loadbalancer.Implementation
+	settings                 *infrav1.DockerLoadBalancerSettings
+	networks                 []string
 }
 
 // NewLoadBalancer returns a new helper for managing a docker loadbalancer with a given name.
@@ -70,41 +74,32 @@ func NewLoadBalancer(ctx context.Context, cluster *clusterv1.Cluster, dockerClus
 		return nil, fmt.Errorf("create load balancer: %s", err)
 	}
 
-	image := getLoadBalancerImage(dockerCluster)
+	implementation, err := loadbalancer.ImplementationFor(dockerCluster.Spec.LoadBalancer.Type)
+	if err != nil {
+		return nil, fmt.Errorf("create load balancer: %s", err)
+	}
 
 	return &LoadBalancer{
 		name:                     cluster.Name,
-		image:                    image,
+		image:                    implementation.Image(dockerCluster.Spec.LoadBalancer.ImageRepository, dockerCluster.Spec.LoadBalancer.ImageTag),
 		container:                container,
 		ipFamily:                 ipFamily,
 		lbCreator:                &Manager{},
 		frontendControlPlanePort: strconv.Itoa(dockerCluster.Spec.ControlPlaneEndpoint.Port),
 		backendControlPlanePort:  "6443",
+		implementation:           implementation,
+		settings:                 dockerCluster.Spec.LoadBalancer.Settings,
+		networks:                 loadBalancerNetworks(cluster.Name, dockerCluster.Spec.Network),
 	}, nil
 }
 
-// getLoadBalancerImage will return the image (e.g. "kindest/haproxy:2.1.1-alpine") to use for
-// the load balancer.
-func getLoadBalancerImage(dockerCluster *infrav1.DockerCluster) string {
-	// Check if a non-default image was provided
-	image := loadbalancer.Image
-	imageRepo := loadbalancer.DefaultImageRepository
-	imageTag := loadbalancer.DefaultImageTag
-
-	if dockerCluster != nil {
-		if dockerCluster.Spec.LoadBalancer.ImageRepository != "" {
-			imageRepo = dockerCluster.Spec.LoadBalancer.ImageRepository
-		}
-		if dockerCluster.Spec.LoadBalancer.ImageTag != "" {
-			imageTag = dockerCluster.Spec.LoadBalancer.ImageTag
-		}
-	}
-
-	return fmt.Sprintf("%s/%s:%s", imageRepo, image, imageTag)
+// ClusterName is the name of the cluster the load balancer belongs to.
+func (s *LoadBalancer) ClusterName() string {
+	return s.name
 }
 
 // ContainerName is the name of the docker container with the load balancer.
-func (s *LoadBalancer) containerName() string {
+func (s *LoadBalancer) ContainerName() string {
 	return fmt.Sprintf("%s-lb", s.name)
 }
 
@@ -123,11 +118,13 @@ func (s *LoadBalancer) Create(ctx context.Context) error {
 		log.Info("Creating load balancer container")
 		s.container, err = s.lbCreator.CreateExternalLoadBalancerNode(
 			ctx,
-			s.containerName(),
+			s.ContainerName(),
 			s.image,
+			s.implementation.Entrypoint(),
 			s.name,
 			listenAddr,
 			0,
+			s.networks,
 			s.ipFamily,
 		)
 		if err != nil {
@@ -169,34 +166,71 @@ func (s *LoadBalancer) UpdateConfiguration(ctx context.Context, unsafeLoadBalanc
 		}
 	}
 
-	loadBalancerConfigTemplate := loadbalancer.DefaultTemplate
+	loadBalancerConfigTemplate := s.implementation.DefaultTemplate()
 	if unsafeLoadBalancerConfig != "" {
 		loadBalancerConfigTemplate = unsafeLoadBalancerConfig
 	}
 
-	loadBalancerConfig, err := loadbalancer.Config(&loadbalancer.ConfigData{
+	configData := &loadbalancer.ConfigData{
 		FrontendControlPlanePort: s.frontendControlPlanePort,
 		BackendControlPlanePort:  s.backendControlPlanePort,
 		BackendServers:           backendServers,
 		IPv6:                     s.ipFamily == clusterv1.IPv6IPFamily,
-	},
-		loadBalancerConfigTemplate,
-	)
+	}
+	if s.settings != nil {
+		configData.ConnectTimeout = milliseconds(s.settings.ConnectTimeout)
+		configData.ClientTimeout = milliseconds(s.settings.ClientTimeout)
+		configData.ServerTimeout = milliseconds(s.settings.ServerTimeout)
+		configData.HealthCheckInterval = milliseconds(s.settings.HealthCheckInterval)
+	}
+
+	loadBalancerConfig, err := loadbalancer.Config(configData, loadBalancerConfigTemplate)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
 	log.Info("Updating load balancer configuration")
-	if err := s.container.WriteFile(ctx, loadbalancer.ConfigPath, loadBalancerConfig); err != nil {
+	if err := s.container.WriteFile(ctx, s.implementation.ConfigPath(), loadBalancerConfig); err != nil {
 		return errors.WithStack(err)
 	}
 
-	return errors.WithStack(s.container.Kill(ctx, "SIGHUP"))
+	return errors.WithStack(s.container.Kill(ctx, s.implementation.ReloadSignal()))
+}
+
+// milliseconds returns a duration in milliseconds, or 0 if the duration is not set.
+func milliseconds(d *metav1.Duration) int64 {
+	if d == nil {
+		return 0
+	}
+	return d.Milliseconds()
 }
 
 // IP returns the load balancer IP address.
+// NOTE: When the cluster has a dedicated network, the load balancer is connected to both the dedicated and the shared network;
+// in this case the IP address in the shared network is returned, because it is reachable from the management cluster.
 func (s *LoadBalancer) IP(ctx context.Context) (string, error) {
-	lbIPv4, lbIPv6, err := s.container.IP(ctx)
+	if len(s.networks) > 1 {
+		return s.networkIP(ctx, DefaultNetwork)
+	}
+	return s.networkIP(ctx, "")
+}
+
+// ClusterNetworkIP returns the load balancer IP address in the dedicated network of the cluster, if any,
+// otherwise in the shared network.
+func (s *LoadBalancer) ClusterNetworkIP(ctx context.Context) (string, error) {
+	return s.networkIP(ctx, s.networks[0])
+}
+
+// networkIP returns the load balancer IP address in a network; if network is empty, the IP address in the
+// network the load balancer has been created with is returned.
+func (s *LoadBalancer) networkIP(ctx context.Context, network string) (string, error) {
+	var lbIPv4, lbIPv6 string
+	var err error
+	if network != "" {
+		lbIPv4, lbIPv6, err = s.container.NetworkIP(ctx, network)
+	} else {
+		lbIPv4, lbIPv6, err = s.container.IP(ctx)
+	}
 	if err != nil {
 		return "", errors.WithStack(err)
 	}
@@ -208,7 +242,7 @@ func (s *LoadBalancer) IP(ctx context.Context) (string, error) {
 	}
 	if lbIP == "" {
 		// if there is a load balancer container with the same name exists but is stopped, it may not have IP address associated with it.
-		return "", errors.Errorf("load balancer IP cannot be empty: container %s does not have an associated IP address", s.containerName())
+		return "", errors.Errorf("load balancer IP cannot be empty: container %s does not have an associated IP address", s.ContainerName())
 	}
 	return lbIP, nil
 }
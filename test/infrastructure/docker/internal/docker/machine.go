@@ -21,8 +21,11 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -55,8 +58,8 @@ var (
 )
 
 type nodeCreator interface {
-	CreateControlPlaneNode(ctx context.Context, name, clusterName, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (node *types.Node, err error)
-	CreateWorkerNode(ctx context.Context, name, clusterName string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (node *types.Node, err error)
+	CreateControlPlaneNode(ctx context.Context, name, clusterName, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, resources *infrav1.DockerMachineResources, networks []string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (node *types.Node, err error)
+	CreateWorkerNode(ctx context.Context, name, clusterName string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, resources *infrav1.DockerMachineResources, networks []string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (node *types.Node, err error)
 }
 
 // Machine implement a service for managing the docker containers hosting a kubernetes nodes.
@@ -168,6 +171,14 @@ func (m *Machine) ContainerName() string {
 	return machineContainerName(m.cluster, m.machine)
 }
 
+// FailureDomain returns the failure domain of the machine, if any.
+func (m *Machine) FailureDomain() string {
+	if m.container == nil {
+		return ""
+	}
+	return m.container.Labels[failureDomainLabelKey]
+}
+
 // ProviderID return the provider identifier for this machine.
 func (m *Machine) ProviderID() string {
 	return fmt.Sprintf("docker:////%s", m.ContainerName())
@@ -191,6 +202,15 @@ func (m *Machine) Address(ctx context.Context) ([]string, error) {
 	return nil, errors.New("unknown ipFamily")
 }
 
+// ContainerState returns the state of the container hosting the machine, as reported by the container runtime.
+func (m *Machine) ContainerState(ctx context.Context) (*container.ContainerState, error) {
+	containerRuntime, err := container.RuntimeFrom(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to container runtime")
+	}
+	return containerRuntime.GetContainerState(ctx, m.ContainerName())
+}
+
 // ContainerImage return the image of the container for this machine
 // or empty string if the container does not exist yet.
 func (m *Machine) ContainerImage() string {
@@ -201,7 +221,10 @@ func (m *Machine) ContainerImage() string {
 }
 
 // Create creates a docker container hosting a Kubernetes node.
-func (m *Machine) Create(ctx context.Context, image string, role string, version *string, labels map[string]string, mounts []infrav1.Mount) error {
+// If resources is not nil, the CPU and memory available to the container are limited accordingly.
+// The container is connected to networks, see MachineNetworks; if networks is empty, the container is connected to DefaultNetwork.
+// If ssh is not nil, the SSH port of the container is published on the host, see ConfigureSSH and SSHEndpoint.
+func (m *Machine) Create(ctx context.Context, image string, role string, version *string, labels map[string]string, mounts []infrav1.Mount, resources *infrav1.DockerMachineResources, networks []string, ssh *infrav1.DockerMachineSSH) error {
 	log := ctrl.LoggerFrom(ctx)
 
 	// Create if not exists.
@@ -232,8 +255,10 @@ func (m *Machine) Create(ctx context.Context, image string, role string, version
 				"127.0.0.1",
 				0,
 				kindMounts(mounts),
-				nil,
+				sshPortMappings(ssh),
 				labels,
+				resources,
+				networks,
 				m.ipFamily,
 				kindMapping,
 			)
@@ -247,8 +272,10 @@ func (m *Machine) Create(ctx context.Context, image string, role string, version
 				m.ContainerName(),
 				m.cluster,
 				kindMounts(mounts),
-				nil,
+				sshPortMappings(ssh),
 				labels,
+				resources,
+				networks,
 				m.ipFamily,
 				kindMapping,
 			)
@@ -308,6 +335,12 @@ func (m *Machine) PreloadLoadImages(ctx context.Context, images []string) error
 	for i, image := range images {
 		imageTarPath := filepath.Clean(filepath.Join(dir, fmt.Sprintf("image-%d.tar", i)))
 
+		// Pull the image on the host only if not already present, so the image is pulled only once from the
+		// registry and then loaded into every machine.
+		if err := containerRuntime.PullContainerImageIfNotExists(ctx, image); err != nil {
+			return errors.Wrapf(err, "failed to pull image %q", image)
+		}
+
 		err = containerRuntime.SaveContainerImage(ctx, image, imageTarPath)
 		if err != nil {
 			return errors.Wrapf(err, "failed to save image %q to %q", image, imageTarPath)
@@ -328,6 +361,73 @@ func (m *Machine) PreloadLoadImages(ctx context.Context, images []string) error
 	return nil
 }
 
+// ConfigureRegistryMirrors configures registry mirrors for the containerd instance running in the machine.
+func (m *Machine) ConfigureRegistryMirrors(ctx context.Context, mirrors []infrav1.RegistryMirror) error {
+	if len(mirrors) == 0 {
+		return nil
+	}
+	if m.container == nil {
+		return errors.New("unable to configure registry mirrors. the container hosting this machine does not exists")
+	}
+
+	// If containerd is configured to read the registry hosts configuration from config_path, as in recent
+	// kindest/node images, mirrors are configured using a hosts.toml file for each registry.
+	if err := m.container.Commander.Command("grep", "-q", "config_path", containerdConfigPath).Run(ctx); err == nil {
+		for _, mirror := range mirrors {
+			dest := filepath.Join(containerdCertsDir, mirror.Registry, "hosts.toml")
+			if err := m.container.WriteFile(ctx, dest, registryHostsConfig(mirror)); err != nil {
+				return errors.Wrapf(err, "failed to write registry mirror configuration for %q", mirror.Registry)
+			}
+		}
+		return nil
+	}
+
+	// Otherwise mirrors are appended to the containerd configuration file, and containerd is restarted.
+	// NOTE: The configuration is appended only once, so it is safe to call this func multiple times.
+	if err := m.container.Commander.Command("grep", "-qF", registryMirrorsMarker, containerdConfigPath).Run(ctx); err == nil {
+		return nil
+	}
+	cmd := m.container.Commander.Command("tee", "-a", containerdConfigPath)
+	cmd.SetStdin(strings.NewReader(registryMirrorsConfig(mirrors)))
+	if err := cmd.Run(ctx); err != nil {
+		return errors.Wrap(err, "failed to append registry mirrors to the containerd configuration")
+	}
+	if err := m.container.Commander.Command("systemctl", "restart", "containerd").Run(ctx); err != nil {
+		return errors.Wrap(err, "failed to restart containerd")
+	}
+	return nil
+}
+
+// RedirectControlPlaneEndpoint redirects the traffic for the control plane endpoint to the address of the load balancer
+// in the dedicated network of the cluster; this is required for machines connected only to the dedicated network, because
+// the control plane endpoint is the address of the load balancer in the shared network, which they cannot reach.
+// NOTE: The rules are added only once, so it is safe to call this func multiple times.
+func (m *Machine) RedirectControlPlaneEndpoint(ctx context.Context, endpointHost string, endpointPort int, loadBalancerIP string) error {
+	if m.container == nil {
+		return errors.New("unable to redirect the control plane endpoint. the container hosting this machine does not exists")
+	}
+	if endpointHost == loadBalancerIP {
+		return nil
+	}
+
+	iptables := "iptables"
+	if ip := net.ParseIP(endpointHost); ip != nil && ip.To4() == nil {
+		iptables = "ip6tables"
+	}
+	destination := net.JoinHostPort(loadBalancerIP, strconv.Itoa(endpointPort))
+	// NOTE: OUTPUT applies to the processes running on the machine, e.g. the kubelet; PREROUTING to the pods, e.g. kube-proxy.
+	for _, chain := range []string{"OUTPUT", "PREROUTING"} {
+		rule := []string{chain, "-t", "nat", "-p", "tcp", "-d", endpointHost, "--dport", strconv.Itoa(endpointPort), "-j", "DNAT", "--to-destination", destination}
+		if err := m.container.Commander.Command(iptables, append([]string{"-C"}, rule...)...).Run(ctx); err == nil {
+			continue
+		}
+		if err := m.container.Commander.Command(iptables, append([]string{"-A"}, rule...)...).Run(ctx); err != nil {
+			return errors.Wrapf(err, "failed to redirect the control plane endpoint %s to %s", net.JoinHostPort(endpointHost, strconv.Itoa(endpointPort)), destination)
+		}
+	}
+	return nil
+}
+
 // ExecBootstrap runs bootstrap on a node, this is generally `kubeadm <init|join>`.
 func (m *Machine) ExecBootstrap(ctx context.Context, data string, format bootstrapv1.Format, version *string, image string) error {
 	log := ctrl.LoggerFrom(ctx)
@@ -414,6 +514,41 @@ func (m *Machine) CheckForBootstrapSuccess(ctx context.Context, logResult bool)
 	return nil
 }
 
+// ConsoleLogs writes the console logs of the container hosting this machine to w.
+func (m *Machine) ConsoleLogs(ctx context.Context, w io.Writer) error {
+	if m.container == nil {
+		return errors.New("unable to get console logs. the container hosting this machine does not exists")
+	}
+
+	containerRuntime, err := container.RuntimeFrom(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to container runtime")
+	}
+	return containerRuntime.ContainerLogs(ctx, m.ContainerName(), w)
+}
+
+// JournalLogs writes the journal of the container hosting this machine to w.
+// If unit is not empty, only the logs for the given systemd unit are written, e.g. kubelet.service.
+func (m *Machine) JournalLogs(ctx context.Context, unit string, w io.Writer) error {
+	if m.container == nil {
+		return errors.New("unable to get journal logs. the container hosting this machine does not exists")
+	}
+
+	args := []string{"--no-pager", "--output=short-precise"}
+	if unit != "" {
+		args = append(args, "--unit", unit)
+	}
+
+	var outErr bytes.Buffer
+	cmd := m.container.Commander.Command("journalctl", args...)
+	cmd.SetStderr(&outErr)
+	cmd.SetStdout(w)
+	if err := cmd.Run(ctx); err != nil {
+		return errors.Wrapf(err, "failed to read journal: stderr: %s", outErr.String())
+	}
+	return nil
+}
+
 // SetNodeProviderID sets the docker provider ID for the kubernetes node.
 func (m *Machine) SetNodeProviderID(ctx context.Context, c client.Client) error {
 	log := ctrl.LoggerFrom(ctx)
@@ -439,6 +574,7 @@ func (m *Machine) SetNodeProviderID(ctx context.Context, c client.Client) error
 	}
 
 	node.Spec.ProviderID = m.ProviderID()
+	m.setNodeTopologyLabels(node)
 
 	if err = patchHelper.Patch(ctx, node); err != nil {
 		return errors.Wrap(err, "failed update providerID")
@@ -449,8 +585,9 @@ func (m *Machine) SetNodeProviderID(ctx context.Context, c client.Client) error
 
 // CloudProviderNodePatch performs the tasks that would normally be down by an external cloud provider.
 // 1) For all CAPD Nodes it sets the ProviderID on the Kubernetes Node.
-// 2) If the cloudProviderTaint is set it updates the addresses in the Kubernetes Node `.status.addresses`.
-// 3) If the cloudProviderTaint is set it removes it to inform Kubernetes that this Node is now initialized.
+// 2) For all CAPD Nodes it sets the topology labels on the Kubernetes Node according to the machine failure domain.
+// 3) If the cloudProviderTaint is set it updates the addresses in the Kubernetes Node `.status.addresses`.
+// 4) If the cloudProviderTaint is set it removes it to inform Kubernetes that this Node is now initialized.
 func (m *Machine) CloudProviderNodePatch(ctx context.Context, c client.Client, dockerMachine *infrav1.DockerMachine) error {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -476,6 +613,9 @@ func (m *Machine) CloudProviderNodePatch(ctx context.Context, c client.Client, d
 	log.Info("Setting Kubernetes node providerID")
 	node.Spec.ProviderID = m.ProviderID()
 
+	// 2) Set the topology labels on the node, simulating failure domains.
+	m.setNodeTopologyLabels(node)
+
 	// If the node is managed by an external cloud provider - e.g. in dualstack tests - add the
 	// machine addresses on the node and remove the cloudProviderTaint.
 	if taints.HasTaint(node.Spec.Taints, cloudProviderTaint) {
@@ -502,7 +642,7 @@ func (m *Machine) CloudProviderNodePatch(ctx context.Context, c client.Client, d
 				Address: addr.Address,
 			})
 		}
-		// R3) emove the cloud provider taint on the node - if it exists - to initialize it.
+		// 4) Remove the cloud provider taint on the node - if it exists - to initialize it.
 		if taints.RemoveNodeTaint(node, cloudProviderTaint) {
 			log.Info("Removing the cloudprovider taint to initialize node")
 		}
@@ -514,6 +654,19 @@ func (m *Machine) CloudProviderNodePatch(ctx context.Context, c client.Client, d
 	return nil
 }
 
+// setNodeTopologyLabels sets the topology labels on the node according to the failure domain of the machine,
+// as a cloud provider would do, so failure domains can be used e.g. in topology spread constraints.
+func (m *Machine) setNodeTopologyLabels(node *corev1.Node) {
+	failureDomain := m.FailureDomain()
+	if failureDomain == "" {
+		return
+	}
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	node.Labels[corev1.LabelTopologyZone] = failureDomain
+}
+
 func (m *Machine) getDockerNode(ctx context.Context) (*types.Node, error) {
 	// collect info about the existing nodes
 	filters := container.FilterBuilder{}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker/types"
+)
+
+func TestSetNodeTopologyLabels(t *testing.T) {
+	tests := []struct {
+		name           string
+		containerLabel map[string]string
+		nodeLabels     map[string]string
+		expectedLabels map[string]string
+	}{
+		{
+			name:           "should not set labels if the machine has no failure domain",
+			nodeLabels:     map[string]string{"foo": "bar"},
+			expectedLabels: map[string]string{"foo": "bar"},
+		},
+		{
+			name:           "should set the zone label if the machine has a failure domain",
+			containerLabel: FailureDomainLabel(pointer.String("fd1")),
+			expectedLabels: map[string]string{corev1.LabelTopologyZone: "fd1"},
+		},
+		{
+			name:           "should preserve existing labels",
+			containerLabel: FailureDomainLabel(pointer.String("fd1")),
+			nodeLabels:     map[string]string{"foo": "bar"},
+			expectedLabels: map[string]string{"foo": "bar", corev1.LabelTopologyZone: "fd1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &Machine{
+				container: types.NewNode("node", "image", constants.WorkerNodeRoleValue).WithLabels(tt.containerLabel),
+			}
+			node := &corev1.Node{}
+			node.Labels = tt.nodeLabels
+
+			m.setNodeTopologyLabels(node)
+			if len(tt.expectedLabels) == 0 {
+				g.Expect(node.Labels).To(BeEmpty())
+				return
+			}
+			g.Expect(node.Labels).To(Equal(tt.expectedLabels))
+		})
+	}
+}
@@ -26,6 +26,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker/types"
 	"sigs.k8s.io/cluster-api/test/infrastructure/kind"
 )
@@ -39,9 +40,6 @@ const ControlPlanePort = 6443
 // DefaultNetwork is the default network name to use in kind.
 const DefaultNetwork = "kind"
 
-// haproxyEntrypoint is the entrypoint used to start the haproxy load balancer container.
-var haproxyEntrypoint = []string{"haproxy", "-W", "-db", "-f", "/usr/local/etc/haproxy/haproxy.cfg"}
-
 // Manager is the kind manager type.
 type Manager struct{}
 
@@ -53,6 +51,8 @@ type nodeCreateOpts struct {
 	Mounts       []v1alpha4.Mount
 	PortMappings []v1alpha4.PortMapping
 	Labels       map[string]string
+	Resources    *infrav1.DockerMachineResources
+	Networks     []string
 	IPFamily     clusterv1.ClusterIPFamily
 	KindMapping  kind.Mapping
 }
@@ -60,7 +60,7 @@ type nodeCreateOpts struct {
 // CreateControlPlaneNode will create a new control plane container.
 // NOTE: If port is 0 picking a host port for the control plane is delegated to the container runtime and is not stable across container restarts.
 // This means that connection to a control plane node may take some time to recover if the underlying container is restarted.
-func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, clusterName, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (*types.Node, error) {
+func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, clusterName, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, resources *infrav1.DockerMachineResources, networks []string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (*types.Node, error) {
 	// add api server port mapping
 	portMappingsWithAPIServer := append(portMappings, v1alpha4.PortMapping{
 		ListenAddress: listenAddress,
@@ -75,6 +75,8 @@ func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, clusterName,
 		PortMappings: portMappingsWithAPIServer,
 		Mounts:       mounts,
 		Labels:       labels,
+		Resources:    resources,
+		Networks:     networks,
 		IPFamily:     ipFamily,
 		KindMapping:  kindMapping,
 	}
@@ -87,7 +89,7 @@ func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, clusterName,
 }
 
 // CreateWorkerNode will create a new worker container.
-func (m *Manager) CreateWorkerNode(ctx context.Context, name, clusterName string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (*types.Node, error) {
+func (m *Manager) CreateWorkerNode(ctx context.Context, name, clusterName string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, resources *infrav1.DockerMachineResources, networks []string, ipFamily clusterv1.ClusterIPFamily, kindMapping kind.Mapping) (*types.Node, error) {
 	createOpts := &nodeCreateOpts{
 		Name:         name,
 		ClusterName:  clusterName,
@@ -95,6 +97,8 @@ func (m *Manager) CreateWorkerNode(ctx context.Context, name, clusterName string
 		PortMappings: portMappings,
 		Mounts:       mounts,
 		Labels:       labels,
+		Resources:    resources,
+		Networks:     networks,
 		IPFamily:     ipFamily,
 		KindMapping:  kindMapping,
 	}
@@ -104,7 +108,7 @@ func (m *Manager) CreateWorkerNode(ctx context.Context, name, clusterName string
 // CreateExternalLoadBalancerNode will create a new container to act as the load balancer for external access.
 // NOTE: If port is 0 picking a host port for the load balancer is delegated to the container runtime and is not stable across container restarts.
 // This can break the Kubeconfig in kind, i.e. the file resulting from `kind get kubeconfig -n $CLUSTER_NAME' if the load balancer container is restarted.
-func (m *Manager) CreateExternalLoadBalancerNode(ctx context.Context, name, image, clusterName, listenAddress string, port int32, _ clusterv1.ClusterIPFamily) (*types.Node, error) {
+func (m *Manager) CreateExternalLoadBalancerNode(ctx context.Context, name, image string, entrypoint []string, clusterName, listenAddress string, port int32, networks []string, _ clusterv1.ClusterIPFamily) (*types.Node, error) {
 	// load balancer port mapping
 	portMappings := []v1alpha4.PortMapping{{
 		ListenAddress: listenAddress,
@@ -117,7 +121,8 @@ func (m *Manager) CreateExternalLoadBalancerNode(ctx context.Context, name, imag
 		ClusterName:  clusterName,
 		Role:         constants.ExternalLoadBalancerNodeRoleValue,
 		PortMappings: portMappings,
-		EntryPoint:   haproxyEntrypoint,
+		EntryPoint:   entrypoint,
+		Networks:     networks,
 		// Load balancer doesn't have an equivalent in kind, but we use a kind.Mapping to
 		// forward the image name to create node.
 		KindMapping: kind.Mapping{
@@ -166,6 +171,18 @@ func createNode(ctx context.Context, opts *nodeCreateOpts) (*types.Node, error)
 		IPFamily: opts.IPFamily,
 		KindMode: opts.KindMapping.Mode,
 	}
+	if len(opts.Networks) > 0 {
+		runOptions.Network = opts.Networks[0]
+		runOptions.AdditionalNetworks = opts.Networks[1:]
+	}
+	if opts.Resources != nil {
+		if opts.Resources.CPU != nil {
+			runOptions.NanoCPUs = opts.Resources.CPU.MilliValue() * 1e6
+		}
+		if opts.Resources.Memory != nil {
+			runOptions.Memory = opts.Resources.Memory.Value()
+		}
+	}
 	if opts.Role == constants.ControlPlaneNodeRoleValue {
 		runOptions.EnvironmentVars = map[string]string{
 			"KUBECONFIG": "/etc/kubernetes/admin.conf",
@@ -184,7 +201,7 @@ func createNode(ctx context.Context, opts *nodeCreateOpts) (*types.Node, error)
 		return nil, err
 	}
 
-	return types.NewNode(opts.Name, opts.KindMapping.Image, opts.Role), nil
+	return types.NewNode(opts.Name, opts.KindMapping.Image, opts.Role).WithLabels(containerLabels), nil
 }
 
 func generateMountInfo(mounts []v1alpha4.Mount) []container.Mount {
@@ -21,11 +21,13 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
 	"sigs.k8s.io/kind/pkg/cluster/constants"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/kind"
 )
 
@@ -72,6 +74,41 @@ func TestCreateNode(t *testing.T) {
 	g.Expect(runConfig.Labels["io.x-k8s.kind.cluster"]).To(Equal("TestClusterName"))
 }
 
+func TestCreateNodeWithResources(t *testing.T) {
+	g := NewWithT(t)
+	containerRuntime := &container.FakeRuntime{}
+	ctx := container.RuntimeInto(context.Background(), containerRuntime)
+	containerRuntime.ResetRunContainerCallLogs()
+
+	cpu := resource.MustParse("1500m")
+	memory := resource.MustParse("2Gi")
+	createOpts := &nodeCreateOpts{
+		Name:        "TestName",
+		ClusterName: "TestClusterName",
+		Role:        constants.WorkerNodeRoleValue,
+		Resources: &infrav1.DockerMachineResources{
+			CPU:    &cpu,
+			Memory: &memory,
+		},
+		IPFamily: clusterv1.IPv4IPFamily,
+		KindMapping: kind.Mapping{
+			Image: "TestImage",
+			Mode:  kind.ModeNone, // no impact on the fake runtime.
+		},
+	}
+	_, err := createNode(ctx, createOpts)
+
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	callLog := containerRuntime.RunContainerCalls()
+	g.Expect(callLog).To(HaveLen(1))
+
+	runConfig := callLog[0].RunConfig
+	g.Expect(runConfig).ToNot(BeNil())
+	g.Expect(runConfig.NanoCPUs).To(Equal(int64(1500000000)))
+	g.Expect(runConfig.Memory).To(Equal(int64(2 * 1024 * 1024 * 1024)))
+}
+
 func TestCreateControlPlaneNode(t *testing.T) {
 	g := NewWithT(t)
 	containerRuntime := &container.FakeRuntime{}
@@ -80,7 +117,7 @@ func TestCreateControlPlaneNode(t *testing.T) {
 
 	containerRuntime.ResetRunContainerCallLogs()
 	m := Manager{}
-	node, err := m.CreateControlPlaneNode(ctx, "TestName", "TestCluster", "100.100.100.100", 80, []v1alpha4.Mount{}, []v1alpha4.PortMapping{}, make(map[string]string), clusterv1.IPv4IPFamily, kind.Mapping{Image: "TestImage"})
+	node, err := m.CreateControlPlaneNode(ctx, "TestName", "TestCluster", "100.100.100.100", 80, []v1alpha4.Mount{}, []v1alpha4.PortMapping{}, make(map[string]string), nil, nil, clusterv1.IPv4IPFamily, kind.Mapping{Image: "TestImage"})
 
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(node.Role()).Should(Equal(constants.ControlPlaneNodeRoleValue))
@@ -103,7 +140,7 @@ func TestCreateWorkerNode(t *testing.T) {
 
 	containerRuntime.ResetRunContainerCallLogs()
 	m := Manager{}
-	node, err := m.CreateWorkerNode(ctx, "TestName", "TestCluster", []v1alpha4.Mount{}, []v1alpha4.PortMapping{}, make(map[string]string), clusterv1.IPv4IPFamily, kind.Mapping{Image: "TestImage"})
+	node, err := m.CreateWorkerNode(ctx, "TestName", "TestCluster", []v1alpha4.Mount{}, []v1alpha4.PortMapping{}, make(map[string]string), nil, []string{"capd-TestCluster", DefaultNetwork}, clusterv1.IPv4IPFamily, kind.Mapping{Image: "TestImage"})
 
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(node.Role()).Should(Equal(constants.WorkerNodeRoleValue))
@@ -116,6 +153,8 @@ func TestCreateWorkerNode(t *testing.T) {
 	g.Expect(runConfig).ToNot(BeNil())
 	g.Expect(runConfig.Labels).To(HaveLen(2))
 	g.Expect(runConfig.Labels["io.x-k8s.kind.role"]).To(Equal(constants.WorkerNodeRoleValue))
+	g.Expect(runConfig.Network).To(Equal("capd-TestCluster"))
+	g.Expect(runConfig.AdditionalNetworks).To(Equal([]string{DefaultNetwork}))
 }
 
 func TestCreateExternalLoadBalancerNode(t *testing.T) {
@@ -126,7 +165,7 @@ func TestCreateExternalLoadBalancerNode(t *testing.T) {
 
 	containerRuntime.ResetRunContainerCallLogs()
 	m := Manager{}
-	node, err := m.CreateExternalLoadBalancerNode(ctx, "TestName", "TestImage", "TestCluster", "100.100.100.100", 0, clusterv1.IPv4IPFamily)
+	node, err := m.CreateExternalLoadBalancerNode(ctx, "TestName", "TestImage", []string{"TestEntrypoint"}, "TestCluster", "100.100.100.100", 0, nil, clusterv1.IPv4IPFamily)
 
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(node.Role()).Should(Equal(constants.ExternalLoadBalancerNodeRoleValue))
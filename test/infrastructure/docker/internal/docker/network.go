@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+// ClusterNetworkName returns the name of the dedicated network of a cluster.
+func ClusterNetworkName(clusterName string) string {
+	return fmt.Sprintf("capd-%s", clusterName)
+}
+
+// MachineNetworks returns the networks the machines of a cluster must be connected to;
+// the first network is the one used for the machine addresses.
+func MachineNetworks(clusterName string, network *infrav1.DockerClusterNetwork) []string {
+	if network == nil {
+		return []string{DefaultNetwork}
+	}
+	if network.Isolated {
+		return []string{ClusterNetworkName(clusterName)}
+	}
+	return []string{ClusterNetworkName(clusterName), DefaultNetwork}
+}
+
+// loadBalancerNetworks returns the networks the load balancer of a cluster must be connected to.
+// NOTE: The load balancer is always connected to the shared network, so the management cluster can reach it.
+func loadBalancerNetworks(clusterName string, network *infrav1.DockerClusterNetwork) []string {
+	if network == nil {
+		return []string{DefaultNetwork}
+	}
+	return []string{ClusterNetworkName(clusterName), DefaultNetwork}
+}
+
+// CreateClusterNetwork creates the dedicated network of a cluster, if not already existing.
+func CreateClusterNetwork(ctx context.Context, clusterName string, network *infrav1.DockerClusterNetwork) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	input := &container.CreateNetworkInput{
+		Name:    ClusterNetworkName(clusterName),
+		Subnets: network.CIDRBlocks,
+		Labels: map[string]string{
+			clusterLabelKey: clusterName,
+		},
+	}
+	for _, cidr := range network.CIDRBlocks {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return errors.Wrapf(err, "invalid network CIDR block %q", cidr)
+		}
+		if ip.To4() == nil {
+			input.EnableIPv6 = true
+		}
+	}
+
+	containerRuntime, err := container.RuntimeFrom(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to container runtime")
+	}
+
+	log.V(4).Info("Creating cluster network", "network", input.Name)
+	return containerRuntime.CreateNetwork(ctx, input)
+}
+
+// DeleteClusterNetwork deletes the dedicated network of a cluster, if existing.
+func DeleteClusterNetwork(ctx context.Context, clusterName string) error {
+	containerRuntime, err := container.RuntimeFrom(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to container runtime")
+	}
+	return containerRuntime.DeleteNetwork(ctx, ClusterNetworkName(clusterName))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker/types"
+)
+
+func TestNetworks(t *testing.T) {
+	tests := []struct {
+		name                     string
+		network                  *infrav1.DockerClusterNetwork
+		wantMachineNetworks      []string
+		wantLoadBalancerNetworks []string
+	}{
+		{
+			name:                     "shared network",
+			network:                  nil,
+			wantMachineNetworks:      []string{"kind"},
+			wantLoadBalancerNetworks: []string{"kind"},
+		},
+		{
+			name:                     "dedicated network",
+			network:                  &infrav1.DockerClusterNetwork{},
+			wantMachineNetworks:      []string{"capd-foo", "kind"},
+			wantLoadBalancerNetworks: []string{"capd-foo", "kind"},
+		},
+		{
+			name:                     "isolated dedicated network",
+			network:                  &infrav1.DockerClusterNetwork{Isolated: true},
+			wantMachineNetworks:      []string{"capd-foo"},
+			wantLoadBalancerNetworks: []string{"capd-foo", "kind"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(MachineNetworks("foo", tt.network)).To(Equal(tt.wantMachineNetworks))
+			g.Expect(loadBalancerNetworks("foo", tt.network)).To(Equal(tt.wantLoadBalancerNetworks))
+		})
+	}
+}
+
+func TestLoadBalancerIP(t *testing.T) {
+	ctx := container.RuntimeInto(context.Background(), &container.FakeRuntime{})
+
+	tests := []struct {
+		name                 string
+		network              *infrav1.DockerClusterNetwork
+		wantIP               string
+		wantClusterNetworkIP string
+	}{
+		{
+			name:                 "shared network",
+			network:              nil,
+			wantIP:               "my-cluster-lbIPv4",
+			wantClusterNetworkIP: "my-cluster-lbkindIPv4",
+		},
+		{
+			name:                 "dedicated network",
+			network:              &infrav1.DockerClusterNetwork{},
+			wantIP:               "my-cluster-lbkindIPv4",
+			wantClusterNetworkIP: "my-cluster-lbcapd-my-clusterIPv4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			lb := &LoadBalancer{
+				name:      "my-cluster",
+				container: types.NewNode("my-cluster-lb", "", constants.ExternalLoadBalancerNodeRoleValue),
+				ipFamily:  clusterv1.IPv4IPFamily,
+				networks:  loadBalancerNetworks("my-cluster", tt.network),
+			}
+
+			ip, err := lb.IP(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ip).To(Equal(tt.wantIP))
+
+			ip, err = lb.ClusterNetworkIP(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ip).To(Equal(tt.wantClusterNetworkIP))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+	"strings"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+const (
+	containerdConfigPath = "/etc/containerd/config.toml"
+	containerdCertsDir   = "/etc/containerd/certs.d"
+
+	// registryMirrorsMarker marks the registry mirrors configuration appended to the containerd configuration file.
+	registryMirrorsMarker = "# Registry mirrors configured by CAPD"
+)
+
+// registryMirrorsConfig returns the containerd configuration for the given registry mirrors.
+func registryMirrorsConfig(mirrors []infrav1.RegistryMirror) string {
+	var b strings.Builder
+	b.WriteString("\n" + registryMirrorsMarker + "\n")
+	for _, mirror := range mirrors {
+		fmt.Fprintf(&b, "[plugins.\"io.containerd.grpc.v1.cri\".registry.mirrors.%q]\n", mirror.Registry)
+		fmt.Fprintf(&b, "  endpoint = [%s]\n", quoteAll(mirror.Endpoints))
+	}
+	return b.String()
+}
+
+// registryHostsConfig returns the containerd hosts.toml configuration for the given registry mirror.
+func registryHostsConfig(mirror infrav1.RegistryMirror) string {
+	var b strings.Builder
+	for _, endpoint := range mirror.Endpoints {
+		fmt.Fprintf(&b, "[host.%q]\n", endpoint)
+		b.WriteString("  capabilities = [\"pull\", \"resolve\"]\n")
+	}
+	return b.String()
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", v))
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+func TestRegistryMirrorsConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	config := registryMirrorsConfig([]infrav1.RegistryMirror{
+		{Registry: "docker.io", Endpoints: []string{"http://kind-registry:5000", "https://mirror.gcr.io"}},
+		{Registry: "registry.k8s.io", Endpoints: []string{"http://kind-registry:5001"}},
+	})
+
+	g.Expect(config).To(Equal(`
+# Registry mirrors configured by CAPD
+[plugins."io.containerd.grpc.v1.cri".registry.mirrors."docker.io"]
+  endpoint = ["http://kind-registry:5000", "https://mirror.gcr.io"]
+[plugins."io.containerd.grpc.v1.cri".registry.mirrors."registry.k8s.io"]
+  endpoint = ["http://kind-registry:5001"]
+`))
+}
+
+func TestRegistryHostsConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	config := registryHostsConfig(infrav1.RegistryMirror{
+		Registry:  "docker.io",
+		Endpoints: []string{"http://kind-registry:5000", "https://mirror.gcr.io"},
+	})
+
+	g.Expect(config).To(Equal(`[host."http://kind-registry:5000"]
+  capabilities = ["pull", "resolve"]
+[host."https://mirror.gcr.io"]
+  capabilities = ["pull", "resolve"]
+`))
+}
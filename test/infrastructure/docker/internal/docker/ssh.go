@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+const (
+	// sshPort is the port the SSH server listens on inside the machine.
+	sshPort = 22
+
+	// sshUser is the user allowed to log in to the machine.
+	sshUser = "root"
+
+	// sshListenAddress is the address the SSH port of the machine is published on; the SSH port is published on
+	// the loopback interface only, so the machine is not exposed outside the container runtime host.
+	sshListenAddress = "127.0.0.1"
+
+	sshAuthorizedKeysPath = "/root/.ssh/authorized_keys"
+
+	// sshInstallScript installs the SSH server, unless already present in the image; the script holds a lock, so
+	// concurrent reconciles do not run the package manager in parallel.
+	sshInstallScript = `set -e
+exec 9>/var/lock/capd-ssh-install.lock
+flock 9
+if [ ! -x /usr/sbin/sshd ]; then
+  apt-get update
+  DEBIAN_FRONTEND=noninteractive apt-get install -y --no-install-recommends openssh-server
+fi
+`
+)
+
+// sshPortMappings returns the port mappings publishing the SSH port of a machine, if SSH access is enabled.
+// NOTE: picking the host port is delegated to the container runtime, so it is not stable across container restarts.
+func sshPortMappings(ssh *infrav1.DockerMachineSSH) []v1alpha4.PortMapping {
+	if ssh == nil {
+		return nil
+	}
+	return []v1alpha4.PortMapping{{
+		ListenAddress: sshListenAddress,
+		ContainerPort: sshPort,
+		Protocol:      v1alpha4.PortMappingProtocolTCP,
+	}}
+}
+
+// sshAuthorizedKeys returns the content of the authorized_keys file for the given SSH access.
+func sshAuthorizedKeys(ssh *infrav1.DockerMachineSSH) string {
+	return strings.Join(ssh.AuthorizedKeys, "\n") + "\n"
+}
+
+// ConfigureSSH injects an SSH server into the machine, if not already present, and configures the authorized keys
+// for the root user.
+// NOTE: kindest/node images do not ship an SSH server, so it is installed from the Debian package repositories, unless
+// the image already contains it (e.g. a custom image built from kindest/node); installing requires the machine to have
+// access to the internet, or to a package mirror configured in the image.
+// It is safe to call this func multiple times: the SSH server is installed and the authorized keys are written only if
+// required, and the SSH server is started only if it is not running.
+func (m *Machine) ConfigureSSH(ctx context.Context, ssh *infrav1.DockerMachineSSH) error {
+	if ssh == nil {
+		return nil
+	}
+	if m.container == nil {
+		return errors.New("unable to configure SSH. the container hosting this machine does not exists")
+	}
+
+	if err := m.container.Commander.Command("test", "-x", "/usr/sbin/sshd").Run(ctx); err != nil {
+		if err := m.container.Commander.Command("sh", "-c", sshInstallScript).Run(ctx); err != nil {
+			return errors.Wrap(err, "failed to install the SSH server")
+		}
+	}
+
+	authorizedKeys := sshAuthorizedKeys(ssh)
+	var currentAuthorizedKeys bytes.Buffer
+	cmd := m.container.Commander.Command("cat", sshAuthorizedKeysPath)
+	cmd.SetStdout(&currentAuthorizedKeys)
+	if err := cmd.Run(ctx); err != nil || currentAuthorizedKeys.String() != authorizedKeys {
+		if err := m.container.WriteFile(ctx, sshAuthorizedKeysPath, authorizedKeys); err != nil {
+			return errors.Wrap(err, "failed to write the SSH authorized keys")
+		}
+		if err := m.container.Commander.Command("chmod", "600", sshAuthorizedKeysPath).Run(ctx); err != nil {
+			return errors.Wrap(err, "failed to set permissions on the SSH authorized keys")
+		}
+	}
+
+	if err := m.container.Commander.Command("systemctl", "is-active", "--quiet", "ssh").Run(ctx); err == nil {
+		return nil
+	}
+	if err := m.container.Commander.Command("systemctl", "enable", "--now", "ssh").Run(ctx); err != nil {
+		return errors.Wrap(err, "failed to start the SSH server")
+	}
+	return nil
+}
+
+// SSHEndpoint returns the endpoint for SSH access to the machine.
+func (m *Machine) SSHEndpoint(ctx context.Context) (*infrav1.DockerMachineSSHEndpoint, error) {
+	containerRuntime, err := container.RuntimeFrom(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to container runtime")
+	}
+
+	hostPort, err := containerRuntime.GetHostPort(ctx, m.ContainerName(), fmt.Sprintf("%d/tcp", sshPort))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the SSH host port")
+	}
+	port, err := strconv.ParseInt(hostPort, 10, 32)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid SSH host port %q", hostPort)
+	}
+
+	return &infrav1.DockerMachineSSHEndpoint{
+		User: sshUser,
+		Host: sshListenAddress,
+		Port: int32(port),
+	}, nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+func TestSSHPortMappings(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(sshPortMappings(nil)).To(BeEmpty())
+	g.Expect(sshPortMappings(&infrav1.DockerMachineSSH{AuthorizedKeys: []string{"ssh-ed25519 AAAA"}})).To(Equal([]v1alpha4.PortMapping{{
+		ListenAddress: "127.0.0.1",
+		ContainerPort: 22,
+		HostPort:      0,
+		Protocol:      v1alpha4.PortMappingProtocolTCP,
+	}}))
+}
+
+func TestSSHAuthorizedKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	ssh := &infrav1.DockerMachineSSH{AuthorizedKeys: []string{"ssh-ed25519 AAAA user1", "ssh-rsa BBBB user2"}}
+	g.Expect(sshAuthorizedKeys(ssh)).To(Equal("ssh-ed25519 AAAA user1\nssh-rsa BBBB user2\n"))
+}
@@ -38,6 +38,7 @@ type Node struct {
 	ClusterRole string
 	InternalIP  string
 	Image       string
+	Labels      map[string]string
 	status      string
 	Commander   *ContainerCmder
 }
@@ -58,6 +59,12 @@ func (n *Node) WithStatus(status string) *Node {
 	return n
 }
 
+// WithLabels sets the labels of the container and returns the node.
+func (n *Node) WithLabels(labels map[string]string) *Node {
+	n.Labels = labels
+	return n
+}
+
 // String returns the name of the node.
 func (n Node) String() string {
 	return n.Name
@@ -85,6 +92,21 @@ func (n *Node) IP(ctx context.Context) (ipv4 string, ipv6 string, err error) {
 	return ipv4, ipv6, nil
 }
 
+// NetworkIP gets the docker ipv4 and ipv6 of the node in a network.
+func (n *Node) NetworkIP(ctx context.Context, network string) (ipv4 string, ipv6 string, err error) {
+	containerRuntime, err := container.RuntimeFrom(ctx)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to connect to container runtime")
+	}
+
+	ipv4, ipv6, err = containerRuntime.GetContainerNetworkIPs(ctx, n.Name, network)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to get node IPs in network %s from runtime", network)
+	}
+
+	return ipv4, ipv6, nil
+}
+
 // IsRunning returns if the container is running.
 func (n *Node) IsRunning() bool {
 	return strings.HasPrefix(n.status, "Up")
@@ -113,7 +113,7 @@ func list(ctx context.Context, visit func(context.Context, string, *types.Node),
 		image := cntr.Image
 		status := cntr.Status
 
-		visit(ctx, cluster, types.NewNode(name, image, "undetermined").WithStatus(status))
+		visit(ctx, cluster, types.NewNode(name, image, "undetermined").WithStatus(status).WithLabels(cntr.Labels))
 	}
 
 	return nil
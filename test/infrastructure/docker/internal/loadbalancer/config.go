@@ -30,6 +30,12 @@ type ConfigData struct {
 	BackendControlPlanePort  string
 	BackendServers           map[string]string
 	IPv6                     bool
+
+	// Timeouts and health check interval, in milliseconds; if not set, defaults are used.
+	ConnectTimeout      int64
+	ClientTimeout       int64
+	ServerTimeout       int64
+	HealthCheckInterval int64
 }
 
 // DefaultTemplate is the loadbalancer config template.
@@ -49,10 +55,9 @@ defaults
   log global
   mode tcp
   option dontlognull
-  # TODO: tune these
-  timeout connect 5000
-  timeout client 50000
-  timeout server 50000
+  timeout connect {{ .ConnectTimeout }}
+  timeout client {{ .ClientTimeout }}
+  timeout server {{ .ServerTimeout }}
   # allow to boot despite dns don't resolve backends
   default-server init-addr none
 
@@ -67,12 +72,84 @@ backend kube-apiservers
   option httpchk GET /healthz
   # TODO: we should be verifying (!)
   {{range $server, $address := .BackendServers}}
-  server {{ $server }} {{ JoinHostPort $address $.BackendControlPlanePort }} check check-ssl verify none resolvers docker resolve-prefer {{ if $.IPv6 -}} ipv6 {{- else -}} ipv4 {{- end }}
+  server {{ $server }} {{ JoinHostPort $address $.BackendControlPlanePort }} check inter {{ $.HealthCheckInterval }} check-ssl verify none resolvers docker resolve-prefer {{ if $.IPv6 -}} ipv6 {{- else -}} ipv4 {{- end }}
+  {{- end}}
+`
+
+// VIPTemplate is the loadbalancer config template simulating a kube-vip style virtual IP.
+// NOTE: All the backend servers are configured as backup servers, so HAProxy sends all the traffic to the first
+// available server, failing over to the next one when it becomes unavailable.
+const VIPTemplate = `# generated by CAPD
+global
+  log /dev/log local0
+  log /dev/log local1 notice
+  daemon
+  maxconn 100000
+
+resolvers docker
+  nameserver dns 127.0.0.11:53
+
+defaults
+  log global
+  mode tcp
+  option dontlognull
+  timeout connect {{ .ConnectTimeout }}
+  timeout client {{ .ClientTimeout }}
+  timeout server {{ .ServerTimeout }}
+  # allow to boot despite dns don't resolve backends
+  default-server init-addr none
+
+frontend control-plane
+  bind *:{{ .FrontendControlPlanePort }}
+  {{ if .IPv6 -}}
+  bind :::{{ .FrontendControlPlanePort }};
+  {{- end }}
+  default_backend kube-apiservers
+
+backend kube-apiservers
+  option httpchk GET /healthz
+  # close connections to a server going down, like when the virtual IP moves to another node
+  default-server on-marked-down shutdown-sessions
+  {{range $server, $address := .BackendServers}}
+  server {{ $server }} {{ JoinHostPort $address $.BackendControlPlanePort }} backup check inter {{ $.HealthCheckInterval }} check-ssl verify none resolvers docker resolve-prefer {{ if $.IPv6 -}} ipv6 {{- else -}} ipv4 {{- end }}
   {{- end}}
 `
 
+// NginxTemplate is the nginx loadbalancer config template.
+// NOTE: nginx only supports passive health checks, a server is considered unavailable for HealthCheckInterval after a failed connection.
+const NginxTemplate = `# generated by CAPD
+worker_processes auto;
+
+events {
+  worker_connections 1024;
+}
+
+stream {
+  upstream kube-apiservers {
+    {{- range $server, $address := .BackendServers }}
+    server {{ JoinHostPort $address $.BackendControlPlanePort }} max_fails=1 fail_timeout={{ $.HealthCheckInterval }}ms;
+    {{- else }}
+    # nginx requires at least one server
+    server 127.0.0.1:{{ .BackendControlPlanePort }} down;
+    {{- end }}
+  }
+
+  server {
+    listen {{ .FrontendControlPlanePort }};
+    {{- if .IPv6 }}
+    listen [::]:{{ .FrontendControlPlanePort }};
+    {{- end }}
+    proxy_connect_timeout {{ .ConnectTimeout }}ms;
+    proxy_timeout {{ .ServerTimeout }}ms;
+    proxy_pass kube-apiservers;
+  }
+}
+`
+
 // Config generates the loadbalancer config from the ConfigTemplate and ConfigData.
 func Config(data *ConfigData, configTemplate string) (config string, err error) {
+	data = withDefaults(data)
+
 	t, err := template.New("loadbalancer-config").Funcs(template.FuncMap{
 		"JoinHostPort": net.JoinHostPort,
 	}).Parse(configTemplate)
@@ -87,3 +164,21 @@ func Config(data *ConfigData, configTemplate string) (config string, err error)
 	}
 	return buff.String(), nil
 }
+
+// withDefaults returns a copy of the ConfigData with defaults applied to the unset timeouts.
+func withDefaults(data *ConfigData) *ConfigData {
+	d := *data
+	if d.ConnectTimeout == 0 {
+		d.ConnectTimeout = DefaultConnectTimeout
+	}
+	if d.ClientTimeout == 0 {
+		d.ClientTimeout = DefaultClientTimeout
+	}
+	if d.ServerTimeout == 0 {
+		d.ServerTimeout = DefaultServerTimeout
+	}
+	if d.HealthCheckInterval == 0 {
+		d.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+	return &d
+}
@@ -57,7 +57,6 @@ defaults
   log global
   mode tcp
   option dontlognull
-  # TODO: tune these
   timeout connect 5000
   timeout client 50000
   timeout server 50000
@@ -73,7 +72,7 @@ backend kube-apiservers
   option httpchk GET /healthz
   # TODO: we should be verifying (!)
   
-  server control-plane-0 1.1.1.1:6443 check check-ssl verify none resolvers docker resolve-prefer ipv4
+  server control-plane-0 1.1.1.1:6443 check inter 2000 check-ssl verify none resolvers docker resolve-prefer ipv4
 `,
 		},
 		{
@@ -178,6 +177,161 @@ backend rke2-servers
   http-check expect status 403
   
   server control-plane-0 1.1.1.1:9345 check check-ssl verify none resolvers docker resolve-prefer ipv4
+`,
+		},
+		{
+			name: "should return a HA proxy config with custom settings",
+			data: &ConfigData{
+				BackendControlPlanePort:  "6443",
+				FrontendControlPlanePort: "7777",
+				BackendServers: map[string]string{
+					"control-plane-0": "1.1.1.1",
+				},
+				ConnectTimeout:      1000,
+				ClientTimeout:       2000,
+				ServerTimeout:       3000,
+				HealthCheckInterval: 500,
+			},
+			configTemplate: DefaultTemplate,
+			expectedConfig: `# generated by kind
+global
+  log /dev/log local0
+  log /dev/log local1 notice
+  daemon
+  # limit memory usage to approximately 18 MB
+  # (see https://github.com/kubernetes-sigs/kind/pull/3115)
+  maxconn 100000
+
+resolvers docker
+  nameserver dns 127.0.0.11:53
+
+defaults
+  log global
+  mode tcp
+  option dontlognull
+  timeout connect 1000
+  timeout client 2000
+  timeout server 3000
+  # allow to boot despite dns don't resolve backends
+  default-server init-addr none
+
+frontend control-plane
+  bind *:7777
+  
+  default_backend kube-apiservers
+
+backend kube-apiservers
+  option httpchk GET /healthz
+  # TODO: we should be verifying (!)
+  
+  server control-plane-0 1.1.1.1:6443 check inter 500 check-ssl verify none resolvers docker resolve-prefer ipv4
+`,
+		},
+		{
+			name: "should return a VIP config",
+			data: &ConfigData{
+				BackendControlPlanePort:  "6443",
+				FrontendControlPlanePort: "7777",
+				BackendServers: map[string]string{
+					"control-plane-0": "1.1.1.1",
+					"control-plane-1": "1.1.1.2",
+				},
+			},
+			configTemplate: VIPTemplate,
+			expectedConfig: `# generated by CAPD
+global
+  log /dev/log local0
+  log /dev/log local1 notice
+  daemon
+  maxconn 100000
+
+resolvers docker
+  nameserver dns 127.0.0.11:53
+
+defaults
+  log global
+  mode tcp
+  option dontlognull
+  timeout connect 5000
+  timeout client 50000
+  timeout server 50000
+  # allow to boot despite dns don't resolve backends
+  default-server init-addr none
+
+frontend control-plane
+  bind *:7777
+  
+  default_backend kube-apiservers
+
+backend kube-apiservers
+  option httpchk GET /healthz
+  # close connections to a server going down, like when the virtual IP moves to another node
+  default-server on-marked-down shutdown-sessions
+  
+  server control-plane-0 1.1.1.1:6443 backup check inter 2000 check-ssl verify none resolvers docker resolve-prefer ipv4
+  server control-plane-1 1.1.1.2:6443 backup check inter 2000 check-ssl verify none resolvers docker resolve-prefer ipv4
+`,
+		},
+		{
+			name: "should return a nginx config",
+			data: &ConfigData{
+				BackendControlPlanePort:  "6443",
+				FrontendControlPlanePort: "7777",
+				BackendServers: map[string]string{
+					"control-plane-0": "1.1.1.1",
+				},
+				IPv6: true,
+			},
+			configTemplate: NginxTemplate,
+			expectedConfig: `# generated by CAPD
+worker_processes auto;
+
+events {
+  worker_connections 1024;
+}
+
+stream {
+  upstream kube-apiservers {
+    server 1.1.1.1:6443 max_fails=1 fail_timeout=2000ms;
+  }
+
+  server {
+    listen 7777;
+    listen [::]:7777;
+    proxy_connect_timeout 5000ms;
+    proxy_timeout 50000ms;
+    proxy_pass kube-apiservers;
+  }
+}
+`,
+		},
+		{
+			name: "should return a nginx config without backend servers",
+			data: &ConfigData{
+				BackendControlPlanePort:  "6443",
+				FrontendControlPlanePort: "7777",
+			},
+			configTemplate: NginxTemplate,
+			expectedConfig: `# generated by CAPD
+worker_processes auto;
+
+events {
+  worker_connections 1024;
+}
+
+stream {
+  upstream kube-apiservers {
+    # nginx requires at least one server
+    server 127.0.0.1:6443 down;
+  }
+
+  server {
+    listen 7777;
+    proxy_connect_timeout 5000ms;
+    proxy_timeout 50000ms;
+    proxy_pass kube-apiservers;
+  }
+}
 `,
 		},
 	}
@@ -29,3 +29,25 @@ const (
 	// ConfigPath is the path to the config file in the image.
 	ConfigPath = "/usr/local/etc/haproxy/haproxy.cfg"
 )
+
+const (
+	// NginxImage is the nginx loadbalancer image name.
+	NginxImage = "nginx"
+	// NginxDefaultImageRepository is the nginx loadbalancer image repository.
+	NginxDefaultImageRepository = "docker.io/library"
+	// NginxDefaultImageTag is the nginx loadbalancer image tag.
+	NginxDefaultImageTag = "1.25.2-alpine"
+	// NginxConfigPath is the path to the nginx config file in the image.
+	NginxConfigPath = "/etc/nginx/nginx.conf"
+)
+
+const (
+	// DefaultConnectTimeout is the default timeout for connecting to a backend server, in milliseconds.
+	DefaultConnectTimeout = 5000
+	// DefaultClientTimeout is the default client inactivity timeout, in milliseconds.
+	DefaultClientTimeout = 50000
+	// DefaultServerTimeout is the default backend server inactivity timeout, in milliseconds.
+	DefaultServerTimeout = 50000
+	// DefaultHealthCheckInterval is the default interval between health checks, in milliseconds.
+	DefaultHealthCheckInterval = 2000
+)
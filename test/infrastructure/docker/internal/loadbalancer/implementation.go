@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+// Implementation defines how to run and configure a load balancer implementation.
+type Implementation interface {
+	// Image returns the image to be used for the load balancer container; the default image repository
+	// and tag are used if imageRepository or imageTag are empty.
+	Image(imageRepository, imageTag string) string
+
+	// Entrypoint returns the entrypoint used to start the load balancer container.
+	Entrypoint() []string
+
+	// ConfigPath returns the path to the config file in the load balancer container.
+	ConfigPath() string
+
+	// DefaultTemplate returns the default config template.
+	DefaultTemplate() string
+
+	// ReloadSignal returns the signal to be sent to the load balancer container for reloading the config file.
+	ReloadSignal() string
+}
+
+// ImplementationFor returns the Implementation for a load balancer type; if the type is empty, haproxy is used.
+func ImplementationFor(lbType infrav1.DockerLoadBalancerType) (Implementation, error) {
+	switch lbType {
+	case "", infrav1.HAProxyLoadBalancerType:
+		return &haproxy{}, nil
+	case infrav1.NginxLoadBalancerType:
+		return &nginx{}, nil
+	case infrav1.VIPLoadBalancerType:
+		return &vip{}, nil
+	default:
+		return nil, errors.Errorf("unknown load balancer type %q", lbType)
+	}
+}
+
+// haproxy is the load balancer implementation based on HAProxy.
+type haproxy struct{}
+
+func (h *haproxy) Image(imageRepository, imageTag string) string {
+	return image(imageRepository, DefaultImageRepository, Image, imageTag, DefaultImageTag)
+}
+
+func (h *haproxy) Entrypoint() []string {
+	return []string{"haproxy", "-W", "-db", "-f", ConfigPath}
+}
+
+func (h *haproxy) ConfigPath() string {
+	return ConfigPath
+}
+
+func (h *haproxy) DefaultTemplate() string {
+	return DefaultTemplate
+}
+
+func (h *haproxy) ReloadSignal() string {
+	return "SIGHUP"
+}
+
+// vip is the load balancer implementation simulating a kube-vip style virtual IP using HAProxy.
+type vip struct {
+	haproxy
+}
+
+func (v *vip) DefaultTemplate() string {
+	return VIPTemplate
+}
+
+// nginx is the load balancer implementation based on nginx.
+type nginx struct{}
+
+func (n *nginx) Image(imageRepository, imageTag string) string {
+	return image(imageRepository, NginxDefaultImageRepository, NginxImage, imageTag, NginxDefaultImageTag)
+}
+
+func (n *nginx) Entrypoint() []string {
+	return []string{"nginx", "-g", "daemon off;", "-c", NginxConfigPath}
+}
+
+func (n *nginx) ConfigPath() string {
+	return NginxConfigPath
+}
+
+func (n *nginx) DefaultTemplate() string {
+	return NginxTemplate
+}
+
+func (n *nginx) ReloadSignal() string {
+	return "SIGHUP"
+}
+
+func image(imageRepository, defaultImageRepository, name, imageTag, defaultImageTag string) string {
+	if imageRepository == "" {
+		imageRepository = defaultImageRepository
+	}
+	if imageTag == "" {
+		imageTag = defaultImageTag
+	}
+	return fmt.Sprintf("%s/%s:%s", imageRepository, name, imageTag)
+}
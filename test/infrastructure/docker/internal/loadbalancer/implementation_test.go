@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+func TestImplementationFor(t *testing.T) {
+	testCases := []struct {
+		name            string
+		lbType          infrav1.DockerLoadBalancerType
+		imageRepository string
+		imageTag        string
+		expectedImage   string
+		expectedConfig  string
+		expectedErr     bool
+	}{
+		{
+			name:           "should default to haproxy",
+			expectedImage:  "kindest/haproxy:" + DefaultImageTag,
+			expectedConfig: ConfigPath,
+		},
+		{
+			name:            "should return haproxy with a custom image",
+			lbType:          infrav1.HAProxyLoadBalancerType,
+			imageRepository: "my-registry",
+			imageTag:        "v1",
+			expectedImage:   "my-registry/haproxy:v1",
+			expectedConfig:  ConfigPath,
+		},
+		{
+			name:           "should return nginx",
+			lbType:         infrav1.NginxLoadBalancerType,
+			expectedImage:  "docker.io/library/nginx:" + NginxDefaultImageTag,
+			expectedConfig: NginxConfigPath,
+		},
+		{
+			name:           "should return vip",
+			lbType:         infrav1.VIPLoadBalancerType,
+			expectedImage:  "kindest/haproxy:" + DefaultImageTag,
+			expectedConfig: ConfigPath,
+		},
+		{
+			name:        "should fail for an unknown type",
+			lbType:      "unknown",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			implementation, err := ImplementationFor(tc.lbType)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(implementation.Image(tc.imageRepository, tc.imageTag)).To(Equal(tc.expectedImage))
+			g.Expect(implementation.ConfigPath()).To(Equal(tc.expectedConfig))
+			g.Expect(implementation.Entrypoint()).ToNot(BeEmpty())
+		})
+	}
+}
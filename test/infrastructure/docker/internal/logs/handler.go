@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logs implements an http.Handler serving the logs of the containers hosting DockerMachines.
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/docker"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+const (
+	// Path is the path the Handler must be registered at.
+	Path = "/logs/"
+
+	machinesPath = Path + "machines/"
+)
+
+// Source identifies which logs to read from the container hosting a DockerMachine.
+type Source string
+
+const (
+	// ConsoleSource reads the output of the container, i.e. the console of the node; this is the default.
+	ConsoleSource Source = "console"
+
+	// KubeletSource reads the journal of the kubelet systemd unit.
+	KubeletSource Source = "kubelet"
+
+	// JournalSource reads the whole journal of the node, e.g. containerd, kubelet and the bootstrap services.
+	JournalSource Source = "journal"
+)
+
+// MachineURL returns the URL where the logs for a DockerMachine are served.
+// If baseURL is empty, the URL is relative to the diagnostics endpoint of the CAPD manager.
+func MachineURL(baseURL string, dockerMachine *infrav1.DockerMachine) string {
+	return fmt.Sprintf("%s%s%s/%s", strings.TrimSuffix(baseURL, "/"), machinesPath, dockerMachine.Namespace, dockerMachine.Name)
+}
+
+// Handler serves the logs of the containers hosting DockerMachines at Path/machines/<namespace>/<name>;
+// the source query parameter selects which logs to read, see Source.
+type Handler struct {
+	Client           client.Client
+	ContainerRuntime container.Runtime
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, fmt.Sprintf("method %s is not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	key, ok := parseMachinePath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	source := Source(r.URL.Query().Get("source"))
+	switch source {
+	case "":
+		source = ConsoleSource
+	case ConsoleSource, KubeletSource, JournalSource:
+	default:
+		http.Error(w, fmt.Sprintf("invalid source %q, must be one of %q, %q or %q", source, ConsoleSource, KubeletSource, JournalSource), http.StatusBadRequest)
+		return
+	}
+
+	ctx := container.RuntimeInto(r.Context(), h.ContainerRuntime)
+	log := ctrl.LoggerFrom(ctx).WithValues("DockerMachine", key, "source", source)
+
+	externalMachine, err := h.getMachine(ctx, key)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Error(err, "Failed to get the container hosting the DockerMachine")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := writeLogs(ctx, externalMachine, source, w); err != nil {
+		// NOTE: The response status cannot be changed once the logs started streaming, so we can only log the error.
+		log.Error(err, "Failed to write logs")
+	}
+}
+
+// parseMachinePath returns the key of the DockerMachine addressed by path.
+func parseMachinePath(path string) (client.ObjectKey, bool) {
+	if !strings.HasPrefix(path, machinesPath) {
+		return client.ObjectKey{}, false
+	}
+	parts := strings.Split(strings.TrimPrefix(path, machinesPath), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return client.ObjectKey{}, false
+	}
+	return client.ObjectKey{Namespace: parts[0], Name: parts[1]}, true
+}
+
+// getMachine returns the docker.Machine for the container hosting a DockerMachine.
+func (h *Handler) getMachine(ctx context.Context, key client.ObjectKey) (*docker.Machine, error) {
+	dockerMachine := &infrav1.DockerMachine{}
+	if err := h.Client.Get(ctx, key, dockerMachine); err != nil {
+		return nil, err
+	}
+
+	machine, err := util.GetOwnerMachine(ctx, h.Client, dockerMachine.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+	if machine == nil {
+		return nil, apierrors.NewNotFound(clusterv1.GroupVersion.WithResource("machines").GroupResource(), fmt.Sprintf("owner of DockerMachine %s", key))
+	}
+
+	cluster, err := util.GetClusterFromMetadata(ctx, h.Client, machine.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: DockerMachines belonging to a DockerMachinePool are backed by a container identified by instanceName.
+	instanceName := machine.Name
+	if dockerMachine.Spec.InstanceName != "" {
+		instanceName = dockerMachine.Spec.InstanceName
+	}
+	externalMachine, err := docker.NewMachine(ctx, cluster, instanceName, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !externalMachine.Exists() {
+		return nil, apierrors.NewNotFound(infrav1.GroupVersion.WithResource("dockermachines").GroupResource(), fmt.Sprintf("container hosting DockerMachine %s", key))
+	}
+	return externalMachine, nil
+}
+
+func writeLogs(ctx context.Context, externalMachine *docker.Machine, source Source, w io.Writer) error {
+	switch source {
+	case KubeletSource:
+		return externalMachine.JournalLogs(ctx, "kubelet.service", w)
+	case JournalSource:
+		return externalMachine.JournalLogs(ctx, "", w)
+	default:
+		return externalMachine.ConsoleLogs(ctx, w)
+	}
+}
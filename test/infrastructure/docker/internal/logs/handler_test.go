@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+func TestMachineURL(t *testing.T) {
+	g := NewWithT(t)
+
+	dockerMachine := &infrav1.DockerMachine{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "foo"}}
+	g.Expect(MachineURL("", dockerMachine)).To(Equal("/logs/machines/ns/foo"))
+	g.Expect(MachineURL("https://capd.example.com:8443/", dockerMachine)).To(Equal("https://capd.example.com:8443/logs/machines/ns/foo"))
+}
+
+func TestHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"},
+	}
+	machine := &clusterv1.Machine{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Machine",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "machine",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: cluster.Name},
+		},
+	}
+	dockerMachine := &infrav1.DockerMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "docker-machine",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Machine", Name: machine.Name},
+			},
+		},
+	}
+	orphanDockerMachine := &infrav1.DockerMachine{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "orphan"},
+	}
+
+	h := &Handler{
+		Client:           fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, machine, dockerMachine, orphanDockerMachine).Build(),
+		ContainerRuntime: &container.FakeRuntime{},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+	}{
+		{
+			name:       "Only GET is allowed",
+			method:     http.MethodPost,
+			target:     "/logs/machines/ns/docker-machine",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "Path without a DockerMachine",
+			method:     http.MethodGet,
+			target:     "/logs/machines/ns",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Path with too many segments",
+			method:     http.MethodGet,
+			target:     "/logs/machines/ns/docker-machine/foo",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid source",
+			method:     http.MethodGet,
+			target:     "/logs/machines/ns/docker-machine?source=foo",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "DockerMachine does not exist",
+			method:     http.MethodGet,
+			target:     "/logs/machines/ns/does-not-exist",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "DockerMachine without an owner Machine",
+			method:     http.MethodGet,
+			target:     "/logs/machines/ns/orphan",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Container hosting the DockerMachine does not exist",
+			method:     http.MethodGet,
+			target:     "/logs/machines/ns/docker-machine?source=kubelet",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, http.NoBody))
+			g.Expect(rec.Code).To(Equal(tt.wantStatus), rec.Body.String())
+		})
+	}
+}
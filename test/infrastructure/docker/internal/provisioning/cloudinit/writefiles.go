@@ -27,6 +27,7 @@ import (
 
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
@@ -85,14 +86,21 @@ func (a *writeFilesAction) Unmarshal(userData []byte, kindMapping kind.Mapping)
 			if len(contentSplit) != 3 {
 				return errors.Errorf("invalid kubeadm config file, unable to parse it")
 			}
+			clusterConfiguration, err := kubeadmtypes.UnmarshalClusterConfiguration(contentSplit[1])
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse cluster configuration")
+			}
 			initConfiguration, err := kubeadmtypes.UnmarshalInitConfiguration(contentSplit[2])
 			if err != nil {
 				return errors.Wrapf(err, "failed to parse init configuration")
 			}
+			if err := setTimeoutForControlPlane(clusterConfiguration, contentSplit[2]); err != nil {
+				return errors.Wrapf(err, "failed to parse init configuration")
+			}
 
 			fixNodeRegistration(&initConfiguration.NodeRegistration, kindMapping)
 
-			contentSplit[2], err = kubeadmtypes.MarshalInitConfigurationForVersion(initConfiguration, kindMapping.KubernetesVersion)
+			contentSplit[2], err = kubeadmtypes.MarshalInitConfigurationForVersion(clusterConfiguration, initConfiguration, kindMapping.KubernetesVersion)
 			if err != nil {
 				return errors.Wrapf(err, "failed to marshal init configuration")
 			}
@@ -100,14 +108,19 @@ func (a *writeFilesAction) Unmarshal(userData []byte, kindMapping kind.Mapping)
 		}
 		if f.Path == kubeadmJoinPath {
 			// NOTE: in case of join the kubeadmConfigFile contains only the join Configuration
+			// NOTE: the ClusterConfiguration is used only to preserve the timeout for the control plane.
 			joinConfiguration, err := kubeadmtypes.UnmarshalJoinConfiguration(f.Content)
 			if err != nil {
 				return errors.Wrapf(err, "failed to parse join configuration")
 			}
+			clusterConfiguration := &bootstrapv1.ClusterConfiguration{}
+			if err := setTimeoutForControlPlane(clusterConfiguration, f.Content); err != nil {
+				return errors.Wrapf(err, "failed to parse join configuration")
+			}
 
 			fixNodeRegistration(&joinConfiguration.NodeRegistration, kindMapping)
 
-			a.Files[i].Content, err = kubeadmtypes.MarshalJoinConfigurationForVersion(joinConfiguration, kindMapping.KubernetesVersion)
+			a.Files[i].Content, err = kubeadmtypes.MarshalJoinConfigurationForVersion(clusterConfiguration, joinConfiguration, kindMapping.KubernetesVersion)
 			if err != nil {
 				return errors.Wrapf(err, "failed to marshal join configuration")
 			}
@@ -116,6 +129,28 @@ func (a *writeFilesAction) Unmarshal(userData []byte, kindMapping kind.Mapping)
 	return nil
 }
 
+// setTimeoutForControlPlane sets the timeout for the control plane in the ClusterConfiguration from an InitConfiguration
+// or JoinConfiguration using the kubeadm v1beta4 API, where the timeout has been moved to timeouts.controlPlaneComponentHealthCheck;
+// this is required because the Cluster API types do not have this field, so it would be lost when marshaling the configuration again.
+func setTimeoutForControlPlane(clusterConfiguration *bootstrapv1.ClusterConfiguration, content string) error {
+	if clusterConfiguration.APIServer.TimeoutForControlPlane != nil {
+		return nil
+	}
+
+	configuration := struct {
+		Timeouts *struct {
+			ControlPlaneComponentHealthCheck *metav1.Duration `json:"controlPlaneComponentHealthCheck,omitempty"`
+		} `json:"timeouts,omitempty"`
+	}{}
+	if err := yaml.Unmarshal([]byte(content), &configuration); err != nil {
+		return err
+	}
+	if configuration.Timeouts != nil {
+		clusterConfiguration.APIServer.TimeoutForControlPlane = configuration.Timeouts.ControlPlaneComponentHealthCheck
+	}
+	return nil
+}
+
 // fixNodeRegistration sets node registration for running Kubernetes/kubelet in docker.
 // NOTE: we add those values if they do not exists; user can set those flags to different values to disable automatic fixing.
 // NOTE: if there will be use case for it, we might investigate better ways to disable automatic fixing.
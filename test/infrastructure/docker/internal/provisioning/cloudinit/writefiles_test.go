@@ -114,7 +114,8 @@ func TestFixKubeletArgs(t *testing.T) {
 write_files:
 - content: |
     ---
-    ClusterConfiguration...
+    apiVersion: kubeadm.k8s.io/v1beta3
+    kind: ClusterConfiguration
     ---
     apiVersion: kubeadm.k8s.io/v1beta3
     kind: InitConfiguration
@@ -140,7 +141,8 @@ write_files:
 			mapping: kind.Mapping{KubernetesVersion: semver.MustParse("1.28.3"), Mode: kind.Mode0_19},
 			expectedContent: []string{
 				`---
-ClusterConfiguration...
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
 ---
 apiVersion: kubeadm.k8s.io/v1beta3
 kind: InitConfiguration
@@ -172,7 +174,8 @@ nodeRegistration:
 write_files:
 - content: |
     ---
-    ClusterConfiguration...
+    apiVersion: kubeadm.k8s.io/v1beta3
+    kind: ClusterConfiguration
     ---
     apiVersion: kubeadm.k8s.io/v1beta3
     kind: InitConfiguration
@@ -198,7 +201,8 @@ write_files:
 			mapping: kind.Mapping{KubernetesVersion: semver.MustParse("1.28.3"), Mode: kind.Mode0_20},
 			expectedContent: []string{
 				`---
-ClusterConfiguration...
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
 ---
 apiVersion: kubeadm.k8s.io/v1beta3
 kind: InitConfiguration
@@ -234,7 +238,8 @@ nodeRegistration:
 write_files:
 - content: |
     ---
-    ClusterConfiguration...
+    apiVersion: kubeadm.k8s.io/v1beta3
+    kind: ClusterConfiguration
     ---
     apiVersion: kubeadm.k8s.io/v1beta3
     kind: InitConfiguration
@@ -260,7 +265,8 @@ write_files:
 			mapping: kind.Mapping{KubernetesVersion: semver.MustParse("1.23.3"), Mode: kind.Mode0_19},
 			expectedContent: []string{
 				`---
-ClusterConfiguration...
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
 ---
 apiVersion: kubeadm.k8s.io/v1beta3
 kind: InitConfiguration
@@ -294,7 +300,8 @@ nodeRegistration:
 write_files:
 - content: |
     ---
-    ClusterConfiguration...
+    apiVersion: kubeadm.k8s.io/v1beta3
+    kind: ClusterConfiguration
     ---
     apiVersion: kubeadm.k8s.io/v1beta3
     kind: InitConfiguration
@@ -320,7 +327,8 @@ write_files:
 			mapping: kind.Mapping{KubernetesVersion: semver.MustParse("1.23.3"), Mode: kind.Mode0_20},
 			expectedContent: []string{
 				`---
-ClusterConfiguration...
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
 ---
 apiVersion: kubeadm.k8s.io/v1beta3
 kind: InitConfiguration
@@ -369,6 +377,43 @@ nodeRegistration:
 	}
 }
 
+func TestFixKubeletArgsPreservesTimeoutForControlPlane(t *testing.T) {
+	g := NewWithT(t)
+
+	files := []byte(`
+write_files:
+- content: |
+    ---
+    apiVersion: kubeadm.k8s.io/v1beta4
+    kind: ClusterConfiguration
+    ---
+    apiVersion: kubeadm.k8s.io/v1beta4
+    kind: InitConfiguration
+    timeouts:
+      controlPlaneComponentHealthCheck: 10m0s
+  owner: root:root
+  path: /run/kubeadm/kubeadm.yaml
+  permissions: '0640'
+- content: |
+    ---
+    apiVersion: kubeadm.k8s.io/v1beta4
+    kind: JoinConfiguration
+    timeouts:
+      controlPlaneComponentHealthCheck: 10m0s
+  path: /run/kubeadm/kubeadm-join-config.yaml
+  owner: root:root
+  permissions: '0640'
+`)
+
+	w := writeFilesAction{}
+	g.Expect(w.Unmarshal(files, kind.Mapping{KubernetesVersion: semver.MustParse("1.31.0"), Mode: kind.Mode0_20})).To(Succeed())
+
+	g.Expect(w.Files[0].Content).To(ContainSubstring("kind: InitConfiguration"))
+	g.Expect(w.Files[0].Content).To(ContainSubstring("controlPlaneComponentHealthCheck: 10m0s"))
+	g.Expect(w.Files[1].Content).To(ContainSubstring("kind: JoinConfiguration"))
+	g.Expect(w.Files[1].Content).To(ContainSubstring("controlPlaneComponentHealthCheck: 10m0s"))
+}
+
 func TestFixContent(t *testing.T) {
 	v := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
 	gv, _ := gZipData([]byte(v))
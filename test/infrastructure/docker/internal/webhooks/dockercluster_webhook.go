@@ -19,6 +19,8 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"net"
+	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -65,14 +67,33 @@ func (webhook *DockerCluster) ValidateCreate(_ context.Context, obj runtime.Obje
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a DockerCluster but got a %T", obj))
 	}
-	if allErrs := validateDockerClusterSpec(cluster.Spec); len(allErrs) > 0 {
+	if allErrs := validateDockerClusterSpec(cluster.Spec, field.NewPath("spec")); len(allErrs) > 0 {
 		return nil, apierrors.NewInvalid(infrav1.GroupVersion.WithKind("DockerCluster").GroupKind(), cluster.Name, allErrs)
 	}
 	return nil, nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *DockerCluster) ValidateUpdate(_ context.Context, _, _ runtime.Object) (admission.Warnings, error) {
+func (webhook *DockerCluster) ValidateUpdate(_ context.Context, oldRaw, newRaw runtime.Object) (admission.Warnings, error) {
+	oldCluster, ok := oldRaw.(*infrav1.DockerCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a DockerCluster but got a %T", oldRaw))
+	}
+	newCluster, ok := newRaw.(*infrav1.DockerCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a DockerCluster but got a %T", newRaw))
+	}
+
+	allErrs := validateDockerClusterSpec(newCluster.Spec, field.NewPath("spec"))
+	if newCluster.Spec.LoadBalancer.Type != oldCluster.Spec.LoadBalancer.Type {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "loadBalancer", "type"), newCluster.Spec.LoadBalancer.Type, "field is immutable"))
+	}
+	if !reflect.DeepEqual(newCluster.Spec.Network, oldCluster.Spec.Network) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "network"), newCluster.Spec.Network, "field is immutable"))
+	}
+	if len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(infrav1.GroupVersion.WithKind("DockerCluster").GroupKind(), newCluster.Name, allErrs)
+	}
 	return nil, nil
 }
 
@@ -87,6 +108,39 @@ func defaultDockerClusterSpec(s *infrav1.DockerClusterSpec) {
 	}
 }
 
-func validateDockerClusterSpec(_ infrav1.DockerClusterSpec) field.ErrorList {
-	return nil
+func validateDockerClusterSpec(s infrav1.DockerClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if s.LoadBalancer.Type == infrav1.NginxLoadBalancerType && s.LoadBalancer.CustomHAProxyConfigTemplateRef != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("loadBalancer", "customHAProxyConfigTemplateRef"), "cannot be set when using the nginx load balancer"))
+	}
+
+	if s.Network != nil {
+		allErrs = append(allErrs, validateDockerClusterNetwork(s.Network, fldPath.Child("network"))...)
+	}
+
+	return allErrs
+}
+
+func validateDockerClusterNetwork(n *infrav1.DockerClusterNetwork, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	var ipv4, ipv6 int
+	for i, cidr := range n.CIDRBlocks {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("cidrBlocks").Index(i), cidr, "must be a valid CIDR block"))
+			continue
+		}
+		if ip.To4() != nil {
+			ipv4++
+		} else {
+			ipv6++
+		}
+	}
+	if ipv4 > 1 || ipv6 > 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("cidrBlocks"), n.CIDRBlocks, "must contain at most one IPv4 and one IPv6 CIDR block"))
+	}
+
+	return allErrs
 }
@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+func TestDockerClusterValidationLoadBalancer(t *testing.T) {
+	dockerCluster := func(lbType infrav1.DockerLoadBalancerType, customConfig bool) *infrav1.DockerCluster {
+		dc := &infrav1.DockerCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dockercluster-test",
+				Namespace: "test-namespace",
+			},
+			Spec: infrav1.DockerClusterSpec{
+				LoadBalancer: infrav1.DockerLoadBalancer{
+					Type: lbType,
+				},
+			},
+		}
+		if customConfig {
+			dc.Spec.LoadBalancer.CustomHAProxyConfigTemplateRef = &corev1.LocalObjectReference{Name: "custom-config"}
+		}
+		return dc
+	}
+
+	t.Run("create should pass with a custom HAProxy config and the haproxy load balancer", func(t *testing.T) {
+		g := NewWithT(t)
+		webhook := DockerCluster{}
+		_, err := webhook.ValidateCreate(ctx, dockerCluster(infrav1.HAProxyLoadBalancerType, true))
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("create should fail with a custom HAProxy config and the nginx load balancer", func(t *testing.T) {
+		g := NewWithT(t)
+		webhook := DockerCluster{}
+		_, err := webhook.ValidateCreate(ctx, dockerCluster(infrav1.NginxLoadBalancerType, true))
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("update should fail when changing the load balancer type", func(t *testing.T) {
+		g := NewWithT(t)
+		webhook := DockerCluster{}
+		_, err := webhook.ValidateUpdate(ctx, dockerCluster("", false), dockerCluster(infrav1.VIPLoadBalancerType, false))
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("update should pass when the load balancer type does not change", func(t *testing.T) {
+		g := NewWithT(t)
+		webhook := DockerCluster{}
+		_, err := webhook.ValidateUpdate(ctx, dockerCluster(infrav1.VIPLoadBalancerType, false), dockerCluster(infrav1.VIPLoadBalancerType, true))
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+}
+
+func TestDockerClusterValidationNetwork(t *testing.T) {
+	dockerCluster := func(network *infrav1.DockerClusterNetwork) *infrav1.DockerCluster {
+		return &infrav1.DockerCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dockercluster-test",
+				Namespace: "test-namespace",
+			},
+			Spec: infrav1.DockerClusterSpec{
+				Network: network,
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		network   *infrav1.DockerClusterNetwork
+		expectErr bool
+	}{
+		{
+			name:    "no network",
+			network: nil,
+		},
+		{
+			name:    "network without CIDR blocks",
+			network: &infrav1.DockerClusterNetwork{Isolated: true},
+		},
+		{
+			name:    "dual-stack network",
+			network: &infrav1.DockerClusterNetwork{CIDRBlocks: []string{"172.30.0.0/16", "fd00:30::/64"}},
+		},
+		{
+			name:      "invalid CIDR block",
+			network:   &infrav1.DockerClusterNetwork{CIDRBlocks: []string{"172.30.0.0"}},
+			expectErr: true,
+		},
+		{
+			name:      "two IPv4 CIDR blocks",
+			network:   &infrav1.DockerClusterNetwork{CIDRBlocks: []string{"172.30.0.0/16", "172.31.0.0/16"}},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			webhook := DockerCluster{}
+			_, err := webhook.ValidateCreate(ctx, dockerCluster(tt.network))
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+
+	t.Run("update should fail when changing the network", func(t *testing.T) {
+		g := NewWithT(t)
+		webhook := DockerCluster{}
+		_, err := webhook.ValidateUpdate(ctx, dockerCluster(nil), dockerCluster(&infrav1.DockerClusterNetwork{}))
+		g.Expect(err).To(HaveOccurred())
+	})
+}
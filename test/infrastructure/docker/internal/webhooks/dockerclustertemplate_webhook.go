@@ -79,7 +79,7 @@ func (webhook *DockerClusterTemplate) ValidateCreate(_ context.Context, obj runt
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a DockerClusterTemplate but got a %T", obj))
 	}
 
-	allErrs := validateDockerClusterSpec(clusterTemplate.Spec.Template.Spec)
+	allErrs := validateDockerClusterSpec(clusterTemplate.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))
 
 	// Validate the metadata of the template.
 	allErrs = append(allErrs, clusterTemplate.Spec.Template.ObjectMeta.Validate(field.NewPath("spec", "template", "metadata"))...)
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+func (webhook *DockerMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&infrav1.DockerMachine{}).
+		WithValidator(webhook).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-dockermachine,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=dockermachines,versions=v1beta1,name=validation.dockermachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// DockerMachine implements a custom validation webhook for DockerMachine.
+// +kubebuilder:object:generate=false
+type DockerMachine struct{}
+
+var _ webhook.CustomValidator = &DockerMachine{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *DockerMachine) ValidateCreate(_ context.Context, raw runtime.Object) (admission.Warnings, error) {
+	if _, ok := raw.(*infrav1.DockerMachine); !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a DockerMachine but got a %T", raw))
+	}
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *DockerMachine) ValidateUpdate(_ context.Context, oldRaw runtime.Object, newRaw runtime.Object) (admission.Warnings, error) {
+	newObj, ok := newRaw.(*infrav1.DockerMachine)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a DockerMachine but got a %T", newRaw))
+	}
+	oldObj, ok := oldRaw.(*infrav1.DockerMachine)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a DockerMachine but got a %T", oldRaw))
+	}
+
+	var allErrs field.ErrorList
+	// NOTE: The SSH port of the machine is published when the container is created, so SSH access cannot be changed later.
+	if !reflect.DeepEqual(newObj.Spec.SSH, oldObj.Spec.SSH) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "ssh"), "field is immutable"))
+	}
+
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(infrav1.GroupVersion.WithKind("DockerMachine").GroupKind(), newObj.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (webhook *DockerMachine) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
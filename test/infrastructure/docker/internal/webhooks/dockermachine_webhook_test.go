@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+func TestDockerMachineValidateUpdate(t *testing.T) {
+	ssh := &infrav1.DockerMachineSSH{AuthorizedKeys: []string{"ssh-ed25519 AAAA... user@example.com"}}
+
+	tests := []struct {
+		name    string
+		oldSSH  *infrav1.DockerMachineSSH
+		newSSH  *infrav1.DockerMachineSSH
+		wantErr bool
+	}{
+		{
+			name:   "allows unchanged SSH access",
+			oldSSH: ssh,
+			newSSH: ssh.DeepCopy(),
+		},
+		{
+			name:    "rejects enabling SSH access",
+			newSSH:  ssh,
+			wantErr: true,
+		},
+		{
+			name:    "rejects disabling SSH access",
+			oldSSH:  ssh,
+			wantErr: true,
+		},
+		{
+			name:    "rejects changing the authorized keys",
+			oldSSH:  ssh,
+			newSSH:  &infrav1.DockerMachineSSH{AuthorizedKeys: []string{"ssh-ed25519 BBBB... user@example.com"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			oldMachine := &infrav1.DockerMachine{Spec: infrav1.DockerMachineSpec{SSH: tt.oldSSH}}
+			newMachine := &infrav1.DockerMachine{Spec: infrav1.DockerMachineSpec{SSH: tt.newSSH}}
+
+			wh := &DockerMachine{}
+			_, err := wh.ValidateUpdate(context.Background(), oldMachine, newMachine)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
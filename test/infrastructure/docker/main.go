@@ -21,6 +21,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	goruntime "runtime"
 	"time"
@@ -56,6 +57,7 @@ import (
 	infraexpv1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
 	expcontrollers "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/controllers"
 	infraexpwebhooks "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/webhooks"
+	machinelogs "sigs.k8s.io/cluster-api/test/infrastructure/docker/internal/logs"
 	infrawebhooks "sigs.k8s.io/cluster-api/test/infrastructure/docker/webhooks"
 	"sigs.k8s.io/cluster-api/util/flags"
 	"sigs.k8s.io/cluster-api/version"
@@ -87,6 +89,9 @@ var (
 	// CAPD specific flags.
 	concurrency                    int
 	clusterCacheTrackerConcurrency int
+	containerRuntime               string
+	consoleLogsBaseURL             string
+	containerHealthCheckInterval   time.Duration
 )
 
 func init() {
@@ -151,6 +156,16 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
 
+	fs.StringVar(&containerRuntime, "container-runtime", "",
+		fmt.Sprintf("The container runtime used for running machines, one of %q or %q. If not set, the value of the %s environment variable is used, defaulting to %q.",
+			container.DockerRuntime, container.PodmanRuntime, container.RuntimeEnvVar, container.DockerRuntime))
+
+	fs.StringVar(&consoleLogsBaseURL, "console-logs-base-url", "",
+		"The base URL of the diagnostics endpoint used in DockerMachine.Status.ConsoleLogsURL, e.g. https://capd.example.com:8443. If not set, the URL is relative to the diagnostics endpoint.")
+
+	fs.DurationVar(&containerHealthCheckInterval, "container-health-check-interval", 10*time.Second,
+		"The interval at which the state of the containers backing provisioned DockerMachines is checked. Set to 0 to only check it when DockerMachines are reconciled for other reasons.")
+
 	flags.AddDiagnosticsOptions(fs, &diagnosticsOptions)
 	flags.AddTLSOptions(fs, &tlsOptions)
 
@@ -198,6 +213,25 @@ func main() {
 
 	diagnosticsOpts := flags.GetDiagnosticsOptions(diagnosticsOptions)
 
+	// Serve the logs of the machines from the diagnostics endpoint, so they are protected by the same authentication and authorization.
+	// NOTE: The handler uses its own client and runtime client, because it must be registered before the manager is created.
+	logsClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client for the logs endpoint")
+		os.Exit(1)
+	}
+	logsRuntimeClient, err := container.NewRuntimeClient(containerRuntime)
+	if err != nil {
+		setupLog.Error(err, "unable to establish container runtime connection for the logs endpoint")
+		os.Exit(1)
+	}
+	diagnosticsOpts.ExtraHandlers = map[string]http.Handler{
+		machinelogs.Path: &machinelogs.Handler{
+			Client:           logsClient,
+			ContainerRuntime: logsRuntimeClient,
+		},
+	}
+
 	var watchNamespaces map[string]cache.Config
 	if watchNamespace != "" {
 		watchNamespaces = map[string]cache.Config{
@@ -297,7 +331,7 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	}
 
 	// Set our runtime client into the context for later use
-	runtimeClient, err := container.NewDockerClient()
+	runtimeClient, err := container.NewRuntimeClient(containerRuntime)
 	if err != nil {
 		setupLog.Error(err, "unable to establish container runtime connection", "controller", "reconciler")
 		os.Exit(1)
@@ -329,10 +363,12 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	}
 
 	if err := (&controllers.DockerMachineReconciler{
-		Client:           mgr.GetClient(),
-		ContainerRuntime: runtimeClient,
-		Tracker:          tracker,
-		WatchFilterValue: watchFilterValue,
+		Client:                       mgr.GetClient(),
+		ContainerRuntime:             runtimeClient,
+		Tracker:                      tracker,
+		WatchFilterValue:             watchFilterValue,
+		ConsoleLogsBaseURL:           consoleLogsBaseURL,
+		ContainerHealthCheckInterval: containerHealthCheckInterval,
 	}).SetupWithManager(ctx, mgr, controller.Options{
 		MaxConcurrentReconciles: concurrency,
 	}); err != nil {
@@ -349,6 +385,14 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	if err := (&controllers.DockerMachineTemplateReconciler{
+		Client:           mgr.GetClient(),
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, controller.Options{}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DockerMachineTemplate")
+		os.Exit(1)
+	}
+
 	if feature.Gates.Enabled(feature.MachinePool) {
 		if err := (&expcontrollers.DockerMachinePoolReconciler{
 			Client:           mgr.GetClient(),
@@ -363,6 +407,11 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 }
 
 func setupWebhooks(mgr ctrl.Manager) {
+	if err := (&infrawebhooks.DockerMachine{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "DockerMachine")
+		os.Exit(1)
+	}
+
 	if err := (&infrawebhooks.DockerMachineTemplate{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "DockerMachineTemplate")
 		os.Exit(1)
@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defines annotations to be applied to InMemoryMachines and InMemoryClusters in order to inject faults
+// in the fake components hosted on them, e.g. for testing the resilience of core controllers.
+const (
+	// KillFaultAnnotation can be applied to an InMemoryMachine to kill some of the components hosted on it;
+	// the value is a comma separated list of components, e.g. "node,etcd,apiserver".
+	// A killed node stops reporting its status, and it becomes not ready; a killed etcd member or API server
+	// stops serving requests, and the corresponding Pod becomes not ready.
+	// Removing the annotation restarts the killed components.
+	KillFaultAnnotation = "fault.inmemory.infrastructure.cluster.x-k8s.io/kill"
+
+	// HangFaultAnnotation can be applied to an InMemoryMachine to hang some of the components hosted on it;
+	// the value is a comma separated list of components, e.g. "node,etcd,apiserver".
+	// A hanging node stops reporting its status, and it becomes not ready; requests to a hanging etcd member do not
+	// get a response until the client gives up; requests to the API servers of a cluster do not get a response when
+	// all the API servers are hanging.
+	// Removing the annotation resumes the hanging components.
+	HangFaultAnnotation = "fault.inmemory.infrastructure.cluster.x-k8s.io/hang"
+
+	// ProvisioningDelayFaultAnnotation can be applied to an InMemoryMachine to delay the provisioning of the VM
+	// by the given duration, e.g. "5m"; the delay adds up to the provisioning duration defined by the machine behaviour.
+	ProvisioningDelayFaultAnnotation = "fault.inmemory.infrastructure.cluster.x-k8s.io/provisioning-delay"
+
+	// APIServerErrorRateFaultAnnotation can be applied to an InMemoryCluster to fail a fraction of the requests to the
+	// API servers of the workload cluster with a 503 Service Unavailable error; the value is a number in the [0, 1]
+	// interval, e.g. "0.1" to fail 10% of the requests.
+	APIServerErrorRateFaultAnnotation = "fault.inmemory.infrastructure.cluster.x-k8s.io/apiserver-error-rate"
+
+	// APIServerLatencyFaultAnnotation can be applied to an InMemoryCluster to delay all the responses of the
+	// API servers of the workload cluster by the given duration, e.g. "500ms".
+	APIServerLatencyFaultAnnotation = "fault.inmemory.infrastructure.cluster.x-k8s.io/apiserver-latency"
+)
+
+// FaultComponent defines a component of an InMemoryMachine faults can be injected into.
+type FaultComponent string
+
+const (
+	// NodeFaultComponent is the Node (the kubelet) hosted on an InMemoryMachine.
+	NodeFaultComponent FaultComponent = "node"
+
+	// EtcdFaultComponent is the etcd member hosted on an InMemoryMachine.
+	EtcdFaultComponent FaultComponent = "etcd"
+
+	// APIServerFaultComponent is the API server hosted on an InMemoryMachine.
+	APIServerFaultComponent FaultComponent = "apiserver"
+)
+
+// ParseFaultComponents parses the value of the KillFaultAnnotation and of the HangFaultAnnotation.
+func ParseFaultComponents(value string) ([]FaultComponent, error) {
+	var components []FaultComponent
+	for _, c := range strings.Split(value, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		switch component := FaultComponent(c); component {
+		case NodeFaultComponent, EtcdFaultComponent, APIServerFaultComponent:
+			components = append(components, component)
+		default:
+			return nil, errors.Errorf("invalid component %q, must be one of %q, %q or %q", c, NodeFaultComponent, EtcdFaultComponent, APIServerFaultComponent)
+		}
+	}
+	return components, nil
+}
+
+// ParseFaultDuration parses the value of the ProvisioningDelayFaultAnnotation and of the APIServerLatencyFaultAnnotation.
+func ParseFaultDuration(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid duration %q", value)
+	}
+	if d < 0 {
+		return 0, errors.Errorf("invalid duration %q, must not be negative", value)
+	}
+	return d, nil
+}
+
+// ParseFaultRate parses the value of the APIServerErrorRateFaultAnnotation.
+func ParseFaultRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid rate %q", value)
+	}
+	if rate < 0 || rate > 1 {
+		return 0, errors.Errorf("invalid rate %q, must be in the [0, 1] interval", value)
+	}
+	return rate, nil
+}
@@ -36,6 +36,61 @@ type InMemoryClusterSpec struct {
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
 	// +optional
 	ControlPlaneEndpoint APIEndpoint `json:"controlPlaneEndpoint"`
+
+	// Behaviour of the InMemoryCluster; this will allow to make a simulation more alike to real use cases
+	// e.g. by defining the latency of the API servers or by adding synthetic objects to the workload cluster.
+	// +optional
+	Behaviour *InMemoryClusterBehaviour `json:"behaviour,omitempty"`
+}
+
+// InMemoryClusterBehaviour defines the behaviour of the InMemoryCluster.
+type InMemoryClusterBehaviour struct {
+	// APIServer defines the behaviour of the API servers of the workload cluster.
+	// +optional
+	APIServer *InMemoryClusterAPIServerBehaviour `json:"apiServer,omitempty"`
+
+	// Scale defines the synthetic objects to be added to the workload cluster, so it is possible to simulate
+	// workload clusters with the same size of production clusters without provisioning the corresponding machines.
+	// +optional
+	Scale *InMemoryClusterScale `json:"scale,omitempty"`
+}
+
+// InMemoryClusterAPIServerBehaviour defines the behaviour of the API servers of the workload cluster.
+type InMemoryClusterAPIServerBehaviour struct {
+	// Latency defines variables influencing the latency of the requests served by the API servers of the workload cluster.
+	Latency LatencySettings `json:"latency,omitempty"`
+}
+
+// LatencySettings holds parameters that applies to the latency of requests.
+type LatencySettings struct {
+	// Duration defines the latency of each request.
+	Duration metav1.Duration `json:"duration"`
+
+	// Jitter adds some randomness on Duration; the actual latency of each request will be Duration plus an additional
+	// amount chosen at random according to Distribution, e.g. using the interval between zero and `Jitter*Duration`
+	// for the Uniform distribution.
+	// NOTE: this is modeled as string because the usage of float is highly discouraged, as support for them varies across languages.
+	// +optional
+	Jitter string `json:"jitter,omitempty"`
+
+	// Distribution defines the probability distribution of the additional amount added to Duration when Jitter is set;
+	// defaults to Uniform.
+	// +optional
+	Distribution Distribution `json:"distribution,omitempty"`
+}
+
+// InMemoryClusterScale defines the synthetic objects to be added to the workload cluster.
+// NOTE: Synthetic objects are not backed by any InMemoryMachine, and they do not change over time.
+type InMemoryClusterScale struct {
+	// Nodes is the number of synthetic Nodes to be added to the workload cluster.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Nodes int32 `json:"nodes,omitempty"`
+
+	// PodsPerNode is the number of synthetic Pods to be added to the workload cluster for each synthetic Node.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PodsPerNode int32 `json:"podsPerNode,omitempty"`
 }
 
 // InMemoryClusterStatus defines the observed state of the InMemoryCluster.
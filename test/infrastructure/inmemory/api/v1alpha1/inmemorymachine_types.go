@@ -110,8 +110,26 @@ type InMemoryNodeBehaviour struct {
 	// Provisioning defines variables influencing how the Node (the kubelet) hosted on the InMemoryMachine is going to be provisioned.
 	// NOTE: Node provisioning includes all the steps from starting kubelet to the node become ready, get a provider ID, and being registered in K8s.
 	Provisioning CommonProvisioningSettings `json:"provisioning,omitempty"`
+
+	// OperatingSystem is the operating system reported by the Node, e.g. windows to simulate the worker nodes
+	// of a mixed-OS cluster; defaults to linux.
+	// NOTE: Control plane machines must use linux.
+	// +optional
+	OperatingSystem NodeOperatingSystem `json:"operatingSystem,omitempty"`
 }
 
+// NodeOperatingSystem is the operating system reported by a Node.
+// +kubebuilder:validation:Enum=linux;windows
+type NodeOperatingSystem string
+
+const (
+	// LinuxNodeOperatingSystem is a Node running Linux; this is the default.
+	LinuxNodeOperatingSystem NodeOperatingSystem = "linux"
+
+	// WindowsNodeOperatingSystem is a Node running Windows Server.
+	WindowsNodeOperatingSystem NodeOperatingSystem = "windows"
+)
+
 // InMemoryAPIServerBehaviour defines the behaviour of the APIServer hosted on the InMemoryMachine.
 type InMemoryAPIServerBehaviour struct {
 	// Provisioning defines variables influencing how the APIServer hosted on the InMemoryMachine is going to be provisioned.
@@ -132,11 +150,34 @@ type CommonProvisioningSettings struct {
 	StartupDuration metav1.Duration `json:"startupDuration"`
 
 	// StartupJitter adds some randomness on StartupDuration; the actual duration will be StartupDuration plus an additional
-	// amount chosen uniformly at random from the interval between zero and `StartupJitter*StartupDuration`.
+	// amount chosen at random according to StartupDistribution; with the default Uniform distribution, the additional amount
+	// is chosen uniformly at random from the interval between zero and `StartupJitter*StartupDuration`.
 	// NOTE: this is modeled as string because the usage of float is highly discouraged, as support for them varies across languages.
 	StartupJitter string `json:"startupJitter,omitempty"`
+
+	// StartupDistribution defines the probability distribution of the additional amount added to StartupDuration
+	// when StartupJitter is set; defaults to Uniform.
+	// +optional
+	StartupDistribution Distribution `json:"startupDistribution,omitempty"`
 }
 
+// Distribution defines the probability distribution of a random amount of time, e.g. the jitter added to a duration.
+// +kubebuilder:validation:Enum=Uniform;Normal;Exponential
+type Distribution string
+
+const (
+	// UniformDistribution chooses the random amount uniformly from the interval between zero and `jitter*duration`.
+	UniformDistribution Distribution = "Uniform"
+
+	// NormalDistribution chooses the random amount from a half-normal distribution with standard deviation `jitter*duration`;
+	// this is useful to mimic an infrastructure with predictable performances.
+	NormalDistribution Distribution = "Normal"
+
+	// ExponentialDistribution chooses the random amount from an exponential distribution with mean `jitter*duration`;
+	// this is useful to mimic an infrastructure with a long tail of slow operations.
+	ExponentialDistribution Distribution = "Exponential"
+)
+
 // InMemoryMachineStatus defines the observed state of InMemoryMachine.
 type InMemoryMachineStatus struct {
 	// Ready denotes that the machine is ready
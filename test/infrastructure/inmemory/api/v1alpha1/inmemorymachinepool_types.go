@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// MachinePoolFinalizer allows ReconcileInMemoryMachinePool to clean up resources associated with InMemoryMachinePool
+	// before removing it from the API server.
+	MachinePoolFinalizer = "inmemorymachinepool.infrastructure.cluster.x-k8s.io"
+
+	// MachinePoolTemplateHashAnnotation is the hash of the MachinePool version, bootstrap config and InMemoryMachinePool
+	// template an InMemoryMachine has been created from; InMemoryMachines with an outdated hash are replaced
+	// one at a time by the InMemoryMachinePool controller.
+	MachinePoolTemplateHashAnnotation = "inmemorymachinepool.infrastructure.cluster.x-k8s.io/template-hash"
+
+	// MachinePoolVersionAnnotation is the Kubernetes version of the MachinePool at the time an InMemoryMachine has been
+	// created; it is used as kubelet version for the Node, given that MachinePool Machines do not have a version.
+	MachinePoolVersionAnnotation = "inmemorymachinepool.infrastructure.cluster.x-k8s.io/version"
+)
+
+// InMemoryMachinePoolSpec defines the desired state of InMemoryMachinePool.
+type InMemoryMachinePoolSpec struct {
+	// Template contains the details used to build the InMemoryMachines of the machine pool.
+	// +optional
+	Template InMemoryMachinePoolMachineTemplate `json:"template,omitempty"`
+
+	// ProviderID is the identification ID of the machine pool.
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
+
+	// ProviderIDList is the list of identification IDs of the InMemoryMachines managed by this machine pool.
+	// +optional
+	ProviderIDList []string `json:"providerIDList,omitempty"`
+}
+
+// InMemoryMachinePoolMachineTemplate defines the desired state of the InMemoryMachines of a machine pool.
+type InMemoryMachinePoolMachineTemplate struct {
+	// Behaviour of the InMemoryMachines of the machine pool; see InMemoryMachineSpec.Behaviour.
+	// +optional
+	Behaviour *InMemoryMachineBehaviour `json:"behaviour,omitempty"`
+}
+
+// InMemoryMachinePoolStatus defines the observed state of InMemoryMachinePool.
+type InMemoryMachinePoolStatus struct {
+	// Ready denotes that all the InMemoryMachines of the machine pool are ready.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Replicas is the most recently observed number of InMemoryMachines.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// UpdatedReplicas is the most recently observed number of InMemoryMachines created from the current template.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// InfrastructureMachineKind is the kind of the infrastructure resources behind MachinePool Machines.
+	// +optional
+	InfrastructureMachineKind string `json:"infrastructureMachineKind,omitempty"`
+}
+
+// +kubebuilder:resource:path=inmemorymachinepools,scope=Namespaced,categories=cluster-api
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels['cluster\\.x-k8s\\.io/cluster-name']",description="Cluster"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas",description="Number of InMemoryMachines"
+// +kubebuilder:printcolumn:name="Updated",type="integer",JSONPath=".status.updatedReplicas",description="Number of InMemoryMachines created from the current template"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine pool ready status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of InMemoryMachinePool"
+
+// InMemoryMachinePool is the schema for the in-memory machine pool API.
+type InMemoryMachinePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InMemoryMachinePoolSpec   `json:"spec,omitempty"`
+	Status InMemoryMachinePoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// InMemoryMachinePoolList contains a list of InMemoryMachinePool.
+type InMemoryMachinePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InMemoryMachinePool `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &InMemoryMachinePool{}, &InMemoryMachinePoolList{})
+}
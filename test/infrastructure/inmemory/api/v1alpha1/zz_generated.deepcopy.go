@@ -77,7 +77,7 @@ func (in *InMemoryCluster) DeepCopyInto(out *InMemoryCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -99,6 +99,47 @@ func (in *InMemoryCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryClusterAPIServerBehaviour) DeepCopyInto(out *InMemoryClusterAPIServerBehaviour) {
+	*out = *in
+	out.Latency = in.Latency
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryClusterAPIServerBehaviour.
+func (in *InMemoryClusterAPIServerBehaviour) DeepCopy() *InMemoryClusterAPIServerBehaviour {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryClusterAPIServerBehaviour)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryClusterBehaviour) DeepCopyInto(out *InMemoryClusterBehaviour) {
+	*out = *in
+	if in.APIServer != nil {
+		in, out := &in.APIServer, &out.APIServer
+		*out = new(InMemoryClusterAPIServerBehaviour)
+		**out = **in
+	}
+	if in.Scale != nil {
+		in, out := &in.Scale, &out.Scale
+		*out = new(InMemoryClusterScale)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryClusterBehaviour.
+func (in *InMemoryClusterBehaviour) DeepCopy() *InMemoryClusterBehaviour {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryClusterBehaviour)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InMemoryClusterList) DeepCopyInto(out *InMemoryClusterList) {
 	*out = *in
@@ -131,10 +172,30 @@ func (in *InMemoryClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryClusterScale) DeepCopyInto(out *InMemoryClusterScale) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryClusterScale.
+func (in *InMemoryClusterScale) DeepCopy() *InMemoryClusterScale {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryClusterScale)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InMemoryClusterSpec) DeepCopyInto(out *InMemoryClusterSpec) {
 	*out = *in
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.Behaviour != nil {
+		in, out := &in.Behaviour, &out.Behaviour
+		*out = new(InMemoryClusterBehaviour)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryClusterSpec.
@@ -231,7 +292,7 @@ func (in *InMemoryClusterTemplateList) DeepCopyObject() runtime.Object {
 func (in *InMemoryClusterTemplateResource) DeepCopyInto(out *InMemoryClusterTemplateResource) {
 	*out = *in
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryClusterTemplateResource.
@@ -370,6 +431,212 @@ func (in *InMemoryMachineList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePool) DeepCopyInto(out *InMemoryMachinePool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePool.
+func (in *InMemoryMachinePool) DeepCopy() *InMemoryMachinePool {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InMemoryMachinePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolList) DeepCopyInto(out *InMemoryMachinePoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InMemoryMachinePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolList.
+func (in *InMemoryMachinePoolList) DeepCopy() *InMemoryMachinePoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InMemoryMachinePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolMachineTemplate) DeepCopyInto(out *InMemoryMachinePoolMachineTemplate) {
+	*out = *in
+	if in.Behaviour != nil {
+		in, out := &in.Behaviour, &out.Behaviour
+		*out = new(InMemoryMachineBehaviour)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolMachineTemplate.
+func (in *InMemoryMachinePoolMachineTemplate) DeepCopy() *InMemoryMachinePoolMachineTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolMachineTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolSpec) DeepCopyInto(out *InMemoryMachinePoolSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.ProviderIDList != nil {
+		in, out := &in.ProviderIDList, &out.ProviderIDList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolSpec.
+func (in *InMemoryMachinePoolSpec) DeepCopy() *InMemoryMachinePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolStatus) DeepCopyInto(out *InMemoryMachinePoolStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolStatus.
+func (in *InMemoryMachinePoolStatus) DeepCopy() *InMemoryMachinePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolTemplate) DeepCopyInto(out *InMemoryMachinePoolTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolTemplate.
+func (in *InMemoryMachinePoolTemplate) DeepCopy() *InMemoryMachinePoolTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InMemoryMachinePoolTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolTemplateList) DeepCopyInto(out *InMemoryMachinePoolTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InMemoryMachinePoolTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolTemplateList.
+func (in *InMemoryMachinePoolTemplateList) DeepCopy() *InMemoryMachinePoolTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InMemoryMachinePoolTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolTemplateResource) DeepCopyInto(out *InMemoryMachinePoolTemplateResource) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolTemplateResource.
+func (in *InMemoryMachinePoolTemplateResource) DeepCopy() *InMemoryMachinePoolTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryMachinePoolTemplateSpec) DeepCopyInto(out *InMemoryMachinePoolTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryMachinePoolTemplateSpec.
+func (in *InMemoryMachinePoolTemplateSpec) DeepCopy() *InMemoryMachinePoolTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryMachinePoolTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InMemoryMachineSpec) DeepCopyInto(out *InMemoryMachineSpec) {
 	*out = *in
@@ -539,3 +806,19 @@ func (in *InMemoryVMBehaviour) DeepCopy() *InMemoryVMBehaviour {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatencySettings) DeepCopyInto(out *LatencySettings) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatencySettings.
+func (in *LatencySettings) DeepCopy() *LatencySettings {
+	if in == nil {
+		return nil
+	}
+	out := new(LatencySettings)
+	in.DeepCopyInto(out)
+	return out
+}
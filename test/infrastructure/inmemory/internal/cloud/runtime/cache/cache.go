@@ -19,6 +19,7 @@ package cache
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"time"
@@ -51,6 +52,9 @@ type Cache interface {
 
 	GetInformer(ctx context.Context, obj client.Object) (Informer, error)
 	GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (Informer, error)
+
+	Snapshot(w io.Writer) error
+	Restore(r io.Reader) error
 }
 
 // Informer forwards events to event handlers.
@@ -91,6 +95,8 @@ type resourceGroupTracker struct {
 	objects map[schema.GroupVersionKind]map[types.NamespacedName]client.Object
 	// ownedObjects tracks ownership. Key is the owner, values are the owned objects.
 	ownedObjects map[ownReference]map[ownReference]struct{}
+	// resourceVersion is the last resource version assigned to an object in the resource group.
+	resourceVersion uint64
 }
 
 type ownReference struct {
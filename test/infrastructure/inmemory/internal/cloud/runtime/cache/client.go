@@ -17,7 +17,11 @@ limitations under the License.
 package cache
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
@@ -101,13 +105,34 @@ func (c *cache) List(resourceGroup string, list client.ObjectList, opts ...clien
 	tracker.lock.RLock()
 	defer tracker.lock.RUnlock()
 
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	var start *types.NamespacedName
+	if listOpts.Continue != "" {
+		start, err = decodeContinue(listOpts.Continue)
+		if err != nil {
+			return err
+		}
+	}
+
 	items := make([]runtime.Object, 0)
 	objects, ok := tracker.objects[unsafeGuessObjectKindFromList(gvk)]
 	if ok {
-		listOpts := client.ListOptions{}
-		listOpts.ApplyOptions(opts)
+		// Objects are listed in key order, like in etcd, so paginated lists are predictable.
+		keys := make([]types.NamespacedName, 0, len(objects))
+		for key := range objects {
+			if start != nil && !namespacedNameLess(*start, key) {
+				continue
+			}
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return namespacedNameLess(keys[i], keys[j])
+		})
 
-		for _, obj := range objects {
+		for _, key := range keys {
+			obj := objects[key]
 			if listOpts.Namespace != "" && obj.GetNamespace() != listOpts.Namespace {
 				continue
 			}
@@ -128,7 +153,7 @@ func (c *cache) List(resourceGroup string, list client.ObjectList, opts ...clien
 				}
 			}
 
-			obj := obj.DeepCopyObject().(client.Object)
+			obj = obj.DeepCopyObject().(client.Object)
 			switch list.(type) {
 			case *unstructured.UnstructuredList:
 				unstructuredObj := &unstructured.Unstructured{}
@@ -142,12 +167,70 @@ func (c *cache) List(resourceGroup string, list client.ObjectList, opts ...clien
 		}
 	}
 
+	listAccessor, err := meta.ListAccessor(list)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	listAccessor.SetResourceVersion(strconv.FormatUint(tracker.resourceVersion, 10))
+	listAccessor.SetContinue("")
+	listAccessor.SetRemainingItemCount(nil)
+	if listOpts.Limit > 0 && int64(len(items)) > listOpts.Limit {
+		remainingItemCount := int64(len(items)) - listOpts.Limit
+		items = items[:listOpts.Limit]
+
+		last, err := meta.Accessor(items[len(items)-1])
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		continueToken, err := encodeContinue(types.NamespacedName{Namespace: last.GetNamespace(), Name: last.GetName()})
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		listAccessor.SetContinue(continueToken)
+		listAccessor.SetRemainingItemCount(&remainingItemCount)
+	}
+
 	if err := meta.SetList(list, items); err != nil {
 		return apierrors.NewInternalError(err)
 	}
 	return nil
 }
 
+// continueToken is the content of the continue token returned by paginated lists.
+// NOTE: Differently from a real API server, following pages are not served from a consistent snapshot;
+// they contain the objects with a key greater than the last one returned, as they are at the time of the request.
+type continueToken struct {
+	// Start is the key of the last object returned by the previous page.
+	Start types.NamespacedName `json:"start"`
+}
+
+func encodeContinue(start types.NamespacedName) (string, error) {
+	raw, err := json.Marshal(continueToken{Start: start})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeContinue(token string) (*types.NamespacedName, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid continue token: %v", err))
+	}
+	c := continueToken{}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid continue token: %v", err))
+	}
+	return &c.Start, nil
+}
+
+func namespacedNameLess(a, b types.NamespacedName) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
 func (c *cache) Create(resourceGroup string, obj client.Object) error {
 	return c.store(resourceGroup, obj, false)
 }
@@ -212,7 +295,7 @@ func (c *cache) store(resourceGroup string, obj client.Object, replaceExisting b
 				return apierrors.NewConflict(unsafeGuessGroupVersionResource(objGVK).GroupResource(), objKey.String(), fmt.Errorf("object has been modified"))
 			}
 
-			if err := c.beforeUpdate(resourceGroup, trackedObj, obj); err != nil {
+			if err := c.beforeUpdate(tracker, trackedObj, obj); err != nil {
 				return err
 			}
 			tracker.objects[objGVK][objKey] = obj.DeepCopyObject().(client.Object)
@@ -227,7 +310,7 @@ func (c *cache) store(resourceGroup string, obj client.Object, replaceExisting b
 		return apierrors.NewNotFound(unsafeGuessGroupVersionResource(objGVK).GroupResource(), objKey.String())
 	}
 
-	if err := c.beforeCreate(resourceGroup, obj); err != nil {
+	if err := c.beforeCreate(tracker, obj); err != nil {
 		return err
 	}
 	tracker.objects[objGVK][objKey] = obj.DeepCopyObject().(client.Object)
@@ -424,7 +507,7 @@ func (c *cache) doTryDeleteLocked(resourceGroup string, tracker *resourceGroupTr
 		oldObj := obj.DeepCopyObject().(client.Object)
 		now := metav1.Time{Time: time.Now().UTC()}
 		obj.SetDeletionTimestamp(&now)
-		if err := c.beforeUpdate(resourceGroup, oldObj, obj); err != nil {
+		if err := c.beforeUpdate(tracker, oldObj, obj); err != nil {
 			return false, apierrors.NewBadRequest(err.Error())
 		}
 
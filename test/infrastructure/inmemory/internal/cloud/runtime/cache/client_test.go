@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cloudv1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/api/v1alpha1"
@@ -104,7 +105,7 @@ func Test_cache_client(t *testing.T) {
 			r := c.resourceGroups["foo"].objects[cloudv1.GroupVersion.WithKind(cloudv1.CloudMachineKind)][key]
 			g.Expect(r.GetObjectKind().GroupVersionKind()).To(BeComparableTo(cloudv1.GroupVersion.WithKind(cloudv1.CloudMachineKind)), "gvk must be set")
 			g.Expect(r.GetName()).To(Equal("bar"), "name must be equal to object tracker key")
-			g.Expect(r.GetResourceVersion()).To(Equal("v1"), "resourceVersion must be set")
+			g.Expect(r.GetResourceVersion()).To(Equal("1"), "resourceVersion must be set")
 			g.Expect(r.GetCreationTimestamp()).ToNot(BeZero(), "creation timestamp must be set")
 			g.Expect(r.GetAnnotations()).To(HaveKey(lastSyncTimeAnnotation), "last sync annotation must exists")
 
@@ -271,7 +272,7 @@ func Test_cache_client(t *testing.T) {
 			// Check all the computed fields are as expected.
 			g.Expect(obj.GetObjectKind().GroupVersionKind()).To(BeComparableTo(cloudv1.GroupVersion.WithKind(cloudv1.CloudMachineKind)), "gvk must be set")
 			g.Expect(obj.GetName()).To(Equal("bar"), "name must be equal to object tracker key")
-			g.Expect(obj.GetResourceVersion()).To(Equal("v1"), "resourceVersion must be set")
+			g.Expect(obj.GetResourceVersion()).ToNot(BeEmpty(), "resourceVersion must be set")
 			g.Expect(obj.GetCreationTimestamp()).ToNot(BeZero(), "creation timestamp must be set")
 			g.Expect(obj.GetAnnotations()).To(HaveKey(lastSyncTimeAnnotation), "last sync annotation must be set")
 		})
@@ -332,6 +333,40 @@ func Test_cache_client(t *testing.T) {
 			g.Expect(i2.GetAnnotations()).To(HaveKey(lastSyncTimeAnnotation), "last sync annotation must be present")
 		})
 
+		t.Run("list with pagination", func(t *testing.T) {
+			g := NewWithT(t)
+
+			createMachine(t, c, "foo", "qux")
+
+			obj := &cloudv1.CloudMachineList{}
+			err := c.List("foo", obj, client.Limit(2))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(obj.Items).To(HaveLen(2))
+			g.Expect(obj.Items[0].Name).To(Equal("bar"), "items must be sorted by key")
+			g.Expect(obj.Items[1].Name).To(Equal("baz"), "items must be sorted by key")
+			g.Expect(obj.ResourceVersion).To(Equal("3"), "list resourceVersion must be the last resourceVersion in the resourceGroup")
+			g.Expect(obj.Continue).ToNot(BeEmpty())
+			g.Expect(obj.RemainingItemCount).To(Equal(pointer.Int64(1)))
+
+			err = c.List("foo", obj, client.Limit(2), client.Continue(obj.Continue))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(obj.Items).To(HaveLen(1))
+			g.Expect(obj.Items[0].Name).To(Equal("qux"))
+			g.Expect(obj.Continue).To(BeEmpty())
+			g.Expect(obj.RemainingItemCount).To(BeNil())
+		})
+
+		t.Run("fails if continue token is invalid", func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &cloudv1.CloudMachineList{}
+			err := c.List("foo", obj, client.Continue("not-a-token"))
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(apierrors.IsBadRequest(err)).To(BeTrue())
+		})
+
 		// TODO: test filtering by labels
 	})
 
@@ -17,7 +17,6 @@ limitations under the License.
 package cache
 
 import (
-	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
@@ -27,12 +26,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func (c *cache) beforeCreate(_ string, obj client.Object) error {
+func (c *cache) beforeCreate(tracker *resourceGroupTracker, obj client.Object) error {
 	now := time.Now().UTC()
 	obj.SetCreationTimestamp(metav1.Time{Time: now})
 	// TODO: UID
 	obj.SetAnnotations(appendAnnotations(obj, lastSyncTimeAnnotation, now.Format(time.RFC3339)))
-	obj.SetResourceVersion(fmt.Sprintf("v%d", 1))
+	obj.SetResourceVersion(tracker.nextResourceVersion())
 	return nil
 }
 
@@ -40,7 +39,7 @@ func (c *cache) afterCreate(resourceGroup string, obj client.Object) {
 	c.informCreate(resourceGroup, obj)
 }
 
-func (c *cache) beforeUpdate(_ string, oldObj, newObj client.Object) error {
+func (c *cache) beforeUpdate(tracker *resourceGroupTracker, oldObj, newObj client.Object) error {
 	newObj.SetCreationTimestamp(oldObj.GetCreationTimestamp())
 	newObj.SetResourceVersion(oldObj.GetResourceVersion())
 	// TODO: UID
@@ -51,9 +50,7 @@ func (c *cache) beforeUpdate(_ string, oldObj, newObj client.Object) error {
 	if !reflect.DeepEqual(newObj, oldObj) {
 		now := time.Now().UTC()
 		newObj.SetAnnotations(appendAnnotations(newObj, lastSyncTimeAnnotation, now.Format(time.RFC3339)))
-
-		oldResourceVersion, _ := strconv.Atoi(strings.TrimPrefix(oldObj.GetResourceVersion(), "v"))
-		newObj.SetResourceVersion(fmt.Sprintf("v%d", oldResourceVersion+1))
+		newObj.SetResourceVersion(tracker.nextResourceVersion())
 	}
 	return nil
 }
@@ -75,6 +72,22 @@ func (c *cache) beforeDelete(_ string, _ client.Object) error {
 func (c *cache) afterDelete(_ string, _ client.Object) {
 }
 
+// nextResourceVersion returns a new resource version for the resource group.
+// Like in etcd, resource versions are increasing integers shared by all the objects in a resource group, so
+// clients can use them for comparing the relative age of two states, e.g. when resuming a watch.
+// Note: The tracker must be already locked when calling this method.
+func (t *resourceGroupTracker) nextResourceVersion() string {
+	t.resourceVersion++
+	return strconv.FormatUint(t.resourceVersion, 10)
+}
+
+// parseResourceVersion returns the integer value of a resource version.
+// Note: resource versions in the "v<N>" format used by previous versions of the cache are still accepted,
+// so snapshots taken with older versions can be restored.
+func parseResourceVersion(resourceVersion string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimPrefix(resourceVersion, "v"), 10, 64)
+}
+
 func appendAnnotations(obj client.Object, kayValuePair ...string) map[string]string {
 	newAnnotations := map[string]string{}
 	for k, v := range obj.GetAnnotations() {
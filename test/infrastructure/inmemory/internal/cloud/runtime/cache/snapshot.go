@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// snapshot is the serialized form of the content of a cache.
+type snapshot struct {
+	// ResourceGroups contains the objects in each resource group.
+	ResourceGroups map[string][]json.RawMessage `json:"resourceGroups"`
+}
+
+// Snapshot writes the content of the cache to w.
+func (c *cache) Snapshot(w io.Writer) error {
+	// NOTE: Trackers are collected before locking them one at a time, so we never hold the cache lock
+	// while waiting for a tracker lock (informers acquire the cache lock while holding a tracker lock).
+	c.lock.RLock()
+	trackers := make(map[string]*resourceGroupTracker, len(c.resourceGroups))
+	for name, tracker := range c.resourceGroups {
+		trackers[name] = tracker
+	}
+	c.lock.RUnlock()
+
+	s := snapshot{
+		ResourceGroups: make(map[string][]json.RawMessage, len(trackers)),
+	}
+	for name, tracker := range trackers {
+		objs, err := tracker.snapshot()
+		if err != nil {
+			return fmt.Errorf("failed to snapshot resourceGroup %s: %w", name, err)
+		}
+		s.ResourceGroups[name] = objs
+	}
+
+	return json.NewEncoder(w).Encode(s)
+}
+
+func (t *resourceGroupTracker) snapshot() ([]json.RawMessage, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	objs := []json.RawMessage{}
+	for _, objects := range t.objects {
+		for _, obj := range objects {
+			raw, err := json.Marshal(obj)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, client.ObjectKeyFromObject(obj), err)
+			}
+			objs = append(objs, raw)
+		}
+	}
+	return objs, nil
+}
+
+// Restore adds to the cache the content of a snapshot read from r.
+// NOTE: Objects are restored as they are, without changing their resource version, and without informing event handlers;
+// resource versions assigned after restore are greater than the ones of the restored objects.
+func (c *cache) Restore(r io.Reader) error {
+	s := snapshot{}
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	decoder := serializer.NewCodecFactory(c.scheme).UniversalDeserializer()
+	for name, objs := range s.ResourceGroups {
+		c.AddResourceGroup(name)
+		if err := c.restoreResourceGroup(name, decoder, objs); err != nil {
+			return fmt.Errorf("failed to restore resourceGroup %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (c *cache) restoreResourceGroup(resourceGroup string, decoder runtime.Decoder, objs []json.RawMessage) error {
+	tracker := c.resourceGroupTracker(resourceGroup)
+
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	for _, raw := range objs {
+		o, gvk, err := decoder.Decode(raw, nil, nil)
+		if err != nil {
+			return fmt.Errorf("failed to decode object: %w", err)
+		}
+		obj, ok := o.(client.Object)
+		if !ok {
+			return fmt.Errorf("failed to decode object: %T is not a client.Object", o)
+		}
+		obj.GetObjectKind().SetGroupVersionKind(*gvk)
+
+		if _, ok := tracker.objects[*gvk]; !ok {
+			tracker.objects[*gvk] = make(map[types.NamespacedName]client.Object)
+		}
+		objKey := client.ObjectKeyFromObject(obj)
+		tracker.objects[*gvk][objKey] = obj
+		if resourceVersion, err := parseResourceVersion(obj.GetResourceVersion()); err == nil && resourceVersion > tracker.resourceVersion {
+			tracker.resourceVersion = resourceVersion
+		}
+		updateTrackerOwnerReferences(tracker, nil, obj, ownReference{gvk: *gvk, key: objKey})
+
+		// Objects being deleted when the snapshot was taken must go through garbage collection again.
+		if !obj.GetDeletionTimestamp().IsZero() && c.garbageCollectorQueue != nil {
+			c.garbageCollectorQueue.Add(gcRequest{
+				resourceGroup: resourceGroup,
+				gvk:           *gvk,
+				key:           objKey,
+			})
+		}
+	}
+	return nil
+}
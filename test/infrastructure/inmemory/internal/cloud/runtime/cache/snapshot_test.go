@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cloudv1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/api/v1alpha1"
+)
+
+func Test_cache_snapshot(t *testing.T) {
+	g := NewWithT(t)
+
+	c := NewCache(scheme).(*cache)
+	c.AddResourceGroup("foo")
+	c.AddResourceGroup("bar")
+
+	owner := &cloudv1.CloudMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "owner",
+		},
+	}
+	g.Expect(c.Create("foo", owner)).To(Succeed())
+
+	owned := &cloudv1.CloudMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "owned",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: cloudv1.GroupVersion.String(),
+					Kind:       "CloudMachine",
+					Name:       owner.Name,
+				},
+			},
+		},
+	}
+	g.Expect(c.Create("foo", owned)).To(Succeed())
+
+	// Bump the resource version of the owned object.
+	owned.Labels = map[string]string{"foo": "bar"}
+	g.Expect(c.Update("foo", owned)).To(Succeed())
+
+	buf := &bytes.Buffer{}
+	g.Expect(c.Snapshot(buf)).To(Succeed())
+
+	restored := NewCache(scheme).(*cache)
+	g.Expect(restored.Restore(buf)).To(Succeed())
+
+	// Resource groups are restored, including empty ones.
+	g.Expect(restored.resourceGroups).To(HaveKey("foo"))
+	g.Expect(restored.resourceGroups).To(HaveKey("bar"))
+
+	// Objects are restored as they are.
+	for _, obj := range []*cloudv1.CloudMachine{owner, owned} {
+		want := &cloudv1.CloudMachine{}
+		g.Expect(c.Get("foo", client.ObjectKeyFromObject(obj), want)).To(Succeed())
+
+		got := &cloudv1.CloudMachine{}
+		g.Expect(restored.Get("foo", client.ObjectKeyFromObject(obj), got)).To(Succeed())
+		g.Expect(got.ResourceVersion).To(Equal(want.ResourceVersion))
+		g.Expect(got.CreationTimestamp.Unix()).To(Equal(want.CreationTimestamp.Unix()))
+		g.Expect(got.Labels).To(Equal(want.Labels))
+	}
+
+	// Ownership is restored.
+	g.Expect(restored.resourceGroups["foo"].ownedObjects).To(Equal(c.resourceGroups["foo"].ownedObjects))
+
+	// Resource versions assigned after restore are greater than the restored ones.
+	g.Expect(restored.resourceGroups["foo"].resourceVersion).To(Equal(c.resourceGroups["foo"].resourceVersion))
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Snapshotter persists the state of a Manager to a file, e.g. on a persistent volume,
+// so the state can be restored when the provider restarts.
+type Snapshotter struct {
+	// Manager is the Manager whose state is persisted.
+	Manager Manager
+
+	// Path is the file the snapshots are written to.
+	Path string
+
+	// Interval is the interval between two consecutive snapshots.
+	Interval time.Duration
+}
+
+// Restore restores the state of the Manager from the snapshot file, if it exists.
+func (s *Snapshotter) Restore(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	f, err := os.Open(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Info("Snapshot file does not exist, starting with an empty state", "path", s.Path)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to open snapshot file %s", s.Path)
+	}
+	defer f.Close()
+
+	if err := s.Manager.GetCache().Restore(f); err != nil {
+		return errors.Wrapf(err, "failed to restore snapshot from %s", s.Path)
+	}
+	log.Info("State restored from snapshot", "path", s.Path)
+	return nil
+}
+
+// Start writes a snapshot every Interval, and a final snapshot when ctx is done.
+// NOTE: Start implements manager.Runnable, so the Snapshotter can be added to a controller-runtime manager;
+// when leader election is enabled, only the leader writes snapshots.
+func (s *Snapshotter) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.snapshot(); err != nil {
+				log.Error(err, "Failed to write snapshot", "path", s.Path)
+			}
+		case <-ctx.Done():
+			if err := s.snapshot(); err != nil {
+				return err
+			}
+			log.Info("Final snapshot written", "path", s.Path)
+			return nil
+		}
+	}
+}
+
+// snapshot writes a snapshot to a temporary file, and then it renames the file to Path,
+// so a crash while writing never corrupts the previous snapshot.
+func (s *Snapshotter) snapshot() error {
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary snapshot file")
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck // The file does not exist anymore if the rename succeeds.
+
+	if err := s.Manager.GetCache().Snapshot(f); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "failed to write snapshot")
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "failed to sync temporary snapshot file")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "failed to close temporary snapshot file")
+	}
+	if err := os.Rename(f.Name(), s.Path); err != nil {
+		return errors.Wrapf(err, "failed to rename temporary snapshot file to %s", s.Path)
+	}
+	return nil
+}
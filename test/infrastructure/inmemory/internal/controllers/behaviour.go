@@ -0,0 +1,186 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+	cclient "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/runtime/client"
+)
+
+// syntheticObjectLabelName is the label applied to the synthetic objects added to a workload cluster.
+const syntheticObjectLabelName = "inmemorycluster.infrastructure.cluster.x-k8s.io/synthetic"
+
+// randomDuration returns duration plus an additional amount chosen at random according to distribution,
+// scaled by jitter.
+func randomDuration(duration time.Duration, jitter string, distribution infrav1.Distribution) (time.Duration, error) {
+	j, err := parseJitter(jitter)
+	if err != nil {
+		return 0, err
+	}
+	return addJitter(duration, j, distribution), nil
+}
+
+func parseJitter(jitter string) (float64, error) {
+	if jitter == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(jitter, 64)
+}
+
+func addJitter(duration time.Duration, jitter float64, distribution infrav1.Distribution) time.Duration {
+	if jitter <= 0.0 {
+		return duration
+	}
+
+	scale := jitter * float64(duration)
+	switch distribution {
+	case infrav1.NormalDistribution:
+		return duration + time.Duration(math.Abs(rand.NormFloat64())*scale) //nolint:gosec // Intentionally using a weak random number generator here.
+	case infrav1.ExponentialDistribution:
+		return duration + time.Duration(rand.ExpFloat64()*scale) //nolint:gosec // Intentionally using a weak random number generator here.
+	default:
+		return duration + time.Duration(rand.Float64()*scale) //nolint:gosec // Intentionally using a weak random number generator here.
+	}
+}
+
+// getAPIServerLatency returns a func computing the latency of each request served by the API servers of an InMemoryCluster,
+// or nil if the InMemoryCluster does not define a latency.
+func getAPIServerLatency(inMemoryCluster *infrav1.InMemoryCluster) (func() time.Duration, error) {
+	if inMemoryCluster.Spec.Behaviour == nil || inMemoryCluster.Spec.Behaviour.APIServer == nil {
+		return nil, nil
+	}
+
+	x := inMemoryCluster.Spec.Behaviour.APIServer.Latency
+	jitter, err := parseJitter(x.Jitter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse API server's latency Jitter")
+	}
+	return func() time.Duration {
+		return addJitter(x.Duration.Duration, jitter, x.Distribution)
+	}, nil
+}
+
+// reconcileSyntheticObjects adds to the workload cluster the synthetic Nodes and Pods defined by scale,
+// and it deletes synthetic objects no longer required, e.g. after a scale down.
+func reconcileSyntheticObjects(ctx context.Context, cloudClient cclient.Client, scale *infrav1.InMemoryClusterScale) error {
+	desiredNodes := map[string]bool{}
+	desiredPods := map[string]string{}
+	if scale != nil {
+		for i := 0; i < int(scale.Nodes); i++ {
+			nodeName := fmt.Sprintf("synthetic-node-%d", i)
+			desiredNodes[nodeName] = true
+			for j := 0; j < int(scale.PodsPerNode); j++ {
+				desiredPods[fmt.Sprintf("%s-pod-%d", nodeName, j)] = nodeName
+			}
+		}
+	}
+
+	// NOTE: Pods are reconciled before Nodes, so Pods are never left behind when a Node is deleted.
+	podList := &corev1.PodList{}
+	if err := cloudClient.List(ctx, podList, client.InNamespace(metav1.NamespaceDefault), client.MatchingLabels{syntheticObjectLabelName: ""}); err != nil {
+		return errors.Wrap(err, "failed to list synthetic Pods")
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if _, ok := desiredPods[pod.Name]; ok {
+			delete(desiredPods, pod.Name)
+			continue
+		}
+		if err := cloudClient.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete synthetic Pod %s", pod.Name)
+		}
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := cloudClient.List(ctx, nodeList, client.MatchingLabels{syntheticObjectLabelName: ""}); err != nil {
+		return errors.Wrap(err, "failed to list synthetic Nodes")
+	}
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if desiredNodes[node.Name] {
+			delete(desiredNodes, node.Name)
+			continue
+		}
+		if err := cloudClient.Delete(ctx, node); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete synthetic Node %s", node.Name)
+		}
+	}
+
+	for nodeName := range desiredNodes {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: nodeName,
+				Labels: map[string]string{
+					syntheticObjectLabelName: "",
+				},
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:   corev1.NodeReady,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			},
+		}
+		if err := cloudClient.Create(ctx, node); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create synthetic Node %s", nodeName)
+		}
+	}
+
+	for podName, nodeName := range desiredPods {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      podName,
+				Labels: map[string]string{
+					syntheticObjectLabelName: "",
+				},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			},
+		}
+		if err := cloudClient.Create(ctx, pod); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create synthetic Pod %s", podName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+	cmanager "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/runtime/manager"
+)
+
+func TestRandomDuration(t *testing.T) {
+	for _, distribution := range []infrav1.Distribution{"", infrav1.UniformDistribution, infrav1.NormalDistribution, infrav1.ExponentialDistribution} {
+		t.Run(string(distribution), func(t *testing.T) {
+			g := NewWithT(t)
+
+			d, err := randomDuration(2*time.Second, "", distribution)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(d).To(Equal(2 * time.Second))
+
+			for i := 0; i < 100; i++ {
+				d, err = randomDuration(2*time.Second, "0.5", distribution)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(d).To(BeNumerically(">=", 2*time.Second))
+			}
+
+			_, err = randomDuration(2*time.Second, "not-a-number", distribution)
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
+
+func TestReconcileSyntheticObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	manager := cmanager.New(scheme)
+	manager.AddResourceGroup("foo")
+	c := manager.GetResourceGroup("foo").GetClient()
+
+	// Scale up.
+	err := reconcileSyntheticObjects(ctx, c, &infrav1.InMemoryClusterScale{Nodes: 3, PodsPerNode: 2})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	nodeList := &corev1.NodeList{}
+	g.Expect(c.List(ctx, nodeList)).To(Succeed())
+	g.Expect(nodeList.Items).To(HaveLen(3))
+
+	podList := &corev1.PodList{}
+	g.Expect(c.List(ctx, podList)).To(Succeed())
+	g.Expect(podList.Items).To(HaveLen(6))
+
+	podList = &corev1.PodList{}
+	g.Expect(c.List(ctx, podList, &client.ListOptions{FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": "synthetic-node-0"})})).To(Succeed())
+	g.Expect(podList.Items).To(HaveLen(2))
+
+	// Scale down.
+	err = reconcileSyntheticObjects(ctx, c, &infrav1.InMemoryClusterScale{Nodes: 1, PodsPerNode: 1})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	nodeList = &corev1.NodeList{}
+	g.Expect(c.List(ctx, nodeList)).To(Succeed())
+	g.Expect(nodeList.Items).To(HaveLen(1))
+	g.Expect(nodeList.Items[0].Name).To(Equal("synthetic-node-0"))
+
+	podList = &corev1.PodList{}
+	g.Expect(c.List(ctx, podList)).To(Succeed())
+	g.Expect(podList.Items).To(HaveLen(1))
+	g.Expect(podList.Items[0].Name).To(Equal("synthetic-node-0-pod-0"))
+
+	// Remove all the synthetic objects, without touching other objects.
+	g.Expect(c.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "bar"}})).To(Succeed())
+
+	err = reconcileSyntheticObjects(ctx, c, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	nodeList = &corev1.NodeList{}
+	g.Expect(c.List(ctx, nodeList)).To(Succeed())
+	g.Expect(nodeList.Items).To(HaveLen(1))
+	g.Expect(nodeList.Items[0].Name).To(Equal("bar"))
+
+	podList = &corev1.PodList{}
+	g.Expect(c.List(ctx, podList)).To(Succeed())
+	g.Expect(podList.Items).To(BeEmpty())
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+	cclient "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/runtime/client"
+)
+
+// machineFaults defines the faults injected in an InMemoryMachine.
+type machineFaults struct {
+	killed            sets.Set[infrav1.FaultComponent]
+	hanging           sets.Set[infrav1.FaultComponent]
+	provisioningDelay time.Duration
+}
+
+// getMachineFaults returns the faults injected in an InMemoryMachine using annotations.
+func getMachineFaults(inMemoryMachine *infrav1.InMemoryMachine) (machineFaults, error) {
+	faults := machineFaults{
+		killed:  sets.New[infrav1.FaultComponent](),
+		hanging: sets.New[infrav1.FaultComponent](),
+	}
+
+	if value, ok := inMemoryMachine.Annotations[infrav1.KillFaultAnnotation]; ok {
+		components, err := infrav1.ParseFaultComponents(value)
+		if err != nil {
+			return machineFaults{}, errors.Wrapf(err, "failed to parse the %s annotation", infrav1.KillFaultAnnotation)
+		}
+		faults.killed.Insert(components...)
+	}
+
+	if value, ok := inMemoryMachine.Annotations[infrav1.HangFaultAnnotation]; ok {
+		components, err := infrav1.ParseFaultComponents(value)
+		if err != nil {
+			return machineFaults{}, errors.Wrapf(err, "failed to parse the %s annotation", infrav1.HangFaultAnnotation)
+		}
+		faults.hanging.Insert(components...)
+	}
+
+	if value, ok := inMemoryMachine.Annotations[infrav1.ProvisioningDelayFaultAnnotation]; ok {
+		delay, err := infrav1.ParseFaultDuration(value)
+		if err != nil {
+			return machineFaults{}, errors.Wrapf(err, "failed to parse the %s annotation", infrav1.ProvisioningDelayFaultAnnotation)
+		}
+		faults.provisioningDelay = delay
+	}
+
+	return faults, nil
+}
+
+// getAPIServerFaults returns the error rate and the latency injected in the API servers of an InMemoryCluster using annotations.
+func getAPIServerFaults(inMemoryCluster *infrav1.InMemoryCluster) (errorRate float64, latency time.Duration, err error) {
+	if value, ok := inMemoryCluster.Annotations[infrav1.APIServerErrorRateFaultAnnotation]; ok {
+		errorRate, err = infrav1.ParseFaultRate(value)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "failed to parse the %s annotation", infrav1.APIServerErrorRateFaultAnnotation)
+		}
+	}
+
+	if value, ok := inMemoryCluster.Annotations[infrav1.APIServerLatencyFaultAnnotation]; ok {
+		latency, err = infrav1.ParseFaultDuration(value)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "failed to parse the %s annotation", infrav1.APIServerLatencyFaultAnnotation)
+		}
+	}
+
+	return errorRate, latency, nil
+}
+
+// setNodeReady sets the status of the Ready condition of a Node, e.g. to mimic a kubelet that stopped posting the node status.
+func setNodeReady(ctx context.Context, cloudClient cclient.Client, node *corev1.Node, status corev1.ConditionStatus) error {
+	for i := range node.Status.Conditions {
+		c := &node.Status.Conditions[i]
+		if c.Type != corev1.NodeReady {
+			continue
+		}
+		if c.Status == status {
+			return nil
+		}
+		c.Status = status
+		c.LastTransitionTime = metav1.Now()
+		return cloudClient.Update(ctx, node)
+	}
+
+	node.Status.Conditions = append(node.Status.Conditions, corev1.NodeCondition{
+		Type:               corev1.NodeReady,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+	})
+	return cloudClient.Update(ctx, node)
+}
+
+// setPodReady sets the Ready condition of a Pod, e.g. to mimic a static Pod that has been killed.
+func setPodReady(ctx context.Context, cloudClient cclient.Client, pod *corev1.Pod, ready bool) error {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+
+	for i := range pod.Status.Conditions {
+		c := &pod.Status.Conditions[i]
+		if c.Type != corev1.PodReady {
+			continue
+		}
+		if c.Status == status {
+			return nil
+		}
+		c.Status = status
+		c.LastTransitionTime = metav1.Now()
+		return cloudClient.Update(ctx, pod)
+	}
+
+	pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+		Type:               corev1.PodReady,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+	})
+	return cloudClient.Update(ctx, pod)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+	cmanager "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/internal/cloud/runtime/manager"
+)
+
+func TestGetMachineFaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        machineFaults
+		wantErr     bool
+	}{
+		{
+			name: "no faults",
+			want: machineFaults{
+				killed:  sets.New[infrav1.FaultComponent](),
+				hanging: sets.New[infrav1.FaultComponent](),
+			},
+		},
+		{
+			name: "all the faults",
+			annotations: map[string]string{
+				infrav1.KillFaultAnnotation:              "node, etcd",
+				infrav1.HangFaultAnnotation:              "apiserver",
+				infrav1.ProvisioningDelayFaultAnnotation: "1m30s",
+			},
+			want: machineFaults{
+				killed:            sets.New[infrav1.FaultComponent](infrav1.NodeFaultComponent, infrav1.EtcdFaultComponent),
+				hanging:           sets.New[infrav1.FaultComponent](infrav1.APIServerFaultComponent),
+				provisioningDelay: 90 * time.Second,
+			},
+		},
+		{
+			name: "empty annotations",
+			annotations: map[string]string{
+				infrav1.KillFaultAnnotation: "",
+				infrav1.HangFaultAnnotation: " , ",
+			},
+			want: machineFaults{
+				killed:  sets.New[infrav1.FaultComponent](),
+				hanging: sets.New[infrav1.FaultComponent](),
+			},
+		},
+		{
+			name:        "fails with an invalid kill component",
+			annotations: map[string]string{infrav1.KillFaultAnnotation: "node,kubelet"},
+			wantErr:     true,
+		},
+		{
+			name:        "fails with an invalid hang component",
+			annotations: map[string]string{infrav1.HangFaultAnnotation: "scheduler"},
+			wantErr:     true,
+		},
+		{
+			name:        "fails with an invalid provisioning delay",
+			annotations: map[string]string{infrav1.ProvisioningDelayFaultAnnotation: "soon"},
+			wantErr:     true,
+		},
+		{
+			name:        "fails with a negative provisioning delay",
+			annotations: map[string]string{infrav1.ProvisioningDelayFaultAnnotation: "-1s"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			inMemoryMachine := &infrav1.InMemoryMachine{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			got, err := getMachineFaults(inMemoryMachine)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetAPIServerFaults(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		wantErrorRate float64
+		wantLatency   time.Duration
+		wantErr       bool
+	}{
+		{
+			name: "no faults",
+		},
+		{
+			name: "error rate and latency",
+			annotations: map[string]string{
+				infrav1.APIServerErrorRateFaultAnnotation: "0.25",
+				infrav1.APIServerLatencyFaultAnnotation:   "200ms",
+			},
+			wantErrorRate: 0.25,
+			wantLatency:   200 * time.Millisecond,
+		},
+		{
+			name:        "fails with an invalid error rate",
+			annotations: map[string]string{infrav1.APIServerErrorRateFaultAnnotation: "often"},
+			wantErr:     true,
+		},
+		{
+			name:        "fails with an error rate out of the [0, 1] interval",
+			annotations: map[string]string{infrav1.APIServerErrorRateFaultAnnotation: "1.5"},
+			wantErr:     true,
+		},
+		{
+			name:        "fails with an invalid latency",
+			annotations: map[string]string{infrav1.APIServerLatencyFaultAnnotation: "slow"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			inMemoryCluster := &infrav1.InMemoryCluster{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			errorRate, latency, err := getAPIServerFaults(inMemoryCluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(errorRate).To(Equal(tt.wantErrorRate))
+			g.Expect(latency).To(Equal(tt.wantLatency))
+		})
+	}
+}
+
+func TestSetNodeReady(t *testing.T) {
+	g := NewWithT(t)
+
+	manager := cmanager.New(scheme)
+	manager.AddResourceGroup("foo")
+	c := manager.GetResourceGroup("foo").GetClient()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+	g.Expect(c.Create(ctx, node)).To(Succeed())
+
+	// Adds the condition if missing.
+	g.Expect(setNodeReady(ctx, c, node, corev1.ConditionTrue)).To(Succeed())
+	got := &corev1.Node{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), got)).To(Succeed())
+	g.Expect(got.Status.Conditions).To(HaveLen(1))
+	g.Expect(got.Status.Conditions[0].Type).To(Equal(corev1.NodeReady))
+	g.Expect(got.Status.Conditions[0].Status).To(Equal(corev1.ConditionTrue))
+
+	// Updates the existing condition.
+	g.Expect(setNodeReady(ctx, c, got, corev1.ConditionUnknown)).To(Succeed())
+	got = &corev1.Node{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), got)).To(Succeed())
+	g.Expect(got.Status.Conditions).To(HaveLen(1))
+	g.Expect(got.Status.Conditions[0].Status).To(Equal(corev1.ConditionUnknown))
+
+	// Does not update the Node if the status is unchanged.
+	resourceVersion := got.ResourceVersion
+	g.Expect(setNodeReady(ctx, c, got, corev1.ConditionUnknown)).To(Succeed())
+	got = &corev1.Node{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(node), got)).To(Succeed())
+	g.Expect(got.ResourceVersion).To(Equal(resourceVersion))
+}
+
+func TestSetPodReady(t *testing.T) {
+	g := NewWithT(t)
+
+	manager := cmanager.New(scheme)
+	manager.AddResourceGroup("foo")
+	c := manager.GetResourceGroup("foo").GetClient()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: metav1.NamespaceSystem}}
+	g.Expect(c.Create(ctx, pod)).To(Succeed())
+
+	// Adds the condition if missing.
+	g.Expect(setPodReady(ctx, c, pod, true)).To(Succeed())
+	got := &corev1.Pod{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pod), got)).To(Succeed())
+	g.Expect(got.Status.Conditions).To(HaveLen(1))
+	g.Expect(got.Status.Conditions[0].Type).To(Equal(corev1.PodReady))
+	g.Expect(got.Status.Conditions[0].Status).To(Equal(corev1.ConditionTrue))
+
+	// Updates the existing condition.
+	g.Expect(setPodReady(ctx, c, got, false)).To(Succeed())
+	got = &corev1.Pod{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pod), got)).To(Succeed())
+	g.Expect(got.Status.Conditions).To(HaveLen(1))
+	g.Expect(got.Status.Conditions[0].Status).To(Equal(corev1.ConditionFalse))
+
+	// Does not update the Pod if the status is unchanged.
+	resourceVersion := got.ResourceVersion
+	g.Expect(setPodReady(ctx, c, got, false)).To(Succeed())
+	got = &corev1.Pod{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pod), got)).To(Succeed())
+	g.Expect(got.ResourceVersion).To(Equal(resourceVersion))
+}
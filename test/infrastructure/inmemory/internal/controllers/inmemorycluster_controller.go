@@ -153,7 +153,7 @@ func (r *InMemoryClusterReconciler) reconcileHotRestart(ctx context.Context) err
 	return nil
 }
 
-func (r *InMemoryClusterReconciler) reconcileNormal(_ context.Context, cluster *clusterv1.Cluster, inMemoryCluster *infrav1.InMemoryCluster) error {
+func (r *InMemoryClusterReconciler) reconcileNormal(ctx context.Context, cluster *clusterv1.Cluster, inMemoryCluster *infrav1.InMemoryCluster) error {
 	// Compute the resource group unique name.
 	resourceGroup := klog.KObj(cluster).String()
 
@@ -176,6 +176,34 @@ func (r *InMemoryClusterReconciler) reconcileNormal(_ context.Context, cluster *
 		return errors.Wrap(err, "failed to init the listener for the workload cluster")
 	}
 
+	// Inject faults in the requests served by the API servers of the workload cluster, if any.
+	errorRate, latency, err := getAPIServerFaults(inMemoryCluster)
+	if err != nil {
+		return err
+	}
+	if err := r.APIServerMux.SetAPIServerFaults(resourceGroup, errorRate, latency); err != nil {
+		return errors.Wrap(err, "failed to set faults for the workload cluster")
+	}
+
+	// Set the latency of the requests served by the API servers of the workload cluster, if any.
+	latencyProfile, err := getAPIServerLatency(inMemoryCluster)
+	if err != nil {
+		return err
+	}
+	if err := r.APIServerMux.SetAPIServerLatencyProfile(resourceGroup, latencyProfile); err != nil {
+		return errors.Wrap(err, "failed to set the latency profile for the workload cluster")
+	}
+
+	// Add synthetic objects to the workload cluster, if any.
+	var scale *infrav1.InMemoryClusterScale
+	if inMemoryCluster.Spec.Behaviour != nil {
+		scale = inMemoryCluster.Spec.Behaviour.Scale
+	}
+	cloudClient := r.CloudManager.GetResourceGroup(resourceGroup).GetClient()
+	if err := reconcileSyntheticObjects(ctx, cloudClient, scale); err != nil {
+		return err
+	}
+
 	// Surface the control plane endpoint
 	if inMemoryCluster.Spec.ControlPlaneEndpoint.Host == "" {
 		inMemoryCluster.Spec.ControlPlaneEndpoint.Host = listener.Host()
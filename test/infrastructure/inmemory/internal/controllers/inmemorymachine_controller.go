@@ -22,7 +22,6 @@ import (
 	"crypto/rsa"
 	"fmt"
 	"math/rand"
-	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -198,7 +197,10 @@ func (r *InMemoryMachineReconciler) reconcileNormal(ctx context.Context, cluster
 	// Make sure bootstrap data is available and populated.
 	// NOTE: we are not using bootstrap data, but we wait for it in order to simulate a real machine
 	// provisioning workflow.
-	if machine.Spec.Bootstrap.DataSecretName == nil {
+	// NOTE: InMemoryMachines belonging to an InMemoryMachinePool are created only after the bootstrap data
+	// of the MachinePool is available, and MachinePool Machines do not have bootstrap data on their own.
+	_, isMachinePoolMachine := inMemoryMachine.Labels[clusterv1.MachinePoolNameLabel]
+	if machine.Spec.Bootstrap.DataSecretName == nil && !isMachinePoolMachine {
 		if !util.IsControlPlaneMachine(machine) && !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
 			conditions.MarkFalse(inMemoryMachine, infrav1.VMProvisionedCondition, infrav1.WaitingControlPlaneInitializedReason, clusterv1.ConditionSeverityInfo, "")
 			log.Info("Waiting for the control plane to be initialized")
@@ -269,17 +271,19 @@ func (r *InMemoryMachineReconciler) reconcileNormalCloudMachine(ctx context.Cont
 	if inMemoryMachine.Spec.Behaviour != nil && inMemoryMachine.Spec.Behaviour.VM != nil {
 		x := inMemoryMachine.Spec.Behaviour.VM.Provisioning
 
-		provisioningDuration = x.StartupDuration.Duration
-		if x.StartupJitter != "" {
-			jitter, err := strconv.ParseFloat(x.StartupJitter, 64)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to parse VM's StartupJitter")
-			}
-			if jitter > 0.0 {
-				provisioningDuration += time.Duration(rand.Float64() * jitter * float64(provisioningDuration)) //nolint:gosec // Intentionally using a weak random number generator here.
-			}
+		d, err := randomDuration(x.StartupDuration.Duration, x.StartupJitter, x.StartupDistribution)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to parse VM's StartupJitter")
 		}
+		provisioningDuration = d
+	}
+
+	// Add the provisioning delay injected as a fault, if any.
+	faults, err := getMachineFaults(inMemoryMachine)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
+	provisioningDuration += faults.provisioningDelay
 
 	start := cloudMachine.CreationTimestamp
 	now := time.Now()
@@ -307,16 +311,11 @@ func (r *InMemoryMachineReconciler) reconcileNormalNode(ctx context.Context, clu
 	if inMemoryMachine.Spec.Behaviour != nil && inMemoryMachine.Spec.Behaviour.Node != nil {
 		x := inMemoryMachine.Spec.Behaviour.Node.Provisioning
 
-		provisioningDuration = x.StartupDuration.Duration
-		if x.StartupJitter != "" {
-			jitter, err := strconv.ParseFloat(x.StartupJitter, 64)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to parse node's StartupJitter")
-			}
-			if jitter > 0.0 {
-				provisioningDuration += time.Duration(rand.Float64() * jitter * float64(provisioningDuration)) //nolint:gosec // Intentionally using a weak random number generator here.
-			}
+		d, err := randomDuration(x.StartupDuration.Duration, x.StartupJitter, x.StartupDistribution)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to parse node's StartupJitter")
 		}
+		provisioningDuration = d
 	}
 
 	start := conditions.Get(inMemoryMachine, infrav1.VMProvisionedCondition).LastTransitionTime
@@ -349,10 +348,8 @@ func (r *InMemoryMachineReconciler) reconcileNormalNode(ctx context.Context, clu
 			},
 		},
 	}
+	setNodeInfo(node, nodeVersion(machine, inMemoryMachine), nodeOperatingSystem(machine, inMemoryMachine))
 	if util.IsControlPlaneMachine(machine) {
-		if node.Labels == nil {
-			node.Labels = map[string]string{}
-		}
 		node.Labels["node-role.kubernetes.io/control-plane"] = ""
 	}
 
@@ -368,6 +365,20 @@ func (r *InMemoryMachineReconciler) reconcileNormalNode(ctx context.Context, clu
 		}
 	}
 
+	// If the node (the kubelet) has been killed or it is hanging, it stops posting the node status, and the node
+	// becomes not ready; when the node is restarted or resumed, it becomes ready again.
+	faults, err := getMachineFaults(inMemoryMachine)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	nodeReady := corev1.ConditionTrue
+	if faults.killed.Has(infrav1.NodeFaultComponent) || faults.hanging.Has(infrav1.NodeFaultComponent) {
+		nodeReady = corev1.ConditionUnknown
+	}
+	if err := setNodeReady(ctx, cloudClient, node, nodeReady); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to update Node")
+	}
+
 	conditions.MarkTrue(inMemoryMachine, infrav1.NodeProvisionedCondition)
 	return ctrl.Result{}, nil
 }
@@ -376,6 +387,58 @@ func calculateProviderID(inMemoryMachine *infrav1.InMemoryMachine) string {
 	return fmt.Sprintf("in-memory://%s", inMemoryMachine.Name)
 }
 
+// nodeOperatingSystem returns the operating system reported by the Node hosted on an InMemoryMachine.
+// NOTE: Control plane machines always use linux.
+func nodeOperatingSystem(machine *clusterv1.Machine, inMemoryMachine *infrav1.InMemoryMachine) infrav1.NodeOperatingSystem {
+	if util.IsControlPlaneMachine(machine) || inMemoryMachine.Spec.Behaviour == nil || inMemoryMachine.Spec.Behaviour.Node == nil || inMemoryMachine.Spec.Behaviour.Node.OperatingSystem == "" {
+		return infrav1.LinuxNodeOperatingSystem
+	}
+	return inMemoryMachine.Spec.Behaviour.Node.OperatingSystem
+}
+
+// nodeVersion returns the kubelet version reported by the Node hosted on an InMemoryMachine.
+// NOTE: MachinePool Machines do not have a version, so the version of the MachinePool at the time
+// the InMemoryMachine has been created is used instead.
+func nodeVersion(machine *clusterv1.Machine, inMemoryMachine *infrav1.InMemoryMachine) *string {
+	if machine.Spec.Version != nil {
+		return machine.Spec.Version
+	}
+	if version, ok := inMemoryMachine.Annotations[infrav1.MachinePoolVersionAnnotation]; ok {
+		return &version
+	}
+	return nil
+}
+
+// setNodeInfo sets the labels and the system info a kubelet running on the given operating system reports for its Node.
+func setNodeInfo(node *corev1.Node, version *string, operatingSystem infrav1.NodeOperatingSystem) {
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	node.Labels[corev1.LabelHostname] = node.Name
+	node.Labels[corev1.LabelOSStable] = string(operatingSystem)
+	node.Labels[corev1.LabelArchStable] = "amd64"
+
+	node.Status.NodeInfo = corev1.NodeSystemInfo{
+		OperatingSystem:         string(operatingSystem),
+		Architecture:            "amd64",
+		ContainerRuntimeVersion: "containerd://1.7.2",
+	}
+	if version != nil {
+		node.Status.NodeInfo.KubeletVersion = *version
+		node.Status.NodeInfo.KubeProxyVersion = *version
+	}
+
+	switch operatingSystem {
+	case infrav1.WindowsNodeOperatingSystem:
+		node.Labels[corev1.LabelWindowsBuild] = "10.0.17763"
+		node.Status.NodeInfo.OSImage = "Windows Server 2019 Datacenter"
+		node.Status.NodeInfo.KernelVersion = "10.0.17763.4131"
+	default:
+		node.Status.NodeInfo.OSImage = "Ubuntu 22.04.2 LTS"
+		node.Status.NodeInfo.KernelVersion = "5.15.0-76-generic"
+	}
+}
+
 func (r *InMemoryMachineReconciler) reconcileNormalETCD(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine, inMemoryMachine *infrav1.InMemoryMachine) (ctrl.Result, error) {
 	// No-op if the machine is not a control plane machine.
 	if !util.IsControlPlaneMachine(machine) {
@@ -392,16 +455,11 @@ func (r *InMemoryMachineReconciler) reconcileNormalETCD(ctx context.Context, clu
 	if inMemoryMachine.Spec.Behaviour != nil && inMemoryMachine.Spec.Behaviour.Etcd != nil {
 		x := inMemoryMachine.Spec.Behaviour.Etcd.Provisioning
 
-		provisioningDuration = x.StartupDuration.Duration
-		if x.StartupJitter != "" {
-			jitter, err := strconv.ParseFloat(x.StartupJitter, 64)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to parse etcd's StartupJitter")
-			}
-			if jitter > 0.0 {
-				provisioningDuration += time.Duration(rand.Float64() * jitter * float64(provisioningDuration)) //nolint:gosec // Intentionally using a weak random number generator here.
-			}
+		d, err := randomDuration(x.StartupDuration.Duration, x.StartupJitter, x.StartupDistribution)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to parse etcd's StartupJitter")
 		}
+		provisioningDuration = d
 	}
 
 	start := conditions.Get(inMemoryMachine, infrav1.NodeProvisionedCondition).LastTransitionTime
@@ -489,6 +547,31 @@ func (r *InMemoryMachineReconciler) reconcileNormalETCD(ctx context.Context, clu
 		}
 	}
 
+	// If the etcd member has been killed, remove it from the server and mark the etcd pod as not ready;
+	// when the etcd member is restarted, it is added back to the server.
+	faults, err := getMachineFaults(inMemoryMachine)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	killed := faults.killed.Has(infrav1.EtcdFaultComponent)
+	if err := setPodReady(ctx, cloudClient, etcdPod, !killed); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to update etcd Pod")
+	}
+	if killed {
+		if r.APIServerMux.HasEtcdMember(resourceGroup, etcdMember) {
+			if err := r.APIServerMux.DeleteEtcdMember(resourceGroup, etcdMember); err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to kill etcd member")
+			}
+		}
+		conditions.MarkTrue(inMemoryMachine, infrav1.EtcdProvisionedCondition)
+		return ctrl.Result{}, nil
+	}
+
+	// If the etcd member is hanging, requests to it do not get a response.
+	if err := r.APIServerMux.SetEtcdMemberHanging(resourceGroup, etcdMember, faults.hanging.Has(infrav1.EtcdFaultComponent)); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to set etcd member hanging")
+	}
+
 	// If there is not yet an etcd member listener for this machine, add it to the server.
 	if !r.APIServerMux.HasEtcdMember(resourceGroup, etcdMember) {
 		// Getting the etcd CA
@@ -596,16 +679,11 @@ func (r *InMemoryMachineReconciler) reconcileNormalAPIServer(ctx context.Context
 	if inMemoryMachine.Spec.Behaviour != nil && inMemoryMachine.Spec.Behaviour.APIServer != nil {
 		x := inMemoryMachine.Spec.Behaviour.APIServer.Provisioning
 
-		provisioningDuration = x.StartupDuration.Duration
-		if x.StartupJitter != "" {
-			jitter, err := strconv.ParseFloat(x.StartupJitter, 64)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to parse API server's StartupJitter")
-			}
-			if jitter > 0.0 {
-				provisioningDuration += time.Duration(rand.Float64() * jitter * float64(provisioningDuration)) //nolint:gosec // Intentionally using a weak random number generator here.
-			}
+		d, err := randomDuration(x.StartupDuration.Duration, x.StartupJitter, x.StartupDistribution)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to parse API server's StartupJitter")
 		}
+		provisioningDuration = d
 	}
 
 	start := conditions.Get(inMemoryMachine, infrav1.NodeProvisionedCondition).LastTransitionTime
@@ -656,6 +734,31 @@ func (r *InMemoryMachineReconciler) reconcileNormalAPIServer(ctx context.Context
 		}
 	}
 
+	// If the API server has been killed, remove it from the server and mark the API server pod as not ready;
+	// when the API server is restarted, it is added back to the server.
+	faults, err := getMachineFaults(inMemoryMachine)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	killed := faults.killed.Has(infrav1.APIServerFaultComponent)
+	if err := setPodReady(ctx, cloudClient, apiServerPod, !killed); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to update apiServer Pod")
+	}
+	if killed {
+		if r.APIServerMux.HasAPIServer(resourceGroup, apiServer) {
+			if err := r.APIServerMux.DeleteAPIServer(resourceGroup, apiServer); err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to kill API server")
+			}
+		}
+		conditions.MarkTrue(inMemoryMachine, infrav1.APIServerProvisionedCondition)
+		return ctrl.Result{}, nil
+	}
+
+	// If the API server is hanging, requests to it do not get a response.
+	if err := r.APIServerMux.SetAPIServerHanging(resourceGroup, apiServer, faults.hanging.Has(infrav1.APIServerFaultComponent)); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to set API server hanging")
+	}
+
 	// If there is not yet an API server listener for this machine.
 	if !r.APIServerMux.HasAPIServer(resourceGroup, apiServer) {
 		// Getting the Kubernetes CA
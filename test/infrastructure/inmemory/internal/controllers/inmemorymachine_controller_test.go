@@ -34,6 +34,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -250,6 +251,60 @@ func TestReconcileNormalNode(t *testing.T) {
 	})
 }
 
+func TestSetNodeInfo(t *testing.T) {
+	windows := &infrav1.InMemoryMachine{
+		Spec: infrav1.InMemoryMachineSpec{
+			Behaviour: &infrav1.InMemoryMachineBehaviour{
+				Node: &infrav1.InMemoryNodeBehaviour{
+					OperatingSystem: infrav1.WindowsNodeOperatingSystem,
+				},
+			},
+		},
+	}
+
+	t.Run("worker machines report the operating system of the InMemoryMachine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(nodeOperatingSystem(workerMachine, &infrav1.InMemoryMachine{})).To(Equal(infrav1.LinuxNodeOperatingSystem))
+		g.Expect(nodeOperatingSystem(workerMachine, windows)).To(Equal(infrav1.WindowsNodeOperatingSystem))
+	})
+
+	t.Run("control plane machines always report linux", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(nodeOperatingSystem(cpMachine, windows)).To(Equal(infrav1.LinuxNodeOperatingSystem))
+	})
+
+	t.Run("MachinePool machines report the version of the MachinePool", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := workerMachine.DeepCopy()
+		machine.Spec.Version = pointer.String("v1.28.0")
+		g.Expect(nodeVersion(machine, &infrav1.InMemoryMachine{})).To(Equal(pointer.String("v1.28.0")))
+
+		inMemoryMachine := &infrav1.InMemoryMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{infrav1.MachinePoolVersionAnnotation: "v1.27.3"},
+			},
+		}
+		g.Expect(nodeVersion(workerMachine, inMemoryMachine)).To(Equal(pointer.String("v1.27.3")))
+		g.Expect(nodeVersion(workerMachine, &infrav1.InMemoryMachine{})).To(BeNil())
+	})
+
+	t.Run("windows nodes have windows labels and system info", func(t *testing.T) {
+		g := NewWithT(t)
+
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "baz"}}
+		setNodeInfo(node, pointer.String("v1.28.0"), infrav1.WindowsNodeOperatingSystem)
+
+		g.Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelOSStable, "windows"))
+		g.Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelHostname, "baz"))
+		g.Expect(node.Labels).To(HaveKey(corev1.LabelWindowsBuild))
+		g.Expect(node.Status.NodeInfo.OperatingSystem).To(Equal("windows"))
+		g.Expect(node.Status.NodeInfo.KubeletVersion).To(Equal("v1.28.0"))
+	})
+}
+
 func TestReconcileNormalEtcd(t *testing.T) {
 	inMemoryMachineWithNodeNotYetProvisioned := &infrav1.InMemoryMachine{
 		ObjectMeta: metav1.ObjectMeta{
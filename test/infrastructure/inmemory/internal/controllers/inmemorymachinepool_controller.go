@@ -0,0 +1,423 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	utilexp "sigs.k8s.io/cluster-api/exp/util"
+	"sigs.k8s.io/cluster-api/internal/util/hash"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/labels/format"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// InMemoryMachinePoolReconciler reconciles a InMemoryMachinePool object.
+type InMemoryMachinePoolReconciler struct {
+	client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=inmemorymachinepools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=inmemorymachinepools/status;inmemorymachinepools/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=inmemorymachines,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch;delete
+
+// Reconcile handles InMemoryMachinePool events.
+func (r *InMemoryMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, rerr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the InMemoryMachinePool instance
+	inMemoryMachinePool := &infrav1.InMemoryMachinePool{}
+	if err := r.Client.Get(ctx, req.NamespacedName, inMemoryMachinePool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Fetch the MachinePool.
+	machinePool, err := utilexp.GetOwnerMachinePool(ctx, r.Client, inMemoryMachinePool.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if machinePool == nil {
+		log.Info("Waiting for MachinePool Controller to set OwnerRef on InMemoryMachinePool")
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("MachinePool", klog.KObj(machinePool))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	// Fetch the Cluster.
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machinePool.ObjectMeta)
+	if err != nil {
+		log.Info("InMemoryMachinePool owner MachinePool is missing cluster label or cluster does not exist")
+		return ctrl.Result{}, err
+	}
+	if cluster == nil {
+		log.Info(fmt.Sprintf("Please associate this machine pool with a cluster using the label %s: <name of cluster>", clusterv1.ClusterNameLabel))
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("Cluster", klog.KObj(cluster))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	// Return early if the object or Cluster is paused.
+	if annotations.IsPaused(cluster, inMemoryMachinePool) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(inMemoryMachinePool, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always attempt to Patch the InMemoryMachinePool object and status after each reconciliation.
+	defer func() {
+		if err := patchHelper.Patch(ctx, inMemoryMachinePool); err != nil {
+			log.Error(err, "failed to patch InMemoryMachinePool")
+			if rerr == nil {
+				rerr = err
+			}
+		}
+	}()
+
+	// Handle deleted machine pools
+	if !inMemoryMachinePool.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDelete(ctx, cluster, machinePool, inMemoryMachinePool)
+	}
+
+	// Add finalizer first if not set to avoid the race condition between init and delete.
+	// Note: Finalizers in general can only be added when the deletionTimestamp is not set.
+	if !controllerutil.ContainsFinalizer(inMemoryMachinePool, infrav1.MachinePoolFinalizer) {
+		controllerutil.AddFinalizer(inMemoryMachinePool, infrav1.MachinePoolFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	// Handle non-deleted machine pools
+	return ctrl.Result{}, r.reconcileNormal(ctx, cluster, machinePool, inMemoryMachinePool)
+}
+
+// reconcileNormal creates and deletes InMemoryMachines to match the MachinePool replicas, so the MachinePool controller
+// can surface them as MachinePool Machines; when the MachinePool version, bootstrap config or the InMemoryMachinePool
+// template change, the InMemoryMachines are replaced one at a time, surging by one.
+func (r *InMemoryMachinePoolReconciler) reconcileNormal(ctx context.Context, cluster *clusterv1.Cluster, machinePool *expv1.MachinePool, inMemoryMachinePool *infrav1.InMemoryMachinePool) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Make sure bootstrap data is available and populated.
+	// NOTE: we are not using bootstrap data, but we wait for it in order to simulate a real machine pool
+	// provisioning workflow; InMemoryMachines belonging to the pool do not wait for bootstrap data.
+	if machinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
+		log.Info("Waiting for the Bootstrap provider controller to set bootstrap data")
+		return nil
+	}
+
+	replicas := 1
+	if machinePool.Spec.Replicas != nil {
+		replicas = int(*machinePool.Spec.Replicas)
+	}
+
+	templateHash, err := machinePoolTemplateHash(machinePool, inMemoryMachinePool)
+	if err != nil {
+		return err
+	}
+
+	inMemoryMachines, err := r.getInMemoryMachines(ctx, cluster, machinePool)
+	if err != nil {
+		return err
+	}
+
+	// InMemoryMachines whose MachinePool Machine is being deleted are going away, even if their deletion is not started yet.
+	machineList := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machineList, client.InNamespace(machinePool.Namespace), client.MatchingLabels(machinePoolMachineLabels(cluster, machinePool))); err != nil {
+		return errors.Wrapf(err, "failed to list Machines for MachinePool %s", klog.KObj(machinePool))
+	}
+	deleting := sets.Set[string]{}
+	for _, machine := range machineList.Items {
+		if !machine.DeletionTimestamp.IsZero() {
+			deleting.Insert(machine.Spec.InfrastructureRef.Name)
+		}
+	}
+
+	current := []*infrav1.InMemoryMachine{}
+	updated, ready := 0, 0
+	inMemoryMachinePool.Spec.ProviderIDList = []string{}
+	for i := range inMemoryMachines {
+		inMemoryMachine := &inMemoryMachines[i]
+		if !inMemoryMachine.DeletionTimestamp.IsZero() || deleting.Has(inMemoryMachine.Name) {
+			continue
+		}
+		current = append(current, inMemoryMachine)
+		if inMemoryMachine.Annotations[infrav1.MachinePoolTemplateHashAnnotation] == templateHash {
+			updated++
+		}
+		if isInMemoryMachineReady(inMemoryMachine) {
+			ready++
+			inMemoryMachinePool.Spec.ProviderIDList = append(inMemoryMachinePool.Spec.ProviderIDList, *inMemoryMachine.Spec.ProviderID)
+		}
+	}
+	sort.Strings(inMemoryMachinePool.Spec.ProviderIDList)
+
+	if inMemoryMachinePool.Spec.ProviderID == "" {
+		// This is a fake provider ID which does not tie back to any in-memory infrastructure.
+		inMemoryMachinePool.Spec.ProviderID = fmt.Sprintf("in-memory://%s-imp-%s", cluster.Name, inMemoryMachinePool.Name)
+	}
+	inMemoryMachinePool.Status.Replicas = int32(len(current))
+	inMemoryMachinePool.Status.UpdatedReplicas = int32(updated)
+	inMemoryMachinePool.Status.Ready = len(current) == replicas && ready == replicas
+	inMemoryMachinePool.Status.InfrastructureMachineKind = "InMemoryMachine"
+
+	rollingOut := updated < len(current)
+	switch {
+	case len(current) < replicas:
+		for i := len(current); i < replicas; i++ {
+			if err := r.createInMemoryMachine(ctx, cluster, machinePool, inMemoryMachinePool, templateHash); err != nil {
+				return err
+			}
+		}
+	case len(current) > replicas:
+		// When rolling out, wait for the surge InMemoryMachine to be ready before deleting an outdated one.
+		if rollingOut && ready < len(current) {
+			log.V(4).Info("Waiting for InMemoryMachines to be ready before deleting outdated ones")
+			return nil
+		}
+		for _, inMemoryMachine := range inMemoryMachinesToDelete(current, templateHash, len(current)-replicas) {
+			if err := r.deleteInMemoryMachine(ctx, inMemoryMachine); err != nil {
+				return err
+			}
+		}
+	case rollingOut:
+		// Surge by one only when all the InMemoryMachines are ready and there are no deletions in progress,
+		// so outdated InMemoryMachines are replaced one at a time.
+		if ready < len(current) || len(current) < len(inMemoryMachines) {
+			log.V(4).Info("Waiting for InMemoryMachines to be ready before replacing outdated ones")
+			return nil
+		}
+		if err := r.createInMemoryMachine(ctx, cluster, machinePool, inMemoryMachinePool, templateHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryMachinePoolReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster, machinePool *expv1.MachinePool, inMemoryMachinePool *infrav1.InMemoryMachinePool) error {
+	inMemoryMachines, err := r.getInMemoryMachines(ctx, cluster, machinePool)
+	if err != nil {
+		return err
+	}
+
+	// Wait for all the InMemoryMachines of the pool to be gone before removing the finalizer.
+	for i := range inMemoryMachines {
+		inMemoryMachine := &inMemoryMachines[i]
+		if !inMemoryMachine.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.deleteInMemoryMachine(ctx, inMemoryMachine); err != nil {
+			return err
+		}
+	}
+	if len(inMemoryMachines) > 0 {
+		ctrl.LoggerFrom(ctx).Info("Waiting for InMemoryMachines to be deleted", "count", len(inMemoryMachines))
+		return nil
+	}
+
+	controllerutil.RemoveFinalizer(inMemoryMachinePool, infrav1.MachinePoolFinalizer)
+	return nil
+}
+
+func (r *InMemoryMachinePoolReconciler) getInMemoryMachines(ctx context.Context, cluster *clusterv1.Cluster, machinePool *expv1.MachinePool) ([]infrav1.InMemoryMachine, error) {
+	inMemoryMachineList := &infrav1.InMemoryMachineList{}
+	if err := r.Client.List(ctx, inMemoryMachineList, client.InNamespace(machinePool.Namespace), client.MatchingLabels(machinePoolMachineLabels(cluster, machinePool))); err != nil {
+		return nil, errors.Wrapf(err, "failed to list InMemoryMachines for MachinePool %s", klog.KObj(machinePool))
+	}
+	return inMemoryMachineList.Items, nil
+}
+
+func (r *InMemoryMachinePoolReconciler) createInMemoryMachine(ctx context.Context, cluster *clusterv1.Cluster, machinePool *expv1.MachinePool, inMemoryMachinePool *infrav1.InMemoryMachinePool, templateHash string) error {
+	inMemoryMachine := &infrav1.InMemoryMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", inMemoryMachinePool.Name),
+			Namespace:    inMemoryMachinePool.Namespace,
+			Labels:       machinePoolMachineLabels(cluster, machinePool),
+			Annotations: map[string]string{
+				infrav1.MachinePoolTemplateHashAnnotation: templateHash,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: infrav1.GroupVersion.String(),
+					Kind:       "InMemoryMachinePool",
+					Name:       inMemoryMachinePool.Name,
+					UID:        inMemoryMachinePool.UID,
+				},
+			},
+		},
+		Spec: infrav1.InMemoryMachineSpec{
+			Behaviour: inMemoryMachinePool.Spec.Template.Behaviour.DeepCopy(),
+		},
+	}
+	if machinePool.Spec.Template.Spec.Version != nil {
+		inMemoryMachine.Annotations[infrav1.MachinePoolVersionAnnotation] = *machinePool.Spec.Template.Spec.Version
+	}
+
+	if err := r.Client.Create(ctx, inMemoryMachine); err != nil {
+		return errors.Wrapf(err, "failed to create InMemoryMachine for InMemoryMachinePool %s", klog.KObj(inMemoryMachinePool))
+	}
+	ctrl.LoggerFrom(ctx).Info("Created InMemoryMachine", "InMemoryMachine", klog.KObj(inMemoryMachine))
+	return nil
+}
+
+// deleteInMemoryMachine deletes the MachinePool Machine owning an InMemoryMachine, so the InMemoryMachine is deleted
+// as part of the Machine deletion; if there is no Machine yet, the InMemoryMachine is deleted directly.
+func (r *InMemoryMachinePoolReconciler) deleteInMemoryMachine(ctx context.Context, inMemoryMachine *infrav1.InMemoryMachine) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	machine, err := util.GetOwnerMachine(ctx, r.Client, inMemoryMachine.ObjectMeta)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get the owner Machine for InMemoryMachine %s", klog.KObj(inMemoryMachine))
+	}
+	if machine != nil {
+		if !machine.DeletionTimestamp.IsZero() {
+			return nil
+		}
+		log.Info("Deleting Machine", "Machine", klog.KObj(machine), "InMemoryMachine", klog.KObj(inMemoryMachine))
+		if err := r.Client.Delete(ctx, machine); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete Machine %s", klog.KObj(machine))
+		}
+		return nil
+	}
+
+	log.Info("Deleting InMemoryMachine", "InMemoryMachine", klog.KObj(inMemoryMachine))
+	if err := r.Client.Delete(ctx, inMemoryMachine); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete InMemoryMachine %s", klog.KObj(inMemoryMachine))
+	}
+	return nil
+}
+
+// inMemoryMachinesToDelete returns the InMemoryMachines to delete when scaling down a pool, preferring
+// outdated InMemoryMachines, then InMemoryMachines not ready yet, then the newest ones.
+func inMemoryMachinesToDelete(inMemoryMachines []*infrav1.InMemoryMachine, templateHash string, count int) []*infrav1.InMemoryMachine {
+	priority := func(m *infrav1.InMemoryMachine) int {
+		switch {
+		case m.Annotations[infrav1.MachinePoolTemplateHashAnnotation] != templateHash:
+			return 0
+		case !isInMemoryMachineReady(m):
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	candidates := append([]*infrav1.InMemoryMachine{}, inMemoryMachines...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if pi, pj := priority(candidates[i]), priority(candidates[j]); pi != pj {
+			return pi < pj
+		}
+		return candidates[j].CreationTimestamp.Before(&candidates[i].CreationTimestamp)
+	})
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+	return candidates[:count]
+}
+
+// machinePoolTemplateHash returns the hash of the MachinePool version, bootstrap config and InMemoryMachinePool
+// template; InMemoryMachines created from a different hash must be replaced.
+func machinePoolTemplateHash(machinePool *expv1.MachinePool, inMemoryMachinePool *infrav1.InMemoryMachinePool) (string, error) {
+	h, err := hash.Compute(struct {
+		Version   *string
+		Bootstrap clusterv1.Bootstrap
+		Template  infrav1.InMemoryMachinePoolMachineTemplate
+	}{
+		Version:   machinePool.Spec.Template.Spec.Version,
+		Bootstrap: machinePool.Spec.Template.Spec.Bootstrap,
+		Template:  inMemoryMachinePool.Spec.Template,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to compute the template hash for InMemoryMachinePool %s", klog.KObj(inMemoryMachinePool))
+	}
+	return fmt.Sprintf("%d", h), nil
+}
+
+// machinePoolMachineLabels returns the labels the MachinePool controller uses to find the InMemoryMachines of a pool.
+func machinePoolMachineLabels(cluster *clusterv1.Cluster, machinePool *expv1.MachinePool) map[string]string {
+	return map[string]string{
+		clusterv1.ClusterNameLabel:     cluster.Name,
+		clusterv1.MachinePoolNameLabel: format.MustFormatValue(machinePool.Name),
+	}
+}
+
+func isInMemoryMachineReady(inMemoryMachine *infrav1.InMemoryMachine) bool {
+	return inMemoryMachine.Status.Ready && inMemoryMachine.Spec.ProviderID != nil
+}
+
+// SetupWithManager will add watches for this controller.
+func (r *InMemoryMachinePoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	clusterToInMemoryMachinePools, err := util.ClusterToTypedObjectsMapper(mgr.GetClient(), &infrav1.InMemoryMachinePoolList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	err = ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.InMemoryMachinePool{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Watches(
+			&expv1.MachinePool{},
+			handler.EnqueueRequestsFromMapFunc(utilexp.MachinePoolToInfrastructureMapFunc(
+				infrav1.GroupVersion.WithKind("InMemoryMachinePool"), ctrl.LoggerFrom(ctx))),
+		).
+		Watches(
+			&infrav1.InMemoryMachine{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &infrav1.InMemoryMachinePool{}),
+		).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(clusterToInMemoryMachinePools),
+			builder.WithPredicates(
+				predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx)),
+			),
+		).Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+)
+
+func TestInMemoryMachinePoolReconcileNormal(t *testing.T) {
+	poolScheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(poolScheme)
+	_ = expv1.AddToScheme(poolScheme)
+	_ = infrav1.AddToScheme(poolScheme)
+
+	newMachinePool := func(replicas int32, version string) *expv1.MachinePool {
+		return &expv1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "mp", Namespace: metav1.NamespaceDefault},
+			Spec: expv1.MachinePoolSpec{
+				ClusterName: cluster.Name,
+				Replicas:    pointer.Int32(replicas),
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						ClusterName: cluster.Name,
+						Version:     pointer.String(version),
+						Bootstrap:   clusterv1.Bootstrap{DataSecretName: pointer.String("mp-bootstrap")},
+					},
+				},
+			},
+		}
+	}
+	inMemoryMachinePool := &infrav1.InMemoryMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "imp", Namespace: metav1.NamespaceDefault},
+	}
+	listInMemoryMachines := func(g *WithT, c client.Client) []infrav1.InMemoryMachine {
+		inMemoryMachineList := &infrav1.InMemoryMachineList{}
+		g.Expect(c.List(ctx, inMemoryMachineList, client.MatchingLabels{clusterv1.MachinePoolNameLabel: "mp"})).To(Succeed())
+		return inMemoryMachineList.Items
+	}
+	setReady := func(g *WithT, c client.Client) {
+		for _, m := range listInMemoryMachines(g, c) {
+			m := m
+			m.Spec.ProviderID = pointer.String(calculateProviderID(&m))
+			m.Status.Ready = true
+			g.Expect(c.Update(ctx, &m)).To(Succeed())
+		}
+	}
+
+	t.Run("waits for bootstrap data", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(poolScheme).Build()
+		r := InMemoryMachinePoolReconciler{Client: c}
+
+		machinePool := newMachinePool(2, "v1.28.0")
+		machinePool.Spec.Template.Spec.Bootstrap.DataSecretName = nil
+		g.Expect(r.reconcileNormal(ctx, cluster, machinePool, inMemoryMachinePool.DeepCopy())).To(Succeed())
+		g.Expect(listInMemoryMachines(g, c)).To(BeEmpty())
+	})
+
+	t.Run("scales up and reports the ready InMemoryMachines", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(poolScheme).Build()
+		r := InMemoryMachinePoolReconciler{Client: c}
+		machinePool := newMachinePool(3, "v1.28.0")
+
+		pool := inMemoryMachinePool.DeepCopy()
+		g.Expect(r.reconcileNormal(ctx, cluster, machinePool, pool)).To(Succeed())
+		inMemoryMachines := listInMemoryMachines(g, c)
+		g.Expect(inMemoryMachines).To(HaveLen(3))
+		for _, m := range inMemoryMachines {
+			g.Expect(m.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, cluster.Name))
+			g.Expect(m.Annotations).To(HaveKeyWithValue(infrav1.MachinePoolVersionAnnotation, "v1.28.0"))
+			g.Expect(m.OwnerReferences).To(ConsistOf(HaveField("Kind", "InMemoryMachinePool")))
+		}
+		g.Expect(pool.Status.InfrastructureMachineKind).To(Equal("InMemoryMachine"))
+		g.Expect(pool.Status.Ready).To(BeFalse())
+
+		setReady(g, c)
+		g.Expect(r.reconcileNormal(ctx, cluster, machinePool, pool)).To(Succeed())
+		g.Expect(listInMemoryMachines(g, c)).To(HaveLen(3))
+		g.Expect(pool.Spec.ProviderIDList).To(HaveLen(3))
+		g.Expect(pool.Status.Replicas).To(Equal(int32(3)))
+		g.Expect(pool.Status.UpdatedReplicas).To(Equal(int32(3)))
+		g.Expect(pool.Status.Ready).To(BeTrue())
+	})
+
+	t.Run("replaces outdated InMemoryMachines one at a time", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(poolScheme).Build()
+		r := InMemoryMachinePoolReconciler{Client: c}
+
+		pool := inMemoryMachinePool.DeepCopy()
+		g.Expect(r.reconcileNormal(ctx, cluster, newMachinePool(2, "v1.27.3"), pool)).To(Succeed())
+		setReady(g, c)
+
+		// Upgrading the MachinePool surges a new InMemoryMachine.
+		machinePool := newMachinePool(2, "v1.28.0")
+		g.Expect(r.reconcileNormal(ctx, cluster, machinePool, pool)).To(Succeed())
+		g.Expect(listInMemoryMachines(g, c)).To(HaveLen(3))
+		g.Expect(pool.Status.UpdatedReplicas).To(Equal(int32(0)))
+
+		// Outdated InMemoryMachines are not deleted until the new one is ready.
+		g.Expect(r.reconcileNormal(ctx, cluster, machinePool, pool)).To(Succeed())
+		g.Expect(listInMemoryMachines(g, c)).To(HaveLen(3))
+		g.Expect(pool.Status.UpdatedReplicas).To(Equal(int32(1)))
+
+		// Once ready, an outdated InMemoryMachine is deleted.
+		setReady(g, c)
+		g.Expect(r.reconcileNormal(ctx, cluster, machinePool, pool)).To(Succeed())
+		inMemoryMachines := listInMemoryMachines(g, c)
+		g.Expect(inMemoryMachines).To(HaveLen(2))
+		g.Expect(inMemoryMachines).To(ContainElement(HaveField("ObjectMeta.Annotations", HaveKeyWithValue(infrav1.MachinePoolVersionAnnotation, "v1.27.3"))))
+		g.Expect(inMemoryMachines).To(ContainElement(HaveField("ObjectMeta.Annotations", HaveKeyWithValue(infrav1.MachinePoolVersionAnnotation, "v1.28.0"))))
+
+		// Repeating the process replaces the last outdated InMemoryMachine.
+		for i := 0; i < 3; i++ {
+			setReady(g, c)
+			g.Expect(r.reconcileNormal(ctx, cluster, machinePool, pool)).To(Succeed())
+		}
+		inMemoryMachines = listInMemoryMachines(g, c)
+		g.Expect(inMemoryMachines).To(HaveLen(2))
+		g.Expect(inMemoryMachines).To(HaveEach(HaveField("ObjectMeta.Annotations", HaveKeyWithValue(infrav1.MachinePoolVersionAnnotation, "v1.28.0"))))
+		g.Expect(pool.Status.UpdatedReplicas).To(Equal(int32(2)))
+	})
+}
+
+func TestInMemoryMachinesToDelete(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	newInMemoryMachine := func(name, hash string, ready bool, age time.Duration) *infrav1.InMemoryMachine {
+		return &infrav1.InMemoryMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+				Annotations:       map[string]string{infrav1.MachinePoolTemplateHashAnnotation: hash},
+			},
+			Spec:   infrav1.InMemoryMachineSpec{ProviderID: pointer.String(name)},
+			Status: infrav1.InMemoryMachineStatus{Ready: ready},
+		}
+	}
+	inMemoryMachines := []*infrav1.InMemoryMachine{
+		newInMemoryMachine("old", "1", true, time.Hour),
+		newInMemoryMachine("newer", "2", true, time.Minute),
+		newInMemoryMachine("newest", "2", true, time.Second),
+		newInMemoryMachine("outdated", "1", true, time.Minute),
+		newInMemoryMachine("not-ready", "2", false, time.Hour),
+	}
+
+	names := func(inMemoryMachines []*infrav1.InMemoryMachine) []string {
+		n := []string{}
+		for _, m := range inMemoryMachines {
+			n = append(n, m.Name)
+		}
+		return n
+	}
+	g.Expect(names(inMemoryMachinesToDelete(inMemoryMachines, "2", 4))).To(Equal([]string{"outdated", "old", "not-ready", "newest"}))
+	g.Expect(names(inMemoryMachinesToDelete(inMemoryMachines, "2", 10))).To(HaveLen(5))
+}
@@ -213,6 +213,8 @@ var (
 			},
 		},
 	}
+	// appsV1ResourceList is the value returned by /apis/apps/v1 discovery call.
+	// Note: This must contain all APIs required by CAPI.
 	appsV1ResourceList = &metav1.APIResourceList{
 		GroupVersion: "apps/v1",
 		APIResources: []metav1.APIResource{
@@ -258,4 +260,12 @@ var (
 			},
 		},
 	}
+
+	// apiResourceLists are all the APIResourceLists served by the API server; they are used for
+	// aggregated discovery, OpenAPI and for mapping requests to the corresponding GroupVersionKind.
+	apiResourceLists = []*metav1.APIResourceList{
+		corev1APIResourceList,
+		rbacv1APIResourceList,
+		appsV1ResourceList,
+	}
 )
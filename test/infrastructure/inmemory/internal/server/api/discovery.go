@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+	apidiscoveryv2beta1 "k8s.io/api/apidiscovery/v2beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// aggregatedDiscoveryContentType is the content type of aggregated discovery responses.
+const aggregatedDiscoveryContentType = "application/json;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList"
+
+// acceptsAggregatedDiscovery is true if the client asks for aggregated discovery, like client-go and kubectl do
+// since Kubernetes v1.26; other clients get the legacy discovery documents.
+func acceptsAggregatedDiscovery(req *restful.Request) bool {
+	for _, accept := range strings.Split(req.HeaderParameter("Accept"), ",") {
+		params := map[string]bool{}
+		for _, p := range strings.Split(accept, ";") {
+			params[strings.TrimSpace(p)] = true
+		}
+		if params["g=apidiscovery.k8s.io"] && params["v=v2beta1"] && params["as=APIGroupDiscoveryList"] {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAggregatedDiscovery writes the aggregated discovery document for the legacy API group (when legacy is true),
+// or for all the other API groups (when legacy is false).
+func writeAggregatedDiscovery(resp *restful.Response, legacy bool) {
+	discovery := &apidiscoveryv2beta1.APIGroupDiscoveryList{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apidiscoveryv2beta1.SchemeGroupVersion.String(),
+			Kind:       "APIGroupDiscoveryList",
+		},
+	}
+
+	groups := map[string]int{}
+	for _, resourceList := range apiResourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
+			return
+		}
+		if (gv.Group == "") != legacy {
+			continue
+		}
+
+		i, ok := groups[gv.Group]
+		if !ok {
+			discovery.Items = append(discovery.Items, apidiscoveryv2beta1.APIGroupDiscovery{
+				ObjectMeta: metav1.ObjectMeta{Name: gv.Group},
+			})
+			i = len(discovery.Items) - 1
+			groups[gv.Group] = i
+		}
+		discovery.Items[i].Versions = append(discovery.Items[i].Versions, apiVersionDiscovery(gv, resourceList))
+	}
+
+	if err := resp.WriteHeaderAndJson(http.StatusOK, discovery, aggregatedDiscoveryContentType); err != nil {
+		_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+func apiVersionDiscovery(gv schema.GroupVersion, resourceList *metav1.APIResourceList) apidiscoveryv2beta1.APIVersionDiscovery {
+	version := apidiscoveryv2beta1.APIVersionDiscovery{
+		Version:   gv.Version,
+		Freshness: apidiscoveryv2beta1.DiscoveryFreshnessCurrent,
+	}
+	for _, r := range resourceList.APIResources {
+		scope := apidiscoveryv2beta1.ScopeCluster
+		if r.Namespaced {
+			scope = apidiscoveryv2beta1.ScopeNamespace
+		}
+		singularName := r.SingularName
+		if singularName == "" {
+			singularName = strings.ToLower(r.Kind)
+		}
+		version.Resources = append(version.Resources, apidiscoveryv2beta1.APIResourceDiscovery{
+			Resource:         r.Name,
+			ResponseKind:     &metav1.GroupVersionKind{Group: gv.Group, Version: gv.Version, Kind: r.Kind},
+			Scope:            scope,
+			SingularResource: singularName,
+			Verbs:            r.Verbs,
+			ShortNames:       r.ShortNames,
+		})
+	}
+	return version
+}
+
+// getAPIResourceListFor returns the APIResourceList for a group version, if served by the API server.
+func getAPIResourceListFor(gv schema.GroupVersion) *metav1.APIResourceList {
+	for _, resourceList := range apiResourceLists {
+		if resourceList.GroupVersion == gv.String() {
+			return resourceList
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+// APIServerFaults defines the faults to be injected in the requests served by the API servers of a workload cluster.
+type APIServerFaults struct {
+	// Hang makes requests hang until the client gives up.
+	Hang bool
+
+	// ErrorRate is the fraction of requests failing with a 503 Service Unavailable error.
+	ErrorRate float64
+
+	// Latency is the delay added to all the responses.
+	Latency time.Duration
+}
+
+// FaultsResolver defines a func that returns the faults to be injected in the requests
+// for a workloadCluster/resourceGroup.
+type FaultsResolver func(resourceGroup string) APIServerFaults
+
+// faultInjection injects faults in the requests served by the API servers of a workload cluster.
+func (h *apiServerHandler) faultInjection(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	if h.faultsResolver == nil {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+
+	wclName, err := h.resourceGroupResolver(req.Request.Host)
+	if err != nil {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	faults := h.faultsResolver(wclName)
+
+	if faults.Hang {
+		h.log.V(4).Info("Injecting fault: hanging request", "resourceGroup", wclName, "url", req.Request.URL)
+		<-req.Request.Context().Done()
+		return
+	}
+
+	if faults.Latency > 0 {
+		select {
+		case <-time.After(faults.Latency):
+		case <-req.Request.Context().Done():
+			return
+		}
+	}
+
+	if faults.ErrorRate > 0 && rand.Float64() < faults.ErrorRate { //nolint:gosec // Intentionally using a weak random number generator here.
+		h.log.V(4).Info("Injecting fault: failing request", "resourceGroup", wclName, "url", req.Request.URL)
+		_ = resp.WriteErrorString(http.StatusServiceUnavailable, "fault injected by the in-memory provider")
+		return
+	}
+
+	chain.ProcessFilter(req, resp)
+}
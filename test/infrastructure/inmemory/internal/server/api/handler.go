@@ -35,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -57,18 +58,21 @@ import (
 type ResourceGroupResolver func(host string) (string, error)
 
 // NewAPIServerHandler returns an http.Handler for a fake API server.
-func NewAPIServerHandler(manager cmanager.Manager, log logr.Logger, resolver ResourceGroupResolver) http.Handler {
+// If faultsResolver is not nil, it is used to inject faults in the requests.
+func NewAPIServerHandler(manager cmanager.Manager, log logr.Logger, resolver ResourceGroupResolver, faultsResolver FaultsResolver) http.Handler {
 	apiServer := &apiServerHandler{
 		container:             restful.NewContainer(),
 		manager:               manager,
 		log:                   log,
 		resourceGroupResolver: resolver,
+		faultsResolver:        faultsResolver,
 		requestInfoResolver: server.NewRequestInfoResolver(&server.Config{
 			LegacyAPIGroupPrefixes: sets.NewString(server.DefaultLegacyAPIPrefix),
 		}),
 	}
 
 	apiServer.container.Filter(apiServer.globalLogging)
+	apiServer.container.Filter(apiServer.faultInjection)
 
 	ws := new(restful.WebService)
 	ws.Consumes(runtime.ContentTypeJSON)
@@ -83,6 +87,11 @@ func NewAPIServerHandler(manager cmanager.Manager, log logr.Logger, resolver Res
 	ws.Route(ws.GET("/apis").To(apiServer.apisDiscovery))
 	ws.Route(ws.GET("/apis/{group}/{version}").To(apiServer.apisDiscovery))
 
+	// OpenAPI endpoints
+	ws.Route(ws.GET("/openapi/v3").To(apiServer.openAPIV3))
+	ws.Route(ws.GET("/openapi/v3/api/{version}").To(apiServer.openAPIV3GroupVersion))
+	ws.Route(ws.GET("/openapi/v3/apis/{group}/{version}").To(apiServer.openAPIV3GroupVersion))
+
 	// CRUD endpoints (global objects)
 	ws.Route(ws.POST("/api/v1/{resource}").Consumes(runtime.ContentTypeProtobuf).To(apiServer.apiV1Create))
 	ws.Route(ws.GET("/api/v1/{resource}").If(isList).To(apiServer.apiV1List))
@@ -131,6 +140,7 @@ type apiServerHandler struct {
 	manager               cmanager.Manager
 	log                   logr.Logger
 	resourceGroupResolver ResourceGroupResolver
+	faultsResolver        FaultsResolver
 	requestInfoResolver   *request.RequestInfoFactory
 }
 
@@ -191,7 +201,12 @@ func cleanDryRun(u *url.URL) string {
 	return strings.Join(sets.NewString(dryRun...).List(), ",")
 }
 
-func (h *apiServerHandler) apiDiscovery(_ *restful.Request, resp *restful.Response) {
+func (h *apiServerHandler) apiDiscovery(req *restful.Request, resp *restful.Response) {
+	if acceptsAggregatedDiscovery(req) {
+		writeAggregatedDiscovery(resp, true)
+		return
+	}
+
 	if err := resp.WriteEntity(apiVersions); err != nil {
 		_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
 		return
@@ -207,22 +222,21 @@ func (h *apiServerHandler) apiV1Discovery(_ *restful.Request, resp *restful.Resp
 
 func (h *apiServerHandler) apisDiscovery(req *restful.Request, resp *restful.Response) {
 	if req.PathParameter("group") != "" {
-		if req.PathParameter("group") == "rbac.authorization.k8s.io" && req.PathParameter("version") == "v1" {
-			if err := resp.WriteEntity(rbacv1APIResourceList); err != nil {
-				_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
-				return
-			}
+		gv := schema.GroupVersion{Group: req.PathParameter("group"), Version: req.PathParameter("version")}
+		resourceList := getAPIResourceListFor(gv)
+		if resourceList == nil {
+			_ = resp.WriteErrorString(http.StatusNotFound, fmt.Sprintf("discovery info not defined for %s", gv))
 			return
 		}
-		if req.PathParameter("group") == "apps" && req.PathParameter("version") == "v1" {
-			if err := resp.WriteEntity(appsV1ResourceList); err != nil {
-				_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
-				return
-			}
+		if err := resp.WriteEntity(resourceList); err != nil {
+			_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
 			return
 		}
+		return
+	}
 
-		_ = resp.WriteErrorString(http.StatusInternalServerError, fmt.Sprintf("discovery info not defined for %s/%s", req.PathParameter("group"), req.PathParameter("version")))
+	if acceptsAggregatedDiscovery(req) {
+		writeAggregatedDiscovery(resp, false)
 		return
 	}
 
@@ -323,7 +337,32 @@ func (h *apiServerHandler) apiV1List(req *restful.Request, resp *restful.Respons
 		listOpts = append(listOpts, client.MatchingFieldsSelector{Selector: selector})
 	}
 
+	labelSelector, err := labels.Parse(req.QueryParameter("labelSelector"))
+	if err != nil {
+		_ = resp.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	if !labelSelector.Empty() {
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: labelSelector})
+	}
+
+	if limit := req.QueryParameter("limit"); limit != "" {
+		l, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil {
+			_ = resp.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("invalid limit %q: %v", limit, err))
+			return
+		}
+		listOpts = append(listOpts, client.Limit(l))
+	}
+	if continueToken := req.QueryParameter("continue"); continueToken != "" {
+		listOpts = append(listOpts, client.Continue(continueToken))
+	}
+
 	if err := cloudClient.List(ctx, list, listOpts...); err != nil {
+		if status, ok := err.(apierrors.APIStatus); ok || errors.As(err, &status) {
+			_ = resp.WriteHeaderAndEntity(int(status.Status().Code), status)
+			return
+		}
 		_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -632,13 +671,7 @@ func requestToGVK(req *restful.Request) (*schema.GroupVersionKind, error) {
 
 func getAPIResourceList(req *restful.Request) *metav1.APIResourceList {
 	if req.PathParameter("group") != "" {
-		if req.PathParameter("group") == "rbac.authorization.k8s.io" && req.PathParameter("version") == "v1" {
-			return rbacv1APIResourceList
-		}
-		if req.PathParameter("group") == "apps" && req.PathParameter("version") == "v1" {
-			return appsV1ResourceList
-		}
-		return nil
+		return getAPIResourceListFor(schema.GroupVersion{Group: req.PathParameter("group"), Version: req.PathParameter("version")})
 	}
 	return corev1APIResourceList
 }
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// openAPIV3Paths is the document returned by the /openapi/v3 call.
+type openAPIV3Paths struct {
+	Paths map[string]openAPIV3Path `json:"paths"`
+}
+
+type openAPIV3Path struct {
+	ServerRelativeURL string `json:"serverRelativeURL"`
+}
+
+// openAPIV3Document is a minimal OpenAPI v3 document for a group version.
+// NOTE: The document contains only the schemas of the kinds served by the API server; schemas accept any field,
+// because the in-memory API server does not validate objects, but they carry the x-kubernetes-group-version-kind
+// extension, which is what clients like kubectl use to lookup schemas.
+type openAPIV3Document struct {
+	OpenAPI    string                 `json:"openapi"`
+	Info       openAPIInfo            `json:"info"`
+	Paths      map[string]interface{} `json:"paths"`
+	Components openAPIComponents      `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]interface{} `json:"schemas"`
+}
+
+func (h *apiServerHandler) openAPIV3(_ *restful.Request, resp *restful.Response) {
+	paths := openAPIV3Paths{Paths: map[string]openAPIV3Path{}}
+	for _, resourceList := range apiResourceLists {
+		path := openAPIV3PathFor(resourceList.GroupVersion)
+		paths.Paths[path] = openAPIV3Path{ServerRelativeURL: "/openapi/v3/" + path}
+	}
+
+	if err := resp.WriteEntity(paths); err != nil {
+		_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+func (h *apiServerHandler) openAPIV3GroupVersion(req *restful.Request, resp *restful.Response) {
+	gv := schema.GroupVersion{Group: req.PathParameter("group"), Version: req.PathParameter("version")}
+	resourceList := getAPIResourceListFor(gv)
+	if resourceList == nil {
+		_ = resp.WriteErrorString(http.StatusNotFound, fmt.Sprintf("OpenAPI not defined for %s", gv))
+		return
+	}
+
+	doc := openAPIV3Document{
+		OpenAPI:    "3.0.0",
+		Info:       openAPIInfo{Title: "Kubernetes", Version: "unversioned"},
+		Paths:      map[string]interface{}{},
+		Components: openAPIComponents{Schemas: map[string]interface{}{}},
+	}
+	for _, r := range resourceList.APIResources {
+		doc.Components.Schemas[openAPISchemaName(gv, r.Kind)] = map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"apiVersion": map[string]interface{}{"type": "string"},
+				"kind":       map[string]interface{}{"type": "string"},
+				"metadata":   map[string]interface{}{"type": "object", "x-kubernetes-preserve-unknown-fields": true},
+			},
+			"x-kubernetes-preserve-unknown-fields": true,
+			"x-kubernetes-group-version-kind": []map[string]string{
+				{"group": gv.Group, "version": gv.Version, "kind": r.Kind},
+			},
+		}
+	}
+
+	if err := resp.WriteEntity(doc); err != nil {
+		_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// openAPIV3PathFor returns the path of the OpenAPI v3 document for a group version, e.g. api/v1 or apis/apps/v1.
+func openAPIV3PathFor(groupVersion string) string {
+	if !strings.Contains(groupVersion, "/") {
+		return "api/" + groupVersion
+	}
+	return "apis/" + groupVersion
+}
+
+// openAPISchemaName returns the name of the schema for a kind, following the naming used by the Kubernetes API server
+// for built-in types, e.g. io.k8s.api.apps.v1.Deployment.
+func openAPISchemaName(gv schema.GroupVersion, kind string) string {
+	group := strings.Split(gv.Group, ".")[0]
+	if group == "" {
+		group = "core"
+	}
+	return fmt.Sprintf("io.k8s.api.%s.%s.%s", group, gv.Version, kind)
+}
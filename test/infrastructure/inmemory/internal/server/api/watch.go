@@ -20,10 +20,15 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/emicklei/go-restful/v3"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
@@ -34,12 +39,29 @@ import (
 type Event struct {
 	Type   watch.EventType `json:"type,omitempty"`
 	Object runtime.Object  `json:"object,omitempty"`
+
+	// oldObject is the object before a Modified event, used to check if the object started or stopped
+	// matching the selectors of a watch.
+	oldObject client.Object
 }
 
 // WatchEventDispatcher dispatches events for a single resourceGroup.
 type WatchEventDispatcher struct {
 	resourceGroup string
 	events        chan *Event
+
+	// namespace, labelSelector and fieldSelector restrict the events sent to the objects matching them.
+	namespace     string
+	labelSelector labels.Selector
+	fieldSelector fields.Selector
+
+	// initialEvents are sent before any other event.
+	initialEvents []*Event
+
+	// resourceVersion is the resource version initialEvents are computed at; events for changes at or before
+	// resourceVersion are not sent, because they are already reflected in initialEvents or they have been
+	// already seen by the client.
+	resourceVersion uint64
 }
 
 // OnCreate dispatches Create events.
@@ -54,13 +76,14 @@ func (m *WatchEventDispatcher) OnCreate(resourceGroup string, o client.Object) {
 }
 
 // OnUpdate dispatches Update events.
-func (m *WatchEventDispatcher) OnUpdate(resourceGroup string, _, o client.Object) {
+func (m *WatchEventDispatcher) OnUpdate(resourceGroup string, oldObj, o client.Object) {
 	if resourceGroup != m.resourceGroup {
 		return
 	}
 	m.events <- &Event{
-		Type:   watch.Modified,
-		Object: o,
+		Type:      watch.Modified,
+		Object:    o,
+		oldObject: oldObj,
 	}
 }
 
@@ -102,12 +125,26 @@ func (h *apiServerHandler) watchForResource(req *restful.Request, resp *restful.
 	watcher := &WatchEventDispatcher{
 		resourceGroup: resourceGroup,
 		events:        events,
+		namespace:     req.PathParameter("namespace"),
+	}
+	if watcher.labelSelector, err = labels.Parse(req.QueryParameter("labelSelector")); err != nil {
+		return errors.Wrapf(err, "invalid labelSelector %q", req.QueryParameter("labelSelector"))
+	}
+	if watcher.fieldSelector, err = fields.ParseSelector(req.QueryParameter("fieldSelector")); err != nil {
+		return errors.Wrapf(err, "invalid fieldSelector %q", req.QueryParameter("fieldSelector"))
 	}
 
 	if err := i.AddEventHandler(watcher); err != nil {
 		return err
 	}
 
+	// NOTE: Initial events are computed after adding the event handler, so changes happening in the meantime are not lost;
+	// events for changes already reflected in the initial events are dropped by Run.
+	if err := h.initialWatchEvents(req, resourceGroup, gvk, watcher); err != nil {
+		_ = i.RemoveEventHandler(watcher)
+		return err
+	}
+
 	// Defer cleanup which removes the event handler and ensures the channel is empty of events.
 	defer func() {
 		// Doing this to ensure the channel is empty.
@@ -127,6 +164,108 @@ func (h *apiServerHandler) watchForResource(req *restful.Request, resp *restful.
 	return watcher.Run(ctx, queryTimeout, resp)
 }
 
+// initialWatchEvents computes the events to be sent when a watch starts, mimicking what a real API server does:
+//   - when resourceVersion is not set or it is "0", an ADDED event for every existing object.
+//   - when resourceVersion is set, a MODIFIED event for every object changed after resourceVersion; this allows
+//     clients, e.g. informers, to not miss changes happening between a list and the subsequent watch.
+//
+// NOTE: The in-memory API server does not keep a history of changes, so objects deleted after resourceVersion
+// are not notified, and the "too old resource version" error is never returned.
+func (h *apiServerHandler) initialWatchEvents(req *restful.Request, resourceGroup string, gvk schema.GroupVersionKind, watcher *WatchEventDispatcher) error {
+	var since uint64
+	if resourceVersion := req.QueryParameter("resourceVersion"); resourceVersion != "" {
+		var err error
+		since, err = strconv.ParseUint(resourceVersion, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid resourceVersion %q", resourceVersion)
+		}
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion(gvk.GroupVersion().String())
+	list.SetKind(fmt.Sprintf("%sList", gvk.Kind))
+
+	listOpts := []client.ListOption{}
+	if watcher.namespace != "" {
+		listOpts = append(listOpts, client.InNamespace(watcher.namespace))
+	}
+	if !watcher.fieldSelector.Empty() {
+		listOpts = append(listOpts, client.MatchingFieldsSelector{Selector: watcher.fieldSelector})
+	}
+	if !watcher.labelSelector.Empty() {
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: watcher.labelSelector})
+	}
+	if err := h.manager.GetResourceGroup(resourceGroup).GetClient().List(req.Request.Context(), list, listOpts...); err != nil {
+		return err
+	}
+
+	// NOTE: The list resource version is the last resource version in the resource group when the list is computed.
+	listResourceVersion, err := strconv.ParseUint(list.GetResourceVersion(), 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid list resourceVersion %q", list.GetResourceVersion())
+	}
+	watcher.resourceVersion = listResourceVersion
+	if since > watcher.resourceVersion {
+		watcher.resourceVersion = since
+	}
+
+	watcher.initialEvents = []*Event{}
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if since == 0 {
+			watcher.initialEvents = append(watcher.initialEvents, &Event{Type: watch.Added, Object: obj})
+			continue
+		}
+		if resourceVersion, err := strconv.ParseUint(obj.GetResourceVersion(), 10, 64); err == nil && resourceVersion > since {
+			watcher.initialEvents = append(watcher.initialEvents, &Event{Type: watch.Modified, Object: obj})
+		}
+	}
+	return nil
+}
+
+// filter returns the event to be sent for an event received from the informer, if any.
+// Events for changes already reflected in the initial events are dropped, as well as events for objects not matching
+// the selectors of the watch; like in a real API server, a Modified event for an object that starts or stops matching
+// the selectors is sent as an Added or a Deleted event.
+func (m *WatchEventDispatcher) filter(event *Event) (*Event, bool) {
+	o, ok := event.Object.(client.Object)
+	if !ok {
+		return event, true
+	}
+	if resourceVersion, err := strconv.ParseUint(o.GetResourceVersion(), 10, 64); err == nil && resourceVersion <= m.resourceVersion {
+		return nil, false
+	}
+
+	matches := m.matches(o)
+	if event.Type != watch.Modified || event.oldObject == nil {
+		return event, matches
+	}
+	switch oldMatches := m.matches(event.oldObject); {
+	case matches && !oldMatches:
+		return &Event{Type: watch.Added, Object: o}, true
+	case !matches && oldMatches:
+		return &Event{Type: watch.Deleted, Object: o}, true
+	}
+	return event, matches
+}
+
+// matches returns true if an object matches the namespace and the selectors of the watch.
+// NOTE: Like for lists, the only field selector supported is `spec.nodeName` on pods.
+func (m *WatchEventDispatcher) matches(o client.Object) bool {
+	if m.namespace != "" && o.GetNamespace() != m.namespace {
+		return false
+	}
+	if m.labelSelector != nil && !m.labelSelector.Empty() && !m.labelSelector.Matches(labels.Set(o.GetLabels())) {
+		return false
+	}
+	if pod, ok := o.(*corev1.Pod); ok && m.fieldSelector != nil && !m.fieldSelector.Empty() {
+		if !m.fieldSelector.Matches(fields.Set{"spec.nodeName": pod.Spec.NodeName}) {
+			return false
+		}
+	}
+	return true
+}
+
 // Run serves a series of encoded events via HTTP with Transfer-Encoding: chunked.
 func (m *WatchEventDispatcher) Run(ctx context.Context, timeout string, w http.ResponseWriter) error {
 	flusher, ok := w.(http.Flusher)
@@ -149,6 +288,14 @@ func (m *WatchEventDispatcher) Run(ctx context.Context, timeout string, w http.R
 	ctx, cancel := context.WithTimeout(ctx, seconds)
 	defer cancel()
 	defer timeoutTimer.Stop()
+
+	for _, event := range m.initialEvents {
+		if err := resp.WriteEntity(event); err != nil {
+			_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
+		}
+	}
+	flusher.Flush()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -160,8 +307,10 @@ func (m *WatchEventDispatcher) Run(ctx context.Context, timeout string, w http.R
 				// End of results.
 				return nil
 			}
-			if err := resp.WriteEntity(event); err != nil {
-				_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
+			if event, ok := m.filter(event); ok {
+				if err := resp.WriteEntity(event); err != nil {
+					_ = resp.WriteErrorString(http.StatusInternalServerError, err.Error())
+				}
 			}
 			if len(m.events) == 0 {
 				flusher.Flush()
@@ -42,14 +42,20 @@ import (
 // request targets.
 type ResourceGroupResolver func(host string) (string, error)
 
+// HangingMemberResolver defines a func that returns true if an etcd member of a workloadCluster/resourceGroup
+// is hanging, identified by the name of the corresponding etcd Pod.
+type HangingMemberResolver func(resourceGroup, etcdPodName string) bool
+
 // NewEtcdServerHandler returns an http.Handler for fake etcd members.
-func NewEtcdServerHandler(manager cmanager.Manager, log logr.Logger, resolver ResourceGroupResolver) http.Handler {
+// If hangingMemberResolver is not nil, it is used to make requests to hanging etcd members hang.
+func NewEtcdServerHandler(manager cmanager.Manager, log logr.Logger, resolver ResourceGroupResolver, hangingMemberResolver HangingMemberResolver) http.Handler {
 	svr := grpc.NewServer()
 
 	baseSvr := &baseServer{
 		manager:               manager,
 		log:                   log,
 		resourceGroupResolver: resolver,
+		hangingMemberResolver: hangingMemberResolver,
 	}
 
 	clusterServerSrv := &clusterServerServer{
@@ -271,6 +277,7 @@ type baseServer struct {
 	manager               cmanager.Manager
 	log                   logr.Logger
 	resourceGroupResolver ResourceGroupResolver
+	hangingMemberResolver HangingMemberResolver
 }
 
 func (b *baseServer) getResourceGroupAndMember(ctx context.Context) (resourceGroup string, etcdMember string, err error) {
@@ -286,6 +293,13 @@ func (b *baseServer) getResourceGroupAndMember(ctx context.Context) (resourceGro
 	}
 	// Calculate the etcd member name by trimming the "etcd-" prefix from ":authority" metadata.
 	etcdMember = strings.TrimPrefix(strings.Join(md.Get(":authority"), ","), "etcd-")
+
+	// If the etcd member is hanging, do not respond until the client gives up.
+	if b.hangingMemberResolver != nil && b.hangingMemberResolver(resourceGroup, fmt.Sprintf("etcd-%s", etcdMember)) {
+		b.log.V(4).Info("Injecting fault: hanging etcd request", "resourceGroup", resourceGroup, "etcdMember", etcdMember)
+		<-ctx.Done()
+		return "", "", ctx.Err()
+	}
 	return
 }
 
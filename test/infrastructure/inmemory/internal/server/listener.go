@@ -22,6 +22,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"net"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -52,6 +53,15 @@ type WorkloadClusterListener struct {
 	etcdMembers             sets.Set[string]
 	etcdServingCertificates map[string]*tls.Certificate
 
+	// Faults injected in the workload cluster.
+	hangingAPIServers  sets.Set[string]
+	hangingEtcdMembers sets.Set[string]
+	apiServerErrorRate float64
+	apiServerLatency   time.Duration
+
+	// Latency profile of the requests served by the workload cluster.
+	apiServerLatencyProfile func() time.Duration
+
 	listener net.Listener
 }
 
@@ -179,8 +179,8 @@ func (m *WorkloadClustersMux) mixedHandler() http.Handler {
 	}
 
 	// build the handlers for API server and etcd.
-	apiHandler := api.NewAPIServerHandler(m.manager, m.log, resourceGroupResolver)
-	etcdHandler := etcd.NewEtcdServerHandler(m.manager, m.log, resourceGroupResolver)
+	apiHandler := api.NewAPIServerHandler(m.manager, m.log, resourceGroupResolver, m.apiServerFaults)
+	etcdHandler := etcd.NewEtcdServerHandler(m.manager, m.log, resourceGroupResolver, m.isEtcdMemberHanging)
 
 	// Creates the mixed handler combining the two above depending on
 	// the type of request being processed
@@ -306,6 +306,8 @@ func (m *WorkloadClustersMux) initWorkloadClusterListenerWithPortLocked(wclName
 		apiServers:              sets.New[string](),
 		etcdMembers:             sets.New[string](),
 		etcdServingCertificates: map[string]*tls.Certificate{},
+		hangingAPIServers:       sets.New[string](),
+		hangingEtcdMembers:      sets.New[string](),
 	}
 	m.workloadClusterListeners[wclName] = wcl
 	m.workloadClusterNameByHost[wcl.HostPort()] = wclName
@@ -435,6 +437,7 @@ func (m *WorkloadClustersMux) DeleteAPIServer(wclName, podName string) error {
 		return errors.Errorf("workloadClusterListener with name %s must be initialized before removing an APIserver", wclName)
 	}
 	wcl.apiServers.Delete(podName)
+	wcl.hangingAPIServers.Delete(podName)
 	m.log.Info("APIServer instance removed from the workloadClusterListener", "listenerName", wclName, "address", wcl.Address(), "podName", podName)
 
 	if wcl.apiServers.Len() < 1 && wcl.listener != nil {
@@ -513,12 +516,116 @@ func (m *WorkloadClustersMux) DeleteEtcdMember(wclName, podName string) error {
 		return errors.Errorf("workloadClusterListener with name %s must be initialized before removing an etcd member", wclName)
 	}
 	wcl.etcdMembers.Delete(podName)
+	wcl.hangingEtcdMembers.Delete(podName)
 	delete(wcl.etcdServingCertificates, podName)
 	m.log.Info("Etcd member removed from WorkloadClusterListener", "listenerName", wclName, "address", wcl.Address(), "podName", podName)
 
 	return nil
 }
 
+// SetAPIServerHanging mimics an API server instance behind the WorkloadClusterListener hanging, or resuming from hanging;
+// requests to the WorkloadClusterListener hang when all the API server instances are hanging.
+func (m *WorkloadClustersMux) SetAPIServerHanging(wclName, podName string, hanging bool) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	wcl, ok := m.workloadClusterListeners[wclName]
+	if !ok {
+		return errors.Errorf("workloadClusterListener with name %s must be initialized before setting an APIserver hanging", wclName)
+	}
+	if hanging == wcl.hangingAPIServers.Has(podName) {
+		return nil
+	}
+	if hanging {
+		wcl.hangingAPIServers.Insert(podName)
+	} else {
+		wcl.hangingAPIServers.Delete(podName)
+	}
+	m.log.Info("APIServer instance hanging state changed", "listenerName", wclName, "address", wcl.Address(), "podName", podName, "hanging", hanging)
+	return nil
+}
+
+// SetEtcdMemberHanging mimics an etcd member behind the WorkloadClusterListener hanging, or resuming from hanging.
+func (m *WorkloadClustersMux) SetEtcdMemberHanging(wclName, podName string, hanging bool) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	wcl, ok := m.workloadClusterListeners[wclName]
+	if !ok {
+		return errors.Errorf("workloadClusterListener with name %s must be initialized before setting an etcd member hanging", wclName)
+	}
+	if hanging == wcl.hangingEtcdMembers.Has(podName) {
+		return nil
+	}
+	if hanging {
+		wcl.hangingEtcdMembers.Insert(podName)
+	} else {
+		wcl.hangingEtcdMembers.Delete(podName)
+	}
+	m.log.Info("Etcd member hanging state changed", "listenerName", wclName, "address", wcl.Address(), "podName", podName, "hanging", hanging)
+	return nil
+}
+
+// SetAPIServerFaults sets the error rate and the latency of the requests served by the WorkloadClusterListener.
+func (m *WorkloadClustersMux) SetAPIServerFaults(wclName string, errorRate float64, latency time.Duration) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	wcl, ok := m.workloadClusterListeners[wclName]
+	if !ok {
+		return errors.Errorf("workloadClusterListener with name %s must be initialized before setting faults", wclName)
+	}
+	wcl.apiServerErrorRate = errorRate
+	wcl.apiServerLatency = latency
+	return nil
+}
+
+// SetAPIServerLatencyProfile sets a func computing the latency of each request served by the WorkloadClusterListener;
+// the latency computed by the profile adds up to the latency injected as a fault, if any.
+func (m *WorkloadClustersMux) SetAPIServerLatencyProfile(wclName string, profile func() time.Duration) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	wcl, ok := m.workloadClusterListeners[wclName]
+	if !ok {
+		return errors.Errorf("workloadClusterListener with name %s must be initialized before setting a latency profile", wclName)
+	}
+	wcl.apiServerLatencyProfile = profile
+	return nil
+}
+
+// apiServerFaults returns the faults to be injected in the requests served by the WorkloadClusterListener.
+func (m *WorkloadClustersMux) apiServerFaults(wclName string) api.APIServerFaults {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	wcl, ok := m.workloadClusterListeners[wclName]
+	if !ok {
+		return api.APIServerFaults{}
+	}
+	latency := wcl.apiServerLatency
+	if wcl.apiServerLatencyProfile != nil {
+		latency += wcl.apiServerLatencyProfile()
+	}
+	return api.APIServerFaults{
+		Hang:      wcl.apiServers.Len() > 0 && wcl.hangingAPIServers.IsSuperset(wcl.apiServers),
+		ErrorRate: wcl.apiServerErrorRate,
+		Latency:   latency,
+	}
+}
+
+// isEtcdMemberHanging returns true if an etcd member behind the WorkloadClusterListener is hanging.
+func (m *WorkloadClustersMux) isEtcdMemberHanging(wclName, podName string) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	wcl, ok := m.workloadClusterListeners[wclName]
+	if !ok {
+		return false
+	}
+	return wcl.hangingEtcdMembers.Has(podName)
+}
+
 // ListListeners implements api.DebugInfoProvider.
 func (m *WorkloadClustersMux) ListListeners() map[string]string {
 	m.lock.RLock()
@@ -34,9 +34,12 @@ import (
 	"google.golang.org/grpc"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -381,6 +384,55 @@ func TestAPI_PortForward(t *testing.T) {
 	g.Expect(err).ToNot(HaveOccurred())
 }
 
+func TestAPI_Faults(t *testing.T) {
+	t.Parallel()
+	g := NewWithT(t)
+
+	wcmux, c := setupWorkloadClusterListener(g, CustomPorts{
+		// NOTE: make sure to use ports different than other tests, so we can run tests in parallel
+		MinPort:   DefaultMinPort + 500,
+		MaxPort:   DefaultMinPort + 599,
+		DebugPort: DefaultDebugPort + 5,
+	})
+
+	wcl1 := "workload-cluster1"
+	apiServerPod1 := "kube-apiserver-1"
+
+	// no faults
+
+	g.Expect(c.List(ctx, &corev1.NodeList{})).To(Succeed())
+
+	// all the requests fail when the error rate is 1
+
+	err := wcmux.SetAPIServerFaults(wcl1, 1, 0)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	err = c.List(ctx, &corev1.NodeList{})
+	g.Expect(apierrors.IsServiceUnavailable(err)).To(BeTrue())
+
+	err = wcmux.SetAPIServerFaults(wcl1, 0, 0)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(c.List(ctx, &corev1.NodeList{})).To(Succeed())
+
+	// requests do not get a response when all the API servers are hanging
+
+	err = wcmux.SetAPIServerHanging(wcl1, apiServerPod1, true)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 1*time.Second)
+	defer cancel()
+	g.Expect(c.List(timeoutCtx, &corev1.NodeList{})).ToNot(Succeed())
+
+	err = wcmux.SetAPIServerHanging(wcl1, apiServerPod1, false)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(c.List(ctx, &corev1.NodeList{})).To(Succeed())
+
+	err = wcmux.Shutdown(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
 func TestAPI_corev1_Watch(t *testing.T) {
 	g := NewWithT(t)
 
@@ -479,6 +531,185 @@ func TestAPI_corev1_Watch(t *testing.T) {
 	g.Expect(receivedEvents).To(Equal(expectedEvents))
 }
 
+func TestAPI_Discovery(t *testing.T) {
+	t.Parallel()
+	g := NewWithT(t)
+
+	wcmux, _ := setupWorkloadClusterListener(g, CustomPorts{
+		// NOTE: make sure to use ports different than other tests, so we can run tests in parallel
+		MinPort:   DefaultMinPort + 600,
+		MaxPort:   DefaultMinPort + 699,
+		DebugPort: DefaultDebugPort + 6,
+	})
+
+	listener, err := wcmux.InitWorkloadClusterListener("workload-cluster1")
+	g.Expect(err).ToNot(HaveOccurred())
+	restConfig, err := listener.RESTConfig()
+	g.Expect(err).ToNot(HaveOccurred())
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// aggregated discovery
+
+	var contentType string
+	_, err = discoveryClient.RESTClient().Get().AbsPath("/apis").SetHeader("Accept", discovery.AcceptV2Beta1).Do(ctx).ContentType(&contentType).Raw()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(contentType).To(HavePrefix(discovery.AcceptV2Beta1))
+
+	for _, legacy := range []bool{false, true} {
+		discoveryClient.UseLegacyDiscovery = legacy
+
+		groups, resources, err := discoveryClient.ServerGroupsAndResources()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(groups).To(ContainElements(HaveField("Name", ""), HaveField("Name", "apps"), HaveField("Name", "rbac.authorization.k8s.io")))
+		g.Expect(resources).To(ContainElement(And(
+			HaveField("GroupVersion", "apps/v1"),
+			HaveField("APIResources", ContainElement(HaveField("Kind", "Deployment"))),
+		)))
+	}
+
+	_, err = discoveryClient.ServerResourcesForGroupVersion("foo/v1")
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// OpenAPI
+
+	paths, err := discoveryClient.OpenAPIV3().Paths()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(paths).To(HaveKey("api/v1"))
+	g.Expect(paths).To(HaveKey("apis/apps/v1"))
+
+	openAPI, err := paths["apis/apps/v1"].Schema(runtime.ContentTypeJSON)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(openAPI)).To(ContainSubstring("io.k8s.api.apps.v1.Deployment"))
+	g.Expect(string(openAPI)).To(ContainSubstring("x-kubernetes-group-version-kind"))
+
+	err = wcmux.Shutdown(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func TestAPI_ListPaginationAndWatchResume(t *testing.T) {
+	t.Parallel()
+	g := NewWithT(t)
+
+	wcmux, c := setupWorkloadClusterListener(g, CustomPorts{
+		// NOTE: make sure to use ports different than other tests, so we can run tests in parallel
+		MinPort:   DefaultMinPort + 700,
+		MaxPort:   DefaultMinPort + 799,
+		DebugPort: DefaultDebugPort + 7,
+	})
+
+	for _, name := range []string{"foo", "bar", "baz"} {
+		g.Expect(c.Create(ctx, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"name": name}},
+		})).To(Succeed())
+	}
+
+	// list with label selector
+
+	nl := &corev1.NodeList{}
+	g.Expect(c.List(ctx, nl, client.MatchingLabels{"name": "foo"})).To(Succeed())
+	g.Expect(nl.Items).To(HaveLen(1))
+	g.Expect(nl.Items[0].Name).To(Equal("foo"))
+
+	// list with pagination
+
+	names := []string{}
+	nl = &corev1.NodeList{}
+	g.Expect(c.List(ctx, nl, client.Limit(2))).To(Succeed())
+	g.Expect(nl.Items).To(HaveLen(2))
+	g.Expect(nl.Continue).ToNot(BeEmpty())
+	g.Expect(nl.ResourceVersion).ToNot(BeEmpty())
+	for _, n := range nl.Items {
+		names = append(names, n.Name)
+	}
+
+	g.Expect(c.List(ctx, nl, client.Limit(2), client.Continue(nl.Continue))).To(Succeed())
+	g.Expect(nl.Items).To(HaveLen(1))
+	g.Expect(nl.Continue).To(BeEmpty())
+	for _, n := range nl.Items {
+		names = append(names, n.Name)
+	}
+	g.Expect(names).To(Equal([]string{"bar", "baz", "foo"}))
+
+	// watch from a resource version
+
+	g.Expect(c.List(ctx, nl)).To(Succeed())
+	n := nl.Items[0].DeepCopy()
+	n.Annotations = map[string]string{"foo": "bar"}
+	g.Expect(c.Patch(ctx, n, client.MergeFrom(&nl.Items[0]))).To(Succeed())
+
+	watcher, err := c.Watch(ctx, &corev1.NodeList{}, &client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: nl.ResourceVersion}})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	event := <-watcher.ResultChan()
+	g.Expect(event.Type).To(Equal(watch.Modified))
+	g.Expect(event.Object).To(HaveField("ObjectMeta.Name", n.Name))
+	watcher.Stop()
+
+	err = wcmux.Shutdown(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func TestAPI_WatchSelectors(t *testing.T) {
+	t.Parallel()
+	g := NewWithT(t)
+
+	wcmux, c := setupWorkloadClusterListener(g, CustomPorts{
+		// NOTE: make sure to use ports different than other tests, so we can run tests in parallel
+		MinPort:   DefaultMinPort + 800,
+		MaxPort:   DefaultMinPort + 899,
+		DebugPort: DefaultDebugPort + 8,
+	})
+
+	nodes := map[string]*corev1.Node{}
+	for _, name := range []string{"foo", "bar"} {
+		nodes[name] = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"name": name}},
+		}
+		g.Expect(c.Create(ctx, nodes[name])).To(Succeed())
+	}
+
+	watcher, err := c.Watch(ctx, &corev1.NodeList{}, client.MatchingLabels{"name": "foo"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	nextEvent := func() string {
+		select {
+		case event := <-watcher.ResultChan():
+			o, ok := event.Object.(client.Object)
+			if !ok {
+				return string(event.Type)
+			}
+			return fmt.Sprintf("%s/%s", event.Type, o.GetName())
+		case <-time.After(time.Second):
+			return ""
+		}
+	}
+
+	// Only the objects matching the selector are sent when the watch starts, once.
+	g.Expect(nextEvent()).To(Equal("ADDED/foo"))
+
+	// Changes to objects not matching the selector are not sent.
+	bar := nodes["bar"].DeepCopy()
+	bar.Annotations = map[string]string{"foo": "bar"}
+	g.Expect(c.Patch(ctx, bar, client.MergeFrom(nodes["bar"]))).To(Succeed())
+
+	// Objects stopping or starting to match the selector are sent as deleted or added.
+	foo := nodes["foo"].DeepCopy()
+	foo.Labels = map[string]string{"name": "baz"}
+	g.Expect(c.Patch(ctx, foo, client.MergeFrom(nodes["foo"]))).To(Succeed())
+	g.Expect(nextEvent()).To(Equal("DELETED/foo"))
+
+	bar2 := bar.DeepCopy()
+	bar2.Labels = map[string]string{"name": "foo"}
+	g.Expect(c.Patch(ctx, bar2, client.MergeFrom(bar))).To(Succeed())
+	g.Expect(nextEvent()).To(Equal("ADDED/bar"))
+	g.Expect(nextEvent()).To(BeEmpty())
+	watcher.Stop()
+
+	err = wcmux.Shutdown(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
 func setupWorkloadClusterListener(g Gomega, ports CustomPorts) (*WorkloadClustersMux, client.WithWatch) {
 	manager := cmanager.New(scheme)
 
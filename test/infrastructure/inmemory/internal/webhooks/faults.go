@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
+)
+
+// validateMachineFaultAnnotations validates the fault annotations applied to an InMemoryMachine.
+func validateMachineFaultAnnotations(annotations map[string]string) field.ErrorList {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("metadata", "annotations")
+
+	for _, annotation := range []string{v1alpha1.KillFaultAnnotation, v1alpha1.HangFaultAnnotation} {
+		if value, ok := annotations[annotation]; ok {
+			if _, err := v1alpha1.ParseFaultComponents(value); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Key(annotation), value, err.Error()))
+			}
+		}
+	}
+
+	if value, ok := annotations[v1alpha1.ProvisioningDelayFaultAnnotation]; ok {
+		if _, err := v1alpha1.ParseFaultDuration(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(v1alpha1.ProvisioningDelayFaultAnnotation), value, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateClusterFaultAnnotations validates the fault annotations applied to an InMemoryCluster.
+func validateClusterFaultAnnotations(annotations map[string]string) field.ErrorList {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("metadata", "annotations")
+
+	if value, ok := annotations[v1alpha1.APIServerErrorRateFaultAnnotation]; ok {
+		if _, err := v1alpha1.ParseFaultRate(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(v1alpha1.APIServerErrorRateFaultAnnotation), value, err.Error()))
+		}
+	}
+
+	if value, ok := annotations[v1alpha1.APIServerLatencyFaultAnnotation]; ok {
+		if _, err := v1alpha1.ParseFaultDuration(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(v1alpha1.APIServerLatencyFaultAnnotation), value, err.Error()))
+		}
+	}
+
+	return allErrs
+}
@@ -18,7 +18,9 @@ package webhooks
 
 import (
 	"context"
+	"fmt"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -52,16 +54,27 @@ func (webhook *InMemoryCluster) Default(_ context.Context, _ runtime.Object) err
 var _ webhook.CustomValidator = &InMemoryCluster{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *InMemoryCluster) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
-	return nil, nil
+func (webhook *InMemoryCluster) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return webhook.validate(obj)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *InMemoryCluster) ValidateUpdate(_ context.Context, _, _ runtime.Object) (admission.Warnings, error) {
-	return nil, nil
+func (webhook *InMemoryCluster) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return webhook.validate(newObj)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (webhook *InMemoryCluster) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
 	return nil, nil
 }
+
+func (webhook *InMemoryCluster) validate(obj runtime.Object) (admission.Warnings, error) {
+	o, ok := obj.(*v1alpha1.InMemoryCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a InMemoryCluster but got a %T", obj))
+	}
+	if allErrs := validateClusterFaultAnnotations(o.Annotations); len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("InMemoryCluster").GroupKind(), o.Name, allErrs)
+	}
+	return nil, nil
+}
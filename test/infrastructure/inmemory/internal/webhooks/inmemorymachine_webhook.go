@@ -18,12 +18,16 @@ package webhooks
 
 import (
 	"context"
+	"fmt"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
 )
 
@@ -52,16 +56,31 @@ func (webhook *InMemoryMachine) Default(_ context.Context, _ runtime.Object) err
 var _ webhook.CustomValidator = &InMemoryMachine{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *InMemoryMachine) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
-	return nil, nil
+func (webhook *InMemoryMachine) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return webhook.validate(obj)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (webhook *InMemoryMachine) ValidateUpdate(_ context.Context, _, _ runtime.Object) (admission.Warnings, error) {
-	return nil, nil
+func (webhook *InMemoryMachine) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return webhook.validate(newObj)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (webhook *InMemoryMachine) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
 	return nil, nil
 }
+
+func (webhook *InMemoryMachine) validate(obj runtime.Object) (admission.Warnings, error) {
+	o, ok := obj.(*v1alpha1.InMemoryMachine)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a InMemoryMachine but got a %T", obj))
+	}
+	allErrs := validateMachineFaultAnnotations(o.Annotations)
+	if _, ok := o.Labels[clusterv1.MachineControlPlaneLabel]; ok && o.Spec.Behaviour != nil && o.Spec.Behaviour.Node != nil && o.Spec.Behaviour.Node.OperatingSystem == v1alpha1.WindowsNodeOperatingSystem {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "behaviour", "node", "operatingSystem"), o.Spec.Behaviour.Node.OperatingSystem, "control plane machines must use linux"))
+	}
+	if len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("InMemoryMachine").GroupKind(), o.Name, allErrs)
+	}
+	return nil, nil
+}
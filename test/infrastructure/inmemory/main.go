@@ -45,6 +45,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/remote"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/inmemory/controllers"
@@ -81,14 +82,18 @@ var (
 	diagnosticsOptions          = flags.DiagnosticsOptions{}
 	logOptions                  = logs.NewOptions()
 	// CAPIM specific flags.
-	clusterConcurrency int
-	machineConcurrency int
+	clusterConcurrency     int
+	machineConcurrency     int
+	machinePoolConcurrency int
+	stateFile              string
+	stateSnapshotInterval  time.Duration
 )
 
 func init() {
 	// scheme used for operating on the management cluster.
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = clusterv1.AddToScheme(scheme)
+	_ = expv1.AddToScheme(scheme)
 	_ = infrav1.AddToScheme(scheme)
 
 	// scheme used for operating on the cloud resource.
@@ -132,6 +137,15 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&machineConcurrency, "machine-concurrency", 10,
 		"Number of machines to process simultaneously")
 
+	fs.IntVar(&machinePoolConcurrency, "machinepool-concurrency", 10,
+		"Number of machine pools to process simultaneously")
+
+	fs.StringVar(&stateFile, "state-file", "",
+		"Path of the file used to persist the state of the in-memory backend across restarts, e.g. on a persistent volume. If unspecified, the state is not persisted.")
+
+	fs.DurationVar(&stateSnapshotInterval, "state-snapshot-interval", 1*time.Minute,
+		"Interval at which the state of the in-memory backend is written to the state file. Only used when state-file is specified.")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
@@ -279,6 +293,23 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	// Restore the state of the cloud manager and persist it periodically, if required.
+	if stateFile != "" {
+		snapshotter := &cloud.Snapshotter{
+			Manager:  cloudMgr,
+			Path:     stateFile,
+			Interval: stateSnapshotInterval,
+		}
+		if err := snapshotter.Restore(ctx); err != nil {
+			setupLog.Error(err, "unable to restore the state of the cloud manager")
+			os.Exit(1)
+		}
+		if err := mgr.Add(snapshotter); err != nil {
+			setupLog.Error(err, "unable to add the cloud manager snapshotter to the manager")
+			os.Exit(1)
+		}
+	}
+
 	// Start an http server
 	podIP := os.Getenv("POD_IP")
 	apiServerMux, err := server.NewWorkloadClustersMux(cloudMgr, podIP)
@@ -307,6 +338,16 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create controller", "controller", "InMemoryMachine")
 		os.Exit(1)
 	}
+
+	if feature.Gates.Enabled(feature.MachinePool) {
+		if err := (&controllers.InMemoryMachinePoolReconciler{
+			Client:           mgr.GetClient(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(machinePoolConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "InMemoryMachinePool")
+			os.Exit(1)
+		}
+	}
 }
 
 func setupWebhooks(mgr ctrl.Manager) {
@@ -329,6 +370,16 @@ func setupWebhooks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "InMemoryMachineTemplate")
 		os.Exit(1)
 	}
+
+	if err := (&webhooks.InMemoryMachinePool{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "InMemoryMachinePool")
+		os.Exit(1)
+	}
+
+	if err := (&webhooks.InMemoryMachinePoolTemplate{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "InMemoryMachinePoolTemplate")
+		os.Exit(1)
+	}
 }
 
 func concurrency(c int) controller.Options {
@@ -54,3 +54,19 @@ type InMemoryMachineTemplate struct{}
 func (webhook *InMemoryMachineTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return (&webhooks.InMemoryMachineTemplate{}).SetupWebhookWithManager(mgr)
 }
+
+// InMemoryMachinePool implements a validating and defaulting webhook for InMemoryMachinePool.
+type InMemoryMachinePool struct{}
+
+// SetupWebhookWithManager sets up InMemoryMachinePool webhooks.
+func (webhook *InMemoryMachinePool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return (&webhooks.InMemoryMachinePool{}).SetupWebhookWithManager(mgr)
+}
+
+// InMemoryMachinePoolTemplate implements a validating and defaulting webhook for InMemoryMachinePoolTemplate.
+type InMemoryMachinePoolTemplate struct{}
+
+// SetupWebhookWithManager sets up InMemoryMachinePoolTemplate webhooks.
+func (webhook *InMemoryMachinePoolTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return (&webhooks.InMemoryMachinePoolTemplate{}).SetupWebhookWithManager(mgr)
+}
@@ -122,6 +122,29 @@ func ModifyImageTag(imageName, tagName string) (string, error) {
 	return reference.TagNameOnly(namedTagged).String(), nil
 }
 
+// ModifyImageDigest takes an imageName (e.g., repository/image:tag@digest), and returns an image name with updated digest.
+// If digest is empty, the digest is removed from the image name.
+func ModifyImageDigest(imageName, digest string) (string, error) {
+	namedRef, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse image name")
+	}
+
+	ref := reference.TrimNamed(namedRef).String()
+	if tagged, ok := namedRef.(reference.Tagged); ok {
+		ref = fmt.Sprintf("%s:%s", ref, tagged.Tag())
+	}
+	if digest != "" {
+		ref = fmt.Sprintf("%s@%s", ref, digest)
+	}
+
+	updatedRef, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to update image digest")
+	}
+	return updatedRef.String(), nil
+}
+
 // ImageTagIsValid ensures that a given image tag is compliant with the OCI spec.
 func ImageTagIsValid(tagName string) bool {
 	return !ociTagAllowedChars.MatchString(tagName)
@@ -241,3 +241,28 @@ func TestModifyImageTag(t *testing.T) {
 		g.Expect(res).To(Equal("docker.io/dev/image:v1.17.4_build1"))
 	})
 }
+
+func TestModifyImageDigest(t *testing.T) {
+	const digest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+	g := NewWithT(t)
+	t.Run("should add the digest to a tagged image", func(t *testing.T) {
+		res, err := ModifyImageDigest("example.com/image:1.17.3", digest)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal("example.com/image:1.17.3@" + digest))
+	})
+	t.Run("should replace the digest of an image", func(t *testing.T) {
+		res, err := ModifyImageDigest("example.com/image:1.17.3@sha256:1111111111111111111111111111111111111111111111111111111111111111", digest)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal("example.com/image:1.17.3@" + digest))
+	})
+	t.Run("should remove the digest of an image", func(t *testing.T) {
+		res, err := ModifyImageDigest("example.com/image:1.17.3@"+digest, "")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal("example.com/image:1.17.3"))
+	})
+	t.Run("should fail for an invalid digest", func(t *testing.T) {
+		_, err := ModifyImageDigest("example.com/image:1.17.3", "sha256:invalid")
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -21,13 +21,19 @@ import (
 	"net/http"
 	"net/http/pprof"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/pflag"
 	"k8s.io/apiserver/pkg/server/routes"
 	"k8s.io/component-base/logs"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 )
 
+// OpenMetricsPath is the path of the endpoint serving metrics in the OpenMetrics format, which includes the
+// exemplars linking metrics to traces; the /metrics endpoint served by controller-runtime does not include them.
+const OpenMetricsPath = "/metrics/openmetrics"
+
 // DiagnosticsOptions has the options to configure diagnostics.
 type DiagnosticsOptions struct {
 	// MetricsBindAddr
@@ -70,6 +76,9 @@ func GetDiagnosticsOptions(options DiagnosticsOptions) metricsserver.Options {
 		return metricsserver.Options{
 			BindAddress:   options.DiagnosticsAddress,
 			SecureServing: false,
+			ExtraHandlers: map[string]http.Handler{
+				OpenMetricsPath: openMetricsHandler(),
+			},
 		}
 	}
 
@@ -81,6 +90,7 @@ func GetDiagnosticsOptions(options DiagnosticsOptions) metricsserver.Options {
 		SecureServing:  true,
 		FilterProvider: filters.WithAuthenticationAndAuthorization,
 		ExtraHandlers: map[string]http.Handler{
+			OpenMetricsPath: openMetricsHandler(),
 			// Add handler to dynamically change log level.
 			"/debug/flags/v": routes.StringFlagPutHandler(logs.GlogSetter),
 			// Add pprof handler.
@@ -92,3 +102,12 @@ func GetDiagnosticsOptions(options DiagnosticsOptions) metricsserver.Options {
 		},
 	}
 }
+
+// openMetricsHandler returns a handler serving the metrics of the controller-runtime metrics registry,
+// in the OpenMetrics format if requested by the client.
+func openMetricsHandler() http.Handler {
+	return promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{
+		ErrorHandling:     promhttp.HTTPErrorOnError,
+		EnableOpenMetrics: true,
+	})
+}
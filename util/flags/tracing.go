@@ -0,0 +1,43 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package flags implements the webhook server TLS options utilities.
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// TracingOptions has the options to configure OpenTelemetry tracing.
+type TracingOptions struct {
+	Endpoint     string
+	Insecure     bool
+	SamplingRate float64
+}
+
+// AddTracingOptions adds the tracing flags to the flag set.
+func AddTracingOptions(fs *pflag.FlagSet, options *TracingOptions) {
+	fs.StringVar(&options.Endpoint, "tracing-endpoint", "",
+		"The address of the OTLP gRPC endpoint traces are exported to, e.g. otel-collector.observability:4317. "+
+			"If not set, tracing is disabled.")
+
+	fs.BoolVar(&options.Insecure, "tracing-insecure", false,
+		"Export traces to the OTLP gRPC endpoint without TLS.")
+
+	fs.Float64Var(&options.SamplingRate, "tracing-sampling-rate", 1,
+		"The fraction of the traces to sample, between 0 and 1. Spans with a sampled parent, e.g. the Runtime Extension "+
+			"calls of a sampled reconcile, are always sampled.")
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InformersSynced returns a Checker which fails until the informers for the given objects, e.g. the objects
+// watched by a controller, are synced.
+func InformersSynced(informers cache.Informers, objs ...client.Object) Checker {
+	return func(req *http.Request) (string, error) {
+		notSynced := []string{}
+		for _, obj := range objs {
+			informer, err := informers.GetInformer(req.Context(), obj, cache.BlockUntilSynced(false))
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to get the informer for %s", kind(obj))
+			}
+			if !informer.HasSynced() {
+				notSynced = append(notSynced, kind(obj))
+			}
+		}
+		if len(notSynced) > 0 {
+			return "", errors.Errorf("informers for %s not synced", strings.Join(notSynced, ", "))
+		}
+		return fmt.Sprintf("%d informers synced", len(objs)), nil
+	}
+}
+
+// kind returns the kind of a typed object.
+func kind(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+}
+
+// CertificateValid returns a Checker which fails if the certificate in the given PEM file, e.g. the serving
+// certificate of the webhook server, cannot be read, is not valid yet or is expired.
+// The file is read on every check, so certificates rotated on disk are checked as well.
+func CertificateValid(certFile string) Checker {
+	return func(_ *http.Request) (string, error) {
+		certPEM, err := os.ReadFile(certFile) //nolint:gosec // The path is configured by the user.
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read certificate %s", certFile)
+		}
+		block, _ := pem.Decode(certPEM)
+		if block == nil {
+			return "", errors.Errorf("failed to decode certificate %s", certFile)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse certificate %s", certFile)
+		}
+
+		now := time.Now()
+		if now.Before(cert.NotBefore) {
+			return "", errors.Errorf("certificate %s is not valid before %s", certFile, cert.NotBefore.UTC().Format(time.RFC3339))
+		}
+		if now.After(cert.NotAfter) {
+			return "", errors.Errorf("certificate %s expired at %s", certFile, cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+		return fmt.Sprintf("certificate valid until %s", cert.NotAfter.UTC().Format(time.RFC3339)), nil
+	}
+}
+
+// LeaderElection returns a Checker reporting if the manager is the leader, given the channel closed when the
+// manager is elected leader, e.g. ctrl.Manager.Elected().
+// The check never fails, because managers which are not the leader are still healthy, e.g. they serve webhooks.
+func LeaderElection(elected <-chan struct{}, enabled bool) Checker {
+	return func(_ *http.Request) (string, error) {
+		if !enabled {
+			return "leader election disabled", nil
+		}
+		select {
+		case <-elected:
+			return "leader", nil
+		default:
+			return "waiting to be elected leader", nil
+		}
+	}
+}
+
+// ClusterCache is a cache of connections to workload clusters, e.g. the ClusterCacheTracker.
+type ClusterCache interface {
+	// ConnectionStatus returns the number of workload clusters connected, and the number of workload clusters
+	// pending, i.e. the ones the cache is connecting to, or adding watches for.
+	ConnectionStatus() (connected int, pending int)
+}
+
+// ClusterCacheBacklog returns a Checker which fails if the number of workload clusters pending in a cluster cache
+// is greater than maxBacklog, e.g. because the API server of the workload clusters are slow or unreachable.
+func ClusterCacheBacklog(clusterCache ClusterCache, maxBacklog int) Checker {
+	return func(_ *http.Request) (string, error) {
+		connected, pending := clusterCache.ConnectionStatus()
+		detail := fmt.Sprintf("%d clusters connected, %d pending", connected, pending)
+		if pending > maxBacklog {
+			return detail, errors.Errorf("%d clusters pending, more than %d", pending, maxBacklog)
+		}
+		return detail, nil
+	}
+}
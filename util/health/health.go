@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health implements granular health checks for the subsystems of a manager, which are added
+// to the healthz and readyz endpoints of the manager and reported with their details by a JSON endpoint.
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// DetailsPath is the path of the endpoint serving the details of the health checks in JSON,
+// which is served by the diagnostics server.
+const DetailsPath = "/debug/health"
+
+// Probe is a probe of a manager.
+type Probe string
+
+const (
+	// Healthz is the liveness probe of a manager; a failing healthz check restarts the manager.
+	Healthz Probe = "healthz"
+
+	// Readyz is the readiness probe of a manager; a failing readyz check stops routing requests, e.g.
+	// webhook requests, to the manager.
+	Readyz Probe = "readyz"
+)
+
+// Checker checks the health of a subsystem of a manager. It returns a human readable detail of the
+// status of the subsystem, and an error if the subsystem is unhealthy.
+type Checker func(req *http.Request) (string, error)
+
+// FromHealthz returns a Checker for a healthz.Checker.
+func FromHealthz(checker healthz.Checker) Checker {
+	return func(req *http.Request) (string, error) {
+		return "", checker(req)
+	}
+}
+
+type check struct {
+	name    string
+	checker Checker
+	probes  []Probe
+}
+
+// Checks is a set of named health checks.
+// Checks are added to the probes of a manager by SetupWithManager; checks without probes are
+// only reported by the JSON endpoint served by Checks.
+type Checks struct {
+	lock   sync.RWMutex
+	checks []check
+}
+
+// Add adds a check with the given name to the given probes.
+func (c *Checks) Add(name string, checker Checker, probes ...Probe) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.checks = append(c.checks, check{name: name, checker: checker, probes: probes})
+}
+
+// SetupWithManager adds the checks to the probes of a manager.
+func (c *Checks) SetupWithManager(mgr ctrl.Manager) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	for _, check := range c.checks {
+		checker := check.checker
+		healthzChecker := func(req *http.Request) error {
+			_, err := checker(req)
+			return err
+		}
+		for _, probe := range check.probes {
+			switch probe {
+			case Healthz:
+				if err := mgr.AddHealthzCheck(check.name, healthzChecker); err != nil {
+					return errors.Wrapf(err, "failed to add %s health check", check.name)
+				}
+			case Readyz:
+				if err := mgr.AddReadyzCheck(check.name, healthzChecker); err != nil {
+					return errors.Wrapf(err, "failed to add %s ready check", check.name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Status is the status of a set of checks.
+type Status struct {
+	// Healthy is true if all the healthz checks succeed.
+	Healthy bool `json:"healthy"`
+
+	// Ready is true if all the readyz checks succeed.
+	Ready bool `json:"ready"`
+
+	// Checks is the status of each check.
+	Checks []CheckStatus `json:"checks"`
+}
+
+// CheckStatus is the status of a check.
+type CheckStatus struct {
+	// Name of the check.
+	Name string `json:"name"`
+
+	// Probes the check is added to, if any.
+	Probes []Probe `json:"probes,omitempty"`
+
+	// Healthy is true if the check succeeds.
+	Healthy bool `json:"healthy"`
+
+	// Detail is a human readable detail of the status of the subsystem checked.
+	Detail string `json:"detail,omitempty"`
+
+	// Error is the error returned by the check, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Status runs the checks and returns their status.
+func (c *Checks) Status(req *http.Request) Status {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	status := Status{Healthy: true, Ready: true, Checks: []CheckStatus{}}
+	for _, check := range c.checks {
+		detail, err := check.checker(req)
+		checkStatus := CheckStatus{
+			Name:    check.name,
+			Probes:  check.probes,
+			Healthy: err == nil,
+			Detail:  detail,
+		}
+		if err != nil {
+			checkStatus.Error = err.Error()
+			for _, probe := range check.probes {
+				switch probe {
+				case Healthz:
+					status.Healthy = false
+				case Readyz:
+					status.Ready = false
+				}
+			}
+		}
+		status.Checks = append(status.Checks, checkStatus)
+	}
+	return status
+}
+
+// ServeHTTP serves the status of the checks in JSON.
+// NOTE: The response status code is always 200, the status of the checks is reported in the response body;
+// use the healthz and readyz endpoints of the manager to probe it.
+func (c *Checks) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(c.Status(req)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestChecks(t *testing.T) {
+	g := NewWithT(t)
+
+	checks := &Checks{}
+	checks.Add("healthy", func(*http.Request) (string, error) { return "all good", nil }, Healthz, Readyz)
+	checks.Add("not-ready", func(*http.Request) (string, error) { return "", errors.New("not synced") }, Readyz)
+	checks.Add("informational", func(*http.Request) (string, error) { return "2 pending", errors.New("too many pending") })
+
+	w := httptest.NewRecorder()
+	checks.ServeHTTP(w, httptest.NewRequest(http.MethodGet, DetailsPath, http.NoBody))
+	g.Expect(w.Code).To(Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Content-Type")).To(Equal("application/json"))
+
+	// Checks which are not added to a probe don't affect the status of the probes.
+	status := Status{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &status)).To(Succeed())
+	g.Expect(status).To(Equal(Status{
+		Healthy: true,
+		Ready:   false,
+		Checks: []CheckStatus{
+			{Name: "healthy", Probes: []Probe{Healthz, Readyz}, Healthy: true, Detail: "all good"},
+			{Name: "not-ready", Probes: []Probe{Readyz}, Healthy: false, Error: "not synced"},
+			{Name: "informational", Healthy: false, Detail: "2 pending", Error: "too many pending"},
+		},
+	}))
+}
+
+func TestInformersSynced(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	informers := &informertest.FakeInformers{Scheme: scheme}
+	req := httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+	checker := InformersSynced(informers, &clusterv1.Cluster{}, &clusterv1.Machine{})
+
+	// The check fails until all the informers are synced.
+	_, err := checker(req)
+	g.Expect(err).To(MatchError("informers for Cluster, Machine not synced"))
+
+	clusterInformer, err := informers.FakeInformerFor(req.Context(), &clusterv1.Cluster{})
+	g.Expect(err).ToNot(HaveOccurred())
+	clusterInformer.Synced = true
+	_, err = checker(req)
+	g.Expect(err).To(MatchError("informers for Machine not synced"))
+
+	machineInformer, err := informers.FakeInformerFor(req.Context(), &clusterv1.Machine{})
+	g.Expect(err).ToNot(HaveOccurred())
+	machineInformer.Synced = true
+	detail, err := checker(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(detail).To(Equal("2 informers synced"))
+}
+
+func TestCertificateValid(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	tests := []struct {
+		name       string
+		notBefore  time.Time
+		notAfter   time.Time
+		wantErr    string
+		wantDetail string
+	}{
+		{
+			name:       "valid certificate",
+			notBefore:  now.Add(-time.Hour),
+			notAfter:   now.Add(time.Hour),
+			wantDetail: "certificate valid until " + now.Add(time.Hour).UTC().Format(time.RFC3339),
+		},
+		{
+			name:      "expired certificate",
+			notBefore: now.Add(-2 * time.Hour),
+			notAfter:  now.Add(-time.Hour),
+			wantErr:   "expired at " + now.Add(-time.Hour).UTC().Format(time.RFC3339),
+		},
+		{
+			name:      "certificate not valid yet",
+			notBefore: now.Add(time.Hour),
+			notAfter:  now.Add(2 * time.Hour),
+			wantErr:   "is not valid before " + now.Add(time.Hour).UTC().Format(time.RFC3339),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			certFile := filepath.Join(t.TempDir(), "tls.crt")
+			g.Expect(os.WriteFile(certFile, newCertificatePEM(g, tt.notBefore, tt.notAfter), 0600)).To(Succeed())
+
+			detail, err := CertificateValid(certFile)(nil)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(detail).To(Equal(tt.wantDetail))
+		})
+	}
+
+	t.Run("missing certificate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := CertificateValid(filepath.Join(t.TempDir(), "tls.crt"))(nil)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to read certificate")))
+	})
+}
+
+func newCertificatePEM(g *WithT, notBefore, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "webhook"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	g.Expect(err).ToNot(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestLeaderElection(t *testing.T) {
+	g := NewWithT(t)
+
+	elected := make(chan struct{})
+
+	detail, err := LeaderElection(elected, false)(nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(detail).To(Equal("leader election disabled"))
+
+	// Managers which are not the leader are healthy.
+	detail, err = LeaderElection(elected, true)(nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(detail).To(Equal("waiting to be elected leader"))
+
+	close(elected)
+	detail, err = LeaderElection(elected, true)(nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(detail).To(Equal("leader"))
+}
+
+type fakeClusterCache struct {
+	connected, pending int
+}
+
+func (c fakeClusterCache) ConnectionStatus() (int, int) {
+	return c.connected, c.pending
+}
+
+func TestClusterCacheBacklog(t *testing.T) {
+	g := NewWithT(t)
+
+	detail, err := ClusterCacheBacklog(fakeClusterCache{connected: 3, pending: 2}, 2)(nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(detail).To(Equal("3 clusters connected, 2 pending"))
+
+	detail, err = ClusterCacheBacklog(fakeClusterCache{connected: 3, pending: 3}, 2)(nil)
+	g.Expect(err).To(MatchError("3 clusters pending, more than 2"))
+	g.Expect(detail).To(Equal("3 clusters connected, 3 pending"))
+}
@@ -176,6 +176,89 @@ func NeedsClientCertRotation(configSecret *corev1.Secret, threshold time.Duratio
 	return false, nil
 }
 
+// ClientCertExpiry returns the time the first of the Kubeconfig secret's client certificates expires.
+// It returns the zero time if the Kubeconfig does not contain client certificates, e.g. if it uses a token.
+func ClientCertExpiry(configSecret *corev1.Secret) (time.Time, error) {
+	data, err := toKubeconfigBytes(configSecret)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to convert kubeconfig Secret into a clientcmdapi.Config")
+	}
+
+	expiry := time.Time{}
+	for _, authInfo := range config.AuthInfos {
+		if len(authInfo.ClientCertificateData) == 0 {
+			continue
+		}
+		cert, err := certs.DecodeCertPEM(authInfo.ClientCertificateData)
+		if err != nil {
+			return time.Time{}, errors.Wrap(err, "failed to decode kubeconfig client certificate")
+		}
+		if cert == nil {
+			return time.Time{}, errors.New("kubeconfig client certificate not found")
+		}
+		if expiry.IsZero() || cert.NotAfter.Before(expiry) {
+			expiry = cert.NotAfter
+		}
+	}
+	return expiry, nil
+}
+
+// RotateClientCerts replaces the client certificates of the Kubeconfig in the given secret with new certificates
+// for the same subjects, signed by the given CA; the endpoint and the CA of the cluster are preserved.
+// The secret is modified in place and must be stored by the caller.
+func RotateClientCerts(configSecret *corev1.Secret, caCert *x509.Certificate, caKey crypto.Signer) error {
+	data, err := toKubeconfigBytes(configSecret)
+	if err != nil {
+		return err
+	}
+
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert kubeconfig Secret into a clientcmdapi.Config")
+	}
+
+	for name, authInfo := range config.AuthInfos {
+		if len(authInfo.ClientCertificateData) == 0 {
+			continue
+		}
+		current, err := certs.DecodeCertPEM(authInfo.ClientCertificateData)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode the client certificate of user %q", name)
+		}
+		if current == nil {
+			return errors.Errorf("client certificate of user %q not found", name)
+		}
+
+		clientKey, err := certs.NewPrivateKey()
+		if err != nil {
+			return errors.Wrap(err, "unable to create private key")
+		}
+		cfg := &certs.Config{
+			CommonName:   current.Subject.CommonName,
+			Organization: current.Subject.Organization,
+			Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+		clientCert, err := cfg.NewSignedCert(clientKey, caCert, caKey)
+		if err != nil {
+			return errors.Wrap(err, "unable to sign certificate")
+		}
+		authInfo.ClientKeyData = certs.EncodePrivateKeyPEM(clientKey)
+		authInfo.ClientCertificateData = certs.EncodeCertPEM(clientCert)
+	}
+
+	out, err := clientcmd.Write(*config)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize config to yaml")
+	}
+	configSecret.Data[secret.KubeconfigDataName] = out
+	return nil
+}
+
 // RegenerateSecret creates and stores a new Kubeconfig in the given secret.
 func RegenerateSecret(ctx context.Context, c client.Client, configSecret *corev1.Secret) error {
 	clusterName, _, err := secret.ParseSecretName(configSecret.Name)
@@ -417,3 +417,66 @@ func TestRegenerateClientCerts(t *testing.T) {
 
 	g.Expect(newCert.NotAfter).To(BeTemporally(">", oldCert.NotAfter))
 }
+
+func TestClientCertExpiry(t *testing.T) {
+	g := NewWithT(t)
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	caCert, err := getTestCACert(caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	config, err := New("foo", "https://127:0.0.1:4003", caCert, caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	out, err := clientcmd.Write(*config)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	kubeconfigSecret := GenerateSecretWithOwner(client.ObjectKey{Name: "foo", Namespace: "test"}, out, metav1.OwnerReference{})
+	expiry, err := ClientCertExpiry(kubeconfigSecret)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(expiry).To(BeTemporally("~", time.Now().Add(certs.DefaultCertDuration), time.Minute))
+
+	config.AuthInfos["foo-admin"].ClientCertificateData = nil
+	config.AuthInfos["foo-admin"].ClientKeyData = nil
+	config.AuthInfos["foo-admin"].Token = "token"
+	out, err = clientcmd.Write(*config)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	kubeconfigSecret = GenerateSecretWithOwner(client.ObjectKey{Name: "foo", Namespace: "test"}, out, metav1.OwnerReference{})
+	expiry, err = ClientCertExpiry(kubeconfigSecret)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(expiry.IsZero()).To(BeTrue())
+}
+
+func TestRotateClientCerts(t *testing.T) {
+	g := NewWithT(t)
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+	caCert, err := getTestCACert(caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	signerKey, err := certs.NewPrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+	signerCert, err := getTestCACert(signerKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	config, err := New("foo", "https://127.0.0.1:6443", caCert, caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	out, err := clientcmd.Write(*config)
+	g.Expect(err).ToNot(HaveOccurred())
+	kubeconfigSecret := GenerateSecretWithOwner(client.ObjectKey{Name: "foo", Namespace: "test"}, out, metav1.OwnerReference{})
+
+	g.Expect(RotateClientCerts(kubeconfigSecret, signerCert, signerKey)).To(Succeed())
+
+	newConfig, err := clientcmd.Load(kubeconfigSecret.Data[secret.KubeconfigDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(newConfig.Clusters["foo"].Server).To(Equal("https://127.0.0.1:6443"))
+	g.Expect(newConfig.Clusters["foo"].CertificateAuthorityData).To(Equal(certs.EncodeCertPEM(caCert)))
+	g.Expect(newConfig.AuthInfos["foo-admin"].ClientKeyData).ToNot(Equal(config.AuthInfos["foo-admin"].ClientKeyData))
+
+	newCert, err := certs.DecodeCertPEM(newConfig.AuthInfos["foo-admin"].ClientCertificateData)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(newCert.Subject.CommonName).To(Equal("kubernetes-admin"))
+	g.Expect(newCert.Subject.Organization).To(ConsistOf("system:masters"))
+	g.Expect(newCert.CheckSignatureFrom(signerCert)).To(Succeed())
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics implements per-cluster reconcile metrics for Cluster API controllers and providers.
+//
+// controller-runtime already exports reconcile metrics partitioned by controller; the metrics in this package
+// are also partitioned by the Cluster the reconciled object belongs to, so it is possible to identify which
+// Clusters are consuming the capacity of a controller on a management cluster with many Clusters.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(reconcileDuration)
+	ctrlmetrics.Registry.MustRegister(reconcileErrors)
+	ctrlmetrics.Registry.MustRegister(reconcileRequeues)
+	ctrlmetrics.Registry.MustRegister(pendingRequeues)
+}
+
+// Metrics subsystem for the reconciles of Cluster API controllers.
+const reconcileSubsystem = "capi_reconcile"
+
+// traceIDLabel is the label of the exemplars linking a metric to the trace of a reconcile.
+const traceIDLabel = "trace_id"
+
+var (
+	// reconcileDuration reports the duration of the reconciles of a controller for the objects of a Cluster.
+	// NOTE: The histogram has a series for each bucket, controller and Cluster, so it uses far fewer buckets than
+	// controller_runtime_reconcile_time_seconds to keep the cardinality low on management clusters with many Clusters.
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: reconcileSubsystem,
+		Name:      "duration_seconds",
+		Help:      "Duration of the reconciles, partitioned by controller and cluster.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 60},
+	}, []string{"controller", "namespace", "cluster_name"})
+
+	// reconcileErrors reports the number of reconciles of a controller for the objects of a Cluster returning an error.
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: reconcileSubsystem,
+		Name:      "errors_total",
+		Help:      "Total number of reconciles returning an error, partitioned by controller and cluster.",
+	}, []string{"controller", "namespace", "cluster_name"})
+
+	// reconcileRequeues reports the number of reconciles of a controller for the objects of a Cluster requesting
+	// a requeue without returning an error.
+	reconcileRequeues = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: reconcileSubsystem,
+		Name:      "requeues_total",
+		Help:      "Total number of reconciles requesting a requeue without an error, partitioned by controller and cluster.",
+	}, []string{"controller", "namespace", "cluster_name"})
+
+	// pendingRequeues reports the number of objects of a Cluster requeued by a controller, either explicitly or
+	// because of an error, and not yet reconciled again.
+	// NOTE: The workqueue of a controller is not partitioned by cluster, so objects enqueued because of an event
+	// are not included; the depth of the workqueue of each controller is reported by controller-runtime.
+	// There is no metric for the depth of the workqueue per Cluster or per shard of Clusters.
+	pendingRequeues = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: reconcileSubsystem,
+		Name:      "pending_requeues",
+		Help:      "Number of objects requeued and not yet reconciled again, partitioned by controller and cluster.",
+	}, []string{"controller", "namespace", "cluster_name"})
+)
+
+// requeued tracks the Cluster of the objects requeued and not yet reconciled again, so pendingRequeues can
+// be decremented for the same Cluster when they are reconciled.
+var requeued = &requeuedTracker{clusters: map[requeuedKey]string{}}
+
+type requeuedKey struct {
+	controller string
+	request    reconcile.Request
+}
+
+type requeuedTracker struct {
+	lock     sync.Mutex
+	clusters map[requeuedKey]string
+}
+
+// add tracks a requeued object, unless it is already tracked.
+func (t *requeuedTracker) add(controllerName string, req reconcile.Request, clusterName string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := requeuedKey{controller: controllerName, request: req}
+	if _, ok := t.clusters[key]; ok {
+		return
+	}
+	t.clusters[key] = clusterName
+	pendingRequeues.WithLabelValues(controllerName, req.Namespace, clusterName).Inc()
+}
+
+// remove stops tracking a requeued object when it is reconciled again.
+func (t *requeuedTracker) remove(controllerName string, req reconcile.Request) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := requeuedKey{controller: controllerName, request: req}
+	clusterName, ok := t.clusters[key]
+	if !ok {
+		return
+	}
+	delete(t.clusters, key)
+	pendingRequeues.WithLabelValues(controllerName, req.Namespace, clusterName).Dec()
+}
+
+// deleteCluster stops tracking the requeued objects of a deleted Cluster.
+func (t *requeuedTracker) deleteCluster(cluster client.ObjectKey) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for key, clusterName := range t.clusters {
+		if key.request.Namespace == cluster.Namespace && clusterName == cluster.Name {
+			delete(t.clusters, key)
+		}
+	}
+}
+
+type clusterKey struct{}
+
+// clusterHolder holds the name of the Cluster the object being reconciled belongs to.
+type clusterHolder struct {
+	name string
+}
+
+// SetCluster sets the name of the Cluster the object being reconciled belongs to, so the metrics of the
+// reconcile are partitioned by this Cluster.
+// It is a no-op if the reconciler is not wrapped with Reconciler.
+func SetCluster(ctx context.Context, clusterName string) {
+	if holder, ok := ctx.Value(clusterKey{}).(*clusterHolder); ok {
+		holder.name = clusterName
+	}
+}
+
+// Reconciler wraps the reconciler of a controller so the duration, the errors and the requeues of each reconcile
+// are reported in metrics partitioned by the controller and by the Cluster the reconciled object belongs to.
+// If the reconcile is traced, the metrics have the trace ID as an exemplar.
+// NOTE: Reconcilers for objects belonging to a Cluster should call SetCluster as soon as the Cluster is known;
+// the metrics of reconciles that don't set the Cluster are reported with an empty cluster_name.
+func Reconciler(controllerName, kind string, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		requeued.remove(controllerName, req)
+
+		holder := &clusterHolder{}
+		if kind == "Cluster" {
+			holder.name = req.Name
+		}
+		ctx = context.WithValue(ctx, clusterKey{}, holder)
+
+		start := time.Now()
+		result, err := r.Reconcile(ctx, req)
+		observe(ctx, controllerName, req, holder.name, time.Since(start), result, err)
+
+		return result, err
+	})
+}
+
+// observe reports the metrics of a reconcile.
+func observe(ctx context.Context, controllerName string, req reconcile.Request, clusterName string, duration time.Duration, result reconcile.Result, err error) {
+	labels := prometheus.Labels{"controller": controllerName, "namespace": req.Namespace, "cluster_name": clusterName}
+	exemplar := exemplarFromContext(ctx)
+
+	observer := reconcileDuration.With(labels)
+	if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok && exemplar != nil {
+		exemplarObserver.ObserveWithExemplar(duration.Seconds(), exemplar)
+	} else {
+		observer.Observe(duration.Seconds())
+	}
+
+	switch {
+	case err != nil:
+		addWithExemplar(reconcileErrors.With(labels), exemplar)
+		// NOTE: controller-runtime does not requeue objects when the error is a terminal error.
+		if !errors.Is(err, reconcile.TerminalError(nil)) {
+			requeued.add(controllerName, req, clusterName)
+		}
+	case result.Requeue || result.RequeueAfter > 0:
+		addWithExemplar(reconcileRequeues.With(labels), exemplar)
+		requeued.add(controllerName, req, clusterName)
+	}
+}
+
+// addWithExemplar increments a counter, with an exemplar if any.
+func addWithExemplar(counter prometheus.Counter, exemplar prometheus.Labels) {
+	if exemplarAdder, ok := counter.(prometheus.ExemplarAdder); ok && exemplar != nil {
+		exemplarAdder.AddWithExemplar(1, exemplar)
+		return
+	}
+	counter.Inc()
+}
+
+// exemplarFromContext returns an exemplar with the ID of the trace in the context, if the trace is sampled.
+func exemplarFromContext(ctx context.Context) prometheus.Labels {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsSampled() {
+		return nil
+	}
+	return prometheus.Labels{traceIDLabel: spanContext.TraceID().String()}
+}
+
+// DeleteClusterMetrics removes the reconcile metrics of a deleted Cluster.
+func DeleteClusterMetrics(cluster client.ObjectKey) {
+	labels := prometheus.Labels{"namespace": cluster.Namespace, "cluster_name": cluster.Name}
+	requeued.deleteCluster(cluster)
+	reconcileDuration.DeletePartialMatch(labels)
+	reconcileErrors.DeletePartialMatch(labels)
+	reconcileRequeues.DeletePartialMatch(labels)
+	pendingRequeues.DeletePartialMatch(labels)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconciler(t *testing.T) {
+	g := NewWithT(t)
+
+	var result reconcile.Result
+	var err error
+	r := Reconciler("test-machine", "Machine", reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		SetCluster(ctx, "test-cluster")
+		return result, err
+	}))
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "test-namespace", Name: "test-machine"}}
+	labels := prometheus.Labels{"controller": "test-machine", "namespace": "test-namespace", "cluster_name": "test-cluster"}
+	t.Cleanup(func() { DeleteClusterMetrics(client.ObjectKey{Namespace: "test-namespace", Name: "test-cluster"}) })
+
+	// A successful reconcile only reports its duration.
+	_, _ = r.Reconcile(context.Background(), req)
+	g.Expect(testutil.CollectAndCount(reconcileDuration, "capi_reconcile_duration_seconds")).To(Equal(1))
+	g.Expect(testutil.ToFloat64(reconcileErrors.With(labels))).To(Equal(0.0))
+	g.Expect(testutil.ToFloat64(reconcileRequeues.With(labels))).To(Equal(0.0))
+	g.Expect(testutil.ToFloat64(pendingRequeues.With(labels))).To(Equal(0.0))
+
+	// A reconcile requesting a requeue is reported as a requeue, pending until the next reconcile of the object.
+	result, err = reconcile.Result{RequeueAfter: time.Minute}, nil
+	_, _ = r.Reconcile(context.Background(), req)
+	g.Expect(testutil.ToFloat64(reconcileRequeues.With(labels))).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(pendingRequeues.With(labels))).To(Equal(1.0))
+
+	// A reconcile returning an error is reported as an error, and the object is requeued.
+	result, err = reconcile.Result{}, errors.New("failed")
+	_, _ = r.Reconcile(context.Background(), req)
+	g.Expect(testutil.ToFloat64(reconcileErrors.With(labels))).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(reconcileRequeues.With(labels))).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(pendingRequeues.With(labels))).To(Equal(1.0))
+
+	// A reconcile returning a terminal error is reported as an error, but the object is not requeued.
+	result, err = reconcile.Result{}, reconcile.TerminalError(errors.New("failed"))
+	_, _ = r.Reconcile(context.Background(), req)
+	g.Expect(testutil.ToFloat64(reconcileErrors.With(labels))).To(Equal(2.0))
+	g.Expect(testutil.ToFloat64(pendingRequeues.With(labels))).To(Equal(0.0))
+
+	// The metrics are removed when the Cluster is deleted.
+	result, err = reconcile.Result{Requeue: true}, nil
+	_, _ = r.Reconcile(context.Background(), req)
+	DeleteClusterMetrics(client.ObjectKey{Namespace: "test-namespace", Name: "test-cluster"})
+	g.Expect(testutil.CollectAndCount(reconcileDuration, "capi_reconcile_duration_seconds")).To(Equal(0))
+	g.Expect(testutil.CollectAndCount(reconcileErrors, "capi_reconcile_errors_total")).To(Equal(0))
+	g.Expect(testutil.CollectAndCount(reconcileRequeues, "capi_reconcile_requeues_total")).To(Equal(0))
+	g.Expect(testutil.CollectAndCount(pendingRequeues, "capi_reconcile_pending_requeues")).To(Equal(0))
+	g.Expect(requeued.clusters).To(BeEmpty())
+}
+
+func TestReconcilerCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	r := Reconciler("test-cluster", "Cluster", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, errors.New("failed")
+	}))
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "test-namespace", Name: "test-cluster"}}
+	t.Cleanup(func() { DeleteClusterMetrics(client.ObjectKey{Namespace: "test-namespace", Name: "test-cluster"}) })
+
+	// The Cluster of the reconciles of Clusters is the reconciled Cluster.
+	_, _ = r.Reconcile(context.Background(), req)
+	g.Expect(testutil.ToFloat64(reconcileErrors.WithLabelValues("test-cluster", "test-namespace", "test-cluster"))).To(Equal(1.0))
+}
+
+func TestReconcilerExemplar(t *testing.T) {
+	g := NewWithT(t)
+
+	r := Reconciler("test-exemplar", "Cluster", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, errors.New("failed")
+	}))
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "test-namespace", Name: "test-cluster"}}
+	t.Cleanup(func() { DeleteClusterMetrics(client.ObjectKey{Namespace: "test-namespace", Name: "test-cluster"}) })
+
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	_, _ = r.Reconcile(ctx, req)
+
+	// The metrics of a traced reconcile have the trace ID as an exemplar.
+	m := &dto.Metric{}
+	g.Expect(reconcileErrors.WithLabelValues("test-exemplar", "test-namespace", "test-cluster").Write(m)).To(Succeed())
+	g.Expect(m.GetCounter().GetExemplar().GetLabel()).To(ConsistOf(
+		HaveField("GetValue()", traceID.String()),
+	))
+}
@@ -0,0 +1,484 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package shard implements the sharding of the reconciliation of Clusters across the replicas of a manager.
+package shard
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// GroupLabel is the label set on the Leases of the members of a group of sharded replicas.
+	GroupLabel = "cluster.x-k8s.io/shard-group"
+
+	// DefaultLeaseDuration is the default duration of the Leases of the members.
+	DefaultLeaseDuration = 15 * time.Second
+
+	// leaseGarbageCollectionDelay is the time after which the expired Leases are deleted.
+	leaseGarbageCollectionDelay = 10 * time.Minute
+)
+
+// Sharder assigns the Clusters to the replicas of a manager, i.e. the members of a group, so that each Cluster is
+// reconciled by a single replica.
+//
+// Each member holds a Lease in Namespace, renewed every third of the LeaseDuration; the members are the holders of
+// the Leases which are not expired. Clusters are assigned to the members using rendezvous hashing of their key, i.e.
+// the value of the shard-key label of the Cluster if any, or its namespace and name, so only the Clusters of the
+// members joining or leaving the group are moved on a membership change.
+//
+// When the members change, or when the shard-key label of a Cluster changes, a Cluster moved to another member is not
+// reconciled by the new member until the handover period, i.e. the LeaseDuration, is over, so the previous member has
+// time to observe the change and to stop reconciling the Cluster; a member failing to renew its Lease stops
+// reconciling all the Clusters once its Lease expires, before the other members take them over.
+type Sharder struct {
+	// Client is used to manage the Leases and to list the Clusters.
+	Client client.Client
+
+	// APIReader is used to read the Leases, which are not cached.
+	APIReader client.Reader
+
+	// ID identifies the member, e.g. the name of the Pod of the replica.
+	ID string
+
+	// Group is the name of the group of members, e.g. the name of the manager.
+	Group string
+
+	// Namespace of the Leases of the members.
+	Namespace string
+
+	// LeaseDuration is the duration of the Leases of the members; defaults to DefaultLeaseDuration.
+	LeaseDuration time.Duration
+
+	lock sync.RWMutex
+
+	// members are the current members of the group, sorted by ID.
+	members []string
+
+	// handover are the members observed since the start of the last handover period, i.e. the members before each
+	// membership change happened during the handover period.
+	handover [][]string
+
+	// changed is the time of the last membership change.
+	changed time.Time
+
+	// renewed is the last time the Lease of the member has been renewed.
+	renewed time.Time
+
+	// owned are the Clusters owned by the member at the last sync.
+	owned map[client.ObjectKey]bool
+
+	// keys are the keys observed for each Cluster, used to apply the handover period to the Clusters whose key changed.
+	keys map[client.ObjectKey]*keyHistory
+
+	// sources receive the Clusters assigned to the member.
+	sources []chan event.GenericEvent
+
+	// releasedSources receive the Clusters which are not assigned to the member anymore.
+	releasedSources []chan event.GenericEvent
+
+	// now returns the current time; overridden in tests.
+	now func() time.Time
+}
+
+// keyHistory tracks the changes of the key of a Cluster.
+type keyHistory struct {
+	// current is the last key observed for the Cluster.
+	current string
+
+	// previous are the keys observed since the start of the last handover period, i.e. the keys before each
+	// change happened during the handover period.
+	previous []string
+
+	// changed is the time of the last key change.
+	changed time.Time
+}
+
+var _ manager.LeaderElectionRunnable = &Sharder{}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; all the replicas are members of the group.
+func (s *Sharder) NeedLeaderElection() bool {
+	return false
+}
+
+// Key returns the key used to assign a Cluster to a member.
+func Key(cluster *clusterv1.Cluster) string {
+	if key, ok := cluster.Labels[clusterv1.ShardKeyLabel]; ok && key != "" {
+		return key
+	}
+	return cluster.Namespace + "/" + cluster.Name
+}
+
+// Owns returns true if a Cluster is assigned to the member. A nil Sharder owns all the Clusters, so it can be used
+// when sharding is disabled.
+func (s *Sharder) Owns(cluster *clusterv1.Cluster) bool {
+	if s == nil {
+		return true
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.ownsCluster(cluster)
+}
+
+// Members returns the current members of the group.
+func (s *Sharder) Members() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return append([]string{}, s.members...)
+}
+
+// owns returns true if a key is assigned to the member; it must be called with the lock held.
+func (s *Sharder) owns(key string) bool {
+	// A member which didn't renew its Lease could be considered gone by the other members.
+	if s.renewed.IsZero() || s.clock().Sub(s.renewed) >= s.leaseDuration() {
+		return false
+	}
+	if owner(key, s.members) != s.ID {
+		return false
+	}
+	if s.clock().Sub(s.changed) >= s.leaseDuration() {
+		return true
+	}
+	// During the handover period only the keys which were not moved are owned.
+	for _, members := range s.handover {
+		if owner(key, members) != s.ID {
+			return false
+		}
+	}
+	return true
+}
+
+// ownsCluster returns true if a Cluster is assigned to the member, taking into account the changes of the key of the
+// Cluster during the handover period; it must be called with the lock held.
+func (s *Sharder) ownsCluster(cluster *clusterv1.Cluster) bool {
+	key := Key(cluster)
+	history := s.observeKey(client.ObjectKeyFromObject(cluster), key)
+	if !s.owns(key) {
+		return false
+	}
+	if s.clock().Sub(history.changed) >= s.leaseDuration() {
+		return true
+	}
+	// During the handover period only the Clusters which were not moved by the key changes are owned.
+	for _, previous := range history.previous {
+		if !s.owns(previous) {
+			return false
+		}
+	}
+	return true
+}
+
+// observeKey records the key of a Cluster, tracking its changes; it must be called with the lock held.
+// NOTE: The first key observed for a Cluster is not considered a change, e.g. after the member started; the handover
+// period of the membership change of the member joining the group applies in this case.
+func (s *Sharder) observeKey(cluster client.ObjectKey, key string) *keyHistory {
+	if s.keys == nil {
+		s.keys = map[client.ObjectKey]*keyHistory{}
+	}
+	history, ok := s.keys[cluster]
+	if !ok {
+		history = &keyHistory{current: key}
+		s.keys[cluster] = history
+		return history
+	}
+	if history.current != key {
+		if s.clock().Sub(history.changed) >= s.leaseDuration() {
+			history.previous = nil
+		}
+		history.previous = append(history.previous, history.current)
+		history.current = key
+		history.changed = s.clock()
+	}
+	return history
+}
+
+// Source returns a source of generic events for the Clusters assigned to the member, e.g. after a membership change,
+// which can be used to reconcile the objects of those Clusters. It must be called before the manager is started.
+func (s *Sharder) Source() source.Source {
+	ch := make(chan event.GenericEvent, 1024)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sources = append(s.sources, ch)
+	return &source.Channel{Source: ch}
+}
+
+// ReleasedSource returns a source of generic events for the Clusters which are not assigned to the member anymore,
+// e.g. after a membership change, which can be used to release the resources held for those Clusters.
+// It must be called before the manager is started.
+func (s *Sharder) ReleasedSource() source.Source {
+	ch := make(chan event.GenericEvent, 1024)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.releasedSources = append(s.releasedSources, ch)
+	return &source.Channel{Source: ch}
+}
+
+// Start implements manager.Runnable; it keeps the Lease of the member, the members and the Clusters assigned to the
+// member up to date until the context is canceled, then it releases the Lease.
+func (s *Sharder) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithValues("group", s.Group, "member", s.ID)
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	if s.ID == "" || s.Group == "" || s.Namespace == "" {
+		return errors.New("ID, Group and Namespace are required for sharding")
+	}
+
+	log.Info("Joining the group of sharded replicas")
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := s.sync(ctx); err != nil {
+			log.Error(err, "Failed to sync the members of the group of sharded replicas")
+		}
+	}, s.leaseDuration()/3)
+
+	// Release the Lease, so the other members take over the Clusters after the handover period instead of waiting
+	// for the Lease to expire.
+	log.Info("Leaving the group of sharded replicas")
+	releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Client.Delete(releaseCtx, s.lease()); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete Lease %s", klog.KObj(s.lease()))
+	}
+	return nil
+}
+
+// sync renews the Lease of the member, then it updates the members and notifies the Clusters newly assigned to the member.
+func (s *Sharder) sync(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if err := s.renew(ctx); err != nil {
+		return err
+	}
+
+	leases := &coordinationv1.LeaseList{}
+	if err := s.APIReader.List(ctx, leases, client.InNamespace(s.Namespace), client.MatchingLabels{GroupLabel: s.Group}); err != nil {
+		return errors.Wrapf(err, "failed to list Leases in namespace %s", s.Namespace)
+	}
+	members := []string{}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if s.clock().Before(expiry) {
+			members = append(members, *lease.Spec.HolderIdentity)
+			continue
+		}
+		// Delete the Leases of the members which didn't leave the group gracefully, e.g. because they crashed.
+		if s.clock().Sub(expiry) >= leaseGarbageCollectionDelay {
+			if err := s.Client.Delete(ctx, lease); err != nil && !apierrors.IsNotFound(err) {
+				log.Error(err, "Failed to delete expired Lease", "Lease", klog.KObj(lease))
+			}
+		}
+	}
+	sort.Strings(members)
+
+	s.lock.Lock()
+	if !equal(members, s.members) {
+		log.Info("Members of the group of sharded replicas changed", "members", members)
+		if s.clock().Sub(s.changed) >= s.leaseDuration() {
+			s.handover = nil
+		}
+		s.handover = append(s.handover, s.members)
+		s.members = members
+		s.changed = s.clock()
+	}
+	s.lock.Unlock()
+
+	return s.notify(ctx)
+}
+
+// renew creates or renews the Lease of the member.
+func (s *Sharder) renew(ctx context.Context) error {
+	now := metav1.NewMicroTime(s.clock())
+	lease := s.lease()
+	if err := s.APIReader.Get(ctx, client.ObjectKeyFromObject(lease), lease); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get Lease %s", klog.KObj(lease))
+		}
+		lease.Spec.AcquireTime = &now
+	}
+
+	lease.Labels = map[string]string{GroupLabel: s.Group}
+	lease.Spec.HolderIdentity = pointer.String(s.ID)
+	lease.Spec.LeaseDurationSeconds = pointer.Int32(int32(s.leaseDuration().Seconds()))
+	lease.Spec.RenewTime = &now
+
+	if lease.ResourceVersion == "" {
+		if err := s.Client.Create(ctx, lease); err != nil {
+			return errors.Wrapf(err, "failed to create Lease %s", klog.KObj(lease))
+		}
+	} else if err := s.Client.Update(ctx, lease); err != nil {
+		return errors.Wrapf(err, "failed to renew Lease %s", klog.KObj(lease))
+	}
+
+	s.lock.Lock()
+	s.renewed = now.Time
+	s.lock.Unlock()
+	return nil
+}
+
+// notify sends a generic event to the sources for each Cluster newly assigned to the member, and to the released
+// sources for each Cluster which is not assigned to the member anymore.
+// NOTE: Deleted Clusters are not notified to the released sources.
+func (s *Sharder) notify(ctx context.Context) error {
+	clusters := &clusterv1.ClusterList{}
+	if err := s.Client.List(ctx, clusters); err != nil {
+		return errors.Wrap(err, "failed to list Clusters")
+	}
+
+	s.lock.Lock()
+	owned := map[client.ObjectKey]bool{}
+	keys := map[client.ObjectKey]*keyHistory{}
+	gained := []*clusterv1.Cluster{}
+	released := []*clusterv1.Cluster{}
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		key := client.ObjectKeyFromObject(cluster)
+		ownsCluster := s.ownsCluster(cluster)
+		keys[key] = s.keys[key]
+		if !ownsCluster {
+			if s.owned[key] {
+				released = append(released, cluster)
+			}
+			continue
+		}
+		owned[key] = true
+		if !s.owned[key] {
+			gained = append(gained, cluster)
+		}
+	}
+	s.owned = owned
+	s.keys = keys
+	sources := s.sources
+	releasedSources := s.releasedSources
+	s.lock.Unlock()
+
+	if len(gained) > 0 {
+		ctrl.LoggerFrom(ctx).V(4).Info("Clusters assigned to the member", "count", len(gained))
+	}
+	if len(released) > 0 {
+		ctrl.LoggerFrom(ctx).V(4).Info("Clusters released by the member", "count", len(released))
+	}
+	if !send(ctx, gained, sources) {
+		return nil
+	}
+	send(ctx, released, releasedSources)
+	return nil
+}
+
+// send sends a generic event to the sources for each of the Clusters; it returns false if the context is canceled.
+func send(ctx context.Context, clusters []*clusterv1.Cluster, sources []chan event.GenericEvent) bool {
+	for _, cluster := range clusters {
+		for _, ch := range sources {
+			select {
+			case ch <- event.GenericEvent{Object: cluster}:
+			case <-ctx.Done():
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// lease returns the Lease of the member.
+func (s *Sharder) lease() *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.Group + "-" + s.ID,
+			Namespace: s.Namespace,
+		},
+	}
+}
+
+func (s *Sharder) clock() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
+
+func (s *Sharder) leaseDuration() time.Duration {
+	if s.LeaseDuration == 0 {
+		return DefaultLeaseDuration
+	}
+	return s.LeaseDuration
+}
+
+// owner returns the member a key is assigned to, i.e. the member with the highest hash of the key, if any.
+func owner(key string, members []string) string {
+	var owner string
+	var highest uint64
+	for _, member := range members {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(member))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(key))
+		if sum := mix(h.Sum64()); owner == "" || sum > highest {
+			owner, highest = member, sum
+		}
+	}
+	return owner
+}
+
+// mix is the finalizer of MurmurHash3, which spreads the bits of the FNV hashes so that the highest hash is
+// uniformly distributed across the members.
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
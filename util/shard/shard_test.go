@@ -0,0 +1,336 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestKey(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cluster1"}}
+	g.Expect(Key(cluster)).To(Equal("ns1/cluster1"))
+
+	cluster.Labels = map[string]string{clusterv1.ShardKeyLabel: "account1"}
+	g.Expect(Key(cluster)).To(Equal("account1"))
+}
+
+func TestOwner(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(owner("key", nil)).To(BeEmpty())
+
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("ns/cluster%d", i)
+	}
+
+	// Keys are spread across all the members.
+	members := []string{"a", "b", "c"}
+	owners := map[string]string{}
+	count := map[string]int{}
+	for _, key := range keys {
+		owners[key] = owner(key, members)
+		count[owners[key]]++
+	}
+	for _, member := range members {
+		g.Expect(count[member]).To(BeNumerically(">", 200), "member %s owns %d keys", member, count[member])
+	}
+
+	// Only the keys of a member leaving are moved.
+	for _, key := range keys {
+		if owners[key] != "c" {
+			g.Expect(owner(key, []string{"a", "b"})).To(Equal(owners[key]))
+		}
+	}
+
+	// Only keys moved to a member joining are moved.
+	for _, key := range keys {
+		if o := owner(key, []string{"a", "b", "c", "d"}); o != "d" {
+			g.Expect(o).To(Equal(owners[key]))
+		}
+	}
+}
+
+func TestSharder_Owns(t *testing.T) {
+	now := time.Now()
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"}}
+
+	// Find a membership change moving the Cluster from b to a.
+	members := []string{"a", "b"}
+	previous := []string{"b"}
+	g := NewWithT(t)
+	g.Expect(owner(Key(cluster), previous)).To(Equal("b"))
+	for owner(Key(cluster), members) != "a" {
+		cluster.Name += "x"
+	}
+
+	tests := []struct {
+		name     string
+		sharder  *Sharder
+		wantOwns bool
+	}{
+		{
+			name:     "a nil sharder owns all the Clusters",
+			wantOwns: true,
+		},
+		{
+			name: "owns the Clusters assigned to the member",
+			sharder: &Sharder{
+				ID:      "a",
+				members: members,
+				changed: now.Add(-time.Minute),
+				renewed: now,
+			},
+			wantOwns: true,
+		},
+		{
+			name: "doesn't own the Clusters assigned to other members",
+			sharder: &Sharder{
+				ID:      "b",
+				members: members,
+				changed: now.Add(-time.Minute),
+				renewed: now,
+			},
+		},
+		{
+			name: "doesn't own any Cluster if the Lease is not renewed",
+			sharder: &Sharder{
+				ID:      "a",
+				members: members,
+				changed: now.Add(-time.Minute),
+				renewed: now.Add(-time.Minute),
+			},
+		},
+		{
+			name: "doesn't own the Clusters moved to the member during the handover period",
+			sharder: &Sharder{
+				ID:       "a",
+				members:  members,
+				handover: [][]string{previous},
+				changed:  now.Add(-time.Second),
+				renewed:  now,
+			},
+		},
+		{
+			name: "doesn't own the Clusters moved during multiple membership changes during the handover period",
+			sharder: &Sharder{
+				ID:       "a",
+				members:  members,
+				handover: [][]string{previous, members},
+				changed:  now.Add(-time.Second),
+				renewed:  now,
+			},
+		},
+		{
+			name: "owns the Clusters not moved during the handover period",
+			sharder: &Sharder{
+				ID:       "a",
+				members:  members,
+				handover: [][]string{{"a"}},
+				changed:  now.Add(-time.Second),
+				renewed:  now,
+			},
+			wantOwns: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			if tt.sharder != nil {
+				tt.sharder.now = func() time.Time { return now }
+			}
+			g.Expect(tt.sharder.Owns(cluster)).To(Equal(tt.wantOwns))
+		})
+	}
+}
+
+func TestSharder_OwnsKeyChange(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	members := []string{"a", "b"}
+	s := &Sharder{
+		ID:      "a",
+		members: members,
+		changed: now.Add(-time.Minute),
+		renewed: now,
+		now:     func() time.Time { return now },
+	}
+
+	// Find a key change moving the Cluster from b to a.
+	keyB, keyA := "key", "key"
+	for owner(keyB, members) != "b" {
+		keyB += "x"
+	}
+	for owner(keyA, members) != "a" {
+		keyA += "y"
+	}
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster", Labels: map[string]string{clusterv1.ShardKeyLabel: keyB}}}
+	g.Expect(s.Owns(cluster)).To(BeFalse())
+
+	// The Cluster moved to the member is not owned during the handover period.
+	cluster.Labels[clusterv1.ShardKeyLabel] = keyA
+	g.Expect(s.Owns(cluster)).To(BeFalse())
+	now = now.Add(s.leaseDuration() / 2)
+	s.renewed = now
+	g.Expect(s.Owns(cluster)).To(BeFalse())
+
+	// The Cluster is owned after the handover period.
+	now = now.Add(s.leaseDuration() / 2)
+	s.renewed = now
+	g.Expect(s.Owns(cluster)).To(BeTrue())
+
+	// The Cluster moved away from the member is not owned anymore immediately.
+	cluster.Labels[clusterv1.ShardKeyLabel] = keyB
+	g.Expect(s.Owns(cluster)).To(BeFalse())
+}
+
+func TestSharder_sync(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	now := time.Now().Truncate(time.Second)
+	lease := func(name string, renewed time.Time) *coordinationv1.Lease {
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "capi-" + name,
+				Namespace: "capi-system",
+				Labels:    map[string]string{GroupLabel: "capi"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       pointer.String(name),
+				LeaseDurationSeconds: pointer.Int32(15),
+				RenewTime:            &metav1.MicroTime{Time: renewed},
+			},
+		}
+	}
+	objs := []client.Object{
+		lease("b", now),
+		lease("c", now.Add(-time.Minute)),
+		lease("d", now.Add(-time.Hour)),
+	}
+	for i := 0; i < 20; i++ {
+		objs = append(objs, &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: fmt.Sprintf("cluster%d", i)}})
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	s := &Sharder{
+		Client:    c,
+		APIReader: c,
+		ID:        "a",
+		Group:     "capi",
+		Namespace: "capi-system",
+		now:       func() time.Time { return now },
+	}
+	s.Source()
+
+	// The member joins the group, but it doesn't own any Cluster until the handover period is over.
+	g.Expect(s.sync(ctx)).To(Succeed())
+	g.Expect(s.Members()).To(Equal([]string{"a", "b"}))
+	g.Expect(s.owned).To(BeEmpty())
+
+	own := &coordinationv1.Lease{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "capi-system", Name: "capi-a"}, own)).To(Succeed())
+	g.Expect(*own.Spec.HolderIdentity).To(Equal("a"))
+	g.Expect(own.Labels).To(HaveKeyWithValue(GroupLabel, "capi"))
+
+	// The Lease expired for a long time is deleted, while the Lease expired recently is kept.
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "capi-system", Name: "capi-c"}, &coordinationv1.Lease{})).To(Succeed())
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "capi-system", Name: "capi-d"}, &coordinationv1.Lease{})).ToNot(Succeed())
+
+	// After the handover period, the member owns and notifies its Clusters.
+	now = now.Add(s.leaseDuration())
+	b := &coordinationv1.Lease{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "capi-system", Name: "capi-b"}, b)).To(Succeed())
+	b.Spec.RenewTime = &metav1.MicroTime{Time: now}
+	g.Expect(c.Update(ctx, b)).To(Succeed())
+
+	g.Expect(s.sync(ctx)).To(Succeed())
+	g.Expect(s.Members()).To(Equal([]string{"a", "b"}))
+	g.Expect(s.owned).ToNot(BeEmpty())
+	g.Expect(len(s.owned)).To(BeNumerically("<", 20))
+	g.Expect(s.sources[0]).To(HaveLen(len(s.owned)))
+	for range s.owned {
+		e := <-s.sources[0]
+		g.Expect(s.owned).To(HaveKey(client.ObjectKeyFromObject(e.Object)))
+	}
+
+	// Clusters already owned are not notified again.
+	g.Expect(s.sync(ctx)).To(Succeed())
+	g.Expect(s.sources[0]).To(BeEmpty())
+
+	// When e joins, the Clusters moved to e are released immediately.
+	s.ReleasedSource()
+	ownedBefore := len(s.owned)
+	g.Expect(c.Create(ctx, lease("e", now))).To(Succeed())
+	g.Expect(s.sync(ctx)).To(Succeed())
+	g.Expect(s.Members()).To(Equal([]string{"a", "b", "e"}))
+	ownedWithE := len(s.owned)
+	g.Expect(ownedWithE).To(BeNumerically("<", ownedBefore))
+	g.Expect(s.releasedSources[0]).To(HaveLen(ownedBefore - ownedWithE))
+	for len(s.releasedSources[0]) > 0 {
+		e := <-s.releasedSources[0]
+		g.Expect(s.owned).ToNot(HaveKey(client.ObjectKeyFromObject(e.Object)))
+	}
+
+	// When e leaves, the member owns the Clusters again after the handover period.
+	g.Expect(c.Delete(ctx, lease("e", now))).To(Succeed())
+	g.Expect(s.sync(ctx)).To(Succeed())
+	now = now.Add(s.leaseDuration())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(b), b)).To(Succeed())
+	b.Spec.RenewTime = &metav1.MicroTime{Time: now}
+	g.Expect(c.Update(ctx, b)).To(Succeed())
+	g.Expect(s.sync(ctx)).To(Succeed())
+	g.Expect(s.owned).To(HaveLen(ownedBefore))
+	g.Expect(s.sources[0]).To(HaveLen(ownedBefore - ownedWithE))
+	for len(s.sources[0]) > 0 {
+		<-s.sources[0]
+	}
+
+	// When b leaves, the member owns all the Clusters after the handover period.
+	g.Expect(c.Delete(ctx, b)).To(Succeed())
+	g.Expect(s.sync(ctx)).To(Succeed())
+	g.Expect(s.Members()).To(Equal([]string{"a"}))
+	owned := len(s.owned)
+	g.Expect(owned).To(BeNumerically("<", 20))
+
+	now = now.Add(s.leaseDuration())
+	g.Expect(s.sync(ctx)).To(Succeed())
+	g.Expect(s.owned).To(HaveLen(20))
+	g.Expect(s.sources[0]).To(HaveLen(20 - owned))
+}
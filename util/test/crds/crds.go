@@ -0,0 +1,433 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crds
+
+import (
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/contract"
+)
+
+// ProviderResource is a resource of a provider defined by the Cluster API contract; it determines the schema of the
+// fake CRD.
+type ProviderResource string
+
+const (
+	// InfrastructureCluster is the infrastructure cluster resource of an infrastructure provider.
+	InfrastructureCluster ProviderResource = "InfrastructureCluster"
+	// InfrastructureClusterTemplate is the infrastructure cluster template resource of an infrastructure provider.
+	InfrastructureClusterTemplate ProviderResource = "InfrastructureClusterTemplate"
+	// InfrastructureMachine is the infrastructure machine resource of an infrastructure provider.
+	InfrastructureMachine ProviderResource = "InfrastructureMachine"
+	// InfrastructureMachineTemplate is the infrastructure machine template resource of an infrastructure provider.
+	InfrastructureMachineTemplate ProviderResource = "InfrastructureMachineTemplate"
+	// InfrastructureMachinePool is the infrastructure machine pool resource of an infrastructure provider.
+	InfrastructureMachinePool ProviderResource = "InfrastructureMachinePool"
+	// InfrastructureMachinePoolTemplate is the infrastructure machine pool template resource of an infrastructure provider.
+	InfrastructureMachinePoolTemplate ProviderResource = "InfrastructureMachinePoolTemplate"
+	// BootstrapConfig is the bootstrap config resource of a bootstrap provider.
+	BootstrapConfig ProviderResource = "BootstrapConfig"
+	// BootstrapConfigTemplate is the bootstrap config template resource of a bootstrap provider.
+	BootstrapConfigTemplate ProviderResource = "BootstrapConfigTemplate"
+	// ControlPlane is the control plane resource of a control plane provider.
+	ControlPlane ProviderResource = "ControlPlane"
+	// ControlPlaneTemplate is the control plane template resource of a control plane provider.
+	ControlPlaneTemplate ProviderResource = "ControlPlaneTemplate"
+)
+
+// Builder holds the variables needed to build a fake provider CRD.
+type Builder struct {
+	resource         ProviderResource
+	gvk              schema.GroupVersionKind
+	versions         []string
+	contractVersions map[string][]string
+	specProperties   map[string]apiextensionsv1.JSONSchemaProps
+	statusProperties map[string]apiextensionsv1.JSONSchemaProps
+}
+
+// New returns a Builder for a fake CRD of the given provider resource, with the given group, version and kind.
+// By default the CRD only serves the given version, and is labelled as compatible with the v1beta1 Cluster API
+// contract with that version.
+func New(resource ProviderResource, gvk schema.GroupVersionKind) *Builder {
+	return &Builder{
+		resource:         resource,
+		gvk:              gvk,
+		versions:         []string{gvk.Version},
+		specProperties:   map[string]apiextensionsv1.JSONSchemaProps{},
+		statusProperties: map[string]apiextensionsv1.JSONSchemaProps{},
+	}
+}
+
+// WithVersions sets the versions served by the CRD; the last one is the storage version.
+func (b *Builder) WithVersions(versions ...string) *Builder {
+	b.versions = versions
+	return b
+}
+
+// WithContractVersions sets the versions of the CRD compatible with each Cluster API contract, e.g. v1beta1,
+// which are set as contract version labels of the CRD.
+func (b *Builder) WithContractVersions(contractVersions map[string][]string) *Builder {
+	b.contractVersions = contractVersions
+	return b
+}
+
+// WithSpecProperties adds provider specific fields to the schema of the spec of the CRD, or of the spec of the
+// template for template resources.
+func (b *Builder) WithSpecProperties(properties map[string]apiextensionsv1.JSONSchemaProps) *Builder {
+	for k, v := range properties {
+		b.specProperties[k] = v
+	}
+	return b
+}
+
+// WithStatusProperties adds provider specific fields to the schema of the status of the CRD.
+func (b *Builder) WithStatusProperties(properties map[string]apiextensionsv1.JSONSchemaProps) *Builder {
+	for k, v := range properties {
+		b.statusProperties[k] = v
+	}
+	return b
+}
+
+// Build builds a new fake provider CRD.
+func (b *Builder) Build() *apiextensionsv1.CustomResourceDefinition {
+	labels := map[string]string{}
+	contractVersions := b.contractVersions
+	if contractVersions == nil {
+		contractVersions = map[string][]string{clusterv1.GroupVersion.Version: {b.gvk.Version}}
+	}
+	for c, versions := range contractVersions {
+		labels[clusterv1.GroupVersion.Group+"/"+c] = strings.Join(versions, "_")
+	}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   contract.CalculateCRDName(b.gvk.Group, b.gvk.Kind),
+			Labels: labels,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: b.gvk.Group,
+			Scope: apiextensionsv1.NamespaceScoped,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:     b.gvk.Kind,
+				ListKind: b.gvk.Kind + "List",
+				Plural:   flect.Pluralize(strings.ToLower(b.gvk.Kind)),
+				Singular: strings.ToLower(b.gvk.Kind),
+			},
+		},
+	}
+
+	for i, version := range b.versions {
+		crdVersion := apiextensionsv1.CustomResourceDefinitionVersion{
+			Name:    version,
+			Served:  true,
+			Storage: i == len(b.versions)-1,
+			Schema: &apiextensionsv1.CustomResourceValidation{
+				OpenAPIV3Schema: b.schema(),
+			},
+		}
+		if !b.isTemplate() {
+			crdVersion.Subresources = &apiextensionsv1.CustomResourceSubresources{
+				Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+			}
+		}
+		if b.resource == ControlPlane {
+			crdVersion.Subresources.Scale = &apiextensionsv1.CustomResourceSubresourceScale{
+				SpecReplicasPath:   ".spec.replicas",
+				StatusReplicasPath: ".status.replicas",
+				LabelSelectorPath:  pointer.String(".status.selector"),
+			}
+		}
+		crd.Spec.Versions = append(crd.Spec.Versions, crdVersion)
+	}
+	return crd
+}
+
+func (b *Builder) isTemplate() bool {
+	return strings.HasSuffix(string(b.resource), "Template")
+}
+
+// schema returns the schema of the CRD, with the fields defined by the Cluster API contract for the resource and the
+// provider specific fields.
+func (b *Builder) schema() *apiextensionsv1.JSONSchemaProps {
+	spec := objectSchema(contractSpecProperties[ProviderResource(strings.TrimSuffix(string(b.resource), "Template"))], b.specProperties)
+
+	if b.isTemplate() {
+		template := apiextensionsv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"spec": spec,
+			},
+		}
+		// NOTE: the control plane template contract does not define template metadata.
+		if b.resource != ControlPlaneTemplate {
+			template.Properties["metadata"] = metadataSchema
+		}
+		return &apiextensionsv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"apiVersion": {Type: "string"},
+				"kind":       {Type: "string"},
+				"metadata":   {Type: "object"},
+				"spec": {
+					Type: "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"template": template,
+					},
+					Required: []string{"template"},
+				},
+			},
+		}
+	}
+
+	return &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"apiVersion": {Type: "string"},
+			"kind":       {Type: "string"},
+			// NOTE: in CRD there is only a partial definition of metadata schema.
+			// Ref https://github.com/kubernetes-sigs/controller-tools/blob/59485af1c1f6a664655dad49543c474bb4a0d2a2/pkg/crd/gen.go#L185
+			"metadata": {Type: "object"},
+			"spec":     spec,
+			"status":   objectSchema(contractStatusProperties[b.resource], b.statusProperties),
+		},
+	}
+}
+
+// objectSchema returns the schema of an object with the union of the given properties.
+func objectSchema(properties ...map[string]apiextensionsv1.JSONSchemaProps) apiextensionsv1.JSONSchemaProps {
+	s := apiextensionsv1.JSONSchemaProps{
+		Type:       "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{},
+	}
+	for _, p := range properties {
+		for k, v := range p {
+			s.Properties[k] = v
+		}
+	}
+	return s
+}
+
+// InfrastructureProviderCRDs returns fake CRDs for all the resources of an infrastructure provider, with kinds
+// prefixed by the given prefix, e.g. FooCluster, FooClusterTemplate, FooMachine, FooMachineTemplate, FooMachinePool
+// and FooMachinePoolTemplate for the Foo prefix.
+func InfrastructureProviderCRDs(gv schema.GroupVersion, kindPrefix string) []*apiextensionsv1.CustomResourceDefinition {
+	return []*apiextensionsv1.CustomResourceDefinition{
+		New(InfrastructureCluster, gv.WithKind(kindPrefix+"Cluster")).Build(),
+		New(InfrastructureClusterTemplate, gv.WithKind(kindPrefix+"ClusterTemplate")).Build(),
+		New(InfrastructureMachine, gv.WithKind(kindPrefix+"Machine")).Build(),
+		New(InfrastructureMachineTemplate, gv.WithKind(kindPrefix+"MachineTemplate")).Build(),
+		New(InfrastructureMachinePool, gv.WithKind(kindPrefix+"MachinePool")).Build(),
+		New(InfrastructureMachinePoolTemplate, gv.WithKind(kindPrefix+"MachinePoolTemplate")).Build(),
+	}
+}
+
+// BootstrapProviderCRDs returns fake CRDs for all the resources of a bootstrap provider, with kinds prefixed by the
+// given prefix, e.g. FooConfig and FooConfigTemplate for the Foo prefix.
+func BootstrapProviderCRDs(gv schema.GroupVersion, kindPrefix string) []*apiextensionsv1.CustomResourceDefinition {
+	return []*apiextensionsv1.CustomResourceDefinition{
+		New(BootstrapConfig, gv.WithKind(kindPrefix+"Config")).Build(),
+		New(BootstrapConfigTemplate, gv.WithKind(kindPrefix+"ConfigTemplate")).Build(),
+	}
+}
+
+// ControlPlaneProviderCRDs returns fake CRDs for all the resources of a control plane provider, with kinds prefixed
+// by the given prefix, e.g. FooControlPlane and FooControlPlaneTemplate for the Foo prefix.
+func ControlPlaneProviderCRDs(gv schema.GroupVersion, kindPrefix string) []*apiextensionsv1.CustomResourceDefinition {
+	return []*apiextensionsv1.CustomResourceDefinition{
+		New(ControlPlane, gv.WithKind(kindPrefix+"ControlPlane")).Build(),
+		New(ControlPlaneTemplate, gv.WithKind(kindPrefix+"ControlPlaneTemplate")).Build(),
+	}
+}
+
+var (
+	refSchema = apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"apiVersion": {Type: "string"},
+			"kind":       {Type: "string"},
+			"name":       {Type: "string"},
+			"namespace":  {Type: "string"},
+			"uid":        {Type: "string"},
+		},
+	}
+
+	metadataSchema = apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"labels": {
+				Type: "object",
+				AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+					Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+				},
+			},
+			"annotations": {
+				Type: "object",
+				AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+					Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+				},
+			},
+		},
+	}
+
+	conditionsSchema = apiextensionsv1.JSONSchemaProps{
+		Type: "array",
+		Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+			Schema: &apiextensionsv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"type":               {Type: "string"},
+					"status":             {Type: "string"},
+					"severity":           {Type: "string"},
+					"reason":             {Type: "string"},
+					"message":            {Type: "string"},
+					"lastTransitionTime": {Type: "string", Format: "date-time"},
+				},
+				Required: []string{"type", "status", "lastTransitionTime"},
+			},
+		},
+	}
+
+	stringListSchema = apiextensionsv1.JSONSchemaProps{
+		Type: "array",
+		Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+			Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+		},
+	}
+
+	// failureProperties are the fields reporting terminal failures, defined by the contract of most resources.
+	failureProperties = map[string]apiextensionsv1.JSONSchemaProps{
+		"failureReason":  {Type: "string"},
+		"failureMessage": {Type: "string"},
+	}
+
+	// contractSpecProperties are the spec fields defined by the Cluster API contract for each resource;
+	// templates use the fields of the corresponding resource.
+	contractSpecProperties = map[ProviderResource]map[string]apiextensionsv1.JSONSchemaProps{
+		InfrastructureCluster: {
+			"controlPlaneEndpoint": {
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"host": {Type: "string"},
+					"port": {Type: "integer", Format: "int32"},
+				},
+			},
+		},
+		InfrastructureMachine: {
+			"providerID":    {Type: "string"},
+			"failureDomain": {Type: "string"},
+		},
+		InfrastructureMachinePool: {
+			"providerIDList": stringListSchema,
+		},
+		BootstrapConfig: {},
+		ControlPlane: {
+			"version":  {Type: "string"},
+			"replicas": {Type: "integer", Format: "int32"},
+			"machineTemplate": {
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"metadata":                metadataSchema,
+					"infrastructureRef":       refSchema,
+					"nodeDrainTimeout":        {Type: "string"},
+					"nodeVolumeDetachTimeout": {Type: "string"},
+					"nodeDeletionTimeout":     {Type: "string"},
+				},
+			},
+		},
+	}
+
+	// contractStatusProperties are the status fields defined by the Cluster API contract for each resource.
+	contractStatusProperties = map[ProviderResource]map[string]apiextensionsv1.JSONSchemaProps{
+		InfrastructureCluster: union(failureProperties, map[string]apiextensionsv1.JSONSchemaProps{
+			"ready":      {Type: "boolean"},
+			"conditions": conditionsSchema,
+			"failureDomains": {
+				Type: "object",
+				AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+					Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"controlPlane": {Type: "boolean"},
+							"attributes": {
+								Type: "object",
+								AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+									Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}),
+		InfrastructureMachine: union(failureProperties, map[string]apiextensionsv1.JSONSchemaProps{
+			"ready":      {Type: "boolean"},
+			"conditions": conditionsSchema,
+			"addresses": {
+				Type: "array",
+				Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+					Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"type":    {Type: "string"},
+							"address": {Type: "string"},
+						},
+						Required: []string{"type", "address"},
+					},
+				},
+			},
+		}),
+		InfrastructureMachinePool: union(failureProperties, map[string]apiextensionsv1.JSONSchemaProps{
+			"ready":                     {Type: "boolean"},
+			"conditions":                conditionsSchema,
+			"replicas":                  {Type: "integer", Format: "int32"},
+			"infrastructureMachineKind": {Type: "string"},
+		}),
+		BootstrapConfig: union(failureProperties, map[string]apiextensionsv1.JSONSchemaProps{
+			"ready":          {Type: "boolean"},
+			"conditions":     conditionsSchema,
+			"dataSecretName": {Type: "string"},
+		}),
+		ControlPlane: union(failureProperties, map[string]apiextensionsv1.JSONSchemaProps{
+			"ready":                       {Type: "boolean"},
+			"initialized":                 {Type: "boolean"},
+			"conditions":                  conditionsSchema,
+			"externalManagedControlPlane": {Type: "boolean"},
+			"version":                     {Type: "string"},
+			"replicas":                    {Type: "integer", Format: "int32"},
+			"selector":                    {Type: "string"},
+			"readyReplicas":               {Type: "integer", Format: "int32"},
+			"updatedReplicas":             {Type: "integer", Format: "int32"},
+			"unavailableReplicas":         {Type: "integer", Format: "int32"},
+		}),
+	}
+)
+
+// union returns the union of the given properties.
+func union(properties ...map[string]apiextensionsv1.JSONSchemaProps) map[string]apiextensionsv1.JSONSchemaProps {
+	return objectSchema(properties...).Properties
+}
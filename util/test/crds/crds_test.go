@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crds
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/cluster-api/util/conversion"
+)
+
+func TestBuilder(t *testing.T) {
+	gv := schema.GroupVersion{Group: "infrastructure.cluster.x-k8s.io", Version: "v1beta2"}
+
+	t.Run("CRD with contract version labels", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := New(InfrastructureMachine, gv.WithKind("FooMachine")).
+			WithVersions("v1beta1", "v1beta2").
+			WithContractVersions(map[string][]string{"v1beta1": {"v1beta1", "v1beta2"}}).
+			WithSpecProperties(map[string]apiextensionsv1.JSONSchemaProps{"image": {Type: "string"}}).
+			WithStatusProperties(map[string]apiextensionsv1.JSONSchemaProps{"instanceState": {Type: "string"}}).
+			Build()
+
+		g.Expect(crd.Name).To(Equal("foomachines.infrastructure.cluster.x-k8s.io"))
+		g.Expect(crd.Labels).To(Equal(map[string]string{"cluster.x-k8s.io/v1beta1": "v1beta1_v1beta2"}))
+		g.Expect(crd.Spec.Versions).To(HaveLen(2))
+		g.Expect(crd.Spec.Versions[0].Storage).To(BeFalse())
+		g.Expect(crd.Spec.Versions[1].Storage).To(BeTrue())
+		g.Expect(crd.Spec.Versions[1].Subresources.Status).ToNot(BeNil())
+
+		properties := crd.Spec.Versions[1].Schema.OpenAPIV3Schema.Properties
+		g.Expect(properties["spec"].Properties).To(HaveKey("providerID"))
+		g.Expect(properties["spec"].Properties).To(HaveKey("image"))
+		g.Expect(properties["status"].Properties).To(HaveKey("ready"))
+		g.Expect(properties["status"].Properties).To(HaveKey("addresses"))
+		g.Expect(properties["status"].Properties).To(HaveKey("instanceState"))
+
+		// The contract version labels are the ones used by Cluster API to pick the version of the references.
+		scheme := runtime.NewScheme()
+		g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crd).Build()
+		ref := &corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "FooMachine"}
+		g.Expect(conversion.UpdateReferenceAPIContract(context.Background(), c, ref)).To(Succeed())
+		g.Expect(ref.APIVersion).To(Equal("infrastructure.cluster.x-k8s.io/v1beta2"))
+	})
+
+	t.Run("Template CRD", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := New(InfrastructureMachineTemplate, gv.WithKind("FooMachineTemplate")).
+			WithSpecProperties(map[string]apiextensionsv1.JSONSchemaProps{"image": {Type: "string"}}).
+			Build()
+
+		g.Expect(crd.Labels).To(Equal(map[string]string{"cluster.x-k8s.io/v1beta1": "v1beta2"}))
+		g.Expect(crd.Spec.Versions[0].Subresources).To(BeNil())
+		properties := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties
+		g.Expect(properties).ToNot(HaveKey("status"))
+		template := properties["spec"].Properties["template"]
+		g.Expect(template.Properties).To(HaveKey("metadata"))
+		g.Expect(template.Properties["spec"].Properties).To(HaveKey("providerID"))
+		g.Expect(template.Properties["spec"].Properties).To(HaveKey("image"))
+	})
+
+	t.Run("Control plane CRD", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crds := ControlPlaneProviderCRDs(schema.GroupVersion{Group: "controlplane.cluster.x-k8s.io", Version: "v1beta1"}, "Foo")
+		g.Expect(crds).To(HaveLen(2))
+		g.Expect(crds[0].Spec.Names.Kind).To(Equal("FooControlPlane"))
+		g.Expect(crds[0].Spec.Versions[0].Subresources.Scale).ToNot(BeNil())
+		g.Expect(crds[0].Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties).To(HaveKey("machineTemplate"))
+		g.Expect(crds[1].Spec.Names.Kind).To(Equal("FooControlPlaneTemplate"))
+		g.Expect(crds[1].Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["template"].Properties).ToNot(HaveKey("metadata"))
+	})
+
+	t.Run("Provider CRDs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(InfrastructureProviderCRDs(gv, "Foo")).To(HaveLen(6))
+		g.Expect(BootstrapProviderCRDs(schema.GroupVersion{Group: "bootstrap.cluster.x-k8s.io", Version: "v1beta1"}, "Foo")).To(HaveLen(2))
+	})
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing implements OpenTelemetry tracing utilities for Cluster API controllers, providers and
+// Runtime Extensions.
+//
+// Tracing is opt-in: until Setup is called with an endpoint, the global OpenTelemetry tracer provider is a no-op
+// and the spans created by the utilities in this package are not recorded.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"sigs.k8s.io/cluster-api/util/flags"
+	"sigs.k8s.io/cluster-api/version"
+)
+
+// TracerName is the name of the tracer used by Cluster API.
+const TracerName = "sigs.k8s.io/cluster-api"
+
+// Attributes set on the spans created by Cluster API.
+const (
+	// ClusterNameKey is the name of the Cluster the span belongs to.
+	ClusterNameKey = attribute.Key("cluster_api.cluster.name")
+
+	// NamespaceKey is the namespace of the object the span is about.
+	NamespaceKey = attribute.Key("k8s.namespace.name")
+
+	// KindKey is the kind of the object the span is about, e.g. Machine.
+	KindKey = attribute.Key("cluster_api.object.kind")
+
+	// NameKey is the name of the object the span is about.
+	NameKey = attribute.Key("cluster_api.object.name")
+
+	// HookKey is the name of the Runtime SDK hook called in the span, e.g. BeforeClusterUpgrade.
+	HookKey = attribute.Key("cluster_api.runtime.hook")
+
+	// ExtensionHandlerKey is the name of the Runtime Extension handler called in the span.
+	ExtensionHandlerKey = attribute.Key("cluster_api.runtime.extension_handler")
+)
+
+// Setup configures the global OpenTelemetry tracer provider to export the spans to the OTLP gRPC endpoint in
+// the options, and the global propagator to propagate the trace context, e.g. to Runtime Extensions, using
+// W3C Trace Context and Baggage headers.
+// If the endpoint is not set, tracing is disabled and Setup is a no-op.
+// The returned function flushes the spans not yet exported and stops the tracer provider; it should be called
+// before the process exits.
+func Setup(ctx context.Context, serviceName string, options flags.TracingOptions) (func(context.Context) error, error) {
+	if options.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if options.SamplingRate < 0 || options.SamplingRate > 1 {
+		return nil, errors.Errorf("invalid tracing sampling rate %v: must be between 0 and 1", options.SamplingRate)
+	}
+
+	exporterOptions := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(options.Endpoint)}
+	if options.Insecure {
+		exporterOptions = append(exporterOptions, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOptions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the OTLP trace exporter")
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version.Get().String()),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the tracing resource")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SamplingRate))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span with the Cluster API tracer, as a child of the span in the context, if any.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End ends a span, recording the error, if any.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SetCluster sets the name of the Cluster the span in the context belongs to, so the spans of all the objects
+// of a Cluster can be searched together.
+func SetCluster(ctx context.Context, clusterName string) {
+	trace.SpanFromContext(ctx).SetAttributes(ClusterNameKey.String(clusterName))
+}
+
+// Reconciler wraps the reconciler of a controller so each reconcile is traced in a span named after the
+// controller, with the kind, the namespace and the name of the reconciled object as attributes; the spans for
+// the calls made during the reconcile, e.g. to the workload cluster or to Runtime Extensions, are children of
+// this span.
+// NOTE: Reconcilers for objects belonging to a Cluster should call SetCluster as soon as the Cluster is known.
+func Reconciler(controllerName, kind string, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (_ reconcile.Result, reterr error) {
+		attributes := []attribute.KeyValue{
+			KindKey.String(kind),
+			NamespaceKey.String(req.Namespace),
+			NameKey.String(req.Name),
+		}
+		if kind == "Cluster" {
+			attributes = append(attributes, ClusterNameKey.String(req.Name))
+		}
+
+		ctx, span := Start(ctx, "Reconcile "+controllerName, attributes...)
+		defer func() { End(span, reterr) }()
+
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// WrapTransport wraps an HTTP transport so each request is traced in a span, as a child of the span in the
+// context of the request, and the trace context is propagated to the server using the headers of the
+// global propagator.
+// It has the signature of a client-go transport.WrapperFunc, so it can be used with rest.Config.Wrap.
+func WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(rt)
+}
+
+// WrapHandler wraps an HTTP handler so each request is traced in a span, as a child of the span propagated
+// by the client, if any.
+func WrapHandler(handler http.Handler, operation string) http.Handler {
+	return otelhttp.NewHandler(handler, operation)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"sigs.k8s.io/cluster-api/util/flags"
+)
+
+func TestSetup(t *testing.T) {
+	t.Run("tracing is disabled without an endpoint", func(t *testing.T) {
+		g := NewWithT(t)
+
+		shutdown, err := Setup(context.Background(), "test", flags.TracingOptions{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(shutdown(context.Background())).To(Succeed())
+	})
+	t.Run("fails with an invalid sampling rate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := Setup(context.Background(), "test", flags.TracingOptions{Endpoint: "localhost:4317", SamplingRate: 2})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestReconciler(t *testing.T) {
+	g := NewWithT(t)
+	recorder := setupTestTracerProvider(t)
+
+	r := Reconciler("machine", "Machine", reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		SetCluster(ctx, "test-cluster")
+		_, span := Start(ctx, "child")
+		End(span, nil)
+		return reconcile.Result{}, errors.New("reconcile failed")
+	}))
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "test-machine"}})
+	g.Expect(err).To(HaveOccurred())
+
+	spans := recorder.Ended()
+	g.Expect(spans).To(HaveLen(2))
+	child, parent := spans[0], spans[1]
+
+	g.Expect(parent.Name()).To(Equal("Reconcile machine"))
+	g.Expect(parent.Attributes()).To(ConsistOf(
+		KindKey.String("Machine"),
+		NamespaceKey.String("ns"),
+		NameKey.String("test-machine"),
+		ClusterNameKey.String("test-cluster"),
+	))
+	g.Expect(parent.Status().Code).To(Equal(codes.Error))
+	g.Expect(parent.Status().Description).To(Equal("reconcile failed"))
+
+	g.Expect(child.Name()).To(Equal("child"))
+	g.Expect(child.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+	g.Expect(child.Status().Code).To(Equal(codes.Unset))
+}
+
+func TestReconcilerCluster(t *testing.T) {
+	g := NewWithT(t)
+	recorder := setupTestTracerProvider(t)
+
+	r := Reconciler("cluster", "Cluster", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, nil
+	}))
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "test-cluster"}})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	spans := recorder.Ended()
+	g.Expect(spans).To(HaveLen(1))
+	g.Expect(spans[0].Attributes()).To(ContainElement(ClusterNameKey.String("test-cluster")))
+	g.Expect(spans[0].Status().Code).To(Equal(codes.Unset))
+}
+
+func TestWrapTransport(t *testing.T) {
+	g := NewWithT(t)
+	recorder := setupTestTracerProvider(t)
+
+	var serverSpanContext trace.SpanContext
+	server := httptest.NewServer(WrapHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		serverSpanContext = trace.SpanContextFromContext(r.Context())
+	}), "test"))
+	defer server.Close()
+
+	ctx, span := Start(context.Background(), "parent")
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, http.NoBody)
+	g.Expect(err).ToNot(HaveOccurred())
+	response, err := (&http.Client{Transport: WrapTransport(http.DefaultTransport)}).Do(request)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(response.Body.Close()).To(Succeed())
+	End(span, nil)
+
+	// The server span is in the same trace of the client span, i.e. the trace context has been propagated.
+	g.Expect(serverSpanContext.IsValid()).To(BeTrue())
+	g.Expect(serverSpanContext.TraceID()).To(Equal(span.SpanContext().TraceID()))
+	g.Expect(serverSpanContext.SpanID()).ToNot(Equal(span.SpanContext().SpanID()))
+	g.Expect(recorder.Ended()).To(HaveLen(3))
+}
+
+// setupTestTracerProvider sets a global tracer provider recording the spans, and the propagator used by Setup,
+// restoring the previous ones at the end of the test.
+func setupTestTracerProvider(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return recorder
+}
@@ -59,12 +59,14 @@ func (webhook *Machine) SetupWebhookWithManager(mgr ctrl.Manager) error {
 // MachineDeployment implements a validating and defaulting webhook for MachineDeployment.
 type MachineDeployment struct {
 	Decoder *admission.Decoder
+	Client  client.Reader
 }
 
 // SetupWebhookWithManager sets up MachineDeployment webhooks.
 func (webhook *MachineDeployment) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return (&webhooks.MachineDeployment{
 		Decoder: webhook.Decoder,
+		Client:  webhook.Client,
 	}).SetupWebhookWithManager(mgr)
 }
 